@@ -0,0 +1,60 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package artifacts
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/pkg/errors"
+)
+
+// BuildInfo are the details of a build, saved along with the exported
+// binaries so they are recorded in the metadata of the artifacts
+type BuildInfo struct {
+	Fqbn              string `json:"fqbn"`
+	Optimization      string `json:"optimization,omitempty"`
+	OptimizationFlags string `json:"optimization_flags,omitempty"`
+}
+
+// BuildInfoFile returns the path of the build info file of the given project
+// (e.g. "Blink.ino") in dir
+func BuildInfoFile(dir *paths.Path, projectName string) *paths.Path {
+	return dir.Join(projectName + ".build_info.json")
+}
+
+// Save writes the build info in the given file
+func (b *BuildInfo) Save(file *paths.Path) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(file.WriteFile(data))
+}
+
+// LoadBuildInfo reads the build info saved in the given file
+func LoadBuildInfo(file *paths.Path) (*BuildInfo, error) {
+	data, err := file.ReadFile()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	info := &BuildInfo{}
+	if err := json.Unmarshal(data, info); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", file, err)
+	}
+	return info, nil
+}
@@ -0,0 +1,141 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package artifacts
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	bldr "github.com/arduino/arduino-cli/arduino/builder"
+	"github.com/arduino/go-paths-helper"
+	"github.com/pkg/errors"
+)
+
+// Manifest lists the binaries exported by a build, with their checksums, and
+// the details of the build, so that the released firmwares can be traced back
+// to the sources and verified
+type Manifest struct {
+	Sketch     string    `json:"sketch"`
+	Fqbn       string    `json:"fqbn"`
+	GitCommit  string    `json:"git_commit,omitempty"`
+	CLIVersion string    `json:"cli_version"`
+	Created    time.Time `json:"created"`
+	// BuildProperties are the build.* properties of the board, without the
+	// paths of the build machine
+	BuildProperties map[string]string   `json:"build_properties"`
+	Artifacts       []*ManifestArtifact `json:"artifacts"`
+}
+
+// ManifestArtifact is a file listed in the Manifest
+type ManifestArtifact struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// ManifestFile returns the path of the artifacts manifest of the given project
+// (e.g. "Blink.ino") in dir. The signature of the manifest, if any, is saved in
+// the same path with the .sig extension added.
+func ManifestFile(dir *paths.Path, projectName string) *paths.Path {
+	return dir.Join(projectName + ".artifacts.json")
+}
+
+func signatureFile(manifestFile *paths.Path) *paths.Path {
+	return paths.New(manifestFile.String() + ".sig")
+}
+
+// AddArtifact adds the given file to the manifest
+func (m *Manifest) AddArtifact(file *paths.Path) error {
+	f, err := file.Open()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer f.Close()
+	hash := sha256.New()
+	size, err := io.Copy(hash, f)
+	if err != nil {
+		return fmt.Errorf("computing hash of %s: %s", file, err)
+	}
+	m.Artifacts = append(m.Artifacts, &ManifestArtifact{
+		Name:   file.Base(),
+		Size:   size,
+		SHA256: hex.EncodeToString(hash.Sum(nil)),
+	})
+	return nil
+}
+
+// Save writes the manifest in the given file. If key is not nil the manifest
+// is signed and the signature is saved in the .sig file next to it.
+func (m *Manifest) Save(file *paths.Path, key crypto.Signer) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := file.WriteFile(data); err != nil {
+		return errors.WithStack(err)
+	}
+	sigFile := signatureFile(file)
+	if key == nil {
+		// don't leave the signature of a previous manifest
+		if sigFile.Exist() {
+			return errors.WithStack(sigFile.Remove())
+		}
+		return nil
+	}
+	signature, err := bldr.SignImage(key, data)
+	if err != nil {
+		return errors.Wrap(err, "signing the artifacts manifest")
+	}
+	return errors.WithStack(sigFile.WriteFile(signature))
+}
+
+// VerifyManifest reads the manifest saved in the given file and checks the
+// checksums of the artifacts, that must be in the same folder. If publicKey
+// is not nil the signature of the manifest is checked too.
+func VerifyManifest(file *paths.Path, publicKey crypto.PublicKey) (*Manifest, error) {
+	data, err := file.ReadFile()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if publicKey != nil {
+		signature, err := signatureFile(file).ReadFile()
+		if err != nil {
+			return nil, fmt.Errorf("reading the signature of the manifest: %s", err)
+		}
+		if err := bldr.VerifyImage(publicKey, data, signature); err != nil {
+			return nil, fmt.Errorf("verifying the signature of the manifest: %s", err)
+		}
+	}
+	manifest := &Manifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", file, err)
+	}
+	for _, artifact := range manifest.Artifacts {
+		check := &Manifest{}
+		if err := check.AddArtifact(file.Parent().Join(artifact.Name)); err != nil {
+			return nil, err
+		}
+		if check.Artifacts[0].SHA256 != artifact.SHA256 {
+			return nil, fmt.Errorf("the checksum of %s doesn't match the manifest", artifact.Name)
+		}
+	}
+	return manifest, nil
+}
@@ -0,0 +1,93 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package artifacts
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManifest(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "artifacts_manifest")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+
+	require.NoError(t, tmp.Join("Blink.ino.hex").WriteFile([]byte("hex")))
+	require.NoError(t, tmp.Join("Blink.ino.elf").WriteFile([]byte("elf")))
+	manifest := &Manifest{
+		Sketch:          "Blink",
+		Fqbn:            "arduino:avr:uno",
+		CLIVersion:      "0.18.0",
+		BuildProperties: map[string]string{"build.mcu": "atmega328p"},
+	}
+	require.NoError(t, manifest.AddArtifact(tmp.Join("Blink.ino.hex")))
+	require.NoError(t, manifest.AddArtifact(tmp.Join("Blink.ino.elf")))
+	require.Error(t, manifest.AddArtifact(tmp.Join("Blink.ino.bin")))
+	require.Equal(t, "Blink.ino.hex", manifest.Artifacts[0].Name)
+	require.Equal(t, int64(3), manifest.Artifacts[0].Size)
+	// echo -n hex | sha256sum
+	require.Equal(t, "128df13c1e54ffaaafcc9d07ec7427d61f764214e6ae0321de23c94d261d0860", manifest.Artifacts[0].SHA256)
+
+	file := ManifestFile(tmp, "Blink.ino")
+	require.NoError(t, manifest.Save(file, nil))
+	require.False(t, signatureFile(file).Exist())
+	loaded, err := VerifyManifest(file, nil)
+	require.NoError(t, err)
+	require.Equal(t, "arduino:avr:uno", loaded.Fqbn)
+	require.Len(t, loaded.Artifacts, 2)
+
+	// A modified artifact is detected
+	require.NoError(t, tmp.Join("Blink.ino.elf").WriteFile([]byte("modified")))
+	_, err = VerifyManifest(file, nil)
+	require.EqualError(t, err, "the checksum of Blink.ino.elf doesn't match the manifest")
+	require.NoError(t, tmp.Join("Blink.ino.elf").WriteFile([]byte("elf")))
+
+	// Signed with Ed25519
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	require.NoError(t, manifest.Save(file, privateKey))
+	require.True(t, signatureFile(file).Exist())
+	_, err = VerifyManifest(file, publicKey)
+	require.NoError(t, err)
+
+	// Signed with ECDSA P-256
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	require.NoError(t, manifest.Save(file, ecKey))
+	_, err = VerifyManifest(file, ecKey.Public())
+	require.NoError(t, err)
+	_, err = VerifyManifest(file, publicKey)
+	require.Error(t, err)
+
+	// A modified manifest is detected
+	data, err := file.ReadFile()
+	require.NoError(t, err)
+	require.NoError(t, file.WriteFile(append(data, ' ')))
+	_, err = VerifyManifest(file, ecKey.Public())
+	require.Error(t, err)
+
+	// Saving without a key removes the old signature
+	require.NoError(t, manifest.Save(file, nil))
+	require.False(t, signatureFile(file).Exist())
+	_, err = VerifyManifest(file, ecKey.Public())
+	require.Error(t, err)
+}
@@ -0,0 +1,231 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package artifacts
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/pkg/errors"
+	"gopkg.in/src-d/go-git.v4"
+)
+
+// metadataFile is the name of the file, in the folder of each artifact,
+// where the artifact metadata are stored
+const metadataFile = "artifact.json"
+
+var validTag = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Artifact is a set of build outputs saved in the store under a tag
+type Artifact struct {
+	Tag        string    `json:"tag"`
+	Sketch     string    `json:"sketch"`
+	SketchPath string    `json:"sketch_path"`
+	Fqbn       string    `json:"fqbn"`
+	Profile    string    `json:"profile,omitempty"`
+	GitCommit  string    `json:"git_commit,omitempty"`
+	Created    time.Time `json:"created"`
+	// Optimization is the optimization preset used for the build, if known
+	Optimization string `json:"optimization,omitempty"`
+	// Keep is true if the artifact must be retained when the store is pruned
+	Keep  bool     `json:"keep"`
+	Files []string `json:"files"`
+
+	dir *paths.Path
+}
+
+// Dir returns the folder containing the files of the artifact
+func (a *Artifact) Dir() *paths.Path {
+	return a.dir
+}
+
+func (a *Artifact) save() error {
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(a.dir.Join(metadataFile).WriteFile(data))
+}
+
+// Store is a local registry of build artifacts, each one stored in a
+// folder named as its tag
+type Store struct {
+	dir *paths.Path
+}
+
+// NewStore returns the Store in the given folder
+func NewStore(dir *paths.Path) *Store {
+	return &Store{dir: dir}
+}
+
+// Add copies in the store the build outputs of the sketch found in buildDir,
+// the files named after the sketch like "Blink.ino.hex", and saves them under
+// the tag of the given artifact. If force is false an existing artifact with
+// the same tag is not replaced.
+func (s *Store) Add(artifact *Artifact, buildDir *paths.Path, force bool) (*Artifact, error) {
+	if !validTag.MatchString(artifact.Tag) {
+		return nil, fmt.Errorf("invalid tag %s: only letters, numbers, '.', '_' and '-' are allowed", artifact.Tag)
+	}
+	files, err := buildDir.ReadDir()
+	if err != nil {
+		return nil, fmt.Errorf("reading build folder: %w", err)
+	}
+	files.FilterOutDirs()
+	files.FilterPrefix(artifact.Sketch + ".")
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no build output of %s found in %s", artifact.Sketch, buildDir)
+	}
+
+	dir := s.dir.Join(artifact.Tag)
+	if dir.Exist() {
+		if !force {
+			return nil, fmt.Errorf("artifact %s already exists", artifact.Tag)
+		}
+		if err := dir.RemoveAll(); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	if err := dir.MkdirAll(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	artifact.dir = dir
+	artifact.Files = []string{}
+	for _, file := range files {
+		if err := file.CopyTo(dir.Join(file.Base())); err != nil {
+			dir.RemoveAll()
+			return nil, fmt.Errorf("copying %s: %w", file, err)
+		}
+		artifact.Files = append(artifact.Files, file.Base())
+	}
+	if artifact.Created.IsZero() {
+		artifact.Created = time.Now()
+	}
+	if artifact.Optimization == "" {
+		if info, err := LoadBuildInfo(BuildInfoFile(buildDir, artifact.Sketch+".ino")); err == nil {
+			artifact.Optimization = info.Optimization
+		}
+	}
+	if err := artifact.save(); err != nil {
+		dir.RemoveAll()
+		return nil, err
+	}
+	return artifact, nil
+}
+
+// Get returns the artifact with the given tag
+func (s *Store) Get(tag string) (*Artifact, error) {
+	if !validTag.MatchString(tag) {
+		return nil, fmt.Errorf("invalid tag %s", tag)
+	}
+	dir := s.dir.Join(tag)
+	data, err := dir.Join(metadataFile).ReadFile()
+	if err != nil {
+		return nil, fmt.Errorf("artifact %s not found", tag)
+	}
+	artifact := &Artifact{}
+	if err := json.Unmarshal(data, artifact); err != nil {
+		return nil, fmt.Errorf("reading metadata of artifact %s: %w", tag, err)
+	}
+	artifact.dir = dir
+	return artifact, nil
+}
+
+// List returns all the artifacts in the store, the most recent first
+func (s *Store) List() ([]*Artifact, error) {
+	res := []*Artifact{}
+	if !s.dir.IsDir() {
+		return res, nil
+	}
+	dirs, err := s.dir.ReadDir()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	dirs.FilterDirs()
+	for _, dir := range dirs {
+		artifact, err := s.Get(dir.Base())
+		if err != nil {
+			// not an artifact
+			continue
+		}
+		res = append(res, artifact)
+	}
+	sort.SliceStable(res, func(i, j int) bool { return res[i].Created.After(res[j].Created) })
+	return res, nil
+}
+
+// SetKeep sets whether the artifact must be retained when the store is pruned
+func (s *Store) SetKeep(tag string, keep bool) (*Artifact, error) {
+	artifact, err := s.Get(tag)
+	if err != nil {
+		return nil, err
+	}
+	artifact.Keep = keep
+	if err := artifact.save(); err != nil {
+		return nil, err
+	}
+	return artifact, nil
+}
+
+// Remove deletes the artifact with the given tag
+func (s *Store) Remove(tag string) error {
+	artifact, err := s.Get(tag)
+	if err != nil {
+		return err
+	}
+	return errors.WithStack(artifact.dir.RemoveAll())
+}
+
+// Prune removes the artifacts created before the given time, except the
+// ones marked to be kept, and returns them. If dryRun is true nothing is
+// actually removed.
+func (s *Store) Prune(before time.Time, dryRun bool) ([]*Artifact, error) {
+	artifacts, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	res := []*Artifact{}
+	for _, artifact := range artifacts {
+		if artifact.Keep || !artifact.Created.Before(before) {
+			continue
+		}
+		if !dryRun {
+			if err := artifact.dir.RemoveAll(); err != nil {
+				return nil, errors.WithStack(err)
+			}
+		}
+		res = append(res, artifact)
+	}
+	return res, nil
+}
+
+// GitCommit returns the hash of the commit checked out in the git repository
+// containing dir, or an empty string if dir is not in a git repository
+func GitCommit(dir *paths.Path) string {
+	repo, err := git.PlainOpenWithOptions(dir.String(), &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return ""
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return ""
+	}
+	return head.Hash().String()
+}
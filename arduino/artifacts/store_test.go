@@ -0,0 +1,103 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package artifacts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "artifacts_store")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+
+	buildDir := tmp.Join("build")
+	require.NoError(t, buildDir.MkdirAll())
+	require.NoError(t, buildDir.Join("Blink.ino.hex").WriteFile([]byte("hex")))
+	require.NoError(t, buildDir.Join("Blink.ino.elf").WriteFile([]byte("elf")))
+	require.NoError(t, buildDir.Join("Other.ino.hex").WriteFile([]byte("other")))
+	require.NoError(t, buildDir.Join("Blink.ino.cpp.d").MkdirAll())
+
+	store := NewStore(tmp.Join("store"))
+	list, err := store.List()
+	require.NoError(t, err)
+	require.Empty(t, list)
+
+	a, err := store.Add(&Artifact{Tag: "v1", Sketch: "Blink", Fqbn: "arduino:avr:uno"}, buildDir, false)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"Blink.ino.hex", "Blink.ino.elf"}, a.Files)
+	require.True(t, a.Dir().Join("Blink.ino.hex").Exist())
+	require.False(t, a.Dir().Join("Other.ino.hex").Exist())
+
+	_, err = store.Add(&Artifact{Tag: "v1", Sketch: "Blink"}, buildDir, false)
+	require.Error(t, err)
+	_, err = store.Add(&Artifact{Tag: "../v1", Sketch: "Blink"}, buildDir, false)
+	require.Error(t, err)
+	_, err = store.Add(&Artifact{Tag: "none", Sketch: "Missing"}, buildDir, false)
+	require.Error(t, err)
+
+	old, err := store.Add(&Artifact{Tag: "v0", Sketch: "Blink", Created: time.Now().Add(-48 * time.Hour)}, buildDir, false)
+	require.NoError(t, err)
+	_, err = store.Add(&Artifact{Tag: "v-1", Sketch: "Blink", Created: time.Now().Add(-72 * time.Hour)}, buildDir, false)
+	require.NoError(t, err)
+	_, err = store.SetKeep("v-1", true)
+	require.NoError(t, err)
+
+	list, err = store.List()
+	require.NoError(t, err)
+	require.Len(t, list, 3)
+	require.Equal(t, "v1", list[0].Tag)
+	require.Equal(t, "v-1", list[2].Tag)
+	require.True(t, list[2].Keep)
+
+	got, err := store.Get("v1")
+	require.NoError(t, err)
+	require.Equal(t, "arduino:avr:uno", got.Fqbn)
+	require.Empty(t, got.Optimization)
+	_, err = store.Get("missing")
+	require.Error(t, err)
+
+	// Only the old artifacts not kept are pruned
+	pruned, err := store.Prune(time.Now().Add(-24*time.Hour), true)
+	require.NoError(t, err)
+	require.Len(t, pruned, 1)
+	require.Equal(t, "v0", pruned[0].Tag)
+	require.True(t, old.Dir().Exist())
+	pruned, err = store.Prune(time.Now().Add(-24*time.Hour), false)
+	require.NoError(t, err)
+	require.Len(t, pruned, 1)
+	require.False(t, old.Dir().Exist())
+
+	require.NoError(t, store.Remove("v-1"))
+	list, err = store.List()
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+
+	// The optimization preset is read from the build info saved with the binaries
+	info := &BuildInfo{Fqbn: "arduino:avr:uno", Optimization: "debug", OptimizationFlags: "-Og -g3"}
+	require.NoError(t, info.Save(BuildInfoFile(buildDir, "Blink.ino")))
+	a, err = store.Add(&Artifact{Tag: "v2", Sketch: "Blink", Fqbn: "arduino:avr:uno"}, buildDir, false)
+	require.NoError(t, err)
+	require.Equal(t, "debug", a.Optimization)
+	require.Contains(t, a.Files, "Blink.ino.build_info.json")
+	got, err = store.Get("v2")
+	require.NoError(t, err)
+	require.Equal(t, "debug", got.Optimization)
+}
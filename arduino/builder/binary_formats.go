@@ -0,0 +1,234 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/marcinbor85/gohex"
+	"github.com/pkg/errors"
+)
+
+// Binary formats that can be exported along with the ones produced by the
+// platform recipes
+const (
+	ExportFormatHex    = "hex"
+	ExportFormatBin    = "bin"
+	ExportFormatUF2    = "uf2"
+	ExportFormatMerged = "merged"
+)
+
+// ExportFormat is a binary format to export, with the optional address where
+// the application must be placed
+type ExportFormat struct {
+	Name      string
+	Offset    uint32
+	HasOffset bool
+}
+
+// ParseExportFormat parses an export format in the NAME or NAME:OFFSET form,
+// for example uf2:0x2000
+func ParseExportFormat(spec string) (*ExportFormat, error) {
+	parts := strings.SplitN(spec, ":", 2)
+	name := parts[0]
+	format := &ExportFormat{Name: name}
+	switch name {
+	case ExportFormatHex, ExportFormatBin, ExportFormatUF2, ExportFormatMerged:
+	default:
+		return nil, fmt.Errorf("invalid export format %s, allowed formats are: %s, %s, %s, %s", name, ExportFormatHex, ExportFormatBin, ExportFormatUF2, ExportFormatMerged)
+	}
+	if len(parts) == 2 {
+		value, err := strconv.ParseUint(parts[1], 0, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid offset %s for export format %s", parts[1], name)
+		}
+		format.Offset = uint32(value)
+		format.HasOffset = true
+	}
+	return format, nil
+}
+
+// FirmwareImage is a contiguous memory image of a firmware
+type FirmwareImage struct {
+	Address uint32
+	Data    []byte
+}
+
+// LoadHexImage loads an Intel HEX file, the gaps between its segments are
+// filled with 0xFF
+func LoadHexImage(file *paths.Path) (*FirmwareImage, error) {
+	data, err := file.ReadFile()
+	if err != nil {
+		return nil, err
+	}
+	mem := gohex.NewMemory()
+	if err := mem.ParseIntelHex(bytes.NewReader(data)); err != nil {
+		return nil, errors.Errorf("%s: %s", file, err)
+	}
+	segments := mem.GetDataSegments()
+	if len(segments) == 0 {
+		return nil, errors.Errorf("%s: no data found", file)
+	}
+	start := segments[0].Address
+	last := segments[len(segments)-1]
+	end := last.Address + uint32(len(last.Data))
+	return &FirmwareImage{Address: start, Data: mem.ToBinary(start, end-start, 0xFF)}, nil
+}
+
+// LoadBinImage loads a raw binary file that must be placed at the given address
+func LoadBinImage(file *paths.Path, address uint32) (*FirmwareImage, error) {
+	data, err := file.ReadFile()
+	if err != nil {
+		return nil, err
+	}
+	return &FirmwareImage{Address: address, Data: data}, nil
+}
+
+// ElfLoadAddress returns the lowest address where the loadable segments of an
+// ELF file are stored, that is the address of the raw binary extracted from it
+func ElfLoadAddress(file *paths.Path) (uint32, error) {
+	f, err := elf.Open(file.String())
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	found := false
+	var address uint64
+	for _, prog := range f.Progs {
+		if prog.Type != elf.PT_LOAD || prog.Filesz == 0 {
+			continue
+		}
+		if !found || prog.Paddr < address {
+			address = prog.Paddr
+			found = true
+		}
+	}
+	if !found {
+		return 0, errors.Errorf("%s: no loadable segments found", file)
+	}
+	return uint32(address), nil
+}
+
+// Slice returns the part of the image starting at the given address. If the
+// address is before the start of the image the gap is filled with 0xFF.
+func (img *FirmwareImage) Slice(address uint32) (*FirmwareImage, error) {
+	if address <= img.Address {
+		data := bytes.Repeat([]byte{0xFF}, int(img.Address-address))
+		return &FirmwareImage{Address: address, Data: append(data, img.Data...)}, nil
+	}
+	if end := img.Address + uint32(len(img.Data)); address >= end {
+		return nil, errors.Errorf("address 0x%x is beyond the end of the image (0x%x)", address, end)
+	}
+	return &FirmwareImage{Address: address, Data: img.Data[address-img.Address:]}, nil
+}
+
+// HexBytes returns the image in the Intel HEX format
+func (img *FirmwareImage) HexBytes() ([]byte, error) {
+	mem := gohex.NewMemory()
+	if err := mem.AddBinary(img.Address, img.Data); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := mem.DumpIntelHex(&buf, 16); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UF2 block layout, see https://github.com/microsoft/uf2
+const (
+	uf2MagicStart0    = 0x0A324655
+	uf2MagicStart1    = 0x9E5D5157
+	uf2MagicEnd       = 0x0AB16F30
+	uf2FlagFamilyID   = 0x00002000
+	uf2BlockSize      = 512
+	uf2PayloadSize    = 256
+	uf2DataAreaLength = 476
+)
+
+// uf2Families are the UF2 family IDs of the microcontrollers, by build.mcu
+var uf2Families = map[string]uint32{
+	"rp2040":   0xe48bff56,
+	"samd21":   0x68ed2b88,
+	"samd51":   0x55114460,
+	"nrf52":    0x1b57745f,
+	"nrf52840": 0xada52840,
+	"esp32s2":  0xbfdd4eee,
+	"esp32s3":  0xc47e5767,
+}
+
+// UF2FamilyID returns the UF2 family ID given as a number or as the name of
+// the microcontroller, a name is matched with the known families also by
+// prefix (for example build.mcu=samd21g18a matches samd21)
+func UF2FamilyID(family string) (uint32, error) {
+	if id, err := strconv.ParseUint(family, 0, 32); err == nil {
+		return uint32(id), nil
+	}
+	family = strings.ToLower(family)
+	if id, ok := uf2Families[family]; ok {
+		return id, nil
+	}
+	// the longest matching name wins, nrf52840 must be preferred to nrf52
+	names := []string{}
+	for name := range uf2Families {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+	for _, name := range names {
+		if strings.HasPrefix(family, name) {
+			return uf2Families[name], nil
+		}
+	}
+	return 0, fmt.Errorf("unknown UF2 family %s", family)
+}
+
+// UF2Bytes returns the image in the UF2 format, split in blocks of 256 bytes
+// tagged with the given family ID
+func (img *FirmwareImage) UF2Bytes(familyID uint32) []byte {
+	numBlocks := (len(img.Data) + uf2PayloadSize - 1) / uf2PayloadSize
+	var buf bytes.Buffer
+	for i := 0; i < numBlocks; i++ {
+		chunk := img.Data[i*uf2PayloadSize:]
+		if len(chunk) > uf2PayloadSize {
+			chunk = chunk[:uf2PayloadSize]
+		}
+		block := make([]byte, uf2BlockSize)
+		header := []uint32{
+			uf2MagicStart0,
+			uf2MagicStart1,
+			uf2FlagFamilyID,
+			img.Address + uint32(i*uf2PayloadSize),
+			uf2PayloadSize,
+			uint32(i),
+			uint32(numBlocks),
+			familyID,
+		}
+		for j, value := range header {
+			binary.LittleEndian.PutUint32(block[j*4:], value)
+		}
+		copy(block[32:32+uf2DataAreaLength], chunk)
+		binary.LittleEndian.PutUint32(block[uf2BlockSize-4:], uf2MagicEnd)
+		buf.Write(block)
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,150 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExportFormat(t *testing.T) {
+	format, err := ParseExportFormat("uf2")
+	require.NoError(t, err)
+	require.Equal(t, &ExportFormat{Name: ExportFormatUF2}, format)
+
+	format, err = ParseExportFormat("hex:0x2000")
+	require.NoError(t, err)
+	require.Equal(t, &ExportFormat{Name: ExportFormatHex, Offset: 0x2000, HasOffset: true}, format)
+
+	_, err = ParseExportFormat("elf")
+	require.Error(t, err)
+	_, err = ParseExportFormat("uf2:zero")
+	require.Error(t, err)
+}
+
+func TestUF2FamilyID(t *testing.T) {
+	id, err := UF2FamilyID("rp2040")
+	require.NoError(t, err)
+	require.Equal(t, uint32(0xe48bff56), id)
+
+	id, err = UF2FamilyID("SAMD21G18A")
+	require.NoError(t, err)
+	require.Equal(t, uint32(0x68ed2b88), id)
+
+	id, err = UF2FamilyID("nrf52840xxaa")
+	require.NoError(t, err)
+	require.Equal(t, uint32(0xada52840), id)
+
+	id, err = UF2FamilyID("0x12345678")
+	require.NoError(t, err)
+	require.Equal(t, uint32(0x12345678), id)
+
+	_, err = UF2FamilyID("atmega328p")
+	require.Error(t, err)
+}
+
+func TestFirmwareImageConversions(t *testing.T) {
+	data := make([]byte, 300)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	image := &FirmwareImage{Address: 0x2000, Data: data}
+
+	// hex round trip
+	hex, err := image.HexBytes()
+	require.NoError(t, err)
+	tmp, err := paths.MkTempDir("", "binary_formats")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+	hexFile := tmp.Join("sketch.hex")
+	require.NoError(t, hexFile.WriteFile(hex))
+	loaded, err := LoadHexImage(hexFile)
+	require.NoError(t, err)
+	require.Equal(t, image, loaded)
+
+	// the image is split in 2 UF2 blocks of 512 bytes
+	uf2 := image.UF2Bytes(0xe48bff56)
+	require.Len(t, uf2, 1024)
+	second := uf2[512:]
+	require.Equal(t, uint32(0x0A324655), binary.LittleEndian.Uint32(second[0:]))
+	require.Equal(t, uint32(0x2100), binary.LittleEndian.Uint32(second[12:]))
+	require.Equal(t, uint32(1), binary.LittleEndian.Uint32(second[20:]))
+	require.Equal(t, uint32(2), binary.LittleEndian.Uint32(second[24:]))
+	require.Equal(t, uint32(0xe48bff56), binary.LittleEndian.Uint32(second[28:]))
+	require.Equal(t, data[256:], second[32:32+44])
+	require.Equal(t, uint32(0x0AB16F30), binary.LittleEndian.Uint32(second[508:]))
+}
+
+func TestFirmwareImageSlice(t *testing.T) {
+	image := &FirmwareImage{Address: 0x2000, Data: []byte{1, 2, 3, 4}}
+
+	slice, err := image.Slice(0x2002)
+	require.NoError(t, err)
+	require.Equal(t, &FirmwareImage{Address: 0x2002, Data: []byte{3, 4}}, slice)
+
+	slice, err = image.Slice(0x1ffe)
+	require.NoError(t, err)
+	require.Equal(t, &FirmwareImage{Address: 0x1ffe, Data: []byte{0xFF, 0xFF, 1, 2, 3, 4}}, slice)
+
+	_, err = image.Slice(0x2004)
+	require.Error(t, err)
+}
+
+// writeTestElf writes a 32 bit ELF file with a loadable segment for each of
+// the given physical addresses
+func writeTestElf(t *testing.T, file *paths.Path, addresses ...uint32) {
+	le := binary.LittleEndian
+	header := make([]byte, 52)
+	copy(header, []byte{0x7f, 'E', 'L', 'F', 1, 1, 1})
+	le.PutUint16(header[16:], 2)  // executable
+	le.PutUint16(header[18:], 40) // ARM
+	le.PutUint32(header[20:], 1)
+	le.PutUint32(header[28:], 52) // program headers offset
+	le.PutUint16(header[40:], 52)
+	le.PutUint16(header[42:], 32)
+	le.PutUint16(header[44:], uint16(len(addresses)))
+	le.PutUint16(header[46:], 40)
+	data := header
+	for _, address := range addresses {
+		prog := make([]byte, 32)
+		le.PutUint32(prog[0:], 1) // PT_LOAD
+		le.PutUint32(prog[8:], address)
+		le.PutUint32(prog[12:], address)
+		le.PutUint32(prog[16:], 4)
+		le.PutUint32(prog[20:], 4)
+		data = append(data, prog...)
+	}
+	require.NoError(t, file.WriteFile(data))
+}
+
+func TestElfLoadAddress(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "binary_formats")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+
+	elfFile := tmp.Join("sketch.elf")
+	writeTestElf(t, elfFile, 0x10000100, 0x10000000)
+	address, err := ElfLoadAddress(elfFile)
+	require.NoError(t, err)
+	require.Equal(t, uint32(0x10000000), address)
+
+	writeTestElf(t, elfFile)
+	_, err = ElfLoadAddress(elfFile)
+	require.Error(t, err)
+}
@@ -0,0 +1,304 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/arduino/go-paths-helper"
+)
+
+// BuildScriptFormat is the format of an exported build script
+type BuildScriptFormat string
+
+const (
+	// BuildScriptMakefile exports the build as a Makefile
+	BuildScriptMakefile BuildScriptFormat = "makefile"
+	// BuildScriptNinja exports the build as a build.ninja file
+	BuildScriptNinja BuildScriptFormat = "ninja"
+)
+
+// ParseBuildScriptFormat returns the BuildScriptFormat with the given name
+func ParseBuildScriptFormat(name string) (BuildScriptFormat, error) {
+	switch format := BuildScriptFormat(strings.ToLower(name)); format {
+	case BuildScriptMakefile, BuildScriptNinja:
+		return format, nil
+	}
+	return "", fmt.Errorf("invalid build script format %s, allowed formats are: %s, %s", name, BuildScriptMakefile, BuildScriptNinja)
+}
+
+// FileName returns the conventional name of a build script in this format
+func (f BuildScriptFormat) FileName() string {
+	if f == BuildScriptNinja {
+		return "build.ninja"
+	}
+	return "Makefile"
+}
+
+// BuildScript keeps track of all the commands run by the builder, with the
+// files they read and write, to export the build as a Makefile or as a
+// build.ninja file that can be run without arduino-cli.
+type BuildScript struct {
+	Steps  []*BuildStep
+	File   *paths.Path
+	Format BuildScriptFormat
+
+	// the builder may add steps from many compile jobs at once
+	lock sync.Mutex
+}
+
+// BuildStep is a single command run by the builder. A step without outputs,
+// like a hook, is run in the same order as the builder did.
+type BuildStep struct {
+	Directory string
+	Arguments []string
+	Inputs    []string
+	Outputs   []string
+}
+
+// NewBuildScript creates an empty BuildScript that is saved in file
+func NewBuildScript(file *paths.Path, format BuildScriptFormat) *BuildScript {
+	return &BuildScript{
+		File:   file,
+		Format: format,
+		Steps:  []*BuildStep{},
+	}
+}
+
+// Add adds the command to the BuildScript
+func (s *BuildScript) Add(command *exec.Cmd, inputs, outputs paths.PathList) {
+	step := &BuildStep{
+		Directory: command.Dir,
+		Arguments: command.Args,
+		Inputs:    inputs.AsStrings(),
+		Outputs:   outputs.AsStrings(),
+	}
+
+	s.lock.Lock()
+	s.Steps = append(s.Steps, step)
+	s.lock.Unlock()
+}
+
+// SaveToFile writes the BuildScript to its file in its format
+func (s *BuildScript) SaveToFile() error {
+	var data []byte
+	if s.Format == BuildScriptNinja {
+		data = s.Ninja()
+	} else {
+		data = s.Makefile()
+	}
+	return s.File.WriteFile(data)
+}
+
+// buildRule is a target of the build script: the steps writing the same
+// files, like the many runs of the archiver on a library, are merged
+type buildRule struct {
+	name     string
+	outputs  []string
+	inputs   []string
+	orderBy  string
+	commands [][]string
+	dirs     []string
+}
+
+// rules groups the steps by output. The steps without outputs become
+// phony rules depending on everything built before them, and everything
+// built after them is ordered after them without being rebuilt every time.
+func (s *BuildScript) rules() []*buildRule {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	rules := []*buildRule{}
+	byOutput := map[string]*buildRule{}
+	built := []string{}
+	barrier := ""
+	phonySteps := 0
+	for _, step := range s.Steps {
+		if len(step.Outputs) == 0 {
+			phonySteps++
+			rule := &buildRule{
+				name:   fmt.Sprintf("step_%d", phonySteps),
+				inputs: append([]string{}, built...),
+			}
+			if barrier != "" {
+				rule.inputs = append(rule.inputs, barrier)
+			}
+			rule.commands = [][]string{step.Arguments}
+			rule.dirs = []string{step.Directory}
+			rules = append(rules, rule)
+			barrier = rule.name
+			continue
+		}
+
+		rule, ok := byOutput[step.Outputs[0]]
+		if !ok {
+			rule = &buildRule{outputs: step.Outputs, orderBy: barrier}
+			for _, output := range step.Outputs {
+				byOutput[output] = rule
+			}
+			rules = append(rules, rule)
+			built = append(built, step.Outputs...)
+		}
+		for _, input := range step.Inputs {
+			if !containsString(rule.inputs, input) {
+				rule.inputs = append(rule.inputs, input)
+			}
+		}
+		rule.commands = append(rule.commands, step.Arguments)
+		rule.dirs = append(rule.dirs, step.Directory)
+	}
+	return rules
+}
+
+// targets returns the names of the files and of the phony steps of the rule
+func (r *buildRule) targets() []string {
+	if r.name != "" {
+		return []string{r.name}
+	}
+	return r.outputs
+}
+
+// shellCommands returns the command lines of the rule. When many commands
+// write the same files, as the archiver does, the files are removed first
+// so they are recreated from scratch.
+func (r *buildRule) shellCommands() []string {
+	res := []string{}
+	if len(r.commands) > 1 {
+		res = append(res, "rm -f "+shellQuoteList(r.outputs))
+	}
+	for i, args := range r.commands {
+		line := shellQuoteList(args)
+		if r.dirs[i] != "" {
+			line = "cd " + shellQuote(r.dirs[i]) + " && " + line
+		}
+		res = append(res, line)
+	}
+	return res
+}
+
+// Makefile renders the BuildScript as a Makefile
+func (s *BuildScript) Makefile() []byte {
+	rules := s.rules()
+	escapeTarget := func(target string) string {
+		target = strings.Replace(target, "$", "$$", -1)
+		target = strings.Replace(target, " ", "\\ ", -1)
+		return strings.Replace(target, ":", "\\:", -1)
+	}
+	escapeTargets := func(targets []string) string {
+		res := []string{}
+		for _, target := range targets {
+			res = append(res, escapeTarget(target))
+		}
+		return strings.Join(res, " ")
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("# Generated by arduino-cli\n\n")
+	all := []string{}
+	phony := []string{"all"}
+	for _, rule := range rules {
+		all = append(all, rule.targets()...)
+		if rule.name != "" {
+			phony = append(phony, rule.name)
+		}
+	}
+	buf.WriteString(".PHONY: " + strings.Join(phony, " ") + "\n\n")
+	buf.WriteString("all: " + escapeTargets(all) + "\n")
+	for _, rule := range rules {
+		buf.WriteString("\n" + escapeTargets(rule.targets()) + ":")
+		if len(rule.inputs) > 0 {
+			buf.WriteString(" " + escapeTargets(rule.inputs))
+		}
+		if rule.orderBy != "" {
+			buf.WriteString(" | " + rule.orderBy)
+		}
+		buf.WriteString("\n")
+		for _, line := range rule.shellCommands() {
+			buf.WriteString("\t" + strings.Replace(line, "$", "$$", -1) + "\n")
+		}
+	}
+	return buf.Bytes()
+}
+
+// Ninja renders the BuildScript as a build.ninja file
+func (s *BuildScript) Ninja() []byte {
+	rules := s.rules()
+	escapePath := func(path string) string {
+		path = strings.Replace(path, "$", "$$", -1)
+		path = strings.Replace(path, " ", "$ ", -1)
+		return strings.Replace(path, ":", "$:", -1)
+	}
+	escapePaths := func(paths []string) string {
+		res := []string{}
+		for _, path := range paths {
+			res = append(res, escapePath(path))
+		}
+		return strings.Join(res, " ")
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("# Generated by arduino-cli\n\n")
+	buf.WriteString("rule run\n  command = $cmd\n")
+	all := []string{}
+	for _, rule := range rules {
+		all = append(all, rule.targets()...)
+		buf.WriteString("\nbuild " + escapePaths(rule.targets()) + ": run")
+		if len(rule.inputs) > 0 {
+			buf.WriteString(" " + escapePaths(rule.inputs))
+		}
+		if rule.orderBy != "" {
+			buf.WriteString(" || " + rule.orderBy)
+		}
+		buf.WriteString("\n")
+		command := strings.Join(rule.shellCommands(), " && ")
+		buf.WriteString("  cmd = " + strings.Replace(command, "$", "$$", -1) + "\n")
+	}
+	buf.WriteString("\nbuild all: phony " + escapePaths(all) + "\n")
+	buf.WriteString("\ndefault all\n")
+	return buf.Bytes()
+}
+
+// shellQuote quotes the argument for a POSIX shell, if needed
+func shellQuote(arg string) string {
+	if arg == "" {
+		return "''"
+	}
+	if !strings.ContainsAny(arg, " \t\n\"'\\$`!*?[]{}()<>|&;#~") {
+		return arg
+	}
+	return "'" + strings.Replace(arg, "'", `'\''`, -1) + "'"
+}
+
+func shellQuoteList(args []string) string {
+	res := []string{}
+	for _, arg := range args {
+		res = append(res, shellQuote(arg))
+	}
+	return strings.Join(res, " ")
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func testBuildScript() *BuildScript {
+	script := NewBuildScript(nil, BuildScriptMakefile)
+	script.Add(exec.Command("echo", "prebuild"), nil, nil)
+	script.Add(exec.Command("gcc", "-c", "my sketch.cpp", "-o", "sketch.o"), paths.NewPathList("my sketch.cpp"), paths.NewPathList("sketch.o"))
+	script.Add(exec.Command("gcc", "-c", "a.c", "-o", "a.o"), paths.NewPathList("a.c"), paths.NewPathList("a.o"))
+	script.Add(exec.Command("gcc", "-c", "b.c", "-o", "b.o"), paths.NewPathList("b.c"), paths.NewPathList("b.o"))
+	script.Add(exec.Command("ar", "rcs", "core.a", "a.o"), paths.NewPathList("a.o"), paths.NewPathList("core.a"))
+	script.Add(exec.Command("ar", "rcs", "core.a", "b.o"), paths.NewPathList("b.o"), paths.NewPathList("core.a"))
+	script.Add(exec.Command("gcc", "-o", "sketch.elf", "sketch.o", "core.a", "-Wl,-Map,$out"), paths.NewPathList("sketch.o", "core.a"), paths.NewPathList("sketch.elf"))
+	script.Add(exec.Command("objcopy", "-O", "ihex", "sketch.elf", "sketch.hex"), nil, nil)
+	return script
+}
+
+func TestParseBuildScriptFormat(t *testing.T) {
+	format, err := ParseBuildScriptFormat("Ninja")
+	require.NoError(t, err)
+	require.Equal(t, BuildScriptNinja, format)
+	require.Equal(t, "build.ninja", format.FileName())
+
+	format, err = ParseBuildScriptFormat("makefile")
+	require.NoError(t, err)
+	require.Equal(t, "Makefile", format.FileName())
+
+	_, err = ParseBuildScriptFormat("scons")
+	require.Error(t, err)
+}
+
+func TestBuildScriptMakefile(t *testing.T) {
+	require.Equal(t, ""+
+		"# Generated by arduino-cli\n"+
+		"\n"+
+		".PHONY: all step_1 step_2\n"+
+		"\n"+
+		"all: step_1 sketch.o a.o b.o core.a sketch.elf step_2\n"+
+		"\n"+
+		"step_1:\n"+
+		"\techo prebuild\n"+
+		"\n"+
+		"sketch.o: my\\ sketch.cpp | step_1\n"+
+		"\tgcc -c 'my sketch.cpp' -o sketch.o\n"+
+		"\n"+
+		"a.o: a.c | step_1\n"+
+		"\tgcc -c a.c -o a.o\n"+
+		"\n"+
+		"b.o: b.c | step_1\n"+
+		"\tgcc -c b.c -o b.o\n"+
+		"\n"+
+		"core.a: a.o b.o | step_1\n"+
+		"\trm -f core.a\n"+
+		"\tar rcs core.a a.o\n"+
+		"\tar rcs core.a b.o\n"+
+		"\n"+
+		"sketch.elf: sketch.o core.a | step_1\n"+
+		"\tgcc -o sketch.elf sketch.o core.a '-Wl,-Map,$$out'\n"+
+		"\n"+
+		"step_2: sketch.o a.o b.o core.a sketch.elf step_1\n"+
+		"\tobjcopy -O ihex sketch.elf sketch.hex\n",
+		string(testBuildScript().Makefile()))
+}
+
+func TestBuildScriptNinja(t *testing.T) {
+	require.Equal(t, ""+
+		"# Generated by arduino-cli\n"+
+		"\n"+
+		"rule run\n"+
+		"  command = $cmd\n"+
+		"\n"+
+		"build step_1: run\n"+
+		"  cmd = echo prebuild\n"+
+		"\n"+
+		"build sketch.o: run my$ sketch.cpp || step_1\n"+
+		"  cmd = gcc -c 'my sketch.cpp' -o sketch.o\n"+
+		"\n"+
+		"build a.o: run a.c || step_1\n"+
+		"  cmd = gcc -c a.c -o a.o\n"+
+		"\n"+
+		"build b.o: run b.c || step_1\n"+
+		"  cmd = gcc -c b.c -o b.o\n"+
+		"\n"+
+		"build core.a: run a.o b.o || step_1\n"+
+		"  cmd = rm -f core.a && ar rcs core.a a.o && ar rcs core.a b.o\n"+
+		"\n"+
+		"build sketch.elf: run sketch.o core.a || step_1\n"+
+		"  cmd = gcc -o sketch.elf sketch.o core.a '-Wl,-Map,$$out'\n"+
+		"\n"+
+		"build step_2: run sketch.o a.o b.o core.a sketch.elf step_1\n"+
+		"  cmd = objcopy -O ihex sketch.elf sketch.hex\n"+
+		"\n"+
+		"build all: phony step_1 sketch.o a.o b.o core.a sketch.elf step_2\n"+
+		"\n"+
+		"default all\n",
+		string(testBuildScript().Ninja()))
+}
+
+func TestBuildScriptSaveToFile(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+
+	script := testBuildScript()
+	script.File = tmp.Join("build.ninja")
+	script.Format = BuildScriptNinja
+	require.NoError(t, script.SaveToFile())
+	data, err := script.File.ReadFile()
+	require.NoError(t, err)
+	require.Equal(t, script.Ninja(), data)
+}
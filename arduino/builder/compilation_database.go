@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"sync"
 
 	"github.com/arduino/go-paths-helper"
 )
@@ -28,6 +29,9 @@ import (
 type CompilationDatabase struct {
 	Contents []CompilationCommand
 	File     *paths.Path
+
+	// the builder may add entries from many compile jobs at once
+	lock sync.Mutex
 }
 
 // CompilationCommand keeps track of a single run of a compile command
@@ -89,5 +93,7 @@ func (db *CompilationDatabase) Add(target *paths.Path, command *exec.Cmd) {
 		File:      target.String(),
 	}
 
+	db.lock.Lock()
 	db.Contents = append(db.Contents, entry)
+	db.lock.Unlock()
 }
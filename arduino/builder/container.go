@@ -0,0 +1,119 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/arduino/go-paths-helper"
+)
+
+// Container runs the commands of the build inside a Docker or Podman
+// container. The folders are mounted at the same path they have on the host,
+// so the command lines don't need to be changed.
+type Container struct {
+	engine    string
+	image     string
+	readOnly  paths.PathList
+	readWrite paths.PathList
+}
+
+// NewContainer returns a Container that runs the commands in the given image
+// using the given engine (docker or podman).
+func NewContainer(engine, image string) (*Container, error) {
+	switch engine {
+	case "":
+		engine = "docker"
+	case "docker", "podman":
+	default:
+		return nil, fmt.Errorf("invalid container engine: %s", engine)
+	}
+	if image == "" {
+		return nil, fmt.Errorf("missing container image")
+	}
+	if runtime.GOOS == "windows" {
+		return nil, fmt.Errorf("building inside a container is not supported on Windows")
+	}
+	return &Container{engine: engine, image: image}, nil
+}
+
+// MountReadOnly makes the given folders available, read-only, in the
+// container. The folders that don't exist are ignored.
+func (c *Container) MountReadOnly(dirs ...*paths.Path) {
+	for _, dir := range dirs {
+		if dir != nil && dir.IsDir() {
+			c.readOnly.AddIfMissing(dir)
+		}
+	}
+}
+
+// MountReadWrite makes the given folders available, writable, in the
+// container. The folders that don't exist are ignored.
+func (c *Container) MountReadWrite(dirs ...*paths.Path) {
+	for _, dir := range dirs {
+		if dir != nil && dir.IsDir() {
+			c.readWrite.AddIfMissing(dir)
+		}
+	}
+}
+
+// Wrap returns a command that runs the given command inside the container.
+// The network is disabled and the command runs with the user of the host, so
+// the files written in the writable folders are owned by the user.
+func (c *Container) Wrap(command *exec.Cmd) *exec.Cmd {
+	args := []string{"run", "--rm", "--network", "none"}
+	if uid, gid := os.Getuid(), os.Getgid(); uid >= 0 {
+		args = append(args, "--user", fmt.Sprintf("%d:%d", uid, gid))
+	}
+	// Writable folders are mounted last, so they take precedence when
+	// nested in a read-only folder
+	for _, dir := range c.readOnly {
+		args = append(args, "--volume", dir.String()+":"+dir.String()+":ro")
+	}
+	for _, dir := range c.readWrite {
+		args = append(args, "--volume", dir.String()+":"+dir.String())
+	}
+	workDir := command.Dir
+	if workDir == "" {
+		workDir, _ = os.Getwd()
+	}
+	if workDir != "" {
+		args = append(args, "--workdir", workDir)
+	}
+	// Only the variables set explicitly for the command are passed, the
+	// environment of the host must not leak in the build
+	hostEnv := map[string]bool{}
+	for _, env := range os.Environ() {
+		hostEnv[env] = true
+	}
+	for _, env := range command.Env {
+		if !hostEnv[env] && strings.Contains(env, "=") {
+			args = append(args, "--env", env)
+		}
+	}
+	args = append(args, c.image)
+	args = append(args, command.Args...)
+
+	wrapped := exec.Command(c.engine, args...)
+	wrapped.Stdin = command.Stdin
+	wrapped.Stdout = command.Stdout
+	wrapped.Stderr = command.Stderr
+	return wrapped
+}
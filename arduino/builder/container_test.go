@@ -0,0 +1,77 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContainerWrap(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("containers are not supported on Windows")
+	}
+	_, err := NewContainer("lxc", "image")
+	require.Error(t, err)
+	_, err = NewContainer("podman", "")
+	require.Error(t, err)
+
+	tmp, err := paths.MkTempDir("", "container_test")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+	sketch := tmp.Join("sketch")
+	build := sketch.Join("build")
+	require.NoError(t, build.MkdirAll())
+
+	container, err := NewContainer("", "gcc:10")
+	require.NoError(t, err)
+	container.MountReadOnly(sketch, tmp.Join("missing"), sketch)
+	container.MountReadWrite(build)
+
+	command := exec.Command("/opt/tools/bin/gcc", "-c", sketch.Join("sketch.cpp").String())
+	command.Dir = build.String()
+	command.Env = append(os.Environ(), "ARDUINO_BUILD_PATH="+build.String())
+	wrapped := container.Wrap(command)
+	require.Equal(t, "docker", wrapped.Args[0])
+	require.Contains(t, wrapped.Args, sketch.String()+":"+sketch.String()+":ro")
+	require.Contains(t, wrapped.Args, build.String()+":"+build.String())
+	require.NotContains(t, wrapped.Args, tmp.Join("missing").String()+":"+tmp.Join("missing").String()+":ro")
+	require.Contains(t, wrapped.Args, "ARDUINO_BUILD_PATH="+build.String())
+	require.Equal(t, []string{"gcc:10", "/opt/tools/bin/gcc", "-c", sketch.Join("sketch.cpp").String()}, wrapped.Args[len(wrapped.Args)-4:])
+
+	// Only the variables set for the command are passed to the container
+	envs := 0
+	for _, arg := range wrapped.Args {
+		if arg == "--env" {
+			envs++
+		}
+	}
+	require.Equal(t, 1, envs)
+
+	// Read-only mounts are listed once
+	mounts := 0
+	for _, arg := range wrapped.Args {
+		if arg == sketch.String()+":"+sketch.String()+":ro" {
+			mounts++
+		}
+	}
+	require.Equal(t, 1, mounts)
+}
@@ -0,0 +1,188 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/pkg/errors"
+)
+
+// CoreCacheEvictionPolicy selects which archives are removed from a CoreCache
+// when the size limit is exceeded
+type CoreCacheEvictionPolicy string
+
+const (
+	// CoreCacheEvictLRU removes the least recently used archives first
+	CoreCacheEvictLRU CoreCacheEvictionPolicy = "lru"
+	// CoreCacheEvictFIFO removes the oldest archives first, regardless of their usage
+	CoreCacheEvictFIFO CoreCacheEvictionPolicy = "fifo"
+)
+
+// CoreCache is a persistent cache of compiled core archives, shared between
+// all the sketches. Each archive is stored under a key that identifies the
+// board, the compiler flags and the core sources used to build it.
+type CoreCache struct {
+	dir     *paths.Path
+	maxSize int64
+	policy  CoreCacheEvictionPolicy
+}
+
+// NewCoreCache returns a CoreCache stored in dir. When the total size of the
+// archives exceeds maxSize bytes, archives are evicted following the given
+// policy. A maxSize of 0 means no limit.
+func NewCoreCache(dir *paths.Path, maxSize int64, policy CoreCacheEvictionPolicy) (*CoreCache, error) {
+	switch policy {
+	case "":
+		policy = CoreCacheEvictLRU
+	case CoreCacheEvictLRU, CoreCacheEvictFIFO:
+	default:
+		return nil, fmt.Errorf("invalid core cache eviction policy: %s", policy)
+	}
+	return &CoreCache{dir: dir, maxSize: maxSize, policy: policy}, nil
+}
+
+// ArchivePath returns the path of the archive stored with the given key
+func (c *CoreCache) ArchivePath(key string) *paths.Path {
+	return c.dir.Join("core_" + key + ".a")
+}
+
+// Get returns the archive stored with the given key, or nil if the cache
+// does not contain it.
+func (c *CoreCache) Get(key string) *paths.Path {
+	archive := c.ArchivePath(key)
+	if !archive.Exist() {
+		return nil
+	}
+	if c.policy == CoreCacheEvictLRU {
+		// the modification time is used to track the last usage
+		now := time.Now()
+		_ = archive.Chtimes(now, now)
+	}
+	return archive
+}
+
+// Put stores a copy of the archive with the given key, evicting other
+// archives if needed to stay within the size limit.
+func (c *CoreCache) Put(key string, archive *paths.Path) (*paths.Path, error) {
+	if err := c.dir.MkdirAll(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	target := c.ArchivePath(key)
+	if err := archive.CopyTo(target); err != nil {
+		return nil, err
+	}
+	if err := c.Evict(target); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return target, nil
+}
+
+// Evict removes archives from the cache until the total size is within the
+// limit. The archives listed in keep are never removed.
+func (c *CoreCache) Evict(keep ...*paths.Path) error {
+	if c.maxSize <= 0 {
+		return nil
+	}
+	files, err := c.dir.ReadDir()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	files.FilterPrefix("core_")
+	files.FilterSuffix(".a")
+
+	type entry struct {
+		path *paths.Path
+		info os.FileInfo
+	}
+	entries := []*entry{}
+	total := int64(0)
+	for _, file := range files {
+		info, err := file.Stat()
+		if err != nil {
+			continue
+		}
+		entries = append(entries, &entry{path: file, info: info})
+		total += info.Size()
+	}
+	// Both policies evict the archive with the oldest modification time,
+	// LRU refreshes it each time an archive is used.
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].info.ModTime().Before(entries[j].info.ModTime())
+	})
+	keepList := paths.PathList(keep)
+	for _, e := range entries {
+		if total <= c.maxSize {
+			break
+		}
+		if keepList.Contains(e.path) {
+			continue
+		}
+		if err := e.path.Remove(); err != nil {
+			return errors.WithStack(err)
+		}
+		total -= e.info.Size()
+	}
+	return nil
+}
+
+// CoreCacheKey computes the cache key of a core archive. The key is the hash
+// of the FQBN (including the menu options), of the expanded compile recipes
+// and of the content of all the files in the given folders.
+func CoreCacheKey(fqbn string, recipes []string, folders ...*paths.Path) (string, error) {
+	hash := sha256.New()
+	fmt.Fprintln(hash, fqbn)
+	for _, recipe := range recipes {
+		fmt.Fprintln(hash, recipe)
+	}
+	for _, folder := range folders {
+		if folder == nil || !folder.IsDir() {
+			continue
+		}
+		files, err := folder.ReadDirRecursive()
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		files.Sort()
+		for _, file := range files {
+			if file.IsDir() {
+				continue
+			}
+			rel, err := file.RelTo(folder)
+			if err != nil {
+				return "", errors.WithStack(err)
+			}
+			fmt.Fprintln(hash, rel.String())
+			f, err := file.Open()
+			if err != nil {
+				return "", errors.WithStack(err)
+			}
+			_, err = io.Copy(hash, f)
+			f.Close()
+			if err != nil {
+				return "", errors.WithStack(err)
+			}
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
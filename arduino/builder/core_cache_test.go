@@ -0,0 +1,95 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoreCacheKey(t *testing.T) {
+	coreFolder, err := paths.MkTempDir("", "core_cache_test")
+	require.NoError(t, err)
+	defer coreFolder.RemoveAll()
+	require.NoError(t, coreFolder.Join("Arduino.h").WriteFile([]byte("void setup();")))
+
+	key, err := CoreCacheKey("arduino:avr:uno", []string{"gcc -Os"}, coreFolder)
+	require.NoError(t, err)
+
+	otherKey, err := CoreCacheKey("arduino:avr:nano:cpu=atmega328", []string{"gcc -Os"}, coreFolder)
+	require.NoError(t, err)
+	require.NotEqual(t, key, otherKey)
+
+	otherKey, err = CoreCacheKey("arduino:avr:uno", []string{"gcc -Og"}, coreFolder)
+	require.NoError(t, err)
+	require.NotEqual(t, key, otherKey)
+
+	// Touching a file doesn't change the key, changing its content does
+	now := time.Now().Add(time.Hour)
+	require.NoError(t, coreFolder.Join("Arduino.h").Chtimes(now, now))
+	sameKey, err := CoreCacheKey("arduino:avr:uno", []string{"gcc -Os"}, coreFolder)
+	require.NoError(t, err)
+	require.Equal(t, key, sameKey)
+
+	require.NoError(t, coreFolder.Join("Arduino.h").WriteFile([]byte("void loop();")))
+	otherKey, err = CoreCacheKey("arduino:avr:uno", []string{"gcc -Os"}, coreFolder)
+	require.NoError(t, err)
+	require.NotEqual(t, key, otherKey)
+}
+
+func TestCoreCacheEviction(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "core_cache_test")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+	archive := tmp.Join("core.a")
+	require.NoError(t, archive.WriteFile(make([]byte, 100)))
+
+	for _, policy := range []CoreCacheEvictionPolicy{CoreCacheEvictLRU, CoreCacheEvictFIFO} {
+		cacheDir := tmp.Join(string(policy))
+		cache, err := NewCoreCache(cacheDir, 250, policy)
+		require.NoError(t, err)
+
+		// Store two archives, then use the oldest one
+		_, err = cache.Put("one", archive)
+		require.NoError(t, err)
+		past := time.Now().Add(-time.Hour)
+		require.NoError(t, cache.ArchivePath("one").Chtimes(past, past))
+		_, err = cache.Put("two", archive)
+		require.NoError(t, err)
+		later := past.Add(30 * time.Minute)
+		require.NoError(t, cache.ArchivePath("two").Chtimes(later, later))
+		require.NotNil(t, cache.Get("one"))
+		require.Nil(t, cache.Get("three"))
+
+		// The third archive exceeds the limit
+		_, err = cache.Put("three", archive)
+		require.NoError(t, err)
+		require.True(t, cache.ArchivePath("three").Exist())
+		if policy == CoreCacheEvictLRU {
+			require.True(t, cache.ArchivePath("one").Exist())
+			require.False(t, cache.ArchivePath("two").Exist())
+		} else {
+			require.False(t, cache.ArchivePath("one").Exist())
+			require.True(t, cache.ArchivePath("two").Exist())
+		}
+	}
+
+	_, err = NewCoreCache(tmp, 0, "random")
+	require.Error(t, err)
+}
@@ -0,0 +1,152 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+)
+
+// Diagnostic is a message emitted by the compiler about a source file
+type Diagnostic struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column,omitempty"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// ToRPCCompileDiagnostic converts the Diagnostic into a rpc.CompileDiagnostic
+func (d *Diagnostic) ToRPCCompileDiagnostic() *rpc.CompileDiagnostic {
+	return &rpc.CompileDiagnostic{
+		File:     d.File,
+		Line:     int32(d.Line),
+		Column:   int32(d.Column),
+		Severity: d.Severity,
+		Message:  d.Message,
+	}
+}
+
+// gccDiagnosticRegexp matches the "file:line:column: severity: message" lines
+// printed by GCC, the column is optional.
+var gccDiagnosticRegexp = regexp.MustCompile(`^(.+?):(\d+):(?:(\d+):)? (fatal error|error|warning|note): (.*)$`)
+
+// ParseGCCDiagnostics extracts the diagnostics from the output of GCC.
+// Duplicated diagnostics, for example a warning in a header included by many
+// source files, are reported only once.
+func ParseGCCDiagnostics(output []byte) []*Diagnostic {
+	res := []*Diagnostic{}
+	seen := map[Diagnostic]bool{}
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		match := gccDiagnosticRegexp.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+		diag := Diagnostic{File: match[1], Severity: match[4], Message: match[5]}
+		diag.Line, _ = strconv.Atoi(match[2])
+		if match[3] != "" {
+			diag.Column, _ = strconv.Atoi(match[3])
+		}
+		if diag.Severity == "fatal error" {
+			diag.Severity = "error"
+		}
+		if seen[diag] {
+			continue
+		}
+		seen[diag] = true
+		res = append(res, &diag)
+	}
+	return res
+}
+
+// CompileWarningPatterns compiles the regular expressions used to select the
+// warnings to promote to errors
+func CompileWarningPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	res := []*regexp.Regexp{}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid warning pattern %s: %w", pattern, err)
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+// PromoteWarnings changes to errors the warnings with a message matching any
+// of the patterns, and returns the promoted diagnostics.
+func PromoteWarnings(diagnostics []*Diagnostic, patterns []*regexp.Regexp) []*Diagnostic {
+	promoted := []*Diagnostic{}
+	for _, diag := range diagnostics {
+		if diag.Severity != "warning" {
+			continue
+		}
+		for _, pattern := range patterns {
+			if pattern.MatchString(diag.Message) {
+				diag.Severity = "error"
+				promoted = append(promoted, diag)
+				break
+			}
+		}
+	}
+	return promoted
+}
+
+// DiagnosticsCollector is an io.Writer that accumulates the output of the
+// compiler to extract the diagnostics. It may be written concurrently by
+// many compile jobs.
+type DiagnosticsCollector struct {
+	// FailOnWarning are the patterns of the warnings promoted to errors
+	FailOnWarning []*regexp.Regexp
+
+	lock   sync.Mutex
+	output bytes.Buffer
+}
+
+// Write implements io.Writer
+func (c *DiagnosticsCollector) Write(p []byte) (int, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.output.Write(p)
+}
+
+// Diagnostics returns the diagnostics found in the collected output, with the
+// warnings matching FailOnWarning promoted to errors
+func (c *DiagnosticsCollector) Diagnostics() []*Diagnostic {
+	diagnostics, _ := c.parse()
+	return diagnostics
+}
+
+// PromotedWarnings returns the warnings in the collected output that match
+// FailOnWarning
+func (c *DiagnosticsCollector) PromotedWarnings() []*Diagnostic {
+	_, promoted := c.parse()
+	return promoted
+}
+
+func (c *DiagnosticsCollector) parse() ([]*Diagnostic, []*Diagnostic) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	diagnostics := ParseGCCDiagnostics(c.output.Bytes())
+	return diagnostics, PromoteWarnings(diagnostics, c.FailOnWarning)
+}
@@ -0,0 +1,75 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGCCDiagnostics(t *testing.T) {
+	output := `In file included from /home/user/Arduino/Blink/Blink.ino:1:0:
+/home/user/Arduino/Blink/config.h:3:9: warning: "LED" redefined
+ #define LED 13
+         ^~~
+/home/user/Arduino/Blink/Blink.ino: In function 'void loop()':
+/home/user/Arduino/Blink/Blink.ino:12:3: error: 'foo' was not declared in this scope
+   foo();
+   ^~~
+/home/user/Arduino/Blink/Blink.ino:12:3: note: suggested alternative: 'for'
+C:\Users\user\Arduino\Blink\other.cpp:5: error: expected ';' before '}' token
+/home/user/Arduino/Blink/missing.cpp:1:10: fatal error: missing.h: No such file or directory
+/home/user/Arduino/Blink/config.h:3:9: warning: "LED" redefined
+compilation terminated.
+`
+	diags := ParseGCCDiagnostics([]byte(output))
+	require.Equal(t, []*Diagnostic{
+		{File: "/home/user/Arduino/Blink/config.h", Line: 3, Column: 9, Severity: "warning", Message: `"LED" redefined`},
+		{File: "/home/user/Arduino/Blink/Blink.ino", Line: 12, Column: 3, Severity: "error", Message: "'foo' was not declared in this scope"},
+		{File: "/home/user/Arduino/Blink/Blink.ino", Line: 12, Column: 3, Severity: "note", Message: "suggested alternative: 'for'"},
+		{File: `C:\Users\user\Arduino\Blink\other.cpp`, Line: 5, Severity: "error", Message: "expected ';' before '}' token"},
+		{File: "/home/user/Arduino/Blink/missing.cpp", Line: 1, Column: 10, Severity: "error", Message: "missing.h: No such file or directory"},
+	}, diags)
+}
+
+func TestPromoteWarnings(t *testing.T) {
+	output := `/home/user/Arduino/Blink/Blink.ino:5:1: warning: no return statement in function returning non-void [-Wreturn-type]
+/home/user/Arduino/libraries/Lib/Lib.cpp:10:5: warning: unused variable 'x' [-Wunused-variable]
+/home/user/Arduino/Blink/Blink.ino:8:3: warning: 'void old()' is deprecated [-Wdeprecated-declarations]
+/home/user/Arduino/Blink/Blink.ino:8:3: note: declared here
+`
+	patterns, err := CompileWarningPatterns([]string{`-Wreturn-type`, `is deprecated`})
+	require.NoError(t, err)
+
+	collector := &DiagnosticsCollector{FailOnWarning: patterns}
+	_, err = collector.Write([]byte(output))
+	require.NoError(t, err)
+	promoted := collector.PromotedWarnings()
+	require.Len(t, promoted, 2)
+	require.Equal(t, 5, promoted[0].Line)
+	require.Equal(t, "error", promoted[0].Severity)
+	require.Equal(t, 8, promoted[1].Line)
+
+	severities := []string{}
+	for _, diag := range collector.Diagnostics() {
+		severities = append(severities, diag.Severity)
+	}
+	require.Equal(t, []string{"error", "warning", "error", "note"}, severities)
+
+	_, err = CompileWarningPatterns([]string{`(`})
+	require.Error(t, err)
+}
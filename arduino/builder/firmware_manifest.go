@@ -0,0 +1,96 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+// FirmwareManifest describes the binaries to flash to install a firmware,
+// it's used to generate the manifests read by ESP Web Tools and by the OTA
+// update servers.
+type FirmwareManifest struct {
+	Name       string
+	Version    string
+	Fqbn       string
+	ChipFamily string
+	Parts      []*FirmwarePart
+}
+
+// FirmwarePart is a binary of the firmware and the offset where it's flashed
+type FirmwarePart struct {
+	Path   string `json:"path"`
+	Offset uint64 `json:"offset"`
+}
+
+// ChipFamily returns the chip family, in the form used by ESP Web Tools
+// (e.g. ESP32-S3), of the given microcontroller.
+func ChipFamily(mcu string) string {
+	family := strings.ToUpper(mcu)
+	if strings.HasPrefix(family, "ESP32") && len(family) > len("ESP32") && family[len("ESP32")] != '-' {
+		family = "ESP32-" + family[len("ESP32"):]
+	}
+	return family
+}
+
+// ESPWebToolsJSON returns the manifest in the format read by ESP Web Tools
+func (m *FirmwareManifest) ESPWebToolsJSON() ([]byte, error) {
+	type build struct {
+		ChipFamily string          `json:"chipFamily"`
+		Parts      []*FirmwarePart `json:"parts"`
+	}
+	manifest := struct {
+		Name    string   `json:"name"`
+		Version string   `json:"version"`
+		Builds  []*build `json:"builds"`
+	}{
+		Name:    m.Name,
+		Version: m.Version,
+		Builds:  []*build{{ChipFamily: m.ChipFamily, Parts: m.Parts}},
+	}
+	res, err := json.MarshalIndent(manifest, "", "  ")
+	return res, errors.WithStack(err)
+}
+
+// Render returns the manifest in the layout of the given text/template. The
+// fields of the FirmwareManifest are available to the template, along with
+// the hex function, that formats a number in hexadecimal, and the json
+// function, that encodes a value in JSON.
+func (m *FirmwareManifest) Render(layout string) ([]byte, error) {
+	tmpl, err := template.New("manifest").Funcs(template.FuncMap{
+		"hex": func(n uint64) string {
+			return fmt.Sprintf("0x%X", n)
+		},
+		"json": func(v interface{}) (string, error) {
+			res, err := json.Marshal(v)
+			return string(res), err
+		},
+	}).Parse(layout)
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest template: %s", err)
+	}
+	var res bytes.Buffer
+	if err := tmpl.Execute(&res, m); err != nil {
+		return nil, fmt.Errorf("rendering manifest template: %s", err)
+	}
+	return res.Bytes(), nil
+}
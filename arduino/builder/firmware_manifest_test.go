@@ -0,0 +1,68 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChipFamily(t *testing.T) {
+	require.Equal(t, "ESP32", ChipFamily("esp32"))
+	require.Equal(t, "ESP32-S3", ChipFamily("esp32s3"))
+	require.Equal(t, "ESP32-C3", ChipFamily("esp32c3"))
+	require.Equal(t, "ESP8266", ChipFamily("esp8266"))
+	require.Equal(t, "RP2040", ChipFamily("rp2040"))
+}
+
+func TestFirmwareManifest(t *testing.T) {
+	manifest := &FirmwareManifest{
+		Name:       "Blink",
+		Version:    "1.2.0",
+		Fqbn:       "esp32:esp32:esp32s3",
+		ChipFamily: "ESP32-S3",
+		Parts: []*FirmwarePart{
+			{Path: "Blink.ino.bootloader.bin", Offset: 0},
+			{Path: "Blink.ino.bin", Offset: 0x10000},
+		},
+	}
+
+	data, err := manifest.ESPWebToolsJSON()
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"name": "Blink",
+		"version": "1.2.0",
+		"builds": [{
+			"chipFamily": "ESP32-S3",
+			"parts": [
+				{"path": "Blink.ino.bootloader.bin", "offset": 0},
+				{"path": "Blink.ino.bin", "offset": 65536}
+			]
+		}]
+	}`, string(data))
+
+	data, err = manifest.Render(`{{.Name}} {{.Version}} {{.Fqbn}}
+{{range .Parts}}{{hex .Offset}} {{json .Path}}
+{{end}}`)
+	require.NoError(t, err)
+	require.Equal(t, "Blink 1.2.0 esp32:esp32:esp32s3\n0x0 \"Blink.ino.bootloader.bin\"\n0x10000 \"Blink.ino.bin\"\n", string(data))
+
+	_, err = manifest.Render(`{{.Name`)
+	require.Error(t, err)
+	_, err = manifest.Render(`{{.Missing}}`)
+	require.Error(t, err)
+}
@@ -0,0 +1,84 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"fmt"
+	"strings"
+
+	properties "github.com/arduino/go-properties-orderedmap"
+)
+
+// Build stages at which the user defined hooks are run
+const (
+	// HookPrePreprocess runs before the sketch is copied in the build path
+	// and preprocessed
+	HookPrePreprocess = "pre_preprocess"
+	// HookPreLink runs after the sketch, the libraries and the core have
+	// been compiled, before linking
+	HookPreLink = "pre_link"
+	// HookPostBuild runs at the end of a successful build
+	HookPostBuild = "post_build"
+)
+
+// HookStages lists the build stages at which the user defined hooks can run
+var HookStages = []string{HookPrePreprocess, HookPreLink, HookPostBuild}
+
+// MergeHooks returns the hooks of all the given sets, for each stage the
+// commands are run in the same order of the sets. An error is returned if
+// a set contains an unknown stage.
+func MergeHooks(sets ...map[string][]string) (map[string][]string, error) {
+	res := map[string][]string{}
+	for _, set := range sets {
+		for stage, commands := range set {
+			if !isHookStage(stage) {
+				return nil, fmt.Errorf("invalid build hook stage %s, allowed stages are: %s", stage, strings.Join(HookStages, ", "))
+			}
+			res[stage] = append(res[stage], commands...)
+		}
+	}
+	return res, nil
+}
+
+func isHookStage(stage string) bool {
+	for _, s := range HookStages {
+		if s == stage {
+			return true
+		}
+	}
+	return false
+}
+
+// HookEnvironment returns the build properties in the form of environment
+// variables: the name is the property key in upper case, with the characters
+// that are not letters or digits replaced by "_", and prefixed by ARDUINO_
+// (e.g. build.path becomes ARDUINO_BUILD_PATH).
+func HookEnvironment(buildProperties *properties.Map) []string {
+	env := []string{}
+	for _, key := range buildProperties.Keys() {
+		name := strings.Map(func(r rune) rune {
+			switch {
+			case r >= 'a' && r <= 'z':
+				return r - 'a' + 'A'
+			case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+				return r
+			}
+			return '_'
+		}, key)
+		env = append(env, "ARDUINO_"+name+"="+buildProperties.ExpandPropsInString(buildProperties.Get(key)))
+	}
+	return env
+}
@@ -0,0 +1,52 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"testing"
+
+	properties "github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeHooks(t *testing.T) {
+	hooks, err := MergeHooks(
+		map[string][]string{HookPostBuild: {"a"}},
+		map[string][]string{HookPostBuild: {"b"}, HookPrePreprocess: {"c"}},
+		nil,
+	)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, hooks[HookPostBuild])
+	require.Equal(t, []string{"c"}, hooks[HookPrePreprocess])
+	require.Empty(t, hooks[HookPreLink])
+
+	_, err = MergeHooks(map[string][]string{"post-upload": {"a"}})
+	require.Error(t, err)
+}
+
+func TestHookEnvironment(t *testing.T) {
+	props := properties.NewMap()
+	props.Set("build.path", "/tmp/build")
+	props.Set("build.project_name", "Blink.ino")
+	props.Set("build.elf", "{build.path}/{build.project_name}.elf")
+	props.Set("runtime.tools.avr-gcc.path", "/opt/avr-gcc")
+	require.Equal(t, []string{
+		"ARDUINO_BUILD_PATH=/tmp/build",
+		"ARDUINO_BUILD_PROJECT_NAME=Blink.ino",
+		"ARDUINO_BUILD_ELF=/tmp/build/Blink.ino.elf",
+		"ARDUINO_RUNTIME_TOOLS_AVR_GCC_PATH=/opt/avr-gcc",
+	}, HookEnvironment(props))
+}
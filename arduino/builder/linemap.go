@@ -0,0 +1,217 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/arduino/go-paths-helper"
+)
+
+var lineDirectiveRegexp = regexp.MustCompile(`^\s*#\s*line\s+(\d+)(?:\s+"((?:[^"\\]|\\.)*)")?`)
+
+// LineMap maps the lines of a preprocessed source file back to the original
+// sources, following the #line directives it contains.
+type LineMap struct {
+	entries []lineMapEntry
+}
+
+type lineMapEntry struct {
+	// line of the preprocessed file where the mapping starts
+	line int
+	// original file and line corresponding to line
+	file     string
+	origLine int
+}
+
+// ParseLineMap builds the LineMap of a preprocessed source file
+func ParseLineMap(source []byte) *LineMap {
+	res := &LineMap{}
+	file := ""
+	scanner := bufio.NewScanner(bytes.NewReader(source))
+	scanner.Buffer(nil, len(source)+1)
+	for line := 1; scanner.Scan(); line++ {
+		match := lineDirectiveRegexp.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+		origLine, _ := strconv.Atoi(match[1])
+		if match[2] != "" {
+			file = unquoteCppString(match[2])
+		}
+		// The directive applies to the line that follows it
+		res.entries = append(res.entries, lineMapEntry{line: line + 1, file: file, origLine: origLine})
+	}
+	return res
+}
+
+func unquoteCppString(str string) string {
+	str = strings.Replace(str, `\"`, `"`, -1)
+	str = strings.Replace(str, `\\`, `\`, -1)
+	return str
+}
+
+// Lookup returns the original file and line of the given line of the
+// preprocessed file. The lines that precede the first #line directive, added
+// by the preprocessor, are mapped to the beginning of the first original file.
+func (m *LineMap) Lookup(line int) (string, int, bool) {
+	if len(m.entries) == 0 {
+		return "", 0, false
+	}
+	i := sort.Search(len(m.entries), func(i int) bool { return m.entries[i].line > line }) - 1
+	if i < 0 {
+		return m.entries[0].file, m.entries[0].origLine, true
+	}
+	entry := m.entries[i]
+	if entry.file == "" {
+		return "", 0, false
+	}
+	return entry.file, entry.origLine + line - entry.line, true
+}
+
+// SourceMapper rewrites the locations pointing into the preprocessed sketch
+// sources so that they refer to the original files of the sketch.
+type SourceMapper struct {
+	dirs []string
+	lock sync.Mutex
+	maps map[string]*LineMap
+}
+
+// NewSourceMapper creates a SourceMapper for the preprocessed sources
+// contained in the given folders
+func NewSourceMapper(dirs ...*paths.Path) *SourceMapper {
+	res := &SourceMapper{maps: map[string]*LineMap{}}
+	for _, dir := range dirs {
+		if abs, err := dir.Abs(); err == nil {
+			dir = abs
+		}
+		res.dirs = append(res.dirs, dir.String())
+	}
+	return res
+}
+
+func (m *SourceMapper) isPreprocessed(file string) bool {
+	for _, dir := range m.dirs {
+		if strings.HasPrefix(file, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// Map returns the original location of the given line of file. The location
+// is returned unchanged if file is not a preprocessed source.
+func (m *SourceMapper) Map(file string, line int) (string, int) {
+	if !m.isPreprocessed(file) {
+		return file, line
+	}
+	m.lock.Lock()
+	lineMap, ok := m.maps[file]
+	if !ok {
+		if source, err := paths.New(file).ReadFile(); err == nil {
+			lineMap = ParseLineMap(source)
+		}
+		m.maps[file] = lineMap
+	}
+	m.lock.Unlock()
+	if lineMap == nil {
+		return file, line
+	}
+	if origFile, origLine, ok := lineMap.Lookup(line); ok {
+		return origFile, origLine
+	}
+	return file, line
+}
+
+// MapDiagnostic rewrites the location of the diagnostic
+func (m *SourceMapper) MapDiagnostic(diag *Diagnostic) {
+	diag.File, diag.Line = m.Map(diag.File, diag.Line)
+}
+
+// Writer returns a writer that rewrites the locations found in the output of
+// the compiler before writing it to w. The returned writer is line buffered,
+// Flush must be called to write the last incomplete line.
+func (m *SourceMapper) Writer(w io.Writer) *SourceMapperWriter {
+	quoted := []string{}
+	for _, dir := range m.dirs {
+		quoted = append(quoted, regexp.QuoteMeta(dir))
+	}
+	var location *regexp.Regexp
+	if len(quoted) > 0 {
+		location = regexp.MustCompile(`((?:` + strings.Join(quoted, "|") + `)[^:\n]*?):(\d+)`)
+	}
+	return &SourceMapperWriter{mapper: m, out: w, location: location}
+}
+
+// SourceMapperWriter is the writer returned by SourceMapper.Writer
+type SourceMapperWriter struct {
+	mapper   *SourceMapper
+	out      io.Writer
+	location *regexp.Regexp
+	lock     sync.Mutex
+	buffer   bytes.Buffer
+}
+
+// Write implements io.Writer
+func (w *SourceMapperWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.buffer.Write(p)
+	for {
+		idx := bytes.IndexByte(w.buffer.Bytes(), '\n')
+		if idx < 0 {
+			break
+		}
+		line := w.buffer.Next(idx + 1)
+		if _, err := w.out.Write(w.rewrite(line)); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush writes the buffered incomplete line, if any
+func (w *SourceMapperWriter) Flush() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.buffer.Len() == 0 {
+		return nil
+	}
+	_, err := w.out.Write(w.rewrite(w.buffer.Next(w.buffer.Len())))
+	return err
+}
+
+func (w *SourceMapperWriter) rewrite(line []byte) []byte {
+	if w.location == nil {
+		return line
+	}
+	return w.location.ReplaceAllFunc(line, func(loc []byte) []byte {
+		match := w.location.FindSubmatch(loc)
+		n, err := strconv.Atoi(string(match[2]))
+		if err != nil {
+			return loc
+		}
+		file, n := w.mapper.Map(string(match[1]), n)
+		return []byte(file + ":" + strconv.Itoa(n))
+	})
+}
@@ -0,0 +1,77 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLineMap(t *testing.T) {
+	source := `#include <Arduino.h>
+#line 1 "/sketch/Blink.ino"
+void setup() {
+}
+#line 1 "/sketch/Other.ino"
+int a;
+#line 10
+int b;
+`
+	lineMap := ParseLineMap([]byte(source))
+
+	check := func(line int, file string, origLine int) {
+		f, l, ok := lineMap.Lookup(line)
+		require.True(t, ok)
+		require.Equal(t, file, f)
+		require.Equal(t, origLine, l)
+	}
+	check(1, "/sketch/Blink.ino", 1)
+	check(3, "/sketch/Blink.ino", 1)
+	check(4, "/sketch/Blink.ino", 2)
+	check(6, "/sketch/Other.ino", 1)
+	check(8, "/sketch/Other.ino", 10)
+
+	_, _, ok := ParseLineMap([]byte("int a;\n")).Lookup(1)
+	require.False(t, ok)
+}
+
+func TestSourceMapper(t *testing.T) {
+	dir, err := paths.MkTempDir("", "source_mapper")
+	require.NoError(t, err)
+	defer dir.RemoveAll()
+
+	cpp := dir.Join("Blink.ino.cpp")
+	require.NoError(t, cpp.WriteFile([]byte("#include <Arduino.h>\n#line 1 \"/sketch/Blink.ino\"\nvoid setup() {\n  foo();\n}\n")))
+
+	mapper := NewSourceMapper(dir)
+	file, line := mapper.Map(cpp.String(), 4)
+	require.Equal(t, "/sketch/Blink.ino", file)
+	require.Equal(t, 2, line)
+
+	file, line = mapper.Map("/other/file.cpp", 4)
+	require.Equal(t, "/other/file.cpp", file)
+	require.Equal(t, 4, line)
+
+	out := &bytes.Buffer{}
+	w := mapper.Writer(out)
+	w.Write([]byte(cpp.String() + ":4:3: error: 'foo' was not declared in this scope\n" + cpp.String() + ":4:"))
+	w.Write([]byte("3: note: unfinished"))
+	require.NoError(t, w.Flush())
+	require.Equal(t, "/sketch/Blink.ino:2:3: error: 'foo' was not declared in this scope\n/sketch/Blink.ino:2:3: note: unfinished", out.String())
+}
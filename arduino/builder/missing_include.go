@@ -0,0 +1,39 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"github.com/arduino/go-paths-helper"
+)
+
+// MissingIncludeError is returned when an #include of a source file can not
+// be resolved to any of the installed libraries
+type MissingIncludeError struct {
+	// Include is the header that could not be found
+	Include string
+	// SourceFile is the file containing the #include
+	SourceFile *paths.Path
+	// Err is the error returned by the preprocessor
+	Err error
+}
+
+func (e *MissingIncludeError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *MissingIncludeError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,99 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	properties "github.com/arduino/go-properties-orderedmap"
+)
+
+// Optimization presets selectable when compiling
+const (
+	OptimizeSize  = "size"
+	OptimizeSpeed = "speed"
+	OptimizeDebug = "debug"
+)
+
+// optimizationPresetFlags are the flags used for each preset when the
+// platform doesn't define them in compiler.optimization_flags.<preset>
+var optimizationPresetFlags = map[string]string{
+	OptimizeSize:  "-Os",
+	OptimizeSpeed: "-O2",
+	OptimizeDebug: "-Og -g3",
+}
+
+// optimizationFlagsProperties are the properties holding compiler flags
+// where the optimization level is replaced if the platform doesn't use
+// compiler.optimization_flags
+var optimizationFlagsProperties = []string{
+	"compiler.c.flags",
+	"compiler.cpp.flags",
+	"compiler.S.flags",
+	"compiler.c.elf.flags",
+}
+
+var optimizationFlagRegexp = regexp.MustCompile(`^-O([0-3sgz]|fast)?$`)
+
+// ApplyOptimizationPreset sets the compiler flags of the given optimization
+// preset (size, speed or debug) in the build properties. If the platform
+// defines compiler.optimization_flags.<preset> that value is used,
+// otherwise the optimization level in compiler.optimization_flags or, if the
+// platform doesn't use it, in the compiler flags of core, libraries and
+// sketch is replaced by the flags of the preset.
+// It returns the optimization flags applied.
+func ApplyOptimizationPreset(buildProperties *properties.Map, preset string) (string, error) {
+	flags, ok := optimizationPresetFlags[preset]
+	if !ok {
+		return "", fmt.Errorf("invalid optimization preset %s, allowed presets are: %s, %s, %s", preset, OptimizeSize, OptimizeSpeed, OptimizeDebug)
+	}
+	if platformFlags, ok := buildProperties.GetOk("compiler.optimization_flags." + preset); ok {
+		flags = platformFlags
+	}
+	if buildProperties.ContainsKey("compiler.optimization_flags") {
+		buildProperties.Set("compiler.optimization_flags", flags)
+		return flags, nil
+	}
+	for _, key := range optimizationFlagsProperties {
+		value, ok := buildProperties.GetOk(key)
+		if !ok {
+			continue
+		}
+		buildProperties.Set(key, replaceOptimizationFlags(value, flags))
+	}
+	buildProperties.Set("compiler.optimization_flags", flags)
+	return flags, nil
+}
+
+// replaceOptimizationFlags replaces the first optimization level flag found
+// in the given flags with the new ones and removes the others
+func replaceOptimizationFlags(value, flags string) string {
+	res := []string{}
+	replaced := false
+	for _, field := range strings.Fields(value) {
+		if !optimizationFlagRegexp.MatchString(field) {
+			res = append(res, field)
+			continue
+		}
+		if !replaced {
+			res = append(res, flags)
+			replaced = true
+		}
+	}
+	return strings.Join(res, " ")
+}
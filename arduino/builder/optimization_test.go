@@ -0,0 +1,58 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"testing"
+
+	properties "github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyOptimizationPreset(t *testing.T) {
+	// Platform defining the flags of the preset
+	props := properties.NewMap()
+	props.Set("compiler.optimization_flags", "-Os")
+	props.Set("compiler.optimization_flags.debug", "-Og -g")
+	flags, err := ApplyOptimizationPreset(props, OptimizeDebug)
+	require.NoError(t, err)
+	require.Equal(t, "-Og -g", flags)
+	require.Equal(t, "-Og -g", props.Get("compiler.optimization_flags"))
+
+	// Platform using compiler.optimization_flags without presets
+	flags, err = ApplyOptimizationPreset(props, OptimizeSpeed)
+	require.NoError(t, err)
+	require.Equal(t, "-O2", flags)
+	require.Equal(t, "-O2", props.Get("compiler.optimization_flags"))
+
+	// Platform with the optimization level in the compiler flags
+	props = properties.NewMap()
+	props.Set("compiler.c.flags", "-c -g -Os -w -std=gnu11")
+	props.Set("compiler.cpp.flags", "-c -g -O3 -w -Os")
+	props.Set("compiler.c.elf.flags", "-w -Os -g -flto")
+	props.Set("compiler.S.flags", "-c -g -x assembler-with-cpp")
+	flags, err = ApplyOptimizationPreset(props, OptimizeDebug)
+	require.NoError(t, err)
+	require.Equal(t, "-Og -g3", flags)
+	require.Equal(t, "-c -g -Og -g3 -w -std=gnu11", props.Get("compiler.c.flags"))
+	require.Equal(t, "-c -g -Og -g3 -w", props.Get("compiler.cpp.flags"))
+	require.Equal(t, "-w -Og -g3 -g -flto", props.Get("compiler.c.elf.flags"))
+	require.Equal(t, "-c -g -x assembler-with-cpp", props.Get("compiler.S.flags"))
+	require.Equal(t, "-Og -g3", props.Get("compiler.optimization_flags"))
+
+	_, err = ApplyOptimizationPreset(props, "fast")
+	require.Error(t, err)
+}
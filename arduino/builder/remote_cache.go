@@ -0,0 +1,215 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/pkg/errors"
+)
+
+// Kinds of the entries stored in a RemoteCache
+const (
+	RemoteCacheCore     = "core"
+	RemoteCacheManifest = "manifest"
+)
+
+// remoteCacheObject is the kind of the content addressed entries
+const remoteCacheObject = "obj"
+
+// RemoteCache is a build cache shared over HTTP. Each entry is read with a
+// GET and stored with a PUT of <url>/<kind>/<key>, so any HTTP server
+// supporting uploads (e.g. nginx with WebDAV) can be used.
+// The compiled files are stored as objects, whose key is the sha256 of their
+// content and is verified when they are fetched. The other entries (the core
+// and manifest kinds) are keyed by the hash of the build inputs and only
+// point to the objects, so a tampered or corrupted entry can't make the
+// build use a compiled file different from the one it points to.
+// The remote cache is a best effort: if the server can't be reached it's not
+// used anymore for the rest of the build and the local build is used.
+type RemoteCache struct {
+	url      *url.URL
+	client   *http.Client
+	readOnly bool
+	roots    []remoteCacheRoot
+	failed   int32
+}
+
+type remoteCacheRoot struct {
+	placeholder string
+	dir         string
+}
+
+// NewRemoteCache returns the RemoteCache at the given URL, accessed with the
+// given http client. If readOnly is true the entries are only fetched and
+// never stored.
+func NewRemoteCache(remoteURL string, readOnly bool, client *http.Client) (*RemoteCache, error) {
+	u, err := url.Parse(remoteURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote cache URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid remote cache URL %s: only http and https are supported", remoteURL)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	return &RemoteCache{
+		url:      u,
+		client:   client,
+		readOnly: readOnly,
+	}, nil
+}
+
+// AddRoot registers a folder whose path changes between machines, e.g. the
+// data directory or the build path. The folder is replaced with the given
+// placeholder when computing the keys, so the same key is obtained on every
+// machine.
+func (c *RemoteCache) AddRoot(placeholder string, dir *paths.Path) {
+	if dir == nil {
+		return
+	}
+	c.roots = append(c.roots, remoteCacheRoot{placeholder: placeholder, dir: dir.Clean().String()})
+	// The longest paths are replaced first, they may be inside other roots
+	sort.SliceStable(c.roots, func(i, j int) bool { return len(c.roots[i].dir) > len(c.roots[j].dir) })
+}
+
+// Normalize replaces the paths of the roots in s with their placeholders
+func (c *RemoteCache) Normalize(s string) string {
+	for _, root := range c.roots {
+		s = strings.ReplaceAll(s, root.dir, root.placeholder)
+	}
+	return s
+}
+
+// Localize replaces the placeholders in s with the paths of the roots on
+// this machine, it's the inverse of Normalize
+func (c *RemoteCache) Localize(s string) string {
+	for _, root := range c.roots {
+		if strings.HasPrefix(s, root.placeholder) {
+			return root.dir + strings.TrimPrefix(s, root.placeholder)
+		}
+	}
+	return s
+}
+
+// Available returns false if the server couldn't be reached earlier in the
+// build
+func (c *RemoteCache) Available() bool {
+	return atomic.LoadInt32(&c.failed) == 0
+}
+
+func (c *RemoteCache) entryURL(kind, key string) string {
+	u := *c.url
+	u.Path += "/" + kind + "/" + key
+	return u.String()
+}
+
+// Get fetches the entry with the given key. It returns nil if the cache
+// doesn't contain the entry or if the server can't be reached.
+func (c *RemoteCache) Get(kind, key string) ([]byte, error) {
+	if !c.Available() {
+		return nil, nil
+	}
+	resp, err := c.client.Get(c.entryURL(kind, key))
+	if err != nil {
+		atomic.StoreInt32(&c.failed, 1)
+		return nil, fmt.Errorf("remote cache unavailable: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s %s from the remote cache: %s", kind, key, resp.Status)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return body, nil
+}
+
+// Put stores the entry with the given key. Nothing is done if the cache is
+// read-only.
+func (c *RemoteCache) Put(kind, key string, data []byte) error {
+	if c.readOnly || !c.Available() {
+		return nil
+	}
+	req, err := http.NewRequest(http.MethodPut, c.entryURL(kind, key), bytes.NewReader(data))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		atomic.StoreInt32(&c.failed, 1)
+		return fmt.Errorf("remote cache unavailable: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("storing %s %s in the remote cache: %s", kind, key, resp.Status)
+	}
+	return nil
+}
+
+// GetObject fetches the object with the given hash, as returned by
+// PutObject. It returns nil if the cache doesn't contain the object or if the
+// server can't be reached, and an error if the content of the object doesn't
+// match its hash.
+func (c *RemoteCache) GetObject(hash string) ([]byte, error) {
+	if !isRemoteCacheObjectHash(hash) {
+		return nil, fmt.Errorf("invalid remote cache object hash %q", hash)
+	}
+	data, err := c.Get(remoteCacheObject, hash)
+	if err != nil || data == nil {
+		return nil, err
+	}
+	if remoteCacheObjectHash(data) != hash {
+		return nil, fmt.Errorf("checksum mismatch in remote cache object %s", hash)
+	}
+	return data, nil
+}
+
+// PutObject stores the given data as an object and returns its hash, which
+// is returned even if the cache is read-only.
+func (c *RemoteCache) PutObject(data []byte) (string, error) {
+	hash := remoteCacheObjectHash(data)
+	return hash, c.Put(remoteCacheObject, hash, data)
+}
+
+func remoteCacheObjectHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// isRemoteCacheObjectHash returns true if hash is a hex encoded sha256, the
+// hashes read from the other entries are checked before being used in an URL
+func isRemoteCacheObjectHash(hash string) bool {
+	if len(hash) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(hash)
+	return err == nil
+}
@@ -0,0 +1,141 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+// newRemoteCacheServer returns an HTTP server storing the uploaded entries
+// in memory
+func newRemoteCacheServer() (*httptest.Server, map[string][]byte) {
+	var lock sync.Mutex
+	entries := map[string][]byte{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		switch r.Method {
+		case http.MethodGet:
+			data, ok := entries[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write(data)
+		case http.MethodPut:
+			data, _ := ioutil.ReadAll(r.Body)
+			entries[r.URL.Path] = data
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	return server, entries
+}
+
+func TestRemoteCache(t *testing.T) {
+	server, entries := newRemoteCacheServer()
+	defer server.Close()
+
+	_, err := NewRemoteCache("ftp://example.com", false, http.DefaultClient)
+	require.Error(t, err)
+
+	cache, err := NewRemoteCache(server.URL+"/cache/", false, http.DefaultClient)
+	require.NoError(t, err)
+	data, err := cache.Get(RemoteCacheCore, "abc")
+	require.NoError(t, err)
+	require.Nil(t, data)
+
+	require.NoError(t, cache.Put(RemoteCacheCore, "abc", []byte("core pointer")))
+	require.Contains(t, entries, "/cache/core/abc")
+	data, err = cache.Get(RemoteCacheCore, "abc")
+	require.NoError(t, err)
+	require.Equal(t, []byte("core pointer"), data)
+
+	// A read-only cache doesn't store the entries
+	readOnly, err := NewRemoteCache(server.URL+"/cache", true, http.DefaultClient)
+	require.NoError(t, err)
+	require.NoError(t, readOnly.Put(RemoteCacheManifest, "def", []byte("manifest")))
+	require.NotContains(t, entries, "/cache/manifest/def")
+
+	// If the server can't be reached the cache is not used anymore
+	server.Close()
+	_, err = cache.Get(RemoteCacheCore, "abc")
+	require.Error(t, err)
+	require.False(t, cache.Available())
+	data, err = cache.Get(RemoteCacheCore, "abc")
+	require.NoError(t, err)
+	require.Nil(t, data)
+}
+
+func TestRemoteCacheObjects(t *testing.T) {
+	server, entries := newRemoteCacheServer()
+	defer server.Close()
+
+	cache, err := NewRemoteCache(server.URL, false, http.DefaultClient)
+	require.NoError(t, err)
+
+	hash, err := cache.PutObject([]byte("core archive"))
+	require.NoError(t, err)
+	require.Equal(t, remoteCacheObjectHash([]byte("core archive")), hash)
+	require.Contains(t, entries, "/obj/"+hash)
+	data, err := cache.GetObject(hash)
+	require.NoError(t, err)
+	require.Equal(t, []byte("core archive"), data)
+
+	missing := remoteCacheObjectHash([]byte("missing"))
+	data, err = cache.GetObject(missing)
+	require.NoError(t, err)
+	require.Nil(t, data)
+
+	// An object whose content doesn't match its key is rejected
+	entries["/obj/"+hash] = []byte("tampered archive")
+	_, err = cache.GetObject(hash)
+	require.Error(t, err)
+
+	// Only the sha256 hashes are used as keys
+	_, err = cache.GetObject("../core/abc")
+	require.Error(t, err)
+	require.True(t, cache.Available())
+
+	// The hash is returned even if the object is not stored
+	readOnly, err := NewRemoteCache(server.URL, true, http.DefaultClient)
+	require.NoError(t, err)
+	hash, err = readOnly.PutObject([]byte("object"))
+	require.NoError(t, err)
+	require.Equal(t, remoteCacheObjectHash([]byte("object")), hash)
+	require.NotContains(t, entries, "/obj/"+hash)
+}
+
+func TestRemoteCacheNormalize(t *testing.T) {
+	cache, err := NewRemoteCache("http://localhost", false, http.DefaultClient)
+	require.NoError(t, err)
+	cache.AddRoot("{sketch}", paths.New("/home/user/Blink"))
+	cache.AddRoot("{build}", paths.New("/home/user/Blink/build"))
+	cache.AddRoot("{data}", paths.New("/home/user/.arduino15"))
+
+	normalized := cache.Normalize("/home/user/.arduino15/packages/gcc -c /home/user/Blink/build/sketch/Blink.ino.cpp -I/home/user/Blink/src")
+	require.Equal(t, "{data}/packages/gcc -c {build}/sketch/Blink.ino.cpp -I{sketch}/src", normalized)
+	require.Equal(t, "/home/user/Blink/build/sketch/Blink.ino.cpp", cache.Localize("{build}/sketch/Blink.ino.cpp"))
+	require.Equal(t, "/usr/include/stdio.h", cache.Localize("/usr/include/stdio.h"))
+}
@@ -0,0 +1,353 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+)
+
+// SBOM formats supported by compile --sbom
+const (
+	SBOMFormatSPDX      = "spdx"
+	SBOMFormatCycloneDX = "cyclonedx"
+)
+
+// Types of the components of a SBOM
+const (
+	SBOMComponentCore    = "core"
+	SBOMComponentLibrary = "library"
+	SBOMComponentTool    = "tool"
+)
+
+// SBOM is the software bill of materials of a build: the core, the libraries
+// linked in the firmware and the tools used to build it.
+type SBOM struct {
+	Name        string
+	Fqbn        string
+	Created     time.Time
+	ToolName    string
+	ToolVersion string
+	Components  []*SBOMComponent
+}
+
+// SBOMComponent is a component of the build listed in the SBOM
+type SBOMComponent struct {
+	Type     string
+	Name     string
+	Version  string
+	Supplier string
+	URL      string
+	License  string
+	Location string
+	// Checksum is in the format used by the package indexes, e.g. SHA-256:0123...
+	Checksum string
+}
+
+// SBOMFileName returns the name of the file where the SBOM in the given
+// format of the build with the given base name is saved.
+func SBOMFileName(baseName, format string) string {
+	if format == SBOMFormatCycloneDX {
+		return baseName + ".cdx.json"
+	}
+	return baseName + ".spdx.json"
+}
+
+// Render returns the SBOM in the given format
+func (s *SBOM) Render(format string) ([]byte, error) {
+	switch format {
+	case SBOMFormatSPDX:
+		return s.SPDXJSON()
+	case SBOMFormatCycloneDX:
+		return s.CycloneDXJSON()
+	default:
+		return nil, fmt.Errorf("invalid SBOM format: %s", format)
+	}
+}
+
+var spdxIDInvalidChars = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+
+// spdxLicenseID matches the licenses that are a single SPDX identifier,
+// the other licenses can't be reported as a license expression.
+var spdxLicenseID = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9.-]*\+?$`)
+
+// spdxID returns the SPDX identifier of the component
+func (c *SBOMComponent) spdxID() string {
+	return "SPDXRef-" + c.Type + "-" + strings.Trim(spdxIDInvalidChars.ReplaceAllString(c.Name, "-"), "-")
+}
+
+// bomRef returns the reference of the component in a CycloneDX SBOM
+func (c *SBOMComponent) bomRef() string {
+	return c.Type + ":" + c.Name + "@" + c.Version
+}
+
+// hash returns the algorithm, in the given spelling of SHA-256, SHA-1 and
+// MD5, and the value of the checksum of the component.
+func (c *SBOMComponent) hash(sha256, sha1, md5 string) (string, string, bool) {
+	split := strings.SplitN(c.Checksum, ":", 2)
+	if len(split) != 2 {
+		return "", "", false
+	}
+	switch strings.ToUpper(split[0]) {
+	case "SHA-256":
+		return sha256, split[1], true
+	case "SHA-1":
+		return sha1, split[1], true
+	case "MD5":
+		return md5, split[1], true
+	}
+	return "", "", false
+}
+
+// SPDXJSON returns the SBOM as a SPDX 2.3 JSON document
+func (s *SBOM) SPDXJSON() ([]byte, error) {
+	type checksum struct {
+		Algorithm string `json:"algorithm"`
+		Value     string `json:"checksumValue"`
+	}
+	type pkg struct {
+		SPDXID           string      `json:"SPDXID"`
+		Name             string      `json:"name"`
+		Version          string      `json:"versionInfo,omitempty"`
+		Supplier         string      `json:"supplier"`
+		DownloadLocation string      `json:"downloadLocation"`
+		FilesAnalyzed    bool        `json:"filesAnalyzed"`
+		Checksums        []*checksum `json:"checksums,omitempty"`
+		LicenseConcluded string      `json:"licenseConcluded"`
+		LicenseDeclared  string      `json:"licenseDeclared"`
+		CopyrightText    string      `json:"copyrightText"`
+		Purpose          string      `json:"primaryPackagePurpose"`
+		Comment          string      `json:"comment,omitempty"`
+	}
+	type relationship struct {
+		Element string `json:"spdxElementId"`
+		Type    string `json:"relationshipType"`
+		Related string `json:"relatedSpdxElement"`
+	}
+
+	namespace, err := uuid.NewV4()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	firmware := &pkg{
+		SPDXID:           "SPDXRef-firmware",
+		Name:             s.Name,
+		Supplier:         "NOASSERTION",
+		DownloadLocation: "NOASSERTION",
+		LicenseConcluded: "NOASSERTION",
+		LicenseDeclared:  "NOASSERTION",
+		CopyrightText:    "NOASSERTION",
+		Purpose:          "FIRMWARE",
+		Comment:          "Built for " + s.Fqbn,
+	}
+	packages := []*pkg{firmware}
+	relationships := []*relationship{{"SPDXRef-DOCUMENT", "DESCRIBES", firmware.SPDXID}}
+	for _, c := range s.Components {
+		p := &pkg{
+			SPDXID:           c.spdxID(),
+			Name:             c.Name,
+			Version:          c.Version,
+			Supplier:         "NOASSERTION",
+			DownloadLocation: "NOASSERTION",
+			LicenseConcluded: "NOASSERTION",
+			LicenseDeclared:  "NOASSERTION",
+			CopyrightText:    "NOASSERTION",
+			Purpose:          "LIBRARY",
+		}
+		if c.Supplier != "" {
+			p.Supplier = "Organization: " + c.Supplier
+		}
+		if c.URL != "" {
+			p.DownloadLocation = c.URL
+		}
+		if alg, value, ok := c.hash("SHA256", "SHA1", "MD5"); ok {
+			p.Checksums = []*checksum{{alg, value}}
+		}
+		if spdxLicenseID.MatchString(c.License) {
+			p.LicenseDeclared = c.License
+		}
+		if c.Location != "" {
+			p.Comment = "Installed in " + c.Location
+		}
+		packages = append(packages, p)
+
+		switch c.Type {
+		case SBOMComponentCore:
+			p.Purpose = "FRAMEWORK"
+			relationships = append(relationships, &relationship{firmware.SPDXID, "CONTAINS", p.SPDXID})
+		case SBOMComponentLibrary:
+			relationships = append(relationships, &relationship{firmware.SPDXID, "CONTAINS", p.SPDXID})
+		case SBOMComponentTool:
+			p.Purpose = "APPLICATION"
+			relationships = append(relationships, &relationship{p.SPDXID, "BUILD_TOOL_OF", firmware.SPDXID})
+		}
+	}
+
+	doc := struct {
+		SPDXVersion       string `json:"spdxVersion"`
+		DataLicense       string `json:"dataLicense"`
+		SPDXID            string `json:"SPDXID"`
+		Name              string `json:"name"`
+		DocumentNamespace string `json:"documentNamespace"`
+		CreationInfo      struct {
+			Created  string   `json:"created"`
+			Creators []string `json:"creators"`
+		} `json:"creationInfo"`
+		Packages      []*pkg          `json:"packages"`
+		Relationships []*relationship `json:"relationships"`
+	}{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              s.Name,
+		DocumentNamespace: "https://arduino.github.io/arduino-cli/spdx/" + s.Name + "-" + namespace.String(),
+		Packages:          packages,
+		Relationships:     relationships,
+	}
+	doc.CreationInfo.Created = s.Created.UTC().Format(time.RFC3339)
+	doc.CreationInfo.Creators = []string{"Tool: " + s.ToolName + "-" + s.ToolVersion}
+	res, err := json.MarshalIndent(doc, "", "  ")
+	return res, errors.WithStack(err)
+}
+
+// CycloneDXJSON returns the SBOM as a CycloneDX 1.4 JSON document. The tools
+// are listed as components excluded from the firmware.
+func (s *SBOM) CycloneDXJSON() ([]byte, error) {
+	type hash struct {
+		Algorithm string `json:"alg"`
+		Content   string `json:"content"`
+	}
+	type license struct {
+		ID   string `json:"id,omitempty"`
+		Name string `json:"name,omitempty"`
+	}
+	type licenseChoice struct {
+		License *license `json:"license"`
+	}
+	type reference struct {
+		Type string `json:"type"`
+		URL  string `json:"url"`
+	}
+	type property struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+	type supplier struct {
+		Name string `json:"name"`
+	}
+	type component struct {
+		Type       string           `json:"type"`
+		BomRef     string           `json:"bom-ref"`
+		Supplier   *supplier        `json:"supplier,omitempty"`
+		Name       string           `json:"name"`
+		Version    string           `json:"version,omitempty"`
+		Scope      string           `json:"scope,omitempty"`
+		Hashes     []*hash          `json:"hashes,omitempty"`
+		Licenses   []*licenseChoice `json:"licenses,omitempty"`
+		References []*reference     `json:"externalReferences,omitempty"`
+		Properties []*property      `json:"properties,omitempty"`
+	}
+	type tool struct {
+		Vendor  string `json:"vendor"`
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+	type dependency struct {
+		Ref       string   `json:"ref"`
+		DependsOn []string `json:"dependsOn"`
+	}
+
+	serial, err := uuid.NewV4()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	firmware := &component{
+		Type:       "firmware",
+		BomRef:     "firmware:" + s.Name,
+		Name:       s.Name,
+		Properties: []*property{{"arduino:fqbn", s.Fqbn}},
+	}
+	components := []*component{}
+	dependsOn := []string{}
+	for _, c := range s.Components {
+		comp := &component{
+			Type:    "library",
+			BomRef:  c.bomRef(),
+			Name:    c.Name,
+			Version: c.Version,
+		}
+		switch c.Type {
+		case SBOMComponentCore:
+			comp.Type = "framework"
+		case SBOMComponentTool:
+			comp.Type = "application"
+			comp.Scope = "excluded"
+		}
+		if c.Supplier != "" {
+			comp.Supplier = &supplier{c.Supplier}
+		}
+		if alg, value, ok := c.hash("SHA-256", "SHA-1", "MD5"); ok {
+			comp.Hashes = []*hash{{alg, value}}
+		}
+		if spdxLicenseID.MatchString(c.License) {
+			comp.Licenses = []*licenseChoice{{&license{ID: c.License}}}
+		} else if c.License != "" {
+			comp.Licenses = []*licenseChoice{{&license{Name: c.License}}}
+		}
+		if c.URL != "" {
+			comp.References = []*reference{{"distribution", c.URL}}
+		}
+		if c.Location != "" {
+			comp.Properties = []*property{{"arduino:location", c.Location}}
+		}
+		components = append(components, comp)
+		if c.Type != SBOMComponentTool {
+			dependsOn = append(dependsOn, comp.BomRef)
+		}
+	}
+
+	doc := struct {
+		BomFormat    string `json:"bomFormat"`
+		SpecVersion  string `json:"specVersion"`
+		SerialNumber string `json:"serialNumber"`
+		Version      int    `json:"version"`
+		Metadata     struct {
+			Timestamp string     `json:"timestamp"`
+			Tools     []*tool    `json:"tools"`
+			Component *component `json:"component"`
+		} `json:"metadata"`
+		Components   []*component  `json:"components"`
+		Dependencies []*dependency `json:"dependencies"`
+	}{
+		BomFormat:    "CycloneDX",
+		SpecVersion:  "1.4",
+		SerialNumber: "urn:uuid:" + serial.String(),
+		Version:      1,
+		Components:   components,
+		Dependencies: []*dependency{{firmware.BomRef, dependsOn}},
+	}
+	doc.Metadata.Timestamp = s.Created.UTC().Format(time.RFC3339)
+	doc.Metadata.Component = firmware
+	doc.Metadata.Tools = []*tool{{"Arduino", s.ToolName, s.ToolVersion}}
+	res, err := json.MarshalIndent(doc, "", "  ")
+	return res, errors.WithStack(err)
+}
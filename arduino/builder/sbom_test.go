@@ -0,0 +1,143 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testSBOM() *SBOM {
+	return &SBOM{
+		Name:        "Blink",
+		Fqbn:        "arduino:avr:uno",
+		Created:     time.Date(2021, 3, 4, 10, 0, 0, 0, time.UTC),
+		ToolName:    "arduino-cli",
+		ToolVersion: "0.18.0",
+		Components: []*SBOMComponent{
+			{
+				Type:     SBOMComponentCore,
+				Name:     "arduino:avr",
+				Version:  "1.8.3",
+				Supplier: "Arduino",
+				URL:      "http://downloads.arduino.cc/cores/avr-1.8.3.tar.bz2",
+				Checksum: "SHA-256:de8a9b982477762d3d138e78a1ff3c5bb2f2fa50e5a7f1c4e6f0f1e1e2ec6f14",
+			},
+			{
+				Type:     SBOMComponentLibrary,
+				Name:     "Servo",
+				Version:  "1.1.7",
+				Supplier: "Arduino",
+				License:  "LGPL-2.1",
+				Location: "user",
+				Checksum: "SHA-256:77a8b4fd8f4a4da5a9f3e6b0ef1f0b82d1dbd6d1b5e71a1e8e2f8c4f1f4b1a9c",
+			},
+			{
+				Type:    SBOMComponentTool,
+				Name:    "avr-gcc",
+				Version: "7.3.0-atmel3.6.1-arduino7",
+				License: "GNU General Public License",
+			},
+		},
+	}
+}
+
+func TestSBOMSPDX(t *testing.T) {
+	data, err := testSBOM().Render(SBOMFormatSPDX)
+	require.NoError(t, err)
+	var doc struct {
+		SPDXVersion string
+		Packages    []struct {
+			SPDXID           string
+			Name             string
+			Supplier         string
+			DownloadLocation string
+			LicenseDeclared  string
+			Checksums        []struct {
+				Algorithm     string
+				ChecksumValue string
+			}
+		}
+		Relationships []struct {
+			SPDXElementID      string
+			RelationshipType   string
+			RelatedSPDXElement string
+		}
+	}
+	require.NoError(t, json.Unmarshal(data, &doc))
+	require.Equal(t, "SPDX-2.3", doc.SPDXVersion)
+	require.Len(t, doc.Packages, 4)
+	require.Equal(t, "SPDXRef-firmware", doc.Packages[0].SPDXID)
+
+	core := doc.Packages[1]
+	require.Equal(t, "SPDXRef-core-arduino-avr", core.SPDXID)
+	require.Equal(t, "Organization: Arduino", core.Supplier)
+	require.Equal(t, "http://downloads.arduino.cc/cores/avr-1.8.3.tar.bz2", core.DownloadLocation)
+	require.Equal(t, "SHA256", core.Checksums[0].Algorithm)
+
+	lib := doc.Packages[2]
+	require.Equal(t, "NOASSERTION", lib.DownloadLocation)
+	require.Equal(t, "LGPL-2.1", lib.LicenseDeclared)
+
+	tool := doc.Packages[3]
+	require.Equal(t, "NOASSERTION", tool.LicenseDeclared)
+	require.Empty(t, tool.Checksums)
+
+	require.Len(t, doc.Relationships, 4)
+	require.Equal(t, "DESCRIBES", doc.Relationships[0].RelationshipType)
+	require.Equal(t, "CONTAINS", doc.Relationships[2].RelationshipType)
+	require.Equal(t, "SPDXRef-library-Servo", doc.Relationships[2].RelatedSPDXElement)
+	require.Equal(t, "BUILD_TOOL_OF", doc.Relationships[3].RelationshipType)
+	require.Equal(t, "SPDXRef-tool-avr-gcc", doc.Relationships[3].SPDXElementID)
+}
+
+func TestSBOMCycloneDX(t *testing.T) {
+	data, err := testSBOM().Render(SBOMFormatCycloneDX)
+	require.NoError(t, err)
+	var doc struct {
+		BomFormat string
+		Metadata  struct {
+			Tools []struct{ Name, Version string }
+		}
+		Components []struct {
+			Type     string
+			BomRef   string `json:"bom-ref"`
+			Scope    string
+			Hashes   []struct{ Alg, Content string }
+			Licenses []struct{ License struct{ ID, Name string } }
+		}
+		Dependencies []struct {
+			Ref       string
+			DependsOn []string
+		}
+	}
+	require.NoError(t, json.Unmarshal(data, &doc))
+	require.Equal(t, "CycloneDX", doc.BomFormat)
+	require.Equal(t, "0.18.0", doc.Metadata.Tools[0].Version)
+	require.Len(t, doc.Components, 3)
+	require.Equal(t, "framework", doc.Components[0].Type)
+	require.Equal(t, "SHA-256", doc.Components[0].Hashes[0].Alg)
+	require.Equal(t, "LGPL-2.1", doc.Components[1].Licenses[0].License.ID)
+	require.Equal(t, "excluded", doc.Components[2].Scope)
+	require.Equal(t, "GNU General Public License", doc.Components[2].Licenses[0].License.Name)
+	require.Equal(t, []string{"core:arduino:avr@1.8.3", "library:Servo@1.1.7"}, doc.Dependencies[0].DependsOn)
+
+	_, err = testSBOM().Render("swid")
+	require.Error(t, err)
+}
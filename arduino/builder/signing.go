@@ -0,0 +1,158 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/pkg/errors"
+)
+
+const (
+	// SignEd25519 is the Ed25519 signature of the whole image
+	SignEd25519 = "ed25519"
+	// SignECDSAP256 is the ECDSA P-256 signature of the SHA-256 of the image,
+	// in ASN.1 DER format
+	SignECDSAP256 = "ecdsa-p256"
+)
+
+// LoadSigningKey reads a private key in PEM format (PKCS #8, or SEC 1 for
+// EC keys) and returns it along with the name of the signature algorithm.
+// Ed25519 and ECDSA P-256 keys are supported.
+func LoadSigningKey(file *paths.Path) (crypto.Signer, string, error) {
+	data, err := file.ReadFile()
+	if err != nil {
+		return nil, "", errors.WithStack(err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, "", fmt.Errorf("%s is not a PEM file", file)
+	}
+
+	var key interface{}
+	if block.Type == "EC PRIVATE KEY" {
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	} else {
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("reading private key %s: %s", file, err)
+	}
+
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		return k, SignEd25519, nil
+	case *ecdsa.PrivateKey:
+		if k.Curve != elliptic.P256() {
+			return nil, "", fmt.Errorf("unsupported ECDSA curve %s, only P-256 keys are supported", k.Curve.Params().Name)
+		}
+		return k, SignECDSAP256, nil
+	default:
+		return nil, "", fmt.Errorf("unsupported private key type %T, only Ed25519 and ECDSA P-256 keys are supported", key)
+	}
+}
+
+// SignImage returns the signature of the image made with the given key
+func SignImage(key crypto.Signer, image []byte) ([]byte, error) {
+	if k, ok := key.(ed25519.PrivateKey); ok {
+		return ed25519.Sign(k, image), nil
+	}
+	digest := sha256.Sum256(image)
+	return key.Sign(rand.Reader, digest[:], crypto.SHA256)
+}
+
+// VerifyImage checks the signature of the image with the given public key
+func VerifyImage(key crypto.PublicKey, image, signature []byte) error {
+	switch k := key.(type) {
+	case ed25519.PublicKey:
+		if !ed25519.Verify(k, image, signature) {
+			return errors.New("invalid signature")
+		}
+	case *ecdsa.PublicKey:
+		var sig struct{ R, S *big.Int }
+		if _, err := asn1.Unmarshal(signature, &sig); err != nil {
+			return fmt.Errorf("invalid signature: %s", err)
+		}
+		digest := sha256.Sum256(image)
+		if !ecdsa.Verify(k, digest[:], sig.R, sig.S) {
+			return errors.New("invalid signature")
+		}
+	default:
+		return fmt.Errorf("unsupported public key type %T", key)
+	}
+	return nil
+}
+
+// SignedImage returns the image followed by the signature and by the length
+// of the signature as a 32 bit little endian integer, the same layout used
+// by the signed OTA updates of the ESP8266.
+func SignedImage(image, signature []byte) []byte {
+	res := make([]byte, 0, len(image)+len(signature)+4)
+	res = append(res, image...)
+	res = append(res, signature...)
+	length := make([]byte, 4)
+	binary.LittleEndian.PutUint32(length, uint32(len(signature)))
+	return append(res, length...)
+}
+
+// OTAManifest describes a firmware image for the OTA update tools
+type OTAManifest struct {
+	Fqbn      string `json:"fqbn"`
+	Image     string `json:"image"`
+	Size      int    `json:"size"`
+	SHA256    string `json:"sha256"`
+	Algorithm string `json:"algorithm,omitempty"`
+	// Signature and PublicKey (PKIX, ASN.1 DER) are base64 encoded
+	Signature string `json:"signature,omitempty"`
+	PublicKey string `json:"public_key,omitempty"`
+}
+
+// NewOTAManifest returns the manifest of the image, the signature is added
+// only if key is not nil.
+func NewOTAManifest(fqbn, imageName string, image []byte, key crypto.Signer, algorithm string, signature []byte) (*OTAManifest, error) {
+	digest := sha256.Sum256(image)
+	res := &OTAManifest{
+		Fqbn:   fqbn,
+		Image:  imageName,
+		Size:   len(image),
+		SHA256: hex.EncodeToString(digest[:]),
+	}
+	if key == nil {
+		return res, nil
+	}
+	publicKey, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	res.Algorithm = algorithm
+	res.Signature = base64.StdEncoding.EncodeToString(signature)
+	res.PublicKey = base64.StdEncoding.EncodeToString(publicKey)
+	return res, nil
+}
@@ -0,0 +1,107 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"testing"
+
+	paths "github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func writeKey(t *testing.T, dir *paths.Path, name string, key interface{}) *paths.Path {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	file := dir.Join(name)
+	require.NoError(t, file.WriteFile(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})))
+	return file
+}
+
+func TestSignImage(t *testing.T) {
+	dir, err := paths.MkTempDir("", "test_signing")
+	require.NoError(t, err)
+	defer dir.RemoveAll()
+
+	image := []byte("firmware image")
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	for _, test := range []struct {
+		file      *paths.Path
+		algorithm string
+	}{
+		{writeKey(t, dir, "ed25519.pem", edKey), SignEd25519},
+		{writeKey(t, dir, "ecdsa.pem", ecKey), SignECDSAP256},
+	} {
+		key, algorithm, err := LoadSigningKey(test.file)
+		require.NoError(t, err)
+		require.Equal(t, test.algorithm, algorithm)
+
+		signature, err := SignImage(key, image)
+		require.NoError(t, err)
+		require.NoError(t, VerifyImage(key.Public(), image, signature))
+		require.Error(t, VerifyImage(key.Public(), []byte("another image"), signature))
+
+		manifest, err := NewOTAManifest("esp32:esp32:esp32", "sketch.ino.signed.bin", image, key, algorithm, signature)
+		require.NoError(t, err)
+		require.Equal(t, len(image), manifest.Size)
+		require.Equal(t, algorithm, manifest.Algorithm)
+		decoded, err := base64.StdEncoding.DecodeString(manifest.Signature)
+		require.NoError(t, err)
+		require.Equal(t, signature, decoded)
+	}
+
+	// EC keys in SEC 1 format
+	der, err := x509.MarshalECPrivateKey(ecKey)
+	require.NoError(t, err)
+	sec1 := dir.Join("sec1.pem")
+	require.NoError(t, sec1.WriteFile(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})))
+	_, algorithm, err := LoadSigningKey(sec1)
+	require.NoError(t, err)
+	require.Equal(t, SignECDSAP256, algorithm)
+
+	_, _, err = LoadSigningKey(writeKey(t, dir, "p384.pem", p384Key))
+	require.Error(t, err)
+	_, _, err = LoadSigningKey(dir.Join("missing.pem"))
+	require.Error(t, err)
+	require.NoError(t, dir.Join("invalid.pem").WriteFile([]byte("not a key")))
+	_, _, err = LoadSigningKey(dir.Join("invalid.pem"))
+	require.Error(t, err)
+}
+
+func TestSignedImage(t *testing.T) {
+	signed := SignedImage([]byte{1, 2, 3}, []byte{4, 5})
+	require.Equal(t, []byte{1, 2, 3, 4, 5}, signed[:5])
+	require.Equal(t, uint32(2), binary.LittleEndian.Uint32(signed[5:]))
+
+	manifest, err := NewOTAManifest("arduino:avr:uno", "sketch.ino.bin", []byte{}, nil, "", nil)
+	require.NoError(t, err)
+	require.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", manifest.SHA256)
+	require.Empty(t, manifest.Signature)
+}
@@ -0,0 +1,76 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SizeLimit is a limit on the memory used by a sketch, either in bytes or as
+// a percentage of the memory available on the board
+type SizeLimit struct {
+	value   float64
+	percent bool
+}
+
+// ParseSizeLimit parses a limit expressed in bytes (e.g. "30000") or as a
+// percentage of the memory available on the board (e.g. "80%")
+func ParseSizeLimit(limit string) (*SizeLimit, error) {
+	number := strings.TrimSpace(limit)
+	res := &SizeLimit{}
+	if strings.HasSuffix(number, "%") {
+		res.percent = true
+		number = strings.TrimSpace(strings.TrimSuffix(number, "%"))
+	}
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil || value < 0 || (!res.percent && value != float64(int64(value))) {
+		return nil, fmt.Errorf("invalid size limit: %s", limit)
+	}
+	res.value = value
+	return res, nil
+}
+
+// MaxSize returns the max number of bytes allowed by the limit given the
+// memory available on the board. An error is returned for a percentage if
+// the available memory is unknown (0 or less).
+func (l *SizeLimit) MaxSize(available int) (int, error) {
+	if !l.percent {
+		return int(l.value), nil
+	}
+	if available <= 0 {
+		return 0, fmt.Errorf("the memory available on the board is unknown, %s can't be applied", l)
+	}
+	return int(float64(available) * l.value / 100), nil
+}
+
+func (l *SizeLimit) String() string {
+	if l.percent {
+		return strconv.FormatFloat(l.value, 'f', -1, 64) + "%"
+	}
+	return strconv.FormatFloat(l.value, 'f', -1, 64) + " bytes"
+}
+
+// SizeLimitError is returned when the sketch uses more memory than available
+// on the board or allowed by a SizeLimit
+type SizeLimitError struct {
+	Message string
+}
+
+func (e *SizeLimitError) Error() string {
+	return e.Message
+}
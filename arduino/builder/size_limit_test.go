@@ -0,0 +1,51 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSizeLimit(t *testing.T) {
+	limit, err := ParseSizeLimit("30000")
+	require.NoError(t, err)
+	require.Equal(t, "30000 bytes", limit.String())
+	max, err := limit.MaxSize(0)
+	require.NoError(t, err)
+	require.Equal(t, 30000, max)
+
+	limit, err = ParseSizeLimit(" 80% ")
+	require.NoError(t, err)
+	require.Equal(t, "80%", limit.String())
+	max, err = limit.MaxSize(32256)
+	require.NoError(t, err)
+	require.Equal(t, 25804, max)
+	_, err = limit.MaxSize(-1)
+	require.Error(t, err)
+
+	limit, err = ParseSizeLimit("12.5%")
+	require.NoError(t, err)
+	max, err = limit.MaxSize(2048)
+	require.NoError(t, err)
+	require.Equal(t, 256, max)
+
+	for _, invalid := range []string{"", "%", "abc", "-10", "10.5", "10 KB"} {
+		_, err := ParseSizeLimit(invalid)
+		require.Error(t, err, invalid)
+	}
+}
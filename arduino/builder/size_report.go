@@ -0,0 +1,388 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"bytes"
+	"debug/elf"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
+	"github.com/pkg/errors"
+)
+
+// OtherOrigin is the origin assigned to the symbols that are not defined in
+// any of the object files given to NewSizeReport, usually coming from the
+// toolchain libraries (libc, libgcc, etc.)
+const OtherOrigin = "other"
+
+// SizeReportTopSymbols is the number of symbols listed in the size report of
+// a build
+const SizeReportTopSymbols = 50
+
+// SectionUsage is the size of a section of the linked executable
+type SectionUsage struct {
+	Name  string
+	Size  uint64
+	Flash bool
+	RAM   bool
+}
+
+// OriginUsage is the flash and RAM used by the symbols defined by an origin
+// (the sketch, the core or a library)
+type OriginUsage struct {
+	Name  string
+	Flash uint64
+	RAM   uint64
+}
+
+// SymbolUsage is the size of a symbol of the linked executable
+type SymbolUsage struct {
+	Name    string
+	Section string
+	Origin  string
+	Size    uint64
+	Flash   bool
+	RAM     bool
+}
+
+// SizeReport is the memory usage of a linked executable broken down by
+// section, origin and symbol
+type SizeReport struct {
+	Sections []*SectionUsage
+	Origins  []*OriginUsage
+	Symbols  []*SymbolUsage
+}
+
+// NewSizeReport analyzes the ELF executable and returns its memory usage.
+// The objects map assigns an origin name to a list of object files or
+// archives: each symbol of the executable is attributed to the origin that
+// defines it. The global symbols are matched by name, the local (static) ones,
+// that many objects may define with the same name, also by the source file
+// and the size given by the symbol tables of the objects. Only the topSymbols
+// biggest symbols are reported, all of them if topSymbols is 0.
+func NewSizeReport(executable *paths.Path, objects map[string]paths.PathList, topSymbols int) (*SizeReport, error) {
+	f, err := elf.Open(executable.String())
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", executable, err)
+	}
+	defer f.Close()
+
+	symbols, err := f.Symbols()
+	if err != nil {
+		return nil, fmt.Errorf("reading symbols of %s: %w", executable, err)
+	}
+
+	origins := newSymbolOrigins()
+	// Sort the origins to get a reproducible attribution of the symbols
+	// defined more than once
+	names := []string{}
+	for name := range objects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, object := range objects[name] {
+			if err := origins.addFile(name, object); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return newSizeReport(f.Sections, symbols, origins, topSymbols), nil
+}
+
+func newSizeReport(sections []*elf.Section, symbols []elf.Symbol, origins *symbolOrigins, topSymbols int) *SizeReport {
+	report := &SizeReport{
+		Sections: []*SectionUsage{},
+		Origins:  []*OriginUsage{},
+		Symbols:  []*SymbolUsage{},
+	}
+	for _, section := range sections {
+		flash, ram := sectionMemory(section)
+		if !flash && !ram {
+			continue
+		}
+		report.Sections = append(report.Sections, &SectionUsage{
+			Name:  section.Name,
+			Size:  section.Size,
+			Flash: flash,
+			RAM:   ram,
+		})
+	}
+
+	byOrigin := map[string]*OriginUsage{}
+	file := ""
+	for _, symbol := range symbols {
+		symType := elf.ST_TYPE(symbol.Info)
+		if symType == elf.STT_FILE {
+			// the local symbols of an object follow the name of its source
+			file = symbol.Name
+			continue
+		}
+		if symbol.Size == 0 || (symType != elf.STT_FUNC && symType != elf.STT_OBJECT) {
+			continue
+		}
+		if int(symbol.Section) >= len(sections) {
+			continue
+		}
+		section := sections[symbol.Section]
+		flash, ram := sectionMemory(section)
+		if !flash && !ram {
+			continue
+		}
+		origin := origins.find(file, symbol)
+		usage := &SymbolUsage{
+			Name:    symbol.Name,
+			Section: section.Name,
+			Origin:  origin,
+			Size:    symbol.Size,
+			Flash:   flash,
+			RAM:     ram,
+		}
+		report.Symbols = append(report.Symbols, usage)
+
+		total, ok := byOrigin[usage.Origin]
+		if !ok {
+			total = &OriginUsage{Name: usage.Origin}
+			byOrigin[usage.Origin] = total
+			report.Origins = append(report.Origins, total)
+		}
+		if flash {
+			total.Flash += symbol.Size
+		}
+		if ram {
+			total.RAM += symbol.Size
+		}
+	}
+
+	sort.SliceStable(report.Origins, func(i, j int) bool {
+		a, b := report.Origins[i], report.Origins[j]
+		if a.Flash+a.RAM != b.Flash+b.RAM {
+			return a.Flash+a.RAM > b.Flash+b.RAM
+		}
+		return a.Name < b.Name
+	})
+	sort.SliceStable(report.Symbols, func(i, j int) bool {
+		a, b := report.Symbols[i], report.Symbols[j]
+		if a.Size != b.Size {
+			return a.Size > b.Size
+		}
+		return a.Name < b.Name
+	})
+	if topSymbols > 0 && len(report.Symbols) > topSymbols {
+		report.Symbols = report.Symbols[:topSymbols]
+	}
+	return report
+}
+
+// sectionMemory returns where the content of the section is stored: the
+// allocated sections with content take space in flash, the writable ones
+// are also copied in RAM at startup, and the ones without content (.bss)
+// take space only in RAM.
+func sectionMemory(section *elf.Section) (flash bool, ram bool) {
+	if section.Flags&elf.SHF_ALLOC == 0 || section.Size == 0 {
+		return false, false
+	}
+	if section.Type == elf.SHT_NOBITS {
+		return false, true
+	}
+	return true, section.Flags&elf.SHF_WRITE != 0
+}
+
+// symbolOrigins maps the symbols defined by the objects to their origin
+type symbolOrigins struct {
+	global map[string]symbolOrigin
+	// the name of the origin is empty if the local symbol is defined by
+	// many origins
+	local map[localSymbol]string
+	// localByName is used for the local symbols not found by source file
+	localByName map[string]string
+}
+
+type symbolOrigin struct {
+	name string
+	weak bool
+}
+
+// localSymbol identifies a local symbol among the ones with the same name
+type localSymbol struct {
+	file string
+	name string
+	size uint64
+}
+
+func newSymbolOrigins() *symbolOrigins {
+	return &symbolOrigins{
+		global:      map[string]symbolOrigin{},
+		local:       map[localSymbol]string{},
+		localByName: map[string]string{},
+	}
+}
+
+// find returns the origin of a symbol of the executable, file is the source
+// file of the local symbols
+func (o *symbolOrigins) find(file string, symbol elf.Symbol) string {
+	if elf.ST_BIND(symbol.Info) != elf.STB_LOCAL {
+		if origin, ok := o.global[symbol.Name]; ok {
+			return origin.name
+		}
+		return OtherOrigin
+	}
+	if origin := o.local[localSymbol{file: file, name: symbol.Name, size: symbol.Size}]; origin != "" {
+		return origin
+	}
+	if origin := o.localByName[symbol.Name]; origin != "" {
+		return origin
+	}
+	return OtherOrigin
+}
+
+func (o *symbolOrigins) addFile(origin string, file *paths.Path) error {
+	if !file.Exist() {
+		return nil
+	}
+	data, err := file.ReadFile()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if !bytes.HasPrefix(data, []byte(arMagic)) {
+		return o.addObject(origin, data)
+	}
+	members, err := readArchive(data)
+	if err != nil {
+		return fmt.Errorf("reading archive %s: %w", file, err)
+	}
+	for _, member := range members {
+		if err := o.addObject(origin, member); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *symbolOrigins) addObject(origin string, data []byte) error {
+	f, err := elf.NewFile(bytes.NewReader(data))
+	if err != nil {
+		// Not an ELF object, nothing to attribute
+		return nil
+	}
+	defer f.Close()
+	symbols, err := f.Symbols()
+	if err != nil {
+		return nil
+	}
+	file := ""
+	for _, symbol := range symbols {
+		if elf.ST_TYPE(symbol.Info) == elf.STT_FILE {
+			file = symbol.Name
+			continue
+		}
+		if symbol.Section == elf.SHN_UNDEF || symbol.Name == "" {
+			continue
+		}
+		if elf.ST_BIND(symbol.Info) == elf.STB_LOCAL {
+			key := localSymbol{file: file, name: symbol.Name, size: symbol.Size}
+			if prev, ok := o.local[key]; ok && prev != origin {
+				o.local[key] = ""
+			} else {
+				o.local[key] = origin
+			}
+			if prev, ok := o.localByName[symbol.Name]; ok && prev != origin {
+				o.localByName[symbol.Name] = ""
+			} else {
+				o.localByName[symbol.Name] = origin
+			}
+			continue
+		}
+		weak := elf.ST_BIND(symbol.Info) == elf.STB_WEAK
+		if prev, ok := o.global[symbol.Name]; ok && (!prev.weak || weak) {
+			// Keep the first strong definition
+			continue
+		}
+		o.global[symbol.Name] = symbolOrigin{name: origin, weak: weak}
+	}
+	return nil
+}
+
+const arMagic = "!<arch>\n"
+
+// readArchive returns the content of the members of an ar archive, skipping
+// the symbol table and the long file names table.
+func readArchive(data []byte) ([][]byte, error) {
+	if !bytes.HasPrefix(data, []byte(arMagic)) {
+		return nil, errors.New("invalid archive header")
+	}
+	members := [][]byte{}
+	data = data[len(arMagic):]
+	for len(data) > 0 {
+		if len(data) < 60 {
+			return nil, errors.New("truncated archive member header")
+		}
+		header := data[:60]
+		name := strings.TrimSpace(string(header[0:16]))
+		size, err := strconv.ParseInt(strings.TrimSpace(string(header[48:58])), 10, 64)
+		if err != nil || size < 0 || int64(len(data)-60) < size {
+			return nil, errors.New("invalid archive member size")
+		}
+		content := data[60 : 60+size]
+		if name != "/" && name != "//" && name != "/SYM64/" && name != "__.SYMDEF" {
+			members = append(members, content)
+		}
+		// Members are aligned to even offsets
+		next := 60 + size + size%2
+		if next > int64(len(data)) {
+			next = int64(len(data))
+		}
+		data = data[next:]
+	}
+	return members, nil
+}
+
+// ToRPCSizeReport converts the report into a rpc.SizeReport
+func (r *SizeReport) ToRPCSizeReport() *rpc.SizeReport {
+	res := &rpc.SizeReport{}
+	for _, s := range r.Sections {
+		res.Sections = append(res.Sections, &rpc.SectionUsage{
+			Name:  s.Name,
+			Size:  int64(s.Size),
+			Flash: s.Flash,
+			Ram:   s.RAM,
+		})
+	}
+	for _, o := range r.Origins {
+		res.Origins = append(res.Origins, &rpc.OriginUsage{
+			Name:  o.Name,
+			Flash: int64(o.Flash),
+			Ram:   int64(o.RAM),
+		})
+	}
+	for _, s := range r.Symbols {
+		res.Symbols = append(res.Symbols, &rpc.SymbolUsage{
+			Name:    s.Name,
+			Section: s.Section,
+			Origin:  s.Origin,
+			Size:    int64(s.Size),
+			Flash:   s.Flash,
+			Ram:     s.RAM,
+		})
+	}
+	return res
+}
@@ -0,0 +1,129 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"debug/elf"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSizeReport(t *testing.T) {
+	section := func(name string, typ elf.SectionType, flags elf.SectionFlag, size uint64) *elf.Section {
+		return &elf.Section{SectionHeader: elf.SectionHeader{Name: name, Type: typ, Flags: flags, Size: size}}
+	}
+	sections := []*elf.Section{
+		section("", elf.SHT_NULL, 0, 0),
+		section(".text", elf.SHT_PROGBITS, elf.SHF_ALLOC|elf.SHF_EXECINSTR, 1000),
+		section(".data", elf.SHT_PROGBITS, elf.SHF_ALLOC|elf.SHF_WRITE, 100),
+		section(".bss", elf.SHT_NOBITS, elf.SHF_ALLOC|elf.SHF_WRITE, 50),
+		section(".debug_info", elf.SHT_PROGBITS, 0, 5000),
+	}
+	symbol := func(name string, typ elf.SymType, sectionIndex elf.SectionIndex, size uint64) elf.Symbol {
+		return elf.Symbol{Name: name, Info: elf.ST_INFO(elf.STB_GLOBAL, typ), Section: sectionIndex, Size: size}
+	}
+	symbols := []elf.Symbol{
+		symbol("setup", elf.STT_FUNC, 1, 20),
+		symbol("loop", elf.STT_FUNC, 1, 30),
+		symbol("Serial", elf.STT_OBJECT, 3, 40),
+		symbol("buffer", elf.STT_OBJECT, 2, 60),
+		symbol("memcpy", elf.STT_FUNC, 1, 10),
+		symbol("empty", elf.STT_FUNC, 1, 0),
+		symbol("file.c", elf.STT_FILE, elf.SHN_ABS, 0),
+	}
+	origins := newSymbolOrigins()
+	origins.global["setup"] = symbolOrigin{name: "sketch"}
+	origins.global["loop"] = symbolOrigin{name: "sketch"}
+	origins.global["Serial"] = symbolOrigin{name: "core"}
+	origins.global["buffer"] = symbolOrigin{name: "MyLib"}
+
+	report := newSizeReport(sections, symbols, origins, 3)
+	require.Equal(t, []*SectionUsage{
+		{Name: ".text", Size: 1000, Flash: true},
+		{Name: ".data", Size: 100, Flash: true, RAM: true},
+		{Name: ".bss", Size: 50, RAM: true},
+	}, report.Sections)
+	require.Equal(t, []*OriginUsage{
+		{Name: "MyLib", Flash: 60, RAM: 60},
+		{Name: "sketch", Flash: 50},
+		{Name: "core", RAM: 40},
+		{Name: "other", Flash: 10},
+	}, report.Origins)
+	require.Equal(t, []*SymbolUsage{
+		{Name: "buffer", Section: ".data", Origin: "MyLib", Size: 60, Flash: true, RAM: true},
+		{Name: "Serial", Section: ".bss", Origin: "core", Size: 40, RAM: true},
+		{Name: "loop", Section: ".text", Origin: "sketch", Size: 30, Flash: true},
+	}, report.Symbols)
+}
+
+func TestSizeReportLocalSymbols(t *testing.T) {
+	sections := []*elf.Section{
+		{SectionHeader: elf.SectionHeader{Name: ""}},
+		{SectionHeader: elf.SectionHeader{Name: ".bss", Type: elf.SHT_NOBITS, Flags: elf.SHF_ALLOC | elf.SHF_WRITE, Size: 100}},
+	}
+	local := func(name string, typ elf.SymType, size uint64) elf.Symbol {
+		return elf.Symbol{Name: name, Info: elf.ST_INFO(elf.STB_LOCAL, typ), Section: 1, Size: size}
+	}
+	// Both the sketch and a library define a static "counter", with the
+	// same size in the sketch and in another library
+	symbols := []elf.Symbol{
+		local("sketch.ino.cpp", elf.STT_FILE, 0),
+		local("counter", elf.STT_OBJECT, 4),
+		local("Lib.cpp", elf.STT_FILE, 0),
+		local("counter", elf.STT_OBJECT, 2),
+		local("Other.cpp", elf.STT_FILE, 0),
+		local("counter", elf.STT_OBJECT, 4),
+		local("unique", elf.STT_OBJECT, 8),
+	}
+	origins := newSymbolOrigins()
+	origins.local[localSymbol{file: "sketch.ino.cpp", name: "counter", size: 4}] = "sketch"
+	origins.local[localSymbol{file: "Lib.cpp", name: "counter", size: 2}] = "Lib"
+	origins.local[localSymbol{file: "Other.cpp", name: "counter", size: 4}] = "Other"
+	origins.localByName["unique"] = "Other"
+
+	report := newSizeReport(sections, symbols, origins, 0)
+	found := []string{}
+	for _, symbol := range report.Symbols {
+		found = append(found, fmt.Sprintf("%s %d %s", symbol.Name, symbol.Size, symbol.Origin))
+	}
+	require.Equal(t, []string{"unique 8 Other", "counter 4 sketch", "counter 4 Other", "counter 2 Lib"}, found)
+}
+
+func TestReadArchive(t *testing.T) {
+	member := func(name string, content string) string {
+		res := fmt.Sprintf("%-16s%-12s%-6s%-6s%-8s%-10d`\n", name, "0", "0", "0", "644", len(content)) + content
+		if len(content)%2 == 1 {
+			res += "\n"
+		}
+		return res
+	}
+	archive := arMagic +
+		member("/", "symtab") +
+		member("//", "HardwareSerial0.cpp.o/\n") +
+		member("/0", "first") +
+		member("main.cpp.o/", "second!")
+
+	members, err := readArchive([]byte(archive))
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{[]byte("first"), []byte("second!")}, members)
+
+	_, err = readArchive([]byte(arMagic + "truncated"))
+	require.Error(t, err)
+	_, err = readArchive([]byte("not an archive"))
+	require.Error(t, err)
+}
@@ -0,0 +1,425 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/arduino/sketches/check"
+	paths "github.com/arduino/go-paths-helper"
+	properties "github.com/arduino/go-properties-orderedmap"
+)
+
+// RequiredRecipes are the recipes needed to build a sketch
+var RequiredRecipes = []string{
+	"recipe.c.o.pattern",
+	"recipe.cpp.o.pattern",
+	"recipe.S.o.pattern",
+	"recipe.ar.pattern",
+	"recipe.c.combine.pattern",
+}
+
+// runtimeProperties are the properties set by the CLI when a sketch is built
+// or uploaded
+var runtimeProperties = map[string]bool{
+	"build.arch":                    true,
+	"build.board":                   true,
+	"build.core.path":               true,
+	"build.fqbn":                    true,
+	"build.library_discovery_phase": true,
+	"build.path":                    true,
+	"build.project_name":            true,
+	"build.source.path":             true,
+	"build.system.path":             true,
+	"build.variant.path":            true,
+	"compiler.optimization_flags":   true,
+	"compiler.warning_flags":        true,
+	"ide_version":                   true,
+	"software":                      true,
+	"includes":                      true,
+	"source_file":                   true,
+	"object_file":                   true,
+	"object_files":                  true,
+	"archive_file":                  true,
+	"archive_file_path":             true,
+	"preprocessed_file_path":        true,
+	"upload.verbose":                true,
+	"upload.verify":                 true,
+	"program.verbose":               true,
+	"program.verify":                true,
+	"erase.verbose":                 true,
+	"erase.verify":                  true,
+	"bootloader.verbose":            true,
+	"bootloader.verify":             true,
+	"serial.port":                   true,
+	"serial.port.file":              true,
+}
+
+// runtimePrefixes are the prefixes of the groups of properties set by the CLI
+var runtimePrefixes = []string{"runtime.", "extra.time.", "upload.port.", "network.", "discovery."}
+
+var referenceRegexp = regexp.MustCompile(`\{([^{}\s]+)\}`)
+var toolReferenceRegexp = regexp.MustCompile(`\{runtime\.tools\.([^{}\s]+)\.path\}`)
+
+func isRuntimeProperty(name string) bool {
+	if runtimeProperties[name] {
+		return true
+	}
+	for _, prefix := range runtimePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Platform runs the checks on the platform in the given folder: the recipes,
+// the references to undefined properties, the boards and their menus. If
+// indexed is not nil, it is the release of the platform in a package index
+// and the tools used by the platform are checked against its dependencies.
+// The paths of the diagnostics are relative to the platform folder.
+func Platform(platformPath *paths.Path, indexed *cores.PlatformRelease) ([]*check.Diagnostic, error) {
+	if !platformPath.Join("platform.txt").Exist() {
+		return nil, fmt.Errorf("platform.txt not found in %s", platformPath)
+	}
+	l := &linter{platformPath: platformPath, lines: map[string]map[string]int{}}
+	var err error
+	if l.platform, err = l.load("platform.txt"); err != nil {
+		return nil, err
+	}
+	if l.boards, err = l.load("boards.txt"); err != nil {
+		return nil, err
+	}
+	l.checkPlatform()
+	l.checkBoards()
+	l.checkReferences()
+	if indexed != nil {
+		l.checkTools(indexed)
+	}
+	return l.diagnostics, nil
+}
+
+type linter struct {
+	platformPath *paths.Path
+	platform     *properties.Map
+	boards       *properties.Map
+	// lines maps the properties of each file to the line where they are defined
+	lines       map[string]map[string]int
+	diagnostics []*check.Diagnostic
+}
+
+func (l *linter) add(severity check.Severity, rule string, file string, key string, format string, args ...interface{}) {
+	d := &check.Diagnostic{Severity: severity, Rule: rule, File: file, Message: fmt.Sprintf(format, args...)}
+	if key != "" {
+		d.Line = l.lines[file][key]
+	}
+	l.diagnostics = append(l.diagnostics, d)
+}
+
+// load reads a properties file of the platform and its .local.txt override
+func (l *linter) load(file string) (*properties.Map, error) {
+	res := properties.NewMap()
+	local := strings.TrimSuffix(file, ".txt") + ".local.txt"
+	for _, name := range []string{file, local} {
+		path := l.platformPath.Join(name)
+		if !path.Exist() {
+			continue
+		}
+		props, err := properties.LoadFromPath(path)
+		if err != nil {
+			return nil, fmt.Errorf("loading %s: %s", name, err)
+		}
+		res.Merge(props)
+
+		data, err := path.ReadFile()
+		if err != nil {
+			return nil, err
+		}
+		lines := map[string]int{}
+		for i, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") || !strings.Contains(line, "=") {
+				continue
+			}
+			lines[strings.TrimSpace(line[:strings.Index(line, "=")])] = i + 1
+		}
+		l.lines[name] = lines
+	}
+	return res, nil
+}
+
+// fileOf returns the file where the given property of a properties file is
+// defined, it may be the .local.txt override
+func (l *linter) fileOf(file, key string) string {
+	local := strings.TrimSuffix(file, ".txt") + ".local.txt"
+	if _, ok := l.lines[local][key]; ok {
+		return local
+	}
+	return file
+}
+
+func (l *linter) checkPlatform() {
+	for _, name := range []string{"name", "version"} {
+		if l.platform.Get(name) == "" {
+			l.add(check.SeverityWarning, "missing-"+name, "platform.txt", "", "the '%s' property is missing", name)
+		}
+	}
+	for _, recipe := range RequiredRecipes {
+		if strings.TrimSpace(l.platform.Get(recipe)) == "" {
+			l.add(check.SeverityError, "missing-recipe", "platform.txt", "", "the '%s' recipe is missing", recipe)
+		}
+	}
+	if strings.TrimSpace(l.platform.Get("recipe.size.pattern")) == "" {
+		l.add(check.SeverityWarning, "missing-recipe", "platform.txt", "", "the 'recipe.size.pattern' recipe is missing, the size of the sketch will not be reported")
+	}
+	if len(l.platform.SubTree("recipe.objcopy").Keys()) == 0 {
+		l.add(check.SeverityWarning, "missing-recipe", "platform.txt", "", "no 'recipe.objcopy.*.pattern' recipe defined, no binary will be exported")
+	}
+}
+
+// boardIDs returns the boards defined in boards.txt, in the order they are defined
+func (l *linter) boardIDs() []string {
+	res := []string{}
+	for _, id := range l.boards.FirstLevelKeys() {
+		if id != "menu" {
+			res = append(res, id)
+		}
+	}
+	return res
+}
+
+func (l *linter) checkBoards() {
+	if !l.platformPath.Join("boards.txt").Exist() {
+		l.add(check.SeverityError, "missing-boards", "", "", "boards.txt not found")
+		return
+	}
+	boardIDs := l.boardIDs()
+	if len(boardIDs) == 0 {
+		l.add(check.SeverityError, "missing-boards", "boards.txt", "", "no boards defined")
+	}
+
+	menus := l.boards.SubTree("menu")
+	usedMenus := map[string]bool{}
+	for _, id := range boardIDs {
+		board := l.boards.SubTree(id)
+		if board.Get("name") == "" {
+			l.add(check.SeverityError, "board-name", l.fileOf("boards.txt", id+".name"), "", "the board '%s' has no name", id)
+		}
+		l.checkBoardFolders(id, board)
+		if board.Get("build.board") == "" {
+			l.add(check.SeverityWarning, "board-build-board", l.fileOf("boards.txt", id+".name"), id+".name", "the board '%s' has no 'build.board' property, the CLI will define ARDUINO_%s", id, strings.ToUpper(l.platformPath.Base()+"_"+id))
+		}
+		if tool := board.Get("upload.tool"); tool != "" && !strings.Contains(tool, ":") && !l.platform.ContainsKey("tools."+tool+".upload.pattern") {
+			key := id + ".upload.tool"
+			l.add(check.SeverityError, "upload-tool", l.fileOf("boards.txt", key), key, "the upload tool '%s' of the board '%s' has no 'tools.%s.upload.pattern' recipe", tool, id, tool)
+		}
+
+		boardMenus := board.SubTree("menu")
+		for _, menuID := range boardMenus.FirstLevelKeys() {
+			usedMenus[menuID] = true
+			if !menus.ContainsKey(menuID) {
+				key := id + ".menu." + menuID + "." + boardMenus.SubTree(menuID).FirstLevelKeys()[0]
+				l.add(check.SeverityError, "menu-undeclared", l.fileOf("boards.txt", key), key, "the board '%s' uses the menu '%s' that is not declared with 'menu.%s=<title>'", id, menuID, menuID)
+			}
+			options := boardMenus.SubTree(menuID)
+			for _, option := range options.FirstLevelKeys() {
+				if !options.ContainsKey(option) {
+					key := id + ".menu." + menuID + "." + option
+					l.add(check.SeverityError, "menu-option-label", "boards.txt", "", "the option '%s' of the menu '%s' of the board '%s' has no label, add '%s=<label>'", option, menuID, id, key)
+				}
+			}
+		}
+	}
+	for _, menuID := range menus.FirstLevelKeys() {
+		key := "menu." + menuID
+		if strings.TrimSpace(menus.Get(menuID)) == "" {
+			l.add(check.SeverityError, "menu-title", l.fileOf("boards.txt", key), key, "the menu '%s' has no title", menuID)
+		}
+		if !usedMenus[menuID] {
+			l.add(check.SeverityWarning, "menu-unused", l.fileOf("boards.txt", key), key, "the menu '%s' is not used by any board", menuID)
+		}
+	}
+}
+
+// checkBoardFolders checks that the core and the variant of a board exist,
+// the ones referenced from other platforms are not checked
+func (l *linter) checkBoardFolders(id string, board *properties.Map) {
+	core := board.Get("build.core")
+	if core == "" {
+		l.add(check.SeverityError, "board-core", l.fileOf("boards.txt", id+".name"), id+".name", "the board '%s' has no 'build.core' property", id)
+	} else if !strings.Contains(core, ":") && !l.platformPath.Join("cores", core).IsDir() {
+		key := id + ".build.core"
+		l.add(check.SeverityError, "board-core", l.fileOf("boards.txt", key), key, "the core '%s' of the board '%s' does not exist in the cores folder", core, id)
+	}
+	if variant := board.Get("build.variant"); variant != "" && !strings.Contains(variant, ":") && !l.platformPath.Join("variants", variant).IsDir() {
+		key := id + ".build.variant"
+		l.add(check.SeverityError, "board-variant", l.fileOf("boards.txt", key), key, "the variant '%s' of the board '%s' does not exist in the variants folder", variant, id)
+	}
+}
+
+// boardProperties returns the properties of a board with the first option of
+// each menu selected, as done when the FQBN doesn't specify the options
+func (l *linter) boardProperties(id string) *properties.Map {
+	board := l.boards.SubTree(id)
+	res := board.Clone()
+	menus := board.SubTree("menu")
+	for _, menuID := range menus.FirstLevelKeys() {
+		options := menus.SubTree(menuID)
+		if keys := options.FirstLevelKeys(); len(keys) > 0 {
+			res.Merge(options.SubTree(keys[0]))
+		}
+	}
+	return res
+}
+
+// isPattern returns true for the properties expanded when a sketch is built
+// or uploaded
+func isPattern(key string) bool {
+	if strings.HasPrefix(key, "recipe.") {
+		return strings.HasSuffix(key, ".pattern")
+	}
+	if strings.HasPrefix(key, "tools.") {
+		for _, action := range []string{".upload.pattern", ".program.pattern", ".erase.pattern", ".bootloader.pattern"} {
+			if strings.HasSuffix(key, action) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkReferences expands the recipes for each board and reports the
+// references to properties that are not defined
+func (l *linter) checkReferences() {
+	boardIDs := l.boardIDs()
+	contexts := map[string]*properties.Map{}
+	for _, id := range boardIDs {
+		props := l.platform.Clone()
+		props.Merge(l.boardProperties(id))
+		contexts[id] = props
+	}
+	if len(contexts) == 0 {
+		contexts[""] = l.platform.Clone()
+	}
+
+	// undefined maps the recipe and the missing property to the boards
+	type missing struct{ recipe, property string }
+	undefined := map[missing][]string{}
+	for id, props := range contexts {
+		for _, key := range l.platform.Keys() {
+			if !isPattern(key) {
+				continue
+			}
+			context := props
+			if strings.HasPrefix(key, "tools.") {
+				// the properties of the tool are available in its recipes
+				tool := strings.Split(key, ".")[1]
+				context = props.Clone()
+				context.Merge(props.SubTree("tools." + tool))
+			}
+			for _, property := range l.undefinedReferences(context, context.Get(key)) {
+				m := missing{recipe: key, property: property}
+				undefined[m] = append(undefined[m], id)
+			}
+		}
+	}
+
+	keys := []missing{}
+	for m := range undefined {
+		keys = append(keys, m)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].recipe != keys[j].recipe {
+			return l.lines["platform.txt"][keys[i].recipe] < l.lines["platform.txt"][keys[j].recipe]
+		}
+		return keys[i].property < keys[j].property
+	})
+	for _, m := range keys {
+		boards := undefined[m]
+		file := l.fileOf("platform.txt", m.recipe)
+		if len(boards) == len(contexts) {
+			l.add(check.SeverityError, "undefined-property", file, m.recipe, "'%s' references the undefined property {%s}", m.recipe, m.property)
+		} else {
+			sort.Strings(boards)
+			l.add(check.SeverityError, "undefined-property", file, m.recipe, "'%s' references the property {%s} that is not defined for the boards: %s", m.recipe, m.property, strings.Join(boards, ", "))
+		}
+	}
+}
+
+// undefinedReferences returns the properties referenced in value, directly
+// or through other properties, that are not defined in props
+func (l *linter) undefinedReferences(props *properties.Map, value string) []string {
+	res := []string{}
+	seen := map[string]bool{}
+	var visit func(value string)
+	visit = func(value string) {
+		for _, m := range referenceRegexp.FindAllStringSubmatch(value, -1) {
+			name := m[1]
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			if nested, ok := props.GetOk(name); ok {
+				visit(nested)
+			} else if !isRuntimeProperty(name) {
+				res = append(res, name)
+			}
+		}
+	}
+	visit(value)
+	return res
+}
+
+// checkTools checks that the tools referenced with {runtime.tools.NAME.path}
+// are dependencies of the indexed platform and that the dependencies are
+// available in the index
+func (l *linter) checkTools(indexed *cores.PlatformRelease) {
+	dependencies := map[string]bool{}
+	for _, dep := range indexed.Dependencies {
+		dependencies[dep.ToolName] = true
+		dependencies[dep.ToolName+"-"+dep.ToolVersion.String()] = true
+
+		var packages cores.Packages
+		if indexed.Platform != nil && indexed.Platform.Package != nil {
+			packages = indexed.Platform.Package.Packages
+		}
+		if _, err := packages.GetDepsOfPlatformRelease(&cores.PlatformRelease{Dependencies: cores.ToolDependencies{dep}}); err != nil {
+			l.add(check.SeverityError, "index-tool", "", "", "the tool dependency %s of %s is not available in the package index: %s", dep, indexed, err)
+		}
+	}
+
+	reported := map[string]bool{}
+	for _, file := range []string{"platform.txt", "boards.txt"} {
+		props := l.platform
+		if file == "boards.txt" {
+			props = l.boards
+		}
+		for _, key := range props.Keys() {
+			for _, m := range toolReferenceRegexp.FindAllStringSubmatch(props.Get(key), -1) {
+				tool := m[1]
+				if dependencies[tool] || reported[tool] {
+					continue
+				}
+				reported[tool] = true
+				l.add(check.SeverityError, "index-tool", l.fileOf(file, key), key, "the tool '%s' is used but it is not a dependency of %s in the package index", tool, indexed)
+			}
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package lint
+
+import (
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/arduino/sketches/check"
+	paths "github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+	semver "go.bug.st/relaxed-semver"
+)
+
+const platformTxt = "name=Test Boards\n" +
+	"version=1.0.0\n" +
+	"compiler.path={runtime.tools.avr-gcc.path}/bin/\n" +
+	"compiler.c.flags=-c -g -Os\n" +
+	"compiler.c.extra_flags=\n" +
+	"recipe.c.o.pattern=\"{compiler.path}avr-gcc\" {compiler.c.flags} -mmcu={build.mcu} {compiler.c.extra_flags} {includes} \"{source_file}\" -o \"{object_file}\"\n" +
+	"recipe.cpp.o.pattern=\"{compiler.path}avr-g++\" {compiler.c.flags} -mmcu={build.mcu} {includes} \"{source_file}\" -o \"{object_file}\"\n" +
+	"recipe.S.o.pattern=\"{compiler.path}avr-gcc\" -mmcu={build.mcu} \"{source_file}\" -o \"{object_file}\"\n" +
+	"recipe.ar.pattern=\"{compiler.path}avr-gcc-ar\" rcs \"{archive_file_path}\" \"{object_file}\"\n" +
+	"recipe.c.combine.pattern=\"{compiler.path}avr-gcc\" -mmcu={build.mcu} -o \"{build.path}/{build.project_name}.elf\" {object_files} \"{archive_file_path}\"\n" +
+	"recipe.objcopy.hex.pattern=\"{compiler.path}avr-objcopy\" -O ihex \"{build.path}/{build.project_name}.elf\" \"{build.path}/{build.project_name}.hex\"\n" +
+	"recipe.size.pattern=\"{compiler.path}avr-size\" -A \"{build.path}/{build.project_name}.elf\"\n" +
+	"tools.avrdude.path={runtime.tools.avrdude.path}\n" +
+	"tools.avrdude.cmd.path={path}/bin/avrdude\n" +
+	"tools.avrdude.upload.params.verbose=-v\n" +
+	"tools.avrdude.upload.params.quiet=-q\n" +
+	"tools.avrdude.upload.pattern=\"{cmd.path}\" {upload.verbose} -p{build.mcu} -c{upload.protocol} -P{serial.port} -b{upload.speed} \"-Uflash:w:{build.path}/{build.project_name}.hex:i\"\n"
+
+const boardsTxt = "menu.cpu=Processor\n" +
+	"uno.name=Arduino Uno\n" +
+	"uno.build.mcu=atmega328p\n" +
+	"uno.build.board=AVR_UNO\n" +
+	"uno.build.core=arduino\n" +
+	"uno.build.variant=standard\n" +
+	"uno.upload.tool=avrdude\n" +
+	"uno.upload.protocol=arduino\n" +
+	"uno.upload.speed=115200\n" +
+	"nano.name=Arduino Nano\n" +
+	"nano.build.board=AVR_NANO\n" +
+	"nano.build.core=arduino\n" +
+	"nano.build.variant=eightanaloginputs\n" +
+	"nano.upload.tool=avrdude\n" +
+	"nano.upload.protocol=arduino\n" +
+	"nano.menu.cpu.atmega328=ATmega328P\n" +
+	"nano.menu.cpu.atmega328.upload.speed=115200\n" +
+	"nano.menu.cpu.atmega328.build.mcu=atmega328p\n" +
+	"nano.menu.cpu.atmega168=ATmega168\n" +
+	"nano.menu.cpu.atmega168.upload.speed=19200\n" +
+	"nano.menu.cpu.atmega168.build.mcu=atmega168\n"
+
+func createPlatform(t *testing.T, dir *paths.Path, files map[string]string) {
+	for name, content := range files {
+		file := dir.Join(name)
+		require.NoError(t, file.Parent().MkdirAll())
+		require.NoError(t, file.WriteFile([]byte(content)))
+	}
+}
+
+func messages(diagnostics []*check.Diagnostic) []string {
+	res := []string{}
+	for _, d := range diagnostics {
+		res = append(res, d.String())
+	}
+	return res
+}
+
+func TestLintValidPlatform(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "core_lint")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+
+	createPlatform(t, tmp, map[string]string{
+		"platform.txt":                      platformTxt,
+		"boards.txt":                        boardsTxt,
+		"cores/arduino/Arduino.h":           "",
+		"variants/standard/pins_arduino.h":  "",
+		"variants/eightanaloginputs/pins.h": "",
+	})
+	diagnostics, err := Platform(tmp, nil)
+	require.NoError(t, err)
+	require.Empty(t, messages(diagnostics))
+}
+
+func TestLintPlatformErrors(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "core_lint")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+
+	_, err = Platform(tmp, nil)
+	require.Error(t, err)
+
+	platformPath := tmp.Join("avr")
+	createPlatform(t, platformPath, map[string]string{
+		"platform.txt": "name=Test Boards\n" +
+			"recipe.c.o.pattern=gcc {compiler.c.flags} {build.extra_flags} -mmcu={build.mcu} {source_file}\n" +
+			"recipe.cpp.o.pattern=g++ {source_file}\n" +
+			"recipe.S.o.pattern=gcc {source_file}\n" +
+			"recipe.c.combine.pattern=gcc {object_files}\n" +
+			"compiler.c.flags=-Os {compiler.c.missing}\n",
+		"boards.txt": "menu.cpu=Processor\n" +
+			"menu.unused=Unused\n" +
+			"uno.name=Arduino Uno\n" +
+			"uno.build.mcu=atmega328p\n" +
+			"uno.build.core=arduino\n" +
+			"uno.build.variant=standard\n" +
+			"uno.build.extra_flags=-DUNO\n" +
+			"uno.upload.tool=avrdude\n" +
+			"uno.menu.clock.16MHz=16 MHz\n" +
+			"uno.menu.cpu.atmega328.build.mcu=atmega328p\n" +
+			"mega.name=Arduino Mega\n" +
+			"mega.build.board=AVR_MEGA\n" +
+			"mega.build.core=arduino\n",
+		"cores/arduino/Arduino.h": "",
+	})
+	diagnostics, err := Platform(platformPath, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"platform.txt: warning: the 'version' property is missing [missing-version]",
+		"platform.txt: error: the 'recipe.ar.pattern' recipe is missing [missing-recipe]",
+		"platform.txt: warning: the 'recipe.size.pattern' recipe is missing, the size of the sketch will not be reported [missing-recipe]",
+		"platform.txt: warning: no 'recipe.objcopy.*.pattern' recipe defined, no binary will be exported [missing-recipe]",
+		"boards.txt:6: error: the variant 'standard' of the board 'uno' does not exist in the variants folder [board-variant]",
+		"boards.txt:3: warning: the board 'uno' has no 'build.board' property, the CLI will define ARDUINO_AVR_UNO [board-build-board]",
+		"boards.txt:8: error: the upload tool 'avrdude' of the board 'uno' has no 'tools.avrdude.upload.pattern' recipe [upload-tool]",
+		"boards.txt:9: error: the board 'uno' uses the menu 'clock' that is not declared with 'menu.clock=<title>' [menu-undeclared]",
+		"boards.txt: error: the option 'atmega328' of the menu 'cpu' of the board 'uno' has no label, add 'uno.menu.cpu.atmega328=<label>' [menu-option-label]",
+		"boards.txt:2: warning: the menu 'unused' is not used by any board [menu-unused]",
+		"platform.txt:2: error: 'recipe.c.o.pattern' references the property {build.extra_flags} that is not defined for the boards: mega [undefined-property]",
+		"platform.txt:2: error: 'recipe.c.o.pattern' references the property {build.mcu} that is not defined for the boards: mega [undefined-property]",
+		"platform.txt:2: error: 'recipe.c.o.pattern' references the undefined property {compiler.c.missing} [undefined-property]",
+	}, messages(diagnostics))
+}
+
+func TestLintTools(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "core_lint")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+
+	createPlatform(t, tmp, map[string]string{
+		"platform.txt":                      platformTxt + "tools.bossac.path={runtime.tools.bossac-1.7.0.path}\n",
+		"boards.txt":                        boardsTxt,
+		"cores/arduino/Arduino.h":           "",
+		"variants/standard/pins_arduino.h":  "",
+		"variants/eightanaloginputs/pins.h": "",
+	})
+
+	packages := cores.NewPackages()
+	arduino := packages.GetOrCreatePackage("arduino")
+	arduino.GetOrCreateTool("avr-gcc").GetOrCreateRelease(semver.ParseRelaxed("7.3.0-atmel3.6.1-arduino7"))
+	release := arduino.GetOrCreatePlatform("avr").GetOrCreateRelease(semver.MustParse("1.0.0"))
+	release.Dependencies = cores.ToolDependencies{
+		{ToolPackager: "arduino", ToolName: "avr-gcc", ToolVersion: semver.ParseRelaxed("7.3.0-atmel3.6.1-arduino7")},
+		{ToolPackager: "arduino", ToolName: "avrdude", ToolVersion: semver.ParseRelaxed("6.3.0-arduino17")},
+	}
+
+	diagnostics, err := Platform(tmp, release)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"error: the tool dependency arduino:avrdude@6.3.0-arduino17 of arduino:avr@1.0.0 is not available in the package index: tool avrdude not found [index-tool]",
+		"platform.txt:18: error: the tool 'bossac-1.7.0' is used but it is not a dependency of arduino:avr@1.0.0 in the package index [index-tool]",
+	}, messages(diagnostics))
+}
@@ -18,6 +18,9 @@ package packageindex
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"path"
+	"strings"
 
 	"github.com/arduino/arduino-cli/arduino/cores"
 	"github.com/arduino/arduino-cli/arduino/resources"
@@ -112,28 +115,6 @@ func (index Index) MergeIntoPackages(outPackages cores.Packages) {
 // which in turn contains a single indexPlatformRelease converted from the one
 // passed as argument
 func IndexFromPlatformRelease(pr *cores.PlatformRelease) Index {
-	boards := []indexBoard{}
-	for _, manifest := range pr.BoardsManifest {
-		board := indexBoard{
-			Name: manifest.Name,
-		}
-		for _, id := range manifest.ID {
-			if id.USB != "" {
-				board.ID = []indexBoardID{{USB: id.USB}}
-			}
-		}
-		boards = append(boards, board)
-	}
-
-	tools := []indexToolDependency{}
-	for _, t := range pr.Dependencies {
-		tools = append(tools, indexToolDependency{
-			Packager: t.ToolPackager,
-			Name:     t.ToolName,
-			Version:  t.ToolVersion,
-		})
-	}
-
 	packageTools := []*indexToolRelease{}
 	for name, tool := range pr.Platform.Package.Tools {
 		for _, toolRelease := range tool.Releases {
@@ -164,27 +145,55 @@ func IndexFromPlatformRelease(pr *cores.PlatformRelease) Index {
 				WebsiteURL: pr.Platform.Package.WebsiteURL,
 				URL:        pr.Platform.Package.URL,
 				Email:      pr.Platform.Package.Email,
-				Platforms: []*indexPlatformRelease{{
-					Name:             pr.Platform.Name,
-					Architecture:     pr.Platform.Architecture,
-					Version:          pr.Version,
-					Deprecated:       pr.Platform.Deprecated,
-					Category:         pr.Platform.Category,
-					URL:              pr.Resource.URL,
-					ArchiveFileName:  pr.Resource.ArchiveFileName,
-					Checksum:         pr.Resource.Checksum,
-					Size:             json.Number(fmt.Sprintf("%d", pr.Resource.Size)),
-					Boards:           boards,
-					Help:             indexHelp{Online: pr.Help.Online},
-					ToolDependencies: tools,
-				}},
-				Tools: packageTools,
-				Help:  indexHelp{Online: pr.Platform.Package.Help.Online},
+				Platforms:  []*indexPlatformRelease{newIndexPlatformRelease(pr)},
+				Tools:      packageTools,
+				Help:       indexHelp{Online: pr.Platform.Package.Help.Online},
 			},
 		},
 	}
 }
 
+// newIndexPlatformRelease converts a PlatformRelease in an entry of the
+// platforms of a package
+func newIndexPlatformRelease(pr *cores.PlatformRelease) *indexPlatformRelease {
+	boards := []indexBoard{}
+	for _, manifest := range pr.BoardsManifest {
+		board := indexBoard{
+			Name: manifest.Name,
+		}
+		for _, id := range manifest.ID {
+			if id.USB != "" {
+				board.ID = []indexBoardID{{USB: id.USB}}
+			}
+		}
+		boards = append(boards, board)
+	}
+
+	tools := []indexToolDependency{}
+	for _, t := range pr.Dependencies {
+		tools = append(tools, indexToolDependency{
+			Packager: t.ToolPackager,
+			Name:     t.ToolName,
+			Version:  t.ToolVersion,
+		})
+	}
+
+	return &indexPlatformRelease{
+		Name:             pr.Platform.Name,
+		Architecture:     pr.Platform.Architecture,
+		Version:          pr.Version,
+		Deprecated:       pr.Platform.Deprecated,
+		Category:         pr.Platform.Category,
+		URL:              pr.Resource.URL,
+		ArchiveFileName:  pr.Resource.ArchiveFileName,
+		Checksum:         pr.Resource.Checksum,
+		Size:             json.Number(fmt.Sprintf("%d", pr.Resource.Size)),
+		Boards:           boards,
+		Help:             indexHelp{Online: pr.Help.Online},
+		ToolDependencies: tools,
+	}
+}
+
 func (inPackage indexPackage) extractPackageIn(outPackages cores.Packages, trusted bool) {
 	outPackage := outPackages.GetOrCreatePackage(inPackage.Name)
 	outPackage.Maintainer = inPackage.Maintainer
@@ -194,7 +203,7 @@ func (inPackage indexPackage) extractPackageIn(outPackages cores.Packages, trust
 	outPackage.Help = cores.PackageHelp{Online: inPackage.Help.Online}
 
 	for _, inTool := range inPackage.Tools {
-		inTool.extractToolIn(outPackage)
+		inTool.extractToolIn(outPackage, trusted)
 	}
 
 	for _, inPlatform := range inPackage.Platforms {
@@ -264,10 +273,11 @@ func (inPlatformRelease indexPlatformRelease) extractBoardsManifest() []*cores.B
 	return boards
 }
 
-func (inToolRelease indexToolRelease) extractToolIn(outPackage *cores.Package) {
+func (inToolRelease indexToolRelease) extractToolIn(outPackage *cores.Package, trusted bool) {
 	outTool := outPackage.GetOrCreateTool(inToolRelease.Name)
 
 	outToolRelease := outTool.GetOrCreateRelease(inToolRelease.Version)
+	outToolRelease.IsTrusted = trusted
 	outToolRelease.Flavors = inToolRelease.extractFlavours()
 }
 
@@ -290,8 +300,33 @@ func (inToolRelease indexToolRelease) extractFlavours() []*cores.Flavor {
 	return ret
 }
 
+// IndexFileName returns the name of the local copy of the package index
+// downloaded from URL. The compressed indexes (.json.gz and .json.zst) are
+// stored uncompressed.
+func IndexFileName(URL *url.URL) string {
+	return strings.TrimSuffix(path.Base(URL.Path), IndexCompression(URL))
+}
+
+// IndexCompression returns the extension of the compressed package index
+// downloaded from URL, ".gz" for gzip or ".zst" for zstd, or "" if the index
+// is not compressed
+func IndexCompression(URL *url.URL) string {
+	for _, ext := range []string{".gz", ".zst"} {
+		if strings.HasSuffix(URL.Path, ext) {
+			return ext
+		}
+	}
+	return ""
+}
+
 // LoadIndex reads a package_index.json from a file and returns the corresponding Index structure.
 func LoadIndex(jsonIndexFile *paths.Path) (*Index, error) {
+	return LoadIndexWithTrustedKeys(jsonIndexFile, nil)
+}
+
+// LoadIndexWithTrustedKeys is like LoadIndex, but the index is trusted also if
+// signed by one of the public keys in the trustedKeys files.
+func LoadIndexWithTrustedKeys(jsonIndexFile *paths.Path, trustedKeys paths.PathList) (*Index, error) {
 	buff, err := jsonIndexFile.ReadFile()
 	if err != nil {
 		return nil, err
@@ -303,7 +338,7 @@ func LoadIndex(jsonIndexFile *paths.Path) (*Index, error) {
 	}
 
 	jsonSignatureFile := jsonIndexFile.Parent().Join(jsonIndexFile.Base() + ".sig")
-	trusted, _, err := security.VerifyArduinoDetachedSignature(jsonIndexFile, jsonSignatureFile)
+	trusted, _, err := security.VerifyDetachedSignatureWithTrustedKeys(jsonIndexFile, jsonSignatureFile, trustedKeys)
 	if err != nil {
 		logrus.
 			WithField("index", jsonIndexFile).
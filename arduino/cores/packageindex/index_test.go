@@ -16,6 +16,7 @@
 package packageindex
 
 import (
+	"net/url"
 	"testing"
 
 	"github.com/arduino/arduino-cli/arduino/cores"
@@ -39,6 +40,19 @@ func TestIndexParsing(t *testing.T) {
 	}
 }
 
+func TestIndexFileName(t *testing.T) {
+	for _, test := range []struct{ url, name, compression string }{
+		{"https://example.com/package_example_index.json", "package_example_index.json", ""},
+		{"https://example.com/package_example_index.json.gz", "package_example_index.json", ".gz"},
+		{"https://example.com/package_example_index.json.zst", "package_example_index.json", ".zst"},
+	} {
+		URL, err := url.Parse(test.url)
+		require.NoError(t, err)
+		require.Equal(t, test.name, IndexFileName(URL))
+		require.Equal(t, test.compression, IndexCompression(URL))
+	}
+}
+
 func TestIndexFromPlatformRelease(t *testing.T) {
 	pr := &cores.PlatformRelease{
 		Resource: &resources.DownloadResource{
@@ -352,3 +366,73 @@ func TestIndexFromPlatformRelease(t *testing.T) {
 		}
 	}
 }
+
+func TestAddPlatformRelease(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "package_index")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+
+	newRelease := func(version string) *cores.PlatformRelease {
+		packages := cores.NewPackages()
+		pkg := packages.GetOrCreatePackage("mycompany")
+		pkg.Maintainer = "My Company"
+		platform := pkg.GetOrCreatePlatform("avr")
+		platform.Name = "My Boards"
+		platform.Category = "Contributed"
+		release := platform.GetOrCreateRelease(semver.MustParse(version))
+		release.Resource = &resources.DownloadResource{
+			URL:             "https://example.com/mycompany-avr-" + version + ".zip",
+			ArchiveFileName: "mycompany-avr-" + version + ".zip",
+			Checksum:        "SHA-256:1234",
+			Size:            100,
+		}
+		release.BoardsManifest = []*cores.BoardManifest{{Name: "My <Uno>"}}
+		release.Dependencies = cores.ToolDependencies{
+			{ToolPackager: "arduino", ToolName: "avr-gcc", ToolVersion: semver.ParseRelaxed("7.3.0-atmel3.6.1-arduino7")},
+		}
+		return release
+	}
+
+	snippet, err := MarshalPlatformRelease(newRelease("1.0.0"))
+	require.NoError(t, err)
+	require.Contains(t, string(snippet), "\"name\": \"My <Uno>\"")
+	require.Contains(t, string(snippet), "\"checksum\": \"SHA-256:1234\"")
+
+	// a new index file is created
+	indexFile := tmp.Join("package_mycompany_index.json")
+	require.NoError(t, AddPlatformRelease(indexFile, newRelease("1.0.0")))
+	index, err := LoadIndexNoSign(indexFile)
+	require.NoError(t, err)
+	require.Len(t, index.Packages, 1)
+	require.Equal(t, "My Company", index.Packages[0].Maintainer)
+	require.Len(t, index.Packages[0].Platforms, 1)
+
+	// the fields unknown to the CLI and their order are preserved
+	require.NoError(t, indexFile.WriteFile([]byte(`{
+  "packages": [
+    {
+      "name": "mycompany",
+      "maintainer": "My Company",
+      "x-custom": {"b": 1, "a": 2},
+      "platforms": [
+        {"name": "My Boards", "architecture": "avr", "version": "1.0.0", "size": "1", "checksum": "SHA-256:0000", "archiveFileName": "old.zip", "toolsDependencies": []}
+      ],
+      "tools": []
+    }
+  ],
+  "x-generator": "make-index.sh"
+}`)))
+	require.NoError(t, AddPlatformRelease(indexFile, newRelease("1.1.0")))
+	require.NoError(t, AddPlatformRelease(indexFile, newRelease("1.0.0")))
+	data, err := indexFile.ReadFile()
+	require.NoError(t, err)
+	require.Contains(t, string(data), "\"x-custom\": {\n        \"b\": 1,\n        \"a\": 2\n      },")
+	require.Contains(t, string(data), "\"x-generator\": \"make-index.sh\"")
+	require.NotContains(t, string(data), "old.zip")
+	index, err = LoadIndexNoSign(indexFile)
+	require.NoError(t, err)
+	require.Len(t, index.Packages[0].Platforms, 2)
+	require.Equal(t, "1.0.0", index.Packages[0].Platforms[0].Version.String())
+	require.Equal(t, "1.1.0", index.Packages[0].Platforms[1].Version.String())
+	require.Equal(t, "SHA-256:1234", index.Packages[0].Platforms[0].Checksum)
+}
@@ -0,0 +1,92 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packageindex
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/arduino/resources"
+)
+
+// MarshalMirrorIndex returns a package index, in JSON format, listing only the
+// given platform and tool releases. The tool flavours not accepted by
+// flavourFilter are left out and the URLs of the archives are replaced with
+// the ones returned by archiveURL. It's used to create the index of a mirror.
+func MarshalMirrorIndex(platforms []*cores.PlatformRelease, tools []*cores.ToolRelease,
+	flavourFilter func(*cores.Flavor) bool, archiveURL func(*resources.DownloadResource) string) ([]byte, error) {
+	index := struct {
+		Packages []*indexPackage `json:"packages"`
+	}{}
+	packages := map[string]*indexPackage{}
+	getPackage := func(pkg *cores.Package) *indexPackage {
+		if res, ok := packages[pkg.Name]; ok {
+			return res
+		}
+		res := &indexPackage{
+			Name:       pkg.Name,
+			Maintainer: pkg.Maintainer,
+			WebsiteURL: pkg.WebsiteURL,
+			URL:        pkg.URL,
+			Email:      pkg.Email,
+			Platforms:  []*indexPlatformRelease{},
+			Tools:      []*indexToolRelease{},
+			Help:       indexHelp{Online: pkg.Help.Online},
+		}
+		packages[pkg.Name] = res
+		index.Packages = append(index.Packages, res)
+		return res
+	}
+
+	for _, pr := range platforms {
+		release := newIndexPlatformRelease(pr)
+		release.URL = archiveURL(pr.Resource)
+		pkg := getPackage(pr.Platform.Package)
+		pkg.Platforms = append(pkg.Platforms, release)
+	}
+	for _, tr := range tools {
+		flavours := []indexToolReleaseFlavour{}
+		for _, flavour := range tr.Flavors {
+			if !flavourFilter(flavour) {
+				continue
+			}
+			flavours = append(flavours, indexToolReleaseFlavour{
+				OS:              flavour.OS,
+				URL:             archiveURL(flavour.Resource),
+				ArchiveFileName: flavour.Resource.ArchiveFileName,
+				Size:            json.Number(fmt.Sprintf("%d", flavour.Resource.Size)),
+				Checksum:        flavour.Resource.Checksum,
+			})
+		}
+		pkg := getPackage(tr.Tool.Package)
+		pkg.Tools = append(pkg.Tools, &indexToolRelease{
+			Name:    tr.Tool.Name,
+			Version: tr.Version,
+			Systems: flavours,
+		})
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(index); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,207 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packageindex
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/go-paths-helper"
+)
+
+// MarshalPlatformRelease returns the entry of a package index describing the
+// given platform release, in JSON format
+func MarshalPlatformRelease(pr *cores.PlatformRelease) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(newIndexPlatformRelease(pr)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// AddPlatformRelease adds the given platform release to a package index file,
+// replacing the release with the same architecture and version if present.
+// The package is added if missing, and the file is created if it doesn't
+// exist. The rest of the file is preserved, including the fields unknown to
+// the CLI and the order of the fields.
+func AddPlatformRelease(indexFile *paths.Path, pr *cores.PlatformRelease) error {
+	index := &jsonObject{}
+	if indexFile.Exist() {
+		data, err := indexFile.ReadFile()
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, index); err != nil {
+			return fmt.Errorf("reading %s: %s", indexFile, err)
+		}
+	}
+
+	packages := []*jsonObject{}
+	if raw, ok := index.values["packages"]; ok {
+		if err := json.Unmarshal(raw, &packages); err != nil {
+			return fmt.Errorf("reading packages: %s", err)
+		}
+	}
+	var targetPackage *jsonObject
+	for _, p := range packages {
+		var name string
+		if err := json.Unmarshal(p.values["name"], &name); err == nil && name == pr.Platform.Package.Name {
+			targetPackage = p
+			break
+		}
+	}
+	if targetPackage == nil {
+		targetPackage = &jsonObject{}
+		targetPackage.set("name", pr.Platform.Package.Name)
+		targetPackage.set("maintainer", pr.Platform.Package.Maintainer)
+		targetPackage.set("websiteURL", pr.Platform.Package.WebsiteURL)
+		targetPackage.set("email", pr.Platform.Package.Email)
+		targetPackage.set("help", indexHelp{Online: pr.Platform.Package.Help.Online})
+		targetPackage.set("platforms", []interface{}{})
+		targetPackage.set("tools", []interface{}{})
+		packages = append(packages, targetPackage)
+	}
+
+	platforms := []json.RawMessage{}
+	if raw, ok := targetPackage.values["platforms"]; ok {
+		if err := json.Unmarshal(raw, &platforms); err != nil {
+			return fmt.Errorf("reading platforms of package %s: %s", pr.Platform.Package.Name, err)
+		}
+	}
+	entry, err := marshal(newIndexPlatformRelease(pr))
+	if err != nil {
+		return err
+	}
+	replaced := false
+	for i, raw := range platforms {
+		var platform struct {
+			Architecture string `json:"architecture"`
+			Version      string `json:"version"`
+		}
+		if err := json.Unmarshal(raw, &platform); err != nil {
+			continue
+		}
+		if platform.Architecture == pr.Platform.Architecture && platform.Version == pr.Version.String() {
+			platforms[i] = entry
+			replaced = true
+		}
+	}
+	if !replaced {
+		platforms = append(platforms, entry)
+	}
+	if err := targetPackage.set("platforms", platforms); err != nil {
+		return err
+	}
+	if err := index.set("packages", packages); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(index); err != nil {
+		return err
+	}
+	return indexFile.WriteFile(buf.Bytes())
+}
+
+// jsonObject is a JSON object that keeps the order of its fields
+type jsonObject struct {
+	keys   []string
+	values map[string]json.RawMessage
+}
+
+func (o *jsonObject) set(key string, value interface{}) error {
+	data, err := marshal(value)
+	if err != nil {
+		return err
+	}
+	if o.values == nil {
+		o.values = map[string]json.RawMessage{}
+	}
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = data
+	return nil
+}
+
+func (o *jsonObject) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := decoder.Token(); err != nil {
+		return err
+	} else if tok != json.Delim('{') {
+		return fmt.Errorf("expected a JSON object")
+	}
+	o.keys = nil
+	o.values = map[string]json.RawMessage{}
+	for decoder.More() {
+		tok, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return fmt.Errorf("invalid key %v", tok)
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return err
+		}
+		if _, ok := o.values[key]; !ok {
+			o.keys = append(o.keys, key)
+		}
+		o.values[key] = value
+	}
+	_, err := decoder.Token()
+	return err
+}
+
+func (o *jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		k, err := marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteString(":")
+		buf.Write(o.values[key])
+	}
+	buf.WriteString("}")
+	return buf.Bytes(), nil
+}
+
+// marshal is like json.Marshal but doesn't escape the HTML characters
+func marshal(value interface{}) (json.RawMessage, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
@@ -0,0 +1,117 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"fmt"
+
+	"github.com/arduino/go-paths-helper"
+	properties "github.com/arduino/go-properties-orderedmap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// LoadBoardsOverlay reads the board definitions found in overlayDir, that must
+// be structured as PACKAGER/ARCHITECTURE/*.txt. The definitions are merged over
+// the boards.txt (and boards.local.txt) of the matching platforms when they are
+// loaded, so it must be called before loading the hardware. A property set to
+// different values in two files of the same platform is reported as a
+// conflict, the value in the last file in alphabetical order is used.
+func (pm *PackageManager) LoadBoardsOverlay(overlayDir *paths.Path) []*status.Status {
+	pm.boardsOverlay = map[string]*properties.Map{}
+	if overlayDir == nil || !overlayDir.IsDir() {
+		return nil
+	}
+	pm.Log.Infof("Loading boards overlay from: %s", overlayDir)
+
+	statuses := []*status.Status{}
+	packagers, err := overlayDir.ReadDir()
+	if err != nil {
+		return []*status.Status{status.Newf(codes.FailedPrecondition, "reading boards overlay directory %s: %s", overlayDir, err)}
+	}
+	packagers.FilterDirs()
+	packagers.FilterOutHiddenFiles()
+	for _, packagerDir := range packagers {
+		architectures, err := packagerDir.ReadDir()
+		if err != nil {
+			statuses = append(statuses, status.Newf(codes.FailedPrecondition, "reading boards overlay directory %s: %s", packagerDir, err))
+			continue
+		}
+		architectures.FilterDirs()
+		architectures.FilterOutHiddenFiles()
+		for _, architectureDir := range architectures {
+			platformID := packagerDir.Base() + ":" + architectureDir.Base()
+			overlay, errs := loadPlatformBoardsOverlay(platformID, architectureDir)
+			statuses = append(statuses, errs...)
+			if overlay.Size() > 0 {
+				pm.boardsOverlay[platformID] = overlay
+			}
+		}
+	}
+	return statuses
+}
+
+func loadPlatformBoardsOverlay(platformID string, dir *paths.Path) (*properties.Map, []*status.Status) {
+	overlay := properties.NewMap()
+	statuses := []*status.Status{}
+	files, err := dir.ReadDir()
+	if err != nil {
+		return overlay, []*status.Status{status.Newf(codes.FailedPrecondition, "reading boards overlay directory %s: %s", dir, err)}
+	}
+	files.FilterOutDirs()
+	files.FilterOutHiddenFiles()
+	files.FilterSuffix(".txt")
+	files.Sort()
+
+	definedIn := map[string]*paths.Path{}
+	for _, file := range files {
+		props, err := properties.LoadFromPath(file)
+		if err != nil {
+			statuses = append(statuses, status.Newf(codes.FailedPrecondition, "loading boards overlay %s: %s", file, err))
+			continue
+		}
+		for _, key := range props.Keys() {
+			value := props.Get(key)
+			if previous, ok := definedIn[key]; ok && overlay.Get(key) != value {
+				statuses = append(statuses, status.Newf(codes.AlreadyExists,
+					"boards overlay conflict for %s: %s is set in both %s and %s, using the value in %s",
+					platformID, key, previous, file, file))
+			}
+			definedIn[key] = file
+			overlay.Set(key, value)
+		}
+	}
+	return overlay, statuses
+}
+
+// checkBoardsOverlay reports the overlays of platforms that are not installed
+func (pm *PackageManager) checkBoardsOverlay() []*status.Status {
+	statuses := []*status.Status{}
+	for platformID := range pm.boardsOverlay {
+		installed := false
+		for _, targetPackage := range pm.Packages {
+			for _, platform := range targetPackage.Platforms {
+				if fmt.Sprintf("%s:%s", targetPackage.Name, platform.Architecture) == platformID {
+					installed = len(platform.GetAllInstalled()) > 0
+				}
+			}
+		}
+		if !installed {
+			statuses = append(statuses, status.Newf(codes.NotFound, "boards overlay for %s: platform not installed", platformID))
+		}
+	}
+	return statuses
+}
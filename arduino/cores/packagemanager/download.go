@@ -105,11 +105,11 @@ func (pm *PackageManager) DownloadToolRelease(tool *cores.ToolRelease, config *d
 	if resource == nil {
 		return nil, fmt.Errorf("tool not available for your OS")
 	}
-	return resource.Download(pm.DownloadDir, config)
+	return resource.Download(pm.DownloadDir, config, pm.Mirrors...)
 }
 
 // DownloadPlatformRelease downloads a PlatformRelease. If the platform is already downloaded a
 // nil Downloader is returned.
 func (pm *PackageManager) DownloadPlatformRelease(platform *cores.PlatformRelease, config *downloader.Config) (*downloader.Downloader, error) {
-	return platform.Resource.Download(pm.DownloadDir, config)
+	return platform.Resource.Download(pm.DownloadDir, config, pm.Mirrors...)
 }
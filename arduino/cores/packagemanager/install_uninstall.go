@@ -19,15 +19,21 @@ import (
 	"encoding/json"
 	"fmt"
 	"runtime"
+	"sort"
 
 	"github.com/arduino/arduino-cli/arduino/cores"
 	"github.com/arduino/arduino-cli/arduino/cores/packageindex"
+	"github.com/arduino/arduino-cli/arduino/security"
 	"github.com/arduino/arduino-cli/executils"
+	"github.com/arduino/go-paths-helper"
 	"github.com/pkg/errors"
 )
 
 // InstallPlatform installs a specific release of a platform.
 func (pm *PackageManager) InstallPlatform(platformRelease *cores.PlatformRelease) error {
+	if err := pm.checkTrusted(platformRelease.String(), platformRelease.IsTrusted); err != nil {
+		return err
+	}
 	destDir := pm.PackagesDir.Join(
 		platformRelease.Platform.Package.Name,
 		"hardware",
@@ -64,12 +70,7 @@ func (pm *PackageManager) RunPostInstallScript(platformRelease *cores.PlatformRe
 	if !platformRelease.IsInstalled() {
 		return errors.New("platform not installed")
 	}
-	postInstallFilename := "post_install.sh"
-	if runtime.GOOS == "windows" {
-		postInstallFilename = "post_install.bat"
-	}
-	postInstall := platformRelease.InstallDir.Join(postInstallFilename)
-	if postInstall.Exist() && postInstall.IsNotDir() {
+	if postInstall := pm.PostInstallScript(platformRelease); postInstall != nil {
 		cmd, err := executils.NewProcessFromPath(postInstall)
 		if err != nil {
 			return err
@@ -82,6 +83,49 @@ func (pm *PackageManager) RunPostInstallScript(platformRelease *cores.PlatformRe
 	return nil
 }
 
+// PostInstallScript returns the post_install.sh (or post_install.bat) script of
+// the specified installed platformRelease, or nil if the platform doesn't have one.
+func (pm *PackageManager) PostInstallScript(platformRelease *cores.PlatformRelease) *paths.Path {
+	if !platformRelease.IsInstalled() {
+		return nil
+	}
+	postInstall := platformRelease.InstallDir.Join(postInstallScriptName())
+	if postInstall.Exist() && postInstall.IsNotDir() {
+		return postInstall
+	}
+	return nil
+}
+
+// ArchiveHasPostInstallScript tells if the downloaded archive of the
+// specified platformRelease contains the post_install.sh (or post_install.bat)
+// script. The second value is false if the archive has not been downloaded.
+func (pm *PackageManager) ArchiveHasPostInstallScript(platformRelease *cores.PlatformRelease) (bool, bool) {
+	if platformRelease.Resource == nil || pm.DownloadDir == nil {
+		return false, false
+	}
+	files, err := platformRelease.Resource.ArchiveRootFiles(pm.DownloadDir)
+	if err != nil {
+		pm.Log.WithError(err).Warnf("Reading archive of %s", platformRelease)
+		return false, false
+	}
+	if files == nil {
+		return false, false
+	}
+	for _, file := range files {
+		if file == postInstallScriptName() {
+			return true, true
+		}
+	}
+	return false, true
+}
+
+func postInstallScriptName() string {
+	if runtime.GOOS == "windows" {
+		return "post_install.bat"
+	}
+	return "post_install.sh"
+}
+
 // IsManagedPlatformRelease returns true if the PlatforRelease is managed by the PackageManager
 func (pm *PackageManager) IsManagedPlatformRelease(platformRelease *cores.PlatformRelease) bool {
 	if pm.PackagesDir == nil {
@@ -122,6 +166,9 @@ func (pm *PackageManager) UninstallPlatform(platformRelease *cores.PlatformRelea
 
 // InstallTool installs a specific release of a tool.
 func (pm *PackageManager) InstallTool(toolRelease *cores.ToolRelease) error {
+	if err := pm.checkTrusted(toolRelease.String(), toolRelease.IsTrusted); err != nil {
+		return err
+	}
 	toolResource := toolRelease.GetCompatibleFlavour()
 	if toolResource == nil {
 		return fmt.Errorf("no compatible version of %s tools found for the current os", toolRelease.Tool.Name)
@@ -187,3 +234,62 @@ func (pm *PackageManager) IsToolRequired(toolRelease *cores.ToolRelease) bool {
 	}
 	return false
 }
+
+// UnusedToolReleases returns the installed tool releases that are not required
+// by any of the installed platforms. The tools of the builtin package are used
+// directly by the CLI and are never returned. An error is returned if the
+// dependencies of an installed platform are unknown, because its index is
+// missing, since the tools it requires can't be determined.
+func (pm *PackageManager) UnusedToolReleases() ([]*cores.ToolRelease, error) {
+	for _, targetPackage := range pm.Packages {
+		for _, platform := range targetPackage.Platforms {
+			platformRelease := pm.GetInstalledPlatformRelease(platform)
+			if platformRelease == nil || platformRelease.Resource != nil || !pm.IsManagedPlatformRelease(platformRelease) {
+				continue
+			}
+			return nil, fmt.Errorf("the tools required by platform %s are unknown, its package index is missing", platformRelease)
+		}
+	}
+
+	res := []*cores.ToolRelease{}
+	for _, packager := range pm.Packages.Names() {
+		if packager == "builtin" {
+			continue
+		}
+		targetPackage := pm.Packages[packager]
+		toolNames := []string{}
+		for name := range targetPackage.Tools {
+			toolNames = append(toolNames, name)
+		}
+		sort.Strings(toolNames)
+		for _, name := range toolNames {
+			releases := []*cores.ToolRelease{}
+			for _, release := range targetPackage.Tools[name].Releases {
+				if release.IsInstalled() && pm.IsManagedToolRelease(release) && !pm.IsToolRequired(release) {
+					releases = append(releases, release)
+				}
+			}
+			sort.Slice(releases, func(i, j int) bool {
+				return releases[i].Version.LessThan(releases[j].Version)
+			})
+			res = append(res, releases...)
+		}
+	}
+	return res, nil
+}
+
+// checkTrusted applies the SecurityLevel policy to the installation of an
+// archive listed in a package index
+func (pm *PackageManager) checkTrusted(name string, trusted bool) error {
+	if trusted {
+		return nil
+	}
+	switch pm.SecurityLevel {
+	case security.LevelOff:
+		return nil
+	case security.LevelStrict:
+		return errors.Errorf("installing %s: the package index is not signed by a trusted key", name)
+	}
+	pm.Log.Warnf("Installing %s from a package index not signed by a trusted key", name)
+	return nil
+}
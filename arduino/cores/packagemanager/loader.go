@@ -26,20 +26,30 @@ import (
 	"github.com/arduino/arduino-cli/configuration"
 	"github.com/arduino/go-paths-helper"
 	properties "github.com/arduino/go-properties-orderedmap"
+	"github.com/spf13/viper"
 	semver "go.bug.st/relaxed-semver"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
-// LoadHardware read all plaforms from the configured paths
-func (pm *PackageManager) LoadHardware() []*status.Status {
-	statuses := []*status.Status{}
-	dirs := configuration.HardwareDirectories(configuration.Settings)
+// LoadHardware read all plaforms from the paths configured in settings
+func (pm *PackageManager) LoadHardware(settings *viper.Viper) []*status.Status {
+	statuses := pm.LoadBoardsOverlay(configuration.BoardsOverlayDir(settings))
+	dirs := configuration.HardwareDirectories(settings)
 	if errs := pm.LoadHardwareFromDirectories(dirs); len(errs) > 0 {
 		statuses = append(statuses, errs...)
 	}
+	for _, platform := range configuration.UserHardwareDirectories(settings) {
+		if platform.Architecture == "" {
+			continue
+		}
+		if err := pm.LoadPlatformFromDirectory(platform.Packager, platform.Architecture, platform.Path); err != nil {
+			statuses = append(statuses, err)
+		}
+	}
+	statuses = append(statuses, pm.checkBoardsOverlay()...)
 
-	dirs = configuration.BundleToolsDirectories(configuration.Settings)
+	dirs = configuration.BundleToolsDirectories(settings)
 	if errs := pm.LoadToolsFromBundleDirectories(dirs); len(errs) > 0 {
 		statuses = append(statuses, errs...)
 	}
@@ -179,17 +189,34 @@ func (pm *PackageManager) loadPlatforms(targetPackage *cores.Package, packageDir
 	return statuses
 }
 
+// LoadPlatformFromDirectory loads the platform in the given folder as the
+// PACKAGER:ARCHITECTURE platform, whatever the names of the folders are. It
+// is used for the platforms under development outside of a hardware folder.
+func (pm *PackageManager) LoadPlatformFromDirectory(packager, architecture string, platformPath *paths.Path) *status.Status {
+	pm.Log.Infof("Loading platform %s:%s from: %s", packager, architecture, platformPath)
+	if err := platformPath.ToAbs(); err != nil {
+		return status.Newf(codes.FailedPrecondition, "find abs path: %s", err)
+	}
+	if !platformPath.Join("boards.txt").Exist() {
+		return status.Newf(codes.NotFound, "boards.txt not found in %s", platformPath)
+	}
+	targetPackage := pm.Packages.GetOrCreatePackage(packager)
+	return pm.loadPlatformWithArchitecture(targetPackage, architecture, platformPath)
+}
+
 // loadPlatform loads a single platform and all its installed releases given a platformPath.
 // platformPath must be a directory.
 // Returns a gRPC Status error in case of failures.
 func (pm *PackageManager) loadPlatform(targetPackage *cores.Package, platformPath *paths.Path) *status.Status {
+	return pm.loadPlatformWithArchitecture(targetPackage, platformPath.Base(), platformPath)
+}
+
+func (pm *PackageManager) loadPlatformWithArchitecture(targetPackage *cores.Package, architecture string, platformPath *paths.Path) *status.Status {
 	// This is not a platform
 	if platformPath.IsNotDir() {
 		return status.Newf(codes.NotFound, "path is not a platform directory: %s", platformPath)
 	}
 
-	architecture := platformPath.Base()
-
 	// There are two possible platform directory structures:
 	// - ARCHITECTURE/boards.txt
 	// - ARCHITECTURE/VERSION/boards.txt
@@ -377,6 +404,11 @@ func (pm *PackageManager) loadBoards(platform *cores.PlatformRelease) error {
 		return err
 	}
 
+	platformID := platform.Platform.Package.Name + ":" + platform.Platform.Architecture
+	if overlay, ok := pm.boardsOverlay[platformID]; ok {
+		boardsProperties.Merge(overlay)
+	}
+
 	propertiesByBoard := boardsProperties.FirstLevelOf()
 
 	if menus, ok := propertiesByBoard["menu"]; ok {
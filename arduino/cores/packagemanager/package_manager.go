@@ -19,11 +19,11 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
-	"path"
 	"strings"
 
 	"github.com/arduino/arduino-cli/arduino/cores"
 	"github.com/arduino/arduino-cli/arduino/cores/packageindex"
+	"github.com/arduino/arduino-cli/arduino/security"
 	paths "github.com/arduino/go-paths-helper"
 	properties "github.com/arduino/go-properties-orderedmap"
 	"github.com/sirupsen/logrus"
@@ -43,6 +43,17 @@ type PackageManager struct {
 	DownloadDir            *paths.Path
 	TempDir                *paths.Path
 	CustomGlobalProperties *properties.Map
+	// SecurityLevel is the policy applied to the archives of the platforms
+	// and tools listed in package indexes without a trusted signature
+	SecurityLevel security.Level
+	// TrustedKeys are the public keys trusted to sign the package indexes,
+	// besides the Arduino ones
+	TrustedKeys paths.PathList
+	// Mirrors are the base URLs used to download the archives when the URL
+	// in the package index is not reachable
+	Mirrors []string
+
+	boardsOverlay map[string]*properties.Map
 }
 
 // NewPackageManager returns a new instance of the PackageManager
@@ -213,8 +224,8 @@ func (pm *PackageManager) ResolveFQBN(fqbn *cores.FQBN) (
 
 // LoadPackageIndex loads a package index by looking up the local cached file from the specified URL
 func (pm *PackageManager) LoadPackageIndex(URL *url.URL) error {
-	indexPath := pm.IndexDir.Join(path.Base(URL.Path))
-	index, err := packageindex.LoadIndex(indexPath)
+	indexPath := pm.IndexDir.Join(packageindex.IndexFileName(URL))
+	index, err := packageindex.LoadIndexWithTrustedKeys(indexPath, pm.TrustedKeys)
 	if err != nil {
 		return fmt.Errorf("loading json index file %s: %s", indexPath, err)
 	}
@@ -229,7 +240,7 @@ func (pm *PackageManager) LoadPackageIndex(URL *url.URL) error {
 
 // LoadPackageIndexFromFile load a package index from the specified file
 func (pm *PackageManager) LoadPackageIndexFromFile(indexPath *paths.Path) (*packageindex.Index, error) {
-	index, err := packageindex.LoadIndex(indexPath)
+	index, err := packageindex.LoadIndexWithTrustedKeys(indexPath, pm.TrustedKeys)
 	if err != nil {
 		return nil, fmt.Errorf("loading json index file %s: %s", indexPath, err)
 	}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/arduino/arduino-cli/arduino/cores"
 	"github.com/arduino/arduino-cli/arduino/cores/packagemanager"
+	"github.com/arduino/arduino-cli/arduino/security"
 	"github.com/arduino/arduino-cli/configuration"
 	"github.com/arduino/go-paths-helper"
 	"github.com/arduino/go-properties-orderedmap"
@@ -212,11 +213,11 @@ func TestBoardOptionsFunctions(t *testing.T) {
 
 func TestFindToolsRequiredForBoard(t *testing.T) {
 	os.Setenv("ARDUINO_DATA_DIR", dataDir1.String())
-	configuration.Settings = configuration.Init("")
+	settings := configuration.Init("")
 	pm := packagemanager.NewPackageManager(
 		dataDir1,
-		configuration.PackagesDir(configuration.Settings),
-		paths.New(configuration.Settings.GetString("directories.Downloads")),
+		configuration.PackagesDir(settings),
+		paths.New(settings.GetString("directories.Downloads")),
 		dataDir1,
 	)
 
@@ -231,7 +232,7 @@ func TestFindToolsRequiredForBoard(t *testing.T) {
 	// We ignore the errors returned since they might not be necessarily blocking
 	// but just warnings for the user, like in the case a board is not loaded
 	// because of malformed menus
-	pm.LoadHardware()
+	pm.LoadHardware(settings)
 	esp32, err := pm.FindBoardWithFQBN("esp32:esp32:esp32")
 	require.NoError(t, err)
 	esptool231 := pm.FindToolDependency(&cores.ToolDependency{
@@ -339,3 +340,83 @@ func TestPackageManagerClear(t *testing.T) {
 	// Verifies both PackageManagers are now equal
 	require.Equal(t, &packageManager, &emptyPackageManager)
 }
+
+func TestUnusedToolReleases(t *testing.T) {
+	// Absolute paths are required to recognize the managed tools
+	dataDir, err := dataDir1.Abs()
+	require.NoError(t, err)
+	os.Setenv("ARDUINO_DATA_DIR", dataDir.String())
+	settings := configuration.Init("")
+	pm := packagemanager.NewPackageManager(
+		dataDir,
+		configuration.PackagesDir(settings),
+		paths.New(settings.GetString("directories.Downloads")),
+		dataDir,
+	)
+	pm.LoadHardware(settings)
+
+	// Without the indexes the tools required by the platforms are unknown
+	_, err = pm.UnusedToolReleases()
+	require.Error(t, err)
+
+	loadIndex := func(addr string) {
+		res, err := url.Parse(addr)
+		require.NoError(t, err)
+		require.NoError(t, pm.LoadPackageIndex(res))
+	}
+	loadIndex("https://dl.espressif.com/dl/package_esp32_index.json")
+	loadIndex("http://arduino.esp8266.com/stable/package_esp8266com_index.json")
+	loadIndex("https://adafruit.github.io/arduino-board-index/package_adafruit_index.json")
+	pm.LoadHardware(settings)
+
+	// All the installed tools are required by a platform
+	unused, err := pm.UnusedToolReleases()
+	require.NoError(t, err)
+	require.Empty(t, unused)
+
+	// Add a release not required by any platform, a builtin tool must be kept
+	// even if no platform requires it
+	oldBossac := pm.Packages["arduino"].Tools["bossac"].GetOrCreateRelease(semver.ParseRelaxed("1.6.1"))
+	oldBossac.InstallDir = dataDir.Join("packages", "arduino", "tools", "bossac")
+	builtinTool := pm.Packages.GetOrCreatePackage("builtin").GetOrCreateTool("ctags").GetOrCreateRelease(semver.ParseRelaxed("5.8"))
+	builtinTool.InstallDir = dataDir.Join("packages", "arduino", "tools")
+
+	unused, err = pm.UnusedToolReleases()
+	require.NoError(t, err)
+	require.Equal(t, []*cores.ToolRelease{oldBossac}, unused)
+}
+
+func TestBoardsOverlay(t *testing.T) {
+	pm := packagemanager.NewPackageManager(customHardware, customHardware, customHardware, customHardware)
+	statuses := pm.LoadBoardsOverlay(paths.New("testdata", "boards_overlay"))
+	require.Len(t, statuses, 1)
+	require.Contains(t, statuses[0].Message(), "boards overlay conflict for test:avr: z.build.mcu is set in both")
+	pm.LoadHardwareFromDirectory(customHardware)
+
+	board, err := pm.FindBoardWithFQBN("test:avr:a")
+	require.NoError(t, err)
+	require.Equal(t, "Board A (overlay)", board.Name())
+
+	board, err = pm.FindBoardWithFQBN("test:avr:z")
+	require.NoError(t, err)
+	require.Equal(t, "Board Z", board.Name())
+	require.Equal(t, "atmega2560", board.Properties.Get("build.mcu"))
+	require.Equal(t, "16000000L", board.Properties.Get("build.f_cpu"))
+
+	// Boards of the other platforms are not affected
+	_, err = pm.FindBoardWithFQBN("arduino:avr:z")
+	require.Error(t, err)
+}
+
+func TestInstallUntrustedPlatformInStrictMode(t *testing.T) {
+	pm := packagemanager.NewPackageManager(nil, paths.New(t.Name()), nil, nil)
+	pm.SecurityLevel = security.LevelStrict
+	platform := pm.Packages.GetOrCreatePackage("test").GetOrCreatePlatform("avr")
+	release := platform.GetOrCreateRelease(semver.MustParse("1.0.0"))
+	release.IsTrusted = false
+
+	err := pm.InstallPlatform(release)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not signed by a trusted key")
+	require.NoDirExists(t, t.Name())
+}
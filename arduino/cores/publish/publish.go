@@ -0,0 +1,144 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package publish
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/arduino/resources"
+	paths "github.com/arduino/go-paths-helper"
+	properties "github.com/arduino/go-properties-orderedmap"
+	semver "go.bug.st/relaxed-semver"
+)
+
+// DefaultExclude are the files never added to the archive of a platform: the
+// local overrides of the platform and boards properties
+var DefaultExclude = []string{"platform.local.txt", "boards.local.txt"}
+
+// Options are the details of a platform release not found in the platform folder
+type Options struct {
+	Packager     string
+	Architecture string
+	// Category defaults to "Contributed"
+	Category string
+	// URL is the URL where the archive will be published, the name of the
+	// archive is added if it ends with a slash
+	URL          string
+	Dependencies cores.ToolDependencies
+	Exclude      []string
+}
+
+// Package creates the archive of the platform in the given folder in outDir,
+// and returns the platform release that describes it in a package index. The
+// name and the version of the platform are read from platform.txt, the
+// boards from boards.txt.
+func Package(platformPath, outDir *paths.Path, opts *Options) (*cores.PlatformRelease, error) {
+	platformProps, err := properties.LoadFromPath(platformPath.Join("platform.txt"))
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(platformProps.Get("name"))
+	if name == "" {
+		return nil, fmt.Errorf("the name of the platform is missing in platform.txt")
+	}
+	version, err := semver.Parse(strings.TrimSpace(platformProps.Get("version")))
+	if err != nil {
+		return nil, fmt.Errorf("invalid version in platform.txt: %s", err)
+	}
+	boards, err := boardsManifest(platformPath)
+	if err != nil {
+		return nil, err
+	}
+
+	folder := opts.Packager + "-" + opts.Architecture + "-" + version.String()
+	if err := outDir.MkdirAll(); err != nil {
+		return nil, err
+	}
+	archivePath := outDir.Join(folder + ".zip")
+	if abs, err := archivePath.Abs(); err == nil {
+		archivePath = abs
+	}
+	exclude := append(append([]string{}, DefaultExclude...), opts.Exclude...)
+	if _, err := resources.CreateZipArchive(platformPath, archivePath, folder, exclude); err != nil {
+		return nil, err
+	}
+	url := opts.URL
+	if url == "" || strings.HasSuffix(url, "/") {
+		url += archivePath.Base()
+	}
+	resource, err := resources.NewDownloadResource(archivePath, url)
+	if err != nil {
+		return nil, err
+	}
+
+	category := opts.Category
+	if category == "" {
+		category = "Contributed"
+	}
+	packages := cores.NewPackages()
+	platform := packages.GetOrCreatePackage(opts.Packager).GetOrCreatePlatform(opts.Architecture)
+	platform.Name = name
+	platform.Category = category
+	release := platform.GetOrCreateRelease(version)
+	release.Resource = resource
+	release.BoardsManifest = boards
+	release.Dependencies = opts.Dependencies
+	if release.Dependencies == nil {
+		release.Dependencies = cores.ToolDependencies{}
+	}
+	return release, nil
+}
+
+// boardsManifest returns the boards listed in the package index: the boards
+// defined in boards.txt that are not hidden
+func boardsManifest(platformPath *paths.Path) ([]*cores.BoardManifest, error) {
+	boardsFile := platformPath.Join("boards.txt")
+	if !boardsFile.Exist() {
+		return nil, fmt.Errorf("boards.txt not found in %s", platformPath)
+	}
+	boardsProps, err := properties.LoadFromPath(boardsFile)
+	if err != nil {
+		return nil, err
+	}
+	res := []*cores.BoardManifest{}
+	for _, id := range boardsProps.FirstLevelKeys() {
+		board := boardsProps.SubTree(id)
+		if id == "menu" || board.GetBoolean("hide") || board.Get("name") == "" {
+			continue
+		}
+		res = append(res, &cores.BoardManifest{Name: board.Get("name")})
+	}
+	return res, nil
+}
+
+// ParseToolDependency parses a tool dependency in the form PACKAGER:NAME@VERSION
+func ParseToolDependency(arg string) (*cores.ToolDependency, error) {
+	split := strings.SplitN(arg, "@", 2)
+	if len(split) != 2 || split[1] == "" {
+		return nil, fmt.Errorf("invalid tool dependency %s, expected PACKAGER:NAME@VERSION", arg)
+	}
+	tool := strings.SplitN(split[0], ":", 2)
+	if len(tool) != 2 || tool[0] == "" || tool[1] == "" {
+		return nil, fmt.Errorf("invalid tool dependency %s, expected PACKAGER:NAME@VERSION", arg)
+	}
+	return &cores.ToolDependency{
+		ToolPackager: tool[0],
+		ToolName:     tool[1],
+		ToolVersion:  semver.ParseRelaxed(split[1]),
+	}, nil
+}
@@ -0,0 +1,96 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package publish
+
+import (
+	"archive/zip"
+	"sort"
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	paths "github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackage(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "core_package")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+
+	platformPath := tmp.Join("avr")
+	for name, content := range map[string]string{
+		"platform.txt":            "name=My Boards\nversion=1.2.0\n",
+		"platform.local.txt":      "compiler.path=/opt/avr/bin/\n",
+		"boards.txt":              "menu.cpu=Processor\nuno.name=My Uno\nnano.name=My Nano\nold.name=Old\nold.hide=true\n",
+		"cores/arduino/Arduino.h": "",
+		"variants/uno/pins.h":     "",
+		".git/HEAD":               "",
+		"extras/test.sh":          "",
+	} {
+		file := platformPath.Join(name)
+		require.NoError(t, file.Parent().MkdirAll())
+		require.NoError(t, file.WriteFile([]byte(content)))
+	}
+
+	tool, err := ParseToolDependency("arduino:avr-gcc@7.3.0-atmel3.6.1-arduino7")
+	require.NoError(t, err)
+	release, err := Package(platformPath, tmp.Join("dist"), &Options{
+		Packager:     "mycompany",
+		Architecture: "avr",
+		URL:          "https://example.com/releases/",
+		Dependencies: cores.ToolDependencies{tool},
+		Exclude:      []string{"extras"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "mycompany:avr@1.2.0", release.String())
+	require.Equal(t, "My Boards", release.Platform.Name)
+	require.Equal(t, "Contributed", release.Platform.Category)
+	require.Equal(t, "mycompany-avr-1.2.0.zip", release.Resource.ArchiveFileName)
+	require.Equal(t, "https://example.com/releases/mycompany-avr-1.2.0.zip", release.Resource.URL)
+	require.Regexp(t, "^SHA-256:[0-9a-f]{64}$", release.Resource.Checksum)
+	require.Len(t, release.BoardsManifest, 2)
+	require.Equal(t, "My Uno", release.BoardsManifest[0].Name)
+	require.Equal(t, "My Nano", release.BoardsManifest[1].Name)
+
+	archive := tmp.Join("dist", "mycompany-avr-1.2.0.zip")
+	info, err := archive.Stat()
+	require.NoError(t, err)
+	require.Equal(t, info.Size(), release.Resource.Size)
+	zipReader, err := zip.OpenReader(archive.String())
+	require.NoError(t, err)
+	defer zipReader.Close()
+	files := []string{}
+	for _, f := range zipReader.File {
+		files = append(files, f.Name)
+	}
+	sort.Strings(files)
+	require.Equal(t, []string{
+		"mycompany-avr-1.2.0/boards.txt",
+		"mycompany-avr-1.2.0/cores/arduino/Arduino.h",
+		"mycompany-avr-1.2.0/platform.txt",
+		"mycompany-avr-1.2.0/variants/uno/pins.h",
+	}, files)
+}
+
+func TestParseToolDependency(t *testing.T) {
+	dep, err := ParseToolDependency("arduino:avrdude@6.3.0-arduino17")
+	require.NoError(t, err)
+	require.Equal(t, "arduino:avrdude@6.3.0-arduino17", dep.String())
+	for _, arg := range []string{"avrdude@6.3.0", "arduino:avrdude", "arduino:@1.0.0", "arduino:avrdude@"} {
+		_, err := ParseToolDependency(arg)
+		require.Error(t, err, arg)
+	}
+}
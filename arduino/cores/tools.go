@@ -38,6 +38,7 @@ type ToolRelease struct {
 	Flavors    []*Flavor              `json:"systems"`          // Maps OS to Flavor
 	Tool       *Tool                  `json:"-"`
 	InstallDir *paths.Path            `json:"-"`
+	IsTrusted  bool                   `json:"-"`
 }
 
 // Flavor represents a flavor of a Tool version.
@@ -0,0 +1,172 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+// Package espota implements the network upload protocol of the ArduinoOTA
+// library of the ESP8266 and ESP32 platforms, the one used by espota.py.
+package espota
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Command selects the partition written by the upload
+type Command int
+
+const (
+	// Flash uploads the sketch
+	Flash Command = 0
+	// Filesystem uploads the filesystem image
+	Filesystem Command = 100
+	auth       Command = 200
+)
+
+const (
+	chunkSize         = 1024
+	invitationTimeout = 10 * time.Second
+	transferTimeout   = 10 * time.Second
+	// the device checks the image before answering the last OK
+	finalTimeout = 60 * time.Second
+)
+
+// ProgressCB is called after each chunk of the image is sent
+type ProgressCB func(sent, total int)
+
+// Upload sends the image to the device listening at host:port. The device
+// is invited with an UDP message, authenticated with the password if it
+// requires one, and then connects back to receive the image over TCP.
+func Upload(host string, port int, password string, cmd Command, name string, image []byte, progress ProgressCB) error {
+	listener, err := net.Listen("tcp", ":0")
+	if err != nil {
+		return errors.Wrap(err, "listening for the device connection")
+	}
+	defer listener.Close()
+	localPort := listener.Addr().(*net.TCPAddr).Port
+
+	udp, err := net.Dial("udp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return errors.Wrap(err, "contacting the device")
+	}
+	defer udp.Close()
+
+	sum := md5.Sum(image)
+	imageMD5 := hex.EncodeToString(sum[:])
+	invitation := fmt.Sprintf("%d %d %d %s\n", cmd, localPort, len(image), imageMD5)
+	reply, err := exchange(udp, invitation)
+	if err != nil {
+		return fmt.Errorf("no response from the device: %s", err)
+	}
+	if strings.HasPrefix(reply, "AUTH") {
+		fields := strings.Fields(reply)
+		if len(fields) < 2 {
+			return fmt.Errorf("invalid authentication request: %s", reply)
+		}
+		if password == "" {
+			return errors.New("the device requires a password")
+		}
+		cnonce := md5Hex(name + strconv.Itoa(len(image)) + imageMD5 + host)
+		response := md5Hex(md5Hex(password) + ":" + fields[1] + ":" + cnonce)
+		if reply, err = exchange(udp, fmt.Sprintf("%d %s %s\n", auth, cnonce, response)); err != nil {
+			return fmt.Errorf("no response from the device: %s", err)
+		}
+		if !strings.HasPrefix(reply, "OK") {
+			return errors.New("authentication failed")
+		}
+	} else if !strings.HasPrefix(reply, "OK") {
+		return fmt.Errorf("the device refused the upload: %s", reply)
+	}
+
+	listener.(*net.TCPListener).SetDeadline(time.Now().Add(invitationTimeout))
+	conn, err := listener.Accept()
+	if err != nil {
+		return fmt.Errorf("the device didn't connect back: %s", err)
+	}
+	defer conn.Close()
+
+	response := make([]byte, 32)
+	lastResponse := ""
+	for sent := 0; sent < len(image); {
+		end := sent + chunkSize
+		if end > len(image) {
+			end = len(image)
+		}
+		conn.SetDeadline(time.Now().Add(transferTimeout))
+		if _, err := conn.Write(image[sent:end]); err != nil {
+			return errors.Wrap(err, "sending the image")
+		}
+		// the device acknowledges each chunk with the number of bytes received
+		n, err := conn.Read(response)
+		if err != nil {
+			return errors.Wrap(err, "sending the image")
+		}
+		lastResponse = string(response[:n])
+		sent = end
+		if progress != nil {
+			progress(sent, len(image))
+		}
+	}
+
+	conn.SetDeadline(time.Now().Add(finalTimeout))
+	// the result can be received along with the acknowledge of the last chunk
+	var res strings.Builder
+	res.WriteString(strings.TrimLeft(lastResponse, "0123456789"))
+	for {
+		if strings.Contains(res.String(), "OK") {
+			return nil
+		}
+		if strings.Contains(res.String(), "E") {
+			return fmt.Errorf("the device reported an error: %s", strings.TrimSpace(res.String()))
+		}
+		n, err := conn.Read(response)
+		res.Write(response[:n])
+		if n > 0 {
+			continue
+		}
+		if err == io.EOF {
+			// older versions of the library close the connection
+			// without the final OK
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "waiting for the result")
+		}
+	}
+}
+
+func exchange(conn net.Conn, msg string) (string, error) {
+	if _, err := conn.Write([]byte(msg)); err != nil {
+		return "", err
+	}
+	conn.SetReadDeadline(time.Now().Add(invitationTimeout))
+	buf := make([]byte, 128)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(buf[:n])), nil
+}
+
+func md5Hex(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
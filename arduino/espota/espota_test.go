@@ -0,0 +1,107 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package espota
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDevice emulates the ArduinoOTA library, it returns the UDP port it
+// listens on and a channel that receives the uploaded image
+func fakeDevice(t *testing.T, password string, finalResponse string) (int, <-chan []byte) {
+	udp, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	received := make(chan []byte, 1)
+	go func() {
+		defer udp.Close()
+		defer close(received)
+		buf := make([]byte, 256)
+		n, addr, err := udp.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(string(buf[:n]))
+		port, _ := strconv.Atoi(fields[1])
+		size, _ := strconv.Atoi(fields[2])
+		if password != "" {
+			udp.WriteTo([]byte("AUTH 1234"), addr)
+			n, addr, err = udp.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			auth := strings.Fields(string(buf[:n]))
+			if auth[2] != md5Hex(md5Hex(password)+":1234:"+auth[1]) {
+				udp.WriteTo([]byte("Authentication Failed"), addr)
+				return
+			}
+		}
+		udp.WriteTo([]byte("OK"), addr)
+
+		conn, err := net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		var image bytes.Buffer
+		chunk := make([]byte, 4096)
+		for image.Len() < size {
+			n, err := conn.Read(chunk)
+			if err != nil && err != io.EOF {
+				return
+			}
+			image.Write(chunk[:n])
+			conn.Write([]byte(strconv.Itoa(n)))
+		}
+		conn.Write([]byte(finalResponse))
+		received <- image.Bytes()
+	}()
+	return udp.LocalAddr().(*net.UDPAddr).Port, received
+}
+
+func TestUpload(t *testing.T) {
+	image := bytes.Repeat([]byte("0123456789"), 500)
+
+	port, received := fakeDevice(t, "", "OK")
+	calls := 0
+	err := Upload("127.0.0.1", port, "", Flash, "sketch.ino.bin", image, func(sent, total int) {
+		calls++
+		require.Equal(t, len(image), total)
+	})
+	require.NoError(t, err)
+	require.Equal(t, image, <-received)
+	require.Equal(t, 5, calls)
+
+	port, received = fakeDevice(t, "secret", "OK")
+	require.NoError(t, Upload("127.0.0.1", port, "secret", Flash, "sketch.ino.bin", image, nil))
+	require.Equal(t, image, <-received)
+
+	port, _ = fakeDevice(t, "secret", "OK")
+	require.EqualError(t, Upload("127.0.0.1", port, "wrong", Flash, "sketch.ino.bin", image, nil), "authentication failed")
+
+	port, _ = fakeDevice(t, "secret", "OK")
+	require.EqualError(t, Upload("127.0.0.1", port, "", Flash, "sketch.ino.bin", image, nil), "the device requires a password")
+
+	port, _ = fakeDevice(t, "", "ERROR: bad md5")
+	require.EqualError(t, Upload("127.0.0.1", port, "", Flash, "sketch.ino.bin", image, nil), "the device reported an error: ERROR: bad md5")
+}
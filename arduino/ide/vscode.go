@@ -0,0 +1,260 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package ide
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino/builder"
+	dbg "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/debug/v1"
+	paths "github.com/arduino/go-paths-helper"
+	"github.com/pkg/errors"
+)
+
+// CortexDebugConfiguration is a launch configuration of the cortex-debug
+// extension of VS Code
+type CortexDebugConfiguration struct {
+	Name             string   `json:"name"`
+	Type             string   `json:"type"`
+	Request          string   `json:"request"`
+	Cwd              string   `json:"cwd"`
+	Executable       string   `json:"executable"`
+	ServerType       string   `json:"servertype"`
+	ServerPath       string   `json:"serverpath,omitempty"`
+	ArmToolchainPath string   `json:"armToolchainPath,omitempty"`
+	ToolchainPrefix  string   `json:"toolchainPrefix,omitempty"`
+	ConfigFiles      []string `json:"configFiles,omitempty"`
+	SearchDir        []string `json:"searchDir,omitempty"`
+	TargetID         string   `json:"targetId,omitempty"`
+	SvdFile          string   `json:"svdFile,omitempty"`
+	RunToEntryPoint  string   `json:"runToEntryPoint,omitempty"`
+}
+
+// cortexDebugServerTypes maps the debug.server of the platforms to the
+// servertype of cortex-debug
+var cortexDebugServerTypes = map[string]string{
+	"openocd": "openocd",
+	"pyocd":   "pyocd",
+	"st-util": "stutil",
+}
+
+// NewCortexDebugConfiguration returns the cortex-debug launch configuration
+// equivalent to the given debug configuration
+func NewCortexDebugConfiguration(name string, info *dbg.GetDebugConfigResponse) (*CortexDebugConfiguration, error) {
+	serverType, ok := cortexDebugServerTypes[info.GetServer()]
+	if !ok {
+		return nil, fmt.Errorf("gdb server '%s' not supported by cortex-debug", info.GetServer())
+	}
+	res := &CortexDebugConfiguration{
+		Name:             name,
+		Type:             "cortex-debug",
+		Request:          "launch",
+		Cwd:              "${workspaceFolder}",
+		Executable:       info.GetExecutable(),
+		ServerType:       serverType,
+		ServerPath:       info.GetServerPath(),
+		ArmToolchainPath: strings.TrimSuffix(info.GetToolchainPath(), "/"),
+		ToolchainPrefix:  strings.TrimSuffix(info.GetToolchainPrefix(), "-"),
+		SvdFile:          info.GetSvdFile(),
+		RunToEntryPoint:  "setup",
+	}
+	config := info.GetServerConfiguration()
+	switch info.GetServer() {
+	case "openocd":
+		if script := config["script"]; script != "" {
+			res.ConfigFiles = []string{script}
+		}
+		if scriptsDir := config["scripts_dir"]; scriptsDir != "" {
+			res.SearchDir = []string{scriptsDir}
+		}
+	case "pyocd":
+		res.TargetID = config["target"]
+	}
+	return res, nil
+}
+
+// UpdateLaunchFile adds the configuration to a launch.json file, replacing
+// the configuration with the same name if already present. The other
+// configurations are preserved.
+func UpdateLaunchFile(file *paths.Path, config *CortexDebugConfiguration) error {
+	launch, err := loadJSON(file)
+	if err != nil {
+		return err
+	}
+	if _, ok := launch["version"]; !ok {
+		launch["version"] = "0.2.0"
+	}
+	replaceConfiguration(launch, config.Name, config)
+	return writeJSON(file, launch)
+}
+
+// CppConfiguration is a configuration of the c_cpp_properties.json file of
+// the C/C++ extension of VS Code
+type CppConfiguration struct {
+	Name             string   `json:"name"`
+	CompilerPath     string   `json:"compilerPath"`
+	CompilerArgs     []string `json:"compilerArgs,omitempty"`
+	IncludePath      []string `json:"includePath"`
+	Defines          []string `json:"defines"`
+	CStandard        string   `json:"cStandard,omitempty"`
+	CppStandard      string   `json:"cppStandard,omitempty"`
+	IntelliSenseMode string   `json:"intelliSenseMode,omitempty"`
+	CompileCommands  string   `json:"compileCommands"`
+}
+
+// NewCppConfiguration returns the IntelliSense configuration of the sketch,
+// derived from the compile command of the sketch in the compilation database
+// of the last build
+func NewCppConfiguration(name string, db *builder.CompilationDatabase) (*CppConfiguration, error) {
+	var cmd *builder.CompilationCommand
+	for i, c := range db.Contents {
+		if strings.HasSuffix(c.File, ".ino.cpp") {
+			cmd = &db.Contents[i]
+			break
+		}
+		if cmd == nil && strings.HasSuffix(c.File, ".cpp") {
+			cmd = &db.Contents[i]
+		}
+	}
+	if cmd == nil || len(cmd.Arguments) == 0 {
+		return nil, errors.New("no C++ compile command found in the compilation database")
+	}
+
+	res := &CppConfiguration{
+		Name:            name,
+		CompilerPath:    cmd.Arguments[0],
+		IncludePath:     []string{},
+		Defines:         []string{},
+		CompileCommands: db.File.String(),
+	}
+	args := cmd.Arguments[1:]
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "-I" || arg == "-D" || arg == "-o":
+			// flags with a separate value
+			if i+1 < len(args) {
+				i++
+				if arg == "-I" {
+					res.IncludePath = append(res.IncludePath, args[i])
+				} else if arg == "-D" {
+					res.Defines = append(res.Defines, args[i])
+				}
+			}
+		case strings.HasPrefix(arg, "-I"):
+			res.IncludePath = append(res.IncludePath, arg[2:])
+		case strings.HasPrefix(arg, "-D"):
+			res.Defines = append(res.Defines, arg[2:])
+		case strings.HasPrefix(arg, "-std="):
+			res.CppStandard = strings.TrimPrefix(arg, "-std=")
+		case strings.HasPrefix(arg, "-m"):
+			// target options are needed to find the built-in defines
+			res.CompilerArgs = append(res.CompilerArgs, arg)
+		}
+	}
+	res.IntelliSenseMode = intelliSenseMode(res.CompilerPath)
+	return res, nil
+}
+
+// intelliSenseMode returns the IntelliSense mode for gcc cross compilers,
+// that cpptools can't detect from the compiler path
+func intelliSenseMode(compilerPath string) string {
+	host := "linux"
+	switch runtime.GOOS {
+	case "windows":
+		host = "windows"
+	case "darwin":
+		host = "macos"
+	}
+	compiler := paths.New(compilerPath).Base()
+	switch {
+	case strings.HasPrefix(compiler, "arm-none-eabi-"), strings.HasPrefix(compiler, "arm-"):
+		return host + "-gcc-arm"
+	case strings.HasPrefix(compiler, "aarch64-"):
+		return host + "-gcc-arm64"
+	case strings.Contains(compiler, "gcc") || strings.Contains(compiler, "g++"):
+		return host + "-gcc-x86"
+	}
+	return ""
+}
+
+// UpdateCppPropertiesFile adds the configuration to a c_cpp_properties.json
+// file, replacing the configuration with the same name if already present
+func UpdateCppPropertiesFile(file *paths.Path, config *CppConfiguration) error {
+	props, err := loadJSON(file)
+	if err != nil {
+		return err
+	}
+	props["version"] = 4
+	replaceConfiguration(props, config.Name, config)
+	return writeJSON(file, props)
+}
+
+// ClangdConfig returns the content of a .clangd file that makes clangd use
+// the compilation database of the last build
+func ClangdConfig(db *builder.CompilationDatabase) []byte {
+	return []byte("CompileFlags:\n" +
+		"  CompilationDatabase: " + db.File.Parent().String() + "\n")
+}
+
+func loadJSON(file *paths.Path) (map[string]interface{}, error) {
+	res := map[string]interface{}{}
+	if !file.Exist() {
+		return res, nil
+	}
+	data, err := file.ReadFile()
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, fmt.Errorf("cannot update %s, it is not valid JSON (comments are not supported): %s", file, err)
+	}
+	return res, nil
+}
+
+// replaceConfiguration replaces the element of the "configurations" list
+// with the given name, or appends config if not found
+func replaceConfiguration(doc map[string]interface{}, name string, config interface{}) {
+	configurations := []interface{}{}
+	replaced := false
+	if list, ok := doc["configurations"].([]interface{}); ok {
+		for _, c := range list {
+			if m, ok := c.(map[string]interface{}); ok && m["name"] == name {
+				c = config
+				replaced = true
+			}
+			configurations = append(configurations, c)
+		}
+	}
+	if !replaced {
+		configurations = append(configurations, config)
+	}
+	doc["configurations"] = configurations
+}
+
+func writeJSON(file *paths.Path, content interface{}) error {
+	data, err := json.MarshalIndent(content, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := file.Parent().MkdirAll(); err != nil {
+		return err
+	}
+	return file.WriteFile(append(data, '\n'))
+}
@@ -0,0 +1,124 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package ide
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/builder"
+	dbg "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/debug/v1"
+	paths "github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCortexDebugConfiguration(t *testing.T) {
+	info := &dbg.GetDebugConfigResponse{
+		Executable:      "/build/Blink.ino.elf",
+		Toolchain:       "gcc",
+		ToolchainPath:   "/tools/arm-none-eabi-gcc/bin/",
+		ToolchainPrefix: "arm-none-eabi-",
+		Server:          "openocd",
+		ServerPath:      "/tools/openocd/bin/openocd",
+		ServerConfiguration: map[string]string{
+			"scripts_dir": "/tools/openocd/share/openocd/scripts/",
+			"script":      "/variants/mkr1000/openocd_scripts/arduino_zero.cfg",
+		},
+		SvdFile: "/svd/ATSAMD21G18A.svd",
+	}
+	config, err := NewCortexDebugConfiguration("Arduino: Blink", info)
+	require.NoError(t, err)
+	require.Equal(t, "openocd", config.ServerType)
+	require.Equal(t, "/tools/arm-none-eabi-gcc/bin", config.ArmToolchainPath)
+	require.Equal(t, "arm-none-eabi", config.ToolchainPrefix)
+	require.Equal(t, []string{"/variants/mkr1000/openocd_scripts/arduino_zero.cfg"}, config.ConfigFiles)
+	require.Equal(t, []string{"/tools/openocd/share/openocd/scripts/"}, config.SearchDir)
+	require.Equal(t, "/svd/ATSAMD21G18A.svd", config.SvdFile)
+
+	info.Server = "pyocd"
+	info.ServerConfiguration = map[string]string{"target": "atsamd21g18a"}
+	config, err = NewCortexDebugConfiguration("Arduino: Blink", info)
+	require.NoError(t, err)
+	require.Equal(t, "pyocd", config.ServerType)
+	require.Equal(t, "atsamd21g18a", config.TargetID)
+	require.Nil(t, config.ConfigFiles)
+
+	info.Server = "jlink"
+	_, err = NewCortexDebugConfiguration("Arduino: Blink", info)
+	require.Error(t, err)
+}
+
+func TestUpdateLaunchFile(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "vscode_test")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+	launchFile := tmp.Join(".vscode", "launch.json")
+	require.NoError(t, launchFile.Parent().MkdirAll())
+	require.NoError(t, launchFile.WriteFile([]byte(`{
+  "version": "0.2.0",
+  "configurations": [
+    {"name": "Python", "type": "python"},
+    {"name": "Arduino: Blink", "type": "cortex-debug", "executable": "old.elf"}
+  ]
+}`)))
+
+	config := &CortexDebugConfiguration{Name: "Arduino: Blink", Type: "cortex-debug", Executable: "new.elf"}
+	require.NoError(t, UpdateLaunchFile(launchFile, config))
+
+	data, err := launchFile.ReadFile()
+	require.NoError(t, err)
+	launch := struct {
+		Version        string
+		Configurations []map[string]interface{}
+	}{}
+	require.NoError(t, json.Unmarshal(data, &launch))
+	require.Equal(t, "0.2.0", launch.Version)
+	require.Len(t, launch.Configurations, 2)
+	require.Equal(t, "Python", launch.Configurations[0]["name"])
+	require.Equal(t, "new.elf", launch.Configurations[1]["executable"])
+
+	// files with comments can't be updated
+	require.NoError(t, launchFile.WriteFile([]byte("// comment\n{}")))
+	require.Error(t, UpdateLaunchFile(launchFile, config))
+}
+
+func TestCppConfiguration(t *testing.T) {
+	db := builder.NewCompilationDatabase(paths.New("/build", "compile_commands.json"))
+	db.Contents = []builder.CompilationCommand{
+		{
+			File:      "/build/core/wiring.c",
+			Arguments: []string{"/tools/bin/arm-none-eabi-gcc", "-c", "-DF_CPU=48000000L", "wiring.c"},
+		},
+		{
+			File: "/build/sketch/Blink.ino.cpp",
+			Arguments: []string{"/tools/bin/arm-none-eabi-g++", "-mcpu=cortex-m0plus", "-mthumb", "-c", "-g", "-Os",
+				"-std=gnu++11", "-DF_CPU=48000000L", "-D", "ARDUINO=10607", "-I/core", "-I", "/variant",
+				"/build/sketch/Blink.ino.cpp", "-o", "/build/sketch/Blink.ino.cpp.o"},
+		},
+	}
+	config, err := NewCppConfiguration("Arduino", db)
+	require.NoError(t, err)
+	require.Equal(t, "/tools/bin/arm-none-eabi-g++", config.CompilerPath)
+	require.Equal(t, []string{"-mcpu=cortex-m0plus", "-mthumb"}, config.CompilerArgs)
+	require.Equal(t, []string{"/core", "/variant"}, config.IncludePath)
+	require.Equal(t, []string{"F_CPU=48000000L", "ARDUINO=10607"}, config.Defines)
+	require.Equal(t, "gnu++11", config.CppStandard)
+	require.Contains(t, config.IntelliSenseMode, "-gcc-arm")
+	require.Equal(t, paths.New("/build", "compile_commands.json").String(), config.CompileCommands)
+
+	_, err = NewCppConfiguration("Arduino", builder.NewCompilationDatabase(paths.New("empty.json")))
+	require.Error(t, err)
+}
@@ -0,0 +1,110 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package keywords
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CtagsArgs are the arguments passed to ctags, followed by the header to parse
+var CtagsArgs = []string{"-u", "--language-force=c++", "-f", "-", "--c++-kinds=cdefgpstu", "--fields=aKSnsz"}
+
+// ctagsKinds maps the kinds reported by ctags to the token types
+var ctagsKinds = map[string]Kind{
+	"class":      KindDatatype,
+	"struct":     KindDatatype,
+	"union":      KindDatatype,
+	"enum":       KindDatatype,
+	"typedef":    KindDatatype,
+	"function":   KindFunction,
+	"prototype":  KindFunction,
+	"enumerator": KindConstant,
+	"macro":      KindConstant,
+}
+
+// ParseCtagsOutput extracts the public symbols from the output of ctags run
+// on the given header with CtagsArgs. Private and protected members,
+// constructors, destructors, operators and the include guard are skipped.
+func ParseCtagsOutput(header string, output string) []*Symbol {
+	symbols := []*Symbol{}
+	for _, row := range strings.Split(output, "\n") {
+		parts := strings.Split(strings.TrimRight(row, "\r"), "\t")
+		if len(parts) < 3 || strings.HasPrefix(parts[0], "!_TAG_") {
+			continue
+		}
+		symbol := &Symbol{Name: parts[0], Header: header}
+		code := ""
+		if start, end := strings.Index(row, "/^"), strings.Index(row, "$/;"); start != -1 && end > start {
+			code = row[start+2 : end]
+		}
+		access := ""
+		for _, field := range parts[2:] {
+			colon := strings.Index(field, ":")
+			if colon == -1 {
+				continue
+			}
+			name, value := field[:colon], strings.TrimSpace(field[colon+1:])
+			switch name {
+			case "kind":
+				symbol.Type = value
+			case "line":
+				symbol.Line, _ = strconv.Atoi(value)
+			case "signature":
+				symbol.Signature = value
+			case "access":
+				access = value
+			case "class", "struct", "union", "namespace", "enum":
+				symbol.Scope = value
+			}
+		}
+
+		kind, ok := ctagsKinds[symbol.Type]
+		if !ok || strings.HasPrefix(symbol.Name, "_") || strings.HasPrefix(symbol.Name, "operator") {
+			continue
+		}
+		if access == "private" || access == "protected" {
+			continue
+		}
+		if kind == KindFunction {
+			symbol.Type = "function"
+		}
+		if kind == KindFunction && symbol.Scope != "" {
+			// constructors and destructors
+			scopeName := symbol.Scope[strings.LastIndex(symbol.Scope, ":")+1:]
+			if symbol.Name == scopeName || strings.HasPrefix(symbol.Name, "~") {
+				continue
+			}
+			symbol.Type = "method"
+		}
+		if symbol.Type == "macro" {
+			definition := ""
+			if i := strings.Index(code, symbol.Name); i != -1 {
+				definition = code[i+len(symbol.Name):]
+			}
+			if strings.TrimSpace(definition) == "" && (strings.HasSuffix(symbol.Name, "_H") || strings.HasSuffix(symbol.Name, "_H_")) {
+				// include guard
+				continue
+			}
+			if strings.HasPrefix(definition, "(") {
+				kind = KindFunction
+			}
+		}
+		symbol.Kind = kind
+		symbols = append(symbols, symbol)
+	}
+	return symbols
+}
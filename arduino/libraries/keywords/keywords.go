@@ -0,0 +1,233 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package keywords
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino/libraries"
+	"github.com/arduino/arduino-cli/executils"
+	paths "github.com/arduino/go-paths-helper"
+)
+
+// Keyword is an entry of a keywords.txt file
+type Keyword struct {
+	Name string
+	Kind Kind
+	// Extra contains the optional reference link and RSYNTAXTEXTAREA token
+	// type columns, preserved as-is
+	Extra []string
+}
+
+// ParseLibrary returns the symbols declared in the public headers of the
+// library: the headers listed in the "includes" field of library.properties
+// or, if not defined, all the headers in the library source folder.
+func ParseLibrary(library *libraries.Library) ([]*Symbol, error) {
+	return parseHeaders(library, func(header string, headerPath *paths.Path) ([]*Symbol, error) {
+		source, err := headerPath.ReadFile()
+		if err != nil {
+			return nil, fmt.Errorf("reading header %s: %s", header, err)
+		}
+		return ParseHeader(header, string(source)), nil
+	})
+}
+
+// ParseLibraryWithCtags is like ParseLibrary but the headers are parsed with
+// the given ctags executable
+func ParseLibraryWithCtags(library *libraries.Library, ctags *paths.Path) ([]*Symbol, error) {
+	return parseHeaders(library, func(header string, headerPath *paths.Path) ([]*Symbol, error) {
+		args := append(append([]string{}, CtagsArgs...), headerPath.String())
+		cmd, err := executils.NewProcessFromPath(ctags, args...)
+		if err != nil {
+			return nil, err
+		}
+		var output bytes.Buffer
+		cmd.RedirectStdoutTo(&output)
+		if err := cmd.Run(); err != nil {
+			return nil, fmt.Errorf("running ctags on header %s: %s", header, err)
+		}
+		return ParseCtagsOutput(header, output.String()), nil
+	})
+}
+
+func parseHeaders(library *libraries.Library, parse func(header string, headerPath *paths.Path) ([]*Symbol, error)) ([]*Symbol, error) {
+	headers := library.DeclaredHeaders()
+	if len(headers) == 0 {
+		h, err := library.SourceHeaders()
+		if err != nil {
+			return nil, err
+		}
+		headers = h
+	}
+
+	symbols := []*Symbol{}
+	for _, header := range headers {
+		headerPath := library.SourceDir.Join(header)
+		if !headerPath.Exist() {
+			continue
+		}
+		headerSymbols, err := parse(header, headerPath)
+		if err != nil {
+			return nil, err
+		}
+		symbols = append(symbols, headerSymbols...)
+	}
+	return symbols, nil
+}
+
+// LoadKeywordsFile reads the keywords defined in a keywords.txt file
+func LoadKeywordsFile(file *paths.Path) ([]*Keyword, error) {
+	data, err := file.ReadFile()
+	if err != nil {
+		return nil, err
+	}
+	res := []*Keyword{}
+	for _, line := range strings.Split(string(data), "\n") {
+		if kw := parseKeywordLine(line); kw != nil {
+			res = append(res, kw)
+		}
+	}
+	return res, nil
+}
+
+// parseKeywordLine returns the keyword defined in a line of a keywords.txt
+// file, or nil for the empty and commented lines
+func parseKeywordLine(line string) *Keyword {
+	line = strings.TrimRight(line, "\r")
+	if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
+		return nil
+	}
+	fields := strings.Split(line, "\t")
+	name := strings.TrimSpace(fields[0])
+	if name == "" {
+		return nil
+	}
+	kw := &Keyword{Name: name}
+	if len(fields) > 1 {
+		kw.Kind = Kind(strings.TrimSpace(fields[1]))
+	}
+	if len(fields) > 2 {
+		kw.Extra = fields[2:]
+	}
+	return kw
+}
+
+// UpdateKeywordsFile adds to the content of a keywords.txt file the symbols
+// not already defined, grouped by kind at the end of the file, and returns
+// the number of keywords added. The existing lines, including the comments
+// and the lines that are not keywords, are kept unchanged. The keywords
+// commented out (e.g. "#begin<TAB>KEYWORD2") are considered defined, so they
+// are not added again.
+func UpdateKeywordsFile(data []byte, symbols []*Symbol) ([]byte, int) {
+	defined := []*Keyword{}
+	for _, line := range strings.Split(string(data), "\n") {
+		if kw := parseKeywordLine(line); kw != nil {
+			defined = append(defined, kw)
+		} else if kw := parseKeywordLine(strings.TrimLeft(line, "# ")); kw != nil && kw.Kind != "" {
+			defined = append(defined, kw)
+		}
+	}
+	added := MergeSymbols(defined, symbols)[len(defined):]
+	if len(added) == 0 {
+		return data, 0
+	}
+
+	var buf bytes.Buffer
+	buf.Write(data)
+	if len(data) > 0 && !bytes.HasSuffix(data, []byte("\n")) {
+		buf.WriteString("\n")
+	}
+	writeSections(&buf, added)
+	return buf.Bytes(), len(added)
+}
+
+// MergeSymbols adds the symbols not already present to the keywords list.
+// Existing keywords are preserved, so manual changes to a keywords.txt are
+// not lost when it is updated.
+func MergeSymbols(keywords []*Keyword, symbols []*Symbol) []*Keyword {
+	present := map[string]bool{}
+	for _, kw := range keywords {
+		present[kw.Name] = true
+	}
+	for _, symbol := range symbols {
+		if present[symbol.Name] {
+			continue
+		}
+		present[symbol.Name] = true
+		keywords = append(keywords, &Keyword{Name: symbol.Name, Kind: symbol.Kind})
+	}
+	return keywords
+}
+
+var sections = []struct {
+	kind  Kind
+	title string
+}{
+	{KindDatatype, "Datatypes (KEYWORD1)"},
+	{KindFunction, "Methods and Functions (KEYWORD2)"},
+	{KindStructure, "Structures (KEYWORD3)"},
+	{KindConstant, "Constants (LITERAL1)"},
+}
+
+// Format renders the keywords in the keywords.txt format, grouped by kind and
+// sorted by name.
+func Format(libraryName string, keywords []*Keyword) []byte {
+	separator := "#######################################\n"
+	var buf bytes.Buffer
+	buf.WriteString(separator)
+	buf.WriteString("# Syntax Coloring Map For " + libraryName + "\n")
+	buf.WriteString(separator)
+	writeSections(&buf, keywords)
+	return buf.Bytes()
+}
+
+// writeSections writes the keywords grouped by kind, each group in a section
+// with its title, sorted by name.
+func writeSections(buf *bytes.Buffer, keywords []*Keyword) {
+	separator := "#######################################\n"
+	byKind := map[Kind][]*Keyword{}
+	for _, kw := range keywords {
+		byKind[kw.Kind] = append(byKind[kw.Kind], kw)
+	}
+	writeSection := func(title string, list []*Keyword) {
+		if len(list) == 0 {
+			return
+		}
+		sort.SliceStable(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+		buf.WriteString("\n" + separator)
+		buf.WriteString("# " + title + "\n")
+		buf.WriteString(separator + "\n")
+		for _, kw := range list {
+			buf.WriteString(strings.Join(append([]string{kw.Name, string(kw.Kind)}, kw.Extra...), "\t") + "\n")
+		}
+	}
+	for _, section := range sections {
+		writeSection(section.title, byKind[section.kind])
+		delete(byKind, section.kind)
+	}
+	// Keep unknown token types at the end
+	others := []Kind{}
+	for kind := range byKind {
+		others = append(others, kind)
+	}
+	sort.Slice(others, func(i, j int) bool { return others[i] < others[j] })
+	for _, kind := range others {
+		writeSection(string(kind), byKind[kind])
+	}
+}
@@ -0,0 +1,172 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package keywords
+
+import (
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/libraries"
+	paths "github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLibrary(t *testing.T) {
+	library, err := libraries.Load(paths.New("testdata", "MyLib"), libraries.User)
+	require.NoError(t, err)
+	symbols, err := ParseLibrary(library)
+	require.NoError(t, err)
+
+	found := map[string]*Symbol{}
+	for _, s := range symbols {
+		found[s.Name] = s
+	}
+	expected := map[string]Kind{
+		"MYLIB_VERSION": KindConstant,
+		"MYLIB_MAX":     KindFunction,
+		"MyLibMode":     KindDatatype,
+		"MODE_FAST":     KindConstant,
+		"MODE_SLOW":     KindConstant,
+		"MyLibCallback": KindDatatype,
+		"MyLibConfig":   KindDatatype,
+		"mylib_init":    KindFunction,
+		"helper":        KindFunction,
+		"MyLib":         KindDatatype,
+		"begin":         KindFunction,
+		"read":          KindFunction,
+		"write":         KindFunction,
+	}
+	for name, kind := range expected {
+		require.Contains(t, found, name)
+		require.Equal(t, kind, found[name].Kind, name)
+	}
+	require.Len(t, found, len(expected))
+
+	require.Equal(t, "MyLib", found["begin"].Scope)
+	require.Equal(t, "method", found["begin"].Type)
+	require.Equal(t, "(unsigned long speed=9600)", found["begin"].Signature)
+	require.Equal(t, "MyLib.h", found["begin"].Header)
+	require.Equal(t, 33, found["begin"].Line)
+	require.Equal(t, "mylib", found["helper"].Scope)
+	require.Equal(t, "MyLibMode", found["MODE_SLOW"].Scope)
+}
+
+func TestKeywordsFileUpdate(t *testing.T) {
+	existing, err := LoadKeywordsFile(paths.New("testdata", "MyLib", "keywords.txt"))
+	require.NoError(t, err)
+	require.Len(t, existing, 2)
+
+	merged := MergeSymbols(existing, []*Symbol{
+		{Name: "MyLib", Kind: KindDatatype},
+		{Name: "begin", Kind: KindFunction},
+		{Name: "MODE_FAST", Kind: KindConstant},
+	})
+	require.Equal(t, ""+
+		"#######################################\n"+
+		"# Syntax Coloring Map For MyLib\n"+
+		"#######################################\n"+
+		"\n"+
+		"#######################################\n"+
+		"# Datatypes (KEYWORD1)\n"+
+		"#######################################\n"+
+		"\n"+
+		"MyLib\tKEYWORD1\tMyLib\n"+
+		"\n"+
+		"#######################################\n"+
+		"# Methods and Functions (KEYWORD2)\n"+
+		"#######################################\n"+
+		"\n"+
+		"begin\tKEYWORD2\n"+
+		"custom\tKEYWORD2\n"+
+		"\n"+
+		"#######################################\n"+
+		"# Constants (LITERAL1)\n"+
+		"#######################################\n"+
+		"\n"+
+		"MODE_FAST\tLITERAL1\n",
+		string(Format("MyLib", merged)))
+}
+
+func TestUpdateKeywordsFile(t *testing.T) {
+	existing := "" +
+		"# Manually written keywords\n" +
+		"MyLib\tKEYWORD1\tMyLib\n" +
+		"custom\tKEYWORD2\n" +
+		"not a keyword\n" +
+		"#end\tKEYWORD2\n"
+
+	updated, added := UpdateKeywordsFile([]byte(existing), []*Symbol{
+		{Name: "MyLib", Kind: KindDatatype},
+		{Name: "begin", Kind: KindFunction},
+		{Name: "end", Kind: KindFunction},
+		{Name: "MODE_FAST", Kind: KindConstant},
+	})
+	require.Equal(t, 2, added)
+	require.Equal(t, existing+
+		"\n"+
+		"#######################################\n"+
+		"# Methods and Functions (KEYWORD2)\n"+
+		"#######################################\n"+
+		"\n"+
+		"begin\tKEYWORD2\n"+
+		"\n"+
+		"#######################################\n"+
+		"# Constants (LITERAL1)\n"+
+		"#######################################\n"+
+		"\n"+
+		"MODE_FAST\tLITERAL1\n",
+		string(updated))
+
+	unchanged, added := UpdateKeywordsFile(updated, []*Symbol{{Name: "begin", Kind: KindFunction}})
+	require.Equal(t, 0, added)
+	require.Equal(t, updated, unchanged)
+}
+
+func TestParseCtagsOutput(t *testing.T) {
+	output := "" +
+		"MYLIB_H\tMyLib.h\t/^#define MYLIB_H$/;\"\tkind:macro\tline:2\n" +
+		"MYLIB_VERSION\tMyLib.h\t/^#define MYLIB_VERSION \"1.0.0\"$/;\"\tkind:macro\tline:4\n" +
+		"MYLIB_MAX\tMyLib.h\t/^#define MYLIB_MAX(a, b) ((a) > (b) ? (a) : (b))$/;\"\tkind:macro\tline:5\n" +
+		"MyLibMode\tMyLib.h\t/^enum MyLibMode {$/;\"\tkind:enum\tline:7\n" +
+		"MODE_FAST\tMyLib.h\t/^  MODE_FAST,$/;\"\tkind:enumerator\tline:8\tenum:MyLibMode\n" +
+		"MyLib\tMyLib.h\t/^class MyLib {$/;\"\tkind:class\tline:11\n" +
+		"MyLib\tMyLib.h\t/^  MyLib(int pin);$/;\"\tkind:prototype\tline:13\tclass:MyLib\taccess:public\tsignature:(int pin)\n" +
+		"~MyLib\tMyLib.h\t/^  ~MyLib();$/;\"\tkind:prototype\tline:14\tclass:MyLib\taccess:public\tsignature:()\n" +
+		"begin\tMyLib.h\t/^  void begin();$/;\"\tkind:prototype\tline:15\tclass:MyLib\taccess:public\tsignature:()\n" +
+		"read\tMyLib.h\t/^  int read() { return _value; }$/;\"\tkind:function\tline:16\tclass:MyLib\taccess:public\tsignature:()\n" +
+		"operator ==\tMyLib.h\t/^  bool operator==(const MyLib &o);$/;\"\tkind:prototype\tline:17\tclass:MyLib\taccess:public\tsignature:(const MyLib &o)\n" +
+		"update\tMyLib.h\t/^  void update();$/;\"\tkind:prototype\tline:19\tclass:MyLib\taccess:private\tsignature:()\n" +
+		"_value\tMyLib.h\t/^  int _value;$/;\"\tkind:member\tline:20\tclass:MyLib\taccess:private\n" +
+		"mylib_init\tMyLib.h\t/^void mylib_init(void);$/;\"\tkind:prototype\tline:23\tsignature:(void)\n"
+
+	symbols := ParseCtagsOutput("MyLib.h", output)
+	res := []string{}
+	for _, s := range symbols {
+		require.Equal(t, "MyLib.h", s.Header)
+		res = append(res, s.Name+" "+string(s.Kind)+" "+s.Type)
+	}
+	require.Equal(t, []string{
+		"MYLIB_VERSION LITERAL1 macro",
+		"MYLIB_MAX KEYWORD2 macro",
+		"MyLibMode KEYWORD1 enum",
+		"MODE_FAST LITERAL1 enumerator",
+		"MyLib KEYWORD1 class",
+		"begin KEYWORD2 method",
+		"read KEYWORD2 method",
+		"mylib_init KEYWORD2 function",
+	}, res)
+	require.Equal(t, "MyLib", symbols[5].Scope)
+	require.Equal(t, 15, symbols[5].Line)
+}
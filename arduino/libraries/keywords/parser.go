@@ -0,0 +1,450 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package keywords
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Symbol is a public symbol declared in a library header
+type Symbol struct {
+	Name   string `json:"name"`
+	Kind   Kind   `json:"kind"`
+	Type   string `json:"type"`
+	Scope  string `json:"scope,omitempty"`
+	Header string `json:"header"`
+	Line   int    `json:"line"`
+	// Signature is the parameter list of functions and methods
+	Signature string `json:"signature,omitempty"`
+}
+
+// Kind is the keywords.txt token type of a Symbol
+type Kind string
+
+const (
+	// KindDatatype is used for classes, structs, enums and typedefs
+	KindDatatype Kind = "KEYWORD1"
+	// KindFunction is used for methods and functions
+	KindFunction Kind = "KEYWORD2"
+	// KindStructure is used for structures (not produced by the parser)
+	KindStructure Kind = "KEYWORD3"
+	// KindConstant is used for macros and enum values
+	KindConstant Kind = "LITERAL1"
+)
+
+var defineRegexp = regexp.MustCompile(`^#\s*define\s+([A-Za-z_][A-Za-z0-9_]*)(\(?)`)
+var ifndefRegexp = regexp.MustCompile(`^#\s*ifndef\s+([A-Za-z_][A-Za-z0-9_]*)`)
+
+// cppKeywords are identifiers that may be followed by an open parenthesis
+// without being a function declaration
+var cppKeywords = map[string]bool{
+	"if": true, "while": true, "for": true, "switch": true, "return": true,
+	"sizeof": true, "alignof": true, "decltype": true, "static_assert": true,
+	"operator": true, "defined": true, "throw": true, "noexcept": true,
+	"__attribute__": true, "alignas": true, "catch": true,
+}
+
+type token struct {
+	text string
+	line int
+}
+
+type scope struct {
+	// kind is one of "namespace", "class", "enum" or "block"
+	kind   string
+	name   string
+	public bool
+}
+
+// ParseHeader extracts the public symbols declared in the given C/C++ header
+// source. The parser is not a full C++ parser, it recognizes the most common
+// declarations found in Arduino libraries: classes, structs, enums, typedefs,
+// functions, public methods and macros.
+func ParseHeader(header string, source string) []*Symbol {
+	source = stripComments(source)
+	symbols := []*Symbol{}
+	addSymbol := func(s *Symbol) {
+		s.Header = header
+		symbols = append(symbols, s)
+	}
+
+	// Preprocessor directives are handled line by line, the remaining code
+	// is passed to the tokenizer.
+	code := []string{}
+	lines := strings.Split(source, "\n")
+	guard := ""
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(line, "#") {
+			code = append(code, lines[i])
+			continue
+		}
+		directive := line
+		start := i
+		for strings.HasSuffix(lines[i], "\\") && i+1 < len(lines) {
+			i++
+			directive += lines[i]
+		}
+		// keep line numbers in sync
+		for j := start; j <= i; j++ {
+			code = append(code, "")
+		}
+
+		if m := ifndefRegexp.FindStringSubmatch(directive); m != nil && guard == "" {
+			guard = m[1]
+			continue
+		}
+		if m := defineRegexp.FindStringSubmatch(directive); m != nil {
+			if m[1] == guard || strings.HasPrefix(m[1], "_") {
+				continue
+			}
+			if m[2] == "(" {
+				addSymbol(&Symbol{Name: m[1], Kind: KindFunction, Type: "macro", Line: start + 1})
+			} else {
+				addSymbol(&Symbol{Name: m[1], Kind: KindConstant, Type: "macro", Line: start + 1})
+			}
+		}
+	}
+
+	tokens := tokenize(code)
+	stack := []*scope{{kind: "namespace", public: true}}
+	current := func() *scope { return stack[len(stack)-1] }
+	scopeName := func() string {
+		names := []string{}
+		for _, s := range stack {
+			if s.name != "" {
+				names = append(names, s.name)
+			}
+		}
+		return strings.Join(names, "::")
+	}
+	visible := func() bool {
+		for _, s := range stack {
+			if s.kind == "block" || !s.public {
+				return false
+			}
+		}
+		return true
+	}
+
+	// pending keeps the kind of the scope opened by the next '{'
+	var pending *scope
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		cur := current()
+		switch {
+		case tok.text == "{":
+			if pending == nil {
+				pending = &scope{kind: "block"}
+				if i > 0 && tokens[i-1].text == "extern" {
+					// extern "C" { ... }
+					pending = &scope{kind: "namespace", public: true}
+				}
+			}
+			stack = append(stack, pending)
+			pending = nil
+		case tok.text == "}":
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+		case tok.text == ";":
+			pending = nil
+		case cur.kind == "block":
+			continue
+		case tok.text == "namespace":
+			if i+1 < len(tokens) && isIdentifier(tokens[i+1].text) {
+				pending = &scope{kind: "namespace", name: tokens[i+1].text, public: true}
+				i++
+			} else {
+				pending = &scope{kind: "namespace", public: true}
+			}
+		case cur.kind == "class" && (tok.text == "public" || tok.text == "private" || tok.text == "protected"):
+			if i+1 < len(tokens) && tokens[i+1].text == ":" {
+				cur.public = tok.text == "public"
+				i++
+			}
+		case tok.text == "class" || tok.text == "struct" || tok.text == "union":
+			if i > 0 && tokens[i-1].text == "enum" {
+				continue
+			}
+			name, end := declaredName(tokens, i+1)
+			if end < len(tokens) && (tokens[end].text == "{" || tokens[end].text == ":") {
+				if name != "" && visible() {
+					addSymbol(&Symbol{Name: name, Kind: KindDatatype, Type: tok.text, Scope: scopeName(), Line: tok.line})
+				}
+				pending = &scope{kind: "class", name: name, public: tok.text != "class"}
+				i = end - 1
+			}
+		case tok.text == "enum":
+			j := i + 1
+			if j < len(tokens) && (tokens[j].text == "class" || tokens[j].text == "struct") {
+				j++
+			}
+			name, end := declaredName(tokens, j)
+			if end < len(tokens) && (tokens[end].text == "{" || tokens[end].text == ":") {
+				if name != "" && visible() {
+					addSymbol(&Symbol{Name: name, Kind: KindDatatype, Type: "enum", Scope: scopeName(), Line: tok.line})
+				}
+				for end < len(tokens) && tokens[end].text != "{" {
+					end++
+				}
+				// enum values
+				expectValue := true
+				depth := 0
+				for end++; end < len(tokens) && tokens[end].text != "}"; end++ {
+					t := tokens[end].text
+					switch {
+					case t == "(":
+						depth++
+					case t == ")":
+						depth--
+					case t == "," && depth == 0:
+						expectValue = true
+					case expectValue && isIdentifier(t):
+						if visible() {
+							addSymbol(&Symbol{Name: t, Kind: KindConstant, Type: "enumerator", Scope: joinScope(scopeName(), name), Line: tokens[end].line})
+						}
+						expectValue = false
+					}
+				}
+				i = end
+			}
+		case tok.text == "typedef":
+			// the typedef name is the last identifier before the ';', or the
+			// identifier inside the parenthesis for function pointers
+			depth := 0
+			name := ""
+			functionPointer := false
+			j := i + 1
+			for ; j < len(tokens) && !(tokens[j].text == ";" && depth == 0); j++ {
+				t := tokens[j].text
+				switch {
+				case t == "{":
+					depth++
+				case t == "}":
+					depth--
+				case depth != 0 || functionPointer:
+				case t == "(" && j+2 < len(tokens) && tokens[j+1].text == "*" && isIdentifier(tokens[j+2].text):
+					name = tokens[j+2].text
+					functionPointer = true
+				case isIdentifier(t):
+					name = t
+				}
+			}
+			if name != "" && visible() {
+				addSymbol(&Symbol{Name: name, Kind: KindDatatype, Type: "typedef", Scope: scopeName(), Line: tok.line})
+			}
+			i = j
+		case isIdentifier(tok.text) && i+1 < len(tokens) && tokens[i+1].text == "(":
+			if cppKeywords[tok.text] || (i > 0 && (tokens[i-1].text == "~" || tokens[i-1].text == "operator" || tokens[i-1].text == "." || tokens[i-1].text == "->" || tokens[i-1].text == "=")) {
+				continue
+			}
+			// skip constructors
+			if cur.kind == "class" && tok.text == cur.name {
+				i = skipParens(tokens, i+1)
+				continue
+			}
+			end := skipParens(tokens, i+1)
+			signature := joinTokens(tokens[i+1 : end+1])
+			if visible() {
+				kind := "function"
+				if cur.kind == "class" {
+					kind = "method"
+				}
+				addSymbol(&Symbol{Name: tok.text, Kind: KindFunction, Type: kind, Scope: scopeName(), Line: tok.line, Signature: signature})
+			}
+			i = end
+		}
+	}
+	return symbols
+}
+
+func joinScope(scope, name string) string {
+	if scope == "" {
+		return name
+	}
+	if name == "" {
+		return scope
+	}
+	return scope + "::" + name
+}
+
+// declaredName returns the name that follows a class/struct/enum keyword and
+// the index of the first token after the name (attributes and "final" are skipped)
+func declaredName(tokens []token, i int) (string, int) {
+	name := ""
+	for ; i < len(tokens); i++ {
+		t := tokens[i].text
+		if t == "__attribute__" || t == "alignas" {
+			i = skipParens(tokens, i+1)
+			continue
+		}
+		if t == "final" {
+			continue
+		}
+		if !isIdentifier(t) && t != "::" {
+			break
+		}
+		if t != "::" {
+			name = t
+		}
+	}
+	return name, i
+}
+
+// skipParens returns the index of the parenthesis closing the one at index i
+func skipParens(tokens []token, i int) int {
+	depth := 0
+	for ; i < len(tokens); i++ {
+		switch tokens[i].text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(tokens) - 1
+}
+
+func joinTokens(tokens []token) string {
+	res := ""
+	for i, t := range tokens {
+		if i > 0 {
+			prev := tokens[i-1].text
+			if isWordToken(prev) && isWordToken(t.text) || prev == "," {
+				res += " "
+			}
+		}
+		res += t.text
+	}
+	return res
+}
+
+func isWordToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	c := s[0]
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	c := s[0]
+	if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z') {
+		return false
+	}
+	switch s {
+	case "const", "static", "virtual", "inline", "extern", "volatile", "unsigned", "signed", "template", "typename":
+		return false
+	}
+	return true
+}
+
+// tokenize splits the code in identifiers, numbers and punctuation, string
+// and char literals are dropped
+func tokenize(lines []string) []token {
+	tokens := []token{}
+	for n, line := range lines {
+		for i := 0; i < len(line); {
+			c := line[i]
+			switch {
+			case c == ' ' || c == '\t' || c == '\r':
+				i++
+			case isWordToken(string(c)):
+				j := i
+				for j < len(line) && isWordToken(string(line[j])) {
+					j++
+				}
+				tokens = append(tokens, token{text: line[i:j], line: n + 1})
+				i = j
+			case c == '"' || c == '\'':
+				j := i + 1
+				for j < len(line) && line[j] != c {
+					if line[j] == '\\' {
+						j++
+					}
+					j++
+				}
+				i = j + 1
+			case c == ':' && i+1 < len(line) && line[i+1] == ':':
+				tokens = append(tokens, token{text: "::", line: n + 1})
+				i += 2
+			case c == '-' && i+1 < len(line) && line[i+1] == '>':
+				tokens = append(tokens, token{text: "->", line: n + 1})
+				i += 2
+			default:
+				tokens = append(tokens, token{text: string(c), line: n + 1})
+				i++
+			}
+		}
+	}
+	return tokens
+}
+
+// stripComments replaces comments with spaces, keeping the newlines so that
+// line numbers are preserved
+func stripComments(source string) string {
+	var res strings.Builder
+	inString := byte(0)
+	for i := 0; i < len(source); i++ {
+		c := source[i]
+		if inString != 0 {
+			res.WriteByte(c)
+			if c == '\\' && i+1 < len(source) {
+				i++
+				res.WriteByte(source[i])
+			} else if c == inString || c == '\n' {
+				inString = 0
+			}
+			continue
+		}
+		if c == '"' || c == '\'' {
+			inString = c
+			res.WriteByte(c)
+			continue
+		}
+		if c == '/' && i+1 < len(source) && source[i+1] == '/' {
+			for i < len(source) && source[i] != '\n' {
+				i++
+			}
+			if i < len(source) {
+				res.WriteByte('\n')
+			}
+			continue
+		}
+		if c == '/' && i+1 < len(source) && source[i+1] == '*' {
+			i += 2
+			for i < len(source) && !(source[i] == '*' && i+1 < len(source) && source[i+1] == '/') {
+				if source[i] == '\n' {
+					res.WriteByte('\n')
+				}
+				i++
+			}
+			i++
+			res.WriteByte(' ')
+			continue
+		}
+		res.WriteByte(c)
+	}
+	return res.String()
+}
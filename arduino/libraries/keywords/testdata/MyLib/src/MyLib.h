@@ -0,0 +1,44 @@
+#ifndef MYLIB_H
+#define MYLIB_H
+
+#include <Arduino.h>
+
+#define MYLIB_VERSION 100
+#define MYLIB_MAX(a, b) ((a) > (b) ? (a) : (b))
+
+/* Modes of operation */
+enum MyLibMode {
+  MODE_FAST,
+  MODE_SLOW = 2,
+};
+
+typedef void (*MyLibCallback)(int value);
+
+typedef struct {
+  int a;
+} MyLibConfig;
+
+extern "C" {
+void mylib_init(const char *name);
+}
+
+namespace mylib {
+int helper(int x, int y);
+}
+
+class MyLib : public Print {
+public:
+  MyLib(int pin);
+  ~MyLib();
+  void begin(unsigned long speed = 9600);
+  int read() { return _read(); }
+  size_t write(uint8_t c) override;
+  operator bool() const;
+  using Print::write;
+
+private:
+  int _read();
+  int _pin;
+};
+
+#endif
@@ -0,0 +1,68 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package librariesindex
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/arduino/arduino-cli/arduino/resources"
+)
+
+// MarshalMirrorIndex returns a library index, in JSON format, listing only
+// the given releases. The URLs of the archives are replaced with the ones
+// returned by archiveURL. It's used to create the index of a mirror.
+func MarshalMirrorIndex(releases []*Release, archiveURL func(*resources.DownloadResource) string) ([]byte, error) {
+	index := indexJSON{Libraries: []indexRelease{}}
+	for _, release := range releases {
+		dependencies := []*indexDependency{}
+		for _, dep := range release.Dependencies {
+			indexDep := &indexDependency{Name: dep.GetName()}
+			if constraint := dep.GetConstraint(); constraint != nil {
+				indexDep.Version = constraint.String()
+			}
+			dependencies = append(dependencies, indexDep)
+		}
+		index.Libraries = append(index.Libraries, indexRelease{
+			Name:             release.Library.Name,
+			Version:          release.Version,
+			Author:           release.Author,
+			Maintainer:       release.Maintainer,
+			Sentence:         release.Sentence,
+			Paragraph:        release.Paragraph,
+			Website:          release.Website,
+			Category:         release.Category,
+			Architectures:    release.Architectures,
+			Types:            release.Types,
+			URL:              archiveURL(release.Resource),
+			ArchiveFileName:  release.Resource.ArchiveFileName,
+			Size:             release.Resource.Size,
+			Checksum:         release.Resource.Checksum,
+			Dependencies:     dependencies,
+			License:          release.License,
+			ProvidesIncludes: release.ProvidesIncludes,
+		})
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(index); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -41,6 +41,9 @@ type LibrariesManager struct {
 	IndexFile          *paths.Path
 	IndexFileSignature *paths.Path
 	DownloadsDir       *paths.Path
+	// Mirrors are the base URLs used to download the libraries when the
+	// URL in the index is not reachable
+	Mirrors []string
 }
 
 // LibrariesDir is a directory containing libraries
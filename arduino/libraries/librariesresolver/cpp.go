@@ -69,14 +69,40 @@ func (resolver *Cpp) AlternativesFor(header string) libraries.List {
 	return resolver.headers[header]
 }
 
+// Candidate is a library that provides a header, with the priority computed
+// by the resolver
+type Candidate struct {
+	Library  *libraries.Library
+	Priority int
+}
+
+// Resolution describes how a header has been resolved: all the libraries
+// that provide it, the selected one and the reason of the choice
+type Resolution struct {
+	Header       string
+	Architecture string
+	Candidates   []*Candidate
+	Selected     *libraries.Library
+	Reason       string
+}
+
 // ResolveFor finds the most suitable library for the specified combination of
 // header and architecture. If no libraries provides the requested header, nil is returned
 func (resolver *Cpp) ResolveFor(header, architecture string) *libraries.Library {
+	return resolver.Resolve(header, architecture).Selected
+}
+
+// Resolve finds the most suitable library for the specified combination of
+// header and architecture and returns the details of the resolution. If no
+// libraries provides the requested header the Selected field is nil.
+func (resolver *Cpp) Resolve(header, architecture string) *Resolution {
 	logrus.Infof("Resolving include %s for arch %s", header, architecture)
+	res := &Resolution{Header: header, Architecture: architecture, Candidates: []*Candidate{}}
 	var found libraries.List
 	var foundPriority int
 	for _, lib := range resolver.headers[header] {
 		libPriority := computePriority(lib, header, architecture)
+		res.Candidates = append(res.Candidates, &Candidate{Library: lib, Priority: libPriority})
 		msg := "  discarded"
 		if found == nil || foundPriority < libPriority {
 			found = libraries.List{}
@@ -93,22 +119,34 @@ func (resolver *Cpp) ResolveFor(header, architecture string) *libraries.Library
 			Infof(msg)
 	}
 	if found == nil {
-		return nil
+		res.Reason = "no library provides the header"
+		return res
+	}
+	if len(res.Candidates) == 1 {
+		res.Selected = found[0]
+		res.Reason = "only candidate"
+		return res
 	}
 	if len(found) == 1 {
-		return found[0]
+		res.Selected = found[0]
+		res.Reason = "highest priority"
+		return res
 	}
 
 	// If more than one library qualifies use the "closestmatch" algorithm to
 	// find the best matching one (instead of choosing it randomly)
 	if best := findLibraryWithNameBestDistance(header, found); best != nil {
 		logrus.WithField("lib", best.Name).Info("  library with the best matching name")
-		return best
+		res.Selected = best
+		res.Reason = "best matching name among libraries with the same priority"
+		return res
 	}
 
 	found.SortByName()
 	logrus.WithField("lib", found[0].Name).Info("  first library in alphabetic order")
-	return found[0]
+	res.Selected = found[0]
+	res.Reason = "first in alphabetic order among libraries with the same priority"
+	return res
 }
 
 func simplify(name string) string {
@@ -158,3 +158,34 @@ func TestCppHeaderResolverWithLibrariesInStrangeDirectoryNames(t *testing.T) {
 	resolver.headers["OneWire.h"] = librarylist2
 	require.Equal(t, "OneWire", resolver.ResolveFor("OneWire.h", "avr").Name)
 }
+
+func TestCppHeaderResolution(t *testing.T) {
+	resolver := NewCppResolver()
+	libraryList := libraries.List{}
+	libraryList.Add(bundleServo)
+	resolver.headers["Servo.h"] = libraryList
+	res := resolver.Resolve("Servo.h", "avr")
+	require.Equal(t, bundleServo, res.Selected)
+	require.Equal(t, "only candidate", res.Reason)
+	require.Len(t, res.Candidates, 1)
+
+	userServo := &libraries.Library{Name: "Servo", Location: libraries.User, Architectures: []string{"avr"}}
+	libraryList.Add(userServo)
+	resolver.headers["Servo.h"] = libraryList
+	res = resolver.Resolve("Servo.h", "avr")
+	require.Equal(t, userServo, res.Selected)
+	require.Equal(t, "highest priority", res.Reason)
+	require.Len(t, res.Candidates, 2)
+	require.Equal(t, computePriority(bundleServo, "Servo.h", "avr"), res.Candidates[0].Priority)
+	require.Equal(t, computePriority(userServo, "Servo.h", "avr"), res.Candidates[1].Priority)
+
+	res = resolver.Resolve("Missing.h", "avr")
+	require.Nil(t, res.Selected)
+	require.Empty(t, res.Candidates)
+
+	libraryList = libraries.List{}
+	libraryList.Add(l5, l6, l7)
+	resolver.headers["XYZ.h"] = libraryList
+	res = resolver.Resolve("XYZ.h", "xyz")
+	require.Equal(t, l7, res.Selected)
+}
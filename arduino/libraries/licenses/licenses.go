@@ -0,0 +1,224 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package licenses
+
+import (
+	"regexp"
+	"strings"
+
+	paths "github.com/arduino/go-paths-helper"
+)
+
+// Known are the SPDX identifiers of the licenses recognized by Normalize and
+// Detect
+var Known = []string{
+	"AGPL-3.0",
+	"Apache-2.0",
+	"BSD-2-Clause",
+	"BSD-3-Clause",
+	"BSL-1.0",
+	"CC0-1.0",
+	"EPL-2.0",
+	"GPL-2.0",
+	"GPL-2.0-or-later",
+	"GPL-3.0",
+	"GPL-3.0-or-later",
+	"ISC",
+	"LGPL-2.1",
+	"LGPL-2.1-or-later",
+	"LGPL-3.0",
+	"LGPL-3.0-or-later",
+	"MIT",
+	"MPL-2.0",
+	"Unlicense",
+	"Zlib",
+}
+
+// aliases maps the squashed names of the licenses to their SPDX identifier
+var aliases = map[string]string{
+	"apache2":                  "Apache-2.0",
+	"bsd2":                     "BSD-2-Clause",
+	"simplifiedbsd":            "BSD-2-Clause",
+	"freebsd":                  "BSD-2-Clause",
+	"bsd3":                     "BSD-3-Clause",
+	"newbsd":                   "BSD-3-Clause",
+	"modifiedbsd":              "BSD-3-Clause",
+	"boostsoftware1":           "BSL-1.0",
+	"cc0":                      "CC0-1.0",
+	"gnuaffero3":               "AGPL-3.0",
+	"gnuaffero3orlater":        "AGPL-3.0",
+	"gnugeneralpublic2":        "GPL-2.0",
+	"gnugeneralpublic3":        "GPL-3.0",
+	"gnugpl2":                  "GPL-2.0",
+	"gnugpl3":                  "GPL-3.0",
+	"gnulessergeneralpublic21": "LGPL-2.1",
+	"gnulessergeneralpublic3":  "LGPL-3.0",
+	"gnulgpl21":                "LGPL-2.1",
+	"gnulgpl3":                 "LGPL-3.0",
+	"mitx11":                   "MIT",
+	"mozillapublic2":           "MPL-2.0",
+	"publicdomainunlicense":    "Unlicense",
+}
+
+func init() {
+	for _, id := range Known {
+		aliases[squash(id)] = id
+	}
+}
+
+var (
+	minorZero  = regexp.MustCompile(`(\d)\.0\b`)
+	versionV   = regexp.MustCompile(`v\.? ?(\d)`)
+	noiseWords = regexp.MustCompile(`\b(the|licen[cs]e|version|only|clause)\b`)
+	nonAlnum   = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// squash reduces the name of a license to a form without the words,
+// the punctuation and the spelling that usually differ between its variants,
+// e.g. "GNU GPL v3.0" and "gnu-gpl-3" are both squashed to "gnugpl3".
+func squash(name string) string {
+	s := strings.ToLower(name)
+	s = strings.Replace(s, "+", " or later", -1)
+	s = minorZero.ReplaceAllString(s, "$1")
+	s = versionV.ReplaceAllString(s, "$1")
+	s = noiseWords.ReplaceAllString(s, "")
+	return nonAlnum.ReplaceAllString(s, "")
+}
+
+// Normalize returns the SPDX identifier, or the SPDX expression with the OR
+// and AND operators, of the license declared with the given name, or an
+// empty string if it's not recognized.
+func Normalize(license string) string {
+	license = strings.TrimSpace(license)
+	if license == "" {
+		return ""
+	}
+	res := []string{}
+	for _, alternative := range strings.Split(license, " OR ") {
+		terms := []string{}
+		for _, term := range strings.Split(alternative, " AND ") {
+			id, ok := aliases[squash(strings.Trim(term, "() "))]
+			if !ok {
+				return ""
+			}
+			terms = append(terms, id)
+		}
+		res = append(res, strings.Join(terms, " AND "))
+	}
+	return strings.Join(res, " OR ")
+}
+
+// Allowed returns true if the license expression is allowed by the given list
+// of licenses: at least one of the alternatives of an OR must be allowed, and
+// all the terms of an AND. The licenses are compared ignoring the case.
+func Allowed(license string, allowlist []string) bool {
+	for _, alternative := range strings.Split(license, " OR ") {
+		allowed := true
+		for _, term := range strings.Split(alternative, " AND ") {
+			allowed = allowed && contains(allowlist, strings.Trim(term, "() "))
+		}
+		if allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, license string) bool {
+	for _, l := range list {
+		if strings.EqualFold(strings.TrimSpace(l), license) {
+			return true
+		}
+	}
+	return false
+}
+
+// licenseFiles are the names, without the .txt or .md extension, of the files
+// looked for by Detect in the folder of a library, in order of preference:
+// the libraries released under the LGPL have both a COPYING.LESSER file and
+// the COPYING file with the text of the GPL.
+var licenseFiles = []string{"copying.lesser", "license", "licence", "unlicense", "copying"}
+
+// textMarkers are the sentences that identify a license in its text, the
+// licenses that contain the text of others (e.g. the LGPL-3.0 the GPL-3.0)
+// are listed first.
+var textMarkers = []struct {
+	id      string
+	markers []string
+}{
+	{"AGPL-3.0", []string{"GNU AFFERO GENERAL PUBLIC LICENSE"}},
+	{"LGPL-2.1", []string{"GNU LESSER GENERAL PUBLIC LICENSE", "Version 2.1"}},
+	{"LGPL-3.0", []string{"GNU LESSER GENERAL PUBLIC LICENSE", "Version 3"}},
+	{"GPL-2.0", []string{"GNU GENERAL PUBLIC LICENSE", "Version 2"}},
+	{"GPL-3.0", []string{"GNU GENERAL PUBLIC LICENSE", "Version 3"}},
+	{"Apache-2.0", []string{"Apache License", "Version 2.0"}},
+	{"MPL-2.0", []string{"Mozilla Public License", "2.0"}},
+	{"EPL-2.0", []string{"Eclipse Public License", "2.0"}},
+	{"BSL-1.0", []string{"Boost Software License"}},
+	{"Unlicense", []string{"This is free and unencumbered software released into the public domain"}},
+	{"CC0-1.0", []string{"CC0 1.0 Universal"}},
+	{"MIT", []string{"Permission is hereby granted, free of charge"}},
+	{"ISC", []string{"Permission to use, copy, modify, and/or distribute this software for any purpose"}},
+	{"Zlib", []string{"This software is provided 'as-is'", "Permission is granted to anyone to use this software for any purpose"}},
+	{"BSD-3-Clause", []string{"Redistribution and use in source and binary forms", "Neither the name"}},
+	{"BSD-2-Clause", []string{"Redistribution and use in source and binary forms"}},
+}
+
+var spaces = regexp.MustCompile(`\s+`)
+
+// Detect returns the SPDX identifier of the license found in the LICENSE or
+// COPYING file in the given folder, recognized from its text, or an empty
+// string if no known license is found.
+func Detect(dir *paths.Path) string {
+	files, err := dir.ReadDir()
+	if err != nil {
+		return ""
+	}
+	files.FilterOutDirs()
+	for _, licenseFile := range licenseFiles {
+		for _, file := range files {
+			name := strings.ToLower(file.Base())
+			name = strings.TrimSuffix(strings.TrimSuffix(name, ".txt"), ".md")
+			if name != licenseFile {
+				continue
+			}
+			data, err := file.ReadFile()
+			if err != nil {
+				continue
+			}
+			if id := DetectFromText(string(data)); id != "" {
+				return id
+			}
+		}
+	}
+	return ""
+}
+
+// DetectFromText returns the SPDX identifier of the license with the given
+// text, or an empty string if it's not recognized.
+func DetectFromText(text string) string {
+	text = spaces.ReplaceAllString(text, " ")
+	for _, license := range textMarkers {
+		found := true
+		for _, marker := range license.markers {
+			found = found && strings.Contains(text, marker)
+		}
+		if found {
+			return license.id
+		}
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package licenses
+
+import (
+	"testing"
+
+	paths "github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	for declared, expected := range map[string]string{
+		"MIT":                                    "MIT",
+		"MIT License":                            "MIT",
+		"mit":                                    "MIT",
+		"GPLv3":                                  "GPL-3.0",
+		"GPL v3.0":                               "GPL-3.0",
+		"GPL-3.0-only":                           "GPL-3.0",
+		"GPL-3.0+":                               "GPL-3.0-or-later",
+		"GNU General Public License v2.0":        "GPL-2.0",
+		"LGPL 2.1":                               "LGPL-2.1",
+		"GNU Lesser General Public License v2.1": "LGPL-2.1",
+		"Apache License, Version 2.0":            "Apache-2.0",
+		"BSD 3-Clause":                           "BSD-3-Clause",
+		"New BSD License":                        "BSD-3-Clause",
+		"MIT OR Apache-2.0":                      "MIT OR Apache-2.0",
+		"(MIT AND Zlib)":                         "MIT AND Zlib",
+		"GPL":                                    "",
+		"Public Domain":                          "",
+		"MIT OR Beerware":                        "",
+		"":                                       "",
+	} {
+		require.Equal(t, expected, Normalize(declared), declared)
+	}
+}
+
+func TestAllowed(t *testing.T) {
+	allowlist := []string{"MIT", "apache-2.0", "Public Domain"}
+	require.True(t, Allowed("MIT", allowlist))
+	require.True(t, Allowed("Apache-2.0", allowlist))
+	require.True(t, Allowed("Public Domain", allowlist))
+	require.False(t, Allowed("GPL-3.0", allowlist))
+	require.True(t, Allowed("GPL-3.0 OR MIT", allowlist))
+	require.False(t, Allowed("GPL-3.0 AND MIT", allowlist))
+	require.True(t, Allowed("Apache-2.0 AND MIT", allowlist))
+	require.False(t, Allowed("MIT", nil))
+}
+
+func TestDetect(t *testing.T) {
+	require.Equal(t, "MIT", DetectFromText(`Copyright (c) 2020 Someone
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files`))
+	require.Equal(t, "BSD-3-Clause", DetectFromText(`Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+3. Neither the name of the copyright holder`))
+	require.Equal(t, "BSD-2-Clause", DetectFromText(`Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:`))
+	require.Equal(t, "", DetectFromText("All rights reserved."))
+
+	dir, err := paths.MkTempDir("", "licenses")
+	require.NoError(t, err)
+	defer dir.RemoveAll()
+	require.Equal(t, "", Detect(dir))
+	require.NoError(t, dir.Join("COPYING").WriteFile([]byte("GNU GENERAL PUBLIC LICENSE\nVersion 3, 29 June 2007")))
+	require.Equal(t, "GPL-3.0", Detect(dir))
+	require.NoError(t, dir.Join("COPYING.LESSER").WriteFile([]byte("GNU LESSER GENERAL PUBLIC LICENSE\n  Version 3, 29 June 2007")))
+	require.Equal(t, "LGPL-3.0", Detect(dir))
+	require.Equal(t, "", Detect(dir.Join("missing")))
+}
@@ -15,31 +15,60 @@
 
 package libraries
 
+import (
+	"fmt"
+	"strings"
+
+	properties "github.com/arduino/go-properties-orderedmap"
+	semver "go.bug.st/relaxed-semver"
+)
+
+// sourceControlFolders are the hidden folders that may be found in a library
+// without being a mistake
+var sourceControlFolders = map[string]bool{".git": true, ".github": true, ".svn": true, ".hg": true, ".bzr": true, ".vscode": true}
+
 // Lint produce warnings about the formal correctness of a Library
 func (l *Library) Lint() ([]string, error) {
+	warnings := []string{}
+
+	dirs, err := l.InstallDir.ReadDir()
+	if err != nil {
+		return nil, fmt.Errorf("reading dir %s: %s", l.InstallDir, err)
+	}
+	dirs.FilterDirs()
+	for _, dir := range dirs {
+		if strings.HasPrefix(dir.Base(), ".") && !sourceControlFolders[dir.Base()] {
+			warnings = append(warnings, fmt.Sprintf("Spurious %s directory in '%s' library", dir.Base(), l.Name))
+		}
+	}
+
+	if l.IsLegacy {
+		warnings = append(warnings, fmt.Sprintf("Library '%s' has no library.properties file", l.Name))
+		return warnings, nil
+	}
+
+	// The loaded properties have been normalized, the original file is
+	// checked instead
+	libProperties, err := properties.Load(l.InstallDir.Join("library.properties").String())
+	if err != nil {
+		return nil, fmt.Errorf("loading library.properties: %s", err)
+	}
+	for _, propName := range MandatoryProperties {
+		if propName == "maintainer" && libProperties.Get("email") != "" {
+			continue
+		}
+		if strings.TrimSpace(libProperties.Get(propName)) == "" {
+			warnings = append(warnings, fmt.Sprintf("Missing '%s' property in library.properties of '%s' library", propName, l.Name))
+		}
+	}
+	if version := strings.TrimSpace(libProperties.Get("version")); version != "" {
+		if _, err := semver.Parse(version); err != nil {
+			warnings = append(warnings, fmt.Sprintf("Invalid version '%s' in '%s' library: %s", version, l.Name, err))
+		}
+	}
+	if category := strings.TrimSpace(libProperties.Get("category")); category != "" && !ValidCategories[category] {
+		warnings = append(warnings, fmt.Sprintf("Category '%s' in library %s is not valid. Setting to '%s'", category, l.Name, "Uncategorized"))
+	}
 
-	// TODO: check for spurious dirs
-	// subDirs, err := ioutil.ReadDir(libraryDir)
-	// if err != nil {
-	// 	return nil, fmt.Errorf("reading dir %s: %s", libraryDir, err)
-	// }
-	// 	for _, subDir := range subDirs {
-	// 		if utils.IsSCCSOrHiddenFile(subDir) {
-	// 			if !utils.IsSCCSFile(subDir) && utils.IsHiddenFile(subDir) {
-	// 				logger.Fprintln(os.Stdout, "warn",
-	// 					"WARNING: Spurious {0} directory in '{1}' library",
-	// 					filepath.Base(subDir.Name()), libProperties["name"])
-	// 			}
-	// 		}
-	// 	}
-
-	// TODO: check for invalid category in library.properties
-	// if !ValidCategories[libProperties["category"]] {
-	//  logger.Fprintln(os.Stdout, "warn",
-	// 	"WARNING: Category '{0}' in library {1} is not valid. Setting to '{2}'",
-	// 	libProperties["category"], libProperties["name"], "Uncategorized")
-	// 	libProperties["category"] = "Uncategorized"
-	// }
-
-	return []string{}, nil
+	return warnings, nil
 }
@@ -0,0 +1,76 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package publish
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino/libraries/librariesindex"
+	"github.com/arduino/arduino-cli/arduino/sketches/check"
+	paths "github.com/arduino/go-paths-helper"
+	properties "github.com/arduino/go-properties-orderedmap"
+	semver "go.bug.st/relaxed-semver"
+)
+
+// CheckIndex runs locally the checks done by the Library Manager indexer on
+// a new release of the library in the given folder: the name must not clash
+// with another library and the version must not be already released.
+func CheckIndex(index *librariesindex.Index, libPath *paths.Path) ([]*check.Diagnostic, error) {
+	props, err := properties.LoadFromPath(libPath.Join("library.properties"))
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(props.Get("name"))
+	version, err := semver.Parse(strings.TrimSpace(props.Get("version")))
+	if name == "" || err != nil {
+		// Already reported by Validate
+		return []*check.Diagnostic{}, nil
+	}
+
+	res := []*check.Diagnostic{}
+	add := func(severity check.Severity, rule string, format string, args ...interface{}) {
+		res = append(res, &check.Diagnostic{
+			Severity: severity,
+			Rule:     rule,
+			File:     "library.properties",
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	indexed, ok := index.Libraries[name]
+	if !ok {
+		for indexedName := range index.Libraries {
+			if strings.EqualFold(indexedName, name) {
+				add(check.SeverityError, "index-name", "the name '%s' differs only by case from the indexed library '%s'", name, indexedName)
+				return res, nil
+			}
+		}
+		add(check.SeverityWarning, "index-new-library", "the library '%s' is not in the index, it must be submitted to the Library Manager registry", name)
+		return res, nil
+	}
+
+	if indexed.Releases[version.String()] != nil {
+		add(check.SeverityError, "index-version", "the version %s of '%s' is already released, increase the version before tagging the release", version, name)
+		return res, nil
+	}
+	if versions := indexed.Versions(); len(versions) > 0 {
+		if latest := versions[len(versions)-1]; !version.GreaterThan(latest) {
+			add(check.SeverityWarning, "index-version", "the version %s is lower than the latest released version %s", version, latest)
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,74 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package publish
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino/resources"
+	paths "github.com/arduino/go-paths-helper"
+	properties "github.com/arduino/go-properties-orderedmap"
+)
+
+// Archive is a release archive of a library
+type Archive struct {
+	Path     *paths.Path `json:"path"`
+	Size     int64       `json:"size"`
+	Checksum string      `json:"checksum"`
+	Files    int         `json:"files"`
+}
+
+// ArchiveName returns the name used by the Library Manager for the release
+// archives of a library, for example "Servo-1.1.8"
+func ArchiveName(name, version string) string {
+	return strings.ReplaceAll(name, " ", "_") + "-" + version
+}
+
+// Package creates the release archive of the library in the given folder in
+// outDir. The files in the archive are in a folder named after the library
+// and the version. Hidden files and folders, and the files matching any of the
+// exclude patterns, are not added. The patterns are matched against both the
+// name of the files and their path relative to the library folder.
+func Package(libPath, outDir *paths.Path, exclude []string) (*Archive, error) {
+	props, err := properties.LoadFromPath(libPath.Join("library.properties"))
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(props.Get("name"))
+	version := strings.TrimSpace(props.Get("version"))
+	if name == "" || version == "" {
+		return nil, fmt.Errorf("the name and the version of the library are required in library.properties")
+	}
+	folder := ArchiveName(name, version)
+
+	if err := outDir.MkdirAll(); err != nil {
+		return nil, err
+	}
+	archivePath := outDir.Join(folder + ".zip")
+	if absOut, err := archivePath.Abs(); err == nil {
+		archivePath = absOut
+	}
+	files, err := resources.CreateZipArchive(libPath, archivePath, folder, exclude)
+	if err != nil {
+		return nil, err
+	}
+	resource, err := resources.NewDownloadResource(archivePath, "")
+	if err != nil {
+		return nil, err
+	}
+	return &Archive{Path: archivePath, Size: resource.Size, Checksum: resource.Checksum, Files: files}, nil
+}
@@ -0,0 +1,185 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package publish
+
+import (
+	"archive/zip"
+	"sort"
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/libraries/librariesindex"
+	"github.com/arduino/arduino-cli/arduino/sketches/check"
+	paths "github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+const validProperties = "name=My Library\n" +
+	"version=1.2.0\n" +
+	"author=Me\n" +
+	"maintainer=Me <me@example.com>\n" +
+	"sentence=A library.\n" +
+	"paragraph=A library that does things.\n" +
+	"category=Sensors\n" +
+	"url=https://github.com/me/mylib\n" +
+	"architectures=*\n" +
+	"depends=Servo (>=1.1.0), Adafruit GFX Library\n" +
+	"includes=MyLib.h\n"
+
+func createLibrary(t *testing.T, dir *paths.Path, files map[string]string) {
+	for name, content := range files {
+		file := dir.Join(name)
+		require.NoError(t, file.Parent().MkdirAll())
+		require.NoError(t, file.WriteFile([]byte(content)))
+	}
+}
+
+func rules(diagnostics []*check.Diagnostic) []string {
+	res := []string{}
+	for _, d := range diagnostics {
+		res = append(res, d.Rule)
+	}
+	return res
+}
+
+func TestValidateName(t *testing.T) {
+	require.NoError(t, ValidateName("Adafruit GFX Library"))
+	require.NoError(t, ValidateName("4-20mA_sensor.v2"))
+	require.Error(t, ValidateName(""))
+	require.Error(t, ValidateName("_private"))
+	require.Error(t, ValidateName("My/Library"))
+	require.Error(t, ValidateName("ThisLibraryNameIsReallyMuchTooLongToBeAcceptedByTheLibraryManager"))
+}
+
+func TestValidate(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "publish")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+
+	libPath := tmp.Join("MyLib")
+	createLibrary(t, libPath, map[string]string{
+		"library.properties":          validProperties,
+		"keywords.txt":                "# comment\nMyLib\tKEYWORD1\nbegin\tKEYWORD2\n",
+		"src/MyLib.h":                 "void begin();\n",
+		"examples/Basic/Basic.ino":    "void setup() {}\nvoid loop() {}\n",
+		"examples/More/Adv/Adv.ino":   "void setup() {}\nvoid loop() {}\n",
+		".git/HEAD":                   "ref: refs/heads/master\n",
+		".github/workflows/build.yml": "",
+	})
+	diagnostics, err := Validate(libPath)
+	require.NoError(t, err)
+	require.Empty(t, diagnostics)
+
+	createLibrary(t, libPath, map[string]string{
+		"library.properties": "name=_MyLib\nversion=one\nauthor=Me\nemail=me@example.com\n" +
+			"url=github.com/me/mylib\ncategory=Things\ndepends=Servo (>=1.1.0\nincludes=Missing.h\n",
+		"keywords.txt":              "MyLib KEYWORD1\nbegin\tKEYWORD7\nend\n",
+		".development":              "",
+		"examples/Broken/Other.ino": "void setup() {}\nvoid loop() {}\n",
+	})
+	diagnostics, err = Validate(libPath)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"development-flag",
+		"deprecated-email",
+		"missing-sentence",
+		"missing-paragraph",
+		"name",
+		"version",
+		"url",
+		"category",
+		"missing-architectures",
+		"depends",
+		"includes",
+		"keywords-separator",
+		"keywords-token-type",
+		"keywords-token-type",
+		"main-file",
+	}, rules(diagnostics))
+	require.Equal(t, "keywords.txt", diagnostics[11].File)
+	require.Equal(t, 1, diagnostics[11].Line)
+	require.Equal(t, "examples/Broken", diagnostics[14].File)
+
+	require.NoError(t, libPath.Join("library.properties").Remove())
+	diagnostics, err = Validate(libPath)
+	require.NoError(t, err)
+	require.Contains(t, rules(diagnostics), "library-properties")
+}
+
+func TestCheckIndex(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "publish")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+
+	index, err := librariesindex.LoadIndex(paths.New("../librariesindex/testdata/library_index.json"))
+	require.NoError(t, err)
+
+	checkIndex := func(props string) []string {
+		require.NoError(t, tmp.Join("library.properties").WriteFile([]byte(props)))
+		diagnostics, err := CheckIndex(index, tmp)
+		require.NoError(t, err)
+		return rules(diagnostics)
+	}
+	require.Equal(t, []string{"index-new-library"}, checkIndex("name=My Library\nversion=1.0.0\n"))
+	require.Equal(t, []string{"index-name"}, checkIndex("name=servo\nversion=1.0.0\n"))
+	require.Equal(t, []string{"index-version"}, checkIndex("name=Servo\nversion=1.1.5\n"))
+	require.Equal(t, []string{"index-version"}, checkIndex("name=Servo\nversion=1.0.5\n"))
+	require.Equal(t, []string{}, checkIndex("name=Servo\nversion=99.0.0\n"))
+}
+
+func TestPackage(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "publish")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+
+	libPath := tmp.Join("MyLib")
+	createLibrary(t, libPath, map[string]string{
+		"library.properties":       validProperties,
+		"src/MyLib.h":              "void begin();\n",
+		"src/MyLib.cpp":            "void begin() {}\n",
+		"examples/Basic/Basic.ino": "void setup() {}\nvoid loop() {}\n",
+		"extras/docs/big.pdf":      "",
+		"build/MyLib.o":            "",
+		".git/HEAD":                "",
+		".clang-format":            "",
+	})
+
+	archive, err := Package(libPath, libPath, []string{"extras/docs", "*.o"})
+	require.NoError(t, err)
+	require.Equal(t, "My_Library-1.2.0.zip", archive.Path.Base())
+	require.Equal(t, 4, archive.Files)
+	require.Regexp(t, "^SHA-256:[0-9a-f]{64}$", archive.Checksum)
+	size, err := archive.Path.Stat()
+	require.NoError(t, err)
+	require.Equal(t, size.Size(), archive.Size)
+
+	zipReader, err := zip.OpenReader(archive.Path.String())
+	require.NoError(t, err)
+	defer zipReader.Close()
+	files := []string{}
+	for _, f := range zipReader.File {
+		files = append(files, f.Name)
+	}
+	sort.Strings(files)
+	require.Equal(t, []string{
+		"My_Library-1.2.0/examples/Basic/Basic.ino",
+		"My_Library-1.2.0/library.properties",
+		"My_Library-1.2.0/src/MyLib.cpp",
+		"My_Library-1.2.0/src/MyLib.h",
+	}, files)
+
+	_, err = Package(libPath, tmp, []string{"[a-"})
+	require.Error(t, err)
+}
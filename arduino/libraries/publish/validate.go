@@ -0,0 +1,309 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package publish
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino/libraries"
+	"github.com/arduino/arduino-cli/arduino/sketches/check"
+	paths "github.com/arduino/go-paths-helper"
+	properties "github.com/arduino/go-properties-orderedmap"
+	semver "go.bug.st/relaxed-semver"
+)
+
+// MaxNameLength is the maximum length of a library name accepted by the
+// Library Manager
+const MaxNameLength = 63
+
+var validName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9 _.\-]*$`)
+
+// ValidKeywordTypes are the token types accepted in a keywords.txt file
+var ValidKeywordTypes = map[string]bool{
+	"KEYWORD1":       true,
+	"KEYWORD2":       true,
+	"KEYWORD3":       true,
+	"LITERAL1":       true,
+	"LITERAL2":       true,
+	"RESERVED_WORD":  true,
+	"RESERVED_WORD2": true,
+	"DATA_TYPE":      true,
+	"PREPROCESSOR":   true,
+}
+
+// Validate checks that the library in the given folder can be published in
+// the Library Manager: the library.properties fields, the keywords.txt
+// syntax, the examples and the files that are rejected by the indexer. The
+// paths of the diagnostics are relative to the library folder.
+func Validate(libPath *paths.Path) ([]*check.Diagnostic, error) {
+	if !libPath.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", libPath)
+	}
+	v := &validator{libPath: libPath}
+	v.checkFiles()
+	if err := v.checkProperties(); err != nil {
+		return nil, err
+	}
+	if err := v.checkKeywords(); err != nil {
+		return nil, err
+	}
+	if err := v.checkExamples(); err != nil {
+		return nil, err
+	}
+	return v.diagnostics, nil
+}
+
+// ValidateName checks that name can be used as the name of a library in the
+// Library Manager
+func ValidateName(name string) error {
+	if name == "" {
+		return fmt.Errorf("the name is empty")
+	}
+	if len(name) > MaxNameLength {
+		return fmt.Errorf("the name is longer than %d characters", MaxNameLength)
+	}
+	if !validName.MatchString(name) {
+		return fmt.Errorf("the name must start with a letter or a number and contain only letters, numbers, spaces, underscores, dots and dashes")
+	}
+	return nil
+}
+
+type validator struct {
+	libPath     *paths.Path
+	diagnostics []*check.Diagnostic
+}
+
+func (v *validator) add(severity check.Severity, rule string, file string, line int, format string, args ...interface{}) {
+	v.diagnostics = append(v.diagnostics, &check.Diagnostic{
+		Severity: severity,
+		Rule:     rule,
+		File:     file,
+		Line:     line,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// checkFiles looks for the files that make the indexer reject a release
+func (v *validator) checkFiles() {
+	if v.libPath.Join(".development").Exist() {
+		v.add(check.SeverityError, "development-flag", ".development", 0, "the .development file prevents the library from being indexed, remove it before the release")
+	}
+	_ = filepath.Walk(v.libPath.String(), func(path string, info os.FileInfo, err error) error {
+		if err != nil || path == v.libPath.String() {
+			return nil
+		}
+		rel, _ := filepath.Rel(v.libPath.String(), path)
+		rel = filepath.ToSlash(rel)
+		if info.IsDir() && strings.HasPrefix(info.Name(), ".") {
+			// Hidden folders are not included in the release archive
+			return filepath.SkipDir
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			v.add(check.SeverityError, "symlink", rel, 0, "symbolic links are not allowed in a library release")
+		}
+		return nil
+	})
+}
+
+func (v *validator) checkProperties() error {
+	propsFile := v.libPath.Join("library.properties")
+	if !propsFile.Exist() {
+		v.add(check.SeverityError, "library-properties", "library.properties", 0, "library.properties is required to publish a library")
+		return nil
+	}
+	props, err := properties.LoadFromPath(propsFile)
+	if err != nil {
+		v.add(check.SeverityError, "library-properties", "library.properties", 0, "%s", err)
+		return nil
+	}
+	field := func(name string) string { return strings.TrimSpace(props.Get(name)) }
+	missing := func(severity check.Severity, name string) bool {
+		if field(name) != "" {
+			return false
+		}
+		v.add(severity, "missing-"+name, "library.properties", 0, "the '%s' field is missing", name)
+		return true
+	}
+
+	for _, name := range libraries.MandatoryProperties {
+		if name == "maintainer" && field("email") != "" {
+			v.add(check.SeverityWarning, "deprecated-email", "library.properties", 0, "the 'email' field is deprecated, use 'maintainer' instead")
+			continue
+		}
+		missing(check.SeverityError, name)
+	}
+	missing(check.SeverityError, "sentence")
+	missing(check.SeverityWarning, "paragraph")
+
+	if name := field("name"); name != "" {
+		if err := ValidateName(name); err != nil {
+			v.add(check.SeverityError, "name", "library.properties", 0, "invalid name '%s': %s", name, err)
+		}
+	}
+	if version := field("version"); version != "" {
+		if _, err := semver.Parse(version); err != nil {
+			v.add(check.SeverityError, "version", "library.properties", 0, "invalid version '%s': %s", version, err)
+		}
+	}
+	if !missing(check.SeverityWarning, "url") {
+		if u, err := url.Parse(field("url")); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			v.add(check.SeverityWarning, "url", "library.properties", 0, "invalid url '%s'", field("url"))
+		}
+	}
+	if !missing(check.SeverityWarning, "category") && !libraries.ValidCategories[field("category")] {
+		v.add(check.SeverityWarning, "category", "library.properties", 0, "invalid category '%s', the library will be listed as 'Uncategorized'", field("category"))
+	}
+	missing(check.SeverityWarning, "architectures")
+
+	if depends := field("depends"); depends != "" {
+		for _, dep := range strings.Split(depends, ",") {
+			if err := validateDependency(strings.TrimSpace(dep)); err != nil {
+				v.add(check.SeverityError, "depends", "library.properties", 0, "invalid dependency '%s': %s", strings.TrimSpace(dep), err)
+			}
+		}
+	}
+
+	if includes := field("includes"); includes != "" {
+		sourceDir := v.libPath
+		if v.libPath.Join("src").IsDir() {
+			sourceDir = v.libPath.Join("src")
+		}
+		for _, include := range strings.Split(includes, ",") {
+			include = strings.TrimSpace(include)
+			if include != "" && !sourceDir.Join(include).Exist() {
+				v.add(check.SeverityError, "includes", "library.properties", 0, "the header '%s' listed in 'includes' does not exist", include)
+			}
+		}
+	}
+	return nil
+}
+
+// validateDependency checks an entry of the depends field, in the form
+// "Name" or "Name (constraint)"
+func validateDependency(dep string) error {
+	name := dep
+	if i := strings.Index(dep, "("); i != -1 {
+		if !strings.HasSuffix(dep, ")") {
+			return fmt.Errorf("missing closing parenthesis")
+		}
+		name = strings.TrimSpace(dep[:i])
+		if _, err := semver.ParseConstraint(strings.TrimSpace(dep[i+1 : len(dep)-1])); err != nil {
+			return err
+		}
+	}
+	return ValidateName(name)
+}
+
+func (v *validator) checkKeywords() error {
+	file := v.libPath.Join("keywords.txt")
+	if !file.Exist() {
+		return nil
+	}
+	data, err := file.ReadFile()
+	if err != nil {
+		return fmt.Errorf("reading keywords.txt: %s", err)
+	}
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) < 2 {
+			if len(strings.Fields(line)) > 1 {
+				v.add(check.SeverityWarning, "keywords-separator", "keywords.txt", i+1, "the fields must be separated by a single tab")
+			} else {
+				v.add(check.SeverityWarning, "keywords-token-type", "keywords.txt", i+1, "missing token type for '%s'", strings.TrimSpace(line))
+			}
+			continue
+		}
+		if fields[1] == "" {
+			v.add(check.SeverityWarning, "keywords-separator", "keywords.txt", i+1, "the fields must be separated by a single tab")
+			continue
+		}
+		if !ValidKeywordTypes[strings.TrimSpace(fields[1])] {
+			v.add(check.SeverityWarning, "keywords-token-type", "keywords.txt", i+1, "invalid token type '%s'", fields[1])
+		}
+	}
+	return nil
+}
+
+// checkExamples runs the sketch checks on every example of the library
+func (v *validator) checkExamples() error {
+	examplesDir := v.libPath.Join("examples")
+	if !examplesDir.IsDir() {
+		if v.libPath.Join("Examples").IsDir() {
+			v.add(check.SeverityError, "examples-folder", "Examples", 0, "the examples folder must be named 'examples'")
+		}
+		return nil
+	}
+	var examples paths.PathList
+	if err := findExamples(examplesDir, &examples); err != nil {
+		return err
+	}
+	if len(examples) == 0 {
+		v.add(check.SeverityWarning, "examples", "examples", 0, "no examples found")
+	}
+	for _, example := range examples {
+		diagnostics, err := check.Sketch(example)
+		if err != nil {
+			return fmt.Errorf("checking example %s: %s", example, err)
+		}
+		rel, err := v.libPath.RelTo(example)
+		if err != nil {
+			return err
+		}
+		for _, d := range diagnostics {
+			if d.File == "" {
+				d.File = filepath.ToSlash(rel.String())
+			} else {
+				d.File = filepath.ToSlash(rel.Join(d.File).String())
+			}
+			v.diagnostics = append(v.diagnostics, d)
+		}
+	}
+	return nil
+}
+
+// findExamples collects the sketches found in dir and in its subfolders
+func findExamples(dir *paths.Path, examples *paths.PathList) error {
+	files, err := dir.ReadDir()
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		if file.Ext() == ".ino" || file.Ext() == ".pde" {
+			examples.Add(dir)
+			return nil
+		}
+	}
+	files.FilterDirs()
+	files.FilterOutPrefix(".")
+	for _, subDir := range files {
+		if err := findExamples(subDir, examples); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package monitors
+
+import (
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// bridgeClientBuffer is the number of chunks of data queued for a bridge
+// client, when a client is slower than the board the data is dropped
+const bridgeClientBuffer = 256
+
+// Bridge shares a monitor with other programs: the data received from the
+// monitor, written to the Bridge, is forwarded to all the connected clients
+// and the data received from any client is sent to the monitor.
+type Bridge struct {
+	mon       Monitor
+	mu        sync.Mutex
+	clients   map[*bridgeClient]bool
+	listeners []net.Listener
+	closed    bool
+}
+
+type bridgeClient struct {
+	conn  io.ReadWriteCloser
+	queue chan []byte
+}
+
+// NewBridge returns a Bridge for mon without clients
+func NewBridge(mon Monitor) *Bridge {
+	return &Bridge{mon: mon, clients: map[*bridgeClient]bool{}}
+}
+
+// ListenTCP accepts the TCP connections to address as clients of the bridge
+// and returns the address actually used.
+func (b *Bridge) ListenTCP(address string) (string, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return "", errors.Wrap(err, "opening bridge")
+	}
+	b.mu.Lock()
+	b.listeners = append(b.listeners, listener)
+	b.mu.Unlock()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			logrus.Infof("Bridge client connected from %s", conn.RemoteAddr())
+			b.AddClient(conn, false)
+		}
+	}()
+	return listener.Addr().String(), nil
+}
+
+// AddClient forwards the data between the monitor and conn. A persistent
+// client is never removed from the bridge, even if reading from it fails:
+// this is the case of a pseudo-terminal that has no programs attached.
+func (b *Bridge) AddClient(conn io.ReadWriteCloser, persistent bool) {
+	client := &bridgeClient{conn: conn, queue: make(chan []byte, bridgeClientBuffer)}
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		conn.Close()
+		return
+	}
+	b.clients[client] = true
+	b.mu.Unlock()
+
+	go func() {
+		for data := range client.queue {
+			if _, err := conn.Write(data); err != nil && !persistent {
+				b.removeClient(client)
+			}
+		}
+	}()
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, err := conn.Read(buf)
+			if n > 0 {
+				if _, err := b.mon.Write(buf[:n]); err != nil {
+					logrus.WithError(err).Warn("Error writing bridge data to the monitor")
+				}
+			}
+			if err == nil {
+				continue
+			}
+			if !persistent || b.isClosed() {
+				b.removeClient(client)
+				return
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+	}()
+}
+
+func (b *Bridge) isClosed() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.closed
+}
+
+func (b *Bridge) removeClient(client *bridgeClient) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.clients[client] {
+		return
+	}
+	delete(b.clients, client)
+	close(client.queue)
+	client.conn.Close()
+}
+
+// Write forwards data to all the clients
+func (b *Bridge) Write(data []byte) (int, error) {
+	chunk := make([]byte, len(data))
+	copy(chunk, data)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for client := range b.clients {
+		select {
+		case client.queue <- chunk:
+		default:
+			// the client is too slow, drop the data
+		}
+	}
+	return len(data), nil
+}
+
+// Close disconnects all the clients and stops accepting new ones
+func (b *Bridge) Close() error {
+	b.mu.Lock()
+	b.closed = true
+	listeners := b.listeners
+	clients := []*bridgeClient{}
+	for client := range b.clients {
+		clients = append(clients, client)
+	}
+	b.mu.Unlock()
+	for _, listener := range listeners {
+		listener.Close()
+	}
+	for _, client := range clients {
+		b.removeClient(client)
+	}
+	return nil
+}
+
+// ParseBridgeSpec splits a bridge specification, tcp:<address> or pty, in
+// its kind and address
+func ParseBridgeSpec(spec string) (kind, address string, err error) {
+	if spec == "pty" {
+		return "pty", "", nil
+	}
+	if strings.HasPrefix(spec, "tcp:") && len(spec) > len("tcp:") {
+		return "tcp", strings.TrimPrefix(spec, "tcp:"), nil
+	}
+	return "", "", errors.Errorf("invalid bridge '%s', valid values are: tcp:<address>:<port>, pty", spec)
+}
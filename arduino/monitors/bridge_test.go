@@ -0,0 +1,99 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package monitors
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type lockedMonitor struct {
+	fakeMonitor
+	mu sync.Mutex
+}
+
+func (m *lockedMonitor) Write(b []byte) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.out.Write(b)
+}
+
+func (m *lockedMonitor) sent() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.out.String()
+}
+
+func TestBridgeTCP(t *testing.T) {
+	mon := &lockedMonitor{fakeMonitor: fakeMonitor{in: &bytes.Buffer{}}}
+	bridge := NewBridge(mon)
+	defer bridge.Close()
+	addr, err := bridge.ListenTCP("127.0.0.1:0")
+	require.NoError(t, err)
+
+	clients := []net.Conn{}
+	for i := 0; i < 2; i++ {
+		conn, err := net.Dial("tcp", addr)
+		require.NoError(t, err)
+		defer conn.Close()
+		clients = append(clients, conn)
+	}
+	require.Eventually(t, func() bool {
+		bridge.mu.Lock()
+		defer bridge.mu.Unlock()
+		return len(bridge.clients) == 2
+	}, time.Second, 10*time.Millisecond)
+
+	// the data of the board reaches all the clients
+	bridge.Write([]byte("hello\n"))
+	for _, conn := range clients {
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		line, err := bufio.NewReader(conn).ReadString('\n')
+		require.NoError(t, err)
+		require.Equal(t, "hello\n", line)
+	}
+
+	// the data of a client reaches the board
+	_, err = clients[1].Write([]byte("cmd\n"))
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return mon.sent() == "cmd\n" }, time.Second, 10*time.Millisecond)
+
+	// a disconnected client is removed
+	clients[0].Close()
+	require.Eventually(t, func() bool {
+		bridge.mu.Lock()
+		defer bridge.mu.Unlock()
+		return len(bridge.clients) == 1
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestParseBridgeSpec(t *testing.T) {
+	kind, address, err := ParseBridgeSpec("tcp:0.0.0.0:4321")
+	require.NoError(t, err)
+	require.Equal(t, "tcp", kind)
+	require.Equal(t, "0.0.0.0:4321", address)
+	kind, _, err = ParseBridgeSpec("pty")
+	require.NoError(t, err)
+	require.Equal(t, "pty", kind)
+	_, _, err = ParseBridgeSpec("udp:4321")
+	require.Error(t, err)
+}
@@ -0,0 +1,152 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package monitors
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+)
+
+// TimestampMode selects the timestamps added to the monitor output
+type TimestampMode string
+
+const (
+	// TimestampNone doesn't add timestamps
+	TimestampNone TimestampMode = ""
+	// TimestampWall prefixes each line with the wall clock time
+	TimestampWall TimestampMode = "wall"
+	// TimestampDelta prefixes each line with the time elapsed since the
+	// previous line
+	TimestampDelta TimestampMode = "delta"
+)
+
+// ParseTimestampMode returns the TimestampMode with the given name
+func ParseTimestampMode(name string) (TimestampMode, error) {
+	switch TimestampMode(name) {
+	case TimestampNone, TimestampWall, TimestampDelta:
+		return TimestampMode(name), nil
+	}
+	return "", fmt.Errorf("invalid timestamps mode '%s', valid values are: wall, delta", name)
+}
+
+// hexBytesPerLine is the number of bytes printed on each line in hex mode
+const hexBytesPerLine = 16
+
+// DisplayWriter formats the data received from a monitor before writing it
+// to the underlying writer: each line may be prefixed with a timestamp and
+// the data may be shown as an hex dump.
+type DisplayWriter struct {
+	out        io.Writer
+	timestamps TimestampMode
+	hex        bool
+	now        func() time.Time
+	last       time.Time
+	lineStart  bool
+	offset     int
+}
+
+// NewDisplayWriter returns a DisplayWriter writing to out
+func NewDisplayWriter(out io.Writer, timestamps TimestampMode, hex bool) *DisplayWriter {
+	w := &DisplayWriter{
+		out:        out,
+		timestamps: timestamps,
+		hex:        hex,
+		now:        time.Now,
+		lineStart:  true,
+	}
+	w.last = w.now()
+	return w
+}
+
+func (w *DisplayWriter) timestamp() string {
+	now := w.now()
+	defer func() { w.last = now }()
+	switch w.timestamps {
+	case TimestampWall:
+		return "[" + now.Format("15:04:05.000") + "] "
+	case TimestampDelta:
+		return fmt.Sprintf("[+%.3fs] ", now.Sub(w.last).Seconds())
+	}
+	return ""
+}
+
+// Write formats data and writes it to the underlying writer
+func (w *DisplayWriter) Write(data []byte) (int, error) {
+	var buf bytes.Buffer
+	if w.hex {
+		w.writeHex(&buf, data)
+	} else {
+		w.writeText(&buf, data)
+	}
+	if _, err := w.out.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+func (w *DisplayWriter) writeText(buf *bytes.Buffer, data []byte) {
+	if w.timestamps == TimestampNone {
+		buf.Write(data)
+		return
+	}
+	for len(data) > 0 {
+		if w.lineStart {
+			buf.WriteString(w.timestamp())
+			w.lineStart = false
+		}
+		i := bytes.IndexByte(data, '\n')
+		if i == -1 {
+			buf.Write(data)
+			return
+		}
+		buf.Write(data[:i+1])
+		data = data[i+1:]
+		w.lineStart = true
+	}
+}
+
+// writeHex prints data as an hex dump, the lines of a dump are never shared
+// between writes, so each chunk of data keeps its own timestamp.
+func (w *DisplayWriter) writeHex(buf *bytes.Buffer, data []byte) {
+	prefix := w.timestamp()
+	for len(data) > 0 {
+		n := len(data)
+		if n > hexBytesPerLine {
+			n = hexBytesPerLine
+		}
+		line := data[:n]
+		fmt.Fprintf(buf, "%s%08x ", prefix, w.offset)
+		for i := 0; i < hexBytesPerLine; i++ {
+			if i < n {
+				fmt.Fprintf(buf, " %02x", line[i])
+			} else {
+				buf.WriteString("   ")
+			}
+		}
+		buf.WriteString("  |")
+		for _, c := range line {
+			if c < 0x20 || c > 0x7e {
+				c = '.'
+			}
+			buf.WriteByte(c)
+		}
+		buf.WriteString("|\n")
+		w.offset += n
+		data = data[n:]
+	}
+}
@@ -0,0 +1,61 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package monitors
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisplayWriterTimestamps(t *testing.T) {
+	clock := time.Date(2021, 5, 10, 12, 30, 15, 0, time.UTC)
+	now := func() time.Time { return clock }
+
+	var out bytes.Buffer
+	w := NewDisplayWriter(&out, TimestampWall, false)
+	w.now = now
+	w.Write([]byte("hel"))
+	w.Write([]byte("lo\nwor"))
+	clock = clock.Add(250 * time.Millisecond)
+	w.Write([]byte("ld\n\n"))
+	require.Equal(t, "[12:30:15.000] hello\n[12:30:15.000] world\n[12:30:15.250] \n", out.String())
+
+	out.Reset()
+	w = NewDisplayWriter(&out, TimestampDelta, false)
+	w.now = now
+	w.last = clock
+	w.Write([]byte("a\n"))
+	clock = clock.Add(1500 * time.Millisecond)
+	w.Write([]byte("b\n"))
+	require.Equal(t, "[+0.000s] a\n[+1.500s] b\n", out.String())
+
+	_, err := ParseTimestampMode("utc")
+	require.Error(t, err)
+}
+
+func TestDisplayWriterHex(t *testing.T) {
+	var out bytes.Buffer
+	w := NewDisplayWriter(&out, TimestampNone, true)
+	w.Write([]byte("0123456789abcdef\r\n"))
+	w.Write([]byte{0x00, 0xff})
+	require.Equal(t, ""+
+		"00000000  30 31 32 33 34 35 36 37 38 39 61 62 63 64 65 66  |0123456789abcdef|\n"+
+		"00000010  0d 0a                                            |..|\n"+
+		"00000012  00 ff                                            |..|\n", out.String())
+}
@@ -0,0 +1,82 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package monitors
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// LineEnding is the line terminator appended to the lines sent to a monitor
+type LineEnding string
+
+const (
+	// LineEndingNone sends the lines without any terminator
+	LineEndingNone LineEnding = "none"
+	// LineEndingNL terminates the lines with a newline (LF)
+	LineEndingNL LineEnding = "nl"
+	// LineEndingCR terminates the lines with a carriage return (CR)
+	LineEndingCR LineEnding = "cr"
+	// LineEndingCRLF terminates the lines with both CR and LF
+	LineEndingCRLF LineEnding = "crlf"
+)
+
+// ParseLineEnding returns the LineEnding with the given name, an empty name
+// selects LineEndingNL.
+func ParseLineEnding(name string) (LineEnding, error) {
+	switch LineEnding(name) {
+	case "":
+		return LineEndingNL, nil
+	case LineEndingNone, LineEndingNL, LineEndingCR, LineEndingCRLF:
+		return LineEnding(name), nil
+	}
+	return "", fmt.Errorf("invalid line ending '%s', valid values are: none, nl, cr, crlf", name)
+}
+
+func (e LineEnding) terminator() []byte {
+	switch e {
+	case LineEndingNone:
+		return []byte{}
+	case LineEndingCR:
+		return []byte("\r")
+	case LineEndingCRLF:
+		return []byte("\r\n")
+	}
+	return []byte("\n")
+}
+
+// LineEndingMonitor is a monitor that replaces the line terminators of the
+// data written with the selected LineEnding
+type LineEndingMonitor struct {
+	Monitor
+	ending LineEnding
+}
+
+// NewLineEndingMonitor wraps mon translating the LF or CRLF terminators of
+// the written data to the given LineEnding
+func NewLineEndingMonitor(mon Monitor, ending LineEnding) *LineEndingMonitor {
+	return &LineEndingMonitor{Monitor: mon, ending: ending}
+}
+
+// Write bytes to the port, translating the line terminators
+func (mon *LineEndingMonitor) Write(data []byte) (int, error) {
+	translated := bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	translated = bytes.ReplaceAll(translated, []byte("\n"), mon.ending.terminator())
+	if _, err := mon.Monitor.Write(translated); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
@@ -0,0 +1,43 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package monitors
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLineEndingMonitor(t *testing.T) {
+	send := func(ending LineEnding, data string) string {
+		mon := &fakeMonitor{in: &bytes.Buffer{}}
+		n, err := NewLineEndingMonitor(mon, ending).Write([]byte(data))
+		require.NoError(t, err)
+		require.Equal(t, len(data), n)
+		return mon.out.String()
+	}
+	require.Equal(t, "cmd\n", send(LineEndingNL, "cmd\r\n"))
+	require.Equal(t, "cmd\r", send(LineEndingCR, "cmd\n"))
+	require.Equal(t, "a\r\nb\r\n", send(LineEndingCRLF, "a\nb\r\n"))
+	require.Equal(t, "cmd", send(LineEndingNone, "cmd\n"))
+
+	ending, err := ParseLineEnding("")
+	require.NoError(t, err)
+	require.Equal(t, LineEndingNL, ending)
+	_, err = ParseLineEnding("lf")
+	require.EqualError(t, err, "invalid line ending 'lf', valid values are: none, nl, cr, crlf")
+}
@@ -0,0 +1,103 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package monitors
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/pkg/errors"
+)
+
+// LogFile is a log of the monitor output that is rotated when it grows over
+// a maximum size: the current log is renamed to <file>.1, the previous
+// <file>.1 to <file>.2 and so on, keeping at most maxFiles old logs.
+type LogFile struct {
+	path     *paths.Path
+	maxSize  int64
+	maxFiles int
+	file     *os.File
+	size     int64
+}
+
+// OpenLogFile opens the log file, appending to it if it already exists. A
+// maxSize of 0 disables the rotation.
+func OpenLogFile(path *paths.Path, maxSize int64, maxFiles int) (*LogFile, error) {
+	l := &LogFile{path: path, maxSize: maxSize, maxFiles: maxFiles}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *LogFile) open() error {
+	file, err := os.OpenFile(l.path.String(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return errors.Wrap(err, "opening log file")
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return errors.Wrap(err, "opening log file")
+	}
+	l.file = file
+	l.size = info.Size()
+	return nil
+}
+
+func (l *LogFile) rotated(n int) *paths.Path {
+	return paths.New(fmt.Sprintf("%s.%d", l.path, n))
+}
+
+func (l *LogFile) rotate() error {
+	if err := l.file.Close(); err != nil {
+		return err
+	}
+	if l.maxFiles > 0 {
+		_ = l.rotated(l.maxFiles).Remove()
+		for n := l.maxFiles - 1; n > 0; n-- {
+			if l.rotated(n).Exist() {
+				if err := l.rotated(n).Rename(l.rotated(n + 1)); err != nil {
+					return errors.Wrap(err, "rotating log file")
+				}
+			}
+		}
+		if err := l.path.Rename(l.rotated(1)); err != nil {
+			return errors.Wrap(err, "rotating log file")
+		}
+	} else if err := l.path.Remove(); err != nil {
+		return errors.Wrap(err, "rotating log file")
+	}
+	return l.open()
+}
+
+// Write appends data to the log, rotating it if needed
+func (l *LogFile) Write(data []byte) (int, error) {
+	if l.maxSize > 0 && l.size > 0 && l.size+int64(len(data)) > l.maxSize {
+		if err := l.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := l.file.Write(data)
+	l.size += int64(n)
+	return n, err
+}
+
+// Close the log file
+func (l *LogFile) Close() error {
+	return l.file.Close()
+}
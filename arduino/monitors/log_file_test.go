@@ -0,0 +1,55 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package monitors
+
+import (
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogFileRotation(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "monitor_log")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+	logPath := tmp.Join("monitor.log")
+
+	l, err := OpenLogFile(logPath, 10, 2)
+	require.NoError(t, err)
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err := l.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	require.NoError(t, l.Close())
+
+	read := func(p *paths.Path) string {
+		data, err := p.ReadFile()
+		require.NoError(t, err)
+		return string(data)
+	}
+	require.Equal(t, "fourth\n", read(logPath))
+	require.Equal(t, "third\n", read(tmp.Join("monitor.log.1")))
+	require.Equal(t, "second\n", read(tmp.Join("monitor.log.2")))
+	require.False(t, tmp.Join("monitor.log.3").Exist())
+
+	// The log is appended when opened again
+	l, err = OpenLogFile(logPath, 0, 0)
+	require.NoError(t, err)
+	l.Write([]byte("fifth\n"))
+	require.NoError(t, l.Close())
+	require.Equal(t, "fourth\nfifth\n", read(logPath))
+}
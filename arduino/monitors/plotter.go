@@ -0,0 +1,243 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package monitors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ChannelFormat selects how the numeric channels are extracted from the lines
+// received by a monitor
+type ChannelFormat string
+
+const (
+	// ChannelFormatCSV reads lines of values separated by commas, spaces or
+	// tabs. The channels are named after the fields of a header line, if
+	// any, or numbered starting from 1.
+	ChannelFormatCSV ChannelFormat = "csv"
+	// ChannelFormatKeyValue reads lines of name=value (or name:value) pairs
+	ChannelFormatKeyValue ChannelFormat = "key=value"
+)
+
+// ParseChannelFormat returns the ChannelFormat with the given name
+func ParseChannelFormat(name string) (ChannelFormat, error) {
+	switch ChannelFormat(name) {
+	case ChannelFormatCSV, ChannelFormatKeyValue:
+		return ChannelFormat(name), nil
+	}
+	return "", fmt.Errorf("invalid channel format '%s', valid values are: csv, key=value", name)
+}
+
+// channel is a named value extracted from a line
+type channel struct {
+	Name  string
+	Value float64
+}
+
+func splitFields(line string) []string {
+	return strings.FieldsFunc(line, func(r rune) bool {
+		return r == ',' || r == ';' || r == ' ' || r == '\t'
+	})
+}
+
+// channelParser extracts the channels from the lines, it keeps the header
+// read in CSV mode
+type channelParser struct {
+	format ChannelFormat
+	header []string
+}
+
+// parse returns the channels found in line, nil if there are none
+func (p *channelParser) parse(line string) []channel {
+	fields := splitFields(line)
+	res := []channel{}
+	if p.format == ChannelFormatKeyValue {
+		for _, field := range fields {
+			sep := strings.IndexAny(field, "=:")
+			if sep <= 0 {
+				continue
+			}
+			if value, err := strconv.ParseFloat(field[sep+1:], 64); err == nil {
+				res = append(res, channel{Name: field[:sep], Value: value})
+			}
+		}
+	} else {
+		values := make([]float64, 0, len(fields))
+		for _, field := range fields {
+			value, err := strconv.ParseFloat(field, 64)
+			if err != nil {
+				values = nil
+				break
+			}
+			values = append(values, value)
+		}
+		if values == nil {
+			// a line of labels is the header of the following lines
+			if len(fields) > 0 {
+				p.header = fields
+			}
+			return nil
+		}
+		for i, value := range values {
+			name := strconv.Itoa(i + 1)
+			if i < len(p.header) {
+				name = p.header[i]
+			}
+			res = append(res, channel{Name: name, Value: value})
+		}
+	}
+	if len(res) == 0 {
+		return nil
+	}
+	return res
+}
+
+// PlotterWriter extracts the numeric channels from the lines received from
+// a monitor and writes them to the underlying writer, as NDJSON or as a
+// sparkline view redrawn at each line.
+type PlotterWriter struct {
+	out       io.Writer
+	parser    channelParser
+	sparkline bool
+	width     int
+	now       func() time.Time
+	pending   []byte
+	names     []string
+	history   map[string][]float64
+	drawn     int
+}
+
+// NewPlotterWriter returns a PlotterWriter writing to out. In sparkline
+// mode the last width values of each channel are shown.
+func NewPlotterWriter(out io.Writer, format ChannelFormat, sparkline bool, width int) *PlotterWriter {
+	return &PlotterWriter{
+		out:       out,
+		parser:    channelParser{format: format},
+		sparkline: sparkline,
+		width:     width,
+		now:       time.Now,
+		history:   map[string][]float64{},
+	}
+}
+
+// plotterEvent is the NDJSON record written for each line with channels
+type plotterEvent struct {
+	Time     string             `json:"time"`
+	Channels map[string]float64 `json:"channels"`
+}
+
+// Write parses the complete lines of data, an incomplete line is kept
+// until the rest of it is received
+func (w *PlotterWriter) Write(data []byte) (int, error) {
+	w.pending = append(w.pending, data...)
+	for {
+		i := bytes.IndexByte(w.pending, '\n')
+		if i == -1 {
+			return len(data), nil
+		}
+		line := strings.TrimRight(string(w.pending[:i]), "\r")
+		w.pending = w.pending[i+1:]
+		channels := w.parser.parse(line)
+		if channels == nil {
+			continue
+		}
+		var err error
+		if w.sparkline {
+			err = w.drawSparklines(channels)
+		} else {
+			err = w.writeEvent(channels)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+}
+
+func (w *PlotterWriter) writeEvent(channels []channel) error {
+	event := plotterEvent{
+		Time:     w.now().Format(time.RFC3339Nano),
+		Channels: map[string]float64{},
+	}
+	for _, c := range channels {
+		event.Channels[c.Name] = c.Value
+	}
+	d, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = w.out.Write(append(d, '\n'))
+	return err
+}
+
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+func (w *PlotterWriter) drawSparklines(channels []channel) error {
+	for _, c := range channels {
+		values, ok := w.history[c.Name]
+		if !ok {
+			w.names = append(w.names, c.Name)
+		}
+		values = append(values, c.Value)
+		if len(values) > w.width {
+			values = values[len(values)-w.width:]
+		}
+		w.history[c.Name] = values
+	}
+
+	nameWidth := 0
+	for _, name := range w.names {
+		if len(name) > nameWidth {
+			nameWidth = len(name)
+		}
+	}
+	var buf bytes.Buffer
+	if w.drawn > 0 {
+		// move back to the first line of the previous view
+		fmt.Fprintf(&buf, "\x1b[%dA", w.drawn)
+	}
+	for _, name := range w.names {
+		values := w.history[name]
+		fmt.Fprintf(&buf, "\r\x1b[K%-*s %s %g\n", nameWidth, name, sparkline(values), values[len(values)-1])
+	}
+	w.drawn = len(w.names)
+	_, err := w.out.Write(buf.Bytes())
+	return err
+}
+
+// sparkline renders values scaled between their minimum and maximum
+func sparkline(values []float64) string {
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+	}
+	res := make([]rune, len(values))
+	for i, v := range values {
+		level := 0
+		if max > min {
+			level = int((v - min) / (max - min) * float64(len(sparks)-1))
+		}
+		res[i] = sparks[level]
+	}
+	return string(res)
+}
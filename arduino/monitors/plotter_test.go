@@ -0,0 +1,58 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package monitors
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlotterWriterCSV(t *testing.T) {
+	var out bytes.Buffer
+	w := NewPlotterWriter(&out, ChannelFormatCSV, false, 0)
+	w.now = func() time.Time { return time.Date(2021, 5, 10, 12, 30, 15, 0, time.UTC) }
+	w.Write([]byte("1,2.5\r\n3"))
+	w.Write([]byte(" 4\nboot ok!\n"))
+	w.Write([]byte("temp\thum\n21.5\t40\n"))
+	require.Equal(t, ""+
+		`{"time":"2021-05-10T12:30:15Z","channels":{"1":1,"2":2.5}}`+"\n"+
+		`{"time":"2021-05-10T12:30:15Z","channels":{"1":3,"2":4}}`+"\n"+
+		`{"time":"2021-05-10T12:30:15Z","channels":{"hum":40,"temp":21.5}}`+"\n", out.String())
+}
+
+func TestPlotterWriterKeyValue(t *testing.T) {
+	p := channelParser{format: ChannelFormatKeyValue}
+	require.Equal(t, []channel{{"temp", 21.5}, {"hum", 40}}, p.parse("temp=21.5, hum:40 status=ok"))
+	require.Nil(t, p.parse("booting..."))
+
+	_, err := ParseChannelFormat("json")
+	require.Error(t, err)
+}
+
+func TestSparkline(t *testing.T) {
+	require.Equal(t, "▁▄█▁", sparkline([]float64{0, 5, 10, 0}))
+	require.Equal(t, "▁▁", sparkline([]float64{3, 3}))
+
+	var out bytes.Buffer
+	w := NewPlotterWriter(&out, ChannelFormatKeyValue, true, 3)
+	w.Write([]byte("a=1 b=2\na=2 b=2\n"))
+	require.Equal(t, ""+
+		"\r\x1b[Ka ▁ 1\n\r\x1b[Kb ▁ 2\n"+
+		"\x1b[2A\r\x1b[Ka ▁█ 2\n\r\x1b[Kb ▁▁ 2\n", out.String())
+}
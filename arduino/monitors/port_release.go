@@ -0,0 +1,107 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package monitors
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/sirupsen/logrus"
+)
+
+// The monitors and the uploads running in different processes coordinate
+// the use of a port through marker files in a shared temporary directory:
+// - <port>.monitor is created by a monitor while it's using the port
+// - <port>.release is created by an upload to ask the monitor to close the
+//   port, and removed when the upload is done to let the monitor reopen it
+// - <port>.released is created by the monitor when the port has been closed
+
+var portNameSanitizer = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+func portMarker(port, kind string) *paths.Path {
+	return paths.TempDir().Join("arduino-cli-monitors", portNameSanitizer.ReplaceAllString(port, "_")+"."+kind)
+}
+
+// portRegistration marks a port as used by a monitor of this process
+type portRegistration struct {
+	port string
+}
+
+func registerPort(port string) (*portRegistration, error) {
+	marker := portMarker(port, "monitor")
+	if err := marker.Parent().MkdirAll(); err != nil {
+		return nil, err
+	}
+	// remove leftovers of previous sessions
+	_ = portMarker(port, "released").Remove()
+	if err := marker.WriteFile([]byte(fmt.Sprint(os.Getpid()))); err != nil {
+		return nil, err
+	}
+	return &portRegistration{port: port}, nil
+}
+
+func (r *portRegistration) releaseRequested() bool {
+	return portMarker(r.port, "release").Exist()
+}
+
+func (r *portRegistration) setReleased(released bool) {
+	marker := portMarker(r.port, "released")
+	if released {
+		_ = marker.WriteFile([]byte{})
+	} else {
+		_ = marker.Remove()
+	}
+}
+
+func (r *portRegistration) close() {
+	_ = portMarker(r.port, "monitor").Remove()
+	_ = portMarker(r.port, "released").Remove()
+}
+
+// ReleasePort asks the monitor that is using the port, if any, to close it
+// and waits until the port is released or the timeout expires. The returned
+// function must be called when the port is no longer needed, to let the
+// monitor reopen it.
+func ReleasePort(port string, timeout time.Duration) func() {
+	if !portMarker(port, "monitor").Exist() {
+		return func() {}
+	}
+	release := portMarker(port, "release")
+	if err := release.WriteFile([]byte(fmt.Sprint(os.Getpid()))); err != nil {
+		logrus.WithError(err).Warnf("Cannot ask the monitor to release port %s", port)
+		return func() {}
+	}
+	reattach := func() { _ = release.Remove() }
+
+	released := portMarker(port, "released")
+	deadline := time.Now().Add(timeout)
+	for !released.Exist() {
+		if time.Now().After(deadline) {
+			// the monitor is not responding, probably it was killed
+			// without removing its marker
+			logrus.Warnf("The monitor on port %s didn't release it", port)
+			_ = portMarker(port, "monitor").Remove()
+			reattach()
+			return func() {}
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	logrus.Infof("Port %s released by the monitor", port)
+	return reattach
+}
@@ -0,0 +1,193 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package monitors
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// reconnectInterval is the delay between the attempts to reopen a port
+var reconnectInterval = 200 * time.Millisecond
+
+// ReconnectingMonitor is a monitor that reopens its port when it's closed,
+// e.g. because the board has been reset and its USB port re-enumerated. The
+// port is also released while an upload is using it, see ReleasePort.
+type ReconnectingMonitor struct {
+	port     string
+	open     func() (Monitor, error)
+	timeout  time.Duration
+	interval time.Duration
+	reg      *portRegistration
+
+	mu      sync.Mutex
+	current Monitor
+	paused  bool
+	closed  bool
+	done    chan bool
+}
+
+// NewReconnectingMonitor opens a monitor on port using the open function.
+// When the port is lost it's reopened, waiting up to timeout for it to
+// come back (a timeout of 0 waits forever).
+func NewReconnectingMonitor(port string, open func() (Monitor, error), timeout time.Duration) (*ReconnectingMonitor, error) {
+	mon, err := open()
+	if err != nil {
+		return nil, err
+	}
+	reg, err := registerPort(port)
+	if err != nil {
+		mon.Close()
+		return nil, errors.Wrap(err, "registering monitor")
+	}
+	r := &ReconnectingMonitor{
+		port:     port,
+		open:     open,
+		timeout:  timeout,
+		interval: reconnectInterval,
+		reg:      reg,
+		current:  mon,
+		done:     make(chan bool),
+	}
+	go r.watchReleaseRequests()
+	return r, nil
+}
+
+// watchReleaseRequests closes the port when an upload asks for it, and
+// lets Read reopen it when the upload is done
+func (r *ReconnectingMonitor) watchReleaseRequests() {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.done:
+			return
+		case <-ticker.C:
+		}
+		requested := r.reg.releaseRequested()
+		r.mu.Lock()
+		if requested && !r.paused {
+			logrus.Infof("Releasing port %s for the upload", r.port)
+			r.paused = true
+			if r.current != nil {
+				r.current.Close()
+				r.current = nil
+			}
+			r.reg.setReleased(true)
+		} else if !requested && r.paused {
+			r.paused = false
+			r.reg.setReleased(false)
+		}
+		r.mu.Unlock()
+	}
+}
+
+// connected returns the current monitor, reopening the port if needed
+func (r *ReconnectingMonitor) connected() (Monitor, error) {
+	start := time.Now()
+	for {
+		r.mu.Lock()
+		if r.closed {
+			r.mu.Unlock()
+			return nil, io.EOF
+		}
+		if r.current != nil {
+			mon := r.current
+			r.mu.Unlock()
+			return mon, nil
+		}
+		if !r.paused {
+			if mon, err := r.open(); err == nil {
+				logrus.Infof("Port %s reopened", r.port)
+				r.current = mon
+				r.mu.Unlock()
+				return mon, nil
+			}
+			if r.timeout > 0 && time.Since(start) > r.timeout {
+				r.mu.Unlock()
+				return nil, errors.Errorf("port %s didn't come back in %s", r.port, r.timeout)
+			}
+		} else {
+			// the time spent waiting for an upload doesn't count
+			start = time.Now()
+		}
+		r.mu.Unlock()
+		time.Sleep(r.interval)
+	}
+}
+
+// Read bytes from the port, reopening it if it has been closed
+func (r *ReconnectingMonitor) Read(data []byte) (int, error) {
+	for {
+		mon, err := r.connected()
+		if err != nil {
+			return 0, err
+		}
+		n, err := mon.Read(data)
+		if n > 0 {
+			return n, nil
+		}
+		r.mu.Lock()
+		if r.current == mon {
+			logrus.WithError(err).Infof("Port %s lost, reconnecting", r.port)
+			mon.Close()
+			r.current = nil
+		}
+		r.mu.Unlock()
+	}
+}
+
+// Write bytes to the port, the data is discarded while the port is not
+// connected
+func (r *ReconnectingMonitor) Write(data []byte) (int, error) {
+	r.mu.Lock()
+	mon, closed := r.current, r.closed
+	r.mu.Unlock()
+	if closed {
+		return 0, errors.New("monitor closed")
+	}
+	if mon == nil {
+		logrus.Warnf("Port %s not connected, %d bytes discarded", r.port, len(data))
+		return len(data), nil
+	}
+	if _, err := mon.Write(data); err != nil {
+		// the port has been lost, Read will reopen it
+		logrus.WithError(err).Warnf("Port %s not connected, %d bytes discarded", r.port, len(data))
+	}
+	return len(data), nil
+}
+
+// Close the port and stop reconnecting
+func (r *ReconnectingMonitor) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+	close(r.done)
+	r.reg.close()
+	if r.current != nil {
+		err := r.current.Close()
+		r.current = nil
+		return err
+	}
+	return nil
+}
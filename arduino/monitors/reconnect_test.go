@@ -0,0 +1,96 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package monitors
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconnectingMonitor(t *testing.T) {
+	reconnectInterval = 10 * time.Millisecond
+	port := fmt.Sprintf("/dev/test-reconnect-%d", os.Getpid())
+
+	opened := 0
+	open := func() (Monitor, error) {
+		opened++
+		if opened == 2 {
+			// the port is not back yet
+			return nil, fmt.Errorf("port not found")
+		}
+		return &fakeMonitor{in: bytes.NewBufferString(fmt.Sprintf("session %d\n", opened))}, nil
+	}
+	mon, err := NewReconnectingMonitor(port, open, time.Second)
+	require.NoError(t, err)
+	require.True(t, portMarker(port, "monitor").Exist())
+
+	buf := make([]byte, 100)
+	n, err := mon.Read(buf)
+	require.NoError(t, err)
+	require.Equal(t, "session 1\n", string(buf[:n]))
+	// the first session ends, the port is reopened at the third attempt
+	n, err = mon.Read(buf)
+	require.NoError(t, err)
+	require.Equal(t, "session 3\n", string(buf[:n]))
+
+	require.NoError(t, mon.Close())
+	require.False(t, portMarker(port, "monitor").Exist())
+	_, err = mon.Read(buf)
+	require.Error(t, err)
+}
+
+func TestReleasePort(t *testing.T) {
+	reconnectInterval = 10 * time.Millisecond
+	port := fmt.Sprintf("/dev/test-release-%d", os.Getpid())
+
+	// no monitor on the port
+	ReleasePort(port, time.Second)()
+
+	fake := &fakeMonitor{in: &bytes.Buffer{}}
+	mon, err := NewReconnectingMonitor(port, func() (Monitor, error) { return fake, nil }, 0)
+	require.NoError(t, err)
+	defer mon.Close()
+
+	reattach := ReleasePort(port, time.Second)
+	mon.mu.Lock()
+	require.True(t, mon.paused)
+	require.Nil(t, mon.current)
+	mon.mu.Unlock()
+	// the data sent while the port is released is discarded
+	_, err = mon.Write([]byte("lost"))
+	require.NoError(t, err)
+
+	reattach()
+	fake.in = bytes.NewBufferString("back\n")
+	buf := make([]byte, 100)
+	n, err := mon.Read(buf)
+	require.NoError(t, err)
+	require.Equal(t, "back\n", string(buf[:n]))
+	require.Empty(t, fake.out.String())
+
+	// a stale marker of a killed monitor doesn't block the upload
+	stale := fmt.Sprintf("/dev/test-stale-%d", os.Getpid())
+	require.NoError(t, ioutil.WriteFile(portMarker(stale, "monitor").String(), []byte("1"), 0644))
+	ReleasePort(stale, 100*time.Millisecond)()
+	require.False(t, portMarker(stale, "monitor").Exist())
+	require.False(t, portMarker(stale, "release").Exist())
+}
@@ -0,0 +1,295 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package monitors
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// defaultScriptTimeout is the timeout of the expect steps of a script that
+// doesn't set one
+const defaultScriptTimeout = 10 * time.Second
+
+// Script is a sequence of steps that interact with the board through a
+// monitor, e.g.:
+//
+//	name: smoke test
+//	timeout: 5s
+//	fail_on: ["Guru Meditation"]
+//	steps:
+//	  - expect: "Ready"
+//	  - send: "ping\n"
+//	  - expect_regex: "pong [0-9]+"
+//	    timeout: 1s
+//	  - sleep: 500ms
+type Script struct {
+	Name string `yaml:"name"`
+	// Timeout is the default timeout of the expect steps
+	Timeout string `yaml:"timeout"`
+	// FailOn are texts that make the script fail as soon as they are
+	// received from the board
+	FailOn []string      `yaml:"fail_on"`
+	Steps  []*ScriptStep `yaml:"steps"`
+
+	timeout time.Duration
+}
+
+// ScriptStep is a step of a Script, only one of the actions must be set
+type ScriptStep struct {
+	Send        string `yaml:"send"`
+	Expect      string `yaml:"expect"`
+	ExpectRegex string `yaml:"expect_regex"`
+	Sleep       string `yaml:"sleep"`
+	Timeout     string `yaml:"timeout"`
+
+	action  string
+	value   string
+	regex   *regexp.Regexp
+	timeout time.Duration
+}
+
+// LoadScript reads and validates a script file
+func LoadScript(file *paths.Path) (*Script, error) {
+	data, err := file.ReadFile()
+	if err != nil {
+		return nil, errors.Wrap(err, "reading script")
+	}
+	script := &Script{}
+	if err := yaml.UnmarshalStrict(data, script); err != nil {
+		return nil, errors.Wrap(err, "parsing script")
+	}
+	if err := script.validate(); err != nil {
+		return nil, err
+	}
+	return script, nil
+}
+
+func parseScriptDuration(value string, def time.Duration) (time.Duration, error) {
+	if value == "" {
+		return def, nil
+	}
+	return time.ParseDuration(value)
+}
+
+func (s *Script) validate() error {
+	timeout, err := parseScriptDuration(s.Timeout, defaultScriptTimeout)
+	if err != nil {
+		return errors.Wrap(err, "invalid script timeout")
+	}
+	s.timeout = timeout
+	if len(s.Steps) == 0 {
+		return errors.New("the script has no steps")
+	}
+	for i, step := range s.Steps {
+		actions := 0
+		if step.Send != "" {
+			step.action, step.value = "send", step.Send
+			actions++
+		}
+		if step.Expect != "" {
+			step.action, step.value = "expect", step.Expect
+			actions++
+		}
+		if step.ExpectRegex != "" {
+			step.action, step.value = "expect_regex", step.ExpectRegex
+			step.regex, err = regexp.Compile(step.ExpectRegex)
+			if err != nil {
+				return errors.Wrapf(err, "step %d: invalid regular expression", i+1)
+			}
+			actions++
+		}
+		if step.Sleep != "" {
+			step.action, step.value = "sleep", step.Sleep
+			if _, err := time.ParseDuration(step.Sleep); err != nil {
+				return errors.Wrapf(err, "step %d: invalid sleep", i+1)
+			}
+			actions++
+		}
+		if actions != 1 {
+			return errors.Errorf("step %d: exactly one of send, expect, expect_regex or sleep must be set", i+1)
+		}
+		if step.timeout, err = parseScriptDuration(step.Timeout, s.timeout); err != nil {
+			return errors.Wrapf(err, "step %d: invalid timeout", i+1)
+		}
+	}
+	return nil
+}
+
+// ScriptResult is the outcome of a script
+type ScriptResult struct {
+	Name   string              `json:"name,omitempty"`
+	Passed bool                `json:"passed"`
+	Steps  []*ScriptStepResult `json:"steps"`
+	// Error is the reason of the failure of the script
+	Error string `json:"error,omitempty"`
+}
+
+// ScriptStepResult is the outcome of a step of a script
+type ScriptStepResult struct {
+	Action     string `json:"action"`
+	Value      string `json:"value"`
+	Passed     bool   `json:"passed"`
+	DurationMs int64  `json:"duration_ms"`
+	// Match is the text that satisfied an expect step
+	Match string `json:"match,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// scriptRunner holds the data received from the board that has not been
+// consumed by an expect step yet
+type scriptRunner struct {
+	script   *Script
+	chunks   chan []byte
+	readErr  chan error
+	pending  string
+	received string
+}
+
+// RunScript runs the script on mon. The data received from the board is
+// also copied to out. The steps are run until one fails, the remaining
+// steps are not reported in the result.
+func RunScript(mon Monitor, script *Script, out io.Writer) *ScriptResult {
+	r := &scriptRunner{
+		script:  script,
+		chunks:  make(chan []byte, 16),
+		readErr: make(chan error, 1),
+	}
+	go func() {
+		for {
+			buf := make([]byte, 1024)
+			n, err := mon.Read(buf)
+			if n > 0 {
+				out.Write(buf[:n])
+				r.chunks <- buf[:n]
+			}
+			if err != nil || n == 0 {
+				if err == nil {
+					err = io.EOF
+				}
+				r.readErr <- err
+				return
+			}
+		}
+	}()
+
+	res := &ScriptResult{Name: script.Name, Steps: []*ScriptStepResult{}}
+	for i, step := range script.Steps {
+		stepRes := &ScriptStepResult{Action: step.action, Value: step.value}
+		res.Steps = append(res.Steps, stepRes)
+		start := time.Now()
+		err := r.runStep(mon, step, stepRes)
+		stepRes.DurationMs = time.Since(start).Milliseconds()
+		if err != nil {
+			stepRes.Error = err.Error()
+			res.Error = fmt.Sprintf("step %d (%s %q) failed: %s", i+1, step.action, step.value, err)
+			return res
+		}
+		stepRes.Passed = true
+	}
+	res.Passed = true
+	return res
+}
+
+func (r *scriptRunner) runStep(mon Monitor, step *ScriptStep, res *ScriptStepResult) error {
+	switch step.action {
+	case "send":
+		_, err := mon.Write([]byte(step.value))
+		return err
+	case "sleep":
+		// keep receiving, to catch the fail_on texts
+		d, _ := time.ParseDuration(step.value)
+		deadline := time.After(d)
+		for {
+			if timedOut, err := r.receive(deadline); err != nil || timedOut {
+				return err
+			}
+		}
+	}
+
+	deadline := time.After(step.timeout)
+	for {
+		if match, end := r.match(step); end >= 0 {
+			res.Match = match
+			r.pending = r.pending[end:]
+			return nil
+		}
+		timedOut, err := r.receive(deadline)
+		if err != nil {
+			return err
+		}
+		if timedOut {
+			return errors.Errorf("timeout after %s", step.timeout)
+		}
+	}
+}
+
+// match looks for the expected text in the pending data, it returns the
+// match and its end or -1 if not found
+func (r *scriptRunner) match(step *ScriptStep) (string, int) {
+	if step.regex != nil {
+		loc := step.regex.FindStringIndex(r.pending)
+		if loc == nil {
+			return "", -1
+		}
+		return r.pending[loc[0]:loc[1]], loc[1]
+	}
+	if i := strings.Index(r.pending, step.value); i >= 0 {
+		return step.value, i + len(step.value)
+	}
+	return "", -1
+}
+
+// receive waits for new data from the board or for the deadline, it fails
+// if the board stops responding or sends one of the fail_on texts
+func (r *scriptRunner) receive(deadline <-chan time.Time) (bool, error) {
+	select {
+	case chunk := <-r.chunks:
+		r.pending += string(chunk)
+		// the fail_on texts may be split between chunks, keep the tail
+		// of the data already checked
+		r.received += string(chunk)
+		longest := 0
+		for _, failOn := range r.script.FailOn {
+			if strings.Contains(r.received, failOn) {
+				return false, errors.Errorf("received %q", failOn)
+			}
+			if len(failOn) > longest {
+				longest = len(failOn)
+			}
+		}
+		if len(r.received) > longest {
+			r.received = r.received[len(r.received)-longest:]
+		}
+		return false, nil
+	case err := <-r.readErr:
+		r.readErr <- err
+		if len(r.chunks) > 0 {
+			// process the data received before the error first
+			return false, nil
+		}
+		return false, errors.Wrap(err, "reading from monitor")
+	case <-deadline:
+		return true, nil
+	}
+}
@@ -0,0 +1,118 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package monitors
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+// echoBoard answers "pong" to each "ping" received
+type echoBoard struct {
+	mu    sync.Mutex
+	in    *io.PipeReader
+	inW   *io.PipeWriter
+	sent  bytes.Buffer
+	reply func(cmd string) string
+}
+
+func newEchoBoard(banner string, reply func(string) string) *echoBoard {
+	r, w := io.Pipe()
+	b := &echoBoard{in: r, inW: w, reply: reply}
+	go w.Write([]byte(banner))
+	return b
+}
+
+func (b *echoBoard) Read(p []byte) (int, error) { return b.in.Read(p) }
+func (b *echoBoard) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	b.sent.Write(p)
+	b.mu.Unlock()
+	go b.inW.Write([]byte(b.reply(string(p))))
+	return len(p), nil
+}
+func (b *echoBoard) Close() error { return b.inW.Close() }
+
+func loadTestScript(t *testing.T, script string) *Script {
+	tmp, err := paths.MkTempDir("", "monitor_script")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+	file := tmp.Join("script.yaml")
+	require.NoError(t, ioutil.WriteFile(file.String(), []byte(script), 0644))
+	s, err := LoadScript(file)
+	require.NoError(t, err)
+	return s
+}
+
+func TestRunScript(t *testing.T) {
+	script := loadTestScript(t, `
+name: smoke
+timeout: 1s
+fail_on: ["PANIC"]
+steps:
+  - expect: "Ready"
+  - send: "ping\n"
+  - expect_regex: "pong [0-9]+"
+  - sleep: 10ms
+`)
+	board := newEchoBoard("booting...\nReady\n", func(cmd string) string { return "pong 42\n" })
+	var out bytes.Buffer
+	res := RunScript(board, script, &out)
+	require.True(t, res.Passed, res.Error)
+	require.Len(t, res.Steps, 4)
+	require.Equal(t, "pong 42", res.Steps[2].Match)
+	require.Equal(t, "ping\n", board.sent.String())
+
+	// a fail_on text fails the script
+	board = newEchoBoard("Ready\n", func(cmd string) string { return "PANIC: stack overflow\n" })
+	res = RunScript(board, script, ioutil.Discard)
+	require.False(t, res.Passed)
+	require.Len(t, res.Steps, 3)
+	require.Equal(t, `step 3 (expect_regex "pong [0-9]+") failed: received "PANIC"`, res.Error)
+
+	// an expect times out
+	script.Steps[0].timeout = 50 * time.Millisecond
+	board = newEchoBoard("booting...\n", func(cmd string) string { return "" })
+	res = RunScript(board, script, ioutil.Discard)
+	require.False(t, res.Passed)
+	require.True(t, strings.HasSuffix(res.Error, "timeout after 50ms"), res.Error)
+}
+
+func TestLoadScriptErrors(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "monitor_script")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+	file := tmp.Join("script.yaml")
+
+	load := func(script string) error {
+		require.NoError(t, ioutil.WriteFile(file.String(), []byte(script), 0644))
+		_, err := LoadScript(file)
+		return err
+	}
+	require.EqualError(t, load("steps: []"), "the script has no steps")
+	require.EqualError(t, load("steps:\n  - send: a\n    expect: b"), "step 1: exactly one of send, expect, expect_regex or sleep must be set")
+	require.Error(t, load("steps:\n  - expect_regex: '('"))
+	require.Error(t, load("steps:\n  - expect: a\n    timeout: soon"))
+	require.Error(t, load("steps:\n  - wait: 1s"))
+}
@@ -0,0 +1,230 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package monitors
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/pkg/errors"
+)
+
+// sessionHeader identifies a session file and the version of its format
+const sessionHeader = "ARDUINO-MONITOR-SESSION 1\n"
+
+// maxSessionEventSize is the maximum size of the data of an event, the larger
+// chunks of data are recorded in multiple events
+const maxSessionEventSize = 1 << 20
+
+// SessionDirection is the direction of the data in a SessionEvent
+type SessionDirection byte
+
+const (
+	// SessionReceived is the data received from the device
+	SessionReceived SessionDirection = '<'
+	// SessionSent is the data sent to the device
+	SessionSent SessionDirection = '>'
+)
+
+// SessionEvent is a chunk of data recorded in a session
+type SessionEvent struct {
+	// Time elapsed from the start of the session
+	Time      time.Duration
+	Direction SessionDirection
+	Data      []byte
+}
+
+// SessionRecorder is a Monitor that records the traffic of another Monitor,
+// with the time of each read and write, so it can be replayed later.
+// Each event is stored as: the direction byte, the time from the start of the
+// session in microseconds and the length of the data as uvarints, and the
+// data.
+type SessionRecorder struct {
+	mon   Monitor
+	out   io.WriteCloser
+	start time.Time
+	lock  sync.Mutex
+}
+
+// NewSessionRecorder records the traffic of the monitor in out
+func NewSessionRecorder(mon Monitor, out io.WriteCloser) (*SessionRecorder, error) {
+	if _, err := io.WriteString(out, sessionHeader); err != nil {
+		return nil, errors.Wrap(err, "writing session header")
+	}
+	return &SessionRecorder{mon: mon, out: out, start: time.Now()}, nil
+}
+
+func (r *SessionRecorder) record(direction SessionDirection, data []byte) error {
+	for len(data) > maxSessionEventSize {
+		if err := r.recordEvent(direction, data[:maxSessionEventSize]); err != nil {
+			return err
+		}
+		data = data[maxSessionEventSize:]
+	}
+	return r.recordEvent(direction, data)
+}
+
+func (r *SessionRecorder) recordEvent(direction SessionDirection, data []byte) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	buf := make([]byte, 1+2*binary.MaxVarintLen64, 1+2*binary.MaxVarintLen64+len(data))
+	buf[0] = byte(direction)
+	n := 1
+	n += binary.PutUvarint(buf[n:], uint64(time.Since(r.start).Microseconds()))
+	n += binary.PutUvarint(buf[n:], uint64(len(data)))
+	// Write the whole event at once to keep the file consistent if the
+	// session is interrupted
+	_, err := r.out.Write(append(buf[:n], data...))
+	return err
+}
+
+// Read bytes from the monitor and record them
+func (r *SessionRecorder) Read(bytes []byte) (int, error) {
+	n, err := r.mon.Read(bytes)
+	if n > 0 {
+		if recErr := r.record(SessionReceived, bytes[:n]); recErr != nil {
+			return n, errors.Wrap(recErr, "recording session")
+		}
+	}
+	return n, err
+}
+
+// Write bytes to the monitor and record them
+func (r *SessionRecorder) Write(bytes []byte) (int, error) {
+	n, err := r.mon.Write(bytes)
+	if n > 0 {
+		if recErr := r.record(SessionSent, bytes[:n]); recErr != nil {
+			return n, errors.Wrap(recErr, "recording session")
+		}
+	}
+	return n, err
+}
+
+// Close the monitor and the session file
+func (r *SessionRecorder) Close() error {
+	err := r.mon.Close()
+	if closeErr := r.out.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// SessionReader reads the events of a session recorded by a SessionRecorder
+type SessionReader struct {
+	in *bufio.Reader
+}
+
+// NewSessionReader returns a SessionReader reading from in
+func NewSessionReader(in io.Reader) (*SessionReader, error) {
+	r := bufio.NewReader(in)
+	header := make([]byte, len(sessionHeader))
+	if _, err := io.ReadFull(r, header); err != nil || string(header) != sessionHeader {
+		return nil, errors.New("invalid monitor session file")
+	}
+	return &SessionReader{in: r}, nil
+}
+
+// Next returns the next event of the session, or io.EOF at the end of the
+// session
+func (r *SessionReader) Next() (*SessionEvent, error) {
+	direction, err := r.in.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if direction != byte(SessionReceived) && direction != byte(SessionSent) {
+		return nil, fmt.Errorf("invalid monitor session event: %q", direction)
+	}
+	micros, err := binary.ReadUvarint(r.in)
+	if err != nil {
+		return nil, errors.Wrap(io.ErrUnexpectedEOF, "reading session event")
+	}
+	size, err := binary.ReadUvarint(r.in)
+	if err != nil {
+		return nil, errors.Wrap(io.ErrUnexpectedEOF, "reading session event")
+	}
+	// The size is checked before allocating the data, to not trust the
+	// length read from a corrupted or malicious file
+	if size > maxSessionEventSize {
+		return nil, fmt.Errorf("invalid monitor session event: size %d exceeds the maximum of %d bytes", size, maxSessionEventSize)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r.in, data); err != nil {
+		return nil, errors.Wrap(io.ErrUnexpectedEOF, "reading session event")
+	}
+	return &SessionEvent{
+		Time:      time.Duration(micros) * time.Microsecond,
+		Direction: SessionDirection(direction),
+		Data:      data,
+	}, nil
+}
+
+// SessionReplay is a Monitor that replays the data received from the device
+// in a recorded session, with the original timing. The data written to it is
+// discarded. Read returns io.EOF at the end of the session.
+type SessionReplay struct {
+	reader  *SessionReader
+	closer  io.Closer
+	start   time.Time
+	pending []byte
+}
+
+// OpenSessionReplay opens a session file recorded by a SessionRecorder
+func OpenSessionReplay(file *paths.Path) (*SessionReplay, error) {
+	f, err := file.Open()
+	if err != nil {
+		return nil, errors.Wrap(err, "opening monitor session")
+	}
+	reader, err := NewSessionReader(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &SessionReplay{reader: reader, closer: f, start: time.Now()}, nil
+}
+
+// Read the data received from the device, waiting to respect the timing of
+// the recorded session
+func (r *SessionReplay) Read(bytes []byte) (int, error) {
+	for len(r.pending) == 0 {
+		event, err := r.reader.Next()
+		if err != nil {
+			return 0, err
+		}
+		if event.Direction != SessionReceived {
+			continue
+		}
+		time.Sleep(time.Until(r.start.Add(event.Time)))
+		r.pending = event.Data
+	}
+	n := copy(bytes, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// Write discards the data, the replayed session can't react to it
+func (r *SessionReplay) Write(bytes []byte) (int, error) {
+	return len(bytes), nil
+}
+
+// Close the session file
+func (r *SessionReplay) Close() error {
+	return r.closer.Close()
+}
@@ -0,0 +1,129 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package monitors
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMonitor struct {
+	in  *bytes.Buffer
+	out bytes.Buffer
+}
+
+func (m *fakeMonitor) Read(b []byte) (int, error)  { return m.in.Read(b) }
+func (m *fakeMonitor) Write(b []byte) (int, error) { return m.out.Write(b) }
+func (m *fakeMonitor) Close() error                { return nil }
+
+func TestSessionRecordAndReplay(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "monitor_session")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+	sessionFile := tmp.Join("session.bin")
+
+	out, err := sessionFile.Create()
+	require.NoError(t, err)
+	mon := &fakeMonitor{in: bytes.NewBufferString("hello\r\nworld\r\n")}
+	rec, err := NewSessionRecorder(mon, out)
+	require.NoError(t, err)
+	buf := make([]byte, 7)
+	n, err := rec.Read(buf)
+	require.NoError(t, err)
+	require.Equal(t, "hello\r\n", string(buf[:n]))
+	_, err = rec.Write([]byte("cmd\n"))
+	require.NoError(t, err)
+	time.Sleep(50 * time.Millisecond)
+	n, err = rec.Read(buf)
+	require.NoError(t, err)
+	require.Equal(t, "world\r\n", string(buf[:n]))
+	require.NoError(t, rec.Close())
+	require.Equal(t, "cmd\n", mon.out.String())
+
+	f, err := sessionFile.Open()
+	require.NoError(t, err)
+	reader, err := NewSessionReader(f)
+	require.NoError(t, err)
+	events := []*SessionEvent{}
+	for {
+		ev, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		events = append(events, ev)
+	}
+	f.Close()
+	require.Len(t, events, 3)
+	require.Equal(t, SessionReceived, events[0].Direction)
+	require.Equal(t, SessionSent, events[1].Direction)
+	require.Equal(t, "cmd\n", string(events[1].Data))
+	require.GreaterOrEqual(t, int64(events[2].Time), int64(50*time.Millisecond))
+
+	replay, err := OpenSessionReplay(sessionFile)
+	require.NoError(t, err)
+	defer replay.Close()
+	start := time.Now()
+	data, err := ioutil.ReadAll(replay)
+	require.NoError(t, err)
+	require.Equal(t, "hello\r\nworld\r\n", string(data))
+	require.GreaterOrEqual(t, int64(time.Since(start)), int64(50*time.Millisecond))
+}
+
+func TestInvalidSession(t *testing.T) {
+	_, err := NewSessionReader(bytes.NewBufferString("not a session"))
+	require.Error(t, err)
+
+	reader, err := NewSessionReader(bytes.NewBufferString(sessionHeader + "<\x01\x05ab"))
+	require.NoError(t, err)
+	_, err = reader.Next()
+	require.Error(t, err)
+}
+
+func TestSessionEventSize(t *testing.T) {
+	// A corrupted size is rejected without allocating it
+	reader, err := NewSessionReader(bytes.NewBufferString(sessionHeader + "<\x01\xff\xff\xff\xff\xff\xff\xff\xff\x7f"))
+	require.NoError(t, err)
+	_, err = reader.Next()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "exceeds the maximum")
+
+	// The data larger than the maximum is recorded in multiple events
+	var out bytes.Buffer
+	rec, err := NewSessionRecorder(&fakeMonitor{}, nopWriteCloser{&out})
+	require.NoError(t, err)
+	data := bytes.Repeat([]byte{'x'}, maxSessionEventSize+10)
+	_, err = rec.Write(data)
+	require.NoError(t, err)
+	reader, err = NewSessionReader(&out)
+	require.NoError(t, err)
+	first, err := reader.Next()
+	require.NoError(t, err)
+	require.Len(t, first.Data, maxSessionEventSize)
+	second, err := reader.Next()
+	require.NoError(t, err)
+	require.Len(t, second.Data, 10)
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
@@ -0,0 +1,157 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package partitions
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	paths "github.com/arduino/go-paths-helper"
+)
+
+// Partition is an entry of an ESP32 partitions table
+type Partition struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	SubType string `json:"subtype"`
+	Offset  uint64 `json:"offset"`
+	Size    uint64 `json:"size"`
+}
+
+// End returns the offset of the first byte after the partition
+func (p *Partition) End() uint64 {
+	return p.Offset + p.Size
+}
+
+const (
+	// The partitions table is stored at 0x8000, the first partition follows it
+	firstPartitionOffset = 0x9000
+	// App partitions must be aligned to 64K
+	appAlignment = 0x10000
+)
+
+// Parse parses a partitions table in the CSV format used by the ESP32
+// platforms. Missing offsets are computed from the end of the previous
+// partition, like the gen_esp32part tool does.
+func Parse(data string) ([]*Partition, error) {
+	res := []*Partition{}
+	next := uint64(firstPartitionOffset)
+	for i, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, ",")
+		if len(fields) < 5 {
+			return nil, fmt.Errorf("line %d: invalid partition entry: %s", i+1, line)
+		}
+		for j := range fields {
+			fields[j] = strings.TrimSpace(fields[j])
+		}
+		part := &Partition{Name: fields[0], Type: fields[1], SubType: fields[2]}
+		if fields[3] == "" {
+			part.Offset = next
+			if part.Type == "app" {
+				part.Offset = (part.Offset + appAlignment - 1) &^ (appAlignment - 1)
+			}
+		} else if offset, err := ParseSize(fields[3]); err != nil {
+			return nil, fmt.Errorf("line %d: invalid offset: %s", i+1, err)
+		} else {
+			part.Offset = offset
+		}
+		size, err := ParseSize(fields[4])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid size: %s", i+1, err)
+		}
+		part.Size = size
+		next = part.End()
+		res = append(res, part)
+	}
+	return res, nil
+}
+
+// Load reads the partitions table from a CSV file
+func Load(file *paths.Path) ([]*Partition, error) {
+	data, err := file.ReadFile()
+	if err != nil {
+		return nil, fmt.Errorf("reading partitions table: %s", err)
+	}
+	res, err := Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("reading partitions table %s: %s", file, err)
+	}
+	return res, nil
+}
+
+// ParseSize parses a number in decimal or hexadecimal notation, optionally
+// followed by a K or M multiplier
+func ParseSize(s string) (uint64, error) {
+	multiplier := uint64(1)
+	switch {
+	case strings.HasSuffix(s, "K") || strings.HasSuffix(s, "k"):
+		multiplier = 1024
+		s = s[:len(s)-1]
+	case strings.HasSuffix(s, "M") || strings.HasSuffix(s, "m"):
+		multiplier = 1024 * 1024
+		s = s[:len(s)-1]
+	}
+	n, err := strconv.ParseUint(s, 0, 64)
+	if err != nil {
+		return 0, err
+	}
+	return n * multiplier, nil
+}
+
+// FindApp returns the partition the sketch is uploaded to: the factory
+// partition or, if the table has none, the first app partition.
+func FindApp(partitions []*Partition) *Partition {
+	var res *Partition
+	for _, part := range partitions {
+		if part.Type != "app" {
+			continue
+		}
+		if part.SubType == "factory" {
+			return part
+		}
+		if res == nil {
+			res = part
+		}
+	}
+	return res
+}
+
+// Validate checks that the partitions table contains an app partition and
+// that the partitions are aligned and don't overlap
+func Validate(partitions []*Partition) error {
+	if FindApp(partitions) == nil {
+		return fmt.Errorf("the partitions table has no app partition")
+	}
+	for i, part := range partitions {
+		if part.Offset < firstPartitionOffset {
+			return fmt.Errorf("partition %s overlaps the bootloader or the partitions table", part.Name)
+		}
+		if part.Type == "app" && part.Offset%appAlignment != 0 {
+			return fmt.Errorf("app partition %s is not aligned to 0x%X", part.Name, appAlignment)
+		}
+		for _, other := range partitions[:i] {
+			if part.Offset < other.End() && other.Offset < part.End() {
+				return fmt.Errorf("partition %s overlaps partition %s", part.Name, other.Name)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package partitions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const defaultTable = `# Name,   Type, SubType, Offset,  Size, Flags
+nvs,      data, nvs,     0x9000,  0x5000,
+otadata,  data, ota,     0xe000,  0x2000,
+app0,     app,  ota_0,   0x10000, 0x140000,
+app1,     app,  ota_1,   0x150000,0x140000,
+spiffs,   data, spiffs,  0x290000,0x170000,
+`
+
+func TestParse(t *testing.T) {
+	partitions, err := Parse(defaultTable)
+	require.NoError(t, err)
+	require.Len(t, partitions, 5)
+	require.Equal(t, &Partition{Name: "spiffs", Type: "data", SubType: "spiffs", Offset: 0x290000, Size: 0x170000}, partitions[4])
+	require.Equal(t, partitions[2], FindApp(partitions))
+	require.NoError(t, Validate(partitions))
+
+	// Offsets are computed when missing, app partitions are aligned to 64K
+	partitions, err = Parse("nvs, data, nvs, , 20K\nfactory, app, factory, , 1M\nffat, data, fat, , 0x100000\n")
+	require.NoError(t, err)
+	require.Equal(t, uint64(0x9000), partitions[0].Offset)
+	require.Equal(t, uint64(0x5000), partitions[0].Size)
+	require.Equal(t, uint64(0x10000), partitions[1].Offset)
+	require.Equal(t, uint64(0x110000), partitions[2].Offset)
+	require.Equal(t, partitions[1], FindApp(partitions))
+	require.NoError(t, Validate(partitions))
+
+	_, err = Parse("nvs, data, nvs\n")
+	require.Error(t, err)
+	_, err = Parse("nvs, data, nvs, 0x9000, 20X\n")
+	require.Error(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	partitions, err := Parse("nvs, data, nvs, 0x9000, 0x5000\n")
+	require.NoError(t, err)
+	require.EqualError(t, Validate(partitions), "the partitions table has no app partition")
+
+	partitions, err = Parse("nvs, data, nvs, 0x9000, 0x5000\napp0, app, ota_0, 0x18000, 1M\n")
+	require.NoError(t, err)
+	require.EqualError(t, Validate(partitions), "app partition app0 is not aligned to 0x10000")
+
+	partitions, err = Parse("nvs, data, nvs, 0x9000, 0x10000\napp0, app, ota_0, 0x10000, 1M\n")
+	require.NoError(t, err)
+	require.EqualError(t, Validate(partitions), "partition app0 overlaps partition nvs")
+
+	partitions, err = Parse("nvs, data, nvs, 0x8000, 0x1000\napp0, app, ota_0, 0x10000, 1M\n")
+	require.NoError(t, err)
+	require.EqualError(t, Validate(partitions), "partition nvs overlaps the bootloader or the partitions table")
+}
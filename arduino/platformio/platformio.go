@@ -0,0 +1,264 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package platformio
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	paths "github.com/arduino/go-paths-helper"
+)
+
+// Project is a PlatformIO project, as described by its platformio.ini file
+type Project struct {
+	Dir         *paths.Path
+	Envs        []*Env
+	DefaultEnvs []string
+	SrcDir      *paths.Path
+	IncludeDir  *paths.Path
+	LibDir      *paths.Path
+}
+
+// Env is an [env:NAME] section of a platformio.ini file
+type Env struct {
+	Name      string
+	Platform  string
+	Board     string
+	Framework string
+	LibDeps   []string
+}
+
+// LoadProject reads the platformio.ini file of the project in the given
+// folder. The values of the common [env] section are inherited by all the
+// environments, the ${...} interpolations are not supported.
+func LoadProject(dir *paths.Path) (*Project, error) {
+	iniFile := dir.Join("platformio.ini")
+	data, err := iniFile.ReadFile()
+	if err != nil {
+		return nil, fmt.Errorf("reading project configuration: %s", err)
+	}
+	sections, err := parseINI(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %s", iniFile, err)
+	}
+
+	project := &Project{Dir: dir}
+	options := sections["platformio"]
+	project.DefaultEnvs = splitList(options["default_envs"])
+	dirOption := func(key, def string) *paths.Path {
+		value := options[key]
+		if value == "" {
+			value = def
+		}
+		if p := paths.New(value); p.IsAbs() {
+			return p
+		}
+		return dir.Join(value)
+	}
+	project.SrcDir = dirOption("src_dir", "src")
+	project.IncludeDir = dirOption("include_dir", "include")
+	project.LibDir = dirOption("lib_dir", "lib")
+
+	common := sections["env"]
+	names := []string{}
+	for name := range sections {
+		if strings.HasPrefix(name, "env:") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		section := sections[name]
+		get := func(key string) string {
+			if value, ok := section[key]; ok {
+				return value
+			}
+			return common[key]
+		}
+		project.Envs = append(project.Envs, &Env{
+			Name:      strings.TrimPrefix(name, "env:"),
+			Platform:  get("platform"),
+			Board:     get("board"),
+			Framework: get("framework"),
+			LibDeps:   splitList(get("lib_deps")),
+		})
+	}
+	if len(project.Envs) == 0 {
+		return nil, fmt.Errorf("no environment defined in %s", iniFile)
+	}
+	return project, nil
+}
+
+// Env returns the environment with the given name or, if name is empty, the
+// first of the default environments of the project.
+func (p *Project) Env(name string) (*Env, error) {
+	if name == "" && len(p.DefaultEnvs) > 0 {
+		name = p.DefaultEnvs[0]
+	}
+	if name == "" {
+		return p.Envs[0], nil
+	}
+	for _, env := range p.Envs {
+		if env.Name == name {
+			return env, nil
+		}
+	}
+	return nil, fmt.Errorf("environment %s not found", name)
+}
+
+// parseINI parses the platformio.ini format: a value continues on the
+// following indented lines, and the lines starting with ; or # are comments.
+func parseINI(data string) (map[string]map[string]string, error) {
+	sections := map[string]map[string]string{}
+	var section map[string]string
+	key := ""
+	for i, line := range strings.Split(data, "\n") {
+		line = strings.TrimRight(line, "\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, ";") || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if key != "" && (line[0] == ' ' || line[0] == '\t') {
+			section[key] += "\n" + stripComment(trimmed)
+			continue
+		}
+		key = ""
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			name := strings.TrimSpace(trimmed[1 : len(trimmed)-1])
+			if sections[name] == nil {
+				sections[name] = map[string]string{}
+			}
+			section = sections[name]
+			continue
+		}
+		sep := strings.IndexAny(trimmed, "=:")
+		if section == nil || sep == -1 {
+			return nil, fmt.Errorf("invalid line %d: %s", i+1, trimmed)
+		}
+		key = strings.TrimSpace(trimmed[:sep])
+		section[key] = stripComment(strings.TrimSpace(trimmed[sep+1:]))
+	}
+	return sections, nil
+}
+
+// stripComment removes an inline comment, that starts with " ;"
+func stripComment(value string) string {
+	if i := strings.Index(value, " ;"); i != -1 {
+		value = value[:i]
+	}
+	return strings.TrimSpace(value)
+}
+
+// splitList splits a multi-line or comma separated value
+func splitList(value string) []string {
+	res := []string{}
+	for _, line := range strings.Split(value, "\n") {
+		for _, item := range strings.Split(line, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				res = append(res, item)
+			}
+		}
+	}
+	return res
+}
+
+// boards maps the PlatformIO board IDs to the FQBNs of the same boards
+var boards = map[string]string{
+	"uno":                   "arduino:avr:uno",
+	"leonardo":              "arduino:avr:leonardo",
+	"micro":                 "arduino:avr:micro",
+	"megaatmega2560":        "arduino:avr:mega:cpu=atmega2560",
+	"megaatmega1280":        "arduino:avr:mega:cpu=atmega1280",
+	"nanoatmega328":         "arduino:avr:nano:cpu=atmega328old",
+	"nanoatmega328new":      "arduino:avr:nano:cpu=atmega328",
+	"nanoatmega168":         "arduino:avr:nano:cpu=atmega168",
+	"pro16MHzatmega328":     "arduino:avr:pro:cpu=16MHzatmega328",
+	"pro8MHzatmega328":      "arduino:avr:pro:cpu=8MHzatmega328",
+	"uno_wifi_rev2":         "arduino:megaavr:uno2018",
+	"nano_every":            "arduino:megaavr:nona4809",
+	"due":                   "arduino:sam:arduino_due_x_dbg",
+	"dueUSB":                "arduino:sam:arduino_due_x",
+	"zero":                  "arduino:samd:arduino_zero_edbg",
+	"zeroUSB":               "arduino:samd:arduino_zero_native",
+	"mkr1000USB":            "arduino:samd:mkr1000",
+	"mkrwifi1010":           "arduino:samd:mkrwifi1010",
+	"mkrzero":               "arduino:samd:mkrzero",
+	"mkrwan1310":            "arduino:samd:mkrwan1310",
+	"nano_33_iot":           "arduino:samd:nano_33_iot",
+	"nano33ble":             "arduino:mbed_nano:nano33ble",
+	"nanorp2040connect":     "arduino:mbed_nano:nanorp2040connect",
+	"portenta_h7_m7":        "arduino:mbed_portenta:envie_m7",
+	"pico":                  "arduino:mbed_rp2040:pico",
+	"esp32dev":              "esp32:esp32:esp32",
+	"esp32-s2-saola-1":      "esp32:esp32:esp32s2",
+	"esp32-c3-devkitm-1":    "esp32:esp32:esp32c3",
+	"esp32-s3-devkitc-1":    "esp32:esp32:esp32s3",
+	"lolin32":               "esp32:esp32:lolin32",
+	"nodemcu-32s":           "esp32:esp32:nodemcu-32s",
+	"featheresp32":          "esp32:esp32:featheresp32",
+	"nodemcuv2":             "esp8266:esp8266:nodemcuv2",
+	"d1_mini":               "esp8266:esp8266:d1_mini",
+	"esp01_1m":              "esp8266:esp8266:generic",
+	"huzzah":                "esp8266:esp8266:huzzah",
+	"adafruit_feather_m0":   "adafruit:samd:adafruit_feather_m0",
+	"adafruit_feather_m4":   "adafruit:samd:adafruit_feather_m4",
+	"adafruit_itsybitsy_m4": "adafruit:samd:adafruit_itsybitsy_m4",
+	"teensy40":              "teensy:avr:teensy40",
+	"teensy41":              "teensy:avr:teensy41",
+	"teensy36":              "teensy:avr:teensy36",
+	"teensy31":              "teensy:avr:teensy31",
+}
+
+// BoardFQBN returns the FQBN of the board with the given PlatformIO ID
+func BoardFQBN(board string) (string, bool) {
+	fqbn, ok := boards[board]
+	return fqbn, ok
+}
+
+var exactVersion = regexp.MustCompile(`^=?\s*v?(\d+\.\d+\.\d+)$`)
+
+// LibraryReference converts a lib_deps entry, e.g. bblanchon/ArduinoJson @ 6.18.5,
+// to a library reference in the NAME[@VERSION] form. Only the exact versions
+// are kept: for a version range the latest version is installed. Libraries
+// from URLs or local folders are not supported.
+func LibraryReference(dep string) (string, error) {
+	name, version := dep, ""
+	if i := strings.Index(dep, "@"); i != -1 {
+		name, version = dep[:i], strings.TrimSpace(dep[i+1:])
+	}
+	name = strings.TrimSpace(name)
+	if strings.Contains(name, "://") || strings.HasPrefix(name, "file:") || strings.HasPrefix(name, "symlink:") ||
+		strings.HasPrefix(name, ".") || strings.HasPrefix(name, "/") || strings.HasSuffix(name, ".git") {
+		return "", fmt.Errorf("library %s is not in the libraries index", dep)
+	}
+	if i := strings.Index(name, "="); i != -1 {
+		// custom name, e.g. MyLib=https://...
+		return "", fmt.Errorf("library %s is not in the libraries index", dep)
+	}
+	// remove the owner
+	if i := strings.LastIndex(name, "/"); i != -1 {
+		name = name[i+1:]
+	}
+	if name == "" {
+		return "", fmt.Errorf("invalid library %s", dep)
+	}
+	if m := exactVersion.FindStringSubmatch(version); m != nil {
+		return name + "@" + m[1], nil
+	}
+	return name, nil
+}
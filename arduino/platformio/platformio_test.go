@@ -0,0 +1,86 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package platformio
+
+import (
+	"testing"
+
+	paths "github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadProject(t *testing.T) {
+	dir := paths.New("testdata", "project")
+	project, err := LoadProject(dir)
+	require.NoError(t, err)
+	require.Equal(t, []string{"uno"}, project.DefaultEnvs)
+	require.Equal(t, dir.Join("src"), project.SrcDir)
+	require.Equal(t, dir.Join("include"), project.IncludeDir)
+	require.Len(t, project.Envs, 2)
+
+	env, err := project.Env("")
+	require.NoError(t, err)
+	require.Equal(t, &Env{
+		Name:      "uno",
+		Platform:  "atmelavr",
+		Board:     "uno",
+		Framework: "arduino",
+		LibDeps:   []string{"bblanchon/ArduinoJson @ 6.18.5", "adafruit/Adafruit GFX Library @ ^1.10.12"},
+	}, env)
+
+	env, err = project.Env("esp32")
+	require.NoError(t, err)
+	require.Equal(t, "esp32dev", env.Board)
+	require.Equal(t, "arduino", env.Framework)
+	require.Equal(t, []string{"https://github.com/me-no-dev/AsyncTCP.git", "Servo"}, env.LibDeps)
+
+	_, err = project.Env("due")
+	require.Error(t, err)
+
+	_, err = LoadProject(paths.New("testdata"))
+	require.Error(t, err)
+}
+
+func TestBoardFQBN(t *testing.T) {
+	fqbn, ok := BoardFQBN("megaatmega2560")
+	require.True(t, ok)
+	require.Equal(t, "arduino:avr:mega:cpu=atmega2560", fqbn)
+
+	_, ok = BoardFQBN("unknown_board")
+	require.False(t, ok)
+}
+
+func TestLibraryReference(t *testing.T) {
+	for dep, ref := range map[string]string{
+		"Servo":                          "Servo",
+		"bblanchon/ArduinoJson @ 6.18.5": "ArduinoJson@6.18.5",
+		"ArduinoJson@=6.18.5":            "ArduinoJson@6.18.5",
+		"adafruit/Adafruit GFX Library @ ^1.10.12": "Adafruit GFX Library",
+		"FastLED @ ~3.4.0":                         "FastLED",
+	} {
+		res, err := LibraryReference(dep)
+		require.NoError(t, err, dep)
+		require.Equal(t, ref, res, dep)
+	}
+	for _, dep := range []string{
+		"https://github.com/me-no-dev/AsyncTCP.git",
+		"file://../MyLib",
+		"MyLib=https://example.com/mylib.zip",
+	} {
+		_, err := LibraryReference(dep)
+		require.Error(t, err, dep)
+	}
+}
@@ -0,0 +1 @@
+#define LED_PIN 13
@@ -0,0 +1,11 @@
+#include <Arduino.h>
+#include "config.h"
+#include "sub/blink.h"
+
+void setup() {
+  blinkSetup();
+}
+
+void loop() {
+  blinkLoop();
+}
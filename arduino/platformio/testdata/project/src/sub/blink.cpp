@@ -0,0 +1,12 @@
+#include <Arduino.h>
+#include "blink.h"
+#include "../config.h"
+
+void blinkSetup() {
+  pinMode(LED_PIN, OUTPUT);
+}
+
+void blinkLoop() {
+  digitalWrite(LED_PIN, !digitalRead(LED_PIN));
+  delay(1000);
+}
@@ -0,0 +1,2 @@
+void blinkSetup();
+void blinkLoop();
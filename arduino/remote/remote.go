@@ -0,0 +1,217 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package remote
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
+)
+
+// Host is a remote machine, reachable with ssh, where the boards are
+// attached. The commands are run with the ssh and scp clients installed on
+// the system, so the keys, the agent and the ~/.ssh/config of the user are
+// used to connect.
+type Host struct {
+	// User is the name used to login, empty to use the ssh default
+	User string
+	// Address is the host name or IP address of the host
+	Address string
+	// Port is the ssh port, 0 to use the ssh default
+	Port int
+	// CLIPath is the path of the arduino-cli executable on the host
+	CLIPath string
+}
+
+// ParseHost parses a remote host in the form [user@]host[:port]. The user and
+// the host can't start with "-", so they are never taken as options of ssh.
+func ParseHost(remote string) (*Host, error) {
+	h := &Host{CLIPath: "arduino-cli"}
+	address := remote
+	if i := strings.LastIndex(address, "@"); i != -1 {
+		h.User = address[:i]
+		address = address[i+1:]
+		if h.User == "" {
+			return nil, fmt.Errorf("invalid remote host %s: missing user", remote)
+		}
+		if strings.HasPrefix(h.User, "-") {
+			return nil, fmt.Errorf("invalid remote host %s: invalid user", remote)
+		}
+	}
+	if i := strings.LastIndex(address, ":"); i != -1 {
+		port, err := strconv.Atoi(address[i+1:])
+		if err != nil || port <= 0 || port > 65535 {
+			return nil, fmt.Errorf("invalid remote host %s: invalid port", remote)
+		}
+		h.Port = port
+		address = address[:i]
+	}
+	if address == "" || strings.HasPrefix(address, "-") || strings.ContainsAny(address, " /") {
+		return nil, fmt.Errorf("invalid remote host %s", remote)
+	}
+	h.Address = address
+	return h, nil
+}
+
+// Destination returns the host in the [user@]host form used by ssh and scp
+func (h *Host) Destination() string {
+	if h.User == "" {
+		return h.Address
+	}
+	return h.User + "@" + h.Address
+}
+
+func (h *Host) String() string {
+	if h.Port == 0 {
+		return h.Destination()
+	}
+	return h.Destination() + ":" + strconv.Itoa(h.Port)
+}
+
+// Command returns a command that runs the given command line on the host.
+// The arguments are quoted, so they reach the remote command unchanged.
+// The options of ssh end before the destination, so neither the destination
+// nor the command can be taken as options.
+func (h *Host) Command(args ...string) *exec.Cmd {
+	sshArgs := []string{}
+	if h.Port != 0 {
+		sshArgs = append(sshArgs, "-p", strconv.Itoa(h.Port))
+	}
+	sshArgs = append(sshArgs, "--", h.Destination())
+	for _, arg := range args {
+		sshArgs = append(sshArgs, shellQuote(arg))
+	}
+	return exec.Command("ssh", sshArgs...)
+}
+
+// CLICommand returns a command that runs arduino-cli on the host
+func (h *Host) CLICommand(args ...string) *exec.Cmd {
+	return h.Command(append([]string{h.CLIPath}, args...)...)
+}
+
+// CopyCommand returns a command that copies the given files in a folder of
+// the host
+func (h *Host) CopyCommand(files paths.PathList, remoteDir string) *exec.Cmd {
+	scpArgs := []string{"-q"}
+	if h.Port != 0 {
+		scpArgs = append(scpArgs, "-P", strconv.Itoa(h.Port))
+	}
+	scpArgs = append(scpArgs, "--")
+	for _, file := range files {
+		scpArgs = append(scpArgs, file.String())
+	}
+	scpArgs = append(scpArgs, h.Destination()+":"+shellQuote(remoteDir))
+	return exec.Command("scp", scpArgs...)
+}
+
+// Run runs the given command line on the host and returns its output
+func (h *Host) Run(args ...string) ([]byte, error) {
+	return run(h.Command(args...))
+}
+
+// Copy copies the given files in a folder of the host
+func (h *Host) Copy(files paths.PathList, remoteDir string) error {
+	_, err := run(h.CopyCommand(files, remoteDir))
+	return err
+}
+
+// MkTempDir creates a temporary folder on the host and returns its path
+func (h *Host) MkTempDir() (string, error) {
+	out, err := h.Run("mktemp", "-d")
+	if err != nil {
+		return "", err
+	}
+	dir := strings.TrimSpace(string(out))
+	if dir == "" {
+		return "", fmt.Errorf("creating temporary folder on %s: empty path", h)
+	}
+	return dir, nil
+}
+
+// RemoveAll removes a folder, and all its content, from the host
+func (h *Host) RemoveAll(remoteDir string) error {
+	_, err := h.Run("rm", "-rf", remoteDir)
+	return err
+}
+
+// BoardList returns the ports, and the boards connected to them, detected by
+// the arduino-cli installed on the host. The timeout is passed to the board
+// list command of the host.
+func (h *Host) BoardList(timeout time.Duration) ([]*rpc.DetectedPort, error) {
+	args := []string{"board", "list", "--format", "json"}
+	if timeout > 0 {
+		args = append(args, "--timeout", timeout.String())
+	}
+	out, err := run(h.CLICommand(args...))
+	if err != nil {
+		return nil, err
+	}
+	ports := []*rpc.DetectedPort{}
+	if err := json.Unmarshal(out, &ports); err != nil {
+		return nil, fmt.Errorf("reading the boards detected on %s: %s", h, err)
+	}
+	return ports, nil
+}
+
+// RunCLI runs arduino-cli on the host, the output is written in the given
+// streams
+func (h *Host) RunCLI(outStream, errStream io.Writer, args ...string) error {
+	cmd := h.CLICommand(args...)
+	cmd.Stdout = outStream
+	cmd.Stderr = errStream
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("running arduino-cli on %s: %s", h, err)
+	}
+	return nil
+}
+
+// run runs the command and returns its output. In case of error the output
+// of the command is added to the error message.
+func run(cmd *exec.Cmd) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	if cmd.Stdout == nil {
+		cmd.Stdout = &stdout
+	}
+	if cmd.Stderr == nil {
+		cmd.Stderr = &stderr
+	}
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s: %s: %s", cmd.Args[0], err, msg)
+		}
+		return nil, fmt.Errorf("%s: %s", cmd.Args[0], err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// shellQuote quotes the argument for the POSIX shell used by ssh to run the
+// remote command
+func shellQuote(arg string) string {
+	if arg != "" && strings.IndexFunc(arg, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:=@%+,", r))
+	}) == -1 {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
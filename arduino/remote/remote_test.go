@@ -0,0 +1,68 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package remote
+
+import (
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHost(t *testing.T) {
+	h, err := ParseHost("pi@lab-pi.local")
+	require.NoError(t, err)
+	require.Equal(t, "pi", h.User)
+	require.Equal(t, "lab-pi.local", h.Address)
+	require.Equal(t, 0, h.Port)
+	require.Equal(t, "arduino-cli", h.CLIPath)
+	require.Equal(t, "pi@lab-pi.local", h.String())
+
+	h, err = ParseHost("192.168.1.20:2222")
+	require.NoError(t, err)
+	require.Equal(t, "", h.User)
+	require.Equal(t, "192.168.1.20", h.Address)
+	require.Equal(t, 2222, h.Port)
+	require.Equal(t, "192.168.1.20:2222", h.String())
+
+	for _, invalid := range []string{"", "pi@", "@host", "host:port", "host:0", "pi@host:70000", "host/path", "-oProxyCommand=sh", "-l@host", "pi@-host"} {
+		_, err := ParseHost(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestCommand(t *testing.T) {
+	h, err := ParseHost("pi@lab-pi:2222")
+	require.NoError(t, err)
+
+	cmd := h.CLICommand("upload", "-b", "arduino:avr:uno", "--input-dir", "/tmp/My Sketch", "--tool-arg", "it's")
+	require.Equal(t, []string{
+		"ssh", "-p", "2222", "--", "pi@lab-pi",
+		"arduino-cli", "upload", "-b", "arduino:avr:uno", "--input-dir", "'/tmp/My Sketch'", "--tool-arg", `'it'\''s'`,
+	}, cmd.Args)
+
+	cmd = h.CopyCommand(paths.NewPathList("build/Blink.ino.hex", "build/Blink.ino.elf"), "/tmp/tmp.1234/Blink")
+	require.Equal(t, []string{
+		"scp", "-q", "-P", "2222", "--", "build/Blink.ino.hex", "build/Blink.ino.elf", "pi@lab-pi:/tmp/tmp.1234/Blink",
+	}, cmd.Args)
+}
+
+func TestShellQuote(t *testing.T) {
+	require.Equal(t, "arduino:avr:uno", shellQuote("arduino:avr:uno"))
+	require.Equal(t, "''", shellQuote(""))
+	require.Equal(t, "'$HOME'", shellQuote("$HOME"))
+	require.Equal(t, "'a;b'", shellQuote("a;b"))
+}
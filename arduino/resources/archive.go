@@ -0,0 +1,138 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package resources
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	paths "github.com/arduino/go-paths-helper"
+)
+
+// NewDownloadResource returns the DownloadResource of a local archive, with
+// its size and SHA-256 checksum, to be published at the given URL
+func NewDownloadResource(archive *paths.Path, url string) (*DownloadResource, error) {
+	f, err := archive.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	size, err := io.Copy(hash, f)
+	if err != nil {
+		return nil, fmt.Errorf("computing hash: %s", err)
+	}
+	return &DownloadResource{
+		URL:             url,
+		ArchiveFileName: archive.Base(),
+		Checksum:        "SHA-256:" + hex.EncodeToString(hash.Sum(nil)),
+		Size:            size,
+	}, nil
+}
+
+// CreateZipArchive creates a zip archive with the content of dir, placed in
+// the given folder of the archive. Hidden files and folders, and the files
+// matching any of the exclude patterns, are not added. The patterns are
+// matched against both the name of the files and their path relative to dir.
+// The number of files added is returned.
+func CreateZipArchive(dir, archive *paths.Path, folder string, exclude []string) (int, error) {
+	for _, pattern := range exclude {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return 0, fmt.Errorf("invalid exclude pattern '%s': %s", pattern, err)
+		}
+	}
+	file, err := archive.Create()
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	files := 0
+	zipWriter := zip.NewWriter(file)
+	err = filepath.Walk(dir.String(), func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == dir.String() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir.String(), path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if strings.HasPrefix(info.Name(), ".") || isExcluded(rel, info.Name(), exclude) || paths.New(path).EquivalentTo(archive) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			return nil
+		}
+		if err := addFileToZip(zipWriter, path, info, folder+"/"+rel); err != nil {
+			return err
+		}
+		files++
+		return nil
+	})
+	if err != nil {
+		zipWriter.Close()
+		return 0, err
+	}
+	if err := zipWriter.Close(); err != nil {
+		return 0, err
+	}
+	return files, file.Close()
+}
+
+func isExcluded(rel, name string, exclude []string) bool {
+	for _, pattern := range exclude {
+		if ok, _ := filepath.Match(pattern, rel); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func addFileToZip(zipWriter *zip.Writer, path string, info os.FileInfo, name string) error {
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	header.Method = zip.Deflate
+	writer, err := zipWriter.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(writer, f)
+	return err
+}
@@ -16,8 +16,11 @@
 package resources
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
+	"strings"
 
 	"github.com/arduino/go-paths-helper"
 	"go.bug.st/downloader/v2"
@@ -42,16 +45,29 @@ func (r *DownloadResource) IsCached(downloadDir *paths.Path) (bool, error) {
 	return archivePath.Exist(), nil
 }
 
-// Download a DownloadResource.
-func (r *DownloadResource) Download(downloadDir *paths.Path, config *downloader.Config) (*downloader.Downloader, error) {
+// Download a DownloadResource. The archive is downloaded from the URL of
+// the resource or, if it is not reachable, from the given mirrors in order. A
+// mirror is a base URL where the archives are stored in the same layout of the
+// download directory (see the "mirror create" command).
+//
+// If a previous download has been interrupted, the partial archive is kept and
+// the download is resumed from where it stopped. If the archive is already
+// downloaded a nil Downloader is returned.
+func (r *DownloadResource) Download(downloadDir *paths.Path, config *downloader.Config, mirrors ...string) (*downloader.Downloader, error) {
 	path, err := r.ArchivePath(downloadDir)
 	if err != nil {
 		return nil, fmt.Errorf("getting archive path: %s", err)
 	}
 
-	if _, err := path.Stat(); os.IsNotExist(err) {
+	resume := false
+	if info, err := path.Stat(); os.IsNotExist(err) {
 		// normal download
-	} else if err == nil {
+	} else if err != nil {
+		return nil, fmt.Errorf("getting archive file info: %s", err)
+	} else if r.Size > 0 && info.Size() < r.Size {
+		// resume download
+		resume = true
+	} else {
 		// check local file integrity
 		ok, err := r.TestLocalArchiveIntegrity(downloadDir)
 		if err != nil || !ok {
@@ -62,11 +78,51 @@ func (r *DownloadResource) Download(downloadDir *paths.Path, config *downloader.
 			// File is cached, nothing to do here
 			return nil, nil
 		}
-	} else if err == nil {
-		// resume download
-	} else {
-		return nil, fmt.Errorf("getting archive file info: %s", err)
 	}
 
-	return downloader.DownloadWithConfig(path.String(), r.URL, *config)
+	var errs []string
+	for _, url := range r.MirrorURLs(mirrors...) {
+		d, err := download(path, url, config, resume)
+		if err == nil {
+			return d, nil
+		}
+		errs = append(errs, fmt.Sprintf("%s: %s", url, err))
+	}
+	return nil, fmt.Errorf("downloading %s: %s", r.ArchiveFileName, strings.Join(errs, ", "))
+}
+
+// MirrorURLs returns the URLs where the archive can be downloaded from: the
+// URL of the resource followed by the URLs of the archive in each mirror.
+func (r *DownloadResource) MirrorURLs(mirrors ...string) []string {
+	res := []string{r.URL}
+	for _, mirror := range mirrors {
+		url := strings.TrimSuffix(mirror, "/") + "/"
+		if r.CachePath != "" {
+			url += r.CachePath + "/"
+		}
+		res = append(res, url+r.ArchiveFileName)
+	}
+	return res
+}
+
+func download(path *paths.Path, url string, config *downloader.Config, resume bool) (*downloader.Downloader, error) {
+	opts := []downloader.DownloadOptions{}
+	if !resume {
+		opts = append(opts, downloader.NoResume)
+	}
+	d, err := downloader.DownloadWithConfig(path.String(), url, *config, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if d.Resp.StatusCode >= 400 && d.Resp.StatusCode <= 599 && d.Resp.StatusCode != http.StatusRequestedRangeNotSatisfiable {
+		d.Close()
+		return nil, errors.New(d.Resp.Status)
+	}
+	if resume && d.Resp.StatusCode != http.StatusPartialContent {
+		// The server doesn't support range requests (or the partial file is
+		// not valid anymore): restart the download from the beginning
+		d.Close()
+		return download(path, url, config, false)
+	}
+	return d, nil
 }
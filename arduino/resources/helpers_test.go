@@ -16,12 +16,14 @@
 package resources
 
 import (
+	"bytes"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/arduino/arduino-cli/httpclient"
 	"github.com/arduino/go-paths-helper"
@@ -82,3 +84,49 @@ func TestDownloadApplyUserAgentHeaderUsingConfig(t *testing.T) {
 	require.Equal(t, goldUserAgentString, userAgentHeaderString)
 
 }
+
+func TestDownloadResumeAndMirrors(t *testing.T) {
+	content := []byte("0123456789abcdefghijklmnopqrstuvwxyz")
+	ranges := []string{}
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/cache/archive.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		ranges = append(ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "archive.txt", time.Time{}, bytes.NewReader(content))
+	}))
+	defer mirror.Close()
+	broken := httptest.NewServer(http.NotFoundHandler())
+	defer broken.Close()
+
+	tmp, err := paths.MkTempDir("", "")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+
+	r := &DownloadResource{
+		ArchiveFileName: "archive.txt",
+		CachePath:       "cache",
+		URL:             broken.URL + "/archive.txt",
+		Size:            int64(len(content)),
+	}
+	require.Equal(t, []string{r.URL, mirror.URL + "/cache/archive.txt"}, r.MirrorURLs(mirror.URL+"/"))
+
+	// simulate an interrupted download
+	require.NoError(t, tmp.Join("cache").MkdirAll())
+	require.NoError(t, tmp.Join("cache", "archive.txt").WriteFile(content[:10]))
+
+	config := &downloader.Config{HttpClient: *httpclient.NewWithConfig(&httpclient.Config{})}
+	d, err := r.Download(tmp, config, mirror.URL)
+	require.NoError(t, err)
+	require.NoError(t, d.Run())
+	require.Equal(t, []string{"bytes=10-"}, ranges)
+	data, err := tmp.Join("cache", "archive.txt").ReadFile()
+	require.NoError(t, err)
+	require.Equal(t, content, data)
+
+	// no reachable URL
+	require.NoError(t, tmp.Join("cache", "archive.txt").Remove())
+	_, err = r.Download(tmp, config)
+	require.Error(t, err)
+}
@@ -19,6 +19,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 
 	paths "github.com/arduino/go-paths-helper"
 	"github.com/codeclysm/extract/v3"
@@ -133,3 +134,41 @@ func findPackageRoot(parent *paths.Path) (*paths.Path, error) {
 	}
 	return root, nil
 }
+
+// ArchiveRootFiles returns the names of the files in the root dir of the
+// downloaded archive of the resource, the files found in destDir after
+// Install. It returns nil if the archive has not been downloaded, or only
+// partially.
+func (release *DownloadResource) ArchiveRootFiles(downloadDir *paths.Path) ([]string, error) {
+	if cached, err := release.IsCached(downloadDir); err != nil || !cached {
+		return nil, err
+	}
+	if ok, err := release.TestLocalArchiveIntegrity(downloadDir); err != nil || !ok {
+		// not completely downloaded
+		return nil, nil
+	}
+	archivePath, err := release.ArchivePath(downloadDir)
+	if err != nil {
+		return nil, fmt.Errorf("getting archive path: %s", err)
+	}
+	file, err := archivePath.Open()
+	if err != nil {
+		return nil, fmt.Errorf("opening archive file: %s", err)
+	}
+	defer file.Close()
+
+	// The entries are only listed: the renamer skips all of them, so nothing
+	// is extracted
+	files := []string{}
+	list := func(name string) string {
+		split := strings.Split(strings.TrimPrefix(name, "./"), "/")
+		if len(split) == 2 && split[1] != "" {
+			files = append(files, split[1])
+		}
+		return ""
+	}
+	if err := extract.Archive(context.Background(), file, downloadDir.String(), list); err != nil {
+		return nil, fmt.Errorf("reading archive: %s", err)
+	}
+	return files, nil
+}
@@ -112,3 +112,39 @@ func TestDownloadAndChecksums(t *testing.T) {
 	_, err = r.TestLocalArchiveChecksum(tmp)
 	require.Error(t, err)
 }
+
+func TestArchiveRootFiles(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+
+	platform := tmp.Join("platform")
+	require.NoError(t, platform.Join("cores").MkdirAll())
+	require.NoError(t, platform.Join("platform.txt").WriteFile([]byte("name=Test\n")))
+	require.NoError(t, platform.Join("post_install.sh").WriteFile([]byte("#!/bin/sh\n")))
+	require.NoError(t, platform.Join("cores", "main.cpp").WriteFile([]byte("int main() {}\n")))
+	archive := tmp.Join("platform.zip")
+	_, err = CreateZipArchive(platform, archive, "avr", nil)
+	require.NoError(t, err)
+	r, err := NewDownloadResource(archive, "https://example.com/platform.zip")
+	require.NoError(t, err)
+	r.CachePath = "cache"
+	downloadDir := tmp.Join("staging")
+
+	// Not downloaded
+	files, err := r.ArchiveRootFiles(downloadDir)
+	require.NoError(t, err)
+	require.Nil(t, files)
+
+	require.NoError(t, archive.CopyTo(downloadDir.Join("cache", "platform.zip")))
+	files, err = r.ArchiveRootFiles(downloadDir)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"platform.txt", "post_install.sh"}, files)
+	require.False(t, downloadDir.Join("avr").Exist())
+
+	// Partially downloaded
+	require.NoError(t, downloadDir.Join("cache", "platform.zip").WriteFile([]byte("PK")))
+	files, err = r.ArchiveRootFiles(downloadDir)
+	require.NoError(t, err)
+	require.Nil(t, files)
+}
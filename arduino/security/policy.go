@@ -0,0 +1,74 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package security
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/arduino/go-paths-helper"
+	rice "github.com/cmaglie/go.rice"
+	"golang.org/x/crypto/openpgp"
+)
+
+// Level is the policy applied to the signatures of the package indexes and,
+// through them, to the archives they list
+type Level string
+
+const (
+	// LevelStrict refuses indexes without a valid signature from a trusted key,
+	// and the installation of archives listed in unsigned indexes
+	LevelStrict Level = "strict"
+	// LevelWarn refuses indexes with an invalid signature, unsigned indexes and
+	// archives are accepted with a warning
+	LevelWarn Level = "warn"
+	// LevelOff disables the signature verification
+	LevelOff Level = "off"
+)
+
+// ParseLevel returns the Level with the given name, the empty string is
+// LevelWarn
+func ParseLevel(level string) (Level, error) {
+	switch Level(level) {
+	case "":
+		return LevelWarn, nil
+	case LevelStrict, LevelWarn, LevelOff:
+		return Level(level), nil
+	}
+	return "", fmt.Errorf("invalid security level: %s", level)
+}
+
+// VerifyDetachedSignatureWithTrustedKeys checks that the detached GPG signature
+// (in the signaturePath file) matches the given targetPath file and is an
+// authentic signature from the bundled Arduino keychain or from one of the
+// public keys in the trustedKeys files. If any of the above conditions fails
+// this function returns false. The PGP entity that produced the signature is
+// returned too.
+func VerifyDetachedSignatureWithTrustedKeys(targetPath *paths.Path, signaturePath *paths.Path, trustedKeys paths.PathList) (bool, *openpgp.Entity, error) {
+	keysBox, err := rice.FindBox("keys")
+	if err != nil {
+		panic("could not find bundled signature keys")
+	}
+	keyRing := keysBox.MustBytes("arduino_public.gpg.key")
+	for _, key := range trustedKeys {
+		data, err := key.ReadFile()
+		if err != nil {
+			return false, nil, fmt.Errorf("reading trusted key: %s", err)
+		}
+		keyRing = append(append(keyRing, '\n'), data...)
+	}
+	return VerifySignature(targetPath, signaturePath, bytes.NewReader(keyRing))
+}
@@ -78,3 +78,32 @@ func TestVerifySignature(t *testing.T) {
 	require.Nil(t, signer)
 	require.Error(t, err)
 }
+
+func TestVerifyDetachedSignatureWithTrustedKeys(t *testing.T) {
+	res, _, err := VerifyDetachedSignatureWithTrustedKeys(ModuleFWIndexPath, ModuleFWSignaturePath, nil)
+	require.False(t, res)
+	require.Error(t, err)
+
+	trustedKeys := paths.NewPathList(ModuleFWIndexKey.String())
+	res, signer, err := VerifyDetachedSignatureWithTrustedKeys(ModuleFWIndexPath, ModuleFWSignaturePath, trustedKeys)
+	require.NoError(t, err)
+	require.True(t, res)
+	require.Equal(t, uint64(0x82f2d7c7c5a22a73), signer.PrimaryKey.KeyId)
+
+	// The bundled keys are still trusted
+	res, signer, err = VerifyDetachedSignatureWithTrustedKeys(PackageIndexPath, PackageSignaturePath, trustedKeys)
+	require.NoError(t, err)
+	require.True(t, res)
+	require.Equal(t, uint64(0x7baf404c2dfab4ae), signer.PrimaryKey.KeyId)
+}
+
+func TestParseLevel(t *testing.T) {
+	level, err := ParseLevel("")
+	require.NoError(t, err)
+	require.Equal(t, LevelWarn, level)
+	level, err = ParseLevel("strict")
+	require.NoError(t, err)
+	require.Equal(t, LevelStrict, level)
+	_, err = ParseLevel("paranoid")
+	require.Error(t, err)
+}
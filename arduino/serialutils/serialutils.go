@@ -79,6 +79,9 @@ type ResetProgressCallbacks struct {
 	Debug func(msg string)
 }
 
+// DefaultWaitTimeout is the time Reset waits for the new port to appear
+const DefaultWaitTimeout = 10 * time.Second
+
 // Reset a board using the 1200 bps port-touch and wait for new ports.
 // Both reset and wait are optional:
 // - if port is "" touch will be skipped
@@ -88,6 +91,13 @@ type ResetProgressCallbacks struct {
 // if the wait parameter is false.
 // The error is set if the port listing fails.
 func Reset(portToTouch string, wait bool, cb *ResetProgressCallbacks) (string, error) {
+	return ResetWithTimeout(portToTouch, wait, DefaultWaitTimeout, cb)
+}
+
+// ResetWithTimeout is like Reset, but waits for the new port to appear for
+// the given time. Native USB boards may take longer than DefaultWaitTimeout
+// to re-enumerate.
+func ResetWithTimeout(portToTouch string, wait bool, timeout time.Duration, cb *ResetProgressCallbacks) (string, error) {
 	last, err := getPortMap()
 	if cb != nil && cb.Debug != nil {
 		cb.Debug(fmt.Sprintf("LAST: %v", last))
@@ -115,7 +125,7 @@ func Reset(portToTouch string, wait bool, cb *ResetProgressCallbacks) (string, e
 		cb.WaitingForNewSerial()
 	}
 
-	deadline := time.Now().Add(10 * time.Second)
+	deadline := time.Now().Add(timeout)
 	for time.Now().Before(deadline) {
 		now, err := getPortMap()
 		if err != nil {
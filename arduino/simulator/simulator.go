@@ -0,0 +1,142 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package simulator
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	paths "github.com/arduino/go-paths-helper"
+	properties "github.com/arduino/go-properties-orderedmap"
+)
+
+// Backend is a simulator able to run the compiled sketches
+type Backend string
+
+const (
+	// BackendSimavr runs AVR sketches with simavr
+	BackendSimavr Backend = "simavr"
+	// BackendQEMU runs AVR sketches with qemu-system-avr
+	BackendQEMU Backend = "qemu"
+	// BackendRenode runs sketches with Renode, using a script describing the
+	// machine
+	BackendRenode Backend = "renode"
+)
+
+// Backends is the list of the supported simulators
+var Backends = []Backend{BackendSimavr, BackendQEMU, BackendRenode}
+
+var executables = map[Backend]string{
+	BackendSimavr: "simavr",
+	BackendQEMU:   "qemu-system-avr",
+	BackendRenode: "renode",
+}
+
+// qemuMachines maps the AVR MCUs to the QEMU machines using them
+var qemuMachines = map[string]string{
+	"atmega168":  "arduino-duemilanove",
+	"atmega328p": "arduino-uno",
+	"atmega1280": "arduino-mega",
+	"atmega2560": "arduino-mega-2560-v3",
+}
+
+var lookPath = exec.LookPath
+
+// Command returns the command line that runs the firmware in the elf file
+// with the given backend, the simulated UART is connected to stdin and
+// stdout. If backend is empty it's read from the simulator.backend board
+// property or chosen among the simulators found in the PATH that support the
+// architecture.
+//
+// The board properties may also define simulator.qemu.machine, the QEMU
+// machine to emulate, and simulator.renode.script, the Renode script that
+// creates the machine and loads the firmware from the $elf variable. The
+// script argument overrides the latter.
+func Command(backend Backend, arch string, props *properties.Map, elf, script *paths.Path) ([]string, error) {
+	if script == nil && props.Get("simulator.renode.script") != "" {
+		script = paths.New(props.ExpandPropsInString(props.Get("simulator.renode.script")))
+	}
+	if backend == "" {
+		backend = Backend(props.Get("simulator.backend"))
+	}
+	if backend == "" {
+		backend = detect(arch, props, script)
+	}
+	if backend == "" {
+		return nil, fmt.Errorf("no simulator found for the %s architecture", arch)
+	}
+
+	exe, ok := executables[backend]
+	if !ok {
+		return nil, fmt.Errorf("invalid simulator: %s", backend)
+	}
+	if path, err := lookPath(exe); err == nil {
+		exe = path
+	} else {
+		return nil, fmt.Errorf("%s not found in PATH", exe)
+	}
+
+	switch backend {
+	case BackendSimavr:
+		mcu := props.Get("build.mcu")
+		if arch != "avr" || mcu == "" {
+			return nil, fmt.Errorf("simavr only supports AVR boards")
+		}
+		cmd := []string{exe, "-m", mcu}
+		if freq := strings.TrimRight(props.Get("build.f_cpu"), "uUlL"); freq != "" {
+			cmd = append(cmd, "-f", freq)
+		}
+		return append(cmd, elf.String()), nil
+
+	case BackendQEMU:
+		machine := props.Get("simulator.qemu.machine")
+		if machine == "" {
+			machine = qemuMachines[props.Get("build.mcu")]
+		}
+		if machine == "" {
+			return nil, fmt.Errorf("no QEMU machine found for MCU %s", props.Get("build.mcu"))
+		}
+		return []string{exe, "-machine", machine, "-bios", elf.String(), "-display", "none", "-monitor", "none", "-serial", "stdio"}, nil
+
+	default: // BackendRenode
+		if script == nil {
+			return nil, fmt.Errorf("the Renode simulator requires a script creating the machine")
+		}
+		return []string{exe, "--disable-xwt", "--console", "--plain", "-e", fmt.Sprintf("$elf=@%s; include @%s", elf, script)}, nil
+	}
+}
+
+// detect returns the first simulator found in the PATH that supports the
+// architecture, or an empty string if none is found
+func detect(arch string, props *properties.Map, script *paths.Path) Backend {
+	candidates := []Backend{}
+	if arch == "avr" {
+		candidates = append(candidates, BackendSimavr)
+		if qemuMachines[props.Get("build.mcu")] != "" || props.Get("simulator.qemu.machine") != "" {
+			candidates = append(candidates, BackendQEMU)
+		}
+	}
+	if script != nil {
+		candidates = append(candidates, BackendRenode)
+	}
+	for _, backend := range candidates {
+		if _, err := lookPath(executables[backend]); err == nil {
+			return backend
+		}
+	}
+	return ""
+}
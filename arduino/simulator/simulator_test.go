@@ -0,0 +1,81 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package simulator
+
+import (
+	"fmt"
+	"testing"
+
+	paths "github.com/arduino/go-paths-helper"
+	properties "github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func fakePath(found ...string) func(string) (string, error) {
+	return func(exe string) (string, error) {
+		for _, f := range found {
+			if f == exe {
+				return "/usr/bin/" + exe, nil
+			}
+		}
+		return "", fmt.Errorf("%s not found", exe)
+	}
+}
+
+func TestCommand(t *testing.T) {
+	defer func(f func(string) (string, error)) { lookPath = f }(lookPath)
+	elf := paths.New("/tmp/build/Blink.ino.elf")
+	uno := properties.NewFromHashmap(map[string]string{"build.mcu": "atmega328p", "build.f_cpu": "16000000L"})
+
+	lookPath = fakePath("simavr", "qemu-system-avr")
+	cmd, err := Command("", "avr", uno, elf, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"/usr/bin/simavr", "-m", "atmega328p", "-f", "16000000", "/tmp/build/Blink.ino.elf"}, cmd)
+
+	lookPath = fakePath("qemu-system-avr")
+	cmd, err = Command("", "avr", uno, elf, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"/usr/bin/qemu-system-avr", "-machine", "arduino-uno", "-bios", "/tmp/build/Blink.ino.elf",
+		"-display", "none", "-monitor", "none", "-serial", "stdio"}, cmd)
+
+	_, err = Command(BackendSimavr, "avr", uno, elf, nil)
+	require.EqualError(t, err, "simavr not found in PATH")
+
+	// The platform can select the simulator and the machine
+	lookPath = fakePath("simavr", "qemu-system-avr")
+	custom := properties.NewFromHashmap(map[string]string{"build.mcu": "atmega4809", "simulator.backend": "qemu", "simulator.qemu.machine": "custom"})
+	cmd, err = Command("", "megaavr", custom, elf, nil)
+	require.NoError(t, err)
+	require.Equal(t, "custom", cmd[2])
+
+	_, err = Command(BackendQEMU, "megaavr", properties.NewFromHashmap(map[string]string{"build.mcu": "atmega4809"}), elf, nil)
+	require.EqualError(t, err, "no QEMU machine found for MCU atmega4809")
+
+	lookPath = fakePath("renode")
+	samd := properties.NewFromHashmap(map[string]string{
+		"runtime.platform.path":   "/platform",
+		"simulator.renode.script": "{runtime.platform.path}/renode/mkr.resc",
+	})
+	cmd, err = Command("", "samd", samd, elf, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"/usr/bin/renode", "--disable-xwt", "--console", "--plain", "-e",
+		"$elf=@/tmp/build/Blink.ino.elf; include @/platform/renode/mkr.resc"}, cmd)
+
+	_, err = Command("", "samd", properties.NewMap(), elf, nil)
+	require.EqualError(t, err, "no simulator found for the samd architecture")
+	_, err = Command("invalid", "avr", uno, elf, nil)
+	require.EqualError(t, err, "invalid simulator: invalid")
+}
@@ -0,0 +1,360 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package check
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino/globals"
+	"github.com/arduino/arduino-cli/arduino/libraries"
+	"github.com/arduino/arduino-cli/arduino/sketches"
+	paths "github.com/arduino/go-paths-helper"
+)
+
+// Severity is the severity of a Diagnostic
+type Severity string
+
+const (
+	// SeverityError is used for the problems that prevent the sketch from
+	// being compiled or shared
+	SeverityError Severity = "error"
+	// SeverityWarning is used for the problems that may cause failures on
+	// other systems or boards
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic is a problem found in a sketch
+type Diagnostic struct {
+	Severity Severity `json:"severity"`
+	Rule     string   `json:"rule"`
+	File     string   `json:"file,omitempty"`
+	Line     int      `json:"line,omitempty"`
+	Message  string   `json:"message"`
+}
+
+func (d *Diagnostic) String() string {
+	location := ""
+	if d.File != "" {
+		location = d.File + ":"
+		if d.Line > 0 {
+			location += fmt.Sprintf("%d:", d.Line)
+		}
+		location += " "
+	}
+	return fmt.Sprintf("%s%s: %s [%s]", location, d.Severity, d.Message, d.Rule)
+}
+
+// Sketch runs all the checks on the sketch in the given folder. The paths of
+// the diagnostics are relative to the sketch folder.
+func Sketch(sketchPath *paths.Path) ([]*Diagnostic, error) {
+	sketchPath, err := sketchPath.Abs()
+	if err != nil {
+		return nil, fmt.Errorf("getting sketch path: %s", err)
+	}
+	if !sketchPath.IsDir() {
+		sketchPath = sketchPath.Parent()
+	}
+	files, err := sketchPath.ReadDirRecursive()
+	if err != nil {
+		return nil, fmt.Errorf("reading sketch folder: %s", err)
+	}
+	files.FilterOutDirs()
+	// The build folder contains the exported binaries
+	files.FilterOutPrefix(sketchPath.Join("build").String())
+	files.Sort()
+
+	c := &checker{sketchPath: sketchPath, files: files}
+	c.checkStructure()
+	c.checkEntryPoints()
+	c.checkLibraries()
+	c.checkSecrets()
+	c.checkPortability()
+	sort.SliceStable(c.diagnostics, func(i, j int) bool {
+		return c.diagnostics[i].File < c.diagnostics[j].File
+	})
+	return c.diagnostics, nil
+}
+
+type checker struct {
+	sketchPath  *paths.Path
+	files       paths.PathList
+	diagnostics []*Diagnostic
+}
+
+func (c *checker) add(severity Severity, rule string, file *paths.Path, line int, format string, args ...interface{}) {
+	d := &Diagnostic{Severity: severity, Rule: rule, Line: line, Message: fmt.Sprintf(format, args...)}
+	if file != nil {
+		d.File = c.rel(file)
+	}
+	c.diagnostics = append(c.diagnostics, d)
+}
+
+func (c *checker) rel(file *paths.Path) string {
+	rel, err := c.sketchPath.RelTo(file)
+	if err != nil {
+		return file.String()
+	}
+	return filepath.ToSlash(rel.String())
+}
+
+// sources returns the files of the sketch with the given extensions that
+// are compiled: the ones in the sketch folder and in the src folder, except
+// the vendored libraries. If root is true only the files in the sketch folder
+// are returned.
+func (c *checker) sources(root bool, extensions ...string) paths.PathList {
+	libraryDirs := paths.NewPathList(c.sketchPath.Join("libraries").String())
+	for _, file := range c.files {
+		if file.Base() == "library.properties" {
+			libraryDirs.Add(file.Parent())
+		}
+	}
+
+	res := paths.PathList{}
+	for _, file := range c.files {
+		inRoot := file.Parent().EqualsTo(c.sketchPath)
+		if root && !inRoot {
+			continue
+		}
+		if inside, _ := file.IsInsideDir(c.sketchPath.Join("src")); !inRoot && !inside {
+			continue
+		}
+		if isInsideAny(file, libraryDirs) {
+			continue
+		}
+		for _, ext := range extensions {
+			if file.Ext() == ext {
+				res.Add(file)
+			}
+		}
+	}
+	return res
+}
+
+func isInsideAny(file *paths.Path, dirs paths.PathList) bool {
+	for _, dir := range dirs {
+		if inside, _ := file.IsInsideDir(dir); inside {
+			return true
+		}
+	}
+	return false
+}
+
+var validSketchName = regexp.MustCompile(`^[0-9a-zA-Z][0-9a-zA-Z_.-]{0,62}$`)
+
+// checkStructure checks the name of the sketch folder and of its main file
+func (c *checker) checkStructure() {
+	name := c.sketchPath.Base()
+	if !validSketchName.MatchString(name) {
+		c.add(SeverityWarning, "sketch-name", nil, 0,
+			"sketch name %s should start with a letter or a number and contain only letters, numbers, underscores, dots and dashes, up to 63 characters", name)
+	}
+
+	if _, err := sketches.NewSketchFromPath(c.sketchPath); err != nil {
+		c.add(SeverityError, "main-file", nil, 0, "%s", err)
+	}
+
+	ino := c.sources(true, globals.MainFileValidExtension)
+	pde := c.sources(true, ".pde")
+	for _, file := range pde {
+		if len(ino) > 0 {
+			c.add(SeverityError, "mixed-pde-ino", file, 0, "the sketch mixes .pde and .ino files, rename it to %s", strings.TrimSuffix(file.Base(), ".pde")+".ino")
+		} else {
+			c.add(SeverityWarning, "pde-deprecated", file, 0, "the .pde extension is deprecated, rename it to %s", strings.TrimSuffix(file.Base(), ".pde")+".ino")
+		}
+	}
+
+	// Files differing only by case collide on case-insensitive file systems
+	seen := map[string]*paths.Path{}
+	for _, file := range c.files {
+		key := strings.ToLower(file.String())
+		if other, ok := seen[key]; ok {
+			c.add(SeverityError, "case-collision", file, 0, "the name differs only by case from %s", c.rel(other))
+			continue
+		}
+		seen[key] = file
+	}
+}
+
+var (
+	commentRegexp    = regexp.MustCompile(`(?s)/\*.*?\*/|//[^\n]*`)
+	entryPointRegexp = regexp.MustCompile(`\bvoid\s+(setup|loop)\s*\(\s*(void)?\s*\)\s*\{`)
+)
+
+// checkEntryPoints checks that setup() and loop() are defined exactly once in
+// the sketch
+func (c *checker) checkEntryPoints() {
+	definitions := map[string][]string{}
+	for _, file := range c.sources(false, ".ino", ".pde", ".cpp", ".c") {
+		data, err := file.ReadFile()
+		if err != nil {
+			continue
+		}
+		source := stripComments(string(data))
+		for _, m := range entryPointRegexp.FindAllStringSubmatchIndex(source, -1) {
+			name := source[m[2]:m[3]]
+			location := fmt.Sprintf("%s:%d", c.rel(file), lineOf(source, m[0]))
+			definitions[name] = append(definitions[name], location)
+		}
+	}
+	for _, name := range []string{"setup", "loop"} {
+		switch len(definitions[name]) {
+		case 0:
+			c.add(SeverityError, "missing-"+name, nil, 0, "%s() is not defined", name)
+		case 1:
+		default:
+			c.add(SeverityError, "duplicate-"+name, nil, 0, "%s() is defined more than once: %s", name, strings.Join(definitions[name], ", "))
+		}
+	}
+}
+
+// checkLibraries lints the libraries vendored in the sketch
+func (c *checker) checkLibraries() {
+	for _, file := range c.files {
+		if file.Base() != "library.properties" {
+			continue
+		}
+		lib, err := libraries.Load(file.Parent(), libraries.Unmanaged)
+		if err != nil {
+			c.add(SeverityError, "library-properties", file, 0, "%s", err)
+			continue
+		}
+		warnings, err := lib.Lint()
+		if err != nil {
+			c.add(SeverityError, "library-properties", file, 0, "%s", err)
+			continue
+		}
+		for _, warning := range warnings {
+			c.add(SeverityWarning, "library-properties", file, 0, "%s", warning)
+		}
+	}
+}
+
+var secretRegexp = regexp.MustCompile(`(?m)^\s*#define\s+(\w+)\s+"([^"]+)"`)
+
+// checkSecrets checks that the values defined in arduino_secrets.h are not
+// shared with the sketch: the file should be ignored by git.
+func (c *checker) checkSecrets() {
+	for _, file := range c.files {
+		if !strings.EqualFold(file.Base(), "arduino_secrets.h") {
+			continue
+		}
+		data, err := file.ReadFile()
+		if err != nil {
+			continue
+		}
+		matches := secretRegexp.FindAllStringSubmatchIndex(string(data), -1)
+		if len(matches) == 0 || c.gitIgnored(file) {
+			continue
+		}
+		for _, m := range matches {
+			name := string(data[m[2]:m[3]])
+			c.add(SeverityError, "secrets", file, lineOf(string(data), m[0]),
+				"%s has a value and may be committed, add arduino_secrets.h to .gitignore", name)
+		}
+	}
+}
+
+// gitIgnored returns true if a .gitignore file in the folders of the sketch
+// or in their parents, up to the root of the git repository, matches the file
+func (c *checker) gitIgnored(file *paths.Path) bool {
+	for _, dir := range file.Parent().Parents() {
+		if data, err := dir.Join(".gitignore").ReadFile(); err == nil {
+			rel, err := dir.RelTo(file)
+			if err != nil {
+				continue
+			}
+			for _, pattern := range strings.Split(string(data), "\n") {
+				pattern = strings.TrimSpace(pattern)
+				if pattern == "" || strings.HasPrefix(pattern, "#") || strings.HasPrefix(pattern, "!") {
+					continue
+				}
+				pattern = strings.TrimPrefix(pattern, "/")
+				if ok, _ := filepath.Match(pattern, filepath.ToSlash(rel.String())); ok {
+					return true
+				}
+				if ok, _ := filepath.Match(pattern, file.Base()); ok && !strings.Contains(pattern, "/") {
+					return true
+				}
+			}
+		}
+		if dir.Join(".git").Exist() {
+			break
+		}
+	}
+	return false
+}
+
+var includeRegexp = regexp.MustCompile(`(?m)^\s*#\s*include\s*([<"])([^>"]+)[>"]`)
+
+// checkPortability checks the includes that only work on some systems or on
+// some architectures
+func (c *checker) checkPortability() {
+	for _, file := range c.sources(false, ".ino", ".pde", ".cpp", ".c", ".h", ".hpp", ".hh", ".S") {
+		data, err := file.ReadFile()
+		if err != nil {
+			continue
+		}
+		source := string(data)
+		for _, m := range includeRegexp.FindAllStringSubmatchIndex(source, -1) {
+			line := lineOf(source, m[0])
+			quote, include := source[m[2]:m[3]], source[m[4]:m[5]]
+			if strings.Contains(include, `\`) {
+				c.add(SeverityWarning, "include-backslash", file, line, "use / instead of \\ in the path of %s", include)
+				include = strings.Replace(include, `\`, "/", -1)
+			}
+			if paths.New(include).IsAbs() || filepath.VolumeName(include) != "" || strings.HasPrefix(include, "/") {
+				c.add(SeverityWarning, "include-absolute", file, line, "absolute include path %s is not available on other systems", include)
+				continue
+			}
+			if quote == `"` {
+				c.checkIncludeCase(file, line, include)
+			}
+			if strings.HasPrefix(include, "avr/") && include != "avr/pgmspace.h" {
+				c.add(SeverityWarning, "arch-specific-include", file, line, "%s is only available on AVR boards", include)
+			}
+		}
+	}
+}
+
+// checkIncludeCase checks that an included file of the sketch is referenced
+// with the same case, otherwise it's not found on case-sensitive file systems
+func (c *checker) checkIncludeCase(file *paths.Path, line int, include string) {
+	target := file.Parent().Join(include).Clean()
+	if target.Exist() && c.files.Contains(target) {
+		return
+	}
+	for _, other := range c.files {
+		if strings.EqualFold(other.String(), target.String()) {
+			c.add(SeverityWarning, "include-case", file, line, "%s doesn't match the case of %s", include, c.rel(other))
+			return
+		}
+	}
+}
+
+func stripComments(source string) string {
+	// comments are replaced with spaces and newlines to keep the line numbers
+	return commentRegexp.ReplaceAllStringFunc(source, func(comment string) string {
+		return strings.Repeat("\n", strings.Count(comment, "\n"))
+	})
+}
+
+func lineOf(source string, offset int) int {
+	return strings.Count(source[:offset], "\n") + 1
+}
@@ -0,0 +1,107 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package check
+
+import (
+	"testing"
+
+	paths "github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func createSketch(t *testing.T, dir *paths.Path, files map[string]string) {
+	for name, content := range files {
+		file := dir.Join(name)
+		require.NoError(t, file.Parent().MkdirAll())
+		require.NoError(t, file.WriteFile([]byte(content)))
+	}
+}
+
+func rules(diagnostics []*Diagnostic) []string {
+	res := []string{}
+	for _, d := range diagnostics {
+		res = append(res, d.Rule)
+	}
+	return res
+}
+
+func TestCheckValidSketch(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "sketch_check")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+
+	sketchPath := tmp.Join("Blink")
+	createSketch(t, sketchPath, map[string]string{
+		"Blink.ino": "#include \"src/led.h\"\n" +
+			"// void setup() {} in a comment\n" +
+			"void setup()\n{\n  ledSetup();\n}\n\nvoid loop() {\n}\n",
+		"src/led.h":           "void ledSetup();\n",
+		"src/led.cpp":         "#include <Arduino.h>\n#include \"led.h\"\nvoid ledSetup() {}\n",
+		"arduino_secrets.h":   "#define SECRET_SSID \"home\"\n",
+		".gitignore":          "/arduino_secrets.h\n",
+		"build/Blink.ino.hex": "",
+		// examples of vendored libraries are not part of the sketch
+		"libraries/Led/library.properties":     "name=Led\nversion=1.0.0\nauthor=me\nmaintainer=me\n",
+		"libraries/Led/examples/Demo/Demo.ino": "void setup() {}\nvoid loop() {}\n",
+	})
+
+	diagnostics, err := Sketch(sketchPath)
+	require.NoError(t, err)
+	require.Empty(t, diagnostics)
+}
+
+func TestCheckInvalidSketch(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "sketch_check")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+
+	sketchPath := tmp.Join("My Sketch")
+	createSketch(t, sketchPath, map[string]string{
+		"My Sketch.ino": "#include \"Config.h\"\n#include \"src\\\\util.h\"\n#include <avr/io.h>\n" +
+			"#include \"arduino_secrets.h\"\nvoid setup() {}\n",
+		"Other.pde":                  "void setup() {}\n",
+		"config.h":                   "",
+		"src/util.h":                 "",
+		"arduino_secrets.h":          "#define SECRET_SSID \"home\"\n#define SECRET_PASS \"\"\n",
+		"lib/Bad/library.properties": "name=Bad\nversion=one\ncategory=Stuff\n",
+		"lib/Bad/.cache/x":           "",
+	})
+
+	diagnostics, err := Sketch(sketchPath)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"sketch-name",
+		"duplicate-setup",
+		"missing-loop",
+		"include-case",
+		"include-backslash",
+		"arch-specific-include",
+		"mixed-pde-ino",
+		"secrets",
+		"library-properties",
+		"library-properties",
+		"library-properties",
+		"library-properties",
+		"library-properties",
+	}, rules(diagnostics))
+
+	d := diagnostics[3]
+	require.Equal(t, "My Sketch.ino", d.File)
+	require.Equal(t, 1, d.Line)
+	require.Equal(t, "My Sketch.ino:1: warning: Config.h doesn't match the case of config.h [include-case]", d.String())
+	require.Equal(t, "arduino_secrets.h", diagnostics[7].File)
+	require.Equal(t, 1, diagnostics[7].Line)
+}
@@ -0,0 +1,178 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package format
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	paths "github.com/arduino/go-paths-helper"
+)
+
+// DefaultStyle is the clang-format configuration used to format the sketches
+// that don't provide their own .clang-format file. It follows the Arduino
+// style used by the Arduino IDE auto format.
+const DefaultStyle = `# Arduino style clang-format configuration
+BasedOnStyle: LLVM
+AccessModifierOffset: -2
+AlignAfterOpenBracket: Align
+AlignConsecutiveAssignments: false
+AlignConsecutiveDeclarations: false
+AlignEscapedNewlines: DontAlign
+AlignOperands: Align
+AlignTrailingComments: true
+AllowAllArgumentsOnNextLine: true
+AllowAllParametersOfDeclarationOnNextLine: true
+AllowShortBlocksOnASingleLine: Always
+AllowShortCaseLabelsOnASingleLine: true
+AllowShortFunctionsOnASingleLine: Empty
+AllowShortIfStatementsOnASingleLine: AllIfsAndElse
+AllowShortLambdasOnASingleLine: Empty
+AllowShortLoopsOnASingleLine: true
+AlwaysBreakBeforeMultilineStrings: false
+AlwaysBreakTemplateDeclarations: No
+BinPackArguments: true
+BinPackParameters: true
+BreakBeforeBinaryOperators: NonAssignment
+BreakBeforeBraces: Attach
+BreakBeforeTernaryOperators: true
+BreakConstructorInitializers: BeforeColon
+BreakStringLiterals: false
+ColumnLimit: 0
+ContinuationIndentWidth: 2
+Cpp11BracedListStyle: false
+DerivePointerAlignment: true
+IncludeBlocks: Preserve
+IndentCaseLabels: true
+IndentPPDirectives: None
+IndentWidth: 2
+KeepEmptyLinesAtTheStartOfBlocks: true
+MaxEmptyLinesToKeep: 100000
+NamespaceIndentation: None
+PointerAlignment: Right
+ReflowComments: false
+SortIncludes: Never
+SortUsingDeclarations: false
+SpaceAfterCStyleCast: false
+SpaceBeforeAssignmentOperators: true
+SpaceBeforeParens: ControlStatements
+SpaceInEmptyParentheses: false
+SpacesBeforeTrailingComments: 2
+SpacesInAngles: false
+SpacesInContainerLiterals: false
+SpacesInParentheses: false
+SpacesInSquareBrackets: false
+TabWidth: 2
+UseTab: Never
+`
+
+// sourceExtensions lists the extensions of the files formatted by clang-format
+var sourceExtensions = []string{".ino", ".pde", ".c", ".cpp", ".h", ".hh", ".hpp", ".tpp", ".ipp"}
+
+// Style returns the value of the clang-format --style option to use for the
+// given sketch: "file" if a .clang-format file is found in the sketch folder
+// or in one of its parents, otherwise the DefaultStyle as an inline
+// configuration.
+func Style(sketchPath *paths.Path) string {
+	for dir := sketchPath; ; dir = dir.Parent() {
+		if dir.Join(".clang-format").Exist() || dir.Join("_clang-format").Exist() {
+			return "file"
+		}
+		if dir.Parent().EquivalentTo(dir) {
+			break
+		}
+	}
+	options := []string{}
+	for _, line := range strings.Split(DefaultStyle, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		options = append(options, line)
+	}
+	return "{" + strings.Join(options, ", ") + "}"
+}
+
+// Files returns the source files of the sketch in the given folder: the files
+// in the sketch root and in the src folder and, if includeLibraries is true,
+// the files of the libraries vendored in the libraries folder.
+func Files(sketchPath *paths.Path, includeLibraries bool) (paths.PathList, error) {
+	files, err := sketchPath.ReadDir()
+	if err != nil {
+		return nil, fmt.Errorf("reading sketch folder: %s", err)
+	}
+	files.FilterOutDirs()
+
+	folders := []string{"src"}
+	if includeLibraries {
+		folders = append(folders, "libraries")
+	}
+	for _, folder := range folders {
+		dir := sketchPath.Join(folder)
+		if !dir.IsDir() {
+			continue
+		}
+		subFiles, err := dir.ReadDirRecursive()
+		if err != nil {
+			return nil, fmt.Errorf("reading %s folder: %s", folder, err)
+		}
+		subFiles.FilterOutDirs()
+		files.AddAll(subFiles)
+	}
+
+	files.FilterSuffix(sourceExtensions...)
+	res := paths.PathList{}
+	for _, file := range files {
+		// Skip hidden files and the content of hidden folders (e.g. .git)
+		rel, err := sketchPath.RelTo(file)
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(rel.String(), ".") || strings.Contains(rel.String(), "/.") || strings.Contains(rel.String(), "\\.") {
+			continue
+		}
+		res.Add(file)
+	}
+	res.Sort()
+	return res, nil
+}
+
+// Format runs clang-format on the given file and returns the formatted source
+// and whether it differs from the current content of the file. The file is
+// not modified. Sketch files (.ino and .pde) are formatted as C++ sources.
+func Format(clangFormat *paths.Path, file *paths.Path, style string) ([]byte, bool, error) {
+	source, err := file.ReadFile()
+	if err != nil {
+		return nil, false, fmt.Errorf("reading %s: %s", file, err)
+	}
+
+	assumeFilename := file.String()
+	if ext := file.Ext(); ext == ".ino" || ext == ".pde" {
+		assumeFilename += ".cpp"
+	}
+	cmd := exec.Command(clangFormat.String(), "--style="+style, "--assume-filename="+assumeFilename)
+	cmd.Stdin = bytes.NewReader(source)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, false, fmt.Errorf("formatting %s: %s %s", file, err, strings.TrimSpace(stderr.String()))
+	}
+	formatted := stdout.Bytes()
+	return formatted, !bytes.Equal(source, formatted), nil
+}
@@ -0,0 +1,121 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package format
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	paths "github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func createSketch(t *testing.T, dir *paths.Path, files map[string]string) {
+	for name, content := range files {
+		file := dir.Join(name)
+		require.NoError(t, file.Parent().MkdirAll())
+		require.NoError(t, file.WriteFile([]byte(content)))
+	}
+}
+
+func TestStyle(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "sketch_format")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+
+	sketchPath := tmp.Join("Blink")
+	require.NoError(t, sketchPath.MkdirAll())
+	style := Style(sketchPath)
+	require.True(t, strings.HasPrefix(style, "{BasedOnStyle: LLVM, "))
+	require.Contains(t, style, "IndentWidth: 2")
+	require.NotContains(t, style, "#")
+
+	// A .clang-format in a parent folder is used for all the sketches in it
+	require.NoError(t, tmp.Join(".clang-format").WriteFile([]byte("BasedOnStyle: Google\n")))
+	require.Equal(t, "file", Style(sketchPath))
+}
+
+func TestFiles(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "sketch_format")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+
+	sketchPath := tmp.Join("Blink")
+	createSketch(t, sketchPath, map[string]string{
+		"Blink.ino":                     "",
+		"led.h":                         "",
+		"README.md":                     "",
+		"src/led/led.cpp":               "",
+		"build/Blink.ino.cpp":           "",
+		".vscode/snippet.cpp":           "",
+		"libraries/Led/src/Led.cpp":     "",
+		"libraries/Led/src/.hidden.cpp": "",
+	})
+
+	rel := func(files paths.PathList) []string {
+		res := []string{}
+		for _, file := range files {
+			r, err := sketchPath.RelTo(file)
+			require.NoError(t, err)
+			res = append(res, filepath.ToSlash(r.String()))
+		}
+		return res
+	}
+
+	files, err := Files(sketchPath, false)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Blink.ino", "led.h", "src/led/led.cpp"}, rel(files))
+
+	files, err = Files(sketchPath, true)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Blink.ino", "led.h", "libraries/Led/src/Led.cpp", "src/led/led.cpp"}, rel(files))
+}
+
+func TestFormat(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake clang-format is a shell script")
+	}
+	tmp, err := paths.MkTempDir("", "sketch_format")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+
+	// The fake clang-format removes the trailing spaces
+	clangFormat := tmp.Join("clang-format")
+	require.NoError(t, clangFormat.WriteFile([]byte("#!/bin/sh\nsed 's/ *$//'\n")))
+	require.NoError(t, os.Chmod(clangFormat.String(), 0755))
+
+	createSketch(t, tmp, map[string]string{
+		"Blink/Blink.ino": "void setup() {}  \nvoid loop() {}\n",
+		"Blink/led.h":     "void ledSetup();\n",
+	})
+
+	formatted, changed, err := Format(clangFormat, tmp.Join("Blink", "Blink.ino"), "file")
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Equal(t, "void setup() {}\nvoid loop() {}\n", string(formatted))
+
+	_, changed, err = Format(clangFormat, tmp.Join("Blink", "led.h"), "file")
+	require.NoError(t, err)
+	require.False(t, changed)
+
+	require.NoError(t, clangFormat.WriteFile([]byte("#!/bin/sh\necho invalid style >&2\nexit 1\n")))
+	_, _, err = Format(clangFormat, tmp.Join("Blink", "led.h"), "file")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid style")
+}
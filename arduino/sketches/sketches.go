@@ -37,6 +37,18 @@ type Sketch struct {
 // Metadata is the kind of data associated to a project such as the connected board
 type Metadata struct {
 	CPU BoardMetadata `json:"cpu,omitempty" gorethink:"cpu"`
+	// Platform required by the sketch, in the PACKAGER:ARCH[@VERSION] form.
+	// If empty it's derived from the board FQBN.
+	Platform string `json:"platform,omitempty"`
+	// Libraries required by the sketch, in the NAME[@VERSION] form
+	Libraries []string `json:"libraries,omitempty"`
+	// Limits on the memory used by the sketch, in bytes or as percentage of
+	// the memory available on the board (e.g. "80%")
+	MaxFlashUsage string `json:"max_flash_usage,omitempty"`
+	MaxRAMUsage   string `json:"max_ram_usage,omitempty"`
+	// Commands run by the builder at the given stages: pre_preprocess,
+	// pre_link and post_build
+	Hooks map[string][]string `json:"hooks,omitempty"`
 }
 
 // BoardMetadata represents the board metadata for the sketch
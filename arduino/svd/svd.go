@@ -0,0 +1,486 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package svd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	paths "github.com/arduino/go-paths-helper"
+	"github.com/pkg/errors"
+)
+
+// Device is the description of a microcontroller loaded from a CMSIS-SVD file
+type Device struct {
+	Name        string
+	Description string
+	Peripherals []*Peripheral
+}
+
+// Peripheral is a peripheral of a Device with its memory mapped registers
+type Peripheral struct {
+	Name        string
+	Description string
+	BaseAddress uint64
+	Registers   []*Register
+}
+
+// Register is a memory mapped register of a Peripheral. The Name of registers
+// that are part of a cluster is prefixed with the cluster name (for example
+// "COUNT16.CTRLA").
+type Register struct {
+	Name        string
+	Description string
+	Address     uint64
+	// Size is the width of the register in bits
+	Size   uint
+	Access string
+	// ReadAction is set when reading the register has side effects (for
+	// example it clears some status flags)
+	ReadAction string
+	Fields     []*Field
+}
+
+// Field is a bit field of a Register
+type Field struct {
+	Name             string
+	Description      string
+	BitOffset        uint
+	BitWidth         uint
+	EnumeratedValues map[uint64]string
+}
+
+// FieldValue is the value of a Field decoded from the value of a Register
+type FieldValue struct {
+	*Field
+	Value uint64
+	// Enum is the name of the enumerated value matching Value, if any
+	Enum string
+}
+
+// Load reads a CMSIS-SVD file
+func Load(file *paths.Path) (*Device, error) {
+	data, err := file.ReadFile()
+	if err != nil {
+		return nil, errors.Wrap(err, "reading SVD file")
+	}
+	return Parse(data)
+}
+
+// Parse decodes the content of a CMSIS-SVD file
+func Parse(data []byte) (*Device, error) {
+	var dev xmlDevice
+	if err := xml.Unmarshal(data, &dev); err != nil {
+		return nil, errors.Wrap(err, "parsing SVD file")
+	}
+
+	defaultSize := uint(32)
+	if dev.Size != "" {
+		size, err := parseNumber(dev.Size)
+		if err != nil {
+			return nil, fmt.Errorf("invalid device size '%s'", dev.Size)
+		}
+		defaultSize = uint(size)
+	}
+
+	byName := map[string]*xmlPeripheral{}
+	for _, p := range dev.Peripherals {
+		byName[p.Name] = p
+	}
+
+	res := &Device{Name: dev.Name, Description: cleanText(dev.Description)}
+	for _, p := range dev.Peripherals {
+		// A derived peripheral inherits the definitions it doesn't override
+		// from the peripheral it is derived from
+		def := p
+		if p.DerivedFrom != "" {
+			base, ok := byName[p.DerivedFrom]
+			if !ok {
+				return nil, fmt.Errorf("peripheral %s derived from unknown peripheral %s", p.Name, p.DerivedFrom)
+			}
+			merged := *base
+			merged.Name = p.Name
+			merged.BaseAddress = p.BaseAddress
+			if p.Description != "" {
+				merged.Description = p.Description
+			}
+			if p.Size != "" {
+				merged.Size = p.Size
+			}
+			if len(p.Registers.Registers) > 0 || len(p.Registers.Clusters) > 0 {
+				merged.Registers = p.Registers
+			}
+			def = &merged
+		}
+
+		baseAddress, err := parseNumber(def.BaseAddress)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base address '%s' of peripheral %s", def.BaseAddress, def.Name)
+		}
+		size := defaultSize
+		if def.Size != "" {
+			s, err := parseNumber(def.Size)
+			if err != nil {
+				return nil, fmt.Errorf("invalid size '%s' of peripheral %s", def.Size, def.Name)
+			}
+			size = uint(s)
+		}
+
+		peripheral := &Peripheral{
+			Name:        def.Name,
+			Description: cleanText(def.Description),
+			BaseAddress: baseAddress,
+		}
+		regs, err := def.Registers.flatten("", baseAddress, size)
+		if err != nil {
+			return nil, fmt.Errorf("peripheral %s: %s", def.Name, err)
+		}
+		peripheral.Registers = regs
+		res.Peripherals = append(res.Peripherals, peripheral)
+	}
+	return res, nil
+}
+
+// FindRegisters returns the registers matching the given patterns, in the
+// order they appear in the device. A pattern is either the name of a
+// peripheral, to select all its registers, or the name of a register in the
+// form PERIPHERAL.REGISTER. Patterns are case insensitive and may contain
+// shell wildcards (for example "TC*.CTRLA").
+func (d *Device) FindRegisters(patterns ...string) ([]*Peripheral, error) {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid register pattern '%s'", pattern)
+		}
+	}
+	matches := func(name string) bool {
+		name = strings.ToUpper(name)
+		for _, pattern := range patterns {
+			pattern = strings.ToUpper(pattern)
+			if ok, _ := path.Match(pattern, name); ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	res := []*Peripheral{}
+	for _, p := range d.Peripherals {
+		if len(patterns) == 0 || matches(p.Name) {
+			res = append(res, p)
+			continue
+		}
+		var selected []*Register
+		for _, r := range p.Registers {
+			// registers of clusters are also selected by the cluster name
+			name := p.Name
+			for _, part := range strings.Split(r.Name, ".") {
+				name += "." + part
+				if matches(name) {
+					selected = append(selected, r)
+					break
+				}
+			}
+		}
+		if len(selected) > 0 {
+			filtered := *p
+			filtered.Registers = selected
+			res = append(res, &filtered)
+		}
+	}
+	return res, nil
+}
+
+// Readable returns true if the register can be read without side effects
+func (r *Register) Readable() bool {
+	return r.Access != "write-only" && r.Access != "writeOnce" && r.ReadAction == ""
+}
+
+// Decode splits the given register value in the values of its fields
+func (r *Register) Decode(value uint64) []*FieldValue {
+	res := []*FieldValue{}
+	for _, f := range r.Fields {
+		v := (value >> f.BitOffset) & (1<<f.BitWidth - 1)
+		res = append(res, &FieldValue{Field: f, Value: v, Enum: f.EnumeratedValues[v]})
+	}
+	return res
+}
+
+type xmlDevice struct {
+	Name        string           `xml:"name"`
+	Description string           `xml:"description"`
+	Size        string           `xml:"size"`
+	Peripherals []*xmlPeripheral `xml:"peripherals>peripheral"`
+}
+
+type xmlPeripheral struct {
+	DerivedFrom string       `xml:"derivedFrom,attr"`
+	Name        string       `xml:"name"`
+	Description string       `xml:"description"`
+	BaseAddress string       `xml:"baseAddress"`
+	Size        string       `xml:"size"`
+	Registers   xmlRegisters `xml:"registers"`
+}
+
+type xmlRegisters struct {
+	Registers []*xmlRegister `xml:"register"`
+	Clusters  []*xmlCluster  `xml:"cluster"`
+}
+
+type xmlDim struct {
+	Dim          string `xml:"dim"`
+	DimIncrement string `xml:"dimIncrement"`
+	DimIndex     string `xml:"dimIndex"`
+}
+
+type xmlCluster struct {
+	xmlDim
+	Name          string         `xml:"name"`
+	AddressOffset string         `xml:"addressOffset"`
+	Size          string         `xml:"size"`
+	Registers     []*xmlRegister `xml:"register"`
+	Clusters      []*xmlCluster  `xml:"cluster"`
+}
+
+type xmlRegister struct {
+	xmlDim
+	Name          string      `xml:"name"`
+	Description   string      `xml:"description"`
+	AddressOffset string      `xml:"addressOffset"`
+	Size          string      `xml:"size"`
+	Access        string      `xml:"access"`
+	ReadAction    string      `xml:"readAction"`
+	Fields        []*xmlField `xml:"fields>field"`
+}
+
+type xmlField struct {
+	Name             string                `xml:"name"`
+	Description      string                `xml:"description"`
+	BitOffset        string                `xml:"bitOffset"`
+	BitWidth         string                `xml:"bitWidth"`
+	Lsb              string                `xml:"lsb"`
+	Msb              string                `xml:"msb"`
+	BitRange         string                `xml:"bitRange"`
+	EnumeratedValues []*xmlEnumeratedValue `xml:"enumeratedValues>enumeratedValue"`
+}
+
+type xmlEnumeratedValue struct {
+	Name  string `xml:"name"`
+	Value string `xml:"value"`
+}
+
+// flatten returns the registers, including the ones inside clusters, with
+// their absolute address
+func (regs *xmlRegisters) flatten(prefix string, baseAddress uint64, size uint) ([]*Register, error) {
+	res := []*Register{}
+	for _, c := range regs.Clusters {
+		instances, err := c.instances()
+		if err != nil {
+			return nil, fmt.Errorf("cluster %s: %s", c.Name, err)
+		}
+		clusterSize := size
+		if c.Size != "" {
+			s, err := parseNumber(c.Size)
+			if err != nil {
+				return nil, fmt.Errorf("invalid size '%s' of cluster %s", c.Size, c.Name)
+			}
+			clusterSize = uint(s)
+		}
+		for _, i := range instances {
+			sub := &xmlRegisters{Registers: c.Registers, Clusters: c.Clusters}
+			clusterRegs, err := sub.flatten(prefix+i.name+".", baseAddress+i.offset, clusterSize)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, clusterRegs...)
+		}
+	}
+	for _, r := range regs.Registers {
+		instances, err := r.instances()
+		if err != nil {
+			return nil, fmt.Errorf("register %s: %s", r.Name, err)
+		}
+		regSize := size
+		if r.Size != "" {
+			s, err := parseNumber(r.Size)
+			if err != nil {
+				return nil, fmt.Errorf("invalid size '%s' of register %s", r.Size, r.Name)
+			}
+			regSize = uint(s)
+		}
+		fields := []*Field{}
+		for _, f := range r.Fields {
+			field, err := f.decode()
+			if err != nil {
+				return nil, fmt.Errorf("register %s: %s", r.Name, err)
+			}
+			fields = append(fields, field)
+		}
+		for _, i := range instances {
+			res = append(res, &Register{
+				Name:        prefix + i.name,
+				Description: cleanText(r.Description),
+				Address:     baseAddress + i.offset,
+				Size:        regSize,
+				Access:      r.Access,
+				ReadAction:  r.ReadAction,
+				Fields:      fields,
+			})
+		}
+	}
+	return res, nil
+}
+
+type instance struct {
+	name   string
+	offset uint64
+}
+
+func (c *xmlCluster) instances() ([]*instance, error) {
+	return c.xmlDim.expand(c.Name, c.AddressOffset)
+}
+
+func (r *xmlRegister) instances() ([]*instance, error) {
+	return r.xmlDim.expand(r.Name, r.AddressOffset)
+}
+
+// expand returns the instances of an array of registers or clusters: the
+// "%s" placeholder in the name is replaced by the index of each element
+func (d *xmlDim) expand(name, addressOffset string) ([]*instance, error) {
+	offset, err := parseNumber(addressOffset)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address offset '%s'", addressOffset)
+	}
+	if d.Dim == "" {
+		return []*instance{{name: name, offset: offset}}, nil
+	}
+	dim, err := parseNumber(d.Dim)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dim '%s'", d.Dim)
+	}
+	increment, err := parseNumber(d.DimIncrement)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dimIncrement '%s'", d.DimIncrement)
+	}
+	indexes := []string{}
+	if d.DimIndex != "" {
+		indexes = expandDimIndex(d.DimIndex)
+	} else {
+		for i := uint64(0); i < dim; i++ {
+			indexes = append(indexes, strconv.FormatUint(i, 10))
+		}
+	}
+	if uint64(len(indexes)) != dim {
+		return nil, fmt.Errorf("dimIndex '%s' doesn't match dim %d", d.DimIndex, dim)
+	}
+	res := []*instance{}
+	for i, index := range indexes {
+		n := strings.Replace(name, "[%s]", index, 1)
+		n = strings.Replace(n, "%s", index, 1)
+		res = append(res, &instance{name: n, offset: offset + uint64(i)*increment})
+	}
+	return res, nil
+}
+
+// expandDimIndex expands a dimIndex in the "A,B,C" or "0-3" form
+func expandDimIndex(dimIndex string) []string {
+	if bounds := strings.SplitN(dimIndex, "-", 2); len(bounds) == 2 {
+		from, err1 := strconv.Atoi(strings.TrimSpace(bounds[0]))
+		to, err2 := strconv.Atoi(strings.TrimSpace(bounds[1]))
+		if err1 == nil && err2 == nil {
+			res := []string{}
+			for i := from; i <= to; i++ {
+				res = append(res, strconv.Itoa(i))
+			}
+			return res
+		}
+	}
+	res := []string{}
+	for _, index := range strings.Split(dimIndex, ",") {
+		res = append(res, strings.TrimSpace(index))
+	}
+	return res
+}
+
+func (f *xmlField) decode() (*Field, error) {
+	res := &Field{Name: f.Name, Description: cleanText(f.Description)}
+	var lsb, msb uint64
+	var err error
+	switch {
+	case f.BitOffset != "":
+		if lsb, err = parseNumber(f.BitOffset); err == nil {
+			var width uint64
+			if width, err = parseNumber(f.BitWidth); err == nil {
+				msb = lsb + width - 1
+			}
+		}
+	case f.Lsb != "":
+		if lsb, err = parseNumber(f.Lsb); err == nil {
+			msb, err = parseNumber(f.Msb)
+		}
+	case f.BitRange != "":
+		// [msb:lsb]
+		bounds := strings.Split(strings.Trim(f.BitRange, "[] "), ":")
+		if len(bounds) != 2 {
+			return nil, fmt.Errorf("invalid bit range '%s' of field %s", f.BitRange, f.Name)
+		}
+		if msb, err = parseNumber(bounds[0]); err == nil {
+			lsb, err = parseNumber(bounds[1])
+		}
+	default:
+		return nil, fmt.Errorf("missing bit range of field %s", f.Name)
+	}
+	if err != nil || msb < lsb || msb >= 64 {
+		return nil, fmt.Errorf("invalid bit range of field %s", f.Name)
+	}
+	res.BitOffset = uint(lsb)
+	res.BitWidth = uint(msb - lsb + 1)
+
+	for _, e := range f.EnumeratedValues {
+		// values with "don't care" bits can't be matched exactly
+		v, err := parseNumber(e.Value)
+		if err != nil {
+			continue
+		}
+		if res.EnumeratedValues == nil {
+			res.EnumeratedValues = map[uint64]string{}
+		}
+		res.EnumeratedValues[v] = e.Name
+	}
+	return res, nil
+}
+
+// parseNumber parses a scaledNonNegativeInteger of the SVD format: a decimal,
+// an hexadecimal (0x prefix) or a binary (# or 0b prefix) number
+func parseNumber(s string) (uint64, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch {
+	case strings.HasPrefix(s, "0x"):
+		return strconv.ParseUint(s[2:], 16, 64)
+	case strings.HasPrefix(s, "#"):
+		return strconv.ParseUint(s[1:], 2, 64)
+	case strings.HasPrefix(s, "0b"):
+		return strconv.ParseUint(s[2:], 2, 64)
+	}
+	return strconv.ParseUint(s, 10, 64)
+}
+
+// cleanText collapses the whitespace of the multiline descriptions
+func cleanText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
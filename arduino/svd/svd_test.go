@@ -0,0 +1,111 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package svd
+
+import (
+	"testing"
+
+	paths "github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	dev, err := Load(paths.New("testdata", "test.svd"))
+	require.NoError(t, err)
+	require.Equal(t, "TESTMCU", dev.Name)
+	require.Equal(t, "Test microcontroller", dev.Description)
+	require.Len(t, dev.Peripherals, 3)
+
+	port := dev.Peripherals[0]
+	require.Equal(t, "PORT", port.Name)
+	require.Len(t, port.Registers, 4)
+	require.Equal(t, "DIRA", port.Registers[0].Name)
+	require.Equal(t, uint64(0x41004400), port.Registers[0].Address)
+	require.Equal(t, "DIRB", port.Registers[1].Name)
+	require.Equal(t, uint64(0x41004480), port.Registers[1].Address)
+	require.Equal(t, uint(32), port.Registers[1].Size)
+
+	ctrl := port.Registers[2]
+	require.Equal(t, uint(8), ctrl.Size)
+	require.Len(t, ctrl.Fields, 2)
+	require.Equal(t, uint(2), ctrl.Fields[1].BitOffset)
+	require.Equal(t, uint(3), ctrl.Fields[1].BitWidth)
+	require.True(t, ctrl.Readable())
+	require.False(t, port.Registers[3].Readable())
+
+	tc3 := dev.Peripherals[1]
+	require.Equal(t, "COUNT16.CTRLA", tc3.Registers[0].Name)
+	require.Equal(t, uint64(0x42002C10), tc3.Registers[1].Address)
+
+	// derived peripherals inherit the registers
+	tc4 := dev.Peripherals[2]
+	require.Equal(t, "TC4", tc4.Name)
+	require.Len(t, tc4.Registers, 2)
+	require.Equal(t, uint64(0x42003010), tc4.Registers[1].Address)
+}
+
+func TestDecode(t *testing.T) {
+	dev, err := Load(paths.New("testdata", "test.svd"))
+	require.NoError(t, err)
+	ctrl := dev.Peripherals[0].Registers[2]
+
+	values := ctrl.Decode(0x0E)
+	require.Len(t, values, 2)
+	require.Equal(t, "ENABLE", values[0].Name)
+	require.Equal(t, uint64(1), values[0].Value)
+	require.Equal(t, "MODE", values[1].Name)
+	require.Equal(t, uint64(3), values[1].Value)
+	require.Equal(t, "RUN", values[1].Enum)
+
+	values = ctrl.Decode(0x10)
+	require.Equal(t, uint64(0), values[0].Value)
+	require.Equal(t, uint64(4), values[1].Value)
+	require.Equal(t, "", values[1].Enum)
+}
+
+func TestFindRegisters(t *testing.T) {
+	dev, err := Load(paths.New("testdata", "test.svd"))
+	require.NoError(t, err)
+
+	names := func(peripherals []*Peripheral) []string {
+		res := []string{}
+		for _, p := range peripherals {
+			for _, r := range p.Registers {
+				res = append(res, p.Name+"."+r.Name)
+			}
+		}
+		return res
+	}
+
+	res, err := dev.FindRegisters("port")
+	require.NoError(t, err)
+	require.Equal(t, []string{"PORT.DIRA", "PORT.DIRB", "PORT.CTRL", "PORT.INTFLAG"}, names(res))
+
+	res, err = dev.FindRegisters("PORT.DIR*", "tc4.count16.count")
+	require.NoError(t, err)
+	require.Equal(t, []string{"PORT.DIRA", "PORT.DIRB", "TC4.COUNT16.COUNT"}, names(res))
+
+	res, err = dev.FindRegisters("TC*.COUNT16")
+	require.NoError(t, err)
+	require.Equal(t, []string{"TC3.COUNT16.CTRLA", "TC3.COUNT16.COUNT", "TC4.COUNT16.CTRLA", "TC4.COUNT16.COUNT"}, names(res))
+
+	res, err = dev.FindRegisters("ADC")
+	require.NoError(t, err)
+	require.Empty(t, res)
+
+	_, err = dev.FindRegisters("[")
+	require.Error(t, err)
+}
@@ -0,0 +1,87 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package trace
+
+// ITMDecoder decodes the ITM (Instrumentation Trace Macrocell) packets
+// received from the SWO pin of ARM Cortex-M targets, and passes the payload
+// of the software stimulus ports to a callback. Synchronization, timestamp,
+// extension and hardware source (DWT) packets are discarded.
+type ITMDecoder struct {
+	onData func(port uint32, data []byte)
+	// Overflows counts the overflow packets received: each one means that
+	// some data has been lost by the target
+	Overflows int
+
+	// state of the packet being decoded
+	header       byte
+	payload      []byte
+	payloadSize  int
+	continuation bool
+	syncing      bool
+}
+
+// NewITMDecoder returns an ITMDecoder that calls onData with the payload of
+// the stimulus port packets
+func NewITMDecoder(onData func(port uint32, data []byte)) *ITMDecoder {
+	return &ITMDecoder{onData: onData}
+}
+
+// Write decodes the given bytes of the ITM stream. Packets may be split
+// between calls.
+func (d *ITMDecoder) Write(data []byte) (int, error) {
+	for _, b := range data {
+		d.decode(b)
+	}
+	return len(data), nil
+}
+
+func (d *ITMDecoder) decode(b byte) {
+	if d.continuation {
+		// protocol packets continue while the most significant bit is set
+		d.continuation = b&0x80 != 0
+		return
+	}
+	if d.payloadSize > 0 {
+		d.payload = append(d.payload, b)
+		if len(d.payload) < d.payloadSize {
+			return
+		}
+		if d.header&0x04 == 0 {
+			d.onData(uint32(d.header>>3), d.payload)
+		}
+		d.payload = nil
+		d.payloadSize = 0
+		return
+	}
+
+	// synchronization packets are a sequence of zeros terminated by 0x80
+	syncing := d.syncing
+	d.syncing = b == 0x00
+	switch {
+	case b == 0x00:
+	case b == 0x80 && syncing:
+	case b == 0x70:
+		d.Overflows++
+	case b&0x03 != 0:
+		// source packet: software (stimulus port) or hardware (DWT)
+		d.header = b
+		d.payloadSize = []int{0, 1, 2, 4}[b&0x03]
+		d.payload = make([]byte, 0, d.payloadSize)
+	default:
+		// timestamp or extension packet
+		d.continuation = b&0x80 != 0
+	}
+}
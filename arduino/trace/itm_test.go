@@ -0,0 +1,51 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package trace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestITMDecoder(t *testing.T) {
+	received := map[uint32]string{}
+	d := NewITMDecoder(func(port uint32, data []byte) {
+		received[port] += string(data)
+	})
+
+	stream := []byte{
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x80, // synchronization
+		0x01, 'H', // port 0, 1 byte
+		0x02, 'e', 'l', // port 0, 2 bytes
+		0xC0, 0x81, 0x01, // local timestamp with continuation
+		0x03, 'l', 'o', '!', '\n', // port 0, 4 bytes
+		0x09, 'x', // port 1, 1 byte
+		0x70,       // overflow
+		0x05, 0xAA, // hardware source packet, discarded
+		0x94, 0x80, 0x80, 0x01, // global timestamp
+		0x20,                     // local timestamp without continuation
+		0x0B, 'y', 'z', 'w', 'v', // port 1, 4 bytes
+	}
+	// packets split between writes are decoded as well
+	for _, b := range stream {
+		n, err := d.Write([]byte{b})
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+	}
+	require.Equal(t, map[uint32]string{0: "Hello!\n", 1: "xyzwv"}, received)
+	require.Equal(t, 1, d.Overflows)
+}
@@ -0,0 +1,61 @@
+// Fake pluggable uploader.
+// This program is used for testing purposes, it implements the pluggable
+// upload protocol and reports a fake upload of the file found in the build
+// path.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type params struct {
+	Port        string            `json:"port"`
+	BuildPath   string            `json:"buildPath"`
+	ProjectName string            `json:"projectName"`
+	Properties  map[string]string `json:"properties"`
+}
+
+func send(msg map[string]interface{}) {
+	data, _ := json.Marshal(msg)
+	fmt.Println(string(data))
+}
+
+func main() {
+	in := bufio.NewScanner(os.Stdin)
+	for in.Scan() {
+		line := in.Text()
+		switch {
+		case strings.HasPrefix(line, "HELLO "):
+			send(map[string]interface{}{"eventType": "hello", "protocolVersion": 1, "message": "OK"})
+		case strings.HasPrefix(line, "UPLOAD "):
+			var p params
+			if err := json.Unmarshal([]byte(line[7:]), &p); err != nil {
+				send(map[string]interface{}{"eventType": "upload", "error": true, "message": err.Error()})
+				continue
+			}
+			image, err := ioutil.ReadFile(filepath.Join(p.BuildPath, p.ProjectName+".bin"))
+			if err != nil {
+				send(map[string]interface{}{"eventType": "upload", "error": true, "message": err.Error()})
+				continue
+			}
+			fmt.Fprintln(os.Stderr, "fake uploader on "+p.Properties["upload.protocol"])
+			send(map[string]interface{}{"eventType": "log", "message": "Uploading to " + p.Port})
+			total := len(image)
+			for current := 0; current <= total; current += 512 {
+				send(map[string]interface{}{"eventType": "progress", "stage": "Writing", "current": current, "total": total})
+			}
+			send(map[string]interface{}{"eventType": "upload", "message": "OK", "bytesWritten": total, "port": p.Port + "-bootloader"})
+		case line == "QUIT":
+			send(map[string]interface{}{"eventType": "quit", "message": "OK"})
+			return
+		default:
+			send(map[string]interface{}{"eventType": "command_error", "error": true, "message": "unknown command"})
+		}
+	}
+}
@@ -0,0 +1,283 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package uploader
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/arduino/arduino-cli/cli/globals"
+	"github.com/arduino/arduino-cli/executils"
+	"github.com/pkg/errors"
+)
+
+// PluggableUploader is a tool, provided by a platform, that uploads the
+// sketch to the boards. Unlike the upload recipes, the tool talks with the
+// CLI through the pluggable upload protocol: it receives the parameters of
+// the upload and reports its progress while running.
+type PluggableUploader struct {
+	id                   string
+	process              *executils.Process
+	outgoingCommandsPipe io.Writer
+	incomingMessagesChan <-chan *uploaderMessage
+
+	// All the following fields are guarded by statusMutex
+	statusMutex           sync.Mutex
+	incomingMessagesError error
+	alive                 bool
+}
+
+type uploaderMessage struct {
+	EventType       string `json:"eventType"`
+	Message         string `json:"message"`
+	Error           bool   `json:"error"`
+	ProtocolVersion int    `json:"protocolVersion"` // Used in HELLO command
+	Stage           string `json:"stage"`           // Used in progress events
+	Current         int64  `json:"current"`         // Used in progress events
+	Total           int64  `json:"total"`           // Used in progress events
+	BytesWritten    int64  `json:"bytesWritten"`    // Used in UPLOAD command
+	Port            string `json:"port"`            // Used in UPLOAD command
+}
+
+// Params are the parameters of the upload sent to the uploader
+type Params struct {
+	Port        string            `json:"port"`
+	Protocol    string            `json:"protocol"`
+	Fqbn        string            `json:"fqbn"`
+	BuildPath   string            `json:"buildPath"`
+	ProjectName string            `json:"projectName"`
+	Verbose     bool              `json:"verbose"`
+	Verify      bool              `json:"verify"`
+	ToolArgs    []string          `json:"toolArgs"`
+	Properties  map[string]string `json:"properties"`
+}
+
+// Progress is a progress event sent by the uploader during the upload
+type Progress struct {
+	// Stage is the name of the step being performed, e.g. "Erasing"
+	Stage string
+	// Current and Total are the progress of the step, in any unit
+	Current int64
+	Total   int64
+}
+
+// ProgressCB is a callback to receive the progress of the upload
+type ProgressCB func(progress *Progress)
+
+// Result is the outcome of a successful upload
+type Result struct {
+	// BytesWritten is the number of bytes written, 0 if unknown
+	BytesWritten int64
+	// Port is the port of the board after the upload, empty if unchanged
+	Port string
+}
+
+// New creates the given pluggable uploader, it must be started with Run
+func New(id string, args ...string) (*PluggableUploader, error) {
+	proc, err := executils.NewProcess(args...)
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := proc.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdin, err := proc.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	messageChan := make(chan *uploaderMessage)
+	up := &PluggableUploader{
+		id:                   id,
+		process:              proc,
+		incomingMessagesChan: messageChan,
+		outgoingCommandsPipe: stdin,
+		alive:                true,
+	}
+	go up.jsonDecodeLoop(stdout, messageChan)
+	return up, nil
+}
+
+func (up *PluggableUploader) String() string {
+	return up.id
+}
+
+// RedirectStderrTo redirects the stderr of the uploader process, it must be
+// called before Run
+func (up *PluggableUploader) RedirectStderrTo(out io.Writer) {
+	up.process.RedirectStderrTo(out)
+}
+
+func (up *PluggableUploader) jsonDecodeLoop(in io.Reader, outChan chan<- *uploaderMessage) {
+	decoder := json.NewDecoder(in)
+	for {
+		var msg uploaderMessage
+		if err := decoder.Decode(&msg); err != nil {
+			up.statusMutex.Lock()
+			up.alive = false
+			up.incomingMessagesError = err
+			up.statusMutex.Unlock()
+			close(outChan)
+			return
+		}
+		outChan <- &msg
+	}
+}
+
+// IsAlive return true if the uploader process is running and so is able to
+// receive commands
+func (up *PluggableUploader) IsAlive() bool {
+	up.statusMutex.Lock()
+	defer up.statusMutex.Unlock()
+	return up.alive
+}
+
+// waitMessage waits for a message from the uploader, a timeout of 0 waits
+// indefinitely
+func (up *PluggableUploader) waitMessage(timeout time.Duration) (*uploaderMessage, error) {
+	var timeoutChan <-chan time.Time
+	if timeout > 0 {
+		timeoutChan = time.After(timeout)
+	}
+	select {
+	case msg := <-up.incomingMessagesChan:
+		if msg == nil {
+			// channel has been closed
+			up.statusMutex.Lock()
+			defer up.statusMutex.Unlock()
+			return nil, up.incomingMessagesError
+		}
+		return msg, nil
+	case <-timeoutChan:
+		return nil, errors.New("timeout")
+	}
+}
+
+func (up *PluggableUploader) sendCommand(command string) error {
+	data := []byte(command)
+	for {
+		n, err := up.outgoingCommandsPipe.Write(data)
+		if err != nil {
+			return err
+		}
+		if n == len(data) {
+			return nil
+		}
+		data = data[n:]
+	}
+}
+
+func (up *PluggableUploader) runProcess() error {
+	return up.process.Start()
+}
+
+// Run starts the uploader executable process and sends the HELLO command to
+// agree on the pluggable upload protocol. This must be the first command to
+// run in the communication with the uploader. If the HELLO command fails the
+// process is killed.
+func (up *PluggableUploader) Run() error {
+	if err := up.runProcess(); err != nil {
+		return err
+	}
+	if err := up.hello(); err != nil {
+		up.process.Kill()
+		return err
+	}
+	return nil
+}
+
+func (up *PluggableUploader) hello() error {
+	if err := up.sendCommand("HELLO 1 \"arduino-cli " + globals.VersionInfo.VersionString + "\"\n"); err != nil {
+		return err
+	}
+	if msg, err := up.waitMessage(time.Second * 10); err != nil {
+		return err
+	} else if msg.EventType != "hello" {
+		return errors.Errorf("communication out of sync, expected 'hello', received '%s'", msg.EventType)
+	} else if msg.Message != "OK" || msg.Error {
+		return errors.Errorf("command failed: %s", msg.Message)
+	} else if msg.ProtocolVersion > 1 {
+		return errors.Errorf("protocol version not supported: requested 1, got %d", msg.ProtocolVersion)
+	}
+	return nil
+}
+
+// Upload sends the UPLOAD command and waits for the upload to complete. The
+// messages logged by the uploader are written to the given streams and the
+// progress events are passed to progressCB, that may be nil.
+func (up *PluggableUploader) Upload(params *Params, outStream, errStream io.Writer, progressCB ProgressCB) (*Result, error) {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	if err := up.sendCommand("UPLOAD " + string(data) + "\n"); err != nil {
+		return nil, err
+	}
+	for {
+		// The upload may take long, or wait for the user to reset the board,
+		// so there is no timeout
+		msg, err := up.waitMessage(0)
+		if err != nil {
+			return nil, errors.Errorf("uploader terminated: %s", err)
+		}
+		switch msg.EventType {
+		case "progress":
+			if progressCB != nil {
+				progressCB(&Progress{Stage: msg.Stage, Current: msg.Current, Total: msg.Total})
+			}
+		case "log":
+			if msg.Error {
+				fmt.Fprintln(errStream, msg.Message)
+			} else {
+				fmt.Fprintln(outStream, msg.Message)
+			}
+		case "upload":
+			if msg.Message != "OK" || msg.Error {
+				return nil, errors.Errorf("command failed: %s", msg.Message)
+			}
+			return &Result{BytesWritten: msg.BytesWritten, Port: msg.Port}, nil
+		default:
+			return nil, errors.Errorf("communication out of sync, expected 'upload', received '%s'", msg.EventType)
+		}
+	}
+}
+
+// Quit terminates the uploader. No more commands can be accepted by the
+// uploader. If the uploader doesn't reply, its process is killed.
+func (up *PluggableUploader) Quit() error {
+	if err := up.quit(); err != nil {
+		up.process.Kill()
+		return err
+	}
+	return up.process.Wait()
+}
+
+func (up *PluggableUploader) quit() error {
+	if err := up.sendCommand("QUIT\n"); err != nil {
+		return err
+	}
+	if msg, err := up.waitMessage(time.Second * 10); err != nil {
+		return err
+	} else if msg.EventType != "quit" {
+		return errors.Errorf("communication out of sync, expected 'quit', received '%s'", msg.EventType)
+	} else if msg.Message != "OK" || msg.Error {
+		return errors.Errorf("command failed: %s", msg.Message)
+	}
+	return nil
+}
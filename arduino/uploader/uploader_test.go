@@ -0,0 +1,73 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package uploader
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/arduino/arduino-cli/executils"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPluggableUploader(t *testing.T) {
+	// Build `fake_uploader` helper inside testdata/fake_uploader
+	builder, err := executils.NewProcess("go", "build")
+	require.NoError(t, err)
+	builder.SetDir("testdata/fake_uploader")
+	require.NoError(t, builder.Run())
+
+	buildPath, err := paths.MkTempDir("", "uploader-test")
+	require.NoError(t, err)
+	defer buildPath.RemoveAll()
+	require.NoError(t, buildPath.Join("Blink.ino.bin").WriteFile(make([]byte, 1024)))
+
+	up, err := New("test", "testdata/fake_uploader/fake_uploader")
+	require.NoError(t, err)
+	stderr := &bytes.Buffer{}
+	up.RedirectStderrTo(stderr)
+	require.NoError(t, up.Run())
+	require.True(t, up.IsAlive())
+
+	stdout := &bytes.Buffer{}
+	progress := []*Progress{}
+	res, err := up.Upload(&Params{
+		Port:        "/dev/ttyACM0",
+		Protocol:    "serial",
+		Fqbn:        "test:test:board",
+		BuildPath:   buildPath.String(),
+		ProjectName: "Blink.ino",
+		Properties:  map[string]string{"upload.protocol": "fake"},
+	}, stdout, stderr, func(p *Progress) { progress = append(progress, p) })
+	require.NoError(t, err)
+	require.Equal(t, int64(1024), res.BytesWritten)
+	require.Equal(t, "/dev/ttyACM0-bootloader", res.Port)
+	require.Equal(t, "Uploading to /dev/ttyACM0\n", stdout.String())
+	require.Equal(t, []*Progress{
+		{Stage: "Writing", Current: 0, Total: 1024},
+		{Stage: "Writing", Current: 512, Total: 1024},
+		{Stage: "Writing", Current: 1024, Total: 1024},
+	}, progress)
+
+	// The failure reported by the uploader is returned as error
+	_, err = up.Upload(&Params{BuildPath: buildPath.String(), ProjectName: "Missing.ino"}, stdout, stderr, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "command failed")
+
+	require.NoError(t, up.Quit())
+	require.Equal(t, "fake uploader on fake\n", stderr.String())
+}
@@ -0,0 +1,82 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package utils
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// ApplyRCSDiff applies to source a diff in the RCS format, the output of
+// "diff -n source target", and returns the target. The diff is a list of
+// commands, referring to the line numbers of the source:
+//
+//	aL N   add the N lines that follow the command after the line L
+//	dL N   delete N lines starting from the line L
+func ApplyRCSDiff(source, diff []byte) ([]byte, error) {
+	src := splitLines(source)
+	cmds := splitLines(diff)
+
+	var res bytes.Buffer
+	next := 0 // the next line of the source to copy
+	for i := 0; i < len(cmds); {
+		cmd := string(bytes.TrimRight(cmds[i], "\r\n"))
+		i++
+		if cmd == "" {
+			return nil, fmt.Errorf("invalid diff command at line %d", i)
+		}
+		var line, count int
+		if _, err := fmt.Sscanf(cmd[1:], "%d %d", &line, &count); err != nil || count < 0 {
+			return nil, fmt.Errorf("invalid diff command at line %d: %s", i, cmd)
+		}
+		switch cmd[0] {
+		case 'a':
+			if line < next || line > len(src) || i+count > len(cmds) {
+				return nil, fmt.Errorf("diff command out of range at line %d: %s", i, cmd)
+			}
+			for ; next < line; next++ {
+				res.Write(src[next])
+			}
+			for _, added := range cmds[i : i+count] {
+				res.Write(added)
+			}
+			i += count
+		case 'd':
+			if line-1 < next || line-1+count > len(src) {
+				return nil, fmt.Errorf("diff command out of range at line %d: %s", i, cmd)
+			}
+			for ; next < line-1; next++ {
+				res.Write(src[next])
+			}
+			next += count
+		default:
+			return nil, fmt.Errorf("invalid diff command at line %d: %s", i, cmd)
+		}
+	}
+	for ; next < len(src); next++ {
+		res.Write(src[next])
+	}
+	return res.Bytes(), nil
+}
+
+// splitLines splits data in lines, keeping the line terminators
+func splitLines(data []byte) [][]byte {
+	lines := bytes.SplitAfter(data, []byte("\n"))
+	if len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
@@ -0,0 +1,42 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyRCSDiff(t *testing.T) {
+	source := "one\ntwo\nthree\nfour\nfive\n"
+	// diff -n source target
+	diff := "d1 1\na2 2\ntwo and a half\ntwo and three quarters\nd4 1\na5 1\nsix"
+	target, err := ApplyRCSDiff([]byte(source), []byte(diff))
+	require.NoError(t, err)
+	require.Equal(t, "two\ntwo and a half\ntwo and three quarters\nthree\nfive\nsix", string(target))
+
+	target, err = ApplyRCSDiff([]byte(source), []byte{})
+	require.NoError(t, err)
+	require.Equal(t, source, string(target))
+
+	_, err = ApplyRCSDiff([]byte(source), []byte("d9 1\n"))
+	require.Error(t, err)
+	_, err = ApplyRCSDiff([]byte(source), []byte("a3 1\nthree and a half\nd1 1\n"))
+	require.Error(t, err)
+	_, err = ApplyRCSDiff([]byte(source), []byte("x1 1\n"))
+	require.Error(t, err)
+}
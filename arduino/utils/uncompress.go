@@ -0,0 +1,63 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package utils
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+)
+
+// Uncompress uncompresses the file src, compressed with the format of the
+// given extension, ".gz" for gzip or ".zst" for zstd, to dest
+func Uncompress(src, dest *paths.Path, compression string) error {
+	switch compression {
+	case ".gz":
+		return paths.GUnzip(src, dest)
+	case ".zst":
+		return unzstd(src, dest)
+	default:
+		return fmt.Errorf("unsupported compression %q", compression)
+	}
+}
+
+func unzstd(src, dest *paths.Path) error {
+	zstIn, err := src.Open()
+	if err != nil {
+		return errors.Wrap(err, "opening "+src.String())
+	}
+	defer zstIn.Close()
+
+	in, err := zstd.NewReader(zstIn)
+	if err != nil {
+		return errors.Wrap(err, "decoding "+src.String())
+	}
+	defer in.Close()
+
+	out, err := dest.Create()
+	if err != nil {
+		return errors.Wrap(err, "creating "+dest.String())
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, in); err != nil {
+		return errors.Wrap(err, "uncompressing "+dest.String())
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package utils
+
+import (
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUncompress(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "uncompress_test")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+	index := []byte(`{"packages": []}`)
+
+	var gz bytes.Buffer
+	gzWriter := gzip.NewWriter(&gz)
+	_, err = gzWriter.Write(index)
+	require.NoError(t, err)
+	require.NoError(t, gzWriter.Close())
+
+	zstEncoder, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	zst := zstEncoder.EncodeAll(index, nil)
+
+	for compression, data := range map[string][]byte{".gz": gz.Bytes(), ".zst": zst} {
+		src := tmp.Join("index.json" + compression)
+		dest := tmp.Join("index.json")
+		require.NoError(t, src.WriteFile(data))
+		require.NoError(t, Uncompress(src, dest, compression))
+		uncompressed, err := dest.ReadFile()
+		require.NoError(t, err)
+		require.Equal(t, index, uncompressed, compression)
+	}
+
+	// The uncompressed file is not accepted
+	require.Error(t, Uncompress(tmp.Join("index.json"), tmp.Join("out.json"), ".zst"))
+	require.Error(t, Uncompress(tmp.Join("index.json"), tmp.Join("out.json"), ".gz"))
+	require.Error(t, Uncompress(tmp.Join("index.json"), tmp.Join("out.json"), ".bz2"))
+}
@@ -0,0 +1,50 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package artifacts
+
+import (
+	"os"
+
+	"github.com/arduino/arduino-cli/arduino/artifacts"
+	"github.com/arduino/arduino-cli/configuration"
+	"github.com/spf13/cobra"
+)
+
+// NewCommand created a new `artifacts` command
+func NewCommand() *cobra.Command {
+	artifactsCommand := &cobra.Command{
+		Use:   "artifacts",
+		Short: "Build artifacts store commands.",
+		Long:  "Tag the build outputs of a sketch and keep them in a local store, to upload known-good builds later.",
+		Example: "  # Tag the binaries exported by compile --export-binaries.\n" +
+			"  " + os.Args[0] + " artifacts tag blink-v1 -b arduino:avr:uno /home/user/Arduino/Blink\n\n" +
+			"  # Upload them.\n" +
+			"  " + os.Args[0] + " upload --artifact blink-v1 -p /dev/ttyACM0",
+	}
+
+	artifactsCommand.AddCommand(initTagCommand())
+	artifactsCommand.AddCommand(initKeepCommand())
+	artifactsCommand.AddCommand(initListCommand())
+	artifactsCommand.AddCommand(initPruneCommand())
+	artifactsCommand.AddCommand(initRemoveCommand())
+
+	return artifactsCommand
+}
+
+// Store returns the artifacts store in the data directory
+func Store() *artifacts.Store {
+	return artifacts.NewStore(configuration.ArtifactsDir(configuration.Settings))
+}
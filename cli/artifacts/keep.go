@@ -0,0 +1,55 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package artifacts
+
+import (
+	"os"
+
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var keepFlags struct {
+	unset bool
+}
+
+func initKeepCommand() *cobra.Command {
+	keepCommand := &cobra.Command{
+		Use:   "keep <tag>",
+		Short: "Retains an artifact when the store is pruned.",
+		Long:  "Marks an artifact to be retained, so it is never removed by artifacts prune.",
+		Example: "" +
+			"  " + os.Args[0] + " artifacts keep blink-v1\n" +
+			"  " + os.Args[0] + " artifacts keep blink-v1 --unset",
+		Args: cobra.ExactArgs(1),
+		Run:  runKeepCommand,
+	}
+	keepCommand.Flags().BoolVar(&keepFlags.unset, "unset", false, "Allow the artifact to be pruned again.")
+	return keepCommand
+}
+
+func runKeepCommand(cmd *cobra.Command, args []string) {
+	logrus.Info("Executing `arduino artifacts keep`")
+
+	artifact, err := Store().SetKeep(args[0], !keepFlags.unset)
+	if err != nil {
+		feedback.Errorf("Error updating artifact: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+	feedback.PrintResult(artifactResult{artifact})
+}
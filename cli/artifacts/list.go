@@ -0,0 +1,116 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package artifacts
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino/artifacts"
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/arduino-cli/table"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func initListCommand() *cobra.Command {
+	listCommand := &cobra.Command{
+		Use:     "list",
+		Short:   "Lists the artifacts in the store.",
+		Long:    "Lists the artifacts in the store, the most recent first.",
+		Example: "  " + os.Args[0] + " artifacts list",
+		Args:    cobra.NoArgs,
+		Run:     runListCommand,
+	}
+	return listCommand
+}
+
+func runListCommand(cmd *cobra.Command, args []string) {
+	logrus.Info("Executing `arduino artifacts list`")
+
+	list, err := Store().List()
+	if err != nil {
+		feedback.Errorf("Error listing artifacts: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+	feedback.PrintResult(listResult{list})
+}
+
+type listResult struct {
+	artifacts []*artifacts.Artifact
+}
+
+func (r listResult) Data() interface{} {
+	return r.artifacts
+}
+
+func (r listResult) String() string {
+	if len(r.artifacts) == 0 {
+		return "No artifacts in the store."
+	}
+	t := table.New()
+	t.SetHeader("Tag", "Sketch", "FQBN", "Profile", "Commit", "Created", "Kept")
+	for _, a := range r.artifacts {
+		t.AddRow(a.Tag, a.Sketch, a.Fqbn, a.Profile, shortCommit(a.GitCommit), a.Created.Format("2006-01-02 15:04"), keptString(a.Keep))
+	}
+	return t.Render()
+}
+
+// artifactResult prints the details of a single artifact
+type artifactResult struct {
+	artifact *artifacts.Artifact
+}
+
+func (r artifactResult) Data() interface{} {
+	return r.artifact
+}
+
+func (r artifactResult) String() string {
+	a := r.artifact
+	t := table.New()
+	t.AddRow("Tag:", a.Tag)
+	t.AddRow("Sketch:", a.SketchPath)
+	t.AddRow("FQBN:", a.Fqbn)
+	if a.Profile != "" {
+		t.AddRow("Profile:", a.Profile)
+	}
+	if a.GitCommit != "" {
+		t.AddRow("Commit:", a.GitCommit)
+	}
+	if a.Optimization != "" {
+		t.AddRow("Optimization:", a.Optimization)
+	}
+	t.AddRow("Created:", a.Created.Format("2006-01-02 15:04:05"))
+	t.AddRow("Kept:", keptString(a.Keep))
+	t.AddRow("Files:", strings.Join(a.Files, ", "))
+	return t.Render() + fmt.Sprintf("\nStored in: %s", a.Dir())
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > 8 {
+		return commit[:8]
+	}
+	return commit
+}
+
+func keptString(keep bool) string {
+	if keep {
+		return "yes"
+	}
+	return "no"
+}
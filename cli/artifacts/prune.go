@@ -0,0 +1,62 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package artifacts
+
+import (
+	"os"
+	"time"
+
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var pruneFlags struct {
+	olderThan time.Duration
+	dryRun    bool
+}
+
+func initPruneCommand() *cobra.Command {
+	pruneCommand := &cobra.Command{
+		Use:   "prune",
+		Short: "Removes the old artifacts.",
+		Long:  "Removes the artifacts older than the given age, except the ones marked with artifacts keep.",
+		Example: "" +
+			"  " + os.Args[0] + " artifacts prune\n" +
+			"  " + os.Args[0] + " artifacts prune --older-than 168h --dry-run",
+		Args: cobra.NoArgs,
+		Run:  runPruneCommand,
+	}
+	pruneCommand.Flags().DurationVar(&pruneFlags.olderThan, "older-than", 30*24*time.Hour, "Remove the artifacts created before this age.")
+	pruneCommand.Flags().BoolVar(&pruneFlags.dryRun, "dry-run", false, "Only show the artifacts that would be removed.")
+	return pruneCommand
+}
+
+func runPruneCommand(cmd *cobra.Command, args []string) {
+	logrus.Info("Executing `arduino artifacts prune`")
+
+	removed, err := Store().Prune(time.Now().Add(-pruneFlags.olderThan), pruneFlags.dryRun)
+	if err != nil {
+		feedback.Errorf("Error pruning artifacts: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+	if len(removed) == 0 && feedback.GetFormat() == feedback.Text {
+		feedback.Print("No artifacts to remove.")
+		return
+	}
+	feedback.PrintResult(listResult{removed})
+}
@@ -0,0 +1,48 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package artifacts
+
+import (
+	"os"
+
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func initRemoveCommand() *cobra.Command {
+	removeCommand := &cobra.Command{
+		Use:     "remove <tag>...",
+		Short:   "Removes artifacts from the store.",
+		Long:    "Removes artifacts from the store, even if they are marked to be kept.",
+		Example: "  " + os.Args[0] + " artifacts remove blink-v1",
+		Args:    cobra.MinimumNArgs(1),
+		Run:     runRemoveCommand,
+	}
+	return removeCommand
+}
+
+func runRemoveCommand(cmd *cobra.Command, args []string) {
+	logrus.Info("Executing `arduino artifacts remove`")
+
+	for _, tag := range args {
+		if err := Store().Remove(tag); err != nil {
+			feedback.Errorf("Error removing artifact: %v", err)
+			os.Exit(errorcodes.ErrGeneric)
+		}
+	}
+}
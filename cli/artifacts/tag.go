@@ -0,0 +1,111 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package artifacts
+
+import (
+	"os"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino/artifacts"
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/arduino/sketches"
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/go-paths-helper"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var tagFlags struct {
+	fqbn     string
+	inputDir string
+	profile  string
+	force    bool
+}
+
+func initTagCommand() *cobra.Command {
+	tagCommand := &cobra.Command{
+		Use:   "tag <tag> [sketchPath]",
+		Short: "Saves the build outputs of a sketch in the artifacts store.",
+		Long: "Saves the build outputs of a sketch in the artifacts store under the given tag, with the sketch, the FQBN,\n" +
+			"the profile and the git commit of the sketch as metadata. By default the binaries exported by\n" +
+			"compile --export-binaries are saved.",
+		Example: "" +
+			"  " + os.Args[0] + " artifacts tag blink-v1 -b arduino:avr:uno /home/user/Arduino/Blink\n" +
+			"  " + os.Args[0] + " artifacts tag blink-v1 -b arduino:avr:uno --input-dir /tmp/build /home/user/Arduino/Blink",
+		Args: cobra.RangeArgs(1, 2),
+		Run:  runTagCommand,
+	}
+	tagCommand.Flags().StringVarP(&tagFlags.fqbn, "fqbn", "b", "", "Fully Qualified Board Name of the build, e.g.: arduino:avr:uno")
+	tagCommand.Flags().StringVar(&tagFlags.inputDir, "input-dir", "", "Directory containing the build outputs, defaults to the folder where compile --export-binaries saves them.")
+	tagCommand.Flags().StringVar(&tagFlags.profile, "profile", "", "Optional, name of the build profile (e.g. release) recorded in the metadata.")
+	tagCommand.Flags().BoolVar(&tagFlags.force, "force", false, "Replace the artifact if the tag already exists.")
+	return tagCommand
+}
+
+func runTagCommand(cmd *cobra.Command, args []string) {
+	logrus.Info("Executing `arduino artifacts tag`")
+
+	sketchPath := paths.New(".")
+	if len(args) > 1 {
+		sketchPath = paths.New(args[1])
+	}
+	sketchPath, err := sketchPath.Abs()
+	if err != nil {
+		feedback.Errorf("Invalid sketch path: %v", err)
+		os.Exit(errorcodes.ErrBadArgument)
+	}
+	sketch, err := sketches.NewSketchFromPath(sketchPath)
+	if err != nil {
+		feedback.Errorf("Error opening sketch: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+
+	fqbn := tagFlags.fqbn
+	if fqbn == "" && sketch.Metadata != nil {
+		fqbn = sketch.Metadata.CPU.Fqbn
+	}
+	if fqbn == "" {
+		feedback.Errorf("Please specify the board of the build with the --fqbn flag.")
+		os.Exit(errorcodes.ErrBadArgument)
+	}
+
+	parsedFqbn, err := cores.ParseFQBN(fqbn)
+	if err != nil {
+		feedback.Errorf("Invalid FQBN: %v", err)
+		os.Exit(errorcodes.ErrBadArgument)
+	}
+
+	inputDir := paths.New(tagFlags.inputDir)
+	if tagFlags.inputDir == "" {
+		// Same folder used by compile --export-binaries
+		inputDir = sketch.FullPath.Join("build", strings.Replace(parsedFqbn.StringWithoutConfig(), ":", ".", -1))
+	}
+
+	artifact, err := Store().Add(&artifacts.Artifact{
+		Tag:        args[0],
+		Sketch:     sketch.Name,
+		SketchPath: sketch.FullPath.String(),
+		Fqbn:       fqbn,
+		Profile:    tagFlags.profile,
+		GitCommit:  artifacts.GitCommit(sketch.FullPath),
+	}, inputDir, tagFlags.force)
+	if err != nil {
+		feedback.Errorf("Error tagging artifact: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+	feedback.PrintResult(artifactResult{artifact})
+}
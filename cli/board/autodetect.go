@@ -0,0 +1,53 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package board
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/arduino/arduino-cli/arduino/sketches"
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/arduino-cli/commands/board"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
+)
+
+// Autodetect fills the FQBN, and the port if not already set, with the ones
+// of the only board connected to the computer. Nothing is done if the FQBN
+// is already set or if it can be read from the metadata of the sketch.
+// The program exits if the board can't be detected.
+func Autodetect(inst *rpc.Instance, sketchPath *paths.Path, fqbn, port *string) {
+	if *fqbn != "" {
+		return
+	}
+	if sketch, err := sketches.NewSketchFromPath(sketchPath); err == nil && sketch.Metadata != nil && sketch.Metadata.CPU.Fqbn != "" {
+		return
+	}
+
+	detectedBoard, detectedPort, err := board.Autodetect(inst.GetId())
+	if err != nil {
+		feedback.Errorf("Error detecting the board: %v. Please specify the board with the --fqbn flag.", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+	*fqbn = detectedBoard.GetFqbn()
+	if *port == "" {
+		*port = detectedPort.GetAddress()
+	}
+	fmt.Fprintf(feedback.ErrorWriter(), "No FQBN specified, using detected board %s (%s) on port %s\n",
+		detectedBoard.GetName(), detectedBoard.GetFqbn(), detectedPort.GetAddress())
+}
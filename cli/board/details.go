@@ -90,9 +90,10 @@ func (dr detailsResult) String() string {
 
 	if listProgrammers {
 		t := table.New()
-		t.AddRow(tr("Id"), tr("Programmer name"))
+		t.AddRow(tr("Id"), tr("Programmer name"), tr("Protocol"), tr("Communication"), tr("Speed"), tr("Tool"))
 		for _, programmer := range details.Programmers {
-			t.AddRow(programmer.GetId(), programmer.GetName())
+			t.AddRow(programmer.GetId(), programmer.GetName(), programmer.GetProtocol(),
+				programmer.GetCommunication(), programmer.GetSpeed(), programmer.GetTool())
 		}
 		return t.Render()
 	}
@@ -22,10 +22,13 @@ import (
 	"time"
 
 	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/arduino/remote"
 	"github.com/arduino/arduino-cli/cli/errorcodes"
 	"github.com/arduino/arduino-cli/cli/feedback"
 	"github.com/arduino/arduino-cli/cli/instance"
+	"github.com/arduino/arduino-cli/cli/output"
 	"github.com/arduino/arduino-cli/commands/board"
+	"github.com/arduino/arduino-cli/configuration"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
 	"github.com/arduino/arduino-cli/table"
 	"github.com/spf13/cobra"
@@ -33,18 +36,23 @@ import (
 
 func initListCommand() *cobra.Command {
 	listCommand := &cobra.Command{
-		Use:     "list",
-		Short:   "List connected boards.",
-		Long:    "Detects and displays a list of boards connected to the current computer.",
-		Example: "  " + os.Args[0] + " board list --timeout 10s",
-		Args:    cobra.NoArgs,
-		Run:     runListCommand,
+		Use:   "list",
+		Short: "List connected boards.",
+		Long:  "Detects and displays a list of boards connected to the current computer.",
+		Example: "" +
+			"  " + os.Args[0] + " board list --timeout 10s\n" +
+			"  " + os.Args[0] + " board list --remote pi@raspberrypi.local",
+		Args: cobra.NoArgs,
+		Run:  runListCommand,
 	}
 
 	listCommand.Flags().StringVar(&listFlags.timeout, "timeout", "0s",
 		"The connected devices search timeout, raise it if your board doesn't show up (e.g. to 10s).")
 	listCommand.Flags().BoolVarP(&listFlags.watch, "watch", "w", false,
 		"Command keeps running and prints list of connected boards whenever there is a change.")
+	listCommand.Flags().StringVar(&listFlags.remote, "remote", "",
+		"List the boards connected to a remote host, reachable with ssh, e.g.: pi@raspberrypi.local. The host must have arduino-cli installed.")
+	output.AddTableFlagsToCommand(listCommand, "port", "protocol", "type", "board", "fqbn", "core")
 
 	return listCommand
 }
@@ -52,23 +60,45 @@ func initListCommand() *cobra.Command {
 var listFlags struct {
 	timeout string // Expressed in a parsable duration, is the timeout for the list and attach commands.
 	watch   bool
+	remote  string
 }
 
 // runListCommand detects and lists the connected arduino boards
 func runListCommand(cmd *cobra.Command, args []string) {
+	if listFlags.watch && listFlags.remote != "" {
+		feedback.Errorf("The --watch and --remote flags cannot be used together")
+		os.Exit(errorcodes.ErrBadArgument)
+	}
 	if listFlags.watch {
 		inst := instance.CreateAndInit()
 		watchList(cmd, inst)
 		os.Exit(0)
 	}
 
-	if timeout, err := time.ParseDuration(listFlags.timeout); err != nil {
+	timeout, err := time.ParseDuration(listFlags.timeout)
+	if err != nil {
 		feedback.Errorf("Invalid timeout: %v", err)
 		os.Exit(errorcodes.ErrBadArgument)
-	} else {
-		time.Sleep(timeout)
 	}
 
+	if listFlags.remote != "" {
+		host, err := remote.ParseHost(listFlags.remote)
+		if err != nil {
+			feedback.Errorf("Invalid remote host: %v", err)
+			os.Exit(errorcodes.ErrBadArgument)
+		}
+		host.CLIPath = configuration.Settings.GetString("remote.cli_path")
+		ports, err := host.BoardList(timeout)
+		if err != nil {
+			feedback.Errorf("Error detecting boards on %s: %v", host, err)
+			os.Exit(errorcodes.ErrNetwork)
+		}
+		feedback.PrintResult(result{ports})
+		return
+	}
+
+	time.Sleep(timeout)
+
 	inst := instance.CreateAndInit()
 	ports, err := board.List(inst.GetId())
 	if err != nil {
@@ -167,6 +197,25 @@ func (dr result) String() string {
 	return t.Render()
 }
 
+// Table returns a row for each board detected, and for each port where no
+// board is detected, to print the columns selected with --columns
+func (dr result) Table() *feedback.Table {
+	t := &feedback.Table{Columns: []string{"port", "protocol", "type", "board", "fqbn", "core"}}
+	for _, port := range dr.ports {
+		if len(port.GetBoards()) == 0 {
+			t.AddRow(port.GetAddress(), port.GetProtocol(), port.GetProtocolLabel(), "", "", "")
+		}
+		for _, b := range port.GetBoards() {
+			coreName := ""
+			if fqbn, err := cores.ParseFQBN(b.GetFqbn()); err == nil {
+				coreName = fmt.Sprintf("%s:%s", fqbn.Package, fqbn.PlatformArch)
+			}
+			t.AddRow(port.GetAddress(), port.GetProtocol(), port.GetProtocolLabel(), b.GetName(), b.GetFqbn(), coreName)
+		}
+	}
+	return t
+}
+
 type watchEvent struct {
 	Type          string               `json:"type"`
 	Address       string               `json:"address,omitempty"`
@@ -17,12 +17,14 @@ package board
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"sort"
 
 	"github.com/arduino/arduino-cli/cli/errorcodes"
 	"github.com/arduino/arduino-cli/cli/feedback"
 	"github.com/arduino/arduino-cli/cli/instance"
+	"github.com/arduino/arduino-cli/cli/output"
 	"github.com/arduino/arduino-cli/commands/board"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
 	"github.com/arduino/arduino-cli/table"
@@ -35,14 +37,17 @@ func initListAllCommand() *cobra.Command {
 		Short: "List all known boards and their corresponding FQBN.",
 		Long: "" +
 			"List all boards that have the support platform installed. You can search\n" +
-			"for a specific board if you specify the board name",
+			"for a specific board if you specify the board name, or list the boards\n" +
+			"matching a FQBN pattern like \"arduino:samd:*\"",
 		Example: "" +
 			"  " + os.Args[0] + " board listall\n" +
-			"  " + os.Args[0] + " board listall zero",
+			"  " + os.Args[0] + " board listall zero\n" +
+			"  " + os.Args[0] + ` board listall "arduino:samd:mkr*"`,
 		Args: cobra.ArbitraryArgs,
 		Run:  runListAllCommand,
 	}
 	listAllCommand.Flags().BoolVarP(&showHiddenBoard, "show-hidden", "a", false, "Show also boards marked as 'hidden' in the platform")
+	output.AddTableFlagsToCommand(listAllCommand, boardColumns...)
 	return listAllCommand
 }
 
@@ -91,3 +96,23 @@ func (dr resultAll) String() string {
 	}
 	return t.Render()
 }
+
+// Table returns all the data of the boards, to print the columns selected
+// with --columns
+func (dr resultAll) Table() *feedback.Table {
+	sort.Slice(dr.list.Boards, func(i, j int) bool {
+		return dr.list.Boards[i].GetName() < dr.list.Boards[j].GetName()
+	})
+	return boardsTable(dr.list.GetBoards())
+}
+
+// boardColumns are the columns of the boards tables
+var boardColumns = []string{"name", "fqbn", "platform", "hidden"}
+
+func boardsTable(boards []*rpc.BoardListItem) *feedback.Table {
+	t := &feedback.Table{Columns: boardColumns}
+	for _, item := range boards {
+		t.AddRow(item.GetName(), item.GetFqbn(), item.GetPlatform().GetId(), fmt.Sprint(item.GetIsHidden()))
+	}
+	return t
+}
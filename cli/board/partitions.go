@@ -0,0 +1,92 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package board
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/arduino-cli/cli/instance"
+	"github.com/arduino/arduino-cli/cli/output"
+	"github.com/arduino/arduino-cli/commands/board"
+	"github.com/arduino/arduino-cli/table"
+	"github.com/spf13/cobra"
+)
+
+var partitionsFlags struct {
+	fqbn string
+}
+
+func initPartitionsCommand() *cobra.Command {
+	partitionsCommand := &cobra.Command{
+		Use:   "partitions -b <FQBN>",
+		Short: tr("Lists the partition schemes of a board."),
+		Long:  tr("Lists the partition schemes available for a board and the partitions of the one selected in the FQBN. The scheme is selected with the PartitionScheme option of the FQBN."),
+		Example: "" +
+			"  " + os.Args[0] + " board partitions -b esp32:esp32:esp32\n" +
+			"  " + os.Args[0] + " board partitions -b esp32:esp32:esp32:PartitionScheme=huge_app",
+		Args: cobra.NoArgs,
+		Run:  runPartitionsCommand,
+	}
+	partitionsCommand.Flags().StringVarP(&partitionsFlags.fqbn, "fqbn", "b", "", "Fully Qualified Board Name, e.g.: esp32:esp32:esp32")
+	partitionsCommand.MarkFlagRequired("fqbn")
+	return partitionsCommand
+}
+
+func runPartitionsCommand(cmd *cobra.Command, args []string) {
+	inst := instance.CreateAndInit()
+
+	res, err := board.Partitions(inst.GetId(), partitionsFlags.fqbn)
+	if err != nil {
+		feedback.Errorf(tr("Error listing the partitions: %v"), err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+	feedback.PrintResult(partitionsResult{res})
+}
+
+type partitionsResult struct {
+	*board.PartitionsResult
+}
+
+func (r partitionsResult) Data() interface{} {
+	return r.PartitionsResult
+}
+
+func (r partitionsResult) String() string {
+	res := ""
+	if len(r.Schemes) > 0 {
+		t := table.New()
+		t.SetHeader("", tr("Scheme"), tr("Name"))
+		for _, scheme := range r.Schemes {
+			selected := ""
+			if scheme.Selected {
+				selected = "*"
+			}
+			t.AddRow(selected, scheme.ID, scheme.Name)
+		}
+		res += t.Render() + "\n"
+	}
+
+	t := table.New()
+	t.SetHeader(tr("Partition"), tr("Type"), tr("SubType"), tr("Offset"), tr("Size"))
+	for _, part := range r.Partitions {
+		t.AddRow(part.Name, part.Type, part.SubType, fmt.Sprintf("0x%X", part.Offset), output.FormatSize(int64(part.Size)))
+	}
+	res += tr("Partitions table:") + " " + r.Table + "\n" + t.Render()
+	return res
+}
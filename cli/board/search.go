@@ -24,6 +24,7 @@ import (
 	"github.com/arduino/arduino-cli/cli/errorcodes"
 	"github.com/arduino/arduino-cli/cli/feedback"
 	"github.com/arduino/arduino-cli/cli/instance"
+	"github.com/arduino/arduino-cli/cli/output"
 	"github.com/arduino/arduino-cli/commands/board"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
 	"github.com/arduino/arduino-cli/table"
@@ -44,6 +45,7 @@ func initSearchCommand() *cobra.Command {
 		Run:  runSearchCommand,
 	}
 	searchCommand.Flags().BoolVarP(&searchFlags.showHiddenBoard, "show-hidden", "a", false, "Show also boards marked as 'hidden' in the platform")
+	output.AddTableFlagsToCommand(searchCommand, boardColumns...)
 	return searchCommand
 }
 
@@ -93,3 +95,12 @@ func (r searchResults) String() string {
 	}
 	return t.Render()
 }
+
+// Table returns all the data of the boards, to print the columns selected
+// with --columns
+func (r searchResults) Table() *feedback.Table {
+	sort.Slice(r.boards, func(i, j int) bool {
+		return r.boards[i].GetName() < r.boards[j].GetName()
+	})
+	return boardsTable(r.boards)
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/arduino/arduino-cli/cli/errorcodes"
 	"github.com/arduino/arduino-cli/cli/feedback"
 	"github.com/arduino/arduino-cli/cli/instance"
+	"github.com/arduino/arduino-cli/cli/output"
 	"github.com/arduino/arduino-cli/commands/upload"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
 	"github.com/spf13/cobra"
@@ -33,6 +34,7 @@ var (
 	verbose    bool
 	verify     bool
 	programmer string
+	fusesOnly  bool
 )
 
 // NewCommand created a new `burn-bootloader` command
@@ -51,6 +53,7 @@ func NewCommand() *cobra.Command {
 	burnBootloaderCommand.Flags().BoolVarP(&verify, "verify", "t", false, "Verify uploaded binary after the upload.")
 	burnBootloaderCommand.Flags().BoolVarP(&verbose, "verbose", "v", false, "Turns on verbose mode.")
 	burnBootloaderCommand.Flags().StringVarP(&programmer, "programmer", "P", "", "Use the specified programmer to upload.")
+	burnBootloaderCommand.Flags().BoolVar(&fusesOnly, "fuses-only", false, "Only program the fuses defined by the board, without erasing the chip and writing the bootloader.")
 
 	return burnBootloaderCommand
 }
@@ -58,16 +61,52 @@ func NewCommand() *cobra.Command {
 func run(command *cobra.Command, args []string) {
 	instance := instance.CreateAndInit()
 
-	if _, err := upload.BurnBootloader(context.Background(), &rpc.BurnBootloaderRequest{
+	toolOutput := output.NewToolOutput()
+	res, err := upload.BurnBootloader(context.Background(), &rpc.BurnBootloaderRequest{
 		Instance:   instance,
 		Fqbn:       fqbn,
 		Port:       port,
 		Verbose:    verbose,
 		Verify:     verify,
 		Programmer: programmer,
-	}, os.Stdout, os.Stderr); err != nil {
+		FusesOnly:  fusesOnly,
+	}, toolOutput.Out, toolOutput.Err)
+	toolOutput.Flush()
+	if output.OutputFormat == "json" {
+		uploadOut, uploadErr := toolOutput.Buffered()
+		result := &burnBootloaderResult{
+			Event:     "result",
+			UploadOut: uploadOut,
+			UploadErr: uploadErr,
+			Fuses:     res.GetFuses(),
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		feedback.PrintEvent(result)
+	}
+	if err != nil {
 		feedback.Errorf("Error during Upload: %v", err)
 		os.Exit(errorcodes.ErrGeneric)
 	}
+	if output.OutputFormat != "json" && len(res.GetFuses()) > 0 {
+		feedback.Print("Programmed fuses:\n" + output.FormatFuses(res.GetFuses()))
+	}
 	os.Exit(0)
 }
+
+type burnBootloaderResult struct {
+	Event     string           `json:"event"`
+	UploadOut string           `json:"upload_out,omitempty"`
+	UploadErr string           `json:"upload_err,omitempty"`
+	Fuses     []*rpc.FuseValue `json:"fuses,omitempty"`
+	Error     string           `json:"error,omitempty"`
+}
+
+func (r *burnBootloaderResult) Data() interface{} {
+	return r
+}
+
+func (r *burnBootloaderResult) String() string {
+	return ""
+}
@@ -21,6 +21,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/arduino/arduino-cli/cli/artifacts"
 	"github.com/arduino/arduino-cli/cli/board"
 	"github.com/arduino/arduino-cli/cli/burnbootloader"
 	"github.com/arduino/arduino-cli/cli/cache"
@@ -30,14 +31,26 @@ import (
 	"github.com/arduino/arduino-cli/cli/core"
 	"github.com/arduino/arduino-cli/cli/daemon"
 	"github.com/arduino/arduino-cli/cli/debug"
+	"github.com/arduino/arduino-cli/cli/eeprom"
 	"github.com/arduino/arduino-cli/cli/errorcodes"
 	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/arduino-cli/cli/flash"
+	"github.com/arduino/arduino-cli/cli/fs"
+	"github.com/arduino/arduino-cli/cli/fuses"
 	"github.com/arduino/arduino-cli/cli/generatedocs"
 	"github.com/arduino/arduino-cli/cli/globals"
+	"github.com/arduino/arduino-cli/cli/ide"
+	"github.com/arduino/arduino-cli/cli/index"
 	"github.com/arduino/arduino-cli/cli/lib"
+	"github.com/arduino/arduino-cli/cli/mirror"
+	"github.com/arduino/arduino-cli/cli/monitor"
 	"github.com/arduino/arduino-cli/cli/outdated"
 	"github.com/arduino/arduino-cli/cli/output"
+	"github.com/arduino/arduino-cli/cli/run"
 	"github.com/arduino/arduino-cli/cli/sketch"
+	"github.com/arduino/arduino-cli/cli/test"
+	"github.com/arduino/arduino-cli/cli/tool"
+	"github.com/arduino/arduino-cli/cli/trace"
 	"github.com/arduino/arduino-cli/cli/update"
 	"github.com/arduino/arduino-cli/cli/upgrade"
 	"github.com/arduino/arduino-cli/cli/upload"
@@ -54,6 +67,7 @@ import (
 var (
 	verbose      bool
 	outputFormat string
+	outputQuery  string
 	configFile   string
 )
 
@@ -79,6 +93,7 @@ func NewCommand() *cobra.Command {
 
 // this is here only for testing
 func createCliCommandTree(cmd *cobra.Command) {
+	cmd.AddCommand(artifacts.NewCommand())
 	cmd.AddCommand(board.NewCommand())
 	cmd.AddCommand(cache.NewCommand())
 	cmd.AddCommand(compile.NewCommand())
@@ -86,24 +101,38 @@ func createCliCommandTree(cmd *cobra.Command) {
 	cmd.AddCommand(config.NewCommand())
 	cmd.AddCommand(core.NewCommand())
 	cmd.AddCommand(daemon.NewCommand())
+	cmd.AddCommand(fs.NewCommand())
 	cmd.AddCommand(generatedocs.NewCommand())
+	cmd.AddCommand(ide.NewCommand())
+	cmd.AddCommand(index.NewCommand())
 	cmd.AddCommand(lib.NewCommand())
+	cmd.AddCommand(mirror.NewCommand())
+	cmd.AddCommand(monitor.NewCommand())
 	cmd.AddCommand(outdated.NewCommand())
+	cmd.AddCommand(run.NewCommand())
 	cmd.AddCommand(sketch.NewCommand())
+	cmd.AddCommand(test.NewCommand())
+	cmd.AddCommand(tool.NewCommand())
 	cmd.AddCommand(update.NewCommand())
 	cmd.AddCommand(upgrade.NewCommand())
 	cmd.AddCommand(upload.NewCommand())
 	cmd.AddCommand(debug.NewCommand())
+	cmd.AddCommand(trace.NewCommand())
 	cmd.AddCommand(burnbootloader.NewCommand())
+	cmd.AddCommand(fuses.NewCommand())
+	cmd.AddCommand(flash.NewCommand())
+	cmd.AddCommand(eeprom.NewCommand())
 	cmd.AddCommand(version.NewCommand())
 
 	cmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Print the logs on the standard output.")
 	cmd.PersistentFlags().String("log-level", "", "Messages with this level and above will be logged. Valid levels are: trace, debug, info, warn, error, fatal, panic")
 	cmd.PersistentFlags().String("log-file", "", "Path to the file where logs will be written.")
 	cmd.PersistentFlags().String("log-format", "", "The output format for the logs, can be {text|json}.")
-	cmd.PersistentFlags().StringVar(&outputFormat, "format", "text", "The output format, can be {text|json}.")
+	cmd.PersistentFlags().StringVar(&outputFormat, "format", "text", "The output format, can be {text|json|ndjson|yaml|toml|junit|csv|gh-annotations}. The junit format is supported by the commands reporting test results, the csv format by the commands reporting tables, ndjson prints the messages, the progress of the long running operations and the results as a stream of typed JSON events, gh-annotations adds the GitHub Actions annotations of the problems found to the text output (default in GitHub Actions).")
+	cmd.PersistentFlags().StringVar(&outputQuery, "query", "", `A jq-style expression selecting the part of the result to print, e.g. '.[] | select(.library.name == "Servo") | .library.install_dir'. In text format the strings are printed without quotes and the other values as JSON.`)
 	cmd.PersistentFlags().StringVar(&configFile, "config-file", "", "The custom config file (if not specified the default will be used).")
 	cmd.PersistentFlags().StringSlice("additional-urls", []string{}, "Comma-separated list of additional URLs for the Boards Manager.")
+	cmd.PersistentFlags().Bool("offline", false, "Forbid all network access, only the local files and file:// URLs are used.")
 	configuration.BindFlags(cmd, configuration.Settings)
 }
 
@@ -125,8 +154,14 @@ func toLogLevel(s string) (t logrus.Level, found bool) {
 
 func parseFormatString(arg string) (feedback.OutputFormat, bool) {
 	f, found := map[string]feedback.OutputFormat{
-		"json": feedback.JSON,
-		"text": feedback.Text,
+		"json":           feedback.JSON,
+		"junit":          feedback.JUnit,
+		"csv":            feedback.CSV,
+		"ndjson":         feedback.NDJSON,
+		"yaml":           feedback.YAML,
+		"toml":           feedback.TOML,
+		"gh-annotations": feedback.GitHubAnnotations,
+		"text":           feedback.Text,
 	}[arg]
 
 	return f, found
@@ -202,10 +237,37 @@ func preRun(cmd *cobra.Command, args []string) {
 		feedback.Error("Invalid output format: " + outputFormat)
 		os.Exit(errorcodes.ErrBadCall)
 	}
+	// The annotations are printed in addition to the text output, they are
+	// enabled by default when running in a GitHub Actions workflow
+	if format == feedback.Text && !cmd.Flags().Changed("format") && os.Getenv("GITHUB_ACTIONS") == "true" {
+		format = feedback.GitHubAnnotations
+	}
+	if format == feedback.GitHubAnnotations {
+		output.OutputFormat = "text"
+	}
+	// The commands produce the same output in YAML, TOML and JSON formats,
+	// only the rendering is different
+	if format == feedback.YAML || format == feedback.TOML {
+		output.OutputFormat = "json"
+	}
 
 	// use the output format to configure the Feedback
 	feedback.SetFormat(format)
 
+	// The query is applied to the same results printed in JSON format, so in
+	// text format the commands behave as in JSON format
+	if outputQuery != "" {
+		query, err := feedback.ParseQuery(outputQuery)
+		if err != nil {
+			feedback.Errorf("Invalid query: %v", err)
+			os.Exit(errorcodes.ErrBadArgument)
+		}
+		feedback.SetQuery(query)
+		if output.OutputFormat == "text" {
+			output.OutputFormat = "json"
+		}
+	}
+
 	//
 	// Print some status info and check command is consistent
 	//
@@ -218,7 +280,7 @@ func preRun(cmd *cobra.Command, args []string) {
 
 	logrus.Info(globals.VersionInfo.Application + " version " + globals.VersionInfo.VersionString)
 
-	if outputFormat != "text" {
+	if output.OutputFormat != "text" {
 		cmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
 			logrus.Warn("Calling help on JSON format")
 			feedback.Error("Invalid Call : should show Help, but it is available only in TEXT mode.")
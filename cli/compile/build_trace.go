@@ -0,0 +1,83 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"encoding/json"
+
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
+)
+
+// traceEvent is a complete event of the Chrome Trace Event Format, the
+// format read by chrome://tracing and https://ui.perfetto.dev
+type traceEvent struct {
+	Name     string `json:"name"`
+	Category string `json:"cat"`
+	Phase    string `json:"ph"`
+	Ts       int64  `json:"ts"`
+	Dur      int64  `json:"dur"`
+	Pid      int    `json:"pid"`
+	Tid      int    `json:"tid"`
+}
+
+// buildTrace converts the events of the build in the Chrome Trace Event
+// Format. The builder phases are shown on the first row, the compiled files
+// and the archives are spread on as many rows as the parallel jobs used.
+func buildTrace(events []*rpc.BuildEvent) []*traceEvent {
+	res := []*traceEvent{}
+	// lanes contains the end time of the last event of each row
+	lanes := []int64{}
+	for _, event := range events {
+		tid := 0
+		if event.GetCategory() != "phase" {
+			for i, end := range lanes {
+				if end <= event.GetStartUs() {
+					tid = i + 1
+					break
+				}
+			}
+			if tid == 0 {
+				lanes = append(lanes, 0)
+				tid = len(lanes)
+			}
+			lanes[tid-1] = event.GetStartUs() + event.GetDurationUs()
+		}
+		res = append(res, &traceEvent{
+			Name:     event.GetName(),
+			Category: event.GetCategory(),
+			Phase:    "X",
+			Ts:       event.GetStartUs(),
+			Dur:      event.GetDurationUs(),
+			Pid:      1,
+			Tid:      tid,
+		})
+	}
+	return res
+}
+
+// writeBuildTrace saves the events of the build to the given file in the
+// Chrome Trace Event Format
+func writeBuildTrace(file *paths.Path, events []*rpc.BuildEvent) error {
+	data, err := json.MarshalIndent(map[string]interface{}{
+		"traceEvents":     buildTrace(events),
+		"displayTimeUnit": "ms",
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return file.WriteFile(data)
+}
@@ -0,0 +1,44 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"testing"
+
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildTrace(t *testing.T) {
+	events := []*rpc.BuildEvent{
+		{Name: "SketchBuilder", Category: "phase", StartUs: 0, DurationUs: 100},
+		{Name: "a.cpp", Category: "compile", StartUs: 0, DurationUs: 50},
+		{Name: "b.cpp", Category: "compile", StartUs: 10, DurationUs: 50},
+		{Name: "c.cpp", Category: "compile", StartUs: 50, DurationUs: 40},
+		{Name: "core.a", Category: "archive", StartUs: 100, DurationUs: 20},
+	}
+	trace := buildTrace(events)
+	require.Len(t, trace, 5)
+	tids := []int{}
+	for _, event := range trace {
+		require.Equal(t, "X", event.Phase)
+		tids = append(tids, event.Tid)
+	}
+	// phases on the first row, the files spread on the free rows
+	require.Equal(t, []int{0, 1, 2, 1, 1}, tids)
+	require.Equal(t, int64(10), trace[2].Ts)
+	require.Equal(t, int64(50), trace[2].Dur)
+}
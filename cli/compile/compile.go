@@ -19,17 +19,26 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"os"
 
+	bldr "github.com/arduino/arduino-cli/arduino/builder"
 	"github.com/arduino/arduino-cli/arduino/sketches"
+	"github.com/arduino/arduino-cli/cli/board"
 	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/arduino-cli/cli/monitor"
 	"github.com/arduino/arduino-cli/cli/output"
 	"github.com/arduino/arduino-cli/configuration"
 
 	"github.com/arduino/arduino-cli/cli/errorcodes"
 	"github.com/arduino/arduino-cli/cli/instance"
+	"github.com/arduino/arduino-cli/cli/lib"
+	"github.com/arduino/arduino-cli/commands"
+	cmdboard "github.com/arduino/arduino-cli/commands/board"
 	"github.com/arduino/arduino-cli/commands/compile"
+	"github.com/arduino/arduino-cli/commands/sketch"
 	"github.com/arduino/arduino-cli/commands/upload"
+	"github.com/arduino/arduino-cli/legacy/builder/ctags"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
 	"github.com/arduino/go-paths-helper"
 	"github.com/sirupsen/logrus"
@@ -38,24 +47,65 @@ import (
 
 var (
 	fqbn                    string   // Fully Qualified Board Name, e.g.: arduino:avr:uno.
+	fqbnFlags               []string // FQBNs or FQBN patterns to compile for.
+	fqbnFile                string   // Path of a file listing the FQBNs to compile for, one per line.
 	showProperties          bool     // Show all build preferences used instead of compiling.
+	showCommands            bool     // Show the command lines of the recipes instead of compiling.
+	traceProperties         []string // Log where the values of these build properties come from.
 	preprocess              bool     // Print preprocessed code to stdout.
 	buildCachePath          string   // Builds of 'core.a' are saved into this path to be cached and reused.
 	buildPath               string   // Path where to save compiled files.
 	buildProperties         []string // List of custom build properties separated by commas. Or can be used multiple times for multiple properties.
 	warnings                string   // Used to tell gcc which warning level to use.
+	libraryWarnings         string   // Warning level used for the libraries and the core.
 	verbose                 bool     // Turns on verbose mode.
 	quiet                   bool     // Suppresses almost every output.
 	vidPid                  string   // VID/PID specific build properties.
 	uploadAfterCompile      bool     // Upload the binary after the compilation.
 	port                    string   // Upload port, e.g.: COM10 or /dev/ttyACM0.
 	verify                  bool     // Upload, verify uploaded binary after the upload.
+	monitorAfterUpload      bool     // Open the monitor after the upload.
+	monitorBaudRate         int      // Baud rate of the monitor opened after the upload.
 	exportDir               string   // The compiled binary is written to this file
+	exportFormats           []string // Additional binary formats to export.
+	partitionTable          string   // Partitions table used instead of the one of the board.
+	signKey                 string   // Private key used to sign the sketch binary.
+	artifactsSignKey        string   // Private key used to sign the manifest of the exported binaries.
+	exportManifest          bool     // Save a manifest of the exported binaries.
+	manifestTemplate        string   // Template of the manifest.
+	manifestVersion         string   // Version of the firmware written in the manifest.
 	optimizeForDebug        bool     // Optimize compile output for debug, not for release
+	optimize                string   // Optimization preset: size, speed or debug.
 	programmer              string   // Use the specified programmer to upload
 	clean                   bool     // Cleanup the build folder and do not use any cached build
 	compilationDatabaseOnly bool     // Only create compilation database without actually compiling
 	sourceOverrides         string   // Path to a .json file that contains a set of replacements of the sketch source code.
+	jobs                    int32    // Max number of parallel compiles, if 0 the number of available CPUs is used.
+	keepGoing               bool     // Keep building what doesn't depend on a failed step.
+	showStats               bool     // Print statistics about the build.
+	statsFile               string   // Append the statistics about the build to this file.
+	buildStatsFile          string   // Save a trace of the duration of the build phases to this file.
+	noAutodetect            bool     // Don't use the FQBN of the connected board if none is specified.
+	compilationDatabasePath string   // Path of the compilation database to produce.
+	exportCMake             string   // Directory where a CMake project equivalent to the build is exported.
+	exportBuild             string   // Format of the build script to export, makefile or ninja.
+	sbomFormat              string   // Format of the software bill of materials to save, spdx or cyclonedx.
+	fromArchive             string   // Path of a sketch archive to compile.
+	diagnosticsFormat       string   // Format of the diagnostics file, only sarif is supported.
+	diagnosticsFile         string   // Path of the diagnostics file.
+	sizeReport              string   // Kind of size report to print, summary or detailed.
+	sizeDeltaFrom           string   // Path of the baseline to compare the size of the executable with.
+	sizeDeltaMaxFlash       int64    // Max growth of the flash usage allowed compared to the baseline.
+	sizeDeltaMaxRAM         int64    // Max growth of the RAM usage allowed compared to the baseline.
+	maxFlashUsage           string   // Max flash usage allowed, in bytes or percentage.
+	maxRAMUsage             string   // Max RAM usage allowed, in bytes or percentage.
+	failOnWarning           []string // Patterns of the compiler warnings promoted to errors.
+	preprocessor            string   // How the prototypes are generated, ctags or treesitter.
+	maxBoards               int      // Max number of boards a FQBN pattern can expand to.
+	dumpLibraryResolution   bool     // Print how the included headers have been resolved to libraries.
+	autoInstallLibs         bool     // Install the libraries that provide the missing includes.
+	containerImage          string   // Image of the container where the toolchain is run.
+	containerEngine         string   // Engine used to run the container, docker or podman.
 	// library and libraries sound similar but they're actually different.
 	// library expects a path to the root folder of one single library.
 	// libraries expects a path to a directory containing multiple libraries, similarly to the <directories.user>/libraries path.
@@ -71,15 +121,26 @@ func NewCommand() *cobra.Command {
 		Long:  "Compiles Arduino sketches.",
 		Example: "" +
 			"  " + os.Args[0] + " compile -b arduino:avr:uno /home/user/Arduino/MySketch\n" +
+			"  " + os.Args[0] + ` compile -b "esp32:esp32:*" /home/user/Arduino/MySketch` + "\n" +
+			"  " + os.Args[0] + " compile -b arduino:avr:uno -b arduino:samd:mkr1000 /home/user/Arduino/MySketch\n" +
+			"  " + os.Args[0] + " compile --fqbn-file boards.txt --format json /home/user/Arduino/MySketch\n" +
 			"  " + os.Args[0] + ` compile -b arduino:avr:uno --build-property "build.extra_flags=\"-DMY_DEFINE=\"hello world\"\"" /home/user/Arduino/MySketch` + "\n" +
 			"  " + os.Args[0] + ` compile -b arduino:avr:uno --build-property "build.extra_flags=-DPIN=2 \"-DMY_DEFINE=\"hello world\"\"" /home/user/Arduino/MySketch` + "\n" +
-			"  " + os.Args[0] + ` compile -b arduino:avr:uno --build-property build.extra_flags=-DPIN=2 --build-property "compiler.cpp.extra_flags=\"-DSSID=\"hello world\"\"" /home/user/Arduino/MySketch` + "\n",
+			"  " + os.Args[0] + ` compile -b arduino:avr:uno --build-property build.extra_flags=-DPIN=2 --build-property "compiler.cpp.extra_flags=\"-DSSID=\"hello world\"\"" /home/user/Arduino/MySketch` + "\n" +
+			"  " + os.Args[0] + ` compile -b arduino:avr:uno --build-property "compiler.cpp.extra_flags+=-DFOO=1" /home/user/Arduino/MySketch` + "\n" +
+			"  " + os.Args[0] + " compile -b arduino:avr:uno --optimize speed /home/user/Arduino/MySketch\n" +
+			"  " + os.Args[0] + " compile -b arduino:avr:uno --warnings all --library-warnings none /home/user/Arduino/MySketch\n" +
+			"  " + os.Args[0] + " compile -b arduino:avr:uno --container debian:bullseye-slim /home/user/Arduino/MySketch\n",
 		Args: cobra.MaximumNArgs(1),
 		Run:  run,
 	}
 
-	command.Flags().StringVarP(&fqbn, "fqbn", "b", "", "Fully Qualified Board Name, e.g.: arduino:avr:uno")
+	command.Flags().StringArrayVarP(&fqbnFlags, "fqbn", "b", []string{}, "Fully Qualified Board Name, e.g.: arduino:avr:uno. Wildcards like esp32:esp32:* compile for all the matching installed boards. Can be used multiple times to compile for multiple boards.")
+	command.Flags().StringVar(&fqbnFile, "fqbn-file", "", "Optional, path of a file listing the FQBNs (or FQBN patterns) to compile for, one per line. Empty lines and lines starting with # are ignored.")
 	command.Flags().BoolVar(&showProperties, "show-properties", false, "Show all build properties used instead of compiling.")
+	command.Flags().StringArrayVar(&traceProperties, "trace-properties", []string{},
+		"Optional, log where the value of this build property comes from: the boards.txt, platform.txt, platform.local.txt and --build-property definitions that set it and the properties it references. Can be used multiple times for multiple properties.")
+	command.Flags().BoolVar(&showCommands, "show-commands", false, "Show the command lines of the recipes of the platform, expanded with the build properties, instead of compiling.")
 	command.Flags().BoolVar(&preprocess, "preprocess", false, "Print preprocessed code to stdout instead of compiling.")
 	command.Flags().StringVar(&buildCachePath, "build-cache-path", "", "Builds of 'core.a' are saved into this path to be cached and reused.")
 	command.Flags().StringVarP(&exportDir, "output-dir", "", "", "Save build artifacts in this directory.")
@@ -88,23 +149,73 @@ func NewCommand() *cobra.Command {
 	command.Flags().StringSliceVar(&buildProperties, "build-properties", []string{},
 		"List of custom build properties separated by commas. Or can be used multiple times for multiple properties.")
 	command.Flags().StringArrayVar(&buildProperties, "build-property", []string{},
-		"Override a build property with a custom value. Can be used multiple times for multiple properties.")
+		"Override a build property with a custom value (KEY=VALUE) or append a value to it (KEY+=VALUE). Can be used multiple times for multiple properties.")
 	command.Flags().StringVar(&warnings, "warnings", "none",
 		`Optional, can be "none", "default", "more" and "all". Defaults to "none". Used to tell gcc which warning level to use (-W flag).`)
+	command.Flags().StringVar(&libraryWarnings, "library-warnings", "",
+		`Optional, the warning level used to compile the libraries and the core: "none", "default", "more" or "all". Defaults to the level of --warnings.`)
 	command.Flags().BoolVarP(&verbose, "verbose", "v", false, "Optional, turns on verbose mode.")
 	command.Flags().BoolVar(&quiet, "quiet", false, "Optional, suppresses almost every output.")
 	command.Flags().BoolVarP(&uploadAfterCompile, "upload", "u", false, "Upload the binary after the compilation.")
 	command.Flags().StringVarP(&port, "port", "p", "", "Upload port, e.g.: COM10 or /dev/ttyACM0")
 	command.Flags().BoolVarP(&verify, "verify", "t", false, "Verify uploaded binary after the upload.")
+	command.Flags().BoolVar(&monitorAfterUpload, "monitor", false, "Open the serial monitor on the upload port after the upload. A monitor already open on the port is released during the upload.")
+	command.Flags().IntVar(&monitorBaudRate, "monitor-baudrate", 9600, "Baud rate of the serial monitor opened with --monitor.")
 	command.Flags().StringVar(&vidPid, "vid-pid", "", "When specified, VID/PID specific build properties are used, if board supports them.")
 	command.Flags().StringSliceVar(&library, "library", []string{},
 		"List of paths to libraries root folders. Libraries set this way have top priority in case of conflicts. Can be used multiple times for different libraries.")
 	command.Flags().StringSliceVar(&libraries, "libraries", []string{},
 		"List of custom libraries dir paths separated by commas. Or can be used multiple times for multiple libraries dir paths.")
-	command.Flags().BoolVar(&optimizeForDebug, "optimize-for-debug", false, "Optional, optimize compile output for debugging, rather than for release.")
+	command.Flags().StringArrayVar(&exportFormats, "export-format", []string{},
+		"Optional, also export the binary in this format: hex, bin, uf2 or merged (sketch and bootloader). An offset can be added to place the sketch at a different address, e.g. uf2:0x2000. Can be used multiple times for multiple formats.")
+	command.Flags().StringVar(&partitionTable, "partition-table", "",
+		"Optional, use this partitions table (ESP32 CSV format) instead of the one selected by the board options. The size of the sketch is checked against its app partition.")
+	command.Flags().StringVar(&signKey, "sign-key", "",
+		"Optional, sign the sketch binary with this private key (Ed25519 or ECDSA P-256, PEM format) and create an OTA manifest. Defaults to the compile.signing.key setting.")
+	command.Flags().StringVar(&artifactsSignKey, "artifacts-sign-key", "",
+		"Optional, sign the manifest of the exported binaries (<sketch>.ino.artifacts.json) with this private key (Ed25519 or ECDSA P-256, PEM format). Defaults to the compile.signing.artifacts_key setting.")
+	command.Flags().BoolVar(&exportManifest, "export-manifest", false,
+		"Optional, save a manifest of the exported binaries, with the offsets where they are flashed, in the format read by ESP Web Tools.")
+	command.Flags().StringVar(&manifestTemplate, "manifest-template", "",
+		"Optional, render the manifest with this Go text/template file instead of using the ESP Web Tools format. Implies --export-manifest.")
+	command.Flags().StringVar(&manifestVersion, "manifest-version", "", "Optional, the version of the firmware written in the manifest. Defaults to \"dev\".")
+	command.Flags().BoolVar(&optimizeForDebug, "optimize-for-debug", false, "Optional, optimize compile output for debugging, rather than for release. Same as --optimize debug.")
+	command.Flags().StringVar(&optimize, "optimize", "", "Optional, optimization preset applied to core, libraries and sketch: size, speed or debug. Defaults to the optimization level of the platform.")
 	command.Flags().StringVarP(&programmer, "programmer", "P", "", "Optional, use the specified programmer to upload.")
 	command.Flags().BoolVar(&compilationDatabaseOnly, "only-compilation-database", false, "Just produce the compilation database, without actually compiling.")
+	command.Flags().StringVar(&compilationDatabasePath, "compilation-database-path", "", "Optional, save the compilation database (compile_commands.json) in this path instead of the build path.")
+	command.Flags().StringVar(&exportCMake, "export-cmake", "", "Export a standalone CMake project (sources, CMakeLists.txt and toolchain file) equivalent to the build in this directory.")
+	command.Flags().StringVar(&fromArchive, "from-archive", "", "Compile the sketch contained in this archive, created by `sketch archive`, using the libraries included in the archive. Use --output-dir to keep the binaries.")
+	command.Flags().StringVar(&exportBuild, "export-build", "", "Export all the commands run by the build as a Makefile or a build.ninja in the build path: makefile or ninja. Implies --clean.")
+	command.Flags().StringVar(&sbomFormat, "sbom", "", "Optional, save a software bill of materials listing the core, the libraries (with versions, hashes and source URLs) and the tools used by the build, in the build path and with the exported binaries: spdx or cyclonedx.")
 	command.Flags().BoolVar(&clean, "clean", false, "Optional, cleanup the build folder and do not use any cached build.")
+	command.Flags().Int32VarP(&jobs, "jobs", "j", 0, "Max number of parallel compiles. If set to 0 the number of available CPUs cores will be used.")
+	command.Flags().BoolVar(&keepGoing, "keep-going", false, "Optional, keep compiling the files that don't depend on a failed step, to show all the compile errors at once.")
+	command.Flags().BoolVar(&showStats, "stats", false, "Optional, print statistics about the build.")
+	command.Flags().StringVar(&statsFile, "stats-file", "", "Optional, append the statistics about the build, in JSON lines format, to this file.")
+	command.Flags().StringVar(&buildStatsFile, "build-stats", "", "Optional, save the duration of each phase of the build and of each compiled file to this file, in Chrome Trace Event Format (viewable with chrome://tracing or https://ui.perfetto.dev).")
+	command.Flags().StringVar(&sizeReport, "size-report", "summary", "Optional, the size report to print: summary or detailed (memory usage by section, library and symbol).")
+	command.Flags().StringVar(&sizeDeltaFrom, "size-delta-from", "", "Optional, compare the size of the executable with a baseline (an ELF file or the JSON output of a compile with --size-report detailed) and fail if it grew more than the allowed thresholds.")
+	command.Flags().Int64Var(&sizeDeltaMaxFlash, "size-delta-max-flash", 0, "Max growth of the flash usage allowed by --size-delta-from, in bytes.")
+	command.Flags().Int64Var(&sizeDeltaMaxRAM, "size-delta-max-ram", 0, "Max growth of the RAM usage allowed by --size-delta-from, in bytes.")
+	command.Flags().StringVar(&maxFlashUsage, "max-flash-usage", "", "Optional, fail if the sketch uses more flash than this limit, in bytes (e.g. 30000) or as a percentage of the board flash (e.g. 80%).")
+	command.Flags().StringVar(&maxRAMUsage, "max-ram-usage", "", "Optional, fail if the sketch uses more RAM than this limit, in bytes (e.g. 1500) or as a percentage of the board RAM (e.g. 75%).")
+	command.Flags().StringArrayVar(&failOnWarning, "fail-on-warning", []string{},
+		"Optional, promote to errors the compiler warnings with a message matching this regular expression (e.g. -Wreturn-type). Can be used multiple times for multiple patterns. Only the files compiled by this build are checked, use --clean to check all of them.")
+	// treesitter is listed only by the builds that support it
+	preprocessorUsage := "Optional, how the prototypes of the sketch functions are generated: ctags is the only one available in this build."
+	if ctags.TreeSitterSupported {
+		preprocessorUsage = "Optional, how the prototypes of the sketch functions are generated: ctags or treesitter (handles lambdas, nested templates and functions returning function pointers)."
+	}
+	command.Flags().StringVar(&preprocessor, "preprocessor", "ctags", preprocessorUsage)
+	command.Flags().BoolVar(&dumpLibraryResolution, "dump-library-resolution", false, "Optional, print how each #include has been resolved: the candidate libraries, their priority and the selected one.")
+	command.Flags().BoolVar(&autoInstallLibs, "auto-install-libs", false, "Optional, install from the libraries index the libraries that provide the headers included by the sketch but not found.")
+	command.Flags().StringVar(&containerImage, "container", "", "Optional, run the toolchain inside a container created from this image. The sketch, the libraries and the platforms are mounted read-only.")
+	command.Flags().StringVar(&containerEngine, "container-engine", "", "Optional, the engine used to run the container: docker or podman. Defaults to the compile.container.engine setting.")
+	command.Flags().IntVar(&maxBoards, "max-boards", 50, "Max number of boards a FQBN pattern (e.g. esp32:esp32:*) can match, 0 means no limit.")
+	command.Flags().BoolVar(&noAutodetect, "no-autodetect", false, "Don't use the connected board when the FQBN is not specified.")
+	command.Flags().StringVar(&diagnosticsFormat, "diagnostics-format", "", "Optional, save the diagnostics of the compiler in the given format (sarif).")
+	command.Flags().StringVar(&diagnosticsFile, "diagnostics-file", "compile.sarif", "Path of the file where the diagnostics are saved when --diagnostics-format is set.")
 	// We must use the following syntax for this flag since it's also bound to settings.
 	// This must be done because the value is set when the binding is accessed from viper. Accessing from cobra would only
 	// read the value if the flag is set explicitly by the user.
@@ -120,12 +231,95 @@ func NewCommand() *cobra.Command {
 }
 
 func run(cmd *cobra.Command, args []string) {
+	if diagnosticsFormat != "" && diagnosticsFormat != "sarif" {
+		feedback.Errorf("Invalid diagnostics format: %s", diagnosticsFormat)
+		exit(errorcodes.ErrBadArgument)
+	}
+	if monitorAfterUpload && !uploadAfterCompile {
+		feedback.Errorf("--monitor can be used only with --upload.")
+		exit(errorcodes.ErrBadArgument)
+	}
+	if monitorAfterUpload && output.OutputFormat == "json" {
+		feedback.Errorf("--monitor can't be used with the JSON output.")
+		exit(errorcodes.ErrBadArgument)
+	}
+	if sizeReport != "summary" && sizeReport != "detailed" {
+		feedback.Errorf("Invalid size report: %s", sizeReport)
+		exit(errorcodes.ErrBadArgument)
+	}
+	for _, level := range []string{warnings, libraryWarnings} {
+		switch level {
+		case "", "none", "default", "more", "all":
+		default:
+			feedback.Errorf("Invalid warning level: %s", level)
+			exit(errorcodes.ErrBadArgument)
+		}
+	}
+	switch optimize {
+	case "", bldr.OptimizeSize, bldr.OptimizeSpeed, bldr.OptimizeDebug:
+	default:
+		feedback.Errorf("Invalid optimization preset: %s", optimize)
+		exit(errorcodes.ErrBadArgument)
+	}
+	for _, format := range exportFormats {
+		if _, err := bldr.ParseExportFormat(format); err != nil {
+			feedback.Errorf("Invalid export format: %v", err)
+			exit(errorcodes.ErrBadArgument)
+		}
+	}
+	if optimizeForDebug {
+		if optimize != "" && optimize != bldr.OptimizeDebug {
+			feedback.Errorf("Can't use --optimize-for-debug together with --optimize %s", optimize)
+			exit(errorcodes.ErrBadArgument)
+		}
+		optimize = bldr.OptimizeDebug
+	}
+
+	fqbns := append([]string{}, fqbnFlags...)
+	if fqbnFile != "" {
+		fileFqbns, err := loadFqbnFile(paths.New(fqbnFile))
+		if err != nil {
+			feedback.Errorf("Error reading FQBN file: %v", err)
+			exit(errorcodes.ErrBadArgument)
+		}
+		fqbns = append(fqbns, fileFqbns...)
+	}
+	if len(fqbns) == 1 {
+		fqbn = fqbns[0]
+	}
+
 	inst := instance.CreateAndInit()
 
 	var path *paths.Path
 	if len(args) > 0 {
 		path = paths.New(args[0])
 	}
+	if fromArchive != "" {
+		if path != nil {
+			feedback.Errorf("Error: the sketch path can't be used together with --from-archive")
+			exit(errorcodes.ErrBadArgument)
+		}
+		tmp, err := paths.MkTempDir("", "arduino-cli-sketch")
+		if err != nil {
+			feedback.Errorf("Error extracting sketch archive: %v", err)
+			exit(errorcodes.ErrGeneric)
+		}
+		extractedArchive = tmp
+		defer removeExtractedArchive()
+		path, err = sketch.ExtractSketchArchive(context.Background(), paths.New(fromArchive), tmp)
+		if err != nil {
+			feedback.Errorf("Error extracting sketch archive: %v", err)
+			exit(errorcodes.ErrGeneric)
+		}
+		// The archive is built only with the platform pinned by --include-profile
+		if err := sketch.CheckArchivePlatform(commands.GetPackageManager(inst.GetId()), path); err != nil {
+			feedback.Errorf("Error: %v", err)
+			exit(errorcodes.ErrGeneric)
+		}
+		if librariesDir := path.Join("libraries"); librariesDir.IsDir() {
+			libraries = append(libraries, librariesDir.String())
+		}
+	}
 
 	sketchPath := initSketchPath(path)
 
@@ -137,19 +331,23 @@ func run(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	if !noAutodetect && len(fqbns) == 0 {
+		board.Autodetect(inst, sketchPath, &fqbn, &port)
+	}
+
 	var overrides map[string]string
 	if sourceOverrides != "" {
 		data, err := paths.New(sourceOverrides).ReadFile()
 		if err != nil {
 			feedback.Errorf("Error opening source code overrides data file: %v", err)
-			os.Exit(errorcodes.ErrGeneric)
+			exit(errorcodes.ErrGeneric)
 		}
 		var o struct {
 			Overrides map[string]string `json:"overrides"`
 		}
 		if err := json.Unmarshal(data, &o); err != nil {
 			feedback.Errorf("Error: invalid source code overrides data file: %v", err)
-			os.Exit(errorcodes.ErrGeneric)
+			exit(errorcodes.ErrGeneric)
 		}
 		overrides = o.Overrides
 	}
@@ -159,33 +357,87 @@ func run(cmd *cobra.Command, args []string) {
 		Fqbn:                          fqbn,
 		SketchPath:                    sketchPath.String(),
 		ShowProperties:                showProperties,
+		ShowCommands:                  showCommands,
+		TraceProperties:               traceProperties,
 		Preprocess:                    preprocess,
 		BuildCachePath:                buildCachePath,
 		BuildPath:                     buildPath,
 		BuildProperties:               buildProperties,
 		Warnings:                      warnings,
+		LibrariesWarnings:             libraryWarnings,
 		Verbose:                       verbose,
 		Quiet:                         quiet,
 		VidPid:                        vidPid,
 		ExportDir:                     exportDir,
 		Libraries:                     libraries,
 		OptimizeForDebug:              optimizeForDebug,
+		Optimize:                      optimize,
+		ExportFormats:                 exportFormats,
+		PartitionTable:                partitionTable,
+		SignKey:                       signKey,
+		ArtifactsSignKey:              artifactsSignKey,
+		ExportManifest:                exportManifest,
+		ManifestTemplate:              manifestTemplate,
+		ManifestVersion:               manifestVersion,
 		Clean:                         clean,
 		CreateCompilationDatabaseOnly: compilationDatabaseOnly,
+		CompilationDatabasePath:       compilationDatabasePath,
+		ExportCmake:                   exportCMake,
+		ExportBuild:                   exportBuild,
+		Sbom:                          sbomFormat,
 		SourceOverride:                overrides,
 		Library:                       library,
+		Jobs:                          jobs,
+		KeepGoing:                     keepGoing,
+		Stats:                         showStats || statsFile != "" || buildStatsFile != "",
+		SizeReport:                    sizeReport,
+		SizeDeltaFrom:                 sizeDeltaFrom,
+		SizeDeltaMaxFlash:             sizeDeltaMaxFlash,
+		SizeDeltaMaxRam:               sizeDeltaMaxRAM,
+		MaxFlashUsage:                 maxFlashUsage,
+		MaxRamUsage:                   maxRAMUsage,
+		FailOnWarning:                 failOnWarning,
+		Preprocessor:                  preprocessor,
+		DumpLibraryResolution:         dumpLibraryResolution,
+		ContainerImage:                containerImage,
+		ContainerEngine:               containerEngine,
 	}
+	if len(fqbns) > 1 || cmdboard.IsFQBNPattern(fqbn) {
+		runMatrix(inst, sketchPath, compileRequest, fqbns)
+		return
+	}
+
 	compileOut := new(bytes.Buffer)
 	compileErr := new(bytes.Buffer)
 	verboseCompile := configuration.Settings.GetString("logging.level") == "debug"
+	build := func() (*rpc.CompileResponse, error) {
+		if output.OutputFormat == "json" {
+			compileOut.Reset()
+			compileErr.Reset()
+			return compile.Compile(context.Background(), compileRequest, compileOut, compileErr, nil, verboseCompile)
+		}
+		if output.OutputFormat == "ndjson" {
+			// The output of the compiler is printed as "output" events
+			stdout, stderr := feedback.NewEventWriter("stdout"), feedback.NewEventWriter("stderr")
+			defer stdout.Flush()
+			defer stderr.Flush()
+			return compile.Compile(context.Background(), compileRequest, stdout, stderr, output.CompileProgress(), verboseCompile)
+		}
+		return compile.Compile(context.Background(), compileRequest, os.Stdout, os.Stderr, nil, verboseCompile)
+	}
 	var compileRes *rpc.CompileResponse
+	var installedLibs []string
 	var err error
-	if output.OutputFormat == "json" {
-		compileRes, err = compile.Compile(context.Background(), compileRequest, compileOut, compileErr, verboseCompile)
+	if autoInstallLibs {
+		compileRes, installedLibs, err = lib.InstallMissingIncludes(inst, build)
 	} else {
-		compileRes, err = compile.Compile(context.Background(), compileRequest, os.Stdout, os.Stderr, verboseCompile)
+		compileRes, err = build()
 	}
 
+	uploadOut := new(bytes.Buffer)
+	uploadErr := new(bytes.Buffer)
+	var uploadRes *rpc.UploadResult
+	uploadError := ""
 	if err == nil && uploadAfterCompile {
 		uploadRequest := &rpc.UploadRequest{
 			Instance:   inst,
@@ -197,33 +449,106 @@ func run(cmd *cobra.Command, args []string) {
 			ImportDir:  buildPath,
 			Programmer: programmer,
 		}
+		var res *rpc.UploadResponse
 		var err error
 		if output.OutputFormat == "json" {
-			// TODO: do not print upload output in json mode
-			uploadOut := new(bytes.Buffer)
-			uploadErr := new(bytes.Buffer)
-			_, err = upload.Upload(context.Background(), uploadRequest, uploadOut, uploadErr)
+			// The output of the tool is reported in the compile result and
+			// no progress events are printed, to keep the JSON valid
+			res, err = upload.Upload(context.Background(), uploadRequest, uploadOut, uploadErr, nil)
+			uploadRes = res.GetResult()
+		} else if output.OutputFormat == "ndjson" {
+			stdout, stderr := feedback.NewEventWriter("stdout"), feedback.NewEventWriter("stderr")
+			res, err = upload.Upload(context.Background(), uploadRequest, stdout, stderr, output.UploadProgressBar())
+			uploadRes = res.GetResult()
+			stdout.Flush()
+			stderr.Flush()
 		} else {
-			_, err = upload.Upload(context.Background(), uploadRequest, os.Stdout, os.Stderr)
+			_, err = upload.Upload(context.Background(), uploadRequest, os.Stdout, os.Stderr, output.UploadProgressBar())
 		}
 		if err != nil {
-			feedback.Errorf("Error during Upload: %v", err)
-			os.Exit(errorcodes.ErrGeneric)
+			uploadError = err.Error()
+			if output.OutputFormat != "json" {
+				feedback.Errorf("Error during Upload: %v", err)
+				exit(errorcodes.ErrGeneric)
+			}
+		}
+	}
+
+	if diagnosticsFormat == "sarif" && compileRes != nil {
+		if err := writeSARIF(paths.New(diagnosticsFile), compileRes.GetDiagnostics()); err != nil {
+			feedback.Errorf("Error writing diagnostics: %v", err)
+		}
+	}
+
+	if err == nil && statsFile != "" {
+		if err := appendStats(paths.New(statsFile), sketchPath, fqbn, compileRes); err != nil {
+			feedback.Errorf("Error writing build statistics: %v", err)
+		}
+	}
+
+	if err == nil && buildStatsFile != "" {
+		if err := writeBuildTrace(paths.New(buildStatsFile), compileRes.GetStats().GetEvents()); err != nil {
+			feedback.Errorf("Error writing build trace: %v", err)
 		}
 	}
 
 	feedback.PrintResult(&compileResult{
-		CompileOut:    compileOut.String(),
-		CompileErr:    compileErr.String(),
-		BuilderResult: compileRes,
-		Success:       err == nil,
+		CompileOut:            compileOut.String(),
+		CompileErr:            compileErr.String(),
+		BuilderResult:         compileRes,
+		Success:               err == nil,
+		showStats:             showStats,
+		showLibraryResolution: dumpLibraryResolution,
+		InstalledLibraries:    installedLibs,
+		UploadOut:             uploadOut.String(),
+		UploadErr:             uploadErr.String(),
+		UploadResult:          uploadRes,
+		UploadError:           uploadError,
 	})
+	if uploadError != "" {
+		exit(errorcodes.ErrGeneric)
+	}
 	if err != nil && output.OutputFormat != "json" {
 		feedback.Errorf("Error during build: %v", err)
-		os.Exit(errorcodes.ErrGeneric)
+		if hint := lib.MissingIncludeHint(inst, err); hint != "" && !autoInstallLibs {
+			feedback.Print(hint)
+		}
+		var sizeErr *bldr.SizeLimitError
+		if errors.As(err, &sizeErr) {
+			exit(errorcodes.ErrSizeLimit)
+		}
+		exit(errorcodes.ErrGeneric)
+	}
+
+	if monitorAfterUpload {
+		if port == "" {
+			feedback.Errorf("No port to monitor, specify it with --port.")
+			exit(errorcodes.ErrBadArgument)
+		}
+		// The monitor runs until the process is terminated
+		removeExtractedArchive()
+		monitor.Run(port, monitorBaudRate)
+	}
+}
+
+// extractedArchive is the temporary folder where the sketch archive given
+// with --from-archive is extracted
+var extractedArchive *paths.Path
+
+func removeExtractedArchive() {
+	if extractedArchive != nil {
+		extractedArchive.RemoveAll()
+		extractedArchive = nil
 	}
 }
 
+// exit terminates the process with the given exit code. The deferred calls
+// are not run by os.Exit, so the extracted archive is removed here.
+func exit(code int) {
+	removeExtractedArchive()
+	os.Exit(code)
+}
+
 // initSketchPath returns the current working directory
 func initSketchPath(sketchPath *paths.Path) *paths.Path {
 	if sketchPath != nil {
@@ -233,24 +558,65 @@ func initSketchPath(sketchPath *paths.Path) *paths.Path {
 	wd, err := paths.Getwd()
 	if err != nil {
 		feedback.Errorf("Couldn't get current working directory: %v", err)
-		os.Exit(errorcodes.ErrGeneric)
+		exit(errorcodes.ErrGeneric)
 	}
 	logrus.Infof("Reading sketch from dir: %s", wd)
 	return wd
 }
 
 type compileResult struct {
-	CompileOut    string               `json:"compiler_out"`
-	CompileErr    string               `json:"compiler_err"`
-	BuilderResult *rpc.CompileResponse `json:"builder_result"`
-	Success       bool                 `json:"success"`
+	CompileOut            string               `json:"compiler_out"`
+	CompileErr            string               `json:"compiler_err"`
+	BuilderResult         *rpc.CompileResponse `json:"builder_result"`
+	Success               bool                 `json:"success"`
+	InstalledLibraries    []string             `json:"installed_libraries,omitempty"`
+	UploadOut             string               `json:"upload_out,omitempty"`
+	UploadErr             string               `json:"upload_err,omitempty"`
+	UploadResult          *rpc.UploadResult    `json:"upload_result,omitempty"`
+	UploadError           string               `json:"upload_error,omitempty"`
+	showStats             bool
+	showLibraryResolution bool
 }
 
 func (r *compileResult) Data() interface{} {
 	return r
 }
 
+func (r *compileResult) Annotations() []*feedback.Annotation {
+	return diagnosticsAnnotations(r.BuilderResult.GetDiagnostics(), "")
+}
+
+// diagnosticsAnnotations converts the compiler diagnostics to annotations
+// with the given title
+func diagnosticsAnnotations(diagnostics []*rpc.CompileDiagnostic, title string) []*feedback.Annotation {
+	res := []*feedback.Annotation{}
+	for _, diag := range diagnostics {
+		res = append(res, &feedback.Annotation{
+			Severity: diag.GetSeverity(),
+			File:     diag.GetFile(),
+			Line:     int(diag.GetLine()),
+			Column:   int(diag.GetColumn()),
+			Title:    title,
+			Message:  diag.GetMessage(),
+		})
+	}
+	return res
+}
+
 func (r *compileResult) String() string {
 	// The output is already printed via os.Stdout/os.Stdin
-	return ""
+	out := ""
+	if r.BuilderResult != nil && r.BuilderResult.GetSizeReport() != nil {
+		out += formatSizeReport(r.BuilderResult.GetSizeReport())
+	}
+	if r.BuilderResult != nil && r.BuilderResult.GetSizeDelta() != nil {
+		out += formatSizeDelta(r.BuilderResult.GetSizeDelta())
+	}
+	if r.showStats && r.BuilderResult != nil && r.BuilderResult.GetStats() != nil {
+		out += formatStats(r.BuilderResult)
+	}
+	if r.showLibraryResolution && r.BuilderResult != nil {
+		out += formatLibraryResolution(r.BuilderResult.GetLibraryResolution())
+	}
+	return out
 }
@@ -0,0 +1,55 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"fmt"
+	"strings"
+
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+)
+
+// formatLibraryResolution renders the trace of the library resolution: for
+// each #include the libraries considered, with their priority, and the
+// selected one.
+func formatLibraryResolution(trace []*rpc.LibraryResolution) string {
+	out := "\nLibrary resolution:\n"
+	if len(trace) == 0 {
+		out += "  No library has been resolved.\n"
+		return out
+	}
+	for _, entry := range trace {
+		out += fmt.Sprintf("  #include <%s> in %s\n", entry.GetInclude(), entry.GetSourceFile())
+		for _, candidate := range entry.GetCandidates() {
+			marker := " "
+			if candidate.GetSelected() {
+				marker = "*"
+			}
+			name := candidate.GetName()
+			if candidate.GetVersion() != "" {
+				name += "@" + candidate.GetVersion()
+			}
+			location := strings.ToLower(strings.TrimPrefix(candidate.GetLocation().String(), "LIBRARY_LOCATION_"))
+			out += fmt.Sprintf("    %s %-30s priority %4d  %-9s %s\n", marker, name, candidate.GetPriority(), location, candidate.GetInstallDir())
+		}
+		if entry.GetSelected() != "" {
+			out += fmt.Sprintf("    -> %s (%s)\n", entry.GetSelected(), entry.GetReason())
+		} else {
+			out += fmt.Sprintf("    -> no library selected (%s)\n", entry.GetReason())
+		}
+	}
+	return out
+}
@@ -0,0 +1,244 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/arduino-cli/cli/lib"
+	"github.com/arduino/arduino-cli/cli/output"
+	"github.com/arduino/arduino-cli/commands/board"
+	"github.com/arduino/arduino-cli/commands/compile"
+	"github.com/arduino/arduino-cli/configuration"
+	"github.com/arduino/arduino-cli/junit"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/arduino-cli/table"
+	"github.com/arduino/go-paths-helper"
+	"google.golang.org/protobuf/proto"
+)
+
+// runMatrix compiles the sketch for all the given FQBNs, the patterns are
+// expanded to all the matching installed boards. The same instance is used
+// for all the builds. The build path, the output dir and the diagnostics file
+// of each board get the FQBN added to their name.
+func runMatrix(inst *rpc.Instance, sketchPath *paths.Path, req *rpc.CompileRequest, patterns []string) {
+	if uploadAfterCompile {
+		feedback.Errorf("Upload is not supported when compiling for multiple boards")
+		exit(errorcodes.ErrBadArgument)
+	}
+	fqbns := []string{}
+	added := map[string]bool{}
+	for _, pattern := range patterns {
+		matches := []string{pattern}
+		if board.IsFQBNPattern(pattern) {
+			var err error
+			matches, err = board.ExpandFQBNPattern(context.Background(), inst, pattern, maxBoards)
+			if err != nil {
+				feedback.Errorf("Error expanding FQBN pattern: %v", err)
+				exit(errorcodes.ErrBadArgument)
+			}
+			if output.OutputFormat != "json" {
+				feedback.Printf("%s matches %d boards:\n  %s\n", pattern, len(matches), strings.Join(matches, "\n  "))
+			}
+		}
+		for _, fqbn := range matches {
+			if !added[fqbn] {
+				added[fqbn] = true
+				fqbns = append(fqbns, fqbn)
+			}
+		}
+	}
+
+	verboseCompile := configuration.Settings.GetString("logging.level") == "debug"
+	res := &matrixResult{Boards: []*matrixEntry{}, sketchName: sketchPath.Base()}
+	for _, fqbn := range fqbns {
+		suffix := strings.ReplaceAll(fqbn, ":", ".")
+		boardReq := proto.Clone(req).(*rpc.CompileRequest)
+		boardReq.Fqbn = fqbn
+		if buildPath != "" {
+			boardReq.BuildPath = paths.New(buildPath).Join(suffix).String()
+		}
+		if exportDir != "" {
+			boardReq.ExportDir = paths.New(exportDir).Join(suffix).String()
+		}
+
+		compileOut := new(bytes.Buffer)
+		compileErr := new(bytes.Buffer)
+		build := func() (*rpc.CompileResponse, error) {
+			if output.OutputFormat != "text" {
+				compileOut.Reset()
+				compileErr.Reset()
+				return compile.Compile(context.Background(), boardReq, compileOut, compileErr, output.CompileProgress(), verboseCompile)
+			}
+			feedback.Printf("Compiling for %s...", fqbn)
+			return compile.Compile(context.Background(), boardReq, os.Stdout, os.Stderr, nil, verboseCompile)
+		}
+		var compileRes *rpc.CompileResponse
+		var installedLibs []string
+		var err error
+		start := time.Now()
+		if autoInstallLibs {
+			compileRes, installedLibs, err = lib.InstallMissingIncludes(inst, build)
+		} else {
+			compileRes, err = build()
+		}
+		duration := time.Since(start)
+
+		if diagnosticsFormat == "sarif" && compileRes != nil {
+			file := paths.New(diagnosticsFile)
+			file = file.Parent().Join(strings.TrimSuffix(file.Base(), file.Ext()) + "." + suffix + file.Ext())
+			if err := writeSARIF(file, compileRes.GetDiagnostics()); err != nil {
+				feedback.Errorf("Error writing diagnostics: %v", err)
+			}
+		}
+		if err == nil && statsFile != "" {
+			if err := appendStats(paths.New(statsFile), sketchPath, fqbn, compileRes); err != nil {
+				feedback.Errorf("Error writing build statistics: %v", err)
+			}
+		}
+		if err == nil && buildStatsFile != "" {
+			file := paths.New(buildStatsFile)
+			file = file.Parent().Join(strings.TrimSuffix(file.Base(), file.Ext()) + "." + suffix + file.Ext())
+			if err := writeBuildTrace(file, compileRes.GetStats().GetEvents()); err != nil {
+				feedback.Errorf("Error writing build trace: %v", err)
+			}
+		}
+
+		entry := &matrixEntry{
+			Fqbn:     fqbn,
+			duration: duration,
+			Result: &compileResult{
+				CompileOut:         compileOut.String(),
+				CompileErr:         compileErr.String(),
+				BuilderResult:      compileRes,
+				Success:            err == nil,
+				showStats:          showStats,
+				InstalledLibraries: installedLibs,
+			},
+		}
+		if err != nil {
+			entry.Error = err.Error()
+			if output.OutputFormat != "json" {
+				feedback.Errorf("Error during build for %s: %v", fqbn, err)
+			}
+		} else if output.OutputFormat != "json" {
+			if out := entry.Result.String(); out != "" {
+				feedback.Print(out)
+			}
+		}
+		res.Boards = append(res.Boards, entry)
+	}
+
+	feedback.PrintResult(res)
+	for _, entry := range res.Boards {
+		if !entry.Result.Success {
+			exit(errorcodes.ErrGeneric)
+		}
+	}
+}
+
+// loadFqbnFile returns the FQBNs listed in the given file, one per line.
+// Empty lines and comments starting with # are ignored.
+func loadFqbnFile(file *paths.Path) ([]string, error) {
+	data, err := file.ReadFile()
+	if err != nil {
+		return nil, err
+	}
+	fqbns := []string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fqbns = append(fqbns, line)
+	}
+	if len(fqbns) == 0 {
+		return nil, fmt.Errorf("no FQBN found in %s", file)
+	}
+	return fqbns, nil
+}
+
+type matrixEntry struct {
+	Fqbn   string         `json:"fqbn"`
+	Result *compileResult `json:"result"`
+	Error  string         `json:"error,omitempty"`
+
+	duration time.Duration
+}
+
+type matrixResult struct {
+	Boards []*matrixEntry `json:"boards"`
+
+	sketchName string
+}
+
+func (r *matrixResult) Data() interface{} {
+	return r
+}
+
+func (r *matrixResult) Annotations() []*feedback.Annotation {
+	res := []*feedback.Annotation{}
+	for _, entry := range r.Boards {
+		res = append(res, diagnosticsAnnotations(entry.Result.BuilderResult.GetDiagnostics(), entry.Fqbn)...)
+	}
+	return res
+}
+
+func (r *matrixResult) String() string {
+	t := table.New()
+	t.SetHeader("FQBN", "Result")
+	failed := 0
+	for _, entry := range r.Boards {
+		result := "ok"
+		if !entry.Result.Success {
+			result = "failed"
+			failed++
+		}
+		t.AddRow(entry.Fqbn, result)
+	}
+	return t.Render() + fmt.Sprintf("\n%d of %d builds failed", failed, len(r.Boards))
+}
+
+// JUnit returns a report with a test suite for the sketch and a test case
+// for each board
+func (r *matrixResult) JUnit() *junit.TestSuites {
+	suite := &junit.TestSuite{Name: r.sketchName}
+	total := time.Duration(0)
+	for _, entry := range r.Boards {
+		c := &junit.TestCase{
+			Name:      entry.Fqbn,
+			ClassName: r.sketchName,
+			Time:      junit.Seconds(entry.duration),
+			SystemOut: entry.Result.CompileOut,
+		}
+		if !entry.Result.Success {
+			c.Failure = &junit.Message{Message: entry.Error, Details: entry.Result.CompileErr}
+		}
+		suite.AddCase(c)
+		total += entry.duration
+	}
+	suite.Time = junit.Seconds(total)
+	report := &junit.TestSuites{}
+	report.AddSuite(suite)
+	return report
+}
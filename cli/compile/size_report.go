@@ -0,0 +1,90 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"fmt"
+
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/arduino-cli/table"
+)
+
+// formatSizeReport renders the detailed memory usage in a human readable form
+func formatSizeReport(report *rpc.SizeReport) string {
+	memory := func(flash, ram bool) string {
+		switch {
+		case flash && ram:
+			return "flash+RAM"
+		case ram:
+			return "RAM"
+		default:
+			return "flash"
+		}
+	}
+
+	out := "\nMemory usage by section:\n"
+	t := table.New()
+	t.SetHeader("Section", "Size", "Memory")
+	for _, section := range report.GetSections() {
+		t.AddRow(section.GetName(), section.GetSize(), memory(section.GetFlash(), section.GetRam()))
+	}
+	out += t.Render()
+
+	out += "\nMemory usage by origin:\n"
+	t = table.New()
+	t.SetHeader("Origin", "Flash", "RAM")
+	for _, origin := range report.GetOrigins() {
+		t.AddRow(origin.GetName(), origin.GetFlash(), origin.GetRam())
+	}
+	out += t.Render()
+
+	symbols := report.GetSymbols()
+	out += fmt.Sprintf("\nTop %d symbols:\n", len(symbols))
+	t = table.New()
+	t.SetHeader("Symbol", "Size", "Section", "Origin")
+	for _, symbol := range symbols {
+		t.AddRow(symbol.GetName(), symbol.GetSize(), symbol.GetSection(), symbol.GetOrigin())
+	}
+	out += t.Render()
+	return out
+}
+
+// formatSizeDelta renders the difference with the baseline size in a human
+// readable form
+func formatSizeDelta(delta *rpc.SizeDelta) string {
+	signed := func(x int64) string {
+		return fmt.Sprintf("%+d", x)
+	}
+
+	out := "\nSize compared to the baseline:\n"
+	t := table.New()
+	t.SetHeader("Memory", "Baseline", "Current", "Delta")
+	t.AddRow("Flash", delta.GetBaselineFlash(), delta.GetFlash(), signed(delta.GetFlash()-delta.GetBaselineFlash()))
+	t.AddRow("RAM", delta.GetBaselineRam(), delta.GetRam(), signed(delta.GetRam()-delta.GetBaselineRam()))
+	out += t.Render()
+
+	if len(delta.GetSymbols()) == 0 {
+		return out
+	}
+	out += "\nSymbols changed:\n"
+	t = table.New()
+	t.SetHeader("Symbol", "Baseline", "Current", "Delta", "Origin")
+	for _, symbol := range delta.GetSymbols() {
+		t.AddRow(symbol.GetName(), symbol.GetBaselineSize(), symbol.GetSize(), signed(symbol.GetSize()-symbol.GetBaselineSize()), symbol.GetOrigin())
+	}
+	out += t.Render()
+	return out
+}
@@ -0,0 +1,102 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
+	"google.golang.org/protobuf/proto"
+)
+
+// statsRecord is a line of the stats file
+type statsRecord struct {
+	Timestamp time.Time                    `json:"timestamp"`
+	Sketch    string                       `json:"sketch"`
+	FQBN      string                       `json:"fqbn"`
+	Stats     *rpc.BuildStats              `json:"stats"`
+	Sections  []*rpc.ExecutableSectionSize `json:"executable_sections_size"`
+}
+
+// appendStats appends the statistics of the build to the given file as a
+// single JSON line, so that the trend of a project can be analyzed over time.
+func appendStats(file *paths.Path, sketchPath *paths.Path, fqbn string, res *rpc.CompileResponse) error {
+	// The events are saved with --build-stats, keep the stats lines short
+	stats := proto.Clone(res.GetStats()).(*rpc.BuildStats)
+	stats.Events = nil
+	record := &statsRecord{
+		Timestamp: time.Now().UTC(),
+		Sketch:    sketchPath.String(),
+		FQBN:      fqbn,
+		Stats:     stats,
+		Sections:  res.GetExecutableSectionsSize(),
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(file.String(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// formatStats renders the statistics of the build in a human readable form
+func formatStats(res *rpc.CompileResponse) string {
+	stats := res.GetStats()
+	libs := "none"
+	if len(stats.GetLibraries()) > 0 {
+		libs = strings.Join(stats.GetLibraries(), ", ")
+	}
+	out := "\nBuild statistics:\n"
+	out += fmt.Sprintf("  Translation units compiled: %d\n", stats.GetTranslationUnits())
+	out += fmt.Sprintf("  Object files reused:        %d\n", stats.GetReusedObjectFiles())
+	out += fmt.Sprintf("  Lines compiled:             %d\n", stats.GetLinesCompiled())
+	out += fmt.Sprintf("  Core cache hit:             %t\n", stats.GetCoreCacheHit())
+	out += fmt.Sprintf("  Libraries used:             %s\n", libs)
+	out += fmt.Sprintf("  Wall time:                  %s\n", time.Duration(stats.GetWallTimeMs())*time.Millisecond)
+	out += fmt.Sprintf("  Compiler CPU time:          %s\n", time.Duration(stats.GetCpuTimeMs())*time.Millisecond)
+	out += fmt.Sprintf("  Binary entropy:             %.3f bits/byte\n", stats.GetBinaryEntropy())
+	for _, section := range res.GetExecutableSectionsSize() {
+		out += fmt.Sprintf("  Section %-19s %d bytes\n", section.GetName()+":", section.GetSize())
+	}
+	phases := []*rpc.BuildEvent{}
+	for _, event := range stats.GetEvents() {
+		if event.GetCategory() == "phase" {
+			phases = append(phases, event)
+		}
+	}
+	sort.SliceStable(phases, func(i, j int) bool { return phases[i].GetDurationUs() > phases[j].GetDurationUs() })
+	if len(phases) > 5 {
+		phases = phases[:5]
+	}
+	if len(phases) > 0 {
+		out += "  Slowest phases:\n"
+	}
+	for _, phase := range phases {
+		out += fmt.Sprintf("    %-40s %s\n", phase.GetName(), time.Duration(phase.GetDurationUs())*time.Microsecond)
+	}
+	return out
+}
@@ -68,6 +68,13 @@ func runSetCommand(cmd *cobra.Command, args []string) {
 			feedback.Errorf("error parsing value: %v", err)
 			os.Exit(errorcodes.ErrGeneric)
 		}
+	case reflect.Int:
+		var err error
+		value, err = strconv.Atoi(args[1])
+		if err != nil {
+			feedback.Errorf("error parsing value: %v", err)
+			os.Exit(errorcodes.ErrGeneric)
+		}
 	}
 
 	configuration.Settings.Set(key, value)
@@ -22,10 +22,27 @@ import (
 
 var validMap = map[string]reflect.Kind{
 	"board_manager.additional_urls": reflect.Slice,
+	"build_cache.path":              reflect.String,
+	"build_cache.max_size_mb":       reflect.Int,
+	"build_cache.eviction_policy":   reflect.String,
+	"build_cache.remote_url":        reflect.String,
+	"build_cache.remote_read_only":  reflect.Bool,
+	"compile.fail_on_warning":       reflect.Slice,
+	"compile.hooks.pre_preprocess":  reflect.Slice,
+	"compile.hooks.pre_link":        reflect.Slice,
+	"compile.hooks.post_build":      reflect.Slice,
+	"compile.allow_sketch_hooks":    reflect.Bool,
+	"compile.container.image":       reflect.String,
+	"compile.container.engine":      reflect.String,
+	"compile.signing.key":           reflect.String,
+	"compile.signing.artifacts_key": reflect.String,
 	"daemon.port":                   reflect.String,
+	"directories.boards_overlay":    reflect.String,
 	"directories.data":              reflect.String,
 	"directories.downloads":         reflect.String,
 	"directories.user":              reflect.String,
+	"directories.user_hardware":     reflect.Slice,
+	"library.allowed_licenses":      reflect.Slice,
 	"library.enable_unsafe_install": reflect.Bool,
 	"logging.file":                  reflect.String,
 	"logging.format":                reflect.String,
@@ -33,8 +50,29 @@ var validMap = map[string]reflect.Kind{
 	"sketch.always_export_binaries": reflect.Bool,
 	"metrics.addr":                  reflect.String,
 	"metrics.enabled":               reflect.Bool,
+	"metrics.pprof_token":           reflect.String,
+	"network.backoff":               reflect.String,
+	"network.mirrors":               reflect.Slice,
+	"network.offline":               reflect.Bool,
+	"network.parallel_downloads":    reflect.Int,
 	"network.proxy":                 reflect.String,
+	"network.proxy_command":         reflect.String,
+	"network.proxy_password":        reflect.String,
+	"network.proxy_username":        reflect.String,
+	"network.retries":               reflect.Int,
+	"network.timeouts.build_cache":  reflect.String,
+	"network.timeouts.discovery":    reflect.String,
+	"network.timeouts.download":     reflect.String,
+	"network.timeouts.index_update": reflect.String,
+	"network.use_netrc":             reflect.Bool,
 	"network.user_agent_ext":        reflect.String,
+	"remote.cli_path":               reflect.String,
+	"security.level":                reflect.String,
+	"security.trusted_keys":         reflect.Slice,
+	"updater.index_delta":           reflect.Bool,
+	"updater.index_ttl":             reflect.String,
+	"upload.discovery_timeout":      reflect.String,
+	"upload.retries":                reflect.Int,
 }
 
 func typeOf(key string) (reflect.Kind, error) {
@@ -31,8 +31,11 @@ func NewCommand() *cobra.Command {
 	}
 
 	coreCommand.AddCommand(initDownloadCommand())
+	coreCommand.AddCommand(initDetailsCommand())
 	coreCommand.AddCommand(initInstallCommand())
 	coreCommand.AddCommand(initListCommand())
+	coreCommand.AddCommand(initLintCommand())
+	coreCommand.AddCommand(initPackageCommand())
 	coreCommand.AddCommand(initUpdateIndexCommand())
 	coreCommand.AddCommand(initUpgradeCommand())
 	coreCommand.AddCommand(initUninstallCommand())
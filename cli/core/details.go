@@ -0,0 +1,130 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/arduino-cli/cli/globals"
+	"github.com/arduino/arduino-cli/cli/instance"
+	"github.com/arduino/arduino-cli/cli/output"
+	"github.com/arduino/arduino-cli/commands/core"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/arduino-cli/table"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func initDetailsCommand() *cobra.Command {
+	detailsCommand := &cobra.Command{
+		Use:   "details PACKAGER:ARCH[@VERSION]",
+		Short: "Shows the details of a core and of the tools it requires.",
+		Long:  "Shows the details of a core, the tools it requires with their download size and whether a post-install script will be run.",
+		Example: "  # show what installing the latest Arduino SAMD core involves.\n" +
+			"  " + os.Args[0] + " core details arduino:samd\n\n" +
+			"  # show the details of a specific version (in this case 1.6.9).\n" +
+			"  " + os.Args[0] + " core details arduino:samd@1.6.9",
+		Args: cobra.ExactArgs(1),
+		Run:  runDetailsCommand,
+	}
+	return detailsCommand
+}
+
+func runDetailsCommand(cmd *cobra.Command, args []string) {
+	inst := instance.CreateAndInit()
+	logrus.Info("Executing `arduino core details`")
+
+	platformsRefs, err := globals.ParseReferenceArgs(args, true)
+	if err != nil {
+		feedback.Errorf("Invalid argument passed: %v", err)
+		os.Exit(errorcodes.ErrBadArgument)
+	}
+	platformRef := platformsRefs[0]
+
+	res, err := core.PlatformDetails(context.Background(), &rpc.PlatformDetailsRequest{
+		Instance:        inst,
+		PlatformPackage: platformRef.PackageName,
+		Architecture:    platformRef.Architecture,
+		Version:         platformRef.Version,
+	})
+	if err != nil {
+		feedback.Errorf("Error getting core details: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+
+	feedback.PrintResult(detailsResult{res})
+}
+
+// output from this command requires special formatting, let's create a dedicated
+// feedback.Result implementation
+type detailsResult struct {
+	details *rpc.PlatformDetailsResponse
+}
+
+func (dr detailsResult) Data() interface{} {
+	return dr.details
+}
+
+func (dr detailsResult) String() string {
+	platform := dr.details.GetPlatform()
+	installed := platform.GetInstalled()
+	if installed == "" {
+		installed = "no"
+	}
+	postInstall := "unknown until downloaded"
+	switch dr.details.GetPostInstall() {
+	case rpc.PostInstallScript_POST_INSTALL_SCRIPT_NONE:
+		postInstall = "no"
+	case rpc.PostInstallScript_POST_INSTALL_SCRIPT_PRESENT:
+		postInstall = "yes, it may install drivers or change system settings"
+	}
+
+	var sb strings.Builder
+	header := table.New()
+	header.AddRow("Name:", platform.GetName())
+	header.AddRow("ID:", platform.GetId())
+	header.AddRow("Version:", platform.GetLatest())
+	header.AddRow("Installed:", installed)
+	header.AddRow("Size:", output.FormatSize(dr.details.GetSize()))
+	header.AddRow("Post-install script:", postInstall)
+	sb.WriteString(header.Render())
+
+	download := int64(0)
+	if platform.GetInstalled() != platform.GetLatest() {
+		download += dr.details.GetSize()
+	}
+	if len(dr.details.GetTools()) > 0 {
+		t := table.New()
+		t.SetHeader("Tool", "Version", "Size", "Installed")
+		for _, tool := range dr.details.GetTools() {
+			installed := "no"
+			if tool.GetInstalled() {
+				installed = "yes"
+			} else {
+				download += tool.GetSize()
+			}
+			t.AddRow(tool.GetPackager()+":"+tool.GetName(), tool.GetVersion(), output.FormatSize(tool.GetSize()), installed)
+		}
+		sb.WriteString("\n" + t.Render())
+	}
+	sb.WriteString(fmt.Sprintf("\nDownload size: %s\n", output.FormatSize(download)))
+	return sb.String()
+}
@@ -0,0 +1,173 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package core
+
+import (
+	"os"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/arduino/cores/lint"
+	"github.com/arduino/arduino-cli/arduino/cores/packageindex"
+	"github.com/arduino/arduino-cli/arduino/sketches/check"
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/go-paths-helper"
+	properties "github.com/arduino/go-properties-orderedmap"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	semver "go.bug.st/relaxed-semver"
+)
+
+var lintFlags struct {
+	packageIndex  string
+	platform      string
+	failOnWarning bool
+}
+
+func initLintCommand() *cobra.Command {
+	lintCommand := &cobra.Command{
+		Use:   "lint [<platformPath>]",
+		Short: "Checks a platform under development for common problems.",
+		Long: "Checks the platform.txt and boards.txt of a platform: the missing recipes, the references to undefined properties, " +
+			"the boards and their menus. With --package-index the tools used by the platform are also checked against the tool " +
+			"dependencies of the platform in the given package index. Use --format json to get the diagnostics in a machine readable format.",
+		Example: "" +
+			"  " + os.Args[0] + " core lint ~/Arduino/hardware/mycompany/avr\n" +
+			"  " + os.Args[0] + " core lint . --package-index package_mycompany_index.json --platform mycompany:avr",
+		Args: cobra.MaximumNArgs(1),
+		Run:  runLintCommand,
+	}
+	lintCommand.Flags().StringVar(&lintFlags.packageIndex, "package-index", "", "Check the tools against the dependencies defined in the given package index file.")
+	lintCommand.Flags().StringVar(&lintFlags.platform, "platform", "", "The platform in the package index as PACKAGER:ARCH, defaults to the names of the platform folder and of its parent.")
+	lintCommand.Flags().BoolVar(&lintFlags.failOnWarning, "fail-on-warning", false, "Exit with an error also if only warnings are found.")
+	return lintCommand
+}
+
+func runLintCommand(cmd *cobra.Command, args []string) {
+	logrus.Info("Executing `arduino core lint`")
+
+	platformPath := paths.New(".")
+	if len(args) == 1 {
+		platformPath = paths.New(args[0])
+	}
+	platformPath, err := platformPath.Abs()
+	if err != nil {
+		feedback.Errorf("Invalid platform path: %v", err)
+		os.Exit(errorcodes.ErrBadArgument)
+	}
+
+	var indexed *cores.PlatformRelease
+	if lintFlags.packageIndex != "" {
+		indexed = findIndexedPlatform(platformPath)
+	}
+	diagnostics, err := lint.Platform(platformPath, indexed)
+	if err != nil {
+		feedback.Errorf("Error checking platform: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+
+	feedback.PrintResult(&lintResult{Diagnostics: diagnostics, platformPath: platformPath})
+	for _, d := range diagnostics {
+		if d.Severity == check.SeverityError || lintFlags.failOnWarning {
+			os.Exit(errorcodes.ErrGeneric)
+		}
+	}
+}
+
+// findIndexedPlatform returns the release of the platform defined in the
+// package index with the version in platform.txt, or the latest release
+func findIndexedPlatform(platformPath *paths.Path) *cores.PlatformRelease {
+	index, err := packageindex.LoadIndexNoSign(paths.New(lintFlags.packageIndex))
+	if err != nil {
+		feedback.Errorf("Error loading package index: %v", err)
+		os.Exit(errorcodes.ErrBadArgument)
+	}
+	packages := cores.NewPackages()
+	index.MergeIntoPackages(packages)
+
+	packager, arch := platformPath.Parent().Base(), platformPath.Base()
+	if lintFlags.platform != "" {
+		split := strings.Split(lintFlags.platform, ":")
+		if len(split) != 2 {
+			feedback.Errorf("Invalid platform %s, expected PACKAGER:ARCH", lintFlags.platform)
+			os.Exit(errorcodes.ErrBadArgument)
+		}
+		packager, arch = split[0], split[1]
+	}
+
+	var platform *cores.Platform
+	if targetPackage, ok := packages[packager]; ok {
+		platform = targetPackage.Platforms[arch]
+	}
+	if platform == nil {
+		feedback.Errorf("Platform %s:%s not found in the package index, use --platform to select it", packager, arch)
+		os.Exit(errorcodes.ErrBadArgument)
+	}
+	if props, err := properties.LoadFromPath(platformPath.Join("platform.txt")); err == nil {
+		if version, err := semver.Parse(props.Get("version")); err == nil {
+			if release := platform.FindReleaseWithVersion(version); release != nil {
+				return release
+			}
+		}
+	}
+	release := platform.GetLatestRelease()
+	if release == nil {
+		feedback.Errorf("Platform %s has no releases in the package index", platform)
+		os.Exit(errorcodes.ErrBadArgument)
+	}
+	logrus.Infof("Checking the tools against %s", release)
+	return release
+}
+
+type lintResult struct {
+	Diagnostics []*check.Diagnostic `json:"diagnostics"`
+
+	platformPath *paths.Path
+}
+
+func (r *lintResult) Data() interface{} {
+	return r
+}
+
+func (r *lintResult) Annotations() []*feedback.Annotation {
+	res := []*feedback.Annotation{}
+	for _, d := range r.Diagnostics {
+		file := d.File
+		if file != "" {
+			file = r.platformPath.Join(file).String()
+		}
+		res = append(res, &feedback.Annotation{
+			Severity: string(d.Severity),
+			File:     file,
+			Line:     d.Line,
+			Title:    d.Rule,
+			Message:  d.Message,
+		})
+	}
+	return res
+}
+
+func (r *lintResult) String() string {
+	if len(r.Diagnostics) == 0 {
+		return "No problems found."
+	}
+	lines := []string{}
+	for _, d := range r.Diagnostics {
+		lines = append(lines, d.String())
+	}
+	return strings.Join(lines, "\n")
+}
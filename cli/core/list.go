@@ -22,6 +22,7 @@ import (
 	"github.com/arduino/arduino-cli/cli/errorcodes"
 	"github.com/arduino/arduino-cli/cli/feedback"
 	"github.com/arduino/arduino-cli/cli/instance"
+	"github.com/arduino/arduino-cli/cli/output"
 	"github.com/arduino/arduino-cli/commands/core"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
 	"github.com/arduino/arduino-cli/table"
@@ -40,6 +41,7 @@ func initListCommand() *cobra.Command {
 	}
 	listCommand.Flags().BoolVar(&listFlags.updatableOnly, "updatable", false, "List updatable platforms.")
 	listCommand.Flags().BoolVar(&listFlags.all, "all", false, "If set return all installable and installed cores, including manually installed.")
+	output.AddTableFlagsToCommand(listCommand, platformColumns...)
 	return listCommand
 }
 
@@ -92,3 +94,22 @@ func (ir installedResult) String() string {
 
 	return t.Render()
 }
+
+// Table returns all the data of the platforms, to print the columns selected
+// with --columns
+func (ir installedResult) Table() *feedback.Table {
+	return platformsTable(ir.platforms)
+}
+
+// platformColumns are the columns of the platforms tables
+var platformColumns = []string{"id", "installed", "latest", "name", "maintainer", "website", "email",
+	"manually_installed", "deprecated"}
+
+func platformsTable(platforms []*rpc.Platform) *feedback.Table {
+	t := &feedback.Table{Columns: platformColumns}
+	for _, p := range platforms {
+		t.AddRow(p.GetId(), p.GetInstalled(), p.GetLatest(), p.GetName(), p.GetMaintainer(), p.GetWebsite(),
+			p.GetEmail(), fmt.Sprint(p.GetManuallyInstalled()), fmt.Sprint(p.GetDeprecated()))
+	}
+	return t
+}
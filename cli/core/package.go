@@ -0,0 +1,172 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/arduino/cores/packageindex"
+	"github.com/arduino/arduino-cli/arduino/cores/publish"
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/go-paths-helper"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var packageFlags struct {
+	packager     string
+	architecture string
+	url          string
+	category     string
+	tools        []string
+	exclude      []string
+	outputDir    string
+	index        string
+}
+
+func initPackageCommand() *cobra.Command {
+	packageCommand := &cobra.Command{
+		Use:   "package [<platformPath>]",
+		Short: "Creates the release archive of a platform and its package index entry.",
+		Long: "Creates the release archive of a platform, computes its size and checksum and prints the entry to add to the platforms " +
+			"of a package index. With --index the entry is added to the given package index file, replacing the release with the same version. " +
+			"Hidden files and folders, platform.local.txt and boards.local.txt are not included in the archive.",
+		Example: "" +
+			"  " + os.Args[0] + " core package ~/Arduino/hardware/mycompany/avr --url https://example.com/releases/ --tool arduino:avr-gcc@7.3.0-atmel3.6.1-arduino7\n" +
+			"  " + os.Args[0] + " core package . --packager mycompany --architecture avr --url https://example.com/releases/ --index package_mycompany_index.json",
+		Args: cobra.MaximumNArgs(1),
+		Run:  runPackageCommand,
+	}
+	packageCommand.Flags().StringVar(&packageFlags.packager, "packager", "", "The packager of the platform, defaults to the name of the parent of the platform folder.")
+	packageCommand.Flags().StringVar(&packageFlags.architecture, "architecture", "", "The architecture of the platform, defaults to the name of the platform folder.")
+	packageCommand.Flags().StringVar(&packageFlags.url, "url", "", "The URL of the archive, the name of the archive is added if it ends with a slash.")
+	packageCommand.Flags().StringVar(&packageFlags.category, "category", "Contributed", "The category of the platform.")
+	packageCommand.Flags().StringSliceVar(&packageFlags.tools, "tool", []string{}, "A tool dependency as PACKAGER:NAME@VERSION, can be used multiple times. Defaults to the dependencies of the latest release in the --index file.")
+	packageCommand.Flags().StringSliceVar(&packageFlags.exclude, "exclude", []string{}, "Exclude the files matching the pattern from the archive, can be used multiple times.")
+	packageCommand.Flags().StringVar(&packageFlags.outputDir, "output-dir", "", "The folder where the archive is created, defaults to the current folder.")
+	packageCommand.Flags().StringVar(&packageFlags.index, "index", "", "Add the release to the given package index file.")
+	return packageCommand
+}
+
+func runPackageCommand(cmd *cobra.Command, args []string) {
+	logrus.Info("Executing `arduino core package`")
+
+	platformPath := paths.New(".")
+	if len(args) == 1 {
+		platformPath = paths.New(args[0])
+	}
+	platformPath, err := platformPath.Abs()
+	if err != nil {
+		feedback.Errorf("Invalid platform path: %v", err)
+		os.Exit(errorcodes.ErrBadArgument)
+	}
+	opts := &publish.Options{
+		Packager:     packageFlags.packager,
+		Architecture: packageFlags.architecture,
+		URL:          packageFlags.url,
+		Category:     packageFlags.category,
+		Exclude:      packageFlags.exclude,
+	}
+	if opts.Packager == "" {
+		opts.Packager = platformPath.Parent().Base()
+	}
+	if opts.Architecture == "" {
+		opts.Architecture = platformPath.Base()
+	}
+	for _, arg := range packageFlags.tools {
+		dep, err := publish.ParseToolDependency(arg)
+		if err != nil {
+			feedback.Errorf("Invalid argument passed: %v", err)
+			os.Exit(errorcodes.ErrBadArgument)
+		}
+		opts.Dependencies = append(opts.Dependencies, dep)
+	}
+	var indexFile *paths.Path
+	if packageFlags.index != "" {
+		indexFile = paths.New(packageFlags.index)
+		if opts.Dependencies == nil && indexFile.Exist() {
+			opts.Dependencies = indexedDependencies(indexFile, opts.Packager, opts.Architecture)
+		}
+	}
+
+	outputDir := paths.New(".")
+	if packageFlags.outputDir != "" {
+		outputDir = paths.New(packageFlags.outputDir)
+	}
+	release, err := publish.Package(platformPath, outputDir, opts)
+	if err != nil {
+		feedback.Errorf("Error creating the platform archive: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+	entry, err := packageindex.MarshalPlatformRelease(release)
+	if err != nil {
+		feedback.Errorf("Error creating the package index entry: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+	res := &packageResult{Archive: outputDir.Join(release.Resource.ArchiveFileName).String(), Entry: entry}
+	if indexFile != nil {
+		if err := packageindex.AddPlatformRelease(indexFile, release); err != nil {
+			feedback.Errorf("Error updating the package index: %v", err)
+			os.Exit(errorcodes.ErrGeneric)
+		}
+		res.Index = indexFile.String()
+	}
+	feedback.PrintResult(res)
+}
+
+// indexedDependencies returns the tool dependencies of the latest release of
+// the platform in the given package index
+func indexedDependencies(indexFile *paths.Path, packager, architecture string) cores.ToolDependencies {
+	index, err := packageindex.LoadIndexNoSign(indexFile)
+	if err != nil {
+		feedback.Errorf("Error loading package index: %v", err)
+		os.Exit(errorcodes.ErrBadArgument)
+	}
+	packages := cores.NewPackages()
+	index.MergeIntoPackages(packages)
+	if targetPackage, ok := packages[packager]; ok {
+		if platform, ok := targetPackage.Platforms[architecture]; ok {
+			if latest := platform.GetLatestRelease(); latest != nil {
+				logrus.Infof("Using the tool dependencies of %s", latest)
+				return latest.Dependencies
+			}
+		}
+	}
+	return nil
+}
+
+type packageResult struct {
+	Archive string          `json:"archive"`
+	Entry   json.RawMessage `json:"entry"`
+	Index   string          `json:"index,omitempty"`
+}
+
+func (r *packageResult) Data() interface{} {
+	return r
+}
+
+func (r *packageResult) String() string {
+	res := fmt.Sprintf("Platform archive created: %s\n\n%s", r.Archive, strings.TrimSpace(string(r.Entry)))
+	if r.Index != "" {
+		res += "\nRelease added to " + r.Index
+	}
+	return res
+}
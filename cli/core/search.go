@@ -16,25 +16,17 @@
 package core
 
 import (
-	"context"
 	"fmt"
 	"os"
-	"path"
 	"strings"
-	"time"
 
-	"github.com/arduino/arduino-cli/arduino/utils"
 	"github.com/arduino/arduino-cli/cli/errorcodes"
 	"github.com/arduino/arduino-cli/cli/feedback"
-	"github.com/arduino/arduino-cli/cli/globals"
 	"github.com/arduino/arduino-cli/cli/instance"
 	"github.com/arduino/arduino-cli/cli/output"
-	"github.com/arduino/arduino-cli/commands"
 	"github.com/arduino/arduino-cli/commands/core"
-	"github.com/arduino/arduino-cli/configuration"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
 	"github.com/arduino/arduino-cli/table"
-	"github.com/arduino/go-paths-helper"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -53,13 +45,12 @@ func initSearchCommand() *cobra.Command {
 		Run:     runSearchCommand,
 	}
 	searchCommand.Flags().BoolVarP(&allVersions, "all", "a", false, "Show all available core versions.")
+	instance.AddIndexUpdateFlagsToCommand(searchCommand)
+	output.AddTableFlagsToCommand(searchCommand, platformColumns...)
 
 	return searchCommand
 }
 
-// indexUpdateInterval specifies the time threshold over which indexes are updated
-const indexUpdateInterval = "24h"
-
 func runSearchCommand(cmd *cobra.Command, args []string) {
 	inst, status := instance.Create()
 	if status != nil {
@@ -67,15 +58,7 @@ func runSearchCommand(cmd *cobra.Command, args []string) {
 		os.Exit(errorcodes.ErrGeneric)
 	}
 
-	if indexesNeedUpdating(indexUpdateInterval) {
-		_, err := commands.UpdateIndex(context.Background(), &rpc.UpdateIndexRequest{
-			Instance: inst,
-		}, output.ProgressBar())
-		if err != nil {
-			feedback.Errorf("Error updating index: %v", err)
-			os.Exit(errorcodes.ErrGeneric)
-		}
-	}
+	instance.UpdateIndexesIfNeeded(inst, true, false)
 
 	for _, err := range instance.Init(inst) {
 		feedback.Errorf("Error initializing instance: %v", err)
@@ -124,48 +107,8 @@ func (sr searchResults) String() string {
 	return "No platforms matching your search."
 }
 
-// indexesNeedUpdating returns whether one or more index files need updating.
-// A duration string must be provided to calculate the time threshold
-// used to update the indexes, if the duration is not valid a default
-// of 24 hours is used.
-// Valid time units are "ns", "us" (or "µs"), "ms", "s", "m", "h".
-func indexesNeedUpdating(duration string) bool {
-	indexpath := paths.New(configuration.Settings.GetString("directories.Data"))
-
-	now := time.Now()
-	modTimeThreshold, err := time.ParseDuration(duration)
-	// Not the most elegant way of handling this error
-	// but it does its job
-	if err != nil {
-		modTimeThreshold, _ = time.ParseDuration("24h")
-	}
-
-	urls := []string{globals.DefaultIndexURL}
-	urls = append(urls, configuration.Settings.GetStringSlice("board_manager.additional_urls")...)
-	for _, u := range urls {
-		URL, err := utils.URLParse(u)
-		if err != nil {
-			continue
-		}
-
-		if URL.Scheme == "file" {
-			// No need to update local files
-			continue
-		}
-
-		coreIndexPath := indexpath.Join(path.Base(URL.Path))
-		if coreIndexPath.NotExist() {
-			return true
-		}
-
-		info, err := coreIndexPath.Stat()
-		if err != nil {
-			return true
-		}
-
-		if now.After(info.ModTime().Add(modTimeThreshold)) {
-			return true
-		}
-	}
-	return false
+// Table returns all the data of the platforms, to print the columns selected
+// with --columns
+func (sr searchResults) Table() *feedback.Table {
+	return platformsTable(sr.platforms)
 }
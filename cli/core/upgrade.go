@@ -24,6 +24,7 @@ import (
 	"github.com/arduino/arduino-cli/cli/globals"
 	"github.com/arduino/arduino-cli/cli/instance"
 	"github.com/arduino/arduino-cli/cli/output"
+	"github.com/arduino/arduino-cli/cli/tool"
 	"github.com/arduino/arduino-cli/commands/core"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
 	"github.com/sirupsen/logrus"
@@ -43,9 +44,14 @@ func initUpgradeCommand() *cobra.Command {
 		Run: runUpgradeCommand,
 	}
 	AddPostInstallFlagsToCommand(upgradeCommand)
+	upgradeCommand.Flags().BoolVar(&upgradeFlags.gc, "gc", false, "Remove the tools not required anymore by any installed platform after the upgrade.")
 	return upgradeCommand
 }
 
+var upgradeFlags struct {
+	gc bool
+}
+
 func runUpgradeCommand(cmd *cobra.Command, args []string) {
 	inst := instance.CreateAndInit()
 	logrus.Info("Executing `arduino core upgrade`")
@@ -63,6 +69,9 @@ func runUpgradeCommand(cmd *cobra.Command, args []string) {
 
 		if len(targets) == 0 {
 			feedback.Print("All the cores are already at the latest version")
+			if upgradeFlags.gc {
+				tool.GarbageCollect(inst, false)
+			}
 			return
 		}
 
@@ -105,4 +114,8 @@ func runUpgradeCommand(cmd *cobra.Command, args []string) {
 	if exitErr {
 		os.Exit(errorcodes.ErrBadArgument)
 	}
+
+	if upgradeFlags.gc {
+		tool.GarbageCollect(inst, false)
+	}
 }
@@ -53,6 +53,8 @@ func NewCommand() *cobra.Command {
 	cmd.PersistentFlags().String("port", "", "The TCP port the daemon will listen to")
 	configuration.Settings.BindPFlag("daemon.port", cmd.PersistentFlags().Lookup("port"))
 	cmd.Flags().BoolVar(&daemonize, "daemonize", false, "Do not terminate daemon process if the parent process dies")
+	cmd.Flags().String("metrics-addr", "", "The address where Prometheus metrics are exposed, it enables metrics if set")
+	configuration.Settings.BindPFlag("metrics.addr", cmd.Flags().Lookup("metrics-addr"))
 	return cmd
 }
 
@@ -60,13 +62,20 @@ var daemonize bool
 
 func runDaemonCommand(cmd *cobra.Command, args []string) {
 
+	if cmd.Flags().Changed("metrics-addr") {
+		configuration.Settings.Set("metrics.enabled", true)
+	}
+	serverOpts := []grpc.ServerOption{}
 	if configuration.Settings.GetBool("metrics.enabled") {
-		metrics.Activate("daemon")
+		metrics.Activate("daemon", configuration.Settings)
 		stats.Incr("daemon", stats.T("success", "true"))
 		defer stats.Flush()
+		serverOpts = append(serverOpts,
+			grpc.ChainUnaryInterceptor(metrics.UnaryServerInterceptor),
+			grpc.ChainStreamInterceptor(metrics.StreamServerInterceptor))
 	}
 	port := configuration.Settings.GetString("daemon.port")
-	s := grpc.NewServer()
+	s := grpc.NewServer(serverOpts...)
 
 	// Set specific user-agent for the daemon
 	configuration.Settings.Set("network.user_agent_ext", "daemon")
@@ -45,6 +45,7 @@ var (
 	importDir   string
 	printInfo   bool
 	programmer  string
+	svdFile     string
 )
 
 // NewCommand created a new `upload` command
@@ -64,6 +65,9 @@ func NewCommand() *cobra.Command {
 	debugCommand.Flags().StringVar(&interpreter, "interpreter", "console", "Debug interpreter e.g.: console, mi, mi1, mi2, mi3")
 	debugCommand.Flags().StringVarP(&importDir, "input-dir", "", "", "Directory containing binaries for debug.")
 	debugCommand.Flags().BoolVarP(&printInfo, "info", "I", false, "Show metadata about the debug session instead of starting the debugger.")
+	debugCommand.Flags().StringVar(&svdFile, "svd", "", "Path or URL of the CMSIS-SVD file describing the registers of the board.")
+
+	debugCommand.AddCommand(initRegistersCommand())
 
 	return debugCommand
 }
@@ -85,6 +89,7 @@ func run(command *cobra.Command, args []string) {
 		Interpreter: interpreter,
 		ImportDir:   importDir,
 		Programmer:  programmer,
+		Svd:         svdFile,
 	}
 
 	if printInfo {
@@ -106,7 +111,7 @@ func run(command *cobra.Command, args []string) {
 		ctrlc := make(chan os.Signal, 1)
 		signal.Notify(ctrlc, os.Interrupt)
 
-		if _, err := debug.Debug(context.Background(), debugConfigRequested, os.Stdin, os.Stdout, ctrlc); err != nil {
+		if _, err := debug.Debug(context.Background(), debugConfigRequested, os.Stdin, os.Stdout, os.Stderr, ctrlc); err != nil {
 			feedback.Errorf("Error during Debug: %v", err)
 			os.Exit(errorcodes.ErrGeneric)
 		}
@@ -165,5 +170,8 @@ func (r *debugInfoResult) String() string {
 			t.AddRow(table.NewCell(" - "+k, dimGreen), table.NewCell(conf.Get(k), dimGreen))
 		}
 	}
+	if svd := r.info.GetSvdFile(); svd != "" {
+		t.AddRow("SVD file", table.NewCell(svd, dimGreen))
+	}
 	return t.Render()
 }
@@ -0,0 +1,147 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package debug
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/arduino-cli/cli/instance"
+	"github.com/arduino/arduino-cli/commands/debug"
+	dbg "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/debug/v1"
+	"github.com/arduino/arduino-cli/table"
+	"github.com/arduino/go-paths-helper"
+	"github.com/fatih/color"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var registersFlags struct {
+	sketchPath string
+	listOnly   bool
+}
+
+func initRegistersCommand() *cobra.Command {
+	registersCommand := &cobra.Command{
+		Use:   "registers [<PERIPHERAL>[.<REGISTER>]...]",
+		Short: "Reads the peripheral registers of the board.",
+		Long: "Reads the peripheral registers of the board and decodes their fields, using the CMSIS-SVD file provided by the platform or specified with --svd. " +
+			"Registers are selected by peripheral name or in the form PERIPHERAL.REGISTER, shell wildcards are allowed.",
+		Example: "" +
+			"  " + os.Args[0] + " debug registers -b arduino:samd:mkr1000 -P atmel_ice --sketch /home/user/Arduino/MySketch PORT TC3.COUNT16.CTRLA\n" +
+			"  " + os.Args[0] + " debug registers -b arduino:samd:mkr1000 --svd ATSAMD21G18A.svd --list 'TC*'",
+		Run: runRegistersCommand,
+	}
+	registersCommand.Flags().StringVarP(&fqbn, "fqbn", "b", "", "Fully Qualified Board Name, e.g.: arduino:avr:uno")
+	registersCommand.Flags().StringVarP(&port, "port", "p", "", "Debug port, e.g.: COM10 or /dev/ttyACM0")
+	registersCommand.Flags().StringVarP(&programmer, "programmer", "P", "", "Programmer to use for debugging")
+	registersCommand.Flags().StringVarP(&importDir, "input-dir", "", "", "Directory containing binaries for debug.")
+	registersCommand.Flags().StringVar(&svdFile, "svd", "", "Path or URL of the CMSIS-SVD file describing the registers of the board.")
+	registersCommand.Flags().StringVar(&registersFlags.sketchPath, "sketch", "", "Path of the sketch running on the board (default: current directory).")
+	registersCommand.Flags().BoolVar(&registersFlags.listOnly, "list", false, "List the registers without reading them from the board.")
+	return registersCommand
+}
+
+func runRegistersCommand(command *cobra.Command, args []string) {
+	instance := instance.CreateAndInit()
+	logrus.Info("Executing `arduino debug registers`")
+
+	var path *paths.Path
+	if registersFlags.sketchPath != "" {
+		path = paths.New(registersFlags.sketchPath)
+	}
+	sketchPath := initSketchPath(path)
+
+	req := &dbg.ReadRegistersRequest{
+		DebugRequest: &dbg.DebugConfigRequest{
+			Instance:   instance,
+			Fqbn:       fqbn,
+			SketchPath: sketchPath.String(),
+			Port:       port,
+			ImportDir:  importDir,
+			Programmer: programmer,
+			Svd:        svdFile,
+		},
+		Registers: args,
+		ListOnly:  registersFlags.listOnly,
+	}
+	res, err := debug.ReadRegisters(context.Background(), req, os.Stderr)
+	if err != nil {
+		feedback.Errorf("Error reading registers: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+	feedback.PrintResult(&registersResult{res})
+}
+
+type registersResult struct {
+	res *dbg.ReadRegistersResponse
+}
+
+func (r *registersResult) Data() interface{} {
+	return r.res
+}
+
+func (r *registersResult) String() string {
+	t := table.New()
+	green := color.New(color.FgHiGreen)
+	dimGreen := color.New(color.FgGreen)
+	t.SetHeader("Register", "Address", "Value", "Description")
+	for _, reg := range r.res.GetRegisters() {
+		value := ""
+		if reg.GetRead() {
+			value = formatValue(reg.GetValue(), uint(reg.GetSize()))
+		} else if reg.GetError() != "" {
+			value = "(" + reg.GetError() + ")"
+		}
+		t.AddRow(
+			table.NewCell(reg.GetPeripheral()+"."+reg.GetName(), green),
+			fmt.Sprintf("0x%08x", reg.GetAddress()),
+			table.NewCell(value, green),
+			reg.GetDescription())
+		for _, field := range reg.GetFields() {
+			bits := fmt.Sprintf("[%d]", field.GetBitOffset())
+			if field.GetBitWidth() > 1 {
+				bits = fmt.Sprintf("[%d:%d]", field.GetBitOffset()+field.GetBitWidth()-1, field.GetBitOffset())
+			}
+			value := ""
+			if reg.GetRead() {
+				value = formatValue(field.GetValue(), uint(field.GetBitWidth()))
+				if enum := field.GetEnumeratedValue(); enum != "" {
+					value += " " + enum
+				}
+			}
+			t.AddRow(
+				table.NewCell("  "+field.GetName(), dimGreen),
+				bits,
+				table.NewCell(value, dimGreen),
+				field.GetDescription())
+		}
+	}
+	return t.Render()
+}
+
+// formatValue prints value in hexadecimal, padded to the given bits width
+func formatValue(value uint64, bits uint) string {
+	if bits == 1 {
+		return fmt.Sprint(value)
+	}
+	digits := int(bits+3) / 4
+	return "0x" + strings.ToLower(fmt.Sprintf("%0*X", digits, value))
+}
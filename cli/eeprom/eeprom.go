@@ -0,0 +1,56 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package eeprom
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// NewCommand created a new `eeprom` command
+func NewCommand() *cobra.Command {
+	eepromCommand := &cobra.Command{
+		Use:   "eeprom",
+		Short: "Reads and writes the EEPROM of the board.",
+		Long:  "Reads and writes the EEPROM of the board with the upload tool or with an external programmer.",
+		Example: "" +
+			"  " + os.Args[0] + " eeprom read -b arduino:avr:uno -p /dev/ttyACM0 --output eeprom.bin\n" +
+			"  " + os.Args[0] + " eeprom write -b arduino:avr:uno -P usbasp --input eeprom.bin",
+	}
+
+	eepromCommand.AddCommand(initReadCommand())
+	eepromCommand.AddCommand(initWriteCommand())
+
+	return eepromCommand
+}
+
+// memoryFlags are the flags selecting the board and the tool used to access
+// the EEPROM
+type memoryFlags struct {
+	fqbn       string
+	port       string
+	programmer string
+	verbose    bool
+}
+
+func (f *memoryFlags) add(command *cobra.Command) {
+	command.Flags().StringVarP(&f.fqbn, "fqbn", "b", "", "Fully Qualified Board Name, e.g.: arduino:avr:uno")
+	command.Flags().StringVarP(&f.port, "port", "p", "", "Port of the board or of the programmer, e.g.: COM10 or /dev/ttyACM0")
+	command.Flags().StringVarP(&f.programmer, "programmer", "P", "", "Optional, use the specified programmer to access the EEPROM.")
+	command.Flags().BoolVarP(&f.verbose, "verbose", "v", false, "Optional, turns on verbose mode.")
+	command.MarkFlagRequired("fqbn")
+}
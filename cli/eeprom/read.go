@@ -0,0 +1,86 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package eeprom
+
+import (
+	"context"
+	"os"
+
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/arduino-cli/cli/instance"
+	"github.com/arduino/arduino-cli/cli/output"
+	"github.com/arduino/arduino-cli/commands/upload"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/spf13/cobra"
+)
+
+var readFlags struct {
+	memoryFlags
+	output string
+}
+
+func initReadCommand() *cobra.Command {
+	readCommand := &cobra.Command{
+		Use:     "read",
+		Short:   "Saves the content of the EEPROM into a file.",
+		Long:    "Saves the content of the EEPROM of the board into a file.",
+		Example: "  " + os.Args[0] + " eeprom read -b arduino:avr:uno -p /dev/ttyACM0 --output eeprom.bin",
+		Args:    cobra.NoArgs,
+		Run:     runReadCommand,
+	}
+	readFlags.add(readCommand)
+	readCommand.Flags().StringVarP(&readFlags.output, "output", "o", "", "The file where the content of the EEPROM is saved.")
+	readCommand.MarkFlagRequired("output")
+	return readCommand
+}
+
+func runReadCommand(cmd *cobra.Command, args []string) {
+	instance := instance.CreateAndInit()
+
+	toolOutput := output.NewToolOutput()
+	res, err := upload.MemoryRead(context.Background(), &rpc.MemoryReadRequest{
+		Instance:   instance,
+		Fqbn:       readFlags.fqbn,
+		Port:       readFlags.port,
+		Programmer: readFlags.programmer,
+		Verbose:    readFlags.verbose,
+		Memory:     "eeprom",
+		OutputPath: readFlags.output,
+	}, toolOutput.Out, toolOutput.Err)
+	toolOutput.Flush()
+	if output.OutputFormat == "json" {
+		toolOut, toolErr := toolOutput.Buffered()
+		result := &output.MemoryResult{
+			Event:   "result",
+			ToolOut: toolOut,
+			ToolErr: toolErr,
+			Path:    readFlags.output,
+			Size:    res.GetSize(),
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		feedback.PrintEvent(result)
+	}
+	if err != nil {
+		feedback.Errorf("Error reading the EEPROM: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+	if output.OutputFormat != "json" {
+		feedback.Printf("Saved %s of EEPROM to %s", output.FormatSize(int64(res.GetSize())), readFlags.output)
+	}
+}
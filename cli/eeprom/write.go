@@ -0,0 +1,85 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package eeprom
+
+import (
+	"context"
+	"os"
+
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/arduino-cli/cli/instance"
+	"github.com/arduino/arduino-cli/cli/output"
+	"github.com/arduino/arduino-cli/commands/upload"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/spf13/cobra"
+)
+
+var writeFlags struct {
+	memoryFlags
+	input string
+}
+
+func initWriteCommand() *cobra.Command {
+	writeCommand := &cobra.Command{
+		Use:     "write",
+		Short:   "Writes the content of a file into the EEPROM.",
+		Long:    "Writes the content of a file into the EEPROM of the board, e.g. to provision the devices in a manufacturing run.",
+		Example: "  " + os.Args[0] + " eeprom write -b arduino:avr:uno -P usbasp --input eeprom.bin",
+		Args:    cobra.NoArgs,
+		Run:     runWriteCommand,
+	}
+	writeFlags.add(writeCommand)
+	writeCommand.Flags().StringVarP(&writeFlags.input, "input", "i", "", "The file with the content to write.")
+	writeCommand.MarkFlagRequired("input")
+	return writeCommand
+}
+
+func runWriteCommand(cmd *cobra.Command, args []string) {
+	instance := instance.CreateAndInit()
+
+	toolOutput := output.NewToolOutput()
+	_, err := upload.MemoryWrite(context.Background(), &rpc.MemoryWriteRequest{
+		Instance:   instance,
+		Fqbn:       writeFlags.fqbn,
+		Port:       writeFlags.port,
+		Programmer: writeFlags.programmer,
+		Verbose:    writeFlags.verbose,
+		Memory:     "eeprom",
+		InputPath:  writeFlags.input,
+	}, toolOutput.Out, toolOutput.Err)
+	toolOutput.Flush()
+	if output.OutputFormat == "json" {
+		toolOut, toolErr := toolOutput.Buffered()
+		result := &output.MemoryResult{
+			Event:   "result",
+			ToolOut: toolOut,
+			ToolErr: toolErr,
+			Path:    writeFlags.input,
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		feedback.PrintEvent(result)
+	}
+	if err != nil {
+		feedback.Errorf("Error writing the EEPROM: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+	if output.OutputFormat != "json" {
+		feedback.Printf("Written %s to the EEPROM", writeFlags.input)
+	}
+}
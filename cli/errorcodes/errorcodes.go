@@ -35,6 +35,12 @@ const (
 	// directories vital for the CLI to work.
 	ErrCoreConfig
 	ErrBadArgument
+	// ErrSizeLimit is returned when the compiled sketch uses more memory than
+	// available on the board or allowed by the user.
+	ErrSizeLimit
+	// ErrTestFailed is returned when the checks run on the board, e.g. by a
+	// monitor script, or the unit tests fail.
+	ErrTestFailed
 )
 
 // ExitWithGrpcStatus will terminate the current process by returing the correct
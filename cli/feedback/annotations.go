@@ -0,0 +1,98 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package feedback
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Annotation is a problem found on a line of a file, e.g. a compiler warning
+type Annotation struct {
+	// Severity is error, warning or notice
+	Severity string
+	File     string
+	Line     int
+	Column   int
+	Title    string
+	Message  string
+}
+
+// AnnotatedResult is a Result reporting problems found in files. With the
+// GitHubAnnotations format the problems are also printed as workflow commands,
+// so they are shown inline in the pull requests.
+type AnnotatedResult interface {
+	Result
+	Annotations() []*Annotation
+}
+
+// annotationSeverity maps the compiler severities to the GitHub Actions
+// workflow commands
+func annotationSeverity(severity string) string {
+	switch strings.ToLower(severity) {
+	case "error", "fatal error":
+		return "error"
+	case "warning":
+		return "warning"
+	default:
+		return "notice"
+	}
+}
+
+var annotationDataEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+var annotationPropertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+
+// FormatAnnotation returns the GitHub Actions workflow command that creates
+// the annotation, e.g.:
+//
+//	::warning file=Blink/Blink.ino,line=3,col=7::unused variable 'x'
+//
+// The file path is made relative to the workspace of the workflow
+// (GITHUB_WORKSPACE), as required to annotate the files of the repository.
+func FormatAnnotation(a *Annotation) string {
+	properties := []string{}
+	if a.File != "" {
+		file := a.File
+		if workspace := os.Getenv("GITHUB_WORKSPACE"); workspace != "" {
+			if rel, err := filepath.Rel(workspace, file); err == nil && !strings.HasPrefix(rel, "..") {
+				file = rel
+			}
+		}
+		properties = append(properties, "file="+annotationPropertyEscaper.Replace(filepath.ToSlash(file)))
+		if a.Line > 0 {
+			properties = append(properties, fmt.Sprintf("line=%d", a.Line))
+		}
+		if a.Column > 0 {
+			properties = append(properties, fmt.Sprintf("col=%d", a.Column))
+		}
+	}
+	if a.Title != "" {
+		properties = append(properties, "title="+annotationPropertyEscaper.Replace(a.Title))
+	}
+	command := "::" + annotationSeverity(a.Severity)
+	if len(properties) > 0 {
+		command += " " + strings.Join(properties, ",")
+	}
+	return command + "::" + annotationDataEscaper.Replace(a.Message)
+}
+
+func (fb *Feedback) printAnnotations(res AnnotatedResult) {
+	for _, a := range res.Annotations() {
+		fmt.Fprintln(fb.out, FormatAnnotation(a))
+	}
+}
@@ -0,0 +1,74 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package feedback
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type annotatedResult struct {
+	annotations []*Annotation
+}
+
+func (r *annotatedResult) Data() interface{} {
+	return r
+}
+
+func (r *annotatedResult) String() string {
+	return "1 warning"
+}
+
+func (r *annotatedResult) Annotations() []*Annotation {
+	return r.annotations
+}
+
+func TestFormatAnnotation(t *testing.T) {
+	defer os.Setenv("GITHUB_WORKSPACE", os.Getenv("GITHUB_WORKSPACE"))
+	os.Setenv("GITHUB_WORKSPACE", "/home/runner/work/repo")
+
+	require.Equal(t, "::warning file=Blink/Blink.ino,line=3,col=7::unused variable 'x'", FormatAnnotation(&Annotation{
+		Severity: "warning",
+		File:     "/home/runner/work/repo/Blink/Blink.ino",
+		Line:     3,
+		Column:   7,
+		Message:  "unused variable 'x'",
+	}))
+	require.Equal(t, "::error file=/tmp/lib.cpp,title=arduino%3Aavr%3Auno::expected ';'%0A  x = 1", FormatAnnotation(&Annotation{
+		Severity: "fatal error",
+		File:     "/tmp/lib.cpp",
+		Title:    "arduino:avr:uno",
+		Message:  "expected ';'\n  x = 1",
+	}))
+	require.Equal(t, "::notice::100%25 done", FormatAnnotation(&Annotation{Severity: "note", Message: "100% done"}))
+}
+
+func TestPrintResultAnnotations(t *testing.T) {
+	res := &annotatedResult{annotations: []*Annotation{{Severity: "warning", File: "a.ino", Line: 1, Message: "unused"}}}
+
+	var out bytes.Buffer
+	fb := New(&out, &out, GitHubAnnotations)
+	fb.PrintResult(res)
+	require.Equal(t, "1 warning\n::warning file=a.ino,line=1::unused\n", out.String())
+
+	out.Reset()
+	fb.SetFormat(Text)
+	fb.PrintResult(res)
+	require.Equal(t, "1 warning\n", out.String())
+}
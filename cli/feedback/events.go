@@ -0,0 +1,98 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package feedback
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// outputEvent is the payload of the "output" events, one for each line
+// printed by the tools run by a command
+type outputEvent struct {
+	Stream string `json:"stream"`
+	Text   string `json:"text"`
+}
+
+// PrintTypedEvent prints the progress of a long running command, as a single
+// line {"type": eventType, eventType: data} event. The event is printed only
+// in NDJSON format, in the other formats the progress is shown by the
+// progress bars of the output package.
+func (fb *Feedback) PrintTypedEvent(eventType string, data interface{}) {
+	if fb.format == NDJSON {
+		fb.printTypedEvent(eventType, data)
+	}
+}
+
+// printTypedEvent prints data as a single line JSON event of the given type.
+// The events may be printed from many goroutines, so the writes are
+// serialized to never mix two events on the same line.
+func (fb *Feedback) printTypedEvent(eventType string, data interface{}) {
+	d, err := json.Marshal(data)
+	if err != nil {
+		eventType = "error"
+		d, _ = json.Marshal(fmt.Sprintf("Error during JSON encoding of the output: %v", err))
+	}
+	// the type is printed first, so that the events are easier to read
+	fb.eventsLock.Lock()
+	defer fb.eventsLock.Unlock()
+	fmt.Fprintf(fb.out, "{\"type\":%q,%q:%s}\n", eventType, eventType, d)
+}
+
+// EventWriter is an io.Writer that prints each line written to it as an
+// "output" event. It's used in NDJSON format in place of the stdout and
+// stderr of the tools run by a command.
+type EventWriter struct {
+	fb     *Feedback
+	stream string
+	buf    bytes.Buffer
+	lock   sync.Mutex
+}
+
+// NewEventWriter returns an EventWriter printing the lines of the given
+// stream, usually "stdout" or "stderr"
+func (fb *Feedback) NewEventWriter(stream string) *EventWriter {
+	return &EventWriter{fb: fb, stream: stream}
+}
+
+// Write prints an event for each complete line of p, the last partial line
+// is kept until the next Write or Flush
+func (w *EventWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.buf.Write(p)
+	for {
+		i := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if i < 0 {
+			break
+		}
+		line := string(bytes.TrimSuffix(w.buf.Next(i + 1)[:i], []byte("\r")))
+		w.fb.printTypedEvent("output", &outputEvent{Stream: w.stream, Text: line})
+	}
+	return len(p), nil
+}
+
+// Flush prints the last line written, if not terminated by a newline
+func (w *EventWriter) Flush() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.buf.Len() > 0 {
+		w.fb.printTypedEvent("output", &outputEvent{Stream: w.stream, Text: w.buf.String()})
+		w.buf.Reset()
+	}
+}
@@ -0,0 +1,67 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package feedback
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNDJSONEvents(t *testing.T) {
+	out := new(bytes.Buffer)
+	errOut := new(bytes.Buffer)
+	fb := New(out, errOut, NDJSON)
+
+	fb.Print("Downloading index...")
+	fb.PrintTypedEvent("task", map[string]interface{}{"name": "index", "completed": true})
+	fb.PrintResult(&annotatedResult{})
+	fb.Error("something failed")
+	require.Equal(t, `{"type":"message","message":"Downloading index..."}
+{"type":"task","task":{"completed":true,"name":"index"}}
+{"type":"result","result":{}}
+{"type":"error","error":"something failed"}
+`, out.String())
+	require.Empty(t, errOut.String())
+
+	// The typed events are printed only in NDJSON format
+	out.Reset()
+	fb.SetFormat(Text)
+	fb.PrintTypedEvent("task", map[string]interface{}{"name": "index"})
+	require.Empty(t, out.String())
+}
+
+func TestEventWriter(t *testing.T) {
+	out := new(bytes.Buffer)
+	fb := New(out, out, NDJSON)
+
+	w := fb.NewEventWriter("stderr")
+	fmt.Fprint(w, "Sketch uses 924 bytes\r\nGlobal vari")
+	fmt.Fprint(w, "ables use 9 bytes\nDone")
+	require.Equal(t, `{"type":"output","output":{"stream":"stderr","text":"Sketch uses 924 bytes"}}
+{"type":"output","output":{"stream":"stderr","text":"Global variables use 9 bytes"}}
+`, out.String())
+
+	out.Reset()
+	w.Flush()
+	require.Equal(t, `{"type":"output","output":{"stream":"stderr","text":"Done"}}
+`, out.String())
+	out.Reset()
+	w.Flush()
+	require.Empty(t, out.String())
+}
@@ -39,6 +39,16 @@ func GetFormat() OutputFormat {
 	return fb.GetFormat()
 }
 
+// SetQuery sets the query selecting the part of the results to print
+func SetQuery(q *Query) {
+	fb.SetQuery(q)
+}
+
+// GetTableOptions returns the options used to print the TableResults
+func GetTableOptions() *TableOptions {
+	return fb.GetTableOptions()
+}
+
 // OutputWriter returns the underlying io.Writer to be used when the Print*
 // api is not enough
 func OutputWriter() io.Writer {
@@ -79,3 +89,21 @@ func Error(v ...interface{}) {
 func PrintResult(res Result) {
 	fb.PrintResult(res)
 }
+
+// PrintEvent prints an event of a long running command, on a single line in
+// JSON format.
+func PrintEvent(res Result) {
+	fb.PrintEvent(res)
+}
+
+// PrintTypedEvent prints the progress of a long running command as a typed
+// event. The event is printed only in NDJSON format.
+func PrintTypedEvent(eventType string, data interface{}) {
+	fb.PrintTypedEvent(eventType, data)
+}
+
+// NewEventWriter returns an io.Writer printing each line written to it as an
+// "output" event of the given stream, in NDJSON format
+func NewEventWriter(stream string) *EventWriter {
+	return fb.NewEventWriter(stream)
+}
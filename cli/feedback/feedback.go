@@ -16,12 +16,15 @@
 package feedback
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"sync"
 
+	"github.com/arduino/arduino-cli/junit"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc/status"
 )
@@ -34,6 +37,26 @@ const (
 	Text OutputFormat = iota
 	// JSON means JSON format
 	JSON
+	// JUnit means JUnit XML format, supported by the commands reporting test
+	// results. The other output is written to the error writer, so that the
+	// output writer contains only the report.
+	JUnit
+	// GitHubAnnotations means plain text format, followed by the GitHub
+	// Actions workflow commands annotating the problems found in the files
+	GitHubAnnotations
+	// CSV means comma separated values, supported by the commands reporting
+	// tables. As for JUnit, the other output is written to the error writer.
+	CSV
+	// NDJSON means newline delimited JSON: the messages, the errors, the
+	// progress of the long running operations and the results are all printed
+	// on the out writer as typed events, one JSON object per line.
+	NDJSON
+	// YAML means YAML format, with the same fields of the JSON format in the
+	// same order
+	YAML
+	// TOML means TOML format, with the same fields of the JSON format in the
+	// same order
+	TOML
 )
 
 // Result is anything more complex than a sentence that needs to be printed
@@ -43,12 +66,30 @@ type Result interface {
 	Data() interface{}
 }
 
+// JUnitResult is a Result that can be printed as a JUnit XML report
+type JUnitResult interface {
+	Result
+	JUnit() *junit.TestSuites
+}
+
+// CSVResult is a Result that can be printed as comma separated values
+type CSVResult interface {
+	Result
+	CSV() [][]string
+}
+
 // Feedback wraps an io.Writer and provides an uniform API the CLI can use to
 // provide feedback to the users.
 type Feedback struct {
 	out    io.Writer
 	err    io.Writer
 	format OutputFormat
+	// serializes the NDJSON events printed from many goroutines
+	eventsLock sync.Mutex
+	// select the columns of the TableResults
+	tableOptions TableOptions
+	// if set, selects the part of the results to print
+	query *Query
 }
 
 // New creates a Feedback instance
@@ -75,6 +116,18 @@ func (fb *Feedback) GetFormat() OutputFormat {
 	return fb.format
 }
 
+// SetQuery sets the query selecting the part of the results to print, nil
+// to print the whole results
+func (fb *Feedback) SetQuery(q *Query) {
+	fb.query = q
+}
+
+// GetTableOptions returns the options used to print the TableResults, they
+// can be changed to select the columns to print
+func (fb *Feedback) GetTableOptions() *TableOptions {
+	return &fb.tableOptions
+}
+
 // OutputWriter returns the underlying io.Writer to be used when the Print*
 // api is not enough.
 func (fb *Feedback) OutputWriter() io.Writer {
@@ -94,9 +147,22 @@ func (fb *Feedback) Printf(format string, v ...interface{}) {
 
 // Print behaves like fmt.Print but writes on the out writer and adds a newline.
 func (fb *Feedback) Print(v interface{}) {
-	if fb.format == JSON {
+	if _, isString := v.(string); !isString && fb.query != nil {
+		fb.printQuery(v)
+		return
+	}
+	switch fb.format {
+	case JSON:
 		fb.printJSON(v)
-	} else {
+	case NDJSON:
+		fb.printTypedEvent("message", v)
+	case YAML:
+		fb.printYAML(v, false)
+	case TOML:
+		fb.printTOML(v, false)
+	case JUnit, CSV:
+		fmt.Fprintln(fb.err, v)
+	default:
 		fmt.Fprintln(fb.out, v)
 	}
 }
@@ -120,7 +186,11 @@ func (fb *Feedback) Errorf(format string, v ...interface{}) {
 // Error behaves like fmt.Print but writes on the error writer and adds a
 // newline. It also logs the error.
 func (fb *Feedback) Error(v ...interface{}) {
-	fmt.Fprintln(fb.err, v...)
+	if fb.format == NDJSON {
+		fb.printTypedEvent("error", fmt.Sprint(v...))
+	} else {
+		fmt.Fprintln(fb.err, v...)
+	}
 	logrus.Error(fmt.Sprint(v...))
 }
 
@@ -134,13 +204,56 @@ func (fb *Feedback) printJSON(v interface{}) {
 	}
 }
 
+// PrintEvent prints an event of a long running command. In JSON format the
+// event is printed on a single line, so that the sequence of events can be
+// parsed as newline delimited JSON (NDJSON). In YAML and TOML formats the
+// events are the items of a sequence and of an array of tables.
+func (fb *Feedback) PrintEvent(res Result) {
+	if fb.format == JSON {
+		if d, err := json.Marshal(res.Data()); err != nil {
+			fb.Errorf("Error during JSON encoding of the output: %v", err)
+		} else {
+			fmt.Fprintf(fb.out, "%v\n", string(d))
+		}
+	} else if fb.format == NDJSON {
+		fb.printTypedEvent("event", res.Data())
+	} else if fb.format == YAML {
+		fb.printYAML(res.Data(), true)
+	} else if fb.format == TOML {
+		fb.printTOML(res.Data(), true)
+	} else {
+		fb.Print(fmt.Sprintf("%s", res))
+	}
+}
+
 // PrintResult is a convenient wrapper to provide feedback for complex data,
 // where the contents can't be just serialized to JSON but requires more
 // structure.
 func (fb *Feedback) PrintResult(res Result) {
-	if fb.format == JSON {
+	if fb.query != nil {
+		fb.printQuery(res.Data())
+	} else if fb.format == JSON {
 		fb.printJSON(res.Data())
+	} else if fb.format == NDJSON {
+		fb.printTypedEvent("result", res.Data())
+	} else if fb.format == YAML {
+		fb.printYAML(res.Data(), false)
+	} else if fb.format == TOML {
+		fb.printTOML(res.Data(), false)
+	} else if junitRes, ok := res.(JUnitResult); ok && fb.format == JUnit {
+		if err := junitRes.JUnit().Write(fb.out); err != nil {
+			fb.Errorf("Error during JUnit encoding of the output: %v", err)
+		}
+	} else if csvRes, ok := res.(CSVResult); ok && fb.format == CSV {
+		if err := csv.NewWriter(fb.out).WriteAll(csvRes.CSV()); err != nil {
+			fb.Errorf("Error during CSV encoding of the output: %v", err)
+		}
+	} else if tableRes, ok := res.(TableResult); ok && (fb.format == CSV || fb.format != JUnit && fb.tableOptions.IsSet()) {
+		fb.printTable(tableRes)
 	} else {
 		fb.Print(fmt.Sprintf("%s", res))
+		if annotatedRes, ok := res.(AnnotatedResult); ok && fb.format == GitHubAnnotations {
+			fb.printAnnotations(annotatedRes)
+		}
 	}
 }
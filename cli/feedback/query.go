@@ -0,0 +1,620 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package feedback
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Query is a jq-style expression selecting part of the result of a command.
+// It supports a subset of the jq language:
+//
+//	.                      the whole result
+//	.foo .foo.bar ."a b"   the fields of an object
+//	.[2] .[-1] .["a b"]    the elements of an array, or the fields of an object
+//	.[]                    all the elements of an array, or the values of an object
+//	a | b                  the results of b applied to each result of a
+//	select(cond)           the input if cond is true
+//	== != < <= > >=        comparisons, with string, number, true, false and null literals
+//	and or not             boolean operators
+//	length keys            the length and the sorted keys (the indexes for an array) of the input
+type Query struct {
+	text   string
+	filter queryFilter
+}
+
+// queryFilter returns the stream of results of a query applied to v
+type queryFilter func(v interface{}) ([]interface{}, error)
+
+// ParseQuery parses a query
+func ParseQuery(text string) (*Query, error) {
+	tokens, err := tokenizeQuery(text)
+	if err != nil {
+		return nil, err
+	}
+	p := &queryParser{tokens: tokens}
+	filter, err := p.parsePipe()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q in query", p.tokens[p.pos])
+	}
+	return &Query{text: text, filter: filter}, nil
+}
+
+// String returns the text of the query
+func (q *Query) String() string {
+	return q.text
+}
+
+// Apply returns the results of the query applied to the JSON representation
+// of data, keeping the order of the fields
+func (q *Query) Apply(data interface{}) ([]interface{}, error) {
+	v, err := toOrdered(data)
+	if err != nil {
+		return nil, err
+	}
+	return q.filter(v)
+}
+
+// printQuery prints the results of the query applied to data, one after
+// the other. In text format the strings are printed without quotes and the
+// other values as JSON.
+func (fb *Feedback) printQuery(data interface{}) {
+	results, err := fb.query.Apply(data)
+	if err != nil {
+		fb.Errorf("Error applying the query %q: %v", fb.query, err)
+		return
+	}
+	for _, res := range results {
+		switch fb.format {
+		case NDJSON:
+			fb.printTypedEvent("result", res)
+		case YAML:
+			fb.printYAML(res, false)
+		case TOML:
+			fb.printTOML(res, false)
+		case JSON:
+			fb.printJSON(res)
+		default:
+			if s, isString := res.(string); isString {
+				fmt.Fprintln(fb.out, s)
+			} else {
+				fb.printJSON(res)
+			}
+		}
+	}
+}
+
+func tokenizeQuery(text string) ([]string, error) {
+	tokens := []string{}
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.ContainsRune(".|[]()", rune(c)):
+			tokens = append(tokens, text[i:i+1])
+			i++
+		case strings.HasPrefix(text[i:], "==") || strings.HasPrefix(text[i:], "!=") ||
+			strings.HasPrefix(text[i:], "<=") || strings.HasPrefix(text[i:], ">="):
+			tokens = append(tokens, text[i:i+2])
+			i += 2
+		case c == '<' || c == '>':
+			tokens = append(tokens, text[i:i+1])
+			i++
+		case c == '"':
+			end := i + 1
+			for ; end < len(text) && text[end] != '"'; end++ {
+				if text[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(text) {
+				return nil, fmt.Errorf("unterminated string in query")
+			}
+			tokens = append(tokens, text[i:end+1])
+			i = end + 1
+		case c == '-' || (c >= '0' && c <= '9'):
+			end := i + 1
+			for end < len(text) && (text[end] == '.' || (text[end] >= '0' && text[end] <= '9')) {
+				end++
+			}
+			tokens = append(tokens, text[i:end])
+			i = end
+		default:
+			r, _ := utf8.DecodeRuneInString(text[i:])
+			if !isQueryIdentRune(r, true) {
+				return nil, fmt.Errorf("unexpected %q in query", r)
+			}
+			end := i
+			for end < len(text) {
+				r, size := utf8.DecodeRuneInString(text[end:])
+				if !isQueryIdentRune(r, end == i) {
+					break
+				}
+				end += size
+			}
+			tokens = append(tokens, text[i:end])
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+func isQueryIdentRune(r rune, first bool) bool {
+	return r == '_' || unicode.IsLetter(r) || (!first && unicode.IsDigit(r))
+}
+
+func isQueryIdent(token string) bool {
+	r, _ := utf8.DecodeRuneInString(token)
+	return token != "" && isQueryIdentRune(r, true)
+}
+
+type queryParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *queryParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *queryParser) next() string {
+	token := p.peek()
+	p.pos++
+	return token
+}
+
+func (p *queryParser) expect(token string) error {
+	if got := p.next(); got != token {
+		if got == "" {
+			return fmt.Errorf("expected %q at the end of the query", token)
+		}
+		return fmt.Errorf("expected %q in query, got %q", token, got)
+	}
+	return nil
+}
+
+// parsePipe parses: or ( "|" or )*
+func (p *queryParser) parsePipe() (queryFilter, error) {
+	left, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "|" {
+		p.next()
+		right, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		left = pipeFilter(left, right)
+	}
+	return left, nil
+}
+
+// parseOr parses: and ( "or" and )*
+func (p *queryParser) parseOr() (queryFilter, error) {
+	return p.parseBinary("or", p.parseAnd)
+}
+
+// parseAnd parses: comparison ( "and" comparison )*
+func (p *queryParser) parseAnd() (queryFilter, error) {
+	return p.parseBinary("and", p.parseComparison)
+}
+
+func (p *queryParser) parseBinary(op string, parseOperand func() (queryFilter, error)) (queryFilter, error) {
+	left, err := parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == op {
+		p.next()
+		right, err := parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryFilter(left, right, func(a, b interface{}) (interface{}, error) {
+			if op == "and" {
+				return isTruthy(a) && isTruthy(b), nil
+			}
+			return isTruthy(a) || isTruthy(b), nil
+		})
+	}
+	return left, nil
+}
+
+// parseComparison parses: term ( op term )?
+func (p *queryParser) parseComparison() (queryFilter, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	switch op := p.peek(); op {
+	case "==", "!=", "<", "<=", ">", ">=":
+		p.next()
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		return binaryFilter(left, right, func(a, b interface{}) (interface{}, error) {
+			return compareValues(op, a, b)
+		}), nil
+	}
+	return left, nil
+}
+
+// parseTerm parses a path, a literal, a function or a parenthesized query
+func (p *queryParser) parseTerm() (queryFilter, error) {
+	token := p.next()
+	switch {
+	case token == ".":
+		return p.parsePath()
+	case token == "(":
+		filter, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		return filter, p.expect(")")
+	case strings.HasPrefix(token, "\""):
+		var s string
+		if err := json.Unmarshal([]byte(token), &s); err != nil {
+			return nil, fmt.Errorf("invalid string %s in query", token)
+		}
+		return literalFilter(s), nil
+	case token == "-" || strings.HasPrefix(token, "-") || (token != "" && token[0] >= '0' && token[0] <= '9'):
+		if _, err := strconv.ParseFloat(token, 64); err != nil {
+			return nil, fmt.Errorf("invalid number %s in query", token)
+		}
+		return literalFilter(json.Number(token)), nil
+	case token == "true" || token == "false":
+		return literalFilter(token == "true"), nil
+	case token == "null":
+		return literalFilter(nil), nil
+	case token == "not":
+		return func(v interface{}) ([]interface{}, error) {
+			return []interface{}{!isTruthy(v)}, nil
+		}, nil
+	case token == "length":
+		return func(v interface{}) ([]interface{}, error) {
+			n, err := valueLength(v)
+			return []interface{}{n}, err
+		}, nil
+	case token == "keys":
+		return func(v interface{}) ([]interface{}, error) {
+			keys, err := valueKeys(v)
+			return []interface{}{keys}, err
+		}, nil
+	case token == "select":
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		cond, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return func(v interface{}) ([]interface{}, error) {
+			results, err := cond(v)
+			if err != nil {
+				return nil, err
+			}
+			res := []interface{}{}
+			for _, r := range results {
+				if isTruthy(r) {
+					res = append(res, v)
+				}
+			}
+			return res, nil
+		}, nil
+	case token == "":
+		return nil, fmt.Errorf("unexpected end of the query")
+	}
+	return nil, fmt.Errorf("unexpected %q in query", token)
+}
+
+// parsePath parses the fields and the indexes following a "."
+func (p *queryParser) parsePath() (queryFilter, error) {
+	filter := queryFilter(func(v interface{}) ([]interface{}, error) {
+		return []interface{}{v}, nil
+	})
+	// the first field doesn't need a "."
+	if token := p.peek(); isQueryIdent(token) && !isQueryKeyword(token) || strings.HasPrefix(token, "\"") {
+		field, err := p.parseField()
+		if err != nil {
+			return nil, err
+		}
+		filter = field
+	}
+	for {
+		switch p.peek() {
+		case ".":
+			p.next()
+			field, err := p.parseField()
+			if err != nil {
+				return nil, err
+			}
+			filter = pipeFilter(filter, field)
+		case "[":
+			p.next()
+			index, err := p.parseIndex()
+			if err != nil {
+				return nil, err
+			}
+			filter = pipeFilter(filter, index)
+		default:
+			return filter, nil
+		}
+	}
+}
+
+func isQueryKeyword(token string) bool {
+	switch token {
+	case "and", "or":
+		return true
+	}
+	return false
+}
+
+func (p *queryParser) parseField() (queryFilter, error) {
+	token := p.next()
+	if token == "[" {
+		return p.parseIndex()
+	}
+	key := token
+	if strings.HasPrefix(token, "\"") {
+		if err := json.Unmarshal([]byte(token), &key); err != nil {
+			return nil, fmt.Errorf("invalid string %s in query", token)
+		}
+	} else if !isQueryIdent(token) {
+		return nil, fmt.Errorf("expected a field name after \".\" in query, got %q", token)
+	}
+	return func(v interface{}) ([]interface{}, error) {
+		res, err := indexValue(v, key)
+		return []interface{}{res}, err
+	}, nil
+}
+
+// parseIndex parses what follows a "[": "]", a number or a string, and "]"
+func (p *queryParser) parseIndex() (queryFilter, error) {
+	token := p.next()
+	if token == "]" {
+		return iterateValue, nil
+	}
+	var index interface{}
+	if strings.HasPrefix(token, "\"") {
+		var key string
+		if err := json.Unmarshal([]byte(token), &key); err != nil {
+			return nil, fmt.Errorf("invalid string %s in query", token)
+		}
+		index = key
+	} else if n, err := strconv.Atoi(token); err == nil {
+		index = n
+	} else {
+		return nil, fmt.Errorf("invalid index %q in query", token)
+	}
+	if err := p.expect("]"); err != nil {
+		return nil, err
+	}
+	return func(v interface{}) ([]interface{}, error) {
+		res, err := indexValue(v, index)
+		return []interface{}{res}, err
+	}, nil
+}
+
+func literalFilter(value interface{}) queryFilter {
+	return func(interface{}) ([]interface{}, error) {
+		return []interface{}{value}, nil
+	}
+}
+
+func pipeFilter(left, right queryFilter) queryFilter {
+	return func(v interface{}) ([]interface{}, error) {
+		results, err := left(v)
+		if err != nil {
+			return nil, err
+		}
+		res := []interface{}{}
+		for _, r := range results {
+			rightResults, err := right(r)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, rightResults...)
+		}
+		return res, nil
+	}
+}
+
+// binaryFilter applies op to each combination of the results of left and
+// right
+func binaryFilter(left, right queryFilter, op func(a, b interface{}) (interface{}, error)) queryFilter {
+	return func(v interface{}) ([]interface{}, error) {
+		leftResults, err := left(v)
+		if err != nil {
+			return nil, err
+		}
+		rightResults, err := right(v)
+		if err != nil {
+			return nil, err
+		}
+		res := []interface{}{}
+		for _, a := range leftResults {
+			for _, b := range rightResults {
+				r, err := op(a, b)
+				if err != nil {
+					return nil, err
+				}
+				res = append(res, r)
+			}
+		}
+		return res, nil
+	}
+}
+
+func iterateValue(v interface{}) ([]interface{}, error) {
+	switch v := v.(type) {
+	case []interface{}:
+		return v, nil
+	case orderedMap:
+		res := []interface{}{}
+		for _, field := range v {
+			res = append(res, field.Value)
+		}
+		return res, nil
+	}
+	return nil, fmt.Errorf("cannot iterate over %s", valueType(v))
+}
+
+// indexValue returns the field of an object or the element of an array,
+// indexing null gives null
+func indexValue(v interface{}, index interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case orderedMap:
+		if key, isKey := index.(string); isKey {
+			for _, field := range v {
+				if field.Key == key {
+					return field.Value, nil
+				}
+			}
+			return nil, nil
+		}
+	case []interface{}:
+		if i, isIndex := index.(int); isIndex {
+			if i < 0 {
+				i += len(v)
+			}
+			if i < 0 || i >= len(v) {
+				return nil, nil
+			}
+			return v[i], nil
+		}
+	}
+	return nil, fmt.Errorf("cannot index %s with %q", valueType(v), fmt.Sprint(index))
+}
+
+func valueLength(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case nil:
+		return json.Number("0"), nil
+	case string:
+		return json.Number(strconv.Itoa(utf8.RuneCountInString(v))), nil
+	case []interface{}:
+		return json.Number(strconv.Itoa(len(v))), nil
+	case orderedMap:
+		return json.Number(strconv.Itoa(len(v))), nil
+	}
+	return nil, fmt.Errorf("%s has no length", valueType(v))
+}
+
+func valueKeys(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case []interface{}:
+		res := []interface{}{}
+		for i := range v {
+			res = append(res, json.Number(strconv.Itoa(i)))
+		}
+		return res, nil
+	case orderedMap:
+		keys := []string{}
+		for _, field := range v {
+			keys = append(keys, field.Key)
+		}
+		sort.Strings(keys)
+		res := []interface{}{}
+		for _, key := range keys {
+			res = append(res, key)
+		}
+		return res, nil
+	}
+	return nil, fmt.Errorf("%s has no keys", valueType(v))
+}
+
+// compareValues compares a and b: numbers by value, strings in lexical order,
+// and the other values only for equality
+func compareValues(op string, a, b interface{}) (bool, error) {
+	var cmp int
+	switch {
+	case valueType(a) == "number" && valueType(b) == "number":
+		x, _ := a.(json.Number).Float64()
+		y, _ := b.(json.Number).Float64()
+		if x < y {
+			cmp = -1
+		} else if x > y {
+			cmp = 1
+		}
+	case valueType(a) == "string" && valueType(b) == "string":
+		cmp = strings.Compare(a.(string), b.(string))
+	default:
+		x, _ := json.Marshal(a)
+		y, _ := json.Marshal(b)
+		equal := string(x) == string(y)
+		switch op {
+		case "==":
+			return equal, nil
+		case "!=":
+			return !equal, nil
+		}
+		return false, fmt.Errorf("cannot compare %s and %s", valueType(a), valueType(b))
+	}
+	switch op {
+	case "==":
+		return cmp == 0, nil
+	case "!=":
+		return cmp != 0, nil
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	}
+	return cmp >= 0, nil
+}
+
+func isTruthy(v interface{}) bool {
+	return v != nil && v != false
+}
+
+func valueType(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	}
+	return "object"
+}
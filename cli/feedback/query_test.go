@@ -0,0 +1,98 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package feedback
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuery(t *testing.T) {
+	libs := []*testLibrary{
+		{Name: "Servo", Version: "1.1.8", Authors: []string{"Arduino"}, Location: &testLocation{Path: "/libs/Servo", User: true}},
+		{Name: "Adafruit GFX Library", Version: "1.10.12", Authors: []string{"Adafruit"}},
+		{Name: "ArduinoJson", Version: "6.9.1", Releases: []*testRelease{{Version: "6.9.1", Size: 100}, {Version: "6.9.0", Size: 90}}},
+	}
+	query := func(q string) string {
+		parsed, err := ParseQuery(q)
+		require.NoError(t, err, q)
+		res, err := parsed.Apply(libs)
+		require.NoError(t, err, q)
+		d, err := json.Marshal(res)
+		require.NoError(t, err)
+		return string(d)
+	}
+
+	require.Equal(t, `[3]`, query(`length`))
+	require.Equal(t, `["Servo","Adafruit GFX Library","ArduinoJson"]`, query(`.[].name`))
+	require.Equal(t, `["ArduinoJson"]`, query(`.[-1] | .name`))
+	require.Equal(t, `[null]`, query(`.[5].name`))
+	require.Equal(t, `["/libs/Servo"]`, query(`.[] | select(.name == "Servo") | .location.path`))
+	require.Equal(t, `["/libs/Servo"]`, query(`.[] | select(.location.user) | .location."path"`))
+	require.Equal(t, `["Adafruit GFX Library","ArduinoJson"]`, query(`.[] | select(.location == null and .name != "x") | .name`))
+	require.Equal(t, `[90]`, query(`.[2].releases[] | select(.size < 100) | .["size"]`))
+	require.Equal(t, `[true,false]`, query(`.[2].releases[] | .version >= "6.9.1"`))
+	require.Equal(t, `[["path","user"]]`, query(`.[0].location | keys`))
+	require.Equal(t, `[[0,1,2]]`, query(`keys`))
+	require.Equal(t, `[[0,1]]`, query(`.[2].releases | keys`))
+	require.Equal(t, `[{"path":"/libs/Servo","user":true}]`, query(`.[0] | .location`))
+	require.Equal(t, `[false,true,true]`, query(`.[] | .location | not`))
+
+	for _, invalid := range []string{``, `.[`, `.foo |`, `select(.a`, `.a == `, `{a}`, `."unterminated`, `.[1.5]`} {
+		_, err := ParseQuery(invalid)
+		require.Error(t, err, invalid)
+	}
+
+	parsed, err := ParseQuery(`.[0].name.first`)
+	require.NoError(t, err)
+	_, err = parsed.Apply(libs)
+	require.EqualError(t, err, `cannot index string with "first"`)
+	parsed, err = ParseQuery(`.[] | select(.name > 1)`)
+	require.NoError(t, err)
+	_, err = parsed.Apply(libs)
+	require.EqualError(t, err, `cannot compare string and number`)
+}
+
+func TestPrintQuery(t *testing.T) {
+	out := new(bytes.Buffer)
+	fb := New(out, out, Text)
+	query, err := ParseQuery(`.releases[] | .version, .releases[0]`)
+	require.Error(t, err)
+	require.Nil(t, query)
+
+	// In text format the strings are printed without quotes
+	query, err = ParseQuery(`.releases[].version`)
+	require.NoError(t, err)
+	fb.SetQuery(query)
+	res := &testLibraryResult{&testLibrary{Name: "ArduinoJson", Releases: []*testRelease{{Version: "6.9.1"}, {Version: "6.9.0"}}}}
+	fb.PrintResult(res)
+	require.Equal(t, "6.9.1\n6.9.0\n", out.String())
+
+	out.Reset()
+	fb.SetFormat(JSON)
+	fb.PrintResult(res)
+	require.Equal(t, "\"6.9.1\"\n\"6.9.0\"\n", out.String())
+
+	out.Reset()
+	query, err = ParseQuery(`.releases[0]`)
+	require.NoError(t, err)
+	fb.SetQuery(query)
+	fb.PrintResult(res)
+	require.Equal(t, "{\n  \"version\": \"6.9.1\",\n  \"size\": 0,\n  \"rating\": 0\n}\n", out.String())
+}
@@ -0,0 +1,207 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package feedback
+
+import (
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/arduino/arduino-cli/table"
+)
+
+// TableResult is a Result that can be printed as a table, whose columns can
+// be selected and sorted with the TableOptions
+type TableResult interface {
+	Result
+	Table() *Table
+}
+
+// Table is the data of a TableResult: the names of the columns and the rows,
+// with a value for each column. The values are not truncated or decorated
+// as in the text output, so that they can be processed by other programs.
+type Table struct {
+	Columns []string
+	Rows    [][]string
+}
+
+// AddRow adds a row to the table
+func (t *Table) AddRow(values ...string) {
+	t.Rows = append(t.Rows, values)
+}
+
+// TableOptions select how the TableResults are printed in text and CSV
+// formats
+type TableOptions struct {
+	// Columns to print, in this order. All the columns if empty.
+	Columns []string
+	// SortBy is the column used to sort the rows, in descending order if
+	// prefixed with "-"
+	SortBy string
+	// NoHeader omits the names of the columns. In text format the values are
+	// separated by a tab instead of being aligned.
+	NoHeader bool
+}
+
+// IsSet returns true if any option is set, otherwise the TableResults are
+// printed as the other Results
+func (o *TableOptions) IsSet() bool {
+	return len(o.Columns) > 0 || o.SortBy != "" || o.NoHeader
+}
+
+// Check returns an error if the options refer to a column not available
+func (o *TableOptions) Check(available []string) error {
+	check := func(column string) error {
+		for _, c := range available {
+			if c == column {
+				return nil
+			}
+		}
+		return fmt.Errorf("unknown column %q, the available columns are: %s", column, strings.Join(available, ", "))
+	}
+	for _, column := range o.Columns {
+		if err := check(column); err != nil {
+			return err
+		}
+	}
+	if o.SortBy != "" {
+		return check(strings.TrimPrefix(o.SortBy, "-"))
+	}
+	return nil
+}
+
+// Select returns a copy of the table with the columns selected by the
+// options, with the rows sorted as requested
+func (t *Table) Select(opts *TableOptions) (*Table, error) {
+	if err := opts.Check(t.Columns); err != nil {
+		return nil, err
+	}
+	index := map[string]int{}
+	for i, column := range t.Columns {
+		index[column] = i
+	}
+
+	rows := append([][]string{}, t.Rows...)
+	if opts.SortBy != "" {
+		column := index[strings.TrimPrefix(opts.SortBy, "-")]
+		descending := strings.HasPrefix(opts.SortBy, "-")
+		sort.SliceStable(rows, func(i, j int) bool {
+			if descending {
+				return naturalLess(rows[j][column], rows[i][column])
+			}
+			return naturalLess(rows[i][column], rows[j][column])
+		})
+	}
+
+	columns := opts.Columns
+	if len(columns) == 0 {
+		columns = t.Columns
+	}
+	res := &Table{Columns: columns}
+	for _, row := range rows {
+		selected := []string{}
+		for _, column := range columns {
+			selected = append(selected, row[index[column]])
+		}
+		res.AddRow(selected...)
+	}
+	return res, nil
+}
+
+// Render returns the table as text: the columns are aligned, or separated
+// by a tab if the header is omitted
+func (t *Table) Render(noHeader bool) string {
+	if noHeader {
+		var sb strings.Builder
+		for _, row := range t.Rows {
+			sb.WriteString(strings.Join(row, "\t") + "\n")
+		}
+		return sb.String()
+	}
+	toCells := func(values []string) []interface{} {
+		cells := []interface{}{}
+		for _, value := range values {
+			cells = append(cells, value)
+		}
+		return cells
+	}
+	res := table.New()
+	res.SetHeader(toCells(t.Columns)...)
+	for _, row := range t.Rows {
+		res.AddRow(toCells(row)...)
+	}
+	return res.Render()
+}
+
+// CSV returns the table as comma separated values, with the header unless
+// omitted
+func (t *Table) CSV(noHeader bool) [][]string {
+	if noHeader {
+		return t.Rows
+	}
+	return append([][]string{t.Columns}, t.Rows...)
+}
+
+// printTable prints a TableResult as selected by the table options
+func (fb *Feedback) printTable(res TableResult) {
+	t, err := res.Table().Select(&fb.tableOptions)
+	if err != nil {
+		fb.Errorf("Error printing the table: %v", err)
+		return
+	}
+	if fb.format == CSV {
+		if err := csv.NewWriter(fb.out).WriteAll(t.CSV(fb.tableOptions.NoHeader)); err != nil {
+			fb.Errorf("Error during CSV encoding of the output: %v", err)
+		}
+		return
+	}
+	fmt.Fprint(fb.out, t.Render(fb.tableOptions.NoHeader))
+}
+
+// naturalLess compares a and b ignoring the case and comparing the runs of
+// digits by their numeric value, so that versions like 1.9.0 and 1.10.0 are
+// sorted correctly
+func naturalLess(a, b string) bool {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	for a != "" && b != "" {
+		da, db := leadingDigits(a), leadingDigits(b)
+		if da != "" && db != "" {
+			na, _ := strconv.ParseUint(da, 10, 64)
+			nb, _ := strconv.ParseUint(db, 10, 64)
+			if na != nb {
+				return na < nb
+			}
+			a, b = a[len(da):], b[len(db):]
+			continue
+		}
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		a, b = a[1:], b[1:]
+	}
+	return len(a) < len(b)
+}
+
+func leadingDigits(s string) string {
+	i := strings.IndexFunc(s, func(r rune) bool { return !unicode.IsDigit(r) })
+	if i < 0 {
+		return s
+	}
+	return s[:i]
+}
@@ -0,0 +1,95 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package feedback
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testTableResult struct{}
+
+func (r *testTableResult) Data() interface{} {
+	return nil
+}
+
+func (r *testTableResult) String() string {
+	return "3 libraries"
+}
+
+func (r *testTableResult) Table() *Table {
+	t := &Table{Columns: []string{"name", "version", "author"}}
+	t.AddRow("Servo", "1.1.8", "Michael Margolis, Arduino")
+	t.AddRow("Adafruit GFX Library", "1.10.12", "Adafruit")
+	t.AddRow("ArduinoJson", "6.9.1", "Benoit Blanchon")
+	return t
+}
+
+func TestTableOptions(t *testing.T) {
+	out := new(bytes.Buffer)
+	fb := New(out, out, Text)
+
+	// Without options the text output is printed
+	fb.PrintResult(&testTableResult{})
+	require.Equal(t, "3 libraries\n", out.String())
+
+	out.Reset()
+	opts := fb.GetTableOptions()
+	opts.Columns = []string{"version", "name"}
+	opts.SortBy = "-version"
+	fb.PrintResult(&testTableResult{})
+	require.Equal(t, ""+
+		"version name                \n"+
+		"6.9.1   ArduinoJson         \n"+
+		"1.10.12 Adafruit GFX Library\n"+
+		"1.1.8   Servo               \n", out.String())
+
+	out.Reset()
+	opts.Columns = nil
+	opts.SortBy = "name"
+	opts.NoHeader = true
+	fb.PrintResult(&testTableResult{})
+	require.Equal(t, ""+
+		"Adafruit GFX Library\t1.10.12\tAdafruit\n"+
+		"ArduinoJson\t6.9.1\tBenoit Blanchon\n"+
+		"Servo\t1.1.8\tMichael Margolis, Arduino\n", out.String())
+
+	// In CSV format the table is printed even without options
+	out.Reset()
+	fb = New(out, out, CSV)
+	fb.GetTableOptions().Columns = []string{"name", "author"}
+	fb.PrintResult(&testTableResult{})
+	require.Equal(t, ""+
+		"name,author\n"+
+		"Servo,\"Michael Margolis, Arduino\"\n"+
+		"Adafruit GFX Library,Adafruit\n"+
+		"ArduinoJson,Benoit Blanchon\n", out.String())
+
+	require.EqualError(t, (&TableOptions{SortBy: "-date"}).Check([]string{"name", "version"}),
+		`unknown column "date", the available columns are: name, version`)
+	require.NoError(t, (&TableOptions{Columns: []string{"version"}}).Check([]string{"name", "version"}))
+}
+
+func TestNaturalLess(t *testing.T) {
+	require.True(t, naturalLess("1.9.0", "1.10.0"))
+	require.False(t, naturalLess("1.10.0", "1.9.0"))
+	require.True(t, naturalLess("arduino:avr", "Arduino:samd"))
+	require.True(t, naturalLess("COM3", "COM10"))
+	require.True(t, naturalLess("1.0", "1.0.1"))
+	require.False(t, naturalLess("servo", "Servo"))
+}
@@ -0,0 +1,165 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package feedback
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var tomlBareKey = regexp.MustCompile("^[A-Za-z0-9_-]+$")
+
+// printTOML prints data as a TOML document. TOML documents are tables, so
+// the data that is not an object is printed in the "result" key. If event is
+// true data is printed as an element of the "events" array of tables, so that
+// the events of a long running command form a single TOML document.
+func (fb *Feedback) printTOML(data interface{}, event bool) {
+	v, err := toOrdered(data)
+	if err != nil {
+		fb.Errorf("Error during TOML encoding of the output: %v", err)
+		return
+	}
+	table, isTable := v.(orderedMap)
+	if !isTable {
+		table = orderedMap{{Key: "result", Value: v}}
+	}
+	var buf bytes.Buffer
+	if event {
+		buf.WriteString("[[events]]\n")
+		encodeTOMLTable(&buf, []string{"events"}, table)
+	} else {
+		encodeTOMLTable(&buf, nil, table)
+	}
+	fmt.Fprint(fb.out, strings.TrimPrefix(buf.String(), "\n"))
+}
+
+// encodeTOMLTable writes the fields of the table at the given path: first the
+// key/value pairs, then the sub tables and the arrays of tables, each one
+// with its header. The null values can't be represented and are skipped.
+func encodeTOMLTable(buf *bytes.Buffer, path []string, table orderedMap) {
+	var nested orderedMap
+	for _, field := range table {
+		switch value := field.Value.(type) {
+		case nil:
+			continue
+		case orderedMap:
+			nested = append(nested, field)
+			continue
+		case []interface{}:
+			if isTOMLArrayOfTables(value) {
+				nested = append(nested, field)
+				continue
+			}
+		}
+		buf.WriteString(tomlKey(field.Key) + " = ")
+		encodeTOMLValue(buf, field.Value)
+		buf.WriteString("\n")
+	}
+	for _, field := range nested {
+		fieldPath := append(append([]string{}, path...), field.Key)
+		header := tomlPath(fieldPath)
+		if table, isTable := field.Value.(orderedMap); isTable {
+			buf.WriteString("\n[" + header + "]\n")
+			encodeTOMLTable(buf, fieldPath, table)
+			continue
+		}
+		for _, item := range field.Value.([]interface{}) {
+			buf.WriteString("\n[[" + header + "]]\n")
+			encodeTOMLTable(buf, fieldPath, item.(orderedMap))
+		}
+	}
+}
+
+// encodeTOMLValue writes value inline, the objects are written as inline
+// tables
+func encodeTOMLValue(buf *bytes.Buffer, value interface{}) {
+	switch value := value.(type) {
+	case orderedMap:
+		buf.WriteString("{")
+		first := true
+		for _, field := range value {
+			if field.Value == nil {
+				continue
+			}
+			if !first {
+				buf.WriteString(", ")
+			}
+			first = false
+			buf.WriteString(tomlKey(field.Key) + " = ")
+			encodeTOMLValue(buf, field.Value)
+		}
+		buf.WriteString("}")
+	case []interface{}:
+		buf.WriteString("[")
+		first := true
+		for _, item := range value {
+			if item == nil {
+				continue
+			}
+			if !first {
+				buf.WriteString(", ")
+			}
+			first = false
+			encodeTOMLValue(buf, item)
+		}
+		buf.WriteString("]")
+	case string:
+		buf.WriteString(tomlString(value))
+	case json.Number:
+		buf.WriteString(value.String())
+	case bool:
+		fmt.Fprint(buf, value)
+	}
+}
+
+// isTOMLArrayOfTables returns true if the array is not empty and contains
+// only objects
+func isTOMLArrayOfTables(array []interface{}) bool {
+	for _, item := range array {
+		if _, isTable := item.(orderedMap); !isTable {
+			return false
+		}
+	}
+	return len(array) > 0
+}
+
+func tomlKey(key string) string {
+	if tomlBareKey.MatchString(key) {
+		return key
+	}
+	return tomlString(key)
+}
+
+func tomlPath(path []string) string {
+	keys := []string{}
+	for _, key := range path {
+		keys = append(keys, tomlKey(key))
+	}
+	return strings.Join(keys, ".")
+}
+
+// tomlString quotes s as a TOML basic string. The escapes of a JSON string
+// are all valid in TOML.
+func tomlString(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
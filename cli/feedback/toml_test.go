@@ -0,0 +1,108 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package feedback
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testLibrary struct {
+	Name     string            `json:"name"`
+	Version  string            `json:"version"`
+	Authors  []string          `json:"authors"`
+	Location *testLocation     `json:"location,omitempty"`
+	Releases []*testRelease    `json:"releases,omitempty"`
+	Extra    map[string]string `json:"extra"`
+}
+
+type testLocation struct {
+	Path string `json:"path"`
+	User bool   `json:"user"`
+}
+
+type testRelease struct {
+	Version string  `json:"version"`
+	Size    int64   `json:"size"`
+	Rating  float64 `json:"rating"`
+}
+
+type testLibraryResult struct {
+	lib interface{}
+}
+
+func (r *testLibraryResult) Data() interface{} {
+	return r.lib
+}
+
+func (r *testLibraryResult) String() string {
+	return "Servo"
+}
+
+func TestTOMLFormat(t *testing.T) {
+	out := new(bytes.Buffer)
+	fb := New(out, out, TOML)
+
+	fb.PrintResult(&testLibraryResult{&testLibrary{
+		Name:     "Servo",
+		Version:  "1.1.8",
+		Authors:  []string{"Michael \"Mike\" Margolis", "Arduino"},
+		Location: &testLocation{Path: "/home/user/Arduino/libraries/Servo", User: true},
+		Releases: []*testRelease{{Version: "1.1.8", Size: 1024, Rating: 4.5}, {Version: "1.1.7", Size: 1000}},
+	}})
+	require.Equal(t, `name = "Servo"
+version = "1.1.8"
+authors = ["Michael \"Mike\" Margolis", "Arduino"]
+
+[location]
+path = "/home/user/Arduino/libraries/Servo"
+user = true
+
+[[releases]]
+version = "1.1.8"
+size = 1024
+rating = 4.5
+
+[[releases]]
+version = "1.1.7"
+size = 1000
+rating = 0
+`, out.String())
+
+	// The data that is not a table is printed in the "result" key
+	out.Reset()
+	fb.PrintResult(&testLibraryResult{[]*testLocation{{Path: "/tmp/a b", User: false}}})
+	require.Equal(t, `[[result]]
+path = "/tmp/a b"
+user = false
+`, out.String())
+
+	// The events form an array of tables
+	out.Reset()
+	fb.PrintEvent(&testLibraryResult{map[string]interface{}{"event": "progress", "current": 1}})
+	fb.PrintEvent(&testLibraryResult{map[string]interface{}{"event": "result", "result": map[string]int{"a.b": 1}}})
+	require.Equal(t, `[[events]]
+current = 1
+event = "progress"
+[[events]]
+event = "result"
+
+[events.result]
+"a.b" = 1
+`, out.String())
+}
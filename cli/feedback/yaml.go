@@ -0,0 +1,148 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package feedback
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+)
+
+// orderedField is a field of an orderedMap
+type orderedField struct {
+	Key   string
+	Value interface{}
+}
+
+// orderedMap is a JSON object that keeps the order of its fields
+type orderedMap []orderedField
+
+// MarshalJSON encodes the fields of the object in their order
+func (m orderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	for i, field := range m {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		key, err := json.Marshal(field.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(field.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteString(":")
+		buf.Write(value)
+	}
+	buf.WriteString("}")
+	return buf.Bytes(), nil
+}
+
+// toOrdered converts data to its JSON representation, made of orderedMap,
+// []interface{}, string, json.Number, bool and nil values. The YAML and TOML
+// formats are rendered from it, so that they have the same field names and
+// the same field order of the JSON format.
+func toOrdered(data interface{}) (interface{}, error) {
+	d, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(d))
+	dec.UseNumber()
+	return decodeOrdered(dec)
+}
+
+func decodeOrdered(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		res := orderedMap{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, orderedField{Key: key.(string), Value: value})
+		}
+		_, err := dec.Token()
+		return res, err
+	case json.Delim('['):
+		res := []interface{}{}
+		for dec.More() {
+			value, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, value)
+		}
+		_, err := dec.Token()
+		return res, err
+	}
+	return tok, nil
+}
+
+// toYAMLValue converts the orderedMaps of v to yaml.MapSlice, so that the
+// order of the fields is kept
+func toYAMLValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case orderedMap:
+		res := yaml.MapSlice{}
+		for _, field := range v {
+			res = append(res, yaml.MapItem{Key: field.Key, Value: toYAMLValue(field.Value)})
+		}
+		return res
+	case []interface{}:
+		res := []interface{}{}
+		for _, item := range v {
+			res = append(res, toYAMLValue(item))
+		}
+		return res
+	}
+	return v
+}
+
+// printYAML prints data as a YAML document. If event is true data is printed
+// as an item of a sequence, so that the events of a long running command
+// form a single YAML document.
+func (fb *Feedback) printYAML(data interface{}, event bool) {
+	v, err := toOrdered(data)
+	if err != nil {
+		fb.Errorf("Error during YAML encoding of the output: %v", err)
+		return
+	}
+	v = toYAMLValue(v)
+	if event {
+		v = []interface{}{v}
+	}
+	d, err := yaml.Marshal(v)
+	if err != nil {
+		fb.Errorf("Error during YAML encoding of the output: %v", err)
+		return
+	}
+	fmt.Fprint(fb.out, string(d))
+}
@@ -0,0 +1,58 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package feedback
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestYAMLFormat(t *testing.T) {
+	out := new(bytes.Buffer)
+	fb := New(out, out, YAML)
+
+	// The fields are printed with the JSON names, in the same order
+	fb.PrintResult(&testLibraryResult{&testLibrary{
+		Name:     "Servo",
+		Version:  "1.1.8",
+		Authors:  []string{"Arduino"},
+		Releases: []*testRelease{{Version: "1.1.8", Size: 1024, Rating: 4.5}},
+	}})
+	require.Equal(t, `name: Servo
+version: 1.1.8
+authors:
+- Arduino
+releases:
+- version: 1.1.8
+  size: 1024
+  rating: 4.5
+extra: null
+`, out.String())
+
+	// The events form a sequence
+	out.Reset()
+	fb.PrintEvent(&testLibraryResult{&testRelease{Version: "1.1.8"}})
+	fb.PrintEvent(&testLibraryResult{&testRelease{Version: "1.1.7"}})
+	require.Equal(t, `- version: 1.1.8
+  size: 0
+  rating: 0
+- version: 1.1.7
+  size: 0
+  rating: 0
+`, out.String())
+}
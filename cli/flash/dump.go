@@ -0,0 +1,101 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package flash
+
+import (
+	"context"
+	"os"
+
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/arduino-cli/cli/instance"
+	"github.com/arduino/arduino-cli/cli/output"
+	"github.com/arduino/arduino-cli/commands/upload"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/spf13/cobra"
+)
+
+var dumpFlags struct {
+	fqbn       string
+	port       string
+	programmer string
+	verbose    bool
+	output     string
+	offset     uint32
+	size       uint32
+}
+
+func initDumpCommand() *cobra.Command {
+	dumpCommand := &cobra.Command{
+		Use:   "dump",
+		Short: "Saves the content of the flash into a file.",
+		Long:  "Saves the content of the flash of the board into a file, e.g. to back up a device before reflashing it.",
+		Example: "" +
+			"  " + os.Args[0] + " flash dump -b arduino:avr:uno -p /dev/ttyACM0 --output fw.bin\n" +
+			"  " + os.Args[0] + " flash dump -b esp32:esp32:esp32 -p /dev/ttyUSB0 --offset 0x10000 --size 0x100000 --output app.bin",
+		Args: cobra.NoArgs,
+		Run:  runDumpCommand,
+	}
+	dumpCommand.Flags().StringVarP(&dumpFlags.fqbn, "fqbn", "b", "", "Fully Qualified Board Name, e.g.: arduino:avr:uno")
+	dumpCommand.Flags().StringVarP(&dumpFlags.port, "port", "p", "", "Port of the board or of the programmer, e.g.: COM10 or /dev/ttyACM0")
+	dumpCommand.Flags().StringVarP(&dumpFlags.programmer, "programmer", "P", "", "Optional, use the specified programmer to read the flash.")
+	dumpCommand.Flags().BoolVarP(&dumpFlags.verbose, "verbose", "v", false, "Optional, turns on verbose mode.")
+	dumpCommand.Flags().StringVarP(&dumpFlags.output, "output", "o", "", "The file where the content of the flash is saved.")
+	dumpCommand.Flags().Uint32Var(&dumpFlags.offset, "offset", 0, "Optional, the address to start reading from.")
+	dumpCommand.Flags().Uint32Var(&dumpFlags.size, "size", 0, "Optional, the number of bytes to read (default is up to the end of the flash).")
+	dumpCommand.MarkFlagRequired("fqbn")
+	dumpCommand.MarkFlagRequired("output")
+	return dumpCommand
+}
+
+func runDumpCommand(cmd *cobra.Command, args []string) {
+	instance := instance.CreateAndInit()
+
+	toolOutput := output.NewToolOutput()
+	res, err := upload.MemoryRead(context.Background(), &rpc.MemoryReadRequest{
+		Instance:   instance,
+		Fqbn:       dumpFlags.fqbn,
+		Port:       dumpFlags.port,
+		Programmer: dumpFlags.programmer,
+		Verbose:    dumpFlags.verbose,
+		Memory:     "flash",
+		OutputPath: dumpFlags.output,
+		Offset:     dumpFlags.offset,
+		Size:       dumpFlags.size,
+	}, toolOutput.Out, toolOutput.Err)
+	toolOutput.Flush()
+	if output.OutputFormat == "json" {
+		toolOut, toolErr := toolOutput.Buffered()
+		result := &output.MemoryResult{
+			Event:   "result",
+			ToolOut: toolOut,
+			ToolErr: toolErr,
+			Path:    dumpFlags.output,
+			Size:    res.GetSize(),
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		feedback.PrintEvent(result)
+	}
+	if err != nil {
+		feedback.Errorf("Error reading the flash: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+	if output.OutputFormat != "json" {
+		feedback.Printf("Saved %s of flash to %s", output.FormatSize(int64(res.GetSize())), dumpFlags.output)
+	}
+}
@@ -0,0 +1,64 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package fs
+
+import (
+	"context"
+	"os"
+
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/arduino-cli/cli/instance"
+	"github.com/arduino/arduino-cli/commands/fs"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func initBuildCommand() *cobra.Command {
+	buildCommand := &cobra.Command{
+		Use:   "build [sketchPath]",
+		Short: "Builds the filesystem image of a sketch.",
+		Long:  "Packs the data folder of the sketch into a filesystem image, sized for the filesystem partition of the board.",
+		Example: "" +
+			"  " + os.Args[0] + " fs build -b esp32:esp32:esp32 /home/user/Arduino/WebServer\n" +
+			"  " + os.Args[0] + " fs build -b esp32:esp32:esp32 --fs fatfs -o /tmp/data.bin /home/user/Arduino/WebServer",
+		Args: cobra.MaximumNArgs(1),
+		Run:  runBuildCommand,
+	}
+	addCommonFlags(buildCommand)
+	return buildCommand
+}
+
+func runBuildCommand(cmd *cobra.Command, args []string) {
+	inst := instance.CreateAndInit()
+	logrus.Info("Executing `arduino fs build`")
+
+	req := initRequest(inst, args)
+	res, err := fs.Build(context.Background(), &rpc.FilesystemBuildRequest{
+		Instance:   req.GetInstance(),
+		Fqbn:       req.GetFqbn(),
+		SketchPath: req.GetSketchPath(),
+		Type:       req.GetType(),
+		OutputPath: req.GetOutputPath(),
+		Verbose:    req.GetVerbose(),
+	}, feedback.OutputWriter(), feedback.ErrorWriter())
+	if err != nil {
+		feedback.Errorf("Error building filesystem image: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+	feedback.PrintResult(fsResult{image: res.GetImage()})
+}
@@ -0,0 +1,124 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package fs
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/arduino/arduino-cli/arduino/sketches"
+	"github.com/arduino/arduino-cli/cli/board"
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/arduino-cli/commands/fs"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
+	"github.com/spf13/cobra"
+)
+
+// NewCommand created a new `fs` command
+func NewCommand() *cobra.Command {
+	fsCommand := &cobra.Command{
+		Use:   "fs",
+		Short: "Filesystem image commands.",
+		Long:  "Build the filesystem image of the data folder of a sketch (LittleFS, SPIFFS or FatFS) and flash it on the board.",
+		Example: "  # Build a LittleFS image of the data folder.\n" +
+			"  " + os.Args[0] + " fs build -b esp8266:esp8266:nodemcuv2 /home/user/Arduino/WebServer\n\n" +
+			"  # Build and upload it.\n" +
+			"  " + os.Args[0] + " fs upload -b esp8266:esp8266:nodemcuv2 -p /dev/ttyUSB0 /home/user/Arduino/WebServer",
+	}
+
+	fsCommand.AddCommand(initBuildCommand())
+	fsCommand.AddCommand(initUploadCommand())
+
+	return fsCommand
+}
+
+var fsFlags struct {
+	fqbn    string
+	port    string
+	fsType  string
+	output  string
+	verbose bool
+}
+
+func addCommonFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&fsFlags.fqbn, "fqbn", "b", "", "Fully Qualified Board Name, e.g.: esp8266:esp8266:nodemcuv2")
+	cmd.Flags().StringVar(&fsFlags.fsType, "fs", "", "Filesystem type: littlefs, spiffs or fatfs. By default the one selected by the board, or littlefs.")
+	cmd.Flags().StringVarP(&fsFlags.output, "output", "o", "", "Save the filesystem image to this file.")
+	cmd.Flags().BoolVarP(&fsFlags.verbose, "verbose", "v", false, "Print the commands executed.")
+}
+
+// initRequest returns the request for the sketch in the given args, the
+// board is taken from the flags, the sketch metadata or detected
+func initRequest(inst *rpc.Instance, args []string) *rpc.FilesystemUploadRequest {
+	sketchPath := paths.New(".")
+	if len(args) > 0 {
+		sketchPath = paths.New(args[0])
+	}
+
+	if fsFlags.fsType != "" && !isValidType(fsFlags.fsType) {
+		feedback.Errorf("Invalid filesystem type: %s", fsFlags.fsType)
+		os.Exit(errorcodes.ErrBadArgument)
+	}
+
+	board.Autodetect(inst, sketchPath, &fsFlags.fqbn, &fsFlags.port)
+	if sketch, err := sketches.NewSketchFromPath(sketchPath); err == nil && sketch.Metadata != nil {
+		if fsFlags.fqbn == "" {
+			fsFlags.fqbn = sketch.Metadata.CPU.Fqbn
+		}
+		if deviceURI, err := url.Parse(sketch.Metadata.CPU.Port); fsFlags.port == "" && err == nil && deviceURI.Scheme == "serial" {
+			fsFlags.port = deviceURI.Host + deviceURI.Path
+		}
+	}
+
+	return &rpc.FilesystemUploadRequest{
+		Instance:   inst,
+		Fqbn:       fsFlags.fqbn,
+		SketchPath: sketchPath.String(),
+		Type:       fsFlags.fsType,
+		OutputPath: fsFlags.output,
+		Verbose:    fsFlags.verbose,
+		Port:       fsFlags.port,
+	}
+}
+
+func isValidType(fsType string) bool {
+	for _, t := range fs.Types {
+		if t == fsType {
+			return true
+		}
+	}
+	return false
+}
+
+type fsResult struct {
+	image    *rpc.FilesystemImage
+	uploaded bool
+}
+
+func (r fsResult) Data() interface{} {
+	return r.image
+}
+
+func (r fsResult) String() string {
+	msg := "Filesystem image (" + r.image.GetType() + ") saved to: " + r.image.GetPath()
+	if r.uploaded {
+		msg += "\nFilesystem image uploaded at offset " + fmt.Sprintf("0x%X", r.image.GetOffset())
+	}
+	return msg
+}
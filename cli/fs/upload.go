@@ -0,0 +1,56 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package fs
+
+import (
+	"context"
+	"os"
+
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/arduino-cli/cli/instance"
+	"github.com/arduino/arduino-cli/commands/fs"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func initUploadCommand() *cobra.Command {
+	uploadCommand := &cobra.Command{
+		Use:   "upload [sketchPath]",
+		Short: "Builds and uploads the filesystem image of a sketch.",
+		Long:  "Packs the data folder of the sketch into a filesystem image and flashes it at the offset of the filesystem partition of the board.",
+		Example: "" +
+			"  " + os.Args[0] + " fs upload -b esp8266:esp8266:nodemcuv2 -p /dev/ttyUSB0 /home/user/Arduino/WebServer",
+		Args: cobra.MaximumNArgs(1),
+		Run:  runUploadCommand,
+	}
+	addCommonFlags(uploadCommand)
+	uploadCommand.Flags().StringVarP(&fsFlags.port, "port", "p", "", "Upload port, e.g.: COM10 or /dev/ttyUSB0")
+	return uploadCommand
+}
+
+func runUploadCommand(cmd *cobra.Command, args []string) {
+	inst := instance.CreateAndInit()
+	logrus.Info("Executing `arduino fs upload`")
+
+	req := initRequest(inst, args)
+	res, err := fs.Upload(context.Background(), req, feedback.OutputWriter(), feedback.ErrorWriter())
+	if err != nil {
+		feedback.Errorf("Error uploading filesystem image: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+	feedback.PrintResult(fsResult{image: res.GetImage(), uploaded: true})
+}
@@ -0,0 +1,39 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package fuses
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// NewCommand created a new `fuses` command
+func NewCommand() *cobra.Command {
+	fusesCommand := &cobra.Command{
+		Use:   "fuses",
+		Short: "Reads and writes the fuses of the board.",
+		Long:  "Reads and writes the fuses of the board using an external programmer. The values of the fuses are the ones defined by the board, supported by the AVR boards programmed with avrdude.",
+		Example: "" +
+			"  " + os.Args[0] + " fuses read -b arduino:avr:uno -P usbasp\n" +
+			"  " + os.Args[0] + " fuses write -b arduino:avr:uno -P usbasp",
+	}
+
+	fusesCommand.AddCommand(initReadCommand())
+	fusesCommand.AddCommand(initWriteCommand())
+
+	return fusesCommand
+}
@@ -0,0 +1,102 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package fuses
+
+import (
+	"context"
+	"os"
+
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/arduino-cli/cli/instance"
+	"github.com/arduino/arduino-cli/cli/output"
+	"github.com/arduino/arduino-cli/commands/upload"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/spf13/cobra"
+)
+
+var readFlags struct {
+	fqbn       string
+	port       string
+	verbose    bool
+	programmer string
+}
+
+func initReadCommand() *cobra.Command {
+	readCommand := &cobra.Command{
+		Use:     "read",
+		Short:   "Reads the fuses of the board.",
+		Long:    "Reads the fuses and the lock bits of the board and compares them with the values defined by the board.",
+		Example: "  " + os.Args[0] + " fuses read -b arduino:avr:uno -P usbasp",
+		Args:    cobra.NoArgs,
+		Run:     runReadCommand,
+	}
+	readCommand.Flags().StringVarP(&readFlags.fqbn, "fqbn", "b", "", "Fully Qualified Board Name, e.g.: arduino:avr:uno")
+	readCommand.Flags().StringVarP(&readFlags.port, "port", "p", "", "Port of the programmer, e.g.: COM10 or /dev/ttyACM0")
+	readCommand.Flags().BoolVarP(&readFlags.verbose, "verbose", "v", false, "Turns on verbose mode.")
+	readCommand.Flags().StringVarP(&readFlags.programmer, "programmer", "P", "", "Use the specified programmer to read the fuses.")
+	return readCommand
+}
+
+func runReadCommand(cmd *cobra.Command, args []string) {
+	instance := instance.CreateAndInit()
+
+	toolOutput := output.NewToolOutput()
+	res, err := upload.FusesRead(context.Background(), &rpc.FusesReadRequest{
+		Instance:   instance,
+		Fqbn:       readFlags.fqbn,
+		Port:       readFlags.port,
+		Verbose:    readFlags.verbose,
+		Programmer: readFlags.programmer,
+	}, toolOutput.Out, toolOutput.Err)
+	toolOutput.Flush()
+	if output.OutputFormat == "json" {
+		toolOut, toolErr := toolOutput.Buffered()
+		result := &fusesResult{
+			Event:   "result",
+			ToolOut: toolOut,
+			ToolErr: toolErr,
+			Fuses:   res.GetFuses(),
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		feedback.PrintEvent(result)
+	}
+	if err != nil {
+		feedback.Errorf("Error reading fuses: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+	if output.OutputFormat != "json" {
+		feedback.Print(output.FormatFuses(res.GetFuses()))
+	}
+}
+
+type fusesResult struct {
+	Event   string           `json:"event"`
+	ToolOut string           `json:"upload_out,omitempty"`
+	ToolErr string           `json:"upload_err,omitempty"`
+	Fuses   []*rpc.FuseValue `json:"fuses,omitempty"`
+	Error   string           `json:"error,omitempty"`
+}
+
+func (r *fusesResult) Data() interface{} {
+	return r
+}
+
+func (r *fusesResult) String() string {
+	return ""
+}
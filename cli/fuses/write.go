@@ -0,0 +1,91 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package fuses
+
+import (
+	"context"
+	"os"
+
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/arduino-cli/cli/instance"
+	"github.com/arduino/arduino-cli/cli/output"
+	"github.com/arduino/arduino-cli/commands/upload"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/spf13/cobra"
+)
+
+var writeFlags struct {
+	fqbn       string
+	port       string
+	verbose    bool
+	verify     bool
+	programmer string
+}
+
+func initWriteCommand() *cobra.Command {
+	writeCommand := &cobra.Command{
+		Use:   "write",
+		Short: "Writes the fuses of the board.",
+		Long: "Writes the fuses defined by the board, without erasing the chip and writing the bootloader. " +
+			"The lock bits are not written, since they can be cleared only by erasing the chip: use burn-bootloader for that.",
+		Example: "  " + os.Args[0] + " fuses write -b arduino:avr:uno -P usbasp",
+		Args:    cobra.NoArgs,
+		Run:     runWriteCommand,
+	}
+	writeCommand.Flags().StringVarP(&writeFlags.fqbn, "fqbn", "b", "", "Fully Qualified Board Name, e.g.: arduino:avr:uno")
+	writeCommand.Flags().StringVarP(&writeFlags.port, "port", "p", "", "Port of the programmer, e.g.: COM10 or /dev/ttyACM0")
+	writeCommand.Flags().BoolVarP(&writeFlags.verify, "verify", "t", false, "Verify the fuses after writing them.")
+	writeCommand.Flags().BoolVarP(&writeFlags.verbose, "verbose", "v", false, "Turns on verbose mode.")
+	writeCommand.Flags().StringVarP(&writeFlags.programmer, "programmer", "P", "", "Use the specified programmer to write the fuses.")
+	return writeCommand
+}
+
+func runWriteCommand(cmd *cobra.Command, args []string) {
+	instance := instance.CreateAndInit()
+
+	toolOutput := output.NewToolOutput()
+	res, err := upload.BurnBootloader(context.Background(), &rpc.BurnBootloaderRequest{
+		Instance:   instance,
+		Fqbn:       writeFlags.fqbn,
+		Port:       writeFlags.port,
+		Verbose:    writeFlags.verbose,
+		Verify:     writeFlags.verify,
+		Programmer: writeFlags.programmer,
+		FusesOnly:  true,
+	}, toolOutput.Out, toolOutput.Err)
+	toolOutput.Flush()
+	if output.OutputFormat == "json" {
+		toolOut, toolErr := toolOutput.Buffered()
+		result := &fusesResult{
+			Event:   "result",
+			ToolOut: toolOut,
+			ToolErr: toolErr,
+			Fuses:   res.GetFuses(),
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		feedback.PrintEvent(result)
+	}
+	if err != nil {
+		feedback.Errorf("Error writing fuses: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+	if output.OutputFormat != "json" {
+		feedback.Print("Programmed fuses:\n" + output.FormatFuses(res.GetFuses()))
+	}
+}
@@ -0,0 +1,159 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package ide
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/arduino/arduino-cli/arduino/builder"
+	"github.com/arduino/arduino-cli/arduino/ide"
+	"github.com/arduino/arduino-cli/arduino/sketches"
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/arduino-cli/cli/instance"
+	"github.com/arduino/arduino-cli/commands/debug"
+	dbg "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/debug/v1"
+	"github.com/arduino/go-paths-helper"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+)
+
+var exportFlags struct {
+	fqbn       string
+	programmer string
+	importDir  string
+}
+
+func initExportCommand() *cobra.Command {
+	exportCommand := &cobra.Command{
+		Use:   "export vscode [<sketchPath>]",
+		Short: "Generates the IDE configuration of a sketch.",
+		Long: "Generates the VS Code configuration of a sketch: the cortex-debug launch configuration in .vscode/launch.json, " +
+			"the IntelliSense configuration in .vscode/c_cpp_properties.json and the clangd configuration in .clangd. " +
+			"The configuration is derived from the debug properties of the board and from the compile flags of the last build, so the sketch must be compiled first. " +
+			"Existing configurations with a different name are preserved.",
+		Example:   "  " + os.Args[0] + " ide export vscode -b arduino:samd:mkr1000 -P atmel_ice /home/user/Arduino/MySketch",
+		Args:      cobra.RangeArgs(1, 2),
+		ValidArgs: []string{"vscode"},
+		Run:       runExportCommand,
+	}
+	exportCommand.Flags().StringVarP(&exportFlags.fqbn, "fqbn", "b", "", "Fully Qualified Board Name, e.g.: arduino:avr:uno")
+	exportCommand.Flags().StringVarP(&exportFlags.programmer, "programmer", "P", "", "Programmer to use for debugging")
+	exportCommand.Flags().StringVarP(&exportFlags.importDir, "input-dir", "", "", "Directory containing the build of the sketch.")
+	return exportCommand
+}
+
+func runExportCommand(cmd *cobra.Command, args []string) {
+	logrus.Info("Executing `arduino ide export`")
+	if args[0] != "vscode" {
+		feedback.Errorf("Unsupported IDE: %s", args[0])
+		os.Exit(errorcodes.ErrBadArgument)
+	}
+
+	var sketchPath *paths.Path
+	if len(args) > 1 {
+		sketchPath = paths.New(args[1])
+	} else {
+		wd, err := paths.Getwd()
+		if err != nil {
+			feedback.Errorf("Couldn't get current working directory: %v", err)
+			os.Exit(errorcodes.ErrGeneric)
+		}
+		sketchPath = wd
+	}
+	sketch, err := sketches.NewSketchFromPath(sketchPath)
+	if err != nil {
+		feedback.Errorf("Error opening sketch: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+	buildPath := paths.New(exportFlags.importDir)
+	if exportFlags.importDir == "" {
+		if buildPath, err = sketch.BuildPath(); err != nil {
+			feedback.Errorf("Error getting build path: %v", err)
+			os.Exit(errorcodes.ErrGeneric)
+		}
+	}
+	name := "Arduino: " + sketch.Name
+	written := []string{}
+
+	// The debug configuration is not available for all the boards
+	inst := instance.CreateAndInit()
+	debugInfo, err := debug.GetDebugConfig(context.Background(), &dbg.DebugConfigRequest{
+		Instance:   inst,
+		Fqbn:       exportFlags.fqbn,
+		SketchPath: sketch.FullPath.String(),
+		ImportDir:  buildPath.String(),
+		Programmer: exportFlags.programmer,
+	})
+	if err != nil {
+		if s, ok := status.FromError(err); ok {
+			err = errors.New(s.Message())
+		}
+		feedback.Errorf("Debug configuration not exported: %v", err)
+	} else if launch, err := ide.NewCortexDebugConfiguration(name, debugInfo); err != nil {
+		feedback.Errorf("Debug configuration not exported: %v", err)
+	} else {
+		launchFile := sketch.FullPath.Join(".vscode", "launch.json")
+		if err := ide.UpdateLaunchFile(launchFile, launch); err != nil {
+			feedback.Errorf("Error writing launch.json: %v", err)
+			os.Exit(errorcodes.ErrGeneric)
+		}
+		written = append(written, launchFile.String())
+	}
+
+	db, err := builder.LoadCompilationDatabase(buildPath.Join("compile_commands.json"))
+	if err != nil {
+		feedback.Errorf("IntelliSense configuration not exported, compile the sketch first: %v", err)
+	} else if cpp, err := ide.NewCppConfiguration(name, db); err != nil {
+		feedback.Errorf("IntelliSense configuration not exported: %v", err)
+	} else {
+		cppFile := sketch.FullPath.Join(".vscode", "c_cpp_properties.json")
+		if err := ide.UpdateCppPropertiesFile(cppFile, cpp); err != nil {
+			feedback.Errorf("Error writing c_cpp_properties.json: %v", err)
+			os.Exit(errorcodes.ErrGeneric)
+		}
+		clangdFile := sketch.FullPath.Join(".clangd")
+		if err := clangdFile.WriteFile(ide.ClangdConfig(db)); err != nil {
+			feedback.Errorf("Error writing .clangd: %v", err)
+			os.Exit(errorcodes.ErrGeneric)
+		}
+		written = append(written, cppFile.String(), clangdFile.String())
+	}
+
+	if len(written) == 0 {
+		os.Exit(errorcodes.ErrGeneric)
+	}
+	feedback.PrintResult(exportResult{Files: written})
+}
+
+type exportResult struct {
+	Files []string `json:"files"`
+}
+
+func (r exportResult) Data() interface{} {
+	return r
+}
+
+func (r exportResult) String() string {
+	res := "Configuration written to:"
+	for _, file := range r.Files {
+		res += "\n  " + file
+	}
+	return res
+}
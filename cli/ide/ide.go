@@ -0,0 +1,37 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package ide
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// NewCommand created a new `ide` command
+func NewCommand() *cobra.Command {
+	ideCommand := &cobra.Command{
+		Use:   "ide",
+		Short: "Arduino IDE integration commands.",
+		Long:  "Arduino IDE integration commands.",
+		Example: "# Generate the VS Code configuration of a sketch.\n" +
+			" " + os.Args[0] + " ide export vscode -b arduino:samd:mkr1000 /home/user/Arduino/MySketch\n\n",
+	}
+
+	ideCommand.AddCommand(initExportCommand())
+
+	return ideCommand
+}
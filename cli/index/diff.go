@@ -0,0 +1,106 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package index
+
+import (
+	"os"
+	"strings"
+
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/arduino-cli/cli/instance"
+	"github.com/arduino/arduino-cli/commands"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/arduino-cli/table"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func initDiffCommand() *cobra.Command {
+	diffCommand := &cobra.Command{
+		Use:   "diff",
+		Short: "Shows the changes of the indexes made by the last update.",
+		Long: "Shows the platforms and libraries added to the indexes by the last update, and the new versions of the installed ones. " +
+			"The indexes are compared with the copies saved before the last `update`, `core update-index` or `lib update-index`.",
+		Example: "" +
+			"  " + os.Args[0] + " index diff\n" +
+			"  " + os.Args[0] + " index diff --all",
+		Args: cobra.NoArgs,
+		Run:  runDiffCommand,
+	}
+	diffCommand.Flags().BoolVar(&diffFlags.all, "all", false, "List the new versions of all the platforms and libraries, not only the installed ones.")
+	return diffCommand
+}
+
+var diffFlags struct {
+	all bool
+}
+
+func runDiffCommand(cmd *cobra.Command, args []string) {
+	logrus.Info("Executing `arduino index diff`")
+	inst := instance.CreateAndInit()
+	PrintDiff(inst, diffFlags.all)
+}
+
+// PrintDiff prints the changes of the indexes made by the last update, the
+// instance must be initialized
+func PrintDiff(inst *rpc.Instance, all bool) {
+	changes, err := commands.IndexDiff(commands.GetPackageManager(inst.GetId()), commands.GetLibraryManager(inst.GetId()), all)
+	if err != nil {
+		feedback.Errorf("Error comparing the indexes: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+	feedback.PrintResult(&diffResult{changes})
+}
+
+type diffResult struct {
+	changes *commands.IndexChanges
+}
+
+func (r *diffResult) Data() interface{} {
+	return r.changes
+}
+
+func (r *diffResult) String() string {
+	sections := []string{}
+	addSection := func(title string, changes []*commands.IndexChange, updated bool) {
+		if len(changes) == 0 {
+			return
+		}
+		t := table.New()
+		if updated {
+			t.SetHeader("ID", "Installed", "Previous", "Latest", "Name")
+		} else {
+			t.SetHeader("ID", "Version", "Name")
+		}
+		for _, change := range changes {
+			if updated {
+				t.AddRow(change.ID, change.Installed, change.PreviousVersion, change.Version, change.Name)
+			} else {
+				t.AddRow(change.ID, change.Version, change.Name)
+			}
+		}
+		sections = append(sections, title+":\n"+t.Render())
+	}
+	addSection("New platforms", r.changes.NewPlatforms, false)
+	addSection("Updated platforms", r.changes.UpdatedPlatforms, true)
+	addSection("New libraries", r.changes.NewLibraries, false)
+	addSection("Updated libraries", r.changes.UpdatedLibraries, true)
+	if len(sections) == 0 {
+		return "No changes in the indexes since the previous update."
+	}
+	return strings.TrimSpace(strings.Join(sections, "\n"))
+}
@@ -0,0 +1,37 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package index
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// NewCommand created a new `index` command
+func NewCommand() *cobra.Command {
+	indexCommand := &cobra.Command{
+		Use:   "index",
+		Short: "Arduino index commands.",
+		Long:  "Arduino commands about the package and library indexes.",
+		Example: "# Show the changes of the indexes made by the last update.\n" +
+			" " + os.Args[0] + " index diff\n\n",
+	}
+
+	indexCommand.AddCommand(initDiffCommand())
+
+	return indexCommand
+}
@@ -0,0 +1,142 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package instance
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/arduino/arduino-cli/arduino/cores/packageindex"
+	"github.com/arduino/arduino-cli/arduino/utils"
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/arduino-cli/cli/globals"
+	"github.com/arduino/arduino-cli/cli/output"
+	"github.com/arduino/arduino-cli/commands"
+	"github.com/arduino/arduino-cli/configuration"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var indexUpdateFlags struct {
+	updateIndex   bool
+	noUpdateIndex bool
+}
+
+// AddIndexUpdateFlagsToCommand adds the flags that can be used to force or
+// skip the update of the indexes, regardless of the updater.index_ttl setting
+func AddIndexUpdateFlagsToCommand(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&indexUpdateFlags.updateIndex, "update-index", false, "Update the indexes before running the command.")
+	cmd.Flags().BoolVar(&indexUpdateFlags.noUpdateIndex, "no-update-index", false, "Don't update the indexes before running the command.")
+}
+
+// UpdateIndexesIfNeeded updates the package indexes and/or the library index
+// if they are older than the updater.index_ttl setting, or if requested with
+// the --update-index flag. The CLI exits if the update fails.
+func UpdateIndexesIfNeeded(inst *rpc.Instance, packageIndexes, libraryIndex bool) {
+	if indexUpdateFlags.updateIndex && indexUpdateFlags.noUpdateIndex {
+		feedback.Errorf("The flags --update-index and --no-update-index can't be both set at the same time.")
+		os.Exit(errorcodes.ErrBadArgument)
+	}
+	if indexUpdateFlags.noUpdateIndex {
+		logrus.Info("Skipping index update by user request")
+		return
+	}
+	ttl, err := IndexTTL()
+	if err != nil {
+		feedback.Error(err)
+		os.Exit(errorcodes.ErrBadArgument)
+	}
+	if ttl < 0 && !indexUpdateFlags.updateIndex {
+		return
+	}
+	force := indexUpdateFlags.updateIndex || ttl == 0
+	dataDir := paths.New(configuration.Settings.GetString("directories.Data"))
+
+	if packageIndexes && (force || packageIndexesNeedUpdating(dataDir, ttl)) {
+		_, err := commands.UpdateIndex(context.Background(), &rpc.UpdateIndexRequest{
+			Instance: inst,
+		}, output.ProgressBar())
+		if err != nil {
+			feedback.Errorf("Error updating index: %v", err)
+			os.Exit(errorcodes.ErrGeneric)
+		}
+	}
+	if libraryIndex && (force || indexNeedsUpdating(dataDir.Join("library_index.json"), ttl)) {
+		err := commands.UpdateLibrariesIndex(context.Background(), &rpc.UpdateLibrariesIndexRequest{
+			Instance: inst,
+		}, output.ProgressBar())
+		if err != nil {
+			feedback.Errorf("Error updating library index: %v", err)
+			os.Exit(errorcodes.ErrGeneric)
+		}
+	}
+}
+
+// IndexTTL returns the time after which the indexes are automatically updated
+// by the commands that use them, as set in updater.index_ttl: 0 means always,
+// a negative value means never.
+func IndexTTL() (time.Duration, error) {
+	value := configuration.Settings.GetString("updater.index_ttl")
+	switch value {
+	case "always":
+		return 0, nil
+	case "never":
+		return -1, nil
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl < 0 {
+		return 0, fmt.Errorf("Invalid updater.index_ttl '%s': must be a duration, always or never", value)
+	}
+	return ttl, nil
+}
+
+// packageIndexesNeedUpdating returns whether one or more package index files
+// need updating
+func packageIndexesNeedUpdating(dataDir *paths.Path, ttl time.Duration) bool {
+	urls := []string{globals.DefaultIndexURL}
+	urls = append(urls, configuration.Settings.GetStringSlice("board_manager.additional_urls")...)
+	for _, u := range urls {
+		URL, err := utils.URLParse(u)
+		if err != nil {
+			continue
+		}
+
+		if URL.Scheme == "file" {
+			// No need to update local files
+			continue
+		}
+
+		if indexNeedsUpdating(dataDir.Join(packageindex.IndexFileName(URL)), ttl) {
+			return true
+		}
+	}
+	return false
+}
+
+// indexNeedsUpdating returns whether the index file is missing or older than
+// the ttl
+func indexNeedsUpdating(index *paths.Path, ttl time.Duration) bool {
+	info, err := index.Stat()
+	if err != nil {
+		return true
+	}
+	return time.Now().After(info.ModTime().Add(ttl))
+}
@@ -0,0 +1,207 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package lib
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/arduino/arduino-cli/arduino/libraries"
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/arduino-cli/cli/instance"
+	"github.com/arduino/arduino-cli/cli/output"
+	"github.com/arduino/arduino-cli/commands/compile"
+	"github.com/arduino/arduino-cli/commands/lib"
+	"github.com/arduino/arduino-cli/junit"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/arduino-cli/table"
+	"github.com/arduino/go-paths-helper"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func initCompileExamplesCommand() *cobra.Command {
+	compileExamplesCommand := &cobra.Command{
+		Use:   "compile-examples <LIBRARY_NAME|libraryPath> -b <FQBN>",
+		Short: "Compiles all the examples of a library.",
+		Long: "Compiles all the examples of an installed library, or of the library in the given folder, for the given board\n" +
+			"and reports the result of each one. The command fails if any example fails to compile.",
+		Example: "" +
+			"  " + os.Args[0] + " lib compile-examples Servo -b arduino:avr:uno\n" +
+			"  " + os.Args[0] + " lib compile-examples ~/Arduino/libraries/MyLibrary -b arduino:avr:uno --format json",
+		Args: cobra.ExactArgs(1),
+		Run:  runCompileExamplesCommand,
+	}
+	compileExamplesCommand.Flags().StringVarP(&compileExamplesFlags.fqbn, "fqbn", "b", "", "Fully Qualified Board Name, e.g.: arduino:avr:uno")
+	compileExamplesCommand.Flags().StringVar(&compileExamplesFlags.warnings, "warnings", "none",
+		`Optional, can be "none", "default", "more" and "all". Used to tell gcc which warning level to use (-W flag).`)
+	compileExamplesCommand.MarkFlagRequired("fqbn")
+	return compileExamplesCommand
+}
+
+var compileExamplesFlags struct {
+	fqbn     string
+	warnings string
+}
+
+func runCompileExamplesCommand(cmd *cobra.Command, args []string) {
+	inst := instance.CreateAndInit()
+	logrus.Info("Executing `arduino lib compile-examples`")
+
+	libraryName, examples, libraryPath := findLibraryExamples(inst, args[0])
+	if len(examples) == 0 {
+		feedback.Errorf("The library %s has no examples", libraryName)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+
+	res := &compileExamplesResult{
+		Library:  libraryName,
+		Fqbn:     compileExamplesFlags.fqbn,
+		Examples: []*exampleResult{},
+	}
+	for _, example := range examples {
+		if output.OutputFormat != "json" {
+			feedback.Printf("Compiling %s...", example.Base())
+		}
+		req := &rpc.CompileRequest{
+			Instance:   inst,
+			Fqbn:       compileExamplesFlags.fqbn,
+			SketchPath: example.String(),
+			Warnings:   compileExamplesFlags.warnings,
+		}
+		if libraryPath != nil {
+			// The library is not installed, it must be added to the build
+			req.Library = []string{libraryPath.String()}
+		}
+		compileOut := new(bytes.Buffer)
+		compileErr := new(bytes.Buffer)
+		start := time.Now()
+		_, err := compile.Compile(context.Background(), req, compileOut, compileErr, nil, false)
+
+		entry := &exampleResult{Example: example.String(), Success: err == nil, duration: time.Since(start)}
+		if err != nil {
+			entry.Error = err.Error()
+			entry.CompileErr = compileErr.String()
+			if output.OutputFormat != "json" {
+				feedback.Errorf("%s%v", compileErr, err)
+			}
+		}
+		res.Examples = append(res.Examples, entry)
+	}
+
+	feedback.PrintResult(res)
+	for _, example := range res.Examples {
+		if !example.Success {
+			os.Exit(errorcodes.ErrGeneric)
+		}
+	}
+}
+
+// findLibraryExamples returns the name and the examples of the library with
+// the given name or in the given folder. If the library has been loaded from a
+// folder its path is returned too.
+func findLibraryExamples(inst *rpc.Instance, arg string) (string, paths.PathList, *paths.Path) {
+	if libraryPath := paths.New(arg); libraryPath.IsDir() {
+		libraryPath, err := libraryPath.Abs()
+		if err != nil {
+			feedback.Errorf("Invalid library path: %v", err)
+			os.Exit(errorcodes.ErrBadArgument)
+		}
+		library, err := libraries.Load(libraryPath, libraries.User)
+		if err != nil {
+			feedback.Errorf("Error loading library: %v", err)
+			os.Exit(errorcodes.ErrGeneric)
+		}
+		return library.Name, library.Examples, libraryPath
+	}
+
+	libs, err := lib.LibraryList(context.Background(), &rpc.LibraryListRequest{
+		Instance: inst,
+		Name:     arg,
+		Fqbn:     compileExamplesFlags.fqbn,
+	})
+	if err != nil {
+		feedback.Errorf("Error getting libraries info: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+	if len(libs.GetInstalledLibraries()) == 0 {
+		feedback.Errorf("Library %s is not installed", arg)
+		os.Exit(errorcodes.ErrBadArgument)
+	}
+	library := libs.GetInstalledLibraries()[0].GetLibrary()
+	return library.GetName(), paths.NewPathList(library.GetExamples()...), nil
+}
+
+type exampleResult struct {
+	Example    string `json:"example"`
+	Success    bool   `json:"success"`
+	Error      string `json:"error,omitempty"`
+	CompileErr string `json:"compiler_err,omitempty"`
+
+	duration time.Duration
+}
+
+type compileExamplesResult struct {
+	Library  string           `json:"library"`
+	Fqbn     string           `json:"fqbn"`
+	Examples []*exampleResult `json:"examples"`
+}
+
+func (r *compileExamplesResult) Data() interface{} {
+	return r
+}
+
+func (r *compileExamplesResult) String() string {
+	t := table.New()
+	t.SetHeader("Example", "Result")
+	failed := 0
+	for _, example := range r.Examples {
+		result := "ok"
+		if !example.Success {
+			result = "failed"
+			failed++
+		}
+		t.AddRow(paths.New(example.Example).Base(), result)
+	}
+	return t.Render() + fmt.Sprintf("\n%d of %d examples of %s failed to compile for %s", failed, len(r.Examples), r.Library, r.Fqbn)
+}
+
+// JUnit returns a report with a test suite for the library and a test case
+// for each example
+func (r *compileExamplesResult) JUnit() *junit.TestSuites {
+	suite := &junit.TestSuite{Name: r.Library + " (" + r.Fqbn + ")"}
+	total := time.Duration(0)
+	for _, example := range r.Examples {
+		c := &junit.TestCase{
+			Name:      paths.New(example.Example).Base(),
+			ClassName: r.Library,
+			Time:      junit.Seconds(example.duration),
+		}
+		if !example.Success {
+			c.Failure = &junit.Message{Message: example.Error, Details: example.CompileErr}
+		}
+		suite.AddCase(c)
+		total += example.duration
+	}
+	suite.Time = junit.Seconds(total)
+	report := &junit.TestSuites{}
+	report.AddSuite(suite)
+	return report
+}
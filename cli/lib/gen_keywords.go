@@ -0,0 +1,127 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package lib
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/arduino/arduino-cli/arduino/libraries"
+	"github.com/arduino/arduino-cli/arduino/libraries/keywords"
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/arduino-cli/cli/instance"
+	"github.com/arduino/arduino-cli/commands"
+	"github.com/arduino/go-paths-helper"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func initGenKeywordsCommand() *cobra.Command {
+	genKeywordsCommand := &cobra.Command{
+		Use:   "gen-keywords <libraryPath>",
+		Short: "Generates the keywords.txt file of a library.",
+		Long:  "Generates or updates the keywords.txt file of a library parsing its public headers. The content of an existing keywords.txt, including comments and keywords commented out, is preserved and only the missing keywords are added.",
+		Example: "" +
+			"  " + os.Args[0] + " lib gen-keywords ~/Arduino/libraries/MyLibrary\n" +
+			"  " + os.Args[0] + " lib gen-keywords ~/Arduino/libraries/MyLibrary --export-syntax json\n" +
+			"  " + os.Args[0] + " lib gen-keywords ~/Arduino/libraries/MyLibrary --parser ctags",
+		Args: cobra.ExactArgs(1),
+		Run:  runGenKeywordsCommand,
+	}
+	genKeywordsCommand.Flags().StringVar(&genKeywordsFlags.exportSyntax, "export-syntax", "", "Also print the metadata of the symbols found in the given format (json).")
+	genKeywordsCommand.Flags().StringVar(&genKeywordsFlags.parser, "parser", "builtin", "The parser used to read the headers: builtin or ctags (the ctags bundled with the CLI).")
+	return genKeywordsCommand
+}
+
+var genKeywordsFlags struct {
+	exportSyntax string
+	parser       string
+}
+
+func runGenKeywordsCommand(cmd *cobra.Command, args []string) {
+	logrus.Info("Executing `arduino lib gen-keywords`")
+
+	if genKeywordsFlags.exportSyntax != "" && genKeywordsFlags.exportSyntax != "json" {
+		feedback.Errorf("Invalid export format: %s", genKeywordsFlags.exportSyntax)
+		os.Exit(errorcodes.ErrBadArgument)
+	}
+	if genKeywordsFlags.parser != "builtin" && genKeywordsFlags.parser != "ctags" {
+		feedback.Errorf("Invalid parser: %s", genKeywordsFlags.parser)
+		os.Exit(errorcodes.ErrBadArgument)
+	}
+
+	libraryPath, err := paths.New(args[0]).Abs()
+	if err != nil {
+		feedback.Errorf("Invalid library path: %v", err)
+		os.Exit(errorcodes.ErrBadArgument)
+	}
+	library, err := libraries.Load(libraryPath, libraries.User)
+	if err != nil {
+		feedback.Errorf("Error loading library: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+
+	var symbols []*keywords.Symbol
+	if genKeywordsFlags.parser == "ctags" {
+		inst := instance.CreateAndInit()
+		ctags, err := commands.GetCtags(commands.GetPackageManager(inst.GetId()))
+		if err != nil {
+			feedback.Errorf("Error getting ctags: %v", err)
+			os.Exit(errorcodes.ErrGeneric)
+		}
+		symbols, err = keywords.ParseLibraryWithCtags(library, ctags)
+	} else {
+		symbols, err = keywords.ParseLibrary(library)
+	}
+	if err != nil {
+		feedback.Errorf("Error parsing library headers: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+
+	// An existing keywords.txt is kept as-is, only the missing keywords are
+	// added at the end
+	keywordsFile := libraryPath.Join("keywords.txt")
+	var content []byte
+	var added int
+	if keywordsFile.Exist() {
+		data, err := keywordsFile.ReadFile()
+		if err != nil {
+			feedback.Errorf("Error reading keywords.txt: %v", err)
+			os.Exit(errorcodes.ErrGeneric)
+		}
+		content, added = keywords.UpdateKeywordsFile(data, symbols)
+	} else {
+		merged := keywords.MergeSymbols(nil, symbols)
+		content, added = keywords.Format(library.Name, merged), len(merged)
+	}
+	if err := keywordsFile.WriteFile(content); err != nil {
+		feedback.Errorf("Error writing keywords.txt: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+	logrus.Infof("Added %d keywords to %s", added, keywordsFile)
+
+	if genKeywordsFlags.exportSyntax == "json" {
+		d, err := json.MarshalIndent(symbols, "", "  ")
+		if err != nil {
+			feedback.Errorf("Error exporting symbols: %v", err)
+			os.Exit(errorcodes.ErrGeneric)
+		}
+		feedback.Print(string(d))
+		return
+	}
+	feedback.Print("Keywords written to: " + keywordsFile.String())
+}
@@ -41,5 +41,10 @@ func NewCommand() *cobra.Command {
 	libCommand.AddCommand(initUpgradeCommand())
 	libCommand.AddCommand(initUpdateIndexCommand())
 	libCommand.AddCommand(initDepsCommand())
+	libCommand.AddCommand(initGenKeywordsCommand())
+	libCommand.AddCommand(initCompileExamplesCommand())
+	libCommand.AddCommand(initValidateCommand())
+	libCommand.AddCommand(initPackageCommand())
+	libCommand.AddCommand(initLicensesCommand())
 	return libCommand
 }
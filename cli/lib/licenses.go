@@ -0,0 +1,161 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package lib
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/arduino-cli/cli/instance"
+	"github.com/arduino/arduino-cli/commands"
+	"github.com/arduino/arduino-cli/commands/compile"
+	"github.com/arduino/arduino-cli/commands/lib"
+	"github.com/arduino/arduino-cli/configuration"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/arduino-cli/table"
+	paths "github.com/arduino/go-paths-helper"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var licensesFlags struct {
+	sketch string
+	fqbn   string
+	all    bool
+	allow  []string
+}
+
+func initLicensesCommand() *cobra.Command {
+	licensesCommand := &cobra.Command{
+		Use:   "licenses",
+		Short: "Shows the licenses of the installed libraries or of the libraries used by a sketch.",
+		Long: "Shows the license of each installed library, or of each library used by the sketch given with --sketch, " +
+			"taken from the library.properties, from the LICENSE or COPYING file of the library or from the libraries index.\n\n" +
+			"The licenses are checked against the allowlist set with --allow or with the library.allowed_licenses setting: " +
+			"the command fails if a library has a license not in the allowlist or a license that can't be recognized. " +
+			"Use --format json or --format csv to save the report.",
+		Example: "" +
+			"  " + os.Args[0] + " lib licenses\n" +
+			"  " + os.Args[0] + " lib licenses --sketch ~/Arduino/MySketch -b arduino:avr:uno --allow MIT,Apache-2.0,BSD-3-Clause\n" +
+			"  " + os.Args[0] + " lib licenses --all --format csv > licenses.csv",
+		Args: cobra.NoArgs,
+		Run:  runLicensesCommand,
+	}
+	licensesCommand.Flags().StringVar(&licensesFlags.sketch, "sketch", "", "Report the licenses of the libraries used by the sketch in this folder, instead of the installed libraries.")
+	licensesCommand.Flags().StringVarP(&licensesFlags.fqbn, "fqbn", "b", "", "Fully Qualified Board Name used to find the libraries of the sketch, e.g.: arduino:avr:uno. Defaults to the board attached to the sketch.")
+	licensesCommand.Flags().BoolVar(&licensesFlags.all, "all", false, "Include built-in libraries (from platforms and IDE) in the report.")
+	licensesCommand.Flags().StringSliceVar(&licensesFlags.allow, "allow", nil, "The allowed licenses, as SPDX identifiers, overrides the library.allowed_licenses setting.")
+	return licensesCommand
+}
+
+func runLicensesCommand(cmd *cobra.Command, args []string) {
+	inst := instance.CreateAndInit()
+	logrus.Info("Executing `arduino lib licenses`")
+
+	libs := []*rpc.Library{}
+	if licensesFlags.sketch != "" {
+		used, err := sketchLibraries(inst, paths.New(licensesFlags.sketch), licensesFlags.fqbn)
+		if err != nil {
+			feedback.Errorf("Error finding the libraries used by the sketch: %v", err)
+			os.Exit(errorcodes.ErrGeneric)
+		}
+		libs = used
+	} else {
+		res, err := lib.LibraryList(context.Background(), &rpc.LibraryListRequest{
+			Instance: inst,
+			All:      licensesFlags.all,
+		})
+		if err != nil {
+			feedback.Errorf("Error listing Libraries: %v", err)
+			os.Exit(errorcodes.ErrGeneric)
+		}
+		for _, installed := range res.GetInstalledLibraries() {
+			libs = append(libs, installed.GetLibrary())
+		}
+	}
+
+	allowlist := licensesFlags.allow
+	if !cmd.Flags().Changed("allow") && configuration.Settings != nil {
+		allowlist = configuration.Settings.GetStringSlice("library.allowed_licenses")
+	}
+	lm := commands.GetLibraryManager(inst.GetId())
+	if lm == nil {
+		feedback.Errorf("Error listing Libraries: invalid instance")
+		os.Exit(errorcodes.ErrGeneric)
+	}
+	licenses := lib.LibraryLicenses(libs, lm.Index, allowlist)
+	feedback.PrintResult(licensesResult{licenses})
+
+	if len(allowlist) > 0 {
+		for _, license := range licenses {
+			if license.Status != lib.LicenseAllowed {
+				os.Exit(errorcodes.ErrGeneric)
+			}
+		}
+	}
+}
+
+// sketchLibraries returns the libraries used by the sketch when compiled for
+// the given board
+func sketchLibraries(inst *rpc.Instance, sketchPath *paths.Path, fqbn string) ([]*rpc.Library, error) {
+	buildPath, err := paths.MkTempDir("", "arduino-cli-licenses")
+	if err != nil {
+		return nil, err
+	}
+	defer buildPath.RemoveAll()
+	res, err := compile.Compile(context.Background(), &rpc.CompileRequest{
+		Instance:                      inst,
+		Fqbn:                          fqbn,
+		SketchPath:                    sketchPath.String(),
+		BuildPath:                     buildPath.String(),
+		CreateCompilationDatabaseOnly: true,
+	}, ioutil.Discard, ioutil.Discard, nil, false)
+	if err != nil {
+		return nil, err
+	}
+	return res.GetUsedLibraries(), nil
+}
+
+type licensesResult struct {
+	licenses []*lib.LibraryLicense
+}
+
+func (r licensesResult) Data() interface{} {
+	return r.licenses
+}
+
+func (r licensesResult) String() string {
+	if len(r.licenses) == 0 {
+		return "No libraries found."
+	}
+	t := table.New()
+	t.SetHeader("Name", "Version", "Location", "License", "Source", "Status")
+	for _, license := range r.licenses {
+		t.AddRow(license.Name, license.Version, license.Location, license.License, license.Source, license.Status)
+	}
+	return t.Render()
+}
+
+func (r licensesResult) CSV() [][]string {
+	res := [][]string{{"name", "version", "location", "license", "source", "status"}}
+	for _, license := range r.licenses {
+		res = append(res, []string{license.Name, license.Version, license.Location, license.License, license.Source, license.Status})
+	}
+	return res
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/arduino/arduino-cli/cli/errorcodes"
 	"github.com/arduino/arduino-cli/cli/feedback"
 	"github.com/arduino/arduino-cli/cli/instance"
+	"github.com/arduino/arduino-cli/cli/output"
 	"github.com/arduino/arduino-cli/commands/lib"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
 	"github.com/arduino/arduino-cli/table"
@@ -46,6 +47,8 @@ func initListCommand() *cobra.Command {
 	listCommand.Flags().BoolVar(&listFlags.all, "all", false, "Include built-in libraries (from platforms and IDE) in listing.")
 	listCommand.Flags().StringVarP(&listFlags.fqbn, "fqbn", "b", "", "Show libraries for the specified board FQBN.")
 	listCommand.Flags().BoolVar(&listFlags.updatable, "updatable", false, "List updatable libraries.")
+	output.AddTableFlagsToCommand(listCommand, "name", "version", "available", "location", "description",
+		"author", "maintainer", "category", "architectures", "license", "install_dir")
 	return listCommand
 }
 
@@ -114,10 +117,7 @@ func (ir installedResult) String() string {
 		}
 		return "No libraries installed."
 	}
-	sort.Slice(ir.installedLibs, func(i, j int) bool {
-		return strings.ToLower(ir.installedLibs[i].Library.Name) < strings.ToLower(ir.installedLibs[j].Library.Name) ||
-			strings.ToLower(ir.installedLibs[i].Library.ContainerPlatform) < strings.ToLower(ir.installedLibs[j].Library.ContainerPlatform)
-	})
+	ir.sort()
 
 	t := table.New()
 	t.SetHeader("Name", "Installed", "Available", "Location", "Description")
@@ -160,3 +160,29 @@ func (ir installedResult) String() string {
 
 	return t.Render()
 }
+
+func (ir installedResult) sort() {
+	sort.Slice(ir.installedLibs, func(i, j int) bool {
+		return strings.ToLower(ir.installedLibs[i].Library.Name) < strings.ToLower(ir.installedLibs[j].Library.Name) ||
+			strings.ToLower(ir.installedLibs[i].Library.ContainerPlatform) < strings.ToLower(ir.installedLibs[j].Library.ContainerPlatform)
+	})
+}
+
+// Table returns all the data of the libraries, to print the columns selected
+// with --columns
+func (ir installedResult) Table() *feedback.Table {
+	ir.sort()
+	t := &feedback.Table{Columns: []string{"name", "version", "available", "location", "description",
+		"author", "maintainer", "category", "architectures", "license", "install_dir"}}
+	for _, libMeta := range ir.installedLibs {
+		lib := libMeta.GetLibrary()
+		location := lib.GetLocation().String()
+		if lib.GetContainerPlatform() != "" {
+			location = lib.GetContainerPlatform()
+		}
+		t.AddRow(lib.GetName(), lib.GetVersion(), libMeta.GetRelease().GetVersion(), location, lib.GetSentence(),
+			lib.GetAuthor(), lib.GetMaintainer(), lib.GetCategory(), strings.Join(lib.GetArchitectures(), ","),
+			lib.GetLicense(), lib.GetInstallDir())
+	}
+	return t
+}
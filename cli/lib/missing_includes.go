@@ -0,0 +1,90 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package lib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	bldr "github.com/arduino/arduino-cli/arduino/builder"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/arduino-cli/cli/output"
+	"github.com/arduino/arduino-cli/commands/lib"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/sirupsen/logrus"
+)
+
+// InstallMissingIncludes runs the given build until it no longer fails because
+// of a missing #include: each time a header is missing, the library of the
+// index that provides it is installed and the build is run again.
+// It returns the result of the last build and the libraries installed, in
+// the NAME@VERSION form.
+func InstallMissingIncludes(inst *rpc.Instance, build func() (*rpc.CompileResponse, error)) (*rpc.CompileResponse, []string, error) {
+	installed := []string{}
+	tried := map[string]bool{}
+	for {
+		res, err := build()
+		var missing *bldr.MissingIncludeError
+		if err == nil || !errors.As(err, &missing) {
+			return res, installed, err
+		}
+		if tried[missing.Include] {
+			return res, installed, fmt.Errorf("%s is still missing after installing the library that provides it: %w", missing.Include, err)
+		}
+		tried[missing.Include] = true
+
+		library, searchErr := lib.LibraryProvidingInclude(inst, missing.Include)
+		if searchErr != nil {
+			return res, installed, fmt.Errorf("searching library for %s: %w", missing.Include, searchErr)
+		}
+		if library == nil {
+			return res, installed, fmt.Errorf("no library of the libraries index provides %s: %w", missing.Include, err)
+		}
+
+		version := library.GetLatest().GetVersion()
+		logrus.Infof("Installing %s@%s to provide %s", library.GetName(), version, missing.Include)
+		if output.OutputFormat != "json" {
+			feedback.Printf("Installing library %s@%s that provides %s", library.GetName(), version, missing.Include)
+		}
+		installErr := lib.LibraryInstall(context.Background(), &rpc.LibraryInstallRequest{
+			Instance: inst,
+			Name:     library.GetName(),
+			Version:  version,
+		}, output.ProgressBar(), output.TaskProgress())
+		if installErr != nil {
+			return res, installed, fmt.Errorf("installing %s: %w", library.GetName(), installErr)
+		}
+		installed = append(installed, library.GetName()+"@"+version)
+	}
+}
+
+// MissingIncludeHint returns a suggestion to install the library that
+// provides the header if err is caused by a missing #include, or an empty
+// string otherwise.
+func MissingIncludeHint(inst *rpc.Instance, err error) string {
+	var missing *bldr.MissingIncludeError
+	if !errors.As(err, &missing) {
+		return ""
+	}
+	library, searchErr := lib.LibraryProvidingInclude(inst, missing.Include)
+	if searchErr != nil || library == nil {
+		return ""
+	}
+	return fmt.Sprintf("The library %s provides %s, install it with:\n  %s lib install \"%s\"\nor compile with --auto-install-libs",
+		library.GetName(), missing.Include, os.Args[0], library.GetName())
+}
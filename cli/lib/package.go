@@ -0,0 +1,99 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package lib
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/arduino/arduino-cli/arduino/libraries/publish"
+	"github.com/arduino/arduino-cli/arduino/sketches/check"
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/go-paths-helper"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var packageFlags struct {
+	outputDir      string
+	exclude        []string
+	checkIndex     bool
+	skipValidation bool
+}
+
+func initPackageCommand() *cobra.Command {
+	packageCommand := &cobra.Command{
+		Use:   "package [<libraryPath>]",
+		Short: "Creates the release archive of a library.",
+		Long: "Validates a library and creates its release archive, in the same format used by the Library Manager. " +
+			"Hidden files and folders are not included in the archive. The archive is not created if the validation fails.",
+		Example: "" +
+			"  " + os.Args[0] + " lib package\n" +
+			"  " + os.Args[0] + " lib package ~/Arduino/libraries/MyLibrary --output-dir /tmp --exclude extras --exclude \"*.psd\"",
+		Args: cobra.MaximumNArgs(1),
+		Run:  runPackageCommand,
+	}
+	packageCommand.Flags().StringVar(&packageFlags.outputDir, "output-dir", "", "The folder where the archive is created, defaults to the current folder.")
+	packageCommand.Flags().StringSliceVar(&packageFlags.exclude, "exclude", []string{}, "Exclude the files matching the pattern from the archive, can be used multiple times.")
+	packageCommand.Flags().BoolVar(&packageFlags.checkIndex, "check-index", false, "Check the name and the version against the libraries index before creating the archive.")
+	packageCommand.Flags().BoolVar(&packageFlags.skipValidation, "skip-validation", false, "Create the archive even if the library has errors.")
+	return packageCommand
+}
+
+func runPackageCommand(cmd *cobra.Command, args []string) {
+	logrus.Info("Executing `arduino lib package`")
+
+	libraryPath := libraryPathArg(args)
+	if !packageFlags.skipValidation {
+		diagnostics := validateLibrary(libraryPath, packageFlags.checkIndex)
+		failed := false
+		for _, d := range diagnostics {
+			feedback.Errorf("%s", d)
+			failed = failed || d.Severity == check.SeverityError
+		}
+		if failed {
+			feedback.Errorf("The library has errors, the archive has not been created.")
+			os.Exit(errorcodes.ErrGeneric)
+		}
+	}
+
+	outputDir := paths.New(".")
+	if packageFlags.outputDir != "" {
+		outputDir = paths.New(packageFlags.outputDir)
+	}
+	archive, err := publish.Package(libraryPath, outputDir, packageFlags.exclude)
+	if err != nil {
+		feedback.Errorf("Error creating the library archive: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+	feedback.PrintResult(&packageResult{archive})
+}
+
+type packageResult struct {
+	*publish.Archive
+}
+
+func (r *packageResult) Data() interface{} {
+	return r.Archive
+}
+
+func (r *packageResult) String() string {
+	return fmt.Sprintf("Library archive created: %s\n"+
+		"Files:    %d\n"+
+		"Size:     %d\n"+
+		"Checksum: %s", r.Path, r.Files, r.Size, r.Checksum)
+}
@@ -26,7 +26,6 @@ import (
 	"github.com/arduino/arduino-cli/cli/feedback"
 	"github.com/arduino/arduino-cli/cli/instance"
 	"github.com/arduino/arduino-cli/cli/output"
-	"github.com/arduino/arduino-cli/commands"
 	"github.com/arduino/arduino-cli/commands/lib"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
 	"github.com/sirupsen/logrus"
@@ -44,6 +43,9 @@ func initSearchCommand() *cobra.Command {
 		Run:     runSearchCommand,
 	}
 	searchCommand.Flags().BoolVar(&searchFlags.namesOnly, "names", false, "Show library names only.")
+	instance.AddIndexUpdateFlagsToCommand(searchCommand)
+	output.AddTableFlagsToCommand(searchCommand, "name", "version", "author", "maintainer", "sentence", "category",
+		"architectures", "types", "license", "website", "versions")
 	return searchCommand
 }
 
@@ -58,13 +60,7 @@ func runSearchCommand(cmd *cobra.Command, args []string) {
 		os.Exit(errorcodes.ErrGeneric)
 	}
 
-	err := commands.UpdateLibrariesIndex(context.Background(), &rpc.UpdateLibrariesIndexRequest{
-		Instance: inst,
-	}, output.ProgressBar())
-	if err != nil {
-		feedback.Errorf("Error updating library index: %v", err)
-		os.Exit(errorcodes.ErrGeneric)
-	}
+	instance.UpdateIndexesIfNeeded(inst, false, true)
 
 	for _, err := range instance.Init(inst) {
 		feedback.Errorf("Error initializing instance: %v", err)
@@ -181,6 +177,29 @@ func (res result) String() string {
 	return fmt.Sprintf("%s", out.String())
 }
 
+// Table returns the data of the latest release of each library, to print
+// the columns selected with --columns
+func (res result) Table() *feedback.Table {
+	results := res.results.GetLibraries()
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+
+	t := &feedback.Table{Columns: []string{"name", "version", "author", "maintainer", "sentence", "category",
+		"architectures", "types", "license", "website", "versions"}}
+	for _, lib := range results {
+		latest := lib.GetLatest()
+		versions := []string{}
+		for _, v := range versionsFromSearchedLibrary(lib) {
+			versions = append(versions, v.String())
+		}
+		t.AddRow(lib.GetName(), latest.GetVersion(), latest.GetAuthor(), latest.GetMaintainer(), latest.GetSentence(),
+			latest.GetCategory(), strings.Join(latest.GetArchitectures(), ","), strings.Join(latest.GetTypes(), ","),
+			latest.GetLicense(), latest.GetWebsite(), strings.Join(versions, ","))
+	}
+	return t
+}
+
 func versionsFromSearchedLibrary(library *rpc.SearchedLibrary) []*semver.Version {
 	res := []*semver.Version{}
 	for str := range library.Releases {
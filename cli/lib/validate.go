@@ -0,0 +1,145 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package lib
+
+import (
+	"os"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino/libraries/publish"
+	"github.com/arduino/arduino-cli/arduino/sketches/check"
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/arduino-cli/cli/instance"
+	"github.com/arduino/arduino-cli/commands"
+	"github.com/arduino/go-paths-helper"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var validateFlags struct {
+	checkIndex    bool
+	failOnWarning bool
+}
+
+func initValidateCommand() *cobra.Command {
+	validateCommand := &cobra.Command{
+		Use:   "validate [<libraryPath>]",
+		Short: "Checks that a library can be published in the Library Manager.",
+		Long: "Checks the library.properties, the keywords.txt and the examples of a library against the rules of the Library Manager. " +
+			"With --check-index the name and the version of the library are also checked against the libraries index, " +
+			"as done by the Library Manager when a new release is indexed.",
+		Example: "" +
+			"  " + os.Args[0] + " lib validate\n" +
+			"  " + os.Args[0] + " lib validate ~/Arduino/libraries/MyLibrary --check-index",
+		Args: cobra.MaximumNArgs(1),
+		Run:  runValidateCommand,
+	}
+	validateCommand.Flags().BoolVar(&validateFlags.checkIndex, "check-index", false, "Check the name and the version against the libraries index.")
+	validateCommand.Flags().BoolVar(&validateFlags.failOnWarning, "fail-on-warning", false, "Exit with an error also if only warnings are found.")
+	return validateCommand
+}
+
+func runValidateCommand(cmd *cobra.Command, args []string) {
+	logrus.Info("Executing `arduino lib validate`")
+
+	libraryPath := libraryPathArg(args)
+	diagnostics := validateLibrary(libraryPath, validateFlags.checkIndex)
+	feedback.PrintResult(&validateResult{Diagnostics: diagnostics, libraryPath: libraryPath})
+	for _, d := range diagnostics {
+		if d.Severity == check.SeverityError || validateFlags.failOnWarning {
+			os.Exit(errorcodes.ErrGeneric)
+		}
+	}
+}
+
+// libraryPathArg returns the absolute path of the library given as argument,
+// or of the current folder
+func libraryPathArg(args []string) *paths.Path {
+	libraryPath := paths.New(".")
+	if len(args) == 1 {
+		libraryPath = paths.New(args[0])
+	}
+	libraryPath, err := libraryPath.Abs()
+	if err != nil {
+		feedback.Errorf("Invalid library path: %v", err)
+		os.Exit(errorcodes.ErrBadArgument)
+	}
+	return libraryPath
+}
+
+func validateLibrary(libraryPath *paths.Path, checkIndex bool) []*check.Diagnostic {
+	diagnostics, err := publish.Validate(libraryPath)
+	if err != nil {
+		feedback.Errorf("Error validating library: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+	if !checkIndex || !libraryPath.Join("library.properties").Exist() {
+		return diagnostics
+	}
+
+	inst := instance.CreateAndInit()
+	lm := commands.GetLibraryManager(inst.GetId())
+	if lm == nil {
+		feedback.Errorf("Error validating library: invalid instance")
+		os.Exit(errorcodes.ErrGeneric)
+	}
+	indexDiagnostics, err := publish.CheckIndex(lm.Index, libraryPath)
+	if err != nil {
+		feedback.Errorf("Error checking the libraries index: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+	return append(diagnostics, indexDiagnostics...)
+}
+
+type validateResult struct {
+	Diagnostics []*check.Diagnostic `json:"diagnostics"`
+
+	libraryPath *paths.Path
+}
+
+func (r *validateResult) Data() interface{} {
+	return r
+}
+
+func (r *validateResult) Annotations() []*feedback.Annotation {
+	res := []*feedback.Annotation{}
+	for _, d := range r.Diagnostics {
+		file := d.File
+		if file != "" {
+			file = r.libraryPath.Join(file).String()
+		}
+		res = append(res, &feedback.Annotation{
+			Severity: string(d.Severity),
+			File:     file,
+			Line:     d.Line,
+			Title:    d.Rule,
+			Message:  d.Message,
+		})
+	}
+	return res
+}
+
+func (r *validateResult) String() string {
+	if len(r.Diagnostics) == 0 {
+		return "The library can be published."
+	}
+	lines := []string{}
+	for _, d := range r.Diagnostics {
+		lines = append(lines, d.String())
+	}
+	return strings.Join(lines, "\n")
+}
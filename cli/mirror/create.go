@@ -0,0 +1,174 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package mirror
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino/cores/packagemanager"
+	"github.com/arduino/arduino-cli/arduino/libraries/librariesindex"
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/arduino-cli/cli/globals"
+	"github.com/arduino/arduino-cli/cli/instance"
+	"github.com/arduino/arduino-cli/cli/lib"
+	"github.com/arduino/arduino-cli/cli/output"
+	"github.com/arduino/arduino-cli/commands"
+	"github.com/arduino/arduino-cli/commands/mirror"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	semver "go.bug.st/relaxed-semver"
+)
+
+var createFlags struct {
+	platforms []string
+	libraries []string
+	allHosts  bool
+	baseURL   string
+}
+
+func initCreateCommand() *cobra.Command {
+	createCommand := &cobra.Command{
+		Use:   "create <dir>",
+		Short: "Creates a mirror of platforms and libraries.",
+		Long: "Updates the indexes and downloads the selected platforms, with their tools, and libraries, with their dependencies, to the given folder. " +
+			"The folder also contains the package index and the library index listing them, so it can be copied to a machine without internet access, " +
+			"or served by a web server, and used as a Boards Manager additional URL.",
+		Example: "" +
+			"  " + os.Args[0] + " mirror create /media/usb/arduino-mirror --platform arduino:avr --platform arduino:samd@1.8.11 --library Servo\n" +
+			"  " + os.Args[0] + " mirror create ./mirror --platform arduino:avr --all-hosts --base-url https://arduino.example.com/mirror",
+		Args: cobra.ExactArgs(1),
+		Run:  runCreateCommand,
+	}
+	createCommand.Flags().StringSliceVar(&createFlags.platforms, "platform", []string{}, "A platform to add to the mirror as PACKAGER:ARCH[@VERSION], can be used multiple times. Defaults to the latest version.")
+	createCommand.Flags().StringSliceVar(&createFlags.libraries, "library", []string{}, "A library to add to the mirror as NAME[@VERSION], can be used multiple times. Defaults to the latest version.")
+	createCommand.Flags().BoolVar(&createFlags.allHosts, "all-hosts", false, "Add the tools for all the operating systems, by default only the ones for the current operating system are added.")
+	createCommand.Flags().StringVar(&createFlags.baseURL, "base-url", "", "The URL where the mirror will be served, defaults to the file:// URL of the mirror folder.")
+	return createCommand
+}
+
+func runCreateCommand(cmd *cobra.Command, args []string) {
+	logrus.Info("Executing `arduino mirror create`")
+
+	if len(createFlags.platforms) == 0 && len(createFlags.libraries) == 0 {
+		feedback.Errorf("Nothing to mirror: use --platform or --library")
+		os.Exit(errorcodes.ErrBadArgument)
+	}
+	opts := &mirror.Options{AllHosts: createFlags.allHosts, BaseURL: createFlags.baseURL}
+	platformRefs, err := globals.ParseReferenceArgs(createFlags.platforms, true)
+	if err != nil {
+		feedback.Errorf("Invalid argument passed: %v", err)
+		os.Exit(errorcodes.ErrBadArgument)
+	}
+	for _, ref := range platformRefs {
+		platformRef := &packagemanager.PlatformReference{Package: ref.PackageName, PlatformArchitecture: ref.Architecture}
+		if ref.Version != "" {
+			if platformRef.PlatformVersion, err = semver.Parse(ref.Version); err != nil {
+				feedback.Errorf("Invalid version %s: %v", ref.Version, err)
+				os.Exit(errorcodes.ErrBadArgument)
+			}
+		}
+		opts.Platforms = append(opts.Platforms, platformRef)
+	}
+	libraryRefs, err := lib.ParseLibraryReferenceArgs(createFlags.libraries)
+	if err != nil {
+		feedback.Errorf("Invalid argument passed: %v", err)
+		os.Exit(errorcodes.ErrBadArgument)
+	}
+	for _, ref := range libraryRefs {
+		libraryRef := &librariesindex.Reference{Name: ref.Name}
+		if ref.Version != "" {
+			if libraryRef.Version, err = semver.Parse(ref.Version); err != nil {
+				feedback.Errorf("Invalid version %s: %v", ref.Version, err)
+				os.Exit(errorcodes.ErrBadArgument)
+			}
+		}
+		opts.Libraries = append(opts.Libraries, libraryRef)
+	}
+
+	inst, status := instance.Create()
+	if status != nil {
+		feedback.Errorf("Error creating instance: %v", status)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+	if err := commands.UpdateCoreLibrariesIndex(context.Background(), &rpc.UpdateCoreLibrariesIndexRequest{Instance: inst}, output.ProgressBar()); err != nil {
+		feedback.Errorf("Error updating core and libraries index: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+	for _, err := range instance.Init(inst) {
+		feedback.Errorf("Error initializing instance: %v", err)
+	}
+
+	pm := commands.GetPackageManager(inst.GetId())
+	lm := commands.GetLibraryManager(inst.GetId())
+	res, err := mirror.Create(pm, commands.GetSettings(inst.GetId()), lm.Index, paths.New(args[0]), opts, output.ProgressBar())
+	if err != nil {
+		feedback.Errorf("Error creating the mirror: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+
+	result := &createResult{}
+	for _, platform := range res.Platforms {
+		result.Platforms = append(result.Platforms, platform.String())
+	}
+	for _, tool := range res.Tools {
+		result.Tools = append(result.Tools, tool.String())
+	}
+	for _, library := range res.Libraries {
+		result.Libraries = append(result.Libraries, library.String())
+	}
+	if res.PackageIndex != nil {
+		result.PackageIndex = mirror.FileURL(res.PackageIndex)
+		if createFlags.baseURL != "" {
+			result.PackageIndex = strings.TrimSuffix(createFlags.baseURL, "/") + "/" + mirror.PackageIndexName
+		}
+	}
+	if res.LibraryIndex != nil {
+		result.LibraryIndex = res.LibraryIndex.String()
+	}
+	feedback.PrintResult(result)
+}
+
+type createResult struct {
+	Platforms    []string `json:"platforms,omitempty"`
+	Tools        []string `json:"tools,omitempty"`
+	Libraries    []string `json:"libraries,omitempty"`
+	PackageIndex string   `json:"package_index,omitempty"`
+	LibraryIndex string   `json:"library_index,omitempty"`
+}
+
+func (r *createResult) Data() interface{} {
+	return r
+}
+
+func (r *createResult) String() string {
+	res := ""
+	for _, item := range append(append(append([]string{}, r.Platforms...), r.Tools...), r.Libraries...) {
+		res += "Mirrored " + item + "\n"
+	}
+	if r.PackageIndex != "" {
+		res += fmt.Sprintf("\nAdd the platforms to the Boards Manager with the additional URL:\n  %s\n", r.PackageIndex)
+	}
+	if r.LibraryIndex != "" {
+		res += fmt.Sprintf("\nCopy the library index to the data directory to install the libraries:\n  %s\n", r.LibraryIndex)
+	}
+	return strings.TrimSpace(res)
+}
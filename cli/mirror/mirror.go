@@ -0,0 +1,37 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package mirror
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// NewCommand created a new `mirror` command
+func NewCommand() *cobra.Command {
+	mirrorCommand := &cobra.Command{
+		Use:   "mirror",
+		Short: "Arduino mirror commands.",
+		Long:  "Arduino mirror commands, to use platforms and libraries on machines without internet access.",
+		Example: "# Create a mirror of a platform and a library.\n" +
+			" " + os.Args[0] + " mirror create /media/usb/arduino-mirror --platform arduino:avr --library Servo\n\n",
+	}
+
+	mirrorCommand.AddCommand(initCreateCommand())
+
+	return mirrorCommand
+}
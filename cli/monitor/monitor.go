@@ -0,0 +1,426 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package monitor
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/arduino/arduino-cli/arduino/monitors"
+	"github.com/arduino/arduino-cli/arduino/partitions"
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/arduino-cli/cli/output"
+	"github.com/arduino/go-paths-helper"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"go.bug.st/serial"
+)
+
+var monitorFlags struct {
+	port             string
+	baudRate         int
+	record           string
+	replay           string
+	pty              bool
+	lineEnding       string
+	echo             bool
+	timestamps       string
+	hex              bool
+	logFile          string
+	logMaxSize       string
+	logMaxFiles      int
+	reconnect        bool
+	parse            string
+	sparkline        bool
+	sparklineWidth   int
+	bridges          []string
+	script           string
+	reconnectTimeout time.Duration
+}
+
+// NewCommand created a new `monitor` command
+func NewCommand() *cobra.Command {
+	monitorCommand := &cobra.Command{
+		Use:   "monitor",
+		Short: "Open a serial monitor.",
+		Long: "Open a serial monitor on the given port. The data received from the board is printed on the standard output " +
+			"and the standard input is sent to the board. The traffic can be recorded, with its timing, in a session file " +
+			"that can be replayed later without the board.",
+		Example: "" +
+			"  " + os.Args[0] + " monitor -p /dev/ttyACM0 -b 115200\n" +
+			"  " + os.Args[0] + " monitor -p /dev/ttyACM0 --line-ending crlf --echo\n" +
+			"  " + os.Args[0] + " monitor -p /dev/ttyACM0 --timestamps --log-file monitor.log --log-max-size 10M\n" +
+			"  " + os.Args[0] + " monitor -p /dev/ttyACM0 --timestamps=delta --hex\n" +
+			"  " + os.Args[0] + " monitor -p /dev/ttyACM0 --parse csv\n" +
+			"  " + os.Args[0] + " monitor -p /dev/ttyACM0 --parse key=value --sparkline\n" +
+			"  " + os.Args[0] + " monitor -p /dev/ttyACM0 --bridge tcp:0.0.0.0:4321 --bridge pty\n" +
+			"  " + os.Args[0] + " monitor -p /dev/ttyACM0 --script smoke-test.yaml --format json\n" +
+			"  " + os.Args[0] + " monitor -p /dev/ttyACM0 --record session.bin\n" +
+			"  " + os.Args[0] + " monitor --replay session.bin\n" +
+			"  " + os.Args[0] + " monitor --replay session.bin --pty",
+		Args: cobra.NoArgs,
+		Run:  runMonitorCommand,
+	}
+	monitorCommand.Flags().StringVarP(&monitorFlags.port, "port", "p", "", "Port to monitor, e.g.: COM10 or /dev/ttyACM0")
+	monitorCommand.Flags().IntVarP(&monitorFlags.baudRate, "baudrate", "b", 9600, "Baud rate of the serial port.")
+	monitorCommand.Flags().StringVar(&monitorFlags.lineEnding, "line-ending", "nl", "Line ending of the lines sent to the board: none, nl, cr or crlf.")
+	monitorCommand.Flags().BoolVar(&monitorFlags.echo, "echo", false, "Print the data sent to the board (local echo).")
+	monitorCommand.Flags().BoolVar(&monitorFlags.reconnect, "reconnect", true, "Reopen the port when it's lost, e.g. when the board is reset. The port is also released while an upload is using it.")
+	monitorCommand.Flags().DurationVar(&monitorFlags.reconnectTimeout, "reconnect-timeout", 0, "How long to wait for a lost port to come back before exiting, 0 waits forever.")
+	monitorCommand.Flags().StringVar(&monitorFlags.timestamps, "timestamps", "", "Prefix each line with a timestamp: wall (the time of the day) or delta (the time elapsed since the previous line).")
+	monitorCommand.Flags().Lookup("timestamps").NoOptDefVal = string(monitors.TimestampWall)
+	monitorCommand.Flags().BoolVar(&monitorFlags.hex, "hex", false, "Show the data received as an hex dump.")
+	monitorCommand.Flags().StringVar(&monitorFlags.parse, "parse", "", "Extract the numeric values of each line and print them as NDJSON, the lines are read as csv (values separated by commas, spaces or tabs, with an optional header line) or key=value (name=value or name:value pairs).")
+	monitorCommand.Flags().BoolVar(&monitorFlags.sparkline, "sparkline", false, "Show the values extracted with --parse as sparklines instead of NDJSON.")
+	monitorCommand.Flags().IntVar(&monitorFlags.sparklineWidth, "sparkline-width", 60, "Number of values shown in each sparkline.")
+	monitorCommand.Flags().StringArrayVar(&monitorFlags.bridges, "bridge", []string{}, "Share the port with other programs through a TCP server (tcp:<address>:<port>) or a pseudo-terminal (pty, Linux only). Can be used multiple times.")
+	monitorCommand.Flags().StringVar(&monitorFlags.script, "script", "", "Run the send/expect steps of the given YAML script instead of reading the standard input, and exit with an error if the script fails.")
+	monitorCommand.Flags().StringVar(&monitorFlags.logFile, "log-file", "", "Also write the monitor output to the given file.")
+	monitorCommand.Flags().StringVar(&monitorFlags.logMaxSize, "log-max-size", "", "Rotate the log file when it grows over the given size, e.g.: 512K or 10M.")
+	monitorCommand.Flags().IntVar(&monitorFlags.logMaxFiles, "log-max-files", 5, "Number of rotated log files to keep.")
+	monitorCommand.Flags().StringVar(&monitorFlags.record, "record", "", "Record the traffic in the given session file.")
+	monitorCommand.Flags().StringVar(&monitorFlags.replay, "replay", "", "Replay the data received in a recorded session instead of opening a port.")
+	monitorCommand.Flags().BoolVar(&monitorFlags.pty, "pty", false, "Replay the session into a pseudo-terminal, that can be opened as a serial port by other programs (Linux only).")
+	return monitorCommand
+}
+
+// Run opens a monitor on the given port with the default settings. It's used
+// to open the monitor after an upload.
+func Run(port string, baudRate int) {
+	monitorFlags.port = port
+	monitorFlags.baudRate = baudRate
+	monitorFlags.lineEnding = string(monitors.LineEndingNL)
+	monitorFlags.reconnect = true
+	runMonitorCommand(nil, nil)
+}
+
+func runMonitorCommand(cmd *cobra.Command, args []string) {
+	logrus.Info("Executing `arduino monitor`")
+
+	if (monitorFlags.port == "") == (monitorFlags.replay == "") {
+		feedback.Errorf("Either a port or a session to replay must be specified.")
+		os.Exit(errorcodes.ErrBadArgument)
+	}
+	if monitorFlags.replay != "" && monitorFlags.record != "" {
+		feedback.Errorf("A replayed session can't be recorded.")
+		os.Exit(errorcodes.ErrBadArgument)
+	}
+	if monitorFlags.pty && monitorFlags.replay == "" {
+		feedback.Errorf("--pty can be used only with --replay.")
+		os.Exit(errorcodes.ErrBadArgument)
+	}
+
+	if monitorFlags.pty && (monitorFlags.timestamps != "" || monitorFlags.hex) {
+		feedback.Errorf("--timestamps and --hex can't be used with --pty.")
+		os.Exit(errorcodes.ErrBadArgument)
+	}
+	if monitorFlags.parse != "" && (monitorFlags.timestamps != "" || monitorFlags.hex || monitorFlags.pty || monitorFlags.echo) {
+		feedback.Errorf("--parse can't be used with --timestamps, --hex, --pty or --echo.")
+		os.Exit(errorcodes.ErrBadArgument)
+	}
+	if monitorFlags.sparkline && monitorFlags.parse == "" {
+		feedback.Errorf("--sparkline can be used only with --parse.")
+		os.Exit(errorcodes.ErrBadArgument)
+	}
+	var channelFormat monitors.ChannelFormat
+	if monitorFlags.parse != "" {
+		format, err := monitors.ParseChannelFormat(monitorFlags.parse)
+		if err != nil {
+			feedback.Error(err)
+			os.Exit(errorcodes.ErrBadArgument)
+		}
+		channelFormat = format
+	}
+	for _, spec := range monitorFlags.bridges {
+		if _, _, err := monitors.ParseBridgeSpec(spec); err != nil {
+			feedback.Error(err)
+			os.Exit(errorcodes.ErrBadArgument)
+		}
+	}
+	var script *monitors.Script
+	if monitorFlags.script != "" {
+		if monitorFlags.pty || monitorFlags.parse != "" {
+			feedback.Errorf("--script can't be used with --pty or --parse.")
+			os.Exit(errorcodes.ErrBadArgument)
+		}
+		s, err := monitors.LoadScript(paths.New(monitorFlags.script))
+		if err != nil {
+			feedback.Errorf("Error loading script: %v", err)
+			os.Exit(errorcodes.ErrBadArgument)
+		}
+		script = s
+	}
+	lineEnding, err := monitors.ParseLineEnding(monitorFlags.lineEnding)
+	if err != nil {
+		feedback.Error(err)
+		os.Exit(errorcodes.ErrBadArgument)
+	}
+	timestamps, err := monitors.ParseTimestampMode(monitorFlags.timestamps)
+	if err != nil {
+		feedback.Error(err)
+		os.Exit(errorcodes.ErrBadArgument)
+	}
+	logMaxSize := uint64(0)
+	if monitorFlags.logMaxSize != "" {
+		if logMaxSize, err = partitions.ParseSize(monitorFlags.logMaxSize); err != nil {
+			feedback.Errorf("Invalid log file size: %v", err)
+			os.Exit(errorcodes.ErrBadArgument)
+		}
+	}
+	var logFile *monitors.LogFile
+	if monitorFlags.logFile != "" {
+		logFile, err = monitors.OpenLogFile(paths.New(monitorFlags.logFile), int64(logMaxSize), monitorFlags.logMaxFiles)
+		if err != nil {
+			feedback.Error(err)
+			os.Exit(errorcodes.ErrGeneric)
+		}
+		defer logFile.Close()
+	}
+
+	var mon monitors.Monitor
+	if monitorFlags.replay != "" {
+		replay, err := monitors.OpenSessionReplay(paths.New(monitorFlags.replay))
+		if err != nil {
+			feedback.Errorf("Error opening session: %v", err)
+			os.Exit(errorcodes.ErrGeneric)
+		}
+		mon = replay
+	} else {
+		openSerial := func() (monitors.Monitor, error) {
+			return monitors.OpenSerialMonitor(monitorFlags.port, monitorFlags.baudRate)
+		}
+		var serialMonitor monitors.Monitor
+		var err error
+		if monitorFlags.reconnect {
+			serialMonitor, err = monitors.NewReconnectingMonitor(monitorFlags.port, openSerial, monitorFlags.reconnectTimeout)
+		} else {
+			serialMonitor, err = openSerial()
+		}
+		if err != nil {
+			feedback.Errorf("Error opening port: %v", err)
+			if ports, err := serial.GetPortsList(); err == nil && len(ports) > 0 {
+				feedback.Errorf("Available ports: %s", strings.Join(ports, ", "))
+			}
+			os.Exit(errorcodes.ErrGeneric)
+		}
+		mon = serialMonitor
+	}
+	if monitorFlags.record != "" {
+		out, err := paths.New(monitorFlags.record).Create()
+		if err != nil {
+			mon.Close()
+			feedback.Errorf("Error creating session file: %v", err)
+			os.Exit(errorcodes.ErrGeneric)
+		}
+		recorder, err := monitors.NewSessionRecorder(mon, out)
+		if err != nil {
+			mon.Close()
+			feedback.Errorf("Error recording session: %v", err)
+			os.Exit(errorcodes.ErrGeneric)
+		}
+		mon = recorder
+	}
+
+	var out io.Writer = os.Stdout
+	var events *feedback.EventWriter
+	if output.OutputFormat == "ndjson" {
+		// the data received is printed as "output" events
+		events = feedback.NewEventWriter("stdout")
+		out = events
+	} else if script != nil && output.OutputFormat == "json" {
+		// the data received is not printed, to keep the JSON valid, but it's
+		// still written to the log file
+		out = ioutil.Discard
+	}
+	flushEvents := func() {
+		if events != nil {
+			events.Flush()
+		}
+	}
+	if monitorFlags.pty {
+		master, name, err := monitors.OpenPty()
+		if err != nil {
+			mon.Close()
+			feedback.Errorf("Error opening pseudo-terminal: %v", err)
+			os.Exit(errorcodes.ErrGeneric)
+		}
+		defer master.Close()
+		out = master
+		feedback.Printf("Replaying session on %s, press Ctrl-C to exit.", name)
+	}
+	if logFile != nil {
+		out = io.MultiWriter(out, logFile)
+	}
+	if timestamps != monitors.TimestampNone || monitorFlags.hex {
+		out = monitors.NewDisplayWriter(out, timestamps, monitorFlags.hex)
+	}
+	if channelFormat != "" {
+		out = monitors.NewPlotterWriter(out, channelFormat, monitorFlags.sparkline, monitorFlags.sparklineWidth)
+	}
+
+	// The clients of the bridges exchange the raw data with the board
+	var received io.Reader = mon
+	if len(monitorFlags.bridges) > 0 {
+		bridge := monitors.NewBridge(mon)
+		defer bridge.Close()
+		for _, spec := range monitorFlags.bridges {
+			kind, address, _ := monitors.ParseBridgeSpec(spec)
+			if kind == "tcp" {
+				addr, err := bridge.ListenTCP(address)
+				if err != nil {
+					mon.Close()
+					feedback.Error(err)
+					os.Exit(errorcodes.ErrGeneric)
+				}
+				feedback.Printf("Bridge listening on tcp:%s", addr)
+				continue
+			}
+			master, name, err := monitors.OpenPty()
+			if err != nil {
+				mon.Close()
+				feedback.Errorf("Error opening pseudo-terminal: %v", err)
+				os.Exit(errorcodes.ErrGeneric)
+			}
+			bridge.AddClient(master, true)
+			feedback.Printf("Bridge available on %s", name)
+		}
+		received = io.TeeReader(mon, bridge)
+	}
+
+	// The line ending is applied after the recorder, so the session contains
+	// the data actually sent to the board
+	mon = monitors.NewLineEndingMonitor(mon, lineEnding)
+
+	if script != nil {
+		runScript(mon, script, received, out, flushEvents)
+		return
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	done := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(out, received)
+		done <- err
+	}()
+	sendErr := make(chan error, 1)
+	if monitorFlags.replay == "" {
+		var in io.Reader = os.Stdin
+		if monitorFlags.echo {
+			in = io.TeeReader(in, out)
+		}
+		go func() {
+			_, err := io.Copy(mon, in)
+			if err != nil {
+				sendErr <- err
+			}
+		}()
+		logrus.Infof("Connected to %s", monitorFlags.port)
+	}
+
+	select {
+	case <-interrupt:
+	case err := <-sendErr:
+		mon.Close()
+		feedback.Errorf("Error writing to monitor: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	case err := <-done:
+		if err != nil {
+			mon.Close()
+			flushEvents()
+			feedback.Errorf("Error reading from monitor: %v", err)
+			os.Exit(errorcodes.ErrGeneric)
+		}
+		flushEvents()
+		if monitorFlags.pty {
+			// Keep the pseudo-terminal open until the user is done with it
+			<-interrupt
+		} else if monitorFlags.replay == "" {
+			logrus.Infof("Port %s closed", monitorFlags.port)
+		}
+	}
+	if err := mon.Close(); err != nil {
+		feedback.Errorf("Error closing monitor: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+}
+
+// runScript runs the script and prints its result, the command fails if the
+// script fails
+func runScript(mon monitors.Monitor, script *monitors.Script, received io.Reader, out io.Writer, flush func()) {
+	res := monitors.RunScript(&scriptMonitor{Monitor: mon, received: received}, script, out)
+	mon.Close()
+	flush()
+	PrintScriptResult(res)
+	if !res.Passed {
+		os.Exit(errorcodes.ErrTestFailed)
+	}
+}
+
+// PrintScriptResult prints the result of a monitor script
+func PrintScriptResult(res *monitors.ScriptResult) {
+	feedback.PrintResult(&scriptResult{res})
+}
+
+// scriptMonitor reads through the bridges of the monitor, if any
+type scriptMonitor struct {
+	monitors.Monitor
+	received io.Reader
+}
+
+func (m *scriptMonitor) Read(data []byte) (int, error) {
+	return m.received.Read(data)
+}
+
+type scriptResult struct {
+	*monitors.ScriptResult
+}
+
+func (r *scriptResult) Data() interface{} {
+	return r.ScriptResult
+}
+
+func (r *scriptResult) String() string {
+	lines := []string{""}
+	for i, step := range r.Steps {
+		status := "PASS"
+		if !step.Passed {
+			status = "FAIL"
+		}
+		line := fmt.Sprintf("%s %d. %s %q (%dms)", status, i+1, step.Action, step.Value, step.DurationMs)
+		if step.Error != "" {
+			line += ": " + step.Error
+		}
+		lines = append(lines, line)
+	}
+	verdict := "PASSED"
+	if !r.Passed {
+		verdict = "FAILED"
+	}
+	name := r.Name
+	if name == "" {
+		name = monitorFlags.script
+	}
+	lines = append(lines, fmt.Sprintf("Script %s %s", name, verdict))
+	return strings.Join(lines, "\n")
+}
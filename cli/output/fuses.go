@@ -0,0 +1,49 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package output
+
+import (
+	"strconv"
+
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/arduino-cli/table"
+)
+
+// FormatFuses renders the fuses in a table, along with the values defined by
+// the board. The fuses whose value doesn't match are marked.
+func FormatFuses(fuses []*rpc.FuseValue) string {
+	t := table.New()
+	t.SetHeader("Fuse", "Value", "Board value", "")
+	for _, fuse := range fuses {
+		mark := ""
+		if !FuseMatches(fuse) {
+			mark = "MISMATCH"
+		}
+		t.AddRow(fuse.GetName(), fuse.GetValue(), fuse.GetExpected(), mark)
+	}
+	return t.Render()
+}
+
+// FuseMatches returns true if the value of the fuse is the one defined by
+// the board. The values are compared as numbers, so 0xfd matches 0xFD.
+func FuseMatches(fuse *rpc.FuseValue) bool {
+	value, err := strconv.ParseUint(fuse.GetValue(), 0, 8)
+	if err != nil {
+		return fuse.GetValue() == fuse.GetExpected()
+	}
+	expected, err := strconv.ParseUint(fuse.GetExpected(), 0, 8)
+	return err == nil && value == expected
+}
@@ -0,0 +1,37 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package output
+
+// MemoryResult is the JSON result event of the commands reading or writing
+// the memory of a board
+type MemoryResult struct {
+	Event   string `json:"event"`
+	ToolOut string `json:"upload_out,omitempty"`
+	ToolErr string `json:"upload_err,omitempty"`
+	Path    string `json:"path"`
+	Size    uint64 `json:"size,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Data implements feedback.Result
+func (r *MemoryResult) Data() interface{} {
+	return r
+}
+
+// String implements feedback.Result
+func (r *MemoryResult) String() string {
+	return ""
+}
@@ -18,36 +18,154 @@ package output
 import (
 	"fmt"
 
+	"github.com/arduino/arduino-cli/cli/feedback"
 	"github.com/arduino/arduino-cli/commands"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
 	"github.com/cmaglie/pb"
 )
 
-// OutputFormat can be "text" or "json"
+// OutputFormat can be "text", "json" or "ndjson"
 var OutputFormat string
 
 // ProgressBar returns a DownloadProgressCB that prints a progress bar.
-// If JSON output format has been selected, the callback outputs nothing.
+// If NDJSON output format has been selected, the callback prints a "download"
+// event for each update. In the other formats the callback outputs nothing.
 func ProgressBar() commands.DownloadProgressCB {
-	if OutputFormat != "json" {
+	if OutputFormat == "text" {
 		return NewDownloadProgressBarCB()
 	}
+	if OutputFormat == "ndjson" {
+		return NewDownloadProgressEventCB()
+	}
 	return func(curr *rpc.DownloadProgress) {
 		// XXX: Output progress in JSON?
 	}
 }
 
 // TaskProgress returns a TaskProgressCB that prints the task progress.
-// If JSON output format has been selected, the callback outputs nothing.
+// If NDJSON output format has been selected, the callback prints a "task"
+// event for each update. In the other formats the callback outputs nothing.
 func TaskProgress() commands.TaskProgressCB {
-	if OutputFormat != "json" {
+	if OutputFormat == "text" {
 		return NewTaskProgressCB()
 	}
+	if OutputFormat == "ndjson" {
+		return NewTaskProgressEventCB("task")
+	}
 	return func(curr *rpc.TaskProgress) {
 		// XXX: Output progress in JSON?
 	}
 }
 
+// CompileProgress returns the TaskProgressCB of the build phases. It prints
+// a "compile" event for each phase started or completed if NDJSON output
+// format has been selected, otherwise it's nil and no progress is reported.
+func CompileProgress() commands.TaskProgressCB {
+	if OutputFormat == "ndjson" {
+		return NewTaskProgressEventCB("compile")
+	}
+	return nil
+}
+
+// UploadProgressBar returns an UploadProgressCB that prints a progress bar.
+// If JSON output format has been selected, the callback prints a "progress"
+// event for each update, in NDJSON format an "upload" event.
+func UploadProgressBar() commands.UploadProgressCB {
+	switch OutputFormat {
+	case "json":
+		return NewUploadProgressEventCB()
+	case "ndjson":
+		return func(curr *rpc.UploadProgress) {
+			feedback.PrintTypedEvent("upload", &uploadProgressEvent{
+				Stage:   curr.GetStage(),
+				Current: curr.GetCurrent(),
+				Total:   curr.GetTotal(),
+			})
+		}
+	}
+	return NewUploadProgressBarCB()
+}
+
+// uploadProgressEvent is the JSON event printed for each progress update of
+// an upload
+type uploadProgressEvent struct {
+	Event   string `json:"event,omitempty"`
+	Stage   string `json:"stage"`
+	Current int64  `json:"current"`
+	Total   int64  `json:"total"`
+}
+
+func (e *uploadProgressEvent) Data() interface{} {
+	return e
+}
+
+func (e *uploadProgressEvent) String() string {
+	return fmt.Sprintf("%s %d/%d", e.Stage, e.Current, e.Total)
+}
+
+// NewUploadProgressEventCB creates a progress callback that prints each
+// update of the upload as a single line JSON event
+func NewUploadProgressEventCB() func(*rpc.UploadProgress) {
+	return func(curr *rpc.UploadProgress) {
+		feedback.PrintEvent(&uploadProgressEvent{
+			Event:   "progress",
+			Stage:   curr.GetStage(),
+			Current: curr.GetCurrent(),
+			Total:   curr.GetTotal(),
+		})
+	}
+}
+
+// downloadProgressEvent is the payload of the "download" events
+type downloadProgressEvent struct {
+	File       string `json:"file,omitempty"`
+	URL        string `json:"url,omitempty"`
+	Downloaded int64  `json:"downloaded"`
+	TotalSize  int64  `json:"total_size"`
+	Completed  bool   `json:"completed"`
+}
+
+// NewDownloadProgressEventCB creates a progress callback that prints each
+// update of the downloads as a "download" event. The file name is reported
+// only by the first update of each download, so it's repeated in all of them.
+func NewDownloadProgressEventCB() func(*rpc.DownloadProgress) {
+	var file, url string
+	var total int64
+	return func(curr *rpc.DownloadProgress) {
+		if curr.GetFile() != "" {
+			file, url, total = curr.GetFile(), curr.GetUrl(), curr.GetTotalSize()
+		}
+		feedback.PrintTypedEvent("download", &downloadProgressEvent{
+			File:       file,
+			URL:        url,
+			Downloaded: curr.GetDownloaded(),
+			TotalSize:  total,
+			Completed:  curr.GetCompleted(),
+		})
+	}
+}
+
+// taskProgressEvent is the payload of the "task" and "compile" events
+type taskProgressEvent struct {
+	Name      string  `json:"name,omitempty"`
+	Message   string  `json:"message,omitempty"`
+	Percent   float32 `json:"percent,omitempty"`
+	Completed bool    `json:"completed"`
+}
+
+// NewTaskProgressEventCB creates a progress callback that prints each update
+// of a task as an event of the given type
+func NewTaskProgressEventCB(eventType string) func(*rpc.TaskProgress) {
+	return func(curr *rpc.TaskProgress) {
+		feedback.PrintTypedEvent(eventType, &taskProgressEvent{
+			Name:      curr.GetName(),
+			Message:   curr.GetMessage(),
+			Percent:   curr.GetPercent(),
+			Completed: curr.GetCompleted(),
+		})
+	}
+}
+
 // NewDownloadProgressBarCB creates a progress bar callback that outputs a progress
 // bar on the terminal
 func NewDownloadProgressBarCB() func(*rpc.DownloadProgress) {
@@ -79,6 +197,28 @@ func NewNullDownloadProgressCB() func(*rpc.DownloadProgress) {
 	return func(*rpc.DownloadProgress) {}
 }
 
+// NewUploadProgressBarCB creates a progress bar callback that outputs a
+// progress bar, for each stage of the upload, on the terminal
+func NewUploadProgressBarCB() func(*rpc.UploadProgress) {
+	var bar *pb.ProgressBar
+	var stage string
+	return func(curr *rpc.UploadProgress) {
+		if bar == nil || curr.GetStage() != stage {
+			if bar != nil {
+				bar.Finish()
+			}
+			stage = curr.GetStage()
+			bar = pb.StartNew(int(curr.GetTotal()))
+			bar.Prefix(stage)
+		}
+		bar.Set(int(curr.GetCurrent()))
+		if curr.GetTotal() > 0 && curr.GetCurrent() >= curr.GetTotal() {
+			bar.Finish()
+			bar = nil
+		}
+	}
+}
+
 // NewTaskProgressCB returns a commands.TaskProgressCB progress listener
 // that outputs to terminal
 func NewTaskProgressCB() func(curr *rpc.TaskProgress) {
@@ -0,0 +1,50 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package output
+
+import (
+	"os"
+	"strings"
+
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/spf13/cobra"
+)
+
+// AddTableFlagsToCommand adds to cmd the --columns, --sort-by and --no-header
+// flags, selecting how the table printed by the command is rendered. The
+// options are checked against the available columns before running the
+// command.
+func AddTableFlagsToCommand(cmd *cobra.Command, columns ...string) {
+	opts := feedback.GetTableOptions()
+	cmd.Flags().StringSliceVar(&opts.Columns, "columns", nil,
+		"Comma separated list of the columns to print, can be: "+strings.Join(columns, ", ")+".")
+	cmd.Flags().StringVar(&opts.SortBy, "sort-by", "",
+		"Sort the rows by this column, in descending order if prefixed with '-'.")
+	cmd.Flags().BoolVar(&opts.NoHeader, "no-header", false,
+		"Don't print the names of the columns, the values are separated by a tab.")
+
+	preRun := cmd.PreRun
+	cmd.PreRun = func(cmd *cobra.Command, args []string) {
+		if err := opts.Check(columns); err != nil {
+			feedback.Errorf("Invalid table options: %v", err)
+			os.Exit(errorcodes.ErrBadArgument)
+		}
+		if preRun != nil {
+			preRun(cmd, args)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package output
+
+import (
+	"bytes"
+	"io"
+	"os"
+
+	"github.com/arduino/arduino-cli/cli/feedback"
+)
+
+// ToolOutput holds the writers passed as stdout and stderr to the tools run by
+// a command (avrdude, bossac, ...). In JSON format the output of the tools is
+// buffered, to be reported in the result and keep the JSON valid, in NDJSON
+// format each line is printed as an "output" event, in the other formats it's
+// printed as is.
+type ToolOutput struct {
+	Out io.Writer
+	Err io.Writer
+}
+
+// NewToolOutput returns the ToolOutput for the selected output format
+func NewToolOutput() *ToolOutput {
+	switch OutputFormat {
+	case "json":
+		return &ToolOutput{Out: new(bytes.Buffer), Err: new(bytes.Buffer)}
+	case "ndjson":
+		return &ToolOutput{Out: feedback.NewEventWriter("stdout"), Err: feedback.NewEventWriter("stderr")}
+	default:
+		return &ToolOutput{Out: os.Stdout, Err: os.Stderr}
+	}
+}
+
+// Flush prints the last lines written by the tools if they are not terminated
+// by a newline, it must be called once the tools are done
+func (o *ToolOutput) Flush() {
+	for _, w := range []io.Writer{o.Out, o.Err} {
+		if events, ok := w.(*feedback.EventWriter); ok {
+			events.Flush()
+		}
+	}
+}
+
+// Buffered returns the output of the tools buffered in JSON format, in the
+// other formats the output has already been printed and it returns empty
+// strings
+func (o *ToolOutput) Buffered() (stdout string, stderr string) {
+	if out, ok := o.Out.(*bytes.Buffer); ok {
+		stdout = out.String()
+	}
+	if err, ok := o.Err.(*bytes.Buffer); ok {
+		stderr = err.String()
+	}
+	return stdout, stderr
+}
@@ -0,0 +1,159 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package run
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/arduino/arduino-cli/arduino/monitors"
+	"github.com/arduino/arduino-cli/arduino/simulator"
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/arduino-cli/cli/instance"
+	"github.com/arduino/arduino-cli/cli/monitor"
+	"github.com/arduino/arduino-cli/commands"
+	"github.com/arduino/arduino-cli/commands/run"
+	"github.com/arduino/go-paths-helper"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fqbn         string
+	simulatorFlg string
+	renodeScript string
+	script       string
+	timeout      time.Duration
+	verbose      bool
+)
+
+// NewCommand created a new `run` command
+func NewCommand() *cobra.Command {
+	backends := []string{}
+	for _, backend := range simulator.Backends {
+		backends = append(backends, string(backend))
+	}
+
+	runCommand := &cobra.Command{
+		Use:   "run [<sketchPath>]",
+		Short: "Builds a sketch and runs it in a simulator.",
+		Long: "Builds a sketch and runs it in a simulator (" + strings.Join(backends, ", ") + "), the simulated UART is " +
+			"connected to the standard input and output. The simulator is chosen among the ones found in the PATH that " +
+			"support the board, unless selected with --simulator or by the simulator.backend board property. " +
+			"With --script the send/expect steps of a monitor script are run on the simulated UART, to smoke test " +
+			"the sketch without a board.",
+		Example: "" +
+			"  " + os.Args[0] + " run --simulator -b arduino:avr:uno /home/user/Arduino/Blink\n" +
+			"  " + os.Args[0] + " run --simulator qemu -b arduino:avr:mega --script smoke.yaml\n" +
+			"  " + os.Args[0] + " run --simulator renode --renode-script mkr1000.resc -b arduino:samd:mkr1000 --timeout 30s",
+		Args: cobra.MaximumNArgs(1),
+		Run:  runRun,
+	}
+
+	runCommand.Flags().StringVarP(&fqbn, "fqbn", "b", "", "Fully Qualified Board Name, e.g.: arduino:avr:uno")
+	runCommand.Flags().StringVar(&simulatorFlg, "simulator", "", "Simulator running the sketch: auto, "+strings.Join(backends, ", ")+".")
+	runCommand.Flags().Lookup("simulator").NoOptDefVal = "auto"
+	runCommand.Flags().StringVar(&renodeScript, "renode-script", "", "Renode script creating the simulated machine, the firmware is in the $elf variable.")
+	runCommand.Flags().StringVar(&script, "script", "", "Run the send/expect steps of the given monitor script on the simulated UART, and exit with an error if the script fails.")
+	runCommand.Flags().DurationVar(&timeout, "timeout", 0, "Stop the simulation after the given time, 0 runs until interrupted.")
+	runCommand.Flags().BoolVarP(&verbose, "verbose", "v", false, "Print the compilation output.")
+
+	return runCommand
+}
+
+func runRun(cmd *cobra.Command, args []string) {
+	inst := instance.CreateAndInit()
+	logrus.Info("Executing `arduino run`")
+
+	backend := simulator.Backend(simulatorFlg)
+	if backend == "auto" {
+		backend = ""
+	}
+	var monitorScript *monitors.Script
+	if script != "" {
+		s, err := monitors.LoadScript(paths.New(script))
+		if err != nil {
+			feedback.Errorf("Error loading script: %v", err)
+			os.Exit(errorcodes.ErrBadArgument)
+		}
+		monitorScript = s
+	}
+
+	sketchPath := paths.New(".")
+	if len(args) > 0 {
+		sketchPath = paths.New(args[0])
+	}
+	req := &run.Request{
+		Instance:   inst,
+		Fqbn:       fqbn,
+		SketchPath: sketchPath,
+		Simulator:  backend,
+		Verbose:    verbose,
+	}
+	if renodeScript != "" {
+		req.Script = paths.New(renodeScript)
+	}
+
+	var buildOut io.Writer = ioutil.Discard
+	if verbose {
+		buildOut = feedback.OutputWriter()
+	}
+	sim, err := run.Start(context.Background(), commands.GetPackageManager(inst.GetId()), req, buildOut, feedback.ErrorWriter())
+	if err != nil {
+		feedback.Errorf("Error running sketch: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+	logrus.Infof("Simulator started: %s", strings.Join(sim.Command, " "))
+
+	// Stop the simulation on timeout or CTRL-C
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		var deadline <-chan time.Time
+		if timeout > 0 {
+			deadline = time.After(timeout)
+		}
+		select {
+		case <-interrupt:
+		case <-deadline:
+		}
+		sim.Close()
+	}()
+
+	if monitorScript != nil {
+		res := monitors.RunScript(sim, monitorScript, os.Stdout)
+		sim.Close()
+		monitor.PrintScriptResult(res)
+		if !res.Passed {
+			os.Exit(errorcodes.ErrTestFailed)
+		}
+		return
+	}
+
+	go io.Copy(sim, os.Stdin)
+	io.Copy(os.Stdout, sim)
+	if err := sim.Wait(); err != nil {
+		logrus.Infof("Simulator exited: %v", err)
+	}
+	sim.Close()
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/arduino/arduino-cli/arduino/sketches"
 	"github.com/arduino/arduino-cli/cli/errorcodes"
 	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/arduino-cli/cli/instance"
 	"github.com/arduino/arduino-cli/commands/sketch"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
 	"github.com/arduino/go-paths-helper"
@@ -29,7 +30,12 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var includeBuildDir bool
+var (
+	includeBuildDir  bool
+	includeLibraries bool
+	includeProfile   bool
+	archiveFqbn      string
+)
 
 // initArchiveCommand creates a new `archive` command
 func initArchiveCommand() *cobra.Command {
@@ -42,12 +48,16 @@ func initArchiveCommand() *cobra.Command {
 			"  " + os.Args[0] + " archive .\n" +
 			"  " + os.Args[0] + " archive . MySketchArchive.zip\n" +
 			"  " + os.Args[0] + " archive /home/user/Arduino/MySketch\n" +
-			"  " + os.Args[0] + " archive /home/user/Arduino/MySketch /home/user/MySketchArchive.zip",
+			"  " + os.Args[0] + " archive /home/user/Arduino/MySketch /home/user/MySketchArchive.zip\n" +
+			"  " + os.Args[0] + " archive /home/user/Arduino/MySketch --include-libraries --include-profile -b arduino:avr:uno",
 		Args: cobra.MaximumNArgs(2),
 		Run:  runArchiveCommand,
 	}
 
 	command.Flags().BoolVar(&includeBuildDir, "include-build-dir", false, "Includes build directory in the archive.")
+	command.Flags().BoolVar(&includeLibraries, "include-libraries", false, "Includes the libraries used by the sketch, except the ones bundled with the platform, in the libraries folder of the archive.")
+	command.Flags().BoolVar(&includeProfile, "include-profile", false, "Pins the FQBN and the exact versions of the platform and of the libraries used by the sketch in the sketch.json file of the archive.")
+	command.Flags().StringVarP(&archiveFqbn, "fqbn", "b", "", "Fully Qualified Board Name used to find the libraries used by the sketch, e.g.: arduino:avr:uno. If not set the FQBN saved in the sketch.json file is used.")
 
 	return command
 }
@@ -73,11 +83,20 @@ func runArchiveCommand(cmd *cobra.Command, args []string) {
 		archivePath = args[1]
 	}
 
+	var inst *rpc.Instance
+	if includeLibraries || includeProfile {
+		inst = instance.CreateAndInit()
+	}
+
 	_, err := sketch.ArchiveSketch(context.Background(),
 		&rpc.ArchiveSketchRequest{
-			SketchPath:      sketchPath,
-			ArchivePath:     archivePath,
-			IncludeBuildDir: includeBuildDir,
+			Instance:         inst,
+			SketchPath:       sketchPath,
+			ArchivePath:      archivePath,
+			IncludeBuildDir:  includeBuildDir,
+			Fqbn:             archiveFqbn,
+			IncludeLibraries: includeLibraries,
+			IncludeProfile:   includeProfile,
 		})
 
 	if err != nil {
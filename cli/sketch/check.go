@@ -0,0 +1,120 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package sketch
+
+import (
+	"os"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino/sketches/check"
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/go-paths-helper"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var checkFlags struct {
+	failOnWarning bool
+}
+
+// initCheckCommand creates a new `check` command
+func initCheckCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "check [<sketchPath>]",
+		Short: "Checks a sketch for common problems.",
+		Long: "Checks the structure of a sketch (the name of the main file, the definitions of setup() and loop(), " +
+			"the .pde and .ino files), the library.properties of the libraries it contains, the secrets in arduino_secrets.h " +
+			"not ignored by git and the includes that don't work on other systems or boards. " +
+			"Use --format json to get the diagnostics in a machine readable format.",
+		Example: "" +
+			"  " + os.Args[0] + " sketch check\n" +
+			"  " + os.Args[0] + " sketch check /home/user/Arduino/MySketch --format json",
+		Args: cobra.MaximumNArgs(1),
+		Run:  runCheckCommand,
+	}
+
+	command.Flags().BoolVar(&checkFlags.failOnWarning, "fail-on-warning", false, "Exit with an error also if only warnings are found.")
+
+	return command
+}
+
+func runCheckCommand(cmd *cobra.Command, args []string) {
+	logrus.Info("Executing `arduino sketch check`")
+
+	sketchPath := paths.New(".")
+	if len(args) == 1 {
+		sketchPath = paths.New(args[0])
+	}
+	diagnostics, err := check.Sketch(sketchPath)
+	if err != nil {
+		feedback.Errorf("Error checking sketch: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+
+	// The paths of the diagnostics are relative to the sketch folder
+	if abs, err := sketchPath.Abs(); err == nil {
+		sketchPath = abs
+	}
+	if !sketchPath.IsDir() {
+		sketchPath = sketchPath.Parent()
+	}
+	feedback.PrintResult(&checkResult{Diagnostics: diagnostics, sketchPath: sketchPath})
+	for _, d := range diagnostics {
+		if d.Severity == check.SeverityError || checkFlags.failOnWarning {
+			os.Exit(errorcodes.ErrGeneric)
+		}
+	}
+}
+
+type checkResult struct {
+	Diagnostics []*check.Diagnostic `json:"diagnostics"`
+
+	sketchPath *paths.Path
+}
+
+func (r *checkResult) Data() interface{} {
+	return r
+}
+
+func (r *checkResult) Annotations() []*feedback.Annotation {
+	res := []*feedback.Annotation{}
+	for _, d := range r.Diagnostics {
+		file := d.File
+		if file != "" {
+			file = r.sketchPath.Join(file).String()
+		}
+		res = append(res, &feedback.Annotation{
+			Severity: string(d.Severity),
+			File:     file,
+			Line:     d.Line,
+			Title:    d.Rule,
+			Message:  d.Message,
+		})
+	}
+	return res
+}
+
+func (r *checkResult) String() string {
+	if len(r.Diagnostics) == 0 {
+		return "No problems found."
+	}
+	lines := []string{}
+	for _, d := range r.Diagnostics {
+		lines = append(lines, d.String())
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,133 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package sketch
+
+import (
+	"context"
+	"os"
+
+	"github.com/arduino/arduino-cli/cli/core"
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/arduino-cli/cli/globals"
+	"github.com/arduino/arduino-cli/cli/instance"
+	"github.com/arduino/arduino-cli/cli/lib"
+	"github.com/arduino/arduino-cli/cli/output"
+	corecmds "github.com/arduino/arduino-cli/commands/core"
+	libcmds "github.com/arduino/arduino-cli/commands/lib"
+	"github.com/arduino/arduino-cli/commands/sketch"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var cloneFlags struct {
+	fqbn        string
+	installDeps bool
+}
+
+// initCloneCommand creates a new `clone` command
+func initCloneCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "clone <gitURL> [<directory>]",
+		Short: "Clones a sketch from a git repository.",
+		Long: "Clones a sketch from a git repository, validates it and initializes its sketch.json file if missing. " +
+			"The platform and the libraries required by the sketch can be installed at the same time, so the sketch is ready to be compiled.",
+		Example: "" +
+			"  " + os.Args[0] + " sketch clone https://github.com/user/MySketch.git\n" +
+			"  " + os.Args[0] + " sketch clone https://github.com/user/MySketch.git MySketch --fqbn arduino:avr:uno\n" +
+			"  " + os.Args[0] + " sketch clone https://github.com/user/MySketch.git --install-deps",
+		Args: cobra.RangeArgs(1, 2),
+		Run:  runCloneCommand,
+	}
+
+	command.Flags().StringVarP(&cloneFlags.fqbn, "fqbn", "b", "", "FQBN saved in the sketch.json file if the sketch doesn't define one, e.g.: arduino:avr:uno")
+	command.Flags().BoolVar(&cloneFlags.installDeps, "install-deps", false, "Installs the platform and the libraries required by the sketch.")
+	core.AddPostInstallFlagsToCommand(command)
+
+	return command
+}
+
+func runCloneCommand(cmd *cobra.Command, args []string) {
+	logrus.Info("Executing `arduino sketch clone`")
+
+	destination := ""
+	if len(args) == 2 {
+		destination = args[1]
+	}
+
+	inst := instance.CreateAndInit()
+	res, err := sketch.CloneSketch(context.Background(), &rpc.CloneSketchRequest{
+		Instance:    inst,
+		Url:         args[0],
+		Destination: destination,
+		Fqbn:        cloneFlags.fqbn,
+	})
+	if err != nil {
+		feedback.Errorf("Error cloning sketch: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+
+	if cloneFlags.installDeps {
+		installSketchDependencies(inst, res.GetPlatform(), res.GetLibraries())
+	}
+
+	feedback.Print("Sketch cloned in: " + res.GetSketchPath())
+	if res.GetFqbn() == "" {
+		feedback.Print("The sketch doesn't define a board, use the --fqbn flag to compile it.")
+	}
+}
+
+// installSketchDependencies installs the platform and the libraries required
+// by a cloned or imported sketch
+func installSketchDependencies(inst *rpc.Instance, platform string, libraries []string) {
+	if platform != "" {
+		platformRefs, err := globals.ParseReferenceArgs([]string{platform}, true)
+		if err != nil {
+			feedback.Errorf("Invalid platform required by the sketch: %v", err)
+			os.Exit(errorcodes.ErrGeneric)
+		}
+		platformRef := platformRefs[0]
+		_, err = corecmds.PlatformInstall(context.Background(), &rpc.PlatformInstallRequest{
+			Instance:        inst,
+			PlatformPackage: platformRef.PackageName,
+			Architecture:    platformRef.Architecture,
+			Version:         platformRef.Version,
+			SkipPostInstall: core.DetectSkipPostInstallValue(),
+		}, output.ProgressBar(), output.TaskProgress())
+		if err != nil {
+			feedback.Errorf("Error installing platform %s: %v", platform, err)
+			os.Exit(errorcodes.ErrGeneric)
+		}
+	}
+
+	libRefs, err := lib.ParseLibraryReferenceArgs(libraries)
+	if err != nil {
+		feedback.Errorf("Invalid library required by the sketch: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+	for _, libRef := range libRefs {
+		err := libcmds.LibraryInstall(context.Background(), &rpc.LibraryInstallRequest{
+			Instance: inst,
+			Name:     libRef.Name,
+			Version:  libRef.Version,
+		}, output.ProgressBar(), output.TaskProgress())
+		if err != nil {
+			feedback.Errorf("Error installing %s: %v", libRef.Name, err)
+			os.Exit(errorcodes.ErrGeneric)
+		}
+	}
+}
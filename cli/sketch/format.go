@@ -0,0 +1,145 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package sketch
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino/sketches/format"
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/arduino-cli/cli/instance"
+	"github.com/arduino/arduino-cli/cli/output"
+	"github.com/arduino/arduino-cli/commands"
+	"github.com/arduino/go-paths-helper"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var formatFlags struct {
+	check            bool
+	includeLibraries bool
+	dumpConfig       bool
+}
+
+// initFormatCommand creates a new `format` command
+func initFormatCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "format [<sketchPath>]",
+		Short: "Formats the sources of a sketch.",
+		Long: "Formats the sources of a sketch with clang-format, using the .clang-format file found in the sketch folder " +
+			"or in one of its parents or, if not found, the Arduino style. clang-format is installed from the package " +
+			"indexes if a platform provides it, otherwise it's searched in the PATH. " +
+			"Use --check to only list the files that are not formatted, without modifying them.",
+		Example: "" +
+			"  " + os.Args[0] + " sketch format\n" +
+			"  " + os.Args[0] + " sketch format /home/user/Arduino/MySketch --check\n" +
+			"  " + os.Args[0] + " sketch format --dump-config > .clang-format",
+		Args: cobra.MaximumNArgs(1),
+		Run:  runFormatCommand,
+	}
+
+	command.Flags().BoolVar(&formatFlags.check, "check", false, "Don't modify the files, exit with an error if some are not formatted.")
+	command.Flags().BoolVar(&formatFlags.includeLibraries, "include-libraries", false, "Also format the libraries in the libraries folder of the sketch.")
+	command.Flags().BoolVar(&formatFlags.dumpConfig, "dump-config", false, "Print the default Arduino style clang-format configuration and exit.")
+
+	return command
+}
+
+func runFormatCommand(cmd *cobra.Command, args []string) {
+	logrus.Info("Executing `arduino sketch format`")
+
+	if formatFlags.dumpConfig {
+		feedback.Print(strings.TrimSpace(format.DefaultStyle))
+		return
+	}
+
+	sketchPath := paths.New(".")
+	if len(args) == 1 {
+		sketchPath = paths.New(args[0])
+	}
+	sketchPath, err := sketchPath.Abs()
+	if err != nil {
+		feedback.Errorf("Invalid sketch path: %v", err)
+		os.Exit(errorcodes.ErrBadArgument)
+	}
+	if !sketchPath.IsDir() {
+		sketchPath = sketchPath.Parent()
+	}
+
+	files, err := format.Files(sketchPath, formatFlags.includeLibraries)
+	if err != nil {
+		feedback.Errorf("Error formatting sketch: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+
+	inst := instance.CreateAndInit()
+	clangFormat, err := commands.GetClangFormat(commands.GetPackageManager(inst.GetId()), commands.GetSettings(inst.GetId()), output.ProgressBar(), output.TaskProgress())
+	if err != nil {
+		feedback.Errorf("Error formatting sketch: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+
+	style := format.Style(sketchPath)
+	res := &formatResult{Check: formatFlags.check, Files: []string{}}
+	for _, file := range files {
+		formatted, changed, err := format.Format(clangFormat, file, style)
+		if err != nil {
+			feedback.Errorf("Error formatting sketch: %v", err)
+			os.Exit(errorcodes.ErrGeneric)
+		}
+		if !changed {
+			continue
+		}
+		if !formatFlags.check {
+			if err := file.WriteFile(formatted); err != nil {
+				feedback.Errorf("Error formatting sketch: %v", err)
+				os.Exit(errorcodes.ErrGeneric)
+			}
+		}
+		rel, err := sketchPath.RelTo(file)
+		if err != nil {
+			rel = file
+		}
+		res.Files = append(res.Files, filepath.ToSlash(rel.String()))
+	}
+
+	feedback.PrintResult(res)
+	if formatFlags.check && len(res.Files) > 0 {
+		os.Exit(errorcodes.ErrGeneric)
+	}
+}
+
+type formatResult struct {
+	Check bool     `json:"check"`
+	Files []string `json:"files"`
+}
+
+func (r *formatResult) Data() interface{} {
+	return r
+}
+
+func (r *formatResult) String() string {
+	if len(r.Files) == 0 {
+		return "All the files are formatted."
+	}
+	if r.Check {
+		return "Files not formatted:\n" + strings.Join(r.Files, "\n")
+	}
+	return "Formatted files:\n" + strings.Join(r.Files, "\n")
+}
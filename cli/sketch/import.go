@@ -0,0 +1,95 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package sketch
+
+import (
+	"context"
+	"os"
+
+	"github.com/arduino/arduino-cli/cli/core"
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/arduino-cli/cli/instance"
+	"github.com/arduino/arduino-cli/commands/sketch"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var importFlags struct {
+	from        string
+	env         string
+	fqbn        string
+	installDeps bool
+}
+
+// initImportCommand creates a new `import` command
+func initImportCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "import --from platformio <projectPath> [<directory>]",
+		Short: "Creates a sketch from a PlatformIO project.",
+		Long: "Creates a sketch from a PlatformIO project: the sources of the project are copied in the sketch, " +
+			"the board of the environment is converted to the FQBN saved in the sketch.json file and " +
+			"the libraries listed in lib_deps are installed.",
+		Example: "" +
+			"  " + os.Args[0] + " sketch import --from platformio ~/Projects/blink\n" +
+			"  " + os.Args[0] + " sketch import --from platformio ~/Projects/blink ~/Arduino/Blink --env esp32\n" +
+			"  " + os.Args[0] + " sketch import --from platformio ~/Projects/blink --fqbn arduino:avr:nano --install-deps=false",
+		Args: cobra.RangeArgs(1, 2),
+		Run:  runImportCommand,
+	}
+
+	command.Flags().StringVar(&importFlags.from, "from", "platformio", "Build system of the project, only platformio is supported.")
+	command.Flags().StringVar(&importFlags.env, "env", "", "Environment of the project to import, if not set the default one.")
+	command.Flags().StringVarP(&importFlags.fqbn, "fqbn", "b", "", "FQBN saved in the sketch.json file instead of the one of the board of the environment, e.g.: arduino:avr:uno")
+	command.Flags().BoolVar(&importFlags.installDeps, "install-deps", true, "Installs the platform and the libraries required by the sketch.")
+	core.AddPostInstallFlagsToCommand(command)
+
+	return command
+}
+
+func runImportCommand(cmd *cobra.Command, args []string) {
+	logrus.Info("Executing `arduino sketch import`")
+
+	destination := ""
+	if len(args) == 2 {
+		destination = args[1]
+	}
+
+	res, err := sketch.ImportSketch(context.Background(), &rpc.ImportSketchRequest{
+		From:        importFlags.from,
+		ProjectPath: args[0],
+		Destination: destination,
+		Env:         importFlags.env,
+		Fqbn:        importFlags.fqbn,
+	})
+	if err != nil {
+		feedback.Errorf("Error importing project: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+	for _, warning := range res.GetWarnings() {
+		feedback.Errorf("Warning: %s", warning)
+	}
+
+	if importFlags.installDeps {
+		installSketchDependencies(instance.CreateAndInit(), res.GetPlatform(), res.GetLibraries())
+	}
+
+	feedback.Print("Sketch created in: " + res.GetSketchPath())
+	if res.GetFqbn() == "" {
+		feedback.Print("The sketch doesn't define a board, use the --fqbn flag to compile it.")
+	}
+}
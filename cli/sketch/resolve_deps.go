@@ -0,0 +1,105 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package sketch
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/arduino-cli/cli/instance"
+	"github.com/arduino/arduino-cli/cli/lib"
+	"github.com/arduino/arduino-cli/commands/compile"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var resolveDepsFlags struct {
+	fqbn string
+}
+
+// initResolveDepsCommand creates a new `resolve-deps` command
+func initResolveDepsCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "resolve-deps [<sketchPath>]",
+		Short: "Installs the libraries included by a sketch.",
+		Long: "Preprocesses the sketch for the given board and, for each #include that can't be resolved with the installed libraries, " +
+			"installs the library of the libraries index that provides the header.",
+		Example: "" +
+			"  " + os.Args[0] + " sketch resolve-deps -b arduino:avr:uno\n" +
+			"  " + os.Args[0] + " sketch resolve-deps -b arduino:avr:uno /home/user/Arduino/MySketch",
+		Args: cobra.MaximumNArgs(1),
+		Run:  runResolveDepsCommand,
+	}
+
+	command.Flags().StringVarP(&resolveDepsFlags.fqbn, "fqbn", "b", "", "Fully Qualified Board Name, e.g.: arduino:avr:uno. If not set the FQBN saved in the sketch.json file is used.")
+
+	return command
+}
+
+func runResolveDepsCommand(cmd *cobra.Command, args []string) {
+	logrus.Info("Executing `arduino sketch resolve-deps`")
+
+	sketchPath := paths.New(".")
+	if len(args) == 1 {
+		sketchPath = paths.New(args[0])
+	}
+	sketchPath, err := sketchPath.Abs()
+	if err != nil {
+		feedback.Errorf("Invalid sketch path: %v", err)
+		os.Exit(errorcodes.ErrBadArgument)
+	}
+
+	inst := instance.CreateAndInit()
+	req := &rpc.CompileRequest{
+		Instance:   inst,
+		Fqbn:       resolveDepsFlags.fqbn,
+		SketchPath: sketchPath.String(),
+		Preprocess: true,
+	}
+	out := new(bytes.Buffer)
+	errOut := new(bytes.Buffer)
+	_, installed, err := lib.InstallMissingIncludes(inst, func() (*rpc.CompileResponse, error) {
+		out.Reset()
+		errOut.Reset()
+		return compile.Compile(context.Background(), req, out, errOut, nil, false)
+	})
+	feedback.PrintResult(&resolveDepsResult{InstalledLibraries: installed})
+	if err != nil {
+		feedback.Errorf("Error resolving the sketch dependencies: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+}
+
+type resolveDepsResult struct {
+	InstalledLibraries []string `json:"installed_libraries"`
+}
+
+func (r *resolveDepsResult) Data() interface{} {
+	return r
+}
+
+func (r *resolveDepsResult) String() string {
+	if len(r.InstalledLibraries) == 0 {
+		return "No library has been installed."
+	}
+	return "Installed libraries:\n  " + strings.Join(r.InstalledLibraries, "\n  ")
+}
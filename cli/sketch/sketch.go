@@ -32,6 +32,11 @@ func NewCommand() *cobra.Command {
 
 	cmd.AddCommand(initNewCommand())
 	cmd.AddCommand(initArchiveCommand())
+	cmd.AddCommand(initCloneCommand())
+	cmd.AddCommand(initImportCommand())
+	cmd.AddCommand(initCheckCommand())
+	cmd.AddCommand(initFormatCommand())
+	cmd.AddCommand(initResolveDepsCommand())
 
 	return cmd
 }
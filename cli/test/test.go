@@ -0,0 +1,182 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/arduino-cli/cli/instance"
+	"github.com/arduino/arduino-cli/commands/test"
+	"github.com/arduino/go-paths-helper"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	harness     string
+	harnessPath string
+	filter      string
+	cc          string
+	cxx         string
+	flags       []string
+	timeout     time.Duration
+	junit       string
+	verbose     bool
+	onTarget    bool
+	fqbn        string
+	port        string
+	baudRate    int
+)
+
+// NewCommand created a new `test` command
+func NewCommand() *cobra.Command {
+	testCommand := &cobra.Command{
+		Use:   "test [<path>]",
+		Short: "Runs the unit tests of a library or sketch on the host.",
+		Long: "Compiles the test programs in the test folder of a library or sketch together with the code under test " +
+			"(the src folder) and a host mock of the Arduino core, runs them natively and reports the results. " +
+			"Each test_*.cpp or *_test.cpp file and each subfolder of the test folder is a test program, the other " +
+			"sources in the test folder are linked in all of them. With --harness unity or gtest the tests are " +
+			"written with the Unity or GoogleTest frameworks and each test is reported separately. " +
+			"With --on-target each test program is built as a sketch, uploaded to the board and the results printed by Unity " +
+			"on the serial port are collected.",
+		Example: "" +
+			"  " + os.Args[0] + " test ~/Arduino/libraries/MyLibrary\n" +
+			"  " + os.Args[0] + " test --harness unity --harness-path ~/Unity/src --junit report.xml\n" +
+			"  " + os.Args[0] + " test --harness gtest --run parser --timeout 10s\n" +
+			"  " + os.Args[0] + " test --on-target -b arduino:avr:uno -p /dev/ttyACM0 --harness unity --harness-path ~/Unity/src",
+		Args: cobra.MaximumNArgs(1),
+		Run:  run,
+	}
+
+	harnesses := []string{}
+	for _, h := range test.Harnesses {
+		harnesses = append(harnesses, string(h))
+	}
+	testCommand.Flags().StringVar(&harness, "harness", string(test.HarnessNone), "Test framework used by the tests: "+strings.Join(harnesses, ", ")+".")
+	testCommand.Flags().StringVar(&harnessPath, "harness-path", "", "Folder containing the sources of the test framework (unity.c for Unity).")
+	testCommand.Flags().StringVar(&filter, "run", "", "Run only the test programs whose name contains the given string.")
+	testCommand.Flags().StringVar(&cc, "cc", "", "Host C compiler, by default $CC or cc.")
+	testCommand.Flags().StringVar(&cxx, "cxx", "", "Host C++ compiler, by default $CXX or c++.")
+	testCommand.Flags().StringSliceVar(&flags, "flags", []string{}, "Additional compiler flags, e.g. --flags=-DDEBUG,-O2")
+	testCommand.Flags().DurationVar(&timeout, "timeout", time.Minute, "Maximum run time of each test program, or maximum time to wait for each test result with --on-target, 0 for no limit.")
+	testCommand.Flags().StringVar(&junit, "junit", "", "Also write the results in the JUnit XML format to the given file.")
+	testCommand.Flags().BoolVarP(&verbose, "verbose", "v", false, "Print the compiler command lines and the output of the tests.")
+	testCommand.Flags().BoolVar(&onTarget, "on-target", false, "Run the tests on the board instead of the host (requires the Unity harness).")
+	testCommand.Flags().StringVarP(&fqbn, "fqbn", "b", "", "Fully Qualified Board Name of the board running the tests, e.g.: arduino:avr:uno")
+	testCommand.Flags().StringVarP(&port, "port", "p", "", "Upload port of the board running the tests, e.g.: COM10 or /dev/ttyACM0")
+	testCommand.Flags().IntVar(&baudRate, "baudrate", 115200, "Speed of the serial port used by the tests on the board to report the results.")
+
+	return testCommand
+}
+
+func run(command *cobra.Command, args []string) {
+	logrus.Info("Executing `arduino test`")
+
+	path := paths.New(".")
+	if len(args) > 0 {
+		path = paths.New(args[0])
+	}
+	req := &test.Request{
+		Path:        path,
+		Harness:     test.Harness(harness),
+		Filter:      filter,
+		CCompiler:   cc,
+		CXXCompiler: cxx,
+		Flags:       flags,
+		Timeout:     timeout,
+		Verbose:     verbose,
+	}
+	if harnessPath != "" {
+		req.HarnessPath = paths.New(harnessPath)
+	}
+
+	var res *test.Result
+	var err error
+	if onTarget {
+		inst := instance.CreateAndInit()
+		res, err = test.RunOnTarget(context.Background(), &test.TargetRequest{
+			Request:  *req,
+			Instance: inst,
+			Fqbn:     fqbn,
+			Port:     port,
+			BaudRate: baudRate,
+		}, feedback.OutputWriter(), feedback.ErrorWriter())
+	} else {
+		res, err = test.Run(req, feedback.OutputWriter(), feedback.ErrorWriter())
+	}
+	if err != nil {
+		feedback.Errorf("Error running tests: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+
+	if junit != "" {
+		file, err := os.Create(junit)
+		if err != nil {
+			feedback.Errorf("Error writing JUnit report: %v", err)
+			os.Exit(errorcodes.ErrGeneric)
+		}
+		err = test.WriteJUnit(res, file)
+		file.Close()
+		if err != nil {
+			feedback.Errorf("Error writing JUnit report: %v", err)
+			os.Exit(errorcodes.ErrGeneric)
+		}
+	}
+
+	feedback.PrintResult(&testResult{res})
+	if !res.Success() {
+		os.Exit(errorcodes.ErrTestFailed)
+	}
+}
+
+type testResult struct {
+	*test.Result
+}
+
+func (r *testResult) Data() interface{} {
+	return r.Result
+}
+
+func (r *testResult) String() string {
+	lines := []string{}
+	for _, suite := range r.Suites {
+		failed := false
+		for _, c := range suite.Cases {
+			line := fmt.Sprintf("%-7s %s", strings.ToUpper(string(c.Status)), c.Name)
+			if c.Name != suite.Name {
+				line += " (" + suite.Name + ")"
+			}
+			if c.Message != "" {
+				line += ": " + c.Message
+			}
+			lines = append(lines, line)
+			failed = failed || c.Status == test.StatusFailed || c.Status == test.StatusError
+		}
+		// The output was already printed while running in verbose mode
+		if failed && suite.Output != "" && !verbose {
+			lines = append(lines, "  "+strings.ReplaceAll(strings.TrimRight(suite.Output, "\n"), "\n", "\n  "))
+		}
+	}
+	lines = append(lines, fmt.Sprintf("\n%d passed, %d failed, %d skipped, %d errors", r.Passed, r.Failed, r.Skipped, r.Errors))
+	return strings.Join(lines, "\n")
+}
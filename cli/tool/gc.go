@@ -0,0 +1,100 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package tool
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/arduino-cli/cli/instance"
+	"github.com/arduino/arduino-cli/cli/output"
+	"github.com/arduino/arduino-cli/commands/core"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/arduino-cli/table"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var gcFlags struct {
+	dryRun bool
+}
+
+func initGCCommand() *cobra.Command {
+	gcCommand := &cobra.Command{
+		Use:   "gc",
+		Short: "Removes the tools not required by any installed platform.",
+		Long:  "Removes the installed tools that are not required by any installed platform, like the old versions left after a platform upgrade.",
+		Example: "" +
+			"  " + os.Args[0] + " tool gc\n" +
+			"  " + os.Args[0] + " tool gc --dry-run",
+		Args: cobra.NoArgs,
+		Run:  runGCCommand,
+	}
+	gcCommand.Flags().BoolVar(&gcFlags.dryRun, "dry-run", false, "Only show the tools that would be removed.")
+	return gcCommand
+}
+
+func runGCCommand(cmd *cobra.Command, args []string) {
+	inst := instance.CreateAndInit()
+	logrus.Info("Executing `arduino tool gc`")
+
+	GarbageCollect(inst, gcFlags.dryRun)
+}
+
+// GarbageCollect removes the tools not required by any installed platform
+// and prints the report of the removed tools
+func GarbageCollect(inst *rpc.Instance, dryRun bool) {
+	res, err := core.ToolsGarbageCollect(context.Background(), &rpc.ToolsGarbageCollectRequest{
+		Instance: inst,
+		DryRun:   dryRun,
+	})
+	if err != nil {
+		feedback.Errorf("Error removing unused tools: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+	feedback.PrintResult(gcResult{tools: res.GetTools(), dryRun: dryRun})
+}
+
+type gcResult struct {
+	tools  []*rpc.UnusedTool
+	dryRun bool
+}
+
+func (r gcResult) Data() interface{} {
+	return r.tools
+}
+
+func (r gcResult) String() string {
+	if len(r.tools) == 0 {
+		return "No unused tools found."
+	}
+
+	t := table.New()
+	t.SetHeader("ID", "Version", "Size")
+	total := int64(0)
+	for _, tool := range r.tools {
+		t.AddRow(tool.GetPackager()+":"+tool.GetName(), tool.GetVersion(), output.FormatSize(tool.GetSize()))
+		total += tool.GetSize()
+	}
+	summary := fmt.Sprintf("Removed %d unused tools, %s freed.", len(r.tools), output.FormatSize(total))
+	if r.dryRun {
+		summary = fmt.Sprintf("%d unused tools would be removed, %s would be freed.", len(r.tools), output.FormatSize(total))
+	}
+	return t.Render() + "\n" + summary
+}
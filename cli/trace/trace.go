@@ -0,0 +1,183 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package trace
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/arduino/arduino-cli/cli/errorcodes"
+	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/arduino-cli/cli/instance"
+	"github.com/arduino/arduino-cli/commands/debug"
+	dbg "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/debug/v1"
+	"github.com/arduino/go-paths-helper"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fqbn         string
+	port         string
+	programmer   string
+	importDir    string
+	backend      string
+	channels     []uint
+	traceClock   uint32
+	swoFrequency uint32
+	rttAddress   string
+	rttSize      string
+	outputs      []string
+)
+
+// NewCommand created a new `trace` command
+func NewCommand() *cobra.Command {
+	traceCommand := &cobra.Command{
+		Use:   "trace",
+		Short: "Captures the trace output of the board.",
+		Long: "Captures the trace output of ARM boards through a debug probe: the ITM stimulus ports sent on the SWO pin (--backend swo) or the RTT up-buffers (--backend rtt). " +
+			"The data of each channel is written to stdout or to the file given with --output.",
+		Example: "" +
+			"  " + os.Args[0] + " trace -b arduino:samd:mkr1000 -P atmel_ice --backend swo --trace-clock 48000000 /home/user/Arduino/MySketch\n" +
+			"  " + os.Args[0] + " trace -b arduino:samd:mkr1000 -P atmel_ice --backend rtt --channel 0,1 --output 1=data.bin",
+		Args: cobra.MaximumNArgs(1),
+		Run:  run,
+	}
+
+	traceCommand.Flags().StringVarP(&fqbn, "fqbn", "b", "", "Fully Qualified Board Name, e.g.: arduino:avr:uno")
+	traceCommand.Flags().StringVarP(&port, "port", "p", "", "Debug port, e.g.: COM10 or /dev/ttyACM0")
+	traceCommand.Flags().StringVarP(&programmer, "programmer", "P", "", "Programmer to use for debugging")
+	traceCommand.Flags().StringVarP(&importDir, "input-dir", "", "", "Directory containing binaries for debug.")
+	traceCommand.Flags().StringVar(&backend, "backend", "", "Trace backend: swo or rtt.")
+	traceCommand.Flags().UintSliceVar(&channels, "channel", []uint{0}, "ITM stimulus ports (swo) or RTT up-buffers (rtt) to capture.")
+	traceCommand.Flags().Uint32Var(&traceClock, "trace-clock", 0, "Frequency in Hz of the trace clock, usually the CPU clock (swo).")
+	traceCommand.Flags().Uint32Var(&swoFrequency, "swo-frequency", 0, "Frequency in Hz of the SWO pin, the highest supported by the probe if not set (swo).")
+	traceCommand.Flags().StringVar(&rttAddress, "rtt-address", "", "Start address of the memory searched for the RTT control block (rtt).")
+	traceCommand.Flags().StringVar(&rttSize, "rtt-size", "", "Size of the memory searched for the RTT control block (rtt).")
+	traceCommand.Flags().StringArrayVar(&outputs, "output", []string{}, "Write a channel to a file instead of stdout, in the form CHANNEL=FILE. Can be used multiple times.")
+	traceCommand.MarkFlagRequired("backend")
+
+	return traceCommand
+}
+
+func run(command *cobra.Command, args []string) {
+	instance := instance.CreateAndInit()
+	logrus.Info("Executing `arduino trace`")
+
+	if backend != "swo" && backend != "rtt" {
+		feedback.Errorf("Invalid trace backend: %s", backend)
+		os.Exit(errorcodes.ErrBadArgument)
+	}
+	address, err := parseNumber(rttAddress)
+	if err != nil {
+		feedback.Errorf("Invalid RTT address: %s", rttAddress)
+		os.Exit(errorcodes.ErrBadArgument)
+	}
+	size, err := parseNumber(rttSize)
+	if err != nil {
+		feedback.Errorf("Invalid RTT size: %s", rttSize)
+		os.Exit(errorcodes.ErrBadArgument)
+	}
+
+	files := map[uint32]*os.File{}
+	for _, output := range outputs {
+		split := strings.SplitN(output, "=", 2)
+		channel, err := strconv.ParseUint(split[0], 10, 32)
+		if len(split) != 2 || split[1] == "" || err != nil {
+			feedback.Errorf("Invalid output, expected CHANNEL=FILE: %s", output)
+			os.Exit(errorcodes.ErrBadArgument)
+		}
+		file, err := os.Create(split[1])
+		if err != nil {
+			feedback.Errorf("Error creating output file: %v", err)
+			os.Exit(errorcodes.ErrGeneric)
+		}
+		defer file.Close()
+		files[uint32(channel)] = file
+	}
+
+	var path *paths.Path
+	if len(args) > 0 {
+		path = paths.New(args[0])
+	}
+	sketchPath := initSketchPath(path)
+
+	req := &dbg.TraceRequest{
+		DebugRequest: &dbg.DebugConfigRequest{
+			Instance:   instance,
+			Fqbn:       fqbn,
+			SketchPath: sketchPath.String(),
+			Port:       port,
+			ImportDir:  importDir,
+			Programmer: programmer,
+		},
+		Backend:      backend,
+		TraceClock:   traceClock,
+		SwoFrequency: swoFrequency,
+		RttAddress:   address,
+		RttSize:      size,
+	}
+	for _, channel := range channels {
+		req.Channels = append(req.Channels, uint32(channel))
+	}
+
+	// Stop the capture on CTRL-C
+	ctx, cancel := context.WithCancel(context.Background())
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-interrupt
+		cancel()
+	}()
+
+	out := func(channel uint32, data []byte) {
+		if file, ok := files[channel]; ok {
+			file.Write(data)
+		} else {
+			os.Stdout.Write(data)
+		}
+	}
+	if err := debug.Trace(ctx, req, out, os.Stderr); err != nil {
+		feedback.Errorf("Error during trace: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+}
+
+func parseNumber(s string) (uint64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(s, 0, 64)
+}
+
+// initSketchPath returns the current working directory
+func initSketchPath(sketchPath *paths.Path) *paths.Path {
+	if sketchPath != nil {
+		return sketchPath
+	}
+
+	wd, err := paths.Getwd()
+	if err != nil {
+		feedback.Errorf("Couldn't get current working directory: %v", err)
+		os.Exit(errorcodes.ErrGeneric)
+	}
+	logrus.Infof("Reading sketch from dir: %s", wd)
+	return wd
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/arduino/arduino-cli/cli/errorcodes"
 	"github.com/arduino/arduino-cli/cli/feedback"
+	"github.com/arduino/arduino-cli/cli/index"
 	"github.com/arduino/arduino-cli/cli/instance"
 	"github.com/arduino/arduino-cli/cli/output"
 	"github.com/arduino/arduino-cli/commands"
@@ -41,11 +42,13 @@ func NewCommand() *cobra.Command {
 		Run:     runUpdateCommand,
 	}
 	updateCommand.Flags().BoolVar(&updateFlags.showOutdated, "show-outdated", false, "Show outdated cores and libraries after index update")
+	updateCommand.Flags().BoolVar(&updateFlags.showDiff, "show-diff", false, "Show the new cores and libraries, and the new versions of the installed ones, after index update")
 	return updateCommand
 }
 
 var updateFlags struct {
 	showOutdated bool
+	showDiff     bool
 }
 
 func runUpdateCommand(cmd *cobra.Command, args []string) {
@@ -77,13 +80,19 @@ func runUpdateCommand(cmd *cobra.Command, args []string) {
 		os.Exit(errorcodes.ErrGeneric)
 	}
 
-	if updateFlags.showOutdated {
+	if updateFlags.showOutdated || updateFlags.showDiff {
 		// To show outdated platforms and libraries we need to initialize our instance
 		// otherwise nothing would be shown
 		for _, err := range instance.Init(inst) {
 			feedback.Errorf("Error initializing instance: %v", err)
 		}
+	}
 
+	if updateFlags.showDiff {
+		index.PrintDiff(inst, false)
+	}
+
+	if updateFlags.showOutdated {
 		outdatedResp, err := commands.Outdated(context.Background(), &rpc.OutdatedRequest{
 			Instance: inst,
 		})
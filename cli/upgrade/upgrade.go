@@ -23,6 +23,7 @@ import (
 	"github.com/arduino/arduino-cli/cli/feedback"
 	"github.com/arduino/arduino-cli/cli/instance"
 	"github.com/arduino/arduino-cli/cli/output"
+	"github.com/arduino/arduino-cli/cli/tool"
 	"github.com/arduino/arduino-cli/commands"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
 	"github.com/sirupsen/logrus"
@@ -41,9 +42,12 @@ func NewCommand() *cobra.Command {
 	}
 
 	core.AddPostInstallFlagsToCommand(upgradeCommand)
+	upgradeCommand.Flags().BoolVar(&gc, "gc", false, "Remove the tools not required anymore by any installed platform after the upgrade.")
 	return upgradeCommand
 }
 
+var gc bool
+
 func runUpgradeCommand(cmd *cobra.Command, args []string) {
 	inst := instance.CreateAndInit()
 	logrus.Info("Executing `arduino upgrade`")
@@ -55,6 +59,8 @@ func runUpgradeCommand(cmd *cobra.Command, args []string) {
 
 	if err != nil {
 		feedback.Errorf("Error upgrading: %v", err)
+	} else if gc {
+		tool.GarbageCollect(inst, false)
 	}
 
 	logrus.Info("Done")
@@ -17,48 +17,80 @@ package upload
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
 
+	"github.com/arduino/arduino-cli/arduino/remote"
 	"github.com/arduino/arduino-cli/arduino/sketches"
+	"github.com/arduino/arduino-cli/cli/artifacts"
+	"github.com/arduino/arduino-cli/cli/board"
 	"github.com/arduino/arduino-cli/cli/errorcodes"
 	"github.com/arduino/arduino-cli/cli/feedback"
 	"github.com/arduino/arduino-cli/cli/instance"
+	"github.com/arduino/arduino-cli/cli/output"
 	"github.com/arduino/arduino-cli/commands/upload"
+	"github.com/arduino/arduino-cli/configuration"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
 	"github.com/arduino/go-paths-helper"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 var (
-	fqbn       string
-	port       string
-	verbose    bool
-	verify     bool
-	importDir  string
-	importFile string
-	programmer string
+	fqbn             string
+	port             string
+	verbose          bool
+	verify           bool
+	verifyReadback   bool
+	importDir        string
+	importFile       string
+	programmer       string
+	uploadSpeed      uint32
+	toolArgs         []string
+	networkPassword  string
+	noAutodetect     bool
+	artifact         string
+	remoteHost       string
+	discoveryTimeout string
+	uploadRetries    uint32
+	dryRun           bool
 )
 
 // NewCommand created a new `upload` command
 func NewCommand() *cobra.Command {
 	uploadCommand := &cobra.Command{
-		Use:     "upload",
-		Short:   "Upload Arduino sketches.",
-		Long:    "Upload Arduino sketches. This does NOT compile the sketch prior to upload.",
-		Example: "  " + os.Args[0] + " upload /home/user/Arduino/MySketch",
-		Args:    cobra.MaximumNArgs(1),
-		PreRun:  checkFlagsConflicts,
-		Run:     run,
+		Use:   "upload",
+		Short: "Upload Arduino sketches.",
+		Long:  "Upload Arduino sketches. This does NOT compile the sketch prior to upload.",
+		Example: "" +
+			"  " + os.Args[0] + " upload /home/user/Arduino/MySketch\n" +
+			"  " + os.Args[0] + " upload --artifact blink-v1 -p /dev/ttyACM0",
+		Args:   cobra.MaximumNArgs(1),
+		PreRun: checkFlagsConflicts,
+		Run:    run,
 	}
 
 	uploadCommand.Flags().StringVarP(&fqbn, "fqbn", "b", "", "Fully Qualified Board Name, e.g.: arduino:avr:uno")
-	uploadCommand.Flags().StringVarP(&port, "port", "p", "", "Upload port, e.g.: COM10 or /dev/ttyACM0")
+	uploadCommand.Flags().StringVarP(&port, "port", "p", "", "Upload port, e.g.: COM10, /dev/ttyACM0 or, for the boards that support the upload over the network, 192.168.1.10[:port]")
 	uploadCommand.Flags().StringVarP(&importDir, "input-dir", "", "", "Directory containing binaries to upload.")
 	uploadCommand.Flags().StringVarP(&importFile, "input-file", "i", "", "Binary file to upload.")
 	uploadCommand.Flags().BoolVarP(&verify, "verify", "t", false, "Verify uploaded binary after the upload.")
+	uploadCommand.Flags().BoolVar(&verifyReadback, "verify-readback", false, "Read back the flashed region after the upload and compare it with the binary, reporting the mismatched ranges. Supported by avrdude, bossac, esptool and by the platforms defining a readback recipe.")
 	uploadCommand.Flags().BoolVarP(&verbose, "verbose", "v", false, "Optional, turns on verbose mode.")
 	uploadCommand.Flags().StringVarP(&programmer, "programmer", "P", "", "Optional, use the specified programmer to upload.")
+	uploadCommand.Flags().Uint32Var(&uploadSpeed, "upload-speed", 0, "Optional, overrides the upload speed (baud rate) of the board.")
+	uploadCommand.Flags().StringArrayVar(&toolArgs, "tool-arg", []string{}, "Optional, additional argument passed to the upload tool. Can be used multiple times for multiple arguments.")
+	uploadCommand.Flags().BoolVar(&noAutodetect, "no-autodetect", false, "Don't use the connected board when the FQBN is not specified.")
+	uploadCommand.Flags().StringVar(&networkPassword, "network-password", "", "Password of the board, when uploading over the network to an IP address or host name given with --port.")
+	uploadCommand.Flags().StringVar(&artifact, "artifact", "", "Upload the binaries saved in the artifacts store with this tag.")
+	uploadCommand.Flags().StringVar(&discoveryTimeout, "discovery-timeout", "", "Time to wait for the board port to appear after the reset, e.g.: 30s (default is the upload.discovery_timeout setting).")
+	uploadCommand.Flags().Uint32Var(&uploadRetries, "upload-retries", 0, "Number of times the upload is retried when the port is busy (default is the upload.retries setting).")
+	uploadCommand.Flags().BoolVar(&dryRun, "dry-run", false, "Print the steps and the tool command lines of the upload without running them. With --verbose the upload properties are printed too.")
+	uploadCommand.Flags().StringVar(&remoteHost, "remote", "", "Upload to a board attached to a remote host, reachable with ssh, e.g.: pi@raspberrypi.local. The host must have arduino-cli installed.")
 
 	return uploadCommand
 }
@@ -68,6 +100,10 @@ func checkFlagsConflicts(command *cobra.Command, args []string) {
 		feedback.Errorf("error: --input-file and --input-dir flags cannot be used together")
 		os.Exit(errorcodes.ErrBadArgument)
 	}
+	if artifact != "" && (importFile != "" || importDir != "") {
+		feedback.Errorf("error: --artifact flag cannot be used together with --input-file or --input-dir")
+		os.Exit(errorcodes.ErrBadArgument)
+	}
 }
 
 func run(command *cobra.Command, args []string) {
@@ -87,20 +123,83 @@ func run(command *cobra.Command, args []string) {
 		}
 	}
 
-	if _, err := upload.Upload(context.Background(), &rpc.UploadRequest{
-		Instance:   instance,
-		Fqbn:       fqbn,
-		SketchPath: sketchPath.String(),
-		Port:       port,
-		Verbose:    verbose,
-		Verify:     verify,
-		ImportFile: importFile,
-		ImportDir:  importDir,
-		Programmer: programmer,
-	}, os.Stdout, os.Stderr); err != nil {
+	if artifact != "" {
+		a, err := artifacts.Store().Get(artifact)
+		if err != nil {
+			feedback.Errorf("Error loading artifact: %v", err)
+			os.Exit(errorcodes.ErrGeneric)
+		}
+		importDir = a.Dir().String()
+		if fqbn == "" {
+			fqbn = a.Fqbn
+		}
+	}
+
+	// The board attached to a remote host is detected on the host itself
+	if !noAutodetect && remoteHost == "" {
+		board.Autodetect(instance, sketchPath, &fqbn, &port)
+	}
+
+	toolOutput := output.NewToolOutput()
+	req := &rpc.UploadRequest{
+		Instance:         instance,
+		Fqbn:             fqbn,
+		SketchPath:       sketchPath.String(),
+		Port:             port,
+		Verbose:          verbose,
+		Verify:           verify,
+		VerifyReadback:   verifyReadback,
+		ImportFile:       importFile,
+		ImportDir:        importDir,
+		Programmer:       programmer,
+		UploadSpeed:      uploadSpeed,
+		ToolArgs:         toolArgs,
+		NetworkPassword:  networkPassword,
+		DiscoveryTimeout: discoveryTimeout,
+		DryRun:           dryRun,
+	}
+	// The upload.retries setting of the instance is used if the flag is not set
+	if command.Flags().Changed("upload-retries") {
+		req.UploadRetries = wrapperspb.UInt32(uploadRetries)
+	}
+	var res *rpc.UploadResponse
+	var err error
+	if remoteHost != "" {
+		host, parseErr := remote.ParseHost(remoteHost)
+		if parseErr != nil {
+			feedback.Errorf("Error during Upload: %v", parseErr)
+			os.Exit(errorcodes.ErrBadArgument)
+		}
+		host.CLIPath = configuration.Settings.GetString("remote.cli_path")
+		res, err = upload.Remote(host, req, toolOutput.Out, toolOutput.Err, output.UploadProgressBar())
+	} else {
+		res, err = upload.Upload(context.Background(), req, toolOutput.Out, toolOutput.Err, output.UploadProgressBar())
+	}
+
+	// In JSON mode the output of the tool is reported in the result event,
+	// even if the upload failed
+	toolOutput.Flush()
+	if output.OutputFormat == "json" {
+		uploadOut, uploadErr := toolOutput.Buffered()
+		result := &uploadResult{
+			Event:     "result",
+			UploadOut: uploadOut,
+			UploadErr: uploadErr,
+			Result:    res.GetResult(),
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		feedback.PrintEvent(result)
+	}
+	if err != nil {
 		feedback.Errorf("Error during Upload: %v", err)
 		os.Exit(errorcodes.ErrGeneric)
 	}
+
+	if output.OutputFormat == "ndjson" || (output.OutputFormat != "json" && dryRun) {
+		feedback.PrintResult(&uploadResult{Result: res.GetResult(), verbose: verbose})
+	}
 }
 
 // initSketchPath returns the current working directory
@@ -117,3 +216,58 @@ func initSketchPath(sketchPath *paths.Path) *paths.Path {
 	logrus.Infof("Reading sketch from dir: %s", wd)
 	return wd
 }
+
+type uploadResult struct {
+	Event     string            `json:"event,omitempty"`
+	UploadOut string            `json:"upload_out,omitempty"`
+	UploadErr string            `json:"upload_err,omitempty"`
+	Result    *rpc.UploadResult `json:"result"`
+	Error     string            `json:"error,omitempty"`
+	verbose   bool
+}
+
+func (r *uploadResult) Data() interface{} {
+	return r
+}
+
+func (r *uploadResult) String() string {
+	// Only the steps of a dry run are printed, the output of the upload
+	// tools has already been streamed
+	steps := r.Result.GetDryRunSteps()
+	if len(steps) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	for i, step := range steps {
+		fmt.Fprintf(&sb, "%d. %s\n", i+1, step.GetDescription())
+		if len(step.GetCommand()) > 0 {
+			fmt.Fprintf(&sb, "   %s\n", quoteCommand(step.GetCommand()))
+		}
+	}
+	if r.verbose {
+		props := r.Result.GetDryRunProperties()
+		keys := make([]string, 0, len(props))
+		for k := range props {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		sb.WriteString("\nUpload properties:\n")
+		for _, k := range keys {
+			fmt.Fprintf(&sb, "%s=%s\n", k, props[k])
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// quoteCommand joins the arguments of a command line, quoting the ones
+// containing spaces
+func quoteCommand(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\"'") {
+			arg = strconv.Quote(arg)
+		}
+		quoted[i] = arg
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,50 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package board
+
+import (
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/pkg/errors"
+)
+
+// Autodetect returns the only identifiable board connected to the computer
+// and the port it is attached to. An error is returned if no board, or more
+// than one board, is found.
+func Autodetect(instanceID int32) (*rpc.BoardListItem, *rpc.DetectedPort, error) {
+	ports, err := List(instanceID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var board *rpc.BoardListItem
+	var port *rpc.DetectedPort
+	for _, p := range ports {
+		for _, b := range p.GetBoards() {
+			if b.GetFqbn() == "" {
+				continue
+			}
+			if board != nil {
+				return nil, nil, errors.New("more than one board detected")
+			}
+			board = b
+			port = p
+		}
+	}
+	if board == nil {
+		return nil, nil, errors.New("no board detected")
+	}
+	return board, port, nil
+}
@@ -139,11 +139,9 @@ func Details(ctx context.Context, req *rpc.BoardDetailsRequest) (*rpc.BoardDetai
 
 	details.Programmers = []*rpc.Programmer{}
 	for id, p := range boardPlatform.Programmers {
-		details.Programmers = append(details.Programmers, &rpc.Programmer{
-			Platform: boardPlatform.Platform.Name,
-			Id:       id,
-			Name:     p.Name,
-		})
+		programmer := commands.ProgrammerToRPC(id, p)
+		programmer.Platform = boardPlatform.Platform.Name
+		details.Programmers = append(details.Programmers, programmer)
 	}
 
 	return details, nil
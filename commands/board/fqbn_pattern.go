@@ -0,0 +1,88 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package board
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+)
+
+// IsFQBNPattern returns true if the FQBN contains wildcards, like
+// "esp32:esp32:*" or "arduino:*:nano*"
+func IsFQBNPattern(fqbn string) bool {
+	return strings.Contains(fqbn, ":") && strings.ContainsAny(fqbn, "*?[")
+}
+
+// matchFQBNPattern returns true if the FQBN matches the pattern. The
+// packager, architecture and board id are matched separately, so a wildcard
+// never spans across them. The board options of the pattern are ignored.
+func matchFQBNPattern(pattern, fqbn string) (bool, error) {
+	patternParts := strings.SplitN(pattern, ":", 4)
+	fqbnParts := strings.SplitN(fqbn, ":", 4)
+	if len(patternParts) < 3 {
+		return false, fmt.Errorf("invalid FQBN pattern %s: it must be in the form PACKAGER:ARCH:BOARD", pattern)
+	}
+	if len(fqbnParts) < 3 {
+		return false, nil
+	}
+	for i := 0; i < 3; i++ {
+		matches, err := path.Match(patternParts[i], fqbnParts[i])
+		if err != nil {
+			return false, fmt.Errorf("invalid FQBN pattern %s: %w", pattern, err)
+		}
+		if !matches {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// ExpandFQBNPattern returns the sorted FQBNs of the installed boards matching
+// the pattern. The board options of the pattern, if any, are added to all the
+// FQBNs. An error is returned if no board matches or if more than max boards
+// match, unless max is 0.
+func ExpandFQBNPattern(ctx context.Context, instance *rpc.Instance, pattern string, max int) ([]string, error) {
+	list, err := ListAll(ctx, &rpc.BoardListAllRequest{
+		Instance:   instance,
+		SearchArgs: []string{pattern},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	options := ""
+	if parts := strings.SplitN(pattern, ":", 4); len(parts) == 4 {
+		options = ":" + parts[3]
+	}
+	res := []string{}
+	for _, board := range list.GetBoards() {
+		res = append(res, board.GetFqbn()+options)
+	}
+	sort.Strings(res)
+
+	if len(res) == 0 {
+		return nil, fmt.Errorf("no installed board matches %s", pattern)
+	}
+	if max > 0 && len(res) > max {
+		return nil, fmt.Errorf("%s matches %d boards, more than the maximum of %d", pattern, len(res), max)
+	}
+	return res, nil
+}
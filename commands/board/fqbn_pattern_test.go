@@ -0,0 +1,52 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package board
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsFQBNPattern(t *testing.T) {
+	require.True(t, IsFQBNPattern("esp32:esp32:*"))
+	require.True(t, IsFQBNPattern("arduino:*:nano?"))
+	require.False(t, IsFQBNPattern("arduino:avr:uno"))
+	require.False(t, IsFQBNPattern("nano*"))
+}
+
+func TestMatchFQBNPattern(t *testing.T) {
+	check := func(pattern, fqbn string, expected bool) {
+		matches, err := matchFQBNPattern(pattern, fqbn)
+		require.NoError(t, err)
+		require.Equal(t, expected, matches, "%s matching %s", pattern, fqbn)
+	}
+	check("esp32:esp32:*", "esp32:esp32:esp32", true)
+	check("esp32:esp32:*", "esp8266:esp8266:generic", false)
+	check("arduino:*:nano*", "arduino:avr:nano", true)
+	check("arduino:*:nano*", "arduino:megaavr:nano4809", true)
+	check("arduino:*:nano*", "arduino:samd:mkr1000", false)
+	// wildcards don't span across the FQBN parts
+	check("arduino*:*:uno", "arduino:avr:uno", true)
+	check("arduino:avr*uno:*", "arduino:avr:uno", false)
+	// board options are ignored
+	check("arduino:avr:*:cpu=atmega328", "arduino:avr:nano", true)
+
+	_, err := matchFQBNPattern("arduino:avr:[", "arduino:avr:uno")
+	require.Error(t, err)
+	_, err = matchFQBNPattern("arduino:*", "arduino:avr:uno")
+	require.Error(t, err)
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/segmentio/stats/v4"
 	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
 )
 
 var (
@@ -43,7 +44,7 @@ var (
 	validVidPid = regexp.MustCompile(`0[xX][a-fA-F\d]{4}`)
 )
 
-func apiByVidPid(vid, pid string) ([]*rpc.BoardListItem, error) {
+func apiByVidPid(settings *viper.Viper, vid, pid string) ([]*rpc.BoardListItem, error) {
 	// ensure vid and pid are valid before hitting the API
 	if !validVidPid.MatchString(vid) {
 		return nil, errors.Errorf("Invalid vid value: '%s'", vid)
@@ -59,7 +60,7 @@ func apiByVidPid(vid, pid string) ([]*rpc.BoardListItem, error) {
 
 	// TODO: use proxy if set
 
-	httpClient, err := httpclient.New()
+	httpClient, err := httpclient.NewForOperationFromSettings(settings, httpclient.OperationDiscovery)
 
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to initialize http client")
@@ -102,7 +103,7 @@ func apiByVidPid(vid, pid string) ([]*rpc.BoardListItem, error) {
 	return retVal, nil
 }
 
-func identifyViaCloudAPI(port *discovery.Port) ([]*rpc.BoardListItem, error) {
+func identifyViaCloudAPI(settings *viper.Viper, port *discovery.Port) ([]*rpc.BoardListItem, error) {
 	// If the port is not USB do not try identification via cloud
 	id := port.Properties
 	if !id.ContainsKey("vid") || !id.ContainsKey("pid") {
@@ -110,11 +111,11 @@ func identifyViaCloudAPI(port *discovery.Port) ([]*rpc.BoardListItem, error) {
 	}
 
 	logrus.Debug("Querying builder API for board identification...")
-	return apiByVidPid(id.Get("vid"), id.Get("pid"))
+	return apiByVidPid(settings, id.Get("vid"), id.Get("pid"))
 }
 
 // identify returns a list of boards checking first the installed platforms or the Cloud API
-func identify(pm *packagemanager.PackageManager, port *discovery.Port) ([]*rpc.BoardListItem, error) {
+func identify(pm *packagemanager.PackageManager, settings *viper.Viper, port *discovery.Port) ([]*rpc.BoardListItem, error) {
 	boards := []*rpc.BoardListItem{}
 
 	// first query installed cores through the Package Manager
@@ -135,8 +136,8 @@ func identify(pm *packagemanager.PackageManager, port *discovery.Port) ([]*rpc.B
 
 	// if installed cores didn't recognize the board, try querying
 	// the builder API if the board is a USB device port
-	if len(boards) == 0 {
-		items, err := identifyViaCloudAPI(port)
+	if len(boards) == 0 && !httpclient.IsOfflineFromSettings(settings) {
+		items, err := identifyViaCloudAPI(settings, port)
 		if err == ErrNotFound {
 			// the board couldn't be detected, print a warning
 			logrus.Debug("Board not recognized")
@@ -200,7 +201,7 @@ func List(instanceID int32) (r []*rpc.DetectedPort, e error) {
 
 	retVal := []*rpc.DetectedPort{}
 	for _, port := range ports {
-		boards, err := identify(pm, port)
+		boards, err := identify(pm, commands.GetSettings(instanceID), port)
 		if err != nil {
 			return nil, err
 		}
@@ -237,7 +238,7 @@ func Watch(instanceID int32, interrupt <-chan bool) (<-chan *rpc.BoardListWatchR
 				boards := []*rpc.BoardListItem{}
 				boardsError := ""
 				if event.Type == "add" {
-					boards, err = identify(pm, event.Port)
+					boards, err = identify(pm, commands.GetSettings(instanceID), event.Port)
 					if err != nil {
 						boardsError = err.Error()
 					}
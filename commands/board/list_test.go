@@ -52,7 +52,7 @@ func TestGetByVidPid(t *testing.T) {
 	defer ts.Close()
 
 	vidPidURL = ts.URL
-	res, err := apiByVidPid("0xf420", "0XF069")
+	res, err := apiByVidPid(configuration.Settings, "0xf420", "0XF069")
 	require.Nil(t, err)
 	require.Len(t, res, 1)
 	require.Equal(t, "Arduino/Genuino MKR1000", res[0].Name)
@@ -61,7 +61,7 @@ func TestGetByVidPid(t *testing.T) {
 	require.Equal(t, "0XF069", res[0].Pid)
 
 	// wrong vid (too long), wrong pid (not an hex value)
-	_, err = apiByVidPid("0xfffff", "0xDEFG")
+	_, err = apiByVidPid(configuration.Settings, "0xfffff", "0xDEFG")
 	require.NotNil(t, err)
 }
 
@@ -72,7 +72,7 @@ func TestGetByVidPidNotFound(t *testing.T) {
 	defer ts.Close()
 
 	vidPidURL = ts.URL
-	res, err := apiByVidPid("0x0420", "0x0069")
+	res, err := apiByVidPid(configuration.Settings, "0x0420", "0x0069")
 	require.NotNil(t, err)
 	require.Equal(t, "board not found", err.Error())
 	require.Len(t, res, 0)
@@ -86,7 +86,7 @@ func TestGetByVidPid5xx(t *testing.T) {
 	defer ts.Close()
 
 	vidPidURL = ts.URL
-	res, err := apiByVidPid("0x0420", "0x0069")
+	res, err := apiByVidPid(configuration.Settings, "0x0420", "0x0069")
 	require.NotNil(t, err)
 	require.Equal(t, "the server responded with status 500 Internal Server Error", err.Error())
 	require.Len(t, res, 0)
@@ -99,7 +99,7 @@ func TestGetByVidPidMalformedResponse(t *testing.T) {
 	defer ts.Close()
 
 	vidPidURL = ts.URL
-	res, err := apiByVidPid("0x0420", "0x0069")
+	res, err := apiByVidPid(configuration.Settings, "0x0420", "0x0069")
 	require.NotNil(t, err)
 	require.Equal(t, "wrong format in server response", err.Error())
 	require.Len(t, res, 0)
@@ -109,7 +109,7 @@ func TestBoardDetectionViaAPIWithNonUSBPort(t *testing.T) {
 	port := &discovery.Port{
 		Properties: properties.NewMap(),
 	}
-	items, err := identifyViaCloudAPI(port)
+	items, err := identifyViaCloudAPI(configuration.Settings, port)
 	require.Equal(t, err, ErrNotFound)
 	require.Empty(t, items)
 }
@@ -152,7 +152,7 @@ func TestBoardIdentifySorting(t *testing.T) {
 	idPrefs := properties.NewMap()
 	idPrefs.Set("vid", "0x0000")
 	idPrefs.Set("pid", "0x0000")
-	res, err := identify(pm, &discovery.Port{Properties: idPrefs})
+	res, err := identify(pm, configuration.Settings, &discovery.Port{Properties: idPrefs})
 	require.NoError(t, err)
 	require.NotNil(t, res)
 	require.Len(t, res, 4)
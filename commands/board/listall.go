@@ -37,8 +37,23 @@ func ListAll(ctx context.Context, req *rpc.BoardListAllRequest) (*rpc.BoardListA
 	}
 
 	searchArgs := []string{}
+	fqbnPatterns := []string{}
 	for _, s := range req.SearchArgs {
-		searchArgs = append(searchArgs, strings.Trim(s, " "))
+		s = strings.Trim(s, " ")
+		if IsFQBNPattern(s) {
+			fqbnPatterns = append(fqbnPatterns, s)
+		} else {
+			searchArgs = append(searchArgs, s)
+		}
+	}
+
+	matchFQBN := func(fqbn string) (bool, error) {
+		for _, pattern := range fqbnPatterns {
+			if matches, err := matchFQBNPattern(pattern, fqbn); err != nil || !matches {
+				return false, err
+			}
+		}
+		return true, nil
 	}
 
 	match := func(toTest []string) (bool, error) {
@@ -98,6 +113,12 @@ func ListAll(ctx context.Context, req *rpc.BoardListAllRequest) (*rpc.BoardListA
 					continue
 				}
 
+				if ok, err := matchFQBN(board.FQBN()); err != nil {
+					return nil, err
+				} else if !ok {
+					continue
+				}
+
 				toTest := append(toTest, board.Name())
 				toTest = append(toTest, board.FQBN())
 				if ok, err := match(toTest); err != nil {
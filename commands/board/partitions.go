@@ -0,0 +1,87 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package board
+
+import (
+	"fmt"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/arduino/partitions"
+	"github.com/arduino/arduino-cli/commands"
+	"github.com/pkg/errors"
+)
+
+// PartitionSchemeOption is the board option that selects the partitions table
+const PartitionSchemeOption = "PartitionScheme"
+
+// PartitionScheme is a value of the partition scheme option of a board
+type PartitionScheme struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Selected bool   `json:"selected,omitempty"`
+}
+
+// PartitionsResult describes the partition schemes of a board and the
+// partitions table selected by the FQBN
+type PartitionsResult struct {
+	Fqbn       string                  `json:"fqbn"`
+	Schemes    []*PartitionScheme      `json:"schemes"`
+	Table      string                  `json:"table"`
+	Partitions []*partitions.Partition `json:"partitions"`
+}
+
+// Partitions returns the partition schemes available for the board and the
+// partitions of the one selected in the FQBN, or of the default one.
+func Partitions(instanceID int32, fqbnIn string) (*PartitionsResult, error) {
+	pm := commands.GetPackageManager(instanceID)
+	if pm == nil {
+		return nil, errors.New("invalid instance")
+	}
+	fqbn, err := cores.ParseFQBN(fqbnIn)
+	if err != nil {
+		return nil, fmt.Errorf("parsing fqbn: %s", err)
+	}
+	_, boardPlatform, board, boardProperties, _, err := pm.ResolveFQBN(fqbn)
+	if err != nil {
+		return nil, fmt.Errorf("loading board data: %s", err)
+	}
+
+	name, ok := boardProperties.GetOk("build.partitions")
+	if !ok {
+		return nil, fmt.Errorf("the board %s doesn't use a partitions table", fqbn)
+	}
+
+	res := &PartitionsResult{Fqbn: fqbn.String(), Schemes: []*PartitionScheme{}}
+	options := board.GetConfigOptionValues(PartitionSchemeOption)
+	selected, ok := fqbn.Configs.GetOk(PartitionSchemeOption)
+	if !ok && options.Size() > 0 {
+		selected = options.Keys()[0]
+	}
+	for _, id := range options.Keys() {
+		res.Schemes = append(res.Schemes, &PartitionScheme{
+			ID:       id,
+			Name:     options.Get(id),
+			Selected: id == selected,
+		})
+	}
+
+	table := boardPlatform.InstallDir.Join("tools", "partitions", name+".csv")
+	res.Table = table.String()
+	if res.Partitions, err = partitions.Load(table); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -21,11 +21,13 @@ import (
 	"github.com/arduino/arduino-cli/arduino/cores"
 	"github.com/arduino/arduino-cli/arduino/cores/packagemanager"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/spf13/viper"
 )
 
-// DownloadToolRelease downloads a ToolRelease
-func DownloadToolRelease(pm *packagemanager.PackageManager, toolRelease *cores.ToolRelease, downloadCB DownloadProgressCB) error {
-	config, err := GetDownloaderConfig()
+// DownloadToolRelease downloads a ToolRelease using the network
+// configuration in settings
+func DownloadToolRelease(pm *packagemanager.PackageManager, settings *viper.Viper, toolRelease *cores.ToolRelease, downloadCB DownloadProgressCB) error {
+	config, err := GetDownloaderConfig(settings)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,73 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package commands
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/arduino/cores/packagemanager"
+	paths "github.com/arduino/go-paths-helper"
+	"github.com/spf13/viper"
+)
+
+// GetClangFormat returns the path of the clang-format executable. An installed
+// clang-format tool is used if available, otherwise the latest release defined
+// in the package indexes is installed. If no package provides the tool,
+// clang-format is searched in the PATH. The download uses the network
+// configuration in settings.
+func GetClangFormat(pm *packagemanager.PackageManager, settings *viper.Viper, downloadCB DownloadProgressCB, taskCB TaskProgressCB) (*paths.Path, error) {
+	var installed, latest *cores.ToolRelease
+	for _, targetPackage := range pm.Packages {
+		tool := targetPackage.Tools["clang-format"]
+		if tool == nil {
+			continue
+		}
+		if release := tool.GetLatestInstalled(); release != nil && (installed == nil || installed.Version.LessThan(release.Version)) {
+			installed = release
+		}
+		if release := tool.LatestRelease(); release != nil && release.GetCompatibleFlavour() != nil && (latest == nil || latest.Version.LessThan(release.Version)) {
+			latest = release
+		}
+	}
+
+	toolRelease := installed
+	if toolRelease == nil && latest != nil {
+		if err := DownloadToolRelease(pm, settings, latest, downloadCB); err != nil {
+			return nil, fmt.Errorf("downloading %s: %s", latest, err)
+		}
+		if err := InstallToolRelease(pm, latest, taskCB); err != nil {
+			return nil, err
+		}
+		toolRelease = latest
+	}
+	if toolRelease != nil {
+		exe := "clang-format"
+		if runtime.GOOS == "windows" {
+			exe += ".exe"
+		}
+		if clangFormat := toolRelease.InstallDir.Join(exe); clangFormat.Exist() {
+			return clangFormat, nil
+		}
+	}
+
+	if clangFormat, err := exec.LookPath("clang-format"); err == nil {
+		return paths.New(clangFormat), nil
+	}
+	return nil, fmt.Errorf("clang-format not found: install a package providing it or add it to the PATH")
+}
@@ -16,9 +16,13 @@
 package commands
 
 import (
+	"fmt"
+	"runtime"
+
 	"github.com/arduino/arduino-cli/arduino/cores"
 	"github.com/arduino/arduino-cli/arduino/cores/packagemanager"
 	"github.com/arduino/arduino-cli/arduino/resources"
+	paths "github.com/arduino/go-paths-helper"
 	semver "go.bug.st/relaxed-semver"
 )
 
@@ -26,6 +30,8 @@ func loadBuiltinCtagsMetadata(pm *packagemanager.PackageManager) {
 	builtinPackage := pm.Packages.GetOrCreatePackage("builtin")
 	ctagsTool := builtinPackage.GetOrCreateTool("ctags")
 	ctagsRel := ctagsTool.GetOrCreateRelease(semver.ParseRelaxed("5.8-arduino11"))
+	// The metadata of the builtin tools are part of the executable
+	ctagsRel.IsTrusted = true
 	ctagsRel.Flavors = []*cores.Flavor{
 		{
 			OS: "i686-pc-linux-gnu",
@@ -96,3 +102,20 @@ func getBuiltinCtagsTool(pm *packagemanager.PackageManager) (*cores.ToolRelease,
 	loadBuiltinCtagsMetadata(pm)
 	return pm.Package("builtin").Tool("ctags").Release(ctagsVersion).Get()
 }
+
+// GetCtags returns the path of the builtin ctags executable, installed when
+// the instance is initialized
+func GetCtags(pm *packagemanager.PackageManager) (*paths.Path, error) {
+	ctagsTool, err := getBuiltinCtagsTool(pm)
+	if err != nil {
+		return nil, err
+	}
+	if !ctagsTool.IsInstalled() {
+		return nil, fmt.Errorf("%s is not installed", ctagsTool)
+	}
+	exe := "ctags"
+	if runtime.GOOS == "windows" {
+		exe += ".exe"
+	}
+	return ctagsTool.InstallDir.Join(exe), nil
+}
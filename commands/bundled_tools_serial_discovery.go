@@ -180,5 +180,6 @@ func getBuiltinSerialDiscoveryTool(pm *packagemanager.PackageManager) (*cores.To
 	serialDiscoveryTool := builtinPackage.GetOrCreateTool("serial-discovery")
 	serialDiscoveryToolRel := serialDiscoveryTool.GetOrCreateRelease(serialDiscoveryVersion)
 	serialDiscoveryToolRel.Flavors = serialDiscoveryFlavors
+	serialDiscoveryToolRel.IsTrusted = true
 	return pm.Package("builtin").Tool("serial-discovery").Release(serialDiscoveryVersion).Get()
 }
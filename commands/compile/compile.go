@@ -17,20 +17,28 @@ package compile
 
 import (
 	"context"
+	"crypto"
 	"fmt"
 	"io"
+	"os"
 	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/arduino/arduino-cli/arduino/artifacts"
 	bldr "github.com/arduino/arduino-cli/arduino/builder"
 	"github.com/arduino/arduino-cli/arduino/cores"
 	"github.com/arduino/arduino-cli/arduino/cores/packagemanager"
+	"github.com/arduino/arduino-cli/arduino/libraries/librariesindex"
 	"github.com/arduino/arduino-cli/arduino/sketches"
 	"github.com/arduino/arduino-cli/commands"
 	"github.com/arduino/arduino-cli/configuration"
+	"github.com/arduino/arduino-cli/httpclient"
 	"github.com/arduino/arduino-cli/legacy/builder"
+	"github.com/arduino/arduino-cli/legacy/builder/constants"
+	"github.com/arduino/arduino-cli/legacy/builder/ctags"
 	"github.com/arduino/arduino-cli/legacy/builder/i18n"
 	"github.com/arduino/arduino-cli/legacy/builder/types"
 	"github.com/arduino/arduino-cli/metrics"
@@ -43,12 +51,16 @@ import (
 )
 
 // Compile FIXMEDOC
-func Compile(ctx context.Context, req *rpc.CompileRequest, outStream, errStream io.Writer, debug bool) (r *rpc.CompileResponse, e error) {
+func Compile(ctx context.Context, req *rpc.CompileRequest, outStream, errStream io.Writer, progressCB commands.TaskProgressCB, debug bool) (r *rpc.CompileResponse, e error) {
+	settings := commands.GetSettings(req.GetInstance().GetId())
+	if settings == nil {
+		return nil, errors.New("invalid instance")
+	}
 
 	// There is a binding between the export binaries setting and the CLI flag to explicitly set it,
 	// since we want this binding to work also for the gRPC interface we must read it here in this
 	// package instead of the cli/compile one, otherwise we'd lose the binding.
-	exportBinaries := configuration.Settings.GetBool("sketch.always_export_binaries")
+	exportBinaries := settings.GetBool("sketch.always_export_binaries")
 	// If we'd just read the binding in any case, even if the request sets the export binaries setting,
 	// the settings value would always overwrite the request one and it wouldn't have any effect
 	// setting it for individual requests. To solve this we use a wrapper.BoolValue to handle
@@ -94,6 +106,25 @@ func Compile(ctx context.Context, req *rpc.CompileRequest, outStream, errStream
 	if req.GetSketchPath() == "" {
 		return nil, fmt.Errorf("missing sketchPath")
 	}
+	switch req.GetSizeReport() {
+	case "", "summary", "detailed":
+	default:
+		return nil, fmt.Errorf("invalid size report: %s", req.GetSizeReport())
+	}
+	switch req.GetPreprocessor() {
+	case "", "ctags":
+	case "treesitter":
+		if !ctags.TreeSitterSupported {
+			return nil, fmt.Errorf("the treesitter preprocessor is not available in this build of the CLI, it must be built with the treesitter tag")
+		}
+	default:
+		return nil, fmt.Errorf("invalid preprocessor: %s", req.GetPreprocessor())
+	}
+	switch req.GetSbom() {
+	case "", bldr.SBOMFormatSPDX, bldr.SBOMFormatCycloneDX:
+	default:
+		return nil, fmt.Errorf("invalid SBOM format: %s", req.GetSbom())
+	}
 	sketchPath := paths.New(req.GetSketchPath())
 	sketch, err := sketches.NewSketchFromPath(sketchPath)
 	if err != nil {
@@ -131,11 +162,11 @@ func Compile(ctx context.Context, req *rpc.CompileRequest, outStream, errStream
 	builderCtx.SketchLocation = sketch.FullPath
 
 	// FIXME: This will be redundant when arduino-builder will be part of the cli
-	builderCtx.HardwareDirs = configuration.HardwareDirectories(configuration.Settings)
-	builderCtx.BuiltInToolsDirs = configuration.BundleToolsDirectories(configuration.Settings)
+	builderCtx.HardwareDirs = configuration.HardwareDirectories(settings)
+	builderCtx.BuiltInToolsDirs = configuration.BundleToolsDirectories(settings)
 
 	builderCtx.OtherLibrariesDirs = paths.NewPathList(req.GetLibraries()...)
-	builderCtx.OtherLibrariesDirs.Add(configuration.LibrariesDir(configuration.Settings))
+	builderCtx.OtherLibrariesDirs.Add(configuration.LibrariesDir(settings))
 
 	builderCtx.LibraryDirs = paths.NewPathList(req.Library...)
 
@@ -147,21 +178,84 @@ func Compile(ctx context.Context, req *rpc.CompileRequest, outStream, errStream
 	if err = builderCtx.BuildPath.MkdirAll(); err != nil {
 		return nil, fmt.Errorf("cannot create build directory: %s", err)
 	}
-	builderCtx.CompilationDatabase = bldr.NewCompilationDatabase(
-		builderCtx.BuildPath.Join("compile_commands.json"),
-	)
+	compilationDatabasePath := builderCtx.BuildPath.Join("compile_commands.json")
+	if p := req.GetCompilationDatabasePath(); p != "" {
+		compilationDatabasePath = paths.New(p)
+		if err := compilationDatabasePath.Parent().MkdirAll(); err != nil {
+			return nil, fmt.Errorf("cannot create compilation database directory: %s", err)
+		}
+	}
+	builderCtx.CompilationDatabase = bldr.NewCompilationDatabase(compilationDatabasePath)
 
 	builderCtx.Verbose = req.GetVerbose()
 
 	// Optimize for debug
 	builderCtx.OptimizeForDebug = req.GetOptimizeForDebug()
+	// Optimization preset, takes precedence over OptimizeForDebug
+	builderCtx.Optimization = req.GetOptimize()
+
+	builderCtx.CoreBuildCachePath = paths.New(settings.GetString("build_cache.path"))
+	builderCtx.CoreBuildCacheMaxSize = settings.GetInt64("build_cache.max_size_mb") * 1024 * 1024
+	builderCtx.CoreBuildCacheEvictionPolicy = settings.GetString("build_cache.eviction_policy")
+
+	for _, spec := range req.GetExportFormats() {
+		format, err := bldr.ParseExportFormat(spec)
+		if err != nil {
+			return nil, err
+		}
+		builderCtx.ExportFormats = append(builderCtx.ExportFormats, format)
+	}
 
-	builderCtx.CoreBuildCachePath = paths.TempDir().Join("arduino-core-cache")
+	if partitionTable := req.GetPartitionTable(); partitionTable != "" {
+		builderCtx.PartitionTable, err = paths.New(partitionTable).Abs()
+		if err != nil {
+			return nil, fmt.Errorf("invalid partitions table path: %s", err)
+		}
+	}
+
+	signKey := req.GetSignKey()
+	if signKey == "" {
+		signKey = settings.GetString("compile.signing.key")
+	}
+	if signKey != "" {
+		builderCtx.SigningKey, err = paths.New(signKey).Abs()
+		if err != nil {
+			return nil, fmt.Errorf("invalid signing key path: %s", err)
+		}
+	}
+
+	var artifactsKey crypto.Signer
+	artifactsSignKey := req.GetArtifactsSignKey()
+	if artifactsSignKey == "" {
+		artifactsSignKey = settings.GetString("compile.signing.artifacts_key")
+	}
+	if artifactsSignKey != "" {
+		if artifactsKey, _, err = bldr.LoadSigningKey(paths.New(artifactsSignKey)); err != nil {
+			return nil, fmt.Errorf("invalid artifacts signing key: %s", err)
+		}
+	}
 
 	builderCtx.Jobs = int(req.GetJobs())
+	builderCtx.ProgressCB = progressCB
+	builderCtx.KeepGoing = req.GetKeepGoing()
 
 	builderCtx.USBVidPid = req.GetVidPid()
 	builderCtx.WarningsLevel = req.GetWarnings()
+	builderCtx.LibrariesWarningsLevel = req.GetLibrariesWarnings()
+
+	failOnWarning := req.GetFailOnWarning()
+	if len(failOnWarning) == 0 {
+		failOnWarning = settings.GetStringSlice("compile.fail_on_warning")
+	}
+	warningPatterns, err := bldr.CompileWarningPatterns(failOnWarning)
+	if err != nil {
+		return nil, err
+	}
+	if len(warningPatterns) > 0 && (builderCtx.WarningsLevel == "" || builderCtx.WarningsLevel == "none") {
+		// With the "none" level the compiler doesn't print any warning, so
+		// nothing could be promoted
+		builderCtx.WarningsLevel = "default"
+	}
 
 	if debug {
 		builderCtx.DebugLevel = 100
@@ -169,7 +263,8 @@ func Compile(ctx context.Context, req *rpc.CompileRequest, outStream, errStream
 		builderCtx.DebugLevel = 5
 	}
 
-	builderCtx.CustomBuildProperties = append(req.GetBuildProperties(), "build.warn_data_percentage=75")
+	// The default goes first so that it can be overridden by the user
+	builderCtx.CustomBuildProperties = append([]string{"build.warn_data_percentage=75"}, req.GetBuildProperties()...)
 
 	if req.GetBuildCachePath() != "" {
 		builderCtx.BuildCachePath = paths.New(req.GetBuildCachePath())
@@ -183,7 +278,7 @@ func Compile(ctx context.Context, req *rpc.CompileRequest, outStream, errStream
 	builderCtx.ArduinoAPIVersion = "10607"
 
 	// Check if Arduino IDE is installed and get it's libraries location.
-	dataDir := paths.New(configuration.Settings.GetString("directories.Data"))
+	dataDir := paths.New(settings.GetString("directories.Data"))
 	preferencesTxt := dataDir.Join("preferences.txt")
 	ideProperties, err := properties.LoadFromPath(preferencesTxt)
 	if err == nil {
@@ -201,35 +296,193 @@ func Compile(ctx context.Context, req *rpc.CompileRequest, outStream, errStream
 		builderCtx.BuiltInLibrariesDirs = paths.NewPathList(ideLibrariesPath)
 	}
 
+	// The locations in the compiler output are mapped back from the
+	// preprocessed sketch to the original sketch files
+	sourceMapper := bldr.NewSourceMapper(builderCtx.BuildPath.Join("sketch"), builderCtx.BuildPath.Join("preproc"))
+	mappedErrStream := sourceMapper.Writer(errStream)
+	diagnostics := &bldr.DiagnosticsCollector{FailOnWarning: warningPatterns}
 	builderCtx.ExecStdout = outStream
-	builderCtx.ExecStderr = errStream
-	builderCtx.SetLogger(i18n.LoggerToCustomStreams{Stdout: outStream, Stderr: errStream})
+	builderCtx.ExecStderr = io.MultiWriter(mappedErrStream, diagnostics)
+	builderCtx.SetLogger(&i18n.LoggerToCustomStreams{Stdout: outStream, Stderr: errStream})
 	builderCtx.Clean = req.GetClean()
 	builderCtx.OnlyUpdateCompilationDatabase = req.GetCreateCompilationDatabaseOnly()
+	if exportCMake := req.GetExportCmake(); exportCMake != "" {
+		if builderCtx.ExportCMakePath, err = paths.New(exportCMake).Abs(); err != nil {
+			return nil, fmt.Errorf("invalid CMake export path: %s", err)
+		}
+		if builderCtx.ExportCMakePath.EquivalentTo(sketch.FullPath) {
+			return nil, fmt.Errorf("cannot export the CMake project in the sketch folder")
+		}
+	}
+	if exportBuild := req.GetExportBuild(); exportBuild != "" {
+		format, err := bldr.ParseBuildScriptFormat(exportBuild)
+		if err != nil {
+			return nil, err
+		}
+		if builderCtx.OnlyUpdateCompilationDatabase {
+			return nil, fmt.Errorf("cannot export the build script without compiling")
+		}
+		builderCtx.BuildScript = bldr.NewBuildScript(builderCtx.BuildPath.Join(format.FileName()), format)
+		// The cached objects and archives are not rebuilt, so every command
+		// must be run to be recorded
+		builderCtx.Clean = true
+	}
 
 	builderCtx.SourceOverride = req.GetSourceOverride()
+	builderCtx.Preprocessor = req.GetPreprocessor()
+
+	maxFlashUsage := req.GetMaxFlashUsage()
+	maxRAMUsage := req.GetMaxRamUsage()
+	if sketch.Metadata != nil {
+		if maxFlashUsage == "" {
+			maxFlashUsage = sketch.Metadata.MaxFlashUsage
+		}
+		if maxRAMUsage == "" {
+			maxRAMUsage = sketch.Metadata.MaxRAMUsage
+		}
+	}
+	if maxFlashUsage != "" {
+		if builderCtx.MaxFlashUsage, err = bldr.ParseSizeLimit(maxFlashUsage); err != nil {
+			return nil, fmt.Errorf("invalid max flash usage: %w", err)
+		}
+	}
+	if maxRAMUsage != "" {
+		if builderCtx.MaxRAMUsage, err = bldr.ParseSizeLimit(maxRAMUsage); err != nil {
+			return nil, fmt.Errorf("invalid max RAM usage: %w", err)
+		}
+	}
+
+	configHooks := map[string][]string{}
+	for _, stage := range bldr.HookStages {
+		configHooks[stage] = settings.GetStringSlice("compile.hooks." + stage)
+	}
+	// The hooks of the sketch run arbitrary commands, they are ignored unless
+	// allowed, since the sketch may come from an untrusted source
+	sketchHooks := map[string][]string{}
+	if sketch.Metadata != nil && len(sketch.Metadata.Hooks) > 0 {
+		if settings.GetBool("compile.allow_sketch_hooks") {
+			sketchHooks = sketch.Metadata.Hooks
+		} else {
+			builderCtx.GetLogger().Fprintln(os.Stderr, constants.LOG_LEVEL_WARN,
+				"Warning: the build hooks of the sketch are ignored, set compile.allow_sketch_hooks to run them")
+		}
+	}
+	builderCtx.UserHooks, err = bldr.MergeHooks(configHooks, sketchHooks)
+	if err != nil {
+		return nil, err
+	}
+
+	containerImage := req.GetContainerImage()
+	if containerImage == "" {
+		containerImage = settings.GetString("compile.container.image")
+	}
+	if containerImage != "" {
+		containerEngine := req.GetContainerEngine()
+		if containerEngine == "" {
+			containerEngine = settings.GetString("compile.container.engine")
+		}
+		container, err := bldr.NewContainer(containerEngine, containerImage)
+		if err != nil {
+			return nil, err
+		}
+		container.MountReadOnly(builderCtx.HardwareDirs...)
+		container.MountReadOnly(builderCtx.BuiltInToolsDirs...)
+		container.MountReadOnly(builderCtx.BuiltInLibrariesDirs...)
+		container.MountReadOnly(builderCtx.OtherLibrariesDirs...)
+		container.MountReadOnly(builderCtx.LibraryDirs...)
+		container.MountReadOnly(sketch.FullPath)
+		// The build caches are written during the build, they are created
+		// now because the folders that don't exist are not mounted
+		for _, cacheDir := range []*paths.Path{builderCtx.CoreBuildCachePath, builderCtx.BuildCachePath} {
+			if cacheDir == nil {
+				continue
+			}
+			if err := cacheDir.MkdirAll(); err != nil {
+				return nil, fmt.Errorf("cannot create build cache directory: %s", err)
+			}
+		}
+		container.MountReadWrite(builderCtx.BuildPath, builderCtx.CoreBuildCachePath, builderCtx.BuildCachePath)
+		builderCtx.Container = container
+	}
+
+	if remoteURL := settings.GetString("build_cache.remote_url"); remoteURL != "" && !settings.GetBool("network.offline") {
+		httpClient, err := httpclient.NewForOperationFromSettings(settings, httpclient.OperationBuildCache)
+		if err != nil {
+			return nil, err
+		}
+		remoteCache, err := bldr.NewRemoteCache(remoteURL, settings.GetBool("build_cache.remote_read_only"), httpClient)
+		if err != nil {
+			return nil, err
+		}
+		remoteCache.AddRoot("{build}", builderCtx.BuildPath)
+		remoteCache.AddRoot("{sketch}", sketch.FullPath)
+		remoteCache.AddRoot("{data}", paths.New(settings.GetString("directories.Data")))
+		remoteCache.AddRoot("{user}", paths.New(settings.GetString("directories.User")))
+		builderCtx.RemoteBuildCache = remoteCache
+	}
+
+	if req.GetStats() || metrics.Enabled() {
+		builderCtx.Stats = &types.BuildStats{}
+	}
+	if traced := req.GetTraceProperties(); len(traced) > 0 {
+		builderCtx.PropertiesTrace = types.NewPropertiesTrace(traced)
+	}
 
 	r = &rpc.CompileResponse{}
 	defer func() {
 		if p := builderCtx.BuildPath; p != nil {
 			r.BuildPath = p.String()
 		}
+		mappedErrStream.Flush()
+		for _, diag := range diagnostics.Diagnostics() {
+			sourceMapper.MapDiagnostic(diag)
+			r.Diagnostics = append(r.Diagnostics, diag.ToRPCCompileDiagnostic())
+		}
+		if req.GetDumpLibraryResolution() {
+			r.LibraryResolution = libraryResolution(builderCtx.LibrariesResolutionTrace)
+		}
 	}()
 
-	// if --preprocess or --show-properties were passed, we can stop here
+	// if --preprocess, --show-properties or --show-commands were passed, we can stop here
 	if req.GetShowProperties() {
 		return r, builder.RunParseHardwareAndDumpBuildProperties(builderCtx)
+	} else if req.GetShowCommands() {
+		return r, builder.RunParseHardwareAndShowCommands(builderCtx)
 	} else if req.GetPreprocess() {
 		return r, builder.RunPreprocess(builderCtx)
 	}
 
 	// if it's a regular build, go on...
+	buildStart := time.Now()
 	if err := builder.RunBuilder(builderCtx); err != nil {
 		return r, err
 	}
+	buildTime := time.Since(buildStart)
+	observeBuildStats(builderCtx, buildTime)
+	if promoted := diagnostics.PromotedWarnings(); len(promoted) > 0 {
+		mappedErrStream.Flush()
+		msgs := []string{}
+		for _, diag := range promoted {
+			sourceMapper.MapDiagnostic(diag)
+			msgs = append(msgs, fmt.Sprintf("%s:%d: %s", diag.File, diag.Line, diag.Message))
+		}
+		return r, fmt.Errorf("warnings promoted to errors:\n  %s", strings.Join(msgs, "\n  "))
+	}
+
+	// Save the SBOM in the build path, it's copied with the binaries when they are exported
+	if format := req.GetSbom(); format != "" {
+		var index *librariesindex.Index
+		if lm := commands.GetLibraryManager(req.GetInstance().GetId()); lm != nil {
+			index = lm.Index
+		}
+		if err := exportSBOM(builderCtx, sketch.Name, fqbn, format, index); err != nil {
+			return r, errors.Wrap(err, "saving SBOM")
+		}
+	}
 
-	// If the export directory is set we assume you want to export the binaries
-	if req.GetExportDir() != "" {
+	exportManifest := req.GetExportManifest() || req.GetManifestTemplate() != ""
+	// If the export directory, the export formats or the manifest are set we assume you want to export the binaries
+	if req.GetExportDir() != "" || len(req.GetExportFormats()) > 0 || exportManifest {
 		exportBinaries = true
 	}
 	// If CreateCompilationDatabaseOnly is set, we do not need to export anything
@@ -260,6 +513,7 @@ func Compile(ctx context.Context, req *rpc.CompileRequest, outStream, errStream
 			return r, errors.Errorf("reading build directory: %s", err)
 		}
 		buildFiles.FilterPrefix(baseName)
+		exportedFiles := paths.NewPathList()
 		for _, buildFile := range buildFiles {
 			exportedFile := exportPath.Join(buildFile.Base())
 			logrus.
@@ -269,6 +523,45 @@ func Compile(ctx context.Context, req *rpc.CompileRequest, outStream, errStream
 			if err = buildFile.CopyTo(exportedFile); err != nil {
 				return r, errors.Wrapf(err, "copying output file %s", buildFile)
 			}
+			exportedFiles.Add(exportedFile)
+		}
+
+		// Save the details of the build along with the binaries
+		optimization := builderCtx.Optimization
+		if optimization == "" && builderCtx.OptimizeForDebug {
+			optimization = bldr.OptimizeDebug
+		}
+		buildInfo := &artifacts.BuildInfo{
+			Fqbn:              fqbn.String(),
+			Optimization:      optimization,
+			OptimizationFlags: builderCtx.OptimizationFlags,
+		}
+		if err := buildInfo.Save(artifacts.BuildInfoFile(exportPath, baseName)); err != nil {
+			return r, errors.Wrap(err, "saving build info")
+		}
+		exportedFiles.Add(artifacts.BuildInfoFile(exportPath, baseName))
+
+		if exportManifest {
+			version := req.GetManifestVersion()
+			if version == "" {
+				version = "dev"
+			}
+			manifest := &bldr.FirmwareManifest{
+				Name:    sketch.Name,
+				Version: version,
+				Fqbn:    fqbn.String(),
+			}
+			if err := exportFirmwareManifest(builderCtx.BuildProperties, exportPath, baseName, manifest, req.GetManifestTemplate()); err != nil {
+				return r, errors.Wrap(err, "saving firmware manifest")
+			}
+			exportedFiles.Add(exportPath.Join(baseName + ".manifest.json"))
+			for _, part := range manifest.Parts {
+				exportedFiles.AddIfMissing(exportPath.Join(part.Path))
+			}
+		}
+
+		if err := exportArtifactsManifest(builderCtx.BuildProperties, sketch.Name, sketch.FullPath, fqbn, exportPath, exportedFiles, artifactsKey); err != nil {
+			return r, errors.Wrap(err, "saving artifacts manifest")
 		}
 	}
 
@@ -283,8 +576,32 @@ func Compile(ctx context.Context, req *rpc.CompileRequest, outStream, errStream
 
 	logrus.Tracef("Compile %s for %s successful", sketch.Name, fqbnIn)
 
-	return &rpc.CompileResponse{
-		UsedLibraries:          importedLibs,
-		ExecutableSectionsSize: builderCtx.ExecutableSectionsSize.ToRPCExecutableSectionSizeArray(),
-	}, nil
+	r.UsedLibraries = importedLibs
+	r.ExecutableSectionsSize = builderCtx.ExecutableSectionsSize.ToRPCExecutableSectionSizeArray()
+	if req.GetStats() {
+		r.Stats = buildStats(builderCtx, buildTime)
+	}
+	if req.GetCreateCompilationDatabaseOnly() {
+		return r, nil
+	}
+	if req.GetSizeReport() == "detailed" || req.GetSizeDeltaFrom() != "" {
+		report, err := sizeReport(builderCtx)
+		if err != nil {
+			return r, fmt.Errorf("creating size report: %w", err)
+		}
+		if req.GetSizeReport() == "detailed" {
+			r.SizeReport = topSymbols(report, bldr.SizeReportTopSymbols)
+		}
+		if req.GetSizeDeltaFrom() != "" {
+			baseline, err := loadSizeBaseline(paths.New(req.GetSizeDeltaFrom()))
+			if err != nil {
+				return r, fmt.Errorf("loading size baseline: %w", err)
+			}
+			r.SizeDelta = sizeDelta(baseline, report)
+			if err := checkSizeDelta(r.SizeDelta, req.GetSizeDeltaMaxFlash(), req.GetSizeDeltaMaxRam()); err != nil {
+				return r, err
+			}
+		}
+	}
+	return r, nil
 }
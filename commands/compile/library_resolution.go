@@ -0,0 +1,55 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"github.com/arduino/arduino-cli/legacy/builder/types"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+)
+
+// libraryResolution converts the library resolution trace recorded by the
+// builder into a list of rpc.LibraryResolution
+func libraryResolution(trace []*types.LibraryResolutionTrace) []*rpc.LibraryResolution {
+	res := []*rpc.LibraryResolution{}
+	for _, entry := range trace {
+		resolution := &rpc.LibraryResolution{
+			SourceFile: entry.SourceFile.String(),
+			Include:    entry.Include,
+			Reason:     entry.Resolution.Reason,
+		}
+		if selected := entry.Resolution.Selected; selected != nil {
+			resolution.Selected = selected.Name
+		}
+		for _, candidate := range entry.Resolution.Candidates {
+			lib := candidate.Library
+			c := &rpc.LibraryResolutionCandidate{
+				Name:     lib.Name,
+				Location: lib.Location.ToRPCLibraryLocation(),
+				Priority: int32(candidate.Priority),
+				Selected: lib == entry.Resolution.Selected,
+			}
+			if lib.Version != nil {
+				c.Version = lib.Version.String()
+			}
+			if lib.InstallDir != nil {
+				c.InstallDir = lib.InstallDir.String()
+			}
+			resolution.Candidates = append(resolution.Candidates, c)
+		}
+		res = append(res, resolution)
+	}
+	return res
+}
@@ -0,0 +1,144 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"crypto"
+	"strings"
+	"time"
+
+	"github.com/arduino/arduino-cli/arduino/artifacts"
+	bldr "github.com/arduino/arduino-cli/arduino/builder"
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/arduino/partitions"
+	"github.com/arduino/arduino-cli/cli/globals"
+	paths "github.com/arduino/go-paths-helper"
+	properties "github.com/arduino/go-properties-orderedmap"
+	"github.com/pkg/errors"
+)
+
+// Offsets of the binaries flashed by the ESP32 platforms
+const (
+	esp32PartitionsOffset = 0x8000
+	esp32BootApp0Offset   = 0xE000
+	esp32AppOffset        = 0x10000
+)
+
+// exportFirmwareManifest saves the manifest of the exported binaries in the
+// export path, in the ESP Web Tools format or in the layout of the given
+// template file.
+func exportFirmwareManifest(buildProperties *properties.Map, exportPath *paths.Path, baseName string, manifest *bldr.FirmwareManifest, templateFile string) error {
+	parts, err := firmwareParts(buildProperties, exportPath, baseName)
+	if err != nil {
+		return err
+	}
+	manifest.Parts = parts
+	manifest.ChipFamily = buildProperties.Get("build.manifest.chip_family")
+	if manifest.ChipFamily == "" {
+		mcu, ok := buildProperties.GetOk("build.mcu")
+		if !ok {
+			mcu = buildProperties.Get("build.arch")
+		}
+		manifest.ChipFamily = bldr.ChipFamily(mcu)
+	}
+
+	var data []byte
+	if templateFile != "" {
+		layout, err := paths.New(templateFile).ReadFile()
+		if err != nil {
+			return errors.Wrap(err, "reading manifest template")
+		}
+		if data, err = manifest.Render(string(layout)); err != nil {
+			return err
+		}
+	} else if data, err = manifest.ESPWebToolsJSON(); err != nil {
+		return err
+	}
+	return errors.WithStack(exportPath.Join(baseName + ".manifest.json").WriteFile(data))
+}
+
+// firmwareParts returns the exported binaries to flash and their offsets.
+// Only the sketch binary, placed at 0, is flashed on most boards. The ESP32
+// boards need the bootloader, the partitions table and the boot_app0.bin
+// of the platform too, that is copied in the export path.
+func firmwareParts(buildProperties *properties.Map, exportPath *paths.Path, baseName string) ([]*bldr.FirmwarePart, error) {
+	app := exportPath.Join(baseName + ".bin")
+	if app.NotExist() {
+		return nil, errors.New("the platform didn't create a .bin file to add to the manifest")
+	}
+	if buildProperties.Get("build.arch") != "ESP32" {
+		return []*bldr.FirmwarePart{{Path: app.Base()}}, nil
+	}
+
+	parts := []*bldr.FirmwarePart{}
+	if bootloader := exportPath.Join(baseName + ".bootloader.bin"); bootloader.Exist() {
+		offset := uint64(0)
+		if mcu := buildProperties.Get("build.mcu"); mcu == "esp32" || mcu == "esp32s2" {
+			offset = 0x1000
+		}
+		if addr, ok := buildProperties.GetOk("build.bootloader_addr"); ok {
+			n, err := partitions.ParseSize(addr)
+			if err != nil {
+				return nil, errors.Errorf("invalid build.bootloader_addr property: %s", err)
+			}
+			offset = n
+		}
+		parts = append(parts, &bldr.FirmwarePart{Path: bootloader.Base(), Offset: offset})
+	}
+	if table := exportPath.Join(baseName + ".partitions.bin"); table.Exist() {
+		parts = append(parts, &bldr.FirmwarePart{Path: table.Base(), Offset: esp32PartitionsOffset})
+	}
+	bootApp0 := buildProperties.GetPath("runtime.platform.path").Join("tools", "partitions", "boot_app0.bin")
+	if bootApp0.Exist() {
+		if err := bootApp0.CopyTo(exportPath.Join(bootApp0.Base())); err != nil {
+			return nil, errors.Wrap(err, "copying boot_app0.bin")
+		}
+		parts = append(parts, &bldr.FirmwarePart{Path: bootApp0.Base(), Offset: esp32BootApp0Offset})
+	}
+	parts = append(parts, &bldr.FirmwarePart{Path: app.Base(), Offset: esp32AppOffset})
+	return parts, nil
+}
+
+// exportArtifactsManifest saves in the export path the manifest of the
+// exported files, signed with the given key if not nil.
+func exportArtifactsManifest(buildProperties *properties.Map, sketchName string, sketchPath *paths.Path, fqbn *cores.FQBN, exportPath *paths.Path, files paths.PathList, key crypto.Signer) error {
+	manifest := &artifacts.Manifest{
+		Sketch:          sketchName,
+		Fqbn:            fqbn.String(),
+		GitCommit:       artifacts.GitCommit(sketchPath),
+		CLIVersion:      globals.VersionInfo.VersionString,
+		Created:         time.Now().UTC(),
+		BuildProperties: map[string]string{},
+		Artifacts:       []*artifacts.ManifestArtifact{},
+	}
+	for _, k := range buildProperties.Keys() {
+		// The paths depend on the machine running the build
+		if strings.HasPrefix(k, "build.") && !strings.HasSuffix(k, ".path") {
+			manifest.BuildProperties[k] = buildProperties.Get(k)
+		}
+	}
+	files.Sort()
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		if err := manifest.AddArtifact(file); err != nil {
+			return err
+		}
+	}
+	baseName := buildProperties.Get("build.project_name")
+	return manifest.Save(artifacts.ManifestFile(exportPath, baseName), key)
+}
@@ -0,0 +1,85 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"encoding/json"
+	"testing"
+
+	bldr "github.com/arduino/arduino-cli/arduino/builder"
+	paths "github.com/arduino/go-paths-helper"
+	properties "github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportFirmwareManifest(t *testing.T) {
+	dir, err := paths.MkTempDir("", "test_firmware_manifest")
+	require.NoError(t, err)
+	defer dir.RemoveAll()
+
+	platformDir := dir.Join("platform")
+	require.NoError(t, platformDir.Join("tools", "partitions").MkdirAll())
+	require.NoError(t, platformDir.Join("tools", "partitions", "boot_app0.bin").WriteFile([]byte{0}))
+	exportPath := dir.Join("export")
+	require.NoError(t, exportPath.MkdirAll())
+
+	buildProperties := properties.NewMap()
+	buildProperties.Set("build.arch", "AVR")
+	buildProperties.Set("build.mcu", "atmega328p")
+	manifest := &bldr.FirmwareManifest{Name: "Blink", Version: "dev", Fqbn: "arduino:avr:uno"}
+
+	// The platform must create a .bin file
+	require.Error(t, exportFirmwareManifest(buildProperties, exportPath, "Blink.ino", manifest, ""))
+
+	require.NoError(t, exportPath.Join("Blink.ino.bin").WriteFile([]byte{0}))
+	require.NoError(t, exportFirmwareManifest(buildProperties, exportPath, "Blink.ino", manifest, ""))
+	require.Equal(t, "ATMEGA328P", manifest.ChipFamily)
+	require.Equal(t, []*bldr.FirmwarePart{{Path: "Blink.ino.bin", Offset: 0}}, manifest.Parts)
+	data, err := exportPath.Join("Blink.ino.manifest.json").ReadFile()
+	require.NoError(t, err)
+	require.True(t, json.Valid(data))
+
+	// ESP32
+	buildProperties.Set("build.arch", "ESP32")
+	buildProperties.Set("build.mcu", "esp32")
+	buildProperties.SetPath("runtime.platform.path", platformDir)
+	require.NoError(t, exportPath.Join("Blink.ino.bootloader.bin").WriteFile([]byte{0}))
+	require.NoError(t, exportPath.Join("Blink.ino.partitions.bin").WriteFile([]byte{0}))
+	require.NoError(t, exportFirmwareManifest(buildProperties, exportPath, "Blink.ino", manifest, ""))
+	require.Equal(t, "ESP32", manifest.ChipFamily)
+	require.Equal(t, []*bldr.FirmwarePart{
+		{Path: "Blink.ino.bootloader.bin", Offset: 0x1000},
+		{Path: "Blink.ino.partitions.bin", Offset: 0x8000},
+		{Path: "boot_app0.bin", Offset: 0xE000},
+		{Path: "Blink.ino.bin", Offset: 0x10000},
+	}, manifest.Parts)
+	require.True(t, exportPath.Join("boot_app0.bin").Exist())
+
+	// The bootloader address can be set by the platform
+	buildProperties.Set("build.mcu", "esp32s3")
+	buildProperties.Set("build.bootloader_addr", "0x0")
+	require.NoError(t, exportFirmwareManifest(buildProperties, exportPath, "Blink.ino", manifest, ""))
+	require.Equal(t, "ESP32-S3", manifest.ChipFamily)
+	require.Equal(t, uint64(0), manifest.Parts[0].Offset)
+
+	// Custom layout
+	template := dir.Join("manifest.tmpl")
+	require.NoError(t, template.WriteFile([]byte(`{{.Name}}@{{.Version}}{{range .Parts}} {{.Path}}:{{hex .Offset}}{{end}}`)))
+	require.NoError(t, exportFirmwareManifest(buildProperties, exportPath, "Blink.ino", manifest, template.String()))
+	data, err = exportPath.Join("Blink.ino.manifest.json").ReadFile()
+	require.NoError(t, err)
+	require.Equal(t, "Blink@dev Blink.ino.bootloader.bin:0x0 Blink.ino.partitions.bin:0x8000 boot_app0.bin:0xE000 Blink.ino.bin:0x10000", string(data))
+}
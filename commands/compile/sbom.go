@@ -0,0 +1,160 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"time"
+
+	bldr "github.com/arduino/arduino-cli/arduino/builder"
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/arduino/libraries"
+	"github.com/arduino/arduino-cli/arduino/libraries/librariesindex"
+	"github.com/arduino/arduino-cli/arduino/resources"
+	"github.com/arduino/arduino-cli/cli/globals"
+	"github.com/arduino/arduino-cli/legacy/builder/types"
+	paths "github.com/arduino/go-paths-helper"
+	"github.com/pkg/errors"
+)
+
+// exportSBOM saves the software bill of materials of the build, in the given
+// format, in the build path. The libraries index, if not nil, is used to find
+// the archives of the libraries installed from it.
+func exportSBOM(builderCtx *types.Context, sketchName string, fqbn *cores.FQBN, format string, index *librariesindex.Index) error {
+	sbom := &bldr.SBOM{
+		Name:        sketchName,
+		Fqbn:        fqbn.String(),
+		Created:     time.Now(),
+		ToolName:    "arduino-cli",
+		ToolVersion: globals.VersionInfo.VersionString,
+	}
+
+	sbom.Components = append(sbom.Components, platformComponent(builderCtx.TargetPlatform))
+	if actual := builderCtx.ActualPlatform; actual != nil && actual != builderCtx.TargetPlatform {
+		sbom.Components = append(sbom.Components, platformComponent(actual))
+	}
+	for _, lib := range builderCtx.ImportedLibraries {
+		component, err := libraryComponent(lib, index)
+		if err != nil {
+			return err
+		}
+		sbom.Components = append(sbom.Components, component)
+	}
+	for _, tool := range builderCtx.RequiredTools {
+		component := &bldr.SBOMComponent{
+			Type:     bldr.SBOMComponentTool,
+			Name:     tool.Tool.Name,
+			Version:  tool.Version.String(),
+			Supplier: tool.Tool.Package.Maintainer,
+		}
+		setComponentResource(component, tool.GetCompatibleFlavour())
+		sbom.Components = append(sbom.Components, component)
+	}
+
+	data, err := sbom.Render(format)
+	if err != nil {
+		return err
+	}
+	baseName := builderCtx.BuildProperties.Get("build.project_name")
+	return errors.WithStack(builderCtx.BuildPath.Join(bldr.SBOMFileName(baseName, format)).WriteFile(data))
+}
+
+// platformComponent returns the SBOM component of a core
+func platformComponent(platform *cores.PlatformRelease) *bldr.SBOMComponent {
+	component := &bldr.SBOMComponent{
+		Type:     bldr.SBOMComponentCore,
+		Name:     platform.Platform.String(),
+		Supplier: platform.Platform.Package.Maintainer,
+	}
+	if platform.Version != nil {
+		component.Version = platform.Version.String()
+	}
+	setComponentResource(component, platform.Resource)
+	return component
+}
+
+// libraryComponent returns the SBOM component of a library. The checksum of
+// the libraries installed from the libraries index is the one of their
+// archive, the checksum of the others is computed from their files.
+func libraryComponent(lib *libraries.Library, index *librariesindex.Index) (*bldr.SBOMComponent, error) {
+	component := &bldr.SBOMComponent{
+		Type:     bldr.SBOMComponentLibrary,
+		Name:     lib.Name,
+		Supplier: lib.Maintainer,
+		License:  lib.License,
+		Location: lib.Location.String(),
+	}
+	if lib.Version != nil {
+		component.Version = lib.Version.String()
+	}
+	if index != nil && lib.Version != nil && (lib.Location == libraries.User || lib.Location == libraries.IDEBuiltIn) {
+		if release := index.FindRelease(&librariesindex.Reference{Name: lib.Name, Version: lib.Version}); release != nil {
+			setComponentResource(component, release.Resource)
+		}
+	}
+	if component.Checksum == "" {
+		checksum, err := filesChecksum(lib.InstallDir)
+		if err != nil {
+			return nil, fmt.Errorf("computing checksum of library %s: %w", lib.Name, err)
+		}
+		component.Checksum = checksum
+	}
+	return component, nil
+}
+
+// setComponentResource sets the source URL and the checksum of the component
+// to the ones of the archive it's installed from, if any.
+func setComponentResource(component *bldr.SBOMComponent, resource *resources.DownloadResource) {
+	if resource == nil {
+		return
+	}
+	component.URL = resource.URL
+	component.Checksum = resource.Checksum
+}
+
+// filesChecksum returns the SHA-256 of the relative paths and of the content
+// of the files in the given folder, sorted by path.
+func filesChecksum(folder *paths.Path) (string, error) {
+	files, err := folder.ReadDirRecursive()
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	files.Sort()
+	hash := sha256.New()
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		rel, err := file.RelTo(folder)
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		fmt.Fprintln(hash, rel.String())
+		f, err := file.Open()
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		_, err = io.Copy(hash, f)
+		f.Close()
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+	}
+	return "SHA-256:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,203 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	bldr "github.com/arduino/arduino-cli/arduino/builder"
+	"github.com/arduino/arduino-cli/legacy/builder/types"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	paths "github.com/arduino/go-paths-helper"
+)
+
+// sizeReport analyzes the linked executable and attributes its symbols to
+// the sketch, the core and the libraries that have been compiled
+func sizeReport(builderCtx *types.Context) (*rpc.SizeReport, error) {
+	projectName, ok := builderCtx.BuildProperties.GetOk("build.project_name")
+	if !ok {
+		return nil, errors.New("missing 'build.project_name' build property")
+	}
+	executable := builderCtx.BuildPath.Join(projectName + ".elf")
+
+	coreObjects := builderCtx.CoreObjectsFiles.Clone()
+	if builderCtx.CoreArchiveFilePath != nil {
+		coreObjects.Add(builderCtx.CoreArchiveFilePath)
+	}
+	objects := map[string]paths.PathList{
+		"sketch": builderCtx.SketchObjectFiles,
+		"core":   coreObjects,
+	}
+	for _, lib := range builderCtx.ImportedLibraries {
+		libBuildPath := builderCtx.LibrariesBuildPath.Join(lib.Name)
+		libObjects := paths.PathList{}
+		for _, object := range builderCtx.LibrariesObjectFiles {
+			if inside, _ := object.IsInsideDir(libBuildPath); inside {
+				libObjects.Add(object)
+			}
+		}
+		objects[lib.Name] = libObjects
+	}
+
+	// All the symbols are needed to compare the executable with the baseline,
+	// the report is cut by topSymbols
+	report, err := bldr.NewSizeReport(executable, objects, 0)
+	if err != nil {
+		return nil, err
+	}
+	return report.ToRPCSizeReport(), nil
+}
+
+// topSymbols returns a copy of the report listing only its n biggest symbols
+func topSymbols(report *rpc.SizeReport, n int) *rpc.SizeReport {
+	if len(report.GetSymbols()) <= n {
+		return report
+	}
+	return &rpc.SizeReport{
+		Sections:         report.GetSections(),
+		Origins:          report.GetOrigins(),
+		Symbols:          report.GetSymbols()[:n],
+		SymbolsTruncated: true,
+	}
+}
+
+// loadSizeBaseline reads the size report to compare the executable with from
+// an ELF file, a size report in JSON format or the JSON output of a compile
+// with the detailed size report.
+func loadSizeBaseline(file *paths.Path) (*rpc.SizeReport, error) {
+	data, err := file.ReadFile()
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(data, []byte(elf.ELFMAG)) {
+		report, err := bldr.NewSizeReport(file, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		return report.ToRPCSizeReport(), nil
+	}
+
+	for {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return nil, fmt.Errorf("reading %s: %w", file, err)
+		}
+		if res, ok := fields["builder_result"]; ok {
+			data = res
+		} else if res, ok := fields["size_report"]; ok {
+			data = res
+		} else {
+			break
+		}
+	}
+	report := &rpc.SizeReport{}
+	if err := json.Unmarshal(data, report); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", file, err)
+	}
+	if len(report.GetSections()) == 0 {
+		return nil, fmt.Errorf("no size report found in %s", file)
+	}
+	return report, nil
+}
+
+// sizeDelta compares the size report of the executable with the baseline
+func sizeDelta(baseline, current *rpc.SizeReport) *rpc.SizeDelta {
+	res := &rpc.SizeDelta{Symbols: []*rpc.SymbolDelta{}}
+	res.BaselineFlash, res.BaselineRam = memoryUsage(baseline)
+	res.Flash, res.Ram = memoryUsage(current)
+
+	symbols := map[string]*rpc.SymbolDelta{}
+	for _, symbol := range baseline.GetSymbols() {
+		delta, ok := symbols[symbol.GetName()]
+		if !ok {
+			delta = &rpc.SymbolDelta{Name: symbol.GetName(), Origin: symbol.GetOrigin()}
+			symbols[symbol.GetName()] = delta
+		}
+		delta.BaselineSize += symbol.GetSize()
+	}
+	// The symbols missing from a truncated baseline may be smaller than the
+	// ones listed, they are compared only if they are bigger
+	minBaselineSize := int64(0)
+	if baseline.GetSymbolsTruncated() {
+		for _, symbol := range baseline.GetSymbols() {
+			if minBaselineSize == 0 || symbol.GetSize() < minBaselineSize {
+				minBaselineSize = symbol.GetSize()
+			}
+		}
+	}
+	for _, symbol := range current.GetSymbols() {
+		delta, ok := symbols[symbol.GetName()]
+		if !ok && symbol.GetSize() <= minBaselineSize {
+			continue
+		}
+		if !ok {
+			delta = &rpc.SymbolDelta{Name: symbol.GetName()}
+			symbols[symbol.GetName()] = delta
+		}
+		// The baseline may come from an ELF file, without attribution
+		delta.Origin = symbol.GetOrigin()
+		delta.Size += symbol.GetSize()
+	}
+	for _, delta := range symbols {
+		if delta.Size != delta.BaselineSize {
+			res.Symbols = append(res.Symbols, delta)
+		}
+	}
+	abs := func(x int64) int64 {
+		if x < 0 {
+			return -x
+		}
+		return x
+	}
+	sort.Slice(res.Symbols, func(i, j int) bool {
+		a, b := res.Symbols[i], res.Symbols[j]
+		if da, db := abs(a.Size-a.BaselineSize), abs(b.Size-b.BaselineSize); da != db {
+			return da > db
+		}
+		return a.Name < b.Name
+	})
+	return res
+}
+
+// memoryUsage returns the flash and the RAM used by the sections in the report
+func memoryUsage(report *rpc.SizeReport) (flash int64, ram int64) {
+	for _, section := range report.GetSections() {
+		if section.GetFlash() {
+			flash += section.GetSize()
+		}
+		if section.GetRam() {
+			ram += section.GetSize()
+		}
+	}
+	return flash, ram
+}
+
+// checkSizeDelta returns an error if the flash or the RAM usage grew more than
+// the allowed thresholds
+func checkSizeDelta(delta *rpc.SizeDelta, maxFlash, maxRAM int64) error {
+	if grown := delta.GetFlash() - delta.GetBaselineFlash(); grown > maxFlash {
+		return fmt.Errorf("flash usage grew by %d bytes, more than the allowed %d bytes", grown, maxFlash)
+	}
+	if grown := delta.GetRam() - delta.GetBaselineRam(); grown > maxRAM {
+		return fmt.Errorf("RAM usage grew by %d bytes, more than the allowed %d bytes", grown, maxRAM)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"testing"
+
+	bldr "github.com/arduino/arduino-cli/arduino/builder"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	paths "github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSizeDelta(t *testing.T) {
+	baseline := &rpc.SizeReport{
+		Sections: []*rpc.SectionUsage{
+			{Name: ".text", Size: 1000, Flash: true},
+			{Name: ".data", Size: 100, Flash: true, Ram: true},
+			{Name: ".bss", Size: 50, Ram: true},
+		},
+		Symbols: []*rpc.SymbolUsage{
+			{Name: "loop", Size: 30, Origin: bldr.OtherOrigin},
+			{Name: "setup", Size: 20, Origin: bldr.OtherOrigin},
+			{Name: "removed", Size: 10, Origin: bldr.OtherOrigin},
+		},
+	}
+	current := &rpc.SizeReport{
+		Sections: []*rpc.SectionUsage{
+			{Name: ".text", Size: 1100, Flash: true},
+			{Name: ".data", Size: 100, Flash: true, Ram: true},
+			{Name: ".bss", Size: 40, Ram: true},
+		},
+		Symbols: []*rpc.SymbolUsage{
+			{Name: "buffer", Size: 64, Origin: "MyLib"},
+			{Name: "loop", Size: 40, Origin: "sketch"},
+			{Name: "setup", Size: 20, Origin: "sketch"},
+		},
+	}
+
+	delta := sizeDelta(baseline, current)
+	require.Equal(t, int64(1100), delta.GetBaselineFlash())
+	require.Equal(t, int64(1200), delta.GetFlash())
+	require.Equal(t, int64(150), delta.GetBaselineRam())
+	require.Equal(t, int64(140), delta.GetRam())
+	require.Equal(t, []*rpc.SymbolDelta{
+		{Name: "buffer", Origin: "MyLib", Size: 64},
+		{Name: "loop", Origin: "sketch", BaselineSize: 30, Size: 40},
+		{Name: "removed", Origin: bldr.OtherOrigin, BaselineSize: 10},
+	}, delta.GetSymbols())
+
+	require.NoError(t, checkSizeDelta(delta, 100, 0))
+	require.EqualError(t, checkSizeDelta(delta, 99, 0), "flash usage grew by 100 bytes, more than the allowed 99 bytes")
+
+	delta = sizeDelta(current, baseline)
+	require.EqualError(t, checkSizeDelta(delta, 0, 5), "RAM usage grew by 10 bytes, more than the allowed 5 bytes")
+}
+
+func TestSizeDeltaTruncatedBaseline(t *testing.T) {
+	report := func(loopSize int64) *rpc.SizeReport {
+		return &rpc.SizeReport{
+			Symbols: []*rpc.SymbolUsage{
+				{Name: "buffer", Size: 64},
+				{Name: "loop", Size: loopSize},
+				{Name: "setup", Size: 20},
+				{Name: "small", Size: 8},
+			},
+		}
+	}
+	baseline := topSymbols(report(40), 2)
+	require.True(t, baseline.GetSymbolsTruncated())
+	require.Len(t, baseline.GetSymbols(), 2)
+	require.False(t, topSymbols(report(40), 4).GetSymbolsTruncated())
+
+	// The symbols not listed in the baseline, because smaller than the ones
+	// listed, are not reported as new
+	current := report(48)
+	delta := sizeDelta(baseline, current)
+	require.Equal(t, []*rpc.SymbolDelta{
+		{Name: "loop", BaselineSize: 40, Size: 48},
+	}, delta.GetSymbols())
+
+	current.Symbols = append(current.Symbols, &rpc.SymbolUsage{Name: "huge", Size: 100})
+	delta = sizeDelta(baseline, current)
+	require.Equal(t, []*rpc.SymbolDelta{
+		{Name: "huge", Size: 100},
+		{Name: "loop", BaselineSize: 40, Size: 48},
+	}, delta.GetSymbols())
+}
+
+func TestLoadSizeBaseline(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "size_baseline")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+
+	report := `{"sections":[{"name":".text","size":1000,"flash":true}],"symbols":[{"name":"loop","size":30}]}`
+	for name, content := range map[string]string{
+		"report.json":         report,
+		"response.json":       `{"size_report":` + report + `}`,
+		"compile_output.json": `{"compiler_out":"","builder_result":{"size_report":` + report + `},"success":true}`,
+	} {
+		file := tmp.Join(name)
+		require.NoError(t, file.WriteFile([]byte(content)))
+		baseline, err := loadSizeBaseline(file)
+		require.NoError(t, err, name)
+		require.Len(t, baseline.GetSections(), 1, name)
+		require.Equal(t, int64(1000), baseline.GetSections()[0].GetSize(), name)
+		require.Equal(t, "loop", baseline.GetSymbols()[0].GetName(), name)
+	}
+
+	// A compile output without the detailed size report
+	file := tmp.Join("no_report.json")
+	require.NoError(t, file.WriteFile([]byte(`{"builder_result":{"build_path":"/tmp"},"success":true}`)))
+	_, err = loadSizeBaseline(file)
+	require.Error(t, err)
+
+	_, err = loadSizeBaseline(tmp.Join("missing.json"))
+	require.Error(t, err)
+}
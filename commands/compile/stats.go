@@ -0,0 +1,116 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/arduino/arduino-cli/legacy/builder/types"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	paths "github.com/arduino/go-paths-helper"
+	"github.com/segmentio/stats/v4"
+)
+
+// buildStats converts the statistics collected by the builder into a
+// rpc.BuildStats
+func buildStats(builderCtx *types.Context, wallTime time.Duration) *rpc.BuildStats {
+	s := builderCtx.Stats
+	res := &rpc.BuildStats{
+		TranslationUnits:  int32(s.TranslationUnits),
+		ReusedObjectFiles: int32(s.ReusedObjectFiles),
+		LinesCompiled:     s.LinesCompiled,
+		WallTimeMs:        wallTime.Milliseconds(),
+		CpuTimeMs:         s.CompilerCPUTime.Milliseconds(),
+		CoreCacheHit:      s.CoreCacheHit,
+		Libraries:         []string{},
+	}
+	for _, lib := range builderCtx.ImportedLibraries {
+		if lib.Version != nil {
+			res.Libraries = append(res.Libraries, lib.Name+"@"+lib.Version.String())
+		} else {
+			res.Libraries = append(res.Libraries, lib.Name)
+		}
+	}
+	if len(s.Events) > 0 {
+		start := s.Events[0].Start
+		for _, event := range s.Events {
+			if event.Start.Before(start) {
+				start = event.Start
+			}
+		}
+		for _, event := range s.Events {
+			res.Events = append(res.Events, &rpc.BuildEvent{
+				Name:       event.Name,
+				Category:   event.Category,
+				StartUs:    event.Start.Sub(start).Microseconds(),
+				DurationUs: event.Duration.Microseconds(),
+			})
+		}
+		sort.SliceStable(res.Events, func(i, j int) bool { return res.Events[i].StartUs < res.Events[j].StartUs })
+	}
+	if builderCtx.BuildProperties != nil {
+		projectName := builderCtx.BuildProperties.Get("build.project_name")
+		for _, ext := range []string{".bin", ".elf"} {
+			binary := builderCtx.BuildPath.Join(projectName + ext)
+			if entropy, err := fileEntropy(binary); err == nil {
+				res.BinaryEntropy = entropy
+				break
+			}
+		}
+	}
+	return res
+}
+
+// observeBuildStats reports the duration of the build and the hit rates of
+// the core and object files caches to the metrics engine
+func observeBuildStats(builderCtx *types.Context, wallTime time.Duration) {
+	s := builderCtx.Stats
+	if s == nil {
+		return
+	}
+	stats.Observe("compile.duration_seconds", wallTime.Seconds())
+	stats.Incr("compile.core_cache", stats.T("hit", strconv.FormatBool(s.CoreCacheHit)))
+	stats.Add("compile.object_files", s.ReusedObjectFiles, stats.T("reused", "true"))
+	stats.Add("compile.object_files", s.TranslationUnits, stats.T("reused", "false"))
+}
+
+// fileEntropy returns the Shannon entropy of the content of the given file,
+// in bits per byte
+func fileEntropy(file *paths.Path) (float64, error) {
+	data, err := file.ReadFile()
+	if err != nil {
+		return 0, err
+	}
+	if len(data) == 0 {
+		return 0, nil
+	}
+	counts := [256]int{}
+	for _, b := range data {
+		counts[b]++
+	}
+	entropy := 0.0
+	for _, c := range counts {
+		if c == 0 {
+			continue
+		}
+		p := float64(c) / float64(len(data))
+		entropy -= p * math.Log2(p)
+	}
+	return entropy, nil
+}
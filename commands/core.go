@@ -64,3 +64,26 @@ func PlatformReleaseToRPC(platformRelease *cores.PlatformRelease) *rpc.Platform
 
 	return result
 }
+
+// ProgrammerToRPC converts a programmer to the RPC structure, id is the
+// identifier of the programmer in programmers.txt.
+func ProgrammerToRPC(id string, programmer *cores.Programmer) *rpc.Programmer {
+	props := programmer.Properties
+	firstOf := func(keys ...string) string {
+		for _, key := range keys {
+			if value, ok := props.GetOk(key); ok {
+				return value
+			}
+		}
+		return ""
+	}
+	return &rpc.Programmer{
+		Id:            id,
+		Platform:      programmer.PlatformRelease.String(),
+		Name:          programmer.Name,
+		Protocol:      firstOf("program.protocol", "protocol"),
+		Communication: props.Get("communication"),
+		Speed:         firstOf("program.speed", "speed"),
+		Tool:          props.Get("program.tool"),
+	}
+}
@@ -0,0 +1,87 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package core
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arduino/arduino-cli/arduino/cores/packagemanager"
+	"github.com/arduino/arduino-cli/commands"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/pkg/errors"
+)
+
+// PlatformDetails describes a platform release and the tools it requires, so
+// the user knows what PlatformInstall is going to download and install
+func PlatformDetails(ctx context.Context, req *rpc.PlatformDetailsRequest) (*rpc.PlatformDetailsResponse, error) {
+	pm := commands.GetPackageManager(req.GetInstance().GetId())
+	if pm == nil {
+		return nil, errors.New("invalid instance")
+	}
+
+	version, err := commands.ParseVersion(req)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version: %s", err)
+	}
+
+	platformRelease, tools, err := pm.FindPlatformReleaseDependencies(&packagemanager.PlatformReference{
+		Package:              req.PlatformPackage,
+		PlatformArchitecture: req.Architecture,
+		PlatformVersion:      version,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("finding platform dependencies: %s", err)
+	}
+
+	platform := commands.PlatformReleaseToRPC(platformRelease)
+	if installed := pm.GetInstalledPlatformRelease(platformRelease.Platform); installed != nil {
+		platform.Installed = installed.Version.String()
+	}
+	res := &rpc.PlatformDetailsResponse{
+		Platform:    platform,
+		PostInstall: rpc.PostInstallScript_POST_INSTALL_SCRIPT_UNKNOWN,
+	}
+	if platformRelease.Resource != nil {
+		res.Size = platformRelease.Resource.Size
+	}
+	if platformRelease.IsInstalled() {
+		res.PostInstall = rpc.PostInstallScript_POST_INSTALL_SCRIPT_NONE
+		if pm.PostInstallScript(platformRelease) != nil {
+			res.PostInstall = rpc.PostInstallScript_POST_INSTALL_SCRIPT_PRESENT
+		}
+	} else if present, known := pm.ArchiveHasPostInstallScript(platformRelease); known {
+		// The archive has already been downloaded
+		res.PostInstall = rpc.PostInstallScript_POST_INSTALL_SCRIPT_NONE
+		if present {
+			res.PostInstall = rpc.PostInstallScript_POST_INSTALL_SCRIPT_PRESENT
+		}
+	}
+
+	for _, tool := range tools {
+		details := &rpc.PlatformToolDetails{
+			Packager:  tool.Tool.Package.Name,
+			Name:      tool.Tool.Name,
+			Version:   tool.Version.String(),
+			Installed: tool.IsInstalled(),
+		}
+		if flavour := tool.GetCompatibleFlavour(); flavour != nil {
+			details.Size = flavour.Size
+		}
+		res.Tools = append(res.Tools, details)
+	}
+	return res, nil
+}
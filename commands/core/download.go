@@ -24,6 +24,7 @@ import (
 	"github.com/arduino/arduino-cli/arduino/cores/packagemanager"
 	"github.com/arduino/arduino-cli/commands"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/spf13/viper"
 )
 
 // PlatformDownload FIXMEDOC
@@ -47,24 +48,17 @@ func PlatformDownload(ctx context.Context, req *rpc.PlatformDownloadRequest, dow
 		return nil, fmt.Errorf("find platform dependencies: %s", err)
 	}
 
-	err = downloadPlatform(pm, platform, downloadCB)
+	err = downloadPlatformAndTools(pm, commands.GetSettings(req.GetInstance().GetId()), platform, tools, downloadCB)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, tool := range tools {
-		err := downloadTool(pm, tool, downloadCB)
-		if err != nil {
-			return nil, fmt.Errorf("downloading tool %s: %s", tool, err)
-		}
-	}
-
 	return &rpc.PlatformDownloadResponse{}, nil
 }
 
-func downloadPlatform(pm *packagemanager.PackageManager, platformRelease *cores.PlatformRelease, downloadCB commands.DownloadProgressCB) error {
+func downloadPlatform(pm *packagemanager.PackageManager, settings *viper.Viper, platformRelease *cores.PlatformRelease, downloadCB commands.DownloadProgressCB) error {
 	// Download platform
-	config, err := commands.GetDownloaderConfig()
+	config, err := commands.GetDownloaderConfig(settings)
 	if err != nil {
 		return err
 	}
@@ -75,11 +69,31 @@ func downloadPlatform(pm *packagemanager.PackageManager, platformRelease *cores.
 	return commands.Download(resp, platformRelease.String(), downloadCB)
 }
 
-func downloadTool(pm *packagemanager.PackageManager, tool *cores.ToolRelease, downloadCB commands.DownloadProgressCB) error {
+// downloadPlatformAndTools downloads the archives of the platform and of the
+// tools concurrently, as configured in the network.parallel_downloads setting
+// of the instance
+func downloadPlatformAndTools(pm *packagemanager.PackageManager, settings *viper.Viper, platformRelease *cores.PlatformRelease, tools []*cores.ToolRelease, downloadCB commands.DownloadProgressCB) error {
+	jobs := []commands.DownloadJob{}
+	for _, tool := range tools {
+		tool := tool
+		jobs = append(jobs, func(downloadCB commands.DownloadProgressCB) error {
+			if err := downloadTool(pm, settings, tool, downloadCB); err != nil {
+				return fmt.Errorf("downloading tool %s: %s", tool, err)
+			}
+			return nil
+		})
+	}
+	jobs = append(jobs, func(downloadCB commands.DownloadProgressCB) error {
+		return downloadPlatform(pm, settings, platformRelease, downloadCB)
+	})
+	return commands.DownloadParallel(settings.GetInt("network.parallel_downloads"), jobs, downloadCB)
+}
+
+func downloadTool(pm *packagemanager.PackageManager, settings *viper.Viper, tool *cores.ToolRelease, downloadCB commands.DownloadProgressCB) error {
 	// Check if tool has a flavor available for the current OS
 	if tool.GetCompatibleFlavour() == nil {
 		return fmt.Errorf("tool %s not available for the current OS", tool)
 	}
 
-	return commands.DownloadToolRelease(pm, tool, downloadCB)
+	return commands.DownloadToolRelease(pm, settings, tool, downloadCB)
 }
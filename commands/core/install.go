@@ -24,6 +24,7 @@ import (
 	"github.com/arduino/arduino-cli/commands"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
 	"github.com/pkg/errors"
+	"github.com/spf13/viper"
 )
 
 // PlatformInstall FIXMEDOC
@@ -49,7 +50,7 @@ func PlatformInstall(ctx context.Context, req *rpc.PlatformInstallRequest,
 		return nil, fmt.Errorf("finding platform dependencies: %s", err)
 	}
 
-	err = installPlatform(pm, platform, tools, downloadCB, taskCB, req.GetSkipPostInstall())
+	err = installPlatform(pm, commands.GetSettings(req.GetInstance().GetId()), platform, tools, downloadCB, taskCB, req.GetSkipPostInstall())
 	if err != nil {
 		return nil, err
 	}
@@ -62,7 +63,7 @@ func PlatformInstall(ctx context.Context, req *rpc.PlatformInstallRequest,
 	return &rpc.PlatformInstallResponse{}, nil
 }
 
-func installPlatform(pm *packagemanager.PackageManager,
+func installPlatform(pm *packagemanager.PackageManager, settings *viper.Viper,
 	platformRelease *cores.PlatformRelease, requiredTools []*cores.ToolRelease,
 	downloadCB commands.DownloadProgressCB, taskCB commands.TaskProgressCB,
 	skipPostInstall bool) error {
@@ -86,12 +87,7 @@ func installPlatform(pm *packagemanager.PackageManager,
 
 	// Package download
 	taskCB(&rpc.TaskProgress{Name: "Downloading packages"})
-	for _, tool := range toolsToInstall {
-		if err := downloadTool(pm, tool, downloadCB); err != nil {
-			return err
-		}
-	}
-	err := downloadPlatform(pm, platformRelease, downloadCB)
+	err := downloadPlatformAndTools(pm, settings, platformRelease, toolsToInstall, downloadCB)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,77 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/arduino/arduino-cli/commands"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	paths "github.com/arduino/go-paths-helper"
+)
+
+// ToolsGarbageCollect removes the installed tools that are not required by
+// any installed platform, for example the old versions left behind by a
+// platform upgrade. If DryRun is set the tools are only reported.
+func ToolsGarbageCollect(ctx context.Context, req *rpc.ToolsGarbageCollectRequest) (*rpc.ToolsGarbageCollectResponse, error) {
+	pm := commands.GetPackageManager(req.GetInstance().GetId())
+	if pm == nil {
+		return nil, errors.New("invalid instance")
+	}
+
+	unused, err := pm.UnusedToolReleases()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &rpc.ToolsGarbageCollectResponse{Tools: []*rpc.UnusedTool{}}
+	for _, toolRelease := range unused {
+		tool := &rpc.UnusedTool{
+			Packager:   toolRelease.Tool.Package.Name,
+			Name:       toolRelease.Tool.Name,
+			Version:    toolRelease.Version.String(),
+			InstallDir: toolRelease.InstallDir.String(),
+			Size:       dirSize(toolRelease.InstallDir),
+		}
+		if !req.GetDryRun() {
+			log := pm.Log.WithField("Tool", toolRelease)
+			log.Info("Removing unused tool")
+			if err := pm.UninstallTool(toolRelease); err != nil {
+				log.WithError(err).Error("Error uninstalling")
+				return nil, fmt.Errorf("removing tool %s: %s", toolRelease, err)
+			}
+		}
+		res.Tools = append(res.Tools, tool)
+	}
+	return res, nil
+}
+
+// dirSize returns the total size of the files in dir, in bytes
+func dirSize(dir *paths.Path) int64 {
+	files, err := dir.ReadDirRecursive()
+	if err != nil {
+		return 0
+	}
+	size := int64(0)
+	for _, file := range files {
+		if info, err := file.Stat(); err == nil && !info.IsDir() {
+			size += info.Size()
+		}
+	}
+	return size
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/arduino/arduino-cli/arduino/cores/packagemanager"
 	"github.com/arduino/arduino-cli/commands"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/spf13/viper"
 )
 
 var (
@@ -45,7 +46,7 @@ func PlatformUpgrade(ctx context.Context, req *rpc.PlatformUpgradeRequest,
 		Package:              req.PlatformPackage,
 		PlatformArchitecture: req.Architecture,
 	}
-	if err := upgradePlatform(pm, ref, downloadCB, taskCB, req.GetSkipPostInstall()); err != nil {
+	if err := upgradePlatform(pm, commands.GetSettings(req.GetInstance().GetId()), ref, downloadCB, taskCB, req.GetSkipPostInstall()); err != nil {
 		return nil, err
 	}
 
@@ -57,7 +58,7 @@ func PlatformUpgrade(ctx context.Context, req *rpc.PlatformUpgradeRequest,
 	return &rpc.PlatformUpgradeResponse{}, nil
 }
 
-func upgradePlatform(pm *packagemanager.PackageManager, platformRef *packagemanager.PlatformReference,
+func upgradePlatform(pm *packagemanager.PackageManager, settings *viper.Viper, platformRef *packagemanager.PlatformReference,
 	downloadCB commands.DownloadProgressCB, taskCB commands.TaskProgressCB,
 	skipPostInstall bool) error {
 	if platformRef.PlatformVersion != nil {
@@ -83,7 +84,7 @@ func upgradePlatform(pm *packagemanager.PackageManager, platformRef *packagemana
 	if err != nil {
 		return fmt.Errorf("platform %s is not installed", platformRef)
 	}
-	err = installPlatform(pm, platformRelease, tools, downloadCB, taskCB, skipPostInstall)
+	err = installPlatform(pm, settings, platformRelease, tools, downloadCB, taskCB, skipPostInstall)
 	if err != nil {
 		return err
 	}
@@ -24,6 +24,7 @@ import (
 	"github.com/arduino/arduino-cli/commands/board"
 	"github.com/arduino/arduino-cli/commands/compile"
 	"github.com/arduino/arduino-cli/commands/core"
+	"github.com/arduino/arduino-cli/commands/fs"
 	"github.com/arduino/arduino-cli/commands/lib"
 	"github.com/arduino/arduino-cli/commands/sketch"
 	"github.com/arduino/arduino-cli/commands/upload"
@@ -207,6 +208,7 @@ func (s *ArduinoCoreServerImpl) Compile(req *rpc.CompileRequest, stream rpc.Ardu
 		stream.Context(), req,
 		utils.FeedStreamTo(func(data []byte) { stream.Send(&rpc.CompileResponse{OutStream: data}) }),
 		utils.FeedStreamTo(func(data []byte) { stream.Send(&rpc.CompileResponse{ErrStream: data}) }),
+		func(p *rpc.TaskProgress) { stream.Send(&rpc.CompileResponse{Progress: p}) },
 		false) // Set debug to false
 	if err != nil {
 		return err
@@ -278,14 +280,29 @@ func (s *ArduinoCoreServerImpl) PlatformList(ctx context.Context, req *rpc.Platf
 	return &rpc.PlatformListResponse{InstalledPlatforms: platforms}, nil
 }
 
+// PlatformDetails describes a platform release and the tools it requires
+func (s *ArduinoCoreServerImpl) PlatformDetails(ctx context.Context, req *rpc.PlatformDetailsRequest) (*rpc.PlatformDetailsResponse, error) {
+	return core.PlatformDetails(ctx, req)
+}
+
+// ToolsGarbageCollect removes the tools not required by any installed platform
+func (s *ArduinoCoreServerImpl) ToolsGarbageCollect(ctx context.Context, req *rpc.ToolsGarbageCollectRequest) (*rpc.ToolsGarbageCollectResponse, error) {
+	return core.ToolsGarbageCollect(ctx, req)
+}
+
 // Upload FIXMEDOC
 func (s *ArduinoCoreServerImpl) Upload(req *rpc.UploadRequest, stream rpc.ArduinoCoreService_UploadServer) error {
 	resp, err := upload.Upload(
 		stream.Context(), req,
 		utils.FeedStreamTo(func(data []byte) { stream.Send(&rpc.UploadResponse{OutStream: data}) }),
 		utils.FeedStreamTo(func(data []byte) { stream.Send(&rpc.UploadResponse{ErrStream: data}) }),
+		func(p *rpc.UploadProgress) { stream.Send(&rpc.UploadResponse{Progress: p}) },
 	)
 	if err != nil {
+		// Send the partial result, if any, before the error
+		if resp != nil {
+			stream.Send(resp)
+		}
 		return err
 	}
 	return stream.Send(resp)
@@ -299,6 +316,10 @@ func (s *ArduinoCoreServerImpl) UploadUsingProgrammer(req *rpc.UploadUsingProgra
 		utils.FeedStreamTo(func(data []byte) { stream.Send(&rpc.UploadUsingProgrammerResponse{ErrStream: data}) }),
 	)
 	if err != nil {
+		// Send the partial result, if any, before the error
+		if resp != nil {
+			stream.Send(resp)
+		}
 		return err
 	}
 	return stream.Send(resp)
@@ -311,6 +332,75 @@ func (s *ArduinoCoreServerImpl) BurnBootloader(req *rpc.BurnBootloaderRequest, s
 		utils.FeedStreamTo(func(data []byte) { stream.Send(&rpc.BurnBootloaderResponse{OutStream: data}) }),
 		utils.FeedStreamTo(func(data []byte) { stream.Send(&rpc.BurnBootloaderResponse{ErrStream: data}) }),
 	)
+	if err != nil {
+		// Send the partial result, if any, before the error
+		if resp != nil {
+			stream.Send(resp)
+		}
+		return err
+	}
+	return stream.Send(resp)
+}
+
+// FusesRead reads the fuses and the lock bits of a board
+func (s *ArduinoCoreServerImpl) FusesRead(req *rpc.FusesReadRequest, stream rpc.ArduinoCoreService_FusesReadServer) error {
+	resp, err := upload.FusesRead(
+		stream.Context(), req,
+		utils.FeedStreamTo(func(data []byte) { stream.Send(&rpc.FusesReadResponse{OutStream: data}) }),
+		utils.FeedStreamTo(func(data []byte) { stream.Send(&rpc.FusesReadResponse{ErrStream: data}) }),
+	)
+	if err != nil {
+		return err
+	}
+	return stream.Send(resp)
+}
+
+// MemoryRead reads the flash or the EEPROM of a board
+func (s *ArduinoCoreServerImpl) MemoryRead(req *rpc.MemoryReadRequest, stream rpc.ArduinoCoreService_MemoryReadServer) error {
+	resp, err := upload.MemoryRead(
+		stream.Context(), req,
+		utils.FeedStreamTo(func(data []byte) { stream.Send(&rpc.MemoryReadResponse{OutStream: data}) }),
+		utils.FeedStreamTo(func(data []byte) { stream.Send(&rpc.MemoryReadResponse{ErrStream: data}) }),
+	)
+	if err != nil {
+		return err
+	}
+	return stream.Send(resp)
+}
+
+// MemoryWrite writes the EEPROM of a board
+func (s *ArduinoCoreServerImpl) MemoryWrite(req *rpc.MemoryWriteRequest, stream rpc.ArduinoCoreService_MemoryWriteServer) error {
+	resp, err := upload.MemoryWrite(
+		stream.Context(), req,
+		utils.FeedStreamTo(func(data []byte) { stream.Send(&rpc.MemoryWriteResponse{OutStream: data}) }),
+		utils.FeedStreamTo(func(data []byte) { stream.Send(&rpc.MemoryWriteResponse{ErrStream: data}) }),
+	)
+	if err != nil {
+		return err
+	}
+	return stream.Send(resp)
+}
+
+// FilesystemBuild FIXMEDOC
+func (s *ArduinoCoreServerImpl) FilesystemBuild(req *rpc.FilesystemBuildRequest, stream rpc.ArduinoCoreService_FilesystemBuildServer) error {
+	resp, err := fs.Build(
+		stream.Context(), req,
+		utils.FeedStreamTo(func(data []byte) { stream.Send(&rpc.FilesystemBuildResponse{OutStream: data}) }),
+		utils.FeedStreamTo(func(data []byte) { stream.Send(&rpc.FilesystemBuildResponse{ErrStream: data}) }),
+	)
+	if err != nil {
+		return err
+	}
+	return stream.Send(resp)
+}
+
+// FilesystemUpload FIXMEDOC
+func (s *ArduinoCoreServerImpl) FilesystemUpload(req *rpc.FilesystemUploadRequest, stream rpc.ArduinoCoreService_FilesystemUploadServer) error {
+	resp, err := fs.Upload(
+		stream.Context(), req,
+		utils.FeedStreamTo(func(data []byte) { stream.Send(&rpc.FilesystemUploadResponse{OutStream: data}) }),
+		utils.FeedStreamTo(func(data []byte) { stream.Send(&rpc.FilesystemUploadResponse{ErrStream: data}) }),
+	)
 	if err != nil {
 		return err
 	}
@@ -390,6 +480,16 @@ func (s *ArduinoCoreServerImpl) ArchiveSketch(ctx context.Context, req *rpc.Arch
 	return sketch.ArchiveSketch(ctx, req)
 }
 
+// CloneSketch clones a Sketch from a git repository
+func (s *ArduinoCoreServerImpl) CloneSketch(ctx context.Context, req *rpc.CloneSketchRequest) (*rpc.CloneSketchResponse, error) {
+	return sketch.CloneSketch(ctx, req)
+}
+
+// ImportSketch creates a Sketch from a project of another build system
+func (s *ArduinoCoreServerImpl) ImportSketch(ctx context.Context, req *rpc.ImportSketchRequest) (*rpc.ImportSketchResponse, error) {
+	return sketch.ImportSketch(ctx, req)
+}
+
 //ZipLibraryInstall FIXMEDOC
 func (s *ArduinoCoreServerImpl) ZipLibraryInstall(req *rpc.ZipLibraryInstallRequest, stream rpc.ArduinoCoreService_ZipLibraryInstallServer) error {
 	err := lib.ZipLibraryInstall(
@@ -17,7 +17,9 @@ package daemon
 
 import (
 	"context"
+	"io/ioutil"
 	"os"
+	"sync"
 
 	"github.com/arduino/arduino-cli/arduino/utils"
 	cmd "github.com/arduino/arduino-cli/commands/debug"
@@ -47,7 +49,9 @@ func (s *DebugService) Debug(stream dbg.DebugService_DebugServer) error {
 		return errors.Errorf("First message must contain debug request, not data")
 	}
 
-	// Launch debug recipe attaching stdin and out to grpc streaming
+	// Launch debug recipe attaching stdin and out to grpc streaming, the
+	// stream can't be used by multiple goroutines at the same time
+	var sendLock sync.Mutex
 	signalChan := make(chan os.Signal)
 	defer close(signalChan)
 	resp, err := cmd.Debug(stream.Context(), req,
@@ -59,12 +63,21 @@ func (s *DebugService) Debug(stream dbg.DebugService_DebugServer) error {
 			return command.GetData(), err
 		}),
 		utils.FeedStreamTo(func(data []byte) {
+			sendLock.Lock()
+			defer sendLock.Unlock()
 			stream.Send(&dbg.DebugResponse{Data: data})
 		}),
+		utils.FeedStreamTo(func(data []byte) {
+			sendLock.Lock()
+			defer sendLock.Unlock()
+			stream.Send(&dbg.DebugResponse{Console: data})
+		}),
 		signalChan)
 	if err != nil {
 		return (err)
 	}
+	sendLock.Lock()
+	defer sendLock.Unlock()
 	return stream.Send(resp)
 }
 
@@ -72,3 +85,25 @@ func (s *DebugService) Debug(stream dbg.DebugService_DebugServer) error {
 func (s *DebugService) GetDebugConfig(ctx context.Context, req *dbg.DebugConfigRequest) (*dbg.GetDebugConfigResponse, error) {
 	return cmd.GetDebugConfig(ctx, req)
 }
+
+// ReadRegisters reads the peripheral registers of the target
+func (s *DebugService) ReadRegisters(ctx context.Context, req *dbg.ReadRegistersRequest) (*dbg.ReadRegistersResponse, error) {
+	return cmd.ReadRegisters(ctx, req, ioutil.Discard)
+}
+
+// Trace streams the trace output of the target
+func (s *DebugService) Trace(req *dbg.TraceRequest, stream dbg.DebugService_TraceServer) error {
+	var sendLock sync.Mutex
+	send := func(resp *dbg.TraceResponse) {
+		sendLock.Lock()
+		defer sendLock.Unlock()
+		stream.Send(resp)
+	}
+	return cmd.Trace(stream.Context(), req,
+		func(channel uint32, data []byte) {
+			send(&dbg.TraceResponse{Channel: channel, Data: data})
+		},
+		utils.FeedStreamTo(func(data []byte) {
+			send(&dbg.TraceResponse{Console: data})
+		}))
+}
@@ -76,6 +76,21 @@ func (s *MonitorService) StreamingOpen(stream rpc.MonitorService_StreamingOpenSe
 		}
 	}
 
+	// translate the line terminators of the data sent, if requested
+	if cfg, ok := config.GetAdditionalConfig().AsMap()["LineEnding"]; ok {
+		name, ok := cfg.(string)
+		if !ok {
+			mon.Close()
+			return errors.New("LineEnding must be a string")
+		}
+		lineEnding, err := monitors.ParseLineEnding(name)
+		if err != nil {
+			mon.Close()
+			return err
+		}
+		mon = monitors.NewLineEndingMonitor(mon, lineEnding)
+	}
+
 	// we'll use these channels to communicate with the goroutines
 	// handling the stream and the target respectively
 	streamClosed := make(chan error)
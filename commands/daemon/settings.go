@@ -22,8 +22,11 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/arduino/arduino-cli/commands"
 	"github.com/arduino/arduino-cli/configuration"
+	commandsrpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/settings/v1"
+	"github.com/spf13/viper"
 )
 
 // SettingsService implements the `Settings` service
@@ -31,10 +34,27 @@ type SettingsService struct {
 	rpc.UnimplementedSettingsServiceServer
 }
 
+// settingsOf returns the settings of the given instance, or the settings of
+// the server if no instance is given.
+func settingsOf(instance *commandsrpc.Instance) (*viper.Viper, error) {
+	if instance == nil {
+		return configuration.Settings, nil
+	}
+	settings := commands.GetSettings(instance.GetId())
+	if settings == nil {
+		return nil, errors.New("invalid instance")
+	}
+	return settings, nil
+}
+
 // GetAll returns a message with a string field containing all the settings
 // currently in use, marshalled in JSON format.
 func (s *SettingsService) GetAll(ctx context.Context, req *rpc.GetAllRequest) (*rpc.GetAllResponse, error) {
-	b, err := json.Marshal(configuration.Settings.AllSettings())
+	settings, err := settingsOf(req.GetInstance())
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(settings.AllSettings())
 	if err == nil {
 		return &rpc.GetAllResponse{
 			JsonData: string(b),
@@ -71,6 +91,10 @@ func mapper(toMap map[string]interface{}) map[string]interface{} {
 
 // Merge applies multiple settings values at once.
 func (s *SettingsService) Merge(ctx context.Context, req *rpc.MergeRequest) (*rpc.MergeResponse, error) {
+	settings, err := settingsOf(req.GetInstance())
+	if err != nil {
+		return nil, err
+	}
 	var toMerge map[string]interface{}
 	if err := json.Unmarshal([]byte(req.GetJsonData()), &toMerge); err != nil {
 		return nil, err
@@ -82,7 +106,7 @@ func (s *SettingsService) Merge(ctx context.Context, req *rpc.MergeRequest) (*rp
 	// This is done because Viper ignores empty strings or maps when
 	// using the MergeConfigMap function.
 	for k, v := range mapped {
-		configuration.Settings.Set(k, v)
+		settings.Set(k, v)
 	}
 
 	return &rpc.MergeResponse{}, nil
@@ -93,12 +117,16 @@ func (s *SettingsService) Merge(ctx context.Context, req *rpc.MergeRequest) (*rp
 // ones.
 func (s *SettingsService) GetValue(ctx context.Context, req *rpc.GetValueRequest) (*rpc.GetValueResponse, error) {
 	key := req.GetKey()
+	settings, err := settingsOf(req.GetInstance())
+	if err != nil {
+		return nil, err
+	}
 
 	// Check if settings key actually existing, we don't use Viper.InConfig()
 	// since that doesn't check for keys formatted like daemon.port or those set
 	// with Viper.Set(). This way we check for all existing settings for sure.
 	keyExists := false
-	for _, k := range configuration.Settings.AllKeys() {
+	for _, k := range settings.AllKeys() {
 		if k == key || strings.HasPrefix(k, key) {
 			keyExists = true
 			break
@@ -108,7 +136,7 @@ func (s *SettingsService) GetValue(ctx context.Context, req *rpc.GetValueRequest
 		return nil, errors.New("key not found in settings")
 	}
 
-	b, err := json.Marshal(configuration.Settings.Get(key))
+	b, err := json.Marshal(settings.Get(key))
 	value := &rpc.GetValueResponse{}
 	if err == nil {
 		value.Key = key
@@ -121,11 +149,15 @@ func (s *SettingsService) GetValue(ctx context.Context, req *rpc.GetValueRequest
 // SetValue updates or set a value for a certain key.
 func (s *SettingsService) SetValue(ctx context.Context, val *rpc.SetValueRequest) (*rpc.SetValueResponse, error) {
 	key := val.GetKey()
+	settings, err := settingsOf(val.GetInstance())
+	if err != nil {
+		return nil, err
+	}
 	var value interface{}
 
-	err := json.Unmarshal([]byte(val.GetJsonData()), &value)
+	err = json.Unmarshal([]byte(val.GetJsonData()), &value)
 	if err == nil {
-		configuration.Settings.Set(key, value)
+		settings.Set(key, value)
 	}
 
 	return &rpc.SetValueResponse{}, err
@@ -136,7 +168,11 @@ func (s *SettingsService) SetValue(ctx context.Context, val *rpc.SetValueRequest
 // and that's picked up when the CLI is run as daemon, either using the default path or a custom one
 // set with the --config-file flag.
 func (s *SettingsService) Write(ctx context.Context, req *rpc.WriteRequest) (*rpc.WriteResponse, error) {
-	if err := configuration.Settings.WriteConfigAs(req.FilePath); err != nil {
+	settings, err := settingsOf(req.GetInstance())
+	if err != nil {
+		return nil, err
+	}
+	if err := settings.WriteConfigAs(req.FilePath); err != nil {
 		return nil, err
 	}
 	return &rpc.WriteResponse{}, nil
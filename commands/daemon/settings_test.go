@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/arduino/arduino-cli/configuration"
+	commandsrpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/settings/v1"
 	"github.com/arduino/go-paths-helper"
 	"github.com/stretchr/testify/require"
@@ -157,3 +158,13 @@ func TestWrite(t *testing.T) {
 	// We don't verify the content since we expect config library, Viper, to work
 	require.True(t, configFile.Exist())
 }
+
+func TestInvalidInstance(t *testing.T) {
+	reset()
+	instance := &commandsrpc.Instance{Id: 42}
+	_, err := svc.GetAll(context.Background(), &rpc.GetAllRequest{Instance: instance})
+	require.EqualError(t, err, "invalid instance")
+	_, err = svc.SetValue(context.Background(), &rpc.SetValueRequest{Instance: instance, Key: "foo", JsonData: `"bar"`})
+	require.EqualError(t, err, "invalid instance")
+	require.Equal(t, "", configuration.Settings.GetString("foo"))
+}
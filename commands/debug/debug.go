@@ -37,19 +37,28 @@ import (
 // It also implements streams routing:
 // gRPC In -> tool stdIn
 // grpc Out <- tool stdOut
-// grpc Out <- tool stdErr
+// grpc Console <- tool stdErr and gdb server output
 // It also implements tool process lifecycle management
-func Debug(ctx context.Context, req *dbg.DebugConfigRequest, inStream io.Reader, out io.Writer, interrupt <-chan os.Signal) (*dbg.DebugResponse, error) {
+func Debug(ctx context.Context, req *dbg.DebugConfigRequest, inStream io.Reader, out, console io.Writer, interrupt <-chan os.Signal) (*dbg.DebugResponse, error) {
 
 	// Get debugging command line to run debugger
 	pm := commands.GetPackageManager(req.GetInstance().GetId())
-	commandLine, err := getCommandLine(req, pm)
+	commandLine, serverCommandLine, serverAddress, err := getCommandLines(req, pm)
 	if err != nil {
 		return nil, errors.Wrap(err, "Cannot get command line for tool")
 	}
 
+	// Start the GDB server, if it doesn't run as a pipe of GDB
+	if serverCommandLine != nil {
+		server, err := startServer(serverCommandLine, serverAddress, console)
+		if err != nil {
+			return nil, err
+		}
+		defer server.Kill()
+	}
+
 	for i, arg := range commandLine {
-		fmt.Printf("%2d: %s\n", i, arg)
+		fmt.Fprintf(console, "%2d: %s\n", i, arg)
 	}
 
 	// Run Tool
@@ -71,9 +80,11 @@ func Debug(ctx context.Context, req *dbg.DebugConfigRequest, inStream io.Reader,
 	}
 	defer in.Close()
 
-	// Merge tool StdOut and StdErr to stream them in the io.Writer passed stream
+	// Keep the output of the tool apart from its error output, that contains
+	// the output of the gdb server when it runs as a pipe, so the GDB/MI
+	// stream is not corrupted
 	cmd.RedirectStdoutTo(out)
-	cmd.RedirectStderrTo(out)
+	cmd.RedirectStderrTo(console)
 
 	// Start the debug command
 	if err := cmd.Start(); err != nil {
@@ -110,9 +121,17 @@ func Debug(ctx context.Context, req *dbg.DebugConfigRequest, inStream io.Reader,
 
 // getCommandLine compose a debug command represented by a core recipe
 func getCommandLine(req *dbg.DebugConfigRequest, pm *packagemanager.PackageManager) ([]string, error) {
+	commandLine, _, _, err := getCommandLines(req, pm)
+	return commandLine, err
+}
+
+// getCommandLines returns the command line of GDB and, for the GDB servers
+// that don't run as a pipe of GDB, the command line of the server and the
+// address where it accepts the GDB connection
+func getCommandLines(req *dbg.DebugConfigRequest, pm *packagemanager.PackageManager) ([]string, []string, string, error) {
 	debugInfo, err := getDebugProperties(req, pm)
 	if err != nil {
-		return nil, err
+		return nil, nil, "", err
 	}
 
 	cmdArgs := []string{}
@@ -128,7 +147,7 @@ func getCommandLine(req *dbg.DebugConfigRequest, pm *packagemanager.PackageManag
 		}
 		gdbPath = paths.New(debugInfo.ToolchainPath).Join(gdbexecutable)
 	default:
-		return nil, errors.Errorf("unsupported toolchain '%s'", debugInfo.GetToolchain())
+		return nil, nil, "", errors.Errorf("unsupported toolchain '%s'", debugInfo.GetToolchain())
 	}
 	add(gdbPath.String())
 
@@ -148,6 +167,8 @@ func getCommandLine(req *dbg.DebugConfigRequest, pm *packagemanager.PackageManag
 	add("set remotetimeout 5")
 
 	// Extract path to GDB Server
+	var serverCmdArgs []string
+	serverAddress := ""
 	switch debugInfo.GetServer() {
 	case "openocd":
 		serverCmd := fmt.Sprintf(`target extended-remote | "%s"`, debugInfo.ServerPath)
@@ -166,8 +187,16 @@ func getCommandLine(req *dbg.DebugConfigRequest, pm *packagemanager.PackageManag
 		add("-ex")
 		add(serverCmd)
 
+	case "pyocd", "st-util":
+		serverCmdArgs, serverAddress, err = tcpServerCommandLine(debugInfo)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		add("-ex")
+		add("target extended-remote " + serverAddress)
+
 	default:
-		return nil, errors.Errorf("unsupported gdb server '%s'", debugInfo.GetServer())
+		return nil, nil, "", errors.Errorf("unsupported gdb server '%s'", debugInfo.GetServer())
 	}
 
 	// Add executable
@@ -179,5 +208,5 @@ func getCommandLine(req *dbg.DebugConfigRequest, pm *packagemanager.PackageManag
 		cmdArgs[i] = filepath.ToSlash(param)
 	}
 
-	return cmdArgs, nil
+	return cmdArgs, serverCmdArgs, serverAddress, nil
 }
@@ -155,6 +155,21 @@ func getDebugProperties(req *debug.DebugConfigRequest, pm *packagemanager.Packag
 		return nil, status.Error(codes.Unimplemented, fmt.Sprintf("debugging not supported for board %s", req.GetFqbn()))
 	}
 
+	// The SVD file requested by the user overrides the one of the platform
+	svdFile := ""
+	if source := req.GetSvd(); source != "" {
+		if svdFile, err = resolveSvdFile(source, pm, commands.GetSettings(req.GetInstance().GetId())); err != nil {
+			return nil, err
+		}
+	} else if source := debugProperties.Get("svd_file"); source != "" {
+		svdFile = source
+	} else if source := debugProperties.Get("svd_url"); source != "" {
+		// The debug session may start even if the SVD file can't be downloaded
+		if svdFile, err = resolveSvdFile(source, pm, commands.GetSettings(req.GetInstance().GetId())); err != nil {
+			logrus.WithError(err).Warn("Cannot get the SVD file of the board")
+		}
+	}
+
 	server := debugProperties.Get("server")
 	toolchain := debugProperties.Get("toolchain")
 	return &debug.GetDebugConfigResponse{
@@ -166,5 +181,6 @@ func getDebugProperties(req *debug.DebugConfigRequest, pm *packagemanager.Packag
 		ToolchainPath:          debugProperties.Get("toolchain.path"),
 		ToolchainPrefix:        debugProperties.Get("toolchain.prefix"),
 		ToolchainConfiguration: debugProperties.SubTree("toolchain." + toolchain).AsMap(),
+		SvdFile:                svdFile,
 	}, nil
 }
@@ -88,3 +88,26 @@ func TestGetCommandLine(t *testing.T) {
 	commandToTest2 := strings.Join(command2[:], " ")
 	assert.Equal(t, filepath.FromSlash(goldCommand2), filepath.FromSlash(commandToTest2))
 }
+
+func TestGetCommandLinesTCPServer(t *testing.T) {
+	customHardware := paths.New("testdata", "custom_hardware")
+	dataDir := paths.New("testdata", "data_dir", "packages")
+	sketchPath := paths.New("testdata", "hello")
+	require.NoError(t, sketchPath.ToAbs())
+
+	pm := packagemanager.NewPackageManager(nil, nil, nil, nil)
+	pm.LoadHardwareFromDirectory(customHardware)
+	pm.LoadHardwareFromDirectory(dataDir)
+
+	req := &dbg.DebugConfigRequest{
+		Instance:   &rpc.Instance{Id: 1},
+		Fqbn:       "arduino-test:samd:mkr1000_pyocd",
+		SketchPath: sketchPath.String(),
+		ImportDir:  sketchPath.Join("build", "arduino-test.samd.mkr1000").String(),
+	}
+	command, serverCommand, serverAddress, err := getCommandLines(req, pm)
+	require.NoError(t, err)
+	require.Equal(t, "localhost:3333", serverAddress)
+	require.Equal(t, "gdbserver --persist --port 3333 --target atsamd21g18a --frequency 4000000", strings.Join(serverCommand[1:], " "))
+	require.Contains(t, strings.Join(command, " "), "-ex target extended-remote localhost:3333 ")
+}
@@ -0,0 +1,114 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package debug
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/arduino/arduino-cli/executils"
+	dbg "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/debug/v1"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultServerPorts are the ports where the GDB servers accept the GDB
+// connection if the platform doesn't define debug.server.<server>.port
+var defaultServerPorts = map[string]string{
+	"pyocd":   "3333",
+	"st-util": "4242",
+}
+
+// serverStartTimeout is how long to wait for a GDB server to accept
+// connections
+var serverStartTimeout = 10 * time.Second
+
+// tcpServerCommandLine returns the command line of a GDB server that accepts
+// the GDB connection on a TCP port, and the address of the server
+func tcpServerCommandLine(debugInfo *dbg.GetDebugConfigResponse) ([]string, string, error) {
+	server := debugInfo.GetServer()
+	config := debugInfo.GetServerConfiguration()
+	if debugInfo.GetServerPath() == "" {
+		return nil, "", errors.Errorf("the path of the gdb server '%s' is not defined", server)
+	}
+	port := config["port"]
+	if port == "" {
+		port = defaultServerPorts[server]
+	}
+
+	args := []string{debugInfo.GetServerPath()}
+	switch server {
+	case "pyocd":
+		args = append(args, "gdbserver", "--persist", "--port", port)
+		if target := config["target"]; target != "" {
+			args = append(args, "--target", target)
+		}
+		if script := config["script"]; script != "" {
+			args = append(args, "--script", script)
+		}
+	case "st-util":
+		args = append(args, "--multi", "--listen_port="+port)
+	}
+	if extra := config["args"]; extra != "" {
+		extraArgs, err := properties.SplitQuotedString(extra, `"'`, false)
+		if err != nil {
+			return nil, "", errors.Wrapf(err, "invalid arguments for gdb server '%s'", server)
+		}
+		args = append(args, extraArgs...)
+	}
+	return args, "localhost:" + port, nil
+}
+
+// startServer runs the GDB server and waits until it accepts connections on
+// address. The output of the server is written to out.
+func startServer(commandLine []string, address string, out io.Writer) (*executils.Process, error) {
+	for i, arg := range commandLine {
+		fmt.Fprintf(out, "%2d: %s\n", i, arg)
+	}
+	server, err := executils.NewProcess(commandLine...)
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot execute gdb server")
+	}
+	server.RedirectStdoutTo(out)
+	server.RedirectStderrTo(out)
+	if err := server.Start(); err != nil {
+		return nil, errors.Wrap(err, "Cannot start gdb server")
+	}
+
+	exited := make(chan error, 1)
+	go func() { exited <- server.Wait() }()
+	deadline := time.Now().Add(serverStartTimeout)
+	for {
+		if conn, err := net.DialTimeout("tcp", address, time.Second); err == nil {
+			conn.Close()
+			logrus.Infof("GDB server listening on %s", address)
+			return server, nil
+		}
+		select {
+		case err := <-exited:
+			return nil, errors.Errorf("gdb server exited before accepting connections: %v", err)
+		default:
+		}
+		if time.Now().After(deadline) {
+			server.Kill()
+			return nil, errors.Errorf("gdb server not listening on %s after %s", address, serverStartTimeout)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
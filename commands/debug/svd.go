@@ -0,0 +1,250 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package debug
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino/cores/packagemanager"
+	"github.com/arduino/arduino-cli/arduino/svd"
+	"github.com/arduino/arduino-cli/commands"
+	"github.com/arduino/arduino-cli/executils"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	dbg "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/debug/v1"
+	"github.com/arduino/go-paths-helper"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"go.bug.st/downloader/v2"
+	"google.golang.org/protobuf/proto"
+)
+
+// ReadRegisters reads the peripheral registers of the target, using the
+// CMSIS-SVD file of the board to find their address and decode their
+// fields. The output of GDB and of the GDB server is written to console.
+func ReadRegisters(ctx context.Context, req *dbg.ReadRegistersRequest, console io.Writer) (*dbg.ReadRegistersResponse, error) {
+	pm := commands.GetPackageManager(req.GetDebugRequest().GetInstance().GetId())
+	return readRegisters(req, pm, console)
+}
+
+func readRegisters(req *dbg.ReadRegistersRequest, pm *packagemanager.PackageManager, console io.Writer) (*dbg.ReadRegistersResponse, error) {
+	debugInfo, err := getDebugProperties(req.GetDebugRequest(), pm)
+	if err != nil {
+		return nil, err
+	}
+	if debugInfo.GetSvdFile() == "" {
+		return nil, fmt.Errorf("no SVD file available for the board, please specify one")
+	}
+	device, err := svd.Load(paths.New(debugInfo.GetSvdFile()))
+	if err != nil {
+		return nil, err
+	}
+	peripherals, err := device.FindRegisters(req.GetRegisters()...)
+	if err != nil {
+		return nil, err
+	}
+	if len(peripherals) == 0 {
+		return nil, fmt.Errorf("no registers matching %s", strings.Join(req.GetRegisters(), ", "))
+	}
+
+	res := &dbg.ReadRegistersResponse{SvdFile: debugInfo.GetSvdFile()}
+	toRead := map[*dbg.PeripheralRegister]*svd.Register{}
+	for _, p := range peripherals {
+		for _, r := range p.Registers {
+			reg := &dbg.PeripheralRegister{
+				Peripheral:  p.Name,
+				Name:        r.Name,
+				Description: r.Description,
+				Address:     r.Address,
+				Size:        uint32(r.Size),
+				Fields:      fieldsToRPC(r.Decode(0), false),
+			}
+			res.Registers = append(res.Registers, reg)
+			if req.GetListOnly() {
+				continue
+			}
+			if !r.Readable() {
+				// reading these registers may change the state of the target
+				reg.Error = "register not readable without side effects"
+				continue
+			}
+			toRead[reg] = r
+		}
+	}
+	if len(toRead) == 0 {
+		return res, nil
+	}
+
+	values, err := readMemory(req.GetDebugRequest(), pm, res.Registers, console)
+	if err != nil {
+		return nil, err
+	}
+	for _, reg := range res.Registers {
+		r, ok := toRead[reg]
+		if !ok {
+			continue
+		}
+		value, ok := values[reg.Address]
+		if !ok {
+			reg.Error = "cannot access memory"
+			continue
+		}
+		reg.Read = true
+		reg.Value = value
+		reg.Fields = fieldsToRPC(r.Decode(value), true)
+	}
+	return res, nil
+}
+
+func fieldsToRPC(values []*svd.FieldValue, read bool) []*dbg.RegisterField {
+	res := []*dbg.RegisterField{}
+	for _, v := range values {
+		field := &dbg.RegisterField{
+			Name:        v.Name,
+			Description: v.Description,
+			BitOffset:   uint32(v.BitOffset),
+			BitWidth:    uint32(v.BitWidth),
+		}
+		if read {
+			field.Value = v.Value
+			field.EnumeratedValue = v.Enum
+		}
+		res = append(res, field)
+	}
+	return res
+}
+
+// readMemory reads the registers running GDB in batch mode and returns the
+// values read, indexed by address
+func readMemory(req *dbg.DebugConfigRequest, pm *packagemanager.PackageManager, registers []*dbg.PeripheralRegister, console io.Writer) (map[uint64]uint64, error) {
+	consoleReq := proto.Clone(req).(*dbg.DebugConfigRequest)
+	consoleReq.Interpreter = "console"
+	commandLine, serverCommandLine, serverAddress, err := getCommandLines(consoleReq, pm)
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot get command line for tool")
+	}
+	commandLine = append([]string{commandLine[0], "--batch"}, commandLine[1:]...)
+	for _, reg := range registers {
+		if reg.GetError() == "" {
+			commandLine = append(commandLine, "-ex", examineCommand(reg))
+		}
+	}
+
+	if serverCommandLine != nil {
+		server, err := startServer(serverCommandLine, serverAddress, console)
+		if err != nil {
+			return nil, err
+		}
+		defer server.Kill()
+	}
+
+	logrus.WithField("commandLine", commandLine).Debug("Reading registers")
+	cmd, err := executils.NewProcess(commandLine...)
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot execute debug tool")
+	}
+	out := &bytes.Buffer{}
+	cmd.RedirectStdoutTo(out)
+	cmd.RedirectStderrTo(console)
+	if err := cmd.Run(); err != nil {
+		return nil, errors.Wrap(err, "reading registers")
+	}
+	return parseExamineOutput(out.String()), nil
+}
+
+// examineCommand returns the GDB command that reads the given register
+func examineCommand(reg *dbg.PeripheralRegister) string {
+	unit := "w"
+	switch reg.GetSize() {
+	case 8:
+		unit = "b"
+	case 16:
+		unit = "h"
+	case 64:
+		unit = "g"
+	}
+	return fmt.Sprintf("x/1%sx 0x%x", unit, reg.GetAddress())
+}
+
+// examineOutputRe matches the output of the GDB "x" command, for example
+// "0x42002c00:	0x0012" or "0x20000000 <buffer>:	0x00000000"
+var examineOutputRe = regexp.MustCompile(`^(0x[0-9a-fA-F]+)(?:\s+<[^>]*>)?:\s+(0x[0-9a-fA-F]+)\s*$`)
+
+func parseExamineOutput(out string) map[uint64]uint64 {
+	res := map[uint64]uint64{}
+	for _, line := range strings.Split(out, "\n") {
+		m := examineOutputRe.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		address, err1 := strconv.ParseUint(m[1][2:], 16, 64)
+		value, err2 := strconv.ParseUint(m[2][2:], 16, 64)
+		if err1 == nil && err2 == nil {
+			res[address] = value
+		}
+	}
+	return res
+}
+
+// resolveSvdFile returns the path of the SVD file specified by source, that
+// is either a path or an URL. SVD files specified by URL are downloaded once
+// in the downloads directory, using the network configuration in settings.
+func resolveSvdFile(source string, pm *packagemanager.PackageManager, settings *viper.Viper) (string, error) {
+	u, err := url.Parse(source)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		file := paths.New(source)
+		if !file.Exist() {
+			return "", fmt.Errorf("SVD file not found: %s", source)
+		}
+		return file.String(), nil
+	}
+
+	hash := sha256.Sum256([]byte(source))
+	file := pm.DownloadDir.Join("svd", hex.EncodeToString(hash[:8])+"-"+path.Base(u.Path))
+	if file.Exist() {
+		return file.String(), nil
+	}
+	if err := file.Parent().MkdirAll(); err != nil {
+		return "", errors.Wrap(err, "creating SVD download directory")
+	}
+	tmp := paths.New(file.String() + ".tmp")
+	defer tmp.Remove()
+	config, err := commands.GetDownloaderConfig(settings)
+	if err != nil {
+		return "", fmt.Errorf("downloading SVD file %s: %s", source, err)
+	}
+	d, err := downloader.DownloadWithConfig(tmp.String(), source, *config, downloader.NoResume)
+	if err != nil {
+		return "", fmt.Errorf("downloading SVD file %s: %s", source, err)
+	}
+	if err := commands.Download(d, "SVD file "+file.Base(), func(*rpc.DownloadProgress) {}); err != nil {
+		return "", fmt.Errorf("downloading SVD file %s: %s", source, err)
+	}
+	if err := tmp.Rename(file); err != nil {
+		return "", fmt.Errorf("saving SVD file %s: %s", source, err)
+	}
+	return file.String(), nil
+}
@@ -0,0 +1,83 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package debug
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/cores/packagemanager"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	dbg "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/debug/v1"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListRegisters(t *testing.T) {
+	customHardware := paths.New("testdata", "custom_hardware")
+	dataDir := paths.New("testdata", "data_dir", "packages")
+	sketchPath := paths.New("testdata", "hello")
+	require.NoError(t, sketchPath.ToAbs())
+
+	pm := packagemanager.NewPackageManager(nil, nil, nil, nil)
+	pm.LoadHardwareFromDirectory(customHardware)
+	pm.LoadHardwareFromDirectory(dataDir)
+
+	req := &dbg.ReadRegistersRequest{
+		DebugRequest: &dbg.DebugConfigRequest{
+			Instance:   &rpc.Instance{Id: 1},
+			Fqbn:       "arduino-test:samd:mkr1000_pyocd",
+			SketchPath: sketchPath.String(),
+			ImportDir:  sketchPath.Join("build", "arduino-test.samd.mkr1000").String(),
+		},
+		Registers: []string{"PORT.CTRL", "PORT.INTFLAG"},
+		ListOnly:  true,
+	}
+	res, err := readRegisters(req, pm, ioutil.Discard)
+	require.NoError(t, err)
+	require.Equal(t, customHardware.Join("arduino-test", "samd", "svd", "ATSAMD21G18A.svd").String(), res.GetSvdFile())
+	require.Len(t, res.GetRegisters(), 2)
+	ctrl := res.GetRegisters()[0]
+	require.Equal(t, "PORT", ctrl.GetPeripheral())
+	require.Equal(t, "CTRL", ctrl.GetName())
+	require.Equal(t, uint64(0x41004424), ctrl.GetAddress())
+	require.False(t, ctrl.GetRead())
+	require.Len(t, ctrl.GetFields(), 2)
+	require.Equal(t, "x/1bx 0x41004424", examineCommand(ctrl))
+
+	// The SVD file of the platform can be overridden
+	req.DebugRequest.Svd = paths.New("testdata", "missing.svd").String()
+	_, err = readRegisters(req, pm, ioutil.Discard)
+	require.EqualError(t, err, "SVD file not found: "+req.DebugRequest.Svd)
+
+	// Boards without an SVD file can't be inspected
+	req.DebugRequest.Svd = ""
+	req.DebugRequest.Fqbn = "arduino-test:samd:mkr1000"
+	_, err = readRegisters(req, pm, ioutil.Discard)
+	require.Error(t, err)
+}
+
+func TestParseExamineOutput(t *testing.T) {
+	out := "0x41004424:\t0x0e\n" +
+		"0x42002c00 <TC3>:\t0x0012\n" +
+		"Cannot access memory at address 0x60000000\n" +
+		"0x41004400:\t0x00000100\n"
+	require.Equal(t, map[uint64]uint64{
+		0x41004424: 0x0e,
+		0x42002c00: 0x12,
+		0x41004400: 0x100,
+	}, parseExamineOutput(out))
+}
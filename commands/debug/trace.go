@@ -0,0 +1,242 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package debug
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/arduino/arduino-cli/arduino/cores/packagemanager"
+	"github.com/arduino/arduino-cli/arduino/trace"
+	"github.com/arduino/arduino-cli/commands"
+	dbg "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/debug/v1"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultTracePort is the first TCP port where OpenOCD sends the trace
+// stream, if the platform doesn't define debug.server.openocd.trace_port
+const defaultTracePort = 19021
+
+const (
+	defaultRTTAddress = 0x20000000
+	defaultRTTSize    = 0x8000
+)
+
+// Trace captures the trace output of the target, using OpenOCD to configure
+// the probe. The decoded data of each channel is passed to out, the output
+// of OpenOCD is written to console. Trace returns when ctx is canceled or
+// when OpenOCD closes the trace stream.
+func Trace(ctx context.Context, req *dbg.TraceRequest, out func(channel uint32, data []byte), console io.Writer) error {
+	pm := commands.GetPackageManager(req.GetDebugRequest().GetInstance().GetId())
+	return runTrace(ctx, req, pm, out, console)
+}
+
+func runTrace(ctx context.Context, req *dbg.TraceRequest, pm *packagemanager.PackageManager, out func(channel uint32, data []byte), console io.Writer) error {
+	debugInfo, err := getDebugProperties(req.GetDebugRequest(), pm)
+	if err != nil {
+		return err
+	}
+	commandLine, addresses, err := traceCommandLine(req, debugInfo)
+	if err != nil {
+		return err
+	}
+	server, err := startServer(commandLine, addresses[0], console)
+	if err != nil {
+		return err
+	}
+	defer server.Kill()
+
+	conns := []net.Conn{}
+	defer func() {
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}()
+	for _, address := range addresses {
+		conn, err := net.Dial("tcp", address)
+		if err != nil {
+			return errors.Wrap(err, "connecting to the trace stream")
+		}
+		conns = append(conns, conn)
+	}
+
+	channels := traceChannels(req)
+	var outLock sync.Mutex
+	send := func(channel uint32, data []byte) {
+		outLock.Lock()
+		defer outLock.Unlock()
+		out(channel, data)
+	}
+
+	var wg sync.WaitGroup
+	if req.GetBackend() == "swo" {
+		// all the stimulus ports are multiplexed in the ITM stream
+		enabled := map[uint32]bool{}
+		for _, channel := range channels {
+			enabled[channel] = true
+		}
+		decoder := trace.NewITMDecoder(func(port uint32, data []byte) {
+			if enabled[port] {
+				send(port, data)
+			}
+		})
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			io.Copy(decoder, conns[0])
+			if decoder.Overflows > 0 {
+				logrus.Warnf("ITM overflow: %d trace packets lost", decoder.Overflows)
+			}
+		}()
+	} else {
+		for i, conn := range conns {
+			channel := channels[i]
+			conn := conn
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				buff := make([]byte, 1024)
+				for {
+					n, err := conn.Read(buff)
+					if n > 0 {
+						send(channel, buff[:n])
+					}
+					if err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-ctx.Done():
+		// unblock the readers, so out is not called after returning
+		for _, conn := range conns {
+			conn.Close()
+		}
+		<-done
+	case <-done:
+	}
+	return nil
+}
+
+func traceChannels(req *dbg.TraceRequest) []uint32 {
+	if len(req.GetChannels()) == 0 {
+		return []uint32{0}
+	}
+	return req.GetChannels()
+}
+
+// traceCommandLine returns the command line of OpenOCD to capture the trace
+// and the addresses of the trace streams: a single ITM stream for SWO or a
+// stream for each RTT channel
+func traceCommandLine(req *dbg.TraceRequest, debugInfo *dbg.GetDebugConfigResponse) ([]string, []string, error) {
+	if debugInfo.GetServer() != "openocd" {
+		return nil, nil, errors.Errorf("trace is not supported with the gdb server '%s'", debugInfo.GetServer())
+	}
+	config := debugInfo.GetServerConfiguration()
+	port := defaultTracePort
+	if p := config["trace_port"]; p != "" {
+		var err error
+		if port, err = strconv.Atoi(p); err != nil {
+			return nil, nil, errors.Errorf("invalid trace port '%s'", p)
+		}
+	}
+
+	args := []string{debugInfo.GetServerPath()}
+	add := func(s ...string) { args = append(args, s...) }
+	if scriptsDir := config["scripts_dir"]; scriptsDir != "" {
+		add("-s", scriptsDir)
+	}
+	if script := config["script"]; script != "" {
+		add("--file", script)
+	}
+	add("-c", "gdb_port disabled", "-c", "telnet_port disabled", "-c", "tcl_port disabled", "-c", "init")
+
+	channels := traceChannels(req)
+	addresses := []string{}
+	switch req.GetBackend() {
+	case "swo":
+		traceClock := uint64(req.GetTraceClock())
+		if traceClock == 0 {
+			if c := config["trace_clock"]; c != "" {
+				// the value is usually taken from build.f_cpu (e.g. "48000000L")
+				var err error
+				if traceClock, err = strconv.ParseUint(strings.TrimSuffix(c, "L"), 10, 32); err != nil {
+					return nil, nil, errors.Errorf("invalid trace clock '%s'", c)
+				}
+			}
+		}
+		if traceClock == 0 {
+			return nil, nil, errors.New("the trace clock frequency is required for SWO")
+		}
+		tpiu := fmt.Sprintf("tpiu config internal :%d uart off %d", port, traceClock)
+		if freq := req.GetSwoFrequency(); freq != 0 {
+			tpiu += fmt.Sprintf(" %d", freq)
+		}
+		add("-c", tpiu, "-c", "itm ports off")
+		for _, channel := range channels {
+			if channel > 31 {
+				return nil, nil, errors.Errorf("invalid ITM stimulus port %d", channel)
+			}
+			add("-c", fmt.Sprintf("itm port %d on", channel))
+		}
+		addresses = append(addresses, fmt.Sprintf("localhost:%d", port))
+
+	case "rtt":
+		address, err := traceConfigNumber(req.GetRttAddress(), config["rtt_address"], defaultRTTAddress)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "invalid RTT address")
+		}
+		size, err := traceConfigNumber(req.GetRttSize(), config["rtt_size"], defaultRTTSize)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "invalid RTT size")
+		}
+		add("-c", fmt.Sprintf(`rtt setup 0x%x 0x%x "SEGGER RTT"`, address, size), "-c", "rtt start")
+		for i, channel := range channels {
+			add("-c", fmt.Sprintf("rtt server start %d %d", port+i, channel))
+			addresses = append(addresses, fmt.Sprintf("localhost:%d", port+i))
+		}
+
+	default:
+		return nil, nil, errors.Errorf("invalid trace backend '%s'", req.GetBackend())
+	}
+	return args, addresses, nil
+}
+
+// traceConfigNumber returns value if not zero, otherwise the number in the
+// platform configuration or the default value
+func traceConfigNumber(value uint64, config string, def uint64) (uint64, error) {
+	if value != 0 {
+		return value, nil
+	}
+	if config == "" {
+		return def, nil
+	}
+	return strconv.ParseUint(config, 0, 64)
+}
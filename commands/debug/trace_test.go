@@ -0,0 +1,74 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package debug
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/cores/packagemanager"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	dbg "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/debug/v1"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceCommandLine(t *testing.T) {
+	customHardware := paths.New("testdata", "custom_hardware")
+	dataDir := paths.New("testdata", "data_dir", "packages")
+	sketchPath := paths.New("testdata", "hello")
+	require.NoError(t, sketchPath.ToAbs())
+
+	pm := packagemanager.NewPackageManager(nil, nil, nil, nil)
+	pm.LoadHardwareFromDirectory(customHardware)
+	pm.LoadHardwareFromDirectory(dataDir)
+
+	debugReq := &dbg.DebugConfigRequest{
+		Instance:   &rpc.Instance{Id: 1},
+		Fqbn:       "arduino-test:samd:mkr1000",
+		SketchPath: sketchPath.String(),
+		ImportDir:  sketchPath.Join("build", "arduino-test.samd.mkr1000").String(),
+	}
+	debugInfo, err := getDebugProperties(debugReq, pm)
+	require.NoError(t, err)
+
+	req := &dbg.TraceRequest{DebugRequest: debugReq, Backend: "swo", Channels: []uint32{0, 2}}
+	_, _, err = traceCommandLine(req, debugInfo)
+	require.EqualError(t, err, "the trace clock frequency is required for SWO")
+
+	req.TraceClock = 48000000
+	command, addresses, err := traceCommandLine(req, debugInfo)
+	require.NoError(t, err)
+	require.Equal(t, []string{"localhost:19021"}, addresses)
+	require.Contains(t, strings.Join(command, " "), "-c init -c tpiu config internal :19021 uart off 48000000 -c itm ports off -c itm port 0 on -c itm port 2 on")
+
+	req = &dbg.TraceRequest{DebugRequest: debugReq, Backend: "rtt", Channels: []uint32{0, 1}, RttSize: 0x1000}
+	command, addresses, err = traceCommandLine(req, debugInfo)
+	require.NoError(t, err)
+	require.Equal(t, []string{"localhost:19021", "localhost:19022"}, addresses)
+	require.Contains(t, strings.Join(command, " "), `-c rtt setup 0x20000000 0x1000 "SEGGER RTT" -c rtt start -c rtt server start 19021 0 -c rtt server start 19022 1`)
+
+	req.Backend = "jtag"
+	_, _, err = traceCommandLine(req, debugInfo)
+	require.EqualError(t, err, "invalid trace backend 'jtag'")
+
+	// only OpenOCD is supported
+	debugReq.Fqbn = "arduino-test:samd:mkr1000_pyocd"
+	debugInfo, err = getDebugProperties(debugReq, pm)
+	require.NoError(t, err)
+	_, _, err = traceCommandLine(req, debugInfo)
+	require.EqualError(t, err, "trace is not supported with the gdb server 'pyocd'")
+}
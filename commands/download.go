@@ -17,18 +17,29 @@ package commands
 
 import (
 	"errors"
+	"sync"
 	"time"
 
 	"github.com/arduino/arduino-cli/httpclient"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/spf13/viper"
 	"go.bug.st/downloader/v2"
 )
 
 // GetDownloaderConfig returns the downloader configuration based on
-// current settings.
-func GetDownloaderConfig() (*downloader.Config, error) {
+// the given settings.
+func GetDownloaderConfig(settings *viper.Viper) (*downloader.Config, error) {
+	return getDownloaderConfigForOperation(settings, httpclient.OperationDownload)
+}
+
+// getIndexDownloaderConfig returns the downloader configuration used to
+// update the package and library indexes.
+func getIndexDownloaderConfig(settings *viper.Viper) (*downloader.Config, error) {
+	return getDownloaderConfigForOperation(settings, httpclient.OperationIndexUpdate)
+}
 
-	httpClient, err := httpclient.New()
+func getDownloaderConfigForOperation(settings *viper.Viper, op httpclient.Operation) (*downloader.Config, error) {
+	httpClient, err := httpclient.NewForOperationFromSettings(settings, op)
 	if err != nil {
 		return nil, err
 	}
@@ -68,3 +79,81 @@ func Download(d *downloader.Downloader, label string, downloadCB DownloadProgres
 	downloadCB(&rpc.DownloadProgress{Completed: true})
 	return nil
 }
+
+// DownloadJob is a download performed by DownloadParallel, the progress must be
+// reported to the given DownloadProgressCB
+type DownloadJob func(downloadCB DownloadProgressCB) error
+
+// DownloadParallel runs the download jobs, up to n at the same time. Since the
+// DownloadProgressCB can follow only one download at a time, the live progress
+// is reported for one download while the others are reported once completed.
+// If a job fails no more jobs are started and the first error is returned.
+func DownloadParallel(n int, jobs []DownloadJob, downloadCB DownloadProgressCB) error {
+	if n < 1 {
+		n = 1
+	}
+
+	var mux sync.Mutex
+	var wg sync.WaitGroup
+	var firstErr error
+	live := -1
+	sem := make(chan bool, n)
+	for i, job := range jobs {
+		sem <- true
+		mux.Lock()
+		failed := firstErr != nil
+		mux.Unlock()
+		if failed {
+			<-sem
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, job DownloadJob) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			var start, last *rpc.DownloadProgress
+			err := job(func(p *rpc.DownloadProgress) {
+				mux.Lock()
+				defer mux.Unlock()
+				if live == -1 && start == nil {
+					live = i
+				}
+				if live == i {
+					downloadCB(p)
+					if p.Completed {
+						live = -1
+					}
+					return
+				}
+				if start == nil {
+					start = p
+				} else if !p.Completed {
+					last = p
+				}
+				if p.Completed {
+					// replay the completed download
+					downloadCB(start)
+					if last != nil {
+						downloadCB(last)
+					}
+					if p != start {
+						downloadCB(p)
+					}
+				}
+			})
+
+			mux.Lock()
+			defer mux.Unlock()
+			if live == i {
+				live = -1
+			}
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}(i, job)
+	}
+	wg.Wait()
+	return firstErr
+}
@@ -0,0 +1,245 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package fs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/arduino/cores/packagemanager"
+	"github.com/arduino/arduino-cli/arduino/partitions"
+	"github.com/arduino/arduino-cli/commands"
+	"github.com/arduino/arduino-cli/commands/upload"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	paths "github.com/arduino/go-paths-helper"
+	properties "github.com/arduino/go-properties-orderedmap"
+	"github.com/sirupsen/logrus"
+)
+
+// Types is the list of the supported filesystems
+var Types = []string{"littlefs", "spiffs", "fatfs"}
+
+// Upload recipes used for the platforms that don't define one, by architecture
+var defaultUploadRecipes = map[string]string{
+	"esp8266": `"{runtime.tools.python3.path}/python3" -I "{runtime.platform.path}/tools/upload.py" --chip esp8266 --port "{serial.port}" --baud "{upload.speed}" write_flash {fs.offset} "{fs.image_path}"`,
+	"esp32":   `"{tools.esptool_py.path}/{tools.esptool_py.cmd}" --chip {build.mcu} --port "{serial.port}" --baud {upload.speed} write_flash -z {fs.offset} "{fs.image_path}"`,
+}
+
+// Build recipes used for the platforms that don't define one, by filesystem type
+var defaultBuildRecipes = map[string]string{
+	"littlefs": `"{fs.tool.path}/{fs.tool}" -c "{fs.data_path}" -b {fs.block_size} -p {fs.page_size} -s {fs.size} "{fs.image_path}"`,
+	"spiffs":   `"{fs.tool.path}/{fs.tool}" -c "{fs.data_path}" -b {fs.block_size} -p {fs.page_size} -s {fs.size} "{fs.image_path}"`,
+	"fatfs":    `"{fs.tool.path}/{fs.tool}" -c "{fs.data_path}" -s {fs.size} "{fs.image_path}"`,
+}
+
+// Build packs the data folder of the sketch into a filesystem image
+func Build(ctx context.Context, req *rpc.FilesystemBuildRequest, outStream, errStream io.Writer) (*rpc.FilesystemBuildResponse, error) {
+	logrus.
+		WithField("fqbn", req.GetFqbn()).
+		WithField("sketch", req.GetSketchPath()).
+		Trace("FilesystemBuild started")
+
+	pm := commands.GetPackageManager(req.GetInstance().GetId())
+	if pm == nil {
+		return nil, fmt.Errorf("invalid instance")
+	}
+	props, image, err := filesystemProperties(pm, req.GetFqbn(), req.GetSketchPath(), req.GetType(), req.GetOutputPath())
+	if err != nil {
+		return nil, err
+	}
+	if err := buildImage(props, outStream, errStream, req.GetVerbose()); err != nil {
+		return nil, err
+	}
+	return &rpc.FilesystemBuildResponse{Image: image}, nil
+}
+
+// Upload builds the filesystem image and flashes it at the offset of the
+// filesystem partition
+func Upload(ctx context.Context, req *rpc.FilesystemUploadRequest, outStream, errStream io.Writer) (*rpc.FilesystemUploadResponse, error) {
+	logrus.
+		WithField("fqbn", req.GetFqbn()).
+		WithField("sketch", req.GetSketchPath()).
+		WithField("port", req.GetPort()).
+		Trace("FilesystemUpload started")
+
+	pm := commands.GetPackageManager(req.GetInstance().GetId())
+	if pm == nil {
+		return nil, fmt.Errorf("invalid instance")
+	}
+	props, image, err := filesystemProperties(pm, req.GetFqbn(), req.GetSketchPath(), req.GetType(), req.GetOutputPath())
+	if err != nil {
+		return nil, err
+	}
+	if err := buildImage(props, outStream, errStream, req.GetVerbose()); err != nil {
+		return nil, err
+	}
+
+	port := req.GetPort()
+	if port == "" {
+		return nil, fmt.Errorf("no upload port provided")
+	}
+	props.Set("serial.port", port)
+	if strings.HasPrefix(port, "/dev/") {
+		props.Set("serial.port.file", port[5:])
+	} else {
+		props.Set("serial.port.file", port)
+	}
+	if err := upload.RunTool("recipe.fs.upload.pattern", props, nil, outStream, errStream, req.GetVerbose()); err != nil {
+		return nil, fmt.Errorf("uploading filesystem image: %s", err)
+	}
+	return &rpc.FilesystemUploadResponse{Image: image}, nil
+}
+
+func buildImage(props *properties.Map, outStream, errStream io.Writer, verbose bool) error {
+	image := props.GetPath("fs.image_path")
+	if err := image.Parent().MkdirAll(); err != nil {
+		return fmt.Errorf("creating output folder: %s", err)
+	}
+	if recipe := props.Get("recipe.fs.build.pattern"); props.IsPropertyMissingInExpandPropsInString("fs.tool.path", recipe) {
+		return fmt.Errorf("the %s tool is not installed", props.Get("fs.tool"))
+	}
+	if err := upload.RunTool("recipe.fs.build.pattern", props, nil, outStream, errStream, verbose); err != nil {
+		return fmt.Errorf("building filesystem image: %s", err)
+	}
+	return nil
+}
+
+func filesystemProperties(pm *packagemanager.PackageManager, fqbnIn, sketchPathIn, fsType, output string) (*properties.Map, *rpc.FilesystemImage, error) {
+	if fqbnIn == "" {
+		return nil, nil, fmt.Errorf("no Fully Qualified Board Name provided")
+	}
+	fqbn, err := cores.ParseFQBN(fqbnIn)
+	if err != nil {
+		return nil, nil, fmt.Errorf("incorrect FQBN: %s", err)
+	}
+	_, boardPlatform, board, boardProperties, _, err := pm.ResolveFQBN(fqbn)
+	if err != nil {
+		return nil, nil, fmt.Errorf("incorrect FQBN: %s", err)
+	}
+
+	props := properties.NewMap()
+	props.Merge(boardPlatform.Properties)
+	props.Merge(boardPlatform.RuntimeProperties())
+	props.Merge(boardProperties)
+	for _, tool := range pm.GetAllInstalledToolsReleases() {
+		props.Merge(tool.RuntimeProperties())
+	}
+	if requiredTools, err := pm.FindToolsRequiredForBoard(board); err == nil {
+		for _, requiredTool := range requiredTools {
+			if requiredTool.IsInstalled() {
+				props.Merge(requiredTool.RuntimeProperties())
+			}
+		}
+	}
+
+	if fsType == "" {
+		fsType = props.Get("fs.type")
+	}
+	if fsType == "" {
+		fsType = "littlefs"
+	}
+	if _, ok := defaultBuildRecipes[fsType]; !ok {
+		return nil, nil, fmt.Errorf("unsupported filesystem type: %s", fsType)
+	}
+
+	if sketchPathIn == "" {
+		return nil, nil, fmt.Errorf("missing sketch path")
+	}
+	sketchPath, err := paths.New(sketchPathIn).Abs()
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid sketch path: %s", err)
+	}
+	dataPath := sketchPath.Join("data")
+	if !dataPath.IsDir() {
+		return nil, nil, fmt.Errorf("the sketch has no data folder: %s", dataPath)
+	}
+
+	table, err := loadPartitions(props, sketchPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	layout, err := LayoutFromProperties(props, fsType, table)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	image := paths.New(output)
+	if image == nil {
+		fqbnSuffix := strings.Replace(fqbn.StringWithoutConfig(), ":", ".", -1)
+		image = sketchPath.Join("build", fqbnSuffix, sketchPath.Base()+"."+fsType+".bin")
+	}
+	image, err = image.Abs()
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid output path: %s", err)
+	}
+
+	tool := "mk" + fsType
+	props.Set("fs.type", fsType)
+	props.Set("fs.tool", tool)
+	if runtime.GOOS == "windows" {
+		props.Set("fs.tool", tool+".exe")
+	}
+	if toolPath, ok := props.GetOk("runtime.tools." + tool + ".path"); ok {
+		props.Set("fs.tool.path", toolPath)
+	}
+	props.Set("fs.offset", fmt.Sprintf("0x%X", layout.Offset))
+	props.Set("fs.size", fmt.Sprint(layout.Size))
+	props.Set("fs.block_size", fmt.Sprint(layout.BlockSize))
+	props.Set("fs.page_size", fmt.Sprint(layout.PageSize))
+	props.SetPath("fs.data_path", dataPath)
+	props.SetPath("fs.image_path", image)
+
+	if !props.ContainsKey("recipe.fs.build.pattern") {
+		props.Set("recipe.fs.build.pattern", defaultBuildRecipes[fsType])
+	}
+	if !props.ContainsKey("recipe.fs.upload.pattern") {
+		if recipe, ok := defaultUploadRecipes[boardPlatform.Platform.Architecture]; ok {
+			props.Set("recipe.fs.upload.pattern", recipe)
+		}
+	}
+	logrus.
+		WithField("type", fsType).
+		WithField("offset", layout.Offset).
+		WithField("size", layout.Size).
+		WithField("image", image).
+		Trace("Filesystem image")
+
+	return props, &rpc.FilesystemImage{
+		Type:   fsType,
+		Path:   image.String(),
+		Offset: layout.Offset,
+		Size:   layout.Size,
+	}, nil
+}
+
+// loadPartitions reads the partitions table of the sketch, if present, or
+// the one selected by the build.partitions board property. It returns nil
+// if the board doesn't use a partitions table.
+func loadPartitions(props *properties.Map, sketchPath *paths.Path) ([]*partitions.Partition, error) {
+	table := sketchPath.Join("partitions.csv")
+	if !table.Exist() {
+		name, ok := props.GetOk("build.partitions")
+		if !ok {
+			return nil, nil
+		}
+		table = props.GetPath("runtime.platform.path").Join("tools", "partitions", name+".csv")
+	}
+	return partitions.Load(table)
+}
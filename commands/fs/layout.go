@@ -0,0 +1,126 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package fs
+
+import (
+	"fmt"
+
+	"github.com/arduino/arduino-cli/arduino/partitions"
+	properties "github.com/arduino/go-properties-orderedmap"
+)
+
+// Layout is the position of the filesystem partition in the flash memory
+type Layout struct {
+	Offset    uint64
+	Size      uint64
+	BlockSize uint64
+	PageSize  uint64
+}
+
+// FindDataPartition returns the data partition that can hold a filesystem
+// of the given type: FAT filesystems use the "fat" subtype, SPIFFS and
+// LittleFS both use the "spiffs" subtype.
+func FindDataPartition(table []*partitions.Partition, fsType string) *partitions.Partition {
+	subType := "spiffs"
+	if fsType == "fatfs" {
+		subType = "fat"
+	}
+	for _, part := range table {
+		if part.Type == "data" && part.SubType == subType {
+			return part
+		}
+	}
+	return nil
+}
+
+// The flash memory of the ESP8266 is mapped at this address, older
+// platforms define the filesystem position using mapped addresses
+const esp8266FlashMappedAddress = 0x40200000
+
+// LayoutFromProperties computes the filesystem layout from the board
+// properties. The fs.offset and fs.size properties, when defined, take
+// precedence over the ESP8266 build.spiffs_* properties. The partitions
+// table is used only if none of them are defined.
+func LayoutFromProperties(props *properties.Map, fsType string, table []*partitions.Partition) (*Layout, error) {
+	layout := &Layout{BlockSize: 4096, PageSize: 256}
+	if err := parseOptionalSize(props, "fs.block_size", &layout.BlockSize); err != nil {
+		return nil, err
+	}
+	if err := parseOptionalSize(props, "fs.page_size", &layout.PageSize); err != nil {
+		return nil, err
+	}
+
+	if props.ContainsKey("fs.offset") && props.ContainsKey("fs.size") {
+		if err := parseOptionalSize(props, "fs.offset", &layout.Offset); err != nil {
+			return nil, err
+		}
+		if err := parseOptionalSize(props, "fs.size", &layout.Size); err != nil {
+			return nil, err
+		}
+		return layout, nil
+	}
+
+	if props.ContainsKey("build.spiffs_start") {
+		var start, end uint64
+		if err := parseOptionalSize(props, "build.spiffs_start", &start); err != nil {
+			return nil, err
+		}
+		if err := parseOptionalSize(props, "build.spiffs_end", &end); err != nil {
+			return nil, err
+		}
+		if end <= start {
+			return nil, fmt.Errorf("the board has no space reserved for the filesystem, select a different flash size")
+		}
+		if start >= esp8266FlashMappedAddress {
+			start -= esp8266FlashMappedAddress
+			end -= esp8266FlashMappedAddress
+		}
+		layout.Offset = start
+		layout.Size = end - start
+		if err := parseOptionalSize(props, "build.spiffs_blocksize", &layout.BlockSize); err != nil {
+			return nil, err
+		}
+		if err := parseOptionalSize(props, "build.spiffs_pagesize", &layout.PageSize); err != nil {
+			return nil, err
+		}
+		return layout, nil
+	}
+
+	if table != nil {
+		part := FindDataPartition(table, fsType)
+		if part == nil {
+			return nil, fmt.Errorf("the partitions table has no data partition for a %s filesystem", fsType)
+		}
+		layout.Offset = part.Offset
+		layout.Size = part.Size
+		return layout, nil
+	}
+
+	return nil, fmt.Errorf("the board doesn't define the position of the filesystem partition")
+}
+
+func parseOptionalSize(props *properties.Map, key string, res *uint64) error {
+	value, ok := props.GetOk(key)
+	if !ok || value == "" {
+		return nil
+	}
+	n, err := partitions.ParseSize(props.ExpandPropsInString(value))
+	if err != nil {
+		return fmt.Errorf("invalid %s property: %s", key, err)
+	}
+	*res = n
+	return nil
+}
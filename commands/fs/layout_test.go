@@ -0,0 +1,85 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package fs
+
+import (
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/partitions"
+	properties "github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+const defaultPartitions = `# Name,   Type, SubType, Offset,  Size, Flags
+nvs,      data, nvs,     0x9000,  0x5000,
+otadata,  data, ota,     0xe000,  0x2000,
+app0,     app,  ota_0,   0x10000, 0x140000,
+app1,     app,  ota_1,   0x150000,0x140000,
+spiffs,   data, spiffs,  0x290000,0x170000,
+`
+
+func TestFindDataPartition(t *testing.T) {
+	table, err := partitions.Parse("nvs, data, nvs, , 20K\nfactory, app, factory, , 1M\nffat, data, fat, , 0x100000\n")
+	require.NoError(t, err)
+	require.Equal(t, table[2], FindDataPartition(table, "fatfs"))
+	require.Nil(t, FindDataPartition(table, "littlefs"))
+}
+
+func TestLayoutFromProperties(t *testing.T) {
+	table, err := partitions.Parse(defaultPartitions)
+	require.NoError(t, err)
+
+	// ESP32: the layout comes from the partitions table
+	layout, err := LayoutFromProperties(properties.NewMap(), "littlefs", table)
+	require.NoError(t, err)
+	require.Equal(t, &Layout{Offset: 0x290000, Size: 0x170000, BlockSize: 4096, PageSize: 256}, layout)
+	_, err = LayoutFromProperties(properties.NewMap(), "fatfs", table)
+	require.Error(t, err)
+
+	// ESP8266: the layout comes from the build.spiffs_* properties
+	props := properties.NewFromHashmap(map[string]string{
+		"build.spiffs_start":     "0x200000",
+		"build.spiffs_end":       "0x3FA000",
+		"build.spiffs_blocksize": "8192",
+	})
+	layout, err = LayoutFromProperties(props, "littlefs", nil)
+	require.NoError(t, err)
+	require.Equal(t, &Layout{Offset: 0x200000, Size: 0x1FA000, BlockSize: 8192, PageSize: 256}, layout)
+
+	// older platforms use the mapped addresses
+	props.Set("build.spiffs_start", "0x40300000")
+	props.Set("build.spiffs_end", "0x403FB000")
+	layout, err = LayoutFromProperties(props, "spiffs", nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0x100000), layout.Offset)
+	require.Equal(t, uint64(0xFB000), layout.Size)
+
+	// a flash size without filesystem
+	props.Set("build.spiffs_end", "0x40300000")
+	_, err = LayoutFromProperties(props, "spiffs", nil)
+	require.Error(t, err)
+
+	// fs.offset and fs.size take precedence
+	props.Set("fs.offset", "0x100000")
+	props.Set("fs.size", "1M")
+	props.Set("fs.block_size", "512")
+	layout, err = LayoutFromProperties(props, "fatfs", table)
+	require.NoError(t, err)
+	require.Equal(t, &Layout{Offset: 0x100000, Size: 0x100000, BlockSize: 512, PageSize: 256}, layout)
+
+	_, err = LayoutFromProperties(properties.NewMap(), "littlefs", nil)
+	require.Error(t, err)
+}
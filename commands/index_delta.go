@@ -0,0 +1,116 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package commands
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino/utils"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"go.bug.st/downloader/v2"
+)
+
+// updateIndexFromDelta updates the local copy of the index downloaded from
+// indexURL, writing the updated index in target, using the delta files that
+// the server may publish next to the index:
+//
+//	<index URL>.sha256                  the SHA-256 of the current index
+//	<index URL>.patches/<SHA-256>.diff  the "diff -n" from the index with the
+//	                                    given SHA-256 to the current one
+//
+// It returns false if the server doesn't publish them, the delta can't be
+// applied or the delta updates are disabled by the updater.index_delta
+// setting: in that case the whole index must be downloaded.
+func updateIndexFromDelta(indexURL *url.URL, localIndex, target *paths.Path, settings *viper.Viper, config *downloader.Config, downloadCB DownloadProgressCB) bool {
+	if !settings.GetBool("updater.index_delta") || localIndex.NotExist() {
+		return false
+	}
+	log := logrus.WithField("url", indexURL)
+	local, err := localIndex.ReadFile()
+	if err != nil {
+		log.WithError(err).Warn("Reading local index")
+		return false
+	}
+
+	checksumURL := *indexURL
+	checksumURL.Path += ".sha256"
+	data, err := fetchIndexDeltaFile(&checksumURL, config)
+	if err != nil {
+		log.WithError(err).Info("Index delta not available")
+		return false
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		log.Warn("Invalid index checksum")
+		return false
+	}
+	remoteChecksum := strings.ToLower(fields[0])
+
+	updated := local
+	label := "Updating index: " + localIndex.Base() + " (up to date)"
+	if localChecksum := sha256Hex(local); localChecksum != remoteChecksum {
+		diffURL := *indexURL
+		diffURL.Path += ".patches/" + localChecksum + ".diff"
+		diff, err := fetchIndexDeltaFile(&diffURL, config)
+		if err != nil {
+			log.WithError(err).Info("Index delta not available")
+			return false
+		}
+		if updated, err = utils.ApplyRCSDiff(local, diff); err != nil {
+			log.WithError(err).Warn("Applying index delta")
+			return false
+		}
+		if sha256Hex(updated) != remoteChecksum {
+			log.Warn("Index checksum mismatch after applying delta")
+			return false
+		}
+		label = "Updating index: " + localIndex.Base() + " (delta)"
+	}
+
+	if err := target.WriteFile(updated); err != nil {
+		log.WithError(err).Warn("Writing updated index")
+		return false
+	}
+	downloadCB(&rpc.DownloadProgress{File: label, Url: indexURL.String(), TotalSize: int64(len(updated))})
+	downloadCB(&rpc.DownloadProgress{Completed: true})
+	return true
+}
+
+func fetchIndexDeltaFile(URL *url.URL, config *downloader.Config) ([]byte, error) {
+	resp, err := config.HttpClient.Get(URL.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", URL, resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+func sha256Hex(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
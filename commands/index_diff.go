@@ -0,0 +1,218 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package commands
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/arduino/cores/packageindex"
+	"github.com/arduino/arduino-cli/arduino/cores/packagemanager"
+	"github.com/arduino/arduino-cli/arduino/libraries/librariesindex"
+	"github.com/arduino/arduino-cli/arduino/libraries/librariesmanager"
+	"github.com/arduino/go-paths-helper"
+	semver "go.bug.st/relaxed-semver"
+)
+
+// IndexChanges are the platforms and libraries added, or updated to a new
+// version, by the last update of the indexes
+type IndexChanges struct {
+	NewPlatforms     []*IndexChange `json:"new_platforms"`
+	UpdatedPlatforms []*IndexChange `json:"updated_platforms"`
+	NewLibraries     []*IndexChange `json:"new_libraries"`
+	UpdatedLibraries []*IndexChange `json:"updated_libraries"`
+}
+
+// IndexChange is a platform (identified by PACKAGER:ARCH) or a library added
+// or updated in an index
+type IndexChange struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	Version         string `json:"version"`
+	PreviousVersion string `json:"previous_version,omitempty"`
+	Installed       string `json:"installed,omitempty"`
+}
+
+// previousIndexFile returns the path of the copy of the index file made
+// before the last update
+func previousIndexFile(index *paths.Path) *paths.Path {
+	return index.Parent().Join("previous_indexes", index.Base())
+}
+
+// snapshotIndex copies the index file before it's updated, to compare the
+// index before and after the update with IndexDiff
+func snapshotIndex(index *paths.Path) error {
+	if index.NotExist() {
+		return nil
+	}
+	previous := previousIndexFile(index)
+	if err := previous.Parent().MkdirAll(); err != nil {
+		return fmt.Errorf("saving previous index: %s", err)
+	}
+	if err := index.CopyTo(previous); err != nil {
+		return fmt.Errorf("saving previous index: %s", err)
+	}
+	return nil
+}
+
+// IndexDiff compares the package and library indexes with the ones before
+// the last update. The updated platforms and libraries are listed only if
+// installed, unless all is true.
+func IndexDiff(pm *packagemanager.PackageManager, lm *librariesmanager.LibrariesManager, all bool) (*IndexChanges, error) {
+	changes := &IndexChanges{
+		NewPlatforms:     []*IndexChange{},
+		UpdatedPlatforms: []*IndexChange{},
+		NewLibraries:     []*IndexChange{},
+		UpdatedLibraries: []*IndexChange{},
+	}
+
+	previousIndexes, err := previousIndexFile(pm.IndexDir.Join("package_index.json")).Parent().ReadDir()
+	if err != nil {
+		// no index has been updated yet
+		return changes, nil
+	}
+	previousIndexes.FilterSuffix(".json")
+	previousIndexes.Sort()
+	for _, previous := range previousIndexes {
+		current := pm.IndexDir.Join(previous.Base())
+		if current.NotExist() {
+			continue
+		}
+		if previous.Base() == lm.IndexFile.Base() {
+			if err := diffLibraryIndex(lm, previous, current, all, changes); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err := diffPackageIndex(pm, previous, current, all, changes); err != nil {
+			return nil, err
+		}
+	}
+	return changes, nil
+}
+
+func loadIndexPackages(file *paths.Path) (cores.Packages, error) {
+	index, err := packageindex.LoadIndexNoSign(file)
+	if err != nil {
+		return nil, err
+	}
+	packages := cores.NewPackages()
+	index.MergeIntoPackages(packages)
+	return packages, nil
+}
+
+func diffPackageIndex(pm *packagemanager.PackageManager, previousFile, currentFile *paths.Path, all bool, changes *IndexChanges) error {
+	previous, err := loadIndexPackages(previousFile)
+	if err != nil {
+		return fmt.Errorf("loading previous index %s: %s", previousFile.Base(), err)
+	}
+	current, err := loadIndexPackages(currentFile)
+	if err != nil {
+		return fmt.Errorf("loading index %s: %s", currentFile.Base(), err)
+	}
+
+	for _, packageName := range current.Names() {
+		platforms := current[packageName].Platforms
+		architectures := []string{}
+		for architecture := range platforms {
+			architectures = append(architectures, architecture)
+		}
+		sort.Strings(architectures)
+		for _, architecture := range architectures {
+			platform := platforms[architecture]
+			latest := platform.GetLatestRelease()
+			if latest == nil {
+				continue
+			}
+			change := &IndexChange{ID: platform.String(), Name: platform.Name, Version: latest.Version.String()}
+			if installed := installedPlatformRelease(pm, platform); installed != nil {
+				change.Installed = installed.Version.String()
+			}
+
+			var previousLatest *cores.PlatformRelease
+			if previousPackage, ok := previous[packageName]; ok {
+				if previousPlatform, ok := previousPackage.Platforms[platform.Architecture]; ok {
+					previousLatest = previousPlatform.GetLatestRelease()
+				}
+			}
+			if previousLatest == nil {
+				changes.NewPlatforms = append(changes.NewPlatforms, change)
+			} else if latest.Version.GreaterThan(previousLatest.Version) && (all || change.Installed != "") {
+				change.PreviousVersion = previousLatest.Version.String()
+				changes.UpdatedPlatforms = append(changes.UpdatedPlatforms, change)
+			}
+		}
+	}
+	return nil
+}
+
+func installedPlatformRelease(pm *packagemanager.PackageManager, platform *cores.Platform) *cores.PlatformRelease {
+	targetPackage, ok := pm.Packages[platform.Package.Name]
+	if !ok {
+		return nil
+	}
+	installedPlatform, ok := targetPackage.Platforms[platform.Architecture]
+	if !ok {
+		return nil
+	}
+	return pm.GetInstalledPlatformRelease(installedPlatform)
+}
+
+func diffLibraryIndex(lm *librariesmanager.LibrariesManager, previousFile, currentFile *paths.Path, all bool, changes *IndexChanges) error {
+	previous, err := librariesindex.LoadIndex(previousFile)
+	if err != nil {
+		return fmt.Errorf("loading previous index %s: %s", previousFile.Base(), err)
+	}
+	current, err := librariesindex.LoadIndex(currentFile)
+	if err != nil {
+		return fmt.Errorf("loading index %s: %s", currentFile.Base(), err)
+	}
+
+	installed := map[string]*semver.Version{}
+	for _, alternatives := range lm.Libraries {
+		for _, library := range alternatives.Alternatives {
+			if library.Version != nil {
+				installed[library.Name] = library.Version
+			}
+		}
+	}
+
+	names := []string{}
+	for name := range current.Libraries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		library := current.Libraries[name]
+		if library.Latest == nil {
+			continue
+		}
+		change := &IndexChange{ID: library.Name, Name: library.Name, Version: library.Latest.Version.String()}
+		if version, ok := installed[library.Name]; ok {
+			change.Installed = version.String()
+		}
+
+		previousLibrary, ok := previous.Libraries[name]
+		if !ok || previousLibrary.Latest == nil {
+			changes.NewLibraries = append(changes.NewLibraries, change)
+		} else if library.Latest.Version.GreaterThan(previousLibrary.Latest.Version) && (all || change.Installed != "") {
+			change.PreviousVersion = previousLibrary.Latest.Version.String()
+			changes.UpdatedLibraries = append(changes.UpdatedLibraries, change)
+		}
+	}
+	return nil
+}
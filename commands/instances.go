@@ -22,6 +22,7 @@ import (
 	"net/url"
 	"os"
 	"path"
+	"strings"
 
 	"github.com/arduino/arduino-cli/arduino/builder"
 	"github.com/arduino/arduino-cli/arduino/cores"
@@ -34,11 +35,15 @@ import (
 	"github.com/arduino/arduino-cli/arduino/utils"
 	"github.com/arduino/arduino-cli/cli/globals"
 	"github.com/arduino/arduino-cli/configuration"
+	"github.com/arduino/arduino-cli/i18n"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
 	paths "github.com/arduino/go-paths-helper"
 	"github.com/pkg/errors"
+	"github.com/segmentio/stats/v4"
 	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
 	"go.bug.st/downloader/v2"
+	pgperrors "golang.org/x/crypto/openpgp/errors"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -54,6 +59,8 @@ var instancesCount int32 = 1
 type CoreInstance struct {
 	PackageManager *packagemanager.PackageManager
 	lm             *librariesmanager.LibrariesManager
+	// Settings is the configuration used by this instance
+	Settings *viper.Viper
 }
 
 // InstanceContainer FIXMEDOC
@@ -77,6 +84,16 @@ func GetPackageManager(id int32) *packagemanager.PackageManager {
 	return i.PackageManager
 }
 
+// GetSettings returns the configuration used by the instance with the given
+// ID, or nil if ID doesn't exist
+func GetSettings(instanceID int32) *viper.Viper {
+	i, ok := instances[instanceID]
+	if !ok {
+		return nil
+	}
+	return i.Settings
+}
+
 // GetLibraryManager returns the library manager for the given instance ID
 func GetLibraryManager(instanceID int32) *librariesmanager.LibrariesManager {
 	i, ok := instances[instanceID]
@@ -91,7 +108,7 @@ func (instance *CoreInstance) installToolIfMissing(tool *cores.ToolRelease, down
 		return false, nil
 	}
 	taskCB(&rpc.TaskProgress{Name: "Downloading missing tool " + tool.String()})
-	if err := DownloadToolRelease(instance.PackageManager, tool, downloadCB); err != nil {
+	if err := DownloadToolRelease(instance.PackageManager, instance.Settings, tool, downloadCB); err != nil {
 		return false, fmt.Errorf("downloading %s tool: %s", tool, err)
 	}
 	taskCB(&rpc.TaskProgress{Completed: true})
@@ -103,10 +120,27 @@ func (instance *CoreInstance) installToolIfMissing(tool *cores.ToolRelease, down
 
 // Create a new CoreInstance ready to be initialized, supporting directories are also created.
 func Create(req *rpc.CreateRequest) (*rpc.CreateResponse, *status.Status) {
-	instance := &CoreInstance{}
+	// Each instance may use its own configuration file, the global one is
+	// used if none is specified
+	settings := configuration.Settings
+	if configFile := req.GetConfigFile(); configFile != "" {
+		if !paths.New(configFile).Exist() {
+			return nil, status.Newf(codes.InvalidArgument, "Config file not found: %s", configFile)
+		}
+		var err error
+		if settings, err = configuration.Load(configFile); err != nil {
+			return nil, status.Newf(codes.InvalidArgument, "Error reading config file %s: %v", configFile, err)
+		}
+		// The messages are translated in a single language for the whole
+		// process, the last instance that sets a locale selects it
+		if settings.InConfig("locale") {
+			i18n.Init(settings)
+		}
+	}
+	instance := &CoreInstance{Settings: settings}
 
 	// Setup downloads directory
-	downloadsDir := paths.New(configuration.Settings.GetString("directories.Downloads"))
+	downloadsDir := paths.New(settings.GetString("directories.Downloads"))
 	if downloadsDir.NotExist() {
 		err := downloadsDir.MkdirAll()
 		if err != nil {
@@ -116,8 +150,8 @@ func Create(req *rpc.CreateRequest) (*rpc.CreateResponse, *status.Status) {
 	}
 
 	// Setup data directory
-	dataDir := paths.New(configuration.Settings.GetString("directories.Data"))
-	packagesDir := configuration.PackagesDir(configuration.Settings)
+	dataDir := paths.New(settings.GetString("directories.Data"))
+	packagesDir := configuration.PackagesDir(settings)
 	if packagesDir.NotExist() {
 		err := packagesDir.MkdirAll()
 		if err != nil {
@@ -129,25 +163,33 @@ func Create(req *rpc.CreateRequest) (*rpc.CreateResponse, *status.Status) {
 	// Create package manager
 	instance.PackageManager = packagemanager.NewPackageManager(
 		dataDir,
-		configuration.PackagesDir(configuration.Settings),
+		configuration.PackagesDir(settings),
 		downloadsDir,
 		dataDir.Join("tmp"),
 	)
+	securityLevel, err := security.ParseLevel(settings.GetString("security.level"))
+	if err != nil {
+		return nil, status.Newf(codes.InvalidArgument, err.Error())
+	}
+	instance.PackageManager.SecurityLevel = securityLevel
+	instance.PackageManager.TrustedKeys = configuration.TrustedKeys(settings)
+	instance.PackageManager.Mirrors = settings.GetStringSlice("network.mirrors")
 
 	// Create library manager and add libraries directories
 	instance.lm = librariesmanager.NewLibraryManager(
 		dataDir,
 		downloadsDir,
 	)
+	instance.lm.Mirrors = settings.GetStringSlice("network.mirrors")
 
 	// Add directories of libraries bundled with IDE
-	if bundledLibsDir := configuration.IDEBundledLibrariesDir(configuration.Settings); bundledLibsDir != nil {
+	if bundledLibsDir := configuration.IDEBundledLibrariesDir(settings); bundledLibsDir != nil {
 		instance.lm.AddLibrariesDir(bundledLibsDir, libraries.IDEBuiltIn)
 	}
 
 	// Add libraries directory from config file
 	instance.lm.AddLibrariesDir(
-		configuration.LibrariesDir(configuration.Settings),
+		configuration.LibrariesDir(settings),
 		libraries.User,
 	)
 
@@ -155,6 +197,7 @@ func Create(req *rpc.CreateRequest) (*rpc.CreateResponse, *status.Status) {
 	instanceID := instancesCount
 	instances[instanceID] = instance
 	instancesCount++
+	stats.Set("instances.active", len(instances))
 
 	return &rpc.CreateResponse{
 		Instance: &rpc.Instance{Id: instanceID},
@@ -184,7 +227,7 @@ func Init(req *rpc.InitRequest, responseCallback func(r *rpc.InitResponse)) *sta
 
 	// Load Platforms
 	urls := []string{globals.DefaultIndexURL}
-	urls = append(urls, configuration.Settings.GetStringSlice("board_manager.additional_urls")...)
+	urls = append(urls, instance.Settings.GetStringSlice("board_manager.additional_urls")...)
 	for _, u := range urls {
 		URL, err := utils.URLParse(u)
 		if err != nil {
@@ -225,7 +268,7 @@ func Init(req *rpc.InitRequest, responseCallback func(r *rpc.InitResponse)) *sta
 	// We load hardware before verifying builtin tools are installed
 	// otherwise we wouldn't find them and reinstall them each time
 	// and they would never get reloaded.
-	for _, err := range instance.PackageManager.LoadHardware() {
+	for _, err := range instance.PackageManager.LoadHardware(instance.Settings) {
 		responseCallback(&rpc.InitResponse{
 			Message: &rpc.InitResponse_Error{
 				Error: err.Proto(),
@@ -298,7 +341,7 @@ func Init(req *rpc.InitRequest, responseCallback func(r *rpc.InitResponse)) *sta
 	if toolHasBeenInstalled {
 		// We installed at least one new tool after loading hardware
 		// so we must reload again otherwise we would never found them.
-		for _, err := range instance.PackageManager.LoadHardware() {
+		for _, err := range instance.PackageManager.LoadHardware(instance.Settings) {
 			responseCallback(&rpc.InitResponse{
 				Message: &rpc.InitResponse_Error{
 					Error: err.Proto(),
@@ -345,6 +388,7 @@ func Destroy(ctx context.Context, req *rpc.DestroyRequest) (*rpc.DestroyResponse
 	}
 
 	delete(instances, id)
+	stats.Set("instances.active", len(instances))
 	return &rpc.DestroyResponse{}, nil
 }
 
@@ -355,7 +399,8 @@ func UpdateLibrariesIndex(ctx context.Context, req *rpc.UpdateLibrariesIndexRequ
 	if lm == nil {
 		return fmt.Errorf("invalid handle")
 	}
-	config, err := GetDownloaderConfig()
+	settings := GetSettings(req.GetInstance().GetId())
+	config, err := getIndexDownloaderConfig(settings)
 	if err != nil {
 		return err
 	}
@@ -371,14 +416,22 @@ func UpdateLibrariesIndex(ctx context.Context, req *rpc.UpdateLibrariesIndexRequ
 	}
 	defer tmp.RemoveAll()
 
-	// Download gzipped library_index
-	tmpIndexGz := tmp.Join("library_index.json.gz")
-	if d, err := downloader.DownloadWithConfig(tmpIndexGz.String(), librariesmanager.LibraryIndexGZURL.String(), *config, downloader.NoResume); err == nil {
-		if err := Download(d, "Updating index: library_index.json.gz", downloadCB); err != nil {
-			return errors.Wrap(err, "downloading library_index.json.gz")
+	// Update library_index from the delta or download the gzipped one
+	tmpIndex := tmp.Join("library_index.json")
+	if !updateIndexFromDelta(librariesmanager.LibraryIndexURL, lm.IndexFile, tmpIndex, settings, config, downloadCB) {
+		tmpIndexGz := tmp.Join("library_index.json.gz")
+		if d, err := downloader.DownloadWithConfig(tmpIndexGz.String(), librariesmanager.LibraryIndexGZURL.String(), *config, downloader.NoResume); err == nil {
+			if err := Download(d, "Updating index: library_index.json.gz", downloadCB); err != nil {
+				return errors.Wrap(err, "downloading library_index.json.gz")
+			}
+		} else {
+			return err
+		}
+
+		// Extract the real library_index
+		if err := paths.GUnzip(tmpIndexGz, tmpIndex); err != nil {
+			return errors.Wrap(err, "unzipping library_index.json.gz")
 		}
-	} else {
-		return err
 	}
 
 	// Download signature
@@ -391,12 +444,6 @@ func UpdateLibrariesIndex(ctx context.Context, req *rpc.UpdateLibrariesIndexRequ
 		return err
 	}
 
-	// Extract the real library_index
-	tmpIndex := tmp.Join("library_index.json")
-	if err := paths.GUnzip(tmpIndexGz, tmpIndex); err != nil {
-		return errors.Wrap(err, "unzipping library_index.json.gz")
-	}
-
 	// Check signature
 	if ok, _, err := security.VerifyArduinoDetachedSignature(tmpIndex, tmpSignature); err != nil {
 		return errors.Wrap(err, "verifying signature")
@@ -405,6 +452,9 @@ func UpdateLibrariesIndex(ctx context.Context, req *rpc.UpdateLibrariesIndexRequ
 	}
 
 	// Copy extracted library_index and signature to final destination
+	if err := snapshotIndex(lm.IndexFile); err != nil {
+		return err
+	}
 	lm.IndexFile.Remove()
 	lm.IndexFileSignature.Remove()
 	if err := tmpIndex.CopyTo(lm.IndexFile); err != nil {
@@ -420,15 +470,17 @@ func UpdateLibrariesIndex(ctx context.Context, req *rpc.UpdateLibrariesIndexRequ
 // UpdateIndex FIXMEDOC
 func UpdateIndex(ctx context.Context, req *rpc.UpdateIndexRequest, downloadCB DownloadProgressCB) (*rpc.UpdateIndexResponse, error) {
 	id := req.GetInstance().GetId()
-	_, ok := instances[id]
+	instance, ok := instances[id]
 	if !ok {
 		return nil, fmt.Errorf("invalid handle")
 	}
 
-	indexpath := paths.New(configuration.Settings.GetString("directories.Data"))
+	indexpath := paths.New(instance.Settings.GetString("directories.Data"))
+	securityLevel := instance.PackageManager.SecurityLevel
+	trustedKeys := instance.PackageManager.TrustedKeys
 
 	urls := []string{globals.DefaultIndexURL}
-	urls = append(urls, configuration.Settings.GetStringSlice("board_manager.additional_urls")...)
+	urls = append(urls, instance.Settings.GetStringSlice("board_manager.additional_urls")...)
 	for _, u := range urls {
 		logrus.Info("URL: ", u)
 		URL, err := utils.URLParse(u)
@@ -464,29 +516,48 @@ func UpdateIndex(ctx context.Context, req *rpc.UpdateIndexRequest, downloadCB Do
 		}
 		defer tmp.Remove()
 
-		config, err := GetDownloaderConfig()
-		if err != nil {
-			return nil, fmt.Errorf("downloading index %s: %s", URL, err)
-		}
-		d, err := downloader.DownloadWithConfig(tmp.String(), URL.String(), *config)
+		config, err := getIndexDownloaderConfig(instance.Settings)
 		if err != nil {
 			return nil, fmt.Errorf("downloading index %s: %s", URL, err)
 		}
-		coreIndexPath := indexpath.Join(path.Base(URL.Path))
-		err = Download(d, "Updating index: "+coreIndexPath.Base(), downloadCB)
-		if err != nil {
-			return nil, fmt.Errorf("downloading index %s: %s", URL, err)
+		// The compressed indexes are stored uncompressed, the delta and the
+		// signature refer to the uncompressed index
+		compression := packageindex.IndexCompression(URL)
+		indexURL := *URL
+		indexURL.Path = strings.TrimSuffix(indexURL.Path, compression)
+		coreIndexPath := indexpath.Join(packageindex.IndexFileName(URL))
+		if !updateIndexFromDelta(&indexURL, coreIndexPath, tmp, instance.Settings, config, downloadCB) {
+			downloadPath := tmp
+			if compression != "" {
+				downloadPath = paths.New(tmp.String() + compression)
+				defer downloadPath.Remove()
+			}
+			d, err := downloader.DownloadWithConfig(downloadPath.String(), URL.String(), *config)
+			if err != nil {
+				return nil, fmt.Errorf("downloading index %s: %s", URL, err)
+			}
+			err = Download(d, "Updating index: "+path.Base(URL.Path), downloadCB)
+			if err != nil {
+				return nil, fmt.Errorf("downloading index %s: %s", URL, err)
+			}
+			if compression != "" {
+				if err := utils.Uncompress(downloadPath, tmp, compression); err != nil {
+					return nil, fmt.Errorf("uncompressing index %s: %s", URL, err)
+				}
+			}
 		}
 
-		// Check for signature
+		// Check for signature: the Arduino indexes must be signed, the
+		// signature of the other indexes is required only in strict mode
 		var tmpSig *paths.Path
 		var coreIndexSigPath *paths.Path
-		if URL.Hostname() == "downloads.arduino.cc" {
-			URLSig, err := url.Parse(URL.String())
+		if securityLevel != security.LevelOff {
+			URLSig, err := url.Parse(indexURL.String())
 			if err != nil {
 				return nil, fmt.Errorf("parsing url for index signature check: %s", err)
 			}
 			URLSig.Path += ".sig"
+			signatureRequired := URL.Hostname() == "downloads.arduino.cc" || securityLevel == security.LevelStrict
 
 			if t, err := ioutil.TempFile("", ""); err != nil {
 				return nil, fmt.Errorf("creating temp file for index signature download: %s", err)
@@ -498,22 +569,26 @@ func UpdateIndex(ctx context.Context, req *rpc.UpdateIndexRequest, downloadCB Do
 			defer tmpSig.Remove()
 
 			d, err := downloader.DownloadWithConfig(tmpSig.String(), URLSig.String(), *config)
-			if err != nil {
-				return nil, fmt.Errorf("downloading index signature %s: %s", URLSig, err)
+			if err == nil {
+				coreIndexSigPath = indexpath.Join(path.Base(URLSig.Path))
+				Download(d, "Updating index: "+coreIndexSigPath.Base(), downloadCB)
+				err = d.Error()
 			}
-
-			coreIndexSigPath = indexpath.Join(path.Base(URLSig.Path))
-			Download(d, "Updating index: "+coreIndexSigPath.Base(), downloadCB)
-			if d.Error() != nil {
-				return nil, fmt.Errorf("downloading index signature %s: %s", URL, d.Error())
-			}
-
-			valid, _, err := security.VerifyArduinoDetachedSignature(tmp, tmpSig)
 			if err != nil {
-				return nil, fmt.Errorf("signature verification error: %s", err)
-			}
-			if !valid {
-				return nil, fmt.Errorf("index has an invalid signature")
+				if signatureRequired {
+					return nil, fmt.Errorf("downloading index signature %s: %s", URLSig, err)
+				}
+				logrus.WithError(err).Warnf("Index %s is not signed", URL)
+				tmpSig = nil
+			} else {
+				valid, _, err := security.VerifyDetachedSignatureWithTrustedKeys(tmp, tmpSig, trustedKeys)
+				if !valid && !signatureRequired && err == pgperrors.ErrUnknownIssuer {
+					logrus.Warnf("Index %s is not signed by a trusted key", URL)
+				} else if err != nil {
+					return nil, fmt.Errorf("signature verification error: %s", err)
+				} else if !valid {
+					return nil, fmt.Errorf("index has an invalid signature")
+				}
 			}
 		}
 
@@ -525,6 +600,9 @@ func UpdateIndex(ctx context.Context, req *rpc.UpdateIndexRequest, downloadCB Do
 			return nil, fmt.Errorf("can't create data directory %s: %s", indexpath, err)
 		}
 
+		if err := snapshotIndex(coreIndexPath); err != nil {
+			return nil, err
+		}
 		if err := tmp.CopyTo(coreIndexPath); err != nil {
 			return nil, fmt.Errorf("saving downloaded index %s: %s", URL, err)
 		}
@@ -677,15 +755,16 @@ func getOutputRelease(lib *librariesindex.Release) *rpc.LibraryRelease {
 
 // Upgrade downloads and installs outdated Cores and Libraries
 func Upgrade(ctx context.Context, req *rpc.UpgradeRequest, downloadCB DownloadProgressCB, taskCB TaskProgressCB) error {
-	downloaderConfig, err := GetDownloaderConfig()
-	if err != nil {
-		return err
-	}
-
 	lm := GetLibraryManager(req.Instance.Id)
 	if lm == nil {
 		return fmt.Errorf("invalid handle")
 	}
+	settings := GetSettings(req.Instance.Id)
+
+	downloaderConfig, err := GetDownloaderConfig(settings)
+	if err != nil {
+		return err
+	}
 
 	for _, libAlternatives := range lm.Libraries {
 		for _, library := range libAlternatives.Alternatives {
@@ -775,7 +854,7 @@ func Upgrade(ctx context.Context, req *rpc.UpgradeRequest, downloadCB DownloadPr
 
 				// Downloads platform tools
 				for _, tool := range toolsToInstall {
-					if err := DownloadToolRelease(pm, tool, downloadCB); err != nil {
+					if err := DownloadToolRelease(pm, settings, tool, downloadCB); err != nil {
 						taskCB(&rpc.TaskProgress{Message: "Error downloading tool " + tool.String()})
 						return err
 					}
@@ -24,6 +24,7 @@ import (
 	"github.com/arduino/arduino-cli/commands"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
 	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
 )
 
 // LibraryDownload FIXMEDOC
@@ -39,22 +40,22 @@ func LibraryDownload(ctx context.Context, req *rpc.LibraryDownloadRequest, downl
 		return nil, fmt.Errorf("looking for library: %s", err)
 	}
 
-	if err := downloadLibrary(lm, lib, downloadCB, func(*rpc.TaskProgress) {}); err != nil {
+	if err := downloadLibrary(lm, commands.GetSettings(req.GetInstance().GetId()), lib, downloadCB, func(*rpc.TaskProgress) {}); err != nil {
 		return nil, err
 	}
 
 	return &rpc.LibraryDownloadResponse{}, nil
 }
 
-func downloadLibrary(lm *librariesmanager.LibrariesManager, libRelease *librariesindex.Release,
+func downloadLibrary(lm *librariesmanager.LibrariesManager, settings *viper.Viper, libRelease *librariesindex.Release,
 	downloadCB commands.DownloadProgressCB, taskCB commands.TaskProgressCB) error {
 
 	taskCB(&rpc.TaskProgress{Name: "Downloading " + libRelease.String()})
-	config, err := commands.GetDownloaderConfig()
+	config, err := commands.GetDownloaderConfig(settings)
 	if err != nil {
 		return err
 	}
-	if d, err := libRelease.Resource.Download(lm.DownloadsDir, config); err != nil {
+	if d, err := libRelease.Resource.Download(lm.DownloadsDir, config, lm.Mirrors...); err != nil {
 		return err
 	} else if err := commands.Download(d, libRelease.String(), downloadCB); err != nil {
 		return err
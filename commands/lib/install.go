@@ -22,6 +22,7 @@ import (
 	"github.com/arduino/arduino-cli/arduino/libraries/librariesindex"
 	"github.com/arduino/arduino-cli/arduino/libraries/librariesmanager"
 	"github.com/arduino/arduino-cli/commands"
+	"github.com/arduino/arduino-cli/httpclient"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
 	"github.com/sirupsen/logrus"
 )
@@ -68,7 +69,7 @@ func LibraryInstall(ctx context.Context, req *rpc.LibraryInstallRequest,
 			return fmt.Errorf("looking for library: %s", err)
 		}
 
-		if err := downloadLibrary(lm, libRelease, downloadCB, taskCB); err != nil {
+		if err := downloadLibrary(lm, commands.GetSettings(req.GetInstance().GetId()), libRelease, downloadCB, taskCB); err != nil {
 			return fmt.Errorf("downloading library: %s", err)
 		}
 
@@ -121,6 +122,9 @@ func ZipLibraryInstall(ctx context.Context, req *rpc.ZipLibraryInstallRequest, t
 
 //GitLibraryInstall FIXMEDOC
 func GitLibraryInstall(ctx context.Context, req *rpc.GitLibraryInstallRequest, taskCB commands.TaskProgressCB) error {
+	if httpclient.IsOfflineFromSettings(commands.GetSettings(req.GetInstance().GetId())) {
+		return httpclient.ErrOffline
+	}
 	lm := commands.GetLibraryManager(req.GetInstance().GetId())
 	if err := lm.InstallGitLib(req.Url, req.Overwrite); err != nil {
 		return err
@@ -0,0 +1,113 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package lib
+
+import (
+	"sort"
+
+	"github.com/arduino/arduino-cli/arduino/libraries/librariesindex"
+	"github.com/arduino/arduino-cli/arduino/libraries/licenses"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	paths "github.com/arduino/go-paths-helper"
+	semver "go.bug.st/relaxed-semver"
+)
+
+// Status of the license of a library in a LibraryLicense
+const (
+	LicenseAllowed    = "allowed"
+	LicenseNotAllowed = "not allowed"
+	LicenseUnknown    = "unknown"
+)
+
+// LibraryLicense is the license of a library
+type LibraryLicense struct {
+	Name     string `json:"name"`
+	Version  string `json:"version,omitempty"`
+	Location string `json:"location"`
+	// License is the SPDX identifier of the license, or the license declared
+	// by the library if it isn't recognized
+	License string `json:"license,omitempty"`
+	// Source is where the license has been found: library.properties, the
+	// license file of the library or the libraries index
+	Source string `json:"source,omitempty"`
+	Status string `json:"status"`
+}
+
+// LibraryLicenses returns the licenses of the given libraries. The license is
+// the one declared in the library.properties, the one of the license file of
+// the library or the one of the release in the libraries index, in this order.
+// The libraries with a license that can't be found or that isn't recognized
+// are marked as unknown, unless it's in the allowlist, the libraries with a
+// license not in the allowlist are marked as not allowed. All the recognized
+// licenses are allowed if the allowlist is empty.
+func LibraryLicenses(libs []*rpc.Library, index *librariesindex.Index, allowlist []string) []*LibraryLicense {
+	res := []*LibraryLicense{}
+	for _, lib := range libs {
+		license := &LibraryLicense{
+			Name:     lib.GetName(),
+			Version:  lib.GetVersion(),
+			Location: lib.GetLocation().String(),
+		}
+		if platform := lib.GetContainerPlatform(); platform != "" {
+			license.Location = platform
+		}
+		// The loader sets the license of the libraries that don't declare it to Unspecified
+		if declared := lib.GetLicense(); declared != "" && declared != "Unspecified" {
+			license.License, license.Source = declared, "library.properties"
+		} else if detected := licenses.Detect(paths.New(lib.GetInstallDir())); detected != "" {
+			license.License, license.Source = detected, "license file"
+		} else if release := findIndexRelease(index, lib); release != nil && release.License != "" {
+			license.License, license.Source = release.License, "libraries index"
+		}
+		if id := licenses.Normalize(license.License); id != "" {
+			license.License = id
+		}
+
+		switch {
+		case license.License == "":
+			license.Status = LicenseUnknown
+		case licenses.Allowed(license.License, allowlist):
+			license.Status = LicenseAllowed
+		case licenses.Normalize(license.License) == "":
+			license.Status = LicenseUnknown
+		case len(allowlist) > 0:
+			license.Status = LicenseNotAllowed
+		default:
+			license.Status = LicenseAllowed
+		}
+		res = append(res, license)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Name != res[j].Name {
+			return res[i].Name < res[j].Name
+		}
+		return res[i].Location < res[j].Location
+	})
+	return res
+}
+
+// findIndexRelease returns the release of the libraries index with the name
+// and the version of the given library, or nil if not found
+func findIndexRelease(index *librariesindex.Index, lib *rpc.Library) *librariesindex.Release {
+	if index == nil {
+		return nil
+	}
+	version, err := semver.Parse(lib.GetVersion())
+	if err != nil {
+		return nil
+	}
+	return index.FindRelease(&librariesindex.Reference{Name: lib.GetName(), Version: version})
+}
@@ -0,0 +1,99 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package lib
+
+import (
+	"errors"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino/libraries/librariesindex"
+	"github.com/arduino/arduino-cli/arduino/libraries/librariesmanager"
+	"github.com/arduino/arduino-cli/arduino/utils"
+	"github.com/arduino/arduino-cli/commands"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+)
+
+// LibraryProvidingInclude returns the library of the index that best provides
+// the given header, looking at the includes declared by the latest release of
+// each library. The libraries whose name matches the header are preferred.
+// If no library provides the header nil is returned.
+func LibraryProvidingInclude(instance *rpc.Instance, include string) (*rpc.SearchedLibrary, error) {
+	lm := commands.GetLibraryManager(instance.GetId())
+	if lm == nil {
+		return nil, errors.New("invalid instance")
+	}
+	lib := libraryProvidingInclude(lm, include)
+	if lib == nil {
+		return nil, nil
+	}
+	return indexLibraryToRPCSearchLibrary(lib), nil
+}
+
+func libraryProvidingInclude(lm *librariesmanager.LibrariesManager, include string) *librariesindex.Library {
+	if lm.Index == nil {
+		return nil
+	}
+	candidates := []*librariesindex.Library{}
+	for _, lib := range lm.Index.Libraries {
+		if lib.Latest == nil {
+			continue
+		}
+		for _, provided := range lib.Latest.ProvidesIncludes {
+			if provided == include {
+				candidates = append(candidates, lib)
+				break
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	header := simplifyName(strings.TrimSuffix(include, filepath.Ext(include)))
+	sort.Slice(candidates, func(i, j int) bool {
+		si := includeMatchScore(candidates[i].Name, header)
+		sj := includeMatchScore(candidates[j].Name, header)
+		if si != sj {
+			return si > sj
+		}
+		if len(candidates[i].Name) != len(candidates[j].Name) {
+			return len(candidates[i].Name) < len(candidates[j].Name)
+		}
+		return candidates[i].Name < candidates[j].Name
+	})
+	return candidates[0]
+}
+
+func simplifyName(name string) string {
+	return strings.ToLower(utils.SanitizeName(name))
+}
+
+// includeMatchScore rates how much the name of a library matches the
+// (simplified) name of a header
+func includeMatchScore(libName, header string) int {
+	name := simplifyName(libName)
+	switch {
+	case name == header:
+		return 3
+	case strings.HasPrefix(name, header):
+		return 2
+	case strings.Contains(name, header):
+		return 1
+	}
+	return 0
+}
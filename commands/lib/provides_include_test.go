@@ -0,0 +1,47 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package lib
+
+import (
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/libraries/librariesindex"
+	"github.com/arduino/arduino-cli/arduino/libraries/librariesmanager"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLibraryProvidingInclude(t *testing.T) {
+	index := &librariesindex.Index{Libraries: map[string]*librariesindex.Library{}}
+	addLib := func(name string, includes ...string) {
+		lib := &librariesindex.Library{Name: name, Index: index}
+		lib.Latest = &librariesindex.Release{Library: lib, ProvidesIncludes: includes}
+		index.Libraries[name] = lib
+	}
+	addLib("Servo", "Servo.h")
+	addLib("ServoEasing", "ServoEasing.h", "Servo.h")
+	addLib("Adafruit TiCoServo", "Adafruit_TiCoServo.h", "Servo.h")
+	addLib("Nothing", "Nothing.h")
+	addLib("Another Lib", "Lib.h")
+	addLib("Lib Extra", "Lib.h")
+
+	lm := librariesmanager.NewLibraryManager(nil, nil)
+	lm.Index = index
+
+	require.Equal(t, "Servo", libraryProvidingInclude(lm, "Servo.h").Name)
+	require.Equal(t, "ServoEasing", libraryProvidingInclude(lm, "ServoEasing.h").Name)
+	require.Equal(t, "Lib Extra", libraryProvidingInclude(lm, "Lib.h").Name)
+	require.Nil(t, libraryProvidingInclude(lm, "Missing.h"))
+}
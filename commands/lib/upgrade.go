@@ -21,6 +21,7 @@ import (
 	"github.com/arduino/arduino-cli/arduino/libraries/librariesmanager"
 	"github.com/arduino/arduino-cli/commands"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/spf13/viper"
 )
 
 // LibraryUpgradeAll upgrades all the available libraries
@@ -29,7 +30,7 @@ func LibraryUpgradeAll(instanceID int32, downloadCB commands.DownloadProgressCB,
 	// get the library manager
 	lm := commands.GetLibraryManager(instanceID)
 
-	if err := upgrade(lm, listLibraries(lm, true, true), downloadCB, taskCB); err != nil {
+	if err := upgrade(lm, commands.GetSettings(instanceID), listLibraries(lm, true, true), downloadCB, taskCB); err != nil {
 		return err
 	}
 
@@ -51,16 +52,16 @@ func LibraryUpgrade(instanceID int32, libraryNames []string, downloadCB commands
 	libs := filterByName(listLibraries(lm, true, true), libraryNames)
 
 	// do it
-	return upgrade(lm, libs, downloadCB, taskCB)
+	return upgrade(lm, commands.GetSettings(instanceID), libs, downloadCB, taskCB)
 }
 
-func upgrade(lm *librariesmanager.LibrariesManager, libs []*installedLib, downloadCB commands.DownloadProgressCB,
+func upgrade(lm *librariesmanager.LibrariesManager, settings *viper.Viper, libs []*installedLib, downloadCB commands.DownloadProgressCB,
 	taskCB commands.TaskProgressCB) error {
 
 	// Go through the list and download them
 
 	for _, lib := range libs {
-		if err := downloadLibrary(lm, lib.Available, downloadCB, taskCB); err != nil {
+		if err := downloadLibrary(lm, settings, lib.Available, downloadCB, taskCB); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,204 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package mirror
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/arduino/cores/packageindex"
+	"github.com/arduino/arduino-cli/arduino/cores/packagemanager"
+	"github.com/arduino/arduino-cli/arduino/libraries/librariesindex"
+	"github.com/arduino/arduino-cli/arduino/resources"
+	"github.com/arduino/arduino-cli/commands"
+	"github.com/arduino/go-paths-helper"
+	"github.com/spf13/viper"
+)
+
+// PackageIndexName is the name of the package index of a mirror
+const PackageIndexName = "package_mirror_index.json"
+
+// LibraryIndexName is the name of the library index of a mirror
+const LibraryIndexName = "library_index.json"
+
+// Options selects the content of a mirror
+type Options struct {
+	Platforms []*packagemanager.PlatformReference
+	Libraries []*librariesindex.Reference
+	// AllHosts adds the tools for all the operating systems, instead of only
+	// the ones for the current one
+	AllHosts bool
+	// BaseURL is the URL where the mirror is served, by default the file://
+	// URL of the mirror folder
+	BaseURL string
+}
+
+// Result describes the content of a mirror
+type Result struct {
+	PackageIndex *paths.Path
+	LibraryIndex *paths.Path
+	Platforms    []*cores.PlatformRelease
+	Tools        []*cores.ToolRelease
+	Libraries    []*librariesindex.Release
+}
+
+// Create downloads the selected platforms, with their tools, and libraries,
+// with their dependencies, to dir and writes the package and library indexes
+// listing them. The indexes use the same layout of the downloads folder, so
+// the archives are found under BaseURL/packages and BaseURL/libraries. The
+// downloads use the network configuration in settings.
+func Create(pm *packagemanager.PackageManager, settings *viper.Viper, libraryIndex *librariesindex.Index, dir *paths.Path, opts *Options, downloadCB commands.DownloadProgressCB) (*Result, error) {
+	dir, err := dir.Abs()
+	if err != nil {
+		return nil, fmt.Errorf("invalid mirror folder: %s", err)
+	}
+	if err := dir.MkdirAll(); err != nil {
+		return nil, fmt.Errorf("creating mirror folder: %s", err)
+	}
+	baseURL := strings.TrimSuffix(opts.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = FileURL(dir)
+	}
+	archiveURL := func(resource *resources.DownloadResource) string {
+		return baseURL + "/" + resource.CachePath + "/" + url.PathEscape(resource.ArchiveFileName)
+	}
+
+	res := &Result{}
+	selectedTools := map[*cores.ToolRelease]bool{}
+	for _, ref := range opts.Platforms {
+		platform, tools, err := pm.FindPlatformReleaseDependencies(ref)
+		if err != nil {
+			return nil, err
+		}
+		res.Platforms = append(res.Platforms, platform)
+		for _, tool := range tools {
+			if !selectedTools[tool] {
+				selectedTools[tool] = true
+				res.Tools = append(res.Tools, tool)
+			}
+		}
+	}
+	selectedLibraries := map[*librariesindex.Release]bool{}
+	for _, ref := range opts.Libraries {
+		if libraryIndex == nil {
+			return nil, fmt.Errorf("library index not loaded")
+		}
+		release := libraryIndex.FindRelease(ref)
+		if release == nil {
+			return nil, fmt.Errorf("library %s not found", ref)
+		}
+		deps := libraryIndex.ResolveDependencies(release)
+		if deps == nil {
+			return nil, fmt.Errorf("no valid dependencies solution found for %s", ref)
+		}
+		for _, dep := range deps {
+			if !selectedLibraries[dep] {
+				selectedLibraries[dep] = true
+				res.Libraries = append(res.Libraries, dep)
+			}
+		}
+	}
+
+	compatible := map[*resources.DownloadResource]bool{}
+	for _, tool := range res.Tools {
+		if resource := tool.GetCompatibleFlavour(); resource != nil {
+			compatible[resource] = true
+		}
+	}
+	flavourFilter := func(flavour *cores.Flavor) bool {
+		return opts.AllHosts || compatible[flavour.Resource]
+	}
+
+	// Download the archives
+	config, err := commands.GetDownloaderConfig(settings)
+	if err != nil {
+		return nil, err
+	}
+	download := func(resource *resources.DownloadResource) error {
+		d, err := resource.Download(dir, config)
+		if err != nil {
+			return fmt.Errorf("downloading %s: %s", resource.ArchiveFileName, err)
+		}
+		if err := commands.Download(d, resource.ArchiveFileName, downloadCB); err != nil {
+			return fmt.Errorf("downloading %s: %s", resource.ArchiveFileName, err)
+		}
+		if ok, err := resource.TestLocalArchiveIntegrity(dir); err != nil || !ok {
+			return fmt.Errorf("archive %s is corrupted", resource.ArchiveFileName)
+		}
+		return nil
+	}
+	for _, platform := range res.Platforms {
+		if err := download(platform.Resource); err != nil {
+			return nil, err
+		}
+	}
+	for _, tool := range res.Tools {
+		found := false
+		for _, flavour := range tool.Flavors {
+			if !flavourFilter(flavour) {
+				continue
+			}
+			found = true
+			if err := download(flavour.Resource); err != nil {
+				return nil, err
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("tool %s is not available for the current OS", tool)
+		}
+	}
+	for _, library := range res.Libraries {
+		if err := download(library.Resource); err != nil {
+			return nil, err
+		}
+	}
+
+	// Write the indexes
+	if len(res.Platforms) > 0 {
+		data, err := packageindex.MarshalMirrorIndex(res.Platforms, res.Tools, flavourFilter, archiveURL)
+		if err != nil {
+			return nil, fmt.Errorf("creating package index: %s", err)
+		}
+		res.PackageIndex = dir.Join(PackageIndexName)
+		if err := res.PackageIndex.WriteFile(data); err != nil {
+			return nil, fmt.Errorf("writing package index: %s", err)
+		}
+	}
+	if len(res.Libraries) > 0 {
+		data, err := librariesindex.MarshalMirrorIndex(res.Libraries, archiveURL)
+		if err != nil {
+			return nil, fmt.Errorf("creating library index: %s", err)
+		}
+		res.LibraryIndex = dir.Join(LibraryIndexName)
+		if err := res.LibraryIndex.WriteFile(data); err != nil {
+			return nil, fmt.Errorf("writing library index: %s", err)
+		}
+	}
+	return res, nil
+}
+
+// FileURL returns the file:// URL of the given absolute path
+func FileURL(path *paths.Path) string {
+	p := filepath.ToSlash(path.String())
+	if !strings.HasPrefix(p, "/") {
+		// Windows drive letter
+		p = "/" + p
+	}
+	return "file://" + p
+}
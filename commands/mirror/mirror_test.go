@@ -0,0 +1,111 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package mirror
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/arduino/cores/packagemanager"
+	"github.com/arduino/arduino-cli/arduino/libraries/librariesindex"
+	"github.com/arduino/arduino-cli/arduino/resources"
+	"github.com/arduino/arduino-cli/configuration"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+	semver "go.bug.st/relaxed-semver"
+)
+
+func TestCreate(t *testing.T) {
+	settings := configuration.Init("")
+	tmp, err := paths.MkTempDir("", "mirror_test")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+
+	// The upstream archives are served with file:// URLs
+	upstream := tmp.Join("upstream")
+	require.NoError(t, upstream.MkdirAll())
+	newResource := func(name, cachePath string) *resources.DownloadResource {
+		archive := upstream.Join(name)
+		require.NoError(t, archive.WriteFile([]byte(name)))
+		resource, err := resources.NewDownloadResource(archive, FileURL(archive))
+		require.NoError(t, err)
+		resource.CachePath = cachePath
+		return resource
+	}
+
+	pm := packagemanager.NewPackageManager(tmp, tmp, tmp, tmp)
+	pkg := pm.Packages.GetOrCreatePackage("test")
+	pkg.Maintainer = "Test"
+	release := pkg.GetOrCreatePlatform("avr").GetOrCreateRelease(semver.MustParse("1.0.0"))
+	release.Platform.Name = "Test AVR Boards"
+	release.Resource = newResource("test-avr-1.0.0.zip", "packages")
+	release.Dependencies = cores.ToolDependencies{
+		{ToolPackager: "test", ToolName: "gcc", ToolVersion: semver.ParseRelaxed("7.3.0")},
+	}
+	tool := pkg.GetOrCreateTool("gcc").GetOrCreateRelease(semver.ParseRelaxed("7.3.0"))
+	tool.Flavors = []*cores.Flavor{
+		{OS: "x86_64-pc-linux-gnu", Resource: newResource("gcc-7.3.0-linux.tar.bz2", "packages")},
+		{OS: "i686-mingw32", Resource: newResource("gcc-7.3.0-windows.zip", "packages")},
+	}
+
+	library := &librariesindex.Library{Name: "Servo", Releases: map[string]*librariesindex.Release{}}
+	libraryRelease := &librariesindex.Release{
+		Version:  semver.MustParse("1.1.8"),
+		Resource: newResource("Servo-1.1.8.zip", "libraries"),
+		Library:  library,
+	}
+	library.Releases["1.1.8"] = libraryRelease
+	library.Latest = libraryRelease
+	libraryIndex := &librariesindex.Index{Libraries: map[string]*librariesindex.Library{"Servo": library}}
+
+	mirrorDir := tmp.Join("mirror")
+	res, err := Create(pm, settings, libraryIndex, mirrorDir, &Options{
+		Platforms: []*packagemanager.PlatformReference{{Package: "test", PlatformArchitecture: "avr"}},
+		Libraries: []*librariesindex.Reference{{Name: "Servo"}},
+		AllHosts:  true,
+		BaseURL:   "https://arduino.example.com/mirror/",
+	}, func(*rpc.DownloadProgress) {})
+	require.NoError(t, err)
+	require.Equal(t, []*cores.PlatformRelease{release}, res.Platforms)
+	require.Equal(t, []*cores.ToolRelease{tool}, res.Tools)
+	require.Equal(t, []*librariesindex.Release{libraryRelease}, res.Libraries)
+
+	for _, name := range []string{"packages/test-avr-1.0.0.zip", "packages/gcc-7.3.0-linux.tar.bz2", "packages/gcc-7.3.0-windows.zip", "libraries/Servo-1.1.8.zip"} {
+		require.FileExists(t, mirrorDir.Join(name).String())
+	}
+
+	var packageIndex struct {
+		Packages []struct {
+			Name      string
+			Platforms []struct{ URL string }
+			Tools     []struct {
+				Systems []struct{ URL string }
+			}
+		}
+	}
+	data, err := res.PackageIndex.ReadFile()
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &packageIndex))
+	require.Len(t, packageIndex.Packages, 1)
+	require.Equal(t, "https://arduino.example.com/mirror/packages/test-avr-1.0.0.zip", packageIndex.Packages[0].Platforms[0].URL)
+	require.Len(t, packageIndex.Packages[0].Tools[0].Systems, 2)
+
+	loaded, err := librariesindex.LoadIndex(res.LibraryIndex)
+	require.NoError(t, err)
+	require.Equal(t, "https://arduino.example.com/mirror/libraries/Servo-1.1.8.zip", loaded.Libraries["Servo"].Latest.Resource.URL)
+}
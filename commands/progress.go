@@ -22,3 +22,6 @@ type DownloadProgressCB func(curr *rpc.DownloadProgress)
 
 // TaskProgressCB is a callback to receive progress messages
 type TaskProgressCB func(msg *rpc.TaskProgress)
+
+// UploadProgressCB is a callback to get updates on the upload progress
+type UploadProgressCB func(curr *rpc.UploadProgress)
@@ -0,0 +1,158 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package run
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/arduino/cores/packagemanager"
+	"github.com/arduino/arduino-cli/arduino/simulator"
+	"github.com/arduino/arduino-cli/commands/compile"
+	"github.com/arduino/arduino-cli/executils"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	paths "github.com/arduino/go-paths-helper"
+	properties "github.com/arduino/go-properties-orderedmap"
+	"github.com/sirupsen/logrus"
+)
+
+// Request contains the parameters to run a sketch in a simulator
+type Request struct {
+	Instance   *rpc.Instance
+	Fqbn       string
+	SketchPath *paths.Path
+	// Simulator is the simulator to use, if empty it's chosen from the board
+	// properties and the simulators available
+	Simulator simulator.Backend
+	// Script is the Renode script creating the simulated machine
+	Script  *paths.Path
+	Verbose bool
+}
+
+// Simulation is a sketch running in a simulator. Reading and writing a
+// Simulation receives and sends data through the simulated UART, so it can be
+// used as a monitors.Monitor.
+type Simulation struct {
+	// Command is the command line of the simulator
+	Command  []string
+	process  *executils.Process
+	stdin    io.WriteCloser
+	stdout   io.ReadCloser
+	buildDir *paths.Path
+}
+
+// Start builds the sketch and starts the simulator. The build output is
+// written to outStream and errStream.
+func Start(ctx context.Context, pm *packagemanager.PackageManager, req *Request, outStream, errStream io.Writer) (*Simulation, error) {
+	if req.Fqbn == "" {
+		return nil, fmt.Errorf("no Fully Qualified Board Name provided")
+	}
+	fqbn, err := cores.ParseFQBN(req.Fqbn)
+	if err != nil {
+		return nil, fmt.Errorf("incorrect FQBN: %s", err)
+	}
+	_, boardPlatform, _, boardProperties, _, err := pm.ResolveFQBN(fqbn)
+	if err != nil {
+		return nil, fmt.Errorf("incorrect FQBN: %s", err)
+	}
+	props := properties.NewMap()
+	props.Merge(boardPlatform.Properties)
+	props.Merge(boardPlatform.RuntimeProperties())
+	props.Merge(boardProperties)
+
+	sketchPath, err := req.SketchPath.Abs()
+	if err != nil {
+		return nil, fmt.Errorf("invalid sketch path: %s", err)
+	}
+	if !sketchPath.IsDir() {
+		sketchPath = sketchPath.Parent()
+	}
+
+	buildDir, err := paths.MkTempDir("", "arduino-run-")
+	if err != nil {
+		return nil, fmt.Errorf("creating build folder: %s", err)
+	}
+	sim := &Simulation{buildDir: buildDir}
+	started := false
+	defer func() {
+		if !started {
+			buildDir.RemoveAll()
+		}
+	}()
+
+	// Check the simulator before the build, the elf path is known in advance
+	elf := buildDir.Join(sketchPath.Base() + ".ino.elf")
+	sim.Command, err = simulator.Command(req.Simulator, boardPlatform.Platform.Architecture, props, elf, req.Script)
+	if err != nil {
+		return nil, err
+	}
+
+	compileReq := &rpc.CompileRequest{
+		Instance:   req.Instance,
+		Fqbn:       req.Fqbn,
+		SketchPath: sketchPath.String(),
+		BuildPath:  buildDir.String(),
+		Verbose:    req.Verbose,
+	}
+	if _, err := compile.Compile(ctx, compileReq, outStream, errStream, nil, false); err != nil {
+		return nil, err
+	}
+	if !elf.Exist() {
+		return nil, fmt.Errorf("compiled firmware not found: %s", elf)
+	}
+
+	logrus.Infof("Starting simulator: %s", strings.Join(sim.Command, " "))
+	if sim.process, err = executils.NewProcess(sim.Command...); err != nil {
+		return nil, err
+	}
+	if sim.stdin, err = sim.process.StdinPipe(); err != nil {
+		return nil, err
+	}
+	if sim.stdout, err = sim.process.StdoutPipe(); err != nil {
+		return nil, err
+	}
+	sim.process.RedirectStderrTo(errStream)
+	if err := sim.process.Start(); err != nil {
+		return nil, fmt.Errorf("starting simulator: %s", err)
+	}
+	started = true
+	return sim, nil
+}
+
+// Read receives data from the simulated UART
+func (s *Simulation) Read(data []byte) (int, error) {
+	return s.stdout.Read(data)
+}
+
+// Write sends data to the simulated UART
+func (s *Simulation) Write(data []byte) (int, error) {
+	return s.stdin.Write(data)
+}
+
+// Wait waits for the simulator to exit
+func (s *Simulation) Wait() error {
+	return s.process.Wait()
+}
+
+// Close stops the simulator and removes the build folder
+func (s *Simulation) Close() error {
+	s.stdin.Close()
+	_ = s.process.Kill()
+	return s.buildDir.RemoveAll()
+}
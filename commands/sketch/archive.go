@@ -18,14 +18,21 @@ package sketch
 import (
 	"archive/zip"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"path/filepath"
 	"strings"
 
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/arduino/cores/packagemanager"
 	"github.com/arduino/arduino-cli/arduino/sketches"
+	"github.com/arduino/arduino-cli/commands"
+	"github.com/arduino/arduino-cli/commands/compile"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
 	paths "github.com/arduino/go-paths-helper"
+	"github.com/codeclysm/extract/v3"
 )
 
 // ArchiveSketch FIXMEDOC
@@ -67,6 +74,17 @@ func ArchiveSketch(ctx context.Context, req *rpc.ArchiveSketchRequest) (*rpc.Arc
 		return nil, fmt.Errorf("archive already exists")
 	}
 
+	var deps *sketchDependencies
+	if req.GetIncludeLibraries() || req.GetIncludeProfile() {
+		if req.GetIncludeLibraries() && sketchPath.Join("libraries").Exist() {
+			return nil, fmt.Errorf("the sketch already contains a libraries folder")
+		}
+		deps, err = resolveSketchDependencies(ctx, req, sketch)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	filesToZip, err := sketchPath.ReadDirRecursive()
 	if err != nil {
 		return nil, fmt.Errorf("Error retrieving sketch files: %v", err)
@@ -96,6 +114,11 @@ func ArchiveSketch(ctx context.Context, req *rpc.ArchiveSketchRequest) (*rpc.Arc
 			}
 		}
 
+		// The pinned sketch.json is added below
+		if deps != nil && req.GetIncludeProfile() && f.EqualsTo(sketchPath.Join("sketch.json")) {
+			continue
+		}
+
 		// We get the parent path since we want the archive to unpack as a folder.
 		// If we don't do this the archive would contain all the sketch files as top level.
 		err = addFileToSketchArchive(zipWriter, f, sketchPath.Parent())
@@ -104,11 +127,181 @@ func ArchiveSketch(ctx context.Context, req *rpc.ArchiveSketchRequest) (*rpc.Arc
 		}
 	}
 
+	if req.GetIncludeLibraries() {
+		for _, lib := range deps.libraries {
+			libPath := paths.New(lib.GetInstallDir())
+			files, err := libPath.ReadDirRecursive()
+			if err != nil {
+				return nil, fmt.Errorf("Error retrieving library files: %v", err)
+			}
+			files.FilterOutDirs()
+			for _, f := range files {
+				// The libraries are added in the libraries folder of the sketch
+				if err := addFileToSketchArchiveAs(zipWriter, f, libPath.Parent(), sketchName+"/libraries"); err != nil {
+					return nil, fmt.Errorf("Error adding file to archive: %v", err)
+				}
+			}
+		}
+	}
+
+	if req.GetIncludeProfile() {
+		metadata := *sketch.Metadata
+		metadata.CPU.Fqbn = deps.fqbn
+		metadata.Platform = deps.platform
+		metadata.Libraries = []string{}
+		for _, lib := range deps.libraries {
+			ref := lib.GetName()
+			if lib.GetVersion() != "" {
+				ref += "@" + lib.GetVersion()
+			}
+			metadata.Libraries = append(metadata.Libraries, ref)
+		}
+		data, err := json.MarshalIndent(&metadata, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("Error encoding sketch metadata: %v", err)
+		}
+		writer, err := zipWriter.Create(sketchName + "/sketch.json")
+		if err != nil {
+			return nil, fmt.Errorf("Error adding file to archive: %v", err)
+		}
+		if _, err := writer.Write(data); err != nil {
+			return nil, fmt.Errorf("Error adding file to archive: %v", err)
+		}
+	}
+
 	return &rpc.ArchiveSketchResponse{}, nil
 }
 
+// sketchDependencies are the board, the platform and the libraries, except
+// the ones bundled with the platform, used to compile a sketch
+type sketchDependencies struct {
+	fqbn      string
+	platform  string
+	libraries []*rpc.Library
+}
+
+// resolveSketchDependencies finds the libraries used by the sketch running
+// the library discovery of the builder, without compiling the sketch
+func resolveSketchDependencies(ctx context.Context, req *rpc.ArchiveSketchRequest, sketch *sketches.Sketch) (*sketchDependencies, error) {
+	pm := commands.GetPackageManager(req.GetInstance().GetId())
+	if pm == nil {
+		return nil, fmt.Errorf("invalid instance")
+	}
+	fqbnIn := req.GetFqbn()
+	if fqbnIn == "" {
+		fqbnIn = sketch.Metadata.CPU.Fqbn
+	}
+	if fqbnIn == "" {
+		return nil, fmt.Errorf("no FQBN provided")
+	}
+	fqbn, err := cores.ParseFQBN(fqbnIn)
+	if err != nil {
+		return nil, fmt.Errorf("incorrect FQBN: %s", err)
+	}
+
+	buildPath, err := paths.MkTempDir("", "arduino-cli-archive")
+	if err != nil {
+		return nil, fmt.Errorf("creating build folder: %s", err)
+	}
+	defer buildPath.RemoveAll()
+	res, err := compile.Compile(ctx, &rpc.CompileRequest{
+		Instance:                      req.GetInstance(),
+		Fqbn:                          fqbn.String(),
+		SketchPath:                    sketch.FullPath.String(),
+		BuildPath:                     buildPath.String(),
+		CreateCompilationDatabaseOnly: true,
+	}, ioutil.Discard, ioutil.Discard, nil, false)
+	if err != nil {
+		return nil, fmt.Errorf("finding the libraries used by the sketch: %s", err)
+	}
+
+	deps := &sketchDependencies{fqbn: fqbn.String()}
+	// The platform has already been found by the compile
+	platform := pm.FindPlatform(&packagemanager.PlatformReference{
+		Package:              fqbn.Package,
+		PlatformArchitecture: fqbn.PlatformArch,
+	})
+	deps.platform = fqbn.Package + ":" + fqbn.PlatformArch
+	if release := pm.GetInstalledPlatformRelease(platform); release != nil {
+		deps.platform += "@" + release.Version.String()
+	}
+	for _, lib := range res.GetUsedLibraries() {
+		switch lib.GetLocation() {
+		case rpc.LibraryLocation_LIBRARY_LOCATION_PLATFORM_BUILTIN, rpc.LibraryLocation_LIBRARY_LOCATION_REFERENCED_PLATFORM_BUILTIN:
+			// installed with the platform
+			continue
+		}
+		deps.libraries = append(deps.libraries, lib)
+	}
+	return deps, nil
+}
+
+// ExtractSketchArchive extracts an archive created by ArchiveSketch in the
+// given folder and returns the path of the sketch
+func ExtractSketchArchive(ctx context.Context, archivePath, destination *paths.Path) (*paths.Path, error) {
+	file, err := archivePath.Open()
+	if err != nil {
+		return nil, fmt.Errorf("opening archive: %s", err)
+	}
+	defer file.Close()
+	if err := extract.Archive(ctx, file, destination.String(), nil); err != nil {
+		return nil, fmt.Errorf("extracting archive: %s", err)
+	}
+
+	dirs, err := destination.ReadDir()
+	if err != nil {
+		return nil, fmt.Errorf("extracting archive: %s", err)
+	}
+	dirs.FilterDirs()
+	dirs.FilterOutHiddenFiles()
+	if len(dirs) != 1 {
+		return nil, fmt.Errorf("the archive must contain exactly one sketch folder")
+	}
+	sketch, err := sketches.NewSketchFromPath(dirs[0])
+	if err != nil {
+		return nil, fmt.Errorf("the archive doesn't contain a valid sketch: %s", err)
+	}
+	return sketch.FullPath, nil
+}
+
+// CheckArchivePlatform returns an error if the platform pinned in the
+// sketch.json of the sketch, as written by ArchiveSketch with the
+// IncludeProfile option, is not installed with the same version
+func CheckArchivePlatform(pm *packagemanager.PackageManager, sketchPath *paths.Path) error {
+	sketch, err := sketches.NewSketchFromPath(sketchPath)
+	if err != nil {
+		return err
+	}
+	pinned := sketch.Metadata.Platform
+	if pinned == "" {
+		return nil
+	}
+	ref := strings.SplitN(pinned, "@", 2)
+	split := strings.Split(ref[0], ":")
+	if len(split) != 2 {
+		return fmt.Errorf("invalid platform %s in sketch.json", pinned)
+	}
+	var installed *cores.PlatformRelease
+	if platform := pm.FindPlatform(&packagemanager.PlatformReference{Package: split[0], PlatformArchitecture: split[1]}); platform != nil {
+		installed = pm.GetInstalledPlatformRelease(platform)
+	}
+	if installed == nil {
+		return fmt.Errorf("the sketch requires the platform %s, which is not installed", pinned)
+	}
+	if len(ref) == 2 && (installed.Version == nil || installed.Version.String() != ref[1]) {
+		return fmt.Errorf("the sketch requires the platform %s, but %s is installed", pinned, installed)
+	}
+	return nil
+}
+
 // Adds a single file to an existing zip file
 func addFileToSketchArchive(zipWriter *zip.Writer, filePath, sketchPath *paths.Path) error {
+	return addFileToSketchArchiveAs(zipWriter, filePath, sketchPath, "")
+}
+
+// Adds a single file to an existing zip file, with its path relative to
+// basePath prefixed by the given folder
+func addFileToSketchArchiveAs(zipWriter *zip.Writer, filePath, basePath *paths.Path, folder string) error {
 	f, err := filePath.Open()
 	if err != nil {
 		return err
@@ -125,12 +318,15 @@ func addFileToSketchArchive(zipWriter *zip.Writer, filePath, sketchPath *paths.P
 		return err
 	}
 
-	filePath, err = sketchPath.RelTo(filePath)
+	filePath, err = basePath.RelTo(filePath)
 	if err != nil {
 		return err
 	}
 
-	header.Name = filePath.String()
+	header.Name = filepath.ToSlash(filePath.String())
+	if folder != "" {
+		header.Name = folder + "/" + header.Name
+	}
 	header.Method = zip.Deflate
 
 	writer, err := zipWriter.CreateHeader(header)
@@ -0,0 +1,94 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package sketch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/cores/packagemanager"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	paths "github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveAndExtractSketch(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "archive_sketch")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+
+	sketchPath := tmp.Join("MySketch")
+	for name, content := range map[string]string{
+		"MySketch.ino":       "void setup() {}\nvoid loop() {}\n",
+		"src/util.cpp":       "void util() {}\n",
+		"build/MySketch.hex": ":00000001FF\n",
+	} {
+		file := sketchPath.Join(name)
+		require.NoError(t, file.Parent().MkdirAll())
+		require.NoError(t, file.WriteFile([]byte(content)))
+	}
+
+	archivePath := tmp.Join("MySketch.zip")
+	_, err = ArchiveSketch(context.Background(), &rpc.ArchiveSketchRequest{
+		SketchPath:  sketchPath.String(),
+		ArchivePath: archivePath.String(),
+	})
+	require.NoError(t, err)
+
+	dest := tmp.Join("extracted")
+	extracted, err := ExtractSketchArchive(context.Background(), archivePath, dest)
+	require.NoError(t, err)
+	require.Equal(t, dest.Join("MySketch").String(), extracted.String())
+	require.True(t, extracted.Join("src", "util.cpp").Exist())
+	require.False(t, extracted.Join("build").Exist())
+
+	// The libraries and the profile are resolved with an instance
+	_, err = ArchiveSketch(context.Background(), &rpc.ArchiveSketchRequest{
+		SketchPath:     sketchPath.String(),
+		ArchivePath:    tmp.Join("Pinned.zip").String(),
+		IncludeProfile: true,
+	})
+	require.Error(t, err)
+}
+
+func TestCheckArchivePlatform(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "archive_platform")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+
+	hardware := tmp.Join("hardware")
+	platformDir := hardware.Join("test", "avr")
+	require.NoError(t, platformDir.MkdirAll())
+	require.NoError(t, platformDir.Join("platform.txt").WriteFile([]byte("name=Test\nversion=1.2.3\n")))
+	require.NoError(t, platformDir.Join("boards.txt").WriteFile([]byte("uno.name=Uno\n")))
+	pm := packagemanager.NewPackageManager(nil, nil, nil, nil)
+	pm.LoadHardwareFromDirectory(hardware)
+
+	sketchPath := tmp.Join("MySketch")
+	require.NoError(t, sketchPath.MkdirAll())
+	require.NoError(t, sketchPath.Join("MySketch.ino").WriteFile([]byte("void setup() {}\nvoid loop() {}\n")))
+	check := func(platform string) error {
+		require.NoError(t, sketchPath.Join("sketch.json").WriteFile([]byte(`{"platform": "`+platform+`"}`)))
+		return CheckArchivePlatform(pm, sketchPath)
+	}
+
+	require.NoError(t, check(""))
+	require.NoError(t, check("test:avr"))
+	require.NoError(t, check("test:avr@1.2.3"))
+	require.EqualError(t, check("test:avr@1.2.4"), "the sketch requires the platform test:avr@1.2.4, but test:avr@1.2.3 is installed")
+	require.EqualError(t, check("test:samd@1.0.0"), "the sketch requires the platform test:samd@1.0.0, which is not installed")
+	require.Error(t, check("avr@1.2.3"))
+}
@@ -0,0 +1,158 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package sketch
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/arduino/sketches"
+	"github.com/arduino/arduino-cli/commands"
+	"github.com/arduino/arduino-cli/httpclient"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	paths "github.com/arduino/go-paths-helper"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/src-d/go-git.v4"
+)
+
+// CloneSketch clones a git repository containing a sketch and initializes
+// its sketch.json file if missing
+func CloneSketch(ctx context.Context, req *rpc.CloneSketchRequest) (*rpc.CloneSketchResponse, error) {
+	settings := commands.GetSettings(req.GetInstance().GetId())
+	if settings == nil {
+		return nil, fmt.Errorf("invalid instance")
+	}
+	if req.GetUrl() == "" {
+		return nil, fmt.Errorf("missing repository URL")
+	}
+	if req.GetFqbn() != "" {
+		if _, err := cores.ParseFQBN(req.GetFqbn()); err != nil {
+			return nil, fmt.Errorf("incorrect FQBN: %s", err)
+		}
+	}
+
+	destination := paths.New(req.GetDestination())
+	if destination == nil {
+		name, err := repositoryName(req.GetUrl())
+		if err != nil {
+			return nil, err
+		}
+		destination = paths.New(name)
+	}
+	destination, err := destination.Abs()
+	if err != nil {
+		return nil, fmt.Errorf("getting destination path: %s", err)
+	}
+	// An empty folder given by the user is kept if the clone fails
+	created := destination.NotExist()
+	if !created {
+		files, err := destination.ReadDir()
+		if err != nil || len(files) > 0 {
+			return nil, fmt.Errorf("destination %s already exists and is not an empty folder", destination)
+		}
+	}
+	cleanup := func() {
+		if created {
+			destination.RemoveAll()
+			return
+		}
+		if files, err := destination.ReadDir(); err == nil {
+			for _, file := range files {
+				file.RemoveAll()
+			}
+		}
+	}
+
+	if u, err := url.Parse(req.GetUrl()); (err != nil || (u.Scheme != "" && u.Scheme != "file")) && httpclient.IsOfflineFromSettings(settings) {
+		return nil, httpclient.ErrOffline
+	}
+
+	logrus.
+		WithField("url", req.GetUrl()).
+		WithField("destination", destination).
+		Trace("Cloning sketch")
+	if _, err := git.PlainCloneContext(ctx, destination.String(), false, &git.CloneOptions{URL: req.GetUrl()}); err != nil {
+		cleanup()
+		return nil, fmt.Errorf("cloning repository: %s", err)
+	}
+
+	res, err := initClonedSketch(destination, req.GetFqbn())
+	if err != nil {
+		// Clean up the destination since this is not a valid sketch
+		cleanup()
+		return nil, err
+	}
+	return res, nil
+}
+
+// initClonedSketch validates the sketch in the given folder and writes its
+// sketch.json file if missing
+func initClonedSketch(sketchPath *paths.Path, fqbn string) (*rpc.CloneSketchResponse, error) {
+	sketch, err := sketches.NewSketchFromPath(sketchPath)
+	if err != nil {
+		return nil, fmt.Errorf("the repository doesn't contain a valid sketch: %s", err)
+	}
+
+	if sketch.FullPath.Join("sketch.json").Exist() {
+		if err := sketch.ImportMetadata(); err != nil {
+			return nil, err
+		}
+		if sketch.Metadata.CPU.Fqbn != "" {
+			if _, err := cores.ParseFQBN(sketch.Metadata.CPU.Fqbn); err != nil {
+				return nil, fmt.Errorf("incorrect FQBN in sketch.json: %s", err)
+			}
+		}
+	}
+	if sketch.Metadata.CPU.Fqbn == "" && fqbn != "" {
+		sketch.Metadata.CPU.Fqbn = fqbn
+		if err := sketch.ExportMetadata(); err != nil {
+			return nil, err
+		}
+	}
+
+	platform := sketch.Metadata.Platform
+	if platform == "" && sketch.Metadata.CPU.Fqbn != "" {
+		// The FQBN has already been validated
+		parsed, _ := cores.ParseFQBN(sketch.Metadata.CPU.Fqbn)
+		platform = parsed.Package + ":" + parsed.PlatformArch
+	}
+	return &rpc.CloneSketchResponse{
+		SketchPath: sketch.FullPath.String(),
+		Fqbn:       sketch.Metadata.CPU.Fqbn,
+		Platform:   platform,
+		Libraries:  sketch.Metadata.Libraries,
+	}, nil
+}
+
+// repositoryName returns the name of the repository from its URL, without
+// the .git extension
+func repositoryName(gitURL string) (string, error) {
+	repoPath := gitURL
+	if !strings.HasPrefix(gitURL, "git@") {
+		if parsed, err := url.Parse(gitURL); err == nil {
+			repoPath = parsed.Path
+		}
+	}
+	repoPath = strings.TrimSuffix(strings.TrimRight(repoPath, "/"), ".git")
+	name := repoPath[strings.LastIndexAny(repoPath, "/:")+1:]
+	if name == "" {
+		return "", fmt.Errorf("invalid repository URL: %s", gitURL)
+	}
+	return name, nil
+}
@@ -0,0 +1,181 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package sketch
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/arduino/arduino-cli/commands"
+	"github.com/arduino/arduino-cli/httpclient"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	paths "github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// createRepository creates a git repository with the given files committed
+func createRepository(t *testing.T, dir *paths.Path, files map[string]string) {
+	repo, err := git.PlainInit(dir.String(), false)
+	require.NoError(t, err)
+	tree, err := repo.Worktree()
+	require.NoError(t, err)
+	for name, content := range files {
+		require.NoError(t, dir.Join(name).WriteFile([]byte(content)))
+		_, err := tree.Add(name)
+		require.NoError(t, err)
+	}
+	_, err = tree.Commit("initial commit", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+}
+
+// createInstance creates an instance with the folders in the given directory
+// and the given additional settings
+func createInstance(t *testing.T, dir *paths.Path, settings string) *rpc.Instance {
+	configFile := dir.Join("arduino-cli.yaml")
+	config := fmt.Sprintf("directories:\n  data: %q\n  downloads: %q\n  user: %q\n",
+		dir.Join("data").String(), dir.Join("downloads").String(), dir.Join("user").String())
+	require.NoError(t, configFile.WriteFile([]byte(config+settings)))
+	res, st := commands.Create(&rpc.CreateRequest{ConfigFile: configFile.String()})
+	require.Nil(t, st)
+	return res.GetInstance()
+}
+
+func TestCloneSketch(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "clone_sketch")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+	inst := createInstance(t, tmp, "")
+
+	sketchRepo := tmp.Join("MySketch")
+	require.NoError(t, sketchRepo.MkdirAll())
+	createRepository(t, sketchRepo, map[string]string{
+		"MySketch.ino": "void setup() {}\nvoid loop() {}\n",
+		"sketch.json":  `{"cpu":{"fqbn":"arduino:avr:uno"},"libraries":["Servo@1.1.8"]}`,
+	})
+
+	dest := tmp.Join("cloned", "MySketch")
+	res, err := CloneSketch(context.Background(), &rpc.CloneSketchRequest{
+		Instance:    inst,
+		Url:         sketchRepo.String(),
+		Destination: dest.String(),
+		Fqbn:        "arduino:samd:mkr1000",
+	})
+	require.NoError(t, err)
+	require.Equal(t, dest.String(), res.GetSketchPath())
+	require.Equal(t, "arduino:avr:uno", res.GetFqbn())
+	require.Equal(t, "arduino:avr", res.GetPlatform())
+	require.Equal(t, []string{"Servo@1.1.8"}, res.GetLibraries())
+	require.True(t, dest.Join(".git").IsDir())
+
+	// The destination must be empty
+	_, err = CloneSketch(context.Background(), &rpc.CloneSketchRequest{
+		Instance:    inst,
+		Url:         sketchRepo.String(),
+		Destination: dest.String(),
+	})
+	require.Error(t, err)
+
+	// The sketch.json is created if missing
+	noMetadataRepo := tmp.Join("NoMetadata")
+	require.NoError(t, noMetadataRepo.MkdirAll())
+	createRepository(t, noMetadataRepo, map[string]string{
+		"NoMetadata.ino": "void setup() {}\nvoid loop() {}\n",
+	})
+	dest = tmp.Join("cloned", "NoMetadata")
+	res, err = CloneSketch(context.Background(), &rpc.CloneSketchRequest{
+		Instance:    inst,
+		Url:         noMetadataRepo.String(),
+		Destination: dest.String(),
+		Fqbn:        "arduino:samd:mkr1000",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "arduino:samd:mkr1000", res.GetFqbn())
+	require.Equal(t, "arduino:samd", res.GetPlatform())
+	require.True(t, dest.Join("sketch.json").Exist())
+
+	// A repository without a sketch is removed after the clone
+	notSketchRepo := tmp.Join("NotSketch")
+	require.NoError(t, notSketchRepo.MkdirAll())
+	createRepository(t, notSketchRepo, map[string]string{
+		"README.md": "not a sketch",
+	})
+	dest = tmp.Join("cloned", "NotSketch")
+	_, err = CloneSketch(context.Background(), &rpc.CloneSketchRequest{
+		Instance:    inst,
+		Url:         notSketchRepo.String(),
+		Destination: dest.String(),
+	})
+	require.Error(t, err)
+	require.False(t, dest.Exist())
+
+	// An empty destination folder given by the user is emptied but kept
+	require.NoError(t, dest.MkdirAll())
+	_, err = CloneSketch(context.Background(), &rpc.CloneSketchRequest{
+		Instance:    inst,
+		Url:         notSketchRepo.String(),
+		Destination: dest.String(),
+	})
+	require.Error(t, err)
+	require.True(t, dest.IsDir())
+	files, err := dest.ReadDir()
+	require.NoError(t, err)
+	require.Empty(t, files)
+
+	// A valid instance is required
+	_, err = CloneSketch(context.Background(), &rpc.CloneSketchRequest{
+		Url:         sketchRepo.String(),
+		Destination: tmp.Join("cloned", "NoInstance").String(),
+	})
+	require.EqualError(t, err, "invalid instance")
+}
+
+func TestCloneSketchOffline(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "clone_sketch")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+
+	// The network settings of the instance are used
+	inst := createInstance(t, tmp, "network:\n  offline: true\n")
+	_, err = CloneSketch(context.Background(), &rpc.CloneSketchRequest{
+		Instance:    inst,
+		Url:         "https://github.com/user/MySketch.git",
+		Destination: tmp.Join("MySketch").String(),
+	})
+	require.Equal(t, httpclient.ErrOffline, err)
+}
+
+func TestRepositoryName(t *testing.T) {
+	for url, name := range map[string]string{
+		"https://github.com/user/MySketch.git":  "MySketch",
+		"https://github.com/user/MySketch":      "MySketch",
+		"https://github.com/user/MySketch.git/": "MySketch",
+		"git@github.com:user/MySketch.git":      "MySketch",
+		"git@github.com:MySketch.git":           "MySketch",
+		"/home/user/MySketch":                   "MySketch",
+	} {
+		res, err := repositoryName(url)
+		require.NoError(t, err, url)
+		require.Equal(t, name, res, url)
+	}
+	_, err := repositoryName("https://github.com/")
+	require.Error(t, err)
+}
@@ -0,0 +1,195 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package sketch
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/arduino/globals"
+	"github.com/arduino/arduino-cli/arduino/platformio"
+	"github.com/arduino/arduino-cli/arduino/sketches"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	paths "github.com/arduino/go-paths-helper"
+	"github.com/sirupsen/logrus"
+)
+
+// ImportSketch creates a sketch from a PlatformIO project: the sources are
+// copied in the sketch and the board and the libraries of the project are
+// written in its sketch.json file
+func ImportSketch(ctx context.Context, req *rpc.ImportSketchRequest) (*rpc.ImportSketchResponse, error) {
+	if req.GetFrom() != "platformio" {
+		return nil, fmt.Errorf("unsupported project format: %s", req.GetFrom())
+	}
+	if req.GetFqbn() != "" {
+		if _, err := cores.ParseFQBN(req.GetFqbn()); err != nil {
+			return nil, fmt.Errorf("incorrect FQBN: %s", err)
+		}
+	}
+
+	projectPath, err := paths.New(req.GetProjectPath()).Abs()
+	if err != nil {
+		return nil, fmt.Errorf("getting project path: %s", err)
+	}
+	project, err := platformio.LoadProject(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	env, err := project.Env(req.GetEnv())
+	if err != nil {
+		return nil, err
+	}
+
+	destination := paths.New(req.GetDestination())
+	if destination == nil {
+		destination = paths.New(projectPath.Base())
+	}
+	destination, err = destination.Abs()
+	if err != nil {
+		return nil, fmt.Errorf("getting destination path: %s", err)
+	}
+	if destination.Exist() {
+		files, err := destination.ReadDir()
+		if err != nil || len(files) > 0 {
+			return nil, fmt.Errorf("destination %s already exists and is not an empty folder", destination)
+		}
+	}
+
+	logrus.
+		WithField("project", projectPath).
+		WithField("env", env.Name).
+		WithField("destination", destination).
+		Trace("Importing PlatformIO project")
+	res := &rpc.ImportSketchResponse{SketchPath: destination.String()}
+	if err := importPlatformIOSources(project, destination, res); err != nil {
+		// Clean up the destination since the sketch is incomplete
+		destination.RemoveAll()
+		return nil, err
+	}
+
+	sketch, err := sketches.NewSketchFromPath(destination)
+	if err != nil {
+		destination.RemoveAll()
+		return nil, err
+	}
+	res.Fqbn = req.GetFqbn()
+	if res.Fqbn == "" {
+		if fqbn, ok := platformio.BoardFQBN(env.Board); ok {
+			res.Fqbn = fqbn
+		} else {
+			res.Warnings = append(res.Warnings, fmt.Sprintf("board %s of environment %s has no known FQBN", env.Board, env.Name))
+		}
+	}
+	if env.Framework != "" && env.Framework != "arduino" {
+		res.Warnings = append(res.Warnings, fmt.Sprintf("environment %s uses the %s framework instead of arduino", env.Name, env.Framework))
+	}
+	for _, dep := range env.LibDeps {
+		ref, err := platformio.LibraryReference(dep)
+		if err != nil {
+			res.Warnings = append(res.Warnings, err.Error())
+			continue
+		}
+		res.Libraries = append(res.Libraries, ref)
+	}
+	if res.Fqbn != "" {
+		// The FQBN has already been validated
+		parsed, _ := cores.ParseFQBN(res.Fqbn)
+		res.Platform = parsed.Package + ":" + parsed.PlatformArch
+	}
+
+	sketch.Metadata.CPU.Fqbn = res.Fqbn
+	sketch.Metadata.Libraries = res.Libraries
+	if err := sketch.ExportMetadata(); err != nil {
+		destination.RemoveAll()
+		return nil, err
+	}
+	return res, nil
+}
+
+// importPlatformIOSources copies the sources and the headers of the project
+// in the src folder of the sketch, that is compiled recursively, and the .ino
+// files in the sketch folder. The main sketch file is created if missing.
+func importPlatformIOSources(project *platformio.Project, sketchPath *paths.Path, res *rpc.ImportSketchResponse) error {
+	if !project.SrcDir.IsDir() {
+		return fmt.Errorf("source folder %s not found", project.SrcDir)
+	}
+	srcPath := sketchPath.Join("src")
+	if err := srcPath.MkdirAll(); err != nil {
+		return fmt.Errorf("creating sketch folder: %s", err)
+	}
+
+	mainFile := sketchPath.Join(sketchPath.Base() + globals.MainFileValidExtension)
+	sketchFiles := paths.NewPathList()
+	for _, dir := range []*paths.Path{project.SrcDir, project.IncludeDir} {
+		if !dir.IsDir() {
+			continue
+		}
+		files, err := dir.ReadDirRecursive()
+		if err != nil {
+			return fmt.Errorf("reading project sources: %s", err)
+		}
+		files.FilterOutDirs()
+		for _, file := range files {
+			rel, err := dir.RelTo(file)
+			if err != nil {
+				return fmt.Errorf("reading project sources: %s", err)
+			}
+			target := srcPath.JoinPath(rel)
+			if _, ok := globals.MainFileValidExtensions[file.Ext()]; ok && dir.EqualsTo(project.SrcDir) && rel.Parent().String() == "." {
+				sketchFiles.Add(file)
+				continue
+			}
+			if target.Exist() {
+				res.Warnings = append(res.Warnings, fmt.Sprintf("file %s overwritten by %s", rel, file))
+			}
+			if err := target.Parent().MkdirAll(); err != nil {
+				return fmt.Errorf("copying project sources: %s", err)
+			}
+			if err := file.CopyTo(target); err != nil {
+				return fmt.Errorf("copying project sources: %s", err)
+			}
+		}
+	}
+
+	for _, file := range sketchFiles {
+		target := sketchPath.Join(file.Base())
+		if len(sketchFiles) == 1 {
+			// the only .ino file becomes the main sketch file
+			target = mainFile
+		}
+		if err := file.CopyTo(target); err != nil {
+			return fmt.Errorf("copying project sources: %s", err)
+		}
+	}
+	if !mainFile.Exist() {
+		content := "// Imported from the PlatformIO project " + project.Dir.String() + ",\n" +
+			"// the sources are in the src folder.\n"
+		if err := mainFile.WriteFile([]byte(content)); err != nil {
+			return fmt.Errorf("creating main sketch file: %s", err)
+		}
+	}
+
+	if project.LibDir.IsDir() {
+		if libs, err := project.LibDir.ReadDir(); err == nil {
+			libs.FilterDirs()
+			for _, lib := range libs {
+				res.Warnings = append(res.Warnings, fmt.Sprintf("private library %s not imported, install it in the libraries folder", lib.Base()))
+			}
+		}
+	}
+	return nil
+}
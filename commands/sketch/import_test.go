@@ -0,0 +1,99 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package sketch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/sketches"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	paths "github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportSketch(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "import_sketch")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+
+	project := tmp.Join("blink")
+	for name, content := range map[string]string{
+		"platformio.ini": "[env:nano]\nplatform = atmelavr\nboard = nanoatmega328new\nframework = arduino\n" +
+			"lib_deps =\n  arduino-libraries/Servo @ 1.1.8\n  https://github.com/user/Private.git\n",
+		"src/main.cpp":      "#include <Arduino.h>\n#include \"config.h\"\nvoid setup() {}\nvoid loop() {}\n",
+		"src/sub/util.cpp":  "void util() {}\n",
+		"include/config.h":  "#define LED 13\n",
+		"lib/Private/lib.h": "",
+	} {
+		file := project.Join(name)
+		require.NoError(t, file.Parent().MkdirAll())
+		require.NoError(t, file.WriteFile([]byte(content)))
+	}
+
+	dest := tmp.Join("sketchbook", "Blink")
+	res, err := ImportSketch(context.Background(), &rpc.ImportSketchRequest{
+		From:        "platformio",
+		ProjectPath: project.String(),
+		Destination: dest.String(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, dest.String(), res.GetSketchPath())
+	require.Equal(t, "arduino:avr:nano:cpu=atmega328", res.GetFqbn())
+	require.Equal(t, "arduino:avr", res.GetPlatform())
+	require.Equal(t, []string{"Servo@1.1.8"}, res.GetLibraries())
+	require.Len(t, res.GetWarnings(), 2)
+
+	require.True(t, dest.Join("Blink.ino").Exist())
+	require.True(t, dest.Join("src", "main.cpp").Exist())
+	require.True(t, dest.Join("src", "sub", "util.cpp").Exist())
+	require.True(t, dest.Join("src", "config.h").Exist())
+	sketch, err := sketches.NewSketchFromPath(dest)
+	require.NoError(t, err)
+	require.Equal(t, "arduino:avr:nano:cpu=atmega328", sketch.Metadata.CPU.Fqbn)
+	require.Equal(t, []string{"Servo@1.1.8"}, sketch.Metadata.Libraries)
+
+	// The destination must be empty
+	_, err = ImportSketch(context.Background(), &rpc.ImportSketchRequest{
+		From:        "platformio",
+		ProjectPath: project.String(),
+		Destination: dest.String(),
+	})
+	require.Error(t, err)
+
+	// The FQBN can be overridden, and the only .ino file becomes the main file
+	require.NoError(t, project.Join("src", "main.cpp").Remove())
+	require.NoError(t, project.Join("src", "main.ino").WriteFile([]byte("void setup() {}\nvoid loop() {}\n")))
+	dest = tmp.Join("sketchbook", "Blink2")
+	res, err = ImportSketch(context.Background(), &rpc.ImportSketchRequest{
+		From:        "platformio",
+		ProjectPath: project.String(),
+		Destination: dest.String(),
+		Fqbn:        "arduino:avr:uno",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "arduino:avr:uno", res.GetFqbn())
+	data, err := dest.Join("Blink2.ino").ReadFile()
+	require.NoError(t, err)
+	require.Equal(t, "void setup() {}\nvoid loop() {}\n", string(data))
+	require.False(t, dest.Join("main.ino").Exist())
+
+	_, err = ImportSketch(context.Background(), &rpc.ImportSketchRequest{
+		From:        "cmake",
+		ProjectPath: project.String(),
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,99 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package test
+
+import (
+	"encoding/xml"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// unityResultRegexp matches the result lines printed by Unity:
+//
+//	test/test_math.c:12:test_add:PASS
+//	test/test_math.c:20:test_sub:FAIL: Expected 1 Was 2
+var unityResultRegexp = regexp.MustCompile(`^(.+?):(\d+):([^:]+):(PASS|FAIL|IGNORE)(?::\s*(.*))?$`)
+
+// parseUnityOutput returns the tests reported in the output of a Unity program
+func parseUnityOutput(output string) []*Case {
+	cases := []*Case{}
+	for _, line := range strings.Split(output, "\n") {
+		match := unityResultRegexp.FindStringSubmatch(strings.TrimRight(line, "\r"))
+		if match == nil {
+			continue
+		}
+		c := &Case{Name: match[3], File: match[1], Message: match[5]}
+		c.Line, _ = strconv.Atoi(match[2])
+		switch match[4] {
+		case "PASS":
+			c.Status = StatusPassed
+		case "FAIL":
+			c.Status = StatusFailed
+		case "IGNORE":
+			c.Status = StatusSkipped
+		}
+		cases = append(cases, c)
+	}
+	return cases
+}
+
+type gtestReport struct {
+	Suites []struct {
+		Name  string `xml:"name,attr"`
+		Cases []struct {
+			Name     string `xml:"name,attr"`
+			Status   string `xml:"status,attr"`
+			Result   string `xml:"result,attr"`
+			Time     string `xml:"time,attr"`
+			File     string `xml:"file,attr"`
+			Line     int    `xml:"line,attr"`
+			Failures []struct {
+				Message string `xml:"message,attr"`
+			} `xml:"failure"`
+			Skipped *struct{} `xml:"skipped"`
+		} `xml:"testcase"`
+	} `xml:"testsuite"`
+}
+
+// parseGoogleTestReport returns the tests reported in the XML report of a
+// GoogleTest program
+func parseGoogleTestReport(data []byte) ([]*Case, error) {
+	var report gtestReport
+	if err := xml.Unmarshal(data, &report); err != nil {
+		return nil, err
+	}
+	cases := []*Case{}
+	for _, suite := range report.Suites {
+		for _, tc := range suite.Cases {
+			c := &Case{Name: suite.Name + "." + tc.Name, File: tc.File, Line: tc.Line, Status: StatusPassed}
+			c.Duration, _ = strconv.ParseFloat(tc.Time, 64)
+			if tc.Status == "notrun" || tc.Result == "skipped" || tc.Skipped != nil {
+				c.Status = StatusSkipped
+			}
+			if len(tc.Failures) > 0 {
+				c.Status = StatusFailed
+				messages := []string{}
+				for _, failure := range tc.Failures {
+					messages = append(messages, failure.Message)
+				}
+				c.Message = strings.Join(messages, "\n")
+			}
+			cases = append(cases, c)
+		}
+	}
+	return cases, nil
+}
@@ -0,0 +1,66 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package test
+
+import (
+	"io"
+	"time"
+
+	"github.com/arduino/arduino-cli/junit"
+)
+
+func seconds(s float64) string {
+	return junit.Seconds(time.Duration(s * float64(time.Second)))
+}
+
+// JUnit returns the results as a JUnit report. Each test program is a test
+// suite.
+func (r *Result) JUnit() *junit.TestSuites {
+	report := &junit.TestSuites{}
+	for _, suite := range r.Suites {
+		js := &junit.TestSuite{
+			Name:      suite.Name,
+			Time:      seconds(suite.Duration),
+			SystemOut: suite.Output,
+		}
+		for _, c := range suite.Cases {
+			jc := &junit.TestCase{
+				Name:      c.Name,
+				ClassName: suite.Name,
+				File:      c.File,
+				Line:      c.Line,
+				Time:      seconds(c.Duration),
+			}
+			switch c.Status {
+			case StatusFailed:
+				jc.Failure = &junit.Message{Message: c.Message}
+			case StatusError:
+				jc.Error = &junit.Message{Message: c.Message}
+			case StatusSkipped:
+				jc.Skipped = &junit.Message{Message: c.Message}
+			}
+			js.AddCase(jc)
+		}
+		report.AddSuite(js)
+	}
+	return report
+}
+
+// WriteJUnit writes the results in the JUnit XML format used by the CI
+// servers
+func WriteJUnit(res *Result, w io.Writer) error {
+	return res.JUnit().Write(w)
+}
@@ -0,0 +1,336 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package test
+
+import (
+	paths "github.com/arduino/go-paths-helper"
+)
+
+// mockCore contains the sources of the host mock of the Arduino core. The
+// mock implements the basic Arduino API in memory, the tests can drive the
+// inputs and inspect the outputs with the mock* functions.
+var mockCore = map[string]string{
+	"Arduino.h": `#ifndef ARDUINO_MOCK_H
+#define ARDUINO_MOCK_H
+
+#include <stdint.h>
+#include <stdbool.h>
+#include <stdlib.h>
+#include <string.h>
+#include <math.h>
+
+#define ARDUINO 10813
+#define ARDUINO_HOST_MOCK 1
+
+#define HIGH 0x1
+#define LOW 0x0
+
+#define INPUT 0x0
+#define OUTPUT 0x1
+#define INPUT_PULLUP 0x2
+
+#define LSBFIRST 0
+#define MSBFIRST 1
+
+#define CHANGE 1
+#define FALLING 2
+#define RISING 3
+
+#ifndef LED_BUILTIN
+#define LED_BUILTIN 13
+#endif
+
+#define NUM_DIGITAL_PINS 64
+#define NUM_ANALOG_INPUTS 16
+
+#define PI 3.1415926535897932384626433832795
+#define HALF_PI 1.5707963267948966192313216916398
+#define TWO_PI 6.283185307179586476925286766559
+#define DEG_TO_RAD 0.017453292519943295769236907684886
+#define RAD_TO_DEG 57.295779513082320876798154814105
+
+#define radians(deg) ((deg)*DEG_TO_RAD)
+#define degrees(rad) ((rad)*RAD_TO_DEG)
+#define sq(x) ((x)*(x))
+#define constrain(amt, low, high) ((amt) < (low) ? (low) : ((amt) > (high) ? (high) : (amt)))
+
+#define lowByte(w) ((uint8_t)((w)&0xff))
+#define highByte(w) ((uint8_t)((w) >> 8))
+#define bitRead(value, bit) (((value) >> (bit)) & 0x01)
+#define bitSet(value, bit) ((value) |= (1UL << (bit)))
+#define bitClear(value, bit) ((value) &= ~(1UL << (bit)))
+#define bitWrite(value, bit, bitvalue) ((bitvalue) ? bitSet(value, bit) : bitClear(value, bit))
+#define bit(b) (1UL << (b))
+
+#define PROGMEM
+#define PSTR(s) (s)
+#define F(s) (s)
+#define pgm_read_byte(addr) (*(const uint8_t *)(addr))
+#define pgm_read_word(addr) (*(const uint16_t *)(addr))
+
+typedef bool boolean;
+typedef uint8_t byte;
+typedef uint16_t word;
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+void pinMode(uint8_t pin, uint8_t mode);
+void digitalWrite(uint8_t pin, uint8_t val);
+int digitalRead(uint8_t pin);
+int analogRead(uint8_t pin);
+void analogWrite(uint8_t pin, int val);
+
+unsigned long millis(void);
+unsigned long micros(void);
+void delay(unsigned long ms);
+void delayMicroseconds(unsigned int us);
+
+void randomSeed(unsigned long seed);
+
+// Functions to drive the mock from the tests
+void mockReset(void);
+void mockSetMillis(unsigned long ms);
+void mockSetMicros(unsigned long us);
+void mockSetDigitalRead(uint8_t pin, int val);
+void mockSetAnalogRead(uint8_t pin, int val);
+int mockGetPinMode(uint8_t pin);
+int mockGetDigitalWrite(uint8_t pin);
+int mockGetAnalogWrite(uint8_t pin);
+
+#ifdef __cplusplus
+} // extern "C"
+
+long random(long max);
+long random(long min, long max);
+long map(long x, long in_min, long in_max, long out_min, long out_max);
+
+template <class T, class L>
+auto min(const T &a, const L &b) -> decltype((b < a) ? b : a) {
+  return (b < a) ? b : a;
+}
+
+template <class T, class L>
+auto max(const T &a, const L &b) -> decltype((b < a) ? b : a) {
+  return (a < b) ? b : a;
+}
+
+#define DEC 10
+#define HEX 16
+#define OCT 8
+#define BIN 2
+
+class Print {
+public:
+  virtual ~Print() {}
+  virtual size_t write(uint8_t c) = 0;
+  size_t write(const char *str);
+  size_t write(const uint8_t *buffer, size_t size);
+  size_t print(const char *str);
+  size_t print(char c);
+  size_t print(unsigned char n, int base = DEC);
+  size_t print(int n, int base = DEC);
+  size_t print(unsigned int n, int base = DEC);
+  size_t print(long n, int base = DEC);
+  size_t print(unsigned long n, int base = DEC);
+  size_t print(double n, int digits = 2);
+  size_t println(void);
+  template <typename T>
+  size_t println(T value) {
+    return print(value) + println();
+  }
+  template <typename T>
+  size_t println(T value, int format) {
+    return print(value, format) + println();
+  }
+
+private:
+  size_t printNumber(unsigned long n, int base);
+};
+
+class MockSerial : public Print {
+public:
+  void begin(unsigned long baud) { (void)baud; }
+  void end() {}
+  int available();
+  int read();
+  int peek();
+  void flush() {}
+  size_t write(uint8_t c) override;
+  using Print::write;
+  operator bool() { return true; }
+
+  // Functions to drive the mock from the tests
+  void mockInput(const char *data);
+  const char *mockOutput();
+  void mockClear();
+};
+
+extern MockSerial Serial;
+#else
+#define min(a, b) ((a) < (b) ? (a) : (b))
+#define max(a, b) ((a) > (b) ? (a) : (b))
+#endif
+
+#endif // ARDUINO_MOCK_H
+`,
+	"Arduino.cpp": `#include "Arduino.h"
+
+#include <stdio.h>
+#include <string>
+
+static int pinModes[NUM_DIGITAL_PINS];
+static int digitalValues[NUM_DIGITAL_PINS];
+static int analogValues[NUM_DIGITAL_PINS];
+static int analogInputs[NUM_DIGITAL_PINS];
+static unsigned long currentMicros = 0;
+
+void mockReset(void) {
+  memset(pinModes, 0, sizeof(pinModes));
+  memset(digitalValues, 0, sizeof(digitalValues));
+  memset(analogValues, 0, sizeof(analogValues));
+  memset(analogInputs, 0, sizeof(analogInputs));
+  currentMicros = 0;
+  Serial.mockClear();
+}
+
+void mockSetMillis(unsigned long ms) { currentMicros = ms * 1000; }
+void mockSetMicros(unsigned long us) { currentMicros = us; }
+void mockSetDigitalRead(uint8_t pin, int val) { digitalValues[pin % NUM_DIGITAL_PINS] = val; }
+void mockSetAnalogRead(uint8_t pin, int val) { analogInputs[pin % NUM_DIGITAL_PINS] = val; }
+int mockGetPinMode(uint8_t pin) { return pinModes[pin % NUM_DIGITAL_PINS]; }
+int mockGetDigitalWrite(uint8_t pin) { return digitalValues[pin % NUM_DIGITAL_PINS]; }
+int mockGetAnalogWrite(uint8_t pin) { return analogValues[pin % NUM_DIGITAL_PINS]; }
+
+void pinMode(uint8_t pin, uint8_t mode) { pinModes[pin % NUM_DIGITAL_PINS] = mode; }
+void digitalWrite(uint8_t pin, uint8_t val) { digitalValues[pin % NUM_DIGITAL_PINS] = val ? HIGH : LOW; }
+int digitalRead(uint8_t pin) { return digitalValues[pin % NUM_DIGITAL_PINS]; }
+int analogRead(uint8_t pin) { return analogInputs[pin % NUM_DIGITAL_PINS]; }
+void analogWrite(uint8_t pin, int val) { analogValues[pin % NUM_DIGITAL_PINS] = val; }
+
+unsigned long millis(void) { return currentMicros / 1000; }
+unsigned long micros(void) { return currentMicros; }
+// Time flows only when the sketch waits, so the tests are deterministic
+void delay(unsigned long ms) { currentMicros += ms * 1000; }
+void delayMicroseconds(unsigned int us) { currentMicros += us; }
+
+void randomSeed(unsigned long seed) {
+  if (seed != 0) {
+    srand(seed);
+  }
+}
+
+long random(long max) { return max == 0 ? 0 : rand() % max; }
+long random(long min, long max) { return min >= max ? min : random(max - min) + min; }
+
+long map(long x, long in_min, long in_max, long out_min, long out_max) {
+  return (x - in_min) * (out_max - out_min) / (in_max - in_min) + out_min;
+}
+
+size_t Print::write(const char *str) { return str == NULL ? 0 : write((const uint8_t *)str, strlen(str)); }
+
+size_t Print::write(const uint8_t *buffer, size_t size) {
+  size_t n = 0;
+  while (size--) {
+    n += write(*buffer++);
+  }
+  return n;
+}
+
+size_t Print::print(const char *str) { return write(str); }
+size_t Print::print(char c) { return write((uint8_t)c); }
+size_t Print::print(unsigned char n, int base) { return print((unsigned long)n, base); }
+size_t Print::print(int n, int base) { return print((long)n, base); }
+size_t Print::print(unsigned int n, int base) { return print((unsigned long)n, base); }
+
+size_t Print::print(long n, int base) {
+  if (base == DEC && n < 0) {
+    return write('-') + printNumber(-n, DEC);
+  }
+  return printNumber(n, base);
+}
+
+size_t Print::print(unsigned long n, int base) { return printNumber(n, base); }
+
+size_t Print::print(double n, int digits) {
+  char buf[64];
+  snprintf(buf, sizeof(buf), "%.*f", digits, n);
+  return write(buf);
+}
+
+size_t Print::println(void) { return write("\r\n"); }
+
+size_t Print::printNumber(unsigned long n, int base) {
+  char buf[8 * sizeof(long) + 1];
+  char *str = &buf[sizeof(buf) - 1];
+  *str = '\0';
+  if (base < 2) {
+    base = 10;
+  }
+  do {
+    char c = n % base;
+    n /= base;
+    *--str = c < 10 ? c + '0' : c + 'A' - 10;
+  } while (n);
+  return write(str);
+}
+
+static std::string serialInput;
+static std::string serialOutput;
+
+MockSerial Serial;
+
+int MockSerial::available() { return serialInput.size(); }
+
+int MockSerial::read() {
+  if (serialInput.empty()) {
+    return -1;
+  }
+  int c = (uint8_t)serialInput[0];
+  serialInput.erase(0, 1);
+  return c;
+}
+
+int MockSerial::peek() { return serialInput.empty() ? -1 : (uint8_t)serialInput[0]; }
+
+size_t MockSerial::write(uint8_t c) {
+  serialOutput.push_back(c);
+  return 1;
+}
+
+void MockSerial::mockInput(const char *data) { serialInput.append(data); }
+const char *MockSerial::mockOutput() { return serialOutput.c_str(); }
+
+void MockSerial::mockClear() {
+  serialInput.clear();
+  serialOutput.clear();
+}
+`,
+}
+
+// writeMockCore saves the sources of the mock core in the given folder
+func writeMockCore(dir *paths.Path) error {
+	if err := dir.MkdirAll(); err != nil {
+		return err
+	}
+	for name, content := range mockCore {
+		if err := dir.Join(name).WriteFile([]byte(content)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/arduino/arduino-cli/arduino/monitors"
+	"github.com/arduino/arduino-cli/commands/compile"
+	"github.com/arduino/arduino-cli/commands/upload"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	paths "github.com/arduino/go-paths-helper"
+	"github.com/sirupsen/logrus"
+)
+
+// TargetRequest contains the parameters to run the tests on a board. The
+// Timeout of the Request is the maximum time to wait for each test result.
+type TargetRequest struct {
+	Request
+	Instance *rpc.Instance
+	Fqbn     string
+	Port     string
+	// BaudRate is the speed of the serial port used by the tests to report
+	// the results
+	BaudRate int
+}
+
+// unitySummaryRegexp matches the summary printed by UNITY_END(), followed by
+// a line with OK or FAIL
+var unitySummaryRegexp = regexp.MustCompile(`^\d+ Tests \d+ Failures \d+ Ignored`)
+
+// RunOnTarget builds each test program as a sketch, uploads it to the board
+// and collects the results printed by Unity on the serial port. The test
+// programs must call UNITY_BEGIN() and UNITY_END() in setup().
+func RunOnTarget(ctx context.Context, req *TargetRequest, outStream, errStream io.Writer) (*Result, error) {
+	if req.Harness != HarnessUnity {
+		return nil, fmt.Errorf("on-target tests require the Unity harness")
+	}
+	if req.HarnessPath == nil || !req.HarnessPath.Join("unity.c").Exist() {
+		return nil, fmt.Errorf("the Unity harness requires the path of the folder containing unity.c")
+	}
+	if req.Fqbn == "" || req.Port == "" {
+		return nil, fmt.Errorf("on-target tests require the board FQBN and port")
+	}
+	root, err := req.Path.Abs()
+	if err != nil {
+		return nil, fmt.Errorf("getting path: %s", err)
+	}
+	testDir := root.Join("test")
+	if !testDir.IsDir() {
+		testDir = root.Join("tests")
+	}
+	if !testDir.IsDir() {
+		return nil, fmt.Errorf("test folder not found in %s", root)
+	}
+	suites, helpers, err := findTests(testDir)
+	if err != nil {
+		return nil, err
+	}
+	if req.Filter != "" {
+		filtered := []*suiteSources{}
+		for _, suite := range suites {
+			if strings.Contains(suite.name, req.Filter) {
+				filtered = append(filtered, suite)
+			}
+		}
+		suites = filtered
+	}
+	if len(suites) == 0 {
+		return nil, fmt.Errorf("no tests found in %s", testDir)
+	}
+
+	tmp, err := paths.MkTempDir("", "arduino-test-")
+	if err != nil {
+		return nil, fmt.Errorf("creating build folder: %s", err)
+	}
+	defer tmp.RemoveAll()
+
+	harnessFiles, err := req.HarnessPath.ReadDir()
+	if err != nil {
+		return nil, fmt.Errorf("reading harness folder: %s", err)
+	}
+	harnessFiles.FilterOutDirs()
+	harnessFiles.FilterSuffix(".c", ".h")
+
+	res := &Result{Suites: []*Suite{}}
+	for _, suite := range suites {
+		sources := paths.PathList{}
+		sources.AddAll(suite.sources)
+		sources.AddAll(helpers)
+		sources.AddAll(harnessFiles)
+		res.add(runSuiteOnTarget(ctx, req, root, tmp.Join(suite.name), suite.name, sources, outStream, errStream))
+	}
+	return res, nil
+}
+
+// runSuiteOnTarget creates a sketch with the sources of a test program,
+// uploads it and collects the results
+func runSuiteOnTarget(ctx context.Context, req *TargetRequest, root, dir *paths.Path, name string, sources paths.PathList, outStream, errStream io.Writer) *Suite {
+	res := &Suite{Name: name}
+	fail := func(message, output string) *Suite {
+		res.Output = output
+		res.Cases = append(res.Cases, &Case{Name: name, Status: StatusError, Message: message})
+		return res
+	}
+
+	sketchDir := dir.Join(name)
+	if err := sketchDir.MkdirAll(); err != nil {
+		return fail(err.Error(), "")
+	}
+	if err := sketchDir.Join(name + ".ino").WriteFile([]byte("// The tests are run by setup() in the test sources\n")); err != nil {
+		return fail(err.Error(), "")
+	}
+	for _, source := range sources {
+		if err := source.CopyTo(sketchDir.Join(source.Base())); err != nil {
+			return fail(err.Error(), "")
+		}
+	}
+
+	compileReq := &rpc.CompileRequest{
+		Instance:   req.Instance,
+		Fqbn:       req.Fqbn,
+		SketchPath: sketchDir.String(),
+		BuildPath:  dir.Join("build").String(),
+		Verbose:    req.Verbose,
+	}
+	if src := root.Join("src"); src.IsDir() && hasSketchFiles(root) {
+		// The sources of a sketch are copied in the test sketch, the tests
+		// include them as on the host
+		if err := src.CopyDirTo(sketchDir.Join("src")); err != nil {
+			return fail(err.Error(), "")
+		}
+		include := fmt.Sprintf(`-I"%s"`, sketchDir.Join("src"))
+		compileReq.BuildProperties = []string{
+			"compiler.c.extra_flags=" + include,
+			"compiler.cpp.extra_flags=" + include,
+		}
+	} else {
+		compileReq.Library = []string{root.String()}
+	}
+
+	var output bytes.Buffer
+	var out, errOut io.Writer = &output, &output
+	if req.Verbose {
+		out, errOut = io.MultiWriter(&output, outStream), io.MultiWriter(&output, errStream)
+	}
+	logrus.Infof("Building test %s", name)
+	if _, err := compile.Compile(ctx, compileReq, out, errOut, nil, false); err != nil {
+		return fail("build failed: "+err.Error(), output.String())
+	}
+	output.Reset()
+
+	uploadReq := &rpc.UploadRequest{
+		Instance:   req.Instance,
+		Fqbn:       req.Fqbn,
+		SketchPath: sketchDir.String(),
+		Port:       req.Port,
+		ImportDir:  compileReq.BuildPath,
+		Verbose:    req.Verbose,
+	}
+	logrus.Infof("Uploading test %s", name)
+	if _, err := upload.Upload(ctx, uploadReq, out, errOut, nil); err != nil {
+		return fail("upload failed: "+err.Error(), output.String())
+	}
+
+	mon, err := monitors.OpenSerialMonitor(req.Port, req.BaudRate)
+	if err != nil {
+		return fail(err.Error(), "")
+	}
+	defer mon.Close()
+	var monitorOut io.Writer = ioutil.Discard
+	if req.Verbose {
+		monitorOut = outStream
+	}
+	start := time.Now()
+	cases, received, err := collectUnityResults(mon, req.Timeout, monitorOut)
+	res.Duration = time.Since(start).Seconds()
+	res.Output = received
+	res.Cases = cases
+	if err != nil {
+		res.Cases = append(res.Cases, &Case{Name: name, Status: StatusError, Message: err.Error()})
+	}
+	return res
+}
+
+// collectUnityResults reads the output of a Unity program until the summary
+// printed by UNITY_END(). It fails if no result is received within the
+// timeout (0 means no limit). The data received is also copied to out.
+func collectUnityResults(r io.Reader, timeout time.Duration, out io.Writer) ([]*Case, string, error) {
+	chunks := make(chan []byte, 16)
+	readErr := make(chan error, 1)
+	done := make(chan bool)
+	defer close(done)
+	go func() {
+		for {
+			buf := make([]byte, 1024)
+			n, err := r.Read(buf)
+			if n > 0 {
+				select {
+				case chunks <- buf[:n]:
+				case <-done:
+					return
+				}
+			}
+			if err != nil {
+				readErr <- err
+				return
+			}
+		}
+	}()
+
+	var timer <-chan time.Time
+	resetTimer := func() {
+		if timeout > 0 {
+			timer = time.After(timeout)
+		}
+	}
+	resetTimer()
+
+	cases := []*Case{}
+	var received strings.Builder
+	pending := ""
+	last := time.Now()
+	summary := false
+	for {
+		select {
+		case chunk := <-chunks:
+			out.Write(chunk)
+			received.Write(chunk)
+			pending += string(chunk)
+		case err := <-readErr:
+			return cases, received.String(), fmt.Errorf("reading test results: %s", err)
+		case <-timer:
+			return cases, received.String(), fmt.Errorf("no test result received within %s", timeout)
+		}
+
+		lines := strings.Split(pending, "\n")
+		pending = lines[len(lines)-1]
+		for _, line := range lines[:len(lines)-1] {
+			line = strings.TrimSpace(line)
+			if summary && (line == "OK" || line == "FAIL") {
+				return cases, received.String(), nil
+			}
+			if unitySummaryRegexp.MatchString(line) {
+				summary = true
+				continue
+			}
+			if parsed := parseUnityOutput(line); len(parsed) > 0 {
+				parsed[0].Duration = time.Since(last).Seconds()
+				last = time.Now()
+				cases = append(cases, parsed[0])
+				resetTimer()
+			}
+		}
+	}
+}
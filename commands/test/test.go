@@ -0,0 +1,508 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/arduino/arduino-cli/executils"
+	paths "github.com/arduino/go-paths-helper"
+	"github.com/sirupsen/logrus"
+)
+
+// Harness is the test framework used by the tests
+type Harness string
+
+const (
+	// HarnessNone runs each test program as a single test, passed if the
+	// program exits successfully
+	HarnessNone Harness = "none"
+	// HarnessUnity compiles the tests with the Unity framework and reports
+	// each test of the programs
+	HarnessUnity Harness = "unity"
+	// HarnessGoogleTest links the tests with the GoogleTest libraries and
+	// reports each test of the programs
+	HarnessGoogleTest Harness = "gtest"
+)
+
+// Harnesses is the list of the supported harnesses
+var Harnesses = []Harness{HarnessNone, HarnessUnity, HarnessGoogleTest}
+
+// Status is the outcome of a test
+type Status string
+
+const (
+	// StatusPassed is used for the tests that succeeded
+	StatusPassed Status = "passed"
+	// StatusFailed is used for the tests that failed
+	StatusFailed Status = "failed"
+	// StatusSkipped is used for the tests ignored by the harness
+	StatusSkipped Status = "skipped"
+	// StatusError is used for the test programs that could not be built or
+	// that crashed or timed out
+	StatusError Status = "error"
+)
+
+// Request contains the parameters to run the tests on the host
+type Request struct {
+	// Path is the library or sketch folder, the tests are in its test folder
+	Path    *paths.Path
+	Harness Harness
+	// HarnessPath is the folder containing the sources of the harness, it's
+	// required by Unity (unity.c and its headers)
+	HarnessPath *paths.Path
+	// Filter runs only the test programs whose name contains the given string
+	Filter string
+	// CCompiler and CXXCompiler are the host compilers, by default the CC and
+	// CXX environment variables or cc and c++
+	CCompiler   string
+	CXXCompiler string
+	// Flags are added to the compiler command lines
+	Flags []string
+	// Timeout is the maximum duration of a test program, 0 means no limit
+	Timeout time.Duration
+	Verbose bool
+}
+
+// Result contains the results of all the test programs
+type Result struct {
+	Suites  []*Suite `json:"suites"`
+	Passed  int      `json:"passed"`
+	Failed  int      `json:"failed"`
+	Skipped int      `json:"skipped"`
+	Errors  int      `json:"errors"`
+}
+
+// Success returns true if no test failed
+func (r *Result) Success() bool {
+	return r.Failed == 0 && r.Errors == 0
+}
+
+// Suite is the result of a test program
+type Suite struct {
+	Name string `json:"name"`
+	// Duration is the run time of the program in seconds
+	Duration float64 `json:"duration"`
+	Cases    []*Case `json:"cases"`
+	// Output contains the output of the program or the errors of the compiler
+	Output string `json:"output,omitempty"`
+}
+
+// Case is the result of a single test
+type Case struct {
+	Name     string  `json:"name"`
+	Status   Status  `json:"status"`
+	File     string  `json:"file,omitempty"`
+	Line     int     `json:"line,omitempty"`
+	Message  string  `json:"message,omitempty"`
+	Duration float64 `json:"duration,omitempty"`
+}
+
+// suiteSources is a test program found in the test folder
+type suiteSources struct {
+	name    string
+	sources paths.PathList
+}
+
+// Run compiles the test programs in the test folder of a library or sketch
+// together with their sources and the host mock of the Arduino core, runs
+// them and collects the results.
+//
+// Each test_*.c(pp) or *_test.c(pp) file in the test folder is a test program,
+// as each subfolder of the test folder. The other sources in the test folder
+// are linked in all the programs. The code under test is in the src folder
+// of the library (or in its root folder for the legacy layout) or in the src
+// folder of the sketch.
+func Run(req *Request, outStream, errStream io.Writer) (*Result, error) {
+	root, err := req.Path.Abs()
+	if err != nil {
+		return nil, fmt.Errorf("getting path: %s", err)
+	}
+	testDir := root.Join("test")
+	if !testDir.IsDir() {
+		testDir = root.Join("tests")
+	}
+	if !testDir.IsDir() {
+		return nil, fmt.Errorf("test folder not found in %s", root)
+	}
+
+	harness := req.Harness
+	if harness == "" {
+		harness = HarnessNone
+	}
+	switch harness {
+	case HarnessNone, HarnessGoogleTest:
+	case HarnessUnity:
+		if req.HarnessPath == nil || !req.HarnessPath.Join("unity.c").Exist() {
+			return nil, fmt.Errorf("the Unity harness requires the path of the folder containing unity.c")
+		}
+	default:
+		return nil, fmt.Errorf("invalid harness: %s", harness)
+	}
+
+	suites, helpers, err := findTests(testDir)
+	if err != nil {
+		return nil, err
+	}
+	if req.Filter != "" {
+		filtered := []*suiteSources{}
+		for _, suite := range suites {
+			if strings.Contains(suite.name, req.Filter) {
+				filtered = append(filtered, suite)
+			}
+		}
+		suites = filtered
+	}
+	if len(suites) == 0 {
+		return nil, fmt.Errorf("no tests found in %s", testDir)
+	}
+
+	buildDir, err := paths.MkTempDir("", "arduino-test-")
+	if err != nil {
+		return nil, fmt.Errorf("creating build folder: %s", err)
+	}
+	defer buildDir.RemoveAll()
+
+	b := &builder{
+		req:      req,
+		buildDir: buildDir,
+		cc:       compiler(req.CCompiler, "CC", "cc"),
+		cxx:      compiler(req.CXXCompiler, "CXX", "c++"),
+		out:      outStream,
+		err:      errStream,
+	}
+	mockDir := buildDir.Join("mock")
+	if err := writeMockCore(mockDir); err != nil {
+		return nil, fmt.Errorf("writing mock core: %s", err)
+	}
+	b.includes = paths.NewPathList(mockDir.String(), testDir.String())
+
+	// The code under test and the harness are compiled once for all the programs
+	common := paths.PathList{mockDir.Join("Arduino.cpp")}
+	sourceDirs := codeUnderTest(root)
+	for _, dir := range sourceDirs {
+		b.includes.Add(dir.dir)
+		common.AddAll(dir.sources)
+	}
+	if harness == HarnessUnity {
+		b.includes.Add(req.HarnessPath)
+		common.Add(req.HarnessPath.Join("unity.c"))
+	}
+	common.AddAll(helpers)
+
+	res := &Result{Suites: []*Suite{}}
+	commonObjs, buildOutput, err := b.compile("common", common)
+	if err != nil {
+		// Nothing can be run without the code under test
+		for _, suite := range suites {
+			res.add(&Suite{
+				Name:   suite.name,
+				Output: buildOutput,
+				Cases:  []*Case{{Name: suite.name, Status: StatusError, Message: "build failed"}},
+			})
+		}
+		return res, nil
+	}
+
+	for _, suite := range suites {
+		res.add(b.runSuite(suite, commonObjs, harness))
+	}
+	return res, nil
+}
+
+func (r *Result) add(suite *Suite) {
+	r.Suites = append(r.Suites, suite)
+	for _, c := range suite.Cases {
+		switch c.Status {
+		case StatusPassed:
+			r.Passed++
+		case StatusFailed:
+			r.Failed++
+		case StatusSkipped:
+			r.Skipped++
+		case StatusError:
+			r.Errors++
+		}
+	}
+}
+
+func compiler(value, env, def string) string {
+	if value != "" {
+		return value
+	}
+	if value := os.Getenv(env); value != "" {
+		return value
+	}
+	return def
+}
+
+func isSource(file *paths.Path) bool {
+	ext := file.Ext()
+	return ext == ".c" || ext == ".cpp" || ext == ".cc"
+}
+
+// findTests returns the test programs in the test folder and the helper
+// sources shared by all of them
+func findTests(testDir *paths.Path) ([]*suiteSources, paths.PathList, error) {
+	files, err := testDir.ReadDir()
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading test folder: %s", err)
+	}
+	files.Sort()
+	suites := []*suiteSources{}
+	helpers := paths.PathList{}
+	for _, file := range files {
+		if strings.HasPrefix(file.Base(), ".") {
+			continue
+		}
+		if file.IsDir() {
+			sources, err := file.ReadDirRecursive()
+			if err != nil {
+				return nil, nil, fmt.Errorf("reading test folder: %s", err)
+			}
+			sources.FilterOutDirs()
+			sources.Sort()
+			suite := &suiteSources{name: file.Base()}
+			for _, source := range sources {
+				if isSource(source) {
+					suite.sources.Add(source)
+				}
+			}
+			if len(suite.sources) > 0 {
+				suites = append(suites, suite)
+			}
+			continue
+		}
+		if !isSource(file) {
+			continue
+		}
+		name := strings.TrimSuffix(file.Base(), file.Ext())
+		if strings.HasPrefix(name, "test_") || strings.HasSuffix(name, "_test") {
+			suites = append(suites, &suiteSources{name: name, sources: paths.PathList{file}})
+		} else {
+			helpers.Add(file)
+		}
+	}
+	return suites, helpers, nil
+}
+
+type sourceDir struct {
+	dir     *paths.Path
+	sources paths.PathList
+}
+
+// codeUnderTest returns the folders to add to the include path and the
+// sources to compile with the tests
+func codeUnderTest(root *paths.Path) []*sourceDir {
+	dirs := []*sourceDir{}
+	add := func(dir *paths.Path, recursive bool) {
+		var files paths.PathList
+		var err error
+		if recursive {
+			files, err = dir.ReadDirRecursive()
+		} else {
+			files, err = dir.ReadDir()
+		}
+		if err != nil {
+			return
+		}
+		files.FilterOutDirs()
+		files.Sort()
+		sd := &sourceDir{dir: dir}
+		for _, file := range files {
+			if isSource(file) {
+				sd.sources.Add(file)
+			}
+		}
+		dirs = append(dirs, sd)
+	}
+	if src := root.Join("src"); src.IsDir() {
+		add(src, true)
+	} else if root.Join("library.properties").Exist() || !hasSketchFiles(root) {
+		// legacy library layout
+		add(root, false)
+		if utility := root.Join("utility"); utility.IsDir() {
+			add(utility, false)
+		}
+	}
+	return dirs
+}
+
+func hasSketchFiles(dir *paths.Path) bool {
+	files, err := dir.ReadDir()
+	if err != nil {
+		return false
+	}
+	files.FilterSuffix(".ino", ".pde")
+	return len(files) > 0
+}
+
+type builder struct {
+	req      *Request
+	buildDir *paths.Path
+	cc       string
+	cxx      string
+	includes paths.PathList
+	out      io.Writer
+	err      io.Writer
+}
+
+// compile builds the sources in a subfolder of the build folder named after
+// the given prefix, it returns the object files and the compiler output
+func (b *builder) compile(prefix string, sources paths.PathList) (paths.PathList, string, error) {
+	objDir := b.buildDir.Join(prefix)
+	if err := objDir.MkdirAll(); err != nil {
+		return nil, "", err
+	}
+	objs := paths.PathList{}
+	var output bytes.Buffer
+	for i, source := range sources {
+		obj := objDir.Join(fmt.Sprintf("%d_%s.o", i, source.Base()))
+		args := []string{b.cxx, "-std=gnu++11"}
+		if source.Ext() == ".c" {
+			args = []string{b.cc, "-std=gnu11"}
+		}
+		args = append(args, "-c", "-g", "-DARDUINO_HOST_MOCK")
+		for _, include := range b.includes {
+			args = append(args, "-I"+include.String())
+		}
+		args = append(args, b.req.Flags...)
+		args = append(args, source.String(), "-o", obj.String())
+		if err := b.exec(args, &output); err != nil {
+			return nil, output.String(), err
+		}
+		objs.Add(obj)
+	}
+	return objs, output.String(), nil
+}
+
+func (b *builder) exec(args []string, output *bytes.Buffer) error {
+	if b.req.Verbose {
+		fmt.Fprintln(b.out, strings.Join(args, " "))
+	}
+	cmd, err := executils.NewProcess(args...)
+	if err != nil {
+		return err
+	}
+	cmd.RedirectStdoutTo(output)
+	cmd.RedirectStderrTo(output)
+	return cmd.Run()
+}
+
+// runSuite builds and runs a test program
+func (b *builder) runSuite(suite *suiteSources, commonObjs paths.PathList, harness Harness) *Suite {
+	res := &Suite{Name: suite.name}
+	buildError := func(output string) *Suite {
+		res.Output = output
+		res.Cases = []*Case{{Name: suite.name, Status: StatusError, Message: "build failed"}}
+		return res
+	}
+
+	objs, output, err := b.compile(suite.name, suite.sources)
+	if err != nil {
+		return buildError(output)
+	}
+	exe := b.buildDir.Join(suite.name, suite.name)
+	if runtime.GOOS == "windows" {
+		exe = exe.Parent().Join(suite.name + ".exe")
+	}
+	args := []string{b.cxx, "-o", exe.String()}
+	args = append(args, objs.AsStrings()...)
+	args = append(args, commonObjs.AsStrings()...)
+	if harness == HarnessGoogleTest {
+		args = append(args, "-lgtest", "-lgtest_main", "-pthread")
+	}
+	var linkOutput bytes.Buffer
+	if err := b.exec(args, &linkOutput); err != nil {
+		return buildError(output + linkOutput.String())
+	}
+
+	runArgs := []string{exe.String()}
+	gtestReport := b.buildDir.Join(suite.name, "gtest.xml")
+	if harness == HarnessGoogleTest {
+		runArgs = append(runArgs, "--gtest_output=xml:"+gtestReport.String())
+	}
+	logrus.Infof("Running test %s", suite.name)
+	start := time.Now()
+	programOutput, runErr := b.run(runArgs)
+	res.Duration = time.Since(start).Seconds()
+	res.Output = programOutput
+
+	switch harness {
+	case HarnessUnity:
+		res.Cases = parseUnityOutput(programOutput)
+	case HarnessGoogleTest:
+		if report, err := gtestReport.ReadFile(); err == nil {
+			res.Cases, _ = parseGoogleTestReport(report)
+		}
+	}
+	if runErr != nil && !hasFailures(res.Cases) {
+		// The program crashed or timed out without reporting a failure
+		res.Cases = append(res.Cases, &Case{Name: suite.name, Status: StatusError, Message: runErr.Error()})
+	} else if len(res.Cases) == 0 {
+		res.Cases = []*Case{{Name: suite.name, Status: StatusPassed, Duration: res.Duration}}
+	}
+	return res
+}
+
+func hasFailures(cases []*Case) bool {
+	for _, c := range cases {
+		if c.Status == StatusFailed || c.Status == StatusError {
+			return true
+		}
+	}
+	return false
+}
+
+// run executes a test program and returns its output, the program is killed
+// if it runs longer than the timeout of the request
+func (b *builder) run(args []string) (string, error) {
+	var output bytes.Buffer
+	cmd, err := executils.NewProcess(args...)
+	if err != nil {
+		return "", err
+	}
+	if b.req.Verbose {
+		cmd.RedirectStdoutTo(io.MultiWriter(&output, b.out))
+		cmd.RedirectStderrTo(io.MultiWriter(&output, b.err))
+	} else {
+		cmd.RedirectStdoutTo(&output)
+		cmd.RedirectStderrTo(&output)
+	}
+	if err := cmd.Start(); err != nil {
+		return "", err
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	var timeout <-chan time.Time
+	if b.req.Timeout > 0 {
+		timeout = time.After(b.req.Timeout)
+	}
+	select {
+	case err = <-done:
+	case <-timeout:
+		_ = cmd.Kill()
+		<-done
+		err = fmt.Errorf("timed out after %s", b.req.Timeout)
+	}
+	return output.String(), err
+}
@@ -0,0 +1,193 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	paths "github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func createFiles(t *testing.T, dir *paths.Path, files map[string]string) {
+	for name, content := range files {
+		file := dir.Join(name)
+		require.NoError(t, file.Parent().MkdirAll())
+		require.NoError(t, file.WriteFile([]byte(content)))
+	}
+}
+
+func TestRunOnHost(t *testing.T) {
+	if _, err := exec.LookPath("c++"); err != nil {
+		t.Skip("host compiler not available")
+	}
+	tmp, err := paths.MkTempDir("", "test_runner")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+
+	libPath := tmp.Join("Blinker")
+	createFiles(t, libPath, map[string]string{
+		"library.properties": "name=Blinker\nversion=1.0.0\n",
+		"src/Blinker.h":      "#include <Arduino.h>\nvoid blink(uint8_t pin, unsigned long period);\n",
+		"src/Blinker.cpp": "#include \"Blinker.h\"\n" +
+			"void blink(uint8_t pin, unsigned long period) {\n" +
+			"  pinMode(pin, OUTPUT);\n" +
+			"  digitalWrite(pin, (millis() / period) % 2 ? HIGH : LOW);\n" +
+			"  Serial.println(millis());\n" +
+			"}\n",
+		"test/helper.cpp": "int answer() { return 42; }\n",
+		"test/test_blink.cpp": "#include <Blinker.h>\n" +
+			"int answer();\n" +
+			"int main() {\n" +
+			"  mockSetMillis(1500);\n" +
+			"  blink(LED_BUILTIN, 1000);\n" +
+			"  if (mockGetPinMode(LED_BUILTIN) != OUTPUT || mockGetDigitalWrite(LED_BUILTIN) != HIGH) return 1;\n" +
+			"  if (strcmp(Serial.mockOutput(), \"1500\\r\\n\") != 0) return 1;\n" +
+			"  return answer() == 42 ? 0 : 1;\n" +
+			"}\n",
+		"test/fail_test.c":    "#include <stdio.h>\nint main() { printf(\"boom\\n\"); return 1; }\n",
+		"test/test_build.cpp": "int main() { return undefined_symbol; }\n",
+		"test/hang/test.cpp":  "int main() { for (;;) {} }\n",
+		"test/README.md":      "",
+		"test/.hidden/test.c": "",
+	})
+
+	res, err := Run(&Request{Path: libPath, Timeout: time.Second}, &bytes.Buffer{}, &bytes.Buffer{})
+	require.NoError(t, err)
+	status := map[string]Status{}
+	for _, suite := range res.Suites {
+		require.Len(t, suite.Cases, 1)
+		status[suite.Name] = suite.Cases[0].Status
+	}
+	require.Equal(t, map[string]Status{
+		"fail_test":  StatusError,
+		"hang":       StatusError,
+		"test_blink": StatusPassed,
+		"test_build": StatusError,
+	}, status)
+	require.Equal(t, 1, res.Passed)
+	require.Equal(t, 3, res.Errors)
+	require.False(t, res.Success())
+	require.Equal(t, "boom\n", res.Suites[0].Output)
+
+	res, err = Run(&Request{Path: libPath, Filter: "blink"}, &bytes.Buffer{}, &bytes.Buffer{})
+	require.NoError(t, err)
+	require.Len(t, res.Suites, 1)
+	require.True(t, res.Success())
+
+	_, err = Run(&Request{Path: libPath, Harness: HarnessUnity}, &bytes.Buffer{}, &bytes.Buffer{})
+	require.Error(t, err)
+	_, err = Run(&Request{Path: tmp}, &bytes.Buffer{}, &bytes.Buffer{})
+	require.Error(t, err)
+}
+
+func TestParseUnityOutput(t *testing.T) {
+	output := "test/test_math.c:12:test_add:PASS\r\n" +
+		"test/test_math.c:20:test_sub:FAIL: Expected 1 Was 2\n" +
+		"test/test_math.c:30:test_div:IGNORE\n" +
+		"\n-----------------------\n3 Tests 1 Failures 1 Ignored\nFAIL\n"
+	cases := parseUnityOutput(output)
+	require.Equal(t, []*Case{
+		{Name: "test_add", Status: StatusPassed, File: "test/test_math.c", Line: 12},
+		{Name: "test_sub", Status: StatusFailed, File: "test/test_math.c", Line: 20, Message: "Expected 1 Was 2"},
+		{Name: "test_div", Status: StatusSkipped, File: "test/test_math.c", Line: 30},
+	}, cases)
+}
+
+func TestParseGoogleTestReport(t *testing.T) {
+	report := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="3" failures="1">
+  <testsuite name="Math" tests="3">
+    <testcase name="Add" status="run" result="completed" time="0.001" file="test_math.cpp" line="4" />
+    <testcase name="Sub" status="run" result="completed" time="0" file="test_math.cpp" line="8">
+      <failure message="Expected equality" type=""></failure>
+    </testcase>
+    <testcase name="Div" status="run" result="skipped" time="0"><skipped message="" /></testcase>
+  </testsuite>
+</testsuites>`
+	cases, err := parseGoogleTestReport([]byte(report))
+	require.NoError(t, err)
+	require.Equal(t, []*Case{
+		{Name: "Math.Add", Status: StatusPassed, File: "test_math.cpp", Line: 4, Duration: 0.001},
+		{Name: "Math.Sub", Status: StatusFailed, File: "test_math.cpp", Line: 8, Message: "Expected equality"},
+		{Name: "Math.Div", Status: StatusSkipped},
+	}, cases)
+}
+
+func TestWriteJUnit(t *testing.T) {
+	res := &Result{}
+	res.add(&Suite{Name: "test_math", Duration: 0.5, Output: "3 Tests 1 Failures", Cases: []*Case{
+		{Name: "test_add", Status: StatusPassed},
+		{Name: "test_sub", Status: StatusFailed, Message: "Expected 1 Was 2"},
+	}})
+	res.add(&Suite{Name: "test_build", Cases: []*Case{{Name: "test_build", Status: StatusError, Message: "build failed"}}})
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteJUnit(res, &buf))
+	report := buf.String()
+	require.True(t, strings.HasPrefix(report, "<?xml"))
+	require.Contains(t, report, `<testsuites tests="3" failures="1" errors="1" skipped="0">`)
+	require.Contains(t, report, `<testsuite name="test_math" tests="2" failures="1" errors="0" skipped="0" time="0.500">`)
+	require.Contains(t, report, `<failure message="Expected 1 Was 2"></failure>`)
+	require.Contains(t, report, `<error message="build failed"></error>`)
+	require.Contains(t, report, `<system-out>3 Tests 1 Failures</system-out>`)
+}
+
+func TestCollectUnityResults(t *testing.T) {
+	r, w := io.Pipe()
+	go func() {
+		w.Write([]byte("booting\r\ntest_math.c:12:test_add:PASS\r\ntest_math.c:20:test_sub:FA"))
+		w.Write([]byte("IL: Expected 1 Was 2\r\n\r\n-----------------------\r\n2 Tests 1 Failures 0 Ignored\r\n"))
+		w.Write([]byte("FAIL\r\n"))
+	}()
+	var out bytes.Buffer
+	cases, received, err := collectUnityResults(r, time.Second, &out)
+	require.NoError(t, err)
+	require.Len(t, cases, 2)
+	require.Equal(t, StatusPassed, cases[0].Status)
+	require.Equal(t, StatusFailed, cases[1].Status)
+	require.Equal(t, "Expected 1 Was 2", cases[1].Message)
+	require.Equal(t, out.String(), received)
+	require.True(t, strings.HasPrefix(received, "booting"))
+
+	// The board stops responding after the first test
+	r, w = io.Pipe()
+	go w.Write([]byte("test_math.c:12:test_add:PASS\n"))
+	cases, _, err = collectUnityResults(r, 100*time.Millisecond, &out)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no test result received within 100ms")
+	require.Len(t, cases, 1)
+	w.Close()
+}
+
+func TestRunOnTargetRequest(t *testing.T) {
+	_, err := RunOnTarget(context.Background(), &TargetRequest{Request: Request{Path: paths.New(".")}}, &bytes.Buffer{}, &bytes.Buffer{})
+	require.EqualError(t, err, "on-target tests require the Unity harness")
+
+	tmp, err := paths.MkTempDir("", "test_runner")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+	createFiles(t, tmp, map[string]string{"unity/unity.c": ""})
+	req := &TargetRequest{Request: Request{Path: tmp, Harness: HarnessUnity, HarnessPath: tmp.Join("unity")}}
+	_, err = RunOnTarget(context.Background(), req, &bytes.Buffer{}, &bytes.Buffer{})
+	require.EqualError(t, err, "on-target tests require the board FQBN and port")
+}
@@ -19,6 +19,7 @@ import (
 	"context"
 	"io"
 
+	"github.com/arduino/arduino-cli/arduino/serialutils"
 	"github.com/arduino/arduino-cli/commands"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
 	"github.com/sirupsen/logrus"
@@ -34,7 +35,11 @@ func BurnBootloader(ctx context.Context, req *rpc.BurnBootloaderRequest, outStre
 
 	pm := commands.GetPackageManager(req.GetInstance().GetId())
 
-	err := runProgramAction(
+	fuses := fusesWithBootloader
+	if req.GetFusesOnly() {
+		fuses = fusesWrite
+	}
+	result, err := runProgramAction(
 		pm,
 		nil, // sketch
 		"",  // importFile
@@ -42,14 +47,27 @@ func BurnBootloader(ctx context.Context, req *rpc.BurnBootloaderRequest, outStre
 		req.GetFqbn(),
 		req.GetPort(),
 		req.GetProgrammer(),
+		0,   // uploadSpeed
+		nil, // toolArgs
+		"",  // networkPassword
+		serialutils.DefaultWaitTimeout,
+		0, // uploadRetries
 		req.GetVerbose(),
 		req.GetVerify(),
-		true, // burnBootloader
+		false, // verifyReadback
+		false, // dryRun
+		true,  // burnBootloader
+		fuses,
 		outStream,
 		errStream,
+		nil,
 	)
 	if err != nil {
+		// The fuses read before the failure help to understand it
+		if result != nil {
+			return &rpc.BurnBootloaderResponse{Fuses: result.GetFuses()}, err
+		}
 		return nil, err
 	}
-	return &rpc.BurnBootloaderResponse{}, nil
+	return &rpc.BurnBootloaderResponse{Fuses: result.GetFuses()}, nil
 }
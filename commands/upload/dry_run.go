@@ -0,0 +1,142 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package upload
+
+import (
+	"fmt"
+	"time"
+
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	paths "github.com/arduino/go-paths-helper"
+	properties "github.com/arduino/go-properties-orderedmap"
+)
+
+// resetSteps returns the steps performed to reset the board before the
+// upload, without touching the port
+func resetSteps(portToTouch string, wait bool, timeout time.Duration, fallbackPort string) []*rpc.UploadStep {
+	steps := []*rpc.UploadStep{{
+		Action:      "touch",
+		Description: fmt.Sprintf("Perform 1200-bps touch reset on serial port %s", portToTouch),
+	}}
+	if wait {
+		steps = append(steps, &rpc.UploadStep{
+			Action:      "wait_for_port",
+			Description: fmt.Sprintf("Wait up to %s for the upload port, falling back to %s", timeout, fallbackPort),
+		})
+	}
+	return steps
+}
+
+// recipeStep returns the step running the given recipe, or nil if the
+// recipe is empty
+func recipeStep(recipeID string, props *properties.Map, extraArgs []string, description string) (*rpc.UploadStep, error) {
+	cmdArgs, _, err := toolCommandLine(recipeID, props, extraArgs)
+	if err != nil {
+		return nil, err
+	}
+	if len(cmdArgs) == 0 {
+		return nil, nil
+	}
+	return &rpc.UploadStep{Action: recipeID, Description: description, Command: cmdArgs}, nil
+}
+
+// dryRunUploadSteps returns the steps that the upload would perform after
+// the board reset, with the fully expanded command lines of the tools
+func dryRunUploadSteps(props *properties.Map, toolID, arch string, toolArgs []string, burnBootloader bool, fuses fusesAction, isNetwork, usingProgrammer bool, readback *readbackRecipe) ([]*rpc.UploadStep, error) {
+	type recipe struct {
+		id, description string
+		extraArgs       []string
+	}
+	recipes := []recipe{}
+	steps := []*rpc.UploadStep{}
+	if burnBootloader && fuses != fusesWithBootloader {
+		fusesProps, description := props, ""
+		var err error
+		if fuses == fusesRead {
+			fusesProps, _, err = readFusesProperties(props, toolID)
+			description = "Read the fuses and the lock bits"
+		} else {
+			fusesProps, _, err = writeFusesProperties(props, toolID)
+			description = "Program the fuses"
+		}
+		if err != nil {
+			return nil, err
+		}
+		step, err := recipeStep("fuses.pattern", fusesProps, nil, description)
+		if err != nil {
+			return nil, err
+		}
+		return []*rpc.UploadStep{step}, nil
+	} else if burnBootloader {
+		recipes = append(recipes,
+			recipe{"erase.pattern", "Erase the chip", nil},
+			recipe{"bootloader.pattern", "Burn the bootloader", nil})
+	} else if isNetwork {
+		if useEspota(props, arch) {
+			steps = append(steps, &rpc.UploadStep{
+				Action: "network_upload",
+				Description: fmt.Sprintf("Upload %s to %s:%s with the built-in ArduinoOTA client",
+					espotaImagePath(props), props.Get("serial.port"), props.Get("network.port")),
+			})
+		} else if !props.ContainsKey("upload.network_pattern") {
+			return nil, fmt.Errorf("the board doesn't support the upload over the network")
+		} else {
+			recipes = append(recipes, recipe{"upload.network_pattern", "Upload the sketch over the network", toolArgs})
+		}
+	} else if usingProgrammer {
+		recipes = append(recipes, recipe{"program.pattern", "Upload the sketch using the programmer", toolArgs})
+	} else if props.ContainsKey("upload.pluggable_pattern") {
+		cmdLine := props.ExpandPropsInString(props.Get("upload.pluggable_pattern"))
+		cmdArgs, err := properties.SplitQuotedString(cmdLine, `"'`, false)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recipe '%s': %s", props.Get("upload.pluggable_pattern"), err)
+		}
+		steps = append(steps, &rpc.UploadStep{
+			Action:      "pluggable_upload",
+			Description: "Upload the sketch with the pluggable upload tool",
+			Command:     cmdArgs,
+		})
+	} else {
+		recipes = append(recipes, recipe{"upload.pattern", "Upload the sketch", toolArgs})
+	}
+
+	for _, r := range recipes {
+		step, err := recipeStep(r.id, props, r.extraArgs, r.description)
+		if err != nil {
+			return nil, err
+		}
+		if step != nil {
+			steps = append(steps, step)
+		}
+	}
+
+	if readback != nil {
+		image, err := loadUploadedImage(props, arch)
+		if err != nil {
+			return nil, fmt.Errorf("loading the uploaded image: %s", err)
+		}
+		// The real readback goes to a new temporary folder
+		readbackPath := paths.TempDir().Join("arduino-readback", props.Get("build.project_name")+".readback.bin")
+		readbackProps := readbackProperties(props, readback, image.Address, len(image.Data), readbackPath)
+		step, err := recipeStep("upload.readback_pattern", readbackProps, nil,
+			fmt.Sprintf("Read back %d bytes at 0x%X and compare them with the uploaded image", len(image.Data), image.Address))
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
@@ -0,0 +1,226 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package upload
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino/serialutils"
+	"github.com/arduino/arduino-cli/commands"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	properties "github.com/arduino/go-properties-orderedmap"
+	"github.com/sirupsen/logrus"
+)
+
+// fusesAction selects what is done with the fuses when burning the bootloader
+type fusesAction int
+
+const (
+	// fusesWithBootloader programs the fuses along with the bootloader
+	fusesWithBootloader fusesAction = iota
+	// fusesWrite only programs the fuses
+	fusesWrite
+	// fusesRead only reads the fuses
+	fusesRead
+)
+
+// boardFuses are the fuse memories of the AVR boards, with the properties
+// defining their values, in the order they're programmed
+var boardFuses = []struct {
+	name     string
+	property string
+}{
+	{"efuse", "bootloader.extended_fuses"},
+	{"hfuse", "bootloader.high_fuses"},
+	{"lfuse", "bootloader.low_fuses"},
+}
+
+// defaultFusesPatterns are the command lines used, if not defined by the
+// platform with fuses.pattern, to read or write the fuses. The arguments to
+// read or write each fuse, in the avrdude syntax, are in {fuses.args}.
+var defaultFusesPatterns = map[string]string{
+	"avrdude": `"{cmd.path}" "-C{config.path}" {erase.verbose} -p{build.mcu} -c{protocol} {program.extra_params} {fuses.args}`,
+}
+
+// fuseValueRegexp matches the fuse values printed by avrdude in hex format
+var fuseValueRegexp = regexp.MustCompile(`(?m)^\s*(0x[0-9A-Fa-f]+)\s*$`)
+
+// FusesRead reads the fuses and the lock bits of the board using a programmer
+func FusesRead(ctx context.Context, req *rpc.FusesReadRequest, outStream io.Writer, errStream io.Writer) (*rpc.FusesReadResponse, error) {
+	logrus.
+		WithField("fqbn", req.GetFqbn()).
+		WithField("port", req.GetPort()).
+		WithField("programmer", req.GetProgrammer()).
+		Trace("FusesRead started")
+
+	pm := commands.GetPackageManager(req.GetInstance().GetId())
+
+	result, err := runProgramAction(
+		pm,
+		nil, // sketch
+		"",  // importFile
+		"",  // importDir
+		req.GetFqbn(),
+		req.GetPort(),
+		req.GetProgrammer(),
+		0,   // uploadSpeed
+		nil, // toolArgs
+		"",  // networkPassword
+		serialutils.DefaultWaitTimeout,
+		0, // uploadRetries
+		req.GetVerbose(),
+		false, // verify
+		false, // verifyReadback
+		false, // dryRun
+		true,  // burnBootloader
+		fusesRead,
+		outStream,
+		errStream,
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &rpc.FusesReadResponse{Fuses: result.GetFuses()}, nil
+}
+
+// boardFuseValues returns the fuses defined by the board and, if withLock is
+// true, the lock bits
+func boardFuseValues(props *properties.Map, withLock bool) []*rpc.FuseValue {
+	res := []*rpc.FuseValue{}
+	add := func(name, property string) {
+		if value := strings.TrimSpace(props.Get(property)); value != "" {
+			res = append(res, &rpc.FuseValue{Name: name, Value: value, Expected: value})
+		}
+	}
+	for _, fuse := range boardFuses {
+		add(fuse.name, fuse.property)
+	}
+	if withLock {
+		add("lock", "bootloader.lock_bits")
+	}
+	return res
+}
+
+// fusesProperties returns a copy of props with the fuses.pattern recipe and
+// the given fuses.args set. The recipe is the one defined by the platform or
+// the default one for the tool.
+func fusesProperties(props *properties.Map, toolID string, args []string) (*properties.Map, error) {
+	pattern, ok := props.GetOk("fuses.pattern")
+	if !ok {
+		pattern, ok = defaultFusesPatterns[toolID]
+	}
+	if !ok {
+		return nil, fmt.Errorf("the tool %s doesn't support the fuses programming", toolID)
+	}
+	props = props.Clone()
+	props.Set("fuses.pattern", pattern)
+	props.Set("fuses.args", strings.Join(args, " "))
+	return props, nil
+}
+
+// writeFusesProperties returns the properties to program the fuses of the
+// board, and the fuses programmed
+func writeFusesProperties(props *properties.Map, toolID string) (*properties.Map, []*rpc.FuseValue, error) {
+	// The lock bits are not programmed: once set, they can be cleared
+	// only by erasing the chip
+	fuses := boardFuseValues(props, false)
+	if len(fuses) == 0 {
+		return nil, nil, fmt.Errorf("the board doesn't define any fuse")
+	}
+	args := []string{}
+	for _, fuse := range fuses {
+		args = append(args, fmt.Sprintf("-U%s:w:%s:m", fuse.Name, fuse.Value))
+	}
+	fusesProps, err := fusesProperties(props, toolID, args)
+	return fusesProps, fuses, err
+}
+
+// readFusesProperties returns the properties to read the fuses and the lock
+// bits of the board, and the fuses to read
+func readFusesProperties(props *properties.Map, toolID string) (*properties.Map, []*rpc.FuseValue, error) {
+	fuses := boardFuseValues(props, true)
+	if len(fuses) == 0 {
+		return nil, nil, fmt.Errorf("the board doesn't define any fuse")
+	}
+	args := []string{}
+	for _, fuse := range fuses {
+		fuse.Value = ""
+		args = append(args, fmt.Sprintf("-U%s:r:-:h", fuse.Name))
+	}
+	fusesProps, err := fusesProperties(props, toolID, args)
+	return fusesProps, fuses, err
+}
+
+// runFusesWrite programs the fuses of the board
+func runFusesWrite(props *properties.Map, toolID string, outStream, errStream io.Writer, verbose bool, result *rpc.UploadResult) error {
+	fusesProps, fuses, err := writeFusesProperties(props, toolID)
+	if err != nil {
+		return err
+	}
+	if err := RunTool("fuses.pattern", fusesProps, nil, outStream, errStream, verbose); err != nil {
+		return err
+	}
+	result.Fuses = fuses
+	return nil
+}
+
+// runFusesRead reads the fuses and the lock bits of the board. The values
+// are printed by the tool on the standard output, in the order they're
+// requested, so the output is not forwarded to outStream.
+func runFusesRead(props *properties.Map, toolID string, outStream, errStream io.Writer, verbose bool, result *rpc.UploadResult) error {
+	fusesProps, fuses, err := readFusesProperties(props, toolID)
+	if err != nil {
+		return err
+	}
+	var out bytes.Buffer
+	if err := RunTool("fuses.pattern", fusesProps, nil, &out, errStream, verbose); err != nil {
+		return err
+	}
+	values, err := parseFuseValues(out.String(), len(fuses))
+	if err != nil {
+		return err
+	}
+	for i, fuse := range fuses {
+		fuse.Value = values[i]
+	}
+	result.Fuses = fuses
+	return nil
+}
+
+// parseFuseValues returns the count fuse values found in the output of the
+// tool, formatted as 0xNN
+func parseFuseValues(output string, count int) ([]string, error) {
+	matches := fuseValueRegexp.FindAllStringSubmatch(output, -1)
+	if len(matches) != count {
+		return nil, fmt.Errorf("expected %d fuse values from the tool, found %d", count, len(matches))
+	}
+	res := []string{}
+	for _, match := range matches {
+		value, err := strconv.ParseUint(match[1], 0, 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fuse value %s: %s", match[1], err)
+		}
+		res = append(res, fmt.Sprintf("0x%02X", value))
+	}
+	return res, nil
+}
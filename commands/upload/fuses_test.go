@@ -0,0 +1,87 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package upload
+
+import (
+	"strings"
+	"testing"
+
+	properties "github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func avrFusesProperties() *properties.Map {
+	props := properties.NewMap()
+	props.Set("cmd.path", "/tools/avrdude")
+	props.Set("config.path", "/tools/avrdude.conf")
+	props.Set("erase.verbose", "-q -q")
+	props.Set("build.mcu", "atmega328p")
+	props.Set("protocol", "usbasp")
+	props.Set("program.extra_params", "-Pusb")
+	props.Set("bootloader.low_fuses", "0xFF")
+	props.Set("bootloader.high_fuses", "0xDE")
+	props.Set("bootloader.extended_fuses", "0xFD")
+	props.Set("bootloader.unlock_bits", "0x3F")
+	props.Set("bootloader.lock_bits", "0x0F")
+	return props
+}
+
+func TestWriteFusesProperties(t *testing.T) {
+	fusesProps, fuses, err := writeFusesProperties(avrFusesProperties(), "avrdude")
+	require.NoError(t, err)
+	require.Len(t, fuses, 3)
+	require.Equal(t, "efuse", fuses[0].Name)
+	require.Equal(t, "0xFD", fuses[0].Value)
+	args, _, err := toolCommandLine("fuses.pattern", fusesProps, nil)
+	require.NoError(t, err)
+	require.Equal(t, "/tools/avrdude -C/tools/avrdude.conf -q -q -patmega328p -cusbasp -Pusb -Uefuse:w:0xFD:m -Uhfuse:w:0xDE:m -Ulfuse:w:0xFF:m", strings.Join(args, " "))
+
+	_, _, err = writeFusesProperties(avrFusesProperties(), "bossac")
+	require.EqualError(t, err, "the tool bossac doesn't support the fuses programming")
+
+	// The recipe of the platform takes precedence
+	props := avrFusesProperties()
+	props.Set("fuses.pattern", "myavrdude {fuses.args}")
+	fusesProps, _, err = writeFusesProperties(props, "bossac")
+	require.NoError(t, err)
+	args, _, err = toolCommandLine("fuses.pattern", fusesProps, nil)
+	require.NoError(t, err)
+	require.Equal(t, "myavrdude -Uefuse:w:0xFD:m -Uhfuse:w:0xDE:m -Ulfuse:w:0xFF:m", strings.Join(args, " "))
+
+	_, _, err = writeFusesProperties(properties.NewMap(), "avrdude")
+	require.EqualError(t, err, "the board doesn't define any fuse")
+}
+
+func TestReadFusesProperties(t *testing.T) {
+	props := avrFusesProperties()
+	props.Remove("bootloader.extended_fuses")
+	fusesProps, fuses, err := readFusesProperties(props, "avrdude")
+	require.NoError(t, err)
+	require.Len(t, fuses, 3)
+	require.Equal(t, "lock", fuses[2].Name)
+	require.Equal(t, "0x0F", fuses[2].Expected)
+	require.Empty(t, fuses[2].Value)
+	require.Equal(t, "-Uhfuse:r:-:h -Ulfuse:r:-:h -Ulock:r:-:h", fusesProps.Get("fuses.args"))
+}
+
+func TestParseFuseValues(t *testing.T) {
+	values, err := parseFuseValues("0xfd\n0xde\r\n0xff\n0xcf\n", 4)
+	require.NoError(t, err)
+	require.Equal(t, []string{"0xFD", "0xDE", "0xFF", "0xCF"}, values)
+
+	_, err = parseFuseValues("0xfd\n", 2)
+	require.EqualError(t, err, "expected 2 fuse values from the tool, found 1")
+}
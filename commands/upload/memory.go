@@ -0,0 +1,270 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package upload
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino/cores/packagemanager"
+	"github.com/arduino/arduino-cli/arduino/serialutils"
+	"github.com/arduino/arduino-cli/commands"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	paths "github.com/arduino/go-paths-helper"
+	properties "github.com/arduino/go-properties-orderedmap"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	avrdudeUploadPrefix  = `"{cmd.path}" "-C{config.path}" {upload.verbose} -p{build.mcu} -c{upload.protocol} "-P{serial.port}" -b{upload.speed} -D`
+	avrdudeProgramPrefix = `"{cmd.path}" "-C{config.path}" {program.verbose} -p{build.mcu} -c{protocol} {program.extra_params}`
+)
+
+// defaultProgramReadbackRecipes are the recipes used, if not defined by the
+// platform with program.readback_pattern, to read the flash with a programmer
+var defaultProgramReadbackRecipes = map[string]*readbackRecipe{
+	"avrdude": {
+		pattern:    avrdudeProgramPrefix + ` "-Uflash:r:{readback.path}:r"`,
+		wholeFlash: true,
+	},
+}
+
+// defaultEepromRecipes are the recipes used, if not defined by the platform,
+// to read and write the EEPROM with the most common tools. The content of
+// the EEPROM is read into, or written from, the {eeprom.path} file.
+var defaultEepromRecipes = map[string]map[string]string{
+	"avrdude": {
+		"upload.eeprom_read_pattern":   avrdudeUploadPrefix + ` "-Ueeprom:r:{eeprom.path}:r"`,
+		"upload.eeprom_write_pattern":  avrdudeUploadPrefix + ` "-Ueeprom:w:{eeprom.path}:r"`,
+		"program.eeprom_read_pattern":  avrdudeProgramPrefix + ` "-Ueeprom:r:{eeprom.path}:r"`,
+		"program.eeprom_write_pattern": avrdudeProgramPrefix + ` "-Ueeprom:w:{eeprom.path}:r"`,
+	},
+}
+
+// MemoryRead reads the flash or the EEPROM of the board into a file
+func MemoryRead(ctx context.Context, req *rpc.MemoryReadRequest, outStream io.Writer, errStream io.Writer) (*rpc.MemoryReadResponse, error) {
+	logrus.
+		WithField("fqbn", req.GetFqbn()).
+		WithField("port", req.GetPort()).
+		WithField("memory", req.GetMemory()).
+		Trace("MemoryRead started")
+
+	if req.GetOutputPath() == "" {
+		return nil, fmt.Errorf("no output file provided")
+	}
+	outputPath, err := paths.New(req.GetOutputPath()).Abs()
+	if err != nil {
+		return nil, fmt.Errorf("invalid output file: %s", err)
+	}
+
+	pm := commands.GetPackageManager(req.GetInstance().GetId())
+	target, err := resolveMemoryTarget(pm, req.GetFqbn(), req.GetPort(), req.GetProgrammer(), req.GetVerbose(), outStream, errStream)
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.GetMemory() {
+	case "flash":
+		err = readFlash(target, req.GetOffset(), req.GetSize(), outputPath, outStream, errStream, req.GetVerbose())
+	case "eeprom":
+		err = runEepromRecipe(target, "eeprom_read", outputPath, outStream, errStream, req.GetVerbose())
+	default:
+		return nil, fmt.Errorf("invalid memory: %s", req.GetMemory())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %s", req.GetMemory(), err)
+	}
+
+	info, err := outputPath.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %s", req.GetMemory(), err)
+	}
+	return &rpc.MemoryReadResponse{Size: uint64(info.Size())}, nil
+}
+
+// MemoryWrite writes the content of a file into the EEPROM of the board
+func MemoryWrite(ctx context.Context, req *rpc.MemoryWriteRequest, outStream io.Writer, errStream io.Writer) (*rpc.MemoryWriteResponse, error) {
+	logrus.
+		WithField("fqbn", req.GetFqbn()).
+		WithField("port", req.GetPort()).
+		WithField("memory", req.GetMemory()).
+		Trace("MemoryWrite started")
+
+	if req.GetMemory() != "eeprom" {
+		return nil, fmt.Errorf("writing %s is not supported", req.GetMemory())
+	}
+	inputPath, err := paths.New(req.GetInputPath()).Abs()
+	if err != nil {
+		return nil, fmt.Errorf("invalid input file: %s", err)
+	}
+	if !inputPath.Exist() {
+		return nil, fmt.Errorf("input file not found: %s", inputPath)
+	}
+
+	pm := commands.GetPackageManager(req.GetInstance().GetId())
+	target, err := resolveMemoryTarget(pm, req.GetFqbn(), req.GetPort(), req.GetProgrammer(), req.GetVerbose(), outStream, errStream)
+	if err != nil {
+		return nil, err
+	}
+	if err := runEepromRecipe(target, "eeprom_write", inputPath, outStream, errStream, req.GetVerbose()); err != nil {
+		return nil, fmt.Errorf("writing %s: %s", req.GetMemory(), err)
+	}
+	return &rpc.MemoryWriteResponse{}, nil
+}
+
+// resolveMemoryTarget selects the tool used to access the memory of the
+// board: the programmer one or the upload one. In the latter case the board
+// is reset, if needed, to start the bootloader.
+func resolveMemoryTarget(pm *packagemanager.PackageManager, fqbn, port, programmerID string, verbose bool, outStream, errStream io.Writer) (*uploadTarget, error) {
+	toolProperty := "upload.tool"
+	if programmerID != "" {
+		toolProperty = "program.tool"
+	}
+	target, err := resolveUploadTarget(pm, fqbn, programmerID, toolProperty, false, 0, verbose, false, errStream)
+	if err != nil {
+		return nil, err
+	}
+	props := target.properties
+	if target.programmer == nil {
+		if port == "" {
+			return nil, fmt.Errorf("no upload port provided")
+		}
+		if props.GetBoolean("upload.use_1200bps_touch") {
+			wait := props.GetBoolean("upload.wait_for_upload_port")
+			if newPort := resetBoard(port, wait, serialutils.DefaultWaitTimeout, port, verbose, outStream); newPort != "" {
+				port = newPort
+			}
+		}
+	}
+	if port != "" {
+		setSerialPortProperties(props, port)
+	}
+	return target, nil
+}
+
+// findMemoryReadbackRecipe returns the recipe to read the flash with the
+// programmer or with the upload tool, or nil if not supported by the tool
+func findMemoryReadbackRecipe(target *uploadTarget) *readbackRecipe {
+	if target.programmer == nil {
+		return findReadbackRecipe(target.properties, target.toolID)
+	}
+	if pattern, ok := target.properties.GetOk("program.readback_pattern"); ok {
+		return &readbackRecipe{
+			pattern:    pattern,
+			wholeFlash: target.properties.GetBoolean("program.readback_whole_flash"),
+		}
+	}
+	return defaultProgramReadbackRecipes[target.toolID]
+}
+
+// readFlash reads size bytes of flash, starting at offset, into outputPath.
+// If size is 0 the flash is read up to its end.
+func readFlash(target *uploadTarget, offset, size uint32, outputPath *paths.Path, outStream, errStream io.Writer, verbose bool) error {
+	recipe := findMemoryReadbackRecipe(target)
+	if recipe == nil {
+		return fmt.Errorf("the tool %s doesn't support reading the flash", target.toolID)
+	}
+	if size == 0 && !recipe.wholeFlash {
+		flashSize, err := boardFlashSize(target.properties)
+		if err != nil {
+			return err
+		}
+		if offset >= flashSize {
+			return fmt.Errorf("offset 0x%X is beyond the end of the flash (%d bytes)", offset, flashSize)
+		}
+		size = flashSize - offset
+	}
+
+	tmp, err := paths.MkTempDir("", "arduino-flash-")
+	if err != nil {
+		return err
+	}
+	defer tmp.RemoveAll()
+	readbackPath := tmp.Join("flash.bin")
+
+	props := readbackProperties(target.properties, recipe, offset, int(size), readbackPath)
+	if err := RunTool("upload.readback_pattern", props, nil, outStream, errStream, verbose); err != nil {
+		return err
+	}
+	data, err := readbackPath.ReadFile()
+	if err != nil {
+		return fmt.Errorf("reading the flash content: %s", err)
+	}
+	if recipe.wholeFlash {
+		if uint32(len(data)) < offset {
+			return fmt.Errorf("offset 0x%X is beyond the end of the flash (%d bytes)", offset, len(data))
+		}
+		data = data[offset:]
+		if size != 0 && uint32(len(data)) > size {
+			data = data[:size]
+		}
+	}
+	return outputPath.WriteFile(data)
+}
+
+// boardFlashSize returns the size of the flash of the board, defined by the
+// build.flash_size property (e.g. 4MB) or, if missing, by
+// upload.maximum_size
+func boardFlashSize(props *properties.Map) (uint32, error) {
+	if s, ok := props.GetOk("build.flash_size"); ok {
+		return parseFlashSize(s)
+	}
+	if s, ok := props.GetOk("upload.maximum_size"); ok {
+		return parseFlashSize(s)
+	}
+	return 0, fmt.Errorf("the flash size of the board is unknown, specify the size to read")
+}
+
+// parseFlashSize parses a size in bytes, optionally followed by the K or M
+// (or KB and MB) units
+func parseFlashSize(size string) (uint32, error) {
+	s := strings.ToUpper(strings.TrimSpace(size))
+	s = strings.TrimSuffix(s, "B")
+	multiplier := uint64(1)
+	if strings.HasSuffix(s, "K") {
+		multiplier = 1024
+	} else if strings.HasSuffix(s, "M") {
+		multiplier = 1024 * 1024
+	}
+	s = strings.TrimRight(s, "KM")
+	n, err := strconv.ParseUint(s, 0, 32)
+	if err != nil || n*multiplier > 0xFFFFFFFF {
+		return 0, fmt.Errorf("invalid flash size: %s", size)
+	}
+	return uint32(n * multiplier), nil
+}
+
+// runEepromRecipe runs the eeprom_read or eeprom_write recipe of the
+// programmer or of the upload tool, with {eeprom.path} set to file
+func runEepromRecipe(target *uploadTarget, name string, file *paths.Path, outStream, errStream io.Writer, verbose bool) error {
+	recipeID := "upload." + name + "_pattern"
+	if target.programmer != nil {
+		recipeID = "program." + name + "_pattern"
+	}
+	props := target.properties.Clone()
+	if !props.ContainsKey(recipeID) {
+		pattern, ok := defaultEepromRecipes[target.toolID][recipeID]
+		if !ok {
+			return fmt.Errorf("the tool %s doesn't support the EEPROM access", target.toolID)
+		}
+		props.Set(recipeID, pattern)
+	}
+	props.SetPath("eeprom.path", file)
+	return RunTool(recipeID, props, nil, outStream, errStream, verbose)
+}
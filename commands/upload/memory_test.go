@@ -0,0 +1,111 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package upload
+
+import (
+	"bytes"
+	"runtime"
+	"testing"
+
+	paths "github.com/arduino/go-paths-helper"
+	properties "github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFlashSize(t *testing.T) {
+	for s, expected := range map[string]uint32{
+		"32256": 32256,
+		"4MB":   4 * 1024 * 1024,
+		"16M":   16 * 1024 * 1024,
+		"512KB": 512 * 1024,
+		"0x400": 1024,
+	} {
+		size, err := parseFlashSize(s)
+		require.NoError(t, err, s)
+		require.Equal(t, expected, size, s)
+	}
+	_, err := parseFlashSize("big")
+	require.EqualError(t, err, "invalid flash size: big")
+	_, err = parseFlashSize("8192MB")
+	require.Error(t, err)
+}
+
+func TestReadFlash(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test recipe uses cp")
+	}
+	tmp, err := paths.MkTempDir("", "flash-test")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+	require.NoError(t, tmp.Join("flash.bin").WriteFile([]byte{0, 1, 2, 3, 4, 5, 6, 7}))
+	output := tmp.Join("dump.bin")
+
+	props := properties.NewMap()
+	props.SetPath("test.path", tmp)
+	props.Set("upload.readback_pattern", `cp "{test.path}/flash.bin" "{readback.path}"`)
+	props.Set("upload.readback_whole_flash", "true")
+	target := &uploadTarget{toolID: "test", properties: props}
+	out := &bytes.Buffer{}
+
+	// The whole flash is cut to the requested range
+	require.NoError(t, readFlash(target, 2, 3, output, out, out, false))
+	data, err := output.ReadFile()
+	require.NoError(t, err)
+	require.Equal(t, []byte{2, 3, 4}, data)
+
+	require.NoError(t, readFlash(target, 6, 0, output, out, out, false))
+	data, err = output.ReadFile()
+	require.NoError(t, err)
+	require.Equal(t, []byte{6, 7}, data)
+
+	// The tools reading a range need the flash size
+	props.Set("upload.readback_whole_flash", "false")
+	err = readFlash(target, 0, 0, output, out, out, false)
+	require.EqualError(t, err, "the flash size of the board is unknown, specify the size to read")
+	props.Set("upload.maximum_size", "8")
+	props.Set("upload.readback_pattern", `sh -c "echo {readback.offset} {readback.size} > '{readback.path}'"`)
+	require.NoError(t, readFlash(target, 2, 0, output, out, out, false))
+	data, err = output.ReadFile()
+	require.NoError(t, err)
+	require.Equal(t, "0x2 6\n", string(data))
+
+	target = &uploadTarget{toolID: "openocd", properties: properties.NewMap()}
+	err = readFlash(target, 0, 0, output, out, out, false)
+	require.EqualError(t, err, "the tool openocd doesn't support reading the flash")
+}
+
+func TestRunEepromRecipe(t *testing.T) {
+	props := properties.NewMap()
+	props.Set("cmd.path", "/tools/avrdude")
+	props.Set("config.path", "/tools/avrdude.conf")
+	props.Set("build.mcu", "atmega328p")
+	props.Set("upload.verbose", "-q")
+	props.Set("upload.protocol", "arduino")
+	props.Set("upload.speed", "115200")
+	props.Set("serial.port", "/dev/ttyACM0")
+	props.Set("upload.eeprom_read_pattern", defaultEepromRecipes["avrdude"]["upload.eeprom_read_pattern"])
+	props.SetPath("eeprom.path", paths.New("/tmp/eeprom.bin"))
+	args, _, err := toolCommandLine("upload.eeprom_read_pattern", props, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"/tools/avrdude", "-C/tools/avrdude.conf", "-q", "-patmega328p", "-carduino", "-P/dev/ttyACM0", "-b115200", "-D",
+		"-Ueeprom:r:/tmp/eeprom.bin:r",
+	}, args)
+
+	target := &uploadTarget{toolID: "bossac", properties: properties.NewMap()}
+	err = runEepromRecipe(target, "eeprom_read", paths.New("eeprom.bin"), nil, nil, false)
+	require.EqualError(t, err, "the tool bossac doesn't support the EEPROM access")
+}
@@ -0,0 +1,145 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package upload
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arduino/arduino-cli/arduino/espota"
+	"github.com/arduino/arduino-cli/commands"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	paths "github.com/arduino/go-paths-helper"
+	properties "github.com/arduino/go-properties-orderedmap"
+)
+
+// hostnameRegexp matches the host names that can't be serial ports: the
+// ones containing a dot, like the mDNS names (e.g. esp32-123abc.local)
+var hostnameRegexp = regexp.MustCompile(`^[A-Za-z0-9-]+(\.[A-Za-z0-9-]+)+$`)
+
+// parseNetworkPort returns the host and the port of a network upload port,
+// given in the <ip>[:port] or <hostname>[:port] form. The port is 0 if not
+// specified. Serial ports are not matched.
+func parseNetworkPort(port string) (string, int, bool) {
+	if strings.HasPrefix(port, "/") || port == "" {
+		return "", 0, false
+	}
+	host, portNumber := port, 0
+	if h, p, err := net.SplitHostPort(port); err == nil {
+		n, err := strconv.Atoi(p)
+		if err != nil || n <= 0 || n > 65535 {
+			return "", 0, false
+		}
+		host, portNumber = h, n
+	}
+	if net.ParseIP(host) == nil && !hostnameRegexp.MatchString(host) {
+		return "", 0, false
+	}
+	return host, portNumber, true
+}
+
+// Default ports of the ArduinoOTA library of the ESP platforms
+var espotaDefaultPorts = map[string]int{
+	"esp8266": 8266,
+	"esp32":   3232,
+}
+
+// setNetworkProperties sets the properties used by the network upload
+// recipes: serial.port is set to the host, like the Arduino IDE does.
+func setNetworkProperties(props *properties.Map, arch, host string, port int, password string) {
+	if port == 0 {
+		if p, err := strconv.Atoi(props.Get("upload.network.port")); err == nil {
+			port = p
+		} else {
+			port = espotaDefaultPorts[arch]
+		}
+	}
+	props.Set("serial.port", host)
+	props.Set("serial.port.file", host)
+	if port != 0 {
+		props.Set("network.port", strconv.Itoa(port))
+	}
+	props.Set("network.password", password)
+}
+
+// useEspota returns true if the board must be uploaded over the network with
+// the built-in ArduinoOTA client
+func useEspota(props *properties.Map, arch string) bool {
+	protocol := props.Get("upload.network.protocol")
+	if protocol == "" {
+		if _, ok := espotaDefaultPorts[arch]; ok {
+			protocol = "espota"
+		}
+	}
+	return protocol == "espota"
+}
+
+// espotaImagePath returns the path of the binary uploaded by the ArduinoOTA
+// client
+func espotaImagePath(props *properties.Map) *paths.Path {
+	return props.GetPath("build.path").Join(props.Get("build.project_name") + ".bin")
+}
+
+// runNetworkUpload uploads the sketch to a board on the network. The boards
+// of the ESP platforms, or the ones with upload.network.protocol=espota, are
+// uploaded with the built-in ArduinoOTA client, the other ones with the
+// upload.network_pattern recipe of the platform.
+func runNetworkUpload(props *properties.Map, arch string, toolArgs []string, outStream, errStream io.Writer, verbose bool, progressCB commands.UploadProgressCB, result *rpc.UploadResult) error {
+	if !useEspota(props, arch) {
+		if !props.ContainsKey("upload.network_pattern") {
+			return fmt.Errorf("the board doesn't support the upload over the network")
+		}
+		return runUploadTool("upload.network_pattern", props, toolArgs, outStream, errStream, verbose, result)
+	}
+
+	imagePath := espotaImagePath(props)
+	image, err := imagePath.ReadFile()
+	if err != nil {
+		return fmt.Errorf("reading the sketch binary: %s", err)
+	}
+	sum := sha256.Sum256(image)
+	result.ImagePath = imagePath.String()
+	result.ImageSha256 = hex.EncodeToString(sum[:])
+
+	host := props.Get("serial.port")
+	port, err := strconv.Atoi(props.Get("network.port"))
+	if err != nil {
+		return fmt.Errorf("invalid network port: %s", props.Get("network.port"))
+	}
+	if verbose {
+		fmt.Fprintf(outStream, "Uploading %s to %s:%d\n", imagePath, host, port)
+	}
+
+	progress := func(sent, total int) {
+		progressCB(&rpc.UploadProgress{Stage: "Uploading", Current: int64(sent), Total: int64(total)})
+	}
+	start := time.Now()
+	err = espota.Upload(host, port, props.Get("network.password"), espota.Flash, imagePath.Base(), image, progress)
+	result.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		result.ExitCode = -1
+		return err
+	}
+	result.BytesWritten = int64(len(image))
+	return nil
+}
@@ -0,0 +1,82 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package upload
+
+import (
+	"bytes"
+	"testing"
+
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	properties "github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNetworkPort(t *testing.T) {
+	tests := []struct {
+		port string
+		host string
+		num  int
+		ok   bool
+	}{
+		{"192.168.1.10", "192.168.1.10", 0, true},
+		{"192.168.1.10:8266", "192.168.1.10", 8266, true},
+		{"esp32-123abc.local", "esp32-123abc.local", 0, true},
+		{"esp32-123abc.local:3232", "esp32-123abc.local", 3232, true},
+		{"[fe80::1]:3232", "fe80::1", 3232, true},
+		{"/dev/ttyACM0", "", 0, false},
+		{"/dev/cu.usbserial-1410", "", 0, false},
+		{"COM3", "", 0, false},
+		{"192.168.1.10:99999", "", 0, false},
+		{"", "", 0, false},
+	}
+	for _, test := range tests {
+		host, num, ok := parseNetworkPort(test.port)
+		require.Equal(t, test.ok, ok, test.port)
+		require.Equal(t, test.host, host, test.port)
+		require.Equal(t, test.num, num, test.port)
+	}
+}
+
+func TestSetNetworkProperties(t *testing.T) {
+	props := properties.NewMap()
+	setNetworkProperties(props, "esp32", "192.168.1.10", 0, "secret")
+	require.Equal(t, "192.168.1.10", props.Get("serial.port"))
+	require.Equal(t, "3232", props.Get("network.port"))
+	require.Equal(t, "secret", props.Get("network.password"))
+
+	setNetworkProperties(props, "esp8266", "192.168.1.10", 0, "")
+	require.Equal(t, "8266", props.Get("network.port"))
+
+	setNetworkProperties(props, "esp8266", "192.168.1.10", 1234, "")
+	require.Equal(t, "1234", props.Get("network.port"))
+
+	props = properties.NewMap()
+	props.Set("upload.network.port", "65280")
+	setNetworkProperties(props, "samd", "192.168.1.10", 0, "")
+	require.Equal(t, "65280", props.Get("network.port"))
+
+	props = properties.NewMap()
+	setNetworkProperties(props, "avr", "192.168.1.10", 0, "")
+	require.False(t, props.ContainsKey("network.port"))
+}
+
+func TestRunNetworkUploadWithoutRecipe(t *testing.T) {
+	props := properties.NewMap()
+	setNetworkProperties(props, "avr", "192.168.1.10", 0, "")
+	out := &bytes.Buffer{}
+	err := runNetworkUpload(props, "avr", nil, out, out, false, nil, &rpc.UploadResult{})
+	require.EqualError(t, err, "the board doesn't support the upload over the network")
+}
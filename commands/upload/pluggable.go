@@ -0,0 +1,86 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package upload
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/arduino/arduino-cli/arduino/uploader"
+	"github.com/arduino/arduino-cli/commands"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	properties "github.com/arduino/go-properties-orderedmap"
+	"github.com/sirupsen/logrus"
+)
+
+// runPluggableUpload uploads the sketch with the pluggable upload tool
+// launched by the upload.pluggable_pattern recipe. The parameters of the
+// upload are sent to the tool, which reports back its progress.
+func runPluggableUpload(props *properties.Map, fqbn string, toolArgs []string, outStream, errStream io.Writer, verbose, verify bool, progressCB commands.UploadProgressCB, result *rpc.UploadResult) error {
+	recipe := props.Get("upload.pluggable_pattern")
+	cmdLine := props.ExpandPropsInString(recipe)
+	cmdArgs, err := properties.SplitQuotedString(cmdLine, `"'`, false)
+	if err != nil {
+		return fmt.Errorf("invalid recipe '%s': %s", recipe, err)
+	}
+	if verbose {
+		outStream.Write([]byte(fmt.Sprintln(cmdLine)))
+	}
+
+	up, err := uploader.New(props.Get("upload.tool"), cmdArgs...)
+	if err != nil {
+		return fmt.Errorf("cannot execute upload tool: %s", err)
+	}
+	up.RedirectStderrTo(errStream)
+	if err := up.Run(); err != nil {
+		return fmt.Errorf("cannot execute upload tool: %s", err)
+	}
+	defer func() {
+		if err := up.Quit(); err != nil {
+			logrus.Warnf("Terminating upload tool: %s", err)
+		}
+	}()
+
+	params := &uploader.Params{
+		Port:        props.Get("serial.port"),
+		Fqbn:        fqbn,
+		BuildPath:   props.Get("build.path"),
+		ProjectName: props.Get("build.project_name"),
+		Verbose:     verbose,
+		Verify:      verify,
+		ToolArgs:    toolArgs,
+		Properties:  props.AsMap(),
+	}
+	if params.Port != "" {
+		params.Protocol = "serial"
+	}
+	start := time.Now()
+	res, err := up.Upload(params, outStream, errStream, func(p *uploader.Progress) {
+		progressCB(&rpc.UploadProgress{Stage: p.Stage, Current: p.Current, Total: p.Total})
+	})
+	result.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		result.ExitCode = -1
+		return err
+	}
+	result.BytesWritten = res.BytesWritten
+	if strings.TrimSpace(res.Port) != "" {
+		result.Port = res.Port
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package upload
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+)
+
+// programmerNotAvailableError returns the error reported when the requested
+// programmer is not defined by the board platform or by the referenced build
+// platform. The error lists the available programmers and, when possible,
+// suggests the one with the closest name.
+func programmerNotAvailableError(programmerID string, fqbn *cores.FQBN, platforms ...*cores.PlatformRelease) error {
+	available := availableProgrammers(platforms...)
+	if len(available) == 0 {
+		return fmt.Errorf("programmer '%s' not available: no programmers are defined for board %s", programmerID, fqbn.StringWithoutConfig())
+	}
+	msg := fmt.Sprintf("programmer '%s' not available for board %s", programmerID, fqbn.StringWithoutConfig())
+	if suggestion := closestProgrammer(programmerID, available); suggestion != "" {
+		msg += fmt.Sprintf(", did you mean '%s'?", suggestion)
+	}
+	return fmt.Errorf("%s (available programmers: %s)", msg, strings.Join(available, ", "))
+}
+
+// availableProgrammers returns the sorted ids of the programmers defined by
+// the given platforms.
+func availableProgrammers(platforms ...*cores.PlatformRelease) []string {
+	ids := []string{}
+	seen := map[string]bool{}
+	for _, platform := range platforms {
+		if platform == nil {
+			continue
+		}
+		for id := range platform.Programmers {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// closestProgrammer returns the id in available that best matches
+// programmerID, or an empty string if none is similar enough. Ties are
+// resolved in favor of the first id in available.
+func closestProgrammer(programmerID string, available []string) string {
+	lowerID := strings.ToLower(programmerID)
+	best := ""
+	bestDistance := len(lowerID)/3 + 2
+	for _, id := range available {
+		if d := editDistance(lowerID, strings.ToLower(id)); d < bestDistance {
+			best = id
+			bestDistance = d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min3(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -44,20 +44,13 @@ func ListProgrammersAvailableForUpload(ctx context.Context, req *rpc.ListProgram
 	}
 
 	result := []*rpc.Programmer{}
-	createRPCProgrammer := func(id string, programmer *cores.Programmer) *rpc.Programmer {
-		return &rpc.Programmer{
-			Id:       id,
-			Platform: programmer.PlatformRelease.String(),
-			Name:     programmer.Name,
-		}
-	}
 	if refPlatform != platform {
 		for id, programmer := range refPlatform.Programmers {
-			result = append(result, createRPCProgrammer(id, programmer))
+			result = append(result, commands.ProgrammerToRPC(id, programmer))
 		}
 	}
 	for id, programmer := range platform.Programmers {
-		result = append(result, createRPCProgrammer(id, programmer))
+		result = append(result, commands.ProgrammerToRPC(id, programmer))
 	}
 
 	return &rpc.ListProgrammersAvailableForUploadResponse{
@@ -0,0 +1,189 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package upload
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	bldr "github.com/arduino/arduino-cli/arduino/builder"
+	"github.com/arduino/arduino-cli/arduino/serialutils"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	paths "github.com/arduino/go-paths-helper"
+	properties "github.com/arduino/go-properties-orderedmap"
+	"github.com/pkg/errors"
+)
+
+// readbackRecipe is a command line that reads the flash of the board into
+// the {readback.path} file
+type readbackRecipe struct {
+	pattern string
+	// wholeFlash is true if the tool reads the flash from address 0,
+	// ignoring {readback.offset} and {readback.size}
+	wholeFlash bool
+}
+
+// defaultReadbackRecipes are the recipes used, if not defined by the
+// platform, for the most common upload tools
+var defaultReadbackRecipes = map[string]*readbackRecipe{
+	"avrdude": {
+		pattern:    `"{cmd.path}" "-C{config.path}" {upload.verbose} -p{build.mcu} -c{upload.protocol} "-P{serial.port}" -b{upload.speed} -D "-Uflash:r:{readback.path}:r"`,
+		wholeFlash: true,
+	},
+	"bossac": {
+		pattern: `"{path}/{cmd}" {upload.verbose} --port={serial.port.file} -U {upload.native_usb} -o {readback.offset} -r "{readback.path}"`,
+	},
+	"esptool_py": {
+		pattern: `"{path}/{cmd}" --chip {build.mcu} --port "{serial.port}" --baud {upload.speed} read_flash {readback.offset} {readback.size} "{readback.path}"`,
+	},
+	"esptool": {
+		pattern: `"{cmd}" -I "{runtime.platform.path}/tools/esptool/esptool.py" --chip esp8266 --port "{serial.port}" --baud {upload.speed} read_flash {readback.offset} {readback.size} "{readback.path}"`,
+	},
+}
+
+// findReadbackRecipe returns the recipe to read back the flash defined by the
+// platform with upload.readback_pattern or, if missing, the default one for
+// the upload tool. It returns nil if the tool doesn't support the readback.
+func findReadbackRecipe(props *properties.Map, toolID string) *readbackRecipe {
+	if pattern, ok := props.GetOk("upload.readback_pattern"); ok {
+		return &readbackRecipe{
+			pattern:    pattern,
+			wholeFlash: props.GetBoolean("upload.readback_whole_flash"),
+		}
+	}
+	return defaultReadbackRecipes[toolID]
+}
+
+// loadUploadedImage loads the image flashed by the upload: the .hex of the
+// sketch, that contains the addresses, or the .bin placed at the address
+// defined by upload.readback.offset (0x10000 for the ESP32, 0 otherwise)
+func loadUploadedImage(props *properties.Map, arch string) (*bldr.FirmwareImage, error) {
+	buildPath := props.GetPath("build.path")
+	projectName := props.Get("build.project_name")
+	if hex := buildPath.Join(projectName + ".hex"); hex.Exist() {
+		return bldr.LoadHexImage(hex)
+	}
+	offset := uint64(0)
+	if arch == "esp32" {
+		offset = 0x10000
+	}
+	if o, ok := props.GetOk("upload.readback.offset"); ok {
+		n, err := strconv.ParseUint(o, 0, 32)
+		if err != nil {
+			return nil, errors.Errorf("invalid upload.readback.offset property: %s", err)
+		}
+		offset = n
+	}
+	if bin := buildPath.Join(projectName + ".bin"); bin.Exist() {
+		return bldr.LoadBinImage(bin, uint32(offset))
+	}
+	return nil, errors.Errorf("no .hex or .bin file found in %s", buildPath)
+}
+
+// readbackProperties returns a copy of props with the properties used by the
+// readback recipe to read size bytes of flash, starting at offset, into
+// readbackPath
+func readbackProperties(props *properties.Map, recipe *readbackRecipe, offset uint32, size int, readbackPath *paths.Path) *properties.Map {
+	props = props.Clone()
+	props.Set("upload.readback_pattern", recipe.pattern)
+	props.SetPath("readback.path", readbackPath)
+	props.Set("readback.offset", fmt.Sprintf("0x%X", offset))
+	props.Set("readback.size", fmt.Sprint(size))
+	return props
+}
+
+// runReadbackVerification reads back the flash of the board with the given
+// recipe and compares it with the uploaded image. The ranges that don't
+// match are reported in the result.
+func runReadbackVerification(props *properties.Map, recipe *readbackRecipe, arch, port string, discoveryTimeout time.Duration, outStream, errStream io.Writer, verbose bool, result *rpc.UploadResult) error {
+	image, err := loadUploadedImage(props, arch)
+	if err != nil {
+		return fmt.Errorf("loading the uploaded image: %s", err)
+	}
+
+	tmp, err := paths.MkTempDir("", "arduino-readback-")
+	if err != nil {
+		return err
+	}
+	defer tmp.RemoveAll()
+	readbackPath := tmp.Join(props.Get("build.project_name") + ".readback.bin")
+
+	props = readbackProperties(props, recipe, image.Address, len(image.Data), readbackPath)
+
+	// The board may have left the bootloader after the upload
+	if props.GetBoolean("upload.use_1200bps_touch") && port != "" {
+		wait := props.GetBoolean("upload.wait_for_upload_port")
+		if newPort, err := serialutils.ResetWithTimeout(port, wait, discoveryTimeout, nil); err != nil {
+			fmt.Fprintf(outStream, "Cannot perform port reset: %s\n", err)
+		} else if newPort != "" {
+			setSerialPortProperties(props, newPort)
+		}
+	}
+
+	fmt.Fprintf(outStream, "Reading back %d bytes at 0x%X...\n", len(image.Data), image.Address)
+	if err := RunTool("upload.readback_pattern", props, nil, outStream, errStream, verbose); err != nil {
+		return err
+	}
+	readback, err := readbackPath.ReadFile()
+	if err != nil {
+		return fmt.Errorf("reading the flash content: %s", err)
+	}
+	if recipe.wholeFlash {
+		if uint32(len(readback)) > image.Address {
+			readback = readback[image.Address:]
+		} else {
+			readback = nil
+		}
+	}
+
+	result.ReadbackMismatches = readbackMismatches(image, readback)
+	if len(result.ReadbackMismatches) > 0 {
+		for _, r := range result.ReadbackMismatches {
+			fmt.Fprintf(errStream, "Mismatch at 0x%08X-0x%08X (%d bytes)\n", r.Start, r.End-1, r.End-r.Start)
+		}
+		return fmt.Errorf("the flash content doesn't match the uploaded image in %d ranges", len(result.ReadbackMismatches))
+	}
+	fmt.Fprintf(outStream, "Readback verified: %d bytes match\n", len(image.Data))
+	return nil
+}
+
+// readbackMismatches compares the image with the flash content read back
+// from the image address, and returns the ranges that differ. The bytes
+// missing at the end of the readback are considered erased (0xFF), since
+// some tools omit them.
+func readbackMismatches(image *bldr.FirmwareImage, readback []byte) []*rpc.FlashRange {
+	res := []*rpc.FlashRange{}
+	var current *rpc.FlashRange
+	for i, expected := range image.Data {
+		actual := byte(0xFF)
+		if i < len(readback) {
+			actual = readback[i]
+		}
+		if actual == expected {
+			current = nil
+			continue
+		}
+		address := uint64(image.Address) + uint64(i)
+		if current == nil {
+			current = &rpc.FlashRange{Start: address}
+			res = append(res, current)
+		}
+		current.End = address + 1
+	}
+	return res
+}
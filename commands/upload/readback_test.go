@@ -0,0 +1,122 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package upload
+
+import (
+	"bytes"
+	"runtime"
+	"testing"
+
+	bldr "github.com/arduino/arduino-cli/arduino/builder"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	paths "github.com/arduino/go-paths-helper"
+	properties "github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadbackMismatches(t *testing.T) {
+	image := &bldr.FirmwareImage{Address: 0x2000, Data: []byte{1, 2, 3, 4, 5, 6, 0xFF, 0xFF}}
+
+	require.Empty(t, readbackMismatches(image, []byte{1, 2, 3, 4, 5, 6, 0xFF, 0xFF}))
+	// The erased bytes at the end may be missing from the readback
+	require.Empty(t, readbackMismatches(image, []byte{1, 2, 3, 4, 5, 6}))
+
+	require.Equal(t, []*rpc.FlashRange{
+		{Start: 0x2001, End: 0x2003},
+		{Start: 0x2005, End: 0x2006},
+	}, readbackMismatches(image, []byte{1, 0, 0, 4, 5, 0, 0xFF, 0xFF}))
+
+	require.Equal(t, []*rpc.FlashRange{
+		{Start: 0x2004, End: 0x2006},
+	}, readbackMismatches(image, []byte{1, 2, 3, 4}))
+}
+
+func TestFindReadbackRecipe(t *testing.T) {
+	props := properties.NewMap()
+	require.Nil(t, findReadbackRecipe(props, "openocd"))
+	require.True(t, findReadbackRecipe(props, "avrdude").wholeFlash)
+	require.False(t, findReadbackRecipe(props, "esptool_py").wholeFlash)
+
+	props.Set("upload.readback_pattern", "mytool read {readback.path}")
+	props.Set("upload.readback_whole_flash", "true")
+	recipe := findReadbackRecipe(props, "openocd")
+	require.Equal(t, "mytool read {readback.path}", recipe.pattern)
+	require.True(t, recipe.wholeFlash)
+}
+
+func TestLoadUploadedImage(t *testing.T) {
+	buildPath, err := paths.MkTempDir("", "readback-test")
+	require.NoError(t, err)
+	defer buildPath.RemoveAll()
+	require.NoError(t, buildPath.Join("Blink.ino.bin").WriteFile([]byte{1, 2, 3}))
+
+	props := properties.NewMap()
+	props.SetPath("build.path", buildPath)
+	props.Set("build.project_name", "Blink.ino")
+
+	image, err := loadUploadedImage(props, "esp32")
+	require.NoError(t, err)
+	require.Equal(t, uint32(0x10000), image.Address)
+	require.Equal(t, []byte{1, 2, 3}, image.Data)
+
+	image, err = loadUploadedImage(props, "samd")
+	require.NoError(t, err)
+	require.Equal(t, uint32(0), image.Address)
+
+	props.Set("upload.readback.offset", "0x2000")
+	image, err = loadUploadedImage(props, "samd")
+	require.NoError(t, err)
+	require.Equal(t, uint32(0x2000), image.Address)
+
+	// The .hex is preferred since it contains the addresses
+	require.NoError(t, buildPath.Join("Blink.ino.hex").WriteFile([]byte(":0300100001020AE0\n:00000001FF\n")))
+	image, err = loadUploadedImage(props, "samd")
+	require.NoError(t, err)
+	require.Equal(t, uint32(0x10), image.Address)
+	require.Equal(t, []byte{1, 2, 10}, image.Data)
+}
+
+func TestRunReadbackVerification(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test recipe uses cp")
+	}
+	buildPath, err := paths.MkTempDir("", "readback-test")
+	require.NoError(t, err)
+	defer buildPath.RemoveAll()
+	require.NoError(t, buildPath.Join("Blink.ino.bin").WriteFile([]byte{1, 2, 3, 4}))
+	require.NoError(t, buildPath.Join("flash.bin").WriteFile([]byte{0, 0, 1, 2, 3, 4}))
+	require.NoError(t, buildPath.Join("corrupted.bin").WriteFile([]byte{0, 0, 1, 0, 3}))
+
+	props := properties.NewMap()
+	props.SetPath("build.path", buildPath)
+	props.Set("build.project_name", "Blink.ino")
+	props.Set("upload.readback.offset", "2")
+
+	out := &bytes.Buffer{}
+	result := &rpc.UploadResult{}
+	recipe := &readbackRecipe{pattern: `cp "{build.path}/flash.bin" "{readback.path}"`, wholeFlash: true}
+	err = runReadbackVerification(props, recipe, "test", "", 0, out, out, false, result)
+	require.NoError(t, err)
+	require.Empty(t, result.ReadbackMismatches)
+	require.Contains(t, out.String(), "Readback verified: 4 bytes match")
+
+	out.Reset()
+	recipe = &readbackRecipe{pattern: `cp "{build.path}/corrupted.bin" "{readback.path}"`, wholeFlash: true}
+	err = runReadbackVerification(props, recipe, "test", "", 0, out, out, false, result)
+	require.EqualError(t, err, "the flash content doesn't match the uploaded image in 2 ranges")
+	require.Equal(t, []*rpc.FlashRange{{Start: 3, End: 4}, {Start: 5, End: 6}}, result.ReadbackMismatches)
+	require.Contains(t, out.String(), "Mismatch at 0x00000003-0x00000003 (1 bytes)")
+}
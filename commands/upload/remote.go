@@ -0,0 +1,265 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package upload
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/arduino/remote"
+	"github.com/arduino/arduino-cli/arduino/sketches"
+	"github.com/arduino/arduino-cli/commands"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	paths "github.com/arduino/go-paths-helper"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Remote uploads the sketch to a board attached to a remote host. The build
+// artifacts are copied to a temporary folder of the host with scp, then the
+// upload is performed by the arduino-cli installed on the host, run with ssh.
+// If the FQBN or the port are not specified, they're detected on the host.
+func Remote(host *remote.Host, req *rpc.UploadRequest, outStream io.Writer, errStream io.Writer, progressCB commands.UploadProgressCB) (*rpc.UploadResponse, error) {
+	logrus.Tracef("Upload %s on %s over ssh to %s started", req.GetSketchPath(), req.GetFqbn(), host)
+
+	sketch, err := sketches.NewSketchFromPath(paths.New(req.GetSketchPath()))
+	if err != nil && req.GetImportDir() == "" && req.GetImportFile() == "" {
+		return nil, fmt.Errorf("opening sketch: %s", err)
+	}
+
+	fqbnIn := req.GetFqbn()
+	if fqbnIn == "" && sketch != nil && sketch.Metadata != nil {
+		fqbnIn = sketch.Metadata.CPU.Fqbn
+	}
+	port := req.GetPort()
+	if fqbnIn == "" || port == "" {
+		ports, err := host.BoardList(0)
+		if err != nil {
+			return nil, fmt.Errorf("detecting boards on %s: %s", host, err)
+		}
+		detectedFqbn, detectedPort, err := selectRemoteBoard(ports, fqbnIn)
+		if err != nil {
+			return nil, fmt.Errorf("detecting boards on %s: %s", host, err)
+		}
+		if fqbnIn == "" {
+			fqbnIn = detectedFqbn
+		}
+		if port == "" {
+			port = detectedPort
+		}
+		fmt.Fprintf(errStream, "Using board %s on port %s of %s\n", detectedFqbn, detectedPort, host)
+	}
+	fqbn, err := cores.ParseFQBN(fqbnIn)
+	if err != nil {
+		return nil, fmt.Errorf("incorrect FQBN: %s", err)
+	}
+
+	buildPath, projectName, err := determineBuildPathAndSketchName(req.GetImportFile(), req.GetImportDir(), sketch, fqbn)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving build artifacts: %s", err)
+	}
+	files, err := buildPath.ReadDir()
+	if err != nil {
+		return nil, fmt.Errorf("retrieving build artifacts: %s", err)
+	}
+	files.FilterOutDirs()
+	files.FilterPrefix(projectName + ".")
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no build artifacts for %s found in %s", projectName, buildPath)
+	}
+
+	tmpDir, err := host.MkTempDir()
+	if err != nil {
+		return nil, fmt.Errorf("creating temporary folder on %s: %s", host, err)
+	}
+	defer func() {
+		if err := host.RemoveAll(tmpDir); err != nil {
+			logrus.Warnf("Removing temporary folder %s from %s: %s", tmpDir, host, err)
+		}
+	}()
+	// The folder is named as the project, so the remote arduino-cli can
+	// detect the project name from it
+	importDir := path.Join(tmpDir, strings.TrimSuffix(projectName, path.Ext(projectName)))
+	if _, err := host.Run("mkdir", importDir); err != nil {
+		return nil, fmt.Errorf("creating temporary folder on %s: %s", host, err)
+	}
+	if req.GetVerbose() {
+		fmt.Fprintf(outStream, "Copying %d files to %s:%s\n", len(files), host, importDir)
+	}
+	if err := host.Copy(files, importDir); err != nil {
+		return nil, fmt.Errorf("copying build artifacts to %s: %s", host, err)
+	}
+
+	args := remoteUploadArgs(req, fqbnIn, port, importDir)
+	var out bytes.Buffer
+	runErr := host.RunCLI(&out, errStream, args...)
+	res, err := parseRemoteUploadOutput(out.Bytes(), progressCB)
+	if err != nil {
+		if runErr != nil {
+			return nil, runErr
+		}
+		return nil, fmt.Errorf("reading upload result from %s: %s", host, err)
+	}
+	outStream.Write([]byte(res.UploadOut))
+	errStream.Write([]byte(res.UploadErr))
+	if runErr != nil {
+		if res.Error != "" {
+			return nil, errors.New(res.Error)
+		}
+		return nil, runErr
+	}
+
+	result := res.Result
+	if result == nil {
+		result = &rpc.UploadResult{}
+	}
+	// The image was uploaded from the temporary folder, report the local one
+	if result.ImagePath != "" {
+		result.ImagePath = buildPath.Join(path.Base(result.ImagePath)).String()
+	}
+	return &rpc.UploadResponse{Result: result}, nil
+}
+
+// remoteUploadResult is the result event printed by the remote upload
+type remoteUploadResult struct {
+	Event     string            `json:"event"`
+	UploadOut string            `json:"upload_out"`
+	UploadErr string            `json:"upload_err"`
+	Result    *rpc.UploadResult `json:"result"`
+	Error     string            `json:"error"`
+}
+
+// parseRemoteUploadOutput parses the JSON output of the remote upload: the
+// progress events, passed to progressCB, followed by the result event. The
+// single JSON object printed by the older versions is accepted too.
+func parseRemoteUploadOutput(data []byte, progressCB commands.UploadProgressCB) (*remoteUploadResult, error) {
+	res := &remoteUploadResult{}
+	if err := json.Unmarshal(data, res); err == nil && res.Event != "progress" {
+		return res, nil
+	}
+	found := false
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		event := &struct {
+			remoteUploadResult
+			Stage   string `json:"stage"`
+			Current int64  `json:"current"`
+			Total   int64  `json:"total"`
+		}{}
+		if err := json.Unmarshal([]byte(line), event); err != nil {
+			return nil, err
+		}
+		switch event.Event {
+		case "progress":
+			if progressCB != nil {
+				progressCB(&rpc.UploadProgress{Stage: event.Stage, Current: event.Current, Total: event.Total})
+			}
+		case "result":
+			*res = event.remoteUploadResult
+			found = true
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("no upload result found")
+	}
+	return res, nil
+}
+
+// selectRemoteBoard returns the FQBN and the port of the only board, among
+// the ones detected, matching the given FQBN. If the FQBN is empty any
+// recognized board matches.
+func selectRemoteBoard(ports []*rpc.DetectedPort, fqbnIn string) (string, string, error) {
+	var wanted *cores.FQBN
+	if fqbnIn != "" {
+		fqbn, err := cores.ParseFQBN(fqbnIn)
+		if err != nil {
+			return "", "", fmt.Errorf("incorrect FQBN: %s", err)
+		}
+		wanted = fqbn
+	}
+
+	found := []string{}
+	fqbn, port := "", ""
+	for _, p := range ports {
+		for _, b := range p.GetBoards() {
+			detected, err := cores.ParseFQBN(b.GetFqbn())
+			if err != nil {
+				continue
+			}
+			if wanted != nil && (detected.Package != wanted.Package ||
+				detected.PlatformArch != wanted.PlatformArch ||
+				detected.BoardID != wanted.BoardID) {
+				continue
+			}
+			found = append(found, p.GetAddress())
+			fqbn, port = b.GetFqbn(), p.GetAddress()
+		}
+	}
+	switch len(found) {
+	case 0:
+		return "", "", fmt.Errorf("no board detected")
+	case 1:
+		return fqbn, port, nil
+	default:
+		return "", "", fmt.Errorf("more than one board detected (%s), please specify the port", strings.Join(found, ", "))
+	}
+}
+
+// remoteUploadArgs returns the arguments of the upload command run on the
+// remote host
+func remoteUploadArgs(req *rpc.UploadRequest, fqbn, port, importDir string) []string {
+	args := []string{"upload", "--format", "json", "--no-autodetect", "-b", fqbn, "-p", port, "--input-dir", importDir}
+	if req.GetVerbose() {
+		args = append(args, "--verbose")
+	}
+	if req.GetVerify() {
+		args = append(args, "--verify")
+	}
+	if req.GetProgrammer() != "" {
+		args = append(args, "--programmer", req.GetProgrammer())
+	}
+	if req.GetUploadSpeed() != 0 {
+		args = append(args, "--upload-speed", strconv.FormatUint(uint64(req.GetUploadSpeed()), 10))
+	}
+	for _, arg := range req.GetToolArgs() {
+		args = append(args, "--tool-arg", arg)
+	}
+	if req.GetNetworkPassword() != "" {
+		args = append(args, "--network-password", req.GetNetworkPassword())
+	}
+	if req.GetVerifyReadback() {
+		args = append(args, "--verify-readback")
+	}
+	if req.GetDiscoveryTimeout() != "" {
+		args = append(args, "--discovery-timeout", req.GetDiscoveryTimeout())
+	}
+	if req.GetUploadRetries() != nil {
+		args = append(args, "--upload-retries", strconv.FormatUint(uint64(req.GetUploadRetries().GetValue()), 10))
+	}
+	if req.GetDryRun() {
+		args = append(args, "--dry-run")
+	}
+	return args
+}
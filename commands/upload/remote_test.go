@@ -0,0 +1,101 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package upload
+
+import (
+	"testing"
+
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestSelectRemoteBoard(t *testing.T) {
+	ports := []*rpc.DetectedPort{
+		{Address: "/dev/ttyS0", Protocol: "serial"},
+		{Address: "/dev/ttyACM0", Protocol: "serial", Boards: []*rpc.BoardListItem{{Name: "Arduino Uno", Fqbn: "arduino:avr:uno"}}},
+		{Address: "/dev/ttyUSB0", Protocol: "serial", Boards: []*rpc.BoardListItem{{Name: "ESP32 Dev Module", Fqbn: "esp32:esp32:esp32"}}},
+	}
+
+	fqbn, port, err := selectRemoteBoard(ports, "arduino:avr:uno")
+	require.NoError(t, err)
+	require.Equal(t, "arduino:avr:uno", fqbn)
+	require.Equal(t, "/dev/ttyACM0", port)
+
+	// Board options are ignored
+	fqbn, port, err = selectRemoteBoard(ports, "esp32:esp32:esp32:PartitionScheme=huge_app")
+	require.NoError(t, err)
+	require.Equal(t, "esp32:esp32:esp32", fqbn)
+	require.Equal(t, "/dev/ttyUSB0", port)
+
+	_, _, err = selectRemoteBoard(ports, "arduino:samd:mkr1000")
+	require.EqualError(t, err, "no board detected")
+
+	_, _, err = selectRemoteBoard(ports, "")
+	require.EqualError(t, err, "more than one board detected (/dev/ttyACM0, /dev/ttyUSB0), please specify the port")
+
+	fqbn, port, err = selectRemoteBoard(ports[:2], "")
+	require.NoError(t, err)
+	require.Equal(t, "arduino:avr:uno", fqbn)
+	require.Equal(t, "/dev/ttyACM0", port)
+}
+
+func TestRemoteUploadArgs(t *testing.T) {
+	req := &rpc.UploadRequest{
+		Verbose:       true,
+		Programmer:    "usbasp",
+		UploadSpeed:   57600,
+		ToolArgs:      []string{"-D"},
+		UploadRetries: wrapperspb.UInt32(3),
+		DryRun:        true,
+	}
+	args := remoteUploadArgs(req, "arduino:avr:uno", "/dev/ttyACM0", "/tmp/tmp.1234/Blink")
+	require.Equal(t, []string{
+		"upload", "--format", "json", "--no-autodetect",
+		"-b", "arduino:avr:uno", "-p", "/dev/ttyACM0", "--input-dir", "/tmp/tmp.1234/Blink",
+		"--verbose", "--programmer", "usbasp", "--upload-speed", "57600", "--tool-arg", "-D",
+		"--upload-retries", "3", "--dry-run",
+	}, args)
+}
+
+func TestParseRemoteUploadOutput(t *testing.T) {
+	progress := []*rpc.UploadProgress{}
+	progressCB := func(p *rpc.UploadProgress) { progress = append(progress, p) }
+
+	out := `{"event":"progress","stage":"Writing","current":512,"total":1024}
+{"event":"progress","stage":"Writing","current":1024,"total":1024}
+{"event":"result","upload_out":"done\n","result":{"exit_code":0,"bytes_written":1024}}
+`
+	res, err := parseRemoteUploadOutput([]byte(out), progressCB)
+	require.NoError(t, err)
+	require.Equal(t, "done\n", res.UploadOut)
+	require.Equal(t, int64(1024), res.Result.BytesWritten)
+	require.Len(t, progress, 2)
+	require.Equal(t, "Writing", progress[1].Stage)
+	require.Equal(t, int64(1024), progress[1].Current)
+
+	// The output of the older versions is a single indented object
+	res, err = parseRemoteUploadOutput([]byte("{\n  \"upload_out\": \"done\\n\",\n  \"result\": {}\n}\n"), nil)
+	require.NoError(t, err)
+	require.Equal(t, "done\n", res.UploadOut)
+
+	res, err = parseRemoteUploadOutput([]byte(`{"event":"result","error":"uploading error: exit status 1"}`), nil)
+	require.NoError(t, err)
+	require.Equal(t, "uploading error: exit status 1", res.Error)
+
+	_, err = parseRemoteUploadOutput([]byte(`{"event":"progress","stage":"Writing"}`), nil)
+	require.EqualError(t, err, "no upload result found")
+}
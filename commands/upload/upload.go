@@ -16,17 +16,25 @@
 package upload
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/url"
+	"os/exec"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	bldr "github.com/arduino/arduino-cli/arduino/builder"
 	"github.com/arduino/arduino-cli/arduino/cores"
 	"github.com/arduino/arduino-cli/arduino/cores/packagemanager"
 	"github.com/arduino/arduino-cli/arduino/globals"
+	"github.com/arduino/arduino-cli/arduino/monitors"
 	"github.com/arduino/arduino-cli/arduino/serialutils"
 	"github.com/arduino/arduino-cli/arduino/sketches"
 	"github.com/arduino/arduino-cli/commands"
@@ -39,7 +47,7 @@ import (
 )
 
 // Upload FIXMEDOC
-func Upload(ctx context.Context, req *rpc.UploadRequest, outStream io.Writer, errStream io.Writer) (*rpc.UploadResponse, error) {
+func Upload(ctx context.Context, req *rpc.UploadRequest, outStream io.Writer, errStream io.Writer, progressCB commands.UploadProgressCB) (*rpc.UploadResponse, error) {
 	logrus.Tracef("Upload %s on %s started", req.GetSketchPath(), req.GetFqbn())
 
 	// TODO: make a generic function to extract sketch from request
@@ -50,9 +58,31 @@ func Upload(ctx context.Context, req *rpc.UploadRequest, outStream io.Writer, er
 		return nil, fmt.Errorf("opening sketch: %s", err)
 	}
 
+	settings := commands.GetSettings(req.GetInstance().GetId())
+	if settings == nil {
+		return nil, errors.New("invalid instance")
+	}
+
+	// The request overrides the upload settings of the instance
+	discoveryTimeout := serialutils.DefaultWaitTimeout
+	discoveryTimeoutValue := req.GetDiscoveryTimeout()
+	if discoveryTimeoutValue == "" {
+		discoveryTimeoutValue = settings.GetString("upload.discovery_timeout")
+	}
+	if discoveryTimeoutValue != "" {
+		discoveryTimeout, err = time.ParseDuration(discoveryTimeoutValue)
+		if err != nil {
+			return nil, fmt.Errorf("invalid discovery timeout: %s", err)
+		}
+	}
+	uploadRetries := settings.GetUint32("upload.retries")
+	if req.GetUploadRetries() != nil {
+		uploadRetries = req.GetUploadRetries().GetValue()
+	}
+
 	pm := commands.GetPackageManager(req.GetInstance().GetId())
 
-	err = runProgramAction(
+	result, err := runProgramAction(
 		pm,
 		sketch,
 		req.GetImportFile(),
@@ -60,16 +90,29 @@ func Upload(ctx context.Context, req *rpc.UploadRequest, outStream io.Writer, er
 		req.GetFqbn(),
 		req.GetPort(),
 		req.GetProgrammer(),
+		req.GetUploadSpeed(),
+		req.GetToolArgs(),
+		req.GetNetworkPassword(),
+		discoveryTimeout,
+		uploadRetries,
 		req.GetVerbose(),
 		req.GetVerify(),
+		req.GetVerifyReadback(),
+		req.GetDryRun(),
 		false, // burnBootloader
+		fusesWithBootloader,
 		outStream,
 		errStream,
+		progressCB,
 	)
 	if err != nil {
+		// The partial result helps to understand the failure
+		if result != nil {
+			return &rpc.UploadResponse{Result: result}, err
+		}
 		return nil, err
 	}
-	return &rpc.UploadResponse{}, nil
+	return &rpc.UploadResponse{Result: result}, nil
 }
 
 // UsingProgrammer FIXMEDOC
@@ -79,59 +122,231 @@ func UsingProgrammer(ctx context.Context, req *rpc.UploadUsingProgrammerRequest,
 	if req.GetProgrammer() == "" {
 		return nil, errors.New("programmer not specified")
 	}
-	_, err := Upload(ctx, &rpc.UploadRequest{
-		Instance:   req.GetInstance(),
-		SketchPath: req.GetSketchPath(),
-		ImportFile: req.GetImportFile(),
-		ImportDir:  req.GetImportDir(),
-		Fqbn:       req.GetFqbn(),
-		Port:       req.GetPort(),
-		Programmer: req.GetProgrammer(),
-		Verbose:    req.GetVerbose(),
-		Verify:     req.GetVerify(),
-	}, outStream, errStream)
-	return &rpc.UploadUsingProgrammerResponse{}, err
+	res, err := Upload(ctx, &rpc.UploadRequest{
+		Instance:    req.GetInstance(),
+		SketchPath:  req.GetSketchPath(),
+		ImportFile:  req.GetImportFile(),
+		ImportDir:   req.GetImportDir(),
+		Fqbn:        req.GetFqbn(),
+		Port:        req.GetPort(),
+		Programmer:  req.GetProgrammer(),
+		Verbose:     req.GetVerbose(),
+		Verify:      req.GetVerify(),
+		UploadSpeed: req.GetUploadSpeed(),
+		ToolArgs:    req.GetToolArgs(),
+	}, outStream, errStream, nil)
+	if err != nil {
+		// The partial result helps to understand the failure
+		if res != nil {
+			return &rpc.UploadUsingProgrammerResponse{Result: res.GetResult()}, err
+		}
+		return nil, err
+	}
+	return &rpc.UploadUsingProgrammerResponse{Result: res.GetResult()}, nil
 }
 
 func runProgramAction(pm *packagemanager.PackageManager,
 	sketch *sketches.Sketch,
 	importFile, importDir, fqbnIn, port string,
 	programmerID string,
-	verbose, verify, burnBootloader bool,
-	outStream, errStream io.Writer) error {
+	uploadSpeed uint32, toolArgs []string, networkPassword string,
+	discoveryTimeout time.Duration, uploadRetries uint32,
+	verbose, verify, verifyReadback, dryRun, burnBootloader bool, fuses fusesAction,
+	outStream, errStream io.Writer, progressCB commands.UploadProgressCB) (*rpc.UploadResult, error) {
 
+	if progressCB == nil {
+		progressCB = func(*rpc.UploadProgress) {}
+	}
 	if burnBootloader && programmerID == "" {
-		return fmt.Errorf("no programmer specified for burning bootloader")
+		return nil, fmt.Errorf("no programmer specified for burning bootloader")
 	}
 
 	// FIXME: make a specification on how a port is specified via command line
 	if port == "" && sketch != nil && sketch.Metadata != nil {
 		deviceURI, err := url.Parse(sketch.Metadata.CPU.Port)
 		if err != nil {
-			return fmt.Errorf("invalid Device URL format: %s", err)
+			return nil, fmt.Errorf("invalid Device URL format: %s", err)
 		}
 		if deviceURI.Scheme == "serial" {
 			port = deviceURI.Host + deviceURI.Path
 		}
 	}
 	logrus.WithField("port", port).Tracef("Upload port")
+	networkHost, networkPort, isNetwork := parseNetworkPort(port)
+	isNetwork = isNetwork && programmerID == "" && !burnBootloader
 
 	if fqbnIn == "" && sketch != nil && sketch.Metadata != nil {
 		fqbnIn = sketch.Metadata.CPU.Fqbn
 	}
+	toolProperty := "upload.tool"
+	if burnBootloader {
+		toolProperty = "bootloader.tool"
+	} else if programmerID != "" {
+		toolProperty = "program.tool"
+	}
+	target, err := resolveUploadTarget(pm, fqbnIn, programmerID, toolProperty, isNetwork, uploadSpeed, verbose, verify, errStream)
+	if err != nil {
+		return nil, err
+	}
+	fqbn, boardPlatform, programmer := target.fqbn, target.boardPlatform, target.programmer
+	uploadToolID, uploadProperties := target.toolID, target.properties
+
+	// Check the readback support before uploading
+	var readback *readbackRecipe
+	if verifyReadback {
+		if burnBootloader || programmer != nil || isNetwork {
+			return nil, fmt.Errorf("the readback verification is only supported when uploading to a serial port")
+		}
+		readback = findReadbackRecipe(uploadProperties, uploadToolID)
+		if readback == nil {
+			return nil, fmt.Errorf("the upload tool %s doesn't support the readback verification", uploadToolID)
+		}
+	}
+
+	if !burnBootloader {
+		importPath, sketchName, err := determineBuildPathAndSketchName(importFile, importDir, sketch, fqbn)
+		if err != nil {
+			return nil, errors.Errorf("retrieving build artifacts: %s", err)
+		}
+		if !importPath.Exist() {
+			return nil, fmt.Errorf("compiled sketch not found in %s", importPath)
+		}
+		if !importPath.IsDir() {
+			return nil, fmt.Errorf("expected compiled sketch in directory %s, but is a file instead", importPath)
+		}
+		uploadProperties.SetPath("build.path", importPath)
+		uploadProperties.Set("build.project_name", sketchName)
+	}
+
+	// A monitor open on the port would make the upload fail, ask it to
+	// release the port until the upload is done
+	if !dryRun && !isNetwork && port != "" {
+		defer monitors.ReleasePort(port, monitorReleaseTimeout)()
+	}
+
+	// If not using programmer perform some action required
+	// to set the board in bootloader mode
+	actualPort := port
+	dryRunSteps := []*rpc.UploadStep{}
+	if programmer == nil && !burnBootloader && !isNetwork {
+
+		// Perform reset via 1200bps touch if requested and wait for upload port also if requested.
+		touch := uploadProperties.GetBoolean("upload.use_1200bps_touch")
+		wait := false
+		portToTouch := ""
+		if touch {
+			portToTouch = port
+			// Waits for upload port only if a 1200bps touch is done
+			wait = uploadProperties.GetBoolean("upload.wait_for_upload_port")
+		}
+
+		// if touch is requested but port is not specified, print a warning
+		if touch && portToTouch == "" {
+			outStream.Write([]byte(fmt.Sprintln("Skipping 1200-bps touch reset: no serial port selected!")))
+		}
+
+		if dryRun {
+			if portToTouch != "" {
+				dryRunSteps = append(dryRunSteps, resetSteps(portToTouch, wait, discoveryTimeout, port)...)
+			}
+		} else if newPort := resetBoard(portToTouch, wait, discoveryTimeout, port, verbose, outStream); newPort != "" {
+			actualPort = newPort
+		}
+	}
+
+	result := &rpc.UploadResult{Port: actualPort}
+	if isNetwork {
+		setNetworkProperties(uploadProperties, boardPlatform.Platform.Architecture, networkHost, networkPort, networkPassword)
+	} else if actualPort != "" {
+		setSerialPortProperties(uploadProperties, actualPort)
+	}
+
+	if dryRun {
+		steps, err := dryRunUploadSteps(uploadProperties, uploadToolID, boardPlatform.Platform.Architecture, toolArgs, burnBootloader, fuses, isNetwork, programmer != nil, readback)
+		if err != nil {
+			return nil, err
+		}
+		result.DryRunSteps = append(dryRunSteps, steps...)
+		result.DryRunProperties = uploadProperties.AsMap()
+		return result, nil
+	}
+
+	// Run recipes for upload
+	if burnBootloader && fuses == fusesRead {
+		if err := runFusesRead(uploadProperties, uploadToolID, outStream, errStream, verbose, result); err != nil {
+			return nil, fmt.Errorf("reading fuses error: %s", err)
+		}
+	} else if burnBootloader && fuses == fusesWrite {
+		if err := runFusesWrite(uploadProperties, uploadToolID, outStream, errStream, verbose, result); err != nil {
+			return nil, fmt.Errorf("programming fuses error: %s", err)
+		}
+	} else if burnBootloader {
+		if err := RunTool("erase.pattern", uploadProperties, nil, outStream, errStream, verbose); err != nil {
+			return nil, fmt.Errorf("chip erase error: %s", err)
+		}
+		if err := RunTool("bootloader.pattern", uploadProperties, nil, outStream, errStream, verbose); err != nil {
+			return nil, fmt.Errorf("burn bootloader error: %s", err)
+		}
+		// The fuses are programmed by the erase recipe, the lock bits by
+		// the bootloader recipe
+		result.Fuses = boardFuseValues(uploadProperties, true)
+	} else if isNetwork {
+		if err := runNetworkUpload(uploadProperties, boardPlatform.Platform.Architecture, toolArgs, outStream, errStream, verbose, progressCB, result); err != nil {
+			return result, fmt.Errorf("uploading error: %s", err)
+		}
+	} else if programmer != nil {
+		if err := runUploadToolWithRetries("program.pattern", uploadProperties, toolArgs, outStream, errStream, verbose, uploadRetries, result); err != nil {
+			return result, fmt.Errorf("programming error: %s", err)
+		}
+	} else if uploadProperties.ContainsKey("upload.pluggable_pattern") {
+		if err := runPluggableUpload(uploadProperties, fqbn.String(), toolArgs, outStream, errStream, verbose, verify, progressCB, result); err != nil {
+			return result, fmt.Errorf("uploading error: %s", err)
+		}
+	} else {
+		if err := runUploadToolWithRetries("upload.pattern", uploadProperties, toolArgs, outStream, errStream, verbose, uploadRetries, result); err != nil {
+			return result, fmt.Errorf("uploading error: %s", err)
+		}
+	}
+
+	if readback != nil {
+		if err := runReadbackVerification(uploadProperties, readback, boardPlatform.Platform.Architecture, actualPort, discoveryTimeout, outStream, errStream, verbose, result); err != nil {
+			return result, fmt.Errorf("readback verification error: %s", err)
+		}
+	}
+
+	logrus.Tracef("Upload successful")
+	return result, nil
+}
+
+// uploadTarget is the board, the programmer and the tool selected for an
+// upload, with the properties used to expand the recipes of the tool
+type uploadTarget struct {
+	fqbn          *cores.FQBN
+	boardPlatform *cores.PlatformRelease
+	programmer    *cores.Programmer
+	toolID        string
+	properties    *properties.Map
+}
+
+// resolveUploadTarget selects the board, the programmer (if programmerID is
+// not empty) and the tool defined by toolProperty, and composes the
+// properties of the upload.
+func resolveUploadTarget(pm *packagemanager.PackageManager, fqbnIn, programmerID, toolProperty string,
+	isNetwork bool, uploadSpeed uint32, verbose, verify bool, errStream io.Writer) (*uploadTarget, error) {
+
 	if fqbnIn == "" {
-		return fmt.Errorf("no Fully Qualified Board Name provided")
+		return nil, fmt.Errorf("no Fully Qualified Board Name provided")
 	}
 	fqbn, err := cores.ParseFQBN(fqbnIn)
 	if err != nil {
-		return fmt.Errorf("incorrect FQBN: %s", err)
+		return nil, fmt.Errorf("incorrect FQBN: %s", err)
 	}
 	logrus.WithField("fqbn", fqbn).Tracef("Detected FQBN")
 
 	// Find target board and board properties
 	_, boardPlatform, board, boardProperties, buildPlatform, err := pm.ResolveFQBN(fqbn)
 	if err != nil {
-		return fmt.Errorf("incorrect FQBN: %s", err)
+		return nil, fmt.Errorf("incorrect FQBN: %s", err)
 	}
 	logrus.
 		WithField("boardPlatform", boardPlatform).
@@ -148,20 +363,13 @@ func runProgramAction(pm *packagemanager.PackageManager,
 			programmer = buildPlatform.Programmers[programmerID]
 		}
 		if programmer == nil {
-			return fmt.Errorf("programmer '%s' not available", programmerID)
+			return nil, programmerNotAvailableError(programmerID, fqbn, boardPlatform, buildPlatform)
 		}
 	}
 
 	// Determine upload tool
 	var uploadToolID string
 	{
-		toolProperty := "upload.tool"
-		if burnBootloader {
-			toolProperty = "bootloader.tool"
-		} else if programmer != nil {
-			toolProperty = "program.tool"
-		}
-
 		// create a temporary configuration only for the selection of upload tool
 		props := properties.NewMap()
 		props.Merge(boardPlatform.Properties)
@@ -170,10 +378,12 @@ func runProgramAction(pm *packagemanager.PackageManager,
 		if programmer != nil {
 			props.Merge(programmer.Properties)
 		}
-		if t, ok := props.GetOk(toolProperty); ok {
+		if t, ok := props.GetOk(toolProperty + ".network"); ok && isNetwork {
+			uploadToolID = t
+		} else if t, ok := props.GetOk(toolProperty); ok {
 			uploadToolID = t
 		} else {
-			return fmt.Errorf("cannot get programmer tool: undefined '%s' property", toolProperty)
+			return nil, fmt.Errorf("cannot get programmer tool: undefined '%s' property", toolProperty)
 		}
 	}
 
@@ -189,7 +399,7 @@ func runProgramAction(pm *packagemanager.PackageManager,
 		Trace("Upload tool")
 
 	if split := strings.Split(uploadToolID, ":"); len(split) > 2 {
-		return fmt.Errorf("invalid 'upload.tool' property: %s", uploadToolID)
+		return nil, fmt.Errorf("invalid 'upload.tool' property: %s", uploadToolID)
 	} else if len(split) == 2 {
 		uploadToolID = split[1]
 		uploadToolPlatform = pm.GetInstalledPlatformRelease(
@@ -227,8 +437,13 @@ func runProgramAction(pm *packagemanager.PackageManager,
 		}
 	}
 
+	// Override the board upload speed if requested
+	if uploadSpeed != 0 {
+		uploadProperties.Set("upload.speed", fmt.Sprint(uploadSpeed))
+	}
+
 	if !uploadProperties.ContainsKey("upload.protocol") && programmer == nil {
-		return fmt.Errorf("a programmer is required to upload for this board")
+		return nil, fmt.Errorf("a programmer is required to upload for this board")
 	}
 
 	// Set properties for verbose upload
@@ -273,126 +488,217 @@ func runProgramAction(pm *packagemanager.PackageManager,
 		uploadProperties.Set("bootloader.verify", uploadProperties.Get("bootloader.params.noverify"))
 	}
 
-	if !burnBootloader {
-		importPath, sketchName, err := determineBuildPathAndSketchName(importFile, importDir, sketch, fqbn)
-		if err != nil {
-			return errors.Errorf("retrieving build artifacts: %s", err)
-		}
-		if !importPath.Exist() {
-			return fmt.Errorf("compiled sketch not found in %s", importPath)
-		}
-		if !importPath.IsDir() {
-			return fmt.Errorf("expected compiled sketch in directory %s, but is a file instead", importPath)
-		}
-		uploadProperties.SetPath("build.path", importPath)
-		uploadProperties.Set("build.project_name", sketchName)
-	}
-
-	// If not using programmer perform some action required
-	// to set the board in bootloader mode
-	actualPort := port
-	if programmer == nil && !burnBootloader {
+	return &uploadTarget{
+		fqbn:          fqbn,
+		boardPlatform: boardPlatform,
+		programmer:    programmer,
+		toolID:        uploadToolID,
+		properties:    uploadProperties,
+	}, nil
+}
 
-		// Perform reset via 1200bps touch if requested and wait for upload port also if requested.
-		touch := uploadProperties.GetBoolean("upload.use_1200bps_touch")
-		wait := false
-		portToTouch := ""
-		if touch {
-			portToTouch = port
-			// Waits for upload port only if a 1200bps touch is done
-			wait = uploadProperties.GetBoolean("upload.wait_for_upload_port")
+// resetBoard performs the 1200-bps touch of the port and waits for the
+// upload port, if requested, and returns the new port or the empty string
+// if not found
+func resetBoard(portToTouch string, wait bool, timeout time.Duration, fallbackPort string, verbose bool, outStream io.Writer) string {
+	var cb *serialutils.ResetProgressCallbacks
+	if verbose {
+		cb = &serialutils.ResetProgressCallbacks{
+			TouchingPort: func(port string) {
+				logrus.WithField("phase", "board reset").Infof("Performing 1200-bps touch reset on serial port %s", port)
+				outStream.Write([]byte(fmt.Sprintf("Performing 1200-bps touch reset on serial port %s", port)))
+				outStream.Write([]byte(fmt.Sprintln()))
+			},
+			WaitingForNewSerial: func() {
+				logrus.WithField("phase", "board reset").Info("Waiting for upload port...")
+				outStream.Write([]byte(fmt.Sprintln("Waiting for upload port...")))
+			},
+			BootloaderPortFound: func(port string) {
+				if port != "" {
+					logrus.WithField("phase", "board reset").Infof("Upload port found on %s", port)
+					outStream.Write([]byte(fmt.Sprintf("Upload port found on %s", port)))
+					outStream.Write([]byte(fmt.Sprintln()))
+				} else {
+					logrus.WithField("phase", "board reset").Infof("No upload port found, using %s as fallback", fallbackPort)
+					outStream.Write([]byte(fmt.Sprintf("No upload port found, using %s as fallback", fallbackPort)))
+					outStream.Write([]byte(fmt.Sprintln()))
+				}
+			},
+			Debug: func(msg string) {
+				logrus.WithField("phase", "board reset").Debug(msg)
+			},
 		}
+	}
+	newPort, err := serialutils.ResetWithTimeout(portToTouch, wait, timeout, cb)
+	if err != nil {
+		outStream.Write([]byte(fmt.Sprintf("Cannot perform port reset: %s", err)))
+		outStream.Write([]byte(fmt.Sprintln()))
+	}
+	return newPort
+}
 
-		// if touch is requested but port is not specified, print a warning
-		if touch && portToTouch == "" {
-			outStream.Write([]byte(fmt.Sprintln("Skipping 1200-bps touch reset: no serial port selected!")))
-		}
+// setSerialPortProperties sets the serial port properties used by the upload
+// recipes
+func setSerialPortProperties(props *properties.Map, port string) {
+	props.Set("serial.port", port)
+	if strings.HasPrefix(port, "/dev/") {
+		props.Set("serial.port.file", port[5:])
+	} else {
+		props.Set("serial.port.file", port)
+	}
+}
 
-		var cb *serialutils.ResetProgressCallbacks
-		if verbose {
-			cb = &serialutils.ResetProgressCallbacks{
-				TouchingPort: func(port string) {
-					logrus.WithField("phase", "board reset").Infof("Performing 1200-bps touch reset on serial port %s", port)
-					outStream.Write([]byte(fmt.Sprintf("Performing 1200-bps touch reset on serial port %s", port)))
-					outStream.Write([]byte(fmt.Sprintln()))
-				},
-				WaitingForNewSerial: func() {
-					logrus.WithField("phase", "board reset").Info("Waiting for upload port...")
-					outStream.Write([]byte(fmt.Sprintln("Waiting for upload port...")))
-				},
-				BootloaderPortFound: func(port string) {
-					if port != "" {
-						logrus.WithField("phase", "board reset").Infof("Upload port found on %s", port)
-						outStream.Write([]byte(fmt.Sprintf("Upload port found on %s", port)))
-						outStream.Write([]byte(fmt.Sprintln()))
-					} else {
-						logrus.WithField("phase", "board reset").Infof("No upload port found, using %s as fallback", actualPort)
-						outStream.Write([]byte(fmt.Sprintf("No upload port found, using %s as fallback", actualPort)))
-						outStream.Write([]byte(fmt.Sprintln()))
-					}
-				},
-				Debug: func(msg string) {
-					logrus.WithField("phase", "board reset").Debug(msg)
-				},
+// bytesWrittenRegexp matches the number of bytes written reported by the most
+// common upload tools (avrdude, bossac, esptool, openocd)
+var bytesWrittenRegexp = regexp.MustCompile(`(?i)(?:wrote (\d+) bytes|(\d+) bytes of flash written)`)
+
+// runUploadTool runs the given upload recipe and records its outcome in result
+func runUploadTool(recipeID string, props *properties.Map, toolArgs []string, outStream, errStream io.Writer, verbose bool, result *rpc.UploadResult) error {
+	if image := findUploadedImage(props, recipeID); image != nil {
+		result.ImagePath = image.String()
+		if data, err := image.ReadFile(); err == nil {
+			sum := sha256.Sum256(data)
+			result.ImageSha256 = hex.EncodeToString(sum[:])
+		}
+	}
+
+	// stdout and stderr are written concurrently, they are captured separately
+	toolOut := &bytes.Buffer{}
+	toolErr := &bytes.Buffer{}
+	start := time.Now()
+	err := RunTool(recipeID, props, toolArgs, io.MultiWriter(outStream, toolOut), io.MultiWriter(errStream, toolErr), verbose)
+	result.DurationMs = time.Since(start).Milliseconds()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		result.ExitCode = int32(exitErr.ExitCode())
+	} else if err != nil {
+		result.ExitCode = -1
+	}
+	for _, output := range [][]byte{toolOut.Bytes(), toolErr.Bytes()} {
+		for _, match := range bytesWrittenRegexp.FindAllSubmatch(output, -1) {
+			n := match[1]
+			if len(n) == 0 {
+				n = match[2]
 			}
-		}
-		if newPort, err := serialutils.Reset(portToTouch, wait, cb); err != nil {
-			outStream.Write([]byte(fmt.Sprintf("Cannot perform port reset: %s", err)))
-			outStream.Write([]byte(fmt.Sprintln()))
-		} else {
-			if newPort != "" {
-				actualPort = newPort
+			if written, err := strconv.ParseInt(string(n), 10, 64); err == nil && written > result.BytesWritten {
+				result.BytesWritten = written
 			}
 		}
 	}
-
-	if actualPort != "" {
-		// Set serial port property
-		uploadProperties.Set("serial.port", actualPort)
-		if strings.HasPrefix(actualPort, "/dev/") {
-			uploadProperties.Set("serial.port.file", actualPort[5:])
-		} else {
-			uploadProperties.Set("serial.port.file", actualPort)
-		}
+	if err != nil && (portBusyRegexp.Match(toolOut.Bytes()) || portBusyRegexp.Match(toolErr.Bytes())) {
+		return &portBusyError{err}
 	}
+	return err
+}
 
-	// Run recipes for upload
-	if burnBootloader {
-		if err := runTool("erase.pattern", uploadProperties, outStream, errStream, verbose); err != nil {
-			return fmt.Errorf("chip erase error: %s", err)
-		}
-		if err := runTool("bootloader.pattern", uploadProperties, outStream, errStream, verbose); err != nil {
-			return fmt.Errorf("burn bootloader error: %s", err)
-		}
-	} else if programmer != nil {
-		if err := runTool("program.pattern", uploadProperties, outStream, errStream, verbose); err != nil {
-			return fmt.Errorf("programming error: %s", err)
+// portBusyRegexp matches the errors reported by the upload tools when the
+// port is busy or not accessible, usually because the board is still being
+// enumerated or the port is opened by another program (e.g. ModemManager)
+var portBusyRegexp = regexp.MustCompile(`(?i)(resource busy|permission denied|access is denied|could not open port|can't open device|cannot open port)`)
+
+// portBusyError is returned by runUploadTool when the upload failed because
+// the port is busy
+type portBusyError struct {
+	err error
+}
+
+func (e *portBusyError) Error() string {
+	return e.err.Error()
+}
+
+func (e *portBusyError) Unwrap() error {
+	return e.err
+}
+
+// retryBaseDelay is the delay before the first upload retry, doubled at
+// each attempt up to retryMaxDelay
+var retryBaseDelay = time.Second
+
+const retryMaxDelay = 8 * time.Second
+
+// monitorReleaseTimeout is how long the upload waits for a monitor open on
+// the upload port to release it
+const monitorReleaseTimeout = 5 * time.Second
+
+// runUploadToolWithRetries runs the given upload recipe, retrying up to
+// retries times, with an exponential backoff, if the port is busy
+func runUploadToolWithRetries(recipeID string, props *properties.Map, toolArgs []string, outStream, errStream io.Writer, verbose bool, retries uint32, result *rpc.UploadResult) error {
+	delay := retryBaseDelay
+	for attempt := uint32(1); ; attempt++ {
+		err := runUploadTool(recipeID, props, toolArgs, outStream, errStream, verbose, result)
+		var busy *portBusyError
+		if err == nil || attempt > retries || !errors.As(err, &busy) {
+			return err
 		}
-	} else {
-		if err := runTool("upload.pattern", uploadProperties, outStream, errStream, verbose); err != nil {
-			return fmt.Errorf("uploading error: %s", err)
+		fmt.Fprintf(outStream, "The port is busy, retrying the upload in %s (%d/%d)...\n", delay, attempt, retries)
+		time.Sleep(delay)
+		if delay *= 2; delay > retryMaxDelay {
+			delay = retryMaxDelay
 		}
 	}
+}
 
-	logrus.Tracef("Upload successful")
+// findUploadedImage returns the build artifact referenced by the given recipe,
+// or nil if none is found
+func findUploadedImage(props *properties.Map, recipeID string) *paths.Path {
+	buildPath := props.GetPath("build.path")
+	if buildPath == nil {
+		return nil
+	}
+	files, err := buildPath.ReadDir()
+	if err != nil {
+		return nil
+	}
+	files.FilterPrefix(props.Get("build.project_name"))
+	cmdLine := props.ExpandPropsInString(props.Get(recipeID))
+	for _, file := range files {
+		if strings.Contains(cmdLine, file.String()) {
+			return file
+		}
+	}
 	return nil
 }
 
-func runTool(recipeID string, props *properties.Map, outStream, errStream io.Writer, verbose bool) error {
+// toolCommandLine returns the arguments, and the command line to show to
+// the user, obtained by expanding the given recipe. The extraArgs are
+// appended as-is to the arguments. No arguments are returned if the recipe
+// is empty.
+func toolCommandLine(recipeID string, props *properties.Map, extraArgs []string) ([]string, string, error) {
 	recipe, ok := props.GetOk(recipeID)
 	if !ok {
-		return fmt.Errorf("recipe not found '%s'", recipeID)
+		return nil, "", fmt.Errorf("recipe not found '%s'", recipeID)
 	}
 	if strings.TrimSpace(recipe) == "" {
-		return nil // Nothing to run
+		return nil, "", nil // Nothing to run
 	}
 	if props.IsPropertyMissingInExpandPropsInString("serial.port", recipe) {
-		return fmt.Errorf("no upload port provided")
+		return nil, "", fmt.Errorf("no upload port provided")
 	}
 	cmdLine := props.ExpandPropsInString(recipe)
 	cmdArgs, err := properties.SplitQuotedString(cmdLine, `"'`, false)
 	if err != nil {
-		return fmt.Errorf("invalid recipe '%s': %s", recipe, err)
+		return nil, "", fmt.Errorf("invalid recipe '%s': %s", recipe, err)
+	}
+	// The extra arguments are added after the split, this way they can't be
+	// broken by quotes or expanded as properties
+	if len(extraArgs) > 0 {
+		cmdArgs = append(cmdArgs, extraArgs...)
+		cmdLine += " " + strings.Join(extraArgs, " ")
+	}
+	return cmdArgs, cmdLine, nil
+}
+
+// RunTool runs the given recipe of the properties, the extraArgs are
+// appended as-is to the arguments obtained from the expanded recipe. An empty
+// recipe runs nothing.
+func RunTool(recipeID string, props *properties.Map, extraArgs []string, outStream, errStream io.Writer, verbose bool) error {
+	cmdArgs, cmdLine, err := toolCommandLine(recipeID, props, extraArgs)
+	if err != nil {
+		return err
+	}
+	if len(cmdArgs) == 0 {
+		return nil // Nothing to run
 	}
 
 	// Run Tool
@@ -412,7 +718,7 @@ func runTool(recipeID string, props *properties.Map, outStream, errStream io.Wri
 	}
 
 	if err := cmd.Wait(); err != nil {
-		return fmt.Errorf("uploading error: %s", err)
+		return fmt.Errorf("uploading error: %w", err)
 	}
 
 	return nil
@@ -18,14 +18,18 @@ package upload
 import (
 	"bytes"
 	"fmt"
+	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/arduino/arduino-cli/arduino/builder"
 	"github.com/arduino/arduino-cli/arduino/cores"
 	"github.com/arduino/arduino-cli/arduino/cores/packagemanager"
 	"github.com/arduino/arduino-cli/arduino/sketches"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
 	paths "github.com/arduino/go-paths-helper"
+	properties "github.com/arduino/go-properties-orderedmap"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
 )
@@ -137,6 +141,8 @@ func TestUploadPropertiesComposition(t *testing.T) {
 		port            string
 		programmer      string
 		burnBootloader  bool
+		uploadSpeed     uint32
+		toolArgs        []string
 		expectedOutput  string
 		expectedOutput2 string
 	}
@@ -147,38 +153,43 @@ func TestUploadPropertiesComposition(t *testing.T) {
 
 	tests := []test{
 		// 0: classic upload, requires port
-		{buildPath1, "alice:avr:board1", "port", "", false, "conf-board1 conf-general conf-upload $$VERBOSE-VERIFY$$ protocol port -bspeed testdata/build_path_1/sketch.ino.hex\n", ""},
-		{buildPath1, "alice:avr:board1", "", "", false, "FAIL", ""},
+		{buildPath1, "alice:avr:board1", "port", "", false, 0, nil, "conf-board1 conf-general conf-upload $$VERBOSE-VERIFY$$ protocol port -bspeed testdata/build_path_1/sketch.ino.hex\n", ""},
+		{buildPath1, "alice:avr:board1", "", "", false, 0, nil, "FAIL", ""},
 		// 2: classic upload, no port
-		{buildPath1, "alice:avr:board2", "port", "", false, "conf-board1 conf-general conf-upload $$VERBOSE-VERIFY$$ protocol -bspeed testdata/build_path_1/sketch.ino.hex\n", ""},
-		{buildPath1, "alice:avr:board2", "", "", false, "conf-board1 conf-general conf-upload $$VERBOSE-VERIFY$$ protocol -bspeed testdata/build_path_1/sketch.ino.hex\n", ""},
+		{buildPath1, "alice:avr:board2", "port", "", false, 0, nil, "conf-board1 conf-general conf-upload $$VERBOSE-VERIFY$$ protocol -bspeed testdata/build_path_1/sketch.ino.hex\n", ""},
+		{buildPath1, "alice:avr:board2", "", "", false, 0, nil, "conf-board1 conf-general conf-upload $$VERBOSE-VERIFY$$ protocol -bspeed testdata/build_path_1/sketch.ino.hex\n", ""},
 
 		// 4: upload with programmer, requires port
-		{buildPath1, "alice:avr:board1", "port", "progr1", false, "conf-board1 conf-general conf-program $$VERBOSE-VERIFY$$ progprotocol port -bspeed testdata/build_path_1/sketch.ino.hex\n", ""},
-		{buildPath1, "alice:avr:board1", "", "progr1", false, "FAIL", ""},
+		{buildPath1, "alice:avr:board1", "port", "progr1", false, 0, nil, "conf-board1 conf-general conf-program $$VERBOSE-VERIFY$$ progprotocol port -bspeed testdata/build_path_1/sketch.ino.hex\n", ""},
+		{buildPath1, "alice:avr:board1", "", "progr1", false, 0, nil, "FAIL", ""},
 		// 6: upload with programmer, no port
-		{buildPath1, "alice:avr:board1", "port", "progr2", false, "conf-board1 conf-general conf-program $$VERBOSE-VERIFY$$ prog2protocol -bspeed testdata/build_path_1/sketch.ino.hex\n", ""},
-		{buildPath1, "alice:avr:board1", "", "progr2", false, "conf-board1 conf-general conf-program $$VERBOSE-VERIFY$$ prog2protocol -bspeed testdata/build_path_1/sketch.ino.hex\n", ""},
+		{buildPath1, "alice:avr:board1", "port", "progr2", false, 0, nil, "conf-board1 conf-general conf-program $$VERBOSE-VERIFY$$ prog2protocol -bspeed testdata/build_path_1/sketch.ino.hex\n", ""},
+		{buildPath1, "alice:avr:board1", "", "progr2", false, 0, nil, "conf-board1 conf-general conf-program $$VERBOSE-VERIFY$$ prog2protocol -bspeed testdata/build_path_1/sketch.ino.hex\n", ""},
 		// 8: upload with programmer, require port through extra params
-		{buildPath1, "alice:avr:board1", "port", "progr3", false, "conf-board1 conf-general conf-program $$VERBOSE-VERIFY$$ prog3protocol port -bspeed testdata/build_path_1/sketch.ino.hex\n", ""},
-		{buildPath1, "alice:avr:board1", "", "progr3", false, "FAIL", ""},
+		{buildPath1, "alice:avr:board1", "port", "progr3", false, 0, nil, "conf-board1 conf-general conf-program $$VERBOSE-VERIFY$$ prog3protocol port -bspeed testdata/build_path_1/sketch.ino.hex\n", ""},
+		{buildPath1, "alice:avr:board1", "", "progr3", false, 0, nil, "FAIL", ""},
 
 		// 10: burn bootloader, require port
-		{buildPath1, "alice:avr:board1", "port", "", true, "FAIL", ""}, // requires programmer
-		{buildPath1, "alice:avr:board1", "port", "progr1", true,
+		{buildPath1, "alice:avr:board1", "port", "", true, 0, nil, "FAIL", ""}, // requires programmer
+		{buildPath1, "alice:avr:board1", "port", "progr1", true, 0, nil,
 			"ERASE conf-board1 conf-general conf-erase $$VERBOSE-VERIFY$$ genprog1protocol port -bspeed\n",
 			"BURN conf-board1 conf-general conf-bootloader $$VERBOSE-VERIFY$$ genprog1protocol port -bspeed -F0xFF " + cwd + "/testdata/hardware/alice/avr/bootloaders/niceboot/niceboot.hex\n"},
 
 		// 12: burn bootloader, preferences override from programmers.txt
-		{buildPath1, "alice:avr:board1", "port", "progr4", true,
+		{buildPath1, "alice:avr:board1", "port", "progr4", true, 0, nil,
 			"ERASE conf-board1 conf-two-general conf-two-erase $$VERBOSE-VERIFY$$ prog4protocol-bootloader port -bspeed\n",
 			"BURN conf-board1 conf-two-general conf-two-bootloader $$VERBOSE-VERIFY$$ prog4protocol-bootloader port -bspeed -F0xFF " + cwd + "/testdata/hardware/alice/avr/bootloaders/niceboot/niceboot.hex\n"},
+
+		// 14: upload speed override and tool arguments
+		{buildPath1, "alice:avr:board1", "port", "", false, 9600, []string{"-x", "with space"}, "conf-board1 conf-general conf-upload $$VERBOSE-VERIFY$$ protocol port -b9600 testdata/build_path_1/sketch.ino.hex -x with space\n", ""},
+		// 15: upload speed override with programmer
+		{buildPath1, "alice:avr:board1", "port", "progr1", false, 9600, nil, "conf-board1 conf-general conf-program $$VERBOSE-VERIFY$$ progprotocol port -b9600 testdata/build_path_1/sketch.ino.hex\n", ""},
 	}
 
 	testRunner := func(t *testing.T, test test, verboseVerify bool) {
 		outStream := &bytes.Buffer{}
 		errStream := &bytes.Buffer{}
-		err := runProgramAction(
+		_, err := runProgramAction(
 			pm,
 			nil,                     // sketch
 			"",                      // importFile
@@ -186,11 +197,20 @@ func TestUploadPropertiesComposition(t *testing.T) {
 			test.fqbn,               // FQBN
 			test.port,               // port
 			test.programmer,         // programmer
+			test.uploadSpeed,        // uploadSpeed
+			test.toolArgs,           // toolArgs
+			"",                      // networkPassword
+			0,                       // discoveryTimeout
+			0,                       // uploadRetries
 			verboseVerify,           // verbose
 			verboseVerify,           // verify
+			false,                   // verifyReadback
+			false,                   // dryRun
 			test.burnBootloader,     // burnBootloader
+			fusesWithBootloader,     // fuses
 			outStream,
 			errStream,
+			nil,
 		)
 		verboseVerifyOutput := "verbose verify"
 		if !verboseVerify {
@@ -216,3 +236,142 @@ func TestUploadPropertiesComposition(t *testing.T) {
 		})
 	}
 }
+
+func TestUploadDryRun(t *testing.T) {
+	pm := packagemanager.NewPackageManager(nil, nil, nil, nil)
+	errs := pm.LoadHardwareFromDirectory(paths.New("testdata", "hardware"))
+	require.Len(t, errs, 0)
+	buildPath1 := paths.New("testdata", "build_path_1")
+
+	cwdPath, err := paths.Getwd()
+	require.NoError(t, err)
+	cwd := strings.ReplaceAll(cwdPath.String(), "\\", "/")
+
+	dryRun := func(fqbn, port, programmer string, burnBootloader bool) (*rpc.UploadResult, string, error) {
+		outStream := &bytes.Buffer{}
+		errStream := &bytes.Buffer{}
+		res, err := runProgramAction(pm, nil, "", buildPath1.String(), fqbn, port, programmer,
+			0, []string{"-x"}, "", 0, 0, false, false, false, true, burnBootloader, fusesWithBootloader,
+			outStream, errStream, nil)
+		return res, outStream.String() + errStream.String(), err
+	}
+	commandLine := func(step *rpc.UploadStep) string {
+		return strings.ReplaceAll(strings.Join(step.Command, " "), "\\", "/")
+	}
+
+	res, output, err := dryRun("alice:avr:board1", "port", "", false)
+	require.NoError(t, err)
+	require.Empty(t, output)
+	require.Len(t, res.DryRunSteps, 1)
+	require.Equal(t, "upload.pattern", res.DryRunSteps[0].Action)
+	require.Contains(t, commandLine(res.DryRunSteps[0]), "conf-board1 conf-general conf-upload quiet noverify protocol port -bspeed testdata/build_path_1/sketch.ino.hex -x")
+	require.Equal(t, "port", res.DryRunProperties["serial.port"])
+
+	res, _, err = dryRun("alice:avr:board1", "port", "progr1", false)
+	require.NoError(t, err)
+	require.Len(t, res.DryRunSteps, 1)
+	require.Equal(t, "program.pattern", res.DryRunSteps[0].Action)
+
+	res, _, err = dryRun("alice:avr:board1", "port", "progr1", true)
+	require.NoError(t, err)
+	require.Len(t, res.DryRunSteps, 2)
+	require.Equal(t, "erase.pattern", res.DryRunSteps[0].Action)
+	require.Equal(t, "bootloader.pattern", res.DryRunSteps[1].Action)
+	require.Contains(t, commandLine(res.DryRunSteps[1]), cwd+"/testdata/hardware/alice/avr/bootloaders/niceboot/niceboot.hex")
+
+	// The dry run fails like the upload
+	_, _, err = dryRun("alice:avr:board1", "", "", false)
+	require.Error(t, err)
+
+	// An unknown programmer is reported before running any tool
+	_, _, err = dryRun("alice:avr:board1", "port", "progr", false)
+	require.EqualError(t, err, "programmer 'progr' not available for board alice:avr:board1, did you mean 'progr1'? (available programmers: progr1, progr2, progr3, progr4)")
+}
+
+func TestResetSteps(t *testing.T) {
+	steps := resetSteps("/dev/ttyACM0", true, 10*time.Second, "/dev/ttyACM0")
+	require.Len(t, steps, 2)
+	require.Equal(t, "touch", steps[0].Action)
+	require.Equal(t, "Perform 1200-bps touch reset on serial port /dev/ttyACM0", steps[0].Description)
+	require.Equal(t, "wait_for_port", steps[1].Action)
+	require.Equal(t, "Wait up to 10s for the upload port, falling back to /dev/ttyACM0", steps[1].Description)
+
+	require.Len(t, resetSteps("/dev/ttyACM0", false, 10*time.Second, "/dev/ttyACM0"), 1)
+}
+
+func TestFindUploadedImage(t *testing.T) {
+	props := properties.NewMap()
+	props.SetPath("build.path", paths.New("testdata", "build_path_1"))
+	props.Set("build.project_name", "sketch.ino")
+	props.Set("upload.pattern", `tool -f "{build.path}/{build.project_name}.bin"`)
+	image := findUploadedImage(props, "upload.pattern")
+	require.NotNil(t, image)
+	require.Equal(t, "sketch.ino.bin", image.Base())
+
+	props.Set("upload.pattern", `tool -f "{build.path}/{build.project_name}.hex"`)
+	require.Nil(t, findUploadedImage(props, "upload.pattern"))
+}
+
+func TestBytesWrittenRegexp(t *testing.T) {
+	for output, expected := range map[string]string{
+		"avrdude: 924 bytes of flash written":                  "924",
+		"Wrote 10880 bytes to flash (43 pages)":                "10880",
+		"Wrote 265552 bytes (136456 compressed) at 0x00010000": "265552",
+	} {
+		match := bytesWrittenRegexp.FindStringSubmatch(output)
+		require.NotNil(t, match, output)
+		require.Equal(t, expected, match[1]+match[2])
+	}
+}
+
+func TestPortBusyRegexp(t *testing.T) {
+	for _, output := range []string{
+		"avrdude: ser_open(): can't open device \"/dev/ttyACM0\": Device or resource busy",
+		"avrdude: ser_open(): can't open device \"/dev/ttyACM0\": Permission denied",
+		"serial.serialutil.SerialException: could not open port 'COM3': PermissionError(13, 'Access is denied.', None, 5)",
+		"No device found on ttyACM0\nSAM-BA operation failed: Resource busy",
+	} {
+		require.True(t, portBusyRegexp.MatchString(output), output)
+	}
+	for _, output := range []string{
+		"avrdude: verification error, first mismatch at byte 0x0000",
+		"A fatal error occurred: Failed to connect to ESP32: Timed out waiting for packet header",
+	} {
+		require.False(t, portBusyRegexp.MatchString(output), output)
+	}
+}
+
+func TestRunUploadToolWithRetries(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test recipe uses sh")
+	}
+	retryBaseDelay = time.Millisecond
+	defer func() { retryBaseDelay = time.Second }()
+
+	buildPath, err := paths.MkTempDir("", "retries-test")
+	require.NoError(t, err)
+	defer buildPath.RemoveAll()
+	attempts := buildPath.Join("attempts")
+
+	props := properties.NewMap()
+	props.SetPath("build.path", buildPath)
+	props.Set("upload.busy.pattern", `sh -c "echo x >> '{build.path}/attempts'; echo 'Device or resource busy' >&2; exit 1"`)
+	props.Set("upload.failing.pattern", `sh -c "echo x >> '{build.path}/attempts'; echo 'verification error' >&2; exit 1"`)
+
+	out := &bytes.Buffer{}
+	err = runUploadToolWithRetries("upload.busy.pattern", props, nil, out, out, false, 2, &rpc.UploadResult{})
+	require.Error(t, err)
+	data, err := attempts.ReadFile()
+	require.NoError(t, err)
+	require.Equal(t, "x\nx\nx\n", string(data))
+	require.Contains(t, out.String(), "The port is busy, retrying the upload in 1ms (1/2)...")
+	require.Contains(t, out.String(), "The port is busy, retrying the upload in 2ms (2/2)...")
+
+	// Other errors are not retried
+	require.NoError(t, attempts.Remove())
+	err = runUploadToolWithRetries("upload.failing.pattern", props, nil, out, out, false, 2, &rpc.UploadResult{})
+	require.Error(t, err)
+	data, err = attempts.ReadFile()
+	require.NoError(t, err)
+	require.Equal(t, "x\n", string(data))
+}
@@ -37,6 +37,21 @@ var Settings *viper.Viper
 // Please note the logging system hasn't been configured yet,
 // so logging shouldn't be used here.
 func Init(configFile string) *viper.Viper {
+	settings, err := Load(configFile)
+	if err != nil {
+		// ConfigFileNotFoundError is acceptable, anything else
+		// should be reported to the user
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+			feedback.Errorf("Error reading config file: %v", err)
+		}
+	}
+	return settings
+}
+
+// Load initialize defaults and read the configuration file, like Init, but
+// returns the error reading the configuration file, if any, with the
+// settings holding the default values.
+func Load(configFile string) (*viper.Viper, error) {
 	jww.SetStdoutThreshold(jww.LevelFatal)
 
 	// Create a new viper instance with default values for all the settings
@@ -59,15 +74,8 @@ func Init(configFile string) *viper.Viper {
 	}
 
 	// Attempt to read config file
-	if err := settings.ReadInConfig(); err != nil {
-		// ConfigFileNotFoundError is acceptable, anything else
-		// should be reported to the user
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-			feedback.Errorf("Error reading config file: %v", err)
-		}
-	}
-
-	return settings
+	err := settings.ReadInConfig()
+	return settings, err
 }
 
 // BindFlags creates all the flags binding between the cobra Command and the instance of viper
@@ -76,6 +84,7 @@ func BindFlags(cmd *cobra.Command, settings *viper.Viper) {
 	settings.BindPFlag("logging.file", cmd.Flag("log-file"))
 	settings.BindPFlag("logging.format", cmd.Flag("log-format"))
 	settings.BindPFlag("board_manager.additional_urls", cmd.Flag("additional-urls"))
+	settings.BindPFlag("network.offline", cmd.Flag("offline"))
 }
 
 // getDefaultArduinoDataDir returns the full path to the default arduino folder
@@ -100,6 +100,22 @@ func TestInit(t *testing.T) {
 	require.Equal(t, ":9090", settings.GetString("metrics.addr"))
 }
 
+func TestLoad(t *testing.T) {
+	tmp := tmpDirOrDie()
+	defer os.RemoveAll(tmp)
+
+	configFile := filepath.Join(tmp, "arduino-cli.yaml")
+	require.NoError(t, ioutil.WriteFile(configFile, []byte("network:\n  offline: true\n"), 0644))
+	settings, err := Load(configFile)
+	require.NoError(t, err)
+	require.True(t, settings.GetBool("network.offline"))
+
+	require.NoError(t, ioutil.WriteFile(configFile, []byte("network: [\n"), 0644))
+	settings, err = Load(configFile)
+	require.Error(t, err)
+	require.Equal(t, "50051", settings.GetString("daemon.port"))
+}
+
 func TestFindConfigFile(t *testing.T) {
 	configFile := FindConfigFileInArgsOrWorkingDirectory([]string{"--config-file"})
 	require.Equal(t, "", configFile)
@@ -136,3 +152,35 @@ func TestFindConfigFile(t *testing.T) {
 	configFile = FindConfigFileInArgsOrWorkingDirectory([]string{})
 	require.Equal(t, filepath.Join(target, "arduino-cli.yaml"), configFile)
 }
+
+func TestUserHardwareDirectories(t *testing.T) {
+	tmp := tmpDirOrDie()
+	defer os.RemoveAll(tmp)
+	hardware := filepath.Join(tmp, "hardware")
+	require.NoError(t, os.MkdirAll(filepath.Join(hardware, "mycompany", "avr"), os.ModePerm))
+	platform := filepath.Join(tmp, "dev", "my-core")
+	require.NoError(t, os.MkdirAll(platform, os.ModePerm))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(platform, "boards.txt"), []byte{}, 0644))
+
+	settings := Init(filepath.Join(tmp, "arduino-cli.yaml"))
+	settings.Set("directories.user_hardware", []string{
+		hardware,
+		platform,
+		"mycompany:samd=" + platform,
+		filepath.Join(tmp, "missing"),
+	})
+
+	dirs := UserHardwareDirectories(settings)
+	require.Len(t, dirs, 3)
+	require.Equal(t, hardware, dirs[0].Path.String())
+	require.Empty(t, dirs[0].Architecture)
+	require.Equal(t, platform, dirs[1].Path.String())
+	require.Equal(t, "dev", dirs[1].Packager)
+	require.Equal(t, "my-core", dirs[1].Architecture)
+	require.Equal(t, "mycompany", dirs[2].Packager)
+	require.Equal(t, "samd", dirs[2].Architecture)
+
+	hardwareDirs := HardwareDirectories(settings)
+	require.True(t, hardwareDirs.Contains(paths.New(hardware)))
+	require.False(t, hardwareDirs.Contains(paths.New(platform)))
+}
@@ -16,6 +16,7 @@
 package configuration
 
 import (
+	"os"
 	"path/filepath"
 	"strings"
 
@@ -29,6 +30,7 @@ func SetDefaults(settings *viper.Viper) {
 	settings.SetDefault("logging.format", "text")
 
 	// Libraries
+	settings.SetDefault("library.allowed_licenses", []string{})
 	settings.SetDefault("library.enable_unsafe_install", false)
 
 	// Boards Manager
@@ -38,9 +40,65 @@ func SetDefaults(settings *viper.Viper) {
 	settings.SetDefault("directories.Data", getDefaultArduinoDataDir())
 	settings.SetDefault("directories.Downloads", filepath.Join(getDefaultArduinoDataDir(), "staging"))
 	settings.SetDefault("directories.User", getDefaultUserDir())
+	settings.SetDefault("directories.user_hardware", []string{})
+	settings.SetDefault("directories.boards_overlay", "")
 
 	// Sketch compilation
 	settings.SetDefault("sketch.always_export_binaries", false)
+	settings.SetDefault("compile.fail_on_warning", []string{})
+	settings.SetDefault("compile.hooks.pre_preprocess", []string{})
+	settings.SetDefault("compile.hooks.pre_link", []string{})
+	settings.SetDefault("compile.hooks.post_build", []string{})
+	settings.SetDefault("compile.allow_sketch_hooks", false)
+	settings.SetDefault("compile.container.image", "")
+	settings.SetDefault("compile.container.engine", "docker")
+	settings.SetDefault("compile.signing.key", "")
+	settings.SetDefault("compile.signing.artifacts_key", "")
+
+	// Cache of the compiled cores, shared by all the sketches
+	settings.SetDefault("build_cache.path", filepath.Join(os.TempDir(), "arduino-core-cache"))
+	settings.SetDefault("build_cache.max_size_mb", 1024)
+	settings.SetDefault("build_cache.eviction_policy", "lru")
+	settings.SetDefault("build_cache.remote_url", "")
+	settings.SetDefault("build_cache.remote_read_only", false)
+
+	// automatic update of the indexes
+	settings.SetDefault("updater.index_ttl", "24h")
+	settings.SetDefault("updater.index_delta", true)
+
+	// Sketch upload
+	settings.SetDefault("upload.discovery_timeout", "10s")
+	settings.SetDefault("upload.retries", 0)
+
+	// Boards attached to a remote host
+	settings.SetDefault("remote.cli_path", "arduino-cli")
+
+	// authentication of the private package and library indexes
+	settings.SetDefault("network.credentials", []interface{}{})
+	settings.SetDefault("network.use_netrc", false)
+	settings.SetDefault("network.offline", false)
+
+	// proxy authentication, the proxy is set with network.proxy or detected
+	// from the system settings
+	settings.SetDefault("network.proxy_username", "")
+	settings.SetDefault("network.proxy_password", "")
+	settings.SetDefault("network.proxy_command", "")
+
+	// retries and timeouts of the network operations
+	settings.SetDefault("network.retries", 0)
+	settings.SetDefault("network.backoff", "1s")
+	settings.SetDefault("network.timeouts.index_update", "60s")
+	settings.SetDefault("network.timeouts.download", "60s")
+	settings.SetDefault("network.timeouts.discovery", "10s")
+	settings.SetDefault("network.timeouts.build_cache", "30s")
+
+	// downloads of the platforms, tools and libraries archives
+	settings.SetDefault("network.mirrors", []string{})
+	settings.SetDefault("network.parallel_downloads", 4)
+
+	// signature verification of package indexes
+	settings.SetDefault("security.level", "warn")
+	settings.SetDefault("security.trusted_keys", []string{})
 
 	// daemon settings
 	settings.SetDefault("daemon.port", "50051")
@@ -48,6 +106,7 @@ func SetDefaults(settings *viper.Viper) {
 	// metrics settings
 	settings.SetDefault("metrics.enabled", true)
 	settings.SetDefault("metrics.addr", ":9090")
+	settings.SetDefault("metrics.pprof_token", "")
 
 	// Bind env vars
 	settings.SetEnvPrefix("ARDUINO")
@@ -16,6 +16,8 @@
 package configuration
 
 import (
+	"regexp"
+
 	"github.com/arduino/go-paths-helper"
 	"github.com/spf13/viper"
 )
@@ -33,7 +35,7 @@ func HardwareDirectories(settings *viper.Viper) paths.PathList {
 	}
 
 	if settings.IsSet("directories.Data") {
-		packagesDir := PackagesDir(Settings)
+		packagesDir := PackagesDir(settings)
 		if packagesDir.IsDir() {
 			res.Add(packagesDir)
 		}
@@ -47,9 +49,64 @@ func HardwareDirectories(settings *viper.Viper) paths.PathList {
 		}
 	}
 
+	for _, platform := range UserHardwareDirectories(settings) {
+		if platform.Architecture == "" {
+			res.Add(platform.Path)
+		}
+	}
+
+	return res
+}
+
+// UserHardwareDirectory is an entry of the directories.user_hardware setting:
+// either a hardware folder (containing PACKAGER/ARCHITECTURE folders) or the
+// folder of a single platform. For a platform folder Packager and Architecture
+// are set.
+type UserHardwareDirectory struct {
+	Path         *paths.Path
+	Packager     string
+	Architecture string
+}
+
+var userPlatformRegexp = regexp.MustCompile(`^([\w.-]+):([\w.-]+)=(.+)$`)
+
+// UserHardwareDirectories returns the existing folders listed in the
+// directories.user_hardware setting. An entry may be a hardware folder, or
+// the folder of a platform under development, with boards.txt in it. The
+// PACKAGER:ARCHITECTURE of a platform folder can be set with the
+// "PACKAGER:ARCHITECTURE=path" syntax, by default they are the names of the
+// parent folder and of the folder.
+func UserHardwareDirectories(settings *viper.Viper) []*UserHardwareDirectory {
+	res := []*UserHardwareDirectory{}
+	for _, entry := range settings.GetStringSlice("directories.user_hardware") {
+		dir := &UserHardwareDirectory{Path: paths.New(entry)}
+		if m := userPlatformRegexp.FindStringSubmatch(entry); m != nil {
+			dir = &UserHardwareDirectory{Path: paths.New(m[3]), Packager: m[1], Architecture: m[2]}
+		}
+		if abs, err := dir.Path.Abs(); err == nil {
+			dir.Path = abs
+		}
+		if !dir.Path.IsDir() {
+			continue
+		}
+		if dir.Architecture == "" && dir.Path.Join("boards.txt").Exist() {
+			dir.Packager = dir.Path.Parent().Base()
+			dir.Architecture = dir.Path.Base()
+		}
+		res = append(res, dir)
+	}
 	return res
 }
 
+// BoardsOverlayDir returns the folder of the board definitions merged over
+// the installed platforms, or nil if not configured
+func BoardsOverlayDir(settings *viper.Viper) *paths.Path {
+	if dir := settings.GetString("directories.boards_overlay"); dir != "" {
+		return paths.New(dir)
+	}
+	return nil
+}
+
 // BundleToolsDirectories returns all paths that may contains bundled-tools.
 func BundleToolsDirectories(settings *viper.Viper) paths.PathList {
 	res := paths.PathList{}
@@ -70,7 +127,7 @@ func BundleToolsDirectories(settings *viper.Viper) paths.PathList {
 // exists then nil is returned
 func IDEBundledLibrariesDir(settings *viper.Viper) *paths.Path {
 	if IsBundledInDesktopIDE(settings) {
-		ideDir := paths.New(Settings.GetString("IDE.Directory"))
+		ideDir := paths.New(settings.GetString("IDE.Directory"))
 		libDir := ideDir.Join("libraries")
 		if libDir.IsDir() {
 			return libDir
@@ -90,3 +147,15 @@ func LibrariesDir(settings *viper.Viper) *paths.Path {
 func PackagesDir(settings *viper.Viper) *paths.Path {
 	return paths.New(settings.GetString("directories.Data")).Join("packages")
 }
+
+// ArtifactsDir returns the full path to the folder where the tagged build
+// artifacts are stored
+func ArtifactsDir(settings *viper.Viper) *paths.Path {
+	return paths.New(settings.GetString("directories.Data")).Join("artifacts")
+}
+
+// TrustedKeys returns the public keys, besides the Arduino ones, trusted to
+// sign the package indexes
+func TrustedKeys(settings *viper.Viper) paths.PathList {
+	return paths.NewPathList(settings.GetStringSlice("security.trusted_keys")...)
+}
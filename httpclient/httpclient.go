@@ -16,17 +16,23 @@
 package httpclient
 
 import (
+	"errors"
 	"net/http"
+
+	"github.com/spf13/viper"
 )
 
-// New returns a default http client for use in the cli API calls
-func New() (*http.Client, error) {
-	config, err := DefaultConfig()
+// ErrOffline is returned for the requests made with the network access
+// disabled
+var ErrOffline = errors.New("network access is disabled in offline mode")
 
+// NewForOperationFromSettings returns a http client configured with the given
+// settings and the timeout configured for the given operation
+func NewForOperationFromSettings(settings *viper.Viper, op Operation) (*http.Client, error) {
+	config, err := ConfigForOperationFromSettings(settings, op)
 	if err != nil {
 		return nil, err
 	}
-
 	return NewWithConfig(config), nil
 }
 
@@ -20,41 +20,145 @@ import (
 	"fmt"
 	"net/url"
 	"runtime"
+	"time"
 
 	"github.com/arduino/arduino-cli/cli/globals"
-	"github.com/arduino/arduino-cli/configuration"
+	"github.com/arduino/go-paths-helper"
+	"github.com/spf13/viper"
 )
 
 // Config is the configuration of the http client
 type Config struct {
 	UserAgent string
 	Proxy     *url.URL
+	// UseSystemProxy selects the proxy set in the environment variables or in
+	// the operating system settings, if Proxy is nil
+	UseSystemProxy bool
+	// ProxyCredential is used to authenticate to the proxy, if its URL
+	// doesn't contain the credentials
+	ProxyCredential *Credential
+	Credentials     []*Credential
+	// Netrc is the netrc file where the credentials of the hosts without a
+	// matching entry in Credentials are searched, nil to disable
+	Netrc *paths.Path
+	// Offline forbids all the requests, except the ones to file:// URLs
+	Offline bool
+	// Retries is the number of times a failed request is retried, waiting
+	// Backoff before the first retry and doubling the wait at each retry
+	Retries int
+	Backoff time.Duration
+	// Timeout is the maximum time the server can take to send the response
+	// or the next chunk of data, 0 means no timeout
+	Timeout time.Duration
 }
 
-// DefaultConfig returns the default http client config
-func DefaultConfig() (*Config, error) {
+// Operation is a kind of network operation with its own timeout, configured
+// in network.timeouts
+type Operation string
+
+const (
+	// OperationIndexUpdate is the download of the package and library indexes
+	OperationIndexUpdate Operation = "index_update"
+	// OperationDownload is the download of the platforms, tools and libraries
+	OperationDownload Operation = "download"
+	// OperationDiscovery is the identification of the boards with the Arduino
+	// Cloud API
+	OperationDiscovery Operation = "discovery"
+	// OperationBuildCache is the access to the remote build cache
+	OperationBuildCache Operation = "build_cache"
+)
+
+// ConfigFromSettings returns the http client config built from the given
+// settings, that may be the ones of a single instance
+func ConfigFromSettings(settings *viper.Viper) (*Config, error) {
 	var proxy *url.URL
-	var err error
-	if configuration.Settings.IsSet("network.proxy") {
-		proxyConfig := configuration.Settings.GetString("network.proxy")
-		if proxyConfig == "" {
-			// empty configuration
-			// this workaround must be here until viper can UnSet properties:
-			// https://github.com/spf13/viper/pull/519
-		} else if proxy, err = url.Parse(proxyConfig); err != nil {
+	useSystemProxy := false
+	switch proxyConfig := settings.GetString("network.proxy"); proxyConfig {
+	case "":
+		useSystemProxy = true
+	case "direct":
+	default:
+		var err error
+		if proxy, err = url.Parse(proxyConfig); err != nil {
 			return nil, errors.New("Invalid network.proxy '" + proxyConfig + "': " + err.Error())
 		}
+		if proxy.Scheme != "http" && proxy.Scheme != "https" && proxy.Scheme != "socks5" {
+			return nil, errors.New("Invalid network.proxy '" + proxyConfig + "': unsupported scheme " + proxy.Scheme)
+		}
+	}
+	var proxyCredential *Credential
+	if username := settings.GetString("network.proxy_username"); username != "" {
+		proxyCredential = &Credential{
+			Username: username,
+			Password: settings.GetString("network.proxy_password"),
+			Command:  settings.GetString("network.proxy_command"),
+		}
+	}
+
+	credentials := []*Credential{}
+	if err := settings.UnmarshalKey("network.credentials", &credentials); err != nil {
+		return nil, errors.New("Invalid network.credentials: " + err.Error())
+	}
+	var netrc *paths.Path
+	if settings.GetBool("network.use_netrc") {
+		netrc = NetrcFile()
+	}
+	retries := settings.GetInt("network.retries")
+	if retries < 0 {
+		return nil, fmt.Errorf("Invalid network.retries '%d': must not be negative", retries)
+	}
+	backoff, err := durationSetting(settings, "network.backoff")
+	if err != nil {
+		return nil, err
 	}
 
 	return &Config{
-		UserAgent: UserAgent(),
-		Proxy:     proxy,
+		UserAgent:       userAgent(settings),
+		Proxy:           proxy,
+		UseSystemProxy:  useSystemProxy,
+		ProxyCredential: proxyCredential,
+		Credentials:     credentials,
+		Netrc:           netrc,
+		Offline:         IsOfflineFromSettings(settings),
+		Retries:         retries,
+		Backoff:         backoff,
 	}, nil
 }
 
-// UserAgent returns the user agent for the cli http client
-func UserAgent() string {
-	subComponent := configuration.Settings.GetString("network.user_agent_ext")
+// ConfigForOperationFromSettings returns the http client config built from
+// the given settings with the timeout configured for the given operation
+func ConfigForOperationFromSettings(settings *viper.Viper, op Operation) (*Config, error) {
+	config, err := ConfigFromSettings(settings)
+	if err != nil {
+		return nil, err
+	}
+	if config.Timeout, err = durationSetting(settings, "network.timeouts."+string(op)); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// durationSetting parses a duration setting, an empty value means 0
+func durationSetting(settings *viper.Viper, key string) (time.Duration, error) {
+	value := settings.GetString(key)
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, errors.New("Invalid " + key + " '" + value + "': " + err.Error())
+	}
+	return d, nil
+}
+
+// IsOfflineFromSettings returns true if the network access is disabled in
+// the given settings
+func IsOfflineFromSettings(settings *viper.Viper) bool {
+	return settings.GetBool("network.offline")
+}
+
+func userAgent(settings *viper.Viper) string {
+	subComponent := settings.GetString("network.user_agent_ext")
 	if subComponent != "" {
 		subComponent = " " + subComponent
 	}
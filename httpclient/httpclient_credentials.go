@@ -0,0 +1,205 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package httpclient
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/arduino/arduino-cli/executils"
+	"github.com/arduino/go-paths-helper"
+	properties "github.com/arduino/go-properties-orderedmap"
+	"github.com/sirupsen/logrus"
+)
+
+// Credential is the authentication used for the requests to the URLs under
+// URL: with the same scheme and host, and a path in the path of URL. A bearer Token is used if set, otherwise the basic
+// authentication with Username and Password. Command is an alternative to
+// storing the secret in the configuration: it's run to get the password (or
+// the token if Username is empty), e.g. from the OS keychain.
+type Credential struct {
+	URL      string `mapstructure:"url"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	Token    string `mapstructure:"token"`
+	Command  string `mapstructure:"command"`
+}
+
+// findCredential returns the credential with the longest URL matching the
+// given URL, or nil if none matches
+func findCredential(credentials []*Credential, u *url.URL) *Credential {
+	var res *Credential
+	resPathLen := -1
+	for _, credential := range credentials {
+		credentialURL, err := url.Parse(credential.URL)
+		if credential.URL == "" || err != nil {
+			continue
+		}
+		if !strings.EqualFold(credentialURL.Scheme, u.Scheme) || hostPort(credentialURL) != hostPort(u) {
+			continue
+		}
+		if !pathContains(credentialURL.Path, u.Path) {
+			continue
+		}
+		if len(credentialURL.Path) > resPathLen {
+			res = credential
+			resPathLen = len(credentialURL.Path)
+		}
+	}
+	return res
+}
+
+// hostPort returns the host of the URL, in lower case, and its port, the
+// default one of the scheme if not set
+func hostPort(u *url.URL) string {
+	port := u.Port()
+	if port == "" {
+		switch strings.ToLower(u.Scheme) {
+		case "http":
+			port = "80"
+		case "https":
+			port = "443"
+		}
+	}
+	return strings.ToLower(u.Hostname()) + ":" + port
+}
+
+// pathContains returns true if path is dir or is inside dir, the paths are
+// compared by segments so that /private doesn't contain /private-other
+func pathContains(dir, path string) bool {
+	dir = strings.TrimSuffix(dir, "/")
+	return path == dir || strings.HasPrefix(path, dir+"/")
+}
+
+// secret returns the password, or the token if Username is empty, running
+// Command if set
+func (credential *Credential) secret() (string, error) {
+	if credential.Command != "" {
+		return runCredentialCommand(credential.Command)
+	}
+	if credential.Username != "" {
+		return credential.Password, nil
+	}
+	return credential.Token, nil
+}
+
+// authenticate sets the Authorization header of the request
+func (credential *Credential) authenticate(req *http.Request) error {
+	secret, err := credential.secret()
+	if err != nil {
+		return fmt.Errorf("getting credentials for %s: %s", credential.URL, err)
+	}
+	if credential.Username != "" {
+		req.SetBasicAuth(credential.Username, secret)
+	} else if secret != "" {
+		req.Header.Set("Authorization", "Bearer "+secret)
+	}
+	return nil
+}
+
+func runCredentialCommand(commandLine string) (string, error) {
+	args, err := properties.SplitQuotedString(commandLine, `"'`, false)
+	if err != nil {
+		return "", err
+	}
+	cmd, err := executils.NewProcess(args...)
+	if err != nil {
+		return "", err
+	}
+	var stdout bytes.Buffer
+	cmd.RedirectStdoutTo(&stdout)
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("running %s: %s", args[0], err)
+	}
+	return strings.TrimRight(stdout.String(), "\r\n"), nil
+}
+
+// NetrcFile returns the path of the netrc file of the user: the one set in the
+// NETRC environment variable or the .netrc (_netrc on Windows) in the home
+// folder.
+func NetrcFile() *paths.Path {
+	if netrc, ok := os.LookupEnv("NETRC"); ok {
+		return paths.New(netrc)
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	if runtime.GOOS == "windows" {
+		return paths.New(home, "_netrc")
+	}
+	return paths.New(home, ".netrc")
+}
+
+// netrcCredential returns the credential of the given host in the netrc file,
+// the "default" entry is used if the host is not listed
+func netrcCredential(netrc *paths.Path, host string) *Credential {
+	data, err := netrc.ReadFile()
+	if err != nil {
+		logrus.WithError(err).Debug("Reading netrc file")
+		return nil
+	}
+	var res, def, current *Credential
+	tokens := strings.Fields(string(data))
+	for i := 0; i < len(tokens); i++ {
+		next := func() string {
+			if i+1 < len(tokens) {
+				i++
+				return tokens[i]
+			}
+			return ""
+		}
+		switch tokens[i] {
+		case "machine":
+			current = &Credential{}
+			if next() == host && res == nil {
+				res = current
+			}
+		case "default":
+			current = &Credential{}
+			if def == nil {
+				def = current
+			}
+		case "login":
+			if value := next(); current != nil {
+				current.Username = value
+			}
+		case "password":
+			if value := next(); current != nil {
+				current.Password = value
+			}
+		case "macdef":
+			// Macros are not supported, the definition ends with an empty line
+			// that is lost splitting the tokens, so stop parsing here
+			return firstCredential(res, def)
+		}
+	}
+	return firstCredential(res, def)
+}
+
+func firstCredential(credentials ...*Credential) *Credential {
+	for _, credential := range credentials {
+		if credential != nil && credential.Username != "" {
+			return credential
+		}
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package httpclient
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// proxyFunc returns the function selecting the proxy used for each request:
+// the configured one, the system one or none. The proxy credentials are added
+// to the proxy URL, the Transport uses them for the Proxy-Authorization header
+// or for the SOCKS5 authentication.
+func proxyFunc(config *Config) func(*http.Request) (*url.URL, error) {
+	var selectProxy func(*url.URL) (*url.URL, error)
+	if config.Proxy != nil {
+		selectProxy = func(*url.URL) (*url.URL, error) { return config.Proxy, nil }
+	} else if config.UseSystemProxy {
+		selectProxy = systemProxyConfig().ProxyFunc()
+	} else {
+		return nil
+	}
+
+	var once sync.Once
+	var user *url.Userinfo
+	var userErr error
+	return func(req *http.Request) (*url.URL, error) {
+		proxyURL, err := selectProxy(req.URL)
+		if err != nil || proxyURL == nil || proxyURL.User != nil || config.ProxyCredential == nil {
+			return proxyURL, err
+		}
+		once.Do(func() {
+			secret, err := config.ProxyCredential.secret()
+			if err != nil {
+				userErr = fmt.Errorf("getting proxy credentials: %s", err)
+				return
+			}
+			user = url.UserPassword(config.ProxyCredential.Username, secret)
+		})
+		if userErr != nil {
+			return nil, userErr
+		}
+		res := *proxyURL
+		res.User = user
+		return &res, nil
+	}
+}
+
+var systemProxy *httpproxy.Config
+var systemProxyOnce sync.Once
+
+// systemProxyConfig returns the proxy set in the HTTP_PROXY, HTTPS_PROXY and
+// NO_PROXY environment variables or, if not set, in the operating system
+// settings
+func systemProxyConfig() *httpproxy.Config {
+	systemProxyOnce.Do(func() {
+		systemProxy = httpproxy.FromEnvironment()
+		if systemProxy.HTTPProxy != "" || systemProxy.HTTPSProxy != "" {
+			return
+		}
+		if config := osProxyConfig(); config != nil {
+			systemProxy = config
+		}
+	})
+	return systemProxy
+}
+
+// parseScutilProxy parses the output of "scutil --proxy", that prints the
+// macOS proxy settings
+func parseScutilProxy(output string) *httpproxy.Config {
+	values := map[string]string{}
+	exceptions := []string{}
+	inExceptions := false
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if inExceptions {
+			if line == "}" {
+				inExceptions = false
+			} else if split := strings.SplitN(line, " : ", 2); len(split) == 2 {
+				exceptions = append(exceptions, split[1])
+			}
+			continue
+		}
+		split := strings.SplitN(line, " : ", 2)
+		if len(split) != 2 {
+			continue
+		}
+		if split[0] == "ExceptionsList" {
+			inExceptions = true
+			continue
+		}
+		values[split[0]] = split[1]
+	}
+
+	proxy := func(kind, scheme string) string {
+		if values[kind+"Enable"] != "1" || values[kind+"Proxy"] == "" {
+			return ""
+		}
+		host := values[kind+"Proxy"]
+		if port := values[kind+"Port"]; port != "" {
+			host += ":" + port
+		}
+		return scheme + "://" + host
+	}
+	config := &httpproxy.Config{
+		HTTPProxy:  proxy("HTTP", "http"),
+		HTTPSProxy: proxy("HTTPS", "http"),
+		NoProxy:    strings.Join(exceptions, ","),
+	}
+	if socks := proxy("SOCKS", "socks5"); socks != "" {
+		if config.HTTPProxy == "" {
+			config.HTTPProxy = socks
+		}
+		if config.HTTPSProxy == "" {
+			config.HTTPSProxy = socks
+		}
+	}
+	if config.HTTPProxy == "" && config.HTTPSProxy == "" {
+		return nil
+	}
+	return config
+}
+
+// parseWindowsProxy parses the ProxyServer and ProxyOverride values of the
+// Windows Internet Settings. ProxyServer is either a single "host:port" used
+// for all the protocols or a list like "http=host:port;https=host:port".
+func parseWindowsProxy(server, override string) *httpproxy.Config {
+	config := &httpproxy.Config{}
+	for _, entry := range strings.Split(server, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		split := strings.SplitN(entry, "=", 2)
+		if len(split) == 1 {
+			config.HTTPProxy = windowsProxyURL(entry)
+			config.HTTPSProxy = config.HTTPProxy
+			continue
+		}
+		// the "socks" entry is a SOCKS4 proxy, not supported
+		switch split[0] {
+		case "http":
+			config.HTTPProxy = windowsProxyURL(split[1])
+		case "https":
+			config.HTTPSProxy = windowsProxyURL(split[1])
+		}
+	}
+	if config.HTTPProxy == "" && config.HTTPSProxy == "" {
+		return nil
+	}
+
+	noProxy := []string{}
+	for _, entry := range strings.Split(override, ";") {
+		entry = strings.TrimSpace(entry)
+		// <local> bypasses the host names without a dot, it can't be
+		// expressed in NO_PROXY
+		if entry == "" || entry == "<local>" {
+			continue
+		}
+		noProxy = append(noProxy, strings.TrimPrefix(entry, "*"))
+	}
+	config.NoProxy = strings.Join(noProxy, ",")
+	return config
+}
+
+func windowsProxyURL(address string) string {
+	if strings.Contains(address, "://") {
+		return address
+	}
+	return "http://" + address
+}
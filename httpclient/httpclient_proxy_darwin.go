@@ -0,0 +1,38 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package httpclient
+
+import (
+	"bytes"
+
+	"github.com/arduino/arduino-cli/executils"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// osProxyConfig returns the proxy set in the macOS network settings, the
+// automatic configuration scripts (PAC) are not supported
+func osProxyConfig() *httpproxy.Config {
+	cmd, err := executils.NewProcess("scutil", "--proxy")
+	if err != nil {
+		return nil
+	}
+	var stdout bytes.Buffer
+	cmd.RedirectStdoutTo(&stdout)
+	if err := cmd.Run(); err != nil {
+		return nil
+	}
+	return parseScutilProxy(stdout.String())
+}
@@ -0,0 +1,41 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package httpclient
+
+import (
+	"golang.org/x/net/http/httpproxy"
+	"golang.org/x/sys/windows/registry"
+)
+
+// osProxyConfig returns the proxy set in the Windows Internet Settings, the
+// automatic configuration scripts (PAC) are not supported
+func osProxyConfig() *httpproxy.Config {
+	key, err := registry.OpenKey(registry.CURRENT_USER, `Software\Microsoft\Windows\CurrentVersion\Internet Settings`, registry.QUERY_VALUE)
+	if err != nil {
+		return nil
+	}
+	defer key.Close()
+
+	if enabled, _, err := key.GetIntegerValue("ProxyEnable"); err != nil || enabled == 0 {
+		return nil
+	}
+	server, _, err := key.GetStringValue("ProxyServer")
+	if err != nil {
+		return nil
+	}
+	override, _, _ := key.GetStringValue("ProxyOverride")
+	return parseWindowsProxy(server, override)
+}
@@ -0,0 +1,177 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RetryError is returned when a request still fails after all the attempts
+// configured with Config.Retries
+type RetryError struct {
+	URL      string
+	Attempts int
+	Err      error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("failed after %d attempts: %s", e.Attempts, e.Err)
+}
+
+// Unwrap returns the error of the last attempt
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// TimeoutError is returned when the server doesn't send any data for the time
+// configured with Config.Timeout
+type TimeoutError struct {
+	Timeout time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("no data received from the server in %s", e.Timeout)
+}
+
+// roundTripWithRetries performs the request, retrying the GET and HEAD
+// requests that fail because of a network error or a server side error.
+// The wait between two attempts starts from Config.Backoff and is doubled
+// after each attempt.
+func (h *httpClientRoundTripper) roundTripWithRetries(req *http.Request) (*http.Response, error) {
+	retries := h.config.Retries
+	if req.URL.Scheme == "file" || (req.Method != http.MethodGet && req.Method != http.MethodHead) {
+		retries = 0
+	}
+	backoff := h.config.Backoff
+	for attempt := 1; ; attempt++ {
+		resp, err := h.roundTripWithTimeout(req)
+		if err == nil && !isRetryableStatus(resp.StatusCode) {
+			return resp, nil
+		}
+		if err != nil && req.Context().Err() != nil {
+			// canceled by the caller
+			return nil, err
+		}
+		if attempt > retries {
+			if attempt == 1 {
+				return resp, err
+			}
+			if err == nil {
+				resp.Body.Close()
+				err = errors.New(resp.Status)
+			}
+			return nil, &RetryError{URL: req.URL.String(), Attempts: attempt, Err: err}
+		}
+		if err == nil {
+			resp.Body.Close()
+			err = errors.New(resp.Status)
+		}
+		logrus.WithError(err).Warnf("Request to %s failed, retrying in %s", req.URL, backoff)
+		select {
+		case <-time.After(backoff):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		backoff *= 2
+	}
+}
+
+func isRetryableStatus(status int) bool {
+	return status == http.StatusRequestTimeout || status == http.StatusTooManyRequests || status >= 500
+}
+
+// roundTripWithTimeout performs the request, aborting it if the server
+// doesn't send any data (the response or a chunk of the body) for the time
+// configured with Config.Timeout
+func (h *httpClientRoundTripper) roundTripWithTimeout(req *http.Request) (*http.Response, error) {
+	if h.config.Timeout <= 0 {
+		return h.transport.RoundTrip(req)
+	}
+	ctx, cancel := context.WithCancel(req.Context())
+	timer := newInactivityTimer(h.config.Timeout, cancel)
+	resp, err := h.transport.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		timer.Stop()
+		if timer.Expired() {
+			return nil, &TimeoutError{Timeout: h.config.Timeout}
+		}
+		return nil, err
+	}
+	resp.Body = &timeoutBody{ReadCloser: resp.Body, timer: timer}
+	return resp, nil
+}
+
+// inactivityTimer cancels a request when not reset in time
+type inactivityTimer struct {
+	timeout time.Duration
+	cancel  context.CancelFunc
+	timer   *time.Timer
+	mux     sync.Mutex
+	expired bool
+}
+
+func newInactivityTimer(timeout time.Duration, cancel context.CancelFunc) *inactivityTimer {
+	t := &inactivityTimer{timeout: timeout, cancel: cancel}
+	t.timer = time.AfterFunc(timeout, func() {
+		t.mux.Lock()
+		t.expired = true
+		t.mux.Unlock()
+		cancel()
+	})
+	return t
+}
+
+func (t *inactivityTimer) Reset() {
+	t.timer.Reset(t.timeout)
+}
+
+func (t *inactivityTimer) Stop() {
+	t.timer.Stop()
+	t.cancel()
+}
+
+func (t *inactivityTimer) Expired() bool {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	return t.expired
+}
+
+type timeoutBody struct {
+	io.ReadCloser
+	timer *inactivityTimer
+}
+
+func (b *timeoutBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF && b.timer.Expired() {
+		return n, &TimeoutError{Timeout: b.timer.timeout}
+	}
+	b.timer.Reset()
+	return n, err
+}
+
+func (b *timeoutBody) Close() error {
+	b.timer.Stop()
+	return b.ReadCloser.Close()
+}
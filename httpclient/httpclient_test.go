@@ -16,13 +16,18 @@
 package httpclient
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/arduino/go-paths-helper"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
 )
 
@@ -68,3 +73,231 @@ func TestProxy(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, http.StatusNoContent, response.StatusCode)
 }
+
+func TestProxyCredential(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.Header.Get("Proxy-Authorization"))
+	}))
+	defer ts.Close()
+
+	proxyURL, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+
+	client := NewWithConfig(&Config{
+		Proxy:           proxyURL,
+		ProxyCredential: &Credential{Username: "user", Password: "pass"},
+	})
+
+	response, err := client.Get("http://arduino.cc")
+	require.NoError(t, err)
+	b, err := ioutil.ReadAll(response.Body)
+	require.NoError(t, err)
+	require.Equal(t, "Basic dXNlcjpwYXNz", string(b))
+}
+
+func TestSystemProxyParsers(t *testing.T) {
+	scutil := `<dictionary> {
+  ExceptionsList : <array> {
+    0 : *.local
+    1 : 169.254/16
+  }
+  FTPPassive : 1
+  HTTPEnable : 1
+  HTTPPort : 3128
+  HTTPProxy : proxy.example.com
+  HTTPSEnable : 0
+  SOCKSEnable : 1
+  SOCKSPort : 1080
+  SOCKSProxy : socks.example.com
+}
+`
+	config := parseScutilProxy(scutil)
+	require.NotNil(t, config)
+	require.Equal(t, "http://proxy.example.com:3128", config.HTTPProxy)
+	require.Equal(t, "socks5://socks.example.com:1080", config.HTTPSProxy)
+	require.Equal(t, "*.local,169.254/16", config.NoProxy)
+	require.Nil(t, parseScutilProxy("<dictionary> {\n  HTTPEnable : 0\n}\n"))
+
+	config = parseWindowsProxy("proxy.example.com:8080", "<local>;*.example.com;10.*")
+	require.NotNil(t, config)
+	require.Equal(t, "http://proxy.example.com:8080", config.HTTPProxy)
+	require.Equal(t, "http://proxy.example.com:8080", config.HTTPSProxy)
+	require.Equal(t, ".example.com,10.*", config.NoProxy)
+
+	config = parseWindowsProxy("http=web:80;https=secure:443;socks=socks:1080", "")
+	require.NotNil(t, config)
+	require.Equal(t, "http://web:80", config.HTTPProxy)
+	require.Equal(t, "http://secure:443", config.HTTPSProxy)
+	require.Nil(t, parseWindowsProxy("socks=socks:1080", ""))
+}
+
+func TestCredentials(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.Header.Get("Authorization"))
+	}))
+	defer ts.Close()
+
+	client := NewWithConfig(&Config{
+		Credentials: []*Credential{
+			{URL: ts.URL + "/private/", Token: "secret-token"},
+			{URL: ts.URL + "/private/basic/", Username: "user", Password: "pass"},
+			{URL: "https://example.com/", Token: "other-token"},
+		},
+	})
+	get := func(path string) string {
+		response, err := client.Get(ts.URL + path)
+		require.NoError(t, err)
+		b, err := ioutil.ReadAll(response.Body)
+		require.NoError(t, err)
+		return string(b)
+	}
+
+	require.Equal(t, "Bearer secret-token", get("/private/package_index.json"))
+	require.Equal(t, "Basic dXNlcjpwYXNz", get("/private/basic/package_index.json"))
+	require.Equal(t, "", get("/public/package_index.json"))
+}
+
+func TestFindCredential(t *testing.T) {
+	corp := &Credential{URL: "https://pkg.corp.com", Token: "corp"}
+	private := &Credential{URL: "https://pkg.corp.com/private", Token: "private"}
+	credentials := []*Credential{corp, private}
+	find := func(rawURL string) *Credential {
+		u, err := url.Parse(rawURL)
+		require.NoError(t, err)
+		return findCredential(credentials, u)
+	}
+
+	require.Equal(t, corp, find("https://pkg.corp.com/package_index.json"))
+	require.Equal(t, corp, find("https://PKG.corp.com:443/package_index.json"))
+	require.Equal(t, private, find("https://pkg.corp.com/private/package_index.json"))
+	require.Equal(t, private, find("https://pkg.corp.com/private"))
+	require.Equal(t, corp, find("https://pkg.corp.com/private-other/package_index.json"))
+	// The credentials are never sent to a host that only starts with the
+	// configured one, nor with another scheme or port
+	require.Nil(t, find("https://pkg.corp.com.evil.net/package_index.json"))
+	require.Nil(t, find("https://pkg.corp.com@evil.net/package_index.json"))
+	require.Nil(t, find("http://pkg.corp.com/package_index.json"))
+	require.Nil(t, find("https://pkg.corp.com:8443/package_index.json"))
+}
+
+func TestNetrcCredential(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "httpclient-netrc")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+	netrc := tmp.Join(".netrc")
+	require.NoError(t, netrc.WriteFile([]byte(`
+machine downloads.example.com
+  login user
+  password pass
+default login anonymous password guest
+`)))
+
+	credential := netrcCredential(netrc, "downloads.example.com")
+	require.NotNil(t, credential)
+	require.Equal(t, "user", credential.Username)
+	require.Equal(t, "pass", credential.Password)
+
+	credential = netrcCredential(netrc, "other.example.com")
+	require.NotNil(t, credential)
+	require.Equal(t, "anonymous", credential.Username)
+
+	require.Nil(t, netrcCredential(tmp.Join("missing"), "downloads.example.com"))
+}
+
+func TestOffline(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "httpclient-offline")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+	file := tmp.Join("package_index.json")
+	require.NoError(t, file.WriteFile([]byte("{}")))
+
+	client := NewWithConfig(&Config{Offline: true})
+
+	_, err = client.Get("http://arduino.cc")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), ErrOffline.Error())
+
+	response, err := client.Get("file://" + filepath.ToSlash(file.String()))
+	require.NoError(t, err)
+	b, err := ioutil.ReadAll(response.Body)
+	require.NoError(t, err)
+	require.Equal(t, "{}", string(b))
+}
+
+func TestConfigFromSettings(t *testing.T) {
+	settings := viper.New()
+	settings.Set("network.offline", true)
+	settings.Set("network.proxy", "http://proxy.example.com:3128")
+	settings.Set("network.timeouts.download", "5s")
+
+	config, err := ConfigForOperationFromSettings(settings, OperationDownload)
+	require.NoError(t, err)
+	require.True(t, config.Offline)
+	require.Equal(t, "proxy.example.com:3128", config.Proxy.Host)
+	require.Equal(t, 5*time.Second, config.Timeout)
+
+	settings.Set("network.proxy", "ftp://proxy.example.com")
+	_, err = ConfigFromSettings(settings)
+	require.Error(t, err)
+}
+
+func TestRetries(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	defer ts.Close()
+
+	client := NewWithConfig(&Config{Retries: 2, Backoff: time.Millisecond})
+	response, err := client.Get(ts.URL)
+	require.NoError(t, err)
+	b, err := ioutil.ReadAll(response.Body)
+	require.NoError(t, err)
+	require.Equal(t, "ok", string(b))
+	require.Equal(t, 3, attempts)
+
+	// retries exhausted
+	attempts = -10
+	_, err = client.Get(ts.URL)
+	var retryErr *RetryError
+	require.True(t, errors.As(err, &retryErr))
+	require.Equal(t, 3, retryErr.Attempts)
+	require.EqualError(t, retryErr, "failed after 3 attempts: 503 Service Unavailable")
+
+	// client errors are not retried
+	attempts = 0
+	notFound := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		http.NotFound(w, r)
+	}))
+	defer notFound.Close()
+	response, err = client.Get(notFound.URL)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, response.StatusCode)
+	require.Equal(t, 1, attempts)
+}
+
+func TestTimeout(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("partial"))
+		w.(http.Flusher).Flush()
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer ts.Close()
+
+	client := NewWithConfig(&Config{Timeout: 100 * time.Millisecond})
+	response, err := client.Get(ts.URL)
+	require.NoError(t, err)
+	_, err = ioutil.ReadAll(response.Body)
+	var timeoutErr *TimeoutError
+	require.True(t, errors.As(err, &timeoutErr))
+	require.EqualError(t, err, "no data received from the server in 100ms")
+}
@@ -15,7 +15,13 @@
 
 package httpclient
 
-import "net/http"
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
 
 type httpClientRoundTripper struct {
 	transport http.RoundTripper
@@ -23,11 +29,11 @@ type httpClientRoundTripper struct {
 }
 
 func newHTTPClientTransport(config *Config) http.RoundTripper {
-	proxy := http.ProxyURL(config.Proxy)
-
 	transport := &http.Transport{
-		Proxy: proxy,
+		Proxy: proxyFunc(config),
 	}
+	// file:// URLs are used by the local mirrors
+	transport.RegisterProtocol("file", http.NewFileTransport(localFileSystem{}))
 
 	return &httpClientRoundTripper{
 		transport: transport,
@@ -36,6 +42,41 @@ func newHTTPClientTransport(config *Config) http.RoundTripper {
 }
 
 func (h *httpClientRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if h.config.Offline && req.URL.Scheme != "file" {
+		return nil, ErrOffline
+	}
 	req.Header.Add("User-Agent", h.config.UserAgent)
-	return h.transport.RoundTrip(req)
+	if req.Header.Get("Authorization") == "" && req.URL.User == nil {
+		if credential := h.credential(req); credential != nil {
+			if err := credential.authenticate(req); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return h.roundTripWithRetries(req)
+}
+
+// credential returns the credential to use for the request. Since it's
+// matched against the URL of each request, the credential is not sent when
+// redirected to a different server.
+func (h *httpClientRoundTripper) credential(req *http.Request) *Credential {
+	if credential := findCredential(h.config.Credentials, req.URL); credential != nil {
+		return credential
+	}
+	if h.config.Netrc != nil {
+		return netrcCredential(h.config.Netrc, req.URL.Hostname())
+	}
+	return nil
+}
+
+// localFileSystem serves the files of the local file system by their absolute
+// path, as found in file:// URLs
+type localFileSystem struct{}
+
+func (localFileSystem) Open(name string) (http.File, error) {
+	if runtime.GOOS == "windows" {
+		// file:///C:/path/to/file
+		name = strings.TrimPrefix(name, "/")
+	}
+	return os.Open(filepath.FromSlash(name))
 }
@@ -15,17 +15,18 @@
 
 package i18n
 
-import "github.com/arduino/arduino-cli/configuration"
+import "github.com/spf13/viper"
 
 // Init initializes the i18n module, setting the locale according to this order of preference:
-// 1. Configuration set in arduino-cli.yaml
+// 1. Configuration set in the given settings
 // 2. OS Locale
 // 3. en (default)
-func Init() {
+// The locale is shared by the whole process.
+func Init(settings *viper.Viper) {
 	initRiceBox()
 	locales := supportedLocales()
 
-	if configLocale := configuration.Settings.GetString("locale"); configLocale != "" {
+	if configLocale := settings.GetString("locale"); configLocale != "" {
 		if locale := findMatchingLocale(configLocale, locales); locale != "" {
 			setLocale(locale)
 			return
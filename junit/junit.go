@@ -0,0 +1,116 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package junit
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+// TestSuites is the root element of a JUnit XML report, as read by the CI
+// servers (Jenkins, GitLab...)
+type TestSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr,omitempty"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Errors   int          `xml:"errors,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Suites   []*TestSuite `xml:"testsuite"`
+}
+
+// TestSuite is a group of test cases
+type TestSuite struct {
+	Name      string      `xml:"name,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Failures  int         `xml:"failures,attr"`
+	Errors    int         `xml:"errors,attr"`
+	Skipped   int         `xml:"skipped,attr"`
+	Time      string      `xml:"time,attr"`
+	Cases     []*TestCase `xml:"testcase"`
+	SystemOut string      `xml:"system-out,omitempty"`
+}
+
+// TestCase is the result of a single test, it passed if Failure, Error and
+// Skipped are all nil
+type TestCase struct {
+	Name      string   `xml:"name,attr"`
+	ClassName string   `xml:"classname,attr"`
+	File      string   `xml:"file,attr,omitempty"`
+	Line      int      `xml:"line,attr,omitempty"`
+	Time      string   `xml:"time,attr"`
+	Failure   *Message `xml:"failure"`
+	Error     *Message `xml:"error"`
+	Skipped   *Message `xml:"skipped"`
+	SystemOut string   `xml:"system-out,omitempty"`
+}
+
+// Message is the reason of a failure, error or skip. Details contains the
+// full text, e.g. the compiler errors.
+type Message struct {
+	Message string `xml:"message,attr,omitempty"`
+	Details string `xml:",chardata"`
+}
+
+// Seconds formats a duration as the time attributes of the report
+func Seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// AddCase adds a test case to the suite and updates the counters
+func (s *TestSuite) AddCase(c *TestCase) {
+	if c.Time == "" {
+		c.Time = Seconds(0)
+	}
+	s.Tests++
+	switch {
+	case c.Failure != nil:
+		s.Failures++
+	case c.Error != nil:
+		s.Errors++
+	case c.Skipped != nil:
+		s.Skipped++
+	}
+	s.Cases = append(s.Cases, c)
+}
+
+// AddSuite adds a test suite to the report and updates the counters
+func (r *TestSuites) AddSuite(s *TestSuite) {
+	if s.Time == "" {
+		s.Time = Seconds(0)
+	}
+	r.Tests += s.Tests
+	r.Failures += s.Failures
+	r.Errors += s.Errors
+	r.Skipped += s.Skipped
+	r.Suites = append(r.Suites, s)
+}
+
+// Write writes the report in the JUnit XML format
+func (r *TestSuites) Write(w io.Writer) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(r); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
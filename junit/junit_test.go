@@ -0,0 +1,49 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package junit
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	suite := &TestSuite{Name: "Servo (arduino:avr:uno)", Time: Seconds(1500 * time.Millisecond)}
+	suite.AddCase(&TestCase{Name: "Sweep", ClassName: "Servo", Time: Seconds(time.Second)})
+	suite.AddCase(&TestCase{Name: "Knob", ClassName: "Servo", Failure: &Message{Message: "exit status 1", Details: "Knob.ino:3: error: 'x' was not declared"}})
+	suite.AddCase(&TestCase{Name: "Other", ClassName: "Servo", Skipped: &Message{}})
+	report := &TestSuites{}
+	report.AddSuite(suite)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.Write(&buf))
+	require.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="3" failures="1" errors="0" skipped="1">
+  <testsuite name="Servo (arduino:avr:uno)" tests="3" failures="1" errors="0" skipped="1" time="1.500">
+    <testcase name="Sweep" classname="Servo" time="1.000"></testcase>
+    <testcase name="Knob" classname="Servo" time="0.000">
+      <failure message="exit status 1">Knob.ino:3: error: &#39;x&#39; was not declared</failure>
+    </testcase>
+    <testcase name="Other" classname="Servo" time="0.000">
+      <skipped></skipped>
+    </testcase>
+  </testsuite>
+</testsuites>
+`, buf.String())
+}
@@ -62,6 +62,9 @@ func (*AddAdditionalEntriesToContext) Run(ctx *types.Context) error {
 	if ctx.WarningsLevel == "" {
 		ctx.WarningsLevel = DEFAULT_WARNINGS_LEVEL
 	}
+	if ctx.LibrariesWarningsLevel == "" {
+		ctx.LibrariesWarningsLevel = ctx.WarningsLevel
+	}
 
 	ctx.CollectedSourceFiles = &types.UniqueSourceFileQueue{}
 
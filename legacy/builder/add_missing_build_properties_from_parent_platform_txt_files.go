@@ -29,6 +29,7 @@ func (s *AddMissingBuildPropertiesFromParentPlatformTxtFiles) Run(ctx *types.Con
 	newBuildProperties.Merge(ArduinoPreprocessorProperties)
 	newBuildProperties.Merge(buildProperties)
 	ctx.BuildProperties = newBuildProperties
+	ctx.PropertiesTrace.Record(newBuildProperties, "the default properties of the preprocessor tools")
 
 	return nil
 }
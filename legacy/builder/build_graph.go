@@ -0,0 +1,154 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"runtime"
+	"sort"
+
+	"github.com/arduino/arduino-cli/legacy/builder/builder_utils"
+	"github.com/arduino/arduino-cli/legacy/builder/types"
+	"github.com/pkg/errors"
+)
+
+// BuildGraph is a set of builder commands with the dependencies between them.
+// A command is run as soon as all its dependencies have completed, so the
+// commands that don't depend on each other are run in parallel.
+type BuildGraph struct {
+	nodes []*BuildNode
+}
+
+// BuildNode is a command of a BuildGraph
+type BuildNode struct {
+	index      int
+	command    types.Command
+	deps       []*BuildNode
+	dependents []*BuildNode
+}
+
+// NewBuildGraph creates an empty BuildGraph
+func NewBuildGraph() *BuildGraph {
+	return &BuildGraph{}
+}
+
+// Add adds a command that is run after all the given nodes have completed
+func (g *BuildGraph) Add(command types.Command, deps ...*BuildNode) *BuildNode {
+	node := &BuildNode{index: len(g.nodes), command: command, deps: deps}
+	for _, dep := range deps {
+		dep.dependents = append(dep.dependents, node)
+	}
+	g.nodes = append(g.nodes, node)
+	return node
+}
+
+// Chain adds a sequence of commands, each one depending on the previous
+// one, the first command is run after all the given nodes have completed.
+// It returns the node of the last command.
+func (g *BuildGraph) Chain(deps []*BuildNode, commands ...types.Command) *BuildNode {
+	for _, command := range commands {
+		node := g.Add(command, deps...)
+		deps = []*BuildNode{node}
+	}
+	if len(deps) != 1 {
+		return nil
+	}
+	return deps[0]
+}
+
+type buildNodeResult struct {
+	node *BuildNode
+	err  error
+}
+
+// Run runs the commands of the graph using up to ctx.Jobs commands in
+// parallel (or the number of available CPUs if ctx.Jobs is 0). When more
+// commands are ready they are started in the order they have been added to
+// the graph, so with ctx.Jobs set to 1 the commands run one after the other
+// in that order.
+// The commands depending on a failed command are never run. The first error
+// stops the scheduling of new commands, unless ctx.KeepGoing is set, and is
+// returned once the running commands are completed.
+func (g *BuildGraph) Run(ctx *types.Context) error {
+	if len(g.nodes) == 0 {
+		return nil
+	}
+
+	steps := ctx.Progress.AddSteps(len(g.nodes))
+	defer steps.Close()
+
+	parallel := ctx.Jobs
+	if parallel <= 0 {
+		parallel = runtime.NumCPU()
+	}
+
+	pending := map[*BuildNode]int{}
+	ready := []*BuildNode{}
+	for _, node := range g.nodes {
+		pending[node] = len(node.deps)
+		if len(node.deps) == 0 {
+			ready = append(ready, node)
+		}
+	}
+
+	var errorsList []error
+	results := make(chan *buildNodeResult)
+	running := 0
+	for {
+		for len(ready) > 0 && running < parallel && (len(errorsList) == 0 || ctx.KeepGoing) {
+			node := ready[0]
+			ready = ready[1:]
+			running++
+			go func() {
+				results <- &buildNodeResult{node: node, err: runBuildNode(ctx, node)}
+			}()
+		}
+		if running == 0 {
+			break
+		}
+
+		res := <-results
+		running--
+		steps.CompleteStep()
+		builder_utils.PrintProgressIfProgressEnabledAndMachineLogger(ctx)
+		ctx.ReportProgress(commandName(res.node.command), true)
+		if res.err != nil {
+			errorsList = append(errorsList, res.err)
+			continue
+		}
+		for _, dependent := range res.node.dependents {
+			pending[dependent]--
+			if pending[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+		sort.Slice(ready, func(i, j int) bool { return ready[i].index < ready[j].index })
+	}
+
+	if len(errorsList) > 0 {
+		// output the first error
+		return errorsList[0]
+	}
+	return nil
+}
+
+func runBuildNode(ctx *types.Context, node *BuildNode) error {
+	PrintRingNameIfDebug(ctx, node.command)
+	ctx.ReportProgress(commandName(node.command), false)
+	endEvent := ctx.Stats.StartEvent(types.BuildEventPhase, commandName(node.command))
+	err := node.command.Run(ctx)
+	endEvent()
+	return errors.WithStack(err)
+}
@@ -22,7 +22,6 @@ import (
 	"time"
 
 	bldr "github.com/arduino/arduino-cli/arduino/builder"
-	"github.com/arduino/arduino-cli/legacy/builder/builder_utils"
 	"github.com/arduino/arduino-cli/legacy/builder/constants"
 	"github.com/arduino/arduino-cli/legacy/builder/phases"
 	"github.com/arduino/arduino-cli/legacy/builder/types"
@@ -45,7 +44,10 @@ func (s *Builder) Run(ctx *types.Context) error {
 		return err
 	}
 
-	commands := []types.Command{
+	// The sketch, the libraries and the core are compiled in parallel, the
+	// libraries and the core don't need to wait for the sketch preprocessing
+	graph := NewBuildGraph()
+	prebuild := graph.Chain(nil,
 		&ContainerSetupHardwareToolsLibsSketchAndProps{},
 
 		&ContainerBuildOptions{},
@@ -54,13 +56,21 @@ func (s *Builder) Run(ctx *types.Context) error {
 
 		&RecipeByPrefixSuffixRunner{Prefix: constants.HOOKS_PREBUILD, Suffix: constants.HOOKS_PATTERN_SUFFIX},
 
+		&SetupPartitionTable{},
+
+		&UserHooksRunner{Stage: bldr.HookPrePreprocess},
+	)
+
+	includes := graph.Chain([]*BuildNode{prebuild},
 		&ContainerMergeCopySketchFiles{},
 
 		utils.LogIfVerbose(constants.LOG_LEVEL_INFO, "Detecting libraries used..."),
 		&ContainerFindIncludes{},
 
 		&WarnAboutArchIncompatibleLibraries{},
+	)
 
+	sketch := graph.Chain([]*BuildNode{includes},
 		utils.LogIfVerbose(constants.LOG_LEVEL_INFO, "Generating function prototypes..."),
 		&PreprocessSketch{},
 
@@ -68,20 +78,27 @@ func (s *Builder) Run(ctx *types.Context) error {
 		&RecipeByPrefixSuffixRunner{Prefix: constants.HOOKS_SKETCH_PREBUILD, Suffix: constants.HOOKS_PATTERN_SUFFIX},
 		&phases.SketchBuilder{},
 		&RecipeByPrefixSuffixRunner{Prefix: constants.HOOKS_SKETCH_POSTBUILD, Suffix: constants.HOOKS_PATTERN_SUFFIX},
+	)
 
+	libraries := graph.Chain([]*BuildNode{includes},
 		utils.LogIfVerbose(constants.LOG_LEVEL_INFO, "Compiling libraries..."),
 		&RecipeByPrefixSuffixRunner{Prefix: constants.HOOKS_LIBRARIES_PREBUILD, Suffix: constants.HOOKS_PATTERN_SUFFIX},
 		&UnusedCompiledLibrariesRemover{},
 		&phases.LibrariesBuilder{},
 		&RecipeByPrefixSuffixRunner{Prefix: constants.HOOKS_LIBRARIES_POSTBUILD, Suffix: constants.HOOKS_PATTERN_SUFFIX},
+	)
 
+	core := graph.Chain([]*BuildNode{prebuild},
 		utils.LogIfVerbose(constants.LOG_LEVEL_INFO, "Compiling core..."),
 		&RecipeByPrefixSuffixRunner{Prefix: constants.HOOKS_CORE_PREBUILD, Suffix: constants.HOOKS_PATTERN_SUFFIX},
 		&phases.CoreBuilder{},
 		&RecipeByPrefixSuffixRunner{Prefix: constants.HOOKS_CORE_POSTBUILD, Suffix: constants.HOOKS_PATTERN_SUFFIX},
+	)
 
+	graph.Chain([]*BuildNode{sketch, libraries, core},
 		utils.LogIfVerbose(constants.LOG_LEVEL_INFO, "Linking everything together..."),
 		&RecipeByPrefixSuffixRunner{Prefix: constants.HOOKS_LINKING_PRELINK, Suffix: constants.HOOKS_PATTERN_SUFFIX},
+		&UserHooksRunner{Stage: bldr.HookPreLink},
 		&phases.Linker{},
 		&RecipeByPrefixSuffixRunner{Prefix: constants.HOOKS_LINKING_POSTLINK, Suffix: constants.HOOKS_PATTERN_SUFFIX},
 
@@ -91,16 +108,29 @@ func (s *Builder) Run(ctx *types.Context) error {
 
 		&MergeSketchWithBootloader{},
 
+		&ExportBinaryFormats{},
+
+		&SignBinaries{},
+
 		&RecipeByPrefixSuffixRunner{Prefix: constants.HOOKS_POSTBUILD, Suffix: constants.HOOKS_PATTERN_SUFFIX},
-	}
 
-	mainErr := runCommands(ctx, commands)
+		&UserHooksRunner{Stage: bldr.HookPostBuild},
+	)
+
+	mainErr := graph.Run(ctx)
 
 	if ctx.CompilationDatabase != nil {
 		ctx.CompilationDatabase.SaveToFile()
 	}
+	if ctx.BuildScript != nil && mainErr == nil {
+		if err := ctx.BuildScript.SaveToFile(); err != nil {
+			mainErr = errors.WithStack(err)
+		} else {
+			ctx.GetLogger().Println(constants.LOG_LEVEL_INFO, "Build script saved to {0}", ctx.BuildScript.File)
+		}
+	}
 
-	commands = []types.Command{
+	commands := []types.Command{
 		&PrintUsedAndNotUsedLibraries{SketchError: mainErr != nil},
 
 		&PrintUsedLibrariesIfVerbose{},
@@ -148,6 +178,8 @@ func (s *Preprocess) Run(ctx *types.Context) error {
 
 		&RecipeByPrefixSuffixRunner{Prefix: constants.HOOKS_PREBUILD, Suffix: constants.HOOKS_PATTERN_SUFFIX},
 
+		&UserHooksRunner{Stage: bldr.HookPrePreprocess},
+
 		&ContainerMergeCopySketchFiles{},
 
 		&ContainerFindIncludes{},
@@ -182,20 +214,23 @@ func (s *ParseHardwareAndDumpBuildProperties) Run(ctx *types.Context) error {
 	return runCommands(ctx, commands)
 }
 
+// runCommands runs the commands one after the other
 func runCommands(ctx *types.Context, commands []types.Command) error {
-	ctx.Progress.AddSubSteps(len(commands))
-	defer ctx.Progress.RemoveSubSteps()
-
-	for _, command := range commands {
-		PrintRingNameIfDebug(ctx, command)
-		err := command.Run(ctx)
-		if err != nil {
-			return errors.WithStack(err)
-		}
-		ctx.Progress.CompleteStep()
-		builder_utils.PrintProgressIfProgressEnabledAndMachineLogger(ctx)
+	graph := NewBuildGraph()
+	graph.Chain(nil, commands...)
+	return graph.Run(ctx)
+}
+
+// commandName returns the name of the command shown in the build statistics
+func commandName(command types.Command) string {
+	name := reflect.Indirect(reflect.ValueOf(command)).Type().Name()
+	switch c := command.(type) {
+	case *RecipeByPrefixSuffixRunner:
+		name += " " + c.Prefix
+	case *UserHooksRunner:
+		name += " " + c.Stage
 	}
-	return nil
+	return name
 }
 
 func PrintRingNameIfDebug(ctx *types.Context, command types.Command) {
@@ -209,6 +244,33 @@ func RunBuilder(ctx *types.Context) error {
 	return command.Run(ctx)
 }
 
+type ParseHardwareAndShowCommands struct{}
+
+func (s *ParseHardwareAndShowCommands) Run(ctx *types.Context) error {
+	if ctx.BuildPath == nil {
+		ctx.BuildPath = bldr.GenBuildPath(ctx.SketchLocation)
+	}
+
+	// The libraries used by the sketch are detected running the
+	// preprocessor, no other command is run
+	commands := []types.Command{
+		&ContainerSetupHardwareToolsLibsSketchAndProps{},
+
+		&ContainerMergeCopySketchFiles{},
+
+		&ContainerFindIncludes{},
+
+		&ShowCommands{},
+	}
+
+	return runCommands(ctx, commands)
+}
+
+func RunParseHardwareAndShowCommands(ctx *types.Context) error {
+	command := ParseHardwareAndShowCommands{}
+	return command.Run(ctx)
+}
+
 func RunParseHardwareAndDumpBuildProperties(ctx *types.Context) error {
 	command := ParseHardwareAndDumpBuildProperties{}
 	return command.Run(ctx)
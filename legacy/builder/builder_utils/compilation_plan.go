@@ -0,0 +1,246 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder_utils
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/arduino/arduino-cli/legacy/builder/constants"
+	"github.com/arduino/arduino-cli/legacy/builder/types"
+	"github.com/arduino/arduino-cli/legacy/builder/utils"
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/pkg/errors"
+)
+
+// CompilationPlan collects the source files of many folders (for example all the
+// libraries used by a sketch) so that they can be compiled in parallel instead of
+// one folder after the other. The plans running at the same time share the job
+// slots of the build context, so that at most ctx.Jobs files are compiled at once.
+type CompilationPlan struct {
+	jobs []*compileJob
+}
+
+// CompilationUnit is the set of source files added to a CompilationPlan with a
+// single call. After the plan has been run it gives access to the resulting
+// object files, always in the same order regardless of the scheduling.
+type CompilationUnit struct {
+	batches [][]*compileJob
+}
+
+type compileJob struct {
+	sourcePath      *paths.Path
+	source          *paths.Path
+	buildPath       *paths.Path
+	buildProperties *properties.Map
+	includes        []string
+	recipe          string
+	databaseOnly    bool
+	objectFile      *paths.Path
+}
+
+// NewCompilationPlan creates an empty CompilationPlan
+func NewCompilationPlan() *CompilationPlan {
+	return &CompilationPlan{}
+}
+
+// AddFiles schedules the compilation of the .S, .c and .cpp files contained in sourcePath
+// (and its subfolders if recurse is true). The object files retain the folder structure
+// of sourcePath inside buildPath.
+func (p *CompilationPlan) AddFiles(sourcePath *paths.Path, recurse bool, buildPath *paths.Path, buildProperties *properties.Map, includes []string) (*CompilationUnit, error) {
+	unit := &CompilationUnit{}
+	if err := p.addFilesToUnit(unit, sourcePath, recurse, buildPath, buildProperties, includes, false); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return unit, nil
+}
+
+// AddFilesToCompilationDatabase adds the compile commands of the files contained in
+// sourcePath to the compilation database, without compiling them. It's used when
+// the object files are already available, for example from the core cache.
+func (p *CompilationPlan) AddFilesToCompilationDatabase(sourcePath *paths.Path, recurse bool, buildPath *paths.Path, buildProperties *properties.Map, includes []string) error {
+	return p.addFilesToUnit(&CompilationUnit{}, sourcePath, recurse, buildPath, buildProperties, includes, true)
+}
+
+// AddFilesRecursive schedules the compilation of all the source files contained in
+// sourcePath and its subfolders, each subfolder is compiled in the corresponding
+// subfolder of buildPath.
+func (p *CompilationPlan) AddFilesRecursive(sourcePath *paths.Path, buildPath *paths.Path, buildProperties *properties.Map, includes []string) (*CompilationUnit, error) {
+	unit := &CompilationUnit{}
+	if err := p.addFilesRecursiveToUnit(unit, sourcePath, buildPath, buildProperties, includes); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return unit, nil
+}
+
+func (p *CompilationPlan) addFilesRecursiveToUnit(unit *CompilationUnit, sourcePath *paths.Path, buildPath *paths.Path, buildProperties *properties.Map, includes []string) error {
+	if err := p.addFilesToUnit(unit, sourcePath, false, buildPath, buildProperties, includes, false); err != nil {
+		return errors.WithStack(err)
+	}
+
+	folders, err := utils.ReadDirFiltered(sourcePath.String(), utils.FilterDirs)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	for _, folder := range folders {
+		err := p.addFilesRecursiveToUnit(unit, sourcePath.Join(folder.Name()), buildPath.Join(folder.Name()), buildProperties, includes)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+func (p *CompilationPlan) addFilesToUnit(unit *CompilationUnit, sourcePath *paths.Path, recurse bool, buildPath *paths.Path, buildProperties *properties.Map, includes []string, databaseOnly bool) error {
+	for _, kind := range []struct {
+		extension string
+		recipe    string
+	}{
+		{".S", constants.RECIPE_S_PATTERN},
+		{".c", constants.RECIPE_C_PATTERN},
+		{".cpp", constants.RECIPE_CPP_PATTERN},
+	} {
+		sources, err := findFilesInFolder(sourcePath, kind.extension, recurse)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		batch := []*compileJob{}
+		for _, source := range sources {
+			job := &compileJob{
+				sourcePath:      sourcePath,
+				source:          source,
+				buildPath:       buildPath,
+				buildProperties: buildProperties,
+				includes:        includes,
+				recipe:          kind.recipe,
+				databaseOnly:    databaseOnly,
+			}
+			batch = append(batch, job)
+			p.jobs = append(p.jobs, job)
+		}
+		unit.batches = append(unit.batches, batch)
+	}
+	return nil
+}
+
+// Run compiles all the scheduled source files using up to ctx.Jobs parallel
+// processes (or the number of available CPUs if ctx.Jobs is 0), counting the
+// ones started by the other plans running at the same time. A progress step is
+// completed when each file has been compiled.
+// The first compile error stops the scheduling of new jobs, unless
+// ctx.KeepGoing is set, and is returned once the running jobs are completed.
+func (p *CompilationPlan) Run(ctx *types.Context) error {
+	if len(p.jobs) == 0 {
+		return nil
+	}
+
+	steps := ctx.Progress.AddSteps(len(p.jobs))
+	defer steps.Close()
+
+	var errorsList []error
+	var errorsMux sync.Mutex
+
+	queue := make(chan *compileJob)
+	run := func(job *compileJob) {
+		ctx.AcquireJobSlot()
+		objectFile, err := compileFileWithRecipe(ctx, job.sourcePath, job.source, job.buildPath, job.buildProperties, job.includes, job.recipe, job.databaseOnly)
+		ctx.ReleaseJobSlot()
+
+		steps.CompleteStep()
+		PrintProgressIfProgressEnabledAndMachineLogger(ctx)
+		if err != nil {
+			errorsMux.Lock()
+			errorsList = append(errorsList, err)
+			errorsMux.Unlock()
+		} else {
+			// each job is owned by a single worker, no locking needed
+			job.objectFile = objectFile
+		}
+	}
+
+	// Spawn jobs runners
+	var wg sync.WaitGroup
+	jobs := ctx.Jobs
+	if jobs <= 0 {
+		jobs = runtime.NumCPU()
+	}
+	if jobs > len(p.jobs) {
+		jobs = len(p.jobs)
+	}
+	for i := 0; i < jobs; i++ {
+		wg.Add(1)
+		go func() {
+			for job := range queue {
+				run(job)
+			}
+			wg.Done()
+		}()
+	}
+
+	// Feed jobs until error or done
+	for _, job := range p.jobs {
+		errorsMux.Lock()
+		gotError := len(errorsList) > 0 && !ctx.KeepGoing
+		errorsMux.Unlock()
+		if gotError {
+			break
+		}
+		queue <- job
+	}
+	close(queue)
+	wg.Wait()
+	if len(errorsList) > 0 {
+		// output the first error
+		return errors.WithStack(errorsList[0])
+	}
+	return nil
+}
+
+// ForEachCommand calls f with the recipe and the build properties used to
+// compile each scheduled source file, in the order they were added, without
+// compiling them.
+func (p *CompilationPlan) ForEachCommand(f func(recipe string, buildProperties *properties.Map) error) error {
+	for _, job := range p.jobs {
+		properties, err := compileProperties(job.sourcePath, job.source, job.buildPath, job.buildProperties, job.includes)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if err := f(job.recipe, properties); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ObjectFiles returns the object files produced by the unit. The object files
+// are grouped by folder and by source type (.S, .c, .cpp) and sorted by name,
+// so that archive and link steps always receive them in the same order.
+func (u *CompilationUnit) ObjectFiles() paths.PathList {
+	objectFiles := paths.NewPathList()
+	for _, batch := range u.batches {
+		batchObjectFiles := paths.NewPathList()
+		for _, job := range batch {
+			if job.objectFile != nil {
+				batchObjectFiles.Add(job.objectFile)
+			}
+		}
+		batchObjectFiles.Sort()
+		objectFiles.AddAll(batchObjectFiles)
+	}
+	return objectFiles
+}
@@ -0,0 +1,101 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder_utils
+
+import (
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/builder"
+	"github.com/arduino/arduino-cli/legacy/builder/constants"
+	"github.com/arduino/arduino-cli/legacy/builder/types"
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompilationPlanObjectFilesOrder(t *testing.T) {
+	sourcePath, err := paths.MkTempDir("", "compilation_plan_test")
+	require.NoError(t, err)
+	defer sourcePath.RemoveAll()
+	buildPath := sourcePath.Join("build")
+
+	for _, f := range []string{"b.cpp", "a.cpp", "z.c", "startup.S", "sub/c.cpp", "sub/a.c"} {
+		file := sourcePath.Join(f)
+		require.NoError(t, file.Parent().MkdirAll())
+		require.NoError(t, file.WriteFile([]byte{}))
+	}
+
+	buildProperties := properties.NewMap()
+	buildProperties.Set(constants.RECIPE_S_PATTERN, "as {source_file} -o {object_file}")
+	buildProperties.Set(constants.RECIPE_C_PATTERN, "cc {source_file} -o {object_file}")
+	buildProperties.Set(constants.RECIPE_CPP_PATTERN, "c++ {source_file} -o {object_file}")
+
+	// Only the compilation database is generated, so no compiler is needed
+	ctx := &types.Context{
+		Jobs:                          3,
+		OnlyUpdateCompilationDatabase: true,
+		CompilationDatabase:           builder.NewCompilationDatabase(buildPath.Join("compile_commands.json")),
+	}
+
+	plan := NewCompilationPlan()
+	rootUnit, err := plan.AddFiles(sourcePath, false, buildPath, buildProperties, nil)
+	require.NoError(t, err)
+	subUnit, err := plan.AddFilesRecursive(sourcePath.Join("sub"), buildPath.Join("sub"), buildProperties, nil)
+	require.NoError(t, err)
+	require.NoError(t, plan.Run(ctx))
+
+	rootObjectFiles := rootUnit.ObjectFiles()
+	subObjectFiles := subUnit.ObjectFiles()
+	require.Equal(t, []string{
+		buildPath.Join("startup.S.o").String(),
+		buildPath.Join("z.c.o").String(),
+		buildPath.Join("a.cpp.o").String(),
+		buildPath.Join("b.cpp.o").String(),
+	}, rootObjectFiles.AsStrings())
+	require.Equal(t, []string{
+		buildPath.Join("sub", "a.c.o").String(),
+		buildPath.Join("sub", "c.cpp.o").String(),
+	}, subObjectFiles.AsStrings())
+	require.Len(t, ctx.CompilationDatabase.Contents, 6)
+}
+
+func TestCompilationPlanStopsOnError(t *testing.T) {
+	sourcePath, err := paths.MkTempDir("", "compilation_plan_test")
+	require.NoError(t, err)
+	defer sourcePath.RemoveAll()
+	require.NoError(t, sourcePath.Join("main.cpp").WriteFile([]byte{}))
+
+	// The cpp recipe is missing
+	ctx := &types.Context{OnlyUpdateCompilationDatabase: true}
+	plan := NewCompilationPlan()
+	_, err = plan.AddFiles(sourcePath, false, sourcePath.Join("build"), properties.NewMap(), nil)
+	require.NoError(t, err)
+	require.Error(t, plan.Run(ctx))
+}
+
+func TestWithWarningsLevel(t *testing.T) {
+	buildProperties := properties.NewMap()
+	buildProperties.Set(constants.BUILD_PROPERTIES_COMPILER_WARNING_FLAGS, "-w")
+	buildProperties.Set(constants.BUILD_PROPERTIES_COMPILER_WARNING_FLAGS+".none", "-w")
+	buildProperties.Set(constants.BUILD_PROPERTIES_COMPILER_WARNING_FLAGS+".all", "-Wall -Wextra")
+
+	all := WithWarningsLevel(buildProperties, "all")
+	none := WithWarningsLevel(buildProperties, "none")
+	require.Equal(t, "-Wall -Wextra", all.Get(constants.BUILD_PROPERTIES_COMPILER_WARNING_FLAGS))
+	require.Equal(t, "-w", none.Get(constants.BUILD_PROPERTIES_COMPILER_WARNING_FLAGS))
+	// The original properties are not changed
+	require.Equal(t, "-w", buildProperties.Get(constants.BUILD_PROPERTIES_COMPILER_WARNING_FLAGS))
+}
@@ -0,0 +1,128 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder_utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/arduino/arduino-cli/legacy/builder/types"
+	"github.com/arduino/arduino-cli/legacy/builder/utils"
+	"github.com/arduino/go-paths-helper"
+	"github.com/pkg/errors"
+)
+
+type cachedFileHash struct {
+	modTime time.Time
+	size    int64
+	hash    string
+}
+
+// FileHash returns the sha256 of the content of the given file. The hashes are
+// cached in ctx.FileHashes until the file size or modification time changes.
+func FileHash(ctx *types.Context, file *paths.Path) (string, error) {
+	stat, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+	key := file.String()
+	if c, ok := ctx.FileHashes.Load(key); ok {
+		cached := c.(*cachedFileHash)
+		if cached.modTime.Equal(stat.ModTime()) && cached.size == stat.Size() {
+			return cached.hash, nil
+		}
+	}
+	data, err := file.ReadFile()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	ctx.FileHashes.Store(key, &cachedFileHash{modTime: stat.ModTime(), size: stat.Size(), hash: hash})
+	return hash, nil
+}
+
+// recipeHash returns the sha256 of the command line used to build an object file
+func recipeHash(recipe []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(recipe, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// hashFilePath returns the path of the file storing the hashes of the
+// recipe and of the sources used to build objectFile
+func hashFilePath(objectFile *paths.Path) *paths.Path {
+	return objectFile.Parent().Join(objectFile.Base() + ".hash")
+}
+
+// readDepFile returns the rows of a dependency file: the first row is the
+// target object file followed by a colon, the others are the source file and
+// all the headers it includes.
+func readDepFile(dependencyFile *paths.Path) ([]string, error) {
+	rows, err := dependencyFile.ReadFileAsLines()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	rows = utils.Map(rows, removeEndingBackSlash)
+	rows = utils.Map(rows, strings.TrimSpace)
+	rows = utils.Map(rows, unescapeDep)
+	rows = utils.Filter(rows, nonEmptyString)
+	return rows, nil
+}
+
+// WriteObjFileHashes records the hash of the recipe used to build the object
+// file and the content hashes of its source file and of all the headers
+// listed in its dependency file. The hashes are used by ObjFileIsUpToDate to
+// detect if the object file must be rebuilt.
+func WriteObjFileHashes(ctx *types.Context, objectFile, sourceFile, dependencyFile *paths.Path, recipe []string) error {
+	files := []string{sourceFile.Clean().String()}
+	if rows, err := readDepFile(dependencyFile); err == nil && len(rows) > 1 {
+		files = rows[1:]
+	}
+
+	lines := []string{"recipe " + recipeHash(recipe)}
+	for _, file := range files {
+		hash, err := FileHash(ctx, paths.New(file))
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		lines = append(lines, hash+" "+file)
+	}
+	return hashFilePath(objectFile).WriteFile([]byte(strings.Join(lines, "\n") + "\n"))
+}
+
+// loadObjFileHashes reads the hashes stored by WriteObjFileHashes
+func loadObjFileHashes(objectFile *paths.Path) (string, map[string]string, error) {
+	rows, err := hashFilePath(objectFile).ReadFileAsLines()
+	if err != nil {
+		return "", nil, err
+	}
+	recipe := ""
+	files := map[string]string{}
+	for _, row := range rows {
+		split := strings.SplitN(row, " ", 2)
+		if len(split) != 2 {
+			continue
+		}
+		if split[0] == "recipe" {
+			recipe = split[1]
+		} else {
+			files[split[1]] = split[0]
+		}
+	}
+	return recipe, files, nil
+}
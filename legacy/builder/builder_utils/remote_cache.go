@@ -0,0 +1,139 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder_utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino/builder"
+	"github.com/arduino/arduino-cli/legacy/builder/types"
+	"github.com/arduino/go-paths-helper"
+	"github.com/pkg/errors"
+)
+
+// remoteObjFileManifest lists the files used to build an object file, with
+// their content hashes, and the hash of the object file. The manifest is
+// stored in the remote cache under the hash of the recipe and of the source
+// file, the object file as an object under the hash of its content. The paths
+// are normalized, so the entries can be used on every machine.
+type remoteObjFileManifest struct {
+	Dependencies []remoteObjFileDependency `json:"dependencies"`
+	Object       string                    `json:"object"`
+}
+
+type remoteObjFileDependency struct {
+	Path string `json:"path"`
+	Hash string `json:"hash"`
+}
+
+func remoteObjFileManifestKey(ctx *types.Context, source *paths.Path, recipe []string) (string, error) {
+	cache := ctx.RemoteBuildCache
+	sourceHash, err := FileHash(ctx, source)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	fmt.Fprintln(hash, cache.Normalize(strings.Join(recipe, "\x00")))
+	fmt.Fprintln(hash, cache.Normalize(source.String()), sourceHash)
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// fetchObjFileFromRemoteCache looks for the object file in the remote cache
+// and, if all the files it depends on have the same content on this machine,
+// saves it along with its dependency file. It returns true if the object file
+// has been fetched.
+func fetchObjFileFromRemoteCache(ctx *types.Context, source, objectFile, depsFile *paths.Path, recipe []string) (bool, error) {
+	cache := ctx.RemoteBuildCache
+	manifestKey, err := remoteObjFileManifestKey(ctx, source, recipe)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	data, err := cache.Get(builder.RemoteCacheManifest, manifestKey)
+	if err != nil || data == nil {
+		return false, err
+	}
+	manifest := &remoteObjFileManifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return false, fmt.Errorf("invalid manifest %s: %w", manifestKey, err)
+	}
+	deps := []string{}
+	for _, dep := range manifest.Dependencies {
+		file := cache.Localize(dep.Path)
+		if hash, err := FileHash(ctx, paths.New(file)); err != nil || hash != dep.Hash {
+			return false, nil
+		}
+		deps = append(deps, file)
+	}
+	object, err := cache.GetObject(manifest.Object)
+	if err != nil || object == nil {
+		return false, err
+	}
+
+	if err := objectFile.WriteFile(object); err != nil {
+		return false, errors.WithStack(err)
+	}
+	depRows := []string{objectFile.String() + ":"}
+	for _, dep := range deps {
+		depRows = append(depRows, strings.ReplaceAll(dep, " ", "\\ "))
+	}
+	if err := depsFile.WriteFile([]byte(strings.Join(depRows, " \\\n ") + "\n")); err != nil {
+		return false, errors.WithStack(err)
+	}
+	if err := WriteObjFileHashes(ctx, objectFile, source, depsFile, recipe); err != nil {
+		return false, errors.WithStack(err)
+	}
+	return true, nil
+}
+
+// storeObjFileInRemoteCache stores the object file built from source, and its
+// manifest, in the remote cache
+func storeObjFileInRemoteCache(ctx *types.Context, source, objectFile, depsFile *paths.Path, recipe []string) error {
+	cache := ctx.RemoteBuildCache
+	manifestKey, err := remoteObjFileManifestKey(ctx, source, recipe)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	files := []string{source.Clean().String()}
+	if rows, err := readDepFile(depsFile); err == nil && len(rows) > 1 {
+		files = rows[1:]
+	}
+	manifest := &remoteObjFileManifest{}
+	for _, file := range files {
+		hash, err := FileHash(ctx, paths.New(file))
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		manifest.Dependencies = append(manifest.Dependencies, remoteObjFileDependency{Path: cache.Normalize(file), Hash: hash})
+	}
+	object, err := objectFile.ReadFile()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	// The object file is stored first, so the manifest never points to a
+	// missing object file
+	if manifest.Object, err = cache.PutObject(object); err != nil {
+		return err
+	}
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return cache.Put(builder.RemoteCacheManifest, manifestKey, data)
+}
@@ -0,0 +1,110 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder_utils
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/builder"
+	"github.com/arduino/arduino-cli/legacy/builder/types"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteCacheObjFile(t *testing.T) {
+	var lock sync.Mutex
+	entries := map[string][]byte{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		if r.Method == http.MethodPut {
+			entries[r.URL.Path], _ = ioutil.ReadAll(r.Body)
+			return
+		}
+		if data, ok := entries[r.URL.Path]; ok {
+			w.Write(data)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	tmp, err := paths.MkTempDir("", "remote_cache_test")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+
+	// Two machines with the same sources in different folders
+	newMachine := func(name string) (*types.Context, *paths.Path) {
+		build := tmp.Join(name, "build")
+		require.NoError(t, build.Join("sketch").MkdirAll())
+		require.NoError(t, build.Join("sketch", "sketch.ino.cpp").WriteFile([]byte("#include \"config.h\"\nint x = X;\n")))
+		require.NoError(t, build.Join("sketch", "config.h").WriteFile([]byte("#define X 1\n")))
+		cache, err := builder.NewRemoteCache(server.URL, false, http.DefaultClient)
+		require.NoError(t, err)
+		cache.AddRoot("{build}", build)
+		return &types.Context{RemoteBuildCache: cache}, build
+	}
+	recipe := func(build *paths.Path) []string {
+		return []string{"g++", "-c", build.Join("sketch", "sketch.ino.cpp").String(), "-o", build.Join("sketch", "sketch.ino.cpp.o").String()}
+	}
+
+	ctxA, buildA := newMachine("a")
+	sourceA := buildA.Join("sketch", "sketch.ino.cpp")
+	objectA := buildA.Join("sketch", "sketch.ino.cpp.o")
+	depsA := buildA.Join("sketch", "sketch.ino.cpp.d")
+	require.NoError(t, objectA.WriteFile([]byte("object")))
+	require.NoError(t, depsA.WriteFile([]byte(objectA.String()+": \\\n "+sourceA.String()+" \\\n "+buildA.Join("sketch", "config.h").String()+"\n")))
+	require.NoError(t, storeObjFileInRemoteCache(ctxA, sourceA, objectA, depsA, recipe(buildA)))
+
+	ctxB, buildB := newMachine("b")
+	sourceB := buildB.Join("sketch", "sketch.ino.cpp")
+	objectB := buildB.Join("sketch", "sketch.ino.cpp.o")
+	depsB := buildB.Join("sketch", "sketch.ino.cpp.d")
+	fetched, err := fetchObjFileFromRemoteCache(ctxB, sourceB, objectB, depsB, recipe(buildB))
+	require.NoError(t, err)
+	require.True(t, fetched)
+	data, err := objectB.ReadFile()
+	require.NoError(t, err)
+	require.Equal(t, "object", string(data))
+	upToDate, err := ObjFileIsUpToDate(ctxB, sourceB, objectB, depsB, recipe(buildB))
+	require.NoError(t, err)
+	require.True(t, upToDate)
+
+	// A header with a different content makes the object file unusable
+	require.NoError(t, objectB.Remove())
+	require.NoError(t, buildB.Join("sketch", "config.h").WriteFile([]byte("#define X 2\n")))
+	fetched, err = fetchObjFileFromRemoteCache(ctxB, sourceB, objectB, depsB, recipe(buildB))
+	require.NoError(t, err)
+	require.False(t, fetched)
+	require.False(t, objectB.Exist())
+
+	// An object file different from the one in the manifest is not used
+	require.NoError(t, buildB.Join("sketch", "config.h").WriteFile([]byte("#define X 1\n")))
+	for path := range entries {
+		if strings.HasPrefix(path, "/obj/") {
+			entries[path] = []byte("tampered")
+		}
+	}
+	fetched, err = fetchObjFileFromRemoteCache(ctxB, sourceB, objectB, depsB, recipe(buildB))
+	require.Error(t, err)
+	require.False(t, fetched)
+	require.False(t, objectB.Exist())
+}
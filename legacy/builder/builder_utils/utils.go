@@ -16,13 +16,12 @@
 package builder_utils
 
 import (
+	"bytes"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"runtime"
 	"strconv"
 	"strings"
-	"sync"
 
 	"github.com/arduino/arduino-cli/legacy/builder/constants"
 	"github.com/arduino/arduino-cli/legacy/builder/types"
@@ -40,67 +39,42 @@ func PrintProgressIfProgressEnabledAndMachineLogger(ctx *types.Context) {
 
 	log := ctx.GetLogger()
 	if log.Name() == "machine" {
-		log.Println(constants.LOG_LEVEL_INFO, constants.MSG_PROGRESS, strconv.FormatFloat(float64(ctx.Progress.Progress), 'f', 2, 32))
+		log.Println(constants.LOG_LEVEL_INFO, constants.MSG_PROGRESS, strconv.FormatFloat(float64(ctx.Progress.Current()), 'f', 2, 32))
 	}
 }
 
+// WithWarningsLevel returns a copy of the build properties where
+// compiler.warning_flags are the flags of the given warnings level. The
+// sketch, the libraries and the core are compiled with different copies, so
+// each one can use its own warnings level.
+func WithWarningsLevel(buildProperties *properties.Map, level string) *properties.Map {
+	props := buildProperties.Clone()
+	props.Set(constants.BUILD_PROPERTIES_COMPILER_WARNING_FLAGS, props.Get(constants.BUILD_PROPERTIES_COMPILER_WARNING_FLAGS+"."+level))
+	return props
+}
+
 func CompileFilesRecursive(ctx *types.Context, sourcePath *paths.Path, buildPath *paths.Path, buildProperties *properties.Map, includes []string) (paths.PathList, error) {
-	objectFiles, err := CompileFiles(ctx, sourcePath, false, buildPath, buildProperties, includes)
+	plan := NewCompilationPlan()
+	unit, err := plan.AddFilesRecursive(sourcePath, buildPath, WithWarningsLevel(buildProperties, ctx.WarningsLevel), includes)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
-
-	folders, err := utils.ReadDirFiltered(sourcePath.String(), utils.FilterDirs)
-	if err != nil {
+	if err := plan.Run(ctx); err != nil {
 		return nil, errors.WithStack(err)
 	}
-
-	for _, folder := range folders {
-		subFolderObjectFiles, err := CompileFilesRecursive(ctx, sourcePath.Join(folder.Name()), buildPath.Join(folder.Name()), buildProperties, includes)
-		if err != nil {
-			return nil, errors.WithStack(err)
-		}
-		objectFiles.AddAll(subFolderObjectFiles)
-	}
-
-	return objectFiles, nil
+	return unit.ObjectFiles(), nil
 }
 
 func CompileFiles(ctx *types.Context, sourcePath *paths.Path, recurse bool, buildPath *paths.Path, buildProperties *properties.Map, includes []string) (paths.PathList, error) {
-	sSources, err := findFilesInFolder(sourcePath, ".S", recurse)
-	if err != nil {
-		return nil, errors.WithStack(err)
-	}
-	cSources, err := findFilesInFolder(sourcePath, ".c", recurse)
-	if err != nil {
-		return nil, errors.WithStack(err)
-	}
-	cppSources, err := findFilesInFolder(sourcePath, ".cpp", recurse)
-	if err != nil {
-		return nil, errors.WithStack(err)
-	}
-
-	ctx.Progress.AddSubSteps(len(sSources) + len(cSources) + len(cppSources))
-	defer ctx.Progress.RemoveSubSteps()
-
-	sObjectFiles, err := compileFilesWithRecipe(ctx, sourcePath, sSources, buildPath, buildProperties, includes, constants.RECIPE_S_PATTERN)
+	plan := NewCompilationPlan()
+	unit, err := plan.AddFiles(sourcePath, recurse, buildPath, WithWarningsLevel(buildProperties, ctx.WarningsLevel), includes)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
-	cObjectFiles, err := compileFilesWithRecipe(ctx, sourcePath, cSources, buildPath, buildProperties, includes, constants.RECIPE_C_PATTERN)
-	if err != nil {
+	if err := plan.Run(ctx); err != nil {
 		return nil, errors.WithStack(err)
 	}
-	cppObjectFiles, err := compileFilesWithRecipe(ctx, sourcePath, cppSources, buildPath, buildProperties, includes, constants.RECIPE_CPP_PATTERN)
-	if err != nil {
-		return nil, errors.WithStack(err)
-	}
-
-	objectFiles := paths.NewPathList()
-	objectFiles.AddAll(sObjectFiles)
-	objectFiles.AddAll(cObjectFiles)
-	objectFiles.AddAll(cppObjectFiles)
-	return objectFiles, nil
+	return unit.ObjectFiles(), nil
 }
 
 func findFilesInFolder(sourcePath *paths.Path, extension string, recurse bool) (paths.PathList, error) {
@@ -162,91 +136,38 @@ func findAllFilesInFolder(sourcePath string, recurse bool) ([]string, error) {
 	return sources, nil
 }
 
-func compileFilesWithRecipe(ctx *types.Context, sourcePath *paths.Path, sources paths.PathList, buildPath *paths.Path, buildProperties *properties.Map, includes []string, recipe string) (paths.PathList, error) {
-	objectFiles := paths.NewPathList()
-	if len(sources) == 0 {
-		return objectFiles, nil
-	}
-	var objectFilesMux sync.Mutex
-	var errorsList []error
-	var errorsMux sync.Mutex
-
-	queue := make(chan *paths.Path)
-	job := func(source *paths.Path) {
-		objectFile, err := compileFileWithRecipe(ctx, sourcePath, source, buildPath, buildProperties, includes, recipe)
-		if err != nil {
-			errorsMux.Lock()
-			errorsList = append(errorsList, err)
-			errorsMux.Unlock()
-		} else {
-			objectFilesMux.Lock()
-			objectFiles.Add(objectFile)
-			objectFilesMux.Unlock()
-		}
-	}
-
-	// Spawn jobs runners
-	var wg sync.WaitGroup
-	jobs := ctx.Jobs
-	if jobs == 0 {
-		jobs = runtime.NumCPU()
-	}
-	for i := 0; i < jobs; i++ {
-		wg.Add(1)
-		go func() {
-			for source := range queue {
-				job(source)
-			}
-			wg.Done()
-		}()
-	}
-
-	// Feed jobs until error or done
-	for _, source := range sources {
-		errorsMux.Lock()
-		gotError := len(errorsList) > 0
-		errorsMux.Unlock()
-		if gotError {
-			break
-		}
-		queue <- source
-
-		ctx.Progress.CompleteStep()
-		PrintProgressIfProgressEnabledAndMachineLogger(ctx)
-	}
-	close(queue)
-	wg.Wait()
-	if len(errorsList) > 0 {
-		// output the first error
-		return nil, errors.WithStack(errorsList[0])
-	}
-	objectFiles.Sort()
-	return objectFiles, nil
-}
-
-func compileFileWithRecipe(ctx *types.Context, sourcePath *paths.Path, source *paths.Path, buildPath *paths.Path, buildProperties *properties.Map, includes []string, recipe string) (*paths.Path, error) {
-	logger := ctx.GetLogger()
+// compileFileWithRecipe compiles source into an object file inside buildPath. If
+// databaseOnly is true the compile command is only added to the compilation
+// database.
+// compileProperties returns the build properties used to compile source, the
+// object file is placed in buildPath keeping the path of source relative to
+// sourcePath
+func compileProperties(sourcePath *paths.Path, source *paths.Path, buildPath *paths.Path, buildProperties *properties.Map, includes []string) (*properties.Map, error) {
 	properties := buildProperties.Clone()
-	properties.Set(constants.BUILD_PROPERTIES_COMPILER_WARNING_FLAGS, properties.Get(constants.BUILD_PROPERTIES_COMPILER_WARNING_FLAGS+"."+ctx.WarningsLevel))
 	properties.Set(constants.BUILD_PROPERTIES_INCLUDES, strings.Join(includes, constants.SPACE))
 	properties.SetPath(constants.BUILD_PROPERTIES_SOURCE_FILE, source)
 	relativeSource, err := sourcePath.RelTo(source)
 	if err != nil {
-		return nil, errors.WithStack(err)
+		return nil, err
 	}
-	depsFile := buildPath.Join(relativeSource.String() + ".d")
-	objectFile := buildPath.Join(relativeSource.String() + ".o")
+	properties.SetPath(constants.BUILD_PROPERTIES_OBJECT_FILE, buildPath.Join(relativeSource.String()+".o"))
+	return properties, nil
+}
 
-	properties.SetPath(constants.BUILD_PROPERTIES_OBJECT_FILE, objectFile)
-	err = objectFile.Parent().MkdirAll()
+func compileFileWithRecipe(ctx *types.Context, sourcePath *paths.Path, source *paths.Path, buildPath *paths.Path, buildProperties *properties.Map, includes []string, recipe string, databaseOnly bool) (*paths.Path, error) {
+	logger := ctx.GetLogger()
+	properties, err := compileProperties(sourcePath, source, buildPath, buildProperties, includes)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
+	objectFile := properties.GetPath(constants.BUILD_PROPERTIES_OBJECT_FILE)
+	depsFile := paths.New(strings.TrimSuffix(objectFile.String(), ".o") + ".d")
 
-	objIsUpToDate, err := ObjFileIsUpToDate(ctx, source, objectFile, depsFile)
+	err = objectFile.Parent().MkdirAll()
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
+
 	command, err := PrepareCommandForRecipe(properties, recipe, false)
 	if err != nil {
 		return nil, errors.WithStack(err)
@@ -254,11 +175,50 @@ func compileFileWithRecipe(ctx *types.Context, sourcePath *paths.Path, source *p
 	if ctx.CompilationDatabase != nil {
 		ctx.CompilationDatabase.Add(source, command)
 	}
+	if databaseOnly {
+		return objectFile, nil
+	}
+	if ctx.BuildScript != nil {
+		ctx.BuildScript.Add(command, paths.NewPathList(source.String()), paths.NewPathList(objectFile.String()))
+	}
+	objIsUpToDate, err := ObjFileIsUpToDate(ctx, source, objectFile, depsFile, command.Args)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	remoteCache := ctx.RemoteBuildCache != nil && !ctx.OnlyUpdateCompilationDatabase
+	if !objIsUpToDate && remoteCache {
+		fetched, err := fetchObjFileFromRemoteCache(ctx, source, objectFile, depsFile, command.Args)
+		if err != nil {
+			logger.Println(constants.LOG_LEVEL_WARN, constants.MSG_REMOTE_CACHE_ERROR, err)
+		} else if fetched {
+			if ctx.Verbose {
+				logger.Println(constants.LOG_LEVEL_INFO, constants.MSG_USING_REMOTE_CACHED_FILE, objectFile)
+			}
+			ctx.Stats.AddReusedObjectFile()
+			return objectFile, nil
+		}
+	}
+	if objIsUpToDate && !ctx.OnlyUpdateCompilationDatabase {
+		ctx.Stats.AddReusedObjectFile()
+	}
 	if !objIsUpToDate && !ctx.OnlyUpdateCompilationDatabase {
+		endEvent := ctx.Stats.StartEvent(types.BuildEventCompile, source.String())
 		_, _, err = utils.ExecCommand(ctx, command, utils.ShowIfVerbose /* stdout */, utils.Show /* stderr */)
+		endEvent()
 		if err != nil {
 			return nil, errors.WithStack(err)
 		}
+		if err := WriteObjFileHashes(ctx, objectFile, source, depsFile, command.Args); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if ctx.Stats != nil {
+			ctx.Stats.AddCompiledUnit(countLines(source), command.ProcessState.UserTime()+command.ProcessState.SystemTime())
+		}
+		if remoteCache {
+			if err := storeObjFileInRemoteCache(ctx, source, objectFile, depsFile, command.Args); err != nil {
+				logger.Println(constants.LOG_LEVEL_WARN, constants.MSG_REMOTE_CACHE_ERROR, err)
+			}
+		}
 	} else if ctx.Verbose {
 		if objIsUpToDate {
 			logger.Println(constants.LOG_LEVEL_INFO, constants.MSG_USING_PREVIOUS_COMPILED_FILE, objectFile)
@@ -270,7 +230,27 @@ func compileFileWithRecipe(ctx *types.Context, sourcePath *paths.Path, source *p
 	return objectFile, nil
 }
 
-func ObjFileIsUpToDate(ctx *types.Context, sourceFile, objectFile, dependencyFile *paths.Path) (bool, error) {
+// countLines returns the number of lines of the given file, or 0 if the file
+// can't be read
+func countLines(file *paths.Path) int64 {
+	data, err := file.ReadFile()
+	if err != nil {
+		return 0
+	}
+	lines := int64(bytes.Count(data, []byte("\n")))
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		lines++
+	}
+	return lines
+}
+
+// ObjFileIsUpToDate returns true if the object file doesn't need to be rebuilt.
+// The content of the source file and of all the headers listed in the
+// dependency file are compared against the hashes recorded by
+// WriteObjFileHashes when the object file was built, so files touched without
+// changes don't trigger a rebuild. If recipe is not nil, the object file is
+// also rebuilt if the command line used to build it has changed.
+func ObjFileIsUpToDate(ctx *types.Context, sourceFile, objectFile, dependencyFile *paths.Path, recipe []string) (bool, error) {
 	logger := ctx.GetLogger()
 	debugLevel := ctx.DebugLevel
 	if debugLevel >= 20 {
@@ -284,62 +264,42 @@ func ObjFileIsUpToDate(ctx *types.Context, sourceFile, objectFile, dependencyFil
 	}
 
 	sourceFile = sourceFile.Clean()
-	sourceFileStat, err := sourceFile.Stat()
-	if err != nil {
+	if _, err := sourceFile.Stat(); err != nil {
 		return false, errors.WithStack(err)
 	}
 
-	objectFile = objectFile.Clean()
-	objectFileStat, err := objectFile.Stat()
-	if err != nil {
-		if os.IsNotExist(err) {
-			if debugLevel >= 20 {
-				logger.Fprintln(os.Stdout, constants.LOG_LEVEL_DEBUG, "Not found: {0}", objectFile)
+	for _, file := range []*paths.Path{objectFile.Clean(), dependencyFile.Clean(), hashFilePath(objectFile.Clean())} {
+		if _, err := file.Stat(); err != nil {
+			if os.IsNotExist(err) {
+				if debugLevel >= 20 {
+					logger.Fprintln(os.Stdout, constants.LOG_LEVEL_DEBUG, "Not found: {0}", file)
+				}
+				return false, nil
 			}
-			return false, nil
-		} else {
 			return false, errors.WithStack(err)
 		}
 	}
-
+	objectFile = objectFile.Clean()
 	dependencyFile = dependencyFile.Clean()
-	dependencyFileStat, err := dependencyFile.Stat()
-	if err != nil {
-		if os.IsNotExist(err) {
-			if debugLevel >= 20 {
-				logger.Fprintln(os.Stdout, constants.LOG_LEVEL_DEBUG, "Not found: {0}", dependencyFile)
-			}
-			return false, nil
-		} else {
-			return false, errors.WithStack(err)
-		}
-	}
 
-	if sourceFileStat.ModTime().After(objectFileStat.ModTime()) {
-		if debugLevel >= 20 {
-			logger.Fprintln(os.Stdout, constants.LOG_LEVEL_DEBUG, "{0} newer than {1}", sourceFile, objectFile)
-		}
-		return false, nil
+	recipeHashInFile, hashes, err := loadObjFileHashes(objectFile)
+	if err != nil {
+		return false, errors.WithStack(err)
 	}
-	if sourceFileStat.ModTime().After(dependencyFileStat.ModTime()) {
+	if recipe != nil && recipeHash(recipe) != recipeHashInFile {
 		if debugLevel >= 20 {
-			logger.Fprintln(os.Stdout, constants.LOG_LEVEL_DEBUG, "{0} newer than {1}", sourceFile, dependencyFile)
+			logger.Fprintln(os.Stdout, constants.LOG_LEVEL_DEBUG, "Recipe changed for {0}", objectFile)
 		}
 		return false, nil
 	}
 
-	rows, err := dependencyFile.ReadFileAsLines()
+	rows, err := readDepFile(dependencyFile)
 	if err != nil {
 		return false, errors.WithStack(err)
 	}
 
-	rows = utils.Map(rows, removeEndingBackSlash)
-	rows = utils.Map(rows, strings.TrimSpace)
-	rows = utils.Map(rows, unescapeDep)
-	rows = utils.Filter(rows, nonEmptyString)
-
 	if len(rows) == 0 {
-		return true, nil
+		rows = []string{objectFile.String() + ":", sourceFile.String()}
 	}
 
 	firstRow := rows[0]
@@ -363,31 +323,23 @@ func ObjFileIsUpToDate(ctx *types.Context, sourceFile, objectFile, dependencyFil
 
 	// If we don't do this check it might happen that trying to compile a source file
 	// that has the same name but a different path wouldn't recreate the object file.
-	if sourceFile.String() != strings.Trim(rows[1], " ") {
+	if len(rows) < 2 || sourceFile.String() != strings.Trim(rows[1], " ") {
 		return false, nil
 	}
 
 	rows = rows[1:]
 	for _, row := range rows {
-		depStat, err := os.Stat(row)
-		if err != nil && !os.IsNotExist(err) {
-			// There is probably a parsing error of the dep file
-			// Ignore the error and trigger a full rebuild anyway
+		hash, err := FileHash(ctx, paths.New(row))
+		if err != nil {
 			if debugLevel >= 20 {
 				logger.Fprintln(os.Stdout, constants.LOG_LEVEL_DEBUG, "Failed to read: {0}", row)
 				logger.Fprintln(os.Stdout, constants.LOG_LEVEL_DEBUG, err.Error())
 			}
 			return false, nil
 		}
-		if os.IsNotExist(err) {
+		if hashes[row] != hash {
 			if debugLevel >= 20 {
-				logger.Fprintln(os.Stdout, constants.LOG_LEVEL_DEBUG, "Not found: {0}", row)
-			}
-			return false, nil
-		}
-		if depStat.ModTime().After(objectFileStat.ModTime()) {
-			if debugLevel >= 20 {
-				logger.Fprintln(os.Stdout, constants.LOG_LEVEL_DEBUG, "{0} newer than {1}", row, objectFile)
+				logger.Fprintln(os.Stdout, constants.LOG_LEVEL_DEBUG, "{0} changed since the build of {1}", row, objectFile)
 			}
 			return false, nil
 		}
@@ -416,28 +368,6 @@ func nonEmptyString(s string) bool {
 	return s != constants.EMPTY_STRING
 }
 
-func CoreOrReferencedCoreHasChanged(corePath, targetCorePath, targetFile *paths.Path) bool {
-
-	targetFileStat, err := targetFile.Stat()
-	if err == nil {
-		files, err := findAllFilesInFolder(corePath.String(), true)
-		if err != nil {
-			return true
-		}
-		for _, file := range files {
-			fileStat, err := os.Stat(file)
-			if err != nil || fileStat.ModTime().After(targetFileStat.ModTime()) {
-				return true
-			}
-		}
-		if targetCorePath != nil && !strings.EqualFold(corePath.String(), targetCorePath.String()) {
-			return CoreOrReferencedCoreHasChanged(targetCorePath, nil, targetFile)
-		}
-		return false
-	}
-	return true
-}
-
 func TXTBuildRulesHaveChanged(corePath, targetCorePath, targetFile *paths.Path) bool {
 
 	targetFileStat, err := targetFile.Stat()
@@ -499,6 +429,7 @@ func ArchiveCompiledFiles(ctx *types.Context, buildPath *paths.Path, archiveFile
 		}
 	}
 
+	defer ctx.Stats.StartEvent(types.BuildEventArchive, archiveFilePath.String())()
 	for _, objectFile := range objectFilesToArchive {
 		properties := buildProperties.Clone()
 		properties.Set(constants.BUILD_PROPERTIES_ARCHIVE_FILE, archiveFilePath.Base())
@@ -509,6 +440,9 @@ func ArchiveCompiledFiles(ctx *types.Context, buildPath *paths.Path, archiveFile
 		if err != nil {
 			return nil, errors.WithStack(err)
 		}
+		if ctx.BuildScript != nil {
+			ctx.BuildScript.Add(command, paths.NewPathList(objectFile.String()), paths.NewPathList(archiveFilePath.String()))
+		}
 
 		_, _, err = utils.ExecCommand(ctx, command, utils.ShowIfVerbose /* stdout */, utils.Show /* stderr */)
 		if err != nil {
@@ -42,6 +42,7 @@ const CTAGS = "ctags"
 const EMPTY_STRING = ""
 const FILE_CTAGS_TARGET_FOR_GCC_MINUS_E = "ctags_target_for_gcc_minus_e.cpp"
 const FILE_PLATFORM_KEYS_REWRITE_TXT = "platform.keys.rewrite.txt"
+const FILE_PROTOTYPES_CACHE = "prototypes.cache"
 const FOLDER_BOOTLOADERS = "bootloaders"
 const FOLDER_CORE = "core"
 const FOLDER_PREPROC = "preproc"
@@ -105,9 +106,17 @@ const MSG_MISSING_CORE_FOR_BOARD = "Selected board depends on '{0}' core (not in
 const MSG_PACKAGE_UNKNOWN = "{0}: Unknown package"
 const MSG_PLATFORM_UNKNOWN = "Platform {0} (package {1}) is unknown"
 const MSG_PROGRESS = "Progress {0}"
+const MSG_PROPERTY_TRACE = "Property {0}:"
+const MSG_PROPERTY_TRACE_CHANGE = "  set by {0}: {1}"
+const MSG_PROPERTY_TRACE_EXPANDED = "  expanded value: {0}"
+const MSG_PROPERTY_TRACE_REFERENCE = "  {0} = {1}"
+const MSG_PROPERTY_TRACE_REFERENCE_UNDEFINED = "  {0} is not defined"
+const MSG_PROPERTY_TRACE_UNDEFINED = "  not defined"
 const MSG_PROP_IN_LIBRARY = "Missing '{0}' from library in {1}"
+const MSG_REMOTE_CACHE_ERROR = "Remote build cache: {0}"
 const MSG_RUNNING_COMMAND = "Ts: {0} - Running: {1}"
 const MSG_RUNNING_RECIPE = "Running recipe: {0}"
+const MSG_RUNNING_USER_HOOK = "Running {0} hook: {1}"
 const MSG_SETTING_BUILD_PATH = "Setting build path to {0}"
 const MSG_SIZER_TEXT_FULL = "Sketch uses {0} bytes ({2}%%) of program storage space. Maximum is {1} bytes."
 const MSG_SIZER_DATA_FULL = "Global variables use {0} bytes ({2}%%) of dynamic memory, leaving {3} bytes for local variables. Maximum is {1} bytes."
@@ -123,7 +132,9 @@ const MSG_USING_LIBRARY = "Using library {0} in folder: {1} {2}"
 const MSG_USING_BOARD = "Using board '{0}' from platform in folder: {1}"
 const MSG_USING_CORE = "Using core '{0}' from platform in folder: {1}"
 const MSG_USING_PREVIOUS_COMPILED_FILE = "Using previously compiled file: {0}"
+const MSG_USING_REMOTE_CACHED_FILE = "Using file from the remote build cache: {0}"
 const MSG_USING_CACHED_INCLUDES = "Using cached library dependencies for file: {0}"
+const MSG_USING_CACHED_PROTOTYPES = "Using cached function prototypes: {0}"
 const MSG_WARNING_LIB_INVALID_CATEGORY = "WARNING: Category '{0}' in library {1} is not valid. Setting to '{2}'"
 const MSG_WARNING_PLATFORM_OLD_VALUES = "Warning: platform.txt from core '{0}' contains deprecated {1}, automatically converted to {2}. Consider upgrading this core."
 const MSG_WARNING_SPURIOUS_FILE_IN_LIB = "WARNING: Spurious {0} folder in '{1}' library"
@@ -18,6 +18,7 @@ package builder
 import (
 	bldr "github.com/arduino/arduino-cli/arduino/builder"
 	"github.com/arduino/arduino-cli/legacy/builder/constants"
+	"github.com/arduino/arduino-cli/legacy/builder/ctags"
 	"github.com/arduino/arduino-cli/legacy/builder/types"
 	"github.com/pkg/errors"
 )
@@ -31,18 +32,51 @@ func (s *ContainerAddPrototypes) Run(ctx *types.Context) error {
 	}
 	targetFilePath := ctx.PreprocPath.Join(constants.FILE_CTAGS_TARGET_FOR_GCC_MINUS_E)
 
-	// Run preprocessor
-	sourceFile := ctx.SketchBuildPath.Join(ctx.Sketch.MainFile.Name.Base() + ".cpp")
-	if err := GCCPreprocRunner(ctx, sourceFile, targetFilePath, ctx.IncludeFolders); err != nil {
+	// The prototypes depend only on the sketch sources and on the headers they
+	// include, if they didn't change there is no need to run the preprocessor
+	// and ctags again
+	cachePath := ctx.PreprocPath.Join(constants.FILE_PROTOTYPES_CACHE)
+	cacheKey, err := prototypesCacheKey(ctx)
+	if err != nil {
 		return errors.WithStack(err)
 	}
+	cache := readPrototypesCache(ctx, cachePath, cacheKey)
 
-	commands := []types.Command{
-		&ReadFileAndStoreInContext{FileToRead: targetFilePath, Target: &ctx.SourceGccMinusE},
-		&FilterSketchSource{Source: &ctx.SourceGccMinusE},
-		&CTagsTargetFileSaver{Source: &ctx.SourceGccMinusE, TargetFileName: constants.FILE_CTAGS_TARGET_FOR_GCC_MINUS_E},
-		&CTagsRunner{},
-		&PrototypesAdder{},
+	var commands []types.Command
+	var includes []prototypesCacheInclude
+	if cache != nil {
+		if ctx.Verbose {
+			ctx.GetLogger().Println(constants.LOG_LEVEL_INFO, constants.MSG_USING_CACHED_PROTOTYPES, cachePath)
+		}
+		ctx.Prototypes = cache.Prototypes
+		ctx.PrototypesLineWhereToInsert = cache.Line
+		commands = []types.Command{&PrototypesAdder{}}
+	} else {
+		// Run preprocessor
+		sourceFile := ctx.SketchBuildPath.Join(ctx.Sketch.MainFile.Name.Base() + ".cpp")
+		if err := GCCPreprocRunner(ctx, sourceFile, targetFilePath, ctx.IncludeFolders); err != nil {
+			return errors.WithStack(err)
+		}
+		preprocessed, err := targetFilePath.ReadFile()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if includes, err = prototypesCacheIncludes(ctx, string(preprocessed), sourceFile); err != nil {
+			return errors.WithStack(err)
+		}
+
+		var prototypesGenerator types.Command = &CTagsRunner{}
+		if ctx.Preprocessor == "treesitter" {
+			prototypesGenerator = &TreeSitterRunner{}
+		}
+
+		commands = []types.Command{
+			&ReadFileAndStoreInContext{FileToRead: targetFilePath, Target: &ctx.SourceGccMinusE},
+			&FilterSketchSource{Source: &ctx.SourceGccMinusE},
+			&CTagsTargetFileSaver{Source: &ctx.SourceGccMinusE, TargetFileName: constants.FILE_CTAGS_TARGET_FOR_GCC_MINUS_E},
+			prototypesGenerator,
+			&PrototypesAdder{},
+		}
 	}
 
 	for _, command := range commands {
@@ -53,6 +87,19 @@ func (s *ContainerAddPrototypes) Run(ctx *types.Context) error {
 		}
 	}
 
+	if cache == nil {
+		err := writePrototypesCache(cachePath, &prototypesCache{
+			Key:        cacheKey,
+			Includes:   includes,
+			Line:       ctx.PrototypesLineWhereToInsert,
+			Prototypes: ctx.Prototypes,
+		})
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	ctx.Source = ctags.CommentNoPrototypesPragmas(ctx.Source)
 	if err := bldr.SketchSaveItemCpp(ctx.Sketch.MainFile.Name.String(), []byte(ctx.Source), ctx.SketchBuildPath.String()); err != nil {
 		return errors.WithStack(err)
 	}
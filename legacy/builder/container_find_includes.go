@@ -99,6 +99,7 @@ import (
 	"os/exec"
 	"time"
 
+	bldr "github.com/arduino/arduino-cli/arduino/builder"
 	"github.com/arduino/arduino-cli/arduino/libraries"
 	"github.com/arduino/arduino-cli/legacy/builder/builder_utils"
 	"github.com/arduino/arduino-cli/legacy/builder/constants"
@@ -302,7 +303,7 @@ func findIncludesUntilDone(ctx *types.Context, cache *includeCache, sourceFile t
 	// TODO: This reads the dependency file, but the actual building
 	// does it again. Should the result be somehow cached? Perhaps
 	// remove the object file if it is found to be stale?
-	unchanged, err := builder_utils.ObjFileIsUpToDate(ctx, sourcePath, objPath, depPath)
+	unchanged, err := builder_utils.ObjFileIsUpToDate(ctx, sourcePath, objPath, depPath, nil)
 	if err != nil {
 		return errors.WithStack(err)
 	}
@@ -362,7 +363,7 @@ func findIncludesUntilDone(ctx *types.Context, cache *includeCache, sourceFile t
 			return nil
 		}
 
-		library := ResolveLibrary(ctx, include)
+		library := ResolveLibrary(ctx, sourcePath, include)
 		if library == nil {
 			// Library could not be resolved, show error
 			// err := runCommand(ctx, &GCCPreprocRunner{SourceFilePath: sourcePath, TargetFileName: paths.New(constants.FILE_CTAGS_TARGET_FOR_GCC_MINUS_E), Includes: includes})
@@ -379,7 +380,7 @@ func findIncludesUntilDone(ctx *types.Context, cache *includeCache, sourceFile t
 				}
 			}
 			os.Stderr.Write(preproc_stderr)
-			return errors.WithStack(preproc_err)
+			return errors.WithStack(&bldr.MissingIncludeError{Include: include, SourceFile: sourcePath, Err: preproc_err})
 		}
 
 		// Add this library to the list of libraries, the
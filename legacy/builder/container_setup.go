@@ -29,9 +29,9 @@ import (
 type ContainerSetupHardwareToolsLibsSketchAndProps struct{}
 
 func (s *ContainerSetupHardwareToolsLibsSketchAndProps) Run(ctx *types.Context) error {
-	// total number of steps in this container: 14
-	ctx.Progress.AddSubSteps(14)
-	defer ctx.Progress.RemoveSubSteps()
+	// total number of steps in this container: 15
+	steps := ctx.Progress.AddSteps(15)
+	defer steps.Close()
 
 	commands := []types.Command{
 		&AddAdditionalEntriesToContext{},
@@ -51,7 +51,7 @@ func (s *ContainerSetupHardwareToolsLibsSketchAndProps) Run(ctx *types.Context)
 		if err != nil {
 			return errors.WithStack(err)
 		}
-		ctx.Progress.CompleteStep()
+		steps.CompleteStep()
 		builder_utils.PrintProgressIfProgressEnabledAndMachineLogger(ctx)
 	}
 
@@ -76,7 +76,7 @@ func (s *ContainerSetupHardwareToolsLibsSketchAndProps) Run(ctx *types.Context)
 		ctx.SketchLocation = paths.New(sketch.MainFile.Path)
 		ctx.Sketch = types.SketchToLegacy(sketch)
 	}
-	ctx.Progress.CompleteStep()
+	steps.CompleteStep()
 	builder_utils.PrintProgressIfProgressEnabledAndMachineLogger(ctx)
 
 	commands = []types.Command{
@@ -84,6 +84,7 @@ func (s *ContainerSetupHardwareToolsLibsSketchAndProps) Run(ctx *types.Context)
 		&LoadVIDPIDSpecificProperties{},
 		&SetCustomBuildProperties{},
 		&AddMissingBuildPropertiesFromParentPlatformTxtFiles{},
+		&PrintPropertiesTrace{},
 	}
 
 	for _, command := range commands {
@@ -92,7 +93,7 @@ func (s *ContainerSetupHardwareToolsLibsSketchAndProps) Run(ctx *types.Context)
 		if err != nil {
 			return errors.WithStack(err)
 		}
-		ctx.Progress.CompleteStep()
+		steps.CompleteStep()
 		builder_utils.PrintProgressIfProgressEnabledAndMachineLogger(ctx)
 	}
 
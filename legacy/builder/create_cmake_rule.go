@@ -32,7 +32,7 @@ import (
 	"github.com/arduino/arduino-cli/legacy/builder/utils"
 )
 
-var VALID_EXPORT_EXTENSIONS = map[string]bool{".h": true, ".c": true, ".hpp": true, ".hh": true, ".cpp": true, ".S": true, ".a": true, ".properties": true}
+var VALID_EXPORT_EXTENSIONS = map[string]bool{".h": true, ".c": true, ".hpp": true, ".hh": true, ".cpp": true, ".S": true, ".a": true, ".properties": true, ".ld": true}
 var DOTHEXTENSION = map[string]bool{".h": true, ".hh": true, ".hpp": true}
 var DOTAEXTENSION = map[string]bool{".a": true}
 
@@ -45,13 +45,20 @@ func (s *ExportProjectCMake) Run(ctx *types.Context) error {
 	//verbose := ctx.Verbose
 	logger := ctx.GetLogger()
 
-	if s.SketchError || !canExportCmakeProject(ctx) {
+	if s.SketchError || (!canExportCmakeProject(ctx) && ctx.ExportCMakePath == nil) {
 		return nil
 	}
 
 	// Create new cmake subFolder - clean if the folder is already there
 	cmakeFolder := ctx.BuildPath.Join("_cmake")
-	if _, err := cmakeFolder.Stat(); err == nil {
+	if ctx.ExportCMakePath != nil {
+		// The folder is chosen by the user, only the exported sources are
+		// replaced
+		cmakeFolder = ctx.ExportCMakePath
+		for _, dir := range []string{"core", "lib", "sketch"} {
+			cmakeFolder.Join(dir).RemoveAll()
+		}
+	} else if _, err := cmakeFolder.Stat(); err == nil {
 		cmakeFolder.RemoveAll()
 	}
 	cmakeFolder.MkdirAll()
@@ -178,6 +185,10 @@ func (s *ExportProjectCMake) Run(ctx *types.Context) error {
 
 	projectName := strings.TrimSuffix(ctx.Sketch.MainFile.Name.Base(), ctx.Sketch.MainFile.Name.Ext())
 
+	if ctx.ExportCMakePath != nil {
+		return exportCrossCompileCMakeProject(ctx, cmakeFolder, projectName, foldersContainingDotH, staticLibs)
+	}
+
 	cmakelist := "cmake_minimum_required(VERSION 3.5.0)\n"
 	cmakelist += "INCLUDE(FindPkgConfig)\n"
 	cmakelist += "project (" + projectName + " C CXX)\n"
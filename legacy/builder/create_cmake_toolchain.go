@@ -0,0 +1,194 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/arduino/arduino-cli/legacy/builder/builder_utils"
+	"github.com/arduino/arduino-cli/legacy/builder/constants"
+	"github.com/arduino/arduino-cli/legacy/builder/types"
+	"github.com/arduino/arduino-cli/legacy/builder/utils"
+	"github.com/arduino/go-paths-helper"
+	"github.com/pkg/errors"
+)
+
+// cmakeRecipe holds the compiler and the flags of a build recipe, split the
+// way CMake needs them
+type cmakeRecipe struct {
+	compiler string
+	flags    []string
+	defines  []string
+	libs     []string
+}
+
+// exportCrossCompileCMakeProject writes a CMakeLists.txt and a toolchain
+// file that build the exported sources with the compilers and the flags of
+// the platform recipes
+func exportCrossCompileCMakeProject(ctx *types.Context, cmakeFolder *paths.Path, projectName, includeFolders string, staticLibs []string) error {
+	props := ctx.BuildProperties
+	// Paths of the build are mapped to the exported copies
+	mappings := []string{}
+	for _, m := range [][2]string{
+		{props.Get("build.variant.path"), "${CMAKE_CURRENT_LIST_DIR}/core/variant"},
+		{props.Get("build.core.path"), "${CMAKE_CURRENT_LIST_DIR}/core"},
+		{props.Get("build.path"), "${CMAKE_BINARY_DIR}"},
+	} {
+		if m[0] != "" {
+			mappings = append(mappings, m[0], m[1])
+		}
+	}
+	replacer := strings.NewReplacer(mappings...)
+	remap := func(arg string) string {
+		if strings.HasPrefix(arg, "@") {
+			// the flags included from files are exported as well
+			file := paths.New(arg[1:])
+			if file.Exist() {
+				target := cmakeFolder.Join("flags", file.Base())
+				if err := target.Parent().MkdirAll(); err == nil && file.CopyTo(target) == nil {
+					return "@${CMAKE_CURRENT_LIST_DIR}/flags/" + file.Base()
+				}
+			}
+		}
+		return filepath.ToSlash(replacer.Replace(arg))
+	}
+
+	c, err := parseRecipeForCMake(ctx, constants.RECIPE_C_PATTERN, remap)
+	if err != nil {
+		return err
+	}
+	cpp, err := parseRecipeForCMake(ctx, constants.RECIPE_CPP_PATTERN, remap)
+	if err != nil {
+		return err
+	}
+	link, err := parseRecipeForCMake(ctx, constants.RECIPE_C_COMBINE_PATTERN, remap)
+	if err != nil {
+		return err
+	}
+	// Not all the platforms compile assembly sources
+	asm, _ := parseRecipeForCMake(ctx, constants.RECIPE_S_PATTERN, remap)
+
+	toolchain := "# CMake toolchain file for " + props.Get("build.fqbn") + "\n"
+	toolchain += "set(CMAKE_SYSTEM_NAME Generic)\n"
+	if mcu := props.Get(constants.BUILD_PROPERTIES_BUILD_MCU); mcu != "" {
+		toolchain += "set(CMAKE_SYSTEM_PROCESSOR " + cmakeQuote(mcu) + ")\n"
+	}
+	toolchain += "set(CMAKE_TRY_COMPILE_TARGET_TYPE STATIC_LIBRARY)\n"
+	toolchain += "set(CMAKE_C_COMPILER " + cmakeQuote(c.compiler) + ")\n"
+	toolchain += "set(CMAKE_CXX_COMPILER " + cmakeQuote(cpp.compiler) + ")\n"
+	if asm != nil {
+		toolchain += "set(CMAKE_ASM_COMPILER " + cmakeQuote(asm.compiler) + ")\n"
+	}
+	toolchain += "set(CMAKE_C_FLAGS_INIT " + cmakeQuote(strings.Join(c.flags, " ")) + ")\n"
+	toolchain += "set(CMAKE_CXX_FLAGS_INIT " + cmakeQuote(strings.Join(cpp.flags, " ")) + ")\n"
+	if asm != nil {
+		toolchain += "set(CMAKE_ASM_FLAGS_INIT " + cmakeQuote(strings.Join(asm.flags, " ")) + ")\n"
+	}
+	toolchain += "set(CMAKE_EXE_LINKER_FLAGS_INIT " + cmakeQuote(strings.Join(link.flags, " ")) + ")\n"
+	if err := cmakeFolder.Join("toolchain.cmake").WriteFile([]byte(toolchain)); err != nil {
+		return errors.WithStack(err)
+	}
+
+	languages := "C CXX"
+	sources := []string{}
+	extensions := []string{"c", "cpp"}
+	if asm != nil {
+		languages += " ASM"
+		extensions = append(extensions, "S")
+	}
+	for _, dir := range []string{"core", "lib", "sketch"} {
+		for _, ext := range extensions {
+			sources = append(sources, dir+"/*."+ext)
+		}
+	}
+	defines := c.defines
+	for _, define := range cpp.defines {
+		defines = utils.AppendIfNotPresent(defines, define)
+	}
+
+	cmakelist := "cmake_minimum_required(VERSION 3.13)\n"
+	cmakelist += "if(NOT CMAKE_TOOLCHAIN_FILE)\n"
+	cmakelist += "  set(CMAKE_TOOLCHAIN_FILE ${CMAKE_CURRENT_LIST_DIR}/toolchain.cmake)\n"
+	cmakelist += "endif()\n"
+	cmakelist += "project(" + projectName + " " + languages + ")\n"
+	cmakelist += "add_compile_definitions(" + cmakeQuoteList(defines) + ")\n"
+	cmakelist += "include_directories(" + includeFolders + ")\n"
+	cmakelist += "file(GLOB_RECURSE SOURCES " + strings.Join(sources, " ") + ")\n"
+	cmakelist += "add_executable(" + projectName + " ${SOURCES})\n"
+	cmakelist += "set_target_properties(" + projectName + " PROPERTIES SUFFIX \".elf\")\n"
+	linkGroup := []string{}
+	for _, staticLib := range staticLibs {
+		lib := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(staticLib), "lib"), ".a")
+		location := filepath.ToSlash(strings.TrimPrefix(staticLib, cmakeFolder.String()))
+		cmakelist += "add_library(" + lib + " STATIC IMPORTED)\n"
+		cmakelist += "set_property(TARGET " + lib + " PROPERTY IMPORTED_LOCATION ${PROJECT_SOURCE_DIR}" + location + ")\n"
+		linkGroup = append(linkGroup, lib)
+	}
+	linkGroup = append(linkGroup, link.libs...)
+	cmakelist += "target_link_libraries(" + projectName + " -Wl,--start-group " + strings.Join(linkGroup, " ") + " -Wl,--end-group)\n"
+	return errors.WithStack(cmakeFolder.Join("CMakeLists.txt").WriteFile([]byte(cmakelist)))
+}
+
+// parseRecipeForCMake splits the command line of a recipe in the compiler,
+// the flags, the defines and the libraries to link. Source, object and
+// dependency files and include paths are discarded.
+func parseRecipeForCMake(ctx *types.Context, recipe string, remap func(string) string) (*cmakeRecipe, error) {
+	command, err := builder_utils.PrepareCommandForRecipe(ctx.BuildProperties, recipe, true)
+	if err != nil {
+		return nil, err
+	}
+	res := &cmakeRecipe{compiler: filepath.ToSlash(command.Args[0])}
+	args := command.Args[1:]
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "-o" || arg == "-MF" || arg == "-I":
+			i++
+		case arg == "-x" || arg == "-include" || arg == "-imacros":
+			// flags with a separate value
+			if i+1 < len(args) {
+				res.flags = append(res.flags, arg, remap(args[i+1]))
+				i++
+			}
+		case arg == "-c" || arg == "-MMD" || arg == "-MD" || arg == "-MP":
+		case arg == "-Wl,--start-group" || arg == "-Wl,--end-group":
+		case strings.HasPrefix(arg, "-I"):
+		case strings.HasPrefix(arg, "-D"):
+			res.defines = utils.AppendIfNotPresent(res.defines, arg[2:])
+		case strings.HasPrefix(arg, "-l"):
+			res.libs = utils.AppendIfNotPresent(res.libs, arg)
+		case strings.HasPrefix(arg, "-") || strings.HasPrefix(arg, "@"):
+			res.flags = append(res.flags, remap(arg))
+		}
+	}
+	return res, nil
+}
+
+// cmakeQuote returns s as a quoted CMake argument
+func cmakeQuote(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, `"`, `\"`, -1)
+	return `"` + s + `"`
+}
+
+func cmakeQuoteList(list []string) string {
+	res := []string{}
+	for _, s := range list {
+		res = append(res, cmakeQuote(s))
+	}
+	return strings.Join(res, " ")
+}
@@ -225,13 +225,11 @@ func (p *CTagsParser) FindCLinkageLines(tags []*types.CTag) map[string][]int {
 	for _, tag := range tags {
 
 		if lines[tag.Filename] != nil {
-			break
+			continue
 		}
 
 		file, err := os.Open(tag.Filename)
 		if err == nil {
-			defer file.Close()
-
 			lines[tag.Filename] = append(lines[tag.Filename], -1)
 
 			scanner := bufio.NewScanner(file)
@@ -248,11 +246,11 @@ func (p *CTagsParser) FindCLinkageLines(tags []*types.CTag) map[string][]int {
 			//		void foo();
 			//		void bar();
 			//	}
-			// case 1 and 2 can be simply recognized with string matching and indent level count
-			// case 3 needs specia attention: if the line ONLY contains `extern "C"` string, don't bail out on indent level = 0
+			// the scope ends when the indent level is back to zero at the end of the
+			// declaration or of the block, so in case 1 and 3 the declaration may
+			// span multiple lines
 
 			inScope := false
-			enteringScope := false
 			indentLevels := 0
 			line := 0
 
@@ -266,29 +264,21 @@ func (p *CTagsParser) FindCLinkageLines(tags []*types.CTag) map[string][]int {
 					continue
 				}
 
-				// check if we are on the first non empty line after externCDecl in case 3
-				if enteringScope == true {
-					enteringScope = false
-				}
-
 				// check if the line contains externCDecl
 				if strings.Contains(str, externCDecl) {
 					inScope = true
-					if len(str) == len(externCDecl) {
-						// case 3
-						enteringScope = true
-					}
 				}
 				if inScope == true {
 					lines[tag.Filename] = append(lines[tag.Filename], line)
 				}
 				indentLevels += strings.Count(str, "{") - strings.Count(str, "}")
 
-				// Bail out if indentLevel is zero and we are not in case 3
-				if indentLevels == 0 && enteringScope == false {
+				// Bail out at the end of the declaration or of the block
+				if indentLevels == 0 && strings.ContainsAny(str, "{};") {
 					inScope = false
 				}
 			}
+			file.Close()
 		}
 
 	}
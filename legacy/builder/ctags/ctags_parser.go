@@ -53,14 +53,17 @@ func (p *CTagsParser) Parse(ctagsOutput string, mainFile *paths.Path) []*types.C
 		p.tags = append(p.tags, parseTag(row))
 	}
 
+	p.process()
+	return p.tags
+}
+
+func (p *CTagsParser) process() {
 	p.skipTagsWhere(tagIsUnknown)
 	p.skipTagsWhere(tagIsUnhandled)
 	p.addPrototypes()
 	p.removeDefinedProtypes()
 	p.skipDuplicates()
 	p.skipTagsWhere(p.prototypeAndCodeDontMatch)
-
-	return p.tags
 }
 
 func (p *CTagsParser) addPrototypes() {
@@ -99,16 +102,22 @@ func addPrototype(tag *types.CTag) {
 	tag.PrototypeModifiers = strings.TrimSpace(tag.PrototypeModifiers)
 }
 
+// prototypeKey identifies the prototype of a function, the same prototype
+// may be declared in different namespaces
+func prototypeKey(tag *types.CTag) string {
+	return tag.Namespace + "::" + tag.Prototype
+}
+
 func (p *CTagsParser) removeDefinedProtypes() {
 	definedPrototypes := make(map[string]bool)
 	for _, tag := range p.tags {
 		if tag.Kind == KIND_PROTOTYPE {
-			definedPrototypes[tag.Prototype] = true
+			definedPrototypes[prototypeKey(tag)] = true
 		}
 	}
 
 	for _, tag := range p.tags {
-		if definedPrototypes[tag.Prototype] {
+		if definedPrototypes[prototypeKey(tag)] {
 			//if ctx.DebugLevel >= 10 {
 			//	ctx.GetLogger().Fprintln(os.Stdout, constants.LOG_LEVEL_DEBUG, constants.MSG_SKIPPING_TAG_ALREADY_DEFINED, tag.FunctionName)
 			//}
@@ -121,8 +130,8 @@ func (p *CTagsParser) skipDuplicates() {
 	definedPrototypes := make(map[string]bool)
 
 	for _, tag := range p.tags {
-		if !definedPrototypes[tag.Prototype] && tag.SkipMe == false {
-			definedPrototypes[tag.Prototype] = true
+		if !definedPrototypes[prototypeKey(tag)] && tag.SkipMe == false {
+			definedPrototypes[prototypeKey(tag)] = true
 		} else {
 			tag.SkipMe = true
 		}
@@ -157,6 +166,8 @@ func tagIsUnhandled(tag *types.CTag) bool {
 	return !isHandled(tag)
 }
 
+// isHandled returns true for the free functions, also the ones defined in a
+// namespace, the members of classes and structs don't need prototypes
 func isHandled(tag *types.CTag) bool {
 	if tag.Class != "" {
 		return false
@@ -164,9 +175,6 @@ func isHandled(tag *types.CTag) bool {
 	if tag.Struct != "" {
 		return false
 	}
-	if tag.Namespace != "" {
-		return false
-	}
 	return true
 }
 
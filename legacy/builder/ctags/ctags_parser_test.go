@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"github.com/arduino/arduino-cli/legacy/builder/types"
+	paths "github.com/arduino/go-paths-helper"
 
 	"github.com/stretchr/testify/require"
 )
@@ -331,3 +332,34 @@ func TestCTagsParserFunctionPointers(t *testing.T) {
 	require.Equal(t, "function", tags[idx].Kind)
 	require.Equal(t, "void funcCombo(void (*(&in)[5])(int));", tags[idx].Prototype)
 }
+
+func TestCTagsParserFindCLinkageLines(t *testing.T) {
+	tmp, err := paths.MkTempDir("", "ctags_c_linkage")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+
+	main := tmp.Join("main.ino")
+	require.NoError(t, main.WriteFile([]byte(`void setup() {}
+extern "C" void
+multiline() {
+}
+void loop() {}
+`)))
+	other := tmp.Join("other.ino")
+	require.NoError(t, other.WriteFile([]byte(`void cpp() {}
+extern "C"
+{
+  void c() {}
+}
+`)))
+
+	tags := []*types.CTag{
+		{Filename: main.String(), Line: 1},
+		{Filename: main.String(), Line: 3},
+		{Filename: other.String(), Line: 4},
+	}
+	parser := CTagsParser{}
+	lines := parser.FindCLinkageLines(tags)
+	require.Equal(t, []int{-1, 2, 3, 4}, lines[main.String()])
+	require.Equal(t, []int{-1, 2, 3, 4, 5}, lines[other.String()])
+}
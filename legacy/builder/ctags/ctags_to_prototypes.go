@@ -77,7 +77,9 @@ func (p *CTagsParser) collectFunctions() []*types.CTag {
 
 func (p *CTagsParser) firstFunctionAtLine() int {
 	for _, tag := range p.tags {
-		if !tagIsUnknown(tag) && isHandled(tag) && tag.Kind == KIND_FUNCTION && tag.Filename == p.mainFile.String() {
+		// The prototypes are added at file scope, so they can't be inserted
+		// before a function defined in a namespace
+		if !tagIsUnknown(tag) && isHandled(tag) && tag.Namespace == "" && tag.Kind == KIND_FUNCTION && tag.Filename == p.mainFile.String() {
 			return tag.Line
 		}
 	}
@@ -97,6 +99,7 @@ func (p *CTagsParser) toPrototypes() []*types.Prototype {
 				Prototype:    tag.Prototype,
 				Modifiers:    tag.PrototypeModifiers,
 				Line:         tag.Line,
+				Namespace:    tag.Namespace,
 				//Fields:       tag,
 			}
 			prototypes = append(prototypes, prototype)
@@ -153,11 +153,15 @@ func TestCTagsToPrototypesDefaultArguments(t *testing.T) {
 func TestCTagsToPrototypesNamespace(t *testing.T) {
 	prototypes, line := producePrototypes(t, "TestCTagsParserNamespace.txt", "/tmp/test030883150/preproc/ctags_target.cpp")
 
-	require.Equal(t, 2, len(prototypes))
-	require.Equal(t, "void setup();", prototypes[0].Prototype)
-	require.Equal(t, "/tmp/test030883150/preproc/ctags_target.cpp", prototypes[0].File)
-	require.Equal(t, "void loop();", prototypes[1].Prototype)
+	require.Equal(t, 3, len(prototypes))
+	require.Equal(t, "int value();", prototypes[0].Prototype)
+	require.Equal(t, "Test", prototypes[0].Namespace)
+	require.Equal(t, "void setup();", prototypes[1].Prototype)
+	require.Equal(t, "/tmp/test030883150/preproc/ctags_target.cpp", prototypes[1].File)
+	require.Equal(t, "", prototypes[1].Namespace)
+	require.Equal(t, "void loop();", prototypes[2].Prototype)
 
+	// the prototypes are added outside of the namespace
 	require.Equal(t, 8, line)
 }
 
@@ -0,0 +1,119 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package ctags
+
+import (
+	"math"
+	"strings"
+
+	"github.com/arduino/arduino-cli/legacy/builder/types"
+)
+
+// noPrototypesRegion is a range of lines of a sketch file where the
+// prototypes of the functions must not be generated
+type noPrototypesRegion struct {
+	file  string
+	start int
+	end   int
+}
+
+// parseNoPrototypesPragma returns true if the line is a
+// "#pragma arduino no_prototypes" directive, and its optional argument
+func parseNoPrototypesPragma(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "#") {
+		return "", false
+	}
+	fields := strings.Fields(line[1:])
+	if len(fields) < 3 || fields[0] != "pragma" || fields[1] != "arduino" || fields[2] != "no_prototypes" {
+		return "", false
+	}
+	if len(fields) > 3 {
+		return fields[3], true
+	}
+	return "", true
+}
+
+// findNoPrototypesRegions looks for the no_prototypes pragmas in the
+// preprocessed source. Without arguments the pragma disables the prototypes
+// of the whole file, otherwise only the ones of the functions between
+// "#pragma arduino no_prototypes begin" and "#pragma arduino no_prototypes end".
+func findNoPrototypesRegions(source string) []*noPrototypesRegion {
+	regions := []*noPrototypesRegion{}
+	open := map[string]*noPrototypesRegion{}
+	file := ""
+	line := 0
+	for _, row := range strings.Split(source, "\n") {
+		if markerLine, markerFile, ok := parseCppLineMarker(row); ok && strings.HasPrefix(strings.TrimSpace(row), "#") {
+			file, line = markerFile, markerLine
+			continue
+		}
+		if arg, ok := parseNoPrototypesPragma(row); ok {
+			switch arg {
+			case "":
+				regions = append(regions, &noPrototypesRegion{file: file, start: 0, end: math.MaxInt32})
+			case "begin":
+				if open[file] == nil {
+					open[file] = &noPrototypesRegion{file: file, start: line, end: math.MaxInt32}
+					regions = append(regions, open[file])
+				}
+			case "end":
+				if region := open[file]; region != nil {
+					region.end = line
+					delete(open, file)
+				}
+			}
+		}
+		line++
+	}
+	return regions
+}
+
+// SkipNoPrototypesTags excludes from the prototypes generation the functions
+// defined where it has been disabled by a "#pragma arduino no_prototypes"
+func (p *CTagsParser) SkipNoPrototypesTags(source string) {
+	regions := findNoPrototypesRegions(source)
+	if len(regions) == 0 {
+		return
+	}
+	p.skipTagsWhere(func(tag *types.CTag) bool {
+		if tag.Kind != KIND_FUNCTION {
+			return false
+		}
+		for _, region := range regions {
+			if tag.Filename == region.file && tag.Line >= region.start && tag.Line <= region.end {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// CommentNoPrototypesPragmas turns the no_prototypes pragmas of the sketch
+// source into comments, so the compiler doesn't warn about unknown pragmas.
+// The number of lines is preserved.
+func CommentNoPrototypesPragmas(source string) string {
+	if !strings.Contains(source, "no_prototypes") {
+		return source
+	}
+	rows := strings.Split(source, "\n")
+	for i, row := range rows {
+		if _, ok := parseNoPrototypesPragma(row); ok {
+			rows[i] = "// " + row
+		}
+	}
+	return strings.Join(rows, "\n")
+}
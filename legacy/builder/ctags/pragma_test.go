@@ -0,0 +1,56 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package ctags
+
+import (
+	"testing"
+
+	paths "github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSkipNoPrototypesTags(t *testing.T) {
+	source := `# 1 "/tmp/sketch/sketch.ino"
+void setup() {}
+#pragma arduino no_prototypes begin
+template <typename T> T twice(T x) { return x * 2; }
+int twice(int x) { return x * 2; }
+#pragma arduino no_prototypes end
+void loop() {}
+# 1 "/tmp/sketch/other.ino"
+#pragma arduino no_prototypes
+void other() {}
+`
+	ctagsOutput := "setup\t/tmp/sketch/sketch.ino\t/^void setup() {}$/;\"\tkind:function\tline:1\tsignature:()\treturntype:void\n" +
+		"twice\t/tmp/sketch/sketch.ino\t/^template <typename T> T twice(T x) { return x * 2; }$/;\"\tkind:function\tline:3\tsignature:(T x)\treturntype:template <typename T> T\n" +
+		"twice\t/tmp/sketch/sketch.ino\t/^int twice(int x) { return x * 2; }$/;\"\tkind:function\tline:4\tsignature:(int x)\treturntype:int\n" +
+		"loop\t/tmp/sketch/sketch.ino\t/^void loop() {}$/;\"\tkind:function\tline:6\tsignature:()\treturntype:void\n" +
+		"other\t/tmp/sketch/other.ino\t/^void other() {}$/;\"\tkind:function\tline:2\tsignature:()\treturntype:void\n"
+	parser := &CTagsParser{}
+	parser.Parse(ctagsOutput, paths.New("/tmp/sketch/sketch.ino"))
+	parser.SkipNoPrototypesTags(source)
+	prototypes, line := parser.GeneratePrototypes()
+
+	require.Equal(t, 1, line)
+	require.Equal(t, 2, len(prototypes))
+	require.Equal(t, "void setup();", prototypes[0].Prototype)
+	require.Equal(t, "void loop();", prototypes[1].Prototype)
+}
+
+func TestCommentNoPrototypesPragmas(t *testing.T) {
+	source := "void setup() {}\n  #  pragma arduino no_prototypes begin\nvoid loop() {}\n#pragma once\n"
+	require.Equal(t, "void setup() {}\n//   #  pragma arduino no_prototypes begin\nvoid loop() {}\n#pragma once\n", CommentNoPrototypesPragmas(source))
+}
@@ -0,0 +1,81 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+//go:build treesitter
+// +build treesitter
+
+package ctags
+
+// #cgo LDFLAGS: -ltree-sitter-cpp -ltree-sitter
+// #include <stdlib.h>
+// #include <tree_sitter/api.h>
+// const TSLanguage *tree_sitter_cpp(void);
+import "C"
+
+import (
+	"errors"
+	"unsafe"
+)
+
+// TreeSitterSupported is true if the build supports the treesitter
+// preprocessor
+const TreeSitterSupported = true
+
+// parseCppTree parses the source with the C++ grammar of tree-sitter and
+// returns a copy of the syntax tree, so the rest of the parser doesn't need
+// cgo
+func parseCppTree(source string) (*cppNode, error) {
+	parser := C.ts_parser_new()
+	defer C.ts_parser_delete(parser)
+	if !C.ts_parser_set_language(parser, C.tree_sitter_cpp()) {
+		return nil, errors.New("the tree-sitter C++ grammar is not compatible with the tree-sitter library")
+	}
+
+	csource := C.CString(source)
+	defer C.free(unsafe.Pointer(csource))
+	tree := C.ts_parser_parse_string(parser, nil, csource, C.uint32_t(len(source)))
+	if tree == nil {
+		return nil, errors.New("tree-sitter failed to parse the sketch")
+	}
+	defer C.ts_tree_delete(tree)
+
+	cursor := C.ts_tree_cursor_new(C.ts_tree_root_node(tree))
+	defer C.ts_tree_cursor_delete(&cursor)
+	return copyCppNode(&cursor), nil
+}
+
+// copyCppNode copies the node at the cursor position and all its children
+func copyCppNode(cursor *C.TSTreeCursor) *cppNode {
+	node := C.ts_tree_cursor_current_node(cursor)
+	res := &cppNode{
+		kind:  C.GoString(C.ts_node_type(node)),
+		start: int(C.ts_node_start_byte(node)),
+		end:   int(C.ts_node_end_byte(node)),
+		row:   int(C.ts_node_start_point(node).row),
+	}
+	if field := C.ts_tree_cursor_current_field_name(cursor); field != nil {
+		res.field = C.GoString(field)
+	}
+	if C.ts_tree_cursor_goto_first_child(cursor) {
+		for {
+			res.children = append(res.children, copyCppNode(cursor))
+			if !C.ts_tree_cursor_goto_next_sibling(cursor) {
+				break
+			}
+		}
+		C.ts_tree_cursor_goto_parent(cursor)
+	}
+	return res
+}
@@ -0,0 +1,377 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package ctags
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/arduino/arduino-cli/legacy/builder/types"
+	"github.com/arduino/arduino-cli/legacy/builder/utils"
+	"github.com/arduino/go-paths-helper"
+	"github.com/pkg/errors"
+)
+
+const KIND_VARIABLE = "variable"
+
+// ParseWithTreeSitter extracts the tags of the functions and variables declared
+// at file scope in the preprocessed source, parsing it with the C++ grammar of
+// tree-sitter instead of using the output of ctags. The tags are filtered like
+// the ones produced by Parse.
+// tree-sitter is linked only when arduino-cli is built with the treesitter tag,
+// otherwise an error is returned.
+func (p *CTagsParser) ParseWithTreeSitter(source string, mainFile *paths.Path) ([]*types.CTag, error) {
+	p.mainFile = mainFile
+
+	// tree-sitter doesn't understand the line markers, the directives are
+	// blanked keeping the position of the other tokens
+	source, lines := blankCppDirectives(source)
+	root, err := parseCppTree(source)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	b := &cppTagsBuilder{source: source, lines: lines, cLinkage: map[*types.CTag]bool{}}
+	b.addItems(root, "", false)
+	p.tags = b.tags
+	p.process()
+
+	// The linkage is known from the parsed source, there is no need to look
+	// for extern "C" blocks in the sketch files like FixCLinkageTagsDeclarations
+	for _, tag := range p.tags {
+		if b.cLinkage[tag] {
+			tag.PrototypeModifiers = strings.TrimSpace(tag.PrototypeModifiers + " " + EXTERN)
+		}
+	}
+	return p.tags, nil
+}
+
+// cppNode is a node of the syntax tree produced by tree-sitter
+type cppNode struct {
+	kind string
+	// field is the name of the node in its parent, if any
+	field      string
+	start, end int
+	row        int
+	children   []*cppNode
+}
+
+// child returns the child with the given field name, or nil
+func (n *cppNode) child(field string) *cppNode {
+	for _, child := range n.children {
+		if child.field == field {
+			return child
+		}
+	}
+	return nil
+}
+
+// cppLocation is the location in the original files of a line of the
+// preprocessed source, as given by the line markers
+type cppLocation struct {
+	file string
+	line int
+}
+
+// blankCppDirectives replaces the preprocessor directives with spaces and
+// returns the location of each line of the source
+func blankCppDirectives(source string) (string, []cppLocation) {
+	rows := strings.Split(source, "\n")
+	lines := make([]cppLocation, len(rows))
+	file := ""
+	line := 1
+	continued := false
+	for i, row := range rows {
+		lines[i] = cppLocation{file: file, line: line}
+		line++
+		directive := continued || strings.HasPrefix(strings.TrimLeft(row, " \t"), "#")
+		if !directive {
+			continue
+		}
+		continued = strings.HasSuffix(row, "\\")
+		if markerLine, markerFile, ok := parseCppLineMarker(row); ok {
+			// the marker refers to the line following it
+			line = markerLine
+			file = markerFile
+		}
+		rows[i] = strings.Repeat(" ", len(row))
+	}
+	return strings.Join(rows, "\n"), lines
+}
+
+// parseCppLineMarker parses a line marker in the "# 123 "file" flags" or
+// "#line 123 "file"" form
+func parseCppLineMarker(directive string) (int, string, bool) {
+	directive = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(directive), "#"))
+	directive = strings.TrimPrefix(directive, "line")
+	fields := strings.SplitN(strings.TrimSpace(directive), " ", 2)
+	if len(fields) < 2 {
+		return 0, "", false
+	}
+	line, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, "", false
+	}
+	file, _, ok := utils.ParseCppString(strings.TrimSpace(fields[1]))
+	if !ok {
+		return 0, "", false
+	}
+	return line, file, true
+}
+
+// cppToken is a token of a C++ source, with its location in the original
+// source file
+type cppToken struct {
+	text string
+	file string
+	line int
+	// space is true if the token is preceded by spaces or comments
+	space bool
+}
+
+// joinCppTokens rebuilds the source of the tokens, normalizing the spaces
+func joinCppTokens(tokens []*cppToken) string {
+	res := ""
+	for i, tok := range tokens {
+		if i > 0 && tok.space {
+			res += " "
+		}
+		res += tok.text
+	}
+	return res
+}
+
+// cppLiterals are the nodes whose source is used as a single token
+var cppLiterals = map[string]bool{
+	"string_literal": true, "raw_string_literal": true, "char_literal": true,
+	"system_lib_string": true,
+}
+
+// cppDeclarators wrap the declarator of a function, like in "int *f()"
+var cppDeclarators = map[string]bool{
+	"pointer_declarator": true, "reference_declarator": true,
+	"parenthesized_declarator": true, "attributed_declarator": true,
+}
+
+// cppSkippedItems are the declarations that never need prototypes
+var cppSkippedItems = map[string]bool{
+	"comment": true, ";": true, "type_definition": true, "alias_declaration": true,
+	"using_declaration": true, "static_assert_declaration": true, "friend_declaration": true,
+	"namespace_alias_definition": true, "template_instantiation": true,
+}
+
+// cppTagsBuilder converts the declarations found in the syntax tree to tags
+type cppTagsBuilder struct {
+	source   string
+	lines    []cppLocation
+	tags     []*types.CTag
+	cLinkage map[*types.CTag]bool
+}
+
+// addItems adds the tags of the declarations contained in a translation unit,
+// a namespace or a linkage specification
+func (b *cppTagsBuilder) addItems(parent *cppNode, namespace string, cLinkage bool) {
+	for _, item := range parent.children {
+		b.addItem(item, namespace, cLinkage)
+	}
+}
+
+func (b *cppTagsBuilder) addItem(item *cppNode, namespace string, cLinkage bool) {
+	switch item.kind {
+	case "namespace_definition":
+		name := "__anon" // anonymous namespaces are named like ctags does
+		if n := item.child("name"); n != nil {
+			name = joinCppTokens(b.tokens(n))
+		}
+		if namespace != "" {
+			name = namespace + "::" + name
+		}
+		if body := item.child("body"); body != nil {
+			b.addItems(body, name, cLinkage)
+		}
+	case "linkage_specification":
+		value := item.child("value")
+		cLinkage = cLinkage || (value != nil && b.source[value.start:value.end] == `"C"`)
+		body := item.child("body")
+		if body != nil && body.kind == "declaration_list" {
+			b.addItems(body, namespace, cLinkage)
+		} else if body != nil {
+			b.addItem(body, namespace, cLinkage)
+		}
+	case "ERROR":
+		// the declarations that tree-sitter was able to recover are still
+		// looked for in the code it can't parse
+		for _, child := range item.children {
+			switch child.kind {
+			case "function_definition", "declaration", "template_declaration", "namespace_definition", "linkage_specification":
+				b.addItem(child, namespace, cLinkage)
+			}
+		}
+	default:
+		if cppSkippedItems[item.kind] || strings.HasPrefix(item.kind, "preproc_") {
+			return
+		}
+		if tag := b.toTag(item, namespace); tag != nil {
+			b.tags = append(b.tags, tag)
+			b.cLinkage[tag] = cLinkage
+		}
+	}
+}
+
+// toTag converts a declaration in a tag, returns nil if the declaration must
+// be ignored
+func (b *cppTagsBuilder) toTag(item *cppNode, namespace string) *types.CTag {
+	decl := item
+	if item.kind == "template_declaration" && len(item.children) > 0 {
+		decl = item.children[len(item.children)-1]
+	}
+
+	body := decl.child("body")
+	if decl.kind != "function_definition" {
+		body = nil
+	}
+	// tree-sitter doesn't know the macros: a macro invocation before the
+	// declaration, like "FASTLED_USING_NAMESPACE", is taken as its type and
+	// the actual type becomes an error. The tokens before the error are left
+	// out.
+	skip := []*cppNode{body}
+	for i, child := range decl.children {
+		if child.field == "declarator" {
+			break
+		}
+		if child.kind == "ERROR" {
+			skip = append(skip, decl.children[:i]...)
+		}
+	}
+	tokens := b.tokens(item, skip...)
+	if len(tokens) > 0 && tokens[len(tokens)-1].text == ";" {
+		tokens = tokens[:len(tokens)-1]
+	}
+	if len(tokens) == 0 {
+		return nil
+	}
+	tag := &types.CTag{
+		Filename:  tokens[0].file,
+		Line:      tokens[0].line,
+		Code:      joinCppTokens(tokens),
+		Namespace: namespace,
+	}
+
+	function := findCppFunctionDeclarator(decl.child("declarator"))
+	if (decl.kind != "function_definition" && decl.kind != "declaration") || function == nil || decl.child("type") == nil {
+		if decl.kind == "function_definition" {
+			// without a return type it's not a function definition
+			return nil
+		}
+		tag.Kind = KIND_VARIABLE
+		return tag
+	}
+	name := function.child("declarator")
+	switch name.kind {
+	case "identifier", "operator_name":
+		tag.FunctionName = joinCppTokens(b.tokens(name))
+	case "qualified_identifier", "template_function":
+		// a member of a class or a namespace, or a specialization
+		return nil
+	default:
+		// a function pointer
+		tag.Kind = KIND_VARIABLE
+		return tag
+	}
+
+	tag.Kind = KIND_PROTOTYPE
+	if body != nil {
+		tag.Kind = KIND_FUNCTION
+	}
+	if params := function.child("parameters"); params != nil {
+		tag.Signature = joinCppTokens(b.tokens(params))
+	}
+	// the static modifier is added back by addPrototype
+	for _, child := range decl.children {
+		if child.kind == "storage_class_specifier" && b.source[child.start:child.end] == STATIC {
+			skip = append(skip, child)
+		}
+	}
+	prototype := b.tokens(item, skip...)
+	if len(prototype) > 0 && prototype[len(prototype)-1].text == ";" {
+		prototype = prototype[:len(prototype)-1]
+	}
+	tag.Prototype = joinCppTokens(prototype) + ";"
+	return tag
+}
+
+// findCppFunctionDeclarator returns the innermost function declarator, it
+// handles the functions returning function pointers, like
+// "void (*getCallback(int id))(int)"
+func findCppFunctionDeclarator(declarator *cppNode) *cppNode {
+	var function *cppNode
+	for declarator != nil {
+		if declarator.kind == "function_declarator" {
+			function = declarator
+		} else if !cppDeclarators[declarator.kind] {
+			break
+		}
+		next := declarator.child("declarator")
+		if next == nil {
+			// the declarator of a reference or in parenthesis has no name
+			for _, child := range declarator.children {
+				if child.kind == "function_declarator" || cppDeclarators[child.kind] {
+					next = child
+				}
+			}
+		}
+		declarator = next
+	}
+	if function == nil || function.child("declarator") == nil {
+		return nil
+	}
+	return function
+}
+
+// tokens returns the tokens of the source of the node, without the comments
+// and the skipped children
+func (b *cppTagsBuilder) tokens(node *cppNode, skip ...*cppNode) []*cppToken {
+	tokens := []*cppToken{}
+	prevEnd := node.start
+	var walk func(n *cppNode)
+	walk = func(n *cppNode) {
+		for _, s := range skip {
+			if n == s {
+				return
+			}
+		}
+		if n.kind == "comment" || n.start == n.end {
+			return
+		}
+		if len(n.children) > 0 && !cppLiterals[n.kind] {
+			for _, child := range n.children {
+				walk(child)
+			}
+			return
+		}
+		text := b.source[n.start:n.end]
+		location := b.lines[n.row]
+		tokens = append(tokens, &cppToken{
+			text:  text,
+			file:  location.file,
+			line:  location.line,
+			space: n.start > prevEnd,
+		})
+		prevEnd = n.end
+	}
+	walk(node)
+	return tokens
+}
@@ -0,0 +1,175 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+//go:build treesitter
+// +build treesitter
+
+package ctags
+
+import (
+	"testing"
+
+	"github.com/arduino/arduino-cli/legacy/builder/types"
+	paths "github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func produceTreeSitterPrototypes(t *testing.T, source string, mainFile string) ([]*types.Prototype, int) {
+	parser := &CTagsParser{}
+	_, err := parser.ParseWithTreeSitter(source, paths.New(mainFile))
+	require.NoError(t, err)
+	return parser.GeneratePrototypes()
+}
+
+func TestParseWithTreeSitter(t *testing.T) {
+	source := `# 1 "/tmp/sketch/sketch.ino"
+# 1 "/tmp/sketch/sketch.ino" 1
+#include <Arduino.h>
+// a comment with a function: void commented() {}
+/* int
+   blockCommented() {} */
+const char *text = "void inString() {";
+auto square = [](int x) { return x * x; };
+std::map<int, std::vector<int>> table;
+
+class Led {
+public:
+  void on() {}
+};
+
+namespace util {
+int helper() { return 1; }
+}
+
+void Led::off() {}
+
+void setup() {
+  int (*fn)(int) = [](int y) -> int { return y; };
+}
+
+static void
+multiline(int a,
+          int b) {
+}
+
+template <typename T>
+T maximum(T a, T b) { return a > b ? a : b; }
+
+std::vector<std::pair<int, int>> pairs(int n) { return {}; }
+
+void (*callback(int id))(int) { return nullptr; }
+
+int declared(int x);
+int declared(int x) { return x; }
+
+extern "C" {
+void fromC() {}
+}
+
+bool operator==(const Led &a, const Led &b) { return true; }
+
+struct Point { int x, y; } origin;
+
+void loop() {}
+# 1 "/tmp/sketch/other.ino"
+void other(char c = '}') {}
+`
+	prototypes, line := produceTreeSitterPrototypes(t, source, "/tmp/sketch/sketch.ino")
+
+	protos := []string{}
+	for _, proto := range prototypes {
+		protos = append(protos, proto.Namespace+"|"+proto.Modifiers+"|"+proto.Prototype)
+	}
+	require.Equal(t, []string{
+		"util||int helper();",
+		"||void setup();",
+		"|static|void multiline(int a, int b);",
+		"||template <typename T> T maximum(T a, T b);",
+		"||std::vector<std::pair<int, int>> pairs(int n);",
+		"||void (*callback(int id))(int);",
+		"|extern \"C\"|void fromC();",
+		"||bool operator==(const Led &a, const Led &b);",
+		"||void loop();",
+		"||void other(char c = '}');",
+	}, protos)
+
+	require.Equal(t, "/tmp/sketch/sketch.ino", prototypes[0].File)
+	require.Equal(t, 15, prototypes[0].Line)
+	require.Equal(t, 20, prototypes[1].Line)
+	require.Equal(t, 24, prototypes[2].Line)
+	require.Equal(t, 29, prototypes[3].Line)
+	require.Equal(t, "/tmp/sketch/other.ino", prototypes[9].File)
+	require.Equal(t, 1, prototypes[9].Line)
+
+	// the prototypes are added outside of the namespace
+	require.Equal(t, 20, line)
+}
+
+func TestParseWithTreeSitterNamespaces(t *testing.T) {
+	source := `# 1 "/tmp/sketch/sketch.ino"
+namespace outer {
+namespace inner {
+int deep() { return 1; }
+}
+void shallow() {}
+}
+namespace {
+void hidden() {}
+}
+extern "C" {
+namespace c {
+void linked() {}
+}
+}
+void setup() {}
+`
+	prototypes, _ := produceTreeSitterPrototypes(t, source, "/tmp/sketch/sketch.ino")
+	protos := []string{}
+	for _, proto := range prototypes {
+		protos = append(protos, proto.Namespace+"|"+proto.Modifiers+"|"+proto.Prototype)
+	}
+	require.Equal(t, []string{
+		"outer::inner||int deep();",
+		"outer||void shallow();",
+		"__anon||void hidden();",
+		"c|extern \"C\"|void linked();",
+		"||void setup();",
+	}, protos)
+}
+
+func TestParseWithTreeSitterFunctionPointer(t *testing.T) {
+	source := `# 1 "/tmp/sketch/sketch.ino"
+void (*handler)() = &blink;
+
+void blink() {}
+void setup() {}
+`
+	prototypes, line := produceTreeSitterPrototypes(t, source, "/tmp/sketch/sketch.ino")
+	require.Len(t, prototypes, 2)
+	require.Equal(t, "void blink();", prototypes[0].Prototype)
+	// the prototypes are added before the function pointer
+	require.Equal(t, 1, line)
+}
+
+func TestParseWithTreeSitterUnknownMacro(t *testing.T) {
+	source := `# 1 "/tmp/sketch/sketch.ino"
+FASTLED_USING_NAMESPACE
+void setup() {}
+`
+	prototypes, line := produceTreeSitterPrototypes(t, source, "/tmp/sketch/sketch.ino")
+	require.Len(t, prototypes, 1)
+	require.Equal(t, "void setup();", prototypes[0].Prototype)
+	require.Equal(t, 2, line)
+}
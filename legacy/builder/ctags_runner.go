@@ -58,6 +58,7 @@ func (s *CTagsRunner) Run(ctx *types.Context) error {
 
 	ctx.CTagsOfPreprocessedSource = parser.Parse(ctx.CTagsOutput, ctx.Sketch.MainFile.Name)
 	parser.FixCLinkageTagsDeclarations(ctx.CTagsOfPreprocessedSource)
+	parser.SkipNoPrototypesTags(ctx.SourceGccMinusE)
 
 	protos, line := parser.GeneratePrototypes()
 	if line != -1 {
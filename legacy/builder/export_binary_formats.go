@@ -0,0 +1,161 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"fmt"
+	"strconv"
+
+	bldr "github.com/arduino/arduino-cli/arduino/builder"
+	"github.com/arduino/arduino-cli/legacy/builder/builder_utils"
+	"github.com/arduino/arduino-cli/legacy/builder/types"
+	"github.com/arduino/arduino-cli/legacy/builder/utils"
+	"github.com/arduino/go-paths-helper"
+	properties "github.com/arduino/go-properties-orderedmap"
+	"github.com/pkg/errors"
+)
+
+// ExportBinaryFormats creates the binaries in the formats requested with
+// ctx.ExportFormats. A platform can create a format with the
+// recipe.export.FORMAT.pattern recipe, otherwise the built-in converter is
+// used, starting from the .hex (or the .bin) created by the objcopy recipes.
+type ExportBinaryFormats struct{}
+
+func (s *ExportBinaryFormats) Run(ctx *types.Context) error {
+	if ctx.OnlyUpdateCompilationDatabase {
+		return nil
+	}
+
+	buildProperties := ctx.BuildProperties
+	for _, format := range ctx.ExportFormats {
+		recipe := "recipe.export." + format.Name + ".pattern"
+		if !buildProperties.ContainsKey(recipe) {
+			if err := exportBinaryFormat(ctx, format); err != nil {
+				return errors.WithStack(err)
+			}
+			continue
+		}
+
+		properties := buildProperties.Clone()
+		if format.HasOffset {
+			properties.Set("export.offset", fmt.Sprintf("0x%x", format.Offset))
+		}
+		command, err := builder_utils.PrepareCommandForRecipe(properties, recipe, true)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if _, _, err := utils.ExecCommand(ctx, command, utils.ShowIfVerbose /* stdout */, utils.Show /* stderr */); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+func exportBinaryFormat(ctx *types.Context, format *bldr.ExportFormat) error {
+	buildProperties := ctx.BuildProperties
+	projectName := buildProperties.Get("build.project_name")
+	output := func(ext string) *paths.Path {
+		if format.HasOffset {
+			return ctx.BuildPath.Join(fmt.Sprintf("%s.at_0x%x.%s", projectName, format.Offset, ext))
+		}
+		return ctx.BuildPath.Join(projectName + "." + ext)
+	}
+
+	if format.Name == bldr.ExportFormatMerged {
+		merged := ctx.BuildPath.Join(projectName + ".with_bootloader.hex")
+		if merged.NotExist() {
+			return errors.New("the board doesn't define a bootloader to merge with the sketch")
+		}
+		image, err := bldr.LoadHexImage(merged)
+		if err != nil {
+			return err
+		}
+		output := ctx.BuildPath.Join(projectName + ".with_bootloader.bin")
+		if format.HasOffset {
+			// the raw binary starts at the given address
+			if image, err = image.Slice(format.Offset); err != nil {
+				return err
+			}
+			output = ctx.BuildPath.Join(fmt.Sprintf("%s.with_bootloader.at_0x%x.bin", projectName, format.Offset))
+		}
+		return output.WriteFile(image.Data)
+	}
+
+	image, err := loadSketchImage(ctx.BuildPath, projectName, buildProperties)
+	if err != nil {
+		return err
+	}
+	if format.HasOffset {
+		image.Address = format.Offset
+	}
+
+	switch format.Name {
+	case bldr.ExportFormatHex:
+		if output("hex").Exist() {
+			return nil
+		}
+		data, err := image.HexBytes()
+		if err != nil {
+			return err
+		}
+		return output("hex").WriteFile(data)
+	case bldr.ExportFormatBin:
+		if output("bin").Exist() {
+			return nil
+		}
+		return output("bin").WriteFile(image.Data)
+	case bldr.ExportFormatUF2:
+		family, ok := buildProperties.GetOk("build.uf2.family")
+		if !ok {
+			family = buildProperties.Get("build.mcu")
+		}
+		familyID, err := bldr.UF2FamilyID(family)
+		if err != nil {
+			return fmt.Errorf("%s, set the build.uf2.family property", err)
+		}
+		return output("uf2").WriteFile(image.UF2Bytes(familyID))
+	}
+	return nil
+}
+
+// loadSketchImage loads the firmware image from the .hex created by the
+// platform or, if missing, from the .bin. The .bin is placed at the address
+// given by the build.flash.address property or, if not set, at the address of
+// the .elf it has been extracted from.
+func loadSketchImage(buildPath *paths.Path, projectName string, buildProperties *properties.Map) (*bldr.FirmwareImage, error) {
+	if hex := buildPath.Join(projectName + ".hex"); hex.Exist() {
+		return bldr.LoadHexImage(hex)
+	}
+	bin := buildPath.Join(projectName + ".bin")
+	if bin.NotExist() {
+		return nil, errors.New("the platform didn't create a .hex or .bin file to convert")
+	}
+	address := uint32(0)
+	if value, ok := buildProperties.GetOk("build.flash.address"); ok {
+		parsed, err := strconv.ParseUint(value, 0, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid build.flash.address %s", value)
+		}
+		address = uint32(parsed)
+	} else if elf := buildPath.Join(projectName + ".elf"); elf.Exist() {
+		elfAddress, err := bldr.ElfLoadAddress(elf)
+		if err != nil {
+			return nil, err
+		}
+		address = elfAddress
+	}
+	return bldr.LoadBinImage(bin, address)
+}
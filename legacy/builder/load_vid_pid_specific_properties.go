@@ -49,6 +49,7 @@ func (s *LoadVIDPIDSpecificProperties) Run(ctx *types.Context) error {
 
 	vidPidSpecificProperties := buildProperties.SubTree(constants.BUILD_PROPERTIES_VID).SubTree(strconv.Itoa(VIDPIDIndex))
 	buildProperties.Merge(vidPidSpecificProperties)
+	ctx.PropertiesTrace.Record(buildProperties, "the properties of the USB VID/PID "+ctx.USBVidPid)
 
 	return nil
 }
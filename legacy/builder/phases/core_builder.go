@@ -17,8 +17,8 @@ package phases
 
 import (
 	"os"
-	"strings"
 
+	"github.com/arduino/arduino-cli/arduino/builder"
 	"github.com/arduino/arduino-cli/legacy/builder/builder_utils"
 	"github.com/arduino/arduino-cli/legacy/builder/constants"
 	"github.com/arduino/arduino-cli/legacy/builder/types"
@@ -33,7 +33,8 @@ type CoreBuilder struct{}
 func (s *CoreBuilder) Run(ctx *types.Context) error {
 	coreBuildPath := ctx.CoreBuildPath
 	coreBuildCachePath := ctx.CoreBuildCachePath
-	buildProperties := ctx.BuildProperties
+	// The core is third-party code like the libraries
+	buildProperties := builder_utils.WithWarningsLevel(ctx.BuildProperties, ctx.LibrariesWarningsLevel)
 
 	if err := coreBuildPath.MkdirAll(); err != nil {
 		return errors.WithStack(err)
@@ -67,8 +68,6 @@ func compileCore(ctx *types.Context, buildPath *paths.Path, buildCachePath *path
 	coreFolder := buildProperties.GetPath("build.core.path")
 	variantFolder := buildProperties.GetPath("build.variant.path")
 
-	targetCoreFolder := buildProperties.GetPath(constants.BUILD_PROPERTIES_RUNTIME_PLATFORM_PATH)
-
 	includes := []string{}
 	includes = append(includes, coreFolder.String())
 	if variantFolder != nil && variantFolder.IsDir() {
@@ -76,41 +75,89 @@ func compileCore(ctx *types.Context, buildPath *paths.Path, buildCachePath *path
 	}
 	includes = utils.Map(includes, utils.WrapWithHyphenI)
 
-	var err error
-
-	variantObjectFiles := paths.NewPathList()
+	// The variant and the core are compiled together, to make better use
+	// of the parallel jobs
+	plan := builder_utils.NewCompilationPlan()
+	var variantUnit *builder_utils.CompilationUnit
 	if variantFolder != nil && variantFolder.IsDir() {
-		variantObjectFiles, err = builder_utils.CompileFiles(ctx, variantFolder, true, buildPath, buildProperties, includes)
+		unit, err := plan.AddFiles(variantFolder, true, buildPath, buildProperties, includes)
 		if err != nil {
 			return nil, nil, errors.WithStack(err)
 		}
+		variantUnit = unit
+	}
+	variantObjectFiles := func() paths.PathList {
+		if variantUnit == nil {
+			return paths.NewPathList()
+		}
+		return variantUnit.ObjectFiles()
 	}
 
-	// Recreate the archive if ANY of the core files (including platform.txt) has changed
-	realCoreFolder := coreFolder.Parent().Parent()
-
-	var targetArchivedCore *paths.Path
+	// Recreate the archive if ANY of the core or variant files, or the
+	// compiler flags, have changed
+	var coreCache *builder.CoreCache
+	var coreCacheKey string
 	if buildCachePath != nil {
-		archivedCoreName := GetCachedCoreArchiveFileName(buildProperties.Get(constants.BUILD_PROPERTIES_FQBN),
-			buildProperties.Get("compiler.optimization_flags"), realCoreFolder)
-		targetArchivedCore = buildCachePath.Join(archivedCoreName)
-		canUseArchivedCore := !ctx.OnlyUpdateCompilationDatabase &&
-			!ctx.Clean &&
-			!builder_utils.CoreOrReferencedCoreHasChanged(realCoreFolder, targetCoreFolder, targetArchivedCore)
-
-		if canUseArchivedCore {
+		cache, err := builder.NewCoreCache(buildCachePath, ctx.CoreBuildCacheMaxSize, builder.CoreCacheEvictionPolicy(ctx.CoreBuildCacheEvictionPolicy))
+		if err != nil {
+			return nil, nil, errors.WithStack(err)
+		}
+		key, err := GetCachedCoreArchiveKey(buildProperties, coreFolder, variantFolder)
+		if err != nil {
+			return nil, nil, errors.WithStack(err)
+		}
+		coreCache = cache
+		coreCacheKey = key
+
+		canUseArchivedCore := !ctx.OnlyUpdateCompilationDatabase && !ctx.Clean
+		if targetArchivedCore := coreCache.Get(coreCacheKey); canUseArchivedCore && targetArchivedCore != nil {
 			// use archived core
+			ctx.Stats.SetCoreCacheHit(true)
 			if ctx.Verbose {
 				logger.Println(constants.LOG_LEVEL_INFO, "Using precompiled core: {0}", targetArchivedCore)
 			}
-			return targetArchivedCore, variantObjectFiles, nil
+			if ctx.CompilationDatabase != nil {
+				if err := plan.AddFilesToCompilationDatabase(coreFolder, true, buildPath, buildProperties, includes); err != nil {
+					return nil, nil, errors.WithStack(err)
+				}
+			}
+			if err := plan.Run(ctx); err != nil {
+				return nil, nil, errors.WithStack(err)
+			}
+			return targetArchivedCore, variantObjectFiles(), nil
+		}
+
+		if ctx.RemoteBuildCache != nil && canUseArchivedCore {
+			if archive, err := fetchCoreArchiveFromRemoteCache(ctx, buildPath, buildProperties, coreFolder, variantFolder); err != nil {
+				logger.Println(constants.LOG_LEVEL_WARN, constants.MSG_REMOTE_CACHE_ERROR, err)
+			} else if archive != nil {
+				ctx.Stats.SetCoreCacheHit(true)
+				if ctx.Verbose {
+					logger.Println(constants.LOG_LEVEL_INFO, constants.MSG_USING_REMOTE_CACHED_FILE, archive)
+				}
+				// Keep a copy in the local cache for the next builds
+				coreCache.Put(coreCacheKey, archive)
+				if ctx.CompilationDatabase != nil {
+					if err := plan.AddFilesToCompilationDatabase(coreFolder, true, buildPath, buildProperties, includes); err != nil {
+						return nil, nil, errors.WithStack(err)
+					}
+				}
+				if err := plan.Run(ctx); err != nil {
+					return nil, nil, errors.WithStack(err)
+				}
+				return archive, variantObjectFiles(), nil
+			}
 		}
 	}
 
-	coreObjectFiles, err := builder_utils.CompileFiles(ctx, coreFolder, true, buildPath, buildProperties, includes)
+	coreUnit, err := plan.AddFiles(coreFolder, true, buildPath, buildProperties, includes)
 	if err != nil {
 		return nil, nil, errors.WithStack(err)
 	}
+	if err := plan.Run(ctx); err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	coreObjectFiles := coreUnit.ObjectFiles()
 
 	archiveFile, err := builder_utils.ArchiveCompiledFiles(ctx, buildPath, paths.New("core.a"), coreObjectFiles, buildProperties)
 	if err != nil {
@@ -118,35 +165,88 @@ func compileCore(ctx *types.Context, buildPath *paths.Path, buildCachePath *path
 	}
 
 	// archive core.a
-	if targetArchivedCore != nil && !ctx.OnlyUpdateCompilationDatabase {
-		err := archiveFile.CopyTo(targetArchivedCore)
+	if coreCache != nil && !ctx.OnlyUpdateCompilationDatabase {
+		targetArchivedCore, err := coreCache.Put(coreCacheKey, archiveFile)
 		if ctx.Verbose {
 			if err == nil {
 				logger.Println(constants.LOG_LEVEL_INFO, constants.MSG_ARCHIVING_CORE_CACHE, targetArchivedCore)
 			} else if os.IsNotExist(err) {
 				logger.Println(constants.LOG_LEVEL_INFO, constants.MSG_CORE_CACHE_UNAVAILABLE, ctx.ActualPlatform)
 			} else {
-				logger.Println(constants.LOG_LEVEL_INFO, constants.MSG_ERROR_ARCHIVING_CORE_CACHE, targetArchivedCore, err)
+				logger.Println(constants.LOG_LEVEL_INFO, constants.MSG_ERROR_ARCHIVING_CORE_CACHE, coreCache.ArchivePath(coreCacheKey), err)
 			}
 		}
+		if ctx.RemoteBuildCache != nil {
+			if err := storeCoreArchiveInRemoteCache(ctx, archiveFile, buildProperties, coreFolder, variantFolder); err != nil {
+				logger.Println(constants.LOG_LEVEL_WARN, constants.MSG_REMOTE_CACHE_ERROR, err)
+			}
+		}
+	}
+
+	return archiveFile, variantObjectFiles(), nil
+}
+
+// GetCachedCoreArchiveKey returns the key used to store the compiled core in
+// the global core cache. The key changes if any of the FQBN (including menu
+// options), the compiler flags or the content of the core and variant sources
+// changes.
+func GetCachedCoreArchiveKey(buildProperties *properties.Map, coreFolder, variantFolder *paths.Path) (string, error) {
+	return coreArchiveKey(buildProperties, coreFolder, variantFolder, func(s string) string { return s })
+}
+
+// getRemoteCoreArchiveKey returns the key used to store the compiled core in
+// the remote cache, the paths that change between machines are normalized
+func getRemoteCoreArchiveKey(ctx *types.Context, buildProperties *properties.Map, coreFolder, variantFolder *paths.Path) (string, error) {
+	return coreArchiveKey(buildProperties, coreFolder, variantFolder, ctx.RemoteBuildCache.Normalize)
+}
+
+func fetchCoreArchiveFromRemoteCache(ctx *types.Context, buildPath *paths.Path, buildProperties *properties.Map, coreFolder, variantFolder *paths.Path) (*paths.Path, error) {
+	key, err := getRemoteCoreArchiveKey(ctx, buildProperties, coreFolder, variantFolder)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	// The core entry holds the hash of the archive, stored as an object
+	hash, err := ctx.RemoteBuildCache.Get(builder.RemoteCacheCore, key)
+	if err != nil || hash == nil {
+		return nil, err
+	}
+	data, err := ctx.RemoteBuildCache.GetObject(string(hash))
+	if err != nil || data == nil {
+		return nil, err
+	}
+	archive := buildPath.Join("core.a")
+	if err := archive.WriteFile(data); err != nil {
+		return nil, errors.WithStack(err)
 	}
+	return archive, nil
+}
 
-	return archiveFile, variantObjectFiles, nil
+func storeCoreArchiveInRemoteCache(ctx *types.Context, archive *paths.Path, buildProperties *properties.Map, coreFolder, variantFolder *paths.Path) error {
+	key, err := getRemoteCoreArchiveKey(ctx, buildProperties, coreFolder, variantFolder)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	data, err := archive.ReadFile()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	hash, err := ctx.RemoteBuildCache.PutObject(data)
+	if err != nil {
+		return err
+	}
+	return ctx.RemoteBuildCache.Put(builder.RemoteCacheCore, key, []byte(hash))
 }
 
-// GetCachedCoreArchiveFileName returns the filename to be used to store
-// the global cached core.a.
-func GetCachedCoreArchiveFileName(fqbn string, optimizationFlags string, coreFolder *paths.Path) string {
-	fqbnToUnderscore := strings.Replace(fqbn, ":", "_", -1)
-	fqbnToUnderscore = strings.Replace(fqbnToUnderscore, "=", "_", -1)
-	if absCoreFolder, err := coreFolder.Abs(); err == nil {
-		coreFolder = absCoreFolder
-	} // silently continue if absolute path can't be detected
-	hash := utils.MD5Sum([]byte(coreFolder.String() + optimizationFlags))
-	realName := "core_" + fqbnToUnderscore + "_" + hash + ".a"
-	if len(realName) > 100 {
-		// avoid really long names, simply hash the final part
-		realName = "core_" + utils.MD5Sum([]byte(fqbnToUnderscore+"_"+hash)) + ".a"
-	}
-	return realName
+func coreArchiveKey(buildProperties *properties.Map, coreFolder, variantFolder *paths.Path, normalize func(string) string) (string, error) {
+	// The recipes are expanded without the sketch specific properties, so
+	// that the same key is obtained for every sketch
+	props := buildProperties.Clone()
+	for _, key := range []string{"build.path", "build.project_name", "build.source.path"} {
+		props.Remove(key)
+	}
+	recipes := []string{}
+	for _, recipe := range []string{constants.RECIPE_S_PATTERN, constants.RECIPE_C_PATTERN, constants.RECIPE_CPP_PATTERN, constants.RECIPE_AR_PATTERN} {
+		recipes = append(recipes, normalize(props.ExpandPropsInString(props.Get(recipe))))
+	}
+	return builder.CoreCacheKey(buildProperties.Get(constants.BUILD_PROPERTIES_FQBN), recipes, coreFolder, variantFolder)
 }
@@ -36,7 +36,7 @@ type LibrariesBuilder struct{}
 
 func (s *LibrariesBuilder) Run(ctx *types.Context) error {
 	librariesBuildPath := ctx.LibrariesBuildPath
-	buildProperties := ctx.BuildProperties
+	buildProperties := builder_utils.WithWarningsLevel(ctx.BuildProperties, ctx.LibrariesWarningsLevel)
 	includes := utils.Map(ctx.IncludeFolders.AsStrings(), utils.WrapWithHyphenI)
 	libs := ctx.ImportedLibraries
 
@@ -110,26 +110,47 @@ func findExpectedPrecompiledLibFolder(ctx *types.Context, library *libraries.Lib
 	return nil
 }
 
+// libraryCompilation keeps track of the object files of a library while
+// its sources are compiled as part of a CompilationPlan
+type libraryCompilation struct {
+	library     *libraries.Library
+	buildPath   *paths.Path
+	objectFiles paths.PathList
+	units       []*builder_utils.CompilationUnit
+}
+
 func compileLibraries(ctx *types.Context, libraries libraries.List, buildPath *paths.Path, buildProperties *properties.Map, includes []string) (paths.PathList, error) {
-	ctx.Progress.AddSubSteps(len(libraries))
-	defer ctx.Progress.RemoveSubSteps()
+	// The sources of all the libraries are compiled together, this way the
+	// parallel jobs are not wasted on libraries made of a few files only.
+	plan := builder_utils.NewCompilationPlan()
+	compilations := []*libraryCompilation{}
+	for _, library := range libraries {
+		compilation, err := planLibraryCompilation(ctx, plan, library, buildPath, buildProperties, includes)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		compilations = append(compilations, compilation)
+	}
 
+	if err := plan.Run(ctx); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	// Object files and archives are collected following the libraries
+	// order, to keep the link command deterministic
 	objectFiles := paths.NewPathList()
-	for _, library := range libraries {
-		libraryObjectFiles, err := compileLibrary(ctx, library, buildPath, buildProperties, includes)
+	for _, compilation := range compilations {
+		libraryObjectFiles, err := compilation.finalize(ctx, buildProperties)
 		if err != nil {
 			return nil, errors.WithStack(err)
 		}
 		objectFiles.AddAll(libraryObjectFiles)
-
-		ctx.Progress.CompleteStep()
-		builder_utils.PrintProgressIfProgressEnabledAndMachineLogger(ctx)
 	}
 
 	return objectFiles, nil
 }
 
-func compileLibrary(ctx *types.Context, library *libraries.Library, buildPath *paths.Path, buildProperties *properties.Map, includes []string) (paths.PathList, error) {
+func planLibraryCompilation(ctx *types.Context, plan *builder_utils.CompilationPlan, library *libraries.Library, buildPath *paths.Path, buildProperties *properties.Map, includes []string) (*libraryCompilation, error) {
 	logger := ctx.GetLogger()
 	if ctx.Verbose {
 		logger.Println(constants.LOG_LEVEL_INFO, "Compiling library \"{0}\"", library.Name)
@@ -140,7 +161,11 @@ func compileLibrary(ctx *types.Context, library *libraries.Library, buildPath *p
 		return nil, errors.WithStack(err)
 	}
 
-	objectFiles := paths.NewPathList()
+	compilation := &libraryCompilation{
+		library:     library,
+		buildPath:   libraryBuildPath,
+		objectFiles: paths.NewPathList(),
+	}
 
 	if library.Precompiled {
 		coreSupportPrecompiled := ctx.BuildProperties.ContainsKey("compiler.libraries.ldflags")
@@ -168,8 +193,9 @@ func compileLibrary(ctx *types.Context, library *libraries.Library, buildPath *p
 				}
 			}
 
-			currLDFlags := ctx.BuildProperties.Get("compiler.libraries.ldflags")
-			ctx.BuildProperties.Set("compiler.libraries.ldflags", currLDFlags+" \"-L"+precompiledPath.String()+"\" "+libsCmd+" ")
+			// The build properties are shared with the sketch and core builders
+			// running in parallel, the Linker adds the flags to them
+			ctx.LibrariesLDFlags += " \"-L" + precompiledPath.String() + "\" " + libsCmd + " "
 
 			// TODO: This codepath is just taken for .a with unusual names that would
 			// be ignored by -L / -l methods.
@@ -178,48 +204,62 @@ func compileLibrary(ctx *types.Context, library *libraries.Library, buildPath *p
 			staticLibs.FilterSuffix(".a")
 			for _, lib := range staticLibs {
 				if !strings.HasPrefix(lib.Base(), "lib") {
-					objectFiles.Add(lib)
+					compilation.objectFiles.Add(lib)
 				}
 			}
 
 			if library.PrecompiledWithSources {
-				return objectFiles, nil
+				return compilation, nil
 			}
 		}
 	}
 
 	if library.Layout == libraries.RecursiveLayout {
-		libObjectFiles, err := builder_utils.CompileFilesRecursive(ctx, library.SourceDir, libraryBuildPath, buildProperties, includes)
+		unit, err := plan.AddFilesRecursive(library.SourceDir, libraryBuildPath, buildProperties, includes)
 		if err != nil {
 			return nil, errors.WithStack(err)
 		}
-		if library.DotALinkage {
-			archiveFile, err := builder_utils.ArchiveCompiledFiles(ctx, libraryBuildPath, paths.New(library.Name+".a"), libObjectFiles, buildProperties)
-			if err != nil {
-				return nil, errors.WithStack(err)
-			}
-			objectFiles.Add(archiveFile)
-		} else {
-			objectFiles.AddAll(libObjectFiles)
-		}
+		compilation.units = append(compilation.units, unit)
 	} else {
 		if library.UtilityDir != nil {
 			includes = append(includes, utils.WrapWithHyphenI(library.UtilityDir.String()))
 		}
-		libObjectFiles, err := builder_utils.CompileFiles(ctx, library.SourceDir, false, libraryBuildPath, buildProperties, includes)
+		unit, err := plan.AddFiles(library.SourceDir, false, libraryBuildPath, buildProperties, includes)
 		if err != nil {
 			return nil, errors.WithStack(err)
 		}
-		objectFiles.AddAll(libObjectFiles)
+		compilation.units = append(compilation.units, unit)
 
 		if library.UtilityDir != nil {
 			utilityBuildPath := libraryBuildPath.Join("utility")
-			utilityObjectFiles, err := builder_utils.CompileFiles(ctx, library.UtilityDir, false, utilityBuildPath, buildProperties, includes)
+			utilityUnit, err := plan.AddFiles(library.UtilityDir, false, utilityBuildPath, buildProperties, includes)
 			if err != nil {
 				return nil, errors.WithStack(err)
 			}
-			objectFiles.AddAll(utilityObjectFiles)
+			compilation.units = append(compilation.units, utilityUnit)
+		}
+	}
+
+	return compilation, nil
+}
+
+// finalize returns the object files of the library, once the CompilationPlan
+// has been run. Libraries with dot_a_linkage are archived at this stage.
+func (c *libraryCompilation) finalize(ctx *types.Context, buildProperties *properties.Map) (paths.PathList, error) {
+	objectFiles := c.objectFiles.Clone()
+	compiledObjectFiles := paths.NewPathList()
+	for _, unit := range c.units {
+		compiledObjectFiles.AddAll(unit.ObjectFiles())
+	}
+
+	if c.library.Layout == libraries.RecursiveLayout && c.library.DotALinkage {
+		archiveFile, err := builder_utils.ArchiveCompiledFiles(ctx, c.buildPath, paths.New(c.library.Name+".a"), compiledObjectFiles, buildProperties)
+		if err != nil {
+			return nil, errors.WithStack(err)
 		}
+		objectFiles.Add(archiveFile)
+	} else {
+		objectFiles.AddAll(compiledObjectFiles)
 	}
 
 	return objectFiles, nil
@@ -30,6 +30,12 @@ import (
 type Linker struct{}
 
 func (s *Linker) Run(ctx *types.Context) error {
+	if ctx.LibrariesLDFlags != "" {
+		currLDFlags := ctx.BuildProperties.Get("compiler.libraries.ldflags")
+		ctx.BuildProperties.Set("compiler.libraries.ldflags", currLDFlags+ctx.LibrariesLDFlags)
+		ctx.LibrariesLDFlags = ""
+	}
+
 	if ctx.OnlyUpdateCompilationDatabase {
 		if ctx.Verbose {
 			ctx.GetLogger().Println("info", "Skip linking of final executable.")
@@ -96,6 +102,9 @@ func link(ctx *types.Context, objectFiles paths.PathList, coreDotARelPath *paths
 			if err != nil {
 				return errors.WithStack(err)
 			}
+			if ctx.BuildScript != nil {
+				ctx.BuildScript.Add(command, paths.NewPathList(object.String()), paths.NewPathList(archive.String()))
+			}
 
 			if _, _, err := utils.ExecCommand(ctx, command, utils.ShowIfVerbose /* stdout */, utils.Show /* stderr */); err != nil {
 				return errors.WithStack(err)
@@ -117,6 +126,12 @@ func link(ctx *types.Context, objectFiles paths.PathList, coreDotARelPath *paths
 	if err != nil {
 		return err
 	}
+	if ctx.BuildScript != nil {
+		inputs := objectFiles.Clone()
+		inputs.Add(coreArchiveFilePath)
+		elf := paths.New(properties.Get("build.path"), properties.Get("build.project_name")+".elf")
+		ctx.BuildScript.Add(command, inputs, paths.NewPathList(elf.String()))
+	}
 
 	_, _, err = utils.ExecCommand(ctx, command, utils.ShowIfVerbose /* stdout */, utils.Show /* stderr */)
 	return err
@@ -16,9 +16,11 @@
 package phases
 
 import (
+	"fmt"
 	"regexp"
 	"strconv"
 
+	"github.com/arduino/arduino-cli/arduino/builder"
 	"github.com/arduino/arduino-cli/legacy/builder/builder_utils"
 	"github.com/arduino/arduino-cli/legacy/builder/constants"
 	"github.com/arduino/arduino-cli/legacy/builder/types"
@@ -59,6 +61,9 @@ func checkSize(ctx *types.Context, buildProperties *properties.Map) error {
 	maxDataSizeString := properties.Get(constants.PROPERTY_UPLOAD_MAX_DATA_SIZE)
 
 	if maxTextSizeString == "" {
+		if ctx.MaxFlashUsage != nil || ctx.MaxRAMUsage != nil {
+			return errors.New("the board doesn't define the maximum sketch size, the memory usage limits can't be checked")
+		}
 		return nil
 	}
 
@@ -107,12 +112,24 @@ func checkSize(ctx *types.Context, buildProperties *properties.Map) error {
 
 	if textSize > maxTextSize {
 		logger.Println(constants.LOG_LEVEL_ERROR, constants.MSG_SIZER_TEXT_TOO_BIG)
-		return errors.New("text section exceeds available space in board")
+		return &builder.SizeLimitError{Message: "text section exceeds available space in board"}
 	}
 
 	if maxDataSize > 0 && dataSize > maxDataSize {
 		logger.Println(constants.LOG_LEVEL_ERROR, constants.MSG_SIZER_DATA_TOO_BIG)
-		return errors.New("data section exceeds available space in board")
+		return &builder.SizeLimitError{Message: "data section exceeds available space in board"}
+	}
+
+	if err := checkSizeLimit("flash", textSize, maxTextSize, ctx.MaxFlashUsage); err != nil {
+		return err
+	}
+	if ctx.MaxRAMUsage != nil {
+		if dataSize < 0 {
+			return errors.New("the RAM usage of the sketch is unknown, the RAM usage limit can't be checked")
+		}
+		if err := checkSizeLimit("RAM", dataSize, maxDataSize, ctx.MaxRAMUsage); err != nil {
+			return err
+		}
 	}
 
 	if properties.Get(constants.PROPERTY_WARN_DATA_PERCENT) != "" {
@@ -128,6 +145,24 @@ func checkSize(ctx *types.Context, buildProperties *properties.Map) error {
 	return nil
 }
 
+// checkSizeLimit returns a SizeLimitError if size exceeds the limit, the
+// available memory is used to compute the percentage limits
+func checkSizeLimit(memory string, size int, available int, limit *builder.SizeLimit) error {
+	if limit == nil {
+		return nil
+	}
+	maxSize, err := limit.MaxSize(available)
+	if err != nil {
+		return err
+	}
+	if size > maxSize {
+		return &builder.SizeLimitError{
+			Message: fmt.Sprintf("%s usage of %d bytes exceeds the limit of %d bytes", memory, size, maxSize),
+		}
+	}
+	return nil
+}
+
 func execSizeRecipe(ctx *types.Context, properties *properties.Map) (textSize int, dataSize int, eepromSize int, resErr error) {
 	command, err := builder_utils.PrepareCommandForRecipe(properties, constants.RECIPE_SIZE_PATTERN, false)
 	if err != nil {
@@ -26,26 +26,35 @@ type SketchBuilder struct{}
 
 func (s *SketchBuilder) Run(ctx *types.Context) error {
 	sketchBuildPath := ctx.SketchBuildPath
-	buildProperties := ctx.BuildProperties
+	buildProperties := builder_utils.WithWarningsLevel(ctx.BuildProperties, ctx.WarningsLevel)
 	includes := utils.Map(ctx.IncludeFolders.AsStrings(), utils.WrapWithHyphenI)
 
 	if err := sketchBuildPath.MkdirAll(); err != nil {
 		return errors.WithStack(err)
 	}
 
-	objectFiles, err := builder_utils.CompileFiles(ctx, sketchBuildPath, false, sketchBuildPath, buildProperties, includes)
+	// The sketch folder and its "src/" subdirectory (compiled recursively)
+	// are compiled together to make better use of the parallel jobs
+	plan := builder_utils.NewCompilationPlan()
+	sketchUnit, err := plan.AddFiles(sketchBuildPath, false, sketchBuildPath, buildProperties, includes)
 	if err != nil {
 		return errors.WithStack(err)
 	}
-
-	// The "src/" subdirectory of a sketch is compiled recursively
+	var srcUnit *builder_utils.CompilationUnit
 	sketchSrcPath := sketchBuildPath.Join("src")
 	if sketchSrcPath.IsDir() {
-		srcObjectFiles, err := builder_utils.CompileFiles(ctx, sketchSrcPath, true, sketchSrcPath, buildProperties, includes)
+		srcUnit, err = plan.AddFiles(sketchSrcPath, true, sketchSrcPath, buildProperties, includes)
 		if err != nil {
 			return errors.WithStack(err)
 		}
-		objectFiles.AddAll(srcObjectFiles)
+	}
+	if err := plan.Run(ctx); err != nil {
+		return errors.WithStack(err)
+	}
+
+	objectFiles := sketchUnit.ObjectFiles()
+	if srcUnit != nil {
+		objectFiles.AddAll(srcUnit.ObjectFiles())
 	}
 
 	ctx.SketchObjectFiles = objectFiles
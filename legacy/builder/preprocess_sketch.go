@@ -115,6 +115,9 @@ func (s *ArduinoPreprocessorRunner) Run(ctx *types.Context) error {
 		command.Dir = filepath.VolumeName(command.Args[0]) + "/"
 		//command.Args[0], _ = filepath.Rel(command.Dir, command.Args[0])
 	}
+	if ctx.Container != nil {
+		command = ctx.Container.Wrap(command)
+	}
 
 	verbose := ctx.Verbose
 	if verbose {
@@ -0,0 +1,56 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"github.com/arduino/arduino-cli/legacy/builder/constants"
+	"github.com/arduino/arduino-cli/legacy/builder/types"
+)
+
+// PrintPropertiesTrace prints where the values of the traced build
+// properties come from, followed by the properties they reference and by
+// their expanded value
+type PrintPropertiesTrace struct{}
+
+func (s *PrintPropertiesTrace) Run(ctx *types.Context) error {
+	trace := ctx.PropertiesTrace
+	if trace == nil {
+		return nil
+	}
+	logger := ctx.GetLogger()
+	buildProperties := ctx.BuildProperties
+	for _, key := range trace.Keys {
+		logger.Println(constants.LOG_LEVEL_INFO, constants.MSG_PROPERTY_TRACE, key)
+		changes := trace.KeyChanges(key)
+		if len(changes) == 0 {
+			logger.Println(constants.LOG_LEVEL_INFO, constants.MSG_PROPERTY_TRACE_UNDEFINED)
+			continue
+		}
+		for _, change := range changes {
+			logger.Println(constants.LOG_LEVEL_INFO, constants.MSG_PROPERTY_TRACE_CHANGE, change.Source, change.Value)
+		}
+		for _, ref := range types.References(buildProperties, key) {
+			value, ok := buildProperties.GetOk(ref)
+			if !ok {
+				logger.Println(constants.LOG_LEVEL_INFO, constants.MSG_PROPERTY_TRACE_REFERENCE_UNDEFINED, "{"+ref+"}")
+				continue
+			}
+			logger.Println(constants.LOG_LEVEL_INFO, constants.MSG_PROPERTY_TRACE_REFERENCE, "{"+ref+"}", value)
+		}
+		logger.Println(constants.LOG_LEVEL_INFO, constants.MSG_PROPERTY_TRACE_EXPANDED, buildProperties.ExpandPropsInString(buildProperties.Get(key)))
+	}
+	return nil
+}
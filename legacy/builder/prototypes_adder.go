@@ -92,11 +92,30 @@ func joinPrototypes(prototypes []*types.Prototype) string {
 			prototypeParts = append(prototypeParts, proto.Modifiers)
 		}
 		prototypeParts = append(prototypeParts, proto.Prototype)
-		prototypesSlice = append(prototypesSlice, strings.Join(prototypeParts, " "))
+		prototypesSlice = append(prototypesSlice, wrapInNamespace(strings.Join(prototypeParts, " "), proto.Namespace))
 	}
 	return strings.Join(prototypesSlice, "\n")
 }
 
+// wrapInNamespace declares the prototype in its namespace, for example
+// "namespace a { namespace b { void foo(); } }" for the namespace "a::b".
+// The anonymous namespaces are reported by ctags as "__anon" followed by a
+// number.
+func wrapInNamespace(prototype string, namespace string) string {
+	if namespace == "" {
+		return prototype
+	}
+	names := strings.Split(namespace, "::")
+	for i := len(names) - 1; i >= 0; i-- {
+		name := names[i]
+		if strings.HasPrefix(name, "__anon") {
+			name = ""
+		}
+		prototype = strings.TrimSpace("namespace "+name) + " { " + prototype + " }"
+	}
+	return prototype
+}
+
 func signatureContainsaDefaultArg(proto *types.Prototype) bool {
 	return strings.Contains(proto.Prototype, "=")
 }
@@ -0,0 +1,122 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/arduino/arduino-cli/legacy/builder/builder_utils"
+	"github.com/arduino/arduino-cli/legacy/builder/types"
+	"github.com/arduino/go-paths-helper"
+	"github.com/pkg/errors"
+)
+
+// prototypesCache stores the prototypes generated for a sketch, so they are
+// not generated again if the sketch sources and the headers they include
+// didn't change.
+type prototypesCache struct {
+	Key        string
+	Includes   []prototypesCacheInclude
+	Line       int
+	Prototypes []*types.Prototype
+}
+
+// prototypesCacheInclude is a header included by the sketch, e.g. from a
+// library, with the hash of its content
+type prototypesCacheInclude struct {
+	Path string
+	Hash string
+}
+
+// prototypesCacheKey hashes everything the generated prototypes depend on:
+// the merged sketch source, the other files of the sketch that it may
+// include, the include path and the selected preprocessor. The build
+// options are not part of the key, the build path is wiped when they change.
+// The headers included from outside the sketch are checked separately, since
+// their list is known only after running the preprocessor.
+func prototypesCacheKey(ctx *types.Context) (string, error) {
+	hash := sha256.New()
+	fmt.Fprintln(hash, ctx.Preprocessor)
+	fmt.Fprintln(hash, ctx.Source)
+	for _, folder := range ctx.IncludeFolders {
+		fmt.Fprintln(hash, folder)
+	}
+	for _, file := range ctx.Sketch.AdditionalFiles {
+		data, err := file.Name.ReadFile()
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		fmt.Fprintln(hash, file.Name)
+		hash.Write(data)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// prototypesCacheIncludes returns the files included by the preprocessed
+// sketch, found in its line markers, with the hashes of their content. The
+// merged sketch source is skipped, it's already part of the key.
+func prototypesCacheIncludes(ctx *types.Context, preprocessed string, source *paths.Path) ([]prototypesCacheInclude, error) {
+	includes := []prototypesCacheInclude{}
+	found := map[string]bool{source.String(): true}
+	scanner := bufio.NewScanner(strings.NewReader(preprocessed))
+	for scanner.Scan() {
+		file := parseLineMarker(scanner.Text())
+		// skip the <built-in> and <command-line> markers
+		if file == nil || strings.HasPrefix(file.String(), "<") || found[file.String()] {
+			continue
+		}
+		found[file.String()] = true
+		hash, err := builder_utils.FileHash(ctx, file)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		includes = append(includes, prototypesCacheInclude{Path: file.String(), Hash: hash})
+	}
+	return includes, errors.WithStack(scanner.Err())
+}
+
+// readPrototypesCache returns the cache stored in the given file, or nil if
+// it's missing, unreadable, was generated for a different key or any of the
+// included headers changed.
+func readPrototypesCache(ctx *types.Context, path *paths.Path, key string) *prototypesCache {
+	data, err := path.ReadFile()
+	if err != nil {
+		return nil
+	}
+	cache := &prototypesCache{}
+	if err := json.Unmarshal(data, cache); err != nil || cache.Key != key {
+		return nil
+	}
+	for _, include := range cache.Includes {
+		if hash, err := builder_utils.FileHash(ctx, paths.New(include.Path)); err != nil || hash != include.Hash {
+			return nil
+		}
+	}
+	return cache
+}
+
+func writePrototypesCache(path *paths.Path, cache *prototypesCache) error {
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(path.WriteFile(data))
+}
@@ -60,6 +60,9 @@ func (s *RecipeByPrefixSuffixRunner) Run(ctx *types.Context) error {
 			return nil
 		}
 
+		if ctx.BuildScript != nil {
+			ctx.BuildScript.Add(command, nil, nil)
+		}
 		_, _, err = utils.ExecCommand(ctx, command, utils.ShowIfVerbose /* stdout */, utils.Show /* stderr */)
 		if err != nil {
 			return errors.WithStack(err)
@@ -21,9 +21,10 @@ import (
 	"github.com/arduino/arduino-cli/arduino/libraries"
 	"github.com/arduino/arduino-cli/legacy/builder/constants"
 	"github.com/arduino/arduino-cli/legacy/builder/types"
+	paths "github.com/arduino/go-paths-helper"
 )
 
-func ResolveLibrary(ctx *types.Context, header string) *libraries.Library {
+func ResolveLibrary(ctx *types.Context, sourcePath *paths.Path, header string) *libraries.Library {
 	resolver := ctx.LibrariesResolver
 	importedLibraries := ctx.ImportedLibraries
 
@@ -46,7 +47,13 @@ func ResolveLibrary(ctx *types.Context, header string) *libraries.Library {
 		}
 	}
 
-	selected := resolver.ResolveFor(header, ctx.TargetPlatform.Platform.Architecture)
+	resolution := resolver.Resolve(header, ctx.TargetPlatform.Platform.Architecture)
+	ctx.LibrariesResolutionTrace = append(ctx.LibrariesResolutionTrace, &types.LibraryResolutionTrace{
+		SourceFile: sourcePath,
+		Include:    header,
+		Resolution: resolution,
+	})
+	selected := resolution.Selected
 	if alreadyImported := importedLibraries.FindByName(selected.Name); alreadyImported != nil {
 		// Certain libraries might have the same name but be different.
 		// This usually happens when the user includes two or more custom libraries that have
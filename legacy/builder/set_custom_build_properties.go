@@ -16,21 +16,37 @@
 package builder
 
 import (
+	"strings"
+
 	"github.com/arduino/arduino-cli/legacy/builder/types"
 	"github.com/arduino/go-properties-orderedmap"
 	"github.com/pkg/errors"
 )
 
+// SetCustomBuildProperties applies the build properties passed by the user,
+// in the KEY=VALUE form to override a property or in the KEY+=VALUE form to
+// append VALUE, separated by a space, to the current value of the property.
 type SetCustomBuildProperties struct{}
 
 func (s *SetCustomBuildProperties) Run(ctx *types.Context) error {
 	buildProperties := ctx.BuildProperties
-	customBuildProperties, err := properties.LoadFromSlice(ctx.CustomBuildProperties)
-	if err != nil {
-		return errors.WithStack(err)
+	for _, prop := range ctx.CustomBuildProperties {
+		if eq := strings.Index(prop, "="); eq > 0 && prop[eq-1] == '+' {
+			key := strings.TrimSpace(prop[:eq-1])
+			value := strings.TrimSpace(prop[eq+1:])
+			if current := buildProperties.Get(key); current != "" {
+				value = current + " " + value
+			}
+			buildProperties.Set(key, value)
+			ctx.PropertiesTrace.Record(buildProperties, "--build-property "+prop)
+			continue
+		}
+		customBuildProperty, err := properties.LoadFromSlice([]string{prop})
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		buildProperties.Merge(customBuildProperty)
+		ctx.PropertiesTrace.Record(buildProperties, "--build-property "+prop)
 	}
-
-	buildProperties.Merge(customBuildProperties)
-
 	return nil
 }
@@ -22,10 +22,13 @@ import (
 	"strings"
 	"time"
 
+	bldr "github.com/arduino/arduino-cli/arduino/builder"
 	"github.com/arduino/arduino-cli/arduino/cores"
 	"github.com/arduino/arduino-cli/legacy/builder/types"
+	"github.com/arduino/go-paths-helper"
 	properties "github.com/arduino/go-properties-orderedmap"
 	timeutils "github.com/arduino/go-timeutils"
+	"github.com/pkg/errors"
 )
 
 type SetupBuildProperties struct{}
@@ -41,6 +44,13 @@ func (s *SetupBuildProperties) Run(ctx *types.Context) error {
 	buildProperties.Merge(actualPlatform.Properties)
 	buildProperties.Merge(targetPlatform.Properties)
 	buildProperties.Merge(targetBoard.Properties)
+	if ctx.PropertiesTrace != nil {
+		tracePlatformProperties(ctx, actualPlatform)
+		if targetPlatform != actualPlatform {
+			tracePlatformProperties(ctx, targetPlatform)
+		}
+		traceBoardProperties(ctx, targetPlatform, targetBoard)
+	}
 
 	if ctx.BuildPath != nil {
 		buildProperties.SetPath("build.path", ctx.BuildPath)
@@ -68,8 +78,13 @@ func (s *SetupBuildProperties) Run(ctx *types.Context) error {
 	buildProperties.Set("ide_version", ctx.ArduinoAPIVersion)
 	buildProperties.Set("runtime.os", properties.GetOSSuffix())
 	buildProperties.Set("build.library_discovery_phase", "0")
+	ctx.PropertiesTrace.Record(buildProperties, "the builder")
 
-	if ctx.OptimizeForDebug {
+	if ctx.Optimization != "" {
+		if _, err := bldr.ApplyOptimizationPreset(buildProperties, ctx.Optimization); err != nil {
+			return errors.WithStack(err)
+		}
+	} else if ctx.OptimizeForDebug {
 		if buildProperties.ContainsKey("compiler.optimization_flags.debug") {
 			buildProperties.Set("compiler.optimization_flags", buildProperties.Get("compiler.optimization_flags.debug"))
 		}
@@ -79,6 +94,7 @@ func (s *SetupBuildProperties) Run(ctx *types.Context) error {
 		}
 	}
 	ctx.OptimizationFlags = buildProperties.Get("compiler.optimization_flags")
+	ctx.PropertiesTrace.Record(buildProperties, "the optimization settings")
 
 	variant := buildProperties.Get("build.variant")
 	if variant == "" {
@@ -124,9 +140,56 @@ func (s *SetupBuildProperties) Run(ctx *types.Context) error {
 	buildProperties.Set("extra.time.zone", strconv.Itoa(timeutils.TimezoneOffsetNoDST(now)))
 	buildProperties.Set("extra.time.dst", strconv.Itoa(timeutils.DaylightSavingsOffset(now)))
 
+	ctx.PropertiesTrace.Record(buildProperties, "the builder")
+
 	buildProperties.Merge(ctx.PackageManager.CustomGlobalProperties)
+	ctx.PropertiesTrace.Record(ctx.PackageManager.CustomGlobalProperties, "the global platform.txt")
 
 	ctx.BuildProperties = buildProperties
 
 	return nil
 }
+
+// tracePlatformProperties records the traced properties defined by the
+// platform.txt and platform.local.txt files of the platform. The values
+// that differ from the ones of the files have been changed by the rewrite
+// rules of platform.keys.rewrite.txt.
+func tracePlatformProperties(ctx *types.Context, platform *cores.PlatformRelease) {
+	for _, file := range []string{"platform.txt", "platform.local.txt"} {
+		path := platform.InstallDir.Join(file)
+		if props, err := properties.SafeLoadFromPath(path); err == nil {
+			ctx.PropertiesTrace.Record(props, path.String())
+		}
+	}
+	ctx.PropertiesTrace.Record(platform.Properties, "the rewrite rules of platform.keys.rewrite.txt")
+}
+
+// traceBoardProperties records the traced properties defined for the board
+// by the boards.txt and boards.local.txt files of the platform, including
+// the ones of the selected menu options
+func traceBoardProperties(ctx *types.Context, platform *cores.PlatformRelease, board *cores.Board) {
+	boardsFiles := []*properties.Map{}
+	boardsPaths := []*paths.Path{}
+	for _, file := range []string{"boards.txt", "boards.local.txt"} {
+		path := platform.InstallDir.Join(file)
+		if props, err := properties.SafeLoadFromPath(path); err == nil {
+			boardsFiles = append(boardsFiles, props.SubTree(board.BoardID))
+			boardsPaths = append(boardsPaths, path)
+		}
+	}
+	for i, props := range boardsFiles {
+		ctx.PropertiesTrace.Record(props, boardsPaths[i].String())
+	}
+
+	menu := board.Properties.SubTree("menu")
+	for _, option := range menu.FirstLevelKeys() {
+		value, ok := ctx.FQBN.Configs.GetOk(option)
+		if !ok {
+			value = menu.SubTree(option).FirstLevelKeys()[0]
+		}
+		for i, props := range boardsFiles {
+			optionProps := props.SubTree("menu").SubTree(option).SubTree(value)
+			ctx.PropertiesTrace.Record(optionProps, boardsPaths[i].String()+" (menu option "+option+"="+value+")")
+		}
+	}
+}
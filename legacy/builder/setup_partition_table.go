@@ -0,0 +1,90 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"fmt"
+
+	"github.com/arduino/arduino-cli/arduino/partitions"
+	"github.com/arduino/arduino-cli/legacy/builder/types"
+	"github.com/arduino/go-paths-helper"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// SetupPartitionTable copies the partitions table given with
+// ctx.PartitionTable in the build path, replacing the one copied there by
+// the prebuild hooks of the ESP32 platforms. The upload.maximum_size
+// property is set to the size of the app partition of the table in use, so
+// the size of the sketch is checked against the partition it's uploaded to.
+type SetupPartitionTable struct{}
+
+func (s *SetupPartitionTable) Run(ctx *types.Context) error {
+	if ctx.PartitionTable == nil {
+		table := findPartitionTable(ctx)
+		if table == nil {
+			return nil
+		}
+		// The tables of the platform are not validated, they can't be fixed
+		// by the user anyway
+		parts, err := partitions.Load(table)
+		if err != nil {
+			logrus.WithError(err).Warn("Error reading partitions table")
+			return nil
+		}
+		setMaximumSize(ctx, parts)
+		return nil
+	}
+
+	parts, err := partitions.Load(ctx.PartitionTable)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := partitions.Validate(parts); err != nil {
+		return fmt.Errorf("invalid partitions table %s: %s", ctx.PartitionTable, err)
+	}
+	if err := ctx.PartitionTable.CopyTo(ctx.BuildPath.Join("partitions.csv")); err != nil {
+		return errors.WithStack(err)
+	}
+	setMaximumSize(ctx, parts)
+	return nil
+}
+
+// findPartitionTable returns the partitions table in the sketch folder or,
+// if not present, the one selected by the build.partitions property
+func findPartitionTable(ctx *types.Context) *paths.Path {
+	buildProperties := ctx.BuildProperties
+	name, ok := buildProperties.GetOk("build.partitions")
+	if !ok {
+		return nil
+	}
+	if ctx.SketchLocation != nil {
+		if table := ctx.SketchLocation.Parent().Join("partitions.csv"); table.Exist() {
+			return table
+		}
+	}
+	table := buildProperties.GetPath("runtime.platform.path").Join("tools", "partitions", name+".csv")
+	if table.NotExist() {
+		return nil
+	}
+	return table
+}
+
+func setMaximumSize(ctx *types.Context, parts []*partitions.Partition) {
+	if app := partitions.FindApp(parts); app != nil {
+		ctx.BuildProperties.Set("upload.maximum_size", fmt.Sprint(app.Size))
+	}
+}
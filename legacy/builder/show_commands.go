@@ -0,0 +1,174 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino/libraries"
+	"github.com/arduino/arduino-cli/legacy/builder/builder_utils"
+	"github.com/arduino/arduino-cli/legacy/builder/constants"
+	"github.com/arduino/arduino-cli/legacy/builder/types"
+	"github.com/arduino/arduino-cli/legacy/builder/utils"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/pkg/errors"
+)
+
+// ShowCommands prints the command lines of the recipes of the platform, in
+// the order they are run by the builder, expanded with the final build
+// properties. The libraries used by the sketch must have been detected
+// already; nothing else is run, so the properties depending on the files
+// being processed are set to the ones of the main sketch file, except for
+// the libraries whose every source file is shown.
+type ShowCommands struct{}
+
+func (s *ShowCommands) Run(ctx *types.Context) error {
+	buildProperties := builder_utils.WithWarningsLevel(ctx.BuildProperties, ctx.WarningsLevel)
+
+	includes := utils.Map(ctx.IncludeFolders.AsStrings(), utils.WrapWithHyphenI)
+	sourceFile := ctx.SketchBuildPath.Join(ctx.Sketch.MainFile.Name.Base() + ".cpp")
+	objectFile := ctx.SketchBuildPath.Join(ctx.Sketch.MainFile.Name.Base() + ".cpp.o")
+	archiveFile := ctx.CoreBuildPath.Join("core.a")
+	archiveRelPath, err := ctx.BuildPath.RelTo(archiveFile)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	sketchProperties := buildProperties.Clone()
+	sketchProperties.Set(constants.BUILD_PROPERTIES_INCLUDES, strings.Join(includes, constants.SPACE))
+	sketchProperties.SetPath(constants.BUILD_PROPERTIES_SOURCE_FILE, sourceFile)
+	sketchProperties.SetPath(constants.BUILD_PROPERTIES_OBJECT_FILE, objectFile)
+	sketchProperties.SetPath(constants.BUILD_PROPERTIES_PREPROCESSED_FILE_PATH, ctx.PreprocPath.Join(constants.FILE_CTAGS_TARGET_FOR_GCC_MINUS_E))
+	sketchProperties.Set(constants.BUILD_PROPERTIES_ARCHIVE_FILE, archiveRelPath.String())
+	sketchProperties.SetPath(constants.BUILD_PROPERTIES_ARCHIVE_FILE_PATH, archiveFile)
+	sketchProperties.Set("object_files", "\""+objectFile.String()+"\"")
+
+	hooks := func(prefix string) []string {
+		return findRecipes(sketchProperties, prefix, constants.HOOKS_PATTERN_SUFFIX)
+	}
+	// The libraries are compiled between their prebuild and postbuild hooks
+	beforeLibraries := [][]string{
+		hooks(constants.HOOKS_PREBUILD),
+		{constants.RECIPE_PREPROC_MACROS},
+		hooks(constants.HOOKS_SKETCH_PREBUILD),
+		{constants.RECIPE_S_PATTERN, constants.RECIPE_C_PATTERN, constants.RECIPE_CPP_PATTERN},
+		hooks(constants.HOOKS_SKETCH_POSTBUILD),
+		hooks(constants.HOOKS_LIBRARIES_PREBUILD),
+	}
+	afterLibraries := [][]string{
+		hooks(constants.HOOKS_LIBRARIES_POSTBUILD),
+		hooks(constants.HOOKS_CORE_PREBUILD),
+		{constants.RECIPE_AR_PATTERN},
+		hooks(constants.HOOKS_CORE_POSTBUILD),
+		hooks(constants.HOOKS_LINKING_PRELINK),
+		{constants.RECIPE_C_COMBINE_PATTERN},
+		hooks(constants.HOOKS_LINKING_POSTLINK),
+		hooks(constants.HOOKS_OBJCOPY_PREOBJCOPY),
+		hooks("recipe.objcopy."),
+		hooks(constants.HOOKS_OBJCOPY_POSTOBJCOPY),
+		{constants.RECIPE_SIZE_PATTERN},
+		hooks(constants.HOOKS_POSTBUILD),
+	}
+	logger := ctx.GetLogger()
+	show := func(recipeProperties *properties.Map, recipe, comment string) error {
+		if recipeProperties.Get(recipe) == "" {
+			return nil
+		}
+		commandLine, err := showCommandLine(recipeProperties, recipe)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		logger.UnformattedFprintln(os.Stdout, "# "+recipe+comment)
+		logger.UnformattedFprintln(os.Stdout, commandLine)
+		return nil
+	}
+	showGroups := func(groups [][]string) error {
+		for _, group := range groups {
+			for _, recipe := range group {
+				if err := show(sketchProperties, recipe, ""); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if err := showGroups(beforeLibraries); err != nil {
+		return err
+	}
+	if err := showLibrariesCommands(ctx, show); err != nil {
+		return err
+	}
+	return showGroups(afterLibraries)
+}
+
+// showLibrariesCommands prints the command lines used to compile the source
+// files of the libraries used by the sketch
+func showLibrariesCommands(ctx *types.Context, show func(*properties.Map, string, string) error) error {
+	buildProperties := builder_utils.WithWarningsLevel(ctx.BuildProperties, ctx.LibrariesWarningsLevel)
+	includes := utils.Map(ctx.IncludeFolders.AsStrings(), utils.WrapWithHyphenI)
+	for _, library := range ctx.ImportedLibraries {
+		if library.Precompiled && !library.PrecompiledWithSources {
+			continue
+		}
+		plan := builder_utils.NewCompilationPlan()
+		libraryBuildPath := ctx.LibrariesBuildPath.Join(library.Name)
+		if library.Layout == libraries.RecursiveLayout {
+			if _, err := plan.AddFilesRecursive(library.SourceDir, libraryBuildPath, buildProperties, includes); err != nil {
+				return errors.WithStack(err)
+			}
+		} else {
+			libraryIncludes := includes
+			if library.UtilityDir != nil {
+				libraryIncludes = append(append([]string{}, includes...), utils.WrapWithHyphenI(library.UtilityDir.String()))
+			}
+			if _, err := plan.AddFiles(library.SourceDir, false, libraryBuildPath, buildProperties, libraryIncludes); err != nil {
+				return errors.WithStack(err)
+			}
+			if library.UtilityDir != nil {
+				if _, err := plan.AddFiles(library.UtilityDir, false, libraryBuildPath.Join("utility"), buildProperties, libraryIncludes); err != nil {
+					return errors.WithStack(err)
+				}
+			}
+		}
+		err := plan.ForEachCommand(func(recipe string, sourceProperties *properties.Map) error {
+			return show(sourceProperties, recipe, " ("+library.Name+": "+sourceProperties.Get(constants.BUILD_PROPERTIES_SOURCE_FILE)+")")
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// showCommandLine returns the command line of the recipe, the arguments
+// containing spaces or quotes are quoted so that the way the command line
+// has been split is visible.
+func showCommandLine(buildProperties *properties.Map, recipe string) (string, error) {
+	command, err := builder_utils.PrepareCommandForRecipe(buildProperties, recipe, false)
+	if err != nil {
+		return "", err
+	}
+	args := []string{}
+	for _, arg := range command.Args {
+		if arg == "" || strings.ContainsAny(arg, " \t\"'") {
+			arg = strconv.Quote(arg)
+		}
+		args = append(args, arg)
+	}
+	return strings.Join(args, " "), nil
+}
@@ -0,0 +1,95 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"encoding/json"
+
+	bldr "github.com/arduino/arduino-cli/arduino/builder"
+	"github.com/arduino/arduino-cli/legacy/builder/constants"
+	"github.com/arduino/arduino-cli/legacy/builder/types"
+	"github.com/pkg/errors"
+)
+
+// SignBinaries signs the sketch binary with the key given with
+// ctx.SigningKey. The platform can sign it with its own tools defining the
+// recipe.sign.pattern recipes, that receive the path of the key in the
+// {sign.key_path} property. Otherwise the built-in Ed25519/ECDSA signer
+// creates the <project>.signed.bin image. In both cases an OTA manifest,
+// <project>.ota.json, describes the image to upload.
+type SignBinaries struct{}
+
+func (s *SignBinaries) Run(ctx *types.Context) error {
+	if ctx.OnlyUpdateCompilationDatabase || ctx.SigningKey == nil {
+		return nil
+	}
+
+	buildProperties := ctx.BuildProperties
+	buildProperties.SetPath("sign.key_path", ctx.SigningKey)
+	projectName := buildProperties.Get("build.project_name")
+	signedImage := ctx.BuildPath.Join(projectName + ".signed.bin")
+
+	if len(findRecipes(buildProperties, "recipe.sign.", constants.HOOKS_PATTERN_SUFFIX)) > 0 {
+		if err := (&RecipeByPrefixSuffixRunner{Prefix: "recipe.sign.", Suffix: constants.HOOKS_PATTERN_SUFFIX}).Run(ctx); err != nil {
+			return err
+		}
+		imageFile := signedImage
+		if imageFile.NotExist() {
+			// the platform tools may sign the binary in place
+			imageFile = ctx.BuildPath.Join(projectName + ".bin")
+		}
+		image, err := imageFile.ReadFile()
+		if err != nil {
+			return errors.Wrap(err, "reading the signed image")
+		}
+		manifest, err := bldr.NewOTAManifest(ctx.FQBN.String(), imageFile.Base(), image, nil, "", nil)
+		if err != nil {
+			return err
+		}
+		return writeOTAManifest(ctx, manifest)
+	}
+
+	key, algorithm, err := bldr.LoadSigningKey(ctx.SigningKey)
+	if err != nil {
+		return err
+	}
+	image, err := loadSketchImage(ctx.BuildPath, projectName, ctx.BuildProperties)
+	if err != nil {
+		return err
+	}
+	signature, err := bldr.SignImage(key, image.Data)
+	if err != nil {
+		return errors.Wrap(err, "signing the sketch binary")
+	}
+	signed := bldr.SignedImage(image.Data, signature)
+	if err := signedImage.WriteFile(signed); err != nil {
+		return errors.WithStack(err)
+	}
+	manifest, err := bldr.NewOTAManifest(ctx.FQBN.String(), signedImage.Base(), signed, key, algorithm, signature)
+	if err != nil {
+		return err
+	}
+	return writeOTAManifest(ctx, manifest)
+}
+
+func writeOTAManifest(ctx *types.Context, manifest *bldr.OTAManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	projectName := ctx.BuildProperties.Get("build.project_name")
+	return errors.WithStack(ctx.BuildPath.Join(projectName + ".ota.json").WriteFile(data))
+}
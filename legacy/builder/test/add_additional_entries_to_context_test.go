@@ -61,3 +61,14 @@ func TestAddAdditionalEntriesToContextWithBuildPath(t *testing.T) {
 
 	require.Equal(t, 0, len(ctx.LibrariesResolutionResults))
 }
+
+func TestAddAdditionalEntriesToContextLibrariesWarningsLevel(t *testing.T) {
+	ctx := &types.Context{WarningsLevel: "all"}
+	NoError(t, (&builder.AddAdditionalEntriesToContext{}).Run(ctx))
+	require.Equal(t, "all", ctx.LibrariesWarningsLevel)
+
+	ctx = &types.Context{WarningsLevel: "all", LibrariesWarningsLevel: "none"}
+	NoError(t, (&builder.AddAdditionalEntriesToContext{}).Run(ctx))
+	require.Equal(t, "all", ctx.WarningsLevel)
+	require.Equal(t, "none", ctx.LibrariesWarningsLevel)
+}
@@ -0,0 +1,150 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/arduino/arduino-cli/legacy/builder"
+	"github.com/arduino/arduino-cli/legacy/builder/types"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingCommand struct {
+	name string
+	log  *[]string
+	lock *sync.Mutex
+	err  error
+}
+
+func (c *recordingCommand) Run(ctx *types.Context) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	*c.log = append(*c.log, c.name)
+	return c.err
+}
+
+func TestBuildGraph(t *testing.T) {
+	log := []string{}
+	lock := &sync.Mutex{}
+	cmd := func(name string, err error) types.Command {
+		return &recordingCommand{name: name, log: &log, lock: lock, err: err}
+	}
+
+	newGraph := func(failure error) *builder.BuildGraph {
+		graph := builder.NewBuildGraph()
+		setup := graph.Chain(nil, cmd("setup", nil))
+		sketch := graph.Chain([]*builder.BuildNode{setup}, cmd("sketch1", nil), cmd("sketch2", failure))
+		core := graph.Chain([]*builder.BuildNode{setup}, cmd("core1", nil), cmd("core2", nil))
+		graph.Chain([]*builder.BuildNode{sketch, core}, cmd("link", nil))
+		return graph
+	}
+
+	// With one job the commands are run in the order they are added
+	ctx := &types.Context{Jobs: 1}
+	require.NoError(t, newGraph(nil).Run(ctx))
+	require.Equal(t, []string{"setup", "sketch1", "sketch2", "core1", "core2", "link"}, log)
+
+	// With many jobs the dependencies are respected
+	log = []string{}
+	ctx = &types.Context{Jobs: 4}
+	require.NoError(t, newGraph(nil).Run(ctx))
+	require.Len(t, log, 6)
+	index := map[string]int{}
+	for i, name := range log {
+		index[name] = i
+	}
+	require.Less(t, index["setup"], index["sketch1"])
+	require.Less(t, index["setup"], index["core1"])
+	require.Less(t, index["sketch1"], index["sketch2"])
+	require.Less(t, index["core1"], index["core2"])
+	require.Less(t, index["sketch2"], index["link"])
+	require.Less(t, index["core2"], index["link"])
+
+	// The first error stops the build
+	failure := errors.New("compile error")
+	log = []string{}
+	ctx = &types.Context{Jobs: 1}
+	err := newGraph(failure).Run(ctx)
+	require.Error(t, err)
+	require.Equal(t, failure.Error(), err.Error())
+	require.Equal(t, []string{"setup", "sketch1", "sketch2"}, log)
+
+	// With KeepGoing the commands not depending on the failed one are run
+	log = []string{}
+	ctx = &types.Context{Jobs: 1, KeepGoing: true}
+	require.Error(t, newGraph(failure).Run(ctx))
+	require.Equal(t, []string{"setup", "sketch1", "sketch2", "core1", "core2"}, log)
+}
+
+func TestBuildGraphProgress(t *testing.T) {
+	log := []string{}
+	lock := &sync.Mutex{}
+	graph := builder.NewBuildGraph()
+	graph.Chain(nil,
+		&recordingCommand{name: "sketch", log: &log, lock: lock},
+		&recordingCommand{name: "link", log: &log, lock: lock})
+
+	progress := []*rpc.TaskProgress{}
+	ctx := &types.Context{Jobs: 1, ProgressCB: func(p *rpc.TaskProgress) { progress = append(progress, p) }}
+	require.NoError(t, graph.Run(ctx))
+	require.Len(t, progress, 4)
+	for i, p := range progress {
+		require.Equal(t, "recordingCommand", p.GetName())
+		require.Equal(t, i%2 == 1, p.GetCompleted())
+	}
+	require.Equal(t, float32(0), progress[0].GetPercent())
+	require.Equal(t, float32(50), progress[1].GetPercent())
+	require.Equal(t, float32(50), progress[2].GetPercent())
+	require.Equal(t, float32(100), progress[3].GetPercent())
+}
+
+type nestedGraphCommand struct {
+	graph *builder.BuildGraph
+}
+
+func (c *nestedGraphCommand) Run(ctx *types.Context) error {
+	return c.graph.Run(ctx)
+}
+
+func TestBuildGraphProgressParallelChains(t *testing.T) {
+	log := []string{}
+	lock := &sync.Mutex{}
+	nested := func(commands int) types.Command {
+		graph := builder.NewBuildGraph()
+		for i := 0; i < commands; i++ {
+			graph.Chain(nil, &recordingCommand{name: "nested", log: &log, lock: lock})
+		}
+		return &nestedGraphCommand{graph: graph}
+	}
+	graph := builder.NewBuildGraph()
+	sketch := graph.Chain(nil, nested(3), nested(2))
+	core := graph.Chain(nil, nested(10))
+	graph.Chain([]*builder.BuildNode{sketch, core}, &recordingCommand{name: "link", log: &log, lock: lock})
+
+	progress := []float32{}
+	ctx := &types.Context{Jobs: 4, ProgressCB: func(p *rpc.TaskProgress) { progress = append(progress, p.GetPercent()) }}
+	require.NoError(t, graph.Run(ctx))
+	// The chains running in parallel don't make the progress go back or
+	// beyond 100%
+	for i := 1; i < len(progress); i++ {
+		require.LessOrEqual(t, progress[i-1], progress[i])
+	}
+	require.Equal(t, float32(100), progress[len(progress)-1])
+}
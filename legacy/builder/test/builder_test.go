@@ -19,7 +19,6 @@ import (
 	"os/exec"
 	"path/filepath"
 	"testing"
-	"time"
 
 	"github.com/arduino/go-paths-helper"
 
@@ -379,8 +378,9 @@ func TestBuilderCacheCoreAFile(t *testing.T) {
 
 	// Pick timestamp of cached core
 	coreFolder := paths.New("downloaded_hardware", "arduino", "avr")
-	coreFileName := phases.GetCachedCoreArchiveFileName(ctx.FQBN.String(), ctx.OptimizationFlags, coreFolder)
-	cachedCoreFile := ctx.CoreBuildCachePath.Join(coreFileName)
+	coreKey, err := phases.GetCachedCoreArchiveKey(ctx.BuildProperties, ctx.BuildProperties.GetPath("build.core.path"), ctx.BuildProperties.GetPath("build.variant.path"))
+	require.NoError(t, err)
+	cachedCoreFile := ctx.CoreBuildCachePath.Join("core_" + coreKey + ".a")
 	coreStatBefore, err := cachedCoreFile.Stat()
 	require.NoError(t, err)
 
@@ -392,17 +392,20 @@ func TestBuilderCacheCoreAFile(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, coreStatBefore.ModTime(), coreStatAfterRebuild.ModTime())
 
-	// Touch a file of the core and check if the builder invalidate the cache
-	time.Sleep(time.Second)
-	now := time.Now().Local()
-	err = coreFolder.Join("cores", "arduino", "Arduino.h").Chtimes(now, now)
+	// Change a file of the core and check if the builder invalidate the cache
+	arduinoH := coreFolder.Join("cores", "arduino", "Arduino.h")
+	arduinoHContent, err := arduinoH.ReadFile()
+	require.NoError(t, err)
+	defer arduinoH.WriteFile(arduinoHContent)
+	err = arduinoH.WriteFile(append(arduinoHContent, []byte("\n")...))
 	require.NoError(t, err)
 
 	// Run build again, to verify that the builder rebuilds core.a
 	err = bldr.Run(ctx)
 	NoError(t, err)
 
-	coreStatAfterTouch, err := cachedCoreFile.Stat()
+	newCoreKey, err := phases.GetCachedCoreArchiveKey(ctx.BuildProperties, ctx.BuildProperties.GetPath("build.core.path"), ctx.BuildProperties.GetPath("build.variant.path"))
 	require.NoError(t, err)
-	require.NotEqual(t, coreStatBefore.ModTime(), coreStatAfterTouch.ModTime())
+	require.NotEqual(t, coreKey, newCoreKey)
+	require.True(t, ctx.CoreBuildCachePath.Join("core_"+newCoreKey+".a").Exist())
 }
@@ -26,12 +26,6 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func sleep(t *testing.T) {
-	dur, err := time.ParseDuration("1s")
-	NoError(t, err)
-	time.Sleep(dur)
-}
-
 func tempFile(t *testing.T, prefix string) *paths.Path {
 	file, err := ioutil.TempFile("", prefix)
 	file.Close()
@@ -45,7 +39,7 @@ func TestObjFileIsUpToDateObjMissing(t *testing.T) {
 	sourceFile := tempFile(t, "source")
 	defer sourceFile.RemoveAll()
 
-	upToDate, err := builder_utils.ObjFileIsUpToDate(ctx, sourceFile, nil, nil)
+	upToDate, err := builder_utils.ObjFileIsUpToDate(ctx, sourceFile, nil, nil, nil)
 	NoError(t, err)
 	require.False(t, upToDate)
 }
@@ -59,93 +53,123 @@ func TestObjFileIsUpToDateDepMissing(t *testing.T) {
 	objFile := tempFile(t, "obj")
 	defer objFile.RemoveAll()
 
-	upToDate, err := builder_utils.ObjFileIsUpToDate(ctx, sourceFile, objFile, nil)
+	upToDate, err := builder_utils.ObjFileIsUpToDate(ctx, sourceFile, objFile, nil, nil)
 	NoError(t, err)
 	require.False(t, upToDate)
 }
 
-func TestObjFileIsUpToDateObjOlder(t *testing.T) {
+func TestObjFileIsUpToDateHashesMissing(t *testing.T) {
 	ctx := &types.Context{}
 
+	sourceFile := tempFile(t, "source")
+	defer sourceFile.RemoveAll()
 	objFile := tempFile(t, "obj")
 	defer objFile.RemoveAll()
 	depFile := tempFile(t, "dep")
 	defer depFile.RemoveAll()
 
-	sleep(t)
-
-	sourceFile := tempFile(t, "source")
-	defer sourceFile.RemoveAll()
-
-	upToDate, err := builder_utils.ObjFileIsUpToDate(ctx, sourceFile, objFile, depFile)
+	upToDate, err := builder_utils.ObjFileIsUpToDate(ctx, sourceFile, objFile, depFile, nil)
 	NoError(t, err)
 	require.False(t, upToDate)
 }
 
-func TestObjFileIsUpToDateObjNewer(t *testing.T) {
+// prepareObjFile creates an object file built from sourceFile and headerFile
+// and records its hashes
+func prepareObjFile(t *testing.T, ctx *types.Context, sourceFile, headerFile *paths.Path, recipe []string) (*paths.Path, *paths.Path) {
+	objFile := tempFile(t, "obj")
+	depFile := tempFile(t, "dep")
+	data := objFile.String() + ": \\\n\t" + sourceFile.String() + " \\\n\t" + headerFile.String()
+	NoError(t, depFile.WriteFile([]byte(data)))
+	NoError(t, builder_utils.WriteObjFileHashes(ctx, objFile, sourceFile, depFile, recipe))
+	return objFile, depFile
+}
+
+func removeObjFile(objFile, depFile *paths.Path) {
+	objFile.RemoveAll()
+	depFile.RemoveAll()
+	paths.New(objFile.String() + ".hash").RemoveAll()
+}
+
+func TestObjFileIsUpToDateUnchanged(t *testing.T) {
 	ctx := &types.Context{}
 
 	sourceFile := tempFile(t, "source")
 	defer sourceFile.RemoveAll()
+	NoError(t, sourceFile.WriteFile([]byte("int main() {}")))
+	headerFile := tempFile(t, "header")
+	defer headerFile.RemoveAll()
 
-	sleep(t)
+	recipe := []string{"gcc", "-c", sourceFile.String()}
+	objFile, depFile := prepareObjFile(t, ctx, sourceFile, headerFile, recipe)
+	defer removeObjFile(objFile, depFile)
 
-	objFile := tempFile(t, "obj")
-	defer objFile.RemoveAll()
-	depFile := tempFile(t, "dep")
-	defer depFile.RemoveAll()
+	upToDate, err := builder_utils.ObjFileIsUpToDate(ctx, sourceFile, objFile, depFile, recipe)
+	NoError(t, err)
+	require.True(t, upToDate)
 
-	upToDate, err := builder_utils.ObjFileIsUpToDate(ctx, sourceFile, objFile, depFile)
+	// Touching the files without changing their content must not trigger a rebuild
+	future := time.Now().Add(time.Hour)
+	NoError(t, sourceFile.Chtimes(future, future))
+	NoError(t, headerFile.Chtimes(future, future))
+
+	upToDate, err = builder_utils.ObjFileIsUpToDate(ctx, sourceFile, objFile, depFile, recipe)
 	NoError(t, err)
 	require.True(t, upToDate)
 }
 
-func TestObjFileIsUpToDateDepIsNewer(t *testing.T) {
+func TestObjFileIsUpToDateSourceChanged(t *testing.T) {
 	ctx := &types.Context{}
 
 	sourceFile := tempFile(t, "source")
 	defer sourceFile.RemoveAll()
+	headerFile := tempFile(t, "header")
+	defer headerFile.RemoveAll()
 
-	sleep(t)
+	objFile, depFile := prepareObjFile(t, ctx, sourceFile, headerFile, nil)
+	defer removeObjFile(objFile, depFile)
 
-	objFile := tempFile(t, "obj")
-	defer objFile.RemoveAll()
-	depFile := tempFile(t, "dep")
-	defer depFile.RemoveAll()
+	NoError(t, sourceFile.WriteFile([]byte("int main() {}")))
+
+	upToDate, err := builder_utils.ObjFileIsUpToDate(ctx, sourceFile, objFile, depFile, nil)
+	NoError(t, err)
+	require.False(t, upToDate)
+}
 
-	sleep(t)
+func TestObjFileIsUpToDateDepChanged(t *testing.T) {
+	ctx := &types.Context{}
 
+	sourceFile := tempFile(t, "source")
+	defer sourceFile.RemoveAll()
 	headerFile := tempFile(t, "header")
 	defer headerFile.RemoveAll()
 
-	data := objFile.String() + ": \\\n\t" + sourceFile.String() + " \\\n\t" + headerFile.String()
-	depFile.WriteFile([]byte(data))
+	objFile, depFile := prepareObjFile(t, ctx, sourceFile, headerFile, nil)
+	defer removeObjFile(objFile, depFile)
 
-	upToDate, err := builder_utils.ObjFileIsUpToDate(ctx, sourceFile, objFile, depFile)
+	NoError(t, headerFile.WriteFile([]byte("#define A 1")))
+
+	upToDate, err := builder_utils.ObjFileIsUpToDate(ctx, sourceFile, objFile, depFile, nil)
 	NoError(t, err)
 	require.False(t, upToDate)
 }
 
-func TestObjFileIsUpToDateDepIsOlder(t *testing.T) {
+func TestObjFileIsUpToDateRecipeChanged(t *testing.T) {
 	ctx := &types.Context{}
 
 	sourceFile := tempFile(t, "source")
 	defer sourceFile.RemoveAll()
-
 	headerFile := tempFile(t, "header")
 	defer headerFile.RemoveAll()
 
-	sleep(t)
+	objFile, depFile := prepareObjFile(t, ctx, sourceFile, headerFile, []string{"gcc", "-O2"})
+	defer removeObjFile(objFile, depFile)
 
-	objFile := tempFile(t, "obj")
-	defer objFile.RemoveAll()
-	depFile := tempFile(t, "dep")
-	defer depFile.RemoveAll()
-
-	res := objFile.String() + ": \\\n\t" + sourceFile.String() + " \\\n\t" + headerFile.String()
-	depFile.WriteFile([]byte(res))
+	upToDate, err := builder_utils.ObjFileIsUpToDate(ctx, sourceFile, objFile, depFile, []string{"gcc", "-Os"})
+	NoError(t, err)
+	require.False(t, upToDate)
 
-	upToDate, err := builder_utils.ObjFileIsUpToDate(ctx, sourceFile, objFile, depFile)
+	// A nil recipe skips the check
+	upToDate, err = builder_utils.ObjFileIsUpToDate(ctx, sourceFile, objFile, depFile, nil)
 	NoError(t, err)
 	require.True(t, upToDate)
 }
@@ -155,23 +179,16 @@ func TestObjFileIsUpToDateDepIsWrong(t *testing.T) {
 
 	sourceFile := tempFile(t, "source")
 	defer sourceFile.RemoveAll()
-
-	sleep(t)
-
-	objFile := tempFile(t, "obj")
-	defer objFile.RemoveAll()
-	depFile := tempFile(t, "dep")
-	defer depFile.RemoveAll()
-
-	sleep(t)
-
 	headerFile := tempFile(t, "header")
 	defer headerFile.RemoveAll()
 
+	objFile, depFile := prepareObjFile(t, ctx, sourceFile, headerFile, nil)
+	defer removeObjFile(objFile, depFile)
+
 	res := sourceFile.String() + ": \\\n\t" + sourceFile.String() + " \\\n\t" + headerFile.String()
 	depFile.WriteFile([]byte(res))
 
-	upToDate, err := builder_utils.ObjFileIsUpToDate(ctx, sourceFile, objFile, depFile)
+	upToDate, err := builder_utils.ObjFileIsUpToDate(ctx, sourceFile, objFile, depFile, nil)
 	NoError(t, err)
 	require.False(t, upToDate)
 }
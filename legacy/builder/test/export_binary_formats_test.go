@@ -0,0 +1,103 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package test
+
+import (
+	"encoding/binary"
+	"testing"
+
+	bldr "github.com/arduino/arduino-cli/arduino/builder"
+	"github.com/arduino/arduino-cli/legacy/builder"
+	"github.com/arduino/arduino-cli/legacy/builder/types"
+	paths "github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func prepareExportBinaryFormatsContext(t *testing.T, buildPath *paths.Path, formats ...string) *types.Context {
+	ctx := &types.Context{}
+	ctx.BuildPath = buildPath
+	ctx.BuildProperties = properties.NewMap()
+	ctx.BuildProperties.SetPath("build.path", buildPath)
+	ctx.BuildProperties.Set("build.project_name", "sketch.ino")
+	ctx.BuildProperties.Set("build.mcu", "rp2040")
+	for _, spec := range formats {
+		format, err := bldr.ParseExportFormat(spec)
+		NoError(t, err)
+		ctx.ExportFormats = append(ctx.ExportFormats, format)
+	}
+	return ctx
+}
+
+func TestExportBinaryFormatsFromBin(t *testing.T) {
+	buildPath, err := paths.MkTempDir("", "test_export_binary_formats")
+	NoError(t, err)
+	defer buildPath.RemoveAll()
+	NoError(t, buildPath.Join("sketch.ino.bin").WriteFile([]byte("firmware image")))
+
+	// Without a .hex the .bin is placed at the flash address of the board
+	ctx := prepareExportBinaryFormatsContext(t, buildPath, "uf2", "hex")
+	ctx.BuildProperties.Set("build.flash.address", "0x10000000")
+	NoError(t, (&builder.ExportBinaryFormats{}).Run(ctx))
+
+	uf2, err := buildPath.Join("sketch.ino.uf2").ReadFile()
+	NoError(t, err)
+	require.Len(t, uf2, 512)
+	require.Equal(t, uint32(0x10000000), binary.LittleEndian.Uint32(uf2[12:]))
+	require.Equal(t, uint32(0xe48bff56), binary.LittleEndian.Uint32(uf2[28:]))
+	require.Equal(t, []byte("firmware image"), uf2[32:32+14])
+
+	image, err := bldr.LoadHexImage(buildPath.Join("sketch.ino.hex"))
+	NoError(t, err)
+	require.Equal(t, uint32(0x10000000), image.Address)
+	require.Equal(t, []byte("firmware image"), image.Data)
+
+	// An invalid flash address is reported
+	NoError(t, buildPath.Join("sketch.ino.hex").Remove())
+	ctx = prepareExportBinaryFormatsContext(t, buildPath, "uf2")
+	ctx.BuildProperties.Set("build.flash.address", "flash")
+	require.Error(t, (&builder.ExportBinaryFormats{}).Run(ctx))
+
+	// Without the property and the .elf the .bin is placed at address 0
+	ctx = prepareExportBinaryFormatsContext(t, buildPath, "uf2")
+	NoError(t, (&builder.ExportBinaryFormats{}).Run(ctx))
+	uf2, err = buildPath.Join("sketch.ino.uf2").ReadFile()
+	NoError(t, err)
+	require.Equal(t, uint32(0), binary.LittleEndian.Uint32(uf2[12:]))
+}
+
+func TestExportBinaryFormatsMergedWithOffset(t *testing.T) {
+	buildPath, err := paths.MkTempDir("", "test_export_binary_formats")
+	NoError(t, err)
+	defer buildPath.RemoveAll()
+	merged := &bldr.FirmwareImage{Address: 0x1000, Data: []byte{1, 2, 3, 4}}
+	hex, err := merged.HexBytes()
+	NoError(t, err)
+	NoError(t, buildPath.Join("sketch.ino.with_bootloader.hex").WriteFile(hex))
+
+	ctx := prepareExportBinaryFormatsContext(t, buildPath, "merged", "merged:0xffe", "merged:0x1002")
+	NoError(t, (&builder.ExportBinaryFormats{}).Run(ctx))
+
+	data, err := buildPath.Join("sketch.ino.with_bootloader.bin").ReadFile()
+	NoError(t, err)
+	require.Equal(t, []byte{1, 2, 3, 4}, data)
+	data, err = buildPath.Join("sketch.ino.with_bootloader.at_0xffe.bin").ReadFile()
+	NoError(t, err)
+	require.Equal(t, []byte{0xFF, 0xFF, 1, 2, 3, 4}, data)
+	data, err = buildPath.Join("sketch.ino.with_bootloader.at_0x1002.bin").ReadFile()
+	NoError(t, err)
+	require.Equal(t, []byte{3, 4}, data)
+}
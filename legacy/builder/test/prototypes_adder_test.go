@@ -934,3 +934,99 @@ func TestPrototypesAdderSketchWithSubstringFunctionMember(t *testing.T) {
 
 	require.Contains(t, ctx.Source, "class Foo {\nint blooper(int x) { return x+1; }\n};\n\nFoo foo;\n\n#line 7 "+quotedSketchLocation+"\nvoid setup();")
 }
+
+func TestPrototypesAdderCache(t *testing.T) {
+	DownloadCoresAndToolsAndLibraries(t)
+
+	sketchLocation := paths.New("sketch_with_config", "sketch_with_config.ino")
+	newContext := func() *types.Context {
+		return &types.Context{
+			HardwareDirs:         paths.NewPathList(filepath.Join("..", "hardware"), "downloaded_hardware"),
+			BuiltInToolsDirs:     paths.NewPathList("downloaded_tools"),
+			BuiltInLibrariesDirs: paths.NewPathList("downloaded_libraries"),
+			OtherLibrariesDirs:   paths.NewPathList("libraries"),
+			SketchLocation:       sketchLocation,
+			FQBN:                 parseFQBN(t, "arduino:avr:leonardo"),
+			ArduinoAPIVersion:    "10600",
+			Verbose:              true,
+		}
+	}
+	run := func(ctx *types.Context) {
+		commands := []types.Command{
+			&builder.ContainerSetupHardwareToolsLibsSketchAndProps{},
+			&builder.ContainerMergeCopySketchFiles{},
+			&builder.ContainerFindIncludes{},
+			&builder.ContainerAddPrototypes{},
+		}
+		for _, command := range commands {
+			err := command.Run(ctx)
+			NoError(t, err)
+		}
+	}
+
+	ctx := newContext()
+	buildPath := SetupBuildPath(t, ctx)
+	defer buildPath.RemoveAll()
+	run(ctx)
+	prototypes := ctx.PrototypesSection
+	require.NotEmpty(t, prototypes)
+	require.True(t, ctx.PreprocPath.Join("prototypes.cache").Exist())
+
+	// The second build takes the prototypes from the cache, without running
+	// the preprocessor and ctags again
+	ctagsTarget := ctx.PreprocPath.Join("ctags_target_for_gcc_minus_e.cpp")
+	require.NoError(t, ctagsTarget.Remove())
+	ctx = newContext()
+	ctx.BuildPath = buildPath
+	run(ctx)
+	require.Equal(t, prototypes, ctx.PrototypesSection)
+	require.False(t, ctagsTarget.Exist())
+}
+
+func TestPrototypesAdderCacheLibraryHeader(t *testing.T) {
+	DownloadCoresAndToolsAndLibraries(t)
+
+	tmp, err := paths.MkTempDir("", "prototypes_cache")
+	NoError(t, err)
+	defer tmp.RemoveAll()
+
+	// The type of the prototype is defined by a library header
+	sketchLocation := tmp.Join("ProtoSketch", "ProtoSketch.ino")
+	NoError(t, sketchLocation.Parent().MkdirAll())
+	NoError(t, sketchLocation.WriteFile([]byte("#include <ProtoLib.h>\n\nvoid setup() {}\n\nvoid loop() {}\n\nPROTO_TYPE helper() { return 0; }\n")))
+	header := tmp.Join("libraries", "ProtoLib", "src", "ProtoLib.h")
+	NoError(t, header.Parent().MkdirAll())
+	NoError(t, header.Parent().Parent().Join("library.properties").WriteFile([]byte("name=ProtoLib\nversion=1.0.0\n")))
+	NoError(t, header.WriteFile([]byte("#define PROTO_TYPE int\n")))
+
+	run := func() *types.Context {
+		ctx := &types.Context{
+			HardwareDirs:         paths.NewPathList(filepath.Join("..", "hardware"), "downloaded_hardware"),
+			BuiltInToolsDirs:     paths.NewPathList("downloaded_tools"),
+			BuiltInLibrariesDirs: paths.NewPathList("downloaded_libraries"),
+			OtherLibrariesDirs:   paths.NewPathList(tmp.Join("libraries").String()),
+			SketchLocation:       sketchLocation,
+			FQBN:                 parseFQBN(t, "arduino:avr:leonardo"),
+			ArduinoAPIVersion:    "10600",
+			BuildPath:            tmp.Join("build"),
+		}
+		commands := []types.Command{
+			&builder.ContainerSetupHardwareToolsLibsSketchAndProps{},
+			&builder.ContainerMergeCopySketchFiles{},
+			&builder.ContainerFindIncludes{},
+			&builder.ContainerAddPrototypes{},
+		}
+		for _, command := range commands {
+			err := command.Run(ctx)
+			NoError(t, err)
+		}
+		return ctx
+	}
+	NoError(t, tmp.Join("build").MkdirAll())
+
+	require.Contains(t, run().PrototypesSection, "int helper();")
+
+	// A change of the header invalidates the cached prototypes
+	NoError(t, header.WriteFile([]byte("#define PROTO_TYPE long\n")))
+	require.Contains(t, run().PrototypesSection, "long helper();")
+}
@@ -0,0 +1,46 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package test
+
+import (
+	"testing"
+
+	"github.com/arduino/arduino-cli/legacy/builder"
+	"github.com/arduino/arduino-cli/legacy/builder/types"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetCustomBuildPropertiesOverrideAndAppend(t *testing.T) {
+	ctx := &types.Context{}
+	ctx.BuildProperties = properties.NewMap()
+	ctx.BuildProperties.Set("compiler.cpp.extra_flags", "-DBOARD=1")
+	ctx.BuildProperties.Set("build.extra_flags", "-DPLATFORM")
+	ctx.CustomBuildProperties = []string{
+		"compiler.cpp.extra_flags+=-DFOO=1",
+		"compiler.cpp.extra_flags += -DBAR=2",
+		"build.extra_flags=-DOVERRIDE",
+		"build.extra_flags+=-DAFTER",
+		"compiler.c.extra_flags+=-DNEW",
+		"build.warn_data_percentage=90",
+	}
+
+	NoError(t, (&builder.SetCustomBuildProperties{}).Run(ctx))
+	require.Equal(t, "-DBOARD=1 -DFOO=1 -DBAR=2", ctx.BuildProperties.Get("compiler.cpp.extra_flags"))
+	require.Equal(t, "-DOVERRIDE -DAFTER", ctx.BuildProperties.Get("build.extra_flags"))
+	require.Equal(t, "-DNEW", ctx.BuildProperties.Get("compiler.c.extra_flags"))
+	require.Equal(t, "90", ctx.BuildProperties.Get("build.warn_data_percentage"))
+}
@@ -0,0 +1,81 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package test
+
+import (
+	"testing"
+
+	"github.com/arduino/arduino-cli/legacy/builder"
+	"github.com/arduino/arduino-cli/legacy/builder/types"
+	paths "github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetupPartitionTable(t *testing.T) {
+	dir, err := paths.MkTempDir("", "test_partition_table")
+	NoError(t, err)
+	defer dir.RemoveAll()
+
+	platformDir := dir.Join("platform")
+	NoError(t, platformDir.Join("tools", "partitions").MkdirAll())
+	NoError(t, platformDir.Join("tools", "partitions", "default.csv").WriteFile([]byte(
+		"nvs, data, nvs, 0x9000, 0x5000\n"+
+			"app0, app, ota_0, 0x10000, 0x140000\n"+
+			"app1, app, ota_1, 0x150000, 0x140000\n")))
+	sketchDir := dir.Join("sketch")
+	NoError(t, sketchDir.MkdirAll())
+	buildPath := dir.Join("build")
+	NoError(t, buildPath.MkdirAll())
+
+	ctx := &types.Context{}
+	ctx.SketchLocation = sketchDir.Join("sketch.ino")
+	ctx.BuildPath = buildPath
+	ctx.BuildProperties = properties.NewMap()
+	ctx.BuildProperties.SetPath("runtime.platform.path", platformDir)
+	ctx.BuildProperties.Set("build.partitions", "default")
+	ctx.BuildProperties.Set("upload.maximum_size", "1310720")
+
+	// The table selected by the board options
+	NoError(t, (&builder.SetupPartitionTable{}).Run(ctx))
+	require.Equal(t, "1310720", ctx.BuildProperties.Get("upload.maximum_size"))
+	require.False(t, buildPath.Join("partitions.csv").Exist())
+
+	// The table in the sketch folder
+	NoError(t, sketchDir.Join("partitions.csv").WriteFile([]byte(
+		"nvs, data, nvs, 0x9000, 0x5000\n"+
+			"factory, app, factory, 0x10000, 0x300000\n")))
+	NoError(t, (&builder.SetupPartitionTable{}).Run(ctx))
+	require.Equal(t, "3145728", ctx.BuildProperties.Get("upload.maximum_size"))
+
+	// A custom table is copied in the build path
+	custom := dir.Join("custom.csv")
+	NoError(t, custom.WriteFile([]byte(
+		"nvs, data, nvs, 0x9000, 0x5000\n"+
+			"app0, app, ota_0, 0x10000, 0x1E0000\n")))
+	ctx.PartitionTable = custom
+	NoError(t, (&builder.SetupPartitionTable{}).Run(ctx))
+	require.Equal(t, "1966080", ctx.BuildProperties.Get("upload.maximum_size"))
+	data, err := buildPath.Join("partitions.csv").ReadFile()
+	NoError(t, err)
+	require.Contains(t, string(data), "app0, app, ota_0, 0x10000, 0x1E0000")
+
+	// Custom tables are validated
+	NoError(t, custom.WriteFile([]byte("nvs, data, nvs, 0x9000, 0x5000\n")))
+	err = (&builder.SetupPartitionTable{}).Run(ctx)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "the partitions table has no app partition")
+}
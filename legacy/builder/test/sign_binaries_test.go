@@ -0,0 +1,111 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"runtime"
+	"testing"
+
+	bldr "github.com/arduino/arduino-cli/arduino/builder"
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/legacy/builder"
+	"github.com/arduino/arduino-cli/legacy/builder/types"
+	paths "github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func prepareSignBinariesContext(t *testing.T, buildPath *paths.Path) *types.Context {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	NoError(t, err)
+	keyFile := buildPath.Join("key.pem")
+	NoError(t, keyFile.WriteFile(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})))
+	NoError(t, buildPath.Join("sketch.ino.bin").WriteFile([]byte("firmware image")))
+
+	ctx := &types.Context{}
+	ctx.BuildPath = buildPath
+	ctx.FQBN, err = cores.ParseFQBN("esp32:esp32:esp32")
+	NoError(t, err)
+	ctx.BuildProperties = properties.NewMap()
+	ctx.BuildProperties.SetPath("build.path", buildPath)
+	ctx.BuildProperties.Set("build.project_name", "sketch.ino")
+	ctx.SigningKey = keyFile
+	return ctx
+}
+
+func loadOTAManifest(t *testing.T, buildPath *paths.Path) *bldr.OTAManifest {
+	data, err := buildPath.Join("sketch.ino.ota.json").ReadFile()
+	NoError(t, err)
+	manifest := &bldr.OTAManifest{}
+	NoError(t, json.Unmarshal(data, manifest))
+	return manifest
+}
+
+func TestSignBinaries(t *testing.T) {
+	buildPath, err := paths.MkTempDir("", "test_sign_binaries")
+	NoError(t, err)
+	defer buildPath.RemoveAll()
+
+	ctx := prepareSignBinariesContext(t, buildPath)
+	NoError(t, (&builder.SignBinaries{}).Run(ctx))
+
+	signed, err := buildPath.Join("sketch.ino.signed.bin").ReadFile()
+	NoError(t, err)
+	manifest := loadOTAManifest(t, buildPath)
+	require.Equal(t, "esp32:esp32:esp32", manifest.Fqbn)
+	require.Equal(t, "sketch.ino.signed.bin", manifest.Image)
+	require.Equal(t, len(signed), manifest.Size)
+	require.Equal(t, bldr.SignEd25519, manifest.Algorithm)
+
+	key, _, err := bldr.LoadSigningKey(ctx.SigningKey)
+	NoError(t, err)
+	image := []byte("firmware image")
+	NoError(t, bldr.VerifyImage(key.Public(), image, signed[len(image):len(signed)-4]))
+}
+
+func TestSignBinariesWithPlatformRecipe(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the recipe of the test uses sh")
+	}
+	buildPath, err := paths.MkTempDir("", "test_sign_binaries")
+	NoError(t, err)
+	defer buildPath.RemoveAll()
+
+	ctx := prepareSignBinariesContext(t, buildPath)
+	ctx.BuildProperties.Set("recipe.sign.1.pattern", `sh -c 'cat "{build.path}/{build.project_name}.bin" "{sign.key_path}" > "{build.path}/{build.project_name}.signed.bin"'`)
+	NoError(t, (&builder.SignBinaries{}).Run(ctx))
+
+	signed, err := buildPath.Join("sketch.ino.signed.bin").ReadFile()
+	NoError(t, err)
+	require.Contains(t, string(signed), "PRIVATE KEY")
+	manifest := loadOTAManifest(t, buildPath)
+	require.Equal(t, "sketch.ino.signed.bin", manifest.Image)
+	require.Equal(t, len(signed), manifest.Size)
+	require.Empty(t, manifest.Signature)
+
+	// Nothing is signed without a key
+	NoError(t, buildPath.Join("sketch.ino.ota.json").Remove())
+	ctx.SigningKey = nil
+	NoError(t, (&builder.SignBinaries{}).Run(ctx))
+	require.False(t, buildPath.Join("sketch.ino.ota.json").Exist())
+}
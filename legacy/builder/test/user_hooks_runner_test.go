@@ -0,0 +1,64 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package test
+
+import (
+	"runtime"
+	"testing"
+
+	bldr "github.com/arduino/arduino-cli/arduino/builder"
+	"github.com/arduino/arduino-cli/legacy/builder"
+	"github.com/arduino/arduino-cli/legacy/builder/types"
+	paths "github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserHooksRunner(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the hooks of the test use sh")
+	}
+	sketchDir, err := paths.MkTempDir("", "test_user_hooks")
+	NoError(t, err)
+	defer sketchDir.RemoveAll()
+
+	ctx := &types.Context{}
+	ctx.SketchLocation = sketchDir
+	ctx.BuildProperties = properties.NewMap()
+	ctx.BuildProperties.Set("build.path", "/tmp/build")
+	ctx.BuildProperties.Set("build.project_name", "Blink.ino")
+	ctx.UserHooks = map[string][]string{
+		bldr.HookPostBuild: {
+			`sh -c 'echo "$ARDUINO_BUILD_PATH" > env.txt'`,
+			`sh -c 'echo "{build.project_name}" > props.txt'`,
+		},
+	}
+
+	// Hooks of other stages are not run
+	NoError(t, (&builder.UserHooksRunner{Stage: bldr.HookPreLink}).Run(ctx))
+	require.False(t, sketchDir.Join("env.txt").Exist())
+
+	NoError(t, (&builder.UserHooksRunner{Stage: bldr.HookPostBuild}).Run(ctx))
+	data, err := sketchDir.Join("env.txt").ReadFile()
+	NoError(t, err)
+	require.Equal(t, "/tmp/build\n", string(data))
+	data, err = sketchDir.Join("props.txt").ReadFile()
+	NoError(t, err)
+	require.Equal(t, "Blink.ino\n", string(data))
+
+	ctx.UserHooks[bldr.HookPostBuild] = []string{"sh -c 'exit 1'"}
+	require.Error(t, (&builder.UserHooksRunner{Stage: bldr.HookPostBuild}).Run(ctx))
+}
@@ -0,0 +1,45 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"github.com/arduino/arduino-cli/legacy/builder/ctags"
+	"github.com/arduino/arduino-cli/legacy/builder/types"
+	"github.com/pkg/errors"
+)
+
+// TreeSitterRunner generates the prototypes parsing the preprocessed sketch
+// with tree-sitter, it's an alternative to CTagsRunner that doesn't need ctags
+type TreeSitterRunner struct{}
+
+func (s *TreeSitterRunner) Run(ctx *types.Context) error {
+	parser := &ctags.CTagsParser{}
+
+	tags, err := parser.ParseWithTreeSitter(ctx.SourceGccMinusE, ctx.Sketch.MainFile.Name)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	ctx.CTagsOfPreprocessedSource = tags
+	parser.SkipNoPrototypesTags(ctx.SourceGccMinusE)
+
+	protos, line := parser.GeneratePrototypes()
+	if line != -1 {
+		ctx.PrototypesLineWhereToInsert = line
+	}
+	ctx.Prototypes = protos
+
+	return nil
+}
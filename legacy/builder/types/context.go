@@ -17,7 +17,9 @@ package types
 
 import (
 	"io"
+	"runtime"
 	"strings"
+	"sync"
 
 	"github.com/arduino/arduino-cli/arduino/builder"
 	"github.com/arduino/arduino-cli/arduino/cores"
@@ -31,33 +33,65 @@ import (
 	properties "github.com/arduino/go-properties-orderedmap"
 )
 
+// ProgressStruct tracks the progress of the build as the ratio between the
+// completed steps and all the steps added so far. The parts of the build
+// running in parallel add and complete their own groups of steps, so they
+// don't interfere with each other.
 type ProgressStruct struct {
 	PrintEnabled bool
-	Progress     float32
-	StepAmount   float32
-	Parent       *ProgressStruct
+	completed    int
+	total        int
+	// the highest percentage reported, the steps added later by the other
+	// parts of the build must not make the progress go back
+	percent float32
+	lock    sync.Mutex
 }
 
-func (p *ProgressStruct) AddSubSteps(steps int) {
-	p.Parent = &ProgressStruct{
-		Progress:   p.Progress,
-		StepAmount: p.StepAmount,
-		Parent:     p.Parent,
-	}
-	if p.StepAmount == 0.0 {
-		p.StepAmount = 100.0
+// ProgressSteps is a group of steps of the build
+type ProgressSteps struct {
+	progress  *ProgressStruct
+	remaining int
+}
+
+// AddSteps adds a group of steps to the build. The caller must complete
+// them with CompleteStep and call Close once done.
+func (p *ProgressStruct) AddSteps(steps int) *ProgressSteps {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.total += steps
+	return &ProgressSteps{progress: p, remaining: steps}
+}
+
+// CompleteStep completes a step of the group
+func (s *ProgressSteps) CompleteStep() {
+	s.progress.lock.Lock()
+	defer s.progress.lock.Unlock()
+	if s.remaining == 0 {
+		return
 	}
-	p.StepAmount /= float32(steps)
+	s.remaining--
+	s.progress.completed++
 }
 
-func (p *ProgressStruct) RemoveSubSteps() {
-	p.Progress = p.Parent.Progress
-	p.StepAmount = p.Parent.StepAmount
-	p.Parent = p.Parent.Parent
+// Close completes the steps of the group not completed yet, e.g. the ones
+// skipped after an error.
+func (s *ProgressSteps) Close() {
+	s.progress.lock.Lock()
+	defer s.progress.lock.Unlock()
+	s.progress.completed += s.remaining
+	s.remaining = 0
 }
 
-func (p *ProgressStruct) CompleteStep() {
-	p.Progress += p.StepAmount
+// Current returns the percentage of the build completed so far
+func (p *ProgressStruct) Current() float32 {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.total > 0 {
+		if percent := float32(p.completed) * 100 / float32(p.total); percent > p.percent {
+			p.percent = percent
+		}
+	}
+	return p.percent
 }
 
 // Context structure
@@ -92,16 +126,19 @@ type Context struct {
 	PlatformKeyRewrites    PlatforKeysRewrite
 	HardwareRewriteResults map[*cores.PlatformRelease][]PlatforKeyRewrite
 
-	BuildProperties              *properties.Map
-	BuildCore                    string
-	BuildPath                    *paths.Path
-	BuildCachePath               *paths.Path
-	SketchBuildPath              *paths.Path
-	CoreBuildPath                *paths.Path
-	CoreBuildCachePath           *paths.Path
-	CoreArchiveFilePath          *paths.Path
-	CoreObjectsFiles             paths.PathList
-	LibrariesBuildPath           *paths.Path
+	BuildProperties     *properties.Map
+	BuildCore           string
+	BuildPath           *paths.Path
+	BuildCachePath      *paths.Path
+	SketchBuildPath     *paths.Path
+	CoreBuildPath       *paths.Path
+	CoreBuildCachePath  *paths.Path
+	CoreArchiveFilePath *paths.Path
+	CoreObjectsFiles    paths.PathList
+	LibrariesBuildPath  *paths.Path
+	// Linker flags of the precompiled libraries, added to
+	// compiler.libraries.ldflags by the Linker
+	LibrariesLDFlags             string
 	LibrariesObjectFiles         paths.PathList
 	PreprocPath                  *paths.Path
 	SketchObjectFiles            paths.PathList
@@ -115,6 +152,9 @@ type Context struct {
 	CodeCompletions string
 
 	WarningsLevel string
+	// LibrariesWarningsLevel is the warnings level used to compile the
+	// libraries and the core, defaults to WarningsLevel
+	LibrariesWarningsLevel string
 
 	// Libraries handling
 	LibrariesManager           *librariesmanager.LibrariesManager
@@ -122,9 +162,15 @@ type Context struct {
 	ImportedLibraries          libraries.List
 	LibrariesResolutionResults map[string]LibraryResolutionResult
 	IncludeFolders             paths.PathList
+	// LibrariesResolutionTrace lists every resolved #include, in the order
+	// they have been found
+	LibrariesResolutionTrace []*LibraryResolutionTrace
 	//OutputGccMinusM            string
 
 	// C++ Parsing
+	// Preprocessor selects how the prototypes are generated: "ctags" (the
+	// default) or "treesitter", that parses the sketch with tree-sitter
+	Preprocessor                string
 	CTagsOutput                 string
 	CTagsTargetFile             *paths.Path
 	CTagsOfPreprocessedSource   []*CTag
@@ -139,10 +185,14 @@ type Context struct {
 
 	// Compile optimization settings
 	OptimizeForDebug  bool
+	Optimization      string
 	OptimizationFlags string
 
 	// Dry run, only create progress map
 	Progress ProgressStruct
+	// ProgressCB, if set, is called when a build phase starts or completes
+	ProgressCB   func(*rpc.TaskProgress)
+	progressLock sync.Mutex
 
 	// Contents of a custom build properties file (line by line)
 	CustomBuildProperties []string
@@ -159,6 +209,28 @@ type Context struct {
 
 	// Parallel processes
 	Jobs int
+	// jobSlots is shared by all the compilation plans of the build, so that no
+	// more than Jobs compilations run at the same time
+	jobSlots     chan struct{}
+	jobSlotsOnce sync.Once
+
+	// Additional binary formats to create after the objcopy recipes
+	ExportFormats []*builder.ExportFormat
+
+	// Partitions table used instead of the one selected by the board options
+	PartitionTable *paths.Path
+
+	// Private key used to sign the sketch binary
+	SigningKey *paths.Path
+
+	// Continue building what doesn't depend on a failed step, instead of
+	// stopping at the first error
+	KeepGoing bool
+
+	// Limits of the core archives cache in CoreBuildCachePath, the
+	// max size is in bytes (0 means no limit)
+	CoreBuildCacheMaxSize        int64
+	CoreBuildCacheEvictionPolicy string
 
 	// Out and Err stream to redirect all Exec commands
 	ExecStdout io.Writer
@@ -167,11 +239,44 @@ type Context struct {
 	// Sizer results
 	ExecutableSectionsSize ExecutablesFileSections
 
+	// Limits on the flash and RAM used by the sketch, checked by the
+	// Sizer if not nil
+	MaxFlashUsage *builder.SizeLimit
+	MaxRAMUsage   *builder.SizeLimit
+
+	// Build statistics, collected only if not nil
+	Stats *BuildStats
+
+	// Records where the values of some build properties come from, if not nil
+	PropertiesTrace *PropertiesTrace
+
+	// User defined commands to run at the build stages, keyed by stage
+	UserHooks map[string][]string
+
+	// If set the external commands are run inside this container
+	Container *builder.Container
+
+	// If set the object files and the core archive are shared through this
+	// remote cache
+	RemoteBuildCache *builder.RemoteCache
+
+	// Content hashes of the files already read during this build, the same
+	// headers are usually included by many source files
+	FileHashes sync.Map
+
 	// Compilation Database to build/update
 	CompilationDatabase *builder.CompilationDatabase
 	// Set to true to skip build and produce only Compilation Database
 	OnlyUpdateCompilationDatabase bool
 
+	// If set, a standalone CMake project equivalent to the build is exported
+	// in this folder
+	ExportCMakePath *paths.Path
+
+	// If set, all the commands run by the builder are recorded to export
+	// the build as a Makefile or a build.ninja file
+	BuildScript *builder.BuildScript
+
 	// Source code overrides (filename -> content map).
 	// The provided source data is used instead of reading it from disk.
 	// The keys of the map are paths relative to sketch folder.
@@ -243,6 +348,41 @@ func (ctx *Context) InjectBuildOptions(opts *properties.Map) {
 	ctx.OptimizationFlags = opts.Get("compiler.optimization_flags")
 }
 
+// ReportProgress calls ProgressCB, if set, with the name of a build phase and
+// the percentage of the build completed so far. The calls are serialized, so
+// the callback doesn't need to be safe for concurrent use.
+func (ctx *Context) ReportProgress(phase string, completed bool) {
+	if ctx.ProgressCB == nil {
+		return
+	}
+	ctx.progressLock.Lock()
+	defer ctx.progressLock.Unlock()
+	ctx.ProgressCB(&rpc.TaskProgress{
+		Name:      phase,
+		Completed: completed,
+		Percent:   ctx.Progress.Current(),
+	})
+}
+
+// AcquireJobSlot blocks until less than ctx.Jobs (or the number of available
+// CPUs if ctx.Jobs is 0) compilations are running in the whole build, then
+// takes a slot that must be given back with ReleaseJobSlot.
+func (ctx *Context) AcquireJobSlot() {
+	ctx.jobSlotsOnce.Do(func() {
+		jobs := ctx.Jobs
+		if jobs <= 0 {
+			jobs = runtime.NumCPU()
+		}
+		ctx.jobSlots = make(chan struct{}, jobs)
+	})
+	ctx.jobSlots <- struct{}{}
+}
+
+// ReleaseJobSlot gives back a slot taken with AcquireJobSlot
+func (ctx *Context) ReleaseJobSlot() {
+	<-ctx.jobSlots
+}
+
 func (ctx *Context) GetLogger() i18n.Logger {
 	if ctx.logger == nil {
 		return &i18n.HumanLogger{}
@@ -16,7 +16,7 @@
 package types
 
 import (
-	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -24,44 +24,52 @@ import (
 
 func TestProgress(t *testing.T) {
 	p := &ProgressStruct{}
-	p.AddSubSteps(3)
-	require.Equal(t, float32(0.0), p.Progress)
-	require.InEpsilon(t, 33.33333, p.StepAmount, 0.00001)
-	fmt.Printf("%+v\n", p)
-	{
-		p.CompleteStep()
-		require.InEpsilon(t, 33.33333, p.Progress, 0.00001)
-		fmt.Printf("%+v\n", p)
+	require.Equal(t, float32(0.0), p.Current())
 
-		p.AddSubSteps(4)
-		require.InEpsilon(t, 33.33333, p.Progress, 0.00001)
-		require.InEpsilon(t, 8.33333, p.StepAmount, 0.00001)
-		fmt.Printf("%+v\n", p)
-		{
-			p.CompleteStep()
-			require.InEpsilon(t, 41.66666, p.Progress, 0.00001)
-			fmt.Printf("%+v\n", p)
+	steps := p.AddSteps(4)
+	require.Equal(t, float32(0.0), p.Current())
+	steps.CompleteStep()
+	require.Equal(t, float32(25.0), p.Current())
 
-			p.CompleteStep()
-			require.InEpsilon(t, 50.0, p.Progress, 0.00001)
-			fmt.Printf("%+v\n", p)
+	// The nested steps are added to the total, the progress doesn't go back
+	nested := p.AddSteps(4)
+	require.Equal(t, float32(25.0), p.Current())
+	nested.CompleteStep()
+	nested.CompleteStep()
+	nested.CompleteStep()
+	require.Equal(t, float32(50.0), p.Current())
+	nested.Close()
+	require.InEpsilon(t, 62.5, p.Current(), 0.00001)
 
-			p.AddSubSteps(0) // zero steps
-			fmt.Printf("%+v\n", p)
-			{
-				// p.CompleteStep() invalid here
-			}
-			p.RemoveSubSteps()
-		}
-		p.RemoveSubSteps()
-		require.InEpsilon(t, 33.33333, p.Progress, 0.00001)
-		fmt.Printf("%+v\n", p)
+	// The steps not completed are completed by Close
+	steps.CompleteStep()
+	steps.Close()
+	require.Equal(t, float32(100.0), p.Current())
+
+	// Completing more steps than added has no effect
+	steps.CompleteStep()
+	require.Equal(t, float32(100.0), p.Current())
+}
 
-		p.CompleteStep()
-		require.InEpsilon(t, 66.66666, p.Progress, 0.00001)
-		fmt.Printf("%+v\n", p)
+func TestProgressConcurrentSteps(t *testing.T) {
+	p := &ProgressStruct{}
+	steps := p.AddSteps(3)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			nested := p.AddSteps(n * 10)
+			defer nested.Close()
+			for j := 0; j < n*10; j++ {
+				nested.CompleteStep()
+				require.LessOrEqual(t, p.Current(), float32(100.0))
+			}
+			steps.CompleteStep()
+		}(i + 1)
 	}
-	p.RemoveSubSteps()
-	require.Equal(t, float32(0.0), p.Progress)
-	require.Equal(t, float32(0.0), p.StepAmount)
+	wg.Wait()
+	steps.Close()
+	require.Equal(t, float32(100.0), p.Current())
 }
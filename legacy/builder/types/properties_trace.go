@@ -0,0 +1,97 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package types
+
+import (
+	"regexp"
+
+	properties "github.com/arduino/go-properties-orderedmap"
+)
+
+// PropertiesTrace records where the values of some build properties come
+// from while the build properties are computed
+type PropertiesTrace struct {
+	Keys    []string
+	Changes []*PropertyChange
+	values  map[string]string
+}
+
+// PropertyChange is a change to the value of a traced property
+type PropertyChange struct {
+	Key    string
+	Value  string
+	Source string
+}
+
+// NewPropertiesTrace creates a PropertiesTrace for the given keys
+func NewPropertiesTrace(keys []string) *PropertiesTrace {
+	return &PropertiesTrace{Keys: keys, values: map[string]string{}}
+}
+
+// Record records a change for each traced key defined in props with a value
+// different from the last recorded one. props may be a whole set of build
+// properties or only the ones defined by a file that is merged into them.
+func (t *PropertiesTrace) Record(props *properties.Map, source string) {
+	if t == nil || props == nil {
+		return
+	}
+	for _, key := range t.Keys {
+		value, ok := props.GetOk(key)
+		if !ok {
+			continue
+		}
+		if last, ok := t.values[key]; ok && last == value {
+			continue
+		}
+		t.values[key] = value
+		t.Changes = append(t.Changes, &PropertyChange{Key: key, Value: value, Source: source})
+	}
+}
+
+// KeyChanges returns the changes recorded for the given key
+func (t *PropertiesTrace) KeyChanges(key string) []*PropertyChange {
+	res := []*PropertyChange{}
+	for _, change := range t.Changes {
+		if change.Key == key {
+			res = append(res, change)
+		}
+	}
+	return res
+}
+
+var propertyReference = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// References returns the properties referenced by the value of key, and
+// recursively by their values, in the order they are found
+func References(props *properties.Map, key string) []string {
+	res := []string{}
+	visited := map[string]bool{key: true}
+	queue := []string{key}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, match := range propertyReference.FindAllStringSubmatch(props.Get(current), -1) {
+			ref := match[1]
+			if visited[ref] {
+				continue
+			}
+			visited[ref] = true
+			res = append(res, ref)
+			queue = append(queue, ref)
+		}
+	}
+	return res
+}
@@ -0,0 +1,59 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package types
+
+import (
+	"testing"
+
+	properties "github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPropertiesTrace(t *testing.T) {
+	trace := NewPropertiesTrace([]string{"upload.maximum_size", "build.flags"})
+
+	boardsTxt := properties.NewMap()
+	boardsTxt.Set("upload.maximum_size", "32256")
+	trace.Record(boardsTxt, "boards.txt")
+
+	menu := properties.NewMap()
+	menu.Set("upload.maximum_size", "30720")
+	trace.Record(menu, "boards.txt (menu option cpu=atmega168)")
+
+	// unchanged values are not recorded again
+	buildProperties := properties.NewMap()
+	buildProperties.Set("upload.maximum_size", "30720")
+	trace.Record(buildProperties, "the builder")
+
+	require.Equal(t, []*PropertyChange{
+		{Key: "upload.maximum_size", Value: "32256", Source: "boards.txt"},
+		{Key: "upload.maximum_size", Value: "30720", Source: "boards.txt (menu option cpu=atmega168)"},
+	}, trace.KeyChanges("upload.maximum_size"))
+	require.Empty(t, trace.KeyChanges("build.flags"))
+
+	// a nil trace is a no-op
+	var nilTrace *PropertiesTrace
+	nilTrace.Record(buildProperties, "the builder")
+}
+
+func TestPropertiesReferences(t *testing.T) {
+	props := properties.NewMap()
+	props.Set("build.flags", "{build.mcu_flags} {build.extra_flags} {build.mcu_flags}")
+	props.Set("build.mcu_flags", "-mmcu={build.mcu}")
+	props.Set("build.mcu", "atmega328p")
+	require.Equal(t, []string{"build.mcu_flags", "build.extra_flags", "build.mcu"}, References(props, "build.flags"))
+	require.Empty(t, References(props, "build.mcu"))
+}
@@ -0,0 +1,104 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package types
+
+import (
+	"sync"
+	"time"
+)
+
+// BuildStats collects statistics about a build. All the methods are safe
+// for concurrent use and do nothing on a nil BuildStats, so the collection
+// can be enabled only when requested.
+type BuildStats struct {
+	lock sync.Mutex
+
+	// Number of source files compiled
+	TranslationUnits int
+	// Number of object files reused from a previous build
+	ReusedObjectFiles int
+	// Total number of lines of the compiled source files
+	LinesCompiled int64
+	// CPU time spent by the compiler processes
+	CompilerCPUTime time.Duration
+	// True if the core archive has been taken from the core cache
+	CoreCacheHit bool
+	// Timed steps of the build, in the order they ended
+	Events []*BuildEvent
+}
+
+// Categories of the BuildEvent
+const (
+	BuildEventPhase   = "phase"
+	BuildEventCompile = "compile"
+	BuildEventArchive = "archive"
+)
+
+// BuildEvent is a timed step of the build: a phase of the builder, the
+// compilation of a source file or the creation of an archive
+type BuildEvent struct {
+	Name     string
+	Category string
+	Start    time.Time
+	Duration time.Duration
+}
+
+// StartEvent starts timing a step of the build, the returned function must
+// be called when the step ends
+func (s *BuildStats) StartEvent(category, name string) func() {
+	if s == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		event := &BuildEvent{Name: name, Category: category, Start: start, Duration: time.Since(start)}
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		s.Events = append(s.Events, event)
+	}
+}
+
+// AddCompiledUnit records a compiled source file
+func (s *BuildStats) AddCompiledUnit(lines int64, cpuTime time.Duration) {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.TranslationUnits++
+	s.LinesCompiled += lines
+	s.CompilerCPUTime += cpuTime
+}
+
+// AddReusedObjectFile records an object file reused from a previous build
+func (s *BuildStats) AddReusedObjectFile() {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.ReusedObjectFiles++
+}
+
+// SetCoreCacheHit records whether the core archive has been found in the cache
+func (s *BuildStats) SetCoreCacheHit(hit bool) {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.CoreCacheHit = hit
+}
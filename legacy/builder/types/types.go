@@ -20,6 +20,7 @@ import (
 	"strconv"
 
 	"github.com/arduino/arduino-cli/arduino/libraries"
+	"github.com/arduino/arduino-cli/arduino/libraries/librariesresolver"
 	"github.com/arduino/arduino-cli/arduino/sketch"
 	paths "github.com/arduino/go-paths-helper"
 )
@@ -173,6 +174,9 @@ type Prototype struct {
 	Prototype    string
 	Modifiers    string
 	Line         int
+	// Namespace is the namespace where the function is defined, nested
+	// namespaces are separated by "::"
+	Namespace string
 }
 
 func (proto *Prototype) String() string {
@@ -184,6 +188,14 @@ type LibraryResolutionResult struct {
 	NotUsedLibraries []*libraries.Library
 }
 
+// LibraryResolutionTrace records how an #include found in a source file has
+// been resolved to a library
+type LibraryResolutionTrace struct {
+	SourceFile *paths.Path
+	Include    string
+	Resolution *librariesresolver.Resolution
+}
+
 type CTag struct {
 	FunctionName string
 	Kind         string
@@ -0,0 +1,67 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"os"
+	"os/exec"
+
+	bldr "github.com/arduino/arduino-cli/arduino/builder"
+	"github.com/arduino/arduino-cli/legacy/builder/constants"
+	"github.com/arduino/arduino-cli/legacy/builder/types"
+	"github.com/arduino/arduino-cli/legacy/builder/utils"
+	properties "github.com/arduino/go-properties-orderedmap"
+	"github.com/pkg/errors"
+)
+
+// UserHooksRunner runs the commands defined by the user for a build stage.
+// The build properties are exported to the commands as environment
+// variables and can also be used in the command line as in the recipes.
+type UserHooksRunner struct {
+	Stage string
+}
+
+func (s *UserHooksRunner) Run(ctx *types.Context) error {
+	hooks := ctx.UserHooks[s.Stage]
+	if len(hooks) == 0 || ctx.OnlyUpdateCompilationDatabase {
+		return nil
+	}
+
+	buildProperties := ctx.BuildProperties
+	env := append(os.Environ(), bldr.HookEnvironment(buildProperties)...)
+	dir := ctx.SketchLocation
+	if !dir.IsDir() {
+		dir = dir.Parent()
+	}
+	for _, hook := range hooks {
+		commandLine := buildProperties.ExpandPropsInString(hook)
+		parts, err := properties.SplitQuotedString(commandLine, `"'`, false)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if len(parts) == 0 {
+			continue
+		}
+		ctx.GetLogger().Println(constants.LOG_LEVEL_INFO, constants.MSG_RUNNING_USER_HOOK, s.Stage, commandLine)
+		command := exec.Command(parts[0], parts[1:]...)
+		command.Env = env
+		command.Dir = dir.String()
+		if _, _, err := utils.ExecCommand(ctx, command, utils.Show /* stdout */, utils.Show /* stderr */); err != nil {
+			return errors.Wrapf(err, "running %s hook %s", s.Stage, commandLine)
+		}
+	}
+	return nil
+}
@@ -178,6 +178,12 @@ func ExecCommand(ctx *types.Context, command *exec.Cmd, stdout int, stderr int)
 	if ctx.ExecStderr == nil {
 		ctx.ExecStderr = os.Stderr
 	}
+	if ctx.Container != nil {
+		original := command
+		command = ctx.Container.Wrap(original)
+		// The callers read the state of the process from their command
+		defer func() { original.ProcessState = command.ProcessState }()
+	}
 
 	if ctx.Verbose {
 		ctx.GetLogger().UnformattedFprintln(os.Stdout, PrintableCommand(command.Args))
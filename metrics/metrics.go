@@ -18,21 +18,35 @@ package metrics
 import (
 	"crypto/hmac"
 	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/hex"
 	"net/http"
+	"net/http/pprof"
 
-	"github.com/arduino/arduino-cli/configuration"
 	"github.com/arduino/arduino-cli/inventory"
 	"github.com/segmentio/stats/v4"
 	"github.com/segmentio/stats/v4/prometheus"
 	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
 )
 
 // serverPattern is the metrics endpoint resource path for consume metrics
 var serverPattern = "/metrics"
 
-// Activate configures and starts the metrics server exposing a Prometheus resource
-func Activate(metricPrefix string) {
+// pprofPattern is the resource path of the profiling endpoints
+var pprofPattern = "/debug/pprof/"
+
+var enabled = false
+
+// Enabled returns true if the metrics server has been activated
+func Enabled() bool {
+	return enabled
+}
+
+// Activate configures and starts the metrics server exposing a Prometheus resource.
+// The server address and the profiling token are read from the given settings,
+// the profiling endpoints are disabled if metrics.pprof_token is empty.
+func Activate(metricPrefix string, settings *viper.Viper) {
 	// Create a Prometheus default handler
 	ph := prometheus.DefaultHandler
 	// Create a new stats engine with an engine that prepends the "daemon" prefix to all metrics
@@ -41,17 +55,59 @@ func Activate(metricPrefix string) {
 		inventory.Store.GetString("installation.id")))
 	// Register the handler so it receives metrics from the default engine.
 	stats.Register(ph)
+	// Prometheus ignores the histograms without buckets
+	stats.Buckets.Set(metricPrefix+".rpc.duration_seconds", 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30, 60)
+	stats.Buckets.Set(metricPrefix+".compile.duration_seconds", 0.5, 1, 2, 5, 10, 20, 30, 60, 120, 300)
+	enabled = true
+
+	mux := newServeMux(ph, settings.GetString("metrics.pprof_token"))
 
 	// Configure using viper settings
-	serverAddr := configuration.Settings.GetString("metrics.addr")
+	serverAddr := settings.GetString("metrics.addr")
 	logrus.Infof("Setting up Prometheus metrics on %s%s", serverAddr, serverPattern)
 	go func() {
-		http.Handle(serverPattern, ph)
-		logrus.Error(http.ListenAndServe(serverAddr, nil))
+		logrus.Error(http.ListenAndServe(serverAddr, mux))
 	}()
 
 }
 
+// newServeMux returns the mux of the metrics server. Profiling endpoints expose
+// the internals of the process, they are registered only if pprofToken is not
+// empty and are available only to the clients that know it
+func newServeMux(metricsHandler http.Handler, pprofToken string) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle(serverPattern, metricsHandler)
+	if pprofToken != "" {
+		mux.Handle(pprofPattern, requireToken(pprofToken, pprofHandler()))
+	}
+	return mux
+}
+
+// pprofHandler returns a handler serving the net/http/pprof endpoints
+func pprofHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(pprofPattern, pprof.Index)
+	mux.HandleFunc(pprofPattern+"cmdline", pprof.Cmdline)
+	mux.HandleFunc(pprofPattern+"profile", pprof.Profile)
+	mux.HandleFunc(pprofPattern+"symbol", pprof.Symbol)
+	mux.HandleFunc(pprofPattern+"trace", pprof.Trace)
+	return mux
+}
+
+// requireToken wraps the handler so that only the requests carrying the
+// "Authorization: Bearer <token>" header are served, an empty token rejects
+// every request
+func requireToken(token string, handler http.Handler) http.Handler {
+	expected := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token == "" || subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
 // Sanitize uses config generated UUID (installation.secret) as an HMAC secret to sanitize and anonymize
 // a string, maintaining it distinguishable from a different string from the same Installation
 func Sanitize(s string) string {
@@ -0,0 +1,83 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequireToken(t *testing.T) {
+	handler := requireToken("secret", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	check := func(authorization string, expected int) {
+		req := httptest.NewRequest("GET", "/debug/pprof/", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		require.Equal(t, expected, rec.Code, authorization)
+	}
+	check("", http.StatusUnauthorized)
+	check("Bearer wrong", http.StatusUnauthorized)
+	check("secret", http.StatusUnauthorized)
+	check("Bearer secret", http.StatusOK)
+}
+
+func TestRequireEmptyToken(t *testing.T) {
+	handler := requireToken("", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	for _, authorization := range []string{"", "Bearer ", "Bearer"} {
+		req := httptest.NewRequest("GET", "/debug/pprof/", nil)
+		req.Header.Set("Authorization", authorization)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusUnauthorized, rec.Code, authorization)
+	}
+}
+
+func TestServeMuxPprof(t *testing.T) {
+	metricsHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	get := func(mux *http.ServeMux, path, authorization string) int {
+		req := httptest.NewRequest("GET", path, nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// Profiling is disabled without a token
+	mux := newServeMux(metricsHandler, "")
+	require.Equal(t, http.StatusOK, get(mux, "/metrics", ""))
+	require.Equal(t, http.StatusNotFound, get(mux, "/debug/pprof/", ""))
+	require.Equal(t, http.StatusNotFound, get(mux, "/debug/pprof/", "Bearer "))
+
+	mux = newServeMux(metricsHandler, "secret")
+	require.Equal(t, http.StatusOK, get(mux, "/metrics", ""))
+	require.Equal(t, http.StatusUnauthorized, get(mux, "/debug/pprof/", ""))
+	require.Equal(t, http.StatusOK, get(mux, "/debug/pprof/", "Bearer secret"))
+}
@@ -40,6 +40,10 @@ type ArduinoCoreServiceClient interface {
 	LoadSketch(ctx context.Context, in *LoadSketchRequest, opts ...grpc.CallOption) (*LoadSketchResponse, error)
 	// Creates a zip file containing all files of specified Sketch
 	ArchiveSketch(ctx context.Context, in *ArchiveSketchRequest, opts ...grpc.CallOption) (*ArchiveSketchResponse, error)
+	// Clones a Sketch from a git repository
+	CloneSketch(ctx context.Context, in *CloneSketchRequest, opts ...grpc.CallOption) (*CloneSketchResponse, error)
+	// Creates a Sketch from a project of another build system
+	ImportSketch(ctx context.Context, in *ImportSketchRequest, opts ...grpc.CallOption) (*ImportSketchResponse, error)
 	// Requests details about a board
 	BoardDetails(ctx context.Context, in *BoardDetailsRequest, opts ...grpc.CallOption) (*BoardDetailsResponse, error)
 	// Attach a board to a sketch. When the `fqbn` field of a request is not
@@ -73,10 +77,26 @@ type ArduinoCoreServiceClient interface {
 	ListProgrammersAvailableForUpload(ctx context.Context, in *ListProgrammersAvailableForUploadRequest, opts ...grpc.CallOption) (*ListProgrammersAvailableForUploadResponse, error)
 	// Burn bootloader to a board.
 	BurnBootloader(ctx context.Context, in *BurnBootloaderRequest, opts ...grpc.CallOption) (ArduinoCoreService_BurnBootloaderClient, error)
+	// Read the fuses and the lock bits of a board using a programmer.
+	FusesRead(ctx context.Context, in *FusesReadRequest, opts ...grpc.CallOption) (ArduinoCoreService_FusesReadClient, error)
+	// Read the flash or the EEPROM of a board.
+	MemoryRead(ctx context.Context, in *MemoryReadRequest, opts ...grpc.CallOption) (ArduinoCoreService_MemoryReadClient, error)
+	// Write the EEPROM of a board.
+	MemoryWrite(ctx context.Context, in *MemoryWriteRequest, opts ...grpc.CallOption) (ArduinoCoreService_MemoryWriteClient, error)
+	// Build the filesystem image of the data folder of a sketch.
+	FilesystemBuild(ctx context.Context, in *FilesystemBuildRequest, opts ...grpc.CallOption) (ArduinoCoreService_FilesystemBuildClient, error)
+	// Build the filesystem image of a sketch and upload it to a board.
+	FilesystemUpload(ctx context.Context, in *FilesystemUploadRequest, opts ...grpc.CallOption) (ArduinoCoreService_FilesystemUploadClient, error)
 	// Search for a platform in the platforms indexes.
 	PlatformSearch(ctx context.Context, in *PlatformSearchRequest, opts ...grpc.CallOption) (*PlatformSearchResponse, error)
 	// List all installed platforms.
 	PlatformList(ctx context.Context, in *PlatformListRequest, opts ...grpc.CallOption) (*PlatformListResponse, error)
+	// Describe a platform release, with the tools it requires and their
+	// download sizes.
+	PlatformDetails(ctx context.Context, in *PlatformDetailsRequest, opts ...grpc.CallOption) (*PlatformDetailsResponse, error)
+	// Remove the installed tools that are not required by any installed
+	// platform.
+	ToolsGarbageCollect(ctx context.Context, in *ToolsGarbageCollectRequest, opts ...grpc.CallOption) (*ToolsGarbageCollectResponse, error)
 	// Download the archive file of an Arduino library in the libraries index to
 	// the staging directory.
 	LibraryDownload(ctx context.Context, in *LibraryDownloadRequest, opts ...grpc.CallOption) (ArduinoCoreService_LibraryDownloadClient, error)
@@ -321,6 +341,24 @@ func (c *arduinoCoreServiceClient) ArchiveSketch(ctx context.Context, in *Archiv
 	return out, nil
 }
 
+func (c *arduinoCoreServiceClient) CloneSketch(ctx context.Context, in *CloneSketchRequest, opts ...grpc.CallOption) (*CloneSketchResponse, error) {
+	out := new(CloneSketchResponse)
+	err := c.cc.Invoke(ctx, "/cc.arduino.cli.commands.v1.ArduinoCoreService/CloneSketch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *arduinoCoreServiceClient) ImportSketch(ctx context.Context, in *ImportSketchRequest, opts ...grpc.CallOption) (*ImportSketchResponse, error) {
+	out := new(ImportSketchResponse)
+	err := c.cc.Invoke(ctx, "/cc.arduino.cli.commands.v1.ArduinoCoreService/ImportSketch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *arduinoCoreServiceClient) BoardDetails(ctx context.Context, in *BoardDetailsRequest, opts ...grpc.CallOption) (*BoardDetailsResponse, error) {
 	out := new(BoardDetailsResponse)
 	err := c.cc.Invoke(ctx, "/cc.arduino.cli.commands.v1.ArduinoCoreService/BoardDetails", in, out, opts...)
@@ -685,6 +723,166 @@ func (x *arduinoCoreServiceBurnBootloaderClient) Recv() (*BurnBootloaderResponse
 	return m, nil
 }
 
+func (c *arduinoCoreServiceClient) FusesRead(ctx context.Context, in *FusesReadRequest, opts ...grpc.CallOption) (ArduinoCoreService_FusesReadClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ArduinoCoreService_serviceDesc.Streams[15], "/cc.arduino.cli.commands.v1.ArduinoCoreService/FusesRead", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &arduinoCoreServiceFusesReadClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ArduinoCoreService_FusesReadClient interface {
+	Recv() (*FusesReadResponse, error)
+	grpc.ClientStream
+}
+
+type arduinoCoreServiceFusesReadClient struct {
+	grpc.ClientStream
+}
+
+func (x *arduinoCoreServiceFusesReadClient) Recv() (*FusesReadResponse, error) {
+	m := new(FusesReadResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *arduinoCoreServiceClient) MemoryRead(ctx context.Context, in *MemoryReadRequest, opts ...grpc.CallOption) (ArduinoCoreService_MemoryReadClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ArduinoCoreService_serviceDesc.Streams[16], "/cc.arduino.cli.commands.v1.ArduinoCoreService/MemoryRead", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &arduinoCoreServiceMemoryReadClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ArduinoCoreService_MemoryReadClient interface {
+	Recv() (*MemoryReadResponse, error)
+	grpc.ClientStream
+}
+
+type arduinoCoreServiceMemoryReadClient struct {
+	grpc.ClientStream
+}
+
+func (x *arduinoCoreServiceMemoryReadClient) Recv() (*MemoryReadResponse, error) {
+	m := new(MemoryReadResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *arduinoCoreServiceClient) MemoryWrite(ctx context.Context, in *MemoryWriteRequest, opts ...grpc.CallOption) (ArduinoCoreService_MemoryWriteClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ArduinoCoreService_serviceDesc.Streams[17], "/cc.arduino.cli.commands.v1.ArduinoCoreService/MemoryWrite", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &arduinoCoreServiceMemoryWriteClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ArduinoCoreService_MemoryWriteClient interface {
+	Recv() (*MemoryWriteResponse, error)
+	grpc.ClientStream
+}
+
+type arduinoCoreServiceMemoryWriteClient struct {
+	grpc.ClientStream
+}
+
+func (x *arduinoCoreServiceMemoryWriteClient) Recv() (*MemoryWriteResponse, error) {
+	m := new(MemoryWriteResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *arduinoCoreServiceClient) FilesystemBuild(ctx context.Context, in *FilesystemBuildRequest, opts ...grpc.CallOption) (ArduinoCoreService_FilesystemBuildClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ArduinoCoreService_serviceDesc.Streams[18], "/cc.arduino.cli.commands.v1.ArduinoCoreService/FilesystemBuild", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &arduinoCoreServiceFilesystemBuildClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ArduinoCoreService_FilesystemBuildClient interface {
+	Recv() (*FilesystemBuildResponse, error)
+	grpc.ClientStream
+}
+
+type arduinoCoreServiceFilesystemBuildClient struct {
+	grpc.ClientStream
+}
+
+func (x *arduinoCoreServiceFilesystemBuildClient) Recv() (*FilesystemBuildResponse, error) {
+	m := new(FilesystemBuildResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *arduinoCoreServiceClient) FilesystemUpload(ctx context.Context, in *FilesystemUploadRequest, opts ...grpc.CallOption) (ArduinoCoreService_FilesystemUploadClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ArduinoCoreService_serviceDesc.Streams[19], "/cc.arduino.cli.commands.v1.ArduinoCoreService/FilesystemUpload", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &arduinoCoreServiceFilesystemUploadClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ArduinoCoreService_FilesystemUploadClient interface {
+	Recv() (*FilesystemUploadResponse, error)
+	grpc.ClientStream
+}
+
+type arduinoCoreServiceFilesystemUploadClient struct {
+	grpc.ClientStream
+}
+
+func (x *arduinoCoreServiceFilesystemUploadClient) Recv() (*FilesystemUploadResponse, error) {
+	m := new(FilesystemUploadResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *arduinoCoreServiceClient) PlatformSearch(ctx context.Context, in *PlatformSearchRequest, opts ...grpc.CallOption) (*PlatformSearchResponse, error) {
 	out := new(PlatformSearchResponse)
 	err := c.cc.Invoke(ctx, "/cc.arduino.cli.commands.v1.ArduinoCoreService/PlatformSearch", in, out, opts...)
@@ -703,8 +901,26 @@ func (c *arduinoCoreServiceClient) PlatformList(ctx context.Context, in *Platfor
 	return out, nil
 }
 
+func (c *arduinoCoreServiceClient) PlatformDetails(ctx context.Context, in *PlatformDetailsRequest, opts ...grpc.CallOption) (*PlatformDetailsResponse, error) {
+	out := new(PlatformDetailsResponse)
+	err := c.cc.Invoke(ctx, "/cc.arduino.cli.commands.v1.ArduinoCoreService/PlatformDetails", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *arduinoCoreServiceClient) ToolsGarbageCollect(ctx context.Context, in *ToolsGarbageCollectRequest, opts ...grpc.CallOption) (*ToolsGarbageCollectResponse, error) {
+	out := new(ToolsGarbageCollectResponse)
+	err := c.cc.Invoke(ctx, "/cc.arduino.cli.commands.v1.ArduinoCoreService/ToolsGarbageCollect", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *arduinoCoreServiceClient) LibraryDownload(ctx context.Context, in *LibraryDownloadRequest, opts ...grpc.CallOption) (ArduinoCoreService_LibraryDownloadClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_ArduinoCoreService_serviceDesc.Streams[15], "/cc.arduino.cli.commands.v1.ArduinoCoreService/LibraryDownload", opts...)
+	stream, err := c.cc.NewStream(ctx, &_ArduinoCoreService_serviceDesc.Streams[20], "/cc.arduino.cli.commands.v1.ArduinoCoreService/LibraryDownload", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -736,7 +952,7 @@ func (x *arduinoCoreServiceLibraryDownloadClient) Recv() (*LibraryDownloadRespon
 }
 
 func (c *arduinoCoreServiceClient) LibraryInstall(ctx context.Context, in *LibraryInstallRequest, opts ...grpc.CallOption) (ArduinoCoreService_LibraryInstallClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_ArduinoCoreService_serviceDesc.Streams[16], "/cc.arduino.cli.commands.v1.ArduinoCoreService/LibraryInstall", opts...)
+	stream, err := c.cc.NewStream(ctx, &_ArduinoCoreService_serviceDesc.Streams[21], "/cc.arduino.cli.commands.v1.ArduinoCoreService/LibraryInstall", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -768,7 +984,7 @@ func (x *arduinoCoreServiceLibraryInstallClient) Recv() (*LibraryInstallResponse
 }
 
 func (c *arduinoCoreServiceClient) ZipLibraryInstall(ctx context.Context, in *ZipLibraryInstallRequest, opts ...grpc.CallOption) (ArduinoCoreService_ZipLibraryInstallClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_ArduinoCoreService_serviceDesc.Streams[17], "/cc.arduino.cli.commands.v1.ArduinoCoreService/ZipLibraryInstall", opts...)
+	stream, err := c.cc.NewStream(ctx, &_ArduinoCoreService_serviceDesc.Streams[22], "/cc.arduino.cli.commands.v1.ArduinoCoreService/ZipLibraryInstall", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -800,7 +1016,7 @@ func (x *arduinoCoreServiceZipLibraryInstallClient) Recv() (*ZipLibraryInstallRe
 }
 
 func (c *arduinoCoreServiceClient) GitLibraryInstall(ctx context.Context, in *GitLibraryInstallRequest, opts ...grpc.CallOption) (ArduinoCoreService_GitLibraryInstallClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_ArduinoCoreService_serviceDesc.Streams[18], "/cc.arduino.cli.commands.v1.ArduinoCoreService/GitLibraryInstall", opts...)
+	stream, err := c.cc.NewStream(ctx, &_ArduinoCoreService_serviceDesc.Streams[23], "/cc.arduino.cli.commands.v1.ArduinoCoreService/GitLibraryInstall", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -832,7 +1048,7 @@ func (x *arduinoCoreServiceGitLibraryInstallClient) Recv() (*GitLibraryInstallRe
 }
 
 func (c *arduinoCoreServiceClient) LibraryUninstall(ctx context.Context, in *LibraryUninstallRequest, opts ...grpc.CallOption) (ArduinoCoreService_LibraryUninstallClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_ArduinoCoreService_serviceDesc.Streams[19], "/cc.arduino.cli.commands.v1.ArduinoCoreService/LibraryUninstall", opts...)
+	stream, err := c.cc.NewStream(ctx, &_ArduinoCoreService_serviceDesc.Streams[24], "/cc.arduino.cli.commands.v1.ArduinoCoreService/LibraryUninstall", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -864,7 +1080,7 @@ func (x *arduinoCoreServiceLibraryUninstallClient) Recv() (*LibraryUninstallResp
 }
 
 func (c *arduinoCoreServiceClient) LibraryUpgradeAll(ctx context.Context, in *LibraryUpgradeAllRequest, opts ...grpc.CallOption) (ArduinoCoreService_LibraryUpgradeAllClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_ArduinoCoreService_serviceDesc.Streams[20], "/cc.arduino.cli.commands.v1.ArduinoCoreService/LibraryUpgradeAll", opts...)
+	stream, err := c.cc.NewStream(ctx, &_ArduinoCoreService_serviceDesc.Streams[25], "/cc.arduino.cli.commands.v1.ArduinoCoreService/LibraryUpgradeAll", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -949,6 +1165,10 @@ type ArduinoCoreServiceServer interface {
 	LoadSketch(context.Context, *LoadSketchRequest) (*LoadSketchResponse, error)
 	// Creates a zip file containing all files of specified Sketch
 	ArchiveSketch(context.Context, *ArchiveSketchRequest) (*ArchiveSketchResponse, error)
+	// Clones a Sketch from a git repository
+	CloneSketch(context.Context, *CloneSketchRequest) (*CloneSketchResponse, error)
+	// Creates a Sketch from a project of another build system
+	ImportSketch(context.Context, *ImportSketchRequest) (*ImportSketchResponse, error)
 	// Requests details about a board
 	BoardDetails(context.Context, *BoardDetailsRequest) (*BoardDetailsResponse, error)
 	// Attach a board to a sketch. When the `fqbn` field of a request is not
@@ -982,10 +1202,26 @@ type ArduinoCoreServiceServer interface {
 	ListProgrammersAvailableForUpload(context.Context, *ListProgrammersAvailableForUploadRequest) (*ListProgrammersAvailableForUploadResponse, error)
 	// Burn bootloader to a board.
 	BurnBootloader(*BurnBootloaderRequest, ArduinoCoreService_BurnBootloaderServer) error
+	// Read the fuses and the lock bits of a board using a programmer.
+	FusesRead(*FusesReadRequest, ArduinoCoreService_FusesReadServer) error
+	// Read the flash or the EEPROM of a board.
+	MemoryRead(*MemoryReadRequest, ArduinoCoreService_MemoryReadServer) error
+	// Write the EEPROM of a board.
+	MemoryWrite(*MemoryWriteRequest, ArduinoCoreService_MemoryWriteServer) error
+	// Build the filesystem image of the data folder of a sketch.
+	FilesystemBuild(*FilesystemBuildRequest, ArduinoCoreService_FilesystemBuildServer) error
+	// Build the filesystem image of a sketch and upload it to a board.
+	FilesystemUpload(*FilesystemUploadRequest, ArduinoCoreService_FilesystemUploadServer) error
 	// Search for a platform in the platforms indexes.
 	PlatformSearch(context.Context, *PlatformSearchRequest) (*PlatformSearchResponse, error)
 	// List all installed platforms.
 	PlatformList(context.Context, *PlatformListRequest) (*PlatformListResponse, error)
+	// Describe a platform release, with the tools it requires and their
+	// download sizes.
+	PlatformDetails(context.Context, *PlatformDetailsRequest) (*PlatformDetailsResponse, error)
+	// Remove the installed tools that are not required by any installed
+	// platform.
+	ToolsGarbageCollect(context.Context, *ToolsGarbageCollectRequest) (*ToolsGarbageCollectResponse, error)
 	// Download the archive file of an Arduino library in the libraries index to
 	// the staging directory.
 	LibraryDownload(*LibraryDownloadRequest, ArduinoCoreService_LibraryDownloadServer) error
@@ -1046,6 +1282,12 @@ func (UnimplementedArduinoCoreServiceServer) LoadSketch(context.Context, *LoadSk
 func (UnimplementedArduinoCoreServiceServer) ArchiveSketch(context.Context, *ArchiveSketchRequest) (*ArchiveSketchResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ArchiveSketch not implemented")
 }
+func (UnimplementedArduinoCoreServiceServer) CloneSketch(context.Context, *CloneSketchRequest) (*CloneSketchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloneSketch not implemented")
+}
+func (UnimplementedArduinoCoreServiceServer) ImportSketch(context.Context, *ImportSketchRequest) (*ImportSketchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportSketch not implemented")
+}
 func (UnimplementedArduinoCoreServiceServer) BoardDetails(context.Context, *BoardDetailsRequest) (*BoardDetailsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BoardDetails not implemented")
 }
@@ -1091,12 +1333,33 @@ func (UnimplementedArduinoCoreServiceServer) ListProgrammersAvailableForUpload(c
 func (UnimplementedArduinoCoreServiceServer) BurnBootloader(*BurnBootloaderRequest, ArduinoCoreService_BurnBootloaderServer) error {
 	return status.Errorf(codes.Unimplemented, "method BurnBootloader not implemented")
 }
+func (UnimplementedArduinoCoreServiceServer) FusesRead(*FusesReadRequest, ArduinoCoreService_FusesReadServer) error {
+	return status.Errorf(codes.Unimplemented, "method FusesRead not implemented")
+}
+func (UnimplementedArduinoCoreServiceServer) MemoryRead(*MemoryReadRequest, ArduinoCoreService_MemoryReadServer) error {
+	return status.Errorf(codes.Unimplemented, "method MemoryRead not implemented")
+}
+func (UnimplementedArduinoCoreServiceServer) MemoryWrite(*MemoryWriteRequest, ArduinoCoreService_MemoryWriteServer) error {
+	return status.Errorf(codes.Unimplemented, "method MemoryWrite not implemented")
+}
+func (UnimplementedArduinoCoreServiceServer) FilesystemBuild(*FilesystemBuildRequest, ArduinoCoreService_FilesystemBuildServer) error {
+	return status.Errorf(codes.Unimplemented, "method FilesystemBuild not implemented")
+}
+func (UnimplementedArduinoCoreServiceServer) FilesystemUpload(*FilesystemUploadRequest, ArduinoCoreService_FilesystemUploadServer) error {
+	return status.Errorf(codes.Unimplemented, "method FilesystemUpload not implemented")
+}
 func (UnimplementedArduinoCoreServiceServer) PlatformSearch(context.Context, *PlatformSearchRequest) (*PlatformSearchResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PlatformSearch not implemented")
 }
 func (UnimplementedArduinoCoreServiceServer) PlatformList(context.Context, *PlatformListRequest) (*PlatformListResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PlatformList not implemented")
 }
+func (UnimplementedArduinoCoreServiceServer) PlatformDetails(context.Context, *PlatformDetailsRequest) (*PlatformDetailsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PlatformDetails not implemented")
+}
+func (UnimplementedArduinoCoreServiceServer) ToolsGarbageCollect(context.Context, *ToolsGarbageCollectRequest) (*ToolsGarbageCollectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ToolsGarbageCollect not implemented")
+}
 func (UnimplementedArduinoCoreServiceServer) LibraryDownload(*LibraryDownloadRequest, ArduinoCoreService_LibraryDownloadServer) error {
 	return status.Errorf(codes.Unimplemented, "method LibraryDownload not implemented")
 }
@@ -1350,6 +1613,42 @@ func _ArduinoCoreService_ArchiveSketch_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ArduinoCoreService_CloneSketch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloneSketchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArduinoCoreServiceServer).CloneSketch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cc.arduino.cli.commands.v1.ArduinoCoreService/CloneSketch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArduinoCoreServiceServer).CloneSketch(ctx, req.(*CloneSketchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ArduinoCoreService_ImportSketch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportSketchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArduinoCoreServiceServer).ImportSketch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cc.arduino.cli.commands.v1.ArduinoCoreService/ImportSketch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArduinoCoreServiceServer).ImportSketch(ctx, req.(*ImportSketchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ArduinoCoreService_BoardDetails_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(BoardDetailsRequest)
 	if err := dec(in); err != nil {
@@ -1655,6 +1954,111 @@ func (x *arduinoCoreServiceBurnBootloaderServer) Send(m *BurnBootloaderResponse)
 	return x.ServerStream.SendMsg(m)
 }
 
+func _ArduinoCoreService_FusesRead_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FusesReadRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ArduinoCoreServiceServer).FusesRead(m, &arduinoCoreServiceFusesReadServer{stream})
+}
+
+type ArduinoCoreService_FusesReadServer interface {
+	Send(*FusesReadResponse) error
+	grpc.ServerStream
+}
+
+type arduinoCoreServiceFusesReadServer struct {
+	grpc.ServerStream
+}
+
+func (x *arduinoCoreServiceFusesReadServer) Send(m *FusesReadResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ArduinoCoreService_MemoryRead_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(MemoryReadRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ArduinoCoreServiceServer).MemoryRead(m, &arduinoCoreServiceMemoryReadServer{stream})
+}
+
+type ArduinoCoreService_MemoryReadServer interface {
+	Send(*MemoryReadResponse) error
+	grpc.ServerStream
+}
+
+type arduinoCoreServiceMemoryReadServer struct {
+	grpc.ServerStream
+}
+
+func (x *arduinoCoreServiceMemoryReadServer) Send(m *MemoryReadResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ArduinoCoreService_MemoryWrite_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(MemoryWriteRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ArduinoCoreServiceServer).MemoryWrite(m, &arduinoCoreServiceMemoryWriteServer{stream})
+}
+
+type ArduinoCoreService_MemoryWriteServer interface {
+	Send(*MemoryWriteResponse) error
+	grpc.ServerStream
+}
+
+type arduinoCoreServiceMemoryWriteServer struct {
+	grpc.ServerStream
+}
+
+func (x *arduinoCoreServiceMemoryWriteServer) Send(m *MemoryWriteResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ArduinoCoreService_FilesystemBuild_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FilesystemBuildRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ArduinoCoreServiceServer).FilesystemBuild(m, &arduinoCoreServiceFilesystemBuildServer{stream})
+}
+
+type ArduinoCoreService_FilesystemBuildServer interface {
+	Send(*FilesystemBuildResponse) error
+	grpc.ServerStream
+}
+
+type arduinoCoreServiceFilesystemBuildServer struct {
+	grpc.ServerStream
+}
+
+func (x *arduinoCoreServiceFilesystemBuildServer) Send(m *FilesystemBuildResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ArduinoCoreService_FilesystemUpload_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FilesystemUploadRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ArduinoCoreServiceServer).FilesystemUpload(m, &arduinoCoreServiceFilesystemUploadServer{stream})
+}
+
+type ArduinoCoreService_FilesystemUploadServer interface {
+	Send(*FilesystemUploadResponse) error
+	grpc.ServerStream
+}
+
+type arduinoCoreServiceFilesystemUploadServer struct {
+	grpc.ServerStream
+}
+
+func (x *arduinoCoreServiceFilesystemUploadServer) Send(m *FilesystemUploadResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _ArduinoCoreService_PlatformSearch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(PlatformSearchRequest)
 	if err := dec(in); err != nil {
@@ -1691,6 +2095,42 @@ func _ArduinoCoreService_PlatformList_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ArduinoCoreService_PlatformDetails_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlatformDetailsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArduinoCoreServiceServer).PlatformDetails(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cc.arduino.cli.commands.v1.ArduinoCoreService/PlatformDetails",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArduinoCoreServiceServer).PlatformDetails(ctx, req.(*PlatformDetailsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ArduinoCoreService_ToolsGarbageCollect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ToolsGarbageCollectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArduinoCoreServiceServer).ToolsGarbageCollect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cc.arduino.cli.commands.v1.ArduinoCoreService/ToolsGarbageCollect",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArduinoCoreServiceServer).ToolsGarbageCollect(ctx, req.(*ToolsGarbageCollectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ArduinoCoreService_LibraryDownload_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(LibraryDownloadRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -1899,6 +2339,14 @@ var _ArduinoCoreService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ArchiveSketch",
 			Handler:    _ArduinoCoreService_ArchiveSketch_Handler,
 		},
+		{
+			MethodName: "CloneSketch",
+			Handler:    _ArduinoCoreService_CloneSketch_Handler,
+		},
+		{
+			MethodName: "ImportSketch",
+			Handler:    _ArduinoCoreService_ImportSketch_Handler,
+		},
 		{
 			MethodName: "BoardDetails",
 			Handler:    _ArduinoCoreService_BoardDetails_Handler,
@@ -1927,6 +2375,14 @@ var _ArduinoCoreService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "PlatformList",
 			Handler:    _ArduinoCoreService_PlatformList_Handler,
 		},
+		{
+			MethodName: "PlatformDetails",
+			Handler:    _ArduinoCoreService_PlatformDetails_Handler,
+		},
+		{
+			MethodName: "ToolsGarbageCollect",
+			Handler:    _ArduinoCoreService_ToolsGarbageCollect_Handler,
+		},
 		{
 			MethodName: "LibraryResolveDependencies",
 			Handler:    _ArduinoCoreService_LibraryResolveDependencies_Handler,
@@ -2017,6 +2473,31 @@ var _ArduinoCoreService_serviceDesc = grpc.ServiceDesc{
 			Handler:       _ArduinoCoreService_BurnBootloader_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "FusesRead",
+			Handler:       _ArduinoCoreService_FusesRead_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "MemoryRead",
+			Handler:       _ArduinoCoreService_MemoryRead_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "MemoryWrite",
+			Handler:       _ArduinoCoreService_MemoryWrite_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "FilesystemBuild",
+			Handler:       _ArduinoCoreService_FilesystemBuild_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "FilesystemUpload",
+			Handler:       _ArduinoCoreService_FilesystemUpload_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "LibraryDownload",
 			Handler:       _ArduinoCoreService_LibraryDownload_Handler,
@@ -35,6 +35,59 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type PostInstallScript int32
+
+const (
+	// The platform must be installed, or its archive downloaded, to know if it
+	// has a post-install script.
+	PostInstallScript_POST_INSTALL_SCRIPT_UNKNOWN PostInstallScript = 0
+	// The platform doesn't have a post-install script.
+	PostInstallScript_POST_INSTALL_SCRIPT_NONE PostInstallScript = 1
+	// The platform has a post-install script for the running OS.
+	PostInstallScript_POST_INSTALL_SCRIPT_PRESENT PostInstallScript = 2
+)
+
+// Enum value maps for PostInstallScript.
+var (
+	PostInstallScript_name = map[int32]string{
+		0: "POST_INSTALL_SCRIPT_UNKNOWN",
+		1: "POST_INSTALL_SCRIPT_NONE",
+		2: "POST_INSTALL_SCRIPT_PRESENT",
+	}
+	PostInstallScript_value = map[string]int32{
+		"POST_INSTALL_SCRIPT_UNKNOWN": 0,
+		"POST_INSTALL_SCRIPT_NONE":    1,
+		"POST_INSTALL_SCRIPT_PRESENT": 2,
+	}
+)
+
+func (x PostInstallScript) Enum() *PostInstallScript {
+	p := new(PostInstallScript)
+	*p = x
+	return p
+}
+
+func (x PostInstallScript) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PostInstallScript) Descriptor() protoreflect.EnumDescriptor {
+	return file_cc_arduino_cli_commands_v1_core_proto_enumTypes[0].Descriptor()
+}
+
+func (PostInstallScript) Type() protoreflect.EnumType {
+	return &file_cc_arduino_cli_commands_v1_core_proto_enumTypes[0]
+}
+
+func (x PostInstallScript) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PostInstallScript.Descriptor instead.
+func (PostInstallScript) EnumDescriptor() ([]byte, []int) {
+	return file_cc_arduino_cli_commands_v1_core_proto_rawDescGZIP(), []int{0}
+}
+
 type PlatformInstallRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -778,6 +831,431 @@ func (x *PlatformListResponse) GetInstalledPlatforms() []*Platform {
 	return nil
 }
 
+type ToolsGarbageCollectRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Arduino Core Service instance from the `Init` response.
+	Instance *Instance `protobuf:"bytes,1,opt,name=instance,proto3" json:"instance,omitempty"`
+	// Set to true to only report the tools that would be removed.
+	DryRun bool `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+}
+
+func (x *ToolsGarbageCollectRequest) Reset() {
+	*x = ToolsGarbageCollectRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cc_arduino_cli_commands_v1_core_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ToolsGarbageCollectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ToolsGarbageCollectRequest) ProtoMessage() {}
+
+func (x *ToolsGarbageCollectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cc_arduino_cli_commands_v1_core_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ToolsGarbageCollectRequest.ProtoReflect.Descriptor instead.
+func (*ToolsGarbageCollectRequest) Descriptor() ([]byte, []int) {
+	return file_cc_arduino_cli_commands_v1_core_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ToolsGarbageCollectRequest) GetInstance() *Instance {
+	if x != nil {
+		return x.Instance
+	}
+	return nil
+}
+
+func (x *ToolsGarbageCollectRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type ToolsGarbageCollectResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The tools removed, or that would be removed if `dry_run` is set.
+	Tools []*UnusedTool `protobuf:"bytes,1,rep,name=tools,proto3" json:"tools,omitempty"`
+}
+
+func (x *ToolsGarbageCollectResponse) Reset() {
+	*x = ToolsGarbageCollectResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cc_arduino_cli_commands_v1_core_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ToolsGarbageCollectResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ToolsGarbageCollectResponse) ProtoMessage() {}
+
+func (x *ToolsGarbageCollectResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cc_arduino_cli_commands_v1_core_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ToolsGarbageCollectResponse.ProtoReflect.Descriptor instead.
+func (*ToolsGarbageCollectResponse) Descriptor() ([]byte, []int) {
+	return file_cc_arduino_cli_commands_v1_core_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ToolsGarbageCollectResponse) GetTools() []*UnusedTool {
+	if x != nil {
+		return x.Tools
+	}
+	return nil
+}
+
+type UnusedTool struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Vendor name of the package containing the tool.
+	Packager string `protobuf:"bytes,1,opt,name=packager,proto3" json:"packager,omitempty"`
+	// Tool name.
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Tool version.
+	Version string `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	// Absolute path of the installation folder of the tool.
+	InstallDir string `protobuf:"bytes,4,opt,name=install_dir,json=installDir,proto3" json:"install_dir,omitempty"`
+	// Disk space used by the tool, in bytes.
+	Size int64 `protobuf:"varint,5,opt,name=size,proto3" json:"size,omitempty"`
+}
+
+func (x *UnusedTool) Reset() {
+	*x = UnusedTool{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cc_arduino_cli_commands_v1_core_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UnusedTool) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnusedTool) ProtoMessage() {}
+
+func (x *UnusedTool) ProtoReflect() protoreflect.Message {
+	mi := &file_cc_arduino_cli_commands_v1_core_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnusedTool.ProtoReflect.Descriptor instead.
+func (*UnusedTool) Descriptor() ([]byte, []int) {
+	return file_cc_arduino_cli_commands_v1_core_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *UnusedTool) GetPackager() string {
+	if x != nil {
+		return x.Packager
+	}
+	return ""
+}
+
+func (x *UnusedTool) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UnusedTool) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *UnusedTool) GetInstallDir() string {
+	if x != nil {
+		return x.InstallDir
+	}
+	return ""
+}
+
+func (x *UnusedTool) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+type PlatformDetailsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Arduino Core Service instance from the `Init` response.
+	Instance *Instance `protobuf:"bytes,1,opt,name=instance,proto3" json:"instance,omitempty"`
+	// Vendor name of the platform (e.g., `arduino`).
+	PlatformPackage string `protobuf:"bytes,2,opt,name=platform_package,json=platformPackage,proto3" json:"platform_package,omitempty"`
+	// Architecture name of the platform (e.g., `avr`).
+	Architecture string `protobuf:"bytes,3,opt,name=architecture,proto3" json:"architecture,omitempty"`
+	// Platform version to describe, the latest if not set.
+	Version string `protobuf:"bytes,4,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+func (x *PlatformDetailsRequest) Reset() {
+	*x = PlatformDetailsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cc_arduino_cli_commands_v1_core_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PlatformDetailsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlatformDetailsRequest) ProtoMessage() {}
+
+func (x *PlatformDetailsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cc_arduino_cli_commands_v1_core_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlatformDetailsRequest.ProtoReflect.Descriptor instead.
+func (*PlatformDetailsRequest) Descriptor() ([]byte, []int) {
+	return file_cc_arduino_cli_commands_v1_core_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *PlatformDetailsRequest) GetInstance() *Instance {
+	if x != nil {
+		return x.Instance
+	}
+	return nil
+}
+
+func (x *PlatformDetailsRequest) GetPlatformPackage() string {
+	if x != nil {
+		return x.PlatformPackage
+	}
+	return ""
+}
+
+func (x *PlatformDetailsRequest) GetArchitecture() string {
+	if x != nil {
+		return x.Architecture
+	}
+	return ""
+}
+
+func (x *PlatformDetailsRequest) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+type PlatformDetailsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The platform release. The `installed` field is the version currently
+	// installed, that may be different from the described one.
+	Platform *Platform `protobuf:"bytes,1,opt,name=platform,proto3" json:"platform,omitempty"`
+	// Size of the platform archive, in bytes.
+	Size int64 `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	// The tools required by the platform.
+	Tools []*PlatformToolDetails `protobuf:"bytes,3,rep,name=tools,proto3" json:"tools,omitempty"`
+	// Tells if the platform runs a post-install script, that may install
+	// drivers or require administrator privileges.
+	PostInstall PostInstallScript `protobuf:"varint,4,opt,name=post_install,json=postInstall,proto3,enum=cc.arduino.cli.commands.v1.PostInstallScript" json:"post_install,omitempty"`
+}
+
+func (x *PlatformDetailsResponse) Reset() {
+	*x = PlatformDetailsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cc_arduino_cli_commands_v1_core_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PlatformDetailsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlatformDetailsResponse) ProtoMessage() {}
+
+func (x *PlatformDetailsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cc_arduino_cli_commands_v1_core_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlatformDetailsResponse.ProtoReflect.Descriptor instead.
+func (*PlatformDetailsResponse) Descriptor() ([]byte, []int) {
+	return file_cc_arduino_cli_commands_v1_core_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *PlatformDetailsResponse) GetPlatform() *Platform {
+	if x != nil {
+		return x.Platform
+	}
+	return nil
+}
+
+func (x *PlatformDetailsResponse) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *PlatformDetailsResponse) GetTools() []*PlatformToolDetails {
+	if x != nil {
+		return x.Tools
+	}
+	return nil
+}
+
+func (x *PlatformDetailsResponse) GetPostInstall() PostInstallScript {
+	if x != nil {
+		return x.PostInstall
+	}
+	return PostInstallScript_POST_INSTALL_SCRIPT_UNKNOWN
+}
+
+type PlatformToolDetails struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Vendor name of the package containing the tool.
+	Packager string `protobuf:"bytes,1,opt,name=packager,proto3" json:"packager,omitempty"`
+	// Tool name.
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Tool version.
+	Version string `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	// Size of the tool archive for the running OS, in bytes.
+	Size int64 `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	// True if the tool is already installed.
+	Installed bool `protobuf:"varint,5,opt,name=installed,proto3" json:"installed,omitempty"`
+}
+
+func (x *PlatformToolDetails) Reset() {
+	*x = PlatformToolDetails{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cc_arduino_cli_commands_v1_core_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PlatformToolDetails) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlatformToolDetails) ProtoMessage() {}
+
+func (x *PlatformToolDetails) ProtoReflect() protoreflect.Message {
+	mi := &file_cc_arduino_cli_commands_v1_core_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlatformToolDetails.ProtoReflect.Descriptor instead.
+func (*PlatformToolDetails) Descriptor() ([]byte, []int) {
+	return file_cc_arduino_cli_commands_v1_core_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *PlatformToolDetails) GetPackager() string {
+	if x != nil {
+		return x.Packager
+	}
+	return ""
+}
+
+func (x *PlatformToolDetails) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PlatformToolDetails) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *PlatformToolDetails) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *PlatformToolDetails) GetInstalled() bool {
+	if x != nil {
+		return x.Installed
+	}
+	return false
+}
+
 var File_cc_arduino_cli_commands_v1_core_proto protoreflect.FileDescriptor
 
 var file_cc_arduino_cli_commands_v1_core_proto_rawDesc = []byte{
@@ -907,12 +1385,80 @@ var file_cc_arduino_cli_commands_v1_core_proto_rawDesc = []byte{
 	0x20, 0x03, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x63, 0x63, 0x2e, 0x61, 0x72, 0x64, 0x75, 0x69, 0x6e,
 	0x6f, 0x2e, 0x63, 0x6c, 0x69, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x2e, 0x76,
 	0x31, 0x2e, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x52, 0x12, 0x69, 0x6e, 0x73, 0x74,
-	0x61, 0x6c, 0x6c, 0x65, 0x64, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x73, 0x42, 0x48,
-	0x5a, 0x46, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x72, 0x64,
-	0x75, 0x69, 0x6e, 0x6f, 0x2f, 0x61, 0x72, 0x64, 0x75, 0x69, 0x6e, 0x6f, 0x2d, 0x63, 0x6c, 0x69,
-	0x2f, 0x72, 0x70, 0x63, 0x2f, 0x63, 0x63, 0x2f, 0x61, 0x72, 0x64, 0x75, 0x69, 0x6e, 0x6f, 0x2f,
-	0x63, 0x6c, 0x69, 0x2f, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x2f, 0x76, 0x31, 0x3b,
-	0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x61, 0x6c, 0x6c, 0x65, 0x64, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x73, 0x22, 0x77,
+	0x0a, 0x1a, 0x54, 0x6f, 0x6f, 0x6c, 0x73, 0x47, 0x61, 0x72, 0x62, 0x61, 0x67, 0x65, 0x43, 0x6f,
+	0x6c, 0x6c, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x40, 0x0a, 0x08,
+	0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24,
+	0x2e, 0x63, 0x63, 0x2e, 0x61, 0x72, 0x64, 0x75, 0x69, 0x6e, 0x6f, 0x2e, 0x63, 0x6c, 0x69, 0x2e,
+	0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x73, 0x74,
+	0x61, 0x6e, 0x63, 0x65, 0x52, 0x08, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x17,
+	0x0a, 0x07, 0x64, 0x72, 0x79, 0x5f, 0x72, 0x75, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x06, 0x64, 0x72, 0x79, 0x52, 0x75, 0x6e, 0x22, 0x5b, 0x0a, 0x1b, 0x54, 0x6f, 0x6f, 0x6c, 0x73,
+	0x47, 0x61, 0x72, 0x62, 0x61, 0x67, 0x65, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3c, 0x0a, 0x05, 0x74, 0x6f, 0x6f, 0x6c, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x63, 0x63, 0x2e, 0x61, 0x72, 0x64, 0x75, 0x69,
+	0x6e, 0x6f, 0x2e, 0x63, 0x6c, 0x69, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x2e,
+	0x76, 0x31, 0x2e, 0x55, 0x6e, 0x75, 0x73, 0x65, 0x64, 0x54, 0x6f, 0x6f, 0x6c, 0x52, 0x05, 0x74,
+	0x6f, 0x6f, 0x6c, 0x73, 0x22, 0x8b, 0x01, 0x0a, 0x0a, 0x55, 0x6e, 0x75, 0x73, 0x65, 0x64, 0x54,
+	0x6f, 0x6f, 0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x72, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x72, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1f, 0x0a,
+	0x0b, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x5f, 0x64, 0x69, 0x72, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0a, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x44, 0x69, 0x72, 0x12, 0x12,
+	0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x73, 0x69,
+	0x7a, 0x65, 0x22, 0xc3, 0x01, 0x0a, 0x16, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x44,
+	0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x40, 0x0a,
+	0x08, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x24, 0x2e, 0x63, 0x63, 0x2e, 0x61, 0x72, 0x64, 0x75, 0x69, 0x6e, 0x6f, 0x2e, 0x63, 0x6c, 0x69,
+	0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x73,
+	0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x08, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x12,
+	0x29, 0x0a, 0x10, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x5f, 0x70, 0x61, 0x63, 0x6b,
+	0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x70, 0x6c, 0x61, 0x74, 0x66,
+	0x6f, 0x72, 0x6d, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x61, 0x72,
+	0x63, 0x68, 0x69, 0x74, 0x65, 0x63, 0x74, 0x75, 0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0c, 0x61, 0x72, 0x63, 0x68, 0x69, 0x74, 0x65, 0x63, 0x74, 0x75, 0x72, 0x65, 0x12, 0x18,
+	0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x88, 0x02, 0x0a, 0x17, 0x50, 0x6c, 0x61,
+	0x74, 0x66, 0x6f, 0x72, 0x6d, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x40, 0x0a, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x63, 0x63, 0x2e, 0x61, 0x72, 0x64, 0x75,
+	0x69, 0x6e, 0x6f, 0x2e, 0x63, 0x6c, 0x69, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73,
+	0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x52, 0x08, 0x70, 0x6c,
+	0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x45, 0x0a, 0x05, 0x74, 0x6f,
+	0x6f, 0x6c, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x63, 0x63, 0x2e, 0x61,
+	0x72, 0x64, 0x75, 0x69, 0x6e, 0x6f, 0x2e, 0x63, 0x6c, 0x69, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x61,
+	0x6e, 0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x54,
+	0x6f, 0x6f, 0x6c, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x52, 0x05, 0x74, 0x6f, 0x6f, 0x6c,
+	0x73, 0x12, 0x50, 0x0a, 0x0c, 0x70, 0x6f, 0x73, 0x74, 0x5f, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6c,
+	0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x2d, 0x2e, 0x63, 0x63, 0x2e, 0x61, 0x72, 0x64,
+	0x75, 0x69, 0x6e, 0x6f, 0x2e, 0x63, 0x6c, 0x69, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
+	0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x73, 0x74, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c,
+	0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x52, 0x0b, 0x70, 0x6f, 0x73, 0x74, 0x49, 0x6e, 0x73, 0x74,
+	0x61, 0x6c, 0x6c, 0x22, 0x91, 0x01, 0x0a, 0x13, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d,
+	0x54, 0x6f, 0x6f, 0x6c, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x70,
+	0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70,
+	0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6e, 0x73,
+	0x74, 0x61, 0x6c, 0x6c, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x69, 0x6e,
+	0x73, 0x74, 0x61, 0x6c, 0x6c, 0x65, 0x64, 0x2a, 0x73, 0x0a, 0x11, 0x50, 0x6f, 0x73, 0x74, 0x49,
+	0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x12, 0x1f, 0x0a, 0x1b,
+	0x50, 0x4f, 0x53, 0x54, 0x5f, 0x49, 0x4e, 0x53, 0x54, 0x41, 0x4c, 0x4c, 0x5f, 0x53, 0x43, 0x52,
+	0x49, 0x50, 0x54, 0x5f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x1c, 0x0a,
+	0x18, 0x50, 0x4f, 0x53, 0x54, 0x5f, 0x49, 0x4e, 0x53, 0x54, 0x41, 0x4c, 0x4c, 0x5f, 0x53, 0x43,
+	0x52, 0x49, 0x50, 0x54, 0x5f, 0x4e, 0x4f, 0x4e, 0x45, 0x10, 0x01, 0x12, 0x1f, 0x0a, 0x1b, 0x50,
+	0x4f, 0x53, 0x54, 0x5f, 0x49, 0x4e, 0x53, 0x54, 0x41, 0x4c, 0x4c, 0x5f, 0x53, 0x43, 0x52, 0x49,
+	0x50, 0x54, 0x5f, 0x50, 0x52, 0x45, 0x53, 0x45, 0x4e, 0x54, 0x10, 0x02, 0x42, 0x48, 0x5a, 0x46,
+	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x72, 0x64, 0x75, 0x69,
+	0x6e, 0x6f, 0x2f, 0x61, 0x72, 0x64, 0x75, 0x69, 0x6e, 0x6f, 0x2d, 0x63, 0x6c, 0x69, 0x2f, 0x72,
+	0x70, 0x63, 0x2f, 0x63, 0x63, 0x2f, 0x61, 0x72, 0x64, 0x75, 0x69, 0x6e, 0x6f, 0x2f, 0x63, 0x6c,
+	0x69, 0x2f, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x2f, 0x76, 0x31, 0x3b, 0x63, 0x6f,
+	0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -927,45 +1473,59 @@ func file_cc_arduino_cli_commands_v1_core_proto_rawDescGZIP() []byte {
 	return file_cc_arduino_cli_commands_v1_core_proto_rawDescData
 }
 
-var file_cc_arduino_cli_commands_v1_core_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_cc_arduino_cli_commands_v1_core_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_cc_arduino_cli_commands_v1_core_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
 var file_cc_arduino_cli_commands_v1_core_proto_goTypes = []interface{}{
-	(*PlatformInstallRequest)(nil),    // 0: cc.arduino.cli.commands.v1.PlatformInstallRequest
-	(*PlatformInstallResponse)(nil),   // 1: cc.arduino.cli.commands.v1.PlatformInstallResponse
-	(*PlatformDownloadRequest)(nil),   // 2: cc.arduino.cli.commands.v1.PlatformDownloadRequest
-	(*PlatformDownloadResponse)(nil),  // 3: cc.arduino.cli.commands.v1.PlatformDownloadResponse
-	(*PlatformUninstallRequest)(nil),  // 4: cc.arduino.cli.commands.v1.PlatformUninstallRequest
-	(*PlatformUninstallResponse)(nil), // 5: cc.arduino.cli.commands.v1.PlatformUninstallResponse
-	(*PlatformUpgradeRequest)(nil),    // 6: cc.arduino.cli.commands.v1.PlatformUpgradeRequest
-	(*PlatformUpgradeResponse)(nil),   // 7: cc.arduino.cli.commands.v1.PlatformUpgradeResponse
-	(*PlatformSearchRequest)(nil),     // 8: cc.arduino.cli.commands.v1.PlatformSearchRequest
-	(*PlatformSearchResponse)(nil),    // 9: cc.arduino.cli.commands.v1.PlatformSearchResponse
-	(*PlatformListRequest)(nil),       // 10: cc.arduino.cli.commands.v1.PlatformListRequest
-	(*PlatformListResponse)(nil),      // 11: cc.arduino.cli.commands.v1.PlatformListResponse
-	(*Instance)(nil),                  // 12: cc.arduino.cli.commands.v1.Instance
-	(*DownloadProgress)(nil),          // 13: cc.arduino.cli.commands.v1.DownloadProgress
-	(*TaskProgress)(nil),              // 14: cc.arduino.cli.commands.v1.TaskProgress
-	(*Platform)(nil),                  // 15: cc.arduino.cli.commands.v1.Platform
+	(PostInstallScript)(0),              // 0: cc.arduino.cli.commands.v1.PostInstallScript
+	(*PlatformInstallRequest)(nil),      // 1: cc.arduino.cli.commands.v1.PlatformInstallRequest
+	(*PlatformInstallResponse)(nil),     // 2: cc.arduino.cli.commands.v1.PlatformInstallResponse
+	(*PlatformDownloadRequest)(nil),     // 3: cc.arduino.cli.commands.v1.PlatformDownloadRequest
+	(*PlatformDownloadResponse)(nil),    // 4: cc.arduino.cli.commands.v1.PlatformDownloadResponse
+	(*PlatformUninstallRequest)(nil),    // 5: cc.arduino.cli.commands.v1.PlatformUninstallRequest
+	(*PlatformUninstallResponse)(nil),   // 6: cc.arduino.cli.commands.v1.PlatformUninstallResponse
+	(*PlatformUpgradeRequest)(nil),      // 7: cc.arduino.cli.commands.v1.PlatformUpgradeRequest
+	(*PlatformUpgradeResponse)(nil),     // 8: cc.arduino.cli.commands.v1.PlatformUpgradeResponse
+	(*PlatformSearchRequest)(nil),       // 9: cc.arduino.cli.commands.v1.PlatformSearchRequest
+	(*PlatformSearchResponse)(nil),      // 10: cc.arduino.cli.commands.v1.PlatformSearchResponse
+	(*PlatformListRequest)(nil),         // 11: cc.arduino.cli.commands.v1.PlatformListRequest
+	(*PlatformListResponse)(nil),        // 12: cc.arduino.cli.commands.v1.PlatformListResponse
+	(*ToolsGarbageCollectRequest)(nil),  // 13: cc.arduino.cli.commands.v1.ToolsGarbageCollectRequest
+	(*ToolsGarbageCollectResponse)(nil), // 14: cc.arduino.cli.commands.v1.ToolsGarbageCollectResponse
+	(*UnusedTool)(nil),                  // 15: cc.arduino.cli.commands.v1.UnusedTool
+	(*PlatformDetailsRequest)(nil),      // 16: cc.arduino.cli.commands.v1.PlatformDetailsRequest
+	(*PlatformDetailsResponse)(nil),     // 17: cc.arduino.cli.commands.v1.PlatformDetailsResponse
+	(*PlatformToolDetails)(nil),         // 18: cc.arduino.cli.commands.v1.PlatformToolDetails
+	(*Instance)(nil),                    // 19: cc.arduino.cli.commands.v1.Instance
+	(*DownloadProgress)(nil),            // 20: cc.arduino.cli.commands.v1.DownloadProgress
+	(*TaskProgress)(nil),                // 21: cc.arduino.cli.commands.v1.TaskProgress
+	(*Platform)(nil),                    // 22: cc.arduino.cli.commands.v1.Platform
 }
 var file_cc_arduino_cli_commands_v1_core_proto_depIdxs = []int32{
-	12, // 0: cc.arduino.cli.commands.v1.PlatformInstallRequest.instance:type_name -> cc.arduino.cli.commands.v1.Instance
-	13, // 1: cc.arduino.cli.commands.v1.PlatformInstallResponse.progress:type_name -> cc.arduino.cli.commands.v1.DownloadProgress
-	14, // 2: cc.arduino.cli.commands.v1.PlatformInstallResponse.task_progress:type_name -> cc.arduino.cli.commands.v1.TaskProgress
-	12, // 3: cc.arduino.cli.commands.v1.PlatformDownloadRequest.instance:type_name -> cc.arduino.cli.commands.v1.Instance
-	13, // 4: cc.arduino.cli.commands.v1.PlatformDownloadResponse.progress:type_name -> cc.arduino.cli.commands.v1.DownloadProgress
-	12, // 5: cc.arduino.cli.commands.v1.PlatformUninstallRequest.instance:type_name -> cc.arduino.cli.commands.v1.Instance
-	14, // 6: cc.arduino.cli.commands.v1.PlatformUninstallResponse.task_progress:type_name -> cc.arduino.cli.commands.v1.TaskProgress
-	12, // 7: cc.arduino.cli.commands.v1.PlatformUpgradeRequest.instance:type_name -> cc.arduino.cli.commands.v1.Instance
-	13, // 8: cc.arduino.cli.commands.v1.PlatformUpgradeResponse.progress:type_name -> cc.arduino.cli.commands.v1.DownloadProgress
-	14, // 9: cc.arduino.cli.commands.v1.PlatformUpgradeResponse.task_progress:type_name -> cc.arduino.cli.commands.v1.TaskProgress
-	12, // 10: cc.arduino.cli.commands.v1.PlatformSearchRequest.instance:type_name -> cc.arduino.cli.commands.v1.Instance
-	15, // 11: cc.arduino.cli.commands.v1.PlatformSearchResponse.search_output:type_name -> cc.arduino.cli.commands.v1.Platform
-	12, // 12: cc.arduino.cli.commands.v1.PlatformListRequest.instance:type_name -> cc.arduino.cli.commands.v1.Instance
-	15, // 13: cc.arduino.cli.commands.v1.PlatformListResponse.installed_platforms:type_name -> cc.arduino.cli.commands.v1.Platform
-	14, // [14:14] is the sub-list for method output_type
-	14, // [14:14] is the sub-list for method input_type
-	14, // [14:14] is the sub-list for extension type_name
-	14, // [14:14] is the sub-list for extension extendee
-	0,  // [0:14] is the sub-list for field type_name
+	19, // 0: cc.arduino.cli.commands.v1.PlatformInstallRequest.instance:type_name -> cc.arduino.cli.commands.v1.Instance
+	20, // 1: cc.arduino.cli.commands.v1.PlatformInstallResponse.progress:type_name -> cc.arduino.cli.commands.v1.DownloadProgress
+	21, // 2: cc.arduino.cli.commands.v1.PlatformInstallResponse.task_progress:type_name -> cc.arduino.cli.commands.v1.TaskProgress
+	19, // 3: cc.arduino.cli.commands.v1.PlatformDownloadRequest.instance:type_name -> cc.arduino.cli.commands.v1.Instance
+	20, // 4: cc.arduino.cli.commands.v1.PlatformDownloadResponse.progress:type_name -> cc.arduino.cli.commands.v1.DownloadProgress
+	19, // 5: cc.arduino.cli.commands.v1.PlatformUninstallRequest.instance:type_name -> cc.arduino.cli.commands.v1.Instance
+	21, // 6: cc.arduino.cli.commands.v1.PlatformUninstallResponse.task_progress:type_name -> cc.arduino.cli.commands.v1.TaskProgress
+	19, // 7: cc.arduino.cli.commands.v1.PlatformUpgradeRequest.instance:type_name -> cc.arduino.cli.commands.v1.Instance
+	20, // 8: cc.arduino.cli.commands.v1.PlatformUpgradeResponse.progress:type_name -> cc.arduino.cli.commands.v1.DownloadProgress
+	21, // 9: cc.arduino.cli.commands.v1.PlatformUpgradeResponse.task_progress:type_name -> cc.arduino.cli.commands.v1.TaskProgress
+	19, // 10: cc.arduino.cli.commands.v1.PlatformSearchRequest.instance:type_name -> cc.arduino.cli.commands.v1.Instance
+	22, // 11: cc.arduino.cli.commands.v1.PlatformSearchResponse.search_output:type_name -> cc.arduino.cli.commands.v1.Platform
+	19, // 12: cc.arduino.cli.commands.v1.PlatformListRequest.instance:type_name -> cc.arduino.cli.commands.v1.Instance
+	22, // 13: cc.arduino.cli.commands.v1.PlatformListResponse.installed_platforms:type_name -> cc.arduino.cli.commands.v1.Platform
+	19, // 14: cc.arduino.cli.commands.v1.ToolsGarbageCollectRequest.instance:type_name -> cc.arduino.cli.commands.v1.Instance
+	15, // 15: cc.arduino.cli.commands.v1.ToolsGarbageCollectResponse.tools:type_name -> cc.arduino.cli.commands.v1.UnusedTool
+	19, // 16: cc.arduino.cli.commands.v1.PlatformDetailsRequest.instance:type_name -> cc.arduino.cli.commands.v1.Instance
+	22, // 17: cc.arduino.cli.commands.v1.PlatformDetailsResponse.platform:type_name -> cc.arduino.cli.commands.v1.Platform
+	18, // 18: cc.arduino.cli.commands.v1.PlatformDetailsResponse.tools:type_name -> cc.arduino.cli.commands.v1.PlatformToolDetails
+	0,  // 19: cc.arduino.cli.commands.v1.PlatformDetailsResponse.post_install:type_name -> cc.arduino.cli.commands.v1.PostInstallScript
+	20, // [20:20] is the sub-list for method output_type
+	20, // [20:20] is the sub-list for method input_type
+	20, // [20:20] is the sub-list for extension type_name
+	20, // [20:20] is the sub-list for extension extendee
+	0,  // [0:20] is the sub-list for field type_name
 }
 
 func init() { file_cc_arduino_cli_commands_v1_core_proto_init() }
@@ -1119,19 +1679,92 @@ func file_cc_arduino_cli_commands_v1_core_proto_init() {
 				return nil
 			}
 		}
+		file_cc_arduino_cli_commands_v1_core_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ToolsGarbageCollectRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cc_arduino_cli_commands_v1_core_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ToolsGarbageCollectResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cc_arduino_cli_commands_v1_core_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UnusedTool); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cc_arduino_cli_commands_v1_core_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PlatformDetailsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cc_arduino_cli_commands_v1_core_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PlatformDetailsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cc_arduino_cli_commands_v1_core_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PlatformToolDetails); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_cc_arduino_cli_commands_v1_core_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   12,
+			NumEnums:      1,
+			NumMessages:   18,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_cc_arduino_cli_commands_v1_core_proto_goTypes,
 		DependencyIndexes: file_cc_arduino_cli_commands_v1_core_proto_depIdxs,
+		EnumInfos:         file_cc_arduino_cli_commands_v1_core_proto_enumTypes,
 		MessageInfos:      file_cc_arduino_cli_commands_v1_core_proto_msgTypes,
 	}.Build()
 	File_cc_arduino_cli_commands_v1_core_proto = out.File
@@ -20,6 +20,12 @@ type DebugServiceClient interface {
 	// Start a debug session and communicate with the debugger tool.
 	Debug(ctx context.Context, opts ...grpc.CallOption) (DebugService_DebugClient, error)
 	GetDebugConfig(ctx context.Context, in *DebugConfigRequest, opts ...grpc.CallOption) (*GetDebugConfigResponse, error)
+	// Read the peripheral registers of the target, as described by the
+	// CMSIS-SVD file of the board.
+	ReadRegisters(ctx context.Context, in *ReadRegistersRequest, opts ...grpc.CallOption) (*ReadRegistersResponse, error)
+	// Capture the trace output of the target, through SWO (ITM stimulus ports)
+	// or RTT (up-buffers). The stream ends when the client cancels the call.
+	Trace(ctx context.Context, in *TraceRequest, opts ...grpc.CallOption) (DebugService_TraceClient, error)
 }
 
 type debugServiceClient struct {
@@ -70,6 +76,47 @@ func (c *debugServiceClient) GetDebugConfig(ctx context.Context, in *DebugConfig
 	return out, nil
 }
 
+func (c *debugServiceClient) ReadRegisters(ctx context.Context, in *ReadRegistersRequest, opts ...grpc.CallOption) (*ReadRegistersResponse, error) {
+	out := new(ReadRegistersResponse)
+	err := c.cc.Invoke(ctx, "/cc.arduino.cli.debug.v1.DebugService/ReadRegisters", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *debugServiceClient) Trace(ctx context.Context, in *TraceRequest, opts ...grpc.CallOption) (DebugService_TraceClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_DebugService_serviceDesc.Streams[1], "/cc.arduino.cli.debug.v1.DebugService/Trace", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &debugServiceTraceClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DebugService_TraceClient interface {
+	Recv() (*TraceResponse, error)
+	grpc.ClientStream
+}
+
+type debugServiceTraceClient struct {
+	grpc.ClientStream
+}
+
+func (x *debugServiceTraceClient) Recv() (*TraceResponse, error) {
+	m := new(TraceResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // DebugServiceServer is the server API for DebugService service.
 // All implementations must embed UnimplementedDebugServiceServer
 // for forward compatibility
@@ -77,6 +124,12 @@ type DebugServiceServer interface {
 	// Start a debug session and communicate with the debugger tool.
 	Debug(DebugService_DebugServer) error
 	GetDebugConfig(context.Context, *DebugConfigRequest) (*GetDebugConfigResponse, error)
+	// Read the peripheral registers of the target, as described by the
+	// CMSIS-SVD file of the board.
+	ReadRegisters(context.Context, *ReadRegistersRequest) (*ReadRegistersResponse, error)
+	// Capture the trace output of the target, through SWO (ITM stimulus ports)
+	// or RTT (up-buffers). The stream ends when the client cancels the call.
+	Trace(*TraceRequest, DebugService_TraceServer) error
 	mustEmbedUnimplementedDebugServiceServer()
 }
 
@@ -90,6 +143,12 @@ func (UnimplementedDebugServiceServer) Debug(DebugService_DebugServer) error {
 func (UnimplementedDebugServiceServer) GetDebugConfig(context.Context, *DebugConfigRequest) (*GetDebugConfigResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetDebugConfig not implemented")
 }
+func (UnimplementedDebugServiceServer) ReadRegisters(context.Context, *ReadRegistersRequest) (*ReadRegistersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadRegisters not implemented")
+}
+func (UnimplementedDebugServiceServer) Trace(*TraceRequest, DebugService_TraceServer) error {
+	return status.Errorf(codes.Unimplemented, "method Trace not implemented")
+}
 func (UnimplementedDebugServiceServer) mustEmbedUnimplementedDebugServiceServer() {}
 
 // UnsafeDebugServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -147,6 +206,45 @@ func _DebugService_GetDebugConfig_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DebugService_ReadRegisters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadRegistersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DebugServiceServer).ReadRegisters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cc.arduino.cli.debug.v1.DebugService/ReadRegisters",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DebugServiceServer).ReadRegisters(ctx, req.(*ReadRegistersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DebugService_Trace_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TraceRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DebugServiceServer).Trace(m, &debugServiceTraceServer{stream})
+}
+
+type DebugService_TraceServer interface {
+	Send(*TraceResponse) error
+	grpc.ServerStream
+}
+
+type debugServiceTraceServer struct {
+	grpc.ServerStream
+}
+
+func (x *debugServiceTraceServer) Send(m *TraceResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _DebugService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "cc.arduino.cli.debug.v1.DebugService",
 	HandlerType: (*DebugServiceServer)(nil),
@@ -155,6 +253,10 @@ var _DebugService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetDebugConfig",
 			Handler:    _DebugService_GetDebugConfig_Handler,
 		},
+		{
+			MethodName: "ReadRegisters",
+			Handler:    _DebugService_ReadRegisters_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -163,6 +265,11 @@ var _DebugService_serviceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "Trace",
+			Handler:       _DebugService_Trace_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "cc/arduino/cli/debug/v1/debug.proto",
 }
@@ -200,7 +200,9 @@ type MonitorConfig struct {
 	Target string                   `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"`
 	Type   MonitorConfig_TargetType `protobuf:"varint,2,opt,name=type,proto3,enum=cc.arduino.cli.monitor.v1.MonitorConfig_TargetType" json:"type,omitempty"`
 	// Additional parameters that might be needed to configure the target or the
-	// monitor itself.
+	// monitor itself. Supported parameters are `BaudRate` for serial targets,
+	// `OutputRate` for null targets and `LineEnding` (`none`, `nl`, `cr` or
+	// `crlf`) to translate the line terminators of the data sent.
 	AdditionalConfig *structpb.Struct `protobuf:"bytes,3,opt,name=additional_config,json=additionalConfig,proto3" json:"additional_config,omitempty"`
 	// This parameter indicates how many bytes should be buffered on the server
 	// side before dropping. If >0 then the server will enable a rate limiter and
@@ -0,0 +1,66 @@
+# This file is part of arduino-cli.
+#
+# Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+#
+# This software is released under the GNU General Public License version 3,
+# which covers the main part of arduino-cli.
+# The terms of this license can be found at:
+# https://www.gnu.org/licenses/gpl-3.0.en.html
+#
+# You can be released from the requirements of the above licenses by purchasing
+# a commercial license. Buying such a license is mandatory if you want to modify or
+# otherwise use the software for commercial activities involving the Arduino
+# software without disclosing the source code of your own applications. To purchase
+# a commercial license, send an email to license@arduino.cc.
+from pathlib import Path
+
+import simplejson as json
+
+
+def test_artifacts_tag_keep_prune(run_command, data_dir):
+    assert run_command("update")
+    assert run_command("core install arduino:avr@1.8.3")
+
+    sketch_name = "ArtifactsSketch"
+    sketch_path = Path(data_dir, sketch_name)
+    fqbn = "arduino:avr:uno"
+    assert run_command(f"sketch new {sketch_path}")
+    assert run_command(f"compile -b {fqbn} {sketch_path} --export-binaries")
+
+    res = run_command(f"artifacts tag v1 -b {fqbn} --profile release {sketch_path} --format json")
+    assert res.ok
+    artifact = json.loads(res.stdout)
+    assert artifact["tag"] == "v1"
+    assert artifact["fqbn"] == fqbn
+    assert artifact["profile"] == "release"
+    assert f"{sketch_name}.ino.hex" in artifact["files"]
+    assert Path(data_dir, "artifacts", "v1", f"{sketch_name}.ino.hex").exists()
+
+    # Tags can't be overwritten unless forced
+    assert run_command(f"artifacts tag v1 -b {fqbn} {sketch_path}").failed
+    assert run_command(f"artifacts tag v1 -b {fqbn} {sketch_path} --force")
+    assert run_command(f"artifacts tag v2 -b {fqbn} {sketch_path}")
+    assert run_command("artifacts keep v1")
+
+    res = run_command("artifacts list --format json")
+    assert res.ok
+    artifacts = {a["tag"]: a for a in json.loads(res.stdout)}
+    assert sorted(artifacts) == ["v1", "v2"]
+    assert artifacts["v1"]["keep"]
+    assert not artifacts["v2"]["keep"]
+
+    # Only the artifacts not kept are pruned
+    res = run_command("artifacts prune --older-than 0s --format json")
+    assert res.ok
+    assert [a["tag"] for a in json.loads(res.stdout)] == ["v2"]
+    assert Path(data_dir, "artifacts", "v1").exists()
+    assert not Path(data_dir, "artifacts", "v2").exists()
+
+    assert run_command("artifacts remove v1")
+    assert not Path(data_dir, "artifacts", "v1").exists()
+    assert run_command("artifacts remove v1").failed
+
+    # The artifact must exist to be uploaded
+    res = run_command("upload --artifact v1 -p /dev/ttyACM0")
+    assert res.failed
+    assert "artifact v1 not found" in res.stderr
@@ -425,6 +425,19 @@ def test_board_listall(run_command):
     assert "Arduino AVR Boards" == platform["name"]
 
 
+def test_board_listall_with_fqbn_pattern(run_command):
+    assert run_command("update")
+    assert run_command("core install arduino:avr@1.8.3")
+    res = run_command('board listall "arduino:avr:mega*" --format json')
+    assert res.ok
+    data = json.loads(res.stdout)
+    fqbns = sorted(b["fqbn"] for b in data["boards"])
+    assert fqbns == ["arduino:avr:mega", "arduino:avr:megaADK"]
+
+    res = run_command('board listall "arduino:*" --format json')
+    assert res.failed
+
+
 def test_board_listall_with_manually_installed_platform(run_command, data_dir):
     assert run_command("update")
 
@@ -555,10 +568,16 @@ def test_board_details_list_programmers_flag(run_command):
     assert result.ok
 
     lines = [l.strip() for l in result.stdout.splitlines()]
-    assert "Id        Programmer name" in lines
-    assert "edbg      Atmel EDBG" in lines
-    assert "atmel_ice Atmel-ICE" in lines
-    assert "sam_ice   Atmel SAM-ICE" in lines
+    assert lines[0].startswith("Id        Programmer name")
+    assert "Protocol" in lines[0]
+    assert any(l.startswith("edbg      Atmel EDBG") for l in lines)
+    assert any(l.startswith("atmel_ice Atmel-ICE") for l in lines)
+    assert any(l.startswith("sam_ice   Atmel SAM-ICE") for l in lines)
+
+    result = run_command("board details -b arduino:samd:nano_33_iot --list-programmers --format json")
+    assert result.ok
+    programmers = {p["id"]: p for p in json.loads(result.stdout)["programmers"]}
+    assert programmers["atmel_ice"]["tool"] == "openocd"
 
 
 def test_board_search(run_command, data_dir):
@@ -38,6 +38,31 @@ def test_compile_without_fqbn(run_command):
     assert result.failed
 
 
+def test_compile_without_fqbn_no_autodetect(run_command, data_dir):
+    # Init the environment explicitly
+    run_command("core update-index")
+
+    # Install Arduino AVR Boards
+    run_command("core install arduino:avr@1.8.3")
+
+    sketch_path = Path(data_dir, "CompileWithoutFqbnNoAutodetect")
+    assert run_command(f"sketch new {sketch_path}")
+
+    # Without the FQBN the connected board is searched
+    result = run_command(f"compile {sketch_path}")
+    if result.failed:
+        assert "Error detecting the board" in result.stderr
+    else:
+        assert "No FQBN specified, using detected board" in result.stderr
+
+    # The connected board is not searched and the build fails
+    result = run_command(f"compile --no-autodetect {sketch_path}")
+    assert result.failed
+    assert "Error detecting the board" not in result.stderr
+    assert "No FQBN specified" not in result.stderr
+    assert "no FQBN provided" in result.stderr
+
+
 def test_compile_with_simple_sketch(run_command, data_dir, working_dir):
     # Init the environment explicitly
     run_command("core update-index")
@@ -345,6 +370,290 @@ def test_compile_with_multiple_build_property_flags(run_command, data_dir, copy_
     assert '-DSSID=\\"hello world\\"' in res.stdout
 
 
+def test_compile_with_build_property_append(run_command, data_dir):
+    # Init the environment explicitly
+    assert run_command("core update-index")
+
+    # Install Arduino AVR Boards
+    assert run_command("core install arduino:avr@1.8.3")
+
+    sketch_path = Path(data_dir, "CompileWithBuildPropertyAppend")
+    assert run_command(f"sketch new {sketch_path}")
+    fqbn = "arduino:avr:uno"
+
+    # Values appended with += are added to the current value of the property
+    res = run_command(
+        f"compile -b {fqbn} "
+        + '--build-property="compiler.cpp.extra_flags=-DPIN=2" '
+        + '--build-property="compiler.cpp.extra_flags+=-DFOO=1" '
+        + f"{sketch_path} --verbose --clean"
+    )
+    assert res.ok
+    assert "-DPIN=2 -DFOO=1" in res.stdout
+
+    # The default of build.warn_data_percentage can be overridden
+    res = run_command(
+        f"compile -b {fqbn} --build-property build.warn_data_percentage=1 --show-properties {sketch_path}"
+    )
+    assert res.ok
+    assert "build.warn_data_percentage=1\n" in res.stdout
+
+
+def test_compile_with_library_warnings(run_command, data_dir):
+    # Init the environment explicitly
+    assert run_command("core update-index")
+
+    # Install Arduino AVR Boards
+    assert run_command("core install arduino:avr@1.8.3")
+
+    sketch_path = Path(data_dir, "CompileWithLibraryWarnings")
+    assert run_command(f"sketch new {sketch_path}")
+    fqbn = "arduino:avr:uno"
+
+    # The core is compiled with the warnings of --library-warnings, the sketch with the ones of --warnings
+    res = run_command(f"compile -b {fqbn} --warnings all --library-warnings none {sketch_path} --verbose --clean")
+    assert res.ok
+    sketch_cmds = [l for l in res.stdout.splitlines() if "avr-g++" in l and "CompileWithLibraryWarnings.ino.cpp.o" in l]
+    core_cmds = [l for l in res.stdout.splitlines() if "avr-g++" in l and "core/main.cpp.o" in l]
+    assert len(sketch_cmds) > 0 and len(core_cmds) > 0
+    assert "-Wall -Wextra" in sketch_cmds[0]
+    assert "-Wall" not in core_cmds[0]
+
+    res = run_command(f"compile -b {fqbn} --library-warnings loud {sketch_path}")
+    assert res.failed
+    assert "Invalid warning level: loud" in res.stderr
+
+
+def test_compile_with_invalid_container_engine(run_command, data_dir):
+    # Init the environment explicitly
+    assert run_command("core update-index")
+
+    # Install Arduino AVR Boards
+    assert run_command("core install arduino:avr@1.8.3")
+
+    sketch_path = Path(data_dir, "CompileWithInvalidContainerEngine")
+    assert run_command(f"sketch new {sketch_path}")
+
+    res = run_command(f"compile -b arduino:avr:uno --container gcc:10 --container-engine lxc {sketch_path}")
+    assert res.failed
+    assert "invalid container engine: lxc" in res.stderr
+
+
+def test_compile_with_optimize_preset(run_command, data_dir):
+    # Init the environment explicitly
+    assert run_command("core update-index")
+
+    # Install Arduino AVR Boards
+    assert run_command("core install arduino:avr@1.8.3")
+
+    sketch_name = "CompileWithOptimizePreset"
+    sketch_path = Path(data_dir, sketch_name)
+    assert run_command(f"sketch new {sketch_path}")
+    fqbn = "arduino:avr:uno"
+
+    # The optimization level of the platform is replaced by the one of the preset
+    res = run_command(f"compile -b {fqbn} --optimize debug {sketch_path} --verbose --clean -e")
+    assert res.ok
+    assert "-Og -g3" in res.stdout
+    assert " -Os " not in res.stdout
+
+    # The preset is saved along with the exported binaries
+    build_info = Path(sketch_path, "build", "arduino.avr.uno", f"{sketch_name}.ino.build_info.json")
+    assert build_info.exists()
+    info = json.loads(build_info.read_text())
+    assert info["fqbn"] == fqbn
+    assert info["optimization"] == "debug"
+
+    # and recorded in the metadata of the artifacts
+    res = run_command(f"artifacts tag v1 -b {fqbn} {sketch_path} --format json")
+    assert res.ok
+    assert json.loads(res.stdout)["optimization"] == "debug"
+
+    res = run_command(f"compile -b {fqbn} --optimize fast {sketch_path}")
+    assert res.failed
+    assert "Invalid optimization preset: fast" in res.stderr
+
+    res = run_command(f"compile -b {fqbn} --optimize size --optimize-for-debug {sketch_path}")
+    assert res.failed
+
+
+def test_compile_with_build_stats(run_command, data_dir):
+    # Init the environment explicitly
+    assert run_command("core update-index")
+
+    # Install Arduino AVR Boards
+    assert run_command("core install arduino:avr@1.8.3")
+
+    sketch_name = "CompileWithBuildStats"
+    sketch_path = Path(data_dir, sketch_name)
+    assert run_command(f"sketch new {sketch_path}")
+    fqbn = "arduino:avr:uno"
+
+    trace_file = Path(data_dir, "trace.json")
+    res = run_command(f"compile -b {fqbn} {sketch_path} --build-stats {trace_file}")
+    assert res.ok
+    assert trace_file.exists()
+    events = json.loads(trace_file.read_text())["traceEvents"]
+    assert len(events) > 0
+    categories = {e["cat"] for e in events}
+    assert "phase" in categories
+    assert "compile" in categories
+    assert "archive" in categories
+    assert any(e["name"] == "Linker" for e in events)
+    assert any(e["name"].endswith(f"{sketch_name}.ino.cpp") for e in events)
+
+    # The slowest phases are shown with the statistics
+    res = run_command(f"compile -b {fqbn} {sketch_path} --stats")
+    assert res.ok
+    assert "Slowest phases:" in res.stdout
+
+
+def test_compile_with_stats(run_command, data_dir):
+    # Init the environment explicitly
+    assert run_command("core update-index")
+
+    # Install Arduino AVR Boards and a library
+    assert run_command("core install arduino:avr@1.8.3")
+    assert run_command("lib install Servo")
+
+    sketch_name = "CompileWithStats"
+    sketch_path = Path(data_dir, sketch_name)
+    assert run_command(f"sketch new {sketch_path}")
+    sketch_file = sketch_path / f"{sketch_name}.ino"
+    sketch_file.write_text("#include <Servo.h>\n" + sketch_file.read_text())
+    fqbn = "arduino:avr:uno"
+
+    stats_file = Path(data_dir, "stats.jsonl")
+    res = run_command(f"compile -b {fqbn} {sketch_path} --stats --stats-file {stats_file}")
+    assert res.ok
+    assert "Build statistics:" in res.stdout
+    assert "Translation units compiled:" in res.stdout
+    assert "Libraries used:             Servo@" in res.stdout
+
+    # Nothing changed, the object files and the core are reused
+    res = run_command(f"compile -b {fqbn} {sketch_path} --stats-file {stats_file}")
+    assert res.ok
+    assert "Build statistics:" not in res.stdout
+
+    # A line is appended for each build
+    records = [json.loads(line) for line in stats_file.read_text().splitlines()]
+    assert len(records) == 2
+    for record in records:
+        assert record["fqbn"] == fqbn
+        assert record["sketch"] == str(sketch_path)
+        assert len(record["stats"]["libraries"]) == 1
+        assert record["stats"]["libraries"][0].startswith("Servo@")
+        assert "events" not in record["stats"]
+        assert len(record["executable_sections_size"]) > 0
+    assert records[0]["stats"]["translation_units"] > 0
+    assert records[0]["stats"]["lines_compiled"] > 0
+    assert records[1]["stats"]["reused_object_files"] > 0
+    assert records[1]["stats"]["core_cache_hit"]
+
+
+def test_compile_show_commands(run_command, data_dir):
+    # Init the environment explicitly
+    assert run_command("core update-index")
+
+    # Install Arduino AVR Boards
+    assert run_command("core install arduino:avr@1.8.3")
+    assert run_command("lib install Servo@1.1.8")
+
+    sketch_name = "CompileShowCommands"
+    sketch_path = Path(data_dir, sketch_name)
+    assert run_command(f"sketch new {sketch_path}")
+    Path(sketch_path, f"{sketch_name}.ino").write_text("#include <Servo.h>\nvoid setup() {}\nvoid loop() {}\n")
+    fqbn = "arduino:avr:uno"
+
+    res = run_command(f"compile -b {fqbn} --show-commands --build-property compiler.cpp.extra_flags=-DFOO {sketch_path}")
+    assert res.ok
+    lines = res.stdout.splitlines()
+    assert "# recipe.cpp.o.pattern" in lines
+    assert "# recipe.c.combine.pattern" in lines
+    assert "# recipe.size.pattern" in lines
+    # The recipes are expanded with the final build properties
+    cpp = lines[lines.index("# recipe.cpp.o.pattern") + 1]
+    assert "avr-g++" in cpp
+    assert "-DFOO" in cpp
+    assert f"{sketch_name}.ino.cpp" in cpp
+    assert "{" not in cpp
+    # The sources of the libraries used by the sketch are shown too
+    servo = [line for line in lines if line.startswith("# recipe.cpp.o.pattern (Servo: ")]
+    assert servo
+    assert "Servo.cpp" in lines[lines.index(servo[0]) + 1]
+
+    # Nothing is compiled
+    assert not list(Path(sketch_path).rglob("*.o"))
+
+
+def test_compile_trace_properties(run_command, data_dir):
+    # Init the environment explicitly
+    assert run_command("core update-index")
+
+    # Install Arduino AVR Boards
+    assert run_command("core install arduino:avr@1.8.3")
+
+    sketch_name = "CompileTraceProperties"
+    sketch_path = Path(data_dir, sketch_name)
+    assert run_command(f"sketch new {sketch_path}")
+    fqbn = "arduino:avr:nano:cpu=atmega168"
+
+    res = run_command(
+        f"compile -b {fqbn} --show-properties {sketch_path} "
+        + "--trace-properties upload.maximum_size --trace-properties build.extra_flags "
+        + "--build-property build.extra_flags=-DFOO={build.mcu}"
+    )
+    assert res.ok
+    out = res.stdout
+    assert "Property upload.maximum_size:" in out
+    # The value is defined by the selected menu option
+    assert "boards.txt (menu option cpu=atmega168): 14336" in out
+    assert "set by --build-property build.extra_flags=-DFOO={build.mcu}: -DFOO={build.mcu}" in out
+    assert "{build.mcu} = atmega168" in out
+    assert "expanded value: -DFOO=atmega168" in out
+
+
+def test_compile_with_export_formats(run_command, data_dir):
+    # Init the environment explicitly
+    assert run_command("core update-index")
+
+    # Install Arduino AVR Boards
+    assert run_command("core install arduino:avr@1.8.3")
+
+    sketch_name = "CompileWithExportFormats"
+    sketch_path = Path(data_dir, sketch_name)
+    assert run_command(f"sketch new {sketch_path}")
+    fqbn = "arduino:avr:uno"
+
+    res = run_command(
+        f"compile -b {fqbn} {sketch_path} --export-format bin --export-format merged --export-format hex:0x2000"
+    )
+    assert res.ok
+    export_path = Path(sketch_path, "build", "arduino.avr.uno")
+    assert Path(export_path, f"{sketch_name}.ino.hex").exists()
+    assert Path(export_path, f"{sketch_name}.ino.bin").exists()
+    assert Path(export_path, f"{sketch_name}.ino.with_bootloader.bin").exists()
+    relocated = Path(export_path, f"{sketch_name}.ino.at_0x2000.hex").read_text()
+    # Extended linear address 0, first data record at 0x2000
+    assert ":102000" in relocated
+
+    # The AVR microcontrollers have no UF2 family
+    res = run_command(f"compile -b {fqbn} {sketch_path} --export-format uf2")
+    assert res.failed
+    assert "build.uf2.family" in res.stderr
+
+    # UF2 family can be set with a property
+    res = run_command(
+        f"compile -b {fqbn} {sketch_path} --export-format uf2 --build-property build.uf2.family=0x68ed2b88"
+    )
+    assert res.ok
+    assert Path(export_path, f"{sketch_name}.ino.uf2").exists()
+
+    res = run_command(f"compile -b {fqbn} {sketch_path} --export-format elf")
+    assert res.failed
+    assert "Invalid export format" in res.stderr
+
+
 def test_compile_with_output_dir_flag(run_command, data_dir):
     # Init the environment explicitly
     run_command("core update-index")
@@ -955,6 +1264,72 @@ def test_compile_with_library_priority(run_command, data_dir):
     assert "\n".join(expected_output) in res.stdout
 
 
+def test_compile_with_dump_library_resolution(run_command, data_dir):
+    assert run_command("update")
+
+    assert run_command("core install arduino:avr@1.8.3")
+
+    sketch_name = "CompileSketchWithDumpLibraryResolution"
+    sketch_path = Path(data_dir, sketch_name)
+    fqbn = "arduino:avr:uno"
+
+    # Manually installs a library
+    git_url = "https://github.com/arduino-libraries/WiFi101.git"
+    manually_install_lib_path = Path(data_dir, "my-libraries", "WiFi101")
+    assert Repo.clone_from(git_url, manually_install_lib_path, multi_options=["-b 0.16.1"])
+
+    # Install the same library we installed manually
+    assert run_command("lib install WiFi101")
+
+    assert run_command(f"sketch new {sketch_path}")
+    sketch_file = sketch_path / f"{sketch_name}.ino"
+    sketch_file.write_text("#include <WiFi101.h>\n" + sketch_file.read_text())
+
+    res = run_command(
+        f"compile -b {fqbn} {sketch_path} --library {manually_install_lib_path} --dump-library-resolution"
+    )
+    assert res.ok
+    assert "Library resolution:" in res.stdout
+    assert "#include <WiFi101.h>" in res.stdout
+
+    res = run_command(
+        f"compile -b {fqbn} {sketch_path} --library {manually_install_lib_path} --dump-library-resolution --format json"
+    )
+    assert res.ok
+    trace = json.loads(res.stdout)["builder_result"]["library_resolution"]
+    wifi = [r for r in trace if r["include"] == "WiFi101.h"][0]
+    assert wifi["selected"] == "WiFi101"
+    assert len(wifi["candidates"]) == 2
+    selected = [c for c in wifi["candidates"] if c["selected"]]
+    assert len(selected) == 1
+    assert selected[0]["install_dir"] == str(manually_install_lib_path)
+    assert selected[0]["priority"] == max(c["priority"] for c in wifi["candidates"])
+
+
+def test_compile_with_auto_install_libs(run_command, data_dir):
+    assert run_command("update")
+    assert run_command("core install arduino:avr@1.8.3")
+
+    sketch_name = "CompileSketchWithAutoInstallLibs"
+    sketch_path = Path(data_dir, sketch_name)
+    fqbn = "arduino:avr:uno"
+    assert run_command(f"sketch new {sketch_path}")
+    sketch_file = sketch_path / f"{sketch_name}.ino"
+    sketch_file.write_text("#include <Servo.h>\n" + sketch_file.read_text())
+
+    # Without the flag the library that provides the header is suggested
+    res = run_command(f"compile -b {fqbn} {sketch_path}")
+    assert res.failed
+    assert 'lib install "Servo"' in res.stdout
+
+    res = run_command(f"compile -b {fqbn} {sketch_path} --auto-install-libs --format json")
+    assert res.ok
+    result = json.loads(res.stdout)
+    assert result["success"]
+    assert [lib.split("@")[0] for lib in result["installed_libraries"]] == ["Servo"]
+    assert Path(data_dir, "libraries", "Servo").exists()
+
+
 def test_recompile_with_different_library(run_command, data_dir):
     assert run_command("update")
 
@@ -1155,3 +1530,378 @@ def test_compile_sketch_with_ipp_file_include(run_command, copy_sketch):
     fqbn = "arduino:avr:uno"
 
     assert run_command(f"compile -b {fqbn} {sketch_path} --verbose")
+
+
+def test_compile_with_compilation_database_path(run_command, data_dir):
+    assert run_command("update")
+
+    # Download latest AVR
+    run_command("core install arduino:avr")
+
+    sketch_name = "CompileWithCompilationDatabasePath"
+    sketch_path = Path(data_dir, sketch_name)
+    fqbn = "arduino:avr:uno"
+
+    # Create a test sketch
+    assert run_command(f"sketch new {sketch_path}")
+
+    database_path = Path(data_dir, "test_dir", "compile_commands.json")
+    res = run_command(
+        f"compile -b {fqbn} {sketch_path} --only-compilation-database --compilation-database-path {database_path}"
+    )
+    assert res.ok
+    assert database_path.exists()
+
+    # Verifies the sketch, the core and the variant are all covered
+    entries = json.loads(database_path.read_text())
+    files = [Path(e["file"]).name for e in entries]
+    assert f"{sketch_name}.ino.cpp" in files
+    assert "main.cpp" in files
+
+
+def test_compile_with_detailed_size_report(run_command, data_dir):
+    assert run_command("update")
+
+    # Download latest AVR
+    run_command("core install arduino:avr")
+
+    sketch_name = "CompileWithDetailedSizeReport"
+    sketch_path = Path(data_dir, sketch_name)
+    fqbn = "arduino:avr:uno"
+
+    # Create a test sketch
+    assert run_command(f"sketch new {sketch_path}")
+
+    res = run_command(f"compile -b {fqbn} {sketch_path} --size-report detailed")
+    assert res.ok
+    assert "Memory usage by origin:" in res.stdout
+
+    res = run_command(f"compile -b {fqbn} {sketch_path} --size-report detailed --format json")
+    assert res.ok
+    report = json.loads(res.stdout)["builder_result"]["size_report"]
+    sections = [s["name"] for s in report["sections"]]
+    assert ".text" in sections
+    origins = [o["name"] for o in report["origins"]]
+    assert "sketch" in origins
+    assert "core" in origins
+    # Only the biggest symbols are listed
+    assert 0 < len(report["symbols"]) <= 50
+
+    res = run_command(f"compile -b {fqbn} {sketch_path} --size-report wrong")
+    assert res.failed
+    assert "Invalid size report: wrong" in res.stderr
+
+
+def test_compile_with_size_delta_from(run_command, data_dir):
+    assert run_command("update")
+
+    # Download latest AVR
+    run_command("core install arduino:avr")
+
+    sketch_name = "CompileWithSizeDeltaFrom"
+    sketch_path = Path(data_dir, sketch_name)
+    fqbn = "arduino:avr:uno"
+
+    # Create a test sketch and save its size report as baseline
+    assert run_command(f"sketch new {sketch_path}")
+    res = run_command(f"compile -b {fqbn} {sketch_path} --size-report detailed --format json")
+    assert res.ok
+    baseline = Path(data_dir, "baseline.json")
+    baseline.write_text(res.stdout)
+
+    # Same sketch, no growth
+    res = run_command(f"compile -b {fqbn} {sketch_path} --size-delta-from {baseline}")
+    assert res.ok
+    assert "Size compared to the baseline:" in res.stdout
+
+    # Add a symbol using both flash and RAM
+    sketch_file = Path(sketch_path, f"{sketch_name}.ino")
+    sketch_file.write_text(
+        "volatile char buffer[64] = {1};\nvoid setup() {\n  buffer[0]++;\n}\n\nvoid loop() {\n}\n"
+    )
+    res = run_command(f"compile -b {fqbn} {sketch_path} --size-delta-from {baseline}")
+    assert res.failed
+    assert "flash usage grew by" in res.stderr
+
+    res = run_command(
+        f"compile -b {fqbn} {sketch_path} --size-delta-from {baseline} "
+        + "--size-delta-max-flash 1000 --size-delta-max-ram 1000 --format json"
+    )
+    assert res.ok
+    delta = json.loads(res.stdout)["builder_result"]["size_delta"]
+    assert delta["flash"] > delta["baseline_flash"]
+    assert delta["ram"] > delta["baseline_ram"]
+    assert "buffer" in [s["name"] for s in delta["symbols"]]
+
+    # The baseline can be an ELF file too
+    build_path = Path(data_dir, "build")
+    assert run_command(f"compile -b {fqbn} {sketch_path} --build-path {build_path}")
+    elf_file = Path(build_path, f"{sketch_name}.ino.elf")
+    res = run_command(f"compile -b {fqbn} {sketch_path} --size-delta-from {elf_file}")
+    assert res.ok
+
+
+def test_compile_with_max_memory_usage(run_command, data_dir):
+    assert run_command("update")
+
+    # Download latest AVR
+    run_command("core install arduino:avr")
+
+    sketch_name = "CompileWithMaxMemoryUsage"
+    sketch_path = Path(data_dir, sketch_name)
+    fqbn = "arduino:avr:uno"
+
+    # Create a test sketch
+    assert run_command(f"sketch new {sketch_path}")
+
+    assert run_command(f"compile -b {fqbn} {sketch_path} --max-flash-usage 90% --max-ram-usage 2000")
+
+    res = run_command(f"compile -b {fqbn} {sketch_path} --max-flash-usage 100")
+    assert res.failed
+    assert res.exited == 8
+    assert "flash usage of" in res.stderr
+
+    res = run_command(f"compile -b {fqbn} {sketch_path} --max-ram-usage 0%")
+    assert res.failed
+    assert res.exited == 8
+    assert "RAM usage of" in res.stderr
+
+    res = run_command(f"compile -b {fqbn} {sketch_path} --max-flash-usage 10KB")
+    assert res.failed
+    assert "invalid size limit: 10KB" in res.stderr
+
+    # The limits can be set in the sketch.json file too
+    Path(sketch_path, "sketch.json").write_text('{"max_flash_usage": "1%"}')
+    res = run_command(f"compile -b {fqbn} {sketch_path}")
+    assert res.failed
+    assert res.exited == 8
+
+    # Flags override the sketch.json
+    assert run_command(f"compile -b {fqbn} {sketch_path} --max-flash-usage 100%")
+
+
+def test_compile_with_fail_on_warning(run_command, data_dir):
+    assert run_command("update")
+
+    # Download latest AVR
+    run_command("core install arduino:avr")
+
+    sketch_name = "CompileWithFailOnWarning"
+    sketch_path = Path(data_dir, sketch_name)
+    fqbn = "arduino:avr:uno"
+
+    # Create a test sketch with a function missing the return statement
+    assert run_command(f"sketch new {sketch_path}")
+    Path(sketch_path, f"{sketch_name}.ino").write_text(
+        "int value() {}\n" "void setup() { value(); }\n" "void loop() {}\n"
+    )
+
+    # The warning doesn't match the pattern
+    assert run_command(f'compile -b {fqbn} {sketch_path} --clean --fail-on-warning "is deprecated"')
+
+    res = run_command(f"compile -b {fqbn} {sketch_path} --clean --fail-on-warning -Wreturn-type")
+    assert res.failed
+    assert "warnings promoted to errors" in res.stderr
+    assert f"{sketch_name}.ino:1: no return statement" in res.stderr
+
+    res = run_command(f'compile -b {fqbn} {sketch_path} --fail-on-warning "("')
+    assert res.failed
+    assert "invalid warning pattern" in res.stderr
+
+
+def test_compile_with_treesitter_preprocessor(run_command, data_dir):
+    assert run_command("update")
+
+    # Download latest AVR
+    run_command("core install arduino:avr")
+
+    sketch_name = "CompileWithTreeSitterPreprocessor"
+    sketch_path = Path(data_dir, sketch_name)
+    fqbn = "arduino:avr:uno"
+
+    # Create a test sketch using functions before their definition
+    assert run_command(f"sketch new {sketch_path}")
+    Path(sketch_path, f"{sketch_name}.ino").write_text(
+        "auto twice = [](int x) { return x * 2; };\n"
+        "void setup() { getHandler(1)(twice(2)); }\n"
+        "void loop() {}\n"
+        "void handler(int value) {}\n"
+        "void (*getHandler(int id))(int) { return handler; }\n"
+    )
+
+    res = run_command(f"compile -b {fqbn} {sketch_path} --preprocessor treesitter")
+    if res.failed:
+        # tree-sitter is linked only in the builds made with the treesitter tag
+        assert "must be built with the treesitter tag" in res.stderr
+    else:
+        assert "Sketch uses" in res.stdout
+
+    res = run_command(f"compile -b {fqbn} {sketch_path} --preprocessor unknown")
+    assert res.failed
+    assert "invalid preprocessor: unknown" in res.stderr
+
+
+def test_compile_with_namespaced_functions(run_command, data_dir):
+    assert run_command("update")
+
+    # Download latest AVR
+    run_command("core install arduino:avr")
+
+    sketch_name = "CompileWithNamespacedFunctions"
+    sketch_path = Path(data_dir, sketch_name)
+    fqbn = "arduino:avr:uno"
+
+    # Create a test sketch calling a namespaced function defined later
+    assert run_command(f"sketch new {sketch_path}")
+    Path(sketch_path, f"{sketch_name}.ino").write_text(
+        "void setup() { util::helper(); }\n"
+        "void loop() {}\n"
+        "namespace util {\n"
+        "void helper() {}\n"
+        "}\n"
+    )
+
+    assert run_command(f"compile -b {fqbn} {sketch_path}")
+    assert run_command(f"compile -b {fqbn} {sketch_path} --preprocessor native")
+
+
+@pytest.mark.skipif(platform.system() == "Windows", reason="the hooks of the test use sh")
+def test_compile_with_user_hooks(run_command, data_dir, downloads_dir):
+    assert run_command("update")
+
+    # Download latest AVR
+    run_command("core install arduino:avr")
+
+    sketch_name = "CompileWithUserHooks"
+    sketch_path = Path(data_dir, sketch_name)
+    build_path = Path(data_dir, "build")
+    fqbn = "arduino:avr:uno"
+
+    # The pre_preprocess hook generates a header included by the sketch
+    assert run_command(f"sketch new {sketch_path}")
+    Path(sketch_path, f"{sketch_name}.ino").write_text(
+        '#include "version.h"\n' "void setup() { (void)VERSION; }\n" "void loop() {}\n"
+    )
+    hooks = {
+        "hooks": {
+            "pre_preprocess": ["sh -c 'echo \"#define VERSION 42\" > version.h'"],
+            "post_build": ["sh -c 'echo post build $ARDUINO_BUILD_PROJECT_NAME'"],
+        }
+    }
+    Path(sketch_path, "sketch.json").write_text(json.dumps(hooks))
+
+    # The hooks of the sketch are ignored unless allowed
+    res = run_command(f"compile -b {fqbn} {sketch_path} --build-path {build_path}")
+    assert res.failed
+    assert "the build hooks of the sketch are ignored" in res.stderr
+    assert not Path(sketch_path, "version.h").exists()
+
+    env = {
+        "ARDUINO_DATA_DIR": data_dir,
+        "ARDUINO_DOWNLOADS_DIR": downloads_dir,
+        "ARDUINO_SKETCHBOOK_DIR": data_dir,
+        "ARDUINO_COMPILE_ALLOW_SKETCH_HOOKS": "true",
+    }
+    res = run_command(f"compile -b {fqbn} {sketch_path} --build-path {build_path}", custom_env=env)
+    assert res.ok
+    assert Path(sketch_path, "version.h").exists()
+    assert f"post build {sketch_name}.ino" in res.stdout
+
+    # A failing hook makes the build fail
+    hooks["hooks"]["pre_link"] = ["sh -c 'exit 3'"]
+    Path(sketch_path, "sketch.json").write_text(json.dumps(hooks))
+    res = run_command(f"compile -b {fqbn} {sketch_path} --build-path {build_path}", custom_env=env)
+    assert res.failed
+    assert "running pre_link hook" in res.stderr
+
+
+def test_compile_with_no_prototypes_pragma(run_command, data_dir):
+    assert run_command("update")
+
+    # Download latest AVR
+    run_command("core install arduino:avr")
+
+    sketch_name = "CompileWithNoPrototypesPragma"
+    sketch_path = Path(data_dir, sketch_name)
+    fqbn = "arduino:avr:uno"
+
+    # The generated prototype of make() would be placed before the definition
+    # of the Value type it returns
+    assert run_command(f"sketch new {sketch_path}")
+    Path(sketch_path, f"{sketch_name}.ino").write_text(
+        "void setup() {}\n"
+        "struct Value { int v; };\n"
+        "#pragma arduino no_prototypes begin\n"
+        "Value make(int v) { return Value{v}; }\n"
+        "#pragma arduino no_prototypes end\n"
+        "void loop() { make(1); }\n"
+    )
+
+    result = run_command(f"compile -b {fqbn} {sketch_path} --warnings all")
+    assert result.ok
+    assert "no_prototypes" not in result.stderr
+
+
+def test_compile_with_fqbn_pattern(run_command, data_dir):
+    assert run_command("update")
+    assert run_command("core install arduino:avr@1.8.3")
+
+    sketch_name = "CompileWithFqbnPattern"
+    sketch_path = Path(data_dir, sketch_name)
+    build_path = Path(data_dir, "build")
+    assert run_command(f"sketch new {sketch_path}")
+
+    res = run_command(f'compile -b "arduino:avr:mega*" {sketch_path} --build-path {build_path} --format json')
+    assert res.ok
+    boards = json.loads(res.stdout)["boards"]
+    assert [b["fqbn"] for b in boards] == ["arduino:avr:mega", "arduino:avr:megaADK"]
+    assert all(b["result"]["success"] for b in boards)
+    assert Path(build_path, "arduino.avr.mega", f"{sketch_name}.ino.hex").exists()
+    assert Path(build_path, "arduino.avr.megaADK", f"{sketch_name}.ino.hex").exists()
+
+    # The expansion is capped
+    res = run_command(f'compile -b "arduino:avr:*" {sketch_path} --max-boards 3')
+    assert res.failed
+    assert "more than the maximum of 3" in res.stderr
+
+    # Upload can't be done for multiple boards
+    res = run_command(f'compile -b "arduino:avr:mega*" {sketch_path} --upload -p /dev/ttyACM0')
+    assert res.failed
+
+
+def test_compile_with_multiple_fqbns(run_command, data_dir):
+    assert run_command("update")
+    assert run_command("core install arduino:avr@1.8.3")
+
+    sketch_name = "CompileWithMultipleFqbns"
+    sketch_path = Path(data_dir, sketch_name)
+    build_path = Path(data_dir, "build")
+    assert run_command(f"sketch new {sketch_path}")
+
+    # FQBNs and patterns can be mixed, each board is built once
+    res = run_command(
+        f'compile -b arduino:avr:uno -b "arduino:avr:mega*" -b arduino:avr:mega {sketch_path} '
+        + f"--build-path {build_path} --format json"
+    )
+    assert res.ok
+    boards = json.loads(res.stdout)["boards"]
+    assert [b["fqbn"] for b in boards] == ["arduino:avr:uno", "arduino:avr:mega", "arduino:avr:megaADK"]
+    assert all(b["result"]["success"] for b in boards)
+    assert Path(build_path, "arduino.avr.uno", f"{sketch_name}.ino.hex").exists()
+
+    # The FQBNs can be listed in a file
+    fqbn_file = Path(data_dir, "boards.txt")
+    fqbn_file.write_text("# boards of the CI\narduino:avr:uno\n\narduino:avr:nano\n")
+    res = run_command(f"compile --fqbn-file {fqbn_file} {sketch_path} --format json")
+    assert res.ok
+    boards = json.loads(res.stdout)["boards"]
+    assert [b["fqbn"] for b in boards] == ["arduino:avr:uno", "arduino:avr:nano"]
+
+    # A single FQBN in the file is a normal build
+    fqbn_file.write_text("arduino:avr:uno\n")
+    res = run_command(f"compile --fqbn-file {fqbn_file} {sketch_path} --format json")
+    assert res.ok
+    assert "boards" not in json.loads(res.stdout)
+
+    res = run_command(f"compile --fqbn-file {Path(data_dir, 'missing.txt')} {sketch_path}")
+    assert res.failed
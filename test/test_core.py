@@ -236,6 +236,43 @@ def test_core_download(run_command, downloads_dir):
     assert result.failed
 
 
+def test_core_details(run_command):
+    assert run_command("core update-index")
+
+    result = run_command("core details arduino:avr@1.8.3 --format json")
+    assert result.ok
+    details = json.loads(result.stdout)
+    assert details["platform"]["id"] == "arduino:avr"
+    assert details["platform"]["latest"] == "1.8.3"
+    assert details["size"] > 0
+    tools = {t["name"]: t for t in details["tools"]}
+    assert tools["avr-gcc"]["version"] == "7.3.0-atmel3.6.1-arduino7"
+    assert tools["avrdude"]["version"] == "6.3.0-arduino18"
+    assert not tools["avr-gcc"].get("installed", False)
+    # The post-install script can't be known before the core is downloaded
+    assert "post_install" not in details
+
+    # It's found in the downloaded archive
+    assert run_command("core download arduino:avr@1.8.3")
+    result = run_command("core details arduino:avr@1.8.3 --format json")
+    assert result.ok
+    details = json.loads(result.stdout)
+    assert "installed" not in details["platform"]
+    assert details["post_install"] != 0
+
+    assert run_command("core install arduino:avr@1.8.3")
+    result = run_command("core details arduino:avr@1.8.3 --format json")
+    assert result.ok
+    details = json.loads(result.stdout)
+    assert details["platform"]["installed"] == "1.8.3"
+    assert all(t["installed"] for t in details["tools"])
+    assert details["post_install"] != 0
+
+    # Wrong core version
+    result = run_command("core details arduino:avr@69.42.0")
+    assert result.failed
+
+
 def _in(jsondata, name, version=None):
     installed_cores = json.loads(jsondata)
     for c in installed_cores:
@@ -712,3 +749,34 @@ def test_core_with_wrong_custom_board_options_is_loaded(run_command, data_dir):
         + "skipping loading of boards arduino-beta-dev:platform_with_wrong_custom_board_options:nessuno: "
         + "malformed custom board options"
     ) in res.stderr
+
+
+def test_tool_gc(run_command, data_dir):
+    assert run_command("update")
+    assert run_command("core install arduino:avr@1.8.3")
+
+    # All the installed tools are required by the platform
+    res = run_command("tool gc --dry-run --format json")
+    assert res.ok
+    assert json.loads(res.stdout) == []
+
+    # Simulates an old tool version left after an upgrade
+    tools_dir = Path(data_dir, "packages", "arduino", "tools", "avrdude")
+    installed_version = next(tools_dir.iterdir())
+    old_version = Path(tools_dir, "6.0.1-arduino5")
+    shutil.copytree(installed_version, old_version)
+
+    res = run_command("tool gc --dry-run --format json")
+    assert res.ok
+    tools = json.loads(res.stdout)
+    assert len(tools) == 1
+    assert tools[0]["packager"] == "arduino"
+    assert tools[0]["name"] == "avrdude"
+    assert tools[0]["version"] == "6.0.1-arduino5"
+    assert old_version.exists()
+
+    res = run_command("tool gc")
+    assert res.ok
+    assert "Removed 1 unused tools" in res.stdout
+    assert not old_version.exists()
+    assert installed_version.exists()
@@ -761,6 +761,44 @@ def test_lib_examples(run_command, data_dir):
     assert str(Path(data_dir, "libraries", "Arduino_JSON", "examples", "JSONObject")) in examples
 
 
+def test_lib_compile_examples(run_command, data_dir):
+    assert run_command("update")
+    assert run_command("core install arduino:avr@1.8.3")
+    assert run_command("lib install Arduino_JSON@0.1.0")
+
+    res = run_command("lib compile-examples Arduino_JSON -b arduino:avr:uno --format json")
+    assert res.ok
+    data = json.loads(res.stdout)
+    assert data["library"] == "Arduino_JSON"
+    assert data["fqbn"] == "arduino:avr:uno"
+    examples = {Path(e["example"]).name: e["success"] for e in data["examples"]}
+    assert examples == {"JSONArray": True, "JSONKitchenSink": True, "JSONObject": True}
+
+    # A library in a folder, with a broken example
+    lib_path = Path(data_dir, "MyLib")
+    Path(lib_path, "src").mkdir(parents=True)
+    Path(lib_path, "library.properties").write_text("name=MyLib\nversion=1.0.0\n")
+    Path(lib_path, "src", "MyLib.h").write_text("int myLib();\n")
+    Path(lib_path, "src", "MyLib.cpp").write_text('#include "MyLib.h"\nint myLib() { return 1; }\n')
+    Path(lib_path, "examples", "Good").mkdir(parents=True)
+    Path(lib_path, "examples", "Good", "Good.ino").write_text(
+        "#include <MyLib.h>\nvoid setup() { myLib(); }\nvoid loop() {}\n"
+    )
+    Path(lib_path, "examples", "Broken").mkdir(parents=True)
+    Path(lib_path, "examples", "Broken", "Broken.ino").write_text("void setup() { missing(); }\nvoid loop() {}\n")
+
+    res = run_command(f"lib compile-examples {lib_path} -b arduino:avr:uno --format json")
+    assert res.failed
+    data = json.loads(res.stdout)
+    examples = {Path(e["example"]).name: e for e in data["examples"]}
+    assert examples["Good"]["success"]
+    assert not examples["Broken"]["success"]
+    assert "missing" in examples["Broken"]["compiler_err"]
+
+    res = run_command("lib compile-examples NotInstalled -b arduino:avr:uno")
+    assert res.failed
+
+
 def test_lib_examples_with_pde_file(run_command, data_dir):
     assert run_command("update")
 
@@ -0,0 +1,44 @@
+# This file is part of arduino-cli.
+#
+# Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+#
+# This software is released under the GNU General Public License version 3,
+# which covers the main part of arduino-cli.
+# The terms of this license can be found at:
+# https://www.gnu.org/licenses/gpl-3.0.en.html
+#
+# You can be released from the requirements of the above licenses by purchasing
+# a commercial license. Buying such a license is mandatory if you want to modify or
+# otherwise use the software for commercial activities involving the Arduino
+# software without disclosing the source code of your own applications. To purchase
+# a commercial license, send an email to license@arduino.cc.
+
+from pathlib import Path
+
+
+def test_monitor_replay(run_command, working_dir):
+    # Session with a received event, a sent event and another received event
+    # 50ms later (times in microseconds encoded as uvarints)
+    session = Path(working_dir, "session.bin")
+    session.write_bytes(
+        b"ARDUINO-MONITOR-SESSION 1\n"
+        + b"<\x00\x07hello\r\n"
+        + b">\x01\x04cmd\n"
+        + b"<\xd0\x86\x03\x07world\r\n"
+    )
+
+    res = run_command(f'monitor --replay "{session}"')
+    assert res.ok
+    assert res.stdout == "hello\r\nworld\r\n"
+
+
+def test_monitor_invalid_arguments(run_command, working_dir):
+    assert not run_command("monitor").ok
+    assert not run_command("monitor -p /dev/ttyACM0 --pty").ok
+    assert not run_command("monitor --replay session.bin --record other.bin").ok
+
+    session = Path(working_dir, "invalid.bin")
+    session.write_text("not a session")
+    res = run_command(f'monitor --replay "{session}"')
+    assert res.failed
+    assert "invalid monitor session file" in res.stderr
@@ -860,3 +860,39 @@ def test_sketch_archive_case_mismatch_fails(run_command, data_dir):
     res = run_command(f'sketch archive "{sketch_path}"')
     assert res.failed
     assert "Error archiving: no valid sketch found" in res.stderr
+
+
+def test_sketch_resolve_deps(run_command, data_dir):
+    assert run_command("update")
+    assert run_command("core install arduino:avr@1.8.3")
+
+    sketch_name = "SketchResolveDeps"
+    sketch_path = Path(data_dir, sketch_name)
+    assert run_command(f"sketch new {sketch_path}")
+    sketch_file = Path(sketch_path, f"{sketch_name}.ino")
+    sketch_file.write_text("#include <ArduinoJson.h>\n" + sketch_file.read_text())
+
+    res = run_command(f"sketch resolve-deps -b arduino:avr:uno {sketch_path}")
+    assert res.ok
+    assert "ArduinoJson@" in res.stdout
+    assert Path(data_dir, "libraries", "ArduinoJson").exists()
+
+    # Nothing left to install
+    res = run_command(f"sketch resolve-deps -b arduino:avr:uno {sketch_path}")
+    assert res.ok
+    assert "No library has been installed." in res.stdout
+
+
+def test_sketch_resolve_deps_with_unknown_include(run_command, data_dir):
+    assert run_command("update")
+    assert run_command("core install arduino:avr@1.8.3")
+
+    sketch_name = "SketchResolveDepsUnknown"
+    sketch_path = Path(data_dir, sketch_name)
+    assert run_command(f"sketch new {sketch_path}")
+    sketch_file = Path(sketch_path, f"{sketch_name}.ino")
+    sketch_file.write_text("#include <ThisHeaderDoesNotExist.h>\n" + sketch_file.read_text())
+
+    res = run_command(f"sketch resolve-deps -b arduino:avr:uno {sketch_path}")
+    assert res.failed
+    assert "no library of the libraries index provides ThisHeaderDoesNotExist.h" in res.stderr